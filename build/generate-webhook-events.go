@@ -0,0 +1,183 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build ignore
+
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"log"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	eventTypeHeader    = "X-Gitea-Event-Type"
+	actionsEventsLabel = "Actions events:"
+)
+
+// payload is a webhook payload declared in the swagger definitions
+type payload struct {
+	TypeName      string
+	EventTypes    []string
+	ActionsEvents []string
+}
+
+func main() {
+	var (
+		input  string
+		output string
+	)
+	flag.StringVar(&input, "i", "../../../routers/api/v1/swagger/webhook.go", "swagger definitions of the webhook payloads")
+	flag.StringVar(&output, "o", "events_gen.go", "file to write the generated code to")
+	flag.Parse()
+
+	payloads, err := parsePayloads(input)
+	if err != nil {
+		log.Fatalf("Unable to parse %s: %v", input, err)
+	}
+
+	data, err := generate(payloads)
+	if err != nil {
+		log.Fatalf("Unable to generate code: %v", err)
+	}
+
+	if err := os.WriteFile(output, data, 0o644); err != nil {
+		log.Fatalf("Unable to write %s: %v", output, err)
+	}
+}
+
+// parsePayloads reads the swagger:response declarations and returns the payload type of their body together
+// with the values of the event type header and the Actions events listed in the doc comment
+func parsePayloads(filename string) ([]*payload, error) {
+	f, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.ParseComments)
+	if err != nil {
+		return nil, err
+	}
+
+	var payloads []*payload
+	for _, decl := range f.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok || gen.Tok != token.TYPE || gen.Doc == nil || !strings.Contains(gen.Doc.Text(), "swagger:response") {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			st, ok := spec.(*ast.TypeSpec).Type.(*ast.StructType)
+			if !ok {
+				continue
+			}
+			p := &payload{ActionsEvents: actionsEvents(gen.Doc)}
+			for _, field := range st.Fields.List {
+				if field.Tag == nil {
+					continue
+				}
+				switch {
+				case strings.Contains(field.Tag.Value, `json:"`+eventTypeHeader+`"`):
+					p.EventTypes = enumValues(field.Doc)
+				case strings.Contains(field.Tag.Value, `json:"body"`):
+					sel, ok := field.Type.(*ast.SelectorExpr)
+					if !ok {
+						return nil, fmt.Errorf("body of %s is not a type of the structs module", spec.(*ast.TypeSpec).Name.Name)
+					}
+					p.TypeName = sel.Sel.Name
+				}
+			}
+			if p.TypeName == "" || len(p.EventTypes) == 0 {
+				return nil, fmt.Errorf("%s misses the body or the enum of the %s header", spec.(*ast.TypeSpec).Name.Name, eventTypeHeader)
+			}
+			payloads = append(payloads, p)
+		}
+	}
+	return payloads, nil
+}
+
+func splitList(list string) []string {
+	var values []string
+	for _, v := range strings.Split(list, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func enumValues(doc *ast.CommentGroup) []string {
+	if doc == nil {
+		return nil
+	}
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		if key, value, ok := strings.Cut(line, ":"); ok && strings.EqualFold(strings.TrimSpace(key), "enum") {
+			return splitList(value)
+		}
+	}
+	return nil
+}
+
+func actionsEvents(doc *ast.CommentGroup) []string {
+	for _, line := range strings.Split(doc.Text(), "\n") {
+		if strings.HasPrefix(line, actionsEventsLabel) {
+			return splitList(line[len(actionsEventsLabel):])
+		}
+	}
+	return nil
+}
+
+func writeMap(buf *bytes.Buffer, name, doc string, entries map[string]string) {
+	keys := make([]string, 0, len(entries))
+	for k := range entries {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	fmt.Fprintf(buf, "// %s %s\n", name, doc)
+	fmt.Fprintf(buf, "var %s = map[string]func() any{\n", name)
+	for _, k := range keys {
+		fmt.Fprintf(buf, "%q: func() any { return &api.%s{} },\n", k, entries[k])
+	}
+	buf.WriteString("}\n\n")
+}
+
+func generate(payloads []*payload) ([]byte, error) {
+	byEventType := make(map[string]string)
+	byActionsEvent := make(map[string]string)
+	for _, p := range payloads {
+		for _, event := range p.EventTypes {
+			if other, ok := byEventType[event]; ok {
+				return nil, fmt.Errorf("event type %s is declared for %s and %s", event, other, p.TypeName)
+			}
+			byEventType[event] = p.TypeName
+		}
+		for _, event := range p.ActionsEvents {
+			if other, ok := byActionsEvent[event]; ok {
+				return nil, fmt.Errorf("Actions event %s is declared for %s and %s", event, other, p.TypeName)
+			}
+			byActionsEvent[event] = p.TypeName
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	buf.WriteString(`// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Code generated by build/generate-webhook-events.go. DO NOT EDIT.
+
+package events
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+`)
+	writeMap(buf, "newPayloadByEventType", "creates the payload for the value of the "+eventTypeHeader+" header", byEventType)
+	writeMap(buf, "newPayloadByActionsEvent", "creates the payload for the name of the event which triggered an Actions run", byActionsEvent)
+
+	return format.Source(bytes.TrimSuffix(buf.Bytes(), []byte("\n")))
+}
@@ -84,6 +84,7 @@ var (
 			microcmdAuthUpdateSMTP,
 			microcmdAuthList,
 			microcmdAuthDelete,
+			microcmdAuthMigrateUsers,
 		},
 	}
 
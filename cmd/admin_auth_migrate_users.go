@@ -0,0 +1,128 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	auth_service "code.gitea.io/gitea/services/auth"
+
+	"github.com/urfave/cli"
+)
+
+var microcmdAuthMigrateUsers = cli.Command{
+	Name:   "migrate-users",
+	Usage:  "Migrate the users of an auth source to another auth source",
+	Action: runMigrateAuthUsers,
+	Flags: []cli.Flag{
+		cli.Int64Flag{
+			Name:  "from-id",
+			Usage: "ID of the auth source of the migrated users, 0 for the local users",
+		},
+		cli.Int64Flag{
+			Name:  "to-id",
+			Usage: "ID of the auth source the users are migrated to",
+		},
+		cli.StringFlag{
+			Name:  "match",
+			Value: "email",
+			Usage: "Match the accounts with the users by \"email\" or \"username\"",
+		},
+		cli.StringFlag{
+			Name:  "accounts",
+			Usage: "CSV file with an account of the new auth source per line: the email or username to match and optionally the login name. All users are migrated if it is omitted",
+		},
+		cli.BoolFlag{
+			Name:  "dry-run",
+			Usage: "Only report what would be migrated",
+		},
+	},
+}
+
+// readMigrateAccounts reads the accounts of the new auth source from a CSV file
+func readMigrateAccounts(filename string) ([]*auth_service.MigrateUsersAccount, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r := csv.NewReader(f)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	accounts := make([]*auth_service.MigrateUsersAccount, 0, 50)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return accounts, nil
+		} else if err != nil {
+			return nil, err
+		}
+		if len(record) == 0 || strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		account := &auth_service.MigrateUsersAccount{Match: strings.TrimSpace(record[0])}
+		if len(record) > 1 {
+			account.LoginName = strings.TrimSpace(record[1])
+		}
+		accounts = append(accounts, account)
+	}
+}
+
+func runMigrateAuthUsers(c *cli.Context) error {
+	if !c.IsSet("to-id") {
+		return errors.New("--to-id flag is missing")
+	}
+
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	opts := &auth_service.MigrateUsersOptions{
+		FromSourceID: c.Int64("from-id"),
+		ToSourceID:   c.Int64("to-id"),
+		MatchBy:      auth_service.MigrateUsersMatch(c.String("match")),
+		DryRun:       c.Bool("dry-run"),
+	}
+	if c.IsSet("accounts") {
+		var err error
+		if opts.Accounts, err = readMigrateAccounts(c.String("accounts")); err != nil {
+			return fmt.Errorf("read accounts: %w", err)
+		}
+	}
+
+	if err := initDB(ctx); err != nil {
+		return err
+	}
+
+	report, err := auth_service.MigrateUsers(ctx, opts)
+	if err != nil {
+		return err
+	}
+
+	verb := "Migrated"
+	if opts.DryRun {
+		verb = "Would migrate"
+	}
+	for _, u := range report.Migrated {
+		fmt.Printf("%s %s with login name %s\n", verb, u.Name, u.LoginName)
+	}
+	for _, name := range report.Conflicts {
+		fmt.Printf("Conflict: login name of %s already used or matched by several accounts\n", name)
+	}
+	for _, match := range report.UnmatchedAccounts {
+		fmt.Printf("Unmatched account: %s\n", match)
+	}
+	for _, name := range report.UnmatchedUsers {
+		fmt.Printf("Unmatched user: %s\n", name)
+	}
+	fmt.Printf("%s %d user(s), %d conflict(s), %d unmatched account(s), %d unmatched user(s)\n",
+		verb, len(report.Migrated), len(report.Conflicts), len(report.UnmatchedAccounts), len(report.UnmatchedUsers))
+	return nil
+}
@@ -199,6 +199,24 @@ Gitea or set your environment appropriately.`, "")
 
 	scanner := bufio.NewScanner(os.Stdin)
 
+	// the push is checked as a whole against the push limits first, so all ref updates are read before they are checked
+	var allOldCommitIDs, allNewCommitIDs, allRefFullNames []string
+	for scanner.Scan() {
+		// TODO: support news feeds for wiki
+		if isWiki {
+			continue
+		}
+
+		fields := bytes.Fields(scanner.Bytes())
+		if len(fields) != 3 {
+			continue
+		}
+
+		allOldCommitIDs = append(allOldCommitIDs, string(fields[0]))
+		allNewCommitIDs = append(allNewCommitIDs, string(fields[1]))
+		allRefFullNames = append(allRefFullNames, string(fields[2]))
+	}
+
 	oldCommitIDs := make([]string, hookBatchSize)
 	newCommitIDs := make([]string, hookBatchSize)
 	refFullNames := make([]string, hookBatchSize)
@@ -223,20 +241,28 @@ Gitea or set your environment appropriately.`, "")
 		supportProcReceive = true
 	}
 
-	for scanner.Scan() {
-		// TODO: support news feeds for wiki
-		if isWiki {
-			continue
-		}
+	// the server tells whether its push limits are enabled, the configuration is used if it doesn't, e.g. for SSH pushes
+	pushLimitsEnabled, err := strconv.ParseBool(os.Getenv(repo_module.EnvPushLimits))
+	if err != nil {
+		pushLimitsEnabled = setting.PushLimitsEnabled()
+	}
+	if pushLimitsEnabled && len(allRefFullNames) > 0 {
+		fmt.Fprintf(out, "Checking push limits\n")
 
-		fields := bytes.Fields(scanner.Bytes())
-		if len(fields) != 3 {
-			continue
+		limitOptions := hookOptions
+		limitOptions.OldCommitIDs = allOldCommitIDs
+		limitOptions.NewCommitIDs = allNewCommitIDs
+		limitOptions.RefFullNames = allRefFullNames
+		extra := private.HookCheckPushLimits(ctx, username, reponame, limitOptions)
+		if extra.HasError() {
+			return fail(ctx, extra.UserMsg, "HookCheckPushLimits failed: %v", extra.Error)
 		}
+	}
 
-		oldCommitID := string(fields[0])
-		newCommitID := string(fields[1])
-		refFullName := string(fields[2])
+	for i := range allRefFullNames {
+		oldCommitID := allOldCommitIDs[i]
+		newCommitID := allNewCommitIDs[i]
+		refFullName := allRefFullNames[i]
 		total++
 		lastline++
 
@@ -374,7 +400,7 @@ Gitea or set your environment appropriately.`, "")
 		oldCommitIDs[count] = string(fields[0])
 		newCommitIDs[count] = string(fields[1])
 		refFullNames[count] = string(fields[2])
-		if refFullNames[count] == git.BranchPrefix+"master" && newCommitIDs[count] != git.EmptySHA && count == total {
+		if refFullNames[count] == git.BranchPrefix+"master" && !git.IsEmptyCommitID(newCommitIDs[count]) && count == total {
 			masterPushed = true
 		}
 		count++
@@ -667,7 +693,7 @@ Gitea or set your environment appropriately.`, "")
 		if err != nil {
 			return err
 		}
-		if rs.OldOID != git.EmptySHA {
+		if !git.IsEmptyCommitID(rs.OldOID) {
 			err = writeDataPktLine(ctx, os.Stdout, []byte("option old-oid "+rs.OldOID))
 			if err != nil {
 				return err
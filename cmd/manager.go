@@ -24,6 +24,7 @@ var (
 			subcmdFlushQueues,
 			subcmdLogging,
 			subCmdProcesses,
+			subcmdMaintenance,
 		},
 	}
 	subcmdShutdown = cli.Command{
@@ -95,6 +96,53 @@ var (
 			},
 		},
 	}
+	subcmdMaintenance = cli.Command{
+		Name:  "maintenance",
+		Usage: "Manage the read-only maintenance mode",
+		Subcommands: []cli.Command{
+			{
+				Name:   "enable",
+				Usage:  "Reject all requests which could change data, reads keep working",
+				Action: runEnableMaintenance,
+				Flags: []cli.Flag{
+					cli.StringFlag{
+						Name:  "message",
+						Usage: "Message shown to the users",
+					},
+					cli.BoolFlag{
+						Name:  "drain",
+						Usage: "Wait until all queues are empty",
+					},
+					cli.DurationFlag{
+						Name:  "timeout",
+						Value: 5 * time.Minute,
+						Usage: "Timeout for draining the queues",
+					},
+					cli.BoolFlag{
+						Name: "debug",
+					},
+				},
+			}, {
+				Name:   "disable",
+				Usage:  "Accept all requests again",
+				Action: runDisableMaintenance,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name: "debug",
+					},
+				},
+			}, {
+				Name:   "status",
+				Usage:  "Show whether the maintenance mode is enabled",
+				Action: runMaintenanceStatus,
+				Flags: []cli.Flag{
+					cli.BoolFlag{
+						Name: "debug",
+					},
+				},
+			},
+		},
+	}
 )
 
 func runShutdown(c *cli.Context) error {
@@ -132,3 +180,34 @@ func runProcesses(c *cli.Context) error {
 	extra := private.Processes(ctx, os.Stdout, c.Bool("flat"), c.Bool("no-system"), c.Bool("stacktraces"), c.Bool("json"), c.String("cancel"))
 	return handleCliResponseExtra(extra)
 }
+
+func runEnableMaintenance(c *cli.Context) error {
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	setup(ctx, c.Bool("debug"))
+	var drainTimeout time.Duration
+	if c.Bool("drain") {
+		drainTimeout = c.Duration("timeout")
+	}
+	extra := private.EnableMaintenanceMode(ctx, c.String("message"), drainTimeout)
+	return handleCliResponseExtra(extra)
+}
+
+func runDisableMaintenance(c *cli.Context) error {
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	setup(ctx, c.Bool("debug"))
+	extra := private.DisableMaintenanceMode(ctx)
+	return handleCliResponseExtra(extra)
+}
+
+func runMaintenanceStatus(c *cli.Context) error {
+	ctx, cancel := installSignals()
+	defer cancel()
+
+	setup(ctx, c.Bool("debug"))
+	extra := private.MaintenanceModeStatus(ctx)
+	return handleCliResponseExtra(extra)
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/urfave/cli"
+)
+
+// CmdReencryptStorage represents the available reencrypt storage sub-command.
+var CmdReencryptStorage = cli.Command{
+	Name:  "reencrypt-storage",
+	Usage: "Encrypt the stored files with the current master key",
+	Description: `Encrypts the files which were stored before the encryption was enabled in [storage_encryption],
+and rewraps the data keys of the files which were encrypted with an older master key.
+Run it after rotating the master key, old master keys can be removed afterwards.`,
+	Action: runReencryptStorage,
+	Flags: []cli.Flag{
+		cli.StringFlag{
+			Name:  "type, t",
+			Value: "",
+			Usage: "Type of stored files to reencrypt, all encrypted types if empty. Allowed types: 'attachments', 'lfs', 'packages'",
+		},
+	},
+}
+
+func runReencryptStorage(ctx *cli.Context) error {
+	stdCtx, cancel := installSignals()
+	defer cancel()
+
+	setting.Init(&setting.Options{})
+	if !setting.StorageEncryption.Enabled {
+		return fmt.Errorf("the encryption of the storages isn't enabled in [storage_encryption]")
+	}
+	if err := storage.Init(); err != nil {
+		return err
+	}
+
+	storagesOfRegion := map[string]func(region string) storage.ObjectStorage{
+		"attachments": storage.AttachmentsOfRegion,
+		"lfs":         storage.LFSOfRegion,
+		"packages":    storage.PackagesOfRegion,
+	}
+
+	types := []string{"attachments", "lfs", "packages"}
+	if tp := strings.ToLower(ctx.String("type")); tp != "" {
+		if _, ok := storagesOfRegion[tp]; !ok {
+			return fmt.Errorf("unsupported storage: %s", ctx.String("type"))
+		}
+		if !setting.IsStorageEncrypted(tp) {
+			return fmt.Errorf("the %s storage isn't encrypted", tp)
+		}
+		types = []string{tp}
+	}
+
+	regions := append([]string{""}, setting.StorageRegionNames()...)
+	for _, tp := range types {
+		if !setting.IsStorageEncrypted(tp) {
+			continue
+		}
+		for _, region := range regions {
+			changed, err := storage.ReencryptObjects(stdCtx, storagesOfRegion[tp](region))
+			if err != nil {
+				return fmt.Errorf("reencrypt %s files: %w", tp, err)
+			}
+			if region == "" {
+				log.Info("%d %s files have been reencrypted.", changed, tp)
+			} else {
+				log.Info("%d %s files of the storage region %s have been reencrypted.", changed, tp, region)
+			}
+		}
+	}
+	return nil
+}
@@ -324,6 +324,9 @@ func runServ(c *cli.Context) error {
 
 	process.SetSysProcAttribute(gitcmd)
 	gitcmd.Dir = setting.RepoRootPath
+	if results.RepoRootPath != "" {
+		gitcmd.Dir = results.RepoRootPath
+	}
 	gitcmd.Stdout = os.Stdout
 	gitcmd.Stdin = os.Stdin
 	gitcmd.Stderr = os.Stderr
@@ -9,10 +9,13 @@ package actions
 import (
 	"context"
 	"errors"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
 )
 
 const (
@@ -22,8 +25,14 @@ const (
 	ArtifactStatusUploadConfirmed = 2
 	// ArtifactStatusUploadError is the status of an artifact upload that is errored
 	ArtifactStatusUploadError = 3
+	// ArtifactStatusExpired is the status of an artifact whose file was deleted after its retention period
+	ArtifactStatusExpired = 4
 )
 
+// ArtifactContentEncodingZip is the content encoding of the artifacts uploaded with the v4 protocol,
+// which are uploaded as one zip archive of all their files
+const ArtifactContentEncodingZip = "application/zip"
+
 func init() {
 	db.RegisterModel(new(ActionArtifact))
 }
@@ -45,22 +54,30 @@ type ActionArtifact struct {
 	Status             int64              `xorm:"index"`              // The status of the artifact, uploading, expired or need-delete
 	CreatedUnix        timeutil.TimeStamp `xorm:"created"`
 	UpdatedUnix        timeutil.TimeStamp `xorm:"updated index"`
+	ExpiredUnix        timeutil.TimeStamp `xorm:"index"` // The time when the artifact will be expired
 }
 
-// CreateArtifact create a new artifact with task info or get same named artifact in the same run
-func CreateArtifact(ctx context.Context, t *ActionTask, artifactName string) (*ActionArtifact, error) {
+// CreateArtifact create a new artifact with task info or get same named artifact in the same run.
+// The artifact expires after retentionDays, it is kept forever if retentionDays is 0.
+func CreateArtifact(ctx context.Context, t *ActionTask, artifactName string, retentionDays int64) (*ActionArtifact, error) {
 	if err := t.LoadJob(ctx); err != nil {
 		return nil, err
 	}
-	artifact, err := getArtifactByArtifactName(ctx, t.Job.RunID, artifactName)
+	var expiredUnix timeutil.TimeStamp
+	if retentionDays > 0 {
+		expiredUnix = timeutil.TimeStamp(time.Now().Unix() + retentionDays*24*60*60)
+	}
+	artifact, err := GetArtifactByArtifactName(ctx, t.Job.RunID, artifactName)
 	if errors.Is(err, util.ErrNotExist) {
 		artifact := &ActionArtifact{
-			RunID:     t.Job.RunID,
-			RunnerID:  t.RunnerID,
-			RepoID:    t.RepoID,
-			OwnerID:   t.OwnerID,
-			CommitSHA: t.CommitSHA,
-			Status:    ArtifactStatusUploadPending,
+			RunID:        t.Job.RunID,
+			RunnerID:     t.RunnerID,
+			RepoID:       t.RepoID,
+			OwnerID:      t.OwnerID,
+			CommitSHA:    t.CommitSHA,
+			ArtifactName: artifactName,
+			Status:       ArtifactStatusUploadPending,
+			ExpiredUnix:  expiredUnix,
 		}
 		if _, err := db.GetEngine(ctx).Insert(artifact); err != nil {
 			return nil, err
@@ -69,10 +86,18 @@ func CreateArtifact(ctx context.Context, t *ActionTask, artifactName string) (*A
 	} else if err != nil {
 		return nil, err
 	}
+	if artifact.ExpiredUnix != expiredUnix {
+		// the artifact is uploaded again, e.g. when the job is rerun
+		artifact.ExpiredUnix = expiredUnix
+		if _, err := db.GetEngine(ctx).ID(artifact.ID).Cols("expired_unix").Update(artifact); err != nil {
+			return nil, err
+		}
+	}
 	return artifact, nil
 }
 
-func getArtifactByArtifactName(ctx context.Context, runID int64, name string) (*ActionArtifact, error) {
+// GetArtifactByArtifactName returns the artifact of a run by its name
+func GetArtifactByArtifactName(ctx context.Context, runID int64, name string) (*ActionArtifact, error) {
 	var art ActionArtifact
 	has, err := db.GetEngine(ctx).Where("run_id = ? AND artifact_name = ?", runID, name).Get(&art)
 	if err != nil {
@@ -103,6 +128,12 @@ func UpdateArtifactByID(ctx context.Context, id int64, art *ActionArtifact) erro
 	return err
 }
 
+// DeleteArtifactByID deletes an artifact by id
+func DeleteArtifactByID(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Delete(&ActionArtifact{})
+	return err
+}
+
 // ListArtifactsByRunID returns all artifacts of a run
 func ListArtifactsByRunID(ctx context.Context, runID int64) ([]*ActionArtifact, error) {
 	arts := make([]*ActionArtifact, 0, 10)
@@ -115,8 +146,28 @@ func ListUploadedArtifactsByRunID(ctx context.Context, runID int64) ([]*ActionAr
 	return arts, db.GetEngine(ctx).Where("run_id=? AND status=?", runID, ArtifactStatusUploadConfirmed).Find(&arts)
 }
 
+// GetArtifactSizesByRepo returns the stored size of the artifacts of every repository which has any,
+// the files of expired artifacts are deleted and not counted
+func GetArtifactSizesByRepo(ctx context.Context) (map[int64]int64, error) {
+	return db.SumGroupBy(ctx, "action_artifact", "repo_id", "file_compressed_size", builder.Neq{"status": ArtifactStatusExpired})
+}
+
 // ListArtifactsByRepoID returns all artifacts of a repo
 func ListArtifactsByRepoID(ctx context.Context, repoID int64) ([]*ActionArtifact, error) {
 	arts := make([]*ActionArtifact, 0, 10)
 	return arts, db.GetEngine(ctx).Where("repo_id=?", repoID).Find(&arts)
 }
+
+// ListNeedExpiredArtifacts returns the artifacts whose retention period is over and whose files weren't deleted yet
+func ListNeedExpiredArtifacts(ctx context.Context) ([]*ActionArtifact, error) {
+	arts := make([]*ActionArtifact, 0, 10)
+	return arts, db.GetEngine(ctx).
+		Where("expired_unix > 0 AND expired_unix < ? AND status != ?", timeutil.TimeStampNow(), ArtifactStatusExpired).
+		Find(&arts)
+}
+
+// SetArtifactExpired marks an artifact as expired after its file was deleted
+func SetArtifactExpired(ctx context.Context, artifactID int64) error {
+	_, err := db.GetEngine(ctx).ID(artifactID).Cols("status").Update(&ActionArtifact{Status: ArtifactStatusExpired})
+	return err
+}
@@ -34,6 +34,8 @@ type ActionRunner struct {
 	Description string                 `xorm:"TEXT"`
 	Base        int                    // 0 native 1 docker 2 virtual machine
 	RepoRange   string                 // glob match which repositories could use this runner
+	// Ephemeral runners run a single task, they are created for the jobs dispatched to Kubernetes
+	Ephemeral bool `xorm:"NOT NULL DEFAULT false"`
 
 	Token     string `xorm:"-"`
 	TokenHash string `xorm:"UNIQUE"` // sha256 of token
@@ -157,6 +159,7 @@ type FindRunnerOptions struct {
 	Sort          string
 	Filter        string
 	WithAvailable bool // not only runners belong to, but also runners can be used
+	IsEphemeral   util.OptionalBool
 }
 
 func (opts FindRunnerOptions) toCond() builder.Cond {
@@ -181,6 +184,9 @@ func (opts FindRunnerOptions) toCond() builder.Cond {
 	if opts.Filter != "" {
 		cond = cond.And(builder.Like{"name", opts.Filter})
 	}
+	if !opts.IsEphemeral.IsNone() {
+		cond = cond.And(builder.Eq{"ephemeral": opts.IsEphemeral.IsTrue()})
+	}
 	return cond
 }
 
@@ -202,6 +208,13 @@ func CountRunners(ctx context.Context, opts FindRunnerOptions) (int64, error) {
 		Count(ActionRunner{})
 }
 
+// CountOnlineRunners returns the number of runners which contacted the instance in the last minute
+func CountOnlineRunners(ctx context.Context) (int64, error) {
+	return db.GetEngine(ctx).
+		Where(builder.Gt{"last_online": timeutil.TimeStampNow().AddDuration(-time.Minute)}).
+		Count(ActionRunner{})
+}
+
 func FindRunners(ctx context.Context, opts FindRunnerOptions) (runners RunnerList, err error) {
 	sess := db.GetEngine(ctx).
 		Where(opts.toCond()).
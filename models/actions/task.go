@@ -11,6 +11,8 @@ import (
 
 	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/container"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -40,6 +42,9 @@ type ActionTask struct {
 	OwnerID           int64  `xorm:"index"`
 	CommitSHA         string `xorm:"index"`
 	IsForkPullRequest bool
+	// RequiredApproval is true if the run of the task had to be approved by a maintainer,
+	// the token of the task can't access the packages of the owner then
+	RequiredApproval bool
 
 	Token          string `xorm:"-"`
 	TokenHash      string `xorm:"UNIQUE"` // sha256 of token
@@ -110,6 +115,35 @@ func (task *ActionTask) GetRepoLink() string {
 	return task.Job.Run.Repo.Link()
 }
 
+// RepoAccessMode returns the access mode of the task token to the repository.
+// A task can write to its own repository and read the internal repositories of the same owner,
+// tasks of pull requests from forks are read only.
+func (task *ActionTask) RepoAccessMode(repo *repo_model.Repository) perm.AccessMode {
+	if task.RepoID == repo.ID {
+		if task.IsForkPullRequest {
+			return perm.AccessModeRead
+		}
+		return perm.AccessModeWrite
+	}
+	if repo.IsInternal && repo.OwnerID == task.OwnerID && !task.IsForkPullRequest {
+		return perm.AccessModeRead
+	}
+	return perm.AccessModeNone
+}
+
+// PackageAccessMode returns the access mode of the task token to the packages of an owner.
+// A task can publish the packages of the owner of its repository, tasks of pull requests from forks can only read them
+// and tasks of runs which had to be approved can't access them.
+func (task *ActionTask) PackageAccessMode(ownerID int64) perm.AccessMode {
+	if task.OwnerID != ownerID || task.RequiredApproval {
+		return perm.AccessModeNone
+	}
+	if task.IsForkPullRequest {
+		return perm.AccessModeRead
+	}
+	return perm.AccessModeWrite
+}
+
 func (task *ActionTask) LoadJob(ctx context.Context) error {
 	if task.Job == nil {
 		job, err := GetRunJobByID(ctx, task.JobID)
@@ -224,6 +258,14 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 
 	e := db.GetEngine(ctx)
 
+	if runner.Ephemeral {
+		if has, err := e.Where("runner_id = ?", runner.ID).Exist(new(ActionTask)); err != nil {
+			return nil, false, err
+		} else if has {
+			return nil, false, nil
+		}
+	}
+
 	jobCond := builder.NewCond()
 	if runner.RepoID != 0 {
 		jobCond = builder.Eq{"repo_id": runner.RepoID}
@@ -272,6 +314,7 @@ func CreateTaskForRunner(ctx context.Context, runner *ActionRunner) (*ActionTask
 		OwnerID:           job.OwnerID,
 		CommitSHA:         job.CommitSHA,
 		IsForkPullRequest: job.IsForkPullRequest,
+		RequiredApproval:  job.Run.ApprovedBy != 0,
 	}
 	if err := task.GenerateToken(); err != nil {
 		return nil, false, err
@@ -469,6 +512,46 @@ func StopTask(ctx context.Context, taskID int64, status Status) error {
 	return nil
 }
 
+// GetLogSizesByRepo returns the size of the stored task logs of every repository which has any
+func GetLogSizesByRepo(ctx context.Context) (map[int64]int64, error) {
+	return db.SumGroupBy(ctx, "action_task", "repo_id", "log_size", builder.Eq{"log_expired": false})
+}
+
+// FindTasksWithExpiredLogs returns the tasks which stopped before the given time and whose logs weren't deleted yet
+func FindTasksWithExpiredLogs(ctx context.Context, stoppedBefore timeutil.TimeStamp, limit int) ([]*ActionTask, error) {
+	tasks := make([]*ActionTask, 0, limit)
+	return tasks, db.GetEngine(ctx).
+		Where("stopped > 0 AND stopped < ? AND log_expired = ?", stoppedBefore, false).
+		Asc("id").
+		Limit(limit).
+		Find(&tasks)
+}
+
+// GetTaskByRunnerID returns the last task of a runner, or nil if the runner didn't run any task
+func GetTaskByRunnerID(ctx context.Context, runnerID int64) (*ActionTask, error) {
+	task := &ActionTask{}
+	has, err := db.GetEngine(ctx).Where("runner_id = ?", runnerID).Desc("id").Get(task)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return task, nil
+}
+
+// FindWaitingJobsLabels returns the labels of the jobs which wait for a runner, a job can only run on a runner with all of them
+func FindWaitingJobsLabels(ctx context.Context) ([][]string, error) {
+	var jobs []*ActionRunJob
+	if err := db.GetEngine(ctx).Cols("runs_on").Where("task_id = ? AND status = ?", 0, StatusWaiting).Asc("id").Find(&jobs); err != nil {
+		return nil, err
+	}
+	labels := make([][]string, 0, len(jobs))
+	for _, job := range jobs {
+		labels = append(labels, job.RunsOn)
+	}
+	return labels, nil
+}
+
 func isSubset(set, subset []string) bool {
 	m := make(container.Set[string], len(set))
 	for _, v := range set {
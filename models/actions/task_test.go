@@ -0,0 +1,43 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActionTaskRepoAccessMode(t *testing.T) {
+	task := &ActionTask{RepoID: 1, OwnerID: 2}
+	forkTask := &ActionTask{RepoID: 1, OwnerID: 2, IsForkPullRequest: true}
+
+	own := &repo_model.Repository{ID: 1, OwnerID: 2}
+	assert.Equal(t, perm.AccessModeWrite, task.RepoAccessMode(own))
+	assert.Equal(t, perm.AccessModeRead, forkTask.RepoAccessMode(own))
+
+	internal := &repo_model.Repository{ID: 3, OwnerID: 2, IsPrivate: true, IsInternal: true}
+	assert.Equal(t, perm.AccessModeRead, task.RepoAccessMode(internal))
+	assert.Equal(t, perm.AccessModeNone, forkTask.RepoAccessMode(internal))
+
+	otherOwner := &repo_model.Repository{ID: 4, OwnerID: 5, IsPrivate: true, IsInternal: true}
+	assert.Equal(t, perm.AccessModeNone, task.RepoAccessMode(otherOwner))
+
+	private := &repo_model.Repository{ID: 6, OwnerID: 2, IsPrivate: true}
+	assert.Equal(t, perm.AccessModeNone, task.RepoAccessMode(private))
+}
+
+func TestActionTaskPackageAccessMode(t *testing.T) {
+	task := &ActionTask{RepoID: 1, OwnerID: 2}
+	forkTask := &ActionTask{RepoID: 1, OwnerID: 2, IsForkPullRequest: true}
+	approvedTask := &ActionTask{RepoID: 1, OwnerID: 2, IsForkPullRequest: true, RequiredApproval: true}
+
+	assert.Equal(t, perm.AccessModeWrite, task.PackageAccessMode(2))
+	assert.Equal(t, perm.AccessModeRead, forkTask.PackageAccessMode(2))
+	assert.Equal(t, perm.AccessModeNone, approvedTask.PackageAccessMode(2))
+	assert.Equal(t, perm.AccessModeNone, task.PackageAccessMode(5))
+}
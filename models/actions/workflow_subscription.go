@@ -0,0 +1,52 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// ActionWorkflowSubscription represents an event of a workflow in the default branch of a repository which
+// is also triggered by the other repositories of the same owner, listed in the `repositories` filter of the event
+type ActionWorkflowSubscription struct {
+	ID           int64
+	OwnerID      int64    `xorm:"index"`
+	RepoID       int64    `xorm:"index"` // the repository of the workflow
+	WorkflowID   string   `xorm:"VARCHAR(255)"`
+	Event        string   `xorm:"VARCHAR(255)"` // the name of the event in the workflow
+	Repositories []string `xorm:"JSON TEXT"`    // the name patterns of the repositories triggering the event
+
+	Created timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(ActionWorkflowSubscription))
+}
+
+// ReplaceWorkflowSubscriptions replaces the subscriptions of the workflows of a repository
+func ReplaceWorkflowSubscriptions(ctx context.Context, repoID int64, subs []*ActionWorkflowSubscription) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Delete(&ActionWorkflowSubscription{RepoID: repoID}); err != nil {
+			return err
+		}
+		if len(subs) == 0 {
+			return nil
+		}
+		return db.Insert(ctx, subs)
+	})
+}
+
+// FindWorkflowSubscriptions returns the subscriptions of the repositories of an owner, except the ones of excludeRepoID
+func FindWorkflowSubscriptions(ctx context.Context, ownerID, excludeRepoID int64) ([]*ActionWorkflowSubscription, error) {
+	subs := make([]*ActionWorkflowSubscription, 0, 10)
+	return subs, db.GetEngine(ctx).
+		Where(builder.Eq{"owner_id": ownerID}.And(builder.Neq{"repo_id": excludeRepoID})).
+		Asc("id").
+		Find(&subs)
+}
@@ -0,0 +1,53 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package activities
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// MailDigestItem is an email notification waiting to be sent in the next digest of a user
+type MailDigestItem struct {
+	ID          int64              `xorm:"pk autoincr"`
+	UserID      int64              `xorm:"INDEX NOT NULL"`
+	RepoID      int64              `xorm:"NOT NULL"`
+	IssueID     int64              `xorm:"NOT NULL"`
+	CommentID   int64              `xorm:"NOT NULL DEFAULT 0"`
+	DoerID      int64              `xorm:"NOT NULL"`
+	OpType      ActionType         `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created INDEX"`
+}
+
+func init() {
+	db.RegisterModel(new(MailDigestItem))
+}
+
+// AddMailDigestItems queues email notifications for the next digests of their receivers
+func AddMailDigestItems(ctx context.Context, items []*MailDigestItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	return db.Insert(ctx, items)
+}
+
+// FindMailDigestUserIDs returns the ids of the users who have notifications waiting for their digest
+func FindMailDigestUserIDs(ctx context.Context) ([]int64, error) {
+	userIDs := make([]int64, 0, 10)
+	return userIDs, db.GetEngine(ctx).Table("mail_digest_item").Distinct("user_id").Find(&userIDs)
+}
+
+// GetMailDigestItems returns the notifications waiting for the digest of the user, oldest first
+func GetMailDigestItems(ctx context.Context, userID int64) ([]*MailDigestItem, error) {
+	items := make([]*MailDigestItem, 0, 10)
+	return items, db.GetEngine(ctx).Where("user_id = ?", userID).Asc("id").Find(&items)
+}
+
+// DeleteMailDigestItems removes the notifications of the user up to the given id once they have been sent
+func DeleteMailDigestItems(ctx context.Context, userID, maxID int64) error {
+	_, err := db.GetEngine(ctx).Where("user_id = ? AND id <= ?", userID, maxID).Delete(new(MailDigestItem))
+	return err
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package activities
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// ActivityCount is the number of actions of a group of a TeamActivitySummary
+type ActivityCount struct {
+	GroupKey int64
+	Count    int64
+}
+
+// TeamActivitySummary aggregates the recent actions in the repositories of a team
+type TeamActivitySummary struct {
+	Since    timeutil.TimeStamp
+	Total    int64
+	ByOpType []*ActivityCount // keyed by ActionType
+	ByRepo   []*ActivityCount // keyed by repository id
+	ByMember []*ActivityCount // keyed by user id, only the members of the team
+}
+
+// GetTeamActivitySummary counts the actions in the repositories of the team since the given time,
+// grouped by their type, their repository and the members of the team who did them
+func GetTeamActivitySummary(ctx context.Context, team *organization.Team, doer *user_model.User, since timeutil.TimeStamp) (*TeamActivitySummary, error) {
+	cond, err := activityQueryCondition(GetFeedsOptions{
+		RequestedTeam:  team,
+		Actor:          doer,
+		IncludePrivate: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	cond = cond.And(builder.Gte{"created_unix": since})
+
+	summary := &TeamActivitySummary{Since: since}
+	if summary.Total, err = db.GetEngine(ctx).Where(cond).Count(new(Action)); err != nil {
+		return nil, err
+	}
+	if summary.ByOpType, err = countTeamActivity(ctx, cond, "op_type"); err != nil {
+		return nil, err
+	}
+	if summary.ByRepo, err = countTeamActivity(ctx, cond, "repo_id"); err != nil {
+		return nil, err
+	}
+	memberCond := cond.And(builder.In("act_user_id", builder.Select("uid").From("team_user").Where(builder.Eq{"team_id": team.ID})))
+	if summary.ByMember, err = countTeamActivity(ctx, memberCond, "act_user_id"); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func countTeamActivity(ctx context.Context, cond builder.Cond, column string) ([]*ActivityCount, error) {
+	counts := make([]*ActivityCount, 0, 10)
+	return counts, db.GetEngine(ctx).
+		Select(column + " AS group_key, count(*) AS `count`").
+		Table("action").
+		Where(cond).
+		GroupBy(column).
+		OrderBy("`count` DESC").
+		Find(&counts)
+}
@@ -45,10 +45,17 @@ func getUserHeatmapData(user *user_model.User, team *organization.Team, doer *us
 		groupByName = groupBy
 	}
 
+	// Users can opt in to count their activity in repositories the doer can not read,
+	// the heatmap only tells when there was activity and not where.
+	actor := doer
+	if team == nil && !user.IsOrganization() && user.CountPrivateActivity {
+		actor = user
+	}
+
 	cond, err := activityQueryCondition(GetFeedsOptions{
 		RequestedUser:  user,
 		RequestedTeam:  team,
-		Actor:          doer,
+		Actor:          actor,
 		IncludePrivate: true, // don't filter by private, as we already filter by repo access
 		IncludeDeleted: true,
 		// * Heatmaps for individual users only include actions that the user themself did.
@@ -99,3 +99,31 @@ func TestGetUserHeatmapDataByUser(t *testing.T) {
 		assert.Equal(t, tc.JSONResult, string(jsonData))
 	}
 }
+
+func TestGetUserHeatmapDataByUserCountPrivateActivity(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	timeutil.Set(time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC))
+	defer timeutil.Unset()
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	other := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+
+	heatmap, err := activities_model.GetUserHeatmapDataByUser(user, other)
+	assert.NoError(t, err)
+	assert.Empty(t, heatmap)
+
+	// the activity in the private repository is counted for everyone once the user opts in
+	user.CountPrivateActivity = true
+	for _, doer := range []*user_model.User{other, nil} {
+		heatmap, err = activities_model.GetUserHeatmapDataByUser(user, doer)
+		assert.NoError(t, err)
+		assert.EqualValues(t, 1, activities_model.GetTotalContributionsInHeatmap(heatmap))
+	}
+
+	// but not when the activity is private
+	user.KeepActivityPrivate = true
+	heatmap, err = activities_model.GetUserHeatmapDataByUser(user, other)
+	assert.NoError(t, err)
+	assert.Empty(t, heatmap)
+}
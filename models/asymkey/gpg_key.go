@@ -43,6 +43,9 @@ type GPGKey struct {
 	CanEncryptComms   bool
 	CanEncryptStorage bool
 	CanCertify        bool
+	SuccessorID       int64              `xorm:"NOT NULL DEFAULT 0"`
+	RotatedUnix       timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	ExpiryMailedUnix  timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
 }
 
 func init() {
@@ -220,6 +223,16 @@ func deleteGPGKey(ctx context.Context, keyID string) (int64, error) {
 	if err != nil {
 		return n, err
 	}
+	// Keys rotated to the deleted key stay rotated without a successor
+	ids := make([]int64, 0, 1)
+	if err := db.GetEngine(ctx).Table("gpg_key").Where("key_id=?", keyID).Cols("id").Find(&ids); err != nil {
+		return n, err
+	}
+	if len(ids) > 0 {
+		if _, err := db.GetEngine(ctx).In("successor_id", ids).Cols("successor_id").Update(&GPGKey{}); err != nil {
+			return n, err
+		}
+	}
 	return db.GetEngine(ctx).Where("key_id=?", keyID).Or("primary_key_id=?", keyID).Delete(new(GPGKey))
 }
 
@@ -51,6 +51,7 @@ type CommitVerification struct {
 	SigningKey     *GPGKey
 	SigningSSHKey  *PublicKey
 	TrustStatus    string
+	KeyRotated     bool
 }
 
 // SignCommit represents a commit with validation of signature.
@@ -382,6 +383,7 @@ func hashAndVerifyWithSubKeysCommitVerification(sig *packet.Signature, payload s
 			SigningUser:    signer,
 			SigningKey:     key,
 			SigningEmail:   email,
+			KeyRotated:     k.IsRotated(),
 		}
 	}
 	return nil
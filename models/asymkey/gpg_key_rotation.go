@@ -0,0 +1,76 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package asymkey
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// IsRotated returns whether the key was replaced by a successor key.
+// Commits signed with a rotated key are still verified, they are annotated as signed with a since-rotated key.
+func (key *GPGKey) IsRotated() bool {
+	return key.RotatedUnix != 0
+}
+
+func getPrimaryGPGKeyOfOwner(ctx context.Context, ownerID, id int64) (*GPGKey, error) {
+	key := new(GPGKey)
+	has, err := db.GetEngine(ctx).Where("id=? AND owner_id=? AND primary_key_id=''", id, ownerID).Get(key)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrGPGKeyNotExist{id}
+	}
+	return key, nil
+}
+
+// RotateGPGKey marks a primary key of a user as replaced by another primary key of the user
+func RotateGPGKey(ctx context.Context, ownerID, id, successorID int64) error {
+	if id == successorID {
+		return util.NewInvalidArgumentErrorf("a key can't be its own successor")
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		key, err := getPrimaryGPGKeyOfOwner(ctx, ownerID, id)
+		if err != nil {
+			return err
+		}
+		if key.IsRotated() {
+			return util.NewInvalidArgumentErrorf("key %s is already rotated", key.KeyID)
+		}
+		successor, err := getPrimaryGPGKeyOfOwner(ctx, ownerID, successorID)
+		if err != nil {
+			return err
+		}
+		if successor.IsRotated() {
+			return util.NewInvalidArgumentErrorf("successor key %s is rotated itself", successor.KeyID)
+		}
+
+		// the subkeys are marked too, as signatures are verified with the subkeys
+		_, err = db.GetEngine(ctx).Where("id=? OR primary_key_id=?", key.ID, key.KeyID).
+			Cols("successor_id", "rotated_unix").
+			Update(&GPGKey{SuccessorID: successor.ID, RotatedUnix: timeutil.TimeStampNow()})
+		return err
+	})
+}
+
+// FindExpiringGPGKeys returns the primary keys which expire until the given time and whose owners
+// were not notified yet. Rotated keys are left out, they have been replaced already.
+func FindExpiringGPGKeys(ctx context.Context, until timeutil.TimeStamp) ([]*GPGKey, error) {
+	keys := make([]*GPGKey, 0, 10)
+	return keys, db.GetEngine(ctx).
+		Where("primary_key_id='' AND rotated_unix=0 AND expiry_mailed_unix=0").
+		And("expired_unix > ? AND expired_unix <= ?", timeutil.TimeStampNow(), until).
+		Asc("expired_unix").
+		Find(&keys)
+}
+
+// SetGPGKeyExpiryMailed records that the owner of a key was notified about its expiry
+func SetGPGKeyExpiryMailed(ctx context.Context, key *GPGKey) error {
+	key.ExpiryMailedUnix = timeutil.TimeStampNow()
+	_, err := db.GetEngine(ctx).ID(key.ID).Cols("expiry_mailed_unix").Update(key)
+	return err
+}
@@ -0,0 +1,79 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package asymkey
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotateGPGKey(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	oldKey := &GPGKey{OwnerID: 2, KeyID: "AAAAAAAAAAAAAAA1", CanSign: true}
+	oldSubKey := &GPGKey{OwnerID: 2, KeyID: "AAAAAAAAAAAAAAA2", PrimaryKeyID: oldKey.KeyID, CanSign: true}
+	newKey := &GPGKey{OwnerID: 2, KeyID: "BBBBBBBBBBBBBBB1", CanSign: true}
+	otherKey := &GPGKey{OwnerID: 4, KeyID: "CCCCCCCCCCCCCCC1", CanSign: true}
+	assert.NoError(t, db.Insert(db.DefaultContext, oldKey, oldSubKey, newKey, otherKey))
+
+	err := RotateGPGKey(db.DefaultContext, 2, oldKey.ID, oldKey.ID)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	err = RotateGPGKey(db.DefaultContext, 2, oldKey.ID, otherKey.ID)
+	assert.True(t, IsErrGPGKeyNotExist(err))
+	err = RotateGPGKey(db.DefaultContext, 2, oldKey.ID, oldSubKey.ID)
+	assert.True(t, IsErrGPGKeyNotExist(err))
+
+	assert.NoError(t, RotateGPGKey(db.DefaultContext, 2, oldKey.ID, newKey.ID))
+	rotated := unittest.AssertExistsAndLoadBean(t, &GPGKey{ID: oldKey.ID})
+	assert.True(t, rotated.IsRotated())
+	assert.EqualValues(t, newKey.ID, rotated.SuccessorID)
+	rotatedSubKey := unittest.AssertExistsAndLoadBean(t, &GPGKey{ID: oldSubKey.ID})
+	assert.True(t, rotatedSubKey.IsRotated())
+	assert.False(t, unittest.AssertExistsAndLoadBean(t, &GPGKey{ID: newKey.ID}).IsRotated())
+
+	err = RotateGPGKey(db.DefaultContext, 2, oldKey.ID, newKey.ID)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	err = RotateGPGKey(db.DefaultContext, 2, newKey.ID, oldKey.ID)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	// the rotated key stays rotated when its successor is deleted
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.NoError(t, DeleteGPGKey(user, newKey.ID))
+	rotated = unittest.AssertExistsAndLoadBean(t, &GPGKey{ID: oldKey.ID})
+	assert.True(t, rotated.IsRotated())
+	assert.EqualValues(t, 0, rotated.SuccessorID)
+}
+
+func TestFindExpiringGPGKeys(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	now := time.Now()
+	expiring := &GPGKey{OwnerID: 2, KeyID: "DDDDDDDDDDDDDDD1", ExpiredUnix: timeutil.TimeStamp(now.AddDate(0, 0, 10).Unix())}
+	expiringSubKey := &GPGKey{OwnerID: 2, KeyID: "DDDDDDDDDDDDDDD2", PrimaryKeyID: expiring.KeyID, ExpiredUnix: expiring.ExpiredUnix}
+	later := &GPGKey{OwnerID: 2, KeyID: "EEEEEEEEEEEEEEE1", ExpiredUnix: timeutil.TimeStamp(now.AddDate(0, 0, 60).Unix())}
+	expired := &GPGKey{OwnerID: 2, KeyID: "FFFFFFFFFFFFFFF1", ExpiredUnix: timeutil.TimeStamp(now.AddDate(0, 0, -1).Unix())}
+	forever := &GPGKey{OwnerID: 2, KeyID: "FFFFFFFFFFFFFFF2"}
+	rotated := &GPGKey{OwnerID: 2, KeyID: "FFFFFFFFFFFFFFF3", ExpiredUnix: expiring.ExpiredUnix, RotatedUnix: timeutil.TimeStampNow()}
+	assert.NoError(t, db.Insert(db.DefaultContext, expiring, expiringSubKey, later, expired, forever, rotated))
+
+	until := timeutil.TimeStamp(now.AddDate(0, 0, 30).Unix())
+	keys, err := FindExpiringGPGKeys(db.DefaultContext, until)
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 1) {
+		assert.EqualValues(t, expiring.ID, keys[0].ID)
+	}
+
+	assert.NoError(t, SetGPGKeyExpiryMailed(db.DefaultContext, keys[0]))
+	keys, err = FindExpiringGPGKeys(db.DefaultContext, until)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+}
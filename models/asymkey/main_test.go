@@ -17,6 +17,7 @@ func TestMain(m *testing.M) {
 			"gpg_key.yml",
 			"public_key.yml",
 			"deploy_key.yml",
+			"deploy_key_usage.yml",
 			"gpg_key_import.yml",
 			"user.yml",
 			"email_address.yml",
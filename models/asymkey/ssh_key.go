@@ -50,6 +50,12 @@ type PublicKey struct {
 	HasRecentActivity bool               `xorm:"-"`
 	HasUsed           bool               `xorm:"-"`
 	Verified          bool               `xorm:"NOT NULL DEFAULT false"`
+
+	// ExpiredUnix is set by the owner of the key, SSH keys don't carry an expiry date of their own
+	ExpiredUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	SuccessorID      int64              `xorm:"NOT NULL DEFAULT 0"`
+	RotatedUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	ExpiryMailedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
 }
 
 func init() {
@@ -245,6 +251,10 @@ func DeletePublicKeys(ctx context.Context, keyIDs ...int64) error {
 		return nil
 	}
 
+	// Keys rotated to the deleted keys stay rotated without a successor
+	if _, err := db.GetEngine(ctx).In("successor_id", keyIDs).Cols("successor_id").Update(&PublicKey{}); err != nil {
+		return err
+	}
 	_, err := db.GetEngine(ctx).In("id", keyIDs).Delete(new(PublicKey))
 	return err
 }
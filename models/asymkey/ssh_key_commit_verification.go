@@ -72,5 +72,6 @@ func verifySSHCommitVerification(sig, payload string, k *PublicKey, committer, s
 		SigningUser:    signer,
 		SigningSSHKey:  k,
 		SigningEmail:   email,
+		KeyRotated:     k.IsRotated(),
 	}
 }
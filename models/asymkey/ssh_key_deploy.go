@@ -6,12 +6,16 @@ package asymkey
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 
+	"github.com/gobwas/glob"
 	"xorm.io/builder"
 )
 
@@ -34,9 +38,14 @@ type DeployKey struct {
 	Content     string `xorm:"-"`
 
 	Mode perm.AccessMode `xorm:"NOT NULL DEFAULT 1"`
+	// RefPatterns are the glob patterns of the branches and tags the key may push to, all refs if empty
+	RefPatterns []string `xorm:"JSON TEXT"`
+	// ArchiveOnly keys may only download archives of the repository with git archive --remote
+	ArchiveOnly bool `xorm:"NOT NULL DEFAULT false"`
 
 	CreatedUnix       timeutil.TimeStamp `xorm:"created"`
 	UpdatedUnix       timeutil.TimeStamp `xorm:"updated"`
+	LastUsedUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
 	HasRecentActivity bool               `xorm:"-"`
 	HasUsed           bool               `xorm:"-"`
 }
@@ -62,6 +71,58 @@ func (key *DeployKey) IsReadOnly() bool {
 	return key.Mode == perm.AccessModeRead
 }
 
+// CanPushRef checks if the ref patterns of the key allow pushing to the ref.
+// The patterns are matched against the full ref name and the name of the branch or tag.
+func (key *DeployKey) CanPushRef(refFullName string) bool {
+	if len(key.RefPatterns) == 0 {
+		return true
+	}
+	shortName := strings.TrimPrefix(strings.TrimPrefix(refFullName, "refs/heads/"), "refs/tags/")
+	for _, pattern := range key.RefPatterns {
+		g, err := glob.Compile(pattern, '/')
+		if err != nil {
+			log.Warn("Invalid ref pattern %q of deploy key %d: %v", pattern, key.ID, err)
+			continue
+		}
+		if g.Match(refFullName) || g.Match(shortName) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateDeployKeyRefPatterns checks the ref patterns of a deploy key and returns them without empty entries
+func ValidateDeployKeyRefPatterns(patterns []string) ([]string, error) {
+	result := make([]string, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if pattern == "" {
+			continue
+		}
+		if _, err := glob.Compile(pattern, '/'); err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid ref pattern %q: %v", pattern, err)
+		}
+		result = append(result, pattern)
+	}
+	return result, nil
+}
+
+// SetDeployKeyRestrictions stores the ref patterns of the key and whether it may only download archives.
+// An archive only key is always read-only.
+func SetDeployKeyRestrictions(ctx context.Context, key *DeployKey, refPatterns []string, archiveOnly bool) error {
+	patterns, err := ValidateDeployKeyRefPatterns(refPatterns)
+	if err != nil {
+		return err
+	}
+	key.RefPatterns = patterns
+	key.ArchiveOnly = archiveOnly
+	if archiveOnly {
+		key.Mode = perm.AccessModeRead
+	}
+	_, err = db.GetEngine(ctx).ID(key.ID).Cols("ref_patterns", "archive_only", "mode").NoAutoTime().Update(key)
+	return err
+}
+
 func init() {
 	db.RegisterModel(new(DeployKey))
 }
@@ -0,0 +1,64 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package asymkey
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeployKeyCanPushRef(t *testing.T) {
+	key := &DeployKey{}
+	assert.True(t, key.CanPushRef("refs/heads/main"))
+
+	key.RefPatterns = []string{"release/*", "refs/tags/v*"}
+	assert.True(t, key.CanPushRef("refs/heads/release/1.0"))
+	assert.True(t, key.CanPushRef("refs/tags/v1.0"))
+	assert.False(t, key.CanPushRef("refs/heads/main"))
+	assert.False(t, key.CanPushRef("refs/heads/release/1.0/hotfix"))
+	assert.False(t, key.CanPushRef("refs/tags/1.0"))
+}
+
+func TestValidateDeployKeyRefPatterns(t *testing.T) {
+	patterns, err := ValidateDeployKeyRefPatterns([]string{" main ", "", "release/*"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"main", "release/*"}, patterns)
+
+	_, err = ValidateDeployKeyRefPatterns([]string{"release/["})
+	assert.Error(t, err)
+}
+
+func TestDeployKeyUsage(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	key, err := AddDeployKey(1, "usage-test", "sk-ecdsa-sha2-nistp256@openssh.com AAAAInNrLWVjZHNhLXNoYTItbmlzdHAyNTZAb3BlbnNzaC5jb20AAAAIbmlzdHAyNTYAAABBBGXEEzWmm1dxb+57RoK5KVCL0w2eNv9cqJX2AGGVlkFsVDhOXHzsadS3LTK4VlEbbrDMJdoti9yM8vclA8IeRacAAAAEc3NoOg== nocomment", false)
+	assert.NoError(t, err)
+
+	assert.NoError(t, SetDeployKeyRestrictions(db.DefaultContext, key, []string{"release/*"}, true))
+	key, err = GetDeployKeyByID(db.DefaultContext, key.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"release/*"}, key.RefPatterns)
+	assert.True(t, key.ArchiveOnly)
+	assert.True(t, key.IsReadOnly())
+
+	assert.NoError(t, RecordDeployKeyUsage(db.DefaultContext, key, perm.AccessModeRead, "git-upload-archive", "192.0.2.1"))
+	assert.NoError(t, RecordDeployKeyUsage(db.DefaultContext, key, perm.AccessModeRead, "git-upload-archive", "192.0.2.2"))
+	assert.NotZero(t, key.LastUsedUnix)
+
+	usages, count, err := ListDeployKeyUsages(db.DefaultContext, key.ID, db.ListOptions{Page: 1, PageSize: 1})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, usages, 1) {
+		assert.Equal(t, "192.0.2.2", usages[0].RemoteAddr)
+	}
+
+	assert.NoError(t, DeleteOldDeployKeyUsages(db.DefaultContext, time.Hour))
+	unittest.AssertCount(t, &DeployKeyUsage{DeployKeyID: key.ID}, 2)
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package asymkey
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// DeployKeyUsage records an access to a repository with a deploy key
+type DeployKeyUsage struct {
+	ID          int64           `xorm:"pk autoincr"`
+	DeployKeyID int64           `xorm:"INDEX NOT NULL"`
+	RepoID      int64           `xorm:"INDEX NOT NULL"`
+	Mode        perm.AccessMode // the requested access mode
+	Verb        string          // the git command, e.g. git-upload-pack
+	RemoteAddr  string
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func init() {
+	db.RegisterModel(new(DeployKeyUsage))
+}
+
+// RecordDeployKeyUsage stores an access with the deploy key and updates the time it was last used
+func RecordDeployKeyUsage(ctx context.Context, key *DeployKey, mode perm.AccessMode, verb, remoteAddr string) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := db.Insert(ctx, &DeployKeyUsage{
+			DeployKeyID: key.ID,
+			RepoID:      key.RepoID,
+			Mode:        mode,
+			Verb:        verb,
+			RemoteAddr:  remoteAddr,
+		}); err != nil {
+			return err
+		}
+		key.LastUsedUnix = timeutil.TimeStampNow()
+		_, err := db.GetEngine(ctx).ID(key.ID).Cols("last_used_unix").NoAutoTime().Update(key)
+		return err
+	})
+}
+
+// ListDeployKeyUsages returns the recorded accesses with the deploy key, the newest first
+func ListDeployKeyUsages(ctx context.Context, deployKeyID int64, listOptions db.ListOptions) ([]*DeployKeyUsage, int64, error) {
+	sess := db.GetEngine(ctx).Where("deploy_key_id = ?", deployKeyID).OrderBy("created_unix DESC, id DESC")
+	if listOptions.Page > 0 {
+		sess = db.SetSessionPagination(sess, &listOptions)
+	}
+	usages := make([]*DeployKeyUsage, 0, listOptions.PageSize)
+	count, err := sess.FindAndCount(&usages)
+	return usages, count, err
+}
+
+// DeleteOldDeployKeyUsages removes the recorded accesses with deploy keys which are older than the duration
+func DeleteOldDeployKeyUsages(ctx context.Context, olderThan time.Duration) error {
+	if olderThan <= 0 {
+		return nil
+	}
+	_, err := db.GetEngine(ctx).Where("created_unix < ?", time.Now().Add(-olderThan).Unix()).Delete(new(DeployKeyUsage))
+	return err
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package asymkey
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// IsRotated returns whether the key was replaced by a successor key.
+// Commits signed with a rotated key are still verified, they are annotated as signed with a since-rotated key.
+func (key *PublicKey) IsRotated() bool {
+	return key.RotatedUnix != 0
+}
+
+func getUserPublicKeyOfOwner(ctx context.Context, ownerID, id int64) (*PublicKey, error) {
+	key := new(PublicKey)
+	has, err := db.GetEngine(ctx).Where("id=? AND owner_id=? AND type=?", id, ownerID, KeyTypeUser).Get(key)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrKeyNotExist{id}
+	}
+	return key, nil
+}
+
+// SetPublicKeyExpiry sets the date until which the owner of a SSH key uses it to sign commits, 0 removes it.
+// The key can still be used to authenticate after the date, it is only used to remind the owner to rotate it.
+func SetPublicKeyExpiry(ctx context.Context, ownerID, id int64, expiry timeutil.TimeStamp) error {
+	if expiry != 0 && expiry <= timeutil.TimeStampNow() {
+		return util.NewInvalidArgumentErrorf("the expiry date must be in the future")
+	}
+	key, err := getUserPublicKeyOfOwner(ctx, ownerID, id)
+	if err != nil {
+		return err
+	}
+	key.ExpiredUnix = expiry
+	key.ExpiryMailedUnix = 0
+	_, err = db.GetEngine(ctx).ID(key.ID).Cols("expired_unix", "expiry_mailed_unix").NoAutoTime().Update(key)
+	return err
+}
+
+// RotatePublicKey marks a SSH key of a user as replaced by another SSH key of the user
+func RotatePublicKey(ctx context.Context, ownerID, id, successorID int64) error {
+	if id == successorID {
+		return util.NewInvalidArgumentErrorf("a key can't be its own successor")
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		key, err := getUserPublicKeyOfOwner(ctx, ownerID, id)
+		if err != nil {
+			return err
+		}
+		if key.IsRotated() {
+			return util.NewInvalidArgumentErrorf("key %s is already rotated", key.Fingerprint)
+		}
+		successor, err := getUserPublicKeyOfOwner(ctx, ownerID, successorID)
+		if err != nil {
+			return err
+		}
+		if successor.IsRotated() {
+			return util.NewInvalidArgumentErrorf("successor key %s is rotated itself", successor.Fingerprint)
+		}
+
+		key.SuccessorID = successor.ID
+		key.RotatedUnix = timeutil.TimeStampNow()
+		_, err = db.GetEngine(ctx).ID(key.ID).Cols("successor_id", "rotated_unix").NoAutoTime().Update(key)
+		return err
+	})
+}
+
+// FindExpiringPublicKeys returns the SSH keys of users which expire until the given time and whose owners
+// were not notified yet. Rotated keys are left out, they have been replaced already.
+func FindExpiringPublicKeys(ctx context.Context, until timeutil.TimeStamp) ([]*PublicKey, error) {
+	keys := make([]*PublicKey, 0, 10)
+	return keys, db.GetEngine(ctx).
+		Where("type=? AND rotated_unix=0 AND expiry_mailed_unix=0", KeyTypeUser).
+		And("expired_unix > ? AND expired_unix <= ?", timeutil.TimeStampNow(), until).
+		Asc("expired_unix").
+		Find(&keys)
+}
+
+// SetPublicKeyExpiryMailed records that the owner of a SSH key was notified about its expiry
+func SetPublicKeyExpiryMailed(ctx context.Context, key *PublicKey) error {
+	key.ExpiryMailedUnix = timeutil.TimeStampNow()
+	_, err := db.GetEngine(ctx).ID(key.ID).Cols("expiry_mailed_unix").NoAutoTime().Update(key)
+	return err
+}
@@ -0,0 +1,87 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package asymkey
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRotatePublicKey(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	oldKey := &PublicKey{OwnerID: 2, Name: "old", Fingerprint: "SHA256:old", Content: "old", Type: KeyTypeUser}
+	newKey := &PublicKey{OwnerID: 2, Name: "new", Fingerprint: "SHA256:new", Content: "new", Type: KeyTypeUser}
+	deployKey := &PublicKey{OwnerID: 2, Name: "deploy", Fingerprint: "SHA256:deploy", Content: "deploy", Type: KeyTypeDeploy}
+	otherKey := &PublicKey{OwnerID: 4, Name: "other", Fingerprint: "SHA256:other", Content: "other", Type: KeyTypeUser}
+	assert.NoError(t, db.Insert(db.DefaultContext, oldKey, newKey, deployKey, otherKey))
+
+	err := RotatePublicKey(db.DefaultContext, 2, oldKey.ID, oldKey.ID)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	err = RotatePublicKey(db.DefaultContext, 2, oldKey.ID, otherKey.ID)
+	assert.True(t, IsErrKeyNotExist(err))
+	err = RotatePublicKey(db.DefaultContext, 2, oldKey.ID, deployKey.ID)
+	assert.True(t, IsErrKeyNotExist(err))
+
+	assert.NoError(t, RotatePublicKey(db.DefaultContext, 2, oldKey.ID, newKey.ID))
+	rotated := unittest.AssertExistsAndLoadBean(t, &PublicKey{ID: oldKey.ID})
+	assert.True(t, rotated.IsRotated())
+	assert.EqualValues(t, newKey.ID, rotated.SuccessorID)
+	assert.False(t, unittest.AssertExistsAndLoadBean(t, &PublicKey{ID: newKey.ID}).IsRotated())
+
+	err = RotatePublicKey(db.DefaultContext, 2, oldKey.ID, newKey.ID)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	err = RotatePublicKey(db.DefaultContext, 2, newKey.ID, oldKey.ID)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	// the rotated key stays rotated when its successor is deleted
+	assert.NoError(t, DeletePublicKeys(db.DefaultContext, newKey.ID))
+	rotated = unittest.AssertExistsAndLoadBean(t, &PublicKey{ID: oldKey.ID})
+	assert.True(t, rotated.IsRotated())
+	assert.EqualValues(t, 0, rotated.SuccessorID)
+}
+
+func TestFindExpiringPublicKeys(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	now := time.Now()
+	expiring := &PublicKey{OwnerID: 2, Name: "expiring", Fingerprint: "SHA256:expiring", Content: "expiring", Type: KeyTypeUser}
+	later := &PublicKey{OwnerID: 2, Name: "later", Fingerprint: "SHA256:later", Content: "later", Type: KeyTypeUser}
+	forever := &PublicKey{OwnerID: 2, Name: "forever", Fingerprint: "SHA256:forever", Content: "forever", Type: KeyTypeUser}
+	rotated := &PublicKey{OwnerID: 2, Name: "rotated", Fingerprint: "SHA256:rotated", Content: "rotated", Type: KeyTypeUser, RotatedUnix: timeutil.TimeStampNow()}
+	assert.NoError(t, db.Insert(db.DefaultContext, expiring, later, forever, rotated))
+
+	err := SetPublicKeyExpiry(db.DefaultContext, 2, expiring.ID, timeutil.TimeStamp(now.AddDate(0, 0, -1).Unix()))
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	err = SetPublicKeyExpiry(db.DefaultContext, 4, expiring.ID, timeutil.TimeStamp(now.AddDate(0, 0, 10).Unix()))
+	assert.True(t, IsErrKeyNotExist(err))
+	assert.NoError(t, SetPublicKeyExpiry(db.DefaultContext, 2, expiring.ID, timeutil.TimeStamp(now.AddDate(0, 0, 10).Unix())))
+	assert.NoError(t, SetPublicKeyExpiry(db.DefaultContext, 2, later.ID, timeutil.TimeStamp(now.AddDate(0, 0, 60).Unix())))
+	assert.NoError(t, SetPublicKeyExpiry(db.DefaultContext, 2, rotated.ID, timeutil.TimeStamp(now.AddDate(0, 0, 10).Unix())))
+
+	until := timeutil.TimeStamp(now.AddDate(0, 0, 30).Unix())
+	keys, err := FindExpiringPublicKeys(db.DefaultContext, until)
+	assert.NoError(t, err)
+	if assert.Len(t, keys, 1) {
+		assert.EqualValues(t, expiring.ID, keys[0].ID)
+	}
+
+	assert.NoError(t, SetPublicKeyExpiryMailed(db.DefaultContext, keys[0]))
+	keys, err = FindExpiringPublicKeys(db.DefaultContext, until)
+	assert.NoError(t, err)
+	assert.Empty(t, keys)
+
+	// changing the expiry date notifies the owner again
+	assert.NoError(t, SetPublicKeyExpiry(db.DefaultContext, 2, expiring.ID, timeutil.TimeStamp(now.AddDate(0, 0, 20).Unix())))
+	keys, err = FindExpiringPublicKeys(db.DefaultContext, until)
+	assert.NoError(t, err)
+	assert.Len(t, keys, 1)
+}
@@ -0,0 +1,96 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// LoginDevice is a device a user signed in from, identified by the hash of the token of its device cookie
+type LoginDevice struct {
+	ID          int64  `xorm:"pk autoincr"`
+	UID         int64  `xorm:"UNIQUE(s) NOT NULL"`
+	Fingerprint string `xorm:"VARCHAR(64) UNIQUE(s) NOT NULL"`
+	UserAgent   string `xorm:"TEXT"`
+	// IP and Location are the ones of the last sign-in, Location is empty if it couldn't be determined
+	IP           string             `xorm:"VARCHAR(64)"`
+	Location     string             `xorm:"VARCHAR(255)"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	LastUsedUnix timeutil.TimeStamp `xorm:"INDEX"`
+}
+
+func init() {
+	db.RegisterModel(new(LoginDevice))
+}
+
+// ErrLoginDeviceNotExist represents a "LoginDeviceNotExist" kind of error.
+type ErrLoginDeviceNotExist struct {
+	ID int64
+}
+
+// IsErrLoginDeviceNotExist checks if an error is a ErrLoginDeviceNotExist.
+func IsErrLoginDeviceNotExist(err error) bool {
+	_, ok := err.(ErrLoginDeviceNotExist)
+	return ok
+}
+
+func (err ErrLoginDeviceNotExist) Error() string {
+	return fmt.Sprintf("login device does not exist [id: %d]", err.ID)
+}
+
+func (err ErrLoginDeviceNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// GetLoginDevice returns the device of a user with the fingerprint, or nil if the user didn't sign in from it yet
+func GetLoginDevice(ctx context.Context, uid int64, fingerprint string) (*LoginDevice, error) {
+	d := &LoginDevice{}
+	has, err := db.GetEngine(ctx).Where("uid = ? AND fingerprint = ?", uid, fingerprint).Get(d)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return d, nil
+}
+
+// HasLoginDevices returns if devices were recorded for a user
+func HasLoginDevices(ctx context.Context, uid int64) (bool, error) {
+	return db.GetEngine(ctx).Where("uid = ?", uid).Exist(new(LoginDevice))
+}
+
+// CreateLoginDevice records a device a user signed in from
+func CreateLoginDevice(ctx context.Context, d *LoginDevice) error {
+	d.LastUsedUnix = timeutil.TimeStampNow()
+	return db.Insert(ctx, d)
+}
+
+// UpdateLoginDeviceUsage records another sign-in from a device
+func UpdateLoginDeviceUsage(ctx context.Context, d *LoginDevice) error {
+	d.LastUsedUnix = timeutil.TimeStampNow()
+	_, err := db.GetEngine(ctx).ID(d.ID).Cols("user_agent", "ip", "location", "last_used_unix").Update(d)
+	return err
+}
+
+// GetLoginDevices returns the devices of a user, the most recently used first
+func GetLoginDevices(ctx context.Context, uid int64) ([]*LoginDevice, error) {
+	devices := make([]*LoginDevice, 0, 5)
+	return devices, db.GetEngine(ctx).Where("uid = ?", uid).Desc("last_used_unix").Find(&devices)
+}
+
+// DeleteLoginDevice removes a device of a user, the next sign-in from it is treated like one from a new device
+func DeleteLoginDevice(ctx context.Context, uid, id int64) error {
+	n, err := db.GetEngine(ctx).ID(id).Where("uid = ?", uid).Delete(new(LoginDevice))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrLoginDeviceNotExist{ID: id}
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package auth_test
+
+import (
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoginDevices(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	has, err := auth_model.HasLoginDevices(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.False(t, has)
+
+	d, err := auth_model.GetLoginDevice(db.DefaultContext, 2, "fingerprint")
+	assert.NoError(t, err)
+	assert.Nil(t, d)
+
+	d = &auth_model.LoginDevice{UID: 2, Fingerprint: "fingerprint", UserAgent: "Firefox", IP: "192.0.2.1"}
+	assert.NoError(t, auth_model.CreateLoginDevice(db.DefaultContext, d))
+	assert.NotZero(t, d.LastUsedUnix)
+
+	has, err = auth_model.HasLoginDevices(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.True(t, has)
+
+	// the fingerprint of a device is only known for the user who signed in from it
+	other, err := auth_model.GetLoginDevice(db.DefaultContext, 4, "fingerprint")
+	assert.NoError(t, err)
+	assert.Nil(t, other)
+
+	d.IP = "198.51.100.1"
+	d.Location = "Amsterdam, Netherlands"
+	assert.NoError(t, auth_model.UpdateLoginDeviceUsage(db.DefaultContext, d))
+
+	loaded, err := auth_model.GetLoginDevice(db.DefaultContext, 2, "fingerprint")
+	assert.NoError(t, err)
+	if assert.NotNil(t, loaded) {
+		assert.Equal(t, "198.51.100.1", loaded.IP)
+		assert.Equal(t, "Amsterdam, Netherlands", loaded.Location)
+	}
+
+	devices, err := auth_model.GetLoginDevices(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.Len(t, devices, 1)
+
+	err = auth_model.DeleteLoginDevice(db.DefaultContext, 4, d.ID)
+	assert.True(t, auth_model.IsErrLoginDeviceNotExist(err))
+
+	assert.NoError(t, auth_model.DeleteLoginDevice(db.DefaultContext, 2, d.ID))
+	unittest.AssertNotExistsBean(t, &auth_model.LoginDevice{ID: d.ID})
+}
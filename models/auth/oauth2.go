@@ -13,6 +13,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
@@ -34,10 +35,16 @@ type OAuth2Application struct {
 	// https://datatracker.ietf.org/doc/html/rfc6749#section-2.1
 	// "Authorization servers MUST record the client type in the client registration details"
 	// https://datatracker.ietf.org/doc/html/rfc8252#section-8.4
-	ConfidentialClient bool               `xorm:"NOT NULL DEFAULT TRUE"`
-	RedirectURIs       []string           `xorm:"redirect_uris JSON TEXT"`
-	CreatedUnix        timeutil.TimeStamp `xorm:"INDEX created"`
-	UpdatedUnix        timeutil.TimeStamp `xorm:"INDEX updated"`
+	ConfidentialClient bool     `xorm:"NOT NULL DEFAULT TRUE"`
+	RedirectURIs       []string `xorm:"redirect_uris JSON TEXT"`
+	// Scopes are the normalized API scopes users can grant to the application, all scopes if empty
+	Scopes []string `xorm:"JSON TEXT"`
+	// AccessTokenExpirationTime is the lifetime of its access tokens in seconds, 0 to use the one of the config
+	AccessTokenExpirationTime int64 `xorm:"NOT NULL DEFAULT 0"`
+	// RefreshTokenExpirationTime is the lifetime of its refresh tokens in hours, 0 to use the one of the config
+	RefreshTokenExpirationTime int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix                timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix                timeutil.TimeStamp `xorm:"INDEX updated"`
 }
 
 func init() {
@@ -78,6 +85,94 @@ func (app *OAuth2Application) ContainsRedirectURI(redirectURI string) bool {
 	return util.SliceContainsString(app.RedirectURIs, redirectURI, true)
 }
 
+// AccessTokenLifetime returns the lifetime of the access tokens of the application in seconds
+func (app *OAuth2Application) AccessTokenLifetime() int64 {
+	if app.AccessTokenExpirationTime > 0 {
+		return app.AccessTokenExpirationTime
+	}
+	return setting.OAuth2.AccessTokenExpirationTime
+}
+
+// RefreshTokenLifetime returns the lifetime of the refresh tokens of the application in hours
+func (app *OAuth2Application) RefreshTokenLifetime() int64 {
+	if app.RefreshTokenExpirationTime > 0 {
+		return app.RefreshTokenExpirationTime
+	}
+	return setting.OAuth2.RefreshTokenExpirationTime
+}
+
+// IsScopeRestricted returns if users can only grant some API scopes to the application
+func (app *OAuth2Application) IsScopeRestricted() bool {
+	return len(app.Scopes) > 0
+}
+
+// RequestedAccessTokenScope returns the API scopes of a space separated authorization request scope,
+// the other scopes like "openid" are skipped. If no API scope is requested, all scopes the application
+// can be granted are returned. An error is returned if a scope is requested the application can't be granted.
+func (app *OAuth2Application) RequestedAccessTokenScope(requestScope string) (AccessTokenScope, error) {
+	requested := requestedAccessTokenScopeBits(requestScope)
+	if !app.IsScopeRestricted() {
+		if requested == 0 {
+			return AccessTokenScopeAll, nil
+		}
+		return requested.ToScope(), nil
+	}
+
+	allowed, err := AccessTokenScope(strings.Join(app.Scopes, ",")).Parse()
+	if err != nil {
+		return "", err
+	}
+	if requested == 0 {
+		return allowed.ToScope(), nil
+	}
+	if requested&allowed != requested {
+		return "", util.NewInvalidArgumentErrorf("the application can only be granted the scopes %s", strings.Join(app.Scopes, ", "))
+	}
+	return requested.ToScope(), nil
+}
+
+func requestedAccessTokenScopeBits(requestScope string) AccessTokenScopeBitmap {
+	var requested AccessTokenScopeBitmap
+	for _, scope := range strings.Fields(requestScope) {
+		bits, err := AccessTokenScope(scope).Parse()
+		if err != nil {
+			// not an API scope
+			continue
+		}
+		requested |= bits
+	}
+	return requested
+}
+
+// GrantedAccessTokenScope returns the API scopes of the tokens of a grant,
+// limited to the ones the application can currently be granted
+func (app *OAuth2Application) GrantedAccessTokenScope(grant *OAuth2Grant) (AccessTokenScope, error) {
+	if !app.IsScopeRestricted() {
+		return grant.AccessTokenScope, nil
+	}
+	granted, err := grant.AccessTokenScope.Parse()
+	if err != nil {
+		return "", err
+	}
+	allowed, err := AccessTokenScope(strings.Join(app.Scopes, ",")).Parse()
+	if err != nil {
+		return "", err
+	}
+	return (granted & allowed).ToScope(), nil
+}
+
+// NormalizeOAuth2ApplicationScopes validates and normalizes the API scopes an application can be granted
+func NormalizeOAuth2ApplicationScopes(scopes []string) ([]string, error) {
+	normalized, err := AccessTokenScope(strings.Join(scopes, ",")).Normalize()
+	if err != nil {
+		return nil, util.NewInvalidArgumentErrorf("%v", err)
+	}
+	if normalized == "" {
+		return nil, nil
+	}
+	return normalized.StringSlice(), nil
+}
+
 // Base32 characters, but lowercased.
 const lowerBase32Chars = "abcdefghijklmnopqrstuvwxyz234567"
 
@@ -122,11 +217,12 @@ func (app *OAuth2Application) GetGrantByUserID(ctx context.Context, userID int64
 }
 
 // CreateGrant generates a grant for an user
-func (app *OAuth2Application) CreateGrant(ctx context.Context, userID int64, scope string) (*OAuth2Grant, error) {
+func (app *OAuth2Application) CreateGrant(ctx context.Context, userID int64, scope string, accessTokenScope AccessTokenScope) (*OAuth2Grant, error) {
 	grant := &OAuth2Grant{
-		ApplicationID: app.ID,
-		UserID:        userID,
-		Scope:         scope,
+		ApplicationID:    app.ID,
+		UserID:           userID,
+		Scope:            scope,
+		AccessTokenScope: accessTokenScope,
 	}
 	err := db.Insert(ctx, grant)
 	if err != nil {
@@ -171,6 +267,7 @@ type CreateOAuth2ApplicationOptions struct {
 	UserID             int64
 	ConfidentialClient bool
 	RedirectURIs       []string
+	Scopes             []string
 }
 
 // CreateOAuth2Application inserts a new oauth2 application
@@ -182,6 +279,7 @@ func CreateOAuth2Application(ctx context.Context, opts CreateOAuth2ApplicationOp
 		ClientID:           clientID,
 		RedirectURIs:       opts.RedirectURIs,
 		ConfidentialClient: opts.ConfidentialClient,
+		Scopes:             opts.Scopes,
 	}
 	if err := db.Insert(ctx, app); err != nil {
 		return nil, err
@@ -196,6 +294,7 @@ type UpdateOAuth2ApplicationOptions struct {
 	UserID             int64
 	ConfidentialClient bool
 	RedirectURIs       []string
+	Scopes             []string
 }
 
 // UpdateOAuth2Application updates an oauth2 application
@@ -217,6 +316,7 @@ func UpdateOAuth2Application(opts UpdateOAuth2ApplicationOptions) (*OAuth2Applic
 	app.Name = opts.Name
 	app.RedirectURIs = opts.RedirectURIs
 	app.ConfidentialClient = opts.ConfidentialClient
+	app.Scopes = opts.Scopes
 
 	if err = updateOAuth2Application(ctx, app); err != nil {
 		return nil, err
@@ -227,12 +327,21 @@ func UpdateOAuth2Application(opts UpdateOAuth2ApplicationOptions) (*OAuth2Applic
 }
 
 func updateOAuth2Application(ctx context.Context, app *OAuth2Application) error {
-	if _, err := db.GetEngine(ctx).ID(app.ID).UseBool("confidential_client").Update(app); err != nil {
+	if _, err := db.GetEngine(ctx).ID(app.ID).UseBool("confidential_client").MustCols("scopes").Update(app); err != nil {
 		return err
 	}
 	return nil
 }
 
+// SetOAuth2ApplicationTokenLifetimes sets the lifetimes of the tokens of an application,
+// the access token one in seconds and the refresh token one in hours, 0 to use the ones of the config
+func SetOAuth2ApplicationTokenLifetimes(ctx context.Context, app *OAuth2Application, accessTokenLifetime, refreshTokenLifetime int64) error {
+	app.AccessTokenExpirationTime = accessTokenLifetime
+	app.RefreshTokenExpirationTime = refreshTokenLifetime
+	_, err := db.GetEngine(ctx).ID(app.ID).Cols("access_token_expiration_time", "refresh_token_expiration_time").Update(app)
+	return err
+}
+
 func deleteOAuth2Application(ctx context.Context, id, userid int64) error {
 	sess := db.GetEngine(ctx)
 	// the userid could be 0 if the app is instance-wide
@@ -378,9 +487,11 @@ type OAuth2Grant struct {
 	ApplicationID int64              `xorm:"INDEX unique(user_application)"`
 	Counter       int64              `xorm:"NOT NULL DEFAULT 1"`
 	Scope         string             `xorm:"TEXT"`
-	Nonce         string             `xorm:"TEXT"`
-	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
-	UpdatedUnix   timeutil.TimeStamp `xorm:"updated"`
+	// AccessTokenScope are the API scopes the user granted, the grants of before the scopes could be chosen have all
+	AccessTokenScope AccessTokenScope   `xorm:"TEXT"`
+	Nonce            string             `xorm:"TEXT"`
+	CreatedUnix      timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix      timeutil.TimeStamp `xorm:"updated"`
 }
 
 // TableName sets the table name to `oauth2_grant`
@@ -436,6 +547,28 @@ func (grant *OAuth2Grant) ScopeContains(scope string) bool {
 	return false
 }
 
+// CoversRequestedScope returns if the user granted the API scopes of a space separated authorization request scope,
+// which is always the case if no API scope is requested
+func (grant *OAuth2Grant) CoversRequestedScope(requestScope string) bool {
+	requested := requestedAccessTokenScopeBits(requestScope)
+	if requested == 0 {
+		return true
+	}
+	granted, err := grant.AccessTokenScope.Parse()
+	if err != nil {
+		return false
+	}
+	return requested&granted == requested
+}
+
+// UpdateScope replaces the requested and the API scopes of a grant after the user consented again
+func (grant *OAuth2Grant) UpdateScope(ctx context.Context, scope string, accessTokenScope AccessTokenScope) error {
+	grant.Scope = scope
+	grant.AccessTokenScope = accessTokenScope
+	_, err := db.GetEngine(ctx).ID(grant.ID).Cols("scope", "access_token_scope").Update(grant)
+	return err
+}
+
 // SetNonce updates the current nonce value of a grant
 func (grant *OAuth2Grant) SetNonce(ctx context.Context, nonce string) error {
 	grant.Nonce = nonce
@@ -9,6 +9,7 @@ import (
 	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -111,7 +112,7 @@ func TestOAuth2Application_GetGrantByUserID(t *testing.T) {
 func TestOAuth2Application_CreateGrant(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 	app := unittest.AssertExistsAndLoadBean(t, &auth_model.OAuth2Application{ID: 1})
-	grant, err := app.CreateGrant(db.DefaultContext, 2, "")
+	grant, err := app.CreateGrant(db.DefaultContext, 2, "", auth_model.AccessTokenScopeAll)
 	assert.NoError(t, err)
 	assert.NotNil(t, grant)
 	assert.Equal(t, int64(2), grant.UserID)
@@ -119,6 +120,56 @@ func TestOAuth2Application_CreateGrant(t *testing.T) {
 	assert.Equal(t, "", grant.Scope)
 }
 
+func TestOAuth2Application_RequestedAccessTokenScope(t *testing.T) {
+	app := &auth_model.OAuth2Application{}
+	scope, err := app.RequestedAccessTokenScope("openid profile")
+	assert.NoError(t, err)
+	assert.Equal(t, auth_model.AccessTokenScopeAll, scope)
+	scope, err = app.RequestedAccessTokenScope("openid read:org read:user")
+	assert.NoError(t, err)
+	assert.Equal(t, auth_model.AccessTokenScope("read:org,read:user"), scope)
+
+	app.Scopes = []string{"repo", "read:org"}
+	scope, err = app.RequestedAccessTokenScope("openid")
+	assert.NoError(t, err)
+	assert.Equal(t, auth_model.AccessTokenScope("repo,read:org"), scope)
+	scope, err = app.RequestedAccessTokenScope("openid public_repo")
+	assert.NoError(t, err)
+	assert.Equal(t, auth_model.AccessTokenScope("public_repo"), scope)
+	_, err = app.RequestedAccessTokenScope("write:org")
+	assert.Error(t, err)
+}
+
+func TestOAuth2Application_GrantedAccessTokenScope(t *testing.T) {
+	app := &auth_model.OAuth2Application{}
+	grant := &auth_model.OAuth2Grant{AccessTokenScope: auth_model.AccessTokenScopeAll}
+	scope, err := app.GrantedAccessTokenScope(grant)
+	assert.NoError(t, err)
+	assert.Equal(t, auth_model.AccessTokenScopeAll, scope)
+
+	// the grants are limited to the scopes the application can be granted now
+	app.Scopes = []string{"read:org"}
+	scope, err = app.GrantedAccessTokenScope(grant)
+	assert.NoError(t, err)
+	assert.Equal(t, auth_model.AccessTokenScope("read:org"), scope)
+	grant.AccessTokenScope = "repo"
+	scope, err = app.GrantedAccessTokenScope(grant)
+	assert.NoError(t, err)
+	assert.Equal(t, auth_model.AccessTokenScope(""), scope)
+}
+
+func TestOAuth2Application_TokenLifetimes(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	app := unittest.AssertExistsAndLoadBean(t, &auth_model.OAuth2Application{ID: 1})
+	assert.Equal(t, setting.OAuth2.AccessTokenExpirationTime, app.AccessTokenLifetime())
+	assert.Equal(t, setting.OAuth2.RefreshTokenExpirationTime, app.RefreshTokenLifetime())
+
+	assert.NoError(t, auth_model.SetOAuth2ApplicationTokenLifetimes(db.DefaultContext, app, 600, 24))
+	app = unittest.AssertExistsAndLoadBean(t, &auth_model.OAuth2Application{ID: 1})
+	assert.EqualValues(t, 600, app.AccessTokenLifetime())
+	assert.EqualValues(t, 24, app.RefreshTokenLifetime())
+}
+
 //////////////////// Grant
 
 func TestGetOAuth2GrantByID(t *testing.T) {
@@ -149,6 +200,15 @@ func TestOAuth2Grant_ScopeContains(t *testing.T) {
 	assert.False(t, grant.ScopeContains("profile2"))
 }
 
+func TestOAuth2Grant_CoversRequestedScope(t *testing.T) {
+	grant := &auth_model.OAuth2Grant{AccessTokenScope: "repo,read:org"}
+	assert.True(t, grant.CoversRequestedScope("openid"))
+	assert.True(t, grant.CoversRequestedScope("openid public_repo read:org"))
+	assert.False(t, grant.CoversRequestedScope("write:org"))
+	grant.AccessTokenScope = auth_model.AccessTokenScopeAll
+	assert.True(t, grant.CoversRequestedScope("write:org"))
+}
+
 func TestOAuth2Grant_GenerateNewAuthorizationCode(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 	grant := unittest.AssertExistsAndLoadBean(t, &auth_model.OAuth2Grant{ID: 1})
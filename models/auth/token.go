@@ -5,9 +5,11 @@
 package auth
 
 import (
+	"context"
 	"crypto/subtle"
 	"encoding/hex"
 	"fmt"
+	"strings"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
@@ -16,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/util"
 
 	lru "github.com/hashicorp/golang-lru"
+	"xorm.io/builder"
 )
 
 // ErrAccessTokenNotExist represents a "AccessTokenNotExist" kind of error.
@@ -79,6 +82,26 @@ func (t *AccessToken) AfterLoad() {
 	t.HasRecentActivity = t.UpdatedUnix.AddDuration(7*24*time.Hour) > timeutil.TimeStampNow()
 }
 
+// ExpiresUnix returns when the token expires according to the token expiry policy, 0 if it never expires
+func (t *AccessToken) ExpiresUnix() timeutil.TimeStamp {
+	var expires timeutil.TimeStamp
+	if setting.AccessTokenMaxLifetime > 0 {
+		expires = t.CreatedUnix.AddDuration(setting.AccessTokenMaxLifetime)
+	}
+	if setting.AccessTokenMaxInactivity > 0 {
+		if inactive := t.UpdatedUnix.AddDuration(setting.AccessTokenMaxInactivity); expires == 0 || inactive < expires {
+			expires = inactive
+		}
+	}
+	return expires
+}
+
+// IsExpired returns true if the token can no longer be used according to the token expiry policy
+func (t *AccessToken) IsExpired() bool {
+	expires := t.ExpiresUnix()
+	return expires != 0 && expires <= timeutil.TimeStampNow()
+}
+
 func init() {
 	db.RegisterModel(new(AccessToken), func() error {
 		if setting.SuccessfulTokensCacheSize > 0 {
@@ -154,6 +177,9 @@ func GetAccessTokenBySHA(token string) (*AccessToken, error) {
 			return nil, err
 		}
 		if has {
+			if token.IsExpired() {
+				return nil, ErrAccessTokenNotExist{lastEight}
+			}
 			return token, nil
 		}
 		successfulAccessTokenCache.Remove(token)
@@ -170,6 +196,9 @@ func GetAccessTokenBySHA(token string) (*AccessToken, error) {
 	for _, t := range tokens {
 		tempHash := HashToken(token, t.TokenSalt)
 		if subtle.ConstantTimeCompare([]byte(t.TokenHash), []byte(tempHash)) == 1 {
+			if t.IsExpired() {
+				return nil, ErrAccessTokenNotExist{lastEight}
+			}
 			if successfulAccessTokenCache != nil {
 				successfulAccessTokenCache.Add(token, t.ID)
 			}
@@ -179,6 +208,44 @@ func GetAccessTokenBySHA(token string) (*AccessToken, error) {
 	return nil, ErrAccessTokenNotExist{token}
 }
 
+// FindAccessTokensByValues returns the access tokens matching any of the given token values,
+// the Token field of each result is set to the matching value.
+// It is used to find tokens which were leaked, so expired tokens are included.
+func FindAccessTokensByValues(ctx context.Context, values []string) ([]*AccessToken, error) {
+	byLastEight := make(map[string][]string, len(values))
+	for _, value := range values {
+		if len(value) != 40 {
+			continue
+		}
+		lastEight := value[len(value)-8:]
+		byLastEight[lastEight] = append(byLastEight[lastEight], value)
+	}
+	if len(byLastEight) == 0 {
+		return nil, nil
+	}
+	lastEights := make([]string, 0, len(byLastEight))
+	for lastEight := range byLastEight {
+		lastEights = append(lastEights, lastEight)
+	}
+
+	candidates := make([]*AccessToken, 0, len(lastEights))
+	if err := db.GetEngine(ctx).In("token_last_eight", lastEights).Find(&candidates); err != nil {
+		return nil, err
+	}
+
+	var tokens []*AccessToken
+	for _, t := range candidates {
+		for _, value := range byLastEight[t.TokenLastEight] {
+			if subtle.ConstantTimeCompare([]byte(t.TokenHash), []byte(HashToken(value, t.TokenSalt))) == 1 {
+				t.Token = value
+				tokens = append(tokens, t)
+				break
+			}
+		}
+	}
+	return tokens, nil
+}
+
 // AccessTokenByNameExists checks if a token name has been used already by a user.
 func AccessTokenByNameExists(token *AccessToken) (bool, error) {
 	return db.GetEngine(db.DefaultContext).Table("access_token").Where("name = ?", token.Name).And("uid = ?", token.UID).Exist()
@@ -227,6 +294,58 @@ func CountAccessTokens(opts ListAccessTokensOptions) (int64, error) {
 	return sess.Count(&AccessToken{})
 }
 
+// FindAccessTokensOptions are the options to find the access tokens of all users
+type FindAccessTokensOptions struct {
+	db.ListOptions
+	UserID int64
+	// NamePattern matches the token names, "*" matches any characters
+	NamePattern string
+	// UnusedSince only matches tokens which have not been used since then
+	UnusedSince timeutil.TimeStamp
+}
+
+// IsFiltered returns true if the options restrict the tokens in any way
+func (opts *FindAccessTokensOptions) IsFiltered() bool {
+	return opts.UserID != 0 || opts.NamePattern != "" || opts.UnusedSince != 0
+}
+
+// ToConds implements db.FindOptions interface
+func (opts *FindAccessTokensOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.UserID != 0 {
+		cond = cond.And(builder.Eq{"uid": opts.UserID})
+	}
+	if opts.NamePattern != "" {
+		if strings.Contains(opts.NamePattern, "*") {
+			// "!" is used as escape character as a backslash needs escaping itself in MySQL
+			escaped := strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(opts.NamePattern)
+			cond = cond.And(builder.Expr("name LIKE ? ESCAPE '!'", strings.ReplaceAll(escaped, "*", "%")))
+		} else {
+			cond = cond.And(builder.Eq{"name": opts.NamePattern})
+		}
+	}
+	if opts.UnusedSince != 0 {
+		cond = cond.And(builder.Lt{"updated_unix": opts.UnusedSince})
+	}
+	return cond
+}
+
+// FindAccessTokens returns the access tokens of all users matching the options
+func FindAccessTokens(ctx context.Context, opts *FindAccessTokensOptions) ([]*AccessToken, int64, error) {
+	sess := db.GetEngine(ctx).Where(opts.ToConds()).Desc("created_unix")
+	if opts.Page != 0 {
+		sess = db.SetSessionPagination(sess, opts)
+	}
+	tokens := make([]*AccessToken, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&tokens)
+	return tokens, count, err
+}
+
+// DeleteAccessTokens deletes all access tokens matching the options and returns how many were deleted
+func DeleteAccessTokens(ctx context.Context, opts *FindAccessTokensOptions) (int64, error) {
+	return db.GetEngine(ctx).Where(opts.ToConds()).Delete(&AccessToken{})
+}
+
 // DeleteAccessTokenByID deletes access token by given ID.
 func DeleteAccessTokenByID(id, userID int64) error {
 	cnt, err := db.GetEngine(db.DefaultContext).ID(id).Delete(&AccessToken{
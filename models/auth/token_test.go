@@ -5,9 +5,13 @@ package auth_test
 
 import (
 	"testing"
+	"time"
 
 	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -129,3 +133,51 @@ func TestDeleteAccessTokenByID(t *testing.T) {
 	assert.Error(t, err)
 	assert.True(t, auth_model.IsErrAccessTokenNotExist(err))
 }
+
+func TestFindAccessTokens(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	tokens, count, err := auth_model.FindAccessTokens(db.DefaultContext, &auth_model.FindAccessTokensOptions{NamePattern: "Token *"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, count)
+	assert.Len(t, tokens, 3)
+
+	_, count, err = auth_model.FindAccessTokens(db.DefaultContext, &auth_model.FindAccessTokensOptions{UserID: 1, NamePattern: "Token A"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	_, count, err = auth_model.FindAccessTokens(db.DefaultContext, &auth_model.FindAccessTokensOptions{NamePattern: "Token_*"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, count)
+}
+
+func TestDeleteAccessTokens(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	deleted, err := auth_model.DeleteAccessTokens(db.DefaultContext, &auth_model.FindAccessTokensOptions{UserID: 1, UnusedSince: timeutil.TimeStampNow()})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, deleted)
+	unittest.AssertNotExistsBean(t, &auth_model.AccessToken{UID: 1})
+	unittest.AssertExistsAndLoadBean(t, &auth_model.AccessToken{ID: 3})
+}
+
+func TestAccessTokenIsExpired(t *testing.T) {
+	defer func(lifetime, inactivity time.Duration) {
+		setting.AccessTokenMaxLifetime, setting.AccessTokenMaxInactivity = lifetime, inactivity
+	}(setting.AccessTokenMaxLifetime, setting.AccessTokenMaxInactivity)
+
+	now := timeutil.TimeStampNow()
+	token := &auth_model.AccessToken{CreatedUnix: now.AddDuration(-48 * time.Hour), UpdatedUnix: now.AddDuration(-time.Hour)}
+
+	setting.AccessTokenMaxLifetime, setting.AccessTokenMaxInactivity = 0, 0
+	assert.EqualValues(t, 0, token.ExpiresUnix())
+	assert.False(t, token.IsExpired())
+
+	setting.AccessTokenMaxInactivity = 2 * time.Hour
+	assert.Equal(t, now.AddDuration(time.Hour), token.ExpiresUnix())
+	assert.False(t, token.IsExpired())
+
+	setting.AccessTokenMaxLifetime = 24 * time.Hour
+	assert.Equal(t, now.AddDuration(-24*time.Hour), token.ExpiresUnix())
+	assert.True(t, token.IsExpired())
+}
@@ -56,6 +56,22 @@ func DefaultAvatarLink() string {
 	return defaultAvatarLink
 }
 
+type enforceDefaultAvatarsKeyType struct{}
+
+var enforceDefaultAvatarsKey = enforceDefaultAvatarsKeyType{}
+
+// WithDefaultAvatarsEnforced returns a context in which users are rendered with the default avatar
+func WithDefaultAvatarsEnforced(ctx context.Context) context.Context {
+	return context.WithValue(ctx, enforceDefaultAvatarsKey, true)
+}
+
+// IsDefaultAvatarEnforced checks if users must be rendered with the default avatar,
+// e.g. on the pages of an organization with an avatar policy
+func IsDefaultAvatarEnforced(ctx context.Context) bool {
+	enforced, _ := ctx.Value(enforceDefaultAvatarsKey).(bool)
+	return enforced
+}
+
 // HashEmail hashes email address to MD5 string. https://en.gravatar.com/site/implement/hash/
 func HashEmail(email string) string {
 	return base.EncodeMD5(strings.ToLower(strings.TrimSpace(email)))
@@ -89,9 +105,9 @@ func LibravatarURL(email string) (*url.URL, error) {
 	return u, nil
 }
 
-// saveEmailHash returns an avatar link for a provided email,
+// SaveEmailHash returns the hash of a provided email,
 // the email and hash are saved into database, which will be used by GetEmailForHash later
-func saveEmailHash(email string) string {
+func SaveEmailHash(email string) string {
 	lowerEmail := strings.ToLower(strings.TrimSpace(email))
 	emailHash := HashEmail(lowerEmail)
 	_, _ = cache.GetString("Avatar:"+emailHash, func() (string, error) {
@@ -157,7 +173,7 @@ func generateEmailAvatarLink(ctx context.Context, email string, size int, final
 
 	var err error
 	if enableFederatedAvatar && system_model.LibravatarService != nil {
-		emailHash := saveEmailHash(email)
+		emailHash := SaveEmailHash(email)
 		if final {
 			// for final link, we can spend more time on slow external query
 			var avatarURL *url.URL
@@ -0,0 +1,92 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package chatops
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// AuditStatus is the outcome of a command execution
+type AuditStatus string
+
+const (
+	// AuditStatusSuccess means the command ran successfully
+	AuditStatusSuccess AuditStatus = "success"
+	// AuditStatusDenied means the user was not allowed to run the command
+	AuditStatusDenied AuditStatus = "denied"
+	// AuditStatusFailed means the command was invalid or failed
+	AuditStatusFailed AuditStatus = "failed"
+)
+
+// Audit records the execution of a command
+type Audit struct {
+	ID        int64       `xorm:"pk autoincr"`
+	RepoID    int64       `xorm:"INDEX NOT NULL"`
+	IssueID   int64       `xorm:"INDEX NOT NULL"`
+	CommentID int64       `xorm:"NOT NULL DEFAULT 0"`
+	DoerID    int64       `xorm:"INDEX NOT NULL"`
+	Command   string      `xorm:"VARCHAR(50) INDEX NOT NULL"`
+	Args      []string    `xorm:"JSON TEXT"`
+	Status    AuditStatus `xorm:"VARCHAR(20) NOT NULL"`
+	Message   string      `xorm:"TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+// TableName sets the table name of the chatops audit
+func (Audit) TableName() string {
+	return "chatops_audit"
+}
+
+func init() {
+	db.RegisterModel(new(Audit))
+}
+
+// InsertAudit records a command execution
+func InsertAudit(ctx context.Context, audit *Audit) error {
+	return db.Insert(ctx, audit)
+}
+
+// FindAuditOptions represents the options to search the command executions
+type FindAuditOptions struct {
+	db.ListOptions
+	RepoID  int64
+	DoerID  int64
+	Command string
+	Status  AuditStatus
+}
+
+// ToConds implements db.FindOptions
+func (opts FindAuditOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.DoerID > 0 {
+		cond = cond.And(builder.Eq{"doer_id": opts.DoerID})
+	}
+	if opts.Command != "" {
+		cond = cond.And(builder.Eq{"command": opts.Command})
+	}
+	if opts.Status != "" {
+		cond = cond.And(builder.Eq{"status": opts.Status})
+	}
+	return cond
+}
+
+// FindAudits returns the command executions matching the options, newest first
+func FindAudits(ctx context.Context, opts FindAuditOptions) ([]*Audit, int64, error) {
+	sess := db.GetEngine(ctx).Where(opts.ToConds()).OrderBy("id DESC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts.ListOptions)
+	}
+	audits := make([]*Audit, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&audits)
+	return audits, count, err
+}
@@ -0,0 +1,143 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package chatops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrCommandNotExist represents a "CommandNotExist" kind of error.
+type ErrCommandNotExist struct {
+	ID   int64
+	Name string
+}
+
+// IsErrCommandNotExist checks if an error is a ErrCommandNotExist.
+func IsErrCommandNotExist(err error) bool {
+	_, ok := err.(ErrCommandNotExist)
+	return ok
+}
+
+func (err ErrCommandNotExist) Error() string {
+	return fmt.Sprintf("chatops command does not exist [id: %d, name: %s]", err.ID, err.Name)
+}
+
+func (err ErrCommandNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// ErrCommandAlreadyExist represents a "CommandAlreadyExist" kind of error.
+type ErrCommandAlreadyExist struct {
+	Name string
+}
+
+// IsErrCommandAlreadyExist checks if an error is a ErrCommandAlreadyExist.
+func IsErrCommandAlreadyExist(err error) bool {
+	_, ok := err.(ErrCommandAlreadyExist)
+	return ok
+}
+
+func (err ErrCommandAlreadyExist) Error() string {
+	return fmt.Sprintf("chatops command already exists [name: %s]", err.Name)
+}
+
+func (err ErrCommandAlreadyExist) Unwrap() error {
+	return util.ErrAlreadyExist
+}
+
+// Command is a slash command registered by a site administrator which is executed by calling a webhook
+type Command struct {
+	ID          int64           `xorm:"pk autoincr"`
+	Name        string          `xorm:"VARCHAR(50) UNIQUE NOT NULL"`
+	Description string          `xorm:"TEXT"`
+	URL         string          `xorm:"TEXT NOT NULL"`
+	Secret      string          `xorm:"TEXT"`
+	AccessMode  perm.AccessMode `xorm:"NOT NULL DEFAULT 2"`
+	IsActive    bool            `xorm:"INDEX NOT NULL DEFAULT true"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// TableName sets the table name of the chatops command
+func (Command) TableName() string {
+	return "chatops_command"
+}
+
+func init() {
+	db.RegisterModel(new(Command))
+}
+
+// GetCommandByID returns the command with the given id
+func GetCommandByID(ctx context.Context, id int64) (*Command, error) {
+	cmd := &Command{}
+	has, err := db.GetEngine(ctx).ID(id).Get(cmd)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrCommandNotExist{ID: id}
+	}
+	return cmd, nil
+}
+
+// GetCommandByName returns the command with the given name
+func GetCommandByName(ctx context.Context, name string) (*Command, error) {
+	cmd := &Command{}
+	has, err := db.GetEngine(ctx).Where("name = ?", strings.ToLower(name)).Get(cmd)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrCommandNotExist{Name: name}
+	}
+	return cmd, nil
+}
+
+// FindCommands returns the registered commands ordered by name
+func FindCommands(ctx context.Context, opts db.ListOptions) ([]*Command, int64, error) {
+	sess := db.GetEngine(ctx).OrderBy("name ASC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+	cmds := make([]*Command, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&cmds)
+	return cmds, count, err
+}
+
+// CreateCommand registers a new command
+func CreateCommand(ctx context.Context, cmd *Command) error {
+	cmd.Name = strings.ToLower(cmd.Name)
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		has, err := db.GetEngine(ctx).Where("name = ?", cmd.Name).Exist(new(Command))
+		if err != nil {
+			return err
+		} else if has {
+			return ErrCommandAlreadyExist{Name: cmd.Name}
+		}
+		return db.Insert(ctx, cmd)
+	})
+}
+
+// UpdateCommand updates the given columns of a command
+func UpdateCommand(ctx context.Context, cmd *Command, cols ...string) error {
+	_, err := db.GetEngine(ctx).ID(cmd.ID).Cols(cols...).Update(cmd)
+	return err
+}
+
+// DeleteCommandByID removes a command
+func DeleteCommandByID(ctx context.Context, id int64) error {
+	n, err := db.GetEngine(ctx).ID(id).Delete(new(Command))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrCommandNotExist{ID: id}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package chatops
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCreateCommand(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	cmd := &Command{Name: "Deploy", URL: "https://example.com/deploy", AccessMode: perm.AccessModeWrite, IsActive: true}
+	assert.NoError(t, CreateCommand(db.DefaultContext, cmd))
+	assert.Equal(t, "deploy", cmd.Name)
+
+	err := CreateCommand(db.DefaultContext, &Command{Name: "deploy", URL: "https://example.com/other"})
+	assert.True(t, IsErrCommandAlreadyExist(err))
+
+	loaded, err := GetCommandByName(db.DefaultContext, "DEPLOY")
+	assert.NoError(t, err)
+	assert.Equal(t, cmd.ID, loaded.ID)
+
+	assert.NoError(t, DeleteCommandByID(db.DefaultContext, cmd.ID))
+	_, err = GetCommandByID(db.DefaultContext, cmd.ID)
+	assert.True(t, IsErrCommandNotExist(err))
+}
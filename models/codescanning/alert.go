@@ -0,0 +1,188 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package codescanning
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/sarif"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// AlertState is the state of a code scanning alert
+type AlertState int
+
+const (
+	// AlertStateOpen is an alert which was reported by the latest analysis
+	AlertStateOpen AlertState = iota + 1
+	// AlertStateDismissed is an alert which was dismissed by a user
+	AlertStateDismissed
+	// AlertStateFixed is an alert which is no longer reported
+	AlertStateFixed
+)
+
+// String returns the name of the state
+func (s AlertState) String() string {
+	switch s {
+	case AlertStateOpen:
+		return "open"
+	case AlertStateDismissed:
+		return "dismissed"
+	case AlertStateFixed:
+		return "fixed"
+	}
+	return "unknown"
+}
+
+// ParseAlertState returns the state for the name, or 0 if the name is unknown
+func ParseAlertState(name string) AlertState {
+	for _, s := range []AlertState{AlertStateOpen, AlertStateDismissed, AlertStateFixed} {
+		if s.String() == name {
+			return s
+		}
+	}
+	return 0
+}
+
+// ErrAlertNotExist represents a "code scanning alert not exist" error
+type ErrAlertNotExist struct {
+	ID int64
+}
+
+func (err ErrAlertNotExist) Error() string {
+	return fmt.Sprintf("code scanning alert does not exist [id: %d]", err.ID)
+}
+
+func (err ErrAlertNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// Alert is a finding of a code scanning tool on a ref of a repository.
+// The same finding on the same ref is identified by its fingerprint across analyses.
+type Alert struct {
+	ID              int64              `xorm:"pk autoincr"`
+	RepoID          int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Ref             string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+	Fingerprint     string             `xorm:"VARCHAR(64) UNIQUE(s) NOT NULL"`
+	Tool            string             `xorm:"VARCHAR(255) INDEX"`
+	RuleID          string             `xorm:"VARCHAR(255)"`
+	RuleDescription string             `xorm:"TEXT"`
+	Severity        sarif.Severity     `xorm:"VARCHAR(20)"`
+	Message         string             `xorm:"TEXT"`
+	Path            string             `xorm:"TEXT"`
+	StartLine       int                `xorm:"NOT NULL DEFAULT 0"`
+	EndLine         int                `xorm:"NOT NULL DEFAULT 0"`
+	CommitSHA       string             `xorm:"VARCHAR(64)"`
+	State           AlertState         `xorm:"INDEX NOT NULL DEFAULT 1"`
+	DismissedByID   int64              `xorm:"NOT NULL DEFAULT 0"`
+	DismissedReason string             `xorm:"TEXT"`
+	FixedUnix       timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix     timeutil.TimeStamp `xorm:"updated"`
+}
+
+// TableName sets the table name
+func (Alert) TableName() string {
+	return "code_scanning_alert"
+}
+
+func init() {
+	db.RegisterModel(new(Alert))
+}
+
+// IsHighSeverity returns true for alerts which may block merging pull requests
+func (a *Alert) IsHighSeverity() bool {
+	return a.Severity.Rank() >= sarif.SeverityHigh.Rank()
+}
+
+// FindAlertsOptions represents the options to search code scanning alerts
+type FindAlertsOptions struct {
+	db.ListOptions
+	RepoID   int64
+	Ref      string
+	Tool     string
+	State    AlertState
+	Severity sarif.Severity
+}
+
+// ToConds implements db.FindOptions
+func (opts FindAlertsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.Ref != "" {
+		cond = cond.And(builder.Eq{"ref": opts.Ref})
+	}
+	if opts.Tool != "" {
+		cond = cond.And(builder.Eq{"tool": opts.Tool})
+	}
+	if opts.State > 0 {
+		cond = cond.And(builder.Eq{"state": opts.State})
+	}
+	if opts.Severity != "" {
+		cond = cond.And(builder.Eq{"severity": opts.Severity})
+	}
+	return cond
+}
+
+// FindAlerts returns the alerts matching the options, ordered by id
+func FindAlerts(ctx context.Context, opts FindAlertsOptions) ([]*Alert, int64, error) {
+	sess := db.GetEngine(ctx).Where(opts.ToConds()).OrderBy("id DESC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts.ListOptions)
+	}
+	alerts := make([]*Alert, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&alerts)
+	return alerts, count, err
+}
+
+// GetAlertByID returns the alert of the repository with the given id
+func GetAlertByID(ctx context.Context, repoID, id int64) (*Alert, error) {
+	alert := &Alert{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Get(alert)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrAlertNotExist{ID: id}
+	}
+	return alert, nil
+}
+
+// InsertAlert inserts a new alert
+func InsertAlert(ctx context.Context, alert *Alert) error {
+	return db.Insert(ctx, alert)
+}
+
+// UpdateAlert updates the given columns of an alert
+func UpdateAlert(ctx context.Context, alert *Alert, cols ...string) error {
+	_, err := db.GetEngine(ctx).ID(alert.ID).Cols(cols...).Update(alert)
+	return err
+}
+
+// DismissAlert marks an alert as dismissed by the doer
+func DismissAlert(ctx context.Context, alert *Alert, doerID int64, reason string) error {
+	alert.State = AlertStateDismissed
+	alert.DismissedByID = doerID
+	alert.DismissedReason = reason
+	return UpdateAlert(ctx, alert, "state", "dismissed_by_id", "dismissed_reason")
+}
+
+// ReopenAlert reopens a dismissed alert
+func ReopenAlert(ctx context.Context, alert *Alert) error {
+	alert.State = AlertStateOpen
+	alert.DismissedByID = 0
+	alert.DismissedReason = ""
+	return UpdateAlert(ctx, alert, "state", "dismissed_by_id", "dismissed_reason")
+}
+
+// DeleteAlertsByRepoID removes all alerts and analyses of a repository
+func DeleteAlertsByRepoID(ctx context.Context, repoID int64) error {
+	return db.DeleteBeans(ctx, &Alert{RepoID: repoID}, &Analysis{RepoID: repoID})
+}
@@ -0,0 +1,47 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package codescanning
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// Analysis is an uploaded SARIF report of a single tool
+type Analysis struct {
+	ID           int64              `xorm:"pk autoincr"`
+	RepoID       int64              `xorm:"INDEX NOT NULL"`
+	Ref          string             `xorm:"VARCHAR(255) INDEX"`
+	CommitSHA    string             `xorm:"VARCHAR(64)"`
+	Tool         string             `xorm:"VARCHAR(255)"`
+	ResultsCount int                `xorm:"NOT NULL DEFAULT 0"`
+	UploaderID   int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+}
+
+// TableName sets the table name
+func (Analysis) TableName() string {
+	return "code_scanning_analysis"
+}
+
+func init() {
+	db.RegisterModel(new(Analysis))
+}
+
+// InsertAnalysis stores a new analysis
+func InsertAnalysis(ctx context.Context, analysis *Analysis) error {
+	return db.Insert(ctx, analysis)
+}
+
+// GetLatestAnalyses returns the latest analysis of every tool for a ref
+func GetLatestAnalyses(ctx context.Context, repoID int64, ref string) ([]*Analysis, error) {
+	analyses := make([]*Analysis, 0, 2)
+	err := db.GetEngine(ctx).
+		Where("id IN (SELECT MAX(id) FROM code_scanning_analysis WHERE repo_id = ? AND ref = ? GROUP BY tool)", repoID, ref).
+		Asc("tool").
+		Find(&analyses)
+	return analyses, err
+}
@@ -4,6 +4,7 @@
 package db
 
 import (
+	"context"
 	"strings"
 
 	"code.gitea.io/gitea/modules/setting"
@@ -20,3 +21,45 @@ func BuildCaseInsensitiveLike(key, value string) builder.Cond {
 	}
 	return builder.Like{"UPPER(" + key + ")", strings.ToUpper(value)}
 }
+
+// SumGroupBy returns the sum of sumColumn for every value of the integer column groupColumn over the rows of table matching cond
+func SumGroupBy(ctx context.Context, table, groupColumn, sumColumn string, cond builder.Cond) (map[int64]int64, error) {
+	type groupSum struct {
+		GroupID int64
+		Total   int64
+	}
+	rows := make([]*groupSum, 0, 10)
+	if err := GetEngine(ctx).Table(table).
+		Select(groupColumn + " AS group_id, SUM(" + sumColumn + ") AS total").
+		Where(cond).
+		GroupBy(groupColumn).
+		Find(&rows); err != nil {
+		return nil, err
+	}
+
+	sums := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		sums[row.GroupID] = row.Total
+	}
+	return sums, nil
+}
+
+// ColumnStats are the aggregates of a numeric column
+type ColumnStats struct {
+	Count   int64
+	Total   int64
+	Minimum int64
+	Maximum int64
+}
+
+// GetColumnStats returns the aggregates of a numeric column over the rows matching cond
+func GetColumnStats(ctx context.Context, table, column string, cond builder.Cond) (*ColumnStats, error) {
+	stats := &ColumnStats{}
+	if _, err := GetEngine(ctx).Table(table).
+		Select("COUNT(*) AS count, COALESCE(SUM(" + column + "), 0) AS total, COALESCE(MIN(" + column + "), 0) AS minimum, COALESCE(MAX(" + column + "), 0) AS maximum").
+		Where(cond).
+		Get(stats); err != nil {
+		return nil, err
+	}
+	return stats, nil
+}
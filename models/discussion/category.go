@@ -0,0 +1,122 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package discussion
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CategoryKind changes how the discussions of a category behave
+type CategoryKind string
+
+const (
+	// CategoryKindGeneral is an open-ended conversation
+	CategoryKindGeneral CategoryKind = "general"
+	// CategoryKindQA discussions are questions, a reply can be accepted as answer
+	CategoryKindQA CategoryKind = "qa"
+	// CategoryKindAnnouncement discussions can only be started and replied to by writers
+	CategoryKindAnnouncement CategoryKind = "announcement"
+)
+
+// IsValid checks if the kind is known
+func (k CategoryKind) IsValid() bool {
+	switch k {
+	case CategoryKindGeneral, CategoryKindQA, CategoryKindAnnouncement:
+		return true
+	}
+	return false
+}
+
+// ErrCategoryNotExist represents a "DiscussionCategoryNotExist" kind of error.
+type ErrCategoryNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrCategoryNotExist checks if an error is a ErrCategoryNotExist.
+func IsErrCategoryNotExist(err error) bool {
+	_, ok := err.(ErrCategoryNotExist)
+	return ok
+}
+
+func (err ErrCategoryNotExist) Error() string {
+	return fmt.Sprintf("discussion category does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+func (err ErrCategoryNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// Category groups the discussions of a repository
+type Category struct {
+	ID          int64        `xorm:"pk autoincr"`
+	RepoID      int64        `xorm:"INDEX NOT NULL"`
+	Name        string       `xorm:"VARCHAR(255) NOT NULL"`
+	Description string       `xorm:"TEXT"`
+	Kind        CategoryKind `xorm:"VARCHAR(20) NOT NULL DEFAULT 'general'"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// TableName sets the table name of the category
+func (Category) TableName() string {
+	return "discussion_category"
+}
+
+func init() {
+	db.RegisterModel(new(Category))
+}
+
+// CreateCategory adds a category to a repository
+func CreateCategory(ctx context.Context, c *Category) error {
+	if !c.Kind.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid category kind %q", c.Kind)
+	}
+	return db.Insert(ctx, c)
+}
+
+// UpdateCategory changes the name, description and kind of a category
+func UpdateCategory(ctx context.Context, c *Category) error {
+	if !c.Kind.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid category kind %q", c.Kind)
+	}
+	_, err := db.GetEngine(ctx).ID(c.ID).Cols("name", "description", "kind").Update(c)
+	return err
+}
+
+// GetCategoryByID returns the category of the repository with the given id
+func GetCategoryByID(ctx context.Context, repoID, id int64) (*Category, error) {
+	c := &Category{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Get(c)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrCategoryNotExist{ID: id, RepoID: repoID}
+	}
+	return c, nil
+}
+
+// GetCategoriesByRepoID returns the categories of a repository ordered by name
+func GetCategoriesByRepoID(ctx context.Context, repoID int64) ([]*Category, error) {
+	categories := make([]*Category, 0, 5)
+	return categories, db.GetEngine(ctx).Where("repo_id = ?", repoID).OrderBy("name ASC").Find(&categories)
+}
+
+// DeleteCategory removes a category, it must not contain any discussions
+func DeleteCategory(ctx context.Context, c *Category) error {
+	count, err := db.GetEngine(ctx).Where("category_id = ?", c.ID).Count(new(Discussion))
+	if err != nil {
+		return err
+	} else if count > 0 {
+		return util.NewInvalidArgumentErrorf("category %d still contains %d discussions", c.ID, count)
+	}
+	_, err = db.GetEngine(ctx).ID(c.ID).Delete(new(Category))
+	return err
+}
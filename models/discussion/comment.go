@@ -0,0 +1,172 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package discussion
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrCommentNotExist represents a "DiscussionCommentNotExist" kind of error.
+type ErrCommentNotExist struct {
+	ID int64
+}
+
+// IsErrCommentNotExist checks if an error is a ErrCommentNotExist.
+func IsErrCommentNotExist(err error) bool {
+	_, ok := err.(ErrCommentNotExist)
+	return ok
+}
+
+func (err ErrCommentNotExist) Error() string {
+	return fmt.Sprintf("discussion comment does not exist [id: %d]", err.ID)
+}
+
+func (err ErrCommentNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// Comment is a reply to a discussion
+type Comment struct {
+	ID           int64            `xorm:"pk autoincr"`
+	DiscussionID int64            `xorm:"INDEX NOT NULL"`
+	PosterID     int64            `xorm:"INDEX"`
+	Poster       *user_model.User `xorm:"-"`
+	Content      string           `xorm:"LONGTEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// TableName sets the table name of the comment
+func (Comment) TableName() string {
+	return "discussion_comment"
+}
+
+func init() {
+	db.RegisterModel(new(Comment))
+}
+
+// LoadPoster loads the poster of the comment, a deleted poster is replaced by the ghost user
+func (c *Comment) LoadPoster(ctx context.Context) (err error) {
+	if c.Poster == nil {
+		c.Poster, err = user_model.GetPossibleUserByID(ctx, c.PosterID)
+		if err != nil {
+			c.PosterID = -1
+			c.Poster = user_model.NewGhostUser()
+			if !user_model.IsErrUserNotExist(err) {
+				return fmt.Errorf("getUserByID.(poster) [%d]: %w", c.PosterID, err)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// HTMLURL returns the absolute URL to this comment, d must be the discussion of the comment
+func (c *Comment) HTMLURL(d *Discussion) string {
+	return fmt.Sprintf("%s#discussioncomment-%d", d.HTMLURL(), c.ID)
+}
+
+// CreateComment adds a reply to a discussion
+func CreateComment(ctx context.Context, d *Discussion, c *Comment) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		c.DiscussionID = d.ID
+		if err := db.Insert(ctx, c); err != nil {
+			return err
+		}
+		d.NumComments++
+		// also bumps updated_unix so active discussions are listed first
+		_, err := db.GetEngine(ctx).ID(d.ID).Incr("num_comments").Update(new(Discussion))
+		return err
+	})
+}
+
+// InsertComments adds existing replies to a discussion keeping their creation time
+func InsertComments(ctx context.Context, d *Discussion, comments []*Comment) error {
+	if len(comments) == 0 {
+		return nil
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		for _, c := range comments {
+			c.DiscussionID = d.ID
+			if c.UpdatedUnix == 0 {
+				c.UpdatedUnix = c.CreatedUnix
+			}
+		}
+		if _, err := db.GetEngine(ctx).NoAutoTime().Insert(comments); err != nil {
+			return err
+		}
+		d.NumComments += int64(len(comments))
+		_, err := db.GetEngine(ctx).ID(d.ID).Cols("num_comments").NoAutoTime().Update(d)
+		return err
+	})
+}
+
+// GetCommentByID returns the comment of the discussion with the given id
+func GetCommentByID(ctx context.Context, discussionID, id int64) (*Comment, error) {
+	c := &Comment{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND discussion_id = ?", id, discussionID).Get(c)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrCommentNotExist{ID: id}
+	}
+	return c, nil
+}
+
+// FindComments returns the comments of a discussion, oldest first
+func FindComments(ctx context.Context, discussionID int64, listOptions db.ListOptions) ([]*Comment, int64, error) {
+	sess := db.GetEngine(ctx).Where("discussion_id = ?", discussionID).OrderBy("created_unix ASC, id ASC")
+	if listOptions.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &listOptions)
+	}
+	comments := make([]*Comment, 0, listOptions.PageSize)
+	count, err := sess.FindAndCount(&comments)
+	return comments, count, err
+}
+
+// UpdateComment changes the content of a comment
+func UpdateComment(ctx context.Context, c *Comment) error {
+	_, err := db.GetEngine(ctx).ID(c.ID).Cols("content").Update(c)
+	return err
+}
+
+// DeleteComment removes a comment, the discussion loses its answer if it was the comment
+func DeleteComment(ctx context.Context, d *Discussion, c *Comment) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).ID(c.ID).Delete(new(Comment)); err != nil {
+			return err
+		}
+		if d.AnswerID == c.ID {
+			d.AnswerID = 0
+		}
+		d.NumComments--
+		_, err := db.GetEngine(ctx).ID(d.ID).Cols("answer_id").Decr("num_comments").NoAutoTime().Update(d)
+		return err
+	})
+}
+
+// SetAnswer accepts a comment as answer of a question, 0 removes the answer
+func SetAnswer(ctx context.Context, d *Discussion, commentID int64) error {
+	if err := d.LoadCategory(ctx); err != nil {
+		return err
+	}
+	if d.Category.Kind != CategoryKindQA {
+		return util.NewInvalidArgumentErrorf("discussion %d is not a question", d.ID)
+	}
+	if commentID > 0 {
+		if _, err := GetCommentByID(ctx, d.ID, commentID); err != nil {
+			return err
+		}
+	}
+	d.AnswerID = commentID
+	_, err := db.GetEngine(ctx).ID(d.ID).Cols("answer_id").NoAutoTime().Update(d)
+	return err
+}
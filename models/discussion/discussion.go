@@ -0,0 +1,288 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package discussion
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ErrDiscussionNotExist represents a "DiscussionNotExist" kind of error.
+type ErrDiscussionNotExist struct {
+	ID     int64
+	RepoID int64
+	Index  int64
+}
+
+// IsErrDiscussionNotExist checks if an error is a ErrDiscussionNotExist.
+func IsErrDiscussionNotExist(err error) bool {
+	_, ok := err.(ErrDiscussionNotExist)
+	return ok
+}
+
+func (err ErrDiscussionNotExist) Error() string {
+	return fmt.Sprintf("discussion does not exist [id: %d, repo_id: %d, index: %d]", err.ID, err.RepoID, err.Index)
+}
+
+func (err ErrDiscussionNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// Discussion is a conversation of a repository that is not tracked as an issue
+type Discussion struct {
+	ID         int64                  `xorm:"pk autoincr"`
+	RepoID     int64                  `xorm:"INDEX UNIQUE(repo_index)"`
+	Repo       *repo_model.Repository `xorm:"-"`
+	Index      int64                  `xorm:"UNIQUE(repo_index)"`
+	CategoryID int64                  `xorm:"INDEX NOT NULL"`
+	Category   *Category              `xorm:"-"`
+	PosterID   int64                  `xorm:"INDEX"`
+	Poster     *user_model.User       `xorm:"-"`
+	Title      string                 `xorm:"TEXT"`
+	Content    string                 `xorm:"LONGTEXT"`
+	IsLocked   bool                   `xorm:"NOT NULL DEFAULT false"`
+	// AnswerID is the comment accepted as answer of a question
+	AnswerID    int64 `xorm:"NOT NULL DEFAULT 0"`
+	NumComments int64 `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+}
+
+// DiscussionIndex represents the discussion index table
+type DiscussionIndex db.ResourceIndex
+
+func init() {
+	db.RegisterModel(new(Discussion))
+	db.RegisterModel(new(DiscussionIndex))
+}
+
+// LoadRepo loads the repository of the discussion
+func (d *Discussion) LoadRepo(ctx context.Context) (err error) {
+	if d.Repo == nil {
+		d.Repo, err = repo_model.GetRepositoryByID(ctx, d.RepoID)
+	}
+	return err
+}
+
+// LoadCategory loads the category of the discussion
+func (d *Discussion) LoadCategory(ctx context.Context) (err error) {
+	if d.Category == nil {
+		d.Category, err = GetCategoryByID(ctx, d.RepoID, d.CategoryID)
+	}
+	return err
+}
+
+// LoadPoster loads the poster of the discussion, a deleted poster is replaced by the ghost user
+func (d *Discussion) LoadPoster(ctx context.Context) (err error) {
+	if d.Poster == nil {
+		d.Poster, err = user_model.GetPossibleUserByID(ctx, d.PosterID)
+		if err != nil {
+			d.PosterID = -1
+			d.Poster = user_model.NewGhostUser()
+			if !user_model.IsErrUserNotExist(err) {
+				return fmt.Errorf("getUserByID.(poster) [%d]: %w", d.PosterID, err)
+			}
+			return nil
+		}
+	}
+	return nil
+}
+
+// LoadAttributes loads the repository, category and poster of the discussion
+func (d *Discussion) LoadAttributes(ctx context.Context) error {
+	if err := d.LoadRepo(ctx); err != nil {
+		return err
+	}
+	if err := d.LoadCategory(ctx); err != nil {
+		return err
+	}
+	return d.LoadPoster(ctx)
+}
+
+// IsAnswered checks if a reply was accepted as answer
+func (d *Discussion) IsAnswered() bool {
+	return d.AnswerID > 0
+}
+
+// HTMLURL returns the absolute URL to this discussion.
+func (d *Discussion) HTMLURL() string {
+	return fmt.Sprintf("%s/discussions/%d", d.Repo.HTMLURL(), d.Index)
+}
+
+// APIURL returns the absolute APIURL to this discussion.
+func (d *Discussion) APIURL() string {
+	return fmt.Sprintf("%s/discussions/%d", d.Repo.APIURL(), d.Index)
+}
+
+// NewDiscussion inserts a discussion with the next free index of the repository,
+// the creation time is kept if it is set, e.g. when converting an issue
+func NewDiscussion(ctx context.Context, d *Discussion) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		idx, err := db.GetNextResourceIndex(ctx, "discussion_index", d.RepoID)
+		if err != nil {
+			return fmt.Errorf("generate discussion index failed: %w", err)
+		}
+		d.Index = idx
+
+		sess := db.GetEngine(ctx)
+		if d.CreatedUnix > 0 {
+			if d.UpdatedUnix == 0 {
+				d.UpdatedUnix = d.CreatedUnix
+			}
+			sess = sess.NoAutoTime()
+		}
+		_, err = sess.Insert(d)
+		return err
+	})
+}
+
+// GetDiscussionByIndex returns the discussion of the repository with the given index
+func GetDiscussionByIndex(ctx context.Context, repoID, index int64) (*Discussion, error) {
+	d := &Discussion{RepoID: repoID, Index: index}
+	has, err := db.GetEngine(ctx).Get(d)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrDiscussionNotExist{RepoID: repoID, Index: index}
+	}
+	return d, nil
+}
+
+// GetDiscussionByID returns the discussion with the given id
+func GetDiscussionByID(ctx context.Context, id int64) (*Discussion, error) {
+	d := &Discussion{}
+	has, err := db.GetEngine(ctx).ID(id).Get(d)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrDiscussionNotExist{ID: id}
+	}
+	return d, nil
+}
+
+// UpdateDiscussionCols updates the given columns of a discussion
+func UpdateDiscussionCols(ctx context.Context, d *Discussion, cols ...string) error {
+	_, err := db.GetEngine(ctx).ID(d.ID).Cols(cols...).Update(d)
+	return err
+}
+
+// FindDiscussionsOptions represents the options to search discussions
+type FindDiscussionsOptions struct {
+	db.ListOptions
+	RepoID     int64
+	CategoryID int64
+	PosterID   int64
+	// Keyword matches the title and content of the discussions and their comments
+	Keyword    string
+	IsAnswered util.OptionalBool
+	// IDs limits the discussions to the given ones, e.g. found by the discussion indexer
+	IDs []int64
+}
+
+func keywordCond(keyword string) builder.Cond {
+	return builder.Or(
+		db.BuildCaseInsensitiveLike("title", keyword),
+		db.BuildCaseInsensitiveLike("content", keyword),
+		builder.In("id", builder.Select("discussion_id").
+			From("discussion_comment").
+			Where(db.BuildCaseInsensitiveLike("content", keyword))),
+	)
+}
+
+// ToConds implements db.FindOptions
+func (opts FindDiscussionsOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.CategoryID > 0 {
+		cond = cond.And(builder.Eq{"category_id": opts.CategoryID})
+	}
+	if opts.PosterID > 0 {
+		cond = cond.And(builder.Eq{"poster_id": opts.PosterID})
+	}
+	if opts.Keyword != "" {
+		cond = cond.And(keywordCond(opts.Keyword))
+	}
+	if opts.IDs != nil {
+		cond = cond.And(builder.In("id", opts.IDs))
+	}
+	if opts.IsAnswered.IsTrue() {
+		cond = cond.And(builder.Gt{"answer_id": 0})
+	} else if opts.IsAnswered.IsFalse() {
+		cond = cond.And(builder.Eq{"answer_id": 0})
+	}
+	return cond
+}
+
+// FindDiscussions returns the discussions matching the options, most recently updated first
+func FindDiscussions(ctx context.Context, opts FindDiscussionsOptions) ([]*Discussion, int64, error) {
+	sess := db.GetEngine(ctx).Where(opts.ToConds()).OrderBy("updated_unix DESC, id DESC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts.ListOptions)
+	}
+	discussions := make([]*Discussion, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&discussions)
+	return discussions, count, err
+}
+
+// SearchDiscussionIDsByKeyword returns the ids of the discussions of the repositories matching the keyword,
+// most recently updated first, and their total number
+func SearchDiscussionIDsByKeyword(ctx context.Context, keyword string, repoIDs []int64, limit, start int) (int64, []int64, error) {
+	cond := builder.And(builder.In("repo_id", repoIDs), keywordCond(keyword))
+
+	ids := make([]int64, 0, limit)
+	if err := db.GetEngine(ctx).Table("discussion").Where(cond).
+		OrderBy("updated_unix DESC, id DESC").Limit(limit, start).
+		Cols("id").Find(&ids); err != nil {
+		return 0, nil, err
+	}
+	total, err := db.GetEngine(ctx).Table("discussion").Where(cond).Count()
+	if err != nil {
+		return 0, nil, err
+	}
+	return total, ids, nil
+}
+
+// GetDiscussionIDsByRepoID returns the ids of the discussions of a repository
+func GetDiscussionIDsByRepoID(ctx context.Context, repoID int64) ([]int64, error) {
+	ids := make([]int64, 0, 10)
+	return ids, db.GetEngine(ctx).Table("discussion").Where("repo_id = ?", repoID).Cols("id").Find(&ids)
+}
+
+// DeleteDiscussion removes a discussion and its comments
+func DeleteDiscussion(ctx context.Context, d *Discussion) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("discussion_id = ?", d.ID).Delete(new(Comment)); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(d.ID).Delete(new(Discussion))
+		return err
+	})
+}
+
+// DeleteDiscussionsByRepoID removes the discussions, their comments and the categories of a repository
+func DeleteDiscussionsByRepoID(ctx context.Context, repoID int64) error {
+	e := db.GetEngine(ctx)
+	if _, err := e.In("discussion_id", builder.Select("id").From("discussion").Where(builder.Eq{"repo_id": repoID})).
+		Delete(new(Comment)); err != nil {
+		return err
+	}
+	if _, err := e.Where("repo_id = ?", repoID).Delete(new(Discussion)); err != nil {
+		return err
+	}
+	if _, err := e.Where("repo_id = ?", repoID).Delete(new(Category)); err != nil {
+		return err
+	}
+	return db.DeleteResourceIndex(ctx, "discussion_index", repoID)
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package discussion
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiscussion(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	general := &Category{RepoID: 1, Name: "General", Kind: CategoryKindGeneral}
+	assert.NoError(t, CreateCategory(db.DefaultContext, general))
+	qa := &Category{RepoID: 1, Name: "Q&A", Kind: CategoryKindQA}
+	assert.NoError(t, CreateCategory(db.DefaultContext, qa))
+	assert.Error(t, CreateCategory(db.DefaultContext, &Category{RepoID: 1, Name: "Other", Kind: "other"}))
+
+	chat := &Discussion{RepoID: 1, CategoryID: general.ID, PosterID: 2, Title: "Hello", Content: "world"}
+	assert.NoError(t, NewDiscussion(db.DefaultContext, chat))
+	question := &Discussion{RepoID: 1, CategoryID: qa.ID, PosterID: 2, Title: "How to build?", Content: "make fails"}
+	assert.NoError(t, NewDiscussion(db.DefaultContext, question))
+	assert.EqualValues(t, 1, chat.Index)
+	assert.EqualValues(t, 2, question.Index)
+
+	answer := &Comment{PosterID: 1, Content: "run make clean first"}
+	assert.NoError(t, CreateComment(db.DefaultContext, question, answer))
+	question, err := GetDiscussionByIndex(db.DefaultContext, 1, 2)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, question.NumComments)
+
+	// only questions have answers
+	assert.Error(t, SetAnswer(db.DefaultContext, chat, answer.ID))
+	assert.NoError(t, SetAnswer(db.DefaultContext, question, answer.ID))
+
+	// comments match the keyword as well
+	found, count, err := FindDiscussions(db.DefaultContext, FindDiscussionsOptions{RepoID: 1, Keyword: "CLEAN"})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	assert.Equal(t, question.ID, found[0].ID)
+	_, count, err = FindDiscussions(db.DefaultContext, FindDiscussionsOptions{RepoID: 1, IsAnswered: util.OptionalBoolFalse})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+
+	assert.Error(t, DeleteCategory(db.DefaultContext, qa))
+
+	assert.NoError(t, DeleteComment(db.DefaultContext, question, answer))
+	question = unittest.AssertExistsAndLoadBean(t, &Discussion{ID: question.ID})
+	assert.EqualValues(t, 0, question.AnswerID)
+	assert.EqualValues(t, 0, question.NumComments)
+
+	assert.NoError(t, DeleteDiscussionsByRepoID(db.DefaultContext, 1))
+	unittest.AssertNotExistsBean(t, &Discussion{RepoID: 1})
+	unittest.AssertNotExistsBean(t, &Category{RepoID: 1})
+}
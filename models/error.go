@@ -6,6 +6,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/git"
@@ -581,6 +582,46 @@ func (err ErrRebaseConflicts) Error() string {
 	return fmt.Sprintf("Rebase Error: %v: Whilst Rebasing: %s\n%s\n%s", err.Err, err.CommitSHA, err.StdErr, err.StdOut)
 }
 
+// ErrCherryPickConflicts represents an error if cherry-picking or reverting a commit fails with a conflict
+type ErrCherryPickConflicts struct {
+	CommitSHA string
+	Files     []string
+}
+
+// IsErrCherryPickConflicts checks if an error is a ErrCherryPickConflicts.
+func IsErrCherryPickConflicts(err error) bool {
+	_, ok := err.(ErrCherryPickConflicts)
+	return ok
+}
+
+func (err ErrCherryPickConflicts) Error() string {
+	return fmt.Sprintf("cherry-pick of %s has conflicts in: %s", err.CommitSHA, strings.Join(err.Files, ", "))
+}
+
+// ErrInvalidMainline represents an error if the mainline parent of a cherry-picked commit is missing or invalid
+type ErrInvalidMainline struct {
+	CommitSHA string
+	Parents   int
+	Mainline  int
+}
+
+// IsErrInvalidMainline checks if an error is a ErrInvalidMainline.
+func IsErrInvalidMainline(err error) bool {
+	_, ok := err.(ErrInvalidMainline)
+	return ok
+}
+
+func (err ErrInvalidMainline) Error() string {
+	if err.Mainline == 0 {
+		return fmt.Sprintf("commit %s is a merge with %d parents but no mainline was given", err.CommitSHA, err.Parents)
+	}
+	return fmt.Sprintf("commit %s has %d parents, mainline %d is invalid", err.CommitSHA, err.Parents, err.Mainline)
+}
+
+func (err ErrInvalidMainline) Unwrap() error {
+	return util.ErrInvalidArgument
+}
+
 // ErrPullRequestHasMerged represents a "PullRequestHasMerged"-error
 type ErrPullRequestHasMerged struct {
 	ID         int64
@@ -361,6 +361,10 @@ func NewCommitStatus(ctx context.Context, opts NewCommitStatusOptions) error {
 		return fmt.Errorf("insert CommitStatus[%s, %s]: %w", repoPath, opts.SHA, err)
 	}
 
+	if err = finishCommitStatusAttempt(ctx, opts.CommitStatus); err != nil {
+		return fmt.Errorf("finish CommitStatusAttempt[%s, %s]: %w", repoPath, opts.SHA, err)
+	}
+
 	return committer.Commit()
 }
 
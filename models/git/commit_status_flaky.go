@@ -0,0 +1,411 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/gobwas/glob"
+)
+
+// FlakyCheckRule marks the commit status contexts of a repository matching ContextPattern as flaky-tolerant.
+// Failing Actions jobs of these contexts are re-dispatched up to MaxRetries times, and if RequiredPasses
+// is set the context only needs to pass in RequiredPasses of the first Attempts attempts.
+type FlakyCheckRule struct {
+	ID             int64              `xorm:"pk autoincr"`
+	RepoID         int64              `xorm:"INDEX NOT NULL"`
+	ContextPattern string             `xorm:"TEXT NOT NULL"` // a glob pattern like the status check contexts of protected branches
+	MaxRetries     int                `xorm:"NOT NULL DEFAULT 0"`
+	RequiredPasses int                `xorm:"NOT NULL DEFAULT 0"`
+	Attempts       int                `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+
+	contextGlob glob.Glob `xorm:"-"`
+}
+
+// CommitStatusAttempt is an attempt of a flaky-tolerant commit status context on a commit.
+// An attempt is started by a retry, which records who or what caused it, and finished by the next
+// success, failure or error status of the context.
+type CommitStatusAttempt struct {
+	ID          int64                 `xorm:"pk autoincr"`
+	RepoID      int64                 `xorm:"INDEX NOT NULL"`
+	SHA         string                `xorm:"VARCHAR(64) NOT NULL INDEX"`
+	ContextHash string                `xorm:"char(40) INDEX"`
+	Context     string                `xorm:"TEXT"`
+	Attempt     int                   `xorm:"NOT NULL"`
+	State       api.CommitStatusState `xorm:"VARCHAR(7)"` // empty while the attempt is running
+	Reason      string                `xorm:"VARCHAR(16)"`
+	DoerID      int64                 // the user who retried the context, 0 for automatic retries
+	JobID       int64                 // the Actions job which was re-dispatched, if any
+	CreatedUnix timeutil.TimeStamp    `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp    `xorm:"updated"`
+}
+
+// Reasons of commit status attempts
+const (
+	CommitStatusAttemptReasonInitial = "initial" // the first run of the context
+	CommitStatusAttemptReasonAuto    = "auto"    // re-dispatched because of a flaky check rule
+	CommitStatusAttemptReasonManual  = "manual"  // retried by a user
+)
+
+func init() {
+	db.RegisterModel(new(FlakyCheckRule))
+	db.RegisterModel(new(CommitStatusAttempt))
+}
+
+// ErrFlakyCheckRuleNotExist represents a "FlakyCheckRuleNotExist" kind of error.
+type ErrFlakyCheckRuleNotExist struct {
+	ID int64
+}
+
+// IsErrFlakyCheckRuleNotExist checks if an error is a ErrFlakyCheckRuleNotExist.
+func IsErrFlakyCheckRuleNotExist(err error) bool {
+	_, ok := err.(ErrFlakyCheckRuleNotExist)
+	return ok
+}
+
+func (err ErrFlakyCheckRuleNotExist) Error() string {
+	return fmt.Sprintf("flaky check rule does not exist [id: %d]", err.ID)
+}
+
+func (err ErrFlakyCheckRuleNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// Validate checks the pattern and the numbers of the rule
+func (rule *FlakyCheckRule) Validate() error {
+	if rule.ContextPattern == "" {
+		return util.NewInvalidArgumentErrorf("context pattern is empty")
+	}
+	if _, err := glob.Compile(rule.ContextPattern); err != nil {
+		return util.NewInvalidArgumentErrorf("invalid context pattern %q: %v", rule.ContextPattern, err)
+	}
+	if rule.MaxRetries < 0 || rule.RequiredPasses < 0 || rule.Attempts < 0 {
+		return util.NewInvalidArgumentErrorf("retries, required passes and attempts must not be negative")
+	}
+	if rule.RequiredPasses > rule.Attempts {
+		return util.NewInvalidArgumentErrorf("required passes must not be more than the attempts")
+	}
+	if rule.MaxRetries == 0 && rule.RequiredPasses == 0 {
+		return util.NewInvalidArgumentErrorf("either retries or required passes must be set")
+	}
+	return nil
+}
+
+// Match returns whether the commit status context matches the pattern of the rule
+func (rule *FlakyCheckRule) Match(context string) bool {
+	if rule.contextGlob == nil {
+		gp, err := glob.Compile(rule.ContextPattern)
+		if err != nil {
+			log.Error("glob.Compile %s failed. Error: %v", rule.ContextPattern, err)
+			return false
+		}
+		rule.contextGlob = gp
+	}
+	return rule.contextGlob.Match(context)
+}
+
+// IsPassRule returns whether the context has to pass in a number of attempts instead of the last one
+func (rule *FlakyCheckRule) IsPassRule() bool {
+	return rule.RequiredPasses > 0 && rule.Attempts > 0
+}
+
+// AggregateState returns the state of a context from its attempts ordered by attempt number.
+// Without a pass rule it is the state of the last finished attempt. With a pass rule it is success once
+// enough of the first attempts passed, failure once they can't pass anymore and pending otherwise.
+func (rule *FlakyCheckRule) AggregateState(attempts []*CommitStatusAttempt) api.CommitStatusState {
+	var passes, finished int
+	var last api.CommitStatusState
+	for _, attempt := range attempts {
+		if attempt.State == "" {
+			continue
+		}
+		if rule.IsPassRule() && finished == rule.Attempts {
+			break
+		}
+		finished++
+		last = attempt.State
+		if attempt.State.IsSuccess() {
+			passes++
+		}
+	}
+	if !rule.IsPassRule() {
+		if last == "" {
+			return api.CommitStatusPending
+		}
+		return last
+	}
+	if passes >= rule.RequiredPasses {
+		return api.CommitStatusSuccess
+	}
+	if finished-passes > rule.Attempts-rule.RequiredPasses {
+		return api.CommitStatusFailure
+	}
+	return api.CommitStatusPending
+}
+
+// NeedsRetry returns whether a finished context should be re-dispatched automatically:
+// a failed one while there are retries left, or any one while a pass rule is undecided.
+func (rule *FlakyCheckRule) NeedsRetry(attempts []*CommitStatusAttempt) bool {
+	if len(attempts) == 0 {
+		return false
+	}
+	var retries int
+	for _, attempt := range attempts {
+		if attempt.State == "" {
+			// there is still an attempt running
+			return false
+		}
+		if attempt.Reason == CommitStatusAttemptReasonAuto {
+			retries++
+		}
+	}
+	if rule.IsPassRule() {
+		// more attempts are needed while the result is undecided
+		return rule.AggregateState(attempts) == api.CommitStatusPending && len(attempts) < rule.Attempts
+	}
+	return !attempts[len(attempts)-1].State.IsSuccess() && retries < rule.MaxRetries
+}
+
+// GetFlakyCheckRules returns the flaky check rules of a repository
+func GetFlakyCheckRules(ctx context.Context, repoID int64) ([]*FlakyCheckRule, error) {
+	rules := make([]*FlakyCheckRule, 0, 5)
+	return rules, db.GetEngine(ctx).Where("repo_id = ?", repoID).OrderBy("id").Find(&rules)
+}
+
+// GetFlakyCheckRuleByID returns the flaky check rule of the repository with the id
+func GetFlakyCheckRuleByID(ctx context.Context, repoID, id int64) (*FlakyCheckRule, error) {
+	rule := &FlakyCheckRule{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Get(rule)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrFlakyCheckRuleNotExist{ID: id}
+	}
+	return rule, nil
+}
+
+// MatchFlakyCheckRule returns the first rule matching the commit status context, or nil
+func MatchFlakyCheckRule(rules []*FlakyCheckRule, context string) *FlakyCheckRule {
+	for _, rule := range rules {
+		if rule.Match(context) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// CreateFlakyCheckRule validates and inserts a flaky check rule
+func CreateFlakyCheckRule(ctx context.Context, rule *FlakyCheckRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	return db.Insert(ctx, rule)
+}
+
+// UpdateFlakyCheckRule validates and updates a flaky check rule
+func UpdateFlakyCheckRule(ctx context.Context, rule *FlakyCheckRule) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+	rule.contextGlob = nil
+	_, err := db.GetEngine(ctx).ID(rule.ID).Cols("context_pattern", "max_retries", "required_passes", "attempts").Update(rule)
+	return err
+}
+
+// DeleteFlakyCheckRule deletes the flaky check rule of the repository with the id
+func DeleteFlakyCheckRule(ctx context.Context, repoID, id int64) error {
+	n, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Delete(new(FlakyCheckRule))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrFlakyCheckRuleNotExist{ID: id}
+	}
+	return nil
+}
+
+// GetCommitStatusAttempts returns the attempts of a commit status context on a commit ordered by attempt number
+func GetCommitStatusAttempts(ctx context.Context, repoID int64, sha, context string) ([]*CommitStatusAttempt, error) {
+	attempts := make([]*CommitStatusAttempt, 0, 2)
+	return attempts, db.GetEngine(ctx).
+		Where("repo_id = ? AND sha = ? AND context_hash = ?", repoID, sha, hashCommitStatusContext(context)).
+		OrderBy("attempt").Find(&attempts)
+}
+
+// GetCommitStatusAttemptsBySHA returns the attempts of all flaky-tolerant contexts on a commit
+func GetCommitStatusAttemptsBySHA(ctx context.Context, repoID int64, sha string) ([]*CommitStatusAttempt, error) {
+	attempts := make([]*CommitStatusAttempt, 0, 10)
+	return attempts, db.GetEngine(ctx).Where("repo_id = ? AND sha = ?", repoID, sha).
+		OrderBy("context_hash, attempt").Find(&attempts)
+}
+
+// StartCommitStatusAttempt records a retry of a commit status context and who or what caused it.
+// Nothing is recorded if an attempt of the context is still running.
+func StartCommitStatusAttempt(ctx context.Context, attempt *CommitStatusAttempt) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		attempts, err := GetCommitStatusAttempts(ctx, attempt.RepoID, attempt.SHA, attempt.Context)
+		if err != nil {
+			return err
+		}
+		if len(attempts) > 0 && attempts[len(attempts)-1].State == "" {
+			return nil
+		}
+		attempt.ContextHash = hashCommitStatusContext(attempt.Context)
+		attempt.Attempt = len(attempts) + 1
+		attempt.State = ""
+		return db.Insert(ctx, attempt)
+	})
+}
+
+// finishCommitStatusAttempt records the final state of a commit status on the running attempt of its context,
+// or on a new initial attempt if none is running. It only records contexts matching a flaky check rule.
+func finishCommitStatusAttempt(ctx context.Context, status *CommitStatus) error {
+	if !status.State.IsSuccess() && !status.State.IsFailure() && !status.State.IsError() {
+		return nil
+	}
+	rules, err := GetFlakyCheckRules(ctx, status.RepoID)
+	if err != nil {
+		return err
+	}
+	if MatchFlakyCheckRule(rules, status.Context) == nil {
+		return nil
+	}
+
+	attempts, err := GetCommitStatusAttempts(ctx, status.RepoID, status.SHA, status.Context)
+	if err != nil {
+		return err
+	}
+	if len(attempts) > 0 && attempts[len(attempts)-1].State == "" {
+		running := attempts[len(attempts)-1]
+		running.State = status.State
+		_, err = db.GetEngine(ctx).ID(running.ID).Cols("state").Update(running)
+		return err
+	}
+	reason := CommitStatusAttemptReasonInitial
+	if len(attempts) > 0 {
+		// a status reported again without a recorded retry, e.g. by an external CI
+		reason = CommitStatusAttemptReasonManual
+	}
+	return db.Insert(ctx, &CommitStatusAttempt{
+		RepoID:      status.RepoID,
+		SHA:         status.SHA,
+		ContextHash: status.ContextHash,
+		Context:     status.Context,
+		Attempt:     len(attempts) + 1,
+		State:       status.State,
+		Reason:      reason,
+		DoerID:      status.CreatorID,
+	})
+}
+
+// ApplyFlakyCheckRules replaces the states of the latest commit statuses of flaky-tolerant contexts
+// by the states aggregated from their attempts. The given statuses are not modified.
+func ApplyFlakyCheckRules(ctx context.Context, repoID int64, sha string, statuses []*CommitStatus) ([]*CommitStatus, error) {
+	rules, err := GetFlakyCheckRules(ctx, repoID)
+	if err != nil || len(rules) == 0 {
+		return statuses, err
+	}
+	attempts, err := GetCommitStatusAttemptsBySHA(ctx, repoID, sha)
+	if err != nil {
+		return nil, err
+	}
+	attemptsByContext := make(map[string][]*CommitStatusAttempt)
+	for _, attempt := range attempts {
+		attemptsByContext[attempt.Context] = append(attemptsByContext[attempt.Context], attempt)
+	}
+
+	ret := make([]*CommitStatus, 0, len(statuses))
+	for _, status := range statuses {
+		rule := MatchFlakyCheckRule(rules, status.Context)
+		contextAttempts := attemptsByContext[status.Context]
+		if rule == nil || !rule.IsPassRule() || len(contextAttempts) == 0 {
+			ret = append(ret, status)
+			continue
+		}
+		aggregated := *status
+		aggregated.State = rule.AggregateState(contextAttempts)
+		ret = append(ret, &aggregated)
+	}
+	return ret, nil
+}
+
+// FlakeRate is the flakiness of a commit status context: how often it failed on a commit and later passed on the same one
+type FlakeRate struct {
+	Context      string
+	Commits      int // the number of commits the context finished on
+	FlakyCommits int // the number of commits the context both failed and passed on
+	Retries      int // the number of retries, by reason
+	AutoRetries  int
+}
+
+// Rate returns the share of commits on which the context was flaky
+func (r *FlakeRate) Rate() float64 {
+	if r.Commits == 0 {
+		return 0
+	}
+	return float64(r.FlakyCommits) / float64(r.Commits)
+}
+
+// GetFlakeRates returns the flake rates of the flaky-tolerant contexts of a repository since the time, ordered by context
+func GetFlakeRates(ctx context.Context, repoID int64, since timeutil.TimeStamp) ([]*FlakeRate, error) {
+	attempts := make([]*CommitStatusAttempt, 0, 50)
+	if err := db.GetEngine(ctx).Where("repo_id = ? AND created_unix >= ?", repoID, since).
+		OrderBy("id").Find(&attempts); err != nil {
+		return nil, err
+	}
+
+	type commitState struct{ passed, failed bool }
+	rates := make(map[string]*FlakeRate)
+	commits := make(map[string]map[string]*commitState)
+	for _, attempt := range attempts {
+		rate, ok := rates[attempt.Context]
+		if !ok {
+			rate = &FlakeRate{Context: attempt.Context}
+			rates[attempt.Context] = rate
+			commits[attempt.Context] = make(map[string]*commitState)
+		}
+		switch attempt.Reason {
+		case CommitStatusAttemptReasonAuto:
+			rate.Retries++
+			rate.AutoRetries++
+		case CommitStatusAttemptReasonManual:
+			rate.Retries++
+		}
+		if attempt.State == "" {
+			continue
+		}
+		state, ok := commits[attempt.Context][attempt.SHA]
+		if !ok {
+			state = &commitState{}
+			commits[attempt.Context][attempt.SHA] = state
+		}
+		if attempt.State.IsSuccess() {
+			state.passed = true
+		} else {
+			state.failed = true
+		}
+	}
+
+	ret := make([]*FlakeRate, 0, len(rates))
+	for context, rate := range rates {
+		for _, state := range commits[context] {
+			rate.Commits++
+			if state.passed && state.failed {
+				rate.FlakyCommits++
+			}
+		}
+		ret = append(ret, rate)
+	}
+	sort.Slice(ret, func(i, j int) bool { return ret[i].Context < ret[j].Context })
+	return ret, nil
+}
@@ -0,0 +1,152 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func attemptsWithStates(states ...structs.CommitStatusState) []*git_model.CommitStatusAttempt {
+	attempts := make([]*git_model.CommitStatusAttempt, 0, len(states))
+	for i, state := range states {
+		reason := git_model.CommitStatusAttemptReasonInitial
+		if i > 0 {
+			reason = git_model.CommitStatusAttemptReasonAuto
+		}
+		attempts = append(attempts, &git_model.CommitStatusAttempt{Attempt: i + 1, State: state, Reason: reason})
+	}
+	return attempts
+}
+
+func TestFlakyCheckRule_AggregateState(t *testing.T) {
+	const (
+		success = structs.CommitStatusSuccess
+		failure = structs.CommitStatusFailure
+		pending = structs.CommitStatusPending
+	)
+
+	retryRule := &git_model.FlakyCheckRule{ContextPattern: "ci/*", MaxRetries: 2}
+	assert.Equal(t, pending, retryRule.AggregateState(nil))
+	assert.Equal(t, failure, retryRule.AggregateState(attemptsWithStates(failure)))
+	assert.Equal(t, success, retryRule.AggregateState(attemptsWithStates(failure, success)))
+	assert.Equal(t, success, retryRule.AggregateState(attemptsWithStates(failure, success, "")))
+
+	passRule := &git_model.FlakyCheckRule{ContextPattern: "ci/*", RequiredPasses: 2, Attempts: 3}
+	assert.Equal(t, pending, passRule.AggregateState(attemptsWithStates(success)))
+	assert.Equal(t, pending, passRule.AggregateState(attemptsWithStates(success, failure)))
+	assert.Equal(t, success, passRule.AggregateState(attemptsWithStates(success, failure, success)))
+	assert.Equal(t, failure, passRule.AggregateState(attemptsWithStates(failure, failure)))
+	// only the first attempts count
+	assert.Equal(t, failure, passRule.AggregateState(attemptsWithStates(failure, success, failure, success)))
+}
+
+func TestFlakyCheckRule_NeedsRetry(t *testing.T) {
+	const (
+		success = structs.CommitStatusSuccess
+		failure = structs.CommitStatusFailure
+	)
+
+	retryRule := &git_model.FlakyCheckRule{ContextPattern: "ci/*", MaxRetries: 2}
+	assert.False(t, retryRule.NeedsRetry(nil))
+	assert.True(t, retryRule.NeedsRetry(attemptsWithStates(failure)))
+	assert.True(t, retryRule.NeedsRetry(attemptsWithStates(failure, failure)))
+	assert.False(t, retryRule.NeedsRetry(attemptsWithStates(failure, failure, failure)))
+	assert.False(t, retryRule.NeedsRetry(attemptsWithStates(failure, success)))
+	assert.False(t, retryRule.NeedsRetry(attemptsWithStates(failure, "")))
+
+	passRule := &git_model.FlakyCheckRule{ContextPattern: "ci/*", RequiredPasses: 2, Attempts: 3}
+	assert.True(t, passRule.NeedsRetry(attemptsWithStates(success)))
+	assert.True(t, passRule.NeedsRetry(attemptsWithStates(success, failure)))
+	assert.False(t, passRule.NeedsRetry(attemptsWithStates(success, success)))
+	assert.False(t, passRule.NeedsRetry(attemptsWithStates(failure, failure)))
+}
+
+func TestFlakyCheckRule_Validate(t *testing.T) {
+	assert.NoError(t, (&git_model.FlakyCheckRule{ContextPattern: "ci/*", MaxRetries: 1}).Validate())
+	assert.NoError(t, (&git_model.FlakyCheckRule{ContextPattern: "ci/*", RequiredPasses: 1, Attempts: 2}).Validate())
+	assert.Error(t, (&git_model.FlakyCheckRule{MaxRetries: 1}).Validate())
+	assert.Error(t, (&git_model.FlakyCheckRule{ContextPattern: "ci/[", MaxRetries: 1}).Validate())
+	assert.Error(t, (&git_model.FlakyCheckRule{ContextPattern: "ci/*"}).Validate())
+	assert.Error(t, (&git_model.FlakyCheckRule{ContextPattern: "ci/*", RequiredPasses: 3, Attempts: 2}).Validate())
+}
+
+func TestCommitStatusAttempts(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	sha := "1234123412341234123412341234123412341234"
+
+	assert.NoError(t, git_model.CreateFlakyCheckRule(db.DefaultContext, &git_model.FlakyCheckRule{
+		RepoID:         repo.ID,
+		ContextPattern: "flaky/*",
+		RequiredPasses: 2,
+		Attempts:       3,
+	}))
+
+	newStatus := func(state structs.CommitStatusState) {
+		assert.NoError(t, git_model.NewCommitStatus(db.DefaultContext, git_model.NewCommitStatusOptions{
+			Repo:         repo,
+			Creator:      doer,
+			SHA:          sha,
+			CommitStatus: &git_model.CommitStatus{State: state, Context: "flaky/test"},
+		}))
+	}
+
+	// pending statuses don't finish an attempt
+	newStatus(structs.CommitStatusPending)
+	newStatus(structs.CommitStatusSuccess)
+	assert.NoError(t, git_model.StartCommitStatusAttempt(db.DefaultContext, &git_model.CommitStatusAttempt{
+		RepoID:  repo.ID,
+		SHA:     sha,
+		Context: "flaky/test",
+		Reason:  git_model.CommitStatusAttemptReasonManual,
+		DoerID:  doer.ID,
+	}))
+	newStatus(structs.CommitStatusFailure)
+
+	attempts, err := git_model.GetCommitStatusAttempts(db.DefaultContext, repo.ID, sha, "flaky/test")
+	assert.NoError(t, err)
+	if assert.Len(t, attempts, 2) {
+		assert.Equal(t, structs.CommitStatusSuccess, attempts[0].State)
+		assert.Equal(t, git_model.CommitStatusAttemptReasonInitial, attempts[0].Reason)
+		assert.Equal(t, structs.CommitStatusFailure, attempts[1].State)
+		assert.Equal(t, git_model.CommitStatusAttemptReasonManual, attempts[1].Reason)
+		assert.Equal(t, doer.ID, attempts[1].DoerID)
+	}
+
+	// the contexts without a rule are not recorded
+	attempts, err = git_model.GetCommitStatusAttemptsBySHA(db.DefaultContext, repo.ID, sha)
+	assert.NoError(t, err)
+	assert.Len(t, attempts, 2)
+
+	statuses, _, err := git_model.GetLatestCommitStatus(db.DefaultContext, repo.ID, sha, db.ListOptions{})
+	assert.NoError(t, err)
+	statuses, err = git_model.ApplyFlakyCheckRules(db.DefaultContext, repo.ID, sha, statuses)
+	assert.NoError(t, err)
+	for _, status := range statuses {
+		if status.Context == "flaky/test" {
+			assert.Equal(t, structs.CommitStatusPending, status.State)
+		}
+	}
+
+	newStatus(structs.CommitStatusSuccess)
+	rates, err := git_model.GetFlakeRates(db.DefaultContext, repo.ID, 0)
+	assert.NoError(t, err)
+	if assert.Len(t, rates, 1) {
+		assert.Equal(t, "flaky/test", rates[0].Context)
+		assert.Equal(t, 1, rates[0].Commits)
+		assert.Equal(t, 1, rates[0].FlakyCommits)
+		assert.Equal(t, 2, rates[0].Retries)
+	}
+}
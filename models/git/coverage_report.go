@@ -0,0 +1,114 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CoverageReport is the test coverage of a commit uploaded by a CI system
+type CoverageReport struct {
+	ID        int64  `xorm:"pk autoincr"`
+	RepoID    int64  `xorm:"INDEX(s) NOT NULL"`
+	Branch    string `xorm:"VARCHAR(255) INDEX(s) NOT NULL DEFAULT ''"`
+	CommitSHA string `xorm:"VARCHAR(64) INDEX NOT NULL"`
+	// Coverage is the percentage of covered lines
+	Coverage    float64            `xorm:"NOT NULL DEFAULT 0"`
+	CreatorID   int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// CoverageReportFile is the coverage of the lines of a file of a coverage report, it's only known if the report was
+// uploaded instead of its percentage
+type CoverageReportFile struct {
+	ID             int64  `xorm:"pk autoincr"`
+	RepoID         int64  `xorm:"INDEX NOT NULL"`
+	ReportID       int64  `xorm:"INDEX NOT NULL"`
+	Path           string `xorm:"VARCHAR(1024) NOT NULL"`
+	CoveredLines   []int  `xorm:"JSON TEXT"`
+	UncoveredLines []int  `xorm:"JSON TEXT"`
+}
+
+func init() {
+	db.RegisterModel(new(CoverageReport))
+	db.RegisterModel(new(CoverageReportFile))
+}
+
+// Hits returns whether the instrumented lines of the file are covered by line number
+func (f *CoverageReportFile) Hits() map[int]bool {
+	hits := make(map[int]bool, len(f.CoveredLines)+len(f.UncoveredLines))
+	for _, line := range f.UncoveredLines {
+		hits[line] = false
+	}
+	for _, line := range f.CoveredLines {
+		hits[line] = true
+	}
+	return hits
+}
+
+// InsertCoverageReport records the coverage of a commit with the coverage of its files
+func InsertCoverageReport(ctx context.Context, r *CoverageReport, files []*CoverageReportFile) error {
+	if r.Coverage < 0 || r.Coverage > 100 {
+		return util.NewInvalidArgumentErrorf("coverage must be between 0 and 100")
+	}
+	if r.CommitSHA == "" {
+		return util.NewInvalidArgumentErrorf("the commit of a coverage report is empty")
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := db.Insert(ctx, r); err != nil {
+			return err
+		}
+		for _, f := range files {
+			f.RepoID = r.RepoID
+			f.ReportID = r.ID
+		}
+		if len(files) == 0 {
+			return nil
+		}
+		return db.Insert(ctx, files)
+	})
+}
+
+// GetLatestCoverageReport returns the last coverage reported for a branch, or nil if there is none
+func GetLatestCoverageReport(ctx context.Context, repoID int64, branch string) (*CoverageReport, error) {
+	return getLatestCoverageReport(ctx, "repo_id = ? AND branch = ?", repoID, branch)
+}
+
+// GetLatestCoverageReportByCommit returns the last coverage reported for a commit, or nil if there is none
+func GetLatestCoverageReportByCommit(ctx context.Context, repoID int64, commitSHA string) (*CoverageReport, error) {
+	return getLatestCoverageReport(ctx, "repo_id = ? AND commit_sha = ?", repoID, commitSHA)
+}
+
+func getLatestCoverageReport(ctx context.Context, query string, args ...interface{}) (*CoverageReport, error) {
+	r := &CoverageReport{}
+	has, err := db.GetEngine(ctx).Where(query, args...).Desc("id").Get(r)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return r, nil
+}
+
+// FindCoverageReportFiles returns the coverage of the files of a report by path, only the given paths are loaded if
+// any are given
+func FindCoverageReportFiles(ctx context.Context, reportID int64, paths ...string) (map[string]*CoverageReportFile, error) {
+	sess := db.GetEngine(ctx).Where("report_id = ?", reportID)
+	if len(paths) > 0 {
+		sess = sess.In("path", paths)
+	}
+	var files []*CoverageReportFile
+	if err := sess.Find(&files); err != nil {
+		return nil, err
+	}
+	byPath := make(map[string]*CoverageReportFile, len(files))
+	for _, f := range files {
+		byPath[f.Path] = f
+	}
+	return byPath, nil
+}
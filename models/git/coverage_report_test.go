@@ -0,0 +1,52 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoverageReport(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	report, err := git_model.GetLatestCoverageReport(db.DefaultContext, 1, "master")
+	assert.NoError(t, err)
+	assert.Nil(t, report)
+
+	assert.NoError(t, git_model.InsertCoverageReport(db.DefaultContext, &git_model.CoverageReport{RepoID: 1, Branch: "master", CommitSHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d", Coverage: 71.5}, nil))
+	assert.NoError(t, git_model.InsertCoverageReport(db.DefaultContext, &git_model.CoverageReport{RepoID: 1, Branch: "master", CommitSHA: "2c54faec6c45d31c1abfaecdab471eac6633738a", Coverage: 80}, []*git_model.CoverageReportFile{
+		{Path: "README.md", CoveredLines: []int{1, 2}, UncoveredLines: []int{3}},
+		{Path: "main.go", CoveredLines: []int{5}},
+	}))
+	assert.NoError(t, git_model.InsertCoverageReport(db.DefaultContext, &git_model.CoverageReport{RepoID: 1, Branch: "develop", CommitSHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d", Coverage: 10}, nil))
+
+	report, err = git_model.GetLatestCoverageReport(db.DefaultContext, 1, "master")
+	assert.NoError(t, err)
+	if assert.NotNil(t, report) {
+		assert.Equal(t, "2c54faec6c45d31c1abfaecdab471eac6633738a", report.CommitSHA)
+		assert.EqualValues(t, 80, report.Coverage)
+
+		files, err := git_model.FindCoverageReportFiles(db.DefaultContext, report.ID, "README.md", "other.go")
+		assert.NoError(t, err)
+		if assert.Len(t, files, 1) {
+			assert.Equal(t, map[int]bool{1: true, 2: true, 3: false}, files["README.md"].Hits())
+		}
+	}
+
+	report, err = git_model.GetLatestCoverageReportByCommit(db.DefaultContext, 1, "65f1bf27bc3bf70f64657658635e66094edbcb4d")
+	assert.NoError(t, err)
+	if assert.NotNil(t, report) {
+		assert.Equal(t, "develop", report.Branch)
+	}
+
+	err = git_model.InsertCoverageReport(db.DefaultContext, &git_model.CoverageReport{RepoID: 1, Branch: "master", CommitSHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d", Coverage: 101}, nil)
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 
@@ -254,6 +255,15 @@ func ExistsLFSObject(ctx context.Context, oid string) (bool, error) {
 	return db.GetEngine(ctx).Exist(&LFSMetaObject{Pointer: lfs.Pointer{Oid: oid}})
 }
 
+// IsLFSObjectReferencedByOtherOwners checks if repositories of other owners reference the LFS object
+func IsLFSObjectReferencedByOtherOwners(ctx context.Context, oid string, ownerID int64) (bool, error) {
+	return db.GetEngine(ctx).
+		Table("lfs_meta_object").
+		Join("INNER", "repository", "repository.id = lfs_meta_object.repository_id").
+		Where("lfs_meta_object.oid = ? AND repository.owner_id <> ?", oid, ownerID).
+		Exist()
+}
+
 // LFSAutoAssociate auto associates accessible LFSMetaObjects
 func LFSAutoAssociate(ctx context.Context, metas []*LFSMetaObject, user *user_model.User, repoID int64) error {
 	ctx, committer, err := db.TxContext(ctx)
@@ -315,12 +325,22 @@ func CopyLFS(ctx context.Context, newRepo, oldRepo *repo_model.Repository) error
 		return err
 	}
 
+	// the objects have to be available in the storage region of the new repository too
+	srcStorage, dstStorage := storage.LFSOfRegion(oldRepo.StorageRegion()), storage.LFSOfRegion(newRepo.StorageRegion())
+
 	for _, v := range lfsObjects {
 		v.ID = 0
 		v.RepositoryID = newRepo.ID
 		if err := db.Insert(ctx, v); err != nil {
 			return err
 		}
+		if srcStorage != dstStorage {
+			if _, err := dstStorage.Stat(v.RelativePath()); err != nil {
+				if _, err := storage.Copy(dstStorage, v.RelativePath(), srcStorage, v.RelativePath()); err != nil {
+					return err
+				}
+			}
+		}
 	}
 
 	return nil
@@ -335,6 +355,11 @@ func GetRepoLFSSize(ctx context.Context, repoID int64) (int64, error) {
 	return lfsSize, nil
 }
 
+// GetLFSSizesByRepo returns the size of the lfs files of every repository which has any
+func GetLFSSizesByRepo(ctx context.Context) (map[int64]int64, error) {
+	return db.SumGroupBy(ctx, "lfs_meta_object", "repository_id", "size", builder.NewCond())
+}
+
 // IterateRepositoryIDsWithLFSMetaObjects iterates across the repositories that have LFSMetaObjects
 func IterateRepositoryIDsWithLFSMetaObjects(ctx context.Context, f func(ctx context.Context, repoID, count int64) error) error {
 	batchSize := setting.Database.IterateBufferSize
@@ -52,7 +52,10 @@ type ProtectedBranch struct {
 	BlockOnRejectedReviews        bool     `xorm:"NOT NULL DEFAULT false"`
 	BlockOnOfficialReviewRequests bool     `xorm:"NOT NULL DEFAULT false"`
 	BlockOnOutdatedBranch         bool     `xorm:"NOT NULL DEFAULT false"`
+	BlockOnCodeScanningAlerts     bool     `xorm:"NOT NULL DEFAULT false"`
+	MinDiffCoverage               int64    `xorm:"NOT NULL DEFAULT 0"`
 	DismissStaleApprovals         bool     `xorm:"NOT NULL DEFAULT false"`
+	RequireReviewChecklist        bool     `xorm:"NOT NULL DEFAULT false"`
 	RequireSignedCommits          bool     `xorm:"NOT NULL DEFAULT false"`
 	ProtectedFilePatterns         string   `xorm:"TEXT"`
 	UnprotectedFilePatterns       string   `xorm:"TEXT"`
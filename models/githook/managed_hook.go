@@ -0,0 +1,148 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package githook
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// HookType is the git hook a managed hook script is run as
+type HookType string
+
+const (
+	// HookTypePreReceive scripts run before refs are updated, a failing script rejects the push
+	HookTypePreReceive HookType = "pre-receive"
+	// HookTypePostReceive scripts run after refs are updated
+	HookTypePostReceive HookType = "post-receive"
+)
+
+// IsValid checks if the hook type is supported
+func (t HookType) IsValid() bool {
+	return t == HookTypePreReceive || t == HookTypePostReceive
+}
+
+// ErrManagedHookNotExist represents a "ManagedHookNotExist" kind of error.
+type ErrManagedHookNotExist struct {
+	OwnerID int64
+	ID      int64
+}
+
+// IsErrManagedHookNotExist checks if an error is a ErrManagedHookNotExist.
+func IsErrManagedHookNotExist(err error) bool {
+	_, ok := err.(ErrManagedHookNotExist)
+	return ok
+}
+
+func (err ErrManagedHookNotExist) Error() string {
+	return fmt.Sprintf("managed hook does not exist [owner_id: %d, id: %d]", err.OwnerID, err.ID)
+}
+
+func (err ErrManagedHookNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// ErrManagedHookAlreadyExist represents a "ManagedHookAlreadyExist" kind of error.
+type ErrManagedHookAlreadyExist struct {
+	OwnerID int64
+	Name    string
+}
+
+// IsErrManagedHookAlreadyExist checks if an error is a ErrManagedHookAlreadyExist.
+func IsErrManagedHookAlreadyExist(err error) bool {
+	_, ok := err.(ErrManagedHookAlreadyExist)
+	return ok
+}
+
+func (err ErrManagedHookAlreadyExist) Error() string {
+	return fmt.Sprintf("managed hook already exists [owner_id: %d, name: %s]", err.OwnerID, err.Name)
+}
+
+func (err ErrManagedHookAlreadyExist) Unwrap() error {
+	return util.ErrAlreadyExist
+}
+
+// ManagedHook is a git hook script defined by a site administrator, which runs for all repositories
+// of the instance or of an organization without being stored in their hook directories
+type ManagedHook struct {
+	ID int64 `xorm:"pk autoincr"`
+	// OwnerID is the organization the hook applies to, 0 for instance wide hooks
+	OwnerID  int64    `xorm:"UNIQUE(owner_name) NOT NULL DEFAULT 0"`
+	Name     string   `xorm:"UNIQUE(owner_name) NOT NULL"`
+	Type     HookType `xorm:"VARCHAR(20) INDEX NOT NULL"`
+	Content  string   `xorm:"LONGTEXT"`
+	IsActive bool     `xorm:"NOT NULL DEFAULT true"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ManagedHook))
+}
+
+// GetManagedHook returns a hook of the instance (ownerID 0) or an organization
+func GetManagedHook(ctx context.Context, ownerID, id int64) (*ManagedHook, error) {
+	hook := &ManagedHook{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND owner_id = ?", id, ownerID).Get(hook)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrManagedHookNotExist{OwnerID: ownerID, ID: id}
+	}
+	return hook, nil
+}
+
+// FindManagedHooks returns the hooks of the instance (ownerID 0) or an organization ordered by name
+func FindManagedHooks(ctx context.Context, ownerID int64) ([]*ManagedHook, error) {
+	hooks := make([]*ManagedHook, 0, 5)
+	return hooks, db.GetEngine(ctx).Where("owner_id = ?", ownerID).OrderBy("name").Find(&hooks)
+}
+
+// GetActiveManagedHooks returns the active hooks of a type which apply to the repositories of an owner,
+// the instance wide hooks first
+func GetActiveManagedHooks(ctx context.Context, ownerID int64, typ HookType) ([]*ManagedHook, error) {
+	hooks := make([]*ManagedHook, 0, 5)
+	return hooks, db.GetEngine(ctx).
+		Where(builder.In("owner_id", 0, ownerID)).
+		And("type = ? AND is_active = ?", typ, true).
+		OrderBy("owner_id, name").
+		Find(&hooks)
+}
+
+// CreateManagedHook creates a hook, its name must be unique for the owner
+func CreateManagedHook(ctx context.Context, hook *ManagedHook) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		has, err := db.GetEngine(ctx).Where("owner_id = ? AND name = ?", hook.OwnerID, hook.Name).Exist(new(ManagedHook))
+		if err != nil {
+			return err
+		} else if has {
+			return ErrManagedHookAlreadyExist{OwnerID: hook.OwnerID, Name: hook.Name}
+		}
+		return db.Insert(ctx, hook)
+	})
+}
+
+// UpdateManagedHook updates the given columns of a hook
+func UpdateManagedHook(ctx context.Context, hook *ManagedHook, cols ...string) error {
+	_, err := db.GetEngine(ctx).ID(hook.ID).Cols(cols...).Update(hook)
+	return err
+}
+
+// DeleteManagedHook removes a hook of the instance (ownerID 0) or an organization
+func DeleteManagedHook(ctx context.Context, ownerID, id int64) error {
+	n, err := db.GetEngine(ctx).Where("id = ? AND owner_id = ?", id, ownerID).Delete(new(ManagedHook))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrManagedHookNotExist{OwnerID: ownerID, ID: id}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package githook_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	githook_model "code.gitea.io/gitea/models/githook"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestManagedHooks(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	for _, hook := range []*githook_model.ManagedHook{
+		{Name: "check-size", Type: githook_model.HookTypePreReceive, Content: "#!/bin/sh\nexit 0\n", IsActive: true},
+		{Name: "notify", Type: githook_model.HookTypePostReceive, Content: "#!/bin/sh\n", IsActive: true},
+		{OwnerID: 3, Name: "check-size", Type: githook_model.HookTypePreReceive, Content: "#!/bin/sh\n", IsActive: true},
+		{OwnerID: 3, Name: "disabled", Type: githook_model.HookTypePreReceive, Content: "#!/bin/sh\n"},
+	} {
+		assert.NoError(t, githook_model.CreateManagedHook(db.DefaultContext, hook))
+	}
+	err := githook_model.CreateManagedHook(db.DefaultContext, &githook_model.ManagedHook{Name: "notify", Type: githook_model.HookTypePreReceive})
+	assert.True(t, githook_model.IsErrManagedHookAlreadyExist(err))
+
+	hooks, err := githook_model.FindManagedHooks(db.DefaultContext, 3)
+	assert.NoError(t, err)
+	assert.Len(t, hooks, 2)
+
+	// instance wide hooks run first, inactive hooks are skipped
+	hooks, err = githook_model.GetActiveManagedHooks(db.DefaultContext, 3, githook_model.HookTypePreReceive)
+	assert.NoError(t, err)
+	if assert.Len(t, hooks, 2) {
+		assert.EqualValues(t, 0, hooks[0].OwnerID)
+		assert.EqualValues(t, 3, hooks[1].OwnerID)
+	}
+	hooks, err = githook_model.GetActiveManagedHooks(db.DefaultContext, 2, githook_model.HookTypePreReceive)
+	assert.NoError(t, err)
+	assert.Len(t, hooks, 1)
+
+	// hooks of another owner can't be accessed
+	_, err = githook_model.GetManagedHook(db.DefaultContext, 0, hooks[0].ID)
+	assert.NoError(t, err)
+	_, err = githook_model.GetManagedHook(db.DefaultContext, 3, hooks[0].ID)
+	assert.True(t, githook_model.IsErrManagedHookNotExist(err))
+	assert.True(t, githook_model.IsErrManagedHookNotExist(githook_model.DeleteManagedHook(db.DefaultContext, 3, hooks[0].ID)))
+	assert.NoError(t, githook_model.DeleteManagedHook(db.DefaultContext, 0, hooks[0].ID))
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package insights
+
+import (
+	"context"
+	"sort"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// PathContribution is the contribution of an author to a directory of the default branch of a repository,
+// computed in the background from the recent commit history
+type PathContribution struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"INDEX NOT NULL"`
+	// Path is the directory, empty for the root of the repository
+	Path string `xorm:"VARCHAR(500) NOT NULL DEFAULT ''"`
+	// Depth is the number of components of the path
+	Depth       int    `xorm:"NOT NULL DEFAULT 0"`
+	AuthorName  string `xorm:"NOT NULL DEFAULT ''"`
+	AuthorEmail string `xorm:"NOT NULL DEFAULT ''"`
+	Commits     int64  `xorm:"NOT NULL DEFAULT 0"`
+	// Lines is the number of lines the author added and deleted
+	Lines          int64              `xorm:"NOT NULL DEFAULT 0"`
+	LastCommitUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+}
+
+// PathOwnershipStatus records the commit of the default branch the path contributions of a repository were computed at
+type PathOwnershipStatus struct {
+	ID           int64              `xorm:"pk autoincr"`
+	RepoID       int64              `xorm:"UNIQUE NOT NULL"`
+	CommitID     string             `xorm:"VARCHAR(64) NOT NULL"`
+	ComputedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+}
+
+func init() {
+	db.RegisterModel(new(PathContribution))
+	db.RegisterModel(new(PathOwnershipStatus))
+}
+
+// GetPathOwnershipStatus returns the status of the path contributions of a repository, nil if they weren't computed yet
+func GetPathOwnershipStatus(ctx context.Context, repoID int64) (*PathOwnershipStatus, error) {
+	status := &PathOwnershipStatus{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(status)
+	if err != nil || !has {
+		return nil, err
+	}
+	return status, nil
+}
+
+// ReplacePathContributions replaces the path contributions of a repository with the ones computed at the commit
+func ReplacePathContributions(ctx context.Context, repoID int64, commitID string, contributions []*PathContribution) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(new(PathContribution)); err != nil {
+			return err
+		}
+		for i := 0; i < len(contributions); i += 100 {
+			end := i + 100
+			if end > len(contributions) {
+				end = len(contributions)
+			}
+			if _, err := db.GetEngine(ctx).Insert(contributions[i:end]); err != nil {
+				return err
+			}
+		}
+
+		status := &PathOwnershipStatus{RepoID: repoID, CommitID: commitID, ComputedUnix: timeutil.TimeStampNow()}
+		n, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Cols("commit_id", "computed_unix").Update(status)
+		if err != nil {
+			return err
+		}
+		if n == 0 {
+			return db.Insert(ctx, status)
+		}
+		return nil
+	})
+}
+
+// GetPathContributions returns the contributions to the directories of a repository up to a depth
+func GetPathContributions(ctx context.Context, repoID int64, maxDepth int) ([]*PathContribution, error) {
+	contributions := make([]*PathContribution, 0, 50)
+	return contributions, db.GetEngine(ctx).
+		Where("repo_id = ? AND depth <= ?", repoID, maxDepth).
+		Asc("path", "id").
+		Find(&contributions)
+}
+
+// PathOwnership summarizes the contributions to a directory
+type PathOwnership struct {
+	Path           string
+	Commits        int64
+	Lines          int64
+	LastCommitUnix timeutil.TimeStamp
+	// Contributors are sorted by their share of the changed lines, then by their commits
+	Contributors []*PathContribution
+	// BusFactor is the smallest number of contributors who changed more than half of the lines
+	BusFactor int
+}
+
+// SummarizePathOwnership groups contributions by directory, sorted by path
+func SummarizePathOwnership(contributions []*PathContribution) []*PathOwnership {
+	byPath := make(map[string]*PathOwnership)
+	for _, c := range contributions {
+		po, ok := byPath[c.Path]
+		if !ok {
+			po = &PathOwnership{Path: c.Path}
+			byPath[c.Path] = po
+		}
+		po.Commits += c.Commits
+		po.Lines += c.Lines
+		if c.LastCommitUnix > po.LastCommitUnix {
+			po.LastCommitUnix = c.LastCommitUnix
+		}
+		po.Contributors = append(po.Contributors, c)
+	}
+
+	result := make([]*PathOwnership, 0, len(byPath))
+	for _, po := range byPath {
+		sort.SliceStable(po.Contributors, func(i, j int) bool {
+			if po.Contributors[i].Lines != po.Contributors[j].Lines {
+				return po.Contributors[i].Lines > po.Contributors[j].Lines
+			}
+			return po.Contributors[i].Commits > po.Contributors[j].Commits
+		})
+		po.BusFactor = busFactor(po)
+		result = append(result, po)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Path < result[j].Path })
+	return result
+}
+
+// busFactor counts the top contributors needed to cover more than half of the changed lines,
+// or of the commits if only binary files were changed
+func busFactor(po *PathOwnership) int {
+	total, share := po.Lines, func(c *PathContribution) int64 { return c.Lines }
+	if total == 0 {
+		total, share = po.Commits, func(c *PathContribution) int64 { return c.Commits }
+	}
+	var covered int64
+	for i, c := range po.Contributors {
+		covered += share(c)
+		if covered*2 > total {
+			return i + 1
+		}
+	}
+	return len(po.Contributors)
+}
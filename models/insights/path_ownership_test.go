@@ -0,0 +1,39 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package insights
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizePathOwnership(t *testing.T) {
+	summary := SummarizePathOwnership([]*PathContribution{
+		{Path: "", AuthorEmail: "a@example.com", Commits: 3, Lines: 40, LastCommitUnix: 300},
+		{Path: "", AuthorEmail: "b@example.com", Commits: 1, Lines: 35, LastCommitUnix: 200},
+		{Path: "", AuthorEmail: "c@example.com", Commits: 2, Lines: 25, LastCommitUnix: 100},
+		{Path: "docs", AuthorEmail: "c@example.com", Commits: 2, Lines: 25, LastCommitUnix: 100},
+		{Path: "assets", AuthorEmail: "a@example.com", Commits: 1, LastCommitUnix: 300},
+		{Path: "assets", AuthorEmail: "b@example.com", Commits: 1, LastCommitUnix: 200},
+	})
+	if assert.Len(t, summary, 3) {
+		root := summary[0]
+		assert.Equal(t, "", root.Path)
+		assert.EqualValues(t, 6, root.Commits)
+		assert.EqualValues(t, 100, root.Lines)
+		assert.EqualValues(t, 300, root.LastCommitUnix)
+		assert.Equal(t, "a@example.com", root.Contributors[0].AuthorEmail)
+		assert.Equal(t, "c@example.com", root.Contributors[2].AuthorEmail)
+		// 40 lines are not more than half, 75 are
+		assert.Equal(t, 2, root.BusFactor)
+
+		assert.Equal(t, "assets", summary[1].Path)
+		// without changed lines the commits count, 1 of 2 is not more than half
+		assert.Equal(t, 2, summary[1].BusFactor)
+
+		assert.Equal(t, "docs", summary[2].Path)
+		assert.Equal(t, 1, summary[2].BusFactor)
+	}
+}
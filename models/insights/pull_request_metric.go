@@ -0,0 +1,202 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package insights
+
+import (
+	"context"
+	"sort"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// PullRequestMetric holds the durations of a merged pull request, computed in the background
+// so the insights of a repository or an organization don't need to inspect every pull request.
+type PullRequestMetric struct {
+	ID            int64 `xorm:"pk autoincr"`
+	PullRequestID int64 `xorm:"UNIQUE NOT NULL"`
+	RepoID        int64 `xorm:"INDEX NOT NULL"`
+	// CycleTime is the number of seconds from opening the pull request to merging it
+	CycleTime int64 `xorm:"NOT NULL DEFAULT 0"`
+	// LeadTime is the number of seconds from the first commit of the pull request to merging it
+	LeadTime int64 `xorm:"NOT NULL DEFAULT 0"`
+	// ReviewLatency is the number of seconds from opening the pull request to its first review, -1 if it wasn't reviewed
+	ReviewLatency int64              `xorm:"NOT NULL DEFAULT -1"`
+	OpenedUnix    timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	MergedUnix    timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+}
+
+func init() {
+	db.RegisterModel(new(PullRequestMetric))
+}
+
+// InsertPullRequestMetric inserts the metric of a merged pull request
+func InsertPullRequestMetric(ctx context.Context, metric *PullRequestMetric) error {
+	return db.Insert(ctx, metric)
+}
+
+// FindPullRequestsWithoutMetric returns the merged pull requests after afterID whose metrics weren't computed yet, oldest first
+func FindPullRequestsWithoutMetric(ctx context.Context, afterID int64, limit int) ([]*issues_model.PullRequest, error) {
+	prs := make([]*issues_model.PullRequest, 0, limit)
+	return prs, db.GetEngine(ctx).
+		Where(builder.Eq{"has_merged": true}.And(builder.Gt{"id": afterID})).
+		And(builder.NotIn("id", builder.Select("pull_request_id").From("pull_request_metric"))).
+		Asc("id").
+		Limit(limit).
+		Find(&prs)
+}
+
+// StatsOptions are the options to aggregate the metrics of the pull requests merged in a time range
+type StatsOptions struct {
+	RepoID  int64
+	OwnerID int64
+	Since   timeutil.TimeStamp
+	Before  timeutil.TimeStamp
+}
+
+func (opts *StatsOptions) repoCond(column string) builder.Cond {
+	cond := builder.NewCond()
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{column: opts.RepoID})
+	}
+	if opts.OwnerID > 0 {
+		cond = cond.And(builder.In(column, builder.Select("id").From("repository").Where(builder.Eq{"owner_id": opts.OwnerID})))
+	}
+	return cond
+}
+
+// DurationStats summarizes durations in seconds
+type DurationStats struct {
+	Count   int64
+	Average int64
+	Median  int64
+}
+
+// Stats are the aggregated metrics of a time range
+type Stats struct {
+	MergedPullRequests int64
+	Deployments        int64
+	CycleTime          DurationStats
+	LeadTime           DurationStats
+	ReviewLatency      DurationStats
+}
+
+// durations collects the durations of a set of pull requests
+type durations struct {
+	cycleTime     []int64
+	leadTime      []int64
+	reviewLatency []int64
+}
+
+func (d *durations) add(metric *PullRequestMetric) {
+	d.cycleTime = append(d.cycleTime, metric.CycleTime)
+	d.leadTime = append(d.leadTime, metric.LeadTime)
+	if metric.ReviewLatency >= 0 {
+		d.reviewLatency = append(d.reviewLatency, metric.ReviewLatency)
+	}
+}
+
+func (d *durations) stats() *Stats {
+	return &Stats{
+		MergedPullRequests: int64(len(d.cycleTime)),
+		CycleTime:          summarizeDurations(d.cycleTime),
+		LeadTime:           summarizeDurations(d.leadTime),
+		ReviewLatency:      summarizeDurations(d.reviewLatency),
+	}
+}
+
+// summarizeDurations returns the count, the average and the median of durations, sorting them in place
+func summarizeDurations(values []int64) DurationStats {
+	if len(values) == 0 {
+		return DurationStats{}
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	var sum int64
+	for _, v := range values {
+		sum += v
+	}
+	median := values[len(values)/2]
+	if len(values)%2 == 0 {
+		median = (values[len(values)/2-1] + median) / 2
+	}
+	return DurationStats{
+		Count:   int64(len(values)),
+		Average: sum / int64(len(values)),
+		Median:  median,
+	}
+}
+
+// GetStats aggregates the metrics of the pull requests merged and the releases published in the time range,
+// in total and per repository. Every published release counts as a deployment.
+func GetStats(ctx context.Context, opts *StatsOptions) (*Stats, map[int64]*Stats, error) {
+	total := &durations{}
+	perRepo := make(map[int64]*durations)
+
+	cond := opts.repoCond("repo_id").
+		And(builder.Gte{"merged_unix": opts.Since}).
+		And(builder.Lt{"merged_unix": opts.Before})
+	err := db.Iterate(ctx, cond, func(ctx context.Context, metric *PullRequestMetric) error {
+		total.add(metric)
+		d, ok := perRepo[metric.RepoID]
+		if !ok {
+			d = &durations{}
+			perRepo[metric.RepoID] = d
+		}
+		d.add(metric)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	type releaseCount struct {
+		RepoID int64
+		Count  int64
+	}
+	releaseCounts := make([]*releaseCount, 0, 10)
+	if err := db.GetEngine(ctx).Table("release").
+		Select("repo_id, COUNT(*) AS count").
+		Where(opts.repoCond("repo_id").
+			And(builder.Eq{"is_draft": false, "is_tag": false}).
+			And(builder.Gte{"created_unix": opts.Since}).
+			And(builder.Lt{"created_unix": opts.Before})).
+		GroupBy("repo_id").
+		Find(&releaseCounts); err != nil {
+		return nil, nil, err
+	}
+
+	totalStats := total.stats()
+	perRepoStats := make(map[int64]*Stats, len(perRepo))
+	for repoID, d := range perRepo {
+		perRepoStats[repoID] = d.stats()
+	}
+	for _, rc := range releaseCounts {
+		totalStats.Deployments += rc.Count
+		s, ok := perRepoStats[rc.RepoID]
+		if !ok {
+			s = (&durations{}).stats()
+			perRepoStats[rc.RepoID] = s
+		}
+		s.Deployments = rc.Count
+	}
+	return totalStats, perRepoStats, nil
+}
+
+// GetFirstReviewUnix returns when a pull request was first reviewed by someone else than its poster, 0 if it wasn't
+func GetFirstReviewUnix(ctx context.Context, issueID, posterID int64) (timeutil.TimeStamp, error) {
+	var first int64
+	if _, err := db.GetEngine(ctx).Table("review").
+		Select("COALESCE(MIN(created_unix), 0)").
+		Where(builder.Eq{"issue_id": issueID}.
+			And(builder.Neq{"reviewer_id": posterID}).
+			And(builder.In("type", issues_model.ReviewTypeApprove, issues_model.ReviewTypeComment, issues_model.ReviewTypeReject))).
+		Get(&first); err != nil {
+		return 0, err
+	}
+	return timeutil.TimeStamp(first), nil
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package insights
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSummarizeDurations(t *testing.T) {
+	assert.Equal(t, DurationStats{}, summarizeDurations(nil))
+	assert.Equal(t, DurationStats{Count: 1, Average: 60, Median: 60}, summarizeDurations([]int64{60}))
+	assert.Equal(t, DurationStats{Count: 3, Average: 40, Median: 30}, summarizeDurations([]int64{80, 10, 30}))
+	assert.Equal(t, DurationStats{Count: 4, Average: 100, Median: 30}, summarizeDurations([]int64{40, 330, 10, 20}))
+}
+
+func TestDurationsStats(t *testing.T) {
+	d := &durations{}
+	d.add(&PullRequestMetric{CycleTime: 100, LeadTime: 300, ReviewLatency: -1})
+	d.add(&PullRequestMetric{CycleTime: 200, LeadTime: 200, ReviewLatency: 50})
+
+	stats := d.stats()
+	assert.EqualValues(t, 2, stats.MergedPullRequests)
+	assert.Equal(t, DurationStats{Count: 2, Average: 150, Median: 150}, stats.CycleTime)
+	assert.Equal(t, DurationStats{Count: 2, Average: 250, Median: 250}, stats.LeadTime)
+	// pull requests merged without a review don't count towards the review latency
+	assert.Equal(t, DurationStats{Count: 1, Average: 50, Median: 50}, stats.ReviewLatency)
+}
@@ -107,6 +107,8 @@ const (
 	CommentTypePRScheduledToAutoMerge   // 34 pr was scheduled to auto merge when checks succeed
 	CommentTypePRUnScheduledToAutoMerge // 35 pr was un scheduled to auto merge when checks succeed
 
+	CommentTypeCloseIssueRef // 36 merged pr closed an issue in another repository
+
 )
 
 var commentStrings = []string{
@@ -146,6 +148,7 @@ var commentStrings = []string{
 	"change_issue_ref",
 	"pull_scheduled_merge",
 	"pull_cancel_scheduled_merge",
+	"close_issue_ref",
 }
 
 func (t CommentType) String() string {
@@ -277,6 +280,14 @@ type Comment struct {
 	ReviewID    int64   `xorm:"index"`
 	Invalidated bool
 
+	// HiddenReason is set when a moderator hides the comment, see HideComment
+	HiddenReason CommentHiddenReason `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+	HiddenByID   int64               `xorm:"NOT NULL DEFAULT 0"`
+
+	// ReplyToID is the top-level comment this comment replies to, see comment_thread.go
+	ReplyToID       int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	ThreadCollapsed bool  `xorm:"NOT NULL DEFAULT false"`
+
 	// Reference an issue or pull from another comment, issue or PR
 	// All information is about the origin of the reference
 	RefRepoID    int64                 `xorm:"index"` // Repo where the referencing
@@ -812,6 +823,7 @@ func CreateComment(ctx context.Context, opts *CreateCommentOptions) (_ *Comment,
 		RefIsPull:        opts.RefIsPull,
 		IsForcePush:      opts.IsForcePush,
 		Invalidated:      opts.Invalidated,
+		ReplyToID:        opts.ReplyToID,
 	}
 	if _, err = e.Insert(comment); err != nil {
 		return nil, err
@@ -983,6 +995,7 @@ type CreateCommentOptions struct {
 	RefIsPull        bool
 	IsForcePush      bool
 	Invalidated      bool
+	ReplyToID        int64
 }
 
 // GetCommentByID returns the comment by given ID.
@@ -1010,6 +1023,7 @@ type FindCommentsOptions struct {
 	Type        CommentType
 	IssueIDs    []int64
 	Invalidated util.OptionalBool
+	ReplyToID   int64
 }
 
 // ToConds implements FindOptions interface
@@ -1044,6 +1058,9 @@ func (opts *FindCommentsOptions) ToConds() builder.Cond {
 	if !opts.Invalidated.IsNone() {
 		cond = cond.And(builder.Eq{"comment.invalidated": opts.Invalidated.IsTrue()})
 	}
+	if opts.ReplyToID > 0 {
+		cond = cond.And(builder.Eq{"comment.reply_to_id": opts.ReplyToID})
+	}
 	return cond
 }
 
@@ -1120,6 +1137,15 @@ func DeleteComment(ctx context.Context, comment *Comment) error {
 		return err
 	}
 
+	// replies of a deleted comment are kept as top-level comments
+	if _, err := e.Table("comment").
+		Where("reply_to_id = ?", comment.ID).
+		Update(map[string]interface{}{
+			"reply_to_id": 0,
+		}); err != nil {
+		return err
+	}
+
 	if comment.Type == CommentTypeComment {
 		if _, err := e.ID(comment.IssueID).Decr("num_comments").Update(new(Issue)); err != nil {
 			return err
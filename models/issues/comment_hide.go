@@ -0,0 +1,58 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CommentHiddenReason is why a moderator hid a comment
+type CommentHiddenReason string
+
+const (
+	CommentHiddenReasonSpam      CommentHiddenReason = "spam"
+	CommentHiddenReasonAbuse     CommentHiddenReason = "abuse"
+	CommentHiddenReasonOffTopic  CommentHiddenReason = "off-topic"
+	CommentHiddenReasonOutdated  CommentHiddenReason = "outdated"
+	CommentHiddenReasonDuplicate CommentHiddenReason = "duplicate"
+	CommentHiddenReasonResolved  CommentHiddenReason = "resolved"
+)
+
+// IsValid checks if the reason is known
+func (r CommentHiddenReason) IsValid() bool {
+	switch r {
+	case CommentHiddenReasonSpam, CommentHiddenReasonAbuse, CommentHiddenReasonOffTopic,
+		CommentHiddenReasonOutdated, CommentHiddenReasonDuplicate, CommentHiddenReasonResolved:
+		return true
+	}
+	return false
+}
+
+// IsHidden checks if a moderator hid the comment
+func (c *Comment) IsHidden() bool {
+	return c.HiddenReason != ""
+}
+
+// HideComment collapses a comment for the given reason, the content is kept
+func HideComment(ctx context.Context, c *Comment, doer *user_model.User, reason CommentHiddenReason) error {
+	if !reason.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid reason %q", reason)
+	}
+	c.HiddenReason = reason
+	c.HiddenByID = doer.ID
+	_, err := db.GetEngine(ctx).ID(c.ID).Cols("hidden_reason", "hidden_by_id").NoAutoTime().Update(c)
+	return err
+}
+
+// UnhideComment shows a hidden comment again
+func UnhideComment(ctx context.Context, c *Comment) error {
+	c.HiddenReason = ""
+	c.HiddenByID = 0
+	_, err := db.GetEngine(ctx).ID(c.ID).Cols("hidden_reason", "hidden_by_id").NoAutoTime().Update(c)
+	return err
+}
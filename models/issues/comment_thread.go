@@ -0,0 +1,56 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// Comments can be threaded one level deep: a reply always points to a top-level
+// comment, replying to a reply attaches the new comment to the same thread.
+
+// IsReply checks if the comment replies to another comment
+func (c *Comment) IsReply() bool {
+	return c.ReplyToID > 0
+}
+
+// GetThreadRootID returns the ID of the top-level comment a reply to replyToID belongs to
+func GetThreadRootID(ctx context.Context, issueID, replyToID int64) (int64, error) {
+	c, err := GetCommentByID(ctx, replyToID)
+	if err != nil {
+		return 0, err
+	}
+	if c.IssueID != issueID {
+		return 0, util.NewInvalidArgumentErrorf("comment %d does not belong to issue %d", replyToID, issueID)
+	}
+	if c.Type != CommentTypeComment {
+		return 0, util.NewInvalidArgumentErrorf("comment %d can not be replied to", replyToID)
+	}
+	if c.IsReply() {
+		return c.ReplyToID, nil
+	}
+	return c.ID, nil
+}
+
+// FindCommentReplies returns the replies to a top-level comment, oldest first
+func FindCommentReplies(ctx context.Context, c *Comment) (CommentList, error) {
+	return FindComments(ctx, &FindCommentsOptions{
+		IssueID:   c.IssueID,
+		Type:      CommentTypeComment,
+		ReplyToID: c.ID,
+	})
+}
+
+// SetCommentThreadCollapsed changes whether the replies to a top-level comment are collapsed
+func SetCommentThreadCollapsed(ctx context.Context, c *Comment, collapsed bool) error {
+	if c.IsReply() {
+		return util.NewInvalidArgumentErrorf("comment %d is not the start of a thread", c.ID)
+	}
+	c.ThreadCollapsed = collapsed
+	_, err := db.GetEngine(ctx).ID(c.ID).Cols("thread_collapsed").NoAutoTime().Update(c)
+	return err
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommentThread(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: issue.RepoID})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	root := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: 2})
+
+	reply := func(replyToID int64) *issues_model.Comment {
+		rootID, err := issues_model.GetThreadRootID(db.DefaultContext, issue.ID, replyToID)
+		assert.NoError(t, err)
+		c, err := issues_model.CreateComment(db.DefaultContext, &issues_model.CreateCommentOptions{
+			Type:      issues_model.CommentTypeComment,
+			Doer:      doer,
+			Repo:      repo,
+			Issue:     issue,
+			Content:   "reply",
+			ReplyToID: rootID,
+		})
+		assert.NoError(t, err)
+		return c
+	}
+
+	first := reply(root.ID)
+	assert.EqualValues(t, root.ID, first.ReplyToID)
+	// replies to a reply join the thread of the top-level comment
+	second := reply(first.ID)
+	assert.EqualValues(t, root.ID, second.ReplyToID)
+
+	replies, err := issues_model.FindCommentReplies(db.DefaultContext, root)
+	assert.NoError(t, err)
+	if assert.Len(t, replies, 2) {
+		assert.EqualValues(t, first.ID, replies[0].ID)
+		assert.EqualValues(t, second.ID, replies[1].ID)
+	}
+
+	// label comment
+	_, err = issues_model.GetThreadRootID(db.DefaultContext, issue.ID, 1)
+	assert.Error(t, err)
+	// comment of another issue
+	_, err = issues_model.GetThreadRootID(db.DefaultContext, 2, root.ID)
+	assert.Error(t, err)
+
+	assert.Error(t, issues_model.SetCommentThreadCollapsed(db.DefaultContext, first, true))
+	assert.NoError(t, issues_model.SetCommentThreadCollapsed(db.DefaultContext, root, true))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: root.ID, ThreadCollapsed: true})
+
+	assert.NoError(t, issues_model.DeleteComment(db.DefaultContext, root))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: first.ID}, unittest.Cond("reply_to_id = ?", 0))
+	unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: second.ID}, unittest.Cond("reply_to_id = ?", 0))
+}
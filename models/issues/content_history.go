@@ -65,6 +65,19 @@ func KeepLimitedContentHistory(ctx context.Context, issueID, commentID int64, li
 		EditedUnix timeutil.TimeStamp
 	}
 
+	// the revisions of held content are kept until the hold is released
+	var repoID int64
+	if _, err := db.GetEngine(ctx).Table("issue").Where("id = ?", issueID).Cols("repo_id").Get(&repoID); err != nil {
+		log.Error("can not query the repository of issue %d, err=%v", issueID, err)
+		return
+	}
+	if held, err := IsUnderLegalHold(ctx, repoID, issueID); err != nil {
+		log.Error("can not query the legal holds of issue %d, err=%v", issueID, err)
+		return
+	} else if held {
+		return
+	}
+
 	var res []*IDEditTime
 	err := db.GetEngine(ctx).Select("id, edited_unix").Table("issue_content_history").
 		Where(builder.Eq{"issue_id": issueID, "comment_id": commentID}).
@@ -191,6 +204,15 @@ func SoftDeleteIssueContentHistory(dbCtx context.Context, historyID int64) error
 	return nil
 }
 
+// IsLatestContentHistory returns true if the revision is the current content of its issue or comment
+func IsLatestContentHistory(ctx context.Context, history *ContentHistory) (bool, error) {
+	hasNewer, err := db.GetEngine(ctx).
+		Where(builder.Eq{"issue_id": history.IssueID, "comment_id": history.CommentID}).
+		And(builder.Gt{"edited_unix": history.EditedUnix}.Or(builder.Eq{"edited_unix": history.EditedUnix}.And(builder.Gt{"id": history.ID}))).
+		Exist(new(ContentHistory))
+	return !hasNewer, err
+}
+
 // ErrIssueContentHistoryNotExist not exist error
 type ErrIssueContentHistoryNotExist struct {
 	ID int64
@@ -132,6 +132,10 @@ type Issue struct {
 	// IsLocked limits commenting abilities to users on an issue
 	// with write access
 	IsLocked bool `xorm:"NOT NULL DEFAULT false"`
+	// LockLevel decides who can still comment on a locked issue, empty is collaborators
+	LockLevel IssueLockLevel `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+	// LockUntilUnix is when a locked issue is unlocked automatically, 0 keeps it locked
+	LockUntilUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
 
 	// For view issue page.
 	ShowRole RoleDescriptor `xorm:"-"`
@@ -561,6 +565,15 @@ func GetIssueIDsByRepoID(ctx context.Context, repoID int64) ([]int64, error) {
 	return ids, err
 }
 
+// GetLastIssueUpdatedUnix returns when an issue or pull request of the repository was last updated, or 0 if it has none
+func GetLastIssueUpdatedUnix(ctx context.Context, repoID int64) (timeutil.TimeStamp, error) {
+	var updated int64
+	if _, err := db.GetEngine(ctx).Select("COALESCE(MAX(updated_unix), 0)").Table("issue").Where("repo_id = ?", repoID).Get(&updated); err != nil {
+		return 0, err
+	}
+	return timeutil.TimeStamp(updated), nil
+}
+
 // GetParticipantsIDsByIssueID returns the IDs of all users who participated in comments of an issue,
 // but skips joining with `user` for performance reasons.
 // User permissions must be verified elsewhere if required.
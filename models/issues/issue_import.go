@@ -0,0 +1,65 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/container"
+)
+
+// ImportIssues inserts issues imported from another system together with their labels and comments,
+// keeping the timestamps and original authors they were given. The issues get the next indexes of the
+// repository in the given order.
+func ImportIssues(ctx context.Context, repo *repo_model.Repository, issues []*Issue) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		e := db.GetEngine(ctx)
+		labelIDs := make(container.Set[int64])
+		for _, issue := range issues {
+			idx, err := db.GetNextResourceIndex(ctx, "issue_index", repo.ID)
+			if err != nil {
+				return fmt.Errorf("generate issue index failed: %w", err)
+			}
+			issue.RepoID = repo.ID
+			issue.Index = idx
+			issue.IsPull = false
+			issue.NumComments = 0
+			for _, comment := range issue.Comments {
+				if comment.Type == CommentTypeComment {
+					issue.NumComments++
+				}
+			}
+			if _, err := e.NoAutoTime().Insert(issue); err != nil {
+				return err
+			}
+
+			for _, label := range issue.Labels {
+				if _, err := e.Insert(&IssueLabel{IssueID: issue.ID, LabelID: label.ID}); err != nil {
+					return err
+				}
+				labelIDs.Add(label.ID)
+			}
+
+			for _, comment := range issue.Comments {
+				comment.IssueID = issue.ID
+				if _, err := e.NoAutoTime().Insert(comment); err != nil {
+					return err
+				}
+			}
+		}
+
+		for labelID := range labelIDs {
+			if err := updateLabelCols(ctx, &Label{ID: labelID}, "num_issues", "num_closed_issue"); err != nil {
+				return err
+			}
+		}
+		if err := repo_model.UpdateRepoIssueNumbers(ctx, repo.ID, false, false); err != nil {
+			return err
+		}
+		return repo_model.UpdateRepoIssueNumbers(ctx, repo.ID, false, true)
+	})
+}
@@ -4,19 +4,81 @@
 package issues
 
 import (
+	"context"
+
 	"code.gitea.io/gitea/models/db"
+	access_model "code.gitea.io/gitea/models/perm/access"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// IssueLockLevel decides who can still comment on a locked issue
+type IssueLockLevel string
+
+const (
+	// IssueLockLevelCollaborators allows the users with write access to the issues or pull requests to comment
+	IssueLockLevelCollaborators IssueLockLevel = "collaborators"
+	// IssueLockLevelMaintainers allows only the administrators of the repository to comment
+	IssueLockLevelMaintainers IssueLockLevel = "maintainers"
 )
 
+// IsValid returns true if the lock level is known
+func (l IssueLockLevel) IsValid() bool {
+	return l == IssueLockLevelCollaborators || l == IssueLockLevelMaintainers
+}
+
 // IssueLockOptions defines options for locking and/or unlocking an issue/PR
 type IssueLockOptions struct {
 	Doer   *user_model.User
 	Issue  *Issue
 	Reason string
+	// Level defaults to collaborators
+	Level IssueLockLevel
+	// Until is when the issue is unlocked automatically, 0 keeps it locked
+	Until timeutil.TimeStamp
+}
+
+// GetLockLevel returns who can still comment on the issue if it's locked
+func (issue *Issue) GetLockLevel() IssueLockLevel {
+	if issue.LockLevel == "" {
+		return IssueLockLevelCollaborators
+	}
+	return issue.LockLevel
+}
+
+// IsLockExpired checks if the issue is locked until a time which has passed, it's unlocked by the next run of the cron task
+func (issue *Issue) IsLockExpired() bool {
+	return issue.IsLocked && issue.LockUntilUnix > 0 && issue.LockUntilUnix <= timeutil.TimeStampNow()
+}
+
+// IsLockedFor checks if the lock of the issue keeps a user with the given permission from commenting
+func (issue *Issue) IsLockedFor(perm access_model.Permission, doer *user_model.User) bool {
+	if !issue.IsLocked || issue.IsLockExpired() {
+		return false
+	}
+	if doer != nil && doer.IsAdmin {
+		return false
+	}
+	if issue.GetLockLevel() == IssueLockLevelMaintainers {
+		return !perm.IsAdmin()
+	}
+	return !perm.CanWriteIssuesOrPulls(issue.IsPull)
+}
+
+// CanChangeLock checks if a user with the given permission can lock the issue at the given level or unlock it
+func (issue *Issue) CanChangeLock(perm access_model.Permission, level IssueLockLevel) bool {
+	if !perm.CanWriteIssuesOrPulls(issue.IsPull) {
+		return false
+	}
+	if level == IssueLockLevelMaintainers || (issue.IsLocked && issue.GetLockLevel() == IssueLockLevelMaintainers) {
+		return perm.IsAdmin()
+	}
+	return true
 }
 
 // LockIssue locks an issue. This would limit commenting abilities to
-// users with write access to the repo
+// users with write access to the repo, or to its administrators.
+// The level and the expiry of an issue which is locked already are changed without a comment.
 func LockIssue(opts *IssueLockOptions) error {
 	return updateIssueLock(opts, true)
 }
@@ -27,6 +89,18 @@ func UnlockIssue(opts *IssueLockOptions) error {
 }
 
 func updateIssueLock(opts *IssueLockOptions, lock bool) error {
+	level := opts.Level
+	if level == "" {
+		level = IssueLockLevelCollaborators
+	}
+	if lock && opts.Issue.IsLocked {
+		if opts.Issue.GetLockLevel() == level && opts.Issue.LockUntilUnix == opts.Until {
+			return nil
+		}
+		opts.Issue.LockLevel = level
+		opts.Issue.LockUntilUnix = opts.Until
+		return UpdateIssueCols(db.DefaultContext, opts.Issue, "lock_level", "lock_until_unix")
+	}
 	if opts.Issue.IsLocked == lock {
 		return nil
 	}
@@ -35,8 +109,12 @@ func updateIssueLock(opts *IssueLockOptions, lock bool) error {
 	var commentType CommentType
 	if opts.Issue.IsLocked {
 		commentType = CommentTypeLock
+		opts.Issue.LockLevel = level
+		opts.Issue.LockUntilUnix = opts.Until
 	} else {
 		commentType = CommentTypeUnlock
+		opts.Issue.LockLevel = ""
+		opts.Issue.LockUntilUnix = 0
 	}
 
 	ctx, committer, err := db.TxContext(db.DefaultContext)
@@ -45,7 +123,7 @@ func updateIssueLock(opts *IssueLockOptions, lock bool) error {
 	}
 	defer committer.Close()
 
-	if err := UpdateIssueCols(ctx, opts.Issue, "is_locked"); err != nil {
+	if err := UpdateIssueCols(ctx, opts.Issue, "is_locked", "lock_level", "lock_until_unix"); err != nil {
 		return err
 	}
 
@@ -62,3 +140,13 @@ func updateIssueLock(opts *IssueLockOptions, lock bool) error {
 
 	return committer.Commit()
 }
+
+// FindExpiredIssueLocks returns up to limit locked issues whose lock has expired, the earliest first
+func FindExpiredIssueLocks(ctx context.Context, limit int) ([]*Issue, error) {
+	issues := make([]*Issue, 0, limit)
+	return issues, db.GetEngine(ctx).
+		Where("is_locked = ? AND lock_until_unix > 0 AND lock_until_unix <= ?", true, timeutil.TimeStampNow()).
+		Asc("lock_until_unix").
+		Limit(limit).
+		Find(&issues)
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// IssueLockPolicy configures how the closed issues and pull requests of a repository are locked once they have been inactive
+type IssueLockPolicy struct {
+	ID      int64 `xorm:"pk autoincr"`
+	RepoID  int64 `xorm:"UNIQUE NOT NULL"`
+	Enabled bool  `xorm:"INDEX NOT NULL DEFAULT true"`
+
+	ApplyToIssues bool `xorm:"NOT NULL DEFAULT true"`
+	ApplyToPulls  bool `xorm:"NOT NULL DEFAULT true"`
+	// DaysAfterClose is the number of days a closed issue must be without activity before it's locked
+	DaysAfterClose int            `xorm:"NOT NULL DEFAULT 365"`
+	LockLevel      IssueLockLevel `xorm:"VARCHAR(20) NOT NULL DEFAULT 'collaborators'"`
+	// Reason is the lock reason shown in the timeline, one of the configured lock reasons or empty
+	Reason string `xorm:"VARCHAR(255)"`
+	// LockComment is posted before an issue is locked, empty posts no comment
+	LockComment string `xorm:"TEXT"`
+
+	LastRunUnix timeutil.TimeStamp
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueLockPolicy))
+}
+
+// Validate checks the policy and defaults an empty lock level to collaborators
+func (p *IssueLockPolicy) Validate() error {
+	if p.LockLevel == "" {
+		p.LockLevel = IssueLockLevelCollaborators
+	}
+	if !p.LockLevel.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid lock level %q", p.LockLevel)
+	}
+	if !p.ApplyToIssues && !p.ApplyToPulls {
+		return util.NewInvalidArgumentErrorf("the policy must apply to issues, pull requests or both")
+	}
+	if p.DaysAfterClose <= 0 {
+		return util.NewInvalidArgumentErrorf("the number of days after closing must be positive")
+	}
+	return nil
+}
+
+// GetIssueLockPolicy returns the lock policy of a repository, or nil if it has none
+func GetIssueLockPolicy(ctx context.Context, repoID int64) (*IssueLockPolicy, error) {
+	p := &IssueLockPolicy{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// SaveIssueLockPolicy validates and creates or updates the lock policy of a repository
+func SaveIssueLockPolicy(ctx context.Context, p *IssueLockPolicy) error {
+	if err := p.Validate(); err != nil {
+		return err
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetIssueLockPolicy(ctx, p.RepoID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return db.Insert(ctx, p)
+		}
+		p.ID = existing.ID
+		p.LastRunUnix = existing.LastRunUnix
+		_, err = db.GetEngine(ctx).ID(p.ID).AllCols().Update(p)
+		return err
+	})
+}
+
+// DeleteIssueLockPolicy removes the lock policy of a repository
+func DeleteIssueLockPolicy(ctx context.Context, repoID int64) error {
+	_, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(new(IssueLockPolicy))
+	return err
+}
+
+// FindEnabledIssueLockPolicies returns all enabled lock policies
+func FindEnabledIssueLockPolicies(ctx context.Context) ([]*IssueLockPolicy, error) {
+	policies := make([]*IssueLockPolicy, 0, 10)
+	return policies, db.GetEngine(ctx).Where("enabled = ?", true).OrderBy("id").Find(&policies)
+}
+
+// UpdateIssueLockPolicyLastRun records when the policy was last executed
+func UpdateIssueLockPolicyLastRun(ctx context.Context, p *IssueLockPolicy) error {
+	p.LastRunUnix = timeutil.TimeStampNow()
+	_, err := db.GetEngine(ctx).ID(p.ID).Cols("last_run_unix").NoAutoTime().Update(p)
+	return err
+}
+
+// FindIssueLockCandidates returns up to limit unlocked issues of the policy's repository which were closed
+// and last updated before the given time, the least recently updated first
+func FindIssueLockCandidates(ctx context.Context, p *IssueLockPolicy, inactiveSince time.Time, limit int) ([]*Issue, error) {
+	cond := builder.Eq{"repo_id": p.RepoID, "is_closed": true, "is_locked": false}.
+		And(builder.Lt{"closed_unix": inactiveSince.Unix()}).
+		And(builder.Lt{"updated_unix": inactiveSince.Unix()})
+	if p.ApplyToIssues != p.ApplyToPulls {
+		cond = cond.And(builder.Eq{"is_pull": p.ApplyToPulls})
+	}
+	issues := make([]*Issue, 0, limit)
+	return issues, db.GetEngine(ctx).Where(cond).Asc("updated_unix").Limit(limit).Find(&issues)
+}
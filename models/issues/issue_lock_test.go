@@ -0,0 +1,51 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIssueLockLevels(t *testing.T) {
+	permWithMode := func(mode perm.AccessMode) access_model.Permission {
+		return access_model.Permission{
+			AccessMode: mode,
+			UnitsMode:  map[unit.Type]perm.AccessMode{unit.TypeIssues: mode, unit.TypePullRequests: mode},
+		}
+	}
+	reader, writer, admin := permWithMode(perm.AccessModeRead), permWithMode(perm.AccessModeWrite), permWithMode(perm.AccessModeAdmin)
+	siteAdmin := &user_model.User{IsAdmin: true}
+
+	issue := &issues_model.Issue{}
+	assert.False(t, issue.IsLockedFor(reader, nil))
+
+	issue.IsLocked = true
+	assert.True(t, issue.IsLockedFor(reader, nil))
+	assert.False(t, issue.IsLockedFor(writer, nil))
+	assert.False(t, issue.IsLockedFor(reader, siteAdmin))
+	assert.True(t, issue.CanChangeLock(writer, ""))
+	assert.False(t, issue.CanChangeLock(writer, issues_model.IssueLockLevelMaintainers))
+	assert.False(t, issue.CanChangeLock(reader, ""))
+
+	issue.LockLevel = issues_model.IssueLockLevelMaintainers
+	assert.True(t, issue.IsLockedFor(writer, nil))
+	assert.False(t, issue.IsLockedFor(admin, nil))
+	assert.False(t, issue.CanChangeLock(writer, ""))
+	assert.True(t, issue.CanChangeLock(admin, ""))
+
+	issue.LockUntilUnix = timeutil.TimeStampNow().Add(3600)
+	assert.True(t, issue.IsLockedFor(writer, nil))
+	issue.LockUntilUnix = timeutil.TimeStampNow().Add(-1)
+	assert.True(t, issue.IsLockExpired())
+	assert.False(t, issue.IsLockedFor(reader, nil))
+}
@@ -6,10 +6,12 @@ package issues
 import (
 	"context"
 	"fmt"
+	"sort"
 
 	"code.gitea.io/gitea/models/db"
 	project_model "code.gitea.io/gitea/models/project"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // LoadProject load the project the issue was assigned to
@@ -185,3 +187,127 @@ func MoveIssueAcrossProjectBoards(issue *Issue, board *project_model.Board) erro
 
 	return committer.Commit()
 }
+
+// ProjectSwimlane is a row of the boards of a project, with the issues grouped by an assignee, a label or a milestone
+type ProjectSwimlane struct {
+	ID        int64 // the id of the assignee, label or milestone, 0 for the issues without one
+	Name      string
+	IssuesMap map[int64]IssueList // the issues of the row by board id
+}
+
+// ProjectFilterOptions are the assignees, labels and milestones of the issues of a project to filter them by
+type ProjectFilterOptions struct {
+	Assignees  []*user_model.User
+	Labels     []*Label
+	Milestones []*Milestone
+}
+
+// GetProjectFilterOptions returns the assignees, labels and milestones of the issues of the boards
+func GetProjectFilterOptions(issuesMap map[int64]IssueList) *ProjectFilterOptions {
+	opts := &ProjectFilterOptions{}
+	seenAssignees := make(map[int64]bool)
+	seenLabels := make(map[int64]bool)
+	seenMilestones := make(map[int64]bool)
+	for _, issues := range issuesMap {
+		for _, issue := range issues {
+			for _, assignee := range issue.Assignees {
+				if !seenAssignees[assignee.ID] {
+					seenAssignees[assignee.ID] = true
+					opts.Assignees = append(opts.Assignees, assignee)
+				}
+			}
+			for _, label := range issue.Labels {
+				if !seenLabels[label.ID] {
+					seenLabels[label.ID] = true
+					opts.Labels = append(opts.Labels, label)
+				}
+			}
+			if issue.Milestone != nil && !seenMilestones[issue.MilestoneID] {
+				seenMilestones[issue.MilestoneID] = true
+				opts.Milestones = append(opts.Milestones, issue.Milestone)
+			}
+		}
+	}
+	sort.Slice(opts.Assignees, func(i, j int) bool { return opts.Assignees[i].Name < opts.Assignees[j].Name })
+	sort.Slice(opts.Labels, func(i, j int) bool { return opts.Labels[i].Name < opts.Labels[j].Name })
+	sort.Slice(opts.Milestones, func(i, j int) bool { return opts.Milestones[i].Name < opts.Milestones[j].Name })
+	return opts
+}
+
+// FilterProjectIssues returns the issues of the boards which match the filters of the view setting
+func FilterProjectIssues(issuesMap map[int64]IssueList, setting *project_model.ViewSetting) map[int64]IssueList {
+	if !setting.HasFilter() {
+		return issuesMap
+	}
+	filtered := make(map[int64]IssueList, len(issuesMap))
+	for boardID, issues := range issuesMap {
+		list := make(IssueList, 0, len(issues))
+		for _, issue := range issues {
+			if issue.matchesProjectViewSetting(setting) {
+				list = append(list, issue)
+			}
+		}
+		filtered[boardID] = list
+	}
+	return filtered
+}
+
+func (issue *Issue) matchesProjectViewSetting(setting *project_model.ViewSetting) bool {
+	if setting.MilestoneID != 0 && issue.MilestoneID != setting.MilestoneID {
+		return false
+	}
+	if setting.AssigneeID != 0 && !util.SliceContainsFunc(issue.Assignees, func(u *user_model.User) bool { return u.ID == setting.AssigneeID }) {
+		return false
+	}
+	if setting.LabelID != 0 && !util.SliceContainsFunc(issue.Labels, func(l *Label) bool { return l.ID == setting.LabelID }) {
+		return false
+	}
+	return true
+}
+
+// GroupProjectIssuesIntoSwimlanes splits the issues of the boards into rows by their first assignee, first label
+// or milestone, in the order the rows first appear and with the issues without one last. Without a swimlane type
+// all issues are in a single row.
+func GroupProjectIssuesIntoSwimlanes(issuesMap map[int64]IssueList, boards project_model.BoardList, swimlane project_model.SwimlaneType) []*ProjectSwimlane {
+	if swimlane == project_model.SwimlaneTypeNone {
+		return []*ProjectSwimlane{{IssuesMap: issuesMap}}
+	}
+
+	lanes := make([]*ProjectSwimlane, 0, 5)
+	lanesByID := make(map[int64]*ProjectSwimlane)
+	none := &ProjectSwimlane{IssuesMap: make(map[int64]IssueList, len(boards))}
+	for _, board := range boards {
+		for _, issue := range issuesMap[board.ID] {
+			id, name := issue.projectSwimlane(swimlane)
+			lane := none
+			if id != 0 {
+				var ok bool
+				if lane, ok = lanesByID[id]; !ok {
+					lane = &ProjectSwimlane{ID: id, Name: name, IssuesMap: make(map[int64]IssueList, len(boards))}
+					lanesByID[id] = lane
+					lanes = append(lanes, lane)
+				}
+			}
+			lane.IssuesMap[board.ID] = append(lane.IssuesMap[board.ID], issue)
+		}
+	}
+	return append(lanes, none)
+}
+
+func (issue *Issue) projectSwimlane(swimlane project_model.SwimlaneType) (int64, string) {
+	switch swimlane {
+	case project_model.SwimlaneTypeAssignee:
+		if len(issue.Assignees) > 0 {
+			return issue.Assignees[0].ID, issue.Assignees[0].GetDisplayName()
+		}
+	case project_model.SwimlaneTypeLabel:
+		if len(issue.Labels) > 0 {
+			return issue.Labels[0].ID, issue.Labels[0].Name
+		}
+	case project_model.SwimlaneTypeMilestone:
+		if issue.Milestone != nil {
+			return issue.MilestoneID, issue.Milestone.Name
+		}
+	}
+	return 0, ""
+}
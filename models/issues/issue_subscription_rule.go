@@ -0,0 +1,183 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// MaxIssueSubscriptionRules is the maximum number of auto-subscription rules a user can define
+const MaxIssueSubscriptionRules = 50
+
+// IssueSubscriptionRuleKind is what an auto-subscription rule matches
+type IssueSubscriptionRuleKind string
+
+const (
+	// IssueSubscriptionRuleLabel matches the issues and pull requests with a label
+	IssueSubscriptionRuleLabel IssueSubscriptionRuleKind = "label"
+	// IssueSubscriptionRulePath matches the pull requests changing files under a path
+	IssueSubscriptionRulePath IssueSubscriptionRuleKind = "path"
+	// IssueSubscriptionRuleOwnedPaths matches the pull requests changing the directories the user is a main contributor of
+	IssueSubscriptionRuleOwnedPaths IssueSubscriptionRuleKind = "owned_paths"
+)
+
+// IsValid returns true if the kind is known
+func (k IssueSubscriptionRuleKind) IsValid() bool {
+	switch k {
+	case IssueSubscriptionRuleLabel, IssueSubscriptionRulePath, IssueSubscriptionRuleOwnedPaths:
+		return true
+	}
+	return false
+}
+
+// ErrIssueSubscriptionRuleNotExist represents a "IssueSubscriptionRuleNotExist" kind of error.
+type ErrIssueSubscriptionRuleNotExist struct {
+	ID     int64
+	UserID int64
+}
+
+// IsErrIssueSubscriptionRuleNotExist checks if an error is a ErrIssueSubscriptionRuleNotExist.
+func IsErrIssueSubscriptionRuleNotExist(err error) bool {
+	_, ok := err.(ErrIssueSubscriptionRuleNotExist)
+	return ok
+}
+
+func (err ErrIssueSubscriptionRuleNotExist) Error() string {
+	return fmt.Sprintf("issue subscription rule does not exist [id: %d, user_id: %d]", err.ID, err.UserID)
+}
+
+func (err ErrIssueSubscriptionRuleNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// IssueSubscriptionRule subscribes a user to the issues and pull requests it matches when they are created or updated
+type IssueSubscriptionRule struct {
+	ID     int64                     `xorm:"pk autoincr"`
+	UserID int64                     `xorm:"INDEX NOT NULL"`
+	Kind   IssueSubscriptionRuleKind `xorm:"VARCHAR(20) NOT NULL"`
+	// Value is the name of the label or the path, empty for the owned paths
+	Value string `xorm:"VARCHAR(255) NOT NULL DEFAULT ''"`
+	// OwnerID limits the rule to the repositories of a user or an organization, 0 for all repositories
+	OwnerID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	// RepoID limits the rule to a repository, 0 for all repositories
+	RepoID      int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(IssueSubscriptionRule))
+}
+
+// NormalizeSubscriptionPath returns the path of a file or a directory relative to the root of the repository,
+// without leading and trailing slashes
+func NormalizeSubscriptionPath(p string) (string, error) {
+	p = strings.Trim(strings.TrimSpace(p), "/")
+	if p == "" {
+		return "", util.NewInvalidArgumentErrorf("the path must not be empty")
+	}
+	cleaned := path.Clean(p)
+	if cleaned != p || cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", util.NewInvalidArgumentErrorf("invalid path %q", p)
+	}
+	return cleaned, nil
+}
+
+// MatchesLabels returns true if a label rule matches one of the labels, the names are compared case-insensitively
+func (r *IssueSubscriptionRule) MatchesLabels(labels []*Label) bool {
+	if r.Kind != IssueSubscriptionRuleLabel {
+		return false
+	}
+	for _, label := range labels {
+		if strings.EqualFold(label.Name, r.Value) {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesFiles returns true if a path rule matches one of the changed files
+func (r *IssueSubscriptionRule) MatchesFiles(files []string) bool {
+	if r.Kind != IssueSubscriptionRulePath {
+		return false
+	}
+	for _, file := range files {
+		if file == r.Value || strings.HasPrefix(file, r.Value+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// GetIssueSubscriptionRules returns the auto-subscription rules of the user
+func GetIssueSubscriptionRules(ctx context.Context, userID int64) ([]*IssueSubscriptionRule, error) {
+	rules := make([]*IssueSubscriptionRule, 0, 5)
+	return rules, db.GetEngine(ctx).Where("user_id = ?", userID).Asc("id").Find(&rules)
+}
+
+// GetRepoIssueSubscriptionRules returns the auto-subscription rules of all users which apply to a repository
+func GetRepoIssueSubscriptionRules(ctx context.Context, repoID, ownerID int64) ([]*IssueSubscriptionRule, error) {
+	rules := make([]*IssueSubscriptionRule, 0, 10)
+	return rules, db.GetEngine(ctx).
+		Where(builder.Eq{"repo_id": repoID}.Or(
+			builder.Eq{"repo_id": 0}.And(builder.In("owner_id", 0, ownerID)),
+		)).
+		Asc("user_id", "id").
+		Find(&rules)
+}
+
+// CreateIssueSubscriptionRule creates an auto-subscription rule of a user
+func CreateIssueSubscriptionRule(ctx context.Context, rule *IssueSubscriptionRule) error {
+	switch rule.Kind {
+	case IssueSubscriptionRuleLabel:
+		rule.Value = strings.TrimSpace(rule.Value)
+		if rule.Value == "" {
+			return util.NewInvalidArgumentErrorf("the label must not be empty")
+		}
+	case IssueSubscriptionRulePath:
+		p, err := NormalizeSubscriptionPath(rule.Value)
+		if err != nil {
+			return err
+		}
+		rule.Value = p
+	case IssueSubscriptionRuleOwnedPaths:
+		rule.Value = ""
+	default:
+		return util.NewInvalidArgumentErrorf("invalid kind %q", rule.Kind)
+	}
+	if rule.RepoID != 0 {
+		// a rule of a repository is found by its id only
+		rule.OwnerID = 0
+	}
+
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		count, err := db.GetEngine(ctx).Where("user_id = ?", rule.UserID).Count(new(IssueSubscriptionRule))
+		if err != nil {
+			return err
+		} else if count >= MaxIssueSubscriptionRules {
+			return util.NewInvalidArgumentErrorf("at most %d subscription rules can be defined", MaxIssueSubscriptionRules)
+		}
+		_, err = db.GetEngine(ctx).Insert(rule)
+		return err
+	})
+}
+
+// DeleteIssueSubscriptionRule deletes an auto-subscription rule of a user
+func DeleteIssueSubscriptionRule(ctx context.Context, userID, id int64) error {
+	deleted, err := db.GetEngine(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(new(IssueSubscriptionRule))
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrIssueSubscriptionRuleNotExist{ID: id, UserID: userID}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"errors"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeSubscriptionPath(t *testing.T) {
+	for input, expected := range map[string]string{
+		"pkg/net":    "pkg/net",
+		"/pkg/net/":  "pkg/net",
+		" docs ":     "docs",
+		"README.md":  "README.md",
+		"a/b/c.go/ ": "a/b/c.go",
+	} {
+		p, err := issues_model.NormalizeSubscriptionPath(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, p, input)
+	}
+
+	for _, input := range []string{"", "/", "..", "../etc", "a/../b", "a//b", "./a"} {
+		_, err := issues_model.NormalizeSubscriptionPath(input)
+		assert.True(t, errors.Is(err, util.ErrInvalidArgument), input)
+	}
+}
+
+func TestIssueSubscriptionRuleMatches(t *testing.T) {
+	label := &issues_model.IssueSubscriptionRule{Kind: issues_model.IssueSubscriptionRuleLabel, Value: "security"}
+	assert.True(t, label.MatchesLabels([]*issues_model.Label{{Name: "bug"}, {Name: "Security"}}))
+	assert.False(t, label.MatchesLabels([]*issues_model.Label{{Name: "security-review"}}))
+	assert.False(t, label.MatchesFiles([]string{"security"}))
+
+	path := &issues_model.IssueSubscriptionRule{Kind: issues_model.IssueSubscriptionRulePath, Value: "pkg/net"}
+	assert.True(t, path.MatchesFiles([]string{"README.md", "pkg/net/http/client.go"}))
+	assert.True(t, path.MatchesFiles([]string{"pkg/net"}))
+	assert.False(t, path.MatchesFiles([]string{"pkg/network/dial.go", "pkg/netutil.go"}))
+	assert.False(t, path.MatchesLabels([]*issues_model.Label{{Name: "pkg/net"}}))
+}
+
+func TestIssueSubscriptionRules(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	rule := &issues_model.IssueSubscriptionRule{UserID: 2, Kind: issues_model.IssueSubscriptionRuleLabel, Value: " security ", OwnerID: 3}
+	assert.NoError(t, issues_model.CreateIssueSubscriptionRule(db.DefaultContext, rule))
+	assert.Equal(t, "security", rule.Value)
+
+	rule = &issues_model.IssueSubscriptionRule{UserID: 2, Kind: issues_model.IssueSubscriptionRulePath, Value: "/pkg/net/", RepoID: 1, OwnerID: 3}
+	assert.NoError(t, issues_model.CreateIssueSubscriptionRule(db.DefaultContext, rule))
+	assert.Equal(t, "pkg/net", rule.Value)
+	assert.EqualValues(t, 0, rule.OwnerID)
+
+	rule = &issues_model.IssueSubscriptionRule{UserID: 4, Kind: issues_model.IssueSubscriptionRuleOwnedPaths, Value: "ignored"}
+	assert.NoError(t, issues_model.CreateIssueSubscriptionRule(db.DefaultContext, rule))
+	assert.Empty(t, rule.Value)
+
+	err := issues_model.CreateIssueSubscriptionRule(db.DefaultContext, &issues_model.IssueSubscriptionRule{UserID: 2, Kind: "milestone", Value: "v1"})
+	assert.True(t, errors.Is(err, util.ErrInvalidArgument))
+	err = issues_model.CreateIssueSubscriptionRule(db.DefaultContext, &issues_model.IssueSubscriptionRule{UserID: 2, Kind: issues_model.IssueSubscriptionRuleLabel})
+	assert.True(t, errors.Is(err, util.ErrInvalidArgument))
+
+	rules, err := issues_model.GetIssueSubscriptionRules(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.Len(t, rules, 2)
+
+	// repository 1 is owned by user 2: the rules of organization 3 don't apply to it
+	rules, err = issues_model.GetRepoIssueSubscriptionRules(db.DefaultContext, 1, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, rules, 2) {
+		assert.Equal(t, issues_model.IssueSubscriptionRulePath, rules[0].Kind)
+		assert.Equal(t, issues_model.IssueSubscriptionRuleOwnedPaths, rules[1].Kind)
+	}
+	// repository 3 is owned by organization 3
+	rules, err = issues_model.GetRepoIssueSubscriptionRules(db.DefaultContext, 3, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, rules, 2) {
+		assert.Equal(t, issues_model.IssueSubscriptionRuleLabel, rules[0].Kind)
+		assert.Equal(t, issues_model.IssueSubscriptionRuleOwnedPaths, rules[1].Kind)
+	}
+
+	assert.NoError(t, issues_model.DeleteIssueSubscriptionRule(db.DefaultContext, 2, rules[0].ID))
+	assert.True(t, issues_model.IsErrIssueSubscriptionRuleNotExist(issues_model.DeleteIssueSubscriptionRule(db.DefaultContext, 2, rules[0].ID)))
+	assert.True(t, issues_model.IsErrIssueSubscriptionRuleNotExist(issues_model.DeleteIssueSubscriptionRule(db.DefaultContext, 2, rules[1].ID)))
+}
@@ -457,6 +457,25 @@ func TestIssue_ResolveMentions(t *testing.T) {
 	testSuccess("user17", "big_test_private_4", "user20", []string{"user5"}, []int64{})
 	// Private repo, whole team
 	testSuccess("user17", "big_test_private_4", "user15", []string{"user17/owners"}, []int64{18})
+
+	// Private repo, only the leads of the team
+	_, err := db.GetEngine(db.DefaultContext).ID(5).Cols("mention_mode").Update(&organization.Team{MentionMode: organization.TeamMentionModeLeads})
+	assert.NoError(t, err)
+	testSuccess("user17", "big_test_private_4", "user15", []string{"user17/owners"}, []int64{})
+	_, err = db.GetEngine(db.DefaultContext).Where("team_id = ? AND uid = ?", 5, 18).Cols("is_lead").Update(&organization.TeamUser{IsLead: true})
+	assert.NoError(t, err)
+	testSuccess("user17", "big_test_private_4", "user15", []string{"user17/owners"}, []int64{18})
+
+	// Private repo, the team is notified through its webhook
+	_, err = db.GetEngine(db.DefaultContext).ID(5).Cols("mention_mode").Update(&organization.Team{MentionMode: organization.TeamMentionModeWebhook})
+	assert.NoError(t, err)
+	testSuccess("user17", "big_test_private_4", "user15", []string{"user17/owners"}, []int64{})
+
+	// Private repo, the team is notified of one mention per hour
+	_, err = db.GetEngine(db.DefaultContext).ID(5).Cols("mention_mode", "mention_rate_limit").Update(&organization.Team{MentionMode: organization.TeamMentionModeAll, MentionRateLimit: 1})
+	assert.NoError(t, err)
+	testSuccess("user17", "big_test_private_4", "user15", []string{"user17/owners"}, []int64{18})
+	testSuccess("user17", "big_test_private_4", "user15", []string{"user17/owners"}, []int64{})
 }
 
 func TestResourceIndex(t *testing.T) {
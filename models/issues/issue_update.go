@@ -18,6 +18,7 @@ import (
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/references"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
@@ -534,6 +535,46 @@ func FindAndUpdateIssueMentions(ctx context.Context, issue *Issue, doer *user_mo
 	return mentions, err
 }
 
+// FindMentionedTeams returns the teams of the organization owning the repository of the issue with the given
+// lower names, which can read the issue
+func FindMentionedTeams(ctx context.Context, issue *Issue, lowerNames []string) ([]*organization.Team, error) {
+	if len(lowerNames) == 0 {
+		return nil, nil
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	teams := make([]*organization.Team, 0, len(lowerNames))
+	if err := db.GetEngine(ctx).
+		Join("INNER", "team_repo", "team_repo.team_id = team.id").
+		Where("team_repo.repo_id=?", issue.Repo.ID).
+		In("team.lower_name", lowerNames).
+		Find(&teams); err != nil {
+		return nil, fmt.Errorf("find mentioned teams: %w", err)
+	}
+
+	unittype := unit.TypeIssues
+	if issue.IsPull {
+		unittype = unit.TypePullRequests
+	}
+	checked := make([]*organization.Team, 0, len(teams))
+	for _, team := range teams {
+		if team.AccessMode >= perm.AccessModeAdmin {
+			checked = append(checked, team)
+			continue
+		}
+		has, err := db.GetEngine(ctx).Get(&organization.TeamUnit{OrgID: issue.Repo.OwnerID, TeamID: team.ID, Type: unittype})
+		if err != nil {
+			return nil, fmt.Errorf("get team units (%d): %w", team.ID, err)
+		}
+		if has {
+			checked = append(checked, team)
+		}
+	}
+	return checked, nil
+}
+
 // ResolveIssueMentionsByVisibility returns the users mentioned in an issue, removing those that
 // don't have access to reading it. Teams are expanded into their users, but organizations are ignored.
 func ResolveIssueMentionsByVisibility(ctx context.Context, issue *Issue, doer *user_model.User, mentions []string) (users []*user_model.User, err error) {
@@ -574,53 +615,51 @@ func ResolveIssueMentionsByVisibility(ctx context.Context, issue *Issue, doer *u
 		}
 	}
 
-	if issue.Repo.Owner.IsOrganization() && len(mentionTeams) > 0 {
-		teams := make([]*organization.Team, 0, len(mentionTeams))
-		if err := db.GetEngine(ctx).
-			Join("INNER", "team_repo", "team_repo.team_id = team.id").
-			Where("team_repo.repo_id=?", issue.Repo.ID).
-			In("team.lower_name", mentionTeams).
-			Find(&teams); err != nil {
-			return nil, fmt.Errorf("find mentioned teams: %w", err)
+	if len(mentionTeams) > 0 {
+		teams, err := FindMentionedTeams(ctx, issue, mentionTeams)
+		if err != nil {
+			return nil, err
 		}
-		if len(teams) != 0 {
-			checked := make([]int64, 0, len(teams))
-			unittype := unit.TypeIssues
-			if issue.IsPull {
-				unittype = unit.TypePullRequests
+		// teams which notify all their members or only their leads
+		allMembers := make([]int64, 0, len(teams))
+		leadsOnly := make([]int64, 0, len(teams))
+		for _, team := range teams {
+			resolved[issue.Repo.Owner.LowerName+"/"+team.LowerName] = true
+			if team.MentionMode == organization.TeamMentionModeWebhook {
+				// the mention is posted to the webhook of the team instead
+				continue
 			}
-			for _, team := range teams {
-				if team.AccessMode >= perm.AccessModeAdmin {
-					checked = append(checked, team.ID)
-					resolved[issue.Repo.Owner.LowerName+"/"+team.LowerName] = true
-					continue
-				}
-				has, err := db.GetEngine(ctx).Get(&organization.TeamUnit{OrgID: issue.Repo.Owner.ID, TeamID: team.ID, Type: unittype})
-				if err != nil {
-					return nil, fmt.Errorf("get team units (%d): %w", team.ID, err)
-				}
-				if has {
-					checked = append(checked, team.ID)
-					resolved[issue.Repo.Owner.LowerName+"/"+team.LowerName] = true
-				}
+			allowed, err := organization.AllowTeamMention(ctx, team, issue.ID, doer.ID)
+			if err != nil {
+				return nil, fmt.Errorf("AllowTeamMention (%d): %w", team.ID, err)
 			}
-			if len(checked) != 0 {
-				teamusers := make([]*user_model.User, 0, 20)
-				if err := db.GetEngine(ctx).
-					Join("INNER", "team_user", "team_user.uid = `user`.id").
-					In("`team_user`.team_id", checked).
-					And("`user`.is_active = ?", true).
-					And("`user`.prohibit_login = ?", false).
-					Find(&teamusers); err != nil {
-					return nil, fmt.Errorf("get teams users: %w", err)
-				}
-				if len(teamusers) > 0 {
-					users = make([]*user_model.User, 0, len(teamusers))
-					for _, user := range teamusers {
-						if already, ok := resolved[user.LowerName]; !ok || !already {
-							users = append(users, user)
-							resolved[user.LowerName] = true
-						}
+			if !allowed {
+				log.Debug("Mention of team %d in issue %d exceeds the mention rate limit of the team", team.ID, issue.ID)
+				continue
+			}
+			if team.MentionMode == organization.TeamMentionModeLeads {
+				leadsOnly = append(leadsOnly, team.ID)
+			} else {
+				allMembers = append(allMembers, team.ID)
+			}
+		}
+		if len(allMembers) != 0 || len(leadsOnly) != 0 {
+			teamusers := make([]*user_model.User, 0, 20)
+			if err := db.GetEngine(ctx).
+				Join("INNER", "team_user", "team_user.uid = `user`.id").
+				Where(builder.In("`team_user`.team_id", allMembers).
+					Or(builder.In("`team_user`.team_id", leadsOnly).And(builder.Eq{"`team_user`.is_lead": true}))).
+				And("`user`.is_active = ?", true).
+				And("`user`.prohibit_login = ?", false).
+				Find(&teamusers); err != nil {
+				return nil, fmt.Errorf("get teams users: %w", err)
+			}
+			if len(teamusers) > 0 {
+				users = make([]*user_model.User, 0, len(teamusers))
+				for _, user := range teamusers {
+					if already, ok := resolved[user.LowerName]; !ok || !already {
+						users = append(users, user)
+						resolved[user.LowerName] = true
 					}
 				}
 			}
@@ -765,7 +804,7 @@ func DeleteIssuesByRepoID(ctx context.Context, repoID int64) (attachmentPaths []
 	}
 
 	for j := range attachments {
-		attachmentPaths = append(attachmentPaths, attachments[j].RelativePath())
+		attachmentPaths = append(attachmentPaths, attachments[j].ObjectPaths()...)
 	}
 
 	if _, err = sess.In("issue_id", deleteCond).
@@ -32,6 +32,7 @@ type crossReferencesContext struct {
 func findOldCrossReferences(ctx context.Context, issueID, commentID int64) ([]*Comment, error) {
 	active := make([]*Comment, 0, 10)
 	return active, db.GetEngine(ctx).Where("`ref_action` IN (?, ?, ?)", references.XRefActionNone, references.XRefActionCloses, references.XRefActionReopens).
+		In("`type`", CommentTypeIssueRef, CommentTypeCommentRef, CommentTypePullRef).
 		And("`ref_issue_id` = ?", issueID).
 		And("`ref_comment_id` = ?", commentID).
 		Find(&active)
@@ -274,7 +275,7 @@ func (c *Comment) LoadRefIssue() (err error) {
 
 // CommentTypeIsRef returns true if CommentType is a reference from another issue
 func CommentTypeIsRef(t CommentType) bool {
-	return t == CommentTypeCommentRef || t == CommentTypePullRef || t == CommentTypeIssueRef
+	return t == CommentTypeCommentRef || t == CommentTypePullRef || t == CommentTypeIssueRef || t == CommentTypeCloseIssueRef
 }
 
 // RefCommentLink returns the relative URL for the comment that created this reference
@@ -308,13 +309,20 @@ func (c *Comment) RefIssueTitle() string {
 	return c.RefIssue.Title
 }
 
-// RefIssueIdent returns the user friendly identity (e.g. "#1234") of the issue where this reference was created
+// RefIssueIdent returns the user friendly identity (e.g. "#1234", or "owner/repo#1234" if it is in
+// another repository) of the issue where this reference was created
 func (c *Comment) RefIssueIdent() string {
 	if err := c.LoadRefIssue(); err != nil { // Silently dropping errors :unamused:
 		log.Error("LoadRefIssue(%d): %v", c.RefCommentID, err)
 		return ""
 	}
-	// FIXME: check this name for cross-repository references (#7901 if it gets merged)
+	if err := c.LoadIssue(db.DefaultContext); err != nil {
+		log.Error("LoadIssue(%d): %v", c.IssueID, err)
+		return ""
+	}
+	if c.RefIssue.RepoID != c.Issue.RepoID {
+		return fmt.Sprintf("%s#%d", c.RefIssue.Repo.FullName(), c.RefIssue.Index)
+	}
 	return fmt.Sprintf("#%d", c.RefIssue.Index)
 }
 
@@ -354,3 +362,23 @@ func (pr *PullRequest) ResolveCrossReferences(ctx context.Context) ([]*Comment,
 
 	return refs, nil
 }
+
+// CreateCloseIssueRefComment records in the pull request that its merge closed an issue in another repository
+func CreateCloseIssueRefComment(ctx context.Context, doer *user_model.User, pr *PullRequest, issue *Issue) (*Comment, error) {
+	if err := pr.LoadIssue(ctx); err != nil {
+		return nil, err
+	}
+	if err := pr.Issue.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	return CreateComment(ctx, &CreateCommentOptions{
+		Type:       CommentTypeCloseIssueRef,
+		Doer:       doer,
+		Repo:       pr.Issue.Repo,
+		Issue:      pr.Issue,
+		RefRepoID:  issue.RepoID,
+		RefIssueID: issue.ID,
+		RefAction:  references.XRefActionCloses,
+		RefIsPull:  issue.IsPull,
+	})
+}
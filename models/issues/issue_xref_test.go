@@ -126,6 +126,38 @@ func TestXRef_ResolveCrossReferences(t *testing.T) {
 	assert.Equal(t, r4.ID, refs[2].ID, "bad ref r4: %+v", refs[2])
 }
 
+func TestXRef_CloseIssueRef(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	d := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+
+	itarget := testCreateIssue(t, 3, 3, "title1", "content1", false)
+	pr := testCreatePR(t, 2, 1, "titlepr", fmt.Sprintf("closes user3/repo3#%d", itarget.Index))
+	assert.NoError(t, pr.Issue.AddCrossReferences(db.DefaultContext, d, false))
+
+	ref := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: itarget.ID, RefIssueID: pr.Issue.ID, RefCommentID: 0})
+	assert.Equal(t, references.XRefActionCloses, ref.RefAction)
+	assert.Equal(t, fmt.Sprintf("user2/repo2#%d", pr.Issue.Index), ref.RefIssueIdent())
+
+	c, err := issues_model.CreateCloseIssueRefComment(db.DefaultContext, d, pr, itarget)
+	assert.NoError(t, err)
+	assert.Equal(t, issues_model.CommentTypeCloseIssueRef, c.Type)
+	assert.Equal(t, fmt.Sprintf("user3/repo3#%d", itarget.Index), c.RefIssueIdent())
+
+	// the comment is not a reference which resolves when the pull request is merged
+	refs, err := pr.ResolveCrossReferences(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Len(t, refs, 1)
+	assert.Equal(t, ref.ID, refs[0].ID)
+
+	// nor is it neutered when the closed issue is edited
+	title := itarget.Title
+	itarget.Title = "title1, edited"
+	assert.NoError(t, issues_model.ChangeIssueTitle(db.DefaultContext, itarget, d, title))
+	c = unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{ID: c.ID})
+	assert.Equal(t, references.XRefActionCloses, c.RefAction)
+}
+
 func testCreateIssue(t *testing.T, repo, doer int64, title, content string, ispull bool) *issues_model.Issue {
 	r := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: repo})
 	d := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: doer})
@@ -0,0 +1,186 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ErrLegalHoldNotExist represents a "LegalHoldNotExist" kind of error.
+type ErrLegalHoldNotExist struct {
+	ID int64
+}
+
+// IsErrLegalHoldNotExist checks if an error is a ErrLegalHoldNotExist.
+func IsErrLegalHoldNotExist(err error) bool {
+	_, ok := err.(ErrLegalHoldNotExist)
+	return ok
+}
+
+func (err ErrLegalHoldNotExist) Error() string {
+	return fmt.Sprintf("legal hold does not exist [id: %d]", err.ID)
+}
+
+func (err ErrLegalHoldNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// ErrUnderLegalHold represents an "UnderLegalHold" kind of error, returned when content under a legal hold is to be removed.
+type ErrUnderLegalHold struct {
+	RepoID  int64
+	IssueID int64
+}
+
+// IsErrUnderLegalHold checks if an error is a ErrUnderLegalHold.
+func IsErrUnderLegalHold(err error) bool {
+	_, ok := err.(ErrUnderLegalHold)
+	return ok
+}
+
+func (err ErrUnderLegalHold) Error() string {
+	if err.IssueID == 0 {
+		return fmt.Sprintf("repository is under legal hold [repo_id: %d]", err.RepoID)
+	}
+	return fmt.Sprintf("issue is under legal hold [repo_id: %d, issue_id: %d]", err.RepoID, err.IssueID)
+}
+
+func (err ErrUnderLegalHold) Unwrap() error {
+	return util.ErrPermissionDenied
+}
+
+// LegalHold preserves a repository or an issue with its comments, attachments and content history for a legal investigation.
+// Content under a hold can't be deleted and the old revisions of edited content aren't purged until the hold is released.
+type LegalHold struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"INDEX NOT NULL"`
+	// IssueID is the held issue or pull request, 0 to hold the whole repository
+	IssueID     int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Reason      string             `xorm:"TEXT"`
+	CreatorID   int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+
+	Repo  *repo_model.Repository `xorm:"-"`
+	Issue *Issue                 `xorm:"-"`
+}
+
+func init() {
+	db.RegisterModel(new(LegalHold))
+}
+
+// LoadAttributes loads the held repository and issue
+func (h *LegalHold) LoadAttributes(ctx context.Context) (err error) {
+	if h.Repo == nil {
+		if h.Repo, err = repo_model.GetRepositoryByID(ctx, h.RepoID); err != nil {
+			return err
+		}
+	}
+	if h.Issue == nil && h.IssueID != 0 {
+		if h.Issue, err = GetIssueByID(ctx, h.IssueID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateLegalHold places a legal hold, a repository or an issue can only be held once
+func CreateLegalHold(ctx context.Context, h *LegalHold) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		has, err := db.GetEngine(ctx).Where("repo_id = ? AND issue_id = ?", h.RepoID, h.IssueID).Exist(new(LegalHold))
+		if err != nil {
+			return err
+		} else if has {
+			return util.NewAlreadyExistErrorf("a legal hold already exists")
+		}
+		return db.Insert(ctx, h)
+	})
+}
+
+// GetLegalHoldByID returns a legal hold
+func GetLegalHoldByID(ctx context.Context, id int64) (*LegalHold, error) {
+	h := &LegalHold{}
+	has, err := db.GetEngine(ctx).ID(id).Get(h)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrLegalHoldNotExist{ID: id}
+	}
+	return h, nil
+}
+
+// FindLegalHoldsOptions represents the options to search the legal holds
+type FindLegalHoldsOptions struct {
+	db.ListOptions
+	RepoID int64
+}
+
+// FindLegalHolds returns the legal holds, the latest first
+func FindLegalHolds(ctx context.Context, opts FindLegalHoldsOptions) ([]*LegalHold, int64, error) {
+	cond := builder.NewCond()
+	if opts.RepoID != 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	sess := db.GetEngine(ctx).Where(cond).Desc("id")
+	if opts.Page != 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+	holds := make([]*LegalHold, 0, 10)
+	count, err := sess.FindAndCount(&holds)
+	return holds, count, err
+}
+
+// DeleteLegalHold releases a legal hold
+func DeleteLegalHold(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Delete(new(LegalHold))
+	return err
+}
+
+// IsUnderLegalHold returns true if the issue of the repository is held, by a hold of the issue or of the repository.
+// With issueID 0 only holds of the whole repository are considered.
+func IsUnderLegalHold(ctx context.Context, repoID, issueID int64) (bool, error) {
+	return db.GetEngine(ctx).Where("repo_id = ?", repoID).And(builder.In("issue_id", 0, issueID)).Exist(new(LegalHold))
+}
+
+// IsRepositoryUnderLegalHold returns true if the repository or any of its issues is held
+func IsRepositoryUnderLegalHold(ctx context.Context, repoID int64) (bool, error) {
+	return db.GetEngine(ctx).Where("repo_id = ?", repoID).Exist(new(LegalHold))
+}
+
+// CheckLegalHold returns ErrUnderLegalHold if the issue of the repository is held, see IsUnderLegalHold
+func CheckLegalHold(ctx context.Context, repoID, issueID int64) error {
+	held, err := IsUnderLegalHold(ctx, repoID, issueID)
+	if err != nil {
+		return err
+	} else if held {
+		return ErrUnderLegalHold{RepoID: repoID, IssueID: issueID}
+	}
+	return nil
+}
+
+// CheckRepositoryLegalHold returns ErrUnderLegalHold if the repository or any of its issues is held
+func CheckRepositoryLegalHold(ctx context.Context, repoID int64) error {
+	held, err := IsRepositoryUnderLegalHold(ctx, repoID)
+	if err != nil {
+		return err
+	} else if held {
+		return ErrUnderLegalHold{RepoID: repoID}
+	}
+	return nil
+}
+
+// CheckAttachmentLegalHold returns ErrUnderLegalHold if the attachment belongs to a held issue or one of its comments.
+// Attachments of releases and attachments which aren't linked yet aren't held.
+func CheckAttachmentLegalHold(ctx context.Context, attach *repo_model.Attachment) error {
+	if attach.IssueID == 0 {
+		return nil
+	}
+	return CheckLegalHold(ctx, attach.RepoID, attach.IssueID)
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegalHold(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	other := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	assert.NoError(t, issues_model.CheckLegalHold(db.DefaultContext, issue.RepoID, issue.ID))
+	assert.NoError(t, issues_model.CheckRepositoryLegalHold(db.DefaultContext, issue.RepoID))
+
+	issueHold := &issues_model.LegalHold{RepoID: issue.RepoID, IssueID: issue.ID, Reason: "investigation", CreatorID: 1}
+	assert.NoError(t, issues_model.CreateLegalHold(db.DefaultContext, issueHold))
+	assert.ErrorIs(t, issues_model.CreateLegalHold(db.DefaultContext, &issues_model.LegalHold{RepoID: issue.RepoID, IssueID: issue.ID}), util.ErrAlreadyExist)
+
+	err := issues_model.CheckLegalHold(db.DefaultContext, issue.RepoID, issue.ID)
+	assert.True(t, issues_model.IsErrUnderLegalHold(err))
+	assert.ErrorIs(t, err, util.ErrPermissionDenied)
+	assert.NoError(t, issues_model.CheckLegalHold(db.DefaultContext, other.RepoID, other.ID))
+	// a held issue prevents the deletion of its repository
+	assert.True(t, issues_model.IsErrUnderLegalHold(issues_model.CheckRepositoryLegalHold(db.DefaultContext, issue.RepoID)))
+
+	// attachments of releases aren't held
+	assert.NoError(t, issues_model.CheckAttachmentLegalHold(db.DefaultContext, &repo_model.Attachment{RepoID: issue.RepoID, ReleaseID: 1}))
+	assert.Error(t, issues_model.CheckAttachmentLegalHold(db.DefaultContext, &repo_model.Attachment{RepoID: issue.RepoID, IssueID: issue.ID}))
+
+	repoHold := &issues_model.LegalHold{RepoID: issue.RepoID, Reason: "investigation", CreatorID: 1}
+	assert.NoError(t, issues_model.CreateLegalHold(db.DefaultContext, repoHold))
+	assert.True(t, issues_model.IsErrUnderLegalHold(issues_model.CheckLegalHold(db.DefaultContext, other.RepoID, other.ID)))
+
+	holds, count, err := issues_model.FindLegalHolds(db.DefaultContext, issues_model.FindLegalHoldsOptions{RepoID: issue.RepoID})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, holds, 2) {
+		assert.EqualValues(t, repoHold.ID, holds[0].ID)
+		assert.EqualValues(t, issueHold.ID, holds[1].ID)
+	}
+
+	assert.NoError(t, issues_model.DeleteLegalHold(db.DefaultContext, repoHold.ID))
+	assert.NoError(t, issues_model.DeleteLegalHold(db.DefaultContext, issueHold.ID))
+	assert.NoError(t, issues_model.CheckRepositoryLegalHold(db.DefaultContext, issue.RepoID))
+	_, err = issues_model.GetLegalHoldByID(db.DefaultContext, issueHold.ID)
+	assert.True(t, issues_model.IsErrLegalHoldNotExist(err))
+}
+
+func TestLegalHoldKeepsContentHistory(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+	now := timeutil.TimeStampNow()
+	for i, content := range []string{"a", "b", "c", "d"} {
+		assert.NoError(t, issues_model.SaveIssueContentHistory(db.DefaultContext, 1, issue.ID, 0, now.Add(int64(i)), content, i == 0))
+	}
+	assert.NoError(t, issues_model.CreateLegalHold(db.DefaultContext, &issues_model.LegalHold{RepoID: issue.RepoID, IssueID: issue.ID}))
+
+	issues_model.KeepLimitedContentHistory(db.DefaultContext, issue.ID, 0, 2)
+	count, err := db.GetEngine(db.DefaultContext).Where("issue_id = ?", issue.ID).Count(new(issues_model.ContentHistory))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 4, count)
+
+	histories := make([]*issues_model.ContentHistory, 0, 4)
+	assert.NoError(t, db.GetEngine(db.DefaultContext).Where("issue_id = ?", issue.ID).Asc("id").Find(&histories))
+	latest, err := issues_model.IsLatestContentHistory(db.DefaultContext, histories[3])
+	assert.NoError(t, err)
+	assert.True(t, latest)
+	latest, err = issues_model.IsLatestContentHistory(db.DefaultContext, histories[2])
+	assert.NoError(t, err)
+	assert.False(t, latest)
+}
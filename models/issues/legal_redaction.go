@@ -0,0 +1,65 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// LegalRedaction records the redaction of a content history revision or of an attachment of an issue or a comment.
+// The redacted content itself isn't kept, the record is the audit trail of the redaction.
+type LegalRedaction struct {
+	ID        int64 `xorm:"pk autoincr"`
+	RepoID    int64 `xorm:"INDEX NOT NULL"`
+	IssueID   int64 `xorm:"INDEX NOT NULL"`
+	CommentID int64 `xorm:"NOT NULL DEFAULT 0"`
+	// ContentHistoryID is the redacted revision, 0 if an attachment was redacted
+	ContentHistoryID int64 `xorm:"NOT NULL DEFAULT 0"`
+	// AttachmentID, AttachmentUUID and AttachmentName identify the redacted attachment, which is deleted
+	AttachmentID   int64  `xorm:"NOT NULL DEFAULT 0"`
+	AttachmentUUID string `xorm:"VARCHAR(40)"`
+	AttachmentName string
+	Reason         string             `xorm:"TEXT"`
+	DoerID         int64              `xorm:"INDEX NOT NULL"`
+	CreatedUnix    timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func init() {
+	db.RegisterModel(new(LegalRedaction))
+}
+
+// InsertLegalRedaction records a redaction
+func InsertLegalRedaction(ctx context.Context, r *LegalRedaction) error {
+	return db.Insert(ctx, r)
+}
+
+// FindLegalRedactionsOptions represents the options to search the redactions
+type FindLegalRedactionsOptions struct {
+	db.ListOptions
+	RepoID  int64
+	IssueID int64
+}
+
+// FindLegalRedactions returns the redactions, the latest first
+func FindLegalRedactions(ctx context.Context, opts FindLegalRedactionsOptions) ([]*LegalRedaction, int64, error) {
+	cond := builder.NewCond()
+	if opts.RepoID != 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	if opts.IssueID != 0 {
+		cond = cond.And(builder.Eq{"issue_id": opts.IssueID})
+	}
+	sess := db.GetEngine(ctx).Where(cond).Desc("id")
+	if opts.Page != 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+	redactions := make([]*LegalRedaction, 0, 10)
+	count, err := sess.FindAndCount(&redactions)
+	return redactions, count, err
+}
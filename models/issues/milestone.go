@@ -438,6 +438,17 @@ func GetMilestonesByRepoIDs(repoIDs []int64, page int, isClosed bool, sortType s
 	)
 }
 
+// GetMilestonesDueBetween returns the milestones of the repositories matching the condition,
+// open or closed, whose deadline is within the given time range, ordered by their deadline
+func GetMilestonesDueBetween(ctx context.Context, repoCond builder.Cond, since, until timeutil.TimeStamp) (MilestoneList, error) {
+	miles := make([]*Milestone, 0, 10)
+	return miles, db.GetEngine(ctx).
+		Where(builder.Gte{"deadline_unix": since}.And(builder.Lte{"deadline_unix": until})).
+		In("repo_id", builder.Select("id").From("repository").Where(repoCond)).
+		Asc("deadline_unix", "id").
+		Find(&miles)
+}
+
 // MilestonesStats represents milestone statistic information.
 type MilestonesStats struct {
 	OpenCount, ClosedCount int64
@@ -676,6 +676,14 @@ func GetAllUnmergedAgitPullRequestByPoster(ctx context.Context, uid int64) ([]*P
 	return pulls, err
 }
 
+// HasMergedPullRequestByPoster returns true if a pull request of the user was merged into the repository
+func HasMergedPullRequestByPoster(ctx context.Context, repoID, posterID int64) (bool, error) {
+	return db.GetEngine(ctx).
+		Join("INNER", "issue", "issue.id=pull_request.issue_id").
+		Where("pull_request.base_repo_id=? AND pull_request.has_merged=? AND issue.poster_id=?", repoID, true, posterID).
+		Exist(new(PullRequest))
+}
+
 // Update updates all fields of pull request.
 func (pr *PullRequest) Update() error {
 	_, err := db.GetEngine(db.DefaultContext).ID(pr.ID).AllCols().Update(pr)
@@ -818,7 +826,8 @@ func HasEnoughApprovals(ctx context.Context, protectBranch *git_model.ProtectedB
 	return GetGrantedApprovalsCount(ctx, protectBranch, pr) >= protectBranch.RequiredApprovals
 }
 
-// GetGrantedApprovalsCount returns the number of granted approvals for pr. A granted approval must be authored by a user in an approval whitelist.
+// GetGrantedApprovalsCount returns the number of granted approvals for pr. A granted approval must be authored by a user in an approval whitelist,
+// and tick the required items of the review checklist if the branch protection requires it.
 func GetGrantedApprovalsCount(ctx context.Context, protectBranch *git_model.ProtectedBranch, pr *PullRequest) int64 {
 	sess := db.GetEngine(ctx).Where("issue_id = ?", pr.IssueID).
 		And("type = ?", ReviewTypeApprove).
@@ -827,6 +836,16 @@ func GetGrantedApprovalsCount(ctx context.Context, protectBranch *git_model.Prot
 	if protectBranch.DismissStaleApprovals {
 		sess = sess.And("stale = ?", false)
 	}
+	if protectBranch.RequireReviewChecklist {
+		cond, err := reviewChecklistCompleteCond(ctx, protectBranch.RepoID)
+		if err != nil {
+			log.Error("GetGrantedApprovalsCount: %v", err)
+			return 0
+		}
+		if cond != nil {
+			sess = sess.And(cond)
+		}
+	}
 	approvals, err := sess.Count(new(Review))
 	if err != nil {
 		log.Error("GetGrantedApprovalsCount: %v", err)
@@ -116,6 +116,18 @@ func TestHasUnmergedPullRequestsByHeadInfo(t *testing.T) {
 	assert.False(t, exist)
 }
 
+func TestHasMergedPullRequestByPoster(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	merged, err := issues_model.HasMergedPullRequestByPoster(db.DefaultContext, 1, 1)
+	assert.NoError(t, err)
+	assert.True(t, merged)
+
+	merged, err = issues_model.HasMergedPullRequestByPoster(db.DefaultContext, 1, 5)
+	assert.NoError(t, err)
+	assert.False(t, merged)
+}
+
 func TestGetUnmergedPullRequestsByHeadInfo(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 	prs, err := issues_model.GetUnmergedPullRequestsByHeadInfo(1, "branch2")
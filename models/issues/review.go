@@ -380,8 +380,9 @@ func IsContentEmptyErr(err error) bool {
 	return ok
 }
 
-// SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist
-func SubmitReview(doer *user_model.User, issue *Issue, reviewType ReviewType, content, commitID string, stale bool, attachmentUUIDs []string) (*Review, *Comment, error) {
+// SubmitReview creates a review out of the existing pending review or creates a new one if no pending review exist.
+// checklistItemIDs are the items of the review checklist of the repository ticked by the review.
+func SubmitReview(doer *user_model.User, issue *Issue, reviewType ReviewType, content, commitID string, stale bool, attachmentUUIDs []string, checklistItemIDs []int64) (*Review, *Comment, error) {
 	ctx, committer, err := db.TxContext(db.DefaultContext)
 	if err != nil {
 		return nil, nil, err
@@ -453,6 +454,10 @@ func SubmitReview(doer *user_model.User, issue *Issue, reviewType ReviewType, co
 		}
 	}
 
+	if err := setReviewChecklistChecks(ctx, issue.RepoID, review.ID, checklistItemIDs); err != nil {
+		return nil, nil, err
+	}
+
 	comm, err := CreateComment(ctx, &CreateCommentOptions{
 		Type:        CommentTypeReview,
 		Doer:        doer,
@@ -0,0 +1,157 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ReviewChecklistItem is an item of the checklist of a repository which reviewers tick when reviewing a pull request
+type ReviewChecklistItem struct {
+	ID          int64  `xorm:"pk autoincr"`
+	RepoID      int64  `xorm:"INDEX NOT NULL"`
+	Name        string `xorm:"NOT NULL"`
+	Description string `xorm:"TEXT"`
+	// Required items must be ticked for an approval to count if the branch protection requires the review checklist
+	Required    bool               `xorm:"NOT NULL DEFAULT false"`
+	Sort        int                `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// ReviewChecklistCheck records that an item of the review checklist was ticked by a review
+type ReviewChecklistCheck struct {
+	ID          int64              `xorm:"pk autoincr"`
+	ReviewID    int64              `xorm:"UNIQUE(s) NOT NULL"`
+	ItemID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(ReviewChecklistItem))
+	db.RegisterModel(new(ReviewChecklistCheck))
+}
+
+// ErrReviewChecklistItemNotExist represents a "ReviewChecklistItemNotExist" kind of error.
+type ErrReviewChecklistItemNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrReviewChecklistItemNotExist checks if an error is a ErrReviewChecklistItemNotExist.
+func IsErrReviewChecklistItemNotExist(err error) bool {
+	_, ok := err.(ErrReviewChecklistItemNotExist)
+	return ok
+}
+
+func (err ErrReviewChecklistItemNotExist) Error() string {
+	return fmt.Sprintf("review checklist item does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+func (err ErrReviewChecklistItemNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// GetReviewChecklist returns the items of the review checklist of a repository
+func GetReviewChecklist(ctx context.Context, repoID int64) ([]*ReviewChecklistItem, error) {
+	items := make([]*ReviewChecklistItem, 0, 5)
+	return items, db.GetEngine(ctx).Where("repo_id = ?", repoID).OrderBy("sort, id").Find(&items)
+}
+
+// GetReviewChecklistItem returns an item of the review checklist of a repository
+func GetReviewChecklistItem(ctx context.Context, repoID, id int64) (*ReviewChecklistItem, error) {
+	item := &ReviewChecklistItem{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Get(item)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrReviewChecklistItemNotExist{ID: id, RepoID: repoID}
+	}
+	return item, nil
+}
+
+// InsertReviewChecklistItem adds an item to the review checklist of a repository
+func InsertReviewChecklistItem(ctx context.Context, item *ReviewChecklistItem) error {
+	return db.Insert(ctx, item)
+}
+
+// UpdateReviewChecklistItem updates an item of the review checklist
+func UpdateReviewChecklistItem(ctx context.Context, item *ReviewChecklistItem) error {
+	_, err := db.GetEngine(ctx).ID(item.ID).Cols("name", "description", "required", "sort").Update(item)
+	return err
+}
+
+// DeleteReviewChecklistItem removes an item from the review checklist of a repository with its checks
+func DeleteReviewChecklistItem(ctx context.Context, repoID, id int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		n, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Delete(new(ReviewChecklistItem))
+		if err != nil {
+			return err
+		} else if n == 0 {
+			return ErrReviewChecklistItemNotExist{ID: id, RepoID: repoID}
+		}
+		_, err = db.GetEngine(ctx).Where("item_id = ?", id).Delete(new(ReviewChecklistCheck))
+		return err
+	})
+}
+
+// GetReviewChecklistCheckedItemIDs returns the IDs of the checklist items ticked by a review
+func GetReviewChecklistCheckedItemIDs(ctx context.Context, reviewID int64) ([]int64, error) {
+	ids := make([]int64, 0, 5)
+	return ids, db.GetEngine(ctx).Table("review_checklist_check").Where("review_id = ?", reviewID).Cols("item_id").Find(&ids)
+}
+
+// setReviewChecklistChecks replaces the checklist items ticked by a review,
+// items which are not in the checklist of the repository are rejected
+func setReviewChecklistChecks(ctx context.Context, repoID, reviewID int64, itemIDs []int64) error {
+	if _, err := db.GetEngine(ctx).Where("review_id = ?", reviewID).Delete(new(ReviewChecklistCheck)); err != nil {
+		return err
+	}
+	if len(itemIDs) == 0 {
+		return nil
+	}
+
+	itemIDs = container.SetOf(itemIDs...).Values()
+	count, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).In("id", itemIDs).Count(new(ReviewChecklistItem))
+	if err != nil {
+		return err
+	}
+	if count != int64(len(itemIDs)) {
+		return util.NewInvalidArgumentErrorf("unknown review checklist items")
+	}
+
+	checks := make([]*ReviewChecklistCheck, 0, len(itemIDs))
+	for _, id := range itemIDs {
+		checks = append(checks, &ReviewChecklistCheck{ReviewID: reviewID, ItemID: id})
+	}
+	return db.Insert(ctx, checks)
+}
+
+// reviewChecklistCompleteCond returns the condition matching the reviews which ticked every required item
+// of the review checklist of a repository, or nil if it has no required items
+func reviewChecklistCompleteCond(ctx context.Context, repoID int64) (builder.Cond, error) {
+	requiredIDs := make([]int64, 0, 5)
+	if err := db.GetEngine(ctx).Table("review_checklist_item").
+		Where("repo_id = ? AND required = ?", repoID, true).
+		Cols("id").
+		Find(&requiredIDs); err != nil {
+		return nil, err
+	}
+	if len(requiredIDs) == 0 {
+		return nil, nil
+	}
+	return builder.In("id", builder.Select("review_id").
+		From("review_checklist_check").
+		Where(builder.In("item_id", requiredIDs)).
+		GroupBy("review_id").
+		Having(fmt.Sprintf("COUNT(*) = %d", len(requiredIDs)))), nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReviewChecklistItems(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	docs := &issues_model.ReviewChecklistItem{RepoID: 1, Name: "Docs updated", Sort: 2}
+	security := &issues_model.ReviewChecklistItem{RepoID: 1, Name: "Security review done", Required: true, Sort: 1}
+	assert.NoError(t, issues_model.InsertReviewChecklistItem(db.DefaultContext, docs))
+	assert.NoError(t, issues_model.InsertReviewChecklistItem(db.DefaultContext, security))
+
+	items, err := issues_model.GetReviewChecklist(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, security.ID, items[0].ID)
+		assert.Equal(t, docs.ID, items[1].ID)
+	}
+
+	docs.Description = "The documentation describes the change"
+	assert.NoError(t, issues_model.UpdateReviewChecklistItem(db.DefaultContext, docs))
+	item, err := issues_model.GetReviewChecklistItem(db.DefaultContext, 1, docs.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, docs.Description, item.Description)
+
+	_, err = issues_model.GetReviewChecklistItem(db.DefaultContext, 2, docs.ID)
+	assert.True(t, issues_model.IsErrReviewChecklistItemNotExist(err))
+	assert.True(t, issues_model.IsErrReviewChecklistItemNotExist(issues_model.DeleteReviewChecklistItem(db.DefaultContext, 2, docs.ID)))
+
+	assert.NoError(t, issues_model.DeleteReviewChecklistItem(db.DefaultContext, 1, docs.ID))
+	unittest.AssertNotExistsBean(t, &issues_model.ReviewChecklistItem{ID: docs.ID})
+}
+
+func TestSubmitReviewUnknownChecklistItem(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 2})
+	_, _, err := issues_model.SubmitReview(doer, issue, issues_model.ReviewTypeComment, "Review", "", false, nil, []int64{1000})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
+
+func TestGetGrantedApprovalsCountReviewChecklist(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	_, err := db.GetEngine(db.DefaultContext).ID(1).Cols("official").Update(&issues_model.Review{Official: true})
+	assert.NoError(t, err)
+	pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: 1})
+	protectBranch := &git_model.ProtectedBranch{RepoID: 1, RuleName: "master", RequireReviewChecklist: true}
+
+	// without required items every approval counts
+	assert.EqualValues(t, 1, issues_model.GetGrantedApprovalsCount(db.DefaultContext, protectBranch, pr))
+
+	item := &issues_model.ReviewChecklistItem{RepoID: 1, Name: "Security review done", Required: true}
+	assert.NoError(t, issues_model.InsertReviewChecklistItem(db.DefaultContext, item))
+	assert.EqualValues(t, 0, issues_model.GetGrantedApprovalsCount(db.DefaultContext, protectBranch, pr))
+
+	assert.NoError(t, db.Insert(db.DefaultContext, &issues_model.ReviewChecklistCheck{ReviewID: 1, ItemID: item.ID}))
+	ids, err := issues_model.GetReviewChecklistCheckedItemIDs(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{item.ID}, ids)
+	assert.EqualValues(t, 1, issues_model.GetGrantedApprovalsCount(db.DefaultContext, protectBranch, pr))
+
+	assert.NoError(t, issues_model.DeleteReviewChecklistItem(db.DefaultContext, 1, item.ID))
+	unittest.AssertNotExistsBean(t, &issues_model.ReviewChecklistCheck{ReviewID: 1})
+}
@@ -0,0 +1,107 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// StalePolicy configures how inactive issues and pull requests of a repository are labeled, commented on and closed
+type StalePolicy struct {
+	ID      int64 `xorm:"pk autoincr"`
+	RepoID  int64 `xorm:"UNIQUE NOT NULL"`
+	Enabled bool  `xorm:"INDEX NOT NULL DEFAULT true"`
+
+	ApplyToIssues bool `xorm:"NOT NULL DEFAULT true"`
+	ApplyToPulls  bool `xorm:"NOT NULL DEFAULT true"`
+	// DaysUntilStale is the number of days without activity after which an issue is marked stale
+	DaysUntilStale int `xorm:"NOT NULL DEFAULT 60"`
+	// DaysUntilClose is the number of days after being marked stale without activity after which an issue is closed, 0 never closes
+	DaysUntilClose int    `xorm:"NOT NULL DEFAULT 7"`
+	StaleLabelID   int64  `xorm:"NOT NULL"`
+	StaleComment   string `xorm:"TEXT"`
+	CloseComment   string `xorm:"TEXT"`
+
+	ExemptLabelIDs     []int64 `xorm:"JSON TEXT"`
+	ExemptMilestoneIDs []int64 `xorm:"JSON TEXT"`
+	// ExemptAllMilestones exempts every issue which has a milestone
+	ExemptAllMilestones bool `xorm:"NOT NULL DEFAULT false"`
+
+	LastRunUnix timeutil.TimeStamp
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(StalePolicy))
+}
+
+// IsExempt checks if the issue is excluded from the policy by its type, labels or milestone.
+// The labels of the issue must be loaded.
+func (p *StalePolicy) IsExempt(issue *Issue) bool {
+	if (issue.IsPull && !p.ApplyToPulls) || (!issue.IsPull && !p.ApplyToIssues) {
+		return true
+	}
+	if issue.MilestoneID > 0 && (p.ExemptAllMilestones || util.SliceContains(p.ExemptMilestoneIDs, issue.MilestoneID)) {
+		return true
+	}
+	for _, label := range issue.Labels {
+		if util.SliceContains(p.ExemptLabelIDs, label.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+// GetStalePolicy returns the stale policy of a repository, or nil if it has none
+func GetStalePolicy(ctx context.Context, repoID int64) (*StalePolicy, error) {
+	p := &StalePolicy{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// SaveStalePolicy creates or updates the stale policy of a repository
+func SaveStalePolicy(ctx context.Context, p *StalePolicy) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetStalePolicy(ctx, p.RepoID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return db.Insert(ctx, p)
+		}
+		p.ID = existing.ID
+		p.LastRunUnix = existing.LastRunUnix
+		_, err = db.GetEngine(ctx).ID(p.ID).AllCols().Update(p)
+		return err
+	})
+}
+
+// DeleteStalePolicy removes the stale policy of a repository
+func DeleteStalePolicy(ctx context.Context, repoID int64) error {
+	_, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(new(StalePolicy))
+	return err
+}
+
+// FindEnabledStalePolicies returns all enabled stale policies
+func FindEnabledStalePolicies(ctx context.Context) ([]*StalePolicy, error) {
+	policies := make([]*StalePolicy, 0, 10)
+	return policies, db.GetEngine(ctx).Where("enabled = ?", true).OrderBy("id").Find(&policies)
+}
+
+// UpdateStalePolicyLastRun records when the policy was last executed
+func UpdateStalePolicyLastRun(ctx context.Context, p *StalePolicy) error {
+	p.LastRunUnix = timeutil.TimeStampNow()
+	_, err := db.GetEngine(ctx).ID(p.ID).Cols("last_run_unix").NoAutoTime().Update(p)
+	return err
+}
@@ -0,0 +1,69 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStalePolicyIsExempt(t *testing.T) {
+	policy := &issues_model.StalePolicy{
+		ApplyToIssues:      true,
+		ExemptLabelIDs:     []int64{3},
+		ExemptMilestoneIDs: []int64{5},
+	}
+
+	assert.False(t, policy.IsExempt(&issues_model.Issue{}))
+	assert.True(t, policy.IsExempt(&issues_model.Issue{IsPull: true}))
+	assert.True(t, policy.IsExempt(&issues_model.Issue{Labels: []*issues_model.Label{{ID: 1}, {ID: 3}}}))
+	assert.False(t, policy.IsExempt(&issues_model.Issue{Labels: []*issues_model.Label{{ID: 1}}}))
+	assert.True(t, policy.IsExempt(&issues_model.Issue{MilestoneID: 5}))
+	assert.False(t, policy.IsExempt(&issues_model.Issue{MilestoneID: 6}))
+
+	policy.ExemptAllMilestones = true
+	assert.True(t, policy.IsExempt(&issues_model.Issue{MilestoneID: 6}))
+}
+
+func TestSaveStalePolicy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	policy, err := issues_model.GetStalePolicy(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Nil(t, policy)
+
+	assert.NoError(t, issues_model.SaveStalePolicy(db.DefaultContext, &issues_model.StalePolicy{
+		RepoID:         1,
+		Enabled:        true,
+		ApplyToIssues:  true,
+		DaysUntilStale: 30,
+		StaleLabelID:   1,
+	}))
+	assert.NoError(t, issues_model.SaveStalePolicy(db.DefaultContext, &issues_model.StalePolicy{
+		RepoID:         1,
+		Enabled:        true,
+		ApplyToIssues:  true,
+		DaysUntilStale: 90,
+		StaleLabelID:   2,
+	}))
+
+	policy, err = issues_model.GetStalePolicy(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	if assert.NotNil(t, policy) {
+		assert.EqualValues(t, 90, policy.DaysUntilStale)
+		assert.EqualValues(t, 2, policy.StaleLabelID)
+	}
+
+	policies, err := issues_model.FindEnabledStalePolicies(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Len(t, policies, 1)
+
+	assert.NoError(t, issues_model.DeleteStalePolicy(db.DefaultContext, 1))
+	unittest.AssertNotExistsBean(t, &issues_model.StalePolicy{RepoID: 1})
+}
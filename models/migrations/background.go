@@ -0,0 +1,128 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// Backfill is a background migration which fills new tables or columns of an expand migration while Gitea is running.
+// The rows of the table are processed in batches ordered by ID, so the backfill can be throttled, interrupted and resumed.
+// A batch may be processed again after a crash or by another instance, so it must be idempotent.
+type Backfill struct {
+	Name        string
+	Description string
+	Table       string
+	// Batch processes the rows with fromID <= id <= toID, it runs in a transaction
+	Batch func(ctx context.Context, fromID, toID int64) error
+}
+
+// This is the list of background migrations. Add new backfills to the bottom of the list,
+// a contract migration of a later release can require them to have finished.
+var backfills = []*Backfill{}
+
+// GetBackfill returns the registered background migration with the name
+func GetBackfill(name string) *Backfill {
+	for _, b := range backfills {
+		if b.Name == name {
+			return b
+		}
+	}
+	return nil
+}
+
+// RunBackgroundMigrations runs the unfinished background migrations one after another until they have finished or ctx is done
+func RunBackgroundMigrations(ctx context.Context) {
+	for _, b := range backfills {
+		if err := runBackfill(ctx, b); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			log.Error("Background migration %s failed: %v", b.Name, err)
+		}
+	}
+}
+
+func runBackfill(ctx context.Context, b *Backfill) error {
+	bm, err := system_model.GetOrCreateBackgroundMigration(ctx, b.Name)
+	if err != nil {
+		return err
+	}
+	if bm.IsFinished {
+		return nil
+	}
+
+	if err := processBackfill(ctx, b, bm); err != nil {
+		if ctx.Err() == nil {
+			if err := system_model.SetBackgroundMigrationError(ctx, bm, err.Error()); err != nil {
+				log.Error("Unable to store the error of background migration %s: %v", b.Name, err)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+func processBackfill(ctx context.Context, b *Backfill, bm *system_model.BackgroundMigration) error {
+	if bm.LastID == 0 {
+		total, err := db.GetEngine(ctx).Table(b.Table).Count()
+		if err != nil {
+			return err
+		}
+		if err := system_model.SetBackgroundMigrationTotal(ctx, bm, total); err != nil {
+			return err
+		}
+		log.Info("Background migration %s started: %s", b.Name, b.Description)
+	}
+
+	batchSize := setting.Database.BackgroundMigrationBatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	for {
+		ids := make([]int64, 0, batchSize)
+		if err := db.GetEngine(ctx).Table(b.Table).Cols("id").Where("id > ?", bm.LastID).OrderBy("id").Limit(batchSize).Find(&ids); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			log.Info("Background migration %s finished, %d rows processed", b.Name, bm.Processed)
+			return system_model.FinishBackgroundMigration(ctx, bm)
+		}
+
+		fromID, toID := ids[0], ids[len(ids)-1]
+		if err := db.WithTx(ctx, func(ctx context.Context) error {
+			return b.Batch(ctx, fromID, toID)
+		}); err != nil {
+			return fmt.Errorf("batch %d-%d: %w", fromID, toID, err)
+		}
+
+		advanced, err := system_model.AdvanceBackgroundMigration(ctx, bm, toID, int64(len(ids)))
+		if err != nil {
+			return err
+		}
+		if !advanced {
+			// another instance processes the same background migration
+			if err := system_model.ReloadBackgroundMigration(ctx, bm); err != nil {
+				return err
+			}
+			if bm.IsFinished {
+				return nil
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(setting.Database.BackgroundMigrationBatchDelay):
+		}
+	}
+}
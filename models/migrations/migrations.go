@@ -20,6 +20,7 @@ import (
 	"code.gitea.io/gitea/models/migrations/v1_18"
 	"code.gitea.io/gitea/models/migrations/v1_19"
 	"code.gitea.io/gitea/models/migrations/v1_20"
+	"code.gitea.io/gitea/models/migrations/v1_21"
 	"code.gitea.io/gitea/models/migrations/v1_6"
 	"code.gitea.io/gitea/models/migrations/v1_7"
 	"code.gitea.io/gitea/models/migrations/v1_8"
@@ -41,13 +42,27 @@ type Migration interface {
 }
 
 type migration struct {
-	description string
-	migrate     func(*xorm.Engine) error
+	description       string
+	migrate           func(*xorm.Engine) error
+	expand            bool
+	requiredBackfills []string
 }
 
-// NewMigration creates a new migration
+// NewMigration creates a new migration. Older Gitea releases can't run with the migrated database.
 func NewMigration(desc string, fn func(*xorm.Engine) error) Migration {
-	return &migration{desc, fn}
+	return &migration{description: desc, migrate: fn}
+}
+
+// NewExpandMigration creates a new migration which only adds tables, columns or indexes the older Gitea releases don't use,
+// so they can keep running with the migrated database during a rolling upgrade
+func NewExpandMigration(desc string, fn func(*xorm.Engine) error) Migration {
+	return &migration{description: desc, migrate: fn, expand: true}
+}
+
+// NewContractMigration creates a new migration which removes data of a previous expand migration.
+// It only runs after the background migrations with the given names have finished.
+func NewContractMigration(desc string, fn func(*xorm.Engine) error, requiredBackfills ...string) Migration {
+	return &migration{description: desc, migrate: fn, requiredBackfills: requiredBackfills}
 }
 
 // Description returns the migration's description
@@ -64,6 +79,30 @@ func (m *migration) Migrate(x *xorm.Engine) error {
 type Version struct {
 	ID      int64 `xorm:"pk autoincr"`
 	Version int64
+	// CompatibleVersion is the lowest version a Gitea release must expect to run with the database,
+	// it is lower than Version if only expand migrations ran since then. 0 if unknown.
+	CompatibleVersion int64
+}
+
+func isExpandMigration(m Migration) bool {
+	mig, ok := m.(*migration)
+	return ok && mig.expand
+}
+
+// compatibleVersion returns the compatible version after running the migrations up to the version
+func compatibleVersion(migrations []Migration, version int64) int64 {
+	compatible := int64(minDBVersion)
+	for i, m := range migrations[:version-minDBVersion] {
+		if !isExpandMigration(m) {
+			compatible = minDBVersion + int64(i) + 1
+		}
+	}
+	return compatible
+}
+
+// isSchemaCompatible returns true if the database is newer than expected, but only expand migrations ran since the expected version
+func isSchemaCompatible(v *Version, expected int64) bool {
+	return v.Version > expected && v.CompatibleVersion > 0 && v.CompatibleVersion <= expected
 }
 
 // Use noopMigration when there is a migration that has been no-oped
@@ -493,23 +532,183 @@ var migrations = []Migration{
 	NewMigration("Add is_internal column to package", v1_20.AddIsInternalColumnToPackage),
 	// v257 -> v258
 	NewMigration("Add Actions Artifact table", v1_20.CreateActionArtifactTable),
+
+	// Gitea 1.20.0 ends at v258
+
+	// v258 -> v259
+	NewMigration("Add repository license and organization license policy tables", v1_21.AddLicenseDetectionAndPolicyTables),
+	// v259 -> v260
+	NewMigration("Add code scanning tables and block_on_code_scanning_alerts to protected_branch", v1_21.AddCodeScanningTables),
+	// v260 -> v261
+	NewMigration("Add repo_maintenance table", v1_21.AddRepoMaintenanceTable),
+	// v261 -> v262
+	NewMigration("Add chatops command and audit tables", v1_21.AddChatOpsTables),
+	// v262 -> v263
+	NewMigration("Add stale policy table", v1_21.AddStalePolicyTable),
+	// v263 -> v264
+	NewMigration("Add user blocking, abuse report and interaction limit tables", v1_21.AddModerationTables),
+	// v264 -> v265
+	NewMigration("Add held content table for spam review", v1_21.AddHeldContentTable),
+	// v265 -> v266
+	NewMigration("Add mail delivery table", v1_21.AddMailDeliveryTable),
+	// v266 -> v267
+	NewMigration("Add organization avatar policy table", v1_21.AddAvatarPolicyTable),
+	// v267 -> v268
+	NewMigration("Add is_internal column to repository table", v1_21.AddIsInternalToRepository),
+	// v268 -> v269
+	NewMigration("Add fork sync table", v1_21.AddForkSyncTable),
+	// v269 -> v270
+	NewMigration("Add fork divergence table", v1_21.AddForkDivergenceTable),
+	// v270 -> v271
+	NewMigration("Add managed hook table", v1_21.AddManagedHookTable),
+	// v271 -> v272
+	NewMigration("Add storage_region column to user table", v1_21.AddStorageRegionToUser),
+	// v272 -> v273
+	NewMigration("Add pull request metric table", v1_21.AddPullRequestMetricTable),
+	// v273 -> v274
+	NewMigration("Add expired_unix column to action_artifact table", v1_21.AddExpiredUnixColumnInActionArtifactTable),
+	// v274 -> v275
+	NewMigration("Create action_workflow_subscription table", v1_21.CreateActionWorkflowSubscriptionTable),
+	// v275 -> v276
+	NewMigration("Add pull_merge_queue and pull_merge_train tables", v1_21.AddMergeQueueTables),
+	// v276 -> v277
+	NewMigration("Add reply_to_id and thread_collapsed columns to comment table", v1_21.AddCommentThreadColumns),
+	// v277 -> v278
+	NewMigration("Create discussion tables", v1_21.CreateDiscussionTables),
+	// v278 -> v279
+	NewMigration("Create user_status table", v1_21.CreateUserStatusTable),
+	// v279 -> v280
+	NewMigration("Create team_reminder table", v1_21.CreateTeamReminderTable),
+	// v280 -> v281
+	NewMigration("Add attachment deduplication columns and attachment_policy table", v1_21.AddAttachmentDeduplicationAndPolicy),
+	// v281 -> v282
+	NewMigration("Add code_paths column to team table", v1_21.AddCodePathsToTeam),
+	// v282 -> v283
+	NewMigration("Add count_private_activity column to user table and linked_identity table", v1_21.AddCountPrivateActivityAndLinkedIdentity),
+	// v283 -> v284
+	NewMigration("Add storage_usage table", v1_21.AddStorageUsageTable),
+	// v284 -> v285
+	NewMigration("Add archive_policy and archive_notice tables", v1_21.AddArchivePolicyAndArchiveNoticeTables),
+	// v285 -> v286
+	NewMigration("Add team mention settings and team_mention table", v1_21.AddTeamMentionSettings),
+	// v286 -> v287
+	NewMigration("Add deprecation to package_version table", v1_21.AddPackageVersionDeprecation),
+	// v287 -> v288
+	NewExpandMigration("Add background_migration table", v1_21.AddBackgroundMigrationTable),
+	// v288 -> v289
+	NewExpandMigration("Add expires_unix column to collaboration table", v1_21.AddExpiresUnixToCollaboration),
+	// v289 -> v290
+	NewExpandMigration("Add restrictions to deploy_key table and add deploy_key_usage table", v1_21.AddDeployKeyRestrictionsAndUsage),
+	// v290 -> v291
+	NewExpandMigration("Add object_format_name column to repository table", v1_21.AddObjectFormatNameToRepository),
+	// v291 -> v292
+	NewExpandMigration("Add flaky_check_rule and commit_status_attempt tables", v1_21.AddFlakyCheckRuleAndCommitStatusAttemptTables),
+	// v292 -> v293
+	NewExpandMigration("Add WIP limits to project_board table and add project_view_setting table", v1_21.AddWipLimitToProjectBoardAndProjectViewSettingTable),
+	// v293 -> v294
+	NewExpandMigration("Create team_homepage and team_pinned_repo tables", v1_21.CreateTeamHomepageAndTeamPinnedRepoTable),
+	// v294 -> v295
+	NewExpandMigration("Create mail_digest_item table", v1_21.CreateMailDigestItemTable),
+	// v295 -> v296
+	NewExpandMigration("Create attachment_blob table and add blob_id to attachment", v1_21.CreateAttachmentBlobTable),
+	// v296 -> v297
+	NewExpandMigration("Add client certificate, allowed IPs and previous secret to webhook", v1_21.AddWebhookSecurityColumns),
+	// v297 -> v298
+	NewExpandMigration("Create vendor_sync table", v1_21.CreateVendorSyncTable),
+	// v298 -> v299
+	NewExpandMigration("Add review checklist tables and require_review_checklist to protected_branch", v1_21.AddReviewChecklistTables),
+	// v299 -> v300
+	NewExpandMigration("Create package_advisory table", v1_21.CreatePackageAdvisoryTable),
+	// v300 -> v301
+	NewExpandMigration("Create download_link table", v1_21.CreateDownloadLinkTable),
+	// v301 -> v302
+	NewExpandMigration("Create path_contribution and path_ownership_status tables", v1_21.CreatePathOwnershipTables),
+	// v302 -> v303
+	NewExpandMigration("Create theme and branding tables", v1_21.CreateThemeAndBrandingTables),
+	// v303 -> v304
+	NewExpandMigration("Create translation_override table", v1_21.CreateTranslationOverrideTable),
+	// v304 -> v305
+	NewExpandMigration("Add scopes and token lifetimes to OAuth2 applications", v1_21.AddOAuth2ScopesAndTokenLifetimes),
+	// v305 -> v306
+	NewExpandMigration("Create login_device table", v1_21.CreateLoginDeviceTable),
+	// v306 -> v307
+	NewExpandMigration("Add created_unix to user_redirect", v1_21.AddCreatedUnixToUserRedirect),
+	// v307 -> v308
+	NewExpandMigration("Create edit_session table", v1_21.CreateEditSessionTable),
+	// v308 -> v309
+	NewExpandMigration("Add ephemeral to action_runner", v1_21.AddEphemeralToActionRunner),
+	// v309 -> v310
+	NewExpandMigration("Add team group sync", v1_21.AddTeamGroupSync),
+	// v310 -> v311
+	NewExpandMigration("Add lock levels and lock policies of issues", v1_21.AddIssueLockLevelsAndPolicy),
+	// v311 -> v312
+	NewExpandMigration("Add template catalog item table", v1_21.AddTemplateCatalogItemTable),
+	// v312 -> v313
+	NewExpandMigration("Add sync hook secret to mirror", v1_21.AddSyncHookSecretToMirror),
+	// v313 -> v314
+	NewExpandMigration("Add coverage report and badge token tables", v1_21.AddCoverageReportAndBadgeTokenTables),
+	// v314 -> v315
+	NewExpandMigration("Add coverage report files and min_diff_coverage to protected_branch", v1_21.AddCoverageReportFileTableAndMinDiffCoverage),
+	// v315 -> v316
+	NewExpandMigration("Add embed token table", v1_21.AddEmbedTokenTable),
+	// v316 -> v317
+	NewExpandMigration("Add issue subscription rule table", v1_21.AddIssueSubscriptionRuleTable),
+	// v317 -> v318
+	NewExpandMigration("Add repository and package version deletion tables", v1_21.AddRepoAndPackageVersionDeletionTables),
+	// v318 -> v319
+	NewExpandMigration("Add required_approval to action_task", v1_21.AddRequiredApprovalToActionTask),
+	// v319 -> v320
+	NewExpandMigration("Add successor, rotation and expiry notification columns to gpg_key", v1_21.AddRotationAndExpiryMailToGPGKey),
+	// v320 -> v321
+	NewExpandMigration("Add legal_hold and legal_redaction tables", v1_21.AddLegalHoldAndRedactionTables),
+	// v321 -> v322
+	NewExpandMigration("Add forbidden to repo_license and widen its commit_id", v1_21.AddForbiddenToRepoLicenseAndWidenCommitID),
+	// v322 -> v323
+	NewExpandMigration("Widen commit_sha of code scanning analyses and alerts", v1_21.WidenCodeScanningCommitSHA),
+	// v323 -> v324
+	NewExpandMigration("Add review assignment settings to team table", v1_21.AddTeamReviewAssignment),
+	// v324 -> v325
+	NewExpandMigration("Add expiry, successor and rotation columns to public_key", v1_21.AddExpiryAndRotationToPublicKey),
+	// v325 -> v326
+	NewExpandMigration("Add dependency to repo_license", v1_21.AddDependencyToRepoLicense),
 }
 
 // GetCurrentDBVersion returns the current db version
 func GetCurrentDBVersion(x *xorm.Engine) (int64, error) {
+	currentVersion, err := getCurrentVersion(x)
+	if err != nil {
+		return -1, err
+	}
+	if currentVersion == nil {
+		return -1, nil
+	}
+	return currentVersion.Version, nil
+}
+
+func getCurrentVersion(x *xorm.Engine) (*Version, error) {
 	if err := x.Sync(new(Version)); err != nil {
-		return -1, fmt.Errorf("sync: %w", err)
+		return nil, fmt.Errorf("sync: %w", err)
 	}
 
 	currentVersion := &Version{ID: 1}
 	has, err := x.Get(currentVersion)
 	if err != nil {
-		return -1, fmt.Errorf("get: %w", err)
+		return nil, fmt.Errorf("get: %w", err)
 	}
 	if !has {
-		return -1, nil
+		return nil, nil
 	}
-	return currentVersion.Version, nil
+	return currentVersion, nil
+}
+
+// IsSchemaCompatible returns true if the database is newer than expected by this release,
+// but only expand migrations ran since, so this release can still run with it
+func IsSchemaCompatible(x *xorm.Engine) (bool, error) {
+	currentVersion, err := getCurrentVersion(x)
+	if err != nil || currentVersion == nil {
+		return false, err
+	}
+	return isSchemaCompatible(currentVersion, ExpectedVersion()), nil
 }
 
 // ExpectedVersion returns the expected db version
@@ -519,14 +718,15 @@ func ExpectedVersion() int64 {
 
 // EnsureUpToDate will check if the db is at the correct version
 func EnsureUpToDate(x *xorm.Engine) error {
-	currentDB, err := GetCurrentDBVersion(x)
+	currentVersion, err := getCurrentVersion(x)
 	if err != nil {
 		return err
 	}
 
-	if currentDB < 0 {
+	if currentVersion == nil {
 		return fmt.Errorf("Database has not been initialized")
 	}
+	currentDB := currentVersion.Version
 
 	if minDBVersion > currentDB {
 		return fmt.Errorf("DB version %d (<= %d) is too old for auto-migration. Upgrade to Gitea 1.6.4 first then upgrade to this version", currentDB, minDBVersion)
@@ -534,6 +734,11 @@ func EnsureUpToDate(x *xorm.Engine) error {
 
 	expected := ExpectedVersion()
 
+	if isSchemaCompatible(currentVersion, expected) {
+		log.Warn("Database version %d is newer than the expected version %d, but compatible with this release", currentDB, expected)
+		return nil
+	}
+
 	if currentDB != expected {
 		return fmt.Errorf(`Current database version %d is not equal to the expected version %d. Please run "gitea [--config /path/to/app.ini] migrate" to update the database version`, currentDB, expected)
 	}
@@ -558,6 +763,7 @@ func Migrate(x *xorm.Engine) error {
 		// it is a fresh installation and we can skip all migrations.
 		currentVersion.ID = 0
 		currentVersion.Version = int64(minDBVersion + len(migrations))
+		currentVersion.CompatibleVersion = compatibleVersion(migrations, currentVersion.Version)
 
 		if _, err = x.InsertOne(currentVersion); err != nil {
 			return fmt.Errorf("insert: %w", err)
@@ -571,6 +777,12 @@ Please try upgrading to a lower version first (suggested v1.6.4), then upgrade t
 		return nil
 	}
 
+	// Older releases keep running with a database migrated by expand migrations only
+	if isSchemaCompatible(currentVersion, int64(minDBVersion+len(migrations))) {
+		log.Warn("Database version %d is newer than the expected version %d, but compatible with this release", v, minDBVersion+len(migrations))
+		return nil
+	}
+
 	// Downgrading Gitea's database version not supported
 	if int(v-minDBVersion) > len(migrations) {
 		msg := fmt.Sprintf("Your database (migration version: %d) is for a newer Gitea, you can not use the newer database for this old Gitea release (%d).", v, minDBVersion+len(migrations))
@@ -590,8 +802,16 @@ Please try upgrading to a lower version first (suggested v1.6.4), then upgrade t
 		}
 	}
 
+	// Databases migrated by older releases don't know their compatible version
+	if currentVersion.CompatibleVersion == 0 {
+		currentVersion.CompatibleVersion = v
+	}
+
 	// Migrate
 	for i, m := range migrations[v-minDBVersion:] {
+		if err = checkRequiredBackfills(x, m); err != nil {
+			return fmt.Errorf("migration[%d]: %s: %w", v+int64(i), m.Description(), err)
+		}
 		log.Info("Migration[%d]: %s", v+int64(i), m.Description())
 		// Reset the mapper between each migration - migrations are not supposed to depend on each other
 		x.SetMapper(names.GonicMapper{})
@@ -599,9 +819,35 @@ Please try upgrading to a lower version first (suggested v1.6.4), then upgrade t
 			return fmt.Errorf("migration[%d]: %s failed: %w", v+int64(i), m.Description(), err)
 		}
 		currentVersion.Version = v + int64(i) + 1
-		if _, err = x.ID(1).Update(currentVersion); err != nil {
+		if !isExpandMigration(m) {
+			currentVersion.CompatibleVersion = currentVersion.Version
+		}
+		if _, err = x.ID(1).Cols("version", "compatible_version").Update(currentVersion); err != nil {
 			return err
 		}
 	}
 	return nil
 }
+
+// checkRequiredBackfills returns an error if a background migration required by a contract migration hasn't finished
+func checkRequiredBackfills(x *xorm.Engine, m Migration) error {
+	mig, ok := m.(*migration)
+	if !ok || len(mig.requiredBackfills) == 0 {
+		return nil
+	}
+	exist, err := x.IsTableExist("background_migration")
+	if err != nil {
+		return err
+	}
+	var finished int64
+	if exist {
+		finished, err = x.Table("background_migration").In("name", mig.requiredBackfills).And("is_finished = ?", true).Count()
+		if err != nil {
+			return err
+		}
+	}
+	if finished != int64(len(mig.requiredBackfills)) {
+		return fmt.Errorf("the background migrations %v have not finished yet, run the previous Gitea release until they have finished", mig.requiredBackfills)
+	}
+	return nil
+}
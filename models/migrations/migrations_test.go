@@ -0,0 +1,36 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package migrations
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompatibleVersion(t *testing.T) {
+	ms := []Migration{
+		NewMigration("a", noopMigration),
+		NewExpandMigration("b", noopMigration),
+		NewMigration("c", noopMigration),
+		NewExpandMigration("d", noopMigration),
+		NewExpandMigration("e", noopMigration),
+	}
+
+	assert.EqualValues(t, minDBVersion, compatibleVersion(ms, minDBVersion))
+	assert.EqualValues(t, minDBVersion+1, compatibleVersion(ms, minDBVersion+1))
+	assert.EqualValues(t, minDBVersion+1, compatibleVersion(ms, minDBVersion+2))
+	assert.EqualValues(t, minDBVersion+3, compatibleVersion(ms, minDBVersion+3))
+	assert.EqualValues(t, minDBVersion+3, compatibleVersion(ms, minDBVersion+5))
+}
+
+func TestIsSchemaCompatible(t *testing.T) {
+	assert.True(t, isSchemaCompatible(&Version{Version: 105, CompatibleVersion: 103}, 104))
+	assert.True(t, isSchemaCompatible(&Version{Version: 105, CompatibleVersion: 104}, 104))
+	assert.False(t, isSchemaCompatible(&Version{Version: 105, CompatibleVersion: 105}, 104))
+	// databases migrated by older releases don't know their compatible version
+	assert.False(t, isSchemaCompatible(&Version{Version: 105}, 104))
+	// older databases have to be migrated
+	assert.False(t, isSchemaCompatible(&Version{Version: 103, CompatibleVersion: 103}, 104))
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddLicenseDetectionAndPolicyTables(x *xorm.Engine) error {
+	type RepoLicense struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CommitID    string             `xorm:"VARCHAR(40)"`
+		License     string             `xorm:"VARCHAR(100) UNIQUE(s) NOT NULL"`
+		Path        string             `xorm:"UNIQUE(s) NOT NULL"`
+		Confidence  float64            `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX CREATED"`
+	}
+
+	type LicensePolicy struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OrgID       int64              `xorm:"UNIQUE NOT NULL"`
+		Allowed     []string           `xorm:"JSON TEXT"`
+		Forbidden   []string           `xorm:"JSON TEXT"`
+		Action      string             `xorm:"VARCHAR(20) NOT NULL DEFAULT 'alert'"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(RepoLicense), new(LicensePolicy))
+}
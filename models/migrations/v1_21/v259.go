@@ -0,0 +1,62 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type codeScanningAlert struct {
+	ID              int64              `xorm:"pk autoincr"`
+	RepoID          int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Ref             string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+	Fingerprint     string             `xorm:"VARCHAR(64) UNIQUE(s) NOT NULL"`
+	Tool            string             `xorm:"VARCHAR(255) INDEX"`
+	RuleID          string             `xorm:"VARCHAR(255)"`
+	RuleDescription string             `xorm:"TEXT"`
+	Severity        string             `xorm:"VARCHAR(20)"`
+	Message         string             `xorm:"TEXT"`
+	Path            string             `xorm:"TEXT"`
+	StartLine       int                `xorm:"NOT NULL DEFAULT 0"`
+	EndLine         int                `xorm:"NOT NULL DEFAULT 0"`
+	CommitSHA       string             `xorm:"VARCHAR(40)"`
+	State           int                `xorm:"INDEX NOT NULL DEFAULT 1"`
+	DismissedByID   int64              `xorm:"NOT NULL DEFAULT 0"`
+	DismissedReason string             `xorm:"TEXT"`
+	FixedUnix       timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix     timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix     timeutil.TimeStamp `xorm:"updated"`
+}
+
+func (codeScanningAlert) TableName() string {
+	return "code_scanning_alert"
+}
+
+type codeScanningAnalysis struct {
+	ID           int64              `xorm:"pk autoincr"`
+	RepoID       int64              `xorm:"INDEX NOT NULL"`
+	Ref          string             `xorm:"VARCHAR(255) INDEX"`
+	CommitSHA    string             `xorm:"VARCHAR(40)"`
+	Tool         string             `xorm:"VARCHAR(255)"`
+	ResultsCount int                `xorm:"NOT NULL DEFAULT 0"`
+	UploaderID   int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+}
+
+func (codeScanningAnalysis) TableName() string {
+	return "code_scanning_analysis"
+}
+
+func AddCodeScanningTables(x *xorm.Engine) error {
+	type ProtectedBranch struct {
+		BlockOnCodeScanningAlerts bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	if err := x.Sync(new(ProtectedBranch)); err != nil {
+		return err
+	}
+	return x.Sync(new(codeScanningAlert), new(codeScanningAnalysis))
+}
@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddRepoMaintenanceTable(x *xorm.Engine) error {
+	type RepoMaintenance struct {
+		ID              int64              `xorm:"pk autoincr"`
+		RepoID          int64              `xorm:"UNIQUE NOT NULL"`
+		LooseObjects    int64              `xorm:"NOT NULL DEFAULT 0"`
+		Packs           int64              `xorm:"NOT NULL DEFAULT 0"`
+		PackSize        int64              `xorm:"NOT NULL DEFAULT 0"`
+		LastTasks       []string           `xorm:"JSON TEXT"`
+		LastError       string             `xorm:"TEXT"`
+		LastDuration    int64              `xorm:"NOT NULL DEFAULT 0"`
+		LastCheckedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		LastRunUnix     timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(RepoMaintenance))
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type chatopsCommand struct {
+	ID          int64  `xorm:"pk autoincr"`
+	Name        string `xorm:"VARCHAR(50) UNIQUE NOT NULL"`
+	Description string `xorm:"TEXT"`
+	URL         string `xorm:"TEXT NOT NULL"`
+	Secret      string `xorm:"TEXT"`
+	AccessMode  int    `xorm:"NOT NULL DEFAULT 2"`
+	IsActive    bool   `xorm:"INDEX NOT NULL DEFAULT true"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func (chatopsCommand) TableName() string {
+	return "chatops_command"
+}
+
+type chatopsAudit struct {
+	ID        int64    `xorm:"pk autoincr"`
+	RepoID    int64    `xorm:"INDEX NOT NULL"`
+	IssueID   int64    `xorm:"INDEX NOT NULL"`
+	CommentID int64    `xorm:"NOT NULL DEFAULT 0"`
+	DoerID    int64    `xorm:"INDEX NOT NULL"`
+	Command   string   `xorm:"VARCHAR(50) INDEX NOT NULL"`
+	Args      []string `xorm:"JSON TEXT"`
+	Status    string   `xorm:"VARCHAR(20) NOT NULL"`
+	Message   string   `xorm:"TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func (chatopsAudit) TableName() string {
+	return "chatops_audit"
+}
+
+func AddChatOpsTables(x *xorm.Engine) error {
+	return x.Sync(new(chatopsCommand), new(chatopsAudit))
+}
@@ -0,0 +1,36 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddStalePolicyTable(x *xorm.Engine) error {
+	type StalePolicy struct {
+		ID      int64 `xorm:"pk autoincr"`
+		RepoID  int64 `xorm:"UNIQUE NOT NULL"`
+		Enabled bool  `xorm:"INDEX NOT NULL DEFAULT true"`
+
+		ApplyToIssues  bool   `xorm:"NOT NULL DEFAULT true"`
+		ApplyToPulls   bool   `xorm:"NOT NULL DEFAULT true"`
+		DaysUntilStale int    `xorm:"NOT NULL DEFAULT 60"`
+		DaysUntilClose int    `xorm:"NOT NULL DEFAULT 7"`
+		StaleLabelID   int64  `xorm:"NOT NULL"`
+		StaleComment   string `xorm:"TEXT"`
+		CloseComment   string `xorm:"TEXT"`
+
+		ExemptLabelIDs      []int64 `xorm:"JSON TEXT"`
+		ExemptMilestoneIDs  []int64 `xorm:"JSON TEXT"`
+		ExemptAllMilestones bool    `xorm:"NOT NULL DEFAULT false"`
+
+		LastRunUnix timeutil.TimeStamp
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(StalePolicy))
+}
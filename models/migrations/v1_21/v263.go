@@ -0,0 +1,54 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type userBlock struct {
+	ID          int64              `xorm:"pk autoincr"`
+	BlockerID   int64              `xorm:"UNIQUE(block) NOT NULL"`
+	BlockeeID   int64              `xorm:"UNIQUE(block) INDEX NOT NULL"`
+	Note        string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func (userBlock) TableName() string {
+	return "user_block"
+}
+
+func AddModerationTables(x *xorm.Engine) error {
+	type AbuseReport struct {
+		ID          int64  `xorm:"pk autoincr"`
+		ReporterID  int64  `xorm:"INDEX NOT NULL"`
+		ContentType string `xorm:"VARCHAR(20) INDEX(content) NOT NULL"`
+		ContentID   int64  `xorm:"INDEX(content) NOT NULL"`
+		Category    string `xorm:"VARCHAR(20) NOT NULL"`
+		Remarks     string `xorm:"TEXT"`
+		Status      int    `xorm:"INDEX NOT NULL DEFAULT 0"`
+		HandledByID int64  `xorm:"NOT NULL DEFAULT 0"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type InteractionLimit struct {
+		ID                int64              `xorm:"pk autoincr"`
+		RepoID            int64              `xorm:"UNIQUE NOT NULL"`
+		MinAccountAgeDays int                `xorm:"NOT NULL"`
+		ExpiresUnix       timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		CreatedByID       int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix       timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type Comment struct {
+		HiddenReason string `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+		HiddenByID   int64  `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(userBlock), new(AbuseReport), new(InteractionLimit), new(Comment))
+}
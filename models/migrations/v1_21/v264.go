@@ -0,0 +1,32 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddHeldContentTable(x *xorm.Engine) error {
+	type HeldContent struct {
+		ID          int64    `xorm:"pk autoincr"`
+		Type        string   `xorm:"VARCHAR(20) INDEX NOT NULL"`
+		PosterID    int64    `xorm:"INDEX NOT NULL"`
+		RepoID      int64    `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Title       string   `xorm:"TEXT"`
+		Content     string   `xorm:"LONGTEXT"`
+		Payload     string   `xorm:"TEXT"`
+		Score       float64  `xorm:"NOT NULL DEFAULT 0"`
+		Reasons     []string `xorm:"JSON TEXT"`
+		Status      int      `xorm:"INDEX NOT NULL DEFAULT 0"`
+		ResultID    int64    `xorm:"NOT NULL DEFAULT 0"`
+		HandledByID int64    `xorm:"NOT NULL DEFAULT 0"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(HeldContent))
+}
@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddMailDeliveryTable(x *xorm.Engine) error {
+	type MailDelivery struct {
+		ID                int64              `xorm:"pk autoincr"`
+		MessageID         string             `xorm:"VARCHAR(255) INDEX"`
+		Recipient         string             `xorm:"VARCHAR(320) INDEX NOT NULL"`
+		Subject           string             `xorm:"TEXT"`
+		Provider          string             `xorm:"VARCHAR(50)"`
+		ProviderMessageID string             `xorm:"VARCHAR(255) INDEX"`
+		Status            int                `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Error             string             `xorm:"TEXT"`
+		CreatedUnix       timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix       timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(MailDelivery))
+}
@@ -0,0 +1,16 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddIsInternalToRepository(x *xorm.Engine) error {
+	type Repository struct {
+		IsInternal bool `xorm:"INDEX NOT NULL DEFAULT false"`
+	}
+
+	return x.Sync(new(Repository))
+}
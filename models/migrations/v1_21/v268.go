@@ -0,0 +1,29 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddForkSyncTable(x *xorm.Engine) error {
+	type ForkSync struct {
+		ID             int64  `xorm:"pk autoincr"`
+		RepoID         int64  `xorm:"UNIQUE(s) NOT NULL"`
+		Branch         string `xorm:"UNIQUE(s) NOT NULL"`
+		UpstreamBranch string `xorm:"NOT NULL"`
+		AllowMerge     bool   `xorm:"NOT NULL DEFAULT false"`
+		DoerID         int64  `xorm:"NOT NULL"`
+
+		LastStatus   int    `xorm:"NOT NULL DEFAULT 0"`
+		LastMessage  string `xorm:"TEXT"`
+		LastSyncUnix timeutil.TimeStamp
+		CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(ForkSync))
+}
@@ -0,0 +1,26 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddForkDivergenceTable(x *xorm.Engine) error {
+	type ForkDivergence struct {
+		ID             int64  `xorm:"pk autoincr"`
+		RepoID         int64  `xorm:"UNIQUE NOT NULL"`
+		BaseRepoID     int64  `xorm:"INDEX NOT NULL"`
+		Ahead          int    `xorm:"NOT NULL DEFAULT 0"`
+		Behind         int    `xorm:"NOT NULL DEFAULT 0"`
+		CommitID       string `xorm:"VARCHAR(64)"`
+		BaseCommitID   string `xorm:"VARCHAR(64)"`
+		LastCommitUnix timeutil.TimeStamp
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(ForkDivergence))
+}
@@ -0,0 +1,26 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddManagedHookTable(x *xorm.Engine) error {
+	type ManagedHook struct {
+		ID       int64  `xorm:"pk autoincr"`
+		OwnerID  int64  `xorm:"UNIQUE(owner_name) NOT NULL DEFAULT 0"`
+		Name     string `xorm:"UNIQUE(owner_name) NOT NULL"`
+		Type     string `xorm:"VARCHAR(20) INDEX NOT NULL"`
+		Content  string `xorm:"LONGTEXT"`
+		IsActive bool   `xorm:"NOT NULL DEFAULT true"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(ManagedHook))
+}
@@ -0,0 +1,16 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddStorageRegionToUser(x *xorm.Engine) error {
+	type User struct {
+		StorageRegion string `xorm:"VARCHAR(50) NOT NULL DEFAULT ''"`
+	}
+
+	return x.Sync(new(User))
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddPullRequestMetricTable(x *xorm.Engine) error {
+	type PullRequestMetric struct {
+		ID            int64              `xorm:"pk autoincr"`
+		PullRequestID int64              `xorm:"UNIQUE NOT NULL"`
+		RepoID        int64              `xorm:"INDEX NOT NULL"`
+		CycleTime     int64              `xorm:"NOT NULL DEFAULT 0"`
+		LeadTime      int64              `xorm:"NOT NULL DEFAULT 0"`
+		ReviewLatency int64              `xorm:"NOT NULL DEFAULT -1"`
+		OpenedUnix    timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		MergedUnix    timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(PullRequestMetric))
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateActionWorkflowSubscriptionTable(x *xorm.Engine) error {
+	type ActionWorkflowSubscription struct {
+		ID           int64
+		OwnerID      int64    `xorm:"index"`
+		RepoID       int64    `xorm:"index"`
+		WorkflowID   string   `xorm:"VARCHAR(255)"`
+		Event        string   `xorm:"VARCHAR(255)"`
+		Repositories []string `xorm:"JSON TEXT"`
+
+		Created timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(ActionWorkflowSubscription))
+}
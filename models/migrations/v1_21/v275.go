@@ -0,0 +1,46 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type pullMergeQueueEntry struct {
+	ID           int64              `xorm:"pk autoincr"`
+	RepoID       int64              `xorm:"INDEX(s)"`
+	BaseBranch   string             `xorm:"INDEX(s)"`
+	PullID       int64              `xorm:"UNIQUE"`
+	DoerID       int64              `xorm:"NOT NULL"`
+	MergeStyle   string             `xorm:"varchar(30)"`
+	Message      string             `xorm:"LONGTEXT"`
+	TrainID      int64              `xorm:"INDEX"`
+	HeadCommitID string             `xorm:"VARCHAR(64)"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+}
+
+func (pullMergeQueueEntry) TableName() string {
+	return "pull_merge_queue"
+}
+
+type pullMergeTrain struct {
+	ID           int64              `xorm:"pk autoincr"`
+	RepoID       int64              `xorm:"UNIQUE(s)"`
+	BaseBranch   string             `xorm:"UNIQUE(s)"`
+	BaseCommitID string             `xorm:"VARCHAR(64)"`
+	CommitID     string             `xorm:"VARCHAR(64) INDEX"`
+	Branch       string             `xorm:"VARCHAR(255)"`
+	Size         int                `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+}
+
+func (pullMergeTrain) TableName() string {
+	return "pull_merge_train"
+}
+
+func AddMergeQueueTables(x *xorm.Engine) error {
+	return x.Sync(new(pullMergeQueueEntry), new(pullMergeTrain))
+}
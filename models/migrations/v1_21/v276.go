@@ -0,0 +1,17 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddCommentThreadColumns(x *xorm.Engine) error {
+	type Comment struct {
+		ReplyToID       int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+		ThreadCollapsed bool  `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	return x.Sync(new(Comment))
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type discussionCategory struct {
+	ID          int64  `xorm:"pk autoincr"`
+	RepoID      int64  `xorm:"INDEX NOT NULL"`
+	Name        string `xorm:"VARCHAR(255) NOT NULL"`
+	Description string `xorm:"TEXT"`
+	Kind        string `xorm:"VARCHAR(20) NOT NULL DEFAULT 'general'"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func (discussionCategory) TableName() string {
+	return "discussion_category"
+}
+
+type discussionComment struct {
+	ID           int64  `xorm:"pk autoincr"`
+	DiscussionID int64  `xorm:"INDEX NOT NULL"`
+	PosterID     int64  `xorm:"INDEX"`
+	Content      string `xorm:"LONGTEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func (discussionComment) TableName() string {
+	return "discussion_comment"
+}
+
+func CreateDiscussionTables(x *xorm.Engine) error {
+	type Discussion struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RepoID      int64  `xorm:"INDEX UNIQUE(repo_index)"`
+		Index       int64  `xorm:"UNIQUE(repo_index)"`
+		CategoryID  int64  `xorm:"INDEX NOT NULL"`
+		PosterID    int64  `xorm:"INDEX"`
+		Title       string `xorm:"TEXT"`
+		Content     string `xorm:"LONGTEXT"`
+		IsLocked    bool   `xorm:"NOT NULL DEFAULT false"`
+		AnswerID    int64  `xorm:"NOT NULL DEFAULT 0"`
+		NumComments int64  `xorm:"NOT NULL DEFAULT 0"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	}
+
+	type DiscussionIndex struct {
+		GroupID  int64 `xorm:"pk"`
+		MaxIndex int64 `xorm:"index"`
+	}
+
+	return x.Sync(new(discussionCategory), new(Discussion), new(DiscussionIndex), new(discussionComment))
+}
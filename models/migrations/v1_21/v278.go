@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type userStatus struct {
+	UserID      int64              `xorm:"pk"`
+	Emoji       string             `xorm:"VARCHAR(100)"`
+	Message     string             `xorm:"VARCHAR(255)"`
+	IsBusy      bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+	EndUnix     timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func (userStatus) TableName() string {
+	return "user_status"
+}
+
+func CreateUserStatusTable(x *xorm.Engine) error {
+	return x.Sync(new(userStatus))
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateTeamReminderTable(x *xorm.Engine) error {
+	type TeamReminder struct {
+		ID          int64  `xorm:"pk autoincr"`
+		OrgID       int64  `xorm:"INDEX NOT NULL"`
+		TeamID      int64  `xorm:"INDEX NOT NULL"`
+		Schedule    string `xorm:"VARCHAR(100) NOT NULL"`
+		WebhookType string `xorm:"VARCHAR(16) NOT NULL"`
+		WebhookURL  string `xorm:"TEXT NOT NULL"`
+		IsActive    bool   `xorm:"INDEX NOT NULL DEFAULT true"`
+
+		IncludeReviewRequests bool `xorm:"NOT NULL DEFAULT true"`
+		IncludeStalePulls     bool `xorm:"NOT NULL DEFAULT true"`
+		StaleDays             int  `xorm:"NOT NULL DEFAULT 7"`
+		IncludeFailingChecks  bool `xorm:"NOT NULL DEFAULT true"`
+		IgnoreWorkInProgress  bool `xorm:"NOT NULL DEFAULT false"`
+
+		LastRunUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		NextRunUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(TeamReminder))
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type attachmentTypeRule struct {
+	Type    string `json:"type"`
+	MaxSize int64  `json:"max_size"`
+}
+
+func AddAttachmentDeduplicationAndPolicy(x *xorm.Engine) error {
+	type Attachment struct {
+		ContentHash  string `xorm:"VARCHAR(64) INDEX"`
+		ObjectUUID   string `xorm:"VARCHAR(40)"`
+		HasThumbnail bool   `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	type AttachmentPolicy struct {
+		ID                int64                 `xorm:"pk autoincr"`
+		OrgID             int64                 `xorm:"UNIQUE NOT NULL"`
+		Rules             []*attachmentTypeRule `xorm:"JSON TEXT"`
+		OptimizeImages    bool                  `xorm:"NOT NULL DEFAULT false"`
+		MaxImageDimension int                   `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix       timeutil.TimeStamp    `xorm:"created"`
+		UpdatedUnix       timeutil.TimeStamp    `xorm:"updated"`
+	}
+
+	return x.Sync(new(Attachment), new(AttachmentPolicy))
+}
@@ -0,0 +1,15 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddCodePathsToTeam(x *xorm.Engine) error {
+	type Team struct {
+		CodePaths []string `xorm:"JSON TEXT"`
+	}
+	return x.Sync(new(Team))
+}
@@ -0,0 +1,29 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddCountPrivateActivityAndLinkedIdentity(x *xorm.Engine) error {
+	type User struct {
+		CountPrivateActivity bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	type LinkedIdentity struct {
+		ID             int64              `xorm:"pk autoincr"`
+		UserID         int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		InstanceURL    string             `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+		RemoteUsername string             `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+		Token          string             `xorm:"VARCHAR(50) NOT NULL"`
+		IsVerified     bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(User), new(LinkedIdentity))
+}
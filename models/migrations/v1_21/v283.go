@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddStorageUsageTable(x *xorm.Engine) error {
+	type StorageUsage struct {
+		ID             int64              `xorm:"pk autoincr"`
+		OwnerID        int64              `xorm:"INDEX NOT NULL"`
+		RepoID         int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		GitSize        int64              `xorm:"NOT NULL DEFAULT 0"`
+		LFSSize        int64              `xorm:"NOT NULL DEFAULT 0"`
+		AttachmentSize int64              `xorm:"NOT NULL DEFAULT 0"`
+		PackageSize    int64              `xorm:"NOT NULL DEFAULT 0"`
+		ActionsSize    int64              `xorm:"NOT NULL DEFAULT 0"`
+		TotalSize      int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	}
+
+	return x.Sync(new(StorageUsage))
+}
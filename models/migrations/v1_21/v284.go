@@ -0,0 +1,33 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddArchivePolicyAndArchiveNoticeTables(x *xorm.Engine) error {
+	type ArchivePolicy struct {
+		ID             int64              `xorm:"pk autoincr"`
+		OrgID          int64              `xorm:"UNIQUE NOT NULL"`
+		InactiveMonths int                `xorm:"NOT NULL DEFAULT 0"`
+		NoticeDays     int                `xorm:"NOT NULL DEFAULT 0"`
+		ExemptTopics   []string           `xorm:"JSON TEXT"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type ArchiveNotice struct {
+		ID               int64              `xorm:"pk autoincr"`
+		RepoID           int64              `xorm:"UNIQUE NOT NULL"`
+		OwnerID          int64              `xorm:"INDEX NOT NULL"`
+		LastActivityUnix timeutil.TimeStamp `xorm:"NOT NULL"`
+		ArchiveUnix      timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		CreatedUnix      timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(ArchivePolicy), new(ArchiveNotice))
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddTeamMentionSettings(x *xorm.Engine) error {
+	type Team struct {
+		MentionMode        string `xorm:"VARCHAR(16) NOT NULL DEFAULT 'all'"`
+		MentionWebhookType string `xorm:"VARCHAR(16)"`
+		MentionWebhookURL  string `xorm:"TEXT"`
+		MentionRateLimit   int    `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	type TeamUser struct {
+		IsLead bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	type TeamMention struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OrgID       int64              `xorm:"INDEX NOT NULL"`
+		TeamID      int64              `xorm:"INDEX NOT NULL"`
+		IssueID     int64              `xorm:"NOT NULL"`
+		DoerID      int64              `xorm:"NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync(new(Team), new(TeamUser), new(TeamMention))
+}
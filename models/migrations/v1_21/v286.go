@@ -0,0 +1,17 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddPackageVersionDeprecation(x *xorm.Engine) error {
+	type PackageVersion struct {
+		IsDeprecated       bool   `xorm:"INDEX NOT NULL DEFAULT false"`
+		DeprecationMessage string `xorm:"TEXT"`
+	}
+
+	return x.Sync(new(PackageVersion))
+}
@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddBackgroundMigrationTable(x *xorm.Engine) error {
+	type BackgroundMigration struct {
+		ID         int64  `xorm:"pk autoincr"`
+		Name       string `xorm:"UNIQUE NOT NULL"`
+		Total      int64  `xorm:"NOT NULL DEFAULT 0"`
+		Processed  int64  `xorm:"NOT NULL DEFAULT 0"`
+		LastID     int64  `xorm:"NOT NULL DEFAULT 0"`
+		IsFinished bool   `xorm:"NOT NULL DEFAULT false"`
+		LastError  string `xorm:"TEXT"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(BackgroundMigration))
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddDeployKeyRestrictionsAndUsage(x *xorm.Engine) error {
+	type DeployKey struct {
+		RefPatterns  []string           `xorm:"JSON TEXT"`
+		ArchiveOnly  bool               `xorm:"NOT NULL DEFAULT false"`
+		LastUsedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	type DeployKeyUsage struct {
+		ID          int64 `xorm:"pk autoincr"`
+		DeployKeyID int64 `xorm:"INDEX NOT NULL"`
+		RepoID      int64 `xorm:"INDEX NOT NULL"`
+		Mode        int
+		Verb        string
+		RemoteAddr  string
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync(new(DeployKey), new(DeployKeyUsage))
+}
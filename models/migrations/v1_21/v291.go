@@ -0,0 +1,40 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddFlakyCheckRuleAndCommitStatusAttemptTables(x *xorm.Engine) error {
+	type FlakyCheckRule struct {
+		ID             int64              `xorm:"pk autoincr"`
+		RepoID         int64              `xorm:"INDEX NOT NULL"`
+		ContextPattern string             `xorm:"TEXT NOT NULL"`
+		MaxRetries     int                `xorm:"NOT NULL DEFAULT 0"`
+		RequiredPasses int                `xorm:"NOT NULL DEFAULT 0"`
+		Attempts       int                `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type CommitStatusAttempt struct {
+		ID          int64  `xorm:"pk autoincr"`
+		RepoID      int64  `xorm:"INDEX NOT NULL"`
+		SHA         string `xorm:"VARCHAR(64) NOT NULL INDEX"`
+		ContextHash string `xorm:"char(40) INDEX"`
+		Context     string `xorm:"TEXT"`
+		Attempt     int    `xorm:"NOT NULL"`
+		State       string `xorm:"VARCHAR(7)"`
+		Reason      string `xorm:"VARCHAR(16)"`
+		DoerID      int64
+		JobID       int64
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(FlakyCheckRule), new(CommitStatusAttempt))
+}
@@ -0,0 +1,39 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type projectBoardWithWipLimit struct {
+	WipLimit     int   `xorm:"NOT NULL DEFAULT 0"`
+	WipLimitMode uint8 `xorm:"NOT NULL DEFAULT 0"`
+}
+
+func (projectBoardWithWipLimit) TableName() string {
+	return "project_board"
+}
+
+type projectViewSetting struct {
+	ID          int64 `xorm:"pk autoincr"`
+	ProjectID   int64 `xorm:"UNIQUE(s) NOT NULL"`
+	UserID      int64 `xorm:"UNIQUE(s) NOT NULL"`
+	Swimlane    uint8 `xorm:"NOT NULL DEFAULT 0"`
+	AssigneeID  int64 `xorm:"NOT NULL DEFAULT 0"`
+	LabelID     int64 `xorm:"NOT NULL DEFAULT 0"`
+	MilestoneID int64 `xorm:"NOT NULL DEFAULT 0"`
+
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func (projectViewSetting) TableName() string {
+	return "project_view_setting"
+}
+
+func AddWipLimitToProjectBoardAndProjectViewSettingTable(x *xorm.Engine) error {
+	return x.Sync(new(projectBoardWithWipLimit), new(projectViewSetting))
+}
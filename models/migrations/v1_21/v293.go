@@ -0,0 +1,30 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateTeamHomepageAndTeamPinnedRepoTable(x *xorm.Engine) error {
+	type TeamHomepage struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OrgID       int64              `xorm:"INDEX NOT NULL"`
+		TeamID      int64              `xorm:"UNIQUE NOT NULL"`
+		Readme      string             `xorm:"LONGTEXT"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type TeamPinnedRepo struct {
+		ID      int64 `xorm:"pk autoincr"`
+		OrgID   int64 `xorm:"INDEX NOT NULL"`
+		TeamID  int64 `xorm:"UNIQUE(s) NOT NULL"`
+		RepoID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		Sorting int   `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(TeamHomepage), new(TeamPinnedRepo))
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateMailDigestItemTable(x *xorm.Engine) error {
+	type MailDigestItem struct {
+		ID          int64              `xorm:"pk autoincr"`
+		UserID      int64              `xorm:"INDEX NOT NULL"`
+		RepoID      int64              `xorm:"NOT NULL"`
+		IssueID     int64              `xorm:"NOT NULL"`
+		CommentID   int64              `xorm:"NOT NULL DEFAULT 0"`
+		DoerID      int64              `xorm:"NOT NULL"`
+		OpType      int                `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created INDEX"`
+	}
+
+	return x.Sync(new(MailDigestItem))
+}
@@ -0,0 +1,29 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateAttachmentBlobTable(x *xorm.Engine) error {
+	type AttachmentBlob struct {
+		ID           int64              `xorm:"pk autoincr"`
+		Region       string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL DEFAULT ''"`
+		HashSHA256   string             `xorm:"hash_sha256 CHAR(64) UNIQUE(s) NOT NULL"`
+		Size         int64              `xorm:"NOT NULL DEFAULT 0"`
+		HasThumbnail bool               `xorm:"NOT NULL DEFAULT false"`
+		RefCount     int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"created NOT NULL"`
+		UpdatedUnix  timeutil.TimeStamp `xorm:"updated INDEX NOT NULL"`
+	}
+
+	type Attachment struct {
+		BlobID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(AttachmentBlob), new(Attachment))
+}
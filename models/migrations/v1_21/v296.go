@@ -0,0 +1,22 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddWebhookSecurityColumns(x *xorm.Engine) error {
+	type Webhook struct {
+		ClientCertificate         string             `xorm:"TEXT"`
+		ClientKeyEncrypted        string             `xorm:"TEXT"`
+		AllowedIPs                string             `xorm:"allowed_ips TEXT"`
+		PreviousSecret            string             `xorm:"TEXT"`
+		PreviousSecretExpiresUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(Webhook))
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateVendorSyncTable(x *xorm.Engine) error {
+	type VendorSync struct {
+		ID         int64  `xorm:"pk autoincr"`
+		RepoID     int64  `xorm:"UNIQUE(s) NOT NULL"`
+		Branch     string `xorm:"UNIQUE(s) NOT NULL"`
+		TargetPath string `xorm:"UNIQUE(s) NOT NULL DEFAULT ''"`
+
+		SourceType               string `xorm:"VARCHAR(16) NOT NULL"`
+		SourceURL                string `xorm:"TEXT NOT NULL"`
+		Bucket                   string
+		Prefix                   string `xorm:"TEXT"`
+		AccessKeyID              string
+		SecretAccessKeyEncrypted string `xorm:"TEXT"`
+		StripComponents          int    `xorm:"NOT NULL DEFAULT 0"`
+
+		Interval time.Duration
+		DoerID   int64 `xorm:"NOT NULL"`
+
+		NextSyncUnix timeutil.TimeStamp `xorm:"INDEX"`
+		LastStatus   int                `xorm:"NOT NULL DEFAULT 0"`
+		LastMessage  string             `xorm:"TEXT"`
+		LastSyncUnix timeutil.TimeStamp
+		LastDigest   string `xorm:"VARCHAR(64)"`
+		LastVersion  string
+		LastCommitID string `xorm:"VARCHAR(64)"`
+
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(VendorSync))
+}
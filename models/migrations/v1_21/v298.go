@@ -0,0 +1,36 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddReviewChecklistTables(x *xorm.Engine) error {
+	type ReviewChecklistItem struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		Name        string             `xorm:"NOT NULL"`
+		Description string             `xorm:"TEXT"`
+		Required    bool               `xorm:"NOT NULL DEFAULT false"`
+		Sort        int                `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type ReviewChecklistCheck struct {
+		ID          int64              `xorm:"pk autoincr"`
+		ReviewID    int64              `xorm:"UNIQUE(s) NOT NULL"`
+		ItemID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type ProtectedBranch struct {
+		RequireReviewChecklist bool `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	return x.Sync(new(ReviewChecklistItem), new(ReviewChecklistCheck), new(ProtectedBranch))
+}
@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreatePackageAdvisoryTable(x *xorm.Engine) error {
+	type PackageAdvisory struct {
+		ID                 int64              `xorm:"pk autoincr"`
+		OwnerID            int64              `xorm:"INDEX(s) NOT NULL"`
+		Type               string             `xorm:"INDEX(s) NOT NULL"`
+		PackageName        string             `xorm:"INDEX(s) NOT NULL"`
+		Title              string             `xorm:"NOT NULL"`
+		URL                string             `xorm:"TEXT"`
+		Severity           string             `xorm:"NOT NULL"`
+		VulnerableVersions string             `xorm:"TEXT NOT NULL"`
+		CreatedUnix        timeutil.TimeStamp `xorm:"created NOT NULL DEFAULT 0"`
+		UpdatedUnix        timeutil.TimeStamp `xorm:"updated NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(PackageAdvisory))
+}
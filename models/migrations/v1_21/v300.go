@@ -0,0 +1,32 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateDownloadLinkTable(x *xorm.Engine) error {
+	type DownloadLink struct {
+		ID             int64 `xorm:"pk autoincr"`
+		RepoID         int64 `xorm:"INDEX NOT NULL"`
+		AttachmentID   int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+		ArchiveName    string
+		ArchiveType    int    `xorm:"NOT NULL DEFAULT 0"`
+		CommitID       string `xorm:"VARCHAR(64)"`
+		TokenHash      string `xorm:"UNIQUE"`
+		TokenSalt      string
+		TokenLastEight string             `xorm:"INDEX"`
+		CreatorID      int64              `xorm:"NOT NULL"`
+		DownloadCount  int64              `xorm:"NOT NULL DEFAULT 0"`
+		ExpiresUnix    timeutil.TimeStamp `xorm:"NOT NULL"`
+		RevokerID      int64              `xorm:"NOT NULL DEFAULT 0"`
+		RevokedUnix    timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(DownloadLink))
+}
@@ -0,0 +1,33 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreatePathOwnershipTables(x *xorm.Engine) error {
+	type PathContribution struct {
+		ID             int64              `xorm:"pk autoincr"`
+		RepoID         int64              `xorm:"INDEX NOT NULL"`
+		Path           string             `xorm:"VARCHAR(500) NOT NULL DEFAULT ''"`
+		Depth          int                `xorm:"NOT NULL DEFAULT 0"`
+		AuthorName     string             `xorm:"NOT NULL DEFAULT ''"`
+		AuthorEmail    string             `xorm:"NOT NULL DEFAULT ''"`
+		Commits        int64              `xorm:"NOT NULL DEFAULT 0"`
+		Lines          int64              `xorm:"NOT NULL DEFAULT 0"`
+		LastCommitUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	type PathOwnershipStatus struct {
+		ID           int64              `xorm:"pk autoincr"`
+		RepoID       int64              `xorm:"UNIQUE NOT NULL"`
+		CommitID     string             `xorm:"VARCHAR(64) NOT NULL"`
+		ComputedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(PathContribution), new(PathOwnershipStatus))
+}
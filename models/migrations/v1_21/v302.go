@@ -0,0 +1,34 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateThemeAndBrandingTables(x *xorm.Engine) error {
+	type Theme struct {
+		ID          int64  `xorm:"pk autoincr"`
+		Name        string `xorm:"VARCHAR(30) UNIQUE NOT NULL"`
+		DisplayName string
+		Variables   map[string]string `xorm:"TEXT JSON"`
+		Logo        string
+		OrgBranding bool               `xorm:"NOT NULL DEFAULT false"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type Branding struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OrgID       int64              `xorm:"UNIQUE NOT NULL"`
+		ThemeID     int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		AccentColor string             `xorm:"VARCHAR(7) NOT NULL DEFAULT ''"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(Theme), new(Branding))
+}
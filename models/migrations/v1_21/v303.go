@@ -0,0 +1,24 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateTranslationOverrideTable(x *xorm.Engine) error {
+	type TranslationOverride struct {
+		ID          int64              `xorm:"pk autoincr"`
+		Lang        string             `xorm:"VARCHAR(20) UNIQUE(s) NOT NULL"`
+		TrKey       string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+		Message     string             `xorm:"TEXT NOT NULL"`
+		UpdaterID   int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(TranslationOverride))
+}
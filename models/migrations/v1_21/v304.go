@@ -0,0 +1,35 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+type oauth2ApplicationV303 struct {
+	Scopes                     []string `xorm:"JSON TEXT"`
+	AccessTokenExpirationTime  int64    `xorm:"NOT NULL DEFAULT 0"`
+	RefreshTokenExpirationTime int64    `xorm:"NOT NULL DEFAULT 0"`
+}
+
+func (oauth2ApplicationV303) TableName() string {
+	return "oauth2_application"
+}
+
+type oauth2GrantV303 struct {
+	AccessTokenScope string `xorm:"TEXT"`
+}
+
+func (oauth2GrantV303) TableName() string {
+	return "oauth2_grant"
+}
+
+func AddOAuth2ScopesAndTokenLifetimes(x *xorm.Engine) error {
+	if err := x.Sync(new(oauth2ApplicationV303), new(oauth2GrantV303)); err != nil {
+		return err
+	}
+	// the existing grants were given access to all API scopes
+	_, err := x.Exec("UPDATE oauth2_grant SET access_token_scope = ?", "all")
+	return err
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateLoginDeviceTable(x *xorm.Engine) error {
+	type LoginDevice struct {
+		ID           int64              `xorm:"pk autoincr"`
+		UID          int64              `xorm:"UNIQUE(s) NOT NULL"`
+		Fingerprint  string             `xorm:"VARCHAR(64) UNIQUE(s) NOT NULL"`
+		UserAgent    string             `xorm:"TEXT"`
+		IP           string             `xorm:"VARCHAR(64)"`
+		Location     string             `xorm:"VARCHAR(255)"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+		LastUsedUnix timeutil.TimeStamp `xorm:"INDEX"`
+	}
+
+	return x.Sync(new(LoginDevice))
+}
@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+type userRedirectV305 struct {
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func (userRedirectV305) TableName() string {
+	return "user_redirect"
+}
+
+func AddCreatedUnixToUserRedirect(x *xorm.Engine) error {
+	if err := x.Sync(new(userRedirectV305)); err != nil {
+		return err
+	}
+	// the retention of the existing redirects starts with the upgrade
+	_, err := x.Exec("UPDATE user_redirect SET created_unix = ? WHERE created_unix = 0 OR created_unix IS NULL", timeutil.TimeStampNow())
+	return err
+}
@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func CreateEditSessionTable(x *xorm.Engine) error {
+	type EditSession struct {
+		ID           int64              `xorm:"pk autoincr"`
+		RepoID       int64              `xorm:"INDEX NOT NULL"`
+		UserID       int64              `xorm:"INDEX NOT NULL"`
+		BaseBranch   string             `xorm:"NOT NULL"`
+		BaseCommitID string             `xorm:"VARCHAR(64) NOT NULL"`
+		HeadCommitID string             `xorm:"VARCHAR(64) NOT NULL"`
+		NumEdits     int                `xorm:"NOT NULL DEFAULT 0"`
+		ExpiresUnix  timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+		CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(EditSession))
+}
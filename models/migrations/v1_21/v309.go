@@ -0,0 +1,46 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddTeamGroupSync(x *xorm.Engine) error {
+	type Team struct {
+		GroupSyncPolicy  string `xorm:"VARCHAR(16) NOT NULL DEFAULT 'any'"`
+		GroupSyncRemoval bool   `xorm:"NOT NULL DEFAULT false"`
+	}
+
+	type TeamGroupMapping struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OrgID       int64              `xorm:"INDEX NOT NULL"`
+		TeamID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+		SourceID    int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		GroupName   string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type ExternalGroupMember struct {
+		ID          int64              `xorm:"pk autoincr"`
+		SourceID    int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		GroupName   string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+		UserID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	type TeamGroupSyncLog struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OrgID       int64              `xorm:"INDEX NOT NULL"`
+		TeamID      int64              `xorm:"INDEX NOT NULL"`
+		UserID      int64              `xorm:"NOT NULL"`
+		Action      string             `xorm:"VARCHAR(16) NOT NULL"`
+		Reason      string             `xorm:"TEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync(new(Team), new(TeamGroupMapping), new(ExternalGroupMember), new(TeamGroupSyncLog))
+}
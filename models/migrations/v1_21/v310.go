@@ -0,0 +1,35 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddIssueLockLevelsAndPolicy(x *xorm.Engine) error {
+	type Issue struct {
+		LockLevel     string             `xorm:"VARCHAR(20) NOT NULL DEFAULT ''"`
+		LockUntilUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	type IssueLockPolicy struct {
+		ID             int64  `xorm:"pk autoincr"`
+		RepoID         int64  `xorm:"UNIQUE NOT NULL"`
+		Enabled        bool   `xorm:"INDEX NOT NULL DEFAULT true"`
+		ApplyToIssues  bool   `xorm:"NOT NULL DEFAULT true"`
+		ApplyToPulls   bool   `xorm:"NOT NULL DEFAULT true"`
+		DaysAfterClose int    `xorm:"NOT NULL DEFAULT 365"`
+		LockLevel      string `xorm:"VARCHAR(20) NOT NULL DEFAULT 'collaborators'"`
+		Reason         string `xorm:"VARCHAR(255)"`
+		LockComment    string `xorm:"TEXT"`
+
+		LastRunUnix timeutil.TimeStamp
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(Issue), new(IssueLockPolicy))
+}
@@ -0,0 +1,24 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddTemplateCatalogItemTable(x *xorm.Engine) error {
+	type TemplateCatalogItem struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OwnerID     int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+		Kind        string             `xorm:"VARCHAR(32) UNIQUE(s) NOT NULL"`
+		Name        string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+		Content     string             `xorm:"LONGTEXT"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+		UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+	}
+
+	return x.Sync(new(TemplateCatalogItem))
+}
@@ -0,0 +1,16 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddSyncHookSecretToMirror(x *xorm.Engine) error {
+	type Mirror struct {
+		SyncHookSecretEncrypted string `xorm:"TEXT"`
+	}
+
+	return x.Sync(new(Mirror))
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddCoverageReportAndBadgeTokenTables(x *xorm.Engine) error {
+	type CoverageReport struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX(s) NOT NULL"`
+		Branch      string             `xorm:"VARCHAR(255) INDEX(s) NOT NULL DEFAULT ''"`
+		CommitSHA   string             `xorm:"VARCHAR(64) INDEX NOT NULL"`
+		Coverage    float64            `xorm:"NOT NULL DEFAULT 0"`
+		CreatorID   int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type BadgeToken struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE NOT NULL"`
+		Salt        string             `xorm:"VARCHAR(40) NOT NULL"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(CoverageReport), new(BadgeToken))
+}
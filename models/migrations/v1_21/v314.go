@@ -0,0 +1,25 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddCoverageReportFileTableAndMinDiffCoverage(x *xorm.Engine) error {
+	type CoverageReportFile struct {
+		ID             int64  `xorm:"pk autoincr"`
+		RepoID         int64  `xorm:"INDEX NOT NULL"`
+		ReportID       int64  `xorm:"INDEX NOT NULL"`
+		Path           string `xorm:"VARCHAR(1024) NOT NULL"`
+		CoveredLines   []int  `xorm:"JSON TEXT"`
+		UncoveredLines []int  `xorm:"JSON TEXT"`
+	}
+
+	type ProtectedBranch struct {
+		MinDiffCoverage int64 `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(CoverageReportFile), new(ProtectedBranch))
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddEmbedTokenTable(x *xorm.Engine) error {
+	type EmbedToken struct {
+		ID             int64              `xorm:"pk autoincr"`
+		RepoID         int64              `xorm:"INDEX NOT NULL"`
+		Name           string             `xorm:"VARCHAR(255) NOT NULL"`
+		AllowedOrigins []string           `xorm:"JSON TEXT"`
+		Widgets        []string           `xorm:"JSON TEXT"`
+		Salt           string             `xorm:"VARCHAR(40) NOT NULL"`
+		CreatorID      int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	}
+
+	return x.Sync(new(EmbedToken))
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddRepoAndPackageVersionDeletionTables(x *xorm.Engine) error {
+	type RepoDeletion struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE NOT NULL"`
+		Status      int                `xorm:"NOT NULL DEFAULT 0"`
+		DeleterID   int64              `xorm:"NOT NULL DEFAULT 0"`
+		DeletedUnix timeutil.TimeStamp `xorm:"created"`
+		PurgeUnix   timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	type PackageVersionDeletion struct {
+		ID          int64              `xorm:"pk autoincr"`
+		OwnerID     int64              `xorm:"INDEX NOT NULL"`
+		PackageType string             `xorm:"NOT NULL"`
+		PackageName string             `xorm:"NOT NULL"`
+		Version     string             `xorm:"NOT NULL"`
+		Snapshot    string             `xorm:"LONGTEXT"`
+		DeleterID   int64              `xorm:"NOT NULL DEFAULT 0"`
+		DeletedUnix timeutil.TimeStamp `xorm:"created"`
+		PurgeUnix   timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	}
+
+	type PackageVersionDeletionBlob struct {
+		ID         int64 `xorm:"pk autoincr"`
+		DeletionID int64 `xorm:"INDEX NOT NULL"`
+		BlobID     int64 `xorm:"INDEX NOT NULL"`
+	}
+
+	return x.Sync(new(RepoDeletion), new(PackageVersionDeletion), new(PackageVersionDeletionBlob))
+}
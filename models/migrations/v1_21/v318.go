@@ -0,0 +1,16 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddRequiredApprovalToActionTask(x *xorm.Engine) error {
+	type ActionTask struct {
+		RequiredApproval bool
+	}
+
+	return x.Sync(new(ActionTask))
+}
@@ -0,0 +1,37 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddLegalHoldAndRedactionTables(x *xorm.Engine) error {
+	type LegalHold struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"INDEX NOT NULL"`
+		IssueID     int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+		Reason      string             `xorm:"TEXT"`
+		CreatorID   int64              `xorm:"NOT NULL DEFAULT 0"`
+		CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	}
+
+	type LegalRedaction struct {
+		ID               int64  `xorm:"pk autoincr"`
+		RepoID           int64  `xorm:"INDEX NOT NULL"`
+		IssueID          int64  `xorm:"INDEX NOT NULL"`
+		CommentID        int64  `xorm:"NOT NULL DEFAULT 0"`
+		ContentHistoryID int64  `xorm:"NOT NULL DEFAULT 0"`
+		AttachmentID     int64  `xorm:"NOT NULL DEFAULT 0"`
+		AttachmentUUID   string `xorm:"VARCHAR(40)"`
+		AttachmentName   string
+		Reason           string             `xorm:"TEXT"`
+		DoerID           int64              `xorm:"INDEX NOT NULL"`
+		CreatedUnix      timeutil.TimeStamp `xorm:"INDEX created"`
+	}
+
+	return x.Sync(new(LegalHold), new(LegalRedaction))
+}
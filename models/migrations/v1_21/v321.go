@@ -0,0 +1,34 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/models/migrations/base"
+
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+func AddForbiddenToRepoLicenseAndWidenCommitID(x *xorm.Engine) error {
+	type RepoLicense struct {
+		Forbidden bool `xorm:"NOT NULL DEFAULT false"`
+	}
+	if err := x.Sync(new(RepoLicense)); err != nil {
+		return err
+	}
+
+	// For SQLITE, the max length doesn't matter.
+	if x.Dialect().URI().DBType == schemas.SQLITE {
+		return nil
+	}
+	return base.ModifyColumn(x, "repo_license", &schemas.Column{
+		Name: "commit_id",
+		SQLType: schemas.SQLType{
+			Name: "VARCHAR",
+		},
+		Length:         64,
+		Nullable:       true,
+		DefaultIsEmpty: true,
+	})
+}
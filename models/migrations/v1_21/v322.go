@@ -0,0 +1,32 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/models/migrations/base"
+
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+func WidenCodeScanningCommitSHA(x *xorm.Engine) error {
+	// For SQLITE, the max length doesn't matter.
+	if x.Dialect().URI().DBType == schemas.SQLITE {
+		return nil
+	}
+	for _, table := range []string{"code_scanning_analysis", "code_scanning_alert"} {
+		if err := base.ModifyColumn(x, table, &schemas.Column{
+			Name: "commit_sha",
+			SQLType: schemas.SQLType{
+				Name: "VARCHAR",
+			},
+			Length:         64,
+			Nullable:       true,
+			DefaultIsEmpty: true,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
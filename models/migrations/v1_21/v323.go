@@ -0,0 +1,17 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"xorm.io/xorm"
+)
+
+func AddTeamReviewAssignment(x *xorm.Engine) error {
+	type Team struct {
+		ReviewAssignCount      int   `xorm:"NOT NULL DEFAULT 0"`
+		ReviewAssignLastUserID int64 `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(Team))
+}
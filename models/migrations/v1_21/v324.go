@@ -0,0 +1,21 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+)
+
+func AddExpiryAndRotationToPublicKey(x *xorm.Engine) error {
+	type PublicKey struct {
+		ExpiredUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		SuccessorID      int64              `xorm:"NOT NULL DEFAULT 0"`
+		RotatedUnix      timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+		ExpiryMailedUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	}
+
+	return x.Sync(new(PublicKey))
+}
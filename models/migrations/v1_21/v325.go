@@ -0,0 +1,44 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package v1_21 //nolint
+
+import (
+	"code.gitea.io/gitea/models/migrations/base"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/xorm"
+	"xorm.io/xorm/schemas"
+)
+
+func AddDependencyToRepoLicense(x *xorm.Engine) error {
+	type RepoLicense struct {
+		ID          int64              `xorm:"pk autoincr"`
+		RepoID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+		CommitID    string             `xorm:"VARCHAR(64)"`
+		License     string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+		Path        string             `xorm:"UNIQUE(s) NOT NULL"`
+		Confidence  float64            `xorm:"NOT NULL DEFAULT 0"`
+		Forbidden   bool               `xorm:"NOT NULL DEFAULT false"`
+		Dependency  string             `xorm:"UNIQUE(s) NOT NULL DEFAULT ''"`
+		CreatedUnix timeutil.TimeStamp `xorm:"INDEX CREATED"`
+	}
+	if err := x.Sync(new(RepoLicense)); err != nil {
+		return err
+	}
+
+	// For SQLITE, the max length doesn't matter.
+	if x.Dialect().URI().DBType == schemas.SQLITE {
+		return nil
+	}
+	// the licenses of dependencies are SPDX expressions, which can be longer than a single license name
+	return base.ModifyColumn(x, "repo_license", &schemas.Column{
+		Name: "license",
+		SQLType: schemas.SQLType{
+			Name: "VARCHAR",
+		},
+		Length:         255,
+		Nullable:       false,
+		DefaultIsEmpty: true,
+	})
+}
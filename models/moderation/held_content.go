@@ -0,0 +1,189 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package moderation
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// HeldContentType is the kind of content held for review
+type HeldContentType string
+
+const (
+	HeldContentTypeRegistration HeldContentType = "registration"
+	HeldContentTypeIssue        HeldContentType = "issue"
+	HeldContentTypePullRequest  HeldContentType = "pull_request"
+)
+
+// HeldContentStatus is the state of held content in the review queue
+type HeldContentStatus int
+
+const (
+	HeldContentStatusPending HeldContentStatus = iota
+	HeldContentStatusApproved
+	HeldContentStatusRejected
+)
+
+// String returns the API name of the status
+func (s HeldContentStatus) String() string {
+	switch s {
+	case HeldContentStatusApproved:
+		return "approved"
+	case HeldContentStatusRejected:
+		return "rejected"
+	default:
+		return "pending"
+	}
+}
+
+// ParseHeldContentStatus returns the status with the given API name
+func ParseHeldContentStatus(s string) (HeldContentStatus, bool) {
+	switch s {
+	case "pending":
+		return HeldContentStatusPending, true
+	case "approved":
+		return HeldContentStatusApproved, true
+	case "rejected":
+		return HeldContentStatusRejected, true
+	}
+	return HeldContentStatusPending, false
+}
+
+// ErrHeldContentNotExist represents a "HeldContentNotExist" kind of error.
+type ErrHeldContentNotExist struct {
+	ID int64
+}
+
+// IsErrHeldContentNotExist checks if an error is a ErrHeldContentNotExist.
+func IsErrHeldContentNotExist(err error) bool {
+	_, ok := err.(ErrHeldContentNotExist)
+	return ok
+}
+
+func (err ErrHeldContentNotExist) Error() string {
+	return fmt.Sprintf("held content does not exist [id: %d]", err.ID)
+}
+
+func (err ErrHeldContentNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// HeldPullRequest is what is needed to create a held pull request once it is approved
+type HeldPullRequest struct {
+	HeadRepoID int64  `json:"head_repo_id"`
+	HeadBranch string `json:"head_branch"`
+	BaseBranch string `json:"base_branch"`
+	MergeBase  string `json:"merge_base"`
+
+	AllowMaintainerEdit bool `json:"allow_maintainer_edit"`
+}
+
+// HeldIssuePayload contains the metadata of a held issue or pull request
+type HeldIssuePayload struct {
+	LabelIDs    []int64          `json:"label_ids,omitempty"`
+	AssigneeIDs []int64          `json:"assignee_ids,omitempty"`
+	MilestoneID int64            `json:"milestone_id,omitempty"`
+	Attachments []string         `json:"attachments,omitempty"`
+	Ref         string           `json:"ref,omitempty"`
+	PullRequest *HeldPullRequest `json:"pull_request,omitempty"`
+}
+
+// HeldContent is a registration, issue or pull request which scored as likely spam and
+// waits for a site administrator instead of being published.
+// Held registrations are created with login prohibited, held issues and pull requests are
+// only created once they are approved.
+type HeldContent struct {
+	ID       int64             `xorm:"pk autoincr"`
+	Type     HeldContentType   `xorm:"VARCHAR(20) INDEX NOT NULL"`
+	PosterID int64             `xorm:"INDEX NOT NULL"`
+	RepoID   int64             `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Title    string            `xorm:"TEXT"`
+	Content  string            `xorm:"LONGTEXT"`
+	Payload  *HeldIssuePayload `xorm:"JSON TEXT"`
+	Score    float64           `xorm:"NOT NULL DEFAULT 0"`
+	Reasons  []string          `xorm:"JSON TEXT"`
+	Status   HeldContentStatus `xorm:"INDEX NOT NULL DEFAULT 0"`
+	// ResultID is the id of the issue created when an issue or pull request is approved
+	ResultID    int64 `xorm:"NOT NULL DEFAULT 0"`
+	HandledByID int64 `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(HeldContent))
+}
+
+// CreateHeldContent adds content to the review queue
+func CreateHeldContent(ctx context.Context, held *HeldContent) error {
+	held.Status = HeldContentStatusPending
+	return db.Insert(ctx, held)
+}
+
+// GetHeldContentByID returns the held content with the given id
+func GetHeldContentByID(ctx context.Context, id int64) (*HeldContent, error) {
+	held := &HeldContent{}
+	has, err := db.GetEngine(ctx).ID(id).Get(held)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrHeldContentNotExist{ID: id}
+	}
+	return held, nil
+}
+
+// UpdateHeldContentStatus records how held content was handled
+func UpdateHeldContentStatus(ctx context.Context, held *HeldContent, status HeldContentStatus, doerID, resultID int64) error {
+	held.Status = status
+	held.HandledByID = doerID
+	held.ResultID = resultID
+	_, err := db.GetEngine(ctx).ID(held.ID).Cols("status", "handled_by_id", "result_id").Update(held)
+	return err
+}
+
+// FindHeldContentOptions represents the options to search held content
+type FindHeldContentOptions struct {
+	db.ListOptions
+	Statuses []HeldContentStatus
+	Type     HeldContentType
+	PosterID int64
+	RepoID   int64
+}
+
+// ToConds implements db.FindOptions
+func (opts FindHeldContentOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if len(opts.Statuses) > 0 {
+		cond = cond.And(builder.In("status", opts.Statuses))
+	}
+	if opts.Type != "" {
+		cond = cond.And(builder.Eq{"type": opts.Type})
+	}
+	if opts.PosterID > 0 {
+		cond = cond.And(builder.Eq{"poster_id": opts.PosterID})
+	}
+	if opts.RepoID > 0 {
+		cond = cond.And(builder.Eq{"repo_id": opts.RepoID})
+	}
+	return cond
+}
+
+// FindHeldContent returns the held content matching the options, oldest first
+func FindHeldContent(ctx context.Context, opts FindHeldContentOptions) ([]*HeldContent, int64, error) {
+	sess := db.GetEngine(ctx).Where(opts.ToConds()).OrderBy("id ASC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts.ListOptions)
+	}
+	held := make([]*HeldContent, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&held)
+	return held, count, err
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package moderation
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// InteractionLimit temporarily prevents new accounts without write access from
+// opening issues, pull requests and commenting in a repository
+type InteractionLimit struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"UNIQUE NOT NULL"`
+	// MinAccountAgeDays is the minimum age of an account to be allowed to interact
+	MinAccountAgeDays int                `xorm:"NOT NULL"`
+	ExpiresUnix       timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	CreatedByID       int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix       timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(InteractionLimit))
+}
+
+// IsExpired checks if the limit no longer applies, a limit without expiry never expires
+func (l *InteractionLimit) IsExpired() bool {
+	return l.ExpiresUnix > 0 && l.ExpiresUnix <= timeutil.TimeStampNow()
+}
+
+// IsAccountTooNew checks if an account created at the given time is not allowed to interact yet
+func (l *InteractionLimit) IsAccountTooNew(accountCreated timeutil.TimeStamp) bool {
+	return accountCreated.Add(int64(l.MinAccountAgeDays)*24*60*60) > timeutil.TimeStampNow()
+}
+
+// GetInteractionLimit returns the interaction limit of a repository, or nil if it has none
+func GetInteractionLimit(ctx context.Context, repoID int64) (*InteractionLimit, error) {
+	limit := &InteractionLimit{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(limit)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return limit, nil
+}
+
+// SetInteractionLimit creates or replaces the interaction limit of a repository
+func SetInteractionLimit(ctx context.Context, limit *InteractionLimit) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := DeleteInteractionLimit(ctx, limit.RepoID); err != nil {
+			return err
+		}
+		limit.ID = 0
+		return db.Insert(ctx, limit)
+	})
+}
+
+// DeleteInteractionLimit removes the interaction limit of a repository
+func DeleteInteractionLimit(ctx context.Context, repoID int64) error {
+	_, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(new(InteractionLimit))
+	return err
+}
@@ -0,0 +1,227 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package moderation
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ReportContentType is the kind of content an abuse report is about
+type ReportContentType string
+
+const (
+	ReportContentTypeUser       ReportContentType = "user"
+	ReportContentTypeRepository ReportContentType = "repository"
+	ReportContentTypeIssue      ReportContentType = "issue"
+	ReportContentTypeComment    ReportContentType = "comment"
+)
+
+// IsValid checks if the content type is known
+func (t ReportContentType) IsValid() bool {
+	switch t {
+	case ReportContentTypeUser, ReportContentTypeRepository, ReportContentTypeIssue, ReportContentTypeComment:
+		return true
+	}
+	return false
+}
+
+// ReportCategory is the reason content is reported for
+type ReportCategory string
+
+const (
+	ReportCategorySpam    ReportCategory = "spam"
+	ReportCategoryAbuse   ReportCategory = "abuse"
+	ReportCategoryMalware ReportCategory = "malware"
+	ReportCategoryIllegal ReportCategory = "illegal"
+	ReportCategoryOther   ReportCategory = "other"
+)
+
+// IsValid checks if the category is known
+func (c ReportCategory) IsValid() bool {
+	switch c {
+	case ReportCategorySpam, ReportCategoryAbuse, ReportCategoryMalware, ReportCategoryIllegal, ReportCategoryOther:
+		return true
+	}
+	return false
+}
+
+// ReportStatus is the state of an abuse report in the moderation queue
+type ReportStatus int
+
+const (
+	ReportStatusOpen ReportStatus = iota
+	ReportStatusResolved
+	ReportStatusDismissed
+)
+
+// String returns the API name of the status
+func (s ReportStatus) String() string {
+	switch s {
+	case ReportStatusResolved:
+		return "resolved"
+	case ReportStatusDismissed:
+		return "dismissed"
+	default:
+		return "open"
+	}
+}
+
+// ParseReportStatus returns the status with the given API name
+func ParseReportStatus(s string) (ReportStatus, bool) {
+	switch s {
+	case "open":
+		return ReportStatusOpen, true
+	case "resolved":
+		return ReportStatusResolved, true
+	case "dismissed":
+		return ReportStatusDismissed, true
+	}
+	return ReportStatusOpen, false
+}
+
+// ErrReportNotExist represents a "ReportNotExist" kind of error.
+type ErrReportNotExist struct {
+	ID int64
+}
+
+// IsErrReportNotExist checks if an error is a ErrReportNotExist.
+func IsErrReportNotExist(err error) bool {
+	_, ok := err.(ErrReportNotExist)
+	return ok
+}
+
+func (err ErrReportNotExist) Error() string {
+	return fmt.Sprintf("abuse report does not exist [id: %d]", err.ID)
+}
+
+func (err ErrReportNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// ErrReportAlreadyExist represents a "ReportAlreadyExist" kind of error.
+type ErrReportAlreadyExist struct {
+	ReporterID  int64
+	ContentType ReportContentType
+	ContentID   int64
+}
+
+// IsErrReportAlreadyExist checks if an error is a ErrReportAlreadyExist.
+func IsErrReportAlreadyExist(err error) bool {
+	_, ok := err.(ErrReportAlreadyExist)
+	return ok
+}
+
+func (err ErrReportAlreadyExist) Error() string {
+	return fmt.Sprintf("an open abuse report already exists [reporter_id: %d, content: %s %d]", err.ReporterID, err.ContentType, err.ContentID)
+}
+
+func (err ErrReportAlreadyExist) Unwrap() error {
+	return util.ErrAlreadyExist
+}
+
+// AbuseReport is a report of abusive content waiting for or handled by a site administrator
+type AbuseReport struct {
+	ID          int64             `xorm:"pk autoincr"`
+	ReporterID  int64             `xorm:"INDEX NOT NULL"`
+	ContentType ReportContentType `xorm:"VARCHAR(20) INDEX(content) NOT NULL"`
+	ContentID   int64             `xorm:"INDEX(content) NOT NULL"`
+	Category    ReportCategory    `xorm:"VARCHAR(20) NOT NULL"`
+	Remarks     string            `xorm:"TEXT"`
+	Status      ReportStatus      `xorm:"INDEX NOT NULL DEFAULT 0"`
+	HandledByID int64             `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(AbuseReport))
+}
+
+// CreateReport files a new abuse report, a reporter may only have one open report per content
+func CreateReport(ctx context.Context, report *AbuseReport) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		has, err := db.GetEngine(ctx).Where(builder.Eq{
+			"reporter_id":  report.ReporterID,
+			"content_type": report.ContentType,
+			"content_id":   report.ContentID,
+			"status":       ReportStatusOpen,
+		}).Exist(new(AbuseReport))
+		if err != nil {
+			return err
+		} else if has {
+			return ErrReportAlreadyExist{ReporterID: report.ReporterID, ContentType: report.ContentType, ContentID: report.ContentID}
+		}
+		report.Status = ReportStatusOpen
+		return db.Insert(ctx, report)
+	})
+}
+
+// GetReportByID returns the abuse report with the given id
+func GetReportByID(ctx context.Context, id int64) (*AbuseReport, error) {
+	report := &AbuseReport{}
+	has, err := db.GetEngine(ctx).ID(id).Get(report)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrReportNotExist{ID: id}
+	}
+	return report, nil
+}
+
+// UpdateReportStatus records how a report was handled
+func UpdateReportStatus(ctx context.Context, report *AbuseReport, status ReportStatus, doerID int64) error {
+	report.Status = status
+	report.HandledByID = doerID
+	if status == ReportStatusOpen {
+		report.HandledByID = 0
+	}
+	_, err := db.GetEngine(ctx).ID(report.ID).Cols("status", "handled_by_id").Update(report)
+	return err
+}
+
+// FindReportOptions represents the options to search abuse reports
+type FindReportOptions struct {
+	db.ListOptions
+	Statuses    []ReportStatus
+	ContentType ReportContentType
+	ContentID   int64
+	ReporterID  int64
+}
+
+// ToConds implements db.FindOptions
+func (opts FindReportOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if len(opts.Statuses) > 0 {
+		cond = cond.And(builder.In("status", opts.Statuses))
+	}
+	if opts.ContentType != "" {
+		cond = cond.And(builder.Eq{"content_type": opts.ContentType})
+	}
+	if opts.ContentID > 0 {
+		cond = cond.And(builder.Eq{"content_id": opts.ContentID})
+	}
+	if opts.ReporterID > 0 {
+		cond = cond.And(builder.Eq{"reporter_id": opts.ReporterID})
+	}
+	return cond
+}
+
+// FindReports returns the abuse reports matching the options, oldest first so the queue is handled in order
+func FindReports(ctx context.Context, opts FindReportOptions) ([]*AbuseReport, int64, error) {
+	sess := db.GetEngine(ctx).Where(opts.ToConds()).OrderBy("id ASC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts.ListOptions)
+	}
+	reports := make([]*AbuseReport, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&reports)
+	return reports, count, err
+}
@@ -241,6 +241,18 @@ func NewTeam(t *organization.Team) (err error) {
 		return err
 	}
 
+	if err = t.ValidateCodePaths(); err != nil {
+		return err
+	}
+
+	if err = t.ValidateMentionSettings(); err != nil {
+		return err
+	}
+
+	if err = t.ValidateReviewAssignSettings(); err != nil {
+		return err
+	}
+
 	has, err := db.GetEngine(db.DefaultContext).ID(t.OrgID).Get(new(user_model.User))
 	if err != nil {
 		return err
@@ -306,6 +318,18 @@ func UpdateTeam(t *organization.Team, authChanged, includeAllChanged bool) (err
 		t.Description = t.Description[:255]
 	}
 
+	if err = t.ValidateCodePaths(); err != nil {
+		return err
+	}
+
+	if err = t.ValidateMentionSettings(); err != nil {
+		return err
+	}
+
+	if err = t.ValidateReviewAssignSettings(); err != nil {
+		return err
+	}
+
 	ctx, committer, err := db.TxContext(db.DefaultContext)
 	if err != nil {
 		return err
@@ -326,7 +350,8 @@ func UpdateTeam(t *organization.Team, authChanged, includeAllChanged bool) (err
 	}
 
 	if _, err = sess.ID(t.ID).Cols("name", "lower_name", "description",
-		"can_create_org_repo", "authorize", "includes_all_repositories").Update(t); err != nil {
+		"can_create_org_repo", "authorize", "includes_all_repositories", "code_paths",
+		"mention_mode", "mention_webhook_type", "mention_webhook_url", "mention_rate_limit", "review_assign_count").Update(t); err != nil {
 		return fmt.Errorf("update: %w", err)
 	}
 
@@ -414,6 +439,11 @@ func DeleteTeam(t *organization.Team) error {
 		&organization.TeamUser{OrgID: t.OrgID, TeamID: t.ID},
 		&organization.TeamUnit{TeamID: t.ID},
 		&organization.TeamInvite{TeamID: t.ID},
+		&organization.TeamReminder{TeamID: t.ID},
+		&organization.TeamMention{TeamID: t.ID},
+		&organization.TeamGroupMapping{TeamID: t.ID},
+		&organization.TeamHomepage{TeamID: t.ID},
+		&organization.TeamPinnedRepo{TeamID: t.ID},
 		&issues_model.Review{Type: issues_model.ReviewTypeRequest, ReviewerTeamID: t.ID}, // batch delete the binding relationship between team and PR (request review from team)
 	); err != nil {
 		return err
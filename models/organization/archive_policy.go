@@ -0,0 +1,85 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ArchivePolicy defines when inactive repositories of an organization are archived automatically,
+// it replaces the instance policy of the archive_inactive_repos cron task
+type ArchivePolicy struct {
+	ID    int64 `xorm:"pk autoincr"`
+	OrgID int64 `xorm:"UNIQUE NOT NULL"`
+	// InactiveMonths without pushes or issue activity before a repository is archived, 0 disables archiving
+	InactiveMonths int `xorm:"NOT NULL DEFAULT 0"`
+	// NoticeDays between notifying the owners and archiving the repository
+	NoticeDays int `xorm:"NOT NULL DEFAULT 0"`
+	// ExemptTopics are the topics which exempt a repository from being archived
+	ExemptTopics []string           `xorm:"JSON TEXT"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ArchivePolicy))
+}
+
+func (p *ArchivePolicy) validate() error {
+	if p.InactiveMonths < 0 {
+		return util.NewInvalidArgumentErrorf("inactive months must not be negative")
+	}
+	if p.InactiveMonths > 0 && p.NoticeDays <= 0 {
+		return util.NewInvalidArgumentErrorf("notice days must be positive")
+	}
+	topics, invalid := repo_model.SanitizeAndValidateTopics(p.ExemptTopics)
+	if len(invalid) > 0 {
+		return util.NewInvalidArgumentErrorf("invalid exempt topics: %s", strings.Join(invalid, ", "))
+	}
+	p.ExemptTopics = topics
+	return nil
+}
+
+// GetArchivePolicy returns the archive policy of an organization, or nil if none is defined
+func GetArchivePolicy(ctx context.Context, orgID int64) (*ArchivePolicy, error) {
+	p := &ArchivePolicy{}
+	has, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// SetArchivePolicy validates and creates or updates the archive policy of an organization
+func SetArchivePolicy(ctx context.Context, p *ArchivePolicy) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetArchivePolicy(ctx, p.OrgID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return db.Insert(ctx, p)
+		}
+		p.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(p.ID).Cols("inactive_months", "notice_days", "exempt_topics").Update(p)
+		return err
+	})
+}
+
+// DeleteArchivePolicy removes the archive policy of an organization
+func DeleteArchivePolicy(ctx context.Context, orgID int64) error {
+	_, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Delete(new(ArchivePolicy))
+	return err
+}
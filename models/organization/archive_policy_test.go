@@ -0,0 +1,41 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetArchivePolicy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	p, err := organization.GetArchivePolicy(db.DefaultContext, 3)
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	err = organization.SetArchivePolicy(db.DefaultContext, &organization.ArchivePolicy{OrgID: 3, InactiveMonths: 6})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	err = organization.SetArchivePolicy(db.DefaultContext, &organization.ArchivePolicy{OrgID: 3, InactiveMonths: 6, NoticeDays: 14, ExemptTopics: []string{"not a topic"}})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	assert.NoError(t, organization.SetArchivePolicy(db.DefaultContext, &organization.ArchivePolicy{OrgID: 3, InactiveMonths: 6, NoticeDays: 14}))
+	assert.NoError(t, organization.SetArchivePolicy(db.DefaultContext, &organization.ArchivePolicy{OrgID: 3, InactiveMonths: 12, NoticeDays: 30, ExemptTopics: []string{" Keep ", "keep", "reference"}}))
+
+	p, err = organization.GetArchivePolicy(db.DefaultContext, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, 12, p.InactiveMonths)
+	assert.Equal(t, 30, p.NoticeDays)
+	assert.Equal(t, []string{"keep", "reference"}, p.ExemptTopics)
+	unittest.AssertCount(t, &organization.ArchivePolicy{OrgID: 3}, 1)
+
+	assert.NoError(t, organization.DeleteArchivePolicy(db.DefaultContext, 3))
+	unittest.AssertNotExistsBean(t, &organization.ArchivePolicy{OrgID: 3})
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// AttachmentTypeRule allows a file type to be attached, optionally with a lower size limit.
+// Type uses the syntax of the ALLOWED_TYPES setting: an extension like ".pdf", a mime type
+// like "application/zip" or a wildcard like "image/*".
+type AttachmentTypeRule struct {
+	Type    string `json:"type"`
+	MaxSize int64  `json:"max_size"` // in bytes, 0 to only apply the global limit
+}
+
+// AttachmentPolicy restricts the attachments uploaded to the repositories of an organization
+type AttachmentPolicy struct {
+	ID    int64                 `xorm:"pk autoincr"`
+	OrgID int64                 `xorm:"UNIQUE NOT NULL"`
+	Rules []*AttachmentTypeRule `xorm:"JSON TEXT"` // if empty, only the global settings apply
+
+	// OptimizeImages recompresses uploaded images and downscales them to MaxImageDimension
+	OptimizeImages    bool `xorm:"NOT NULL DEFAULT false"`
+	MaxImageDimension int  `xorm:"NOT NULL DEFAULT 0"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(AttachmentPolicy))
+}
+
+func (p *AttachmentPolicy) validate() error {
+	for _, rule := range p.Rules {
+		rule.Type = strings.ToLower(strings.TrimSpace(rule.Type))
+		if rule.Type == "" {
+			return util.NewInvalidArgumentErrorf("attachment type must not be empty")
+		}
+		if !strings.HasPrefix(rule.Type, ".") && !strings.Contains(rule.Type, "/") {
+			return util.NewInvalidArgumentErrorf("attachment type %q is neither an extension nor a mime type", rule.Type)
+		}
+		if rule.MaxSize < 0 {
+			return util.NewInvalidArgumentErrorf("maximum size of attachment type %q must not be negative", rule.Type)
+		}
+	}
+	if p.MaxImageDimension < 0 {
+		return util.NewInvalidArgumentErrorf("maximum image dimension must not be negative")
+	}
+	return nil
+}
+
+// GetAttachmentPolicy returns the attachment policy of an organization, or nil if none is defined
+func GetAttachmentPolicy(ctx context.Context, orgID int64) (*AttachmentPolicy, error) {
+	p := &AttachmentPolicy{}
+	has, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// SetAttachmentPolicy validates and creates or updates the attachment policy of an organization
+func SetAttachmentPolicy(ctx context.Context, p *AttachmentPolicy) error {
+	if err := p.validate(); err != nil {
+		return err
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetAttachmentPolicy(ctx, p.OrgID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return db.Insert(ctx, p)
+		}
+		p.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(p.ID).Cols("rules", "optimize_images", "max_image_dimension").Update(p)
+		return err
+	})
+}
+
+// DeleteAttachmentPolicy removes the attachment policy of an organization
+func DeleteAttachmentPolicy(ctx context.Context, orgID int64) error {
+	_, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Delete(new(AttachmentPolicy))
+	return err
+}
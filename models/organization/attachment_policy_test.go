@@ -0,0 +1,53 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetAttachmentPolicy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	p, err := organization.GetAttachmentPolicy(db.DefaultContext, 3)
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	err = organization.SetAttachmentPolicy(db.DefaultContext, &organization.AttachmentPolicy{
+		OrgID: 3,
+		Rules: []*organization.AttachmentTypeRule{{Type: "pdf"}},
+	})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	assert.NoError(t, organization.SetAttachmentPolicy(db.DefaultContext, &organization.AttachmentPolicy{
+		OrgID: 3,
+		Rules: []*organization.AttachmentTypeRule{{Type: ".PDF"}},
+	}))
+	assert.NoError(t, organization.SetAttachmentPolicy(db.DefaultContext, &organization.AttachmentPolicy{
+		OrgID:             3,
+		Rules:             []*organization.AttachmentTypeRule{{Type: " image/* ", MaxSize: 1024}},
+		OptimizeImages:    true,
+		MaxImageDimension: 2048,
+	}))
+
+	p, err = organization.GetAttachmentPolicy(db.DefaultContext, 3)
+	assert.NoError(t, err)
+	if assert.Len(t, p.Rules, 1) {
+		assert.Equal(t, "image/*", p.Rules[0].Type)
+		assert.EqualValues(t, 1024, p.Rules[0].MaxSize)
+	}
+	assert.True(t, p.OptimizeImages)
+	assert.Equal(t, 2048, p.MaxImageDimension)
+	unittest.AssertCount(t, &organization.AttachmentPolicy{OrgID: 3}, 1)
+
+	assert.NoError(t, organization.DeleteAttachmentPolicy(db.DefaultContext, 3))
+	unittest.AssertNotExistsBean(t, &organization.AttachmentPolicy{OrgID: 3})
+}
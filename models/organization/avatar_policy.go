@@ -0,0 +1,59 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// AvatarPolicy defines how user avatars are shown on the pages of an organization and its repositories
+type AvatarPolicy struct {
+	ID    int64 `xorm:"pk autoincr"`
+	OrgID int64 `xorm:"UNIQUE NOT NULL"`
+	// EnforceDefault shows every user with the default avatar, e.g. so screenshots can be shared for compliance
+	EnforceDefault bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(AvatarPolicy))
+}
+
+// GetAvatarPolicy returns the avatar policy of an organization, or nil if none is defined
+func GetAvatarPolicy(ctx context.Context, orgID int64) (*AvatarPolicy, error) {
+	p := &AvatarPolicy{}
+	has, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// SetAvatarPolicy creates or updates the avatar policy of an organization
+func SetAvatarPolicy(ctx context.Context, p *AvatarPolicy) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetAvatarPolicy(ctx, p.OrgID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return db.Insert(ctx, p)
+		}
+		p.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(p.ID).Cols("enforce_default").Update(p)
+		return err
+	})
+}
+
+// DeleteAvatarPolicy removes the avatar policy of an organization
+func DeleteAvatarPolicy(ctx context.Context, orgID int64) error {
+	_, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Delete(new(AvatarPolicy))
+	return err
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// Branding defines the theme and the accent color the pages of an organization and its repositories are rendered with
+type Branding struct {
+	ID      int64               `xorm:"pk autoincr"`
+	OrgID   int64               `xorm:"UNIQUE NOT NULL"`
+	ThemeID int64               `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Theme   *system_model.Theme `xorm:"-"`
+	// AccentColor overrides the primary color of the theme, empty to keep it
+	AccentColor string             `xorm:"VARCHAR(7) NOT NULL DEFAULT ''"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(Branding))
+}
+
+// LoadTheme loads the theme of the branding, if it has one
+func (b *Branding) LoadTheme(ctx context.Context) (err error) {
+	if b.Theme != nil || b.ThemeID == 0 {
+		return nil
+	}
+	b.Theme, err = system_model.GetThemeByID(ctx, b.ThemeID)
+	return err
+}
+
+// GetBranding returns the branding of an organization, or nil if none is defined
+func GetBranding(ctx context.Context, orgID int64) (*Branding, error) {
+	b := &Branding{}
+	has, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Get(b)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return b, nil
+}
+
+// SetBranding creates or updates the branding of an organization
+func SetBranding(ctx context.Context, b *Branding) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetBranding(ctx, b.OrgID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return db.Insert(ctx, b)
+		}
+		b.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(b.ID).Cols("theme_id", "accent_color").Update(b)
+		return err
+	})
+}
+
+// DeleteBranding removes the branding of an organization
+func DeleteBranding(ctx context.Context, orgID int64) error {
+	_, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Delete(new(Branding))
+	return err
+}
+
+// DeleteBrandingsByThemeID removes the brandings using a theme
+func DeleteBrandingsByThemeID(ctx context.Context, themeID int64) error {
+	_, err := db.GetEngine(ctx).Where("theme_id = ?", themeID).Delete(new(Branding))
+	return err
+}
@@ -0,0 +1,165 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// LicensePolicyAction is the action to take when a repository violates the license policy
+type LicensePolicyAction string
+
+const (
+	// LicensePolicyActionAlert creates a notice for the site administrators and mails the admins of the organization
+	LicensePolicyActionAlert LicensePolicyAction = "alert"
+	// LicensePolicyActionBlock additionally prevents merging pull requests which introduce a violation
+	LicensePolicyActionBlock LicensePolicyAction = "block"
+)
+
+// IsValid checks if the action is known
+func (a LicensePolicyAction) IsValid() bool {
+	return a == LicensePolicyActionAlert || a == LicensePolicyActionBlock
+}
+
+// LicensePolicy defines which licenses may be used by the repositories of an organization
+type LicensePolicy struct {
+	ID          int64               `xorm:"pk autoincr"`
+	OrgID       int64               `xorm:"UNIQUE NOT NULL"`
+	Allowed     []string            `xorm:"JSON TEXT"`
+	Forbidden   []string            `xorm:"JSON TEXT"`
+	Action      LicensePolicyAction `xorm:"VARCHAR(20) NOT NULL DEFAULT 'alert'"`
+	CreatedUnix timeutil.TimeStamp  `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp  `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(LicensePolicy))
+}
+
+// BlocksMerge returns true if violations should prevent pull requests from being merged
+func (p *LicensePolicy) BlocksMerge() bool {
+	return p.Action == LicensePolicyActionBlock
+}
+
+// IsAllowed checks a single license against the policy.
+// A license is allowed if it is not forbidden and, when an allow list is set, it is part of it.
+func (p *LicensePolicy) IsAllowed(license string) bool {
+	for _, l := range p.Forbidden {
+		if strings.EqualFold(l, license) {
+			return false
+		}
+	}
+	if len(p.Allowed) == 0 {
+		return true
+	}
+	for _, l := range p.Allowed {
+		if strings.EqualFold(l, license) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsExpressionAllowed checks a SPDX license expression like `MIT OR (GPL-2.0-only WITH Classpath-exception-2.0)`
+// against the policy. Any of the alternatives of an OR must be allowed and all the licenses combined by an AND.
+func (p *LicensePolicy) IsExpressionAllowed(expression string) bool {
+	expression = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)
+	tokens := strings.Fields(expression)
+	if len(tokens) == 0 {
+		return true
+	}
+	allowed := p.allowsOr(&tokens)
+	return allowed && len(tokens) == 0
+}
+
+func (p *LicensePolicy) allowsOr(tokens *[]string) bool {
+	allowed := p.allowsAnd(tokens)
+	for len(*tokens) > 0 && strings.EqualFold((*tokens)[0], "OR") {
+		*tokens = (*tokens)[1:]
+		// both sides are parsed to consume all their tokens
+		other := p.allowsAnd(tokens)
+		allowed = allowed || other
+	}
+	return allowed
+}
+
+func (p *LicensePolicy) allowsAnd(tokens *[]string) bool {
+	allowed := p.allowsLicense(tokens)
+	for len(*tokens) > 0 && strings.EqualFold((*tokens)[0], "AND") {
+		*tokens = (*tokens)[1:]
+		other := p.allowsLicense(tokens)
+		allowed = allowed && other
+	}
+	return allowed
+}
+
+func (p *LicensePolicy) allowsLicense(tokens *[]string) bool {
+	if len(*tokens) == 0 {
+		return false
+	}
+	token := (*tokens)[0]
+	*tokens = (*tokens)[1:]
+	if token == "(" {
+		allowed := p.allowsOr(tokens)
+		if len(*tokens) == 0 || (*tokens)[0] != ")" {
+			return false
+		}
+		*tokens = (*tokens)[1:]
+		return allowed
+	}
+	// the exception of a license doesn't change whether the license is allowed
+	if len(*tokens) > 1 && strings.EqualFold((*tokens)[0], "WITH") {
+		*tokens = (*tokens)[2:]
+	}
+	return p.IsAllowed(token)
+}
+
+// Violations returns the licenses or license expressions which are not allowed by the policy
+func (p *LicensePolicy) Violations(licenses []string) []string {
+	var violations []string
+	for _, l := range licenses {
+		if !p.IsExpressionAllowed(l) {
+			violations = append(violations, l)
+		}
+	}
+	return violations
+}
+
+// GetLicensePolicy returns the license policy of an organization, or nil if none is defined
+func GetLicensePolicy(ctx context.Context, orgID int64) (*LicensePolicy, error) {
+	p := &LicensePolicy{}
+	has, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Get(p)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return p, nil
+}
+
+// SetLicensePolicy creates or updates the license policy of an organization
+func SetLicensePolicy(ctx context.Context, p *LicensePolicy) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetLicensePolicy(ctx, p.OrgID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return db.Insert(ctx, p)
+		}
+		p.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(p.ID).Cols("allowed", "forbidden", "action").Update(p)
+		return err
+	})
+}
+
+// DeleteLicensePolicy removes the license policy of an organization
+func DeleteLicensePolicy(ctx context.Context, orgID int64) error {
+	_, err := db.GetEngine(ctx).Where("org_id = ?", orgID).Delete(new(LicensePolicy))
+	return err
+}
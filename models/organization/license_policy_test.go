@@ -0,0 +1,68 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLicensePolicy_Violations(t *testing.T) {
+	p := &organization.LicensePolicy{Forbidden: []string{"AGPL-3.0-only"}}
+	assert.Empty(t, p.Violations([]string{"MIT", "Apache-2.0"}))
+	assert.Equal(t, []string{"agpl-3.0-only"}, p.Violations([]string{"MIT", "agpl-3.0-only"}))
+
+	p = &organization.LicensePolicy{Allowed: []string{"MIT", "Apache-2.0"}, Forbidden: []string{"MIT"}}
+	assert.Equal(t, []string{"MIT", "GPL-2.0-only"}, p.Violations([]string{"MIT", "Apache-2.0", "GPL-2.0-only"}))
+}
+
+func TestLicensePolicy_IsExpressionAllowed(t *testing.T) {
+	p := &organization.LicensePolicy{Forbidden: []string{"GPL-3.0-only", "AGPL-3.0-only"}}
+	assert.True(t, p.IsExpressionAllowed("MIT"))
+	assert.False(t, p.IsExpressionAllowed("GPL-3.0-only"))
+	assert.True(t, p.IsExpressionAllowed("(MIT OR GPL-3.0-only)"))
+	assert.False(t, p.IsExpressionAllowed("MIT AND GPL-3.0-only"))
+	assert.True(t, p.IsExpressionAllowed("MIT AND (Apache-2.0 OR GPL-3.0-only)"))
+	assert.False(t, p.IsExpressionAllowed("(GPL-3.0-only OR AGPL-3.0-only) AND MIT"))
+	assert.True(t, p.IsExpressionAllowed("GPL-2.0-only WITH Classpath-exception-2.0"))
+	assert.False(t, p.IsExpressionAllowed("GPL-3.0-only WITH GCC-exception-3.1"))
+	assert.False(t, p.IsExpressionAllowed("(MIT"))
+
+	p = &organization.LicensePolicy{Allowed: []string{"MIT"}}
+	assert.Equal(t, []string{"Apache-2.0 AND MIT"}, p.Violations([]string{"MIT OR Apache-2.0", "Apache-2.0 AND MIT"}))
+}
+
+func TestSetLicensePolicy(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	p, err := organization.GetLicensePolicy(db.DefaultContext, 3)
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	assert.NoError(t, organization.SetLicensePolicy(db.DefaultContext, &organization.LicensePolicy{
+		OrgID:     3,
+		Forbidden: []string{"GPL-3.0-only"},
+		Action:    organization.LicensePolicyActionAlert,
+	}))
+	assert.NoError(t, organization.SetLicensePolicy(db.DefaultContext, &organization.LicensePolicy{
+		OrgID:   3,
+		Allowed: []string{"MIT"},
+		Action:  organization.LicensePolicyActionBlock,
+	}))
+
+	p, err = organization.GetLicensePolicy(db.DefaultContext, 3)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"MIT"}, p.Allowed)
+	assert.Empty(t, p.Forbidden)
+	assert.True(t, p.BlocksMerge())
+	unittest.AssertCount(t, &organization.LicensePolicy{OrgID: 3}, 1)
+
+	assert.NoError(t, organization.DeleteLicensePolicy(db.DefaultContext, 3))
+	unittest.AssertNotExistsBean(t, &organization.LicensePolicy{OrgID: 3})
+}
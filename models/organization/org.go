@@ -10,6 +10,8 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
+	githook_model "code.gitea.io/gitea/models/githook"
+	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/perm"
 	repo_model "code.gitea.io/gitea/models/repo"
 	secret_model "code.gitea.io/gitea/models/secret"
@@ -287,6 +289,10 @@ func CreateOrganization(org *Organization, owner *user_model.User) (err error) {
 		return user_model.ErrUserAlreadyExist{Name: org.Name}
 	}
 
+	if err = user_model.CheckUserRedirectReserved(db.DefaultContext, 0, org.Name); err != nil {
+		return err
+	}
+
 	org.LowerName = strings.ToLower(org.Name)
 	if org.Rands, err = user_model.GetUserSalt(); err != nil {
 		return err
@@ -399,7 +405,21 @@ func DeleteOrganization(ctx context.Context, org *Organization) error {
 		&TeamUser{OrgID: org.ID},
 		&TeamUnit{OrgID: org.ID},
 		&TeamInvite{OrgID: org.ID},
+		&TeamReminder{OrgID: org.ID},
+		&TeamMention{OrgID: org.ID},
+		&TeamGroupMapping{OrgID: org.ID},
+		&TeamGroupSyncLog{OrgID: org.ID},
+		&LicensePolicy{OrgID: org.ID},
+		&AvatarPolicy{OrgID: org.ID},
+		&Branding{OrgID: org.ID},
+		&AttachmentPolicy{OrgID: org.ID},
+		&ArchivePolicy{OrgID: org.ID},
 		&secret_model.Secret{OwnerID: org.ID},
+		&githook_model.ManagedHook{OwnerID: org.ID},
+		&repo_model.StorageUsage{OwnerID: org.ID},
+		&repo_model.TemplateCatalogItem{OwnerID: org.ID},
+		&packages_model.PackageAdvisory{OwnerID: org.ID},
+		&user_model.Block{BlockerID: org.ID},
 	); err != nil {
 		return fmt.Errorf("DeleteBeans: %w", err)
 	}
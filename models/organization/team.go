@@ -84,6 +84,22 @@ type Team struct {
 	Units                   []*TeamUnit `xorm:"-"`
 	IncludesAllRepositories bool        `xorm:"NOT NULL DEFAULT false"`
 	CanCreateOrgRepo        bool        `xorm:"NOT NULL DEFAULT false"`
+	// CodePaths restricts the members to reading these directories and files of the code, empty for no restriction
+	CodePaths []string `xorm:"JSON TEXT"`
+	// MentionMode controls who is notified when the team is mentioned
+	MentionMode        TeamMentionMode `xorm:"VARCHAR(16) NOT NULL DEFAULT 'all'"`
+	MentionWebhookType string          `xorm:"VARCHAR(16)"`
+	MentionWebhookURL  string          `xorm:"TEXT"`
+	// MentionRateLimit is the number of mentions per hour which notify the team, 0 for no limit
+	MentionRateLimit int `xorm:"NOT NULL DEFAULT 0"`
+	// GroupSyncPolicy decides the membership of a user if the sources of the groups mapped to the team disagree
+	GroupSyncPolicy TeamGroupSyncPolicy `xorm:"VARCHAR(16) NOT NULL DEFAULT 'any'"`
+	// GroupSyncRemoval removes the members which aren't in the mapped groups, otherwise the group sync only adds members
+	GroupSyncRemoval bool `xorm:"NOT NULL DEFAULT false"`
+	// ReviewAssignCount is the number of members who are requested to review when the team is, 0 to request the whole team
+	ReviewAssignCount int `xorm:"NOT NULL DEFAULT 0"`
+	// ReviewAssignLastUserID is the member who was requested to review last, the next requests go to the members after them
+	ReviewAssignLastUserID int64 `xorm:"NOT NULL DEFAULT 0"`
 }
 
 func init() {
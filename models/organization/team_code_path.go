@@ -0,0 +1,53 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization
+
+import (
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// IsCodePathScoped returns true if the members of the team may only read some paths of the code
+func (t *Team) IsCodePathScoped() bool {
+	return len(t.CodePaths) > 0
+}
+
+// NormalizeCodePaths cleans the code paths of a team, e.g. "/docs/" becomes "docs".
+// Empty entries are dropped and duplicate entries removed.
+func NormalizeCodePaths(paths []string) ([]string, error) {
+	normalized := make([]string, 0, len(paths))
+	for _, p := range paths {
+		p = strings.Trim(strings.TrimSpace(p), "/")
+		if p == "" {
+			continue
+		}
+		cleaned := path.Clean(p)
+		if cleaned == ".." || strings.HasPrefix(cleaned, "../") || cleaned != p {
+			return nil, util.NewInvalidArgumentErrorf("invalid code path %q", p)
+		}
+		if !util.SliceContainsString(normalized, cleaned) {
+			normalized = append(normalized, cleaned)
+		}
+	}
+	if len(normalized) == 0 {
+		return nil, nil
+	}
+	return normalized, nil
+}
+
+// ValidateCodePaths normalizes the code paths of the team, teams with administrative access can not be scoped
+func (t *Team) ValidateCodePaths() error {
+	paths, err := NormalizeCodePaths(t.CodePaths)
+	if err != nil {
+		return err
+	}
+	if len(paths) > 0 && t.AccessMode >= perm.AccessModeAdmin {
+		return util.NewInvalidArgumentErrorf("teams with administrator access can not be limited to code paths")
+	}
+	t.CodePaths = paths
+	return nil
+}
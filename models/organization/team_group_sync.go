@@ -0,0 +1,220 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ExternalGroupSourceAPI is the source of the external groups whose members are pushed through the API,
+// e.g. by a SCIM provisioning bridge, other sources are authentication sources
+const ExternalGroupSourceAPI int64 = 0
+
+// TeamGroupSyncPolicy decides the membership of a user if the sources of the groups mapped to a team disagree
+type TeamGroupSyncPolicy string
+
+const (
+	// TeamGroupSyncPolicyAny makes a user a member if any source has the user in a mapped group
+	TeamGroupSyncPolicyAny TeamGroupSyncPolicy = "any"
+	// TeamGroupSyncPolicyAll makes a user a member only if every source has the user in one of its mapped groups
+	TeamGroupSyncPolicyAll TeamGroupSyncPolicy = "all"
+)
+
+// IsValid returns true if the policy is known
+func (p TeamGroupSyncPolicy) IsValid() bool {
+	return p == TeamGroupSyncPolicyAny || p == TeamGroupSyncPolicyAll
+}
+
+// TeamGroupSyncAction is a change of the membership of a team made by the group sync
+type TeamGroupSyncAction string
+
+const (
+	// TeamGroupSyncActionAdd is the addition of a member
+	TeamGroupSyncActionAdd TeamGroupSyncAction = "add"
+	// TeamGroupSyncActionRemove is the removal of a member
+	TeamGroupSyncActionRemove TeamGroupSyncAction = "remove"
+)
+
+// TeamGroupMapping maps a group of an external source to a team, the members of the group become members of the team
+type TeamGroupMapping struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OrgID       int64              `xorm:"INDEX NOT NULL"`
+	TeamID      int64              `xorm:"UNIQUE(s) NOT NULL"`
+	SourceID    int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	GroupName   string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// ExternalGroupMember is a member of a group of an external source, as last reported by the source
+type ExternalGroupMember struct {
+	ID          int64              `xorm:"pk autoincr"`
+	SourceID    int64              `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	GroupName   string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+	UserID      int64              `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// TeamGroupSyncLog records a change of the membership of a team made by the group sync
+type TeamGroupSyncLog struct {
+	ID     int64               `xorm:"pk autoincr"`
+	OrgID  int64               `xorm:"INDEX NOT NULL"`
+	TeamID int64               `xorm:"INDEX NOT NULL"`
+	UserID int64               `xorm:"NOT NULL"`
+	Action TeamGroupSyncAction `xorm:"VARCHAR(16) NOT NULL"`
+	// Reason lists the mapped groups which contain the user, or why the user was removed
+	Reason      string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func init() {
+	db.RegisterModel(new(TeamGroupMapping))
+	db.RegisterModel(new(ExternalGroupMember))
+	db.RegisterModel(new(TeamGroupSyncLog))
+}
+
+// ValidateGroupSyncSettings validates how the members of the team are synced from external groups, an empty policy defaults to any
+func (t *Team) ValidateGroupSyncSettings() error {
+	if t.GroupSyncPolicy == "" {
+		t.GroupSyncPolicy = TeamGroupSyncPolicyAny
+	}
+	if !t.GroupSyncPolicy.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid group sync policy %q", t.GroupSyncPolicy)
+	}
+	return nil
+}
+
+// FindTeamGroupMappings returns the external groups mapped to a team
+func FindTeamGroupMappings(ctx context.Context, teamID int64) ([]*TeamGroupMapping, error) {
+	mappings := make([]*TeamGroupMapping, 0, 5)
+	return mappings, db.GetEngine(ctx).Where("team_id = ?", teamID).Asc("source_id").Asc("group_name").Find(&mappings)
+}
+
+// FindTeamIDsWithGroupMappings returns the ids of all teams which have external groups mapped to them
+func FindTeamIDsWithGroupMappings(ctx context.Context) ([]int64, error) {
+	teamIDs := make([]int64, 0, 10)
+	return teamIDs, db.GetEngine(ctx).Table("team_group_mapping").Distinct("team_id").Asc("team_id").Find(&teamIDs)
+}
+
+// ReplaceTeamGroupMappings replaces the external groups mapped to a team and its group sync settings
+func ReplaceTeamGroupMappings(ctx context.Context, team *Team, mappings []*TeamGroupMapping) error {
+	if err := team.ValidateGroupSyncSettings(); err != nil {
+		return err
+	}
+	type mappedGroup struct {
+		sourceID int64
+		name     string
+	}
+	seen := make(container.Set[mappedGroup], len(mappings))
+	for _, m := range mappings {
+		if m.GroupName == "" {
+			return util.NewInvalidArgumentErrorf("the name of a mapped group is empty")
+		}
+		if !seen.Add(mappedGroup{sourceID: m.SourceID, name: m.GroupName}) {
+			return util.NewInvalidArgumentErrorf("group %q is mapped twice", m.GroupName)
+		}
+		m.ID = 0
+		m.OrgID = team.OrgID
+		m.TeamID = team.ID
+	}
+
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).ID(team.ID).Cols("group_sync_policy", "group_sync_removal").Update(team); err != nil {
+			return err
+		}
+		if _, err := db.GetEngine(ctx).Delete(&TeamGroupMapping{TeamID: team.ID}); err != nil {
+			return err
+		}
+		if len(mappings) == 0 {
+			return nil
+		}
+		return db.Insert(ctx, mappings)
+	})
+}
+
+// ReplaceExternalGroupsOfUser replaces the groups of a source which contain a user, it's used by the sources which
+// report the groups of a user when the user signs in or is synchronized
+func ReplaceExternalGroupsOfUser(ctx context.Context, sourceID, userID int64, groupNames []string) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("source_id = ? AND user_id = ?", sourceID, userID).Delete(new(ExternalGroupMember)); err != nil {
+			return err
+		}
+		members := make([]*ExternalGroupMember, 0, len(groupNames))
+		for _, name := range container.SetOf(groupNames...).Values() {
+			if name == "" {
+				continue
+			}
+			members = append(members, &ExternalGroupMember{SourceID: sourceID, GroupName: name, UserID: userID})
+		}
+		if len(members) == 0 {
+			return nil
+		}
+		return db.Insert(ctx, members)
+	})
+}
+
+// ReplaceExternalGroupMembers replaces the members of a group of a source, it's used by the sources which report
+// the members of a group
+func ReplaceExternalGroupMembers(ctx context.Context, sourceID int64, groupName string, userIDs []int64) error {
+	if groupName == "" {
+		return util.NewInvalidArgumentErrorf("the name of the group is empty")
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("source_id = ? AND group_name = ?", sourceID, groupName).Delete(new(ExternalGroupMember)); err != nil {
+			return err
+		}
+		members := make([]*ExternalGroupMember, 0, len(userIDs))
+		for _, userID := range container.SetOf(userIDs...).Values() {
+			members = append(members, &ExternalGroupMember{SourceID: sourceID, GroupName: groupName, UserID: userID})
+		}
+		if len(members) == 0 {
+			return nil
+		}
+		return db.Insert(ctx, members)
+	})
+}
+
+// FindExternalGroupMembers returns the members of the given groups of a source
+func FindExternalGroupMembers(ctx context.Context, sourceID int64, groupNames []string) ([]*ExternalGroupMember, error) {
+	members := make([]*ExternalGroupMember, 0, 10)
+	return members, db.GetEngine(ctx).
+		Where(builder.Eq{"source_id": sourceID}.And(builder.In("group_name", groupNames))).
+		Asc("id").
+		Find(&members)
+}
+
+// FindTeamGroupSyncLogsOptions are the options to find the changes made by the group sync
+type FindTeamGroupSyncLogsOptions struct {
+	db.ListOptions
+	OrgID  int64
+	TeamID int64
+}
+
+func (opts FindTeamGroupSyncLogsOptions) toConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.OrgID > 0 {
+		cond = cond.And(builder.Eq{"org_id": opts.OrgID})
+	}
+	if opts.TeamID > 0 {
+		cond = cond.And(builder.Eq{"team_id": opts.TeamID})
+	}
+	return cond
+}
+
+// FindTeamGroupSyncLogs returns the changes made by the group sync, the most recent first
+func FindTeamGroupSyncLogs(ctx context.Context, opts FindTeamGroupSyncLogsOptions) ([]*TeamGroupSyncLog, int64, error) {
+	sess := db.GetEngine(ctx).Where(opts.toConds()).Desc("id")
+	if opts.Page > 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+	logs := make([]*TeamGroupSyncLog, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&logs)
+	return logs, count, err
+}
@@ -0,0 +1,122 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// MaxTeamPinnedRepos is the maximum number of repositories a team can pin on its homepage
+const MaxTeamPinnedRepos = 6
+
+// TeamHomepage is the landing page maintained by a team: a README describing the team
+type TeamHomepage struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OrgID       int64              `xorm:"INDEX NOT NULL"`
+	TeamID      int64              `xorm:"UNIQUE NOT NULL"`
+	Readme      string             `xorm:"LONGTEXT"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// TeamPinnedRepo is a repository of the organization pinned on the homepage of a team
+type TeamPinnedRepo struct {
+	ID      int64 `xorm:"pk autoincr"`
+	OrgID   int64 `xorm:"INDEX NOT NULL"`
+	TeamID  int64 `xorm:"UNIQUE(s) NOT NULL"`
+	RepoID  int64 `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	Sorting int   `xorm:"NOT NULL DEFAULT 0"`
+}
+
+func init() {
+	db.RegisterModel(new(TeamHomepage))
+	db.RegisterModel(new(TeamPinnedRepo))
+}
+
+// IsTeamLead returns true if the user is one of the leads of the team
+func IsTeamLead(ctx context.Context, teamID, userID int64) (bool, error) {
+	return db.GetEngine(ctx).
+		Where("team_id = ? AND uid = ? AND is_lead = ?", teamID, userID, true).
+		Exist(new(TeamUser))
+}
+
+// GetTeamHomepage returns the homepage of the team, an empty one if the team hasn't written it yet
+func GetTeamHomepage(ctx context.Context, team *Team) (*TeamHomepage, error) {
+	h := &TeamHomepage{OrgID: team.OrgID, TeamID: team.ID}
+	if _, err := db.GetEngine(ctx).Where("team_id = ?", team.ID).Get(h); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// UpdateTeamReadme inserts or updates the README of the homepage of the team
+func UpdateTeamReadme(ctx context.Context, team *Team, readme string) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		h, err := GetTeamHomepage(ctx, team)
+		if err != nil {
+			return err
+		}
+		h.Readme = readme
+		if h.ID == 0 {
+			return db.Insert(ctx, h)
+		}
+		_, err = db.GetEngine(ctx).ID(h.ID).Cols("readme").Update(h)
+		return err
+	})
+}
+
+// GetTeamPinnedRepos returns the repositories pinned on the homepage of the team in their order
+func GetTeamPinnedRepos(ctx context.Context, teamID int64) ([]*repo_model.Repository, error) {
+	repos := make([]*repo_model.Repository, 0, MaxTeamPinnedRepos)
+	return repos, db.GetEngine(ctx).
+		Join("INNER", "team_pinned_repo", "team_pinned_repo.repo_id = repository.id").
+		Where("team_pinned_repo.team_id = ?", teamID).
+		OrderBy("team_pinned_repo.sorting, repository.name").
+		Find(&repos)
+}
+
+// SetTeamPinnedRepos replaces the repositories pinned on the homepage of the team,
+// which must all belong to the organization of the team
+func SetTeamPinnedRepos(ctx context.Context, team *Team, repoIDs []int64) error {
+	ids := make([]int64, 0, len(repoIDs))
+	for _, repoID := range repoIDs {
+		if !util.SliceContains(ids, repoID) {
+			ids = append(ids, repoID)
+		}
+	}
+	if len(ids) > MaxTeamPinnedRepos {
+		return util.NewInvalidArgumentErrorf("a team can pin at most %d repositories", MaxTeamPinnedRepos)
+	}
+
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("team_id = ?", team.ID).Delete(new(TeamPinnedRepo)); err != nil {
+			return err
+		}
+		if len(ids) == 0 {
+			return nil
+		}
+
+		count, err := db.GetEngine(ctx).
+			Where(builder.In("id", ids).And(builder.Eq{"owner_id": team.OrgID})).
+			Count(new(repo_model.Repository))
+		if err != nil {
+			return err
+		}
+		if count != int64(len(ids)) {
+			return util.NewInvalidArgumentErrorf("pinned repositories must belong to the organization")
+		}
+
+		pins := make([]*TeamPinnedRepo, 0, len(ids))
+		for i, repoID := range ids {
+			pins = append(pins, &TeamPinnedRepo{OrgID: team.OrgID, TeamID: team.ID, RepoID: repoID, Sorting: i})
+		}
+		return db.Insert(ctx, pins)
+	})
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamHomepage(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	team := unittest.AssertExistsAndLoadBean(t, &organization.Team{ID: 1})
+
+	homepage, err := organization.GetTeamHomepage(db.DefaultContext, team)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, homepage.ID)
+	assert.Empty(t, homepage.Readme)
+
+	assert.NoError(t, organization.UpdateTeamReadme(db.DefaultContext, team, "# Owners"))
+	assert.NoError(t, organization.UpdateTeamReadme(db.DefaultContext, team, "# The owners"))
+	homepage, err = organization.GetTeamHomepage(db.DefaultContext, team)
+	assert.NoError(t, err)
+	assert.Equal(t, "# The owners", homepage.Readme)
+	unittest.AssertCount(t, &organization.TeamHomepage{TeamID: team.ID}, 1)
+}
+
+func TestSetTeamPinnedRepos(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	team := unittest.AssertExistsAndLoadBean(t, &organization.Team{ID: 1})
+
+	assert.NoError(t, organization.SetTeamPinnedRepos(db.DefaultContext, team, []int64{5, 3, 5}))
+	repos, err := organization.GetTeamPinnedRepos(db.DefaultContext, team.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, repos, 2) {
+		assert.EqualValues(t, 5, repos[0].ID)
+		assert.EqualValues(t, 3, repos[1].ID)
+	}
+
+	// repository 1 belongs to another owner
+	assert.Error(t, organization.SetTeamPinnedRepos(db.DefaultContext, team, []int64{3, 1}))
+	assert.Error(t, organization.SetTeamPinnedRepos(db.DefaultContext, team, []int64{1, 2, 3, 4, 5, 6, 7}))
+	repos, err = organization.GetTeamPinnedRepos(db.DefaultContext, team.ID)
+	assert.NoError(t, err)
+	assert.Len(t, repos, 2)
+
+	assert.NoError(t, organization.SetTeamPinnedRepos(db.DefaultContext, team, nil))
+	repos, err = organization.GetTeamPinnedRepos(db.DefaultContext, team.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, repos)
+}
+
+func TestIsTeamLead(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	team := unittest.AssertExistsAndLoadBean(t, &organization.Team{ID: 1})
+	isLead, err := organization.IsTeamLead(db.DefaultContext, team.ID, 2)
+	assert.NoError(t, err)
+	assert.False(t, isLead)
+
+	assert.NoError(t, organization.SetTeamLead(db.DefaultContext, team, 2, true))
+	isLead, err = organization.IsTeamLead(db.DefaultContext, team.ID, 2)
+	assert.NoError(t, err)
+	assert.True(t, isLead)
+}
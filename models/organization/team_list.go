@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/models/perm"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/container"
 
 	"xorm.io/builder"
 )
@@ -126,3 +127,48 @@ func GetUserRepoTeams(ctx context.Context, orgID, userID, repoID int64) (teams T
 		And("team_repo.repo_id=?", repoID).
 		Find(&teams)
 }
+
+// GetUserReposTeams returns the teams of the user which have access to the repositories, keyed by repository ID.
+// The units of the teams are loaded.
+func GetUserReposTeams(ctx context.Context, userID int64, repoIDs []int64) (map[int64]TeamList, error) {
+	teamRepos := make([]*TeamRepo, 0, len(repoIDs))
+	if err := db.GetEngine(ctx).
+		Join("INNER", "team_user", "team_user.team_id = team_repo.team_id").
+		Where("team_user.uid=?", userID).
+		In("team_repo.repo_id", repoIDs).
+		Find(&teamRepos); err != nil {
+		return nil, err
+	}
+	if len(teamRepos) == 0 {
+		return map[int64]TeamList{}, nil
+	}
+
+	teamIDs := make(container.Set[int64])
+	for _, tr := range teamRepos {
+		teamIDs.Add(tr.TeamID)
+	}
+	teams := make(map[int64]*Team, len(teamIDs))
+	if err := db.GetEngine(ctx).In("id", teamIDs.Values()).Find(&teams); err != nil {
+		return nil, err
+	}
+	units := make([]*TeamUnit, 0, len(teams)*5)
+	if err := db.GetEngine(ctx).In("team_id", teamIDs.Values()).Find(&units); err != nil {
+		return nil, err
+	}
+	for _, team := range teams {
+		team.Units = []*TeamUnit{}
+	}
+	for _, u := range units {
+		if team, ok := teams[u.TeamID]; ok {
+			team.Units = append(team.Units, u)
+		}
+	}
+
+	reposTeams := make(map[int64]TeamList, len(repoIDs))
+	for _, tr := range teamRepos {
+		if team, ok := teams[tr.TeamID]; ok {
+			reposTeams[tr.RepoID] = append(reposTeams[tr.RepoID], team)
+		}
+	}
+	return reposTeams, nil
+}
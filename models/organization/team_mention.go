@@ -0,0 +1,138 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization
+
+import (
+	"context"
+	"net/url"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+
+	"xorm.io/builder"
+)
+
+// TeamMentionMode controls who is notified when a team is mentioned
+type TeamMentionMode string
+
+const (
+	// TeamMentionModeAll notifies all members of the team
+	TeamMentionModeAll TeamMentionMode = "all"
+	// TeamMentionModeLeads notifies only the leads of the team
+	TeamMentionModeLeads TeamMentionMode = "leads"
+	// TeamMentionModeWebhook posts the mention to the mention webhook of the team instead of notifying its members
+	TeamMentionModeWebhook TeamMentionMode = "webhook"
+)
+
+// teamMentionRatePeriod is the period over which the mention rate limit of a team applies
+const teamMentionRatePeriod = time.Hour
+
+// IsValid returns true if the mode is known
+func (m TeamMentionMode) IsValid() bool {
+	switch m {
+	case TeamMentionModeAll, TeamMentionModeLeads, TeamMentionModeWebhook:
+		return true
+	}
+	return false
+}
+
+// ValidateMentionSettings validates how the team is notified of mentions, an empty mode defaults to notifying all members
+func (t *Team) ValidateMentionSettings() error {
+	if t.MentionMode == "" {
+		t.MentionMode = TeamMentionModeAll
+	}
+	if !t.MentionMode.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid mention mode %q", t.MentionMode)
+	}
+	if t.MentionRateLimit < 0 {
+		return util.NewInvalidArgumentErrorf("mention rate limit must not be negative")
+	}
+	if t.MentionMode != TeamMentionModeWebhook {
+		return nil
+	}
+	switch t.MentionWebhookType {
+	case webhook_module.GITEA, webhook_module.SLACK, webhook_module.DISCORD, webhook_module.MSTEAMS:
+	default:
+		return util.NewInvalidArgumentErrorf("invalid mention webhook type %q", t.MentionWebhookType)
+	}
+	if u, err := url.Parse(t.MentionWebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") {
+		return util.NewInvalidArgumentErrorf("invalid mention webhook url")
+	}
+	return nil
+}
+
+// TeamMention records a mention of a team which notified it, to rate limit the mentions of the team
+type TeamMention struct {
+	ID          int64              `xorm:"pk autoincr"`
+	OrgID       int64              `xorm:"INDEX NOT NULL"`
+	TeamID      int64              `xorm:"INDEX NOT NULL"`
+	IssueID     int64              `xorm:"NOT NULL"`
+	DoerID      int64              `xorm:"NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+}
+
+func init() {
+	db.RegisterModel(new(TeamMention))
+}
+
+// AllowTeamMention returns if a mention of the team by the doer notifies the team, and records the mention if it does.
+// Mentions beyond the rate limit of the team within an hour are ignored.
+func AllowTeamMention(ctx context.Context, team *Team, issueID, doerID int64) (bool, error) {
+	if team.MentionRateLimit <= 0 {
+		return true, nil
+	}
+
+	allowed := false
+	err := db.WithTx(ctx, func(ctx context.Context) error {
+		since := timeutil.TimeStampNow().AddDuration(-teamMentionRatePeriod)
+		if _, err := db.GetEngine(ctx).Where(builder.Eq{"team_id": team.ID}.And(builder.Lt{"created_unix": since})).Delete(new(TeamMention)); err != nil {
+			return err
+		}
+		count, err := db.GetEngine(ctx).Where("team_id = ?", team.ID).Count(new(TeamMention))
+		if err != nil {
+			return err
+		}
+		if count >= int64(team.MentionRateLimit) {
+			return nil
+		}
+		allowed = true
+		return db.Insert(ctx, &TeamMention{OrgID: team.OrgID, TeamID: team.ID, IssueID: issueID, DoerID: doerID})
+	})
+	return allowed, err
+}
+
+// GetTeamLeads returns the leads of the team
+func GetTeamLeads(ctx context.Context, teamID int64) ([]*user_model.User, error) {
+	leads := make([]*user_model.User, 0, 5)
+	return leads, db.GetEngine(ctx).
+		Join("INNER", "team_user", "team_user.uid = `user`.id").
+		Where("team_user.team_id = ? AND team_user.is_lead = ?", teamID, true).
+		OrderBy("`user`.name").
+		Find(&leads)
+}
+
+// SetTeamLead makes a member of the team one of its leads or removes it from them
+func SetTeamLead(ctx context.Context, team *Team, userID int64, isLead bool) error {
+	n, err := db.GetEngine(ctx).
+		Where("team_id = ? AND uid = ?", team.ID, userID).
+		Cols("is_lead").
+		Update(&TeamUser{IsLead: isLead})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		isMember, err := IsTeamMember(ctx, team.OrgID, team.ID, userID)
+		if err != nil {
+			return err
+		}
+		if !isMember {
+			return util.NewNotExistErrorf("user %d is not a member of team %d", userID, team.ID)
+		}
+	}
+	return nil
+}
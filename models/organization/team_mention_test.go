@@ -0,0 +1,77 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateMentionSettings(t *testing.T) {
+	team := &organization.Team{}
+	assert.NoError(t, team.ValidateMentionSettings())
+	assert.Equal(t, organization.TeamMentionModeAll, team.MentionMode)
+
+	team = &organization.Team{MentionMode: "everyone"}
+	assert.ErrorIs(t, team.ValidateMentionSettings(), util.ErrInvalidArgument)
+
+	team = &organization.Team{MentionMode: organization.TeamMentionModeWebhook, MentionWebhookType: "slack"}
+	assert.ErrorIs(t, team.ValidateMentionSettings(), util.ErrInvalidArgument)
+
+	team.MentionWebhookURL = "https://example.com/hook"
+	assert.NoError(t, team.ValidateMentionSettings())
+
+	team.MentionRateLimit = -1
+	assert.ErrorIs(t, team.ValidateMentionSettings(), util.ErrInvalidArgument)
+}
+
+func TestAllowTeamMention(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	team := unittest.AssertExistsAndLoadBean(t, &organization.Team{ID: 2})
+	for i := 0; i < 3; i++ {
+		allowed, err := organization.AllowTeamMention(db.DefaultContext, team, 1, 2)
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	}
+	unittest.AssertCount(t, &organization.TeamMention{TeamID: team.ID}, 0)
+
+	team.MentionRateLimit = 2
+	for i := 0; i < 2; i++ {
+		allowed, err := organization.AllowTeamMention(db.DefaultContext, team, 1, 2)
+		assert.NoError(t, err)
+		assert.True(t, allowed)
+	}
+	allowed, err := organization.AllowTeamMention(db.DefaultContext, team, 1, 2)
+	assert.NoError(t, err)
+	assert.False(t, allowed)
+	unittest.AssertCount(t, &organization.TeamMention{TeamID: team.ID}, 2)
+}
+
+func TestSetTeamLead(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	team := unittest.AssertExistsAndLoadBean(t, &organization.Team{ID: 2})
+	assert.NoError(t, organization.SetTeamLead(db.DefaultContext, team, 4, true))
+	leads, err := organization.GetTeamLeads(db.DefaultContext, team.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, leads, 1) {
+		assert.EqualValues(t, 4, leads[0].ID)
+	}
+
+	// setting the same value again is not an error
+	assert.NoError(t, organization.SetTeamLead(db.DefaultContext, team, 4, true))
+	assert.ErrorIs(t, organization.SetTeamLead(db.DefaultContext, team, 5, true), util.ErrNotExist)
+
+	assert.NoError(t, organization.SetTeamLead(db.DefaultContext, team, 4, false))
+	leads, err = organization.GetTeamLeads(db.DefaultContext, team.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, leads)
+}
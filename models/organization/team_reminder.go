@@ -0,0 +1,158 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ErrTeamReminderNotExist represents a "TeamReminderNotExist" kind of error.
+type ErrTeamReminderNotExist struct {
+	ID int64
+}
+
+// IsErrTeamReminderNotExist checks if an error is a ErrTeamReminderNotExist.
+func IsErrTeamReminderNotExist(err error) bool {
+	_, ok := err.(ErrTeamReminderNotExist)
+	return ok
+}
+
+func (err ErrTeamReminderNotExist) Error() string {
+	return fmt.Sprintf("team reminder does not exist [id: %d]", err.ID)
+}
+
+func (err ErrTeamReminderNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// TeamReminder periodically posts a digest of the pull requests awaiting review by a team,
+// its stale pull requests and its repositories with failing checks on the default branch
+// to a chat webhook
+type TeamReminder struct {
+	ID          int64  `xorm:"pk autoincr"`
+	OrgID       int64  `xorm:"INDEX NOT NULL"`
+	TeamID      int64  `xorm:"INDEX NOT NULL"`
+	Schedule    string `xorm:"VARCHAR(100) NOT NULL"` // standard 5 field cron expression
+	WebhookType string `xorm:"VARCHAR(16) NOT NULL"`
+	WebhookURL  string `xorm:"TEXT NOT NULL"`
+	IsActive    bool   `xorm:"INDEX NOT NULL DEFAULT true"`
+
+	// Filters of what the digest contains
+	IncludeReviewRequests bool `xorm:"NOT NULL DEFAULT true"`
+	IncludeStalePulls     bool `xorm:"NOT NULL DEFAULT true"`
+	StaleDays             int  `xorm:"NOT NULL DEFAULT 7"`
+	IncludeFailingChecks  bool `xorm:"NOT NULL DEFAULT true"`
+	IgnoreWorkInProgress  bool `xorm:"NOT NULL DEFAULT false"`
+
+	LastRunUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	NextRunUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(TeamReminder))
+}
+
+// ScheduleNext validates the schedule of the reminder and computes its next run after the given time
+func (r *TeamReminder) ScheduleNext(after time.Time) error {
+	schedule, err := timeutil.ParseCronSchedule(r.Schedule)
+	if err != nil {
+		return util.NewInvalidArgumentErrorf("invalid schedule: %v", err)
+	}
+	next := schedule.Next(after)
+	if next.IsZero() {
+		return util.NewInvalidArgumentErrorf("schedule %q never runs", r.Schedule)
+	}
+	r.NextRunUnix = timeutil.TimeStamp(next.Unix())
+	return nil
+}
+
+func (r *TeamReminder) validate() error {
+	if r.WebhookURL == "" {
+		return util.NewInvalidArgumentErrorf("webhook url is required")
+	}
+	if r.StaleDays <= 0 {
+		return util.NewInvalidArgumentErrorf("stale days must be positive")
+	}
+	return r.ScheduleNext(time.Now())
+}
+
+// GetTeamReminder returns the reminder of a team by its id
+func GetTeamReminder(ctx context.Context, teamID, id int64) (*TeamReminder, error) {
+	r := &TeamReminder{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND team_id = ?", id, teamID).Get(r)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTeamReminderNotExist{ID: id}
+	}
+	return r, nil
+}
+
+// FindTeamReminders returns all reminders of a team
+func FindTeamReminders(ctx context.Context, teamID int64) ([]*TeamReminder, error) {
+	reminders := make([]*TeamReminder, 0, 5)
+	return reminders, db.GetEngine(ctx).Where("team_id = ?", teamID).Asc("id").Find(&reminders)
+}
+
+// FindDueTeamReminders returns the active reminders which should have run before the given time
+func FindDueTeamReminders(ctx context.Context, now timeutil.TimeStamp) ([]*TeamReminder, error) {
+	reminders := make([]*TeamReminder, 0, 10)
+	return reminders, db.GetEngine(ctx).
+		Where(builder.Eq{"is_active": true}.And(builder.Lte{"next_run_unix": now})).
+		Asc("next_run_unix").
+		Find(&reminders)
+}
+
+// CreateTeamReminder validates and inserts a new reminder
+func CreateTeamReminder(ctx context.Context, r *TeamReminder) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	return db.Insert(ctx, r)
+}
+
+// UpdateTeamReminder validates and updates the settings of a reminder, its next run is rescheduled
+func UpdateTeamReminder(ctx context.Context, r *TeamReminder) error {
+	if err := r.validate(); err != nil {
+		return err
+	}
+	_, err := db.GetEngine(ctx).ID(r.ID).Cols(
+		"schedule", "webhook_type", "webhook_url", "is_active",
+		"include_review_requests", "include_stale_pulls", "stale_days",
+		"include_failing_checks", "ignore_work_in_progress", "next_run_unix",
+	).Update(r)
+	return err
+}
+
+// UpdateTeamReminderRun records that the reminder ran at the given time and schedules its next run
+func UpdateTeamReminderRun(ctx context.Context, r *TeamReminder, now time.Time) error {
+	r.LastRunUnix = timeutil.TimeStamp(now.Unix())
+	if err := r.ScheduleNext(now); err != nil {
+		// the schedule was valid when saved, stop a reminder which can no longer run
+		r.IsActive = false
+	}
+	_, err := db.GetEngine(ctx).ID(r.ID).Cols("last_run_unix", "next_run_unix", "is_active").NoAutoTime().Update(r)
+	return err
+}
+
+// DeleteTeamReminder deletes a reminder of a team
+func DeleteTeamReminder(ctx context.Context, teamID, id int64) error {
+	n, err := db.GetEngine(ctx).Where("id = ? AND team_id = ?", id, teamID).Delete(new(TeamReminder))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrTeamReminderNotExist{ID: id}
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization_test
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTeamReminder(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.Error(t, organization.CreateTeamReminder(db.DefaultContext, &organization.TeamReminder{OrgID: 3, TeamID: 1, Schedule: "0 9 * *", WebhookURL: "https://example.com/hook", StaleDays: 7}))
+	assert.Error(t, organization.CreateTeamReminder(db.DefaultContext, &organization.TeamReminder{OrgID: 3, TeamID: 1, Schedule: "0 9 * * 1-5", StaleDays: 7}))
+
+	r := &organization.TeamReminder{OrgID: 3, TeamID: 1, Schedule: "0 9 * * 1-5", WebhookType: "slack", WebhookURL: "https://example.com/hook", IsActive: true, StaleDays: 7}
+	assert.NoError(t, organization.CreateTeamReminder(db.DefaultContext, r))
+	assert.Greater(t, r.NextRunUnix, timeutil.TimeStampNow())
+
+	reminders, err := organization.FindTeamReminders(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Len(t, reminders, 1)
+
+	due, err := organization.FindDueTeamReminders(db.DefaultContext, timeutil.TimeStampNow())
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+	due, err = organization.FindDueTeamReminders(db.DefaultContext, r.NextRunUnix)
+	assert.NoError(t, err)
+	assert.Len(t, due, 1)
+
+	ranAt := r.NextRunUnix.AsTime()
+	assert.NoError(t, organization.UpdateTeamReminderRun(db.DefaultContext, r, ranAt))
+	r, err = organization.GetTeamReminder(db.DefaultContext, 1, r.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, ranAt.Unix(), r.LastRunUnix)
+	assert.Greater(t, r.NextRunUnix.AsTime(), ranAt.Add(time.Hour))
+
+	r.Schedule = "@daily"
+	assert.NoError(t, organization.UpdateTeamReminder(db.DefaultContext, r))
+
+	_, err = organization.GetTeamReminder(db.DefaultContext, 2, r.ID)
+	assert.True(t, organization.IsErrTeamReminderNotExist(err))
+	assert.True(t, organization.IsErrTeamReminderNotExist(organization.DeleteTeamReminder(db.DefaultContext, 2, r.ID)))
+	assert.NoError(t, organization.DeleteTeamReminder(db.DefaultContext, 1, r.ID))
+	unittest.AssertNotExistsBean(t, &organization.TeamReminder{ID: r.ID})
+}
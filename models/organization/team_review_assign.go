@@ -0,0 +1,67 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization
+
+import (
+	"context"
+	"sort"
+
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// IsReviewAssignEnabled returns true if a review request of the team is assigned to some of its members
+func (t *Team) IsReviewAssignEnabled() bool {
+	return t.ReviewAssignCount > 0
+}
+
+// ValidateReviewAssignSettings validates how review requests of the team are assigned to its members
+func (t *Team) ValidateReviewAssignSettings() error {
+	if t.ReviewAssignCount < 0 {
+		return util.NewInvalidArgumentErrorf("review assign count must not be negative")
+	}
+	return nil
+}
+
+// AssignReviewers returns the members of the team a review request of the team is assigned to. They are picked in
+// round-robin order, starting after the member who was picked last, and busy members as well as the excluded users are skipped.
+func AssignReviewers(ctx context.Context, t *Team, excludeIDs ...int64) ([]*user_model.User, error) {
+	if !t.IsReviewAssignEnabled() {
+		return nil, nil
+	}
+	members, err := GetTeamMembers(ctx, &SearchMembersOptions{TeamID: t.ID})
+	if err != nil {
+		return nil, err
+	}
+	candidates := make([]*user_model.User, 0, len(members))
+	for _, member := range members {
+		if !util.SliceContains(excludeIDs, member.ID) {
+			candidates = append(candidates, member)
+		}
+	}
+	if candidates, err = user_model.FilterBusyUsers(ctx, candidates); err != nil {
+		return nil, err
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].ID < candidates[j].ID })
+	start := sort.Search(len(candidates), func(i int) bool { return candidates[i].ID > t.ReviewAssignLastUserID })
+	count := t.ReviewAssignCount
+	if count > len(candidates) {
+		count = len(candidates)
+	}
+	reviewers := make([]*user_model.User, 0, count)
+	for i := 0; i < count; i++ {
+		reviewers = append(reviewers, candidates[(start+i)%len(candidates)])
+	}
+
+	t.ReviewAssignLastUserID = reviewers[len(reviewers)-1].ID
+	if _, err := db.GetEngine(ctx).ID(t.ID).Cols("review_assign_last_user_id").Update(t); err != nil {
+		return nil, err
+	}
+	return reviewers, nil
+}
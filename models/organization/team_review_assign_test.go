@@ -0,0 +1,53 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package organization_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssignReviewers(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// team 2 has the members 2 and 4
+	assert.NoError(t, db.Insert(db.DefaultContext, &organization.TeamUser{OrgID: 3, TeamID: 2, UID: 5}))
+	team := unittest.AssertExistsAndLoadBean(t, &organization.Team{ID: 2})
+
+	assignedIDs := func(excludeIDs ...int64) []int64 {
+		reviewers, err := organization.AssignReviewers(db.DefaultContext, team, excludeIDs...)
+		assert.NoError(t, err)
+		ids := make([]int64, 0, len(reviewers))
+		for _, reviewer := range reviewers {
+			ids = append(ids, reviewer.ID)
+		}
+		return ids
+	}
+
+	assert.Empty(t, assignedIDs())
+
+	team.ReviewAssignCount = 1
+	assert.Equal(t, []int64{4}, assignedIDs(2))
+	assert.Equal(t, []int64{5}, assignedIDs(2))
+	assert.Equal(t, []int64{4}, assignedIDs(2))
+	unittest.AssertExistsAndLoadBean(t, &organization.Team{ID: 2, ReviewAssignLastUserID: 4})
+
+	// busy members are skipped
+	assert.NoError(t, user_model.SetUserStatus(db.DefaultContext, &user_model.UserStatus{UserID: 5, IsBusy: true}))
+	assert.Equal(t, []int64{2}, assignedIDs())
+	assert.Equal(t, []int64{4}, assignedIDs())
+	assert.Equal(t, []int64{2}, assignedIDs())
+
+	team.ReviewAssignCount = 3
+	assert.Equal(t, []int64{4, 2}, assignedIDs())
+	assert.NoError(t, user_model.SetUserStatus(db.DefaultContext, &user_model.UserStatus{UserID: 2, IsBusy: true}))
+	assert.NoError(t, user_model.SetUserStatus(db.DefaultContext, &user_model.UserStatus{UserID: 4, IsBusy: true}))
+	assert.Empty(t, assignedIDs())
+}
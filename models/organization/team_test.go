@@ -9,6 +9,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/organization"
 	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -197,3 +198,18 @@ func TestUsersInTeamsCount(t *testing.T) {
 	test([]int64{1, 2, 3, 4, 5}, []int64{2, 5}, 2)    // userid 2,4
 	test([]int64{1, 2, 3, 4, 5}, []int64{2, 3, 5}, 3) // userid 2,4,5
 }
+
+func TestNormalizeCodePaths(t *testing.T) {
+	paths, err := organization.NormalizeCodePaths([]string{" /services/web/ ", "", "docs", "docs/"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"services/web", "docs"}, paths)
+
+	paths, err = organization.NormalizeCodePaths([]string{" ", "/"})
+	assert.NoError(t, err)
+	assert.Nil(t, paths)
+
+	for _, invalid := range []string{"../secret", "docs/../secret", "docs//api", "./docs"} {
+		_, err = organization.NormalizeCodePaths([]string{invalid})
+		assert.ErrorIs(t, err, util.ErrInvalidArgument, invalid)
+	}
+}
@@ -18,6 +18,7 @@ type TeamUser struct {
 	OrgID  int64 `xorm:"INDEX"`
 	TeamID int64 `xorm:"UNIQUE(s)"`
 	UID    int64 `xorm:"UNIQUE(s)"`
+	IsLead bool  `xorm:"NOT NULL DEFAULT false"`
 }
 
 // IsTeamMember returns true if given user is a member of team.
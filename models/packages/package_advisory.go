@@ -0,0 +1,87 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrPackageAdvisoryNotExist indicates a package advisory not exist error
+var ErrPackageAdvisoryNotExist = util.NewNotExistErrorf("package advisory does not exist")
+
+func init() {
+	db.RegisterModel(new(PackageAdvisory))
+}
+
+// PackageAdvisory is a security advisory published by the owner of packages for the versions of a package,
+// it is served to package managers auditing their dependencies
+type PackageAdvisory struct {
+	ID          int64  `xorm:"pk autoincr"`
+	OwnerID     int64  `xorm:"INDEX(s) NOT NULL"`
+	Type        Type   `xorm:"INDEX(s) NOT NULL"`
+	PackageName string `xorm:"INDEX(s) NOT NULL"`
+	Title       string `xorm:"NOT NULL"`
+	URL         string `xorm:"TEXT"`
+	Severity    string `xorm:"NOT NULL"`
+	// VulnerableVersions is the range of affected versions in the syntax of the package type
+	VulnerableVersions string             `xorm:"TEXT NOT NULL"`
+	CreatedUnix        timeutil.TimeStamp `xorm:"created NOT NULL DEFAULT 0"`
+	UpdatedUnix        timeutil.TimeStamp `xorm:"updated NOT NULL DEFAULT 0"`
+}
+
+// InsertAdvisory publishes an advisory
+func InsertAdvisory(ctx context.Context, pa *PackageAdvisory) error {
+	return db.Insert(ctx, pa)
+}
+
+// UpdateAdvisory updates an advisory
+func UpdateAdvisory(ctx context.Context, pa *PackageAdvisory) error {
+	_, err := db.GetEngine(ctx).ID(pa.ID).AllCols().Update(pa)
+	return err
+}
+
+// GetAdvisoryByID returns an advisory of the owner
+func GetAdvisoryByID(ctx context.Context, ownerID, id int64) (*PackageAdvisory, error) {
+	pa := &PackageAdvisory{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND owner_id = ?", id, ownerID).Get(pa)
+	if err != nil {
+		return nil, err
+	}
+	if !has {
+		return nil, ErrPackageAdvisoryNotExist
+	}
+	return pa, nil
+}
+
+// GetAdvisoriesByOwner returns the advisories published by the owner
+func GetAdvisoriesByOwner(ctx context.Context, ownerID int64) ([]*PackageAdvisory, error) {
+	pas := make([]*PackageAdvisory, 0, 10)
+	return pas, db.GetEngine(ctx).Where("owner_id = ?", ownerID).OrderBy("id").Find(&pas)
+}
+
+// GetAdvisoriesByPackageName returns the advisories published by the owner for a package
+func GetAdvisoriesByPackageName(ctx context.Context, ownerID int64, packageType Type, name string) ([]*PackageAdvisory, error) {
+	pas := make([]*PackageAdvisory, 0, 5)
+	return pas, db.GetEngine(ctx).
+		Where("owner_id = ? AND type = ? AND package_name = ?", ownerID, packageType, strings.ToLower(name)).
+		OrderBy("id").
+		Find(&pas)
+}
+
+// DeleteAdvisoryByID deletes an advisory of the owner
+func DeleteAdvisoryByID(ctx context.Context, ownerID, id int64) error {
+	n, err := db.GetEngine(ctx).Where("id = ? AND owner_id = ?", id, ownerID).Delete(&PackageAdvisory{})
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return ErrPackageAdvisoryNotExist
+	}
+	return nil
+}
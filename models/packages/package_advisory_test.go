@@ -0,0 +1,50 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPackageAdvisories(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	pa := &packages_model.PackageAdvisory{
+		OwnerID:            3,
+		Type:               packages_model.TypeNpm,
+		PackageName:        "@scope/package",
+		Title:              "Prototype pollution",
+		Severity:           "high",
+		VulnerableVersions: "<1.2.3",
+	}
+	assert.NoError(t, packages_model.InsertAdvisory(db.DefaultContext, pa))
+
+	pas, err := packages_model.GetAdvisoriesByPackageName(db.DefaultContext, 3, packages_model.TypeNpm, "@Scope/Package")
+	assert.NoError(t, err)
+	assert.Len(t, pas, 1)
+	pas, err = packages_model.GetAdvisoriesByPackageName(db.DefaultContext, 2, packages_model.TypeNpm, "@scope/package")
+	assert.NoError(t, err)
+	assert.Empty(t, pas)
+
+	pa.Severity = "critical"
+	assert.NoError(t, packages_model.UpdateAdvisory(db.DefaultContext, pa))
+	pa, err = packages_model.GetAdvisoryByID(db.DefaultContext, 3, pa.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "critical", pa.Severity)
+
+	_, err = packages_model.GetAdvisoryByID(db.DefaultContext, 2, pa.ID)
+	assert.ErrorIs(t, err, packages_model.ErrPackageAdvisoryNotExist)
+	assert.ErrorIs(t, packages_model.DeleteAdvisoryByID(db.DefaultContext, 2, pa.ID), packages_model.ErrPackageAdvisoryNotExist)
+
+	assert.NoError(t, packages_model.DeleteAdvisoryByID(db.DefaultContext, 3, pa.ID))
+	pas, err = packages_model.GetAdvisoriesByOwner(db.DefaultContext, 3)
+	assert.NoError(t, err)
+	assert.Empty(t, pas)
+}
@@ -10,6 +10,8 @@ import (
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
 )
 
 // ErrPackageBlobNotExist indicates a package blob not exist error
@@ -68,6 +70,33 @@ func ExistPackageBlobWithSHA(ctx context.Context, blobSha256 string) (bool, erro
 	})
 }
 
+// GetBlobsByOwnerID gets the blobs referenced by the package files of an owner
+func GetBlobsByOwnerID(ctx context.Context, ownerID int64) ([]*PackageBlob, error) {
+	pbs := make([]*PackageBlob, 0, 10)
+	return pbs, db.GetEngine(ctx).
+		Table("package_blob").
+		Where(builder.In("package_blob.id", ownerBlobIDs(ownerID))).
+		Find(&pbs)
+}
+
+// IsBlobReferencedByOtherOwners checks if package files of other owners reference the blob
+func IsBlobReferencedByOtherOwners(ctx context.Context, blobID, ownerID int64) (bool, error) {
+	return db.GetEngine(ctx).
+		Table("package_file").
+		Join("INNER", "package_version", "package_version.id = package_file.version_id").
+		Join("INNER", "package", "package.id = package_version.package_id").
+		Where("package_file.blob_id = ? AND package.owner_id <> ?", blobID, ownerID).
+		Exist()
+}
+
+func ownerBlobIDs(ownerID int64) *builder.Builder {
+	return builder.Select("package_file.blob_id").
+		From("package_file").
+		InnerJoin("package_version", "package_version.id = package_file.version_id").
+		InnerJoin("package", "package.id = package_version.package_id").
+		Where(builder.Eq{"package.owner_id": ownerID})
+}
+
 // FindExpiredUnreferencedBlobs gets all blobs without associated files older than the specific duration
 func FindExpiredUnreferencedBlobs(ctx context.Context, olderThan time.Duration) ([]*PackageBlob, error) {
 	pbs := make([]*PackageBlob, 0, 10)
@@ -75,6 +104,8 @@ func FindExpiredUnreferencedBlobs(ctx context.Context, olderThan time.Duration)
 		Table("package_blob").
 		Join("LEFT", "package_file", "package_file.blob_id = package_blob.id").
 		Where("package_file.id IS NULL AND package_blob.created_unix < ?", time.Now().Add(-olderThan).Unix()).
+		// the blobs of deleted package versions are kept until they are purged
+		And(builder.NotIn("package_blob.id", builder.Select("blob_id").From("package_version_deletion_blob"))).
 		Find(&pbs)
 }
 
@@ -90,6 +121,32 @@ func GetTotalBlobSize(ctx context.Context) (int64, error) {
 		SumInt(&PackageBlob{}, "size")
 }
 
+// GetBlobSizesByOwner returns the size of the blobs referenced by the package files of every owner which has any,
+// blobs shared by files of the same owner are counted once
+func GetBlobSizesByOwner(ctx context.Context) (map[int64]int64, error) {
+	type ownerBlob struct {
+		OwnerID int64
+		BlobID  int64
+		Size    int64
+	}
+	rows := make([]*ownerBlob, 0, 100)
+	if err := db.GetEngine(ctx).
+		Table("package_file").
+		Select("DISTINCT package.owner_id, package_file.blob_id, package_blob.size").
+		Join("INNER", "package_version", "package_version.id = package_file.version_id").
+		Join("INNER", "package", "package.id = package_version.package_id").
+		Join("INNER", "package_blob", "package_blob.id = package_file.blob_id").
+		Find(&rows); err != nil {
+		return nil, err
+	}
+
+	sizes := make(map[int64]int64)
+	for _, row := range rows {
+		sizes[row.OwnerID] += row.Size
+	}
+	return sizes, nil
+}
+
 // GetTotalUnreferencedBlobSize returns the total size of all unreferenced blobs in bytes
 func GetTotalUnreferencedBlobSize(ctx context.Context) (int64, error) {
 	return db.GetEngine(ctx).
@@ -33,6 +33,9 @@ type PackageVersion struct {
 	IsInternal    bool               `xorm:"INDEX NOT NULL DEFAULT false"`
 	MetadataJSON  string             `xorm:"metadata_json LONGTEXT"`
 	DownloadCount int64              `xorm:"NOT NULL DEFAULT 0"`
+	// IsDeprecated marks a version which is kept but should no longer be used
+	IsDeprecated       bool   `xorm:"INDEX NOT NULL DEFAULT false"`
+	DeprecationMessage string `xorm:"TEXT"`
 }
 
 // GetOrInsertVersion inserts a version. If the same version exist already ErrDuplicatePackageVersion is returned
@@ -63,6 +66,17 @@ func UpdateVersion(ctx context.Context, pv *PackageVersion) error {
 	return err
 }
 
+// SetVersionDeprecation marks a version as deprecated with the message or removes its deprecation
+func SetVersionDeprecation(ctx context.Context, pv *PackageVersion, deprecated bool, message string) error {
+	if !deprecated {
+		message = ""
+	}
+	pv.IsDeprecated = deprecated
+	pv.DeprecationMessage = message
+	_, err := db.GetEngine(ctx).ID(pv.ID).Cols("is_deprecated", "deprecation_message").Update(pv)
+	return err
+}
+
 // IncrementDownloadCounter increments the download counter of a version
 func IncrementDownloadCounter(ctx context.Context, versionID int64) error {
 	_, err := db.GetEngine(ctx).Exec("UPDATE `package_version` SET `download_count` = `download_count` + 1 WHERE `id` = ?", versionID)
@@ -175,17 +189,19 @@ const (
 // PackageSearchOptions are options for SearchXXX methods
 // All fields optional and are not used if they have their default value (nil, "", 0)
 type PackageSearchOptions struct {
-	OwnerID         int64
-	RepoID          int64
-	Type            Type
-	PackageID       int64
-	Name            SearchValue       // only results with the specific name are found
-	Version         SearchValue       // only results with the specific version are found
-	Properties      map[string]string // only results are found which contain all listed version properties with the specific value
-	IsInternal      util.OptionalBool
-	HasFileWithName string            // only results are found which are associated with a file with the specific name
-	HasFiles        util.OptionalBool // only results are found which have associated files
-	Sort            VersionSort
+	OwnerID           int64
+	OwnerCond         builder.Cond // only results are found whose owner matches the condition on the user table
+	RepoID            int64
+	HideInternalRepos bool // hide packages linked to internal repositories, they are not visible for anonymous users
+	Type              Type
+	PackageID         int64
+	Name              SearchValue       // only results with the specific name are found
+	Version           SearchValue       // only results with the specific version are found
+	Properties        map[string]string // only results are found which contain all listed version properties with the specific value
+	IsInternal        util.OptionalBool
+	HasFileWithName   string            // only results are found which are associated with a file with the specific name
+	HasFiles          util.OptionalBool // only results are found which have associated files
+	Sort              VersionSort
 	db.Paginator
 }
 
@@ -200,9 +216,15 @@ func (opts *PackageSearchOptions) toConds() builder.Cond {
 	if opts.OwnerID != 0 {
 		cond = cond.And(builder.Eq{"package.owner_id": opts.OwnerID})
 	}
+	if opts.OwnerCond != nil {
+		cond = cond.And(builder.In("package.owner_id", builder.Select("`user`.id").From("`user`").Where(opts.OwnerCond)))
+	}
 	if opts.RepoID != 0 {
 		cond = cond.And(builder.Eq{"package.repo_id": opts.RepoID})
 	}
+	if opts.HideInternalRepos {
+		cond = cond.And(builder.NotIn("package.repo_id", builder.Select("id").From("repository").Where(builder.Eq{"is_internal": true})))
+	}
 	if opts.Type != "" && opts.Type != "all" {
 		cond = cond.And(builder.Eq{"package.type": opts.Type})
 	}
@@ -0,0 +1,230 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+func init() {
+	db.RegisterModel(new(PackageVersionDeletion))
+	db.RegisterModel(new(PackageVersionDeletionBlob))
+}
+
+// ErrPackageVersionDeletionNotExist represents a "PackageVersionDeletionNotExist" kind of error.
+type ErrPackageVersionDeletionNotExist struct {
+	ID      int64
+	OwnerID int64
+}
+
+// IsErrPackageVersionDeletionNotExist checks if an error is a ErrPackageVersionDeletionNotExist.
+func IsErrPackageVersionDeletionNotExist(err error) bool {
+	_, ok := err.(ErrPackageVersionDeletionNotExist)
+	return ok
+}
+
+func (err ErrPackageVersionDeletionNotExist) Error() string {
+	return fmt.Sprintf("deleted package version does not exist [id: %d, owner_id: %d]", err.ID, err.OwnerID)
+}
+
+func (err ErrPackageVersionDeletionNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// PackageFileSnapshot is a file of a deleted package version
+type PackageFileSnapshot struct {
+	File       *PackageFile
+	Properties []*PackageProperty
+}
+
+// PackageVersionSnapshot holds the rows of a deleted package version which are inserted again to restore it
+type PackageVersionSnapshot struct {
+	Package           *Package
+	PackageProperties []*PackageProperty
+	Version           *PackageVersion
+	VersionProperties []*PackageProperty
+	Files             []*PackageFileSnapshot
+}
+
+// PackageVersionDeletion keeps a deleted package version until it is purged, so it can be restored.
+// The rows of the version are deleted like without retention, the blobs of its files are kept.
+type PackageVersionDeletion struct {
+	ID          int64                   `xorm:"pk autoincr"`
+	OwnerID     int64                   `xorm:"INDEX NOT NULL"`
+	PackageType Type                    `xorm:"NOT NULL"`
+	PackageName string                  `xorm:"NOT NULL"`
+	Version     string                  `xorm:"NOT NULL"`
+	Snapshot    *PackageVersionSnapshot `xorm:"JSON LONGTEXT"`
+	DeleterID   int64                   `xorm:"NOT NULL DEFAULT 0"`
+	DeletedUnix timeutil.TimeStamp      `xorm:"created"`
+	PurgeUnix   timeutil.TimeStamp      `xorm:"INDEX NOT NULL DEFAULT 0"`
+}
+
+// PackageVersionDeletionBlob keeps a blob of a deleted package version from being cleaned up
+type PackageVersionDeletionBlob struct {
+	ID         int64 `xorm:"pk autoincr"`
+	DeletionID int64 `xorm:"INDEX NOT NULL"`
+	BlobID     int64 `xorm:"INDEX NOT NULL"`
+}
+
+// NewPackageVersionSnapshot returns the snapshot of the rows of a package version
+func NewPackageVersionSnapshot(pd *PackageDescriptor) *PackageVersionSnapshot {
+	snapshot := &PackageVersionSnapshot{
+		Package:           pd.Package,
+		PackageProperties: pd.PackageProperties,
+		Version:           pd.Version,
+		VersionProperties: pd.VersionProperties,
+		Files:             make([]*PackageFileSnapshot, 0, len(pd.Files)),
+	}
+	for _, pfd := range pd.Files {
+		snapshot.Files = append(snapshot.Files, &PackageFileSnapshot{File: pfd.File, Properties: pfd.Properties})
+	}
+	return snapshot
+}
+
+// InsertPackageVersionDeletion keeps the blobs of the files of a deleted package version and its snapshot
+func InsertPackageVersionDeletion(ctx context.Context, d *PackageVersionDeletion) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Insert(d); err != nil {
+			return err
+		}
+		blobs := make([]*PackageVersionDeletionBlob, 0, len(d.Snapshot.Files))
+		for _, f := range d.Snapshot.Files {
+			blobs = append(blobs, &PackageVersionDeletionBlob{DeletionID: d.ID, BlobID: f.File.BlobID})
+		}
+		if len(blobs) == 0 {
+			return nil
+		}
+		_, err := db.GetEngine(ctx).Insert(&blobs)
+		return err
+	})
+}
+
+// GetPackageVersionDeletion returns a deleted package version of an owner
+func GetPackageVersionDeletion(ctx context.Context, ownerID, id int64) (*PackageVersionDeletion, error) {
+	d := &PackageVersionDeletion{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND owner_id = ?", id, ownerID).Get(d)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrPackageVersionDeletionNotExist{ID: id, OwnerID: ownerID}
+	}
+	return d, nil
+}
+
+// FindPackageVersionDeletions returns the deleted package versions of an owner, the versions purged first come first
+func FindPackageVersionDeletions(ctx context.Context, ownerID int64) ([]*PackageVersionDeletion, error) {
+	deletions := make([]*PackageVersionDeletion, 0, 10)
+	return deletions, db.GetEngine(ctx).Where("owner_id = ?", ownerID).Asc("purge_unix", "id").Find(&deletions)
+}
+
+// FindExpiredPackageVersionDeletions returns the deleted package versions which are to be purged
+func FindExpiredPackageVersionDeletions(ctx context.Context) ([]*PackageVersionDeletion, error) {
+	deletions := make([]*PackageVersionDeletion, 0, 10)
+	return deletions, db.GetEngine(ctx).Where("purge_unix <= ?", timeutil.TimeStampNow()).Asc("purge_unix").Find(&deletions)
+}
+
+// DeletePackageVersionDeletion drops a deleted package version, its blobs are cleaned up with the unreferenced blobs
+func DeletePackageVersionDeletion(ctx context.Context, id int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("deletion_id = ?", id).Delete(new(PackageVersionDeletionBlob)); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(id).Delete(new(PackageVersionDeletion))
+		return err
+	})
+}
+
+// DeletePackageVersionDeletionsByOwner drops the deleted package versions of an owner
+func DeletePackageVersionDeletionsByOwner(ctx context.Context, ownerID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).
+			Where(builder.In("deletion_id", builder.Select("id").From("package_version_deletion").Where(builder.Eq{"owner_id": ownerID}))).
+			Delete(new(PackageVersionDeletionBlob)); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).Where("owner_id = ?", ownerID).Delete(new(PackageVersionDeletion))
+		return err
+	})
+}
+
+func insertSnapshotProperties(ctx context.Context, refType PropertyType, refID int64, pps []*PackageProperty) error {
+	for _, pp := range pps {
+		if _, err := InsertProperty(ctx, refType, refID, pp.Name, pp.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RestorePackageVersionDeletion inserts the rows of a deleted package version again. The package is created again
+// if it was removed meanwhile. ErrDuplicatePackageVersion is returned if the version was uploaded again.
+func RestorePackageVersionDeletion(ctx context.Context, d *PackageVersionDeletion) (*PackageVersion, error) {
+	var pv *PackageVersion
+	err := db.WithTx(ctx, func(ctx context.Context) error {
+		snapshot := d.Snapshot
+		if snapshot == nil || snapshot.Package == nil || snapshot.Version == nil {
+			return util.NewInvalidArgumentErrorf("the deleted package version can't be restored")
+		}
+
+		p := *snapshot.Package
+		p.ID = 0
+		if p.RepoID != 0 {
+			if has, err := db.GetEngine(ctx).Table("repository").Where("id = ?", p.RepoID).Exist(); err != nil {
+				return err
+			} else if !has {
+				p.RepoID = 0
+			}
+		}
+		inserted, err := TryInsertPackage(ctx, &p)
+		if err != nil && !errors.Is(err, ErrDuplicatePackage) {
+			return err
+		}
+		if err == nil {
+			if err := insertSnapshotProperties(ctx, PropertyTypePackage, inserted.ID, snapshot.PackageProperties); err != nil {
+				return err
+			}
+		}
+
+		v := *snapshot.Version
+		v.ID = 0
+		v.PackageID = inserted.ID
+		if has, err := db.GetEngine(ctx).Where("package_id = ? AND lower_version = ?", v.PackageID, v.LowerVersion).Exist(new(PackageVersion)); err != nil {
+			return err
+		} else if has {
+			return ErrDuplicatePackageVersion
+		}
+		// keep the creation times to keep the order of the versions and files
+		if _, err := db.GetEngine(ctx).NoAutoTime().Insert(&v); err != nil {
+			return err
+		}
+		if err := insertSnapshotProperties(ctx, PropertyTypeVersion, v.ID, snapshot.VersionProperties); err != nil {
+			return err
+		}
+
+		for _, fs := range snapshot.Files {
+			f := *fs.File
+			f.ID = 0
+			f.VersionID = v.ID
+			if _, err := db.GetEngine(ctx).NoAutoTime().Insert(&f); err != nil {
+				return err
+			}
+			if err := insertSnapshotProperties(ctx, PropertyTypeFile, f.ID, fs.Properties); err != nil {
+				return err
+			}
+		}
+
+		pv = &v
+		return DeletePackageVersionDeletion(ctx, d.ID)
+	})
+	return pv, err
+}
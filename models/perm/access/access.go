@@ -29,7 +29,9 @@ func init() {
 	db.RegisterModel(new(Access))
 }
 
-func accessLevel(ctx context.Context, user *user_model.User, repo *repo_model.Repository) (perm.AccessMode, error) {
+// baseAccessLevel returns the access of a user to a repository without the access table,
+// it returns false if the access table may grant another access
+func baseAccessLevel(user *user_model.User, repo *repo_model.Repository) (perm.AccessMode, bool) {
 	mode := perm.AccessModeNone
 	var userID int64
 	restricted := false
@@ -39,19 +41,28 @@ func accessLevel(ctx context.Context, user *user_model.User, repo *repo_model.Re
 		restricted = user.IsRestricted
 	}
 
-	if !restricted && !repo.IsPrivate {
+	// internal repositories can be read by every signed-in user
+	if !restricted && (!repo.IsPrivate || (repo.IsInternal && userID != 0)) {
 		mode = perm.AccessModeRead
 	}
 
 	if userID == 0 {
-		return mode, nil
+		return mode, true
 	}
 
 	if userID == repo.OwnerID {
-		return perm.AccessModeOwner, nil
+		return perm.AccessModeOwner, true
+	}
+	return mode, false
+}
+
+func accessLevel(ctx context.Context, user *user_model.User, repo *repo_model.Repository) (perm.AccessMode, error) {
+	mode, final := baseAccessLevel(user, repo)
+	if final {
+		return mode, nil
 	}
 
-	a := &Access{UserID: userID, RepoID: repo.ID}
+	a := &Access{UserID: user.ID, RepoID: repo.ID}
 	if has, err := db.GetByBean(ctx, a); !has || err != nil {
 		return mode, err
 	}
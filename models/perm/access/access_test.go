@@ -67,6 +67,30 @@ func TestAccessLevel(t *testing.T) {
 	assert.Equal(t, perm_model.AccessModeRead, level)
 }
 
+func TestAccessLevelInternal(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	user5 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+	user29 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 29})
+	// A private repository owned by Org 3, made internal
+	repo3 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 3})
+	repo3.IsInternal = true
+
+	level, err := access_model.AccessLevel(db.DefaultContext, user5, repo3)
+	assert.NoError(t, err)
+	assert.Equal(t, perm_model.AccessModeRead, level)
+
+	// anonymous users have no access to an internal repo
+	level, err = access_model.AccessLevel(db.DefaultContext, nil, repo3)
+	assert.NoError(t, err)
+	assert.Equal(t, perm_model.AccessModeNone, level)
+
+	// neither have restricted users
+	level, err = access_model.AccessLevel(db.DefaultContext, user29, repo3)
+	assert.NoError(t, err)
+	assert.Equal(t, perm_model.AccessModeNone, level)
+}
+
 func TestHasAccess(t *testing.T) {
 	assert.NoError(t, unittest.PrepareTestDatabase())
 
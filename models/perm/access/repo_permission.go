@@ -6,6 +6,7 @@ package access
 import (
 	"context"
 	"fmt"
+	"strings"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/organization"
@@ -14,6 +15,7 @@ import (
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // Permission contains all the permissions related variables to a repository for a user
@@ -21,6 +23,42 @@ type Permission struct {
 	AccessMode perm_model.AccessMode
 	Units      []*repo_model.RepoUnit
 	UnitsMode  map[unit.Type]perm_model.AccessMode
+
+	// CodePaths are the only directories and files of the code which may be read, nil if reading is not restricted
+	CodePaths []string
+}
+
+// IsCodePathRestricted returns true if the user may only read some paths of the code
+func (p *Permission) IsCodePathRestricted() bool {
+	return p.CodePaths != nil
+}
+
+// CanReadCodePath returns true if the user may read the file or directory, including everything in it
+func (p *Permission) CanReadCodePath(treePath string) bool {
+	if p.CodePaths == nil {
+		return true
+	}
+	treePath = strings.Trim(treePath, "/")
+	for _, allowed := range p.CodePaths {
+		if treePath == allowed || strings.HasPrefix(treePath, allowed+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// CanBrowseCodePath returns true if the user may list the directory, because it is readable or leads to a readable path
+func (p *Permission) CanBrowseCodePath(treePath string) bool {
+	treePath = strings.Trim(treePath, "/")
+	if treePath == "" || p.CanReadCodePath(treePath) {
+		return true
+	}
+	for _, allowed := range p.CodePaths {
+		if strings.HasPrefix(allowed, treePath+"/") {
+			return true
+		}
+	}
+	return false
 }
 
 // IsOwner returns true if current user is the owner of repository.
@@ -143,8 +181,42 @@ func (p *Permission) ColorFormat(s fmt.State) {
 	log.ColorFprintf(s, format, args...)
 }
 
+// permissionLoader loads the relations of a user to a repository which the permission is computed from
+type permissionLoader interface {
+	isCollaborator(ctx context.Context, repo *repo_model.Repository, user *user_model.User) (bool, error)
+	hasOwnerVisible(ctx context.Context, repo *repo_model.Repository, user *user_model.User) bool
+	accessLevel(ctx context.Context, repo *repo_model.Repository, user *user_model.User) (perm_model.AccessMode, error)
+	repoTeams(ctx context.Context, repo *repo_model.Repository, user *user_model.User) ([]*organization.Team, error)
+}
+
+// dbPermissionLoader queries the relations of a user to a single repository
+type dbPermissionLoader struct{}
+
+func (dbPermissionLoader) isCollaborator(ctx context.Context, repo *repo_model.Repository, user *user_model.User) (bool, error) {
+	return repo_model.IsCollaborator(ctx, repo.ID, user.ID)
+}
+
+func (dbPermissionLoader) hasOwnerVisible(ctx context.Context, repo *repo_model.Repository, user *user_model.User) bool {
+	return organization.HasOrgOrUserVisible(ctx, repo.Owner, user)
+}
+
+func (dbPermissionLoader) accessLevel(ctx context.Context, repo *repo_model.Repository, user *user_model.User) (perm_model.AccessMode, error) {
+	return accessLevel(ctx, user, repo)
+}
+
+func (dbPermissionLoader) repoTeams(ctx context.Context, repo *repo_model.Repository, user *user_model.User) ([]*organization.Team, error) {
+	return organization.GetUserRepoTeams(ctx, repo.OwnerID, user.ID, repo.ID)
+}
+
 // GetUserRepoPermission returns the user permissions to the repository
-func GetUserRepoPermission(ctx context.Context, repo *repo_model.Repository, user *user_model.User) (perm Permission, err error) {
+func GetUserRepoPermission(ctx context.Context, repo *repo_model.Repository, user *user_model.User) (Permission, error) {
+	if perm, ok := getCachedUserRepoPermission(ctx, repo, user); ok {
+		return perm, nil
+	}
+	return getUserRepoPermission(ctx, repo, user, dbPermissionLoader{})
+}
+
+func getUserRepoPermission(ctx context.Context, repo *repo_model.Repository, user *user_model.User, loader permissionLoader) (perm Permission, err error) {
 	if log.IsTrace() {
 		defer func() {
 			if user == nil {
@@ -169,7 +241,7 @@ func GetUserRepoPermission(ctx context.Context, repo *repo_model.Repository, use
 
 	var is bool
 	if user != nil {
-		is, err = repo_model.IsCollaborator(ctx, repo.ID, user.ID)
+		is, err = loader.isCollaborator(ctx, repo, user)
 		if err != nil {
 			return perm, err
 		}
@@ -181,7 +253,7 @@ func GetUserRepoPermission(ctx context.Context, repo *repo_model.Repository, use
 
 	// Prevent strangers from checking out public repo of private organization/users
 	// Allow user if they are collaborator of a repo within a private user or a private organization but not a member of the organization itself
-	if !organization.HasOrgOrUserVisible(ctx, repo.Owner, user) && !is {
+	if !loader.hasOwnerVisible(ctx, repo, user) && !is {
 		perm.AccessMode = perm_model.AccessModeNone
 		return
 	}
@@ -205,7 +277,7 @@ func GetUserRepoPermission(ctx context.Context, repo *repo_model.Repository, use
 	}
 
 	// plain user
-	perm.AccessMode, err = accessLevel(ctx, user, repo)
+	perm.AccessMode, err = loader.accessLevel(ctx, repo, user)
 	if err != nil {
 		return
 	}
@@ -227,7 +299,7 @@ func GetUserRepoPermission(ctx context.Context, repo *repo_model.Repository, use
 	}
 
 	// get units mode from teams
-	teams, err := organization.GetUserRepoTeams(ctx, repo.OwnerID, user.ID, repo.ID)
+	teams, err := loader.repoTeams(ctx, repo, user)
 	if err != nil {
 		return
 	}
@@ -254,14 +326,23 @@ func GetUserRepoPermission(ctx context.Context, repo *repo_model.Repository, use
 			}
 		}
 
-		// for a public repo on an organization, a non-restricted user has read permission on non-team defined units.
-		if !found && !repo.IsPrivate && !user.IsRestricted {
+		// for a public or internal repo on an organization, a non-restricted user has read permission on non-team defined units.
+		if !found && (!repo.IsPrivate || repo.IsInternal) && !user.IsRestricted {
 			if _, ok := perm.UnitsMode[u.Type]; !ok {
 				perm.UnitsMode[u.Type] = perm_model.AccessModeRead
 			}
 		}
 	}
 
+	// teams limited to some code paths only restrict the code of repositories the user could not read otherwise
+	if !is && perm.CanRead(unit.TypeCode) && (user.IsRestricted || (repo.IsPrivate && !repo.IsInternal)) {
+		perm.CodePaths = codePathsOfTeams(ctx, teams)
+		if perm.CodePaths != nil {
+			// path restricted code is read only
+			perm.UnitsMode[unit.TypeCode] = perm_model.AccessModeRead
+		}
+	}
+
 	// remove no permission units
 	perm.Units = make([]*repo_model.RepoUnit, 0, len(repo.Units))
 	for t := range perm.UnitsMode {
@@ -275,6 +356,26 @@ func GetUserRepoPermission(ctx context.Context, repo *repo_model.Repository, use
 	return perm, err
 }
 
+// codePathsOfTeams returns the union of the code paths of the teams granting access to the code,
+// or nil if one of them grants access to all of the code
+func codePathsOfTeams(ctx context.Context, teams []*organization.Team) []string {
+	var paths []string
+	for _, team := range teams {
+		if team.UnitAccessMode(ctx, unit.TypeCode) <= perm_model.AccessModeNone {
+			continue
+		}
+		if !team.IsCodePathScoped() {
+			return nil
+		}
+		for _, p := range team.CodePaths {
+			if !util.SliceContainsString(paths, p) {
+				paths = append(paths, p)
+			}
+		}
+	}
+	return paths
+}
+
 // IsUserRealRepoAdmin check if this user is real repo admin
 func IsUserRealRepoAdmin(repo *repo_model.Repository, user *user_model.User) (bool, error) {
 	if repo.OwnerID == user.ID {
@@ -0,0 +1,188 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package access
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	perm_model "code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/container"
+)
+
+// repoPermissionCacheGroup is the group of the permissions in the cache of the request
+const repoPermissionCacheGroup = "repo_permission"
+
+type repoPermissionCacheKey struct {
+	UserID int64
+	RepoID int64
+}
+
+func newRepoPermissionCacheKey(repo *repo_model.Repository, user *user_model.User) repoPermissionCacheKey {
+	key := repoPermissionCacheKey{RepoID: repo.ID}
+	if user != nil {
+		key.UserID = user.ID
+	}
+	return key
+}
+
+// clone returns a copy of the permission which can be modified without changing the permission
+func (p *Permission) clone() Permission {
+	c := Permission{AccessMode: p.AccessMode}
+	if p.Units != nil {
+		c.Units = make([]*repo_model.RepoUnit, len(p.Units))
+		copy(c.Units, p.Units)
+	}
+	if p.UnitsMode != nil {
+		c.UnitsMode = make(map[unit.Type]perm_model.AccessMode, len(p.UnitsMode))
+		for t, mode := range p.UnitsMode {
+			c.UnitsMode[t] = mode
+		}
+	}
+	if p.CodePaths != nil {
+		c.CodePaths = make([]string, len(p.CodePaths))
+		copy(c.CodePaths, p.CodePaths)
+	}
+	return c
+}
+
+// getCachedUserRepoPermission returns the permission resolved by GetUserRepoPermissions earlier in the request
+func getCachedUserRepoPermission(ctx context.Context, repo *repo_model.Repository, user *user_model.User) (Permission, bool) {
+	perm, ok := cache.GetContextData(ctx, repoPermissionCacheGroup, newRepoPermissionCacheKey(repo, user)).(*Permission)
+	if !ok {
+		return Permission{}, false
+	}
+	return perm.clone(), true
+}
+
+// batchPermissionLoader looks up the relations of a user to many repositories, which were loaded with a few queries
+type batchPermissionLoader struct {
+	collaborations container.Set[int64]
+	accesses       map[int64]perm_model.AccessMode
+	teams          map[int64]organization.TeamList
+	ownerVisible   map[int64]bool
+}
+
+func newBatchPermissionLoader(ctx context.Context, repos repo_model.RepositoryList, user *user_model.User) (*batchPermissionLoader, error) {
+	loader := &batchPermissionLoader{
+		collaborations: make(container.Set[int64]),
+		accesses:       make(map[int64]perm_model.AccessMode),
+		teams:          make(map[int64]organization.TeamList),
+		ownerVisible:   make(map[int64]bool),
+	}
+	if user == nil {
+		return loader, nil
+	}
+
+	repoIDs := make([]int64, 0, len(repos))
+	for _, repo := range repos {
+		repoIDs = append(repoIDs, repo.ID)
+	}
+
+	collaborations := make([]*repo_model.Collaboration, 0, len(repoIDs))
+	if err := db.GetEngine(ctx).
+		Where("user_id = ?", user.ID).
+		In("repo_id", repoIDs).
+		Find(&collaborations); err != nil {
+		return nil, err
+	}
+	for _, c := range collaborations {
+		loader.collaborations.Add(c.RepoID)
+	}
+
+	accesses := make([]*Access, 0, len(repoIDs))
+	if err := db.GetEngine(ctx).
+		Where("user_id = ?", user.ID).
+		In("repo_id", repoIDs).
+		Find(&accesses); err != nil {
+		return nil, err
+	}
+	for _, a := range accesses {
+		loader.accesses[a.RepoID] = a.Mode
+	}
+
+	var err error
+	loader.teams, err = organization.GetUserReposTeams(ctx, user.ID, repoIDs)
+	return loader, err
+}
+
+func (l *batchPermissionLoader) isCollaborator(_ context.Context, repo *repo_model.Repository, _ *user_model.User) (bool, error) {
+	return l.collaborations.Contains(repo.ID), nil
+}
+
+func (l *batchPermissionLoader) hasOwnerVisible(ctx context.Context, repo *repo_model.Repository, user *user_model.User) bool {
+	visible, ok := l.ownerVisible[repo.OwnerID]
+	if !ok {
+		visible = organization.HasOrgOrUserVisible(ctx, repo.Owner, user)
+		l.ownerVisible[repo.OwnerID] = visible
+	}
+	return visible
+}
+
+func (l *batchPermissionLoader) accessLevel(_ context.Context, repo *repo_model.Repository, user *user_model.User) (perm_model.AccessMode, error) {
+	mode, final := baseAccessLevel(user, repo)
+	if final {
+		return mode, nil
+	}
+	if m, ok := l.accesses[repo.ID]; ok {
+		return m, nil
+	}
+	return mode, nil
+}
+
+func (l *batchPermissionLoader) repoTeams(_ context.Context, repo *repo_model.Repository, _ *user_model.User) ([]*organization.Team, error) {
+	teams := make([]*organization.Team, 0, len(l.teams[repo.ID]))
+	for _, team := range l.teams[repo.ID] {
+		if team.OrgID == repo.OwnerID {
+			teams = append(teams, team)
+		}
+	}
+	return teams, nil
+}
+
+// GetUserRepoPermissions returns the permissions of the user to the repositories, keyed by repository ID.
+// The relations of the user to all of the repositories are loaded with a few queries instead of the queries for
+// each repository of GetUserRepoPermission, which dominate lists of many repositories. The permissions are kept
+// in the cache of the request, so that later calls of GetUserRepoPermission for these repositories don't query
+// them again.
+func GetUserRepoPermissions(ctx context.Context, repos repo_model.RepositoryList, user *user_model.User) (map[int64]Permission, error) {
+	perms := make(map[int64]Permission, len(repos))
+	if len(repos) == 0 {
+		return perms, nil
+	}
+
+	if err := repos.LoadOwners(ctx); err != nil {
+		return nil, err
+	}
+	if err := repos.LoadUnits(ctx); err != nil {
+		return nil, err
+	}
+	loader, err := newBatchPermissionLoader(ctx, repos, user)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, repo := range repos {
+		if _, ok := perms[repo.ID]; ok {
+			continue
+		}
+		// the owner is only missing if it doesn't exist anymore, which is reported like GetUserRepoPermission does
+		if err := repo.LoadOwner(ctx); err != nil {
+			return nil, err
+		}
+		perm, err := getUserRepoPermission(ctx, repo, user, loader)
+		if err != nil {
+			return nil, err
+		}
+		perms[repo.ID] = perm
+		cached := perm.clone()
+		cache.SetContextData(ctx, repoPermissionCacheGroup, newRepoPermissionCacheKey(repo, user), &cached)
+	}
+	return perms, nil
+}
@@ -0,0 +1,78 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package access_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/cache"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPermission_CodePaths(t *testing.T) {
+	p := &access_model.Permission{}
+	assert.False(t, p.IsCodePathRestricted())
+	assert.True(t, p.CanReadCodePath("any/file.go"))
+
+	p = &access_model.Permission{CodePaths: []string{"services/web", "README.md"}}
+	assert.True(t, p.IsCodePathRestricted())
+	assert.True(t, p.CanReadCodePath("services/web"))
+	assert.True(t, p.CanReadCodePath("/services/web/main.go"))
+	assert.True(t, p.CanReadCodePath("README.md"))
+	assert.False(t, p.CanReadCodePath("services/website/main.go"))
+	assert.False(t, p.CanReadCodePath("services"))
+	assert.False(t, p.CanReadCodePath(""))
+
+	assert.True(t, p.CanBrowseCodePath(""))
+	assert.True(t, p.CanBrowseCodePath("services"))
+	assert.True(t, p.CanBrowseCodePath("services/web/static"))
+	assert.False(t, p.CanBrowseCodePath("services/api"))
+	assert.False(t, p.CanBrowseCodePath("docs"))
+}
+
+func TestGetUserRepoPermissions(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	var repos repo_model.RepositoryList
+	assert.NoError(t, db.GetEngine(db.DefaultContext).OrderBy("id").Find(&repos))
+
+	// anonymous, admin, regular, organization member and restricted users
+	for _, userID := range []int64{0, 1, 2, 4, 5, 15, 29} {
+		var user *user_model.User
+		if userID > 0 {
+			user = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: userID})
+		}
+
+		ctx := cache.WithCacheContext(db.DefaultContext)
+		perms, err := access_model.GetUserRepoPermissions(ctx, repos, user)
+		assert.NoError(t, err)
+		assert.Len(t, perms, len(repos))
+
+		for _, repo := range repos {
+			expected, err := access_model.GetUserRepoPermission(db.DefaultContext, repo, user)
+			assert.NoError(t, err)
+			actual := perms[repo.ID]
+			assert.Equal(t, expected.AccessMode, actual.AccessMode, "user %d, repo %d", userID, repo.ID)
+			assert.Equal(t, expected.UnitsMode, actual.UnitsMode, "user %d, repo %d", userID, repo.ID)
+			assert.ElementsMatch(t, expected.Units, actual.Units, "user %d, repo %d", userID, repo.ID)
+			assert.Equal(t, expected.CodePaths, actual.CodePaths, "user %d, repo %d", userID, repo.ID)
+
+			// the permission is taken from the cache of the request, changing it doesn't change the cache
+			cached, err := access_model.GetUserRepoPermission(ctx, repo, user)
+			assert.NoError(t, err)
+			assert.Equal(t, actual.AccessMode, cached.AccessMode)
+			if cached.UnitsMode != nil {
+				cached.UnitsMode[0] = 0
+				again, _ := access_model.GetUserRepoPermission(ctx, repo, user)
+				assert.Equal(t, actual.UnitsMode, again.UnitsMode)
+			}
+		}
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 
 	"xorm.io/builder"
 )
@@ -22,6 +23,9 @@ type (
 	// CardType is used to represent a project board card type
 	CardType uint8
 
+	// WipLimitMode is used to represent how the work in progress limit of a project board is enforced
+	WipLimitMode uint8
+
 	// BoardList is a list of all project boards in a repository
 	BoardList []*Board
 )
@@ -45,6 +49,14 @@ const (
 	CardTypeImagesAndText
 )
 
+const (
+	// WipLimitModeWarn moves issues into a board over its limit, but warns about it
+	WipLimitModeWarn WipLimitMode = iota
+
+	// WipLimitModeBlock refuses to move issues into a board over its limit
+	WipLimitModeBlock
+)
+
 // BoardColorPattern is a regexp witch can validate BoardColor
 var BoardColorPattern = regexp.MustCompile("^#[0-9a-fA-F]{6}$")
 
@@ -56,6 +68,10 @@ type Board struct {
 	Sorting int8   `xorm:"NOT NULL DEFAULT 0"`
 	Color   string `xorm:"VARCHAR(7)"`
 
+	// the maximum number of issues in the board, 0 for no limit
+	WipLimit     int          `xorm:"NOT NULL DEFAULT 0"`
+	WipLimitMode WipLimitMode `xorm:"NOT NULL DEFAULT 0"`
+
 	ProjectID int64 `xorm:"INDEX NOT NULL"`
 	CreatorID int64 `xorm:"NOT NULL"`
 
@@ -82,10 +98,35 @@ func (b *Board) NumIssues() int {
 	return int(c)
 }
 
+// IsOverWipLimit returns whether the board has more issues than its work in progress limit
+func (b *Board) IsOverWipLimit() bool {
+	return b.WipLimit > 0 && b.NumIssues() > b.WipLimit
+}
+
 func init() {
 	db.RegisterModel(new(Board))
 }
 
+// ErrProjectBoardWipLimitExceeded represents a "ProjectBoardWipLimitExceeded" kind of error.
+type ErrProjectBoardWipLimitExceeded struct {
+	BoardID  int64
+	WipLimit int
+}
+
+// IsErrProjectBoardWipLimitExceeded checks if an error is a ErrProjectBoardWipLimitExceeded
+func IsErrProjectBoardWipLimitExceeded(err error) bool {
+	_, ok := err.(ErrProjectBoardWipLimitExceeded)
+	return ok
+}
+
+func (err ErrProjectBoardWipLimitExceeded) Error() string {
+	return fmt.Sprintf("project board would exceed its work in progress limit [id: %d, limit: %d]", err.BoardID, err.WipLimit)
+}
+
+func (err ErrProjectBoardWipLimitExceeded) Unwrap() error {
+	return util.ErrInvalidArgument
+}
+
 // IsBoardTypeValid checks if the project board type is valid
 func IsBoardTypeValid(p BoardType) bool {
 	switch p {
@@ -96,6 +137,16 @@ func IsBoardTypeValid(p BoardType) bool {
 	}
 }
 
+// IsWipLimitModeValid checks if the project board work in progress limit mode is valid
+func IsWipLimitModeValid(m WipLimitMode) bool {
+	switch m {
+	case WipLimitModeWarn, WipLimitModeBlock:
+		return true
+	default:
+		return false
+	}
+}
+
 // IsCardTypeValid checks if the project board card type is valid
 func IsCardTypeValid(p CardType) bool {
 	switch p {
@@ -222,6 +273,11 @@ func UpdateBoard(ctx context.Context, board *Board) error {
 	}
 	fieldToUpdate = append(fieldToUpdate, "color")
 
+	if board.WipLimit < 0 || !IsWipLimitModeValid(board.WipLimitMode) {
+		return fmt.Errorf("bad work in progress limit: %d", board.WipLimit)
+	}
+	fieldToUpdate = append(fieldToUpdate, "wip_limit", "wip_limit_mode")
+
 	_, err := db.GetEngine(ctx).ID(board.ID).Cols(fieldToUpdate...).Update(board)
 
 	return err
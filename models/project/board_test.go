@@ -0,0 +1,66 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMoveIssuesOnProjectBoardWipLimit(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	board, err := GetBoard(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	board.WipLimit = 1
+	board.WipLimitMode = WipLimitModeBlock
+	assert.NoError(t, UpdateBoard(db.DefaultContext, board))
+
+	// sorting the issues already in the board is allowed
+	overWipLimit, err := MoveIssuesOnProjectBoard(board, map[int64]int64{0: 3})
+	assert.NoError(t, err)
+	assert.False(t, overWipLimit)
+
+	_, err = MoveIssuesOnProjectBoard(board, map[int64]int64{0: 3, 1: 1})
+	assert.True(t, IsErrProjectBoardWipLimitExceeded(err))
+	unittest.AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: 1, ProjectBoardID: 1})
+
+	board.WipLimitMode = WipLimitModeWarn
+	assert.NoError(t, UpdateBoard(db.DefaultContext, board))
+	overWipLimit, err = MoveIssuesOnProjectBoard(board, map[int64]int64{0: 3, 1: 1})
+	assert.NoError(t, err)
+	assert.True(t, overWipLimit)
+	unittest.AssertExistsAndLoadBean(t, &ProjectIssue{IssueID: 1, ProjectBoardID: 2})
+	assert.True(t, board.IsOverWipLimit())
+}
+
+func TestViewSetting(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	setting, err := GetViewSetting(db.DefaultContext, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, SwimlaneTypeNone, setting.Swimlane)
+	assert.False(t, setting.HasFilter())
+
+	setting.Swimlane = SwimlaneTypeAssignee
+	setting.LabelID = 1
+	assert.NoError(t, SaveViewSetting(db.DefaultContext, setting))
+	setting.Swimlane = SwimlaneTypeMilestone
+	assert.NoError(t, SaveViewSetting(db.DefaultContext, setting))
+
+	setting, err = GetViewSetting(db.DefaultContext, 1, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, SwimlaneTypeMilestone, setting.Swimlane)
+	assert.True(t, setting.HasFilter())
+	unittest.AssertCount(t, &ViewSetting{ProjectID: 1}, 1)
+
+	// the settings of other users are not affected
+	setting, err = GetViewSetting(db.DefaultContext, 1, 4)
+	assert.NoError(t, err)
+	assert.Equal(t, SwimlaneTypeNone, setting.Swimlane)
+}
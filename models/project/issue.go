@@ -75,9 +75,11 @@ func (p *Project) NumOpenIssues() int {
 	return int(c)
 }
 
-// MoveIssuesOnProjectBoard moves or keeps issues in a column and sorts them inside that column
-func MoveIssuesOnProjectBoard(board *Board, sortedIssueIDs map[int64]int64) error {
-	return db.WithTx(db.DefaultContext, func(ctx context.Context) error {
+// MoveIssuesOnProjectBoard moves or keeps issues in a column and sorts them inside that column.
+// It returns whether the column is over its work in progress limit after issues were moved into it,
+// or an ErrProjectBoardWipLimitExceeded if the column blocks that.
+func MoveIssuesOnProjectBoard(board *Board, sortedIssueIDs map[int64]int64) (overWipLimit bool, err error) {
+	err = db.WithTx(db.DefaultContext, func(ctx context.Context) error {
 		sess := db.GetEngine(ctx)
 
 		issueIDs := make([]int64, 0, len(sortedIssueIDs))
@@ -92,6 +94,15 @@ func MoveIssuesOnProjectBoard(board *Board, sortedIssueIDs map[int64]int64) erro
 			return fmt.Errorf("all issues have to be added to a project first")
 		}
 
+		if board.ID != 0 && board.WipLimit > 0 {
+			if overWipLimit, err = isOverWipLimitAfterMove(ctx, board, issueIDs); err != nil {
+				return err
+			}
+			if overWipLimit && board.WipLimitMode == WipLimitModeBlock {
+				return ErrProjectBoardWipLimitExceeded{BoardID: board.ID, WipLimit: board.WipLimit}
+			}
+		}
+
 		for sorting, issueID := range sortedIssueIDs {
 			_, err = sess.Exec("UPDATE `project_issue` SET project_board_id=?, sorting=? WHERE issue_id=?", board.ID, sorting, issueID)
 			if err != nil {
@@ -100,6 +111,26 @@ func MoveIssuesOnProjectBoard(board *Board, sortedIssueIDs map[int64]int64) erro
 		}
 		return nil
 	})
+	return overWipLimit, err
+}
+
+// isOverWipLimitAfterMove returns whether moving the issues into the board adds issues to it and brings it over its limit,
+// sorting the issues already in the board is always allowed
+func isOverWipLimitAfterMove(ctx context.Context, board *Board, issueIDs []int64) (bool, error) {
+	inBoard, err := db.GetEngine(ctx).Table(new(ProjectIssue)).
+		Where("project_board_id=?", board.ID).In("issue_id", issueIDs).Count()
+	if err != nil {
+		return false, err
+	}
+	if int(inBoard) == len(issueIDs) {
+		return false, nil
+	}
+	kept, err := db.GetEngine(ctx).Table(new(ProjectIssue)).
+		Where("project_board_id=?", board.ID).NotIn("issue_id", issueIDs).Count()
+	if err != nil {
+		return false, err
+	}
+	return int(kept)+len(issueIDs) > board.WipLimit, nil
 }
 
 func (b *Board) removeIssues(ctx context.Context) error {
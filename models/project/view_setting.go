@@ -0,0 +1,99 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package project
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// SwimlaneType is used to represent how the issues of a project board are grouped into rows
+type SwimlaneType uint8
+
+const (
+	// SwimlaneTypeNone shows all issues in a single row
+	SwimlaneTypeNone SwimlaneType = iota
+
+	// SwimlaneTypeAssignee groups the issues by their first assignee
+	SwimlaneTypeAssignee
+
+	// SwimlaneTypeLabel groups the issues by their first label
+	SwimlaneTypeLabel
+
+	// SwimlaneTypeMilestone groups the issues by their milestone
+	SwimlaneTypeMilestone
+)
+
+// IsSwimlaneTypeValid checks if the project board swimlane type is valid
+func IsSwimlaneTypeValid(s SwimlaneType) bool {
+	switch s {
+	case SwimlaneTypeNone, SwimlaneTypeAssignee, SwimlaneTypeLabel, SwimlaneTypeMilestone:
+		return true
+	default:
+		return false
+	}
+}
+
+// ViewSetting is how a user views the boards of a project: the swimlanes and the filters of the issues
+type ViewSetting struct {
+	ID          int64        `xorm:"pk autoincr"`
+	ProjectID   int64        `xorm:"UNIQUE(s) NOT NULL"`
+	UserID      int64        `xorm:"UNIQUE(s) NOT NULL"`
+	Swimlane    SwimlaneType `xorm:"NOT NULL DEFAULT 0"`
+	AssigneeID  int64        `xorm:"NOT NULL DEFAULT 0"` // 0 for all issues
+	LabelID     int64        `xorm:"NOT NULL DEFAULT 0"`
+	MilestoneID int64        `xorm:"NOT NULL DEFAULT 0"`
+
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// TableName return the real table name
+func (ViewSetting) TableName() string {
+	return "project_view_setting"
+}
+
+func init() {
+	db.RegisterModel(new(ViewSetting))
+}
+
+// HasFilter returns whether the setting hides some issues of the boards
+func (s *ViewSetting) HasFilter() bool {
+	return s.AssigneeID != 0 || s.LabelID != 0 || s.MilestoneID != 0
+}
+
+// GetViewSetting returns how the user views the project, the default setting if the user hasn't changed it
+func GetViewSetting(ctx context.Context, projectID, userID int64) (*ViewSetting, error) {
+	setting := &ViewSetting{ProjectID: projectID, UserID: userID}
+	if userID == 0 {
+		return setting, nil
+	}
+	if _, err := db.GetEngine(ctx).Where("project_id=? AND user_id=?", projectID, userID).Get(setting); err != nil {
+		return nil, err
+	}
+	return setting, nil
+}
+
+// SaveViewSetting inserts or updates how the user views the project
+func SaveViewSetting(ctx context.Context, setting *ViewSetting) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing := &ViewSetting{}
+		has, err := db.GetEngine(ctx).Where("project_id=? AND user_id=?", setting.ProjectID, setting.UserID).Get(existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return db.Insert(ctx, setting)
+		}
+		setting.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(setting.ID).Cols("swimlane", "assignee_id", "label_id", "milestone_id").Update(setting)
+		return err
+	})
+}
+
+func deleteViewSettingsByProjectID(ctx context.Context, projectID int64) error {
+	_, err := db.GetEngine(ctx).Where("project_id=?", projectID).Delete(&ViewSetting{})
+	return err
+}
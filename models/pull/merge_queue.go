@@ -0,0 +1,173 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// MergeQueueEntry represents a pull request waiting in the merge queue of its base branch
+type MergeQueueEntry struct {
+	ID           int64                 `xorm:"pk autoincr"`
+	RepoID       int64                 `xorm:"INDEX(s)"`
+	BaseBranch   string                `xorm:"INDEX(s)"`
+	PullID       int64                 `xorm:"UNIQUE"`
+	DoerID       int64                 `xorm:"NOT NULL"`
+	Doer         *user_model.User      `xorm:"-"`
+	MergeStyle   repo_model.MergeStyle `xorm:"varchar(30)"`
+	Message      string                `xorm:"LONGTEXT"`
+	TrainID      int64                 `xorm:"INDEX"`       // the merge train testing the pull request, 0 while it waits
+	HeadCommitID string                `xorm:"VARCHAR(64)"` // the head commit of the pull request tested by the merge train
+	CreatedUnix  timeutil.TimeStamp    `xorm:"created"`
+}
+
+// TableName return database table name for xorm
+func (MergeQueueEntry) TableName() string {
+	return "pull_merge_queue"
+}
+
+// MergeTrain represents the speculative merge of the first pull requests of a merge queue, which is tested before they are merged.
+// There is at most one train per base branch.
+type MergeTrain struct {
+	ID           int64  `xorm:"pk autoincr"`
+	RepoID       int64  `xorm:"UNIQUE(s)"`
+	BaseBranch   string `xorm:"UNIQUE(s)"`
+	BaseCommitID string `xorm:"VARCHAR(64)"`
+	// CommitID is the result of merging the pull requests of the train into the base commit, it is pushed to Branch
+	CommitID    string             `xorm:"VARCHAR(64) INDEX"`
+	Branch      string             `xorm:"VARCHAR(255)"`
+	Size        int                `xorm:"NOT NULL DEFAULT 0"` // the number of pull requests tested by the train
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+// TableName return database table name for xorm
+func (MergeTrain) TableName() string {
+	return "pull_merge_train"
+}
+
+func init() {
+	db.RegisterModel(new(MergeQueueEntry))
+	db.RegisterModel(new(MergeTrain))
+}
+
+// ErrAlreadyInMergeQueue represents a "PullRequestAlreadyInMergeQueue"-error
+type ErrAlreadyInMergeQueue struct {
+	PullID int64
+}
+
+func (err ErrAlreadyInMergeQueue) Error() string {
+	return fmt.Sprintf("pull request is already in the merge queue [pull_id: %d]", err.PullID)
+}
+
+func (err ErrAlreadyInMergeQueue) Unwrap() error {
+	return util.ErrAlreadyExist
+}
+
+// IsErrAlreadyInMergeQueue checks if an error is a ErrAlreadyInMergeQueue.
+func IsErrAlreadyInMergeQueue(err error) bool {
+	_, ok := err.(ErrAlreadyInMergeQueue)
+	return ok
+}
+
+// AddToMergeQueue adds a pull request at the end of the merge queue of its base branch
+func AddToMergeQueue(ctx context.Context, entry *MergeQueueEntry) error {
+	if has, err := db.GetEngine(ctx).Exist(&MergeQueueEntry{PullID: entry.PullID}); err != nil {
+		return err
+	} else if has {
+		return ErrAlreadyInMergeQueue{PullID: entry.PullID}
+	}
+	entry.TrainID = 0
+	entry.HeadCommitID = ""
+	_, err := db.GetEngine(ctx).Insert(entry)
+	return err
+}
+
+// GetMergeQueueEntryByPullID returns the merge queue entry of a pull request
+func GetMergeQueueEntryByPullID(ctx context.Context, pullID int64) (bool, *MergeQueueEntry, error) {
+	entry := &MergeQueueEntry{}
+	exists, err := db.GetEngine(ctx).Where("pull_id = ?", pullID).Get(entry)
+	if err != nil || !exists {
+		return false, nil, err
+	}
+	return true, entry, nil
+}
+
+// GetMergeQueue returns the entries of the merge queue of a branch in their order
+func GetMergeQueue(ctx context.Context, repoID int64, baseBranch string) ([]*MergeQueueEntry, error) {
+	entries := make([]*MergeQueueEntry, 0, 10)
+	return entries, db.GetEngine(ctx).Where("repo_id = ? AND base_branch = ?", repoID, baseBranch).Asc("id").Find(&entries)
+}
+
+// LoadDoer loads the user who added the pull request to the merge queue
+func (entry *MergeQueueEntry) LoadDoer(ctx context.Context) (err error) {
+	if entry.Doer != nil {
+		return nil
+	}
+	entry.Doer, err = user_model.GetUserByID(ctx, entry.DoerID)
+	return err
+}
+
+// DeleteMergeQueueEntries removes pull requests from the merge queue
+func DeleteMergeQueueEntries(ctx context.Context, ids ...int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	_, err := db.GetEngine(ctx).In("id", ids).Delete(&MergeQueueEntry{})
+	return err
+}
+
+// GetMergeTrain returns the merge train of a branch, nil if there is none
+func GetMergeTrain(ctx context.Context, repoID int64, baseBranch string) (*MergeTrain, error) {
+	train := &MergeTrain{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND base_branch = ?", repoID, baseBranch).Get(train)
+	if err != nil || !has {
+		return nil, err
+	}
+	return train, nil
+}
+
+// GetMergeTrainByCommitID returns the merge train of a commit, nil if there is none
+func GetMergeTrainByCommitID(ctx context.Context, repoID int64, commitID string) (*MergeTrain, error) {
+	train := &MergeTrain{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND commit_id = ?", repoID, commitID).Get(train)
+	if err != nil || !has {
+		return nil, err
+	}
+	return train, nil
+}
+
+// CreateMergeTrain creates a merge train for merge queue entries, their HeadCommitID must be set
+func CreateMergeTrain(ctx context.Context, train *MergeTrain, entries []*MergeQueueEntry) error {
+	train.Size = len(entries)
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if err := db.Insert(ctx, train); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			entry.TrainID = train.ID
+			if _, err := db.GetEngine(ctx).ID(entry.ID).Cols("train_id", "head_commit_id").Update(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteMergeTrain deletes a merge train, its entries which are still queued wait for the next train
+func DeleteMergeTrain(ctx context.Context, trainID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("train_id = ?", trainID).Cols("train_id", "head_commit_id").Update(&MergeQueueEntry{}); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).ID(trainID).Delete(&MergeTrain{})
+		return err
+	})
+}
@@ -15,12 +15,18 @@ import (
 	activities_model "code.gitea.io/gitea/models/activities"
 	admin_model "code.gitea.io/gitea/models/admin"
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	chatops_model "code.gitea.io/gitea/models/chatops"
+	codescanning_model "code.gitea.io/gitea/models/codescanning"
 	"code.gitea.io/gitea/models/db"
+	discussion_model "code.gitea.io/gitea/models/discussion"
 	git_model "code.gitea.io/gitea/models/git"
+	insights_model "code.gitea.io/gitea/models/insights"
 	issues_model "code.gitea.io/gitea/models/issues"
+	moderation_model "code.gitea.io/gitea/models/moderation"
 	"code.gitea.io/gitea/models/organization"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	project_model "code.gitea.io/gitea/models/project"
+	pull_model "code.gitea.io/gitea/models/pull"
 	repo_model "code.gitea.io/gitea/models/repo"
 	secret_model "code.gitea.io/gitea/models/secret"
 	system_model "code.gitea.io/gitea/models/system"
@@ -53,6 +59,10 @@ func DeleteRepository(doer *user_model.User, uid, repoID int64) error {
 	defer committer.Close()
 	sess := db.GetEngine(ctx)
 
+	if err := issues_model.CheckRepositoryLegalHold(ctx, repoID); err != nil {
+		return err
+	}
+
 	// Query the action tasks of this repo, they will be needed after they have been deleted to remove the logs
 	tasks, err := actions_model.FindTasks(ctx, actions_model.FindTaskOptions{RepoID: repoID})
 	if err != nil {
@@ -129,7 +139,7 @@ func DeleteRepository(doer *user_model.User, uid, repoID int64) error {
 	}
 	releaseAttachments := make([]string, 0, len(attachments))
 	for i := 0; i < len(attachments); i++ {
-		releaseAttachments = append(releaseAttachments, attachments[i].RelativePath())
+		releaseAttachments = append(releaseAttachments, attachments[i].ObjectPaths()...)
 	}
 
 	if _, err := db.Exec(ctx, "UPDATE `user` SET num_stars=num_stars-1 WHERE id IN (SELECT `uid` FROM `star` WHERE repo_id = ?)", repo.ID); err != nil {
@@ -141,23 +151,51 @@ func DeleteRepository(doer *user_model.User, uid, repoID int64) error {
 		return err
 	}
 
+	if _, err := db.GetEngine(ctx).In("item_id", builder.Select("id").From("review_checklist_item").Where(builder.Eq{"review_checklist_item.repo_id": repo.ID})).
+		Delete(&issues_model.ReviewChecklistCheck{}); err != nil {
+		return err
+	}
+
 	if err := db.DeleteBeans(ctx,
 		&access_model.Access{RepoID: repo.ID},
 		&activities_model.Action{RepoID: repo.ID},
 		&repo_model.Collaboration{RepoID: repoID},
 		&issues_model.Comment{RefRepoID: repoID},
 		&git_model.CommitStatus{RepoID: repoID},
+		&git_model.CoverageReport{RepoID: repoID},
+		&git_model.CoverageReportFile{RepoID: repoID},
 		&git_model.DeletedBranch{RepoID: repoID},
 		&git_model.LFSLock{RepoID: repoID},
+		&repo_model.ForkDivergence{RepoID: repoID},
+		&repo_model.ForkSync{RepoID: repoID},
+		&repo_model.VendorSync{RepoID: repoID},
+		&insights_model.PullRequestMetric{RepoID: repoID},
+		&insights_model.PathContribution{RepoID: repoID},
+		&insights_model.PathOwnershipStatus{RepoID: repoID},
 		&repo_model.LanguageStat{RepoID: repoID},
+		&repo_model.RepoLicense{RepoID: repoID},
 		&issues_model.Milestone{RepoID: repoID},
+		&issues_model.StalePolicy{RepoID: repoID},
+		&issues_model.IssueLockPolicy{RepoID: repoID},
+		&issues_model.ReviewChecklistItem{RepoID: repoID},
+		&pull_model.MergeQueueEntry{RepoID: repoID},
+		&pull_model.MergeTrain{RepoID: repoID},
 		&repo_model.Mirror{RepoID: repoID},
+		&repo_model.BadgeToken{RepoID: repoID},
+		&repo_model.EmbedToken{RepoID: repoID},
+		&repo_model.RepoDeletion{RepoID: repoID},
+		&issues_model.IssueSubscriptionRule{RepoID: repoID},
 		&activities_model.Notification{RepoID: repoID},
 		&git_model.ProtectedBranch{RepoID: repoID},
 		&git_model.ProtectedTag{RepoID: repoID},
 		&repo_model.PushMirror{RepoID: repoID},
 		&repo_model.Release{RepoID: repoID},
 		&repo_model.RepoIndexerStatus{RepoID: repoID},
+		&repo_model.RepoMaintenance{RepoID: repoID},
+		&repo_model.ArchiveNotice{RepoID: repoID},
+		&repo_model.StorageUsage{RepoID: repoID},
+		&repo_model.DownloadLink{RepoID: repoID},
+		&repo_model.EditSession{RepoID: repoID},
 		&repo_model.Redirect{RedirectRepoID: repoID},
 		&repo_model.RepoUnit{RepoID: repoID},
 		&repo_model.Star{RepoID: repoID},
@@ -171,6 +209,13 @@ func DeleteRepository(doer *user_model.User, uid, repoID int64) error {
 		&actions_model.ActionRun{RepoID: repoID},
 		&actions_model.ActionRunner{RepoID: repoID},
 		&actions_model.ActionArtifact{RepoID: repoID},
+		&actions_model.ActionWorkflowSubscription{RepoID: repoID},
+		&codescanning_model.Alert{RepoID: repoID},
+		&codescanning_model.Analysis{RepoID: repoID},
+		&chatops_model.Audit{RepoID: repoID},
+		&moderation_model.InteractionLimit{RepoID: repoID},
+		&moderation_model.HeldContent{RepoID: repoID},
+		&organization.TeamPinnedRepo{RepoID: repoID},
 	); err != nil {
 		return fmt.Errorf("deleteBeans: %w", err)
 	}
@@ -196,6 +241,10 @@ func DeleteRepository(doer *user_model.User, uid, repoID int64) error {
 		return err
 	}
 
+	if err := discussion_model.DeleteDiscussionsByRepoID(ctx, repoID); err != nil {
+		return err
+	}
+
 	if repo.IsFork {
 		if _, err := db.Exec(ctx, "UPDATE `repository` SET num_forks=num_forks-1 WHERE id=?", repo.ForkID); err != nil {
 			return fmt.Errorf("decrease fork count: %w", err)
@@ -258,6 +307,9 @@ func DeleteRepository(doer *user_model.User, uid, repoID int64) error {
 		if _, err = sess.Exec("UPDATE `repository` SET fork_id=0,is_fork=? WHERE fork_id=?", false, repo.ID); err != nil {
 			log.Error("reset 'fork_id' and 'is_fork': %v", err)
 		}
+		if err := repo_model.DeleteForkDivergencesByBaseRepoID(ctx, repo.ID); err != nil {
+			return err
+		}
 	}
 
 	// Get all attachments with both issue_id and release_id are zero
@@ -272,7 +324,7 @@ func DeleteRepository(doer *user_model.User, uid, repoID int64) error {
 
 	newAttachmentPaths := make([]string, 0, len(newAttachments))
 	for _, attach := range newAttachments {
-		newAttachmentPaths = append(newAttachmentPaths, attach.RelativePath())
+		newAttachmentPaths = append(newAttachmentPaths, attach.ObjectPaths()...)
 	}
 
 	if _, err := sess.Where("repo_id=?", repo.ID).Delete(new(repo_model.Attachment)); err != nil {
@@ -308,29 +360,38 @@ func DeleteRepository(doer *user_model.User, uid, repoID int64) error {
 		system_model.RemoveStorageWithNotice(db.DefaultContext, storage.RepoArchives, "Delete repo archive file", archive)
 	}
 
+	region := repo.StorageRegion()
+
 	// Remove lfs objects
 	for _, lfsObj := range lfsPaths {
-		system_model.RemoveStorageWithNotice(db.DefaultContext, storage.LFS, "Delete orphaned LFS file", lfsObj)
+		system_model.RemoveStorageWithNotice(db.DefaultContext, storage.LFSOfRegion(region), "Delete orphaned LFS file", lfsObj)
 	}
 
 	// Remove issue attachment files.
 	for _, attachment := range attachmentPaths {
-		system_model.RemoveStorageWithNotice(db.DefaultContext, storage.Attachments, "Delete issue attachment", attachment)
+		system_model.RemoveStorageWithNotice(db.DefaultContext, storage.AttachmentsOfRegion(region), "Delete issue attachment", attachment)
 	}
 
 	// Remove release attachment files.
 	for _, releaseAttachment := range releaseAttachments {
-		system_model.RemoveStorageWithNotice(db.DefaultContext, storage.Attachments, "Delete release attachment", releaseAttachment)
+		system_model.RemoveStorageWithNotice(db.DefaultContext, storage.AttachmentsOfRegion(region), "Delete release attachment", releaseAttachment)
 	}
 
 	// Remove attachment with no issue_id and release_id.
 	for _, newAttachment := range newAttachmentPaths {
-		system_model.RemoveStorageWithNotice(db.DefaultContext, storage.Attachments, "Delete issue attachment", newAttachment)
+		system_model.RemoveStorageWithNotice(db.DefaultContext, storage.AttachmentsOfRegion(region), "Delete issue attachment", newAttachment)
 	}
 
+	// avatars are shared by all repositories using the same image
 	if len(repo.Avatar) > 0 {
-		if err := storage.RepoAvatars.Delete(repo.CustomAvatarRelativePath()); err != nil {
-			return fmt.Errorf("Failed to remove %s: %w", repo.Avatar, err)
+		used, err := repo_model.ExistsWithAvatarAtStoragePath(db.DefaultContext, repo.CustomAvatarRelativePath())
+		if err != nil {
+			return err
+		}
+		if !used {
+			if err := storage.RepoAvatars.Delete(repo.CustomAvatarRelativePath()); err != nil {
+				return fmt.Errorf("Failed to remove %s: %w", repo.Avatar, err)
+			}
 		}
 	}
 
@@ -677,6 +738,11 @@ func DeleteDeployKey(ctx context.Context, doer *user_model.User, id int64) error
 	}); err != nil {
 		return fmt.Errorf("delete deploy key [%d]: %w", key.ID, err)
 	}
+	if _, err := db.DeleteByBean(ctx, &asymkey_model.DeployKeyUsage{
+		DeployKeyID: key.ID,
+	}); err != nil {
+		return fmt.Errorf("delete usage of deploy key [%d]: %w", key.ID, err)
+	}
 
 	// Check if this is the last reference to same key content.
 	has, err := asymkey_model.IsDeployKeyExistByKeyID(ctx, key.KeyID)
@@ -0,0 +1,72 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// ArchiveNotice records that the owners of an inactive repository were notified it is going to be archived.
+// It is removed when the repository becomes active again or is archived.
+type ArchiveNotice struct {
+	ID               int64              `xorm:"pk autoincr"`
+	RepoID           int64              `xorm:"UNIQUE NOT NULL"`
+	OwnerID          int64              `xorm:"INDEX NOT NULL"`
+	LastActivityUnix timeutil.TimeStamp `xorm:"NOT NULL"`
+	ArchiveUnix      timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	CreatedUnix      timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(ArchiveNotice))
+}
+
+// GetArchiveNotice returns the archive notice of the repository, or nil if there is none
+func GetArchiveNotice(ctx context.Context, repoID int64) (*ArchiveNotice, error) {
+	n := &ArchiveNotice{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(n)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return n, nil
+}
+
+// CreateArchiveNotice records the notice of the archival of a repository
+func CreateArchiveNotice(ctx context.Context, n *ArchiveNotice) error {
+	return db.Insert(ctx, n)
+}
+
+// DeleteArchiveNotice removes the archive notice of the repository
+func DeleteArchiveNotice(ctx context.Context, repoID int64) error {
+	_, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(new(ArchiveNotice))
+	return err
+}
+
+// FindArchiveNoticesOptions represents the options to find the upcoming archivals
+type FindArchiveNoticesOptions struct {
+	db.ListOptions
+	OwnerID int64
+}
+
+// FindArchiveNotices returns the archive notices, the repositories which are archived first come first
+func FindArchiveNotices(ctx context.Context, opts FindArchiveNoticesOptions) ([]*ArchiveNotice, int64, error) {
+	cond := builder.NewCond()
+	if opts.OwnerID > 0 {
+		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
+	}
+	sess := db.GetEngine(ctx).Where(cond).OrderBy("archive_unix ASC, id ASC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+	notices := make([]*ArchiveNotice, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&notices)
+	return notices, count, err
+}
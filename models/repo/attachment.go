@@ -14,6 +14,8 @@ import (
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
 )
 
 // Attachment represent a attachment of issue/comment/release.
@@ -28,6 +30,10 @@ type Attachment struct {
 	Name              string
 	DownloadCount     int64              `xorm:"DEFAULT 0"`
 	Size              int64              `xorm:"DEFAULT 0"`
+	ContentHash       string             `xorm:"VARCHAR(64) INDEX"`        // sha256 of the content, empty for attachments uploaded before it was recorded
+	ObjectUUID        string             `xorm:"VARCHAR(40)"`              // uuid of the attachment whose stored file is shared by this duplicate, empty if it has its own
+	BlobID            int64              `xorm:"INDEX NOT NULL DEFAULT 0"` // the content-addressed blob storing the file, zero for attachments stored under their uuid
+	HasThumbnail      bool               `xorm:"NOT NULL DEFAULT false"`
 	CreatedUnix       timeutil.TimeStamp `xorm:"created"`
 	CustomDownloadURL string             `xorm:"-"`
 }
@@ -51,9 +57,61 @@ func AttachmentRelativePath(uuid string) string {
 	return path.Join(uuid[0:1], uuid[1:2], uuid)
 }
 
+// ObjectID returns the uuid the stored file of the attachment is named after
+func (a *Attachment) ObjectID() string {
+	if a.ObjectUUID != "" {
+		return a.ObjectUUID
+	}
+	return a.UUID
+}
+
 // RelativePath returns the relative path of the attachment
 func (a *Attachment) RelativePath() string {
-	return AttachmentRelativePath(a.UUID)
+	if a.BlobID > 0 {
+		return AttachmentBlobRelativePath(a.ContentHash)
+	}
+	return AttachmentRelativePath(a.ObjectID())
+}
+
+// ThumbnailRelativePath returns the relative path of the thumbnail of an image attachment
+func (a *Attachment) ThumbnailRelativePath() string {
+	return path.Join("thumbnails", a.RelativePath())
+}
+
+// ObjectPaths returns the relative paths of all files stored for the attachment. The files of
+// an attachment stored in a blob are shared, they are removed by the garbage collection of the blobs.
+func (a *Attachment) ObjectPaths() []string {
+	if a.BlobID > 0 {
+		return nil
+	}
+	if a.HasThumbnail {
+		return []string{a.RelativePath(), a.ThumbnailRelativePath()}
+	}
+	return []string{a.RelativePath()}
+}
+
+// StorageRegion returns the storage region the repository of the attachment is pinned to
+func (a *Attachment) StorageRegion(ctx context.Context) (string, error) {
+	if a.RepoID == 0 || len(setting.StorageRegions) == 0 {
+		return "", nil
+	}
+	repo, err := GetRepositoryByID(ctx, a.RepoID)
+	if err != nil {
+		if IsErrRepoNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return repo.StorageRegion(), nil
+}
+
+// Storage returns the attachment storage of the storage region the repository of the attachment is pinned to
+func (a *Attachment) Storage(ctx context.Context) (storage.ObjectStorage, error) {
+	region, err := a.StorageRegion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return storage.AttachmentsOfRegion(region), nil
 }
 
 // DownloadURL returns the download url of the attached file
@@ -192,16 +250,87 @@ func DeleteAttachments(ctx context.Context, attachments []*Attachment, remove bo
 		return 0, err
 	}
 
+	for _, a := range attachments {
+		if a.BlobID > 0 {
+			if err := IncreaseAttachmentBlobRefCount(ctx, a.BlobID, -1); err != nil {
+				return 0, err
+			}
+		}
+	}
+
 	if remove {
-		for i, a := range attachments {
-			if err := storage.Attachments.Delete(a.RelativePath()); err != nil {
+		removable, err := RemovableAttachments(ctx, attachments)
+		if err != nil {
+			return 0, err
+		}
+		for i, a := range removable {
+			attachmentStorage, err := a.Storage(ctx)
+			if err != nil {
 				return i, err
 			}
+			for _, p := range a.ObjectPaths() {
+				if err := attachmentStorage.Delete(p); err != nil {
+					return i, err
+				}
+			}
 		}
 	}
 	return int(cnt), nil
 }
 
+// RemovableAttachments returns one attachment for every stored file of the given attachments
+// which is not shared with any other attachment, so the file can be removed with them.
+// Attachments stored in blobs are never returned, their files are garbage collected.
+func RemovableAttachments(ctx context.Context, attachments []*Attachment) ([]*Attachment, error) {
+	ids := make([]int64, 0, len(attachments))
+	objectIDs := make([]string, 0, len(attachments))
+	byObjectID := make(map[string]*Attachment, len(attachments))
+	for _, a := range attachments {
+		ids = append(ids, a.ID)
+		if a.BlobID > 0 {
+			continue
+		}
+		if _, ok := byObjectID[a.ObjectID()]; !ok {
+			objectIDs = append(objectIDs, a.ObjectID())
+		}
+		// prefer an attachment which knows about the thumbnail
+		if existing, ok := byObjectID[a.ObjectID()]; !ok || (!existing.HasThumbnail && a.HasThumbnail) {
+			byObjectID[a.ObjectID()] = a
+		}
+	}
+	if len(objectIDs) == 0 {
+		return nil, nil
+	}
+
+	type sharedObject struct {
+		UUID       string
+		ObjectUUID string
+	}
+	shared := make([]*sharedObject, 0, 5)
+	if err := db.GetEngine(ctx).Table("attachment").Cols("uuid", "object_uuid").
+		Where(builder.NotIn("id", ids).And(builder.In("uuid", objectIDs).Or(builder.In("object_uuid", objectIDs)))).
+		Find(&shared); err != nil {
+		return nil, err
+	}
+	for _, o := range shared {
+		delete(byObjectID, o.UUID)
+		delete(byObjectID, o.ObjectUUID)
+	}
+
+	removable := make([]*Attachment, 0, len(byObjectID))
+	for _, objectID := range objectIDs {
+		if a, ok := byObjectID[objectID]; ok {
+			removable = append(removable, a)
+		}
+	}
+	return removable, nil
+}
+
+// GetAttachmentSizesByRepo returns the size of the attachments of every repository which has any
+func GetAttachmentSizesByRepo(ctx context.Context) (map[int64]int64, error) {
+	return db.SumGroupBy(ctx, "attachment", "repo_id", "size", builder.Gt{"repo_id": 0})
+}
+
 // DeleteAttachmentsByIssue deletes all attachments associated with the given issue.
 func DeleteAttachmentsByIssue(issueID int64, remove bool) (int, error) {
 	attachments, err := GetAttachmentsByIssueID(db.DefaultContext, issueID)
@@ -0,0 +1,216 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"path"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ErrAttachmentBlobNotExist represents a "AttachmentBlobNotExist" kind of error.
+type ErrAttachmentBlobNotExist struct {
+	ID     int64
+	Region string
+	Hash   string
+}
+
+// IsErrAttachmentBlobNotExist checks if an error is a ErrAttachmentBlobNotExist.
+func IsErrAttachmentBlobNotExist(err error) bool {
+	_, ok := err.(ErrAttachmentBlobNotExist)
+	return ok
+}
+
+func (err ErrAttachmentBlobNotExist) Error() string {
+	return fmt.Sprintf("attachment blob does not exist [id: %d, region: %s, hash: %s]", err.ID, err.Region, err.Hash)
+}
+
+func (err ErrAttachmentBlobNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// AttachmentBlob is a file stored once per storage region for all the attachments with the same content.
+// The stored file is named after the hash of its content.
+type AttachmentBlob struct {
+	ID           int64              `xorm:"pk autoincr"`
+	Region       string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL DEFAULT ''"`
+	HashSHA256   string             `xorm:"hash_sha256 CHAR(64) UNIQUE(s) NOT NULL"`
+	Size         int64              `xorm:"NOT NULL DEFAULT 0"`
+	HasThumbnail bool               `xorm:"NOT NULL DEFAULT false"`
+	RefCount     int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created NOT NULL"`
+	UpdatedUnix  timeutil.TimeStamp `xorm:"updated INDEX NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(AttachmentBlob))
+}
+
+// AttachmentBlobRelativePath returns the relative path of the file with the given sha256 hash in the attachment storage
+func AttachmentBlobRelativePath(hash string) string {
+	return path.Join("cas", hash[0:2], hash[2:4], hash)
+}
+
+// AttachmentBlobThumbnailRelativePath returns the relative path of the thumbnail of the file with the given sha256 hash
+func AttachmentBlobThumbnailRelativePath(hash string) string {
+	return path.Join("thumbnails", AttachmentBlobRelativePath(hash))
+}
+
+// RelativePath returns the relative path of the blob in the attachment storage
+func (b *AttachmentBlob) RelativePath() string {
+	return AttachmentBlobRelativePath(b.HashSHA256)
+}
+
+// ObjectPaths returns the relative paths of all files stored for the blob
+func (b *AttachmentBlob) ObjectPaths() []string {
+	if b.HasThumbnail {
+		return []string{b.RelativePath(), AttachmentBlobThumbnailRelativePath(b.HashSHA256)}
+	}
+	return []string{b.RelativePath()}
+}
+
+// GetAttachmentBlobByID returns the attachment blob with the given id
+func GetAttachmentBlobByID(ctx context.Context, id int64) (*AttachmentBlob, error) {
+	blob := &AttachmentBlob{}
+	if has, err := db.GetEngine(ctx).ID(id).Get(blob); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrAttachmentBlobNotExist{ID: id}
+	}
+	return blob, nil
+}
+
+// GetAttachmentBlob returns the blob with the given content in the storage region
+func GetAttachmentBlob(ctx context.Context, region, hash string) (*AttachmentBlob, error) {
+	blob := &AttachmentBlob{}
+	if has, err := db.GetEngine(ctx).Where("region = ? AND hash_sha256 = ?", region, hash).Get(blob); err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrAttachmentBlobNotExist{Region: region, Hash: hash}
+	}
+	return blob, nil
+}
+
+// ExistAttachmentBlobWithSHA returns if a blob with the given content is stored in any storage region
+func ExistAttachmentBlobWithSHA(ctx context.Context, hash string) (bool, error) {
+	return db.GetEngine(ctx).Where("hash_sha256 = ?", hash).Exist(new(AttachmentBlob))
+}
+
+// InsertAttachmentBlob inserts a new blob, its file must have been stored already
+func InsertAttachmentBlob(ctx context.Context, blob *AttachmentBlob) error {
+	return db.Insert(ctx, blob)
+}
+
+// IncreaseAttachmentBlobRefCount changes the number of attachments referencing the blob by the given amount
+func IncreaseAttachmentBlobRefCount(ctx context.Context, id, amount int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Incr("ref_count", amount).Update(new(AttachmentBlob))
+	return err
+}
+
+// SetAttachmentBlobThumbnail records that the thumbnail of the blob has been stored
+func SetAttachmentBlobThumbnail(ctx context.Context, blob *AttachmentBlob) error {
+	blob.HasThumbnail = true
+	_, err := db.GetEngine(ctx).ID(blob.ID).Cols("has_thumbnail").Update(blob)
+	return err
+}
+
+// ClearAttachmentBlobThumbnail records that the thumbnail of the blob is missing for the blob and its attachments
+func ClearAttachmentBlobThumbnail(ctx context.Context, blob *AttachmentBlob) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		blob.HasThumbnail = false
+		if _, err := db.GetEngine(ctx).ID(blob.ID).Cols("has_thumbnail").Update(blob); err != nil {
+			return err
+		}
+		_, err := db.GetEngine(ctx).Where("blob_id = ?", blob.ID).Cols("has_thumbnail").Update(&Attachment{HasThumbnail: false})
+		return err
+	})
+}
+
+// RecountAttachmentBlobRefs corrects the reference counts of the blobs from the attachments referencing them
+func RecountAttachmentBlobRefs(ctx context.Context) error {
+	_, err := db.GetEngine(ctx).Exec("UPDATE `attachment_blob` SET ref_count = (SELECT COUNT(*) FROM `attachment` WHERE `attachment`.blob_id = `attachment_blob`.id)")
+	return err
+}
+
+// FindUnreferencedAttachmentBlobs returns the blobs which have not been referenced by any attachment since the given time
+func FindUnreferencedAttachmentBlobs(ctx context.Context, olderThan timeutil.TimeStamp) ([]*AttachmentBlob, error) {
+	blobs := make([]*AttachmentBlob, 0, 10)
+	return blobs, db.GetEngine(ctx).
+		Where(builder.Eq{"ref_count": 0}.And(builder.Lt{"updated_unix": olderThan})).
+		And(builder.NotIn("id", builder.Select("blob_id").From("attachment"))).
+		Find(&blobs)
+}
+
+// DeleteAttachmentBlob deletes the blob unless an attachment references it again, and returns if it was deleted
+func DeleteAttachmentBlob(ctx context.Context, blob *AttachmentBlob) (bool, error) {
+	cnt, err := db.GetEngine(ctx).
+		Where(builder.Eq{"id": blob.ID, "ref_count": 0}).
+		And(builder.NotIn("id", builder.Select("blob_id").From("attachment"))).
+		NoAutoCondition().
+		Delete(new(AttachmentBlob))
+	return cnt > 0, err
+}
+
+// FindLegacyAttachments returns the attachments after the given id which are stored under their uuid instead of in a blob
+func FindLegacyAttachments(ctx context.Context, afterID int64, limit int) ([]*Attachment, error) {
+	attachments := make([]*Attachment, 0, limit)
+	return attachments, db.GetEngine(ctx).
+		Where("blob_id = 0 AND id > ?", afterID).
+		Asc("id").
+		Limit(limit).
+		Find(&attachments)
+}
+
+// ExistLegacyAttachmentsWithObjectID returns if any attachment still uses the file stored under the given uuid
+func ExistLegacyAttachmentsWithObjectID(ctx context.Context, objectID string) (bool, error) {
+	return db.GetEngine(ctx).
+		Where("blob_id = 0 AND (uuid = ? OR object_uuid = ?)", objectID, objectID).
+		Exist(new(Attachment))
+}
+
+// SetAttachmentBlob stores the attachment in the given blob instead of under its uuid
+func SetAttachmentBlob(ctx context.Context, attach *Attachment, blob *AttachmentBlob) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		attach.BlobID = blob.ID
+		attach.ContentHash = blob.HashSHA256
+		attach.ObjectUUID = ""
+		attach.HasThumbnail = blob.HasThumbnail
+		if _, err := db.GetEngine(ctx).ID(attach.ID).Cols("blob_id", "content_hash", "object_uuid", "has_thumbnail").Update(attach); err != nil {
+			return err
+		}
+		return IncreaseAttachmentBlobRefCount(ctx, blob.ID, 1)
+	})
+}
+
+// GetAttachmentBlobsByRepoIDs returns the blobs referenced by the attachments of the given repositories
+func GetAttachmentBlobsByRepoIDs(ctx context.Context, repoIDs []int64) ([]*AttachmentBlob, error) {
+	blobs := make([]*AttachmentBlob, 0, 10)
+	return blobs, db.GetEngine(ctx).
+		In("id", builder.Select("blob_id").From("attachment").Where(builder.In("repo_id", repoIDs))).
+		Find(&blobs)
+}
+
+// ReplaceAttachmentBlob makes the attachments of the given repositories reference another blob with the same content
+func ReplaceAttachmentBlob(ctx context.Context, repoIDs []int64, oldBlob, newBlob *AttachmentBlob) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		cnt, err := db.GetEngine(ctx).
+			Where(builder.In("repo_id", repoIDs).And(builder.Eq{"blob_id": oldBlob.ID})).
+			Cols("blob_id", "has_thumbnail").
+			NoAutoCondition().
+			Update(&Attachment{BlobID: newBlob.ID, HasThumbnail: newBlob.HasThumbnail})
+		if err != nil || cnt == 0 {
+			return err
+		}
+		if err := IncreaseAttachmentBlobRefCount(ctx, oldBlob.ID, -cnt); err != nil {
+			return err
+		}
+		return IncreaseAttachmentBlobRefCount(ctx, newBlob.ID, cnt)
+	})
+}
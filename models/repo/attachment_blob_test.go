@@ -0,0 +1,65 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAttachmentBlobRelativePath(t *testing.T) {
+	hash := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	assert.Equal(t, "cas/2c/f2/"+hash, repo_model.AttachmentBlobRelativePath(hash))
+	assert.Equal(t, "thumbnails/cas/2c/f2/"+hash, repo_model.AttachmentBlobThumbnailRelativePath(hash))
+
+	attach := &repo_model.Attachment{UUID: "a0eebc99-9c0b-4ef8-bb6d-6bb9bd380a11", ContentHash: hash, BlobID: 1, HasThumbnail: true}
+	assert.Equal(t, repo_model.AttachmentBlobRelativePath(hash), attach.RelativePath())
+	assert.Equal(t, repo_model.AttachmentBlobThumbnailRelativePath(hash), attach.ThumbnailRelativePath())
+	assert.Empty(t, attach.ObjectPaths())
+}
+
+func TestAttachmentBlobRefCount(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	hash := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+	blob := &repo_model.AttachmentBlob{HashSHA256: hash, Size: 5}
+	assert.NoError(t, repo_model.InsertAttachmentBlob(db.DefaultContext, blob))
+
+	attach, err := repo_model.GetAttachmentByID(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.NoError(t, repo_model.SetAttachmentBlob(db.DefaultContext, attach, blob))
+	blob = unittest.AssertExistsAndLoadBean(t, &repo_model.AttachmentBlob{ID: blob.ID})
+	assert.EqualValues(t, 1, blob.RefCount)
+
+	exist, err := repo_model.ExistLegacyAttachmentsWithObjectID(db.DefaultContext, attach.UUID)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+
+	// referenced blobs are never unreferenced, even if their count is wrong
+	assert.NoError(t, repo_model.IncreaseAttachmentBlobRefCount(db.DefaultContext, blob.ID, -1))
+	blobs, err := repo_model.FindUnreferencedAttachmentBlobs(db.DefaultContext, timeutil.TimeStampNow()+1)
+	assert.NoError(t, err)
+	assert.Empty(t, blobs)
+
+	assert.NoError(t, repo_model.RecountAttachmentBlobRefs(db.DefaultContext))
+	blob = unittest.AssertExistsAndLoadBean(t, &repo_model.AttachmentBlob{ID: blob.ID})
+	assert.EqualValues(t, 1, blob.RefCount)
+
+	assert.NoError(t, repo_model.DeleteAttachment(attach, false))
+	assert.NoError(t, repo_model.RecountAttachmentBlobRefs(db.DefaultContext))
+	blobs, err = repo_model.FindUnreferencedAttachmentBlobs(db.DefaultContext, timeutil.TimeStampNow()+1)
+	assert.NoError(t, err)
+	if assert.Len(t, blobs, 1) {
+		deleted, err := repo_model.DeleteAttachmentBlob(db.DefaultContext, blobs[0])
+		assert.NoError(t, err)
+		assert.True(t, deleted)
+	}
+	unittest.AssertNotExistsBean(t, &repo_model.AttachmentBlob{ID: blob.ID})
+}
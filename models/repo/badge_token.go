@@ -0,0 +1,82 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// BadgeToken allows to embed the badges of a private repository. The token is signed with the secret key of the
+// instance and the salt, regenerating the salt revokes the previous token.
+type BadgeToken struct {
+	ID          int64              `xorm:"pk autoincr"`
+	RepoID      int64              `xorm:"UNIQUE NOT NULL"`
+	Salt        string             `xorm:"VARCHAR(40) NOT NULL"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(BadgeToken))
+}
+
+// Token returns the token to add to the URLs of the badges
+func (t *BadgeToken) Token() string {
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	_, _ = mac.Write([]byte("badge:" + strconv.FormatInt(t.RepoID, 10) + ":" + t.Salt))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a token given with the URL of a badge
+func (t *BadgeToken) Verify(token string) bool {
+	return hmac.Equal([]byte(t.Token()), []byte(token))
+}
+
+// GetBadgeToken returns the badge token of a repository, or nil if none was generated
+func GetBadgeToken(ctx context.Context, repoID int64) (*BadgeToken, error) {
+	t := &BadgeToken{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return t, nil
+}
+
+// RegenerateBadgeToken generates a new badge token of a repository, the previous token becomes invalid
+func RegenerateBadgeToken(ctx context.Context, repoID int64) (*BadgeToken, error) {
+	salt, err := util.CryptoRandomString(40)
+	if err != nil {
+		return nil, err
+	}
+	t := &BadgeToken{RepoID: repoID, Salt: salt}
+	return t, db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetBadgeToken(ctx, repoID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return db.Insert(ctx, t)
+		}
+		t.ID = existing.ID
+		t.CreatedUnix = existing.CreatedUnix
+		_, err = db.GetEngine(ctx).ID(t.ID).Cols("salt").Update(t)
+		return err
+	})
+}
+
+// DeleteBadgeToken revokes the badge token of a repository
+func DeleteBadgeToken(ctx context.Context, repoID int64) error {
+	_, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(new(BadgeToken))
+	return err
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBadgeToken(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	token, err := repo_model.GetBadgeToken(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.Nil(t, token)
+
+	token, err = repo_model.RegenerateBadgeToken(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.True(t, token.Verify(token.Token()))
+	assert.False(t, token.Verify(""))
+	previous := token.Token()
+
+	// regenerating the token revokes the previous one
+	_, err = repo_model.RegenerateBadgeToken(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	token, err = repo_model.GetBadgeToken(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.False(t, token.Verify(previous))
+	unittest.AssertCount(t, &repo_model.BadgeToken{RepoID: 2}, 1)
+
+	// the token of a repository isn't valid for another one
+	assert.False(t, (&repo_model.BadgeToken{RepoID: 3, Salt: token.Salt}).Verify(token.Token()))
+
+	assert.NoError(t, repo_model.DeleteBadgeToken(db.DefaultContext, 2))
+	unittest.AssertCount(t, &repo_model.BadgeToken{RepoID: 2}, 0)
+}
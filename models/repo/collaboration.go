@@ -13,6 +13,8 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
 )
 
 // Collaboration represent the relation between an individual and a repository.
@@ -23,6 +25,13 @@ type Collaboration struct {
 	Mode        perm.AccessMode    `xorm:"DEFAULT 2 NOT NULL"`
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
+	// ExpiresUnix is the time the collaboration is removed, 0 if it doesn't expire
+	ExpiresUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+}
+
+// IsExpired returns true if the collaboration has expired
+func (c *Collaboration) IsExpired() bool {
+	return c.ExpiresUnix != 0 && c.ExpiresUnix <= timeutil.TimeStampNow()
 }
 
 func init() {
@@ -156,3 +165,32 @@ func IsOwnerMemberCollaborator(repo *Repository, userID int64) (bool, error) {
 
 	return db.GetEngine(db.DefaultContext).Get(&Collaboration{RepoID: repo.ID, UserID: userID})
 }
+
+// SetCollaborationExpiry sets the time the collaboration expires, 0 if it doesn't expire
+func SetCollaborationExpiry(ctx context.Context, repoID, uid int64, expires timeutil.TimeStamp) error {
+	_, err := db.GetEngine(ctx).
+		Where("repo_id = ? AND user_id = ?", repoID, uid).
+		Cols("expires_unix").
+		Update(&Collaboration{ExpiresUnix: expires})
+	return err
+}
+
+// FindExpiredCollaborations returns the collaborations which have expired
+func FindExpiredCollaborations(ctx context.Context) ([]*Collaboration, error) {
+	collaborations := make([]*Collaboration, 0, 10)
+	return collaborations, db.GetEngine(ctx).
+		Where("expires_unix > 0 AND expires_unix <= ?", timeutil.TimeStampNow()).
+		Find(&collaborations)
+}
+
+// FindOutsideCollaborations returns the collaborations on the repositories of the organization
+// of the users who aren't members of the organization
+func FindOutsideCollaborations(ctx context.Context, orgID int64) ([]*Collaboration, error) {
+	collaborations := make([]*Collaboration, 0, 10)
+	return collaborations, db.GetEngine(ctx).
+		Join("INNER", "repository", "repository.id = collaboration.repo_id").
+		Where("repository.owner_id = ?", orgID).
+		And(builder.NotIn("collaboration.user_id", builder.Select("uid").From("org_user").Where(builder.Eq{"org_id": orgID}))).
+		OrderBy("collaboration.user_id, repository.lower_name").
+		Find(&collaborations)
+}
@@ -11,6 +11,7 @@ import (
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -68,3 +69,30 @@ func TestRepository_ChangeCollaborationAccessMode(t *testing.T) {
 
 	unittest.CheckConsistencyFor(t, &repo_model.Repository{ID: repo.ID})
 }
+
+func TestRepository_CollaborationExpiry(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	expired, err := repo_model.FindExpiredCollaborations(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+
+	assert.NoError(t, repo_model.SetCollaborationExpiry(db.DefaultContext, 3, 2, timeutil.TimeStampNow()-10))
+	assert.NoError(t, repo_model.SetCollaborationExpiry(db.DefaultContext, 4, 4, timeutil.TimeStampNow()+3600))
+
+	expired, err = repo_model.FindExpiredCollaborations(db.DefaultContext)
+	assert.NoError(t, err)
+	if assert.Len(t, expired, 1) {
+		assert.EqualValues(t, 3, expired[0].RepoID)
+		assert.EqualValues(t, 2, expired[0].UserID)
+		assert.True(t, expired[0].IsExpired())
+	}
+
+	collaboration := unittest.AssertExistsAndLoadBean(t, &repo_model.Collaboration{RepoID: 4, UserID: 4})
+	assert.False(t, collaboration.IsExpired())
+
+	assert.NoError(t, repo_model.SetCollaborationExpiry(db.DefaultContext, 3, 2, 0))
+	expired, err = repo_model.FindExpiredCollaborations(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+}
@@ -0,0 +1,166 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/hex"
+	"fmt"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// DownloadLink is a secret, expiring link to download an attachment or an archive of a repository without
+// authentication. Revoked links are kept as record of the shared downloads.
+type DownloadLink struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"INDEX NOT NULL"`
+	// AttachmentID is the attachment the link serves, 0 for archives
+	AttachmentID int64 `xorm:"INDEX NOT NULL DEFAULT 0"`
+	// ArchiveName, ArchiveType and CommitID describe the archive the link serves,
+	// it is pinned to the commit the ref pointed to when the link was created
+	ArchiveName string
+	ArchiveType git.ArchiveType `xorm:"NOT NULL DEFAULT 0"`
+	CommitID    string          `xorm:"VARCHAR(64)"`
+
+	Token          string `xorm:"-"`
+	TokenHash      string `xorm:"UNIQUE"` // sha256 of token
+	TokenSalt      string
+	TokenLastEight string `xorm:"INDEX"`
+
+	CreatorID     int64              `xorm:"NOT NULL"`
+	Creator       *user_model.User   `xorm:"-"`
+	DownloadCount int64              `xorm:"NOT NULL DEFAULT 0"`
+	ExpiresUnix   timeutil.TimeStamp `xorm:"NOT NULL"`
+	RevokerID     int64              `xorm:"NOT NULL DEFAULT 0"`
+	Revoker       *user_model.User   `xorm:"-"`
+	RevokedUnix   timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix   timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(DownloadLink))
+}
+
+// ErrDownloadLinkNotExist represents a "DownloadLinkNotExist" kind of error.
+type ErrDownloadLinkNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrDownloadLinkNotExist checks if an error is a ErrDownloadLinkNotExist.
+func IsErrDownloadLinkNotExist(err error) bool {
+	_, ok := err.(ErrDownloadLinkNotExist)
+	return ok
+}
+
+func (err ErrDownloadLinkNotExist) Error() string {
+	return fmt.Sprintf("download link does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+func (err ErrDownloadLinkNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// IsActive returns if the link is neither revoked nor expired
+func (l *DownloadLink) IsActive() bool {
+	return l.RevokedUnix == 0 && l.ExpiresUnix > timeutil.TimeStampNow()
+}
+
+// Link returns the URL of the link, only known after it is created
+func (l *DownloadLink) Link() string {
+	return setting.AppURL + "repo/shared/" + l.Token
+}
+
+// LoadAttributes loads the creator and the revoker of the link
+func (l *DownloadLink) LoadAttributes(ctx context.Context) (err error) {
+	if l.Creator == nil {
+		if l.Creator, err = user_model.GetPossibleUserByID(ctx, l.CreatorID); err != nil {
+			return err
+		}
+	}
+	if l.Revoker == nil && l.RevokerID > 0 {
+		if l.Revoker, err = user_model.GetPossibleUserByID(ctx, l.RevokerID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CreateDownloadLink creates a download link and generates its token
+func CreateDownloadLink(ctx context.Context, l *DownloadLink) error {
+	salt, err := util.CryptoRandomString(10)
+	if err != nil {
+		return err
+	}
+	buf, err := util.CryptoRandomBytes(20)
+	if err != nil {
+		return err
+	}
+	l.Token = hex.EncodeToString(buf)
+	l.TokenSalt = salt
+	l.TokenHash = auth_model.HashToken(l.Token, salt)
+	l.TokenLastEight = l.Token[len(l.Token)-8:]
+	return db.Insert(ctx, l)
+}
+
+// GetDownloadLinkByToken returns the download link with the token, even if it is not active anymore
+func GetDownloadLinkByToken(ctx context.Context, token string) (*DownloadLink, error) {
+	if len(token) < 8 {
+		return nil, ErrDownloadLinkNotExist{}
+	}
+
+	var links []*DownloadLink
+	if err := db.GetEngine(ctx).Where("token_last_eight = ?", token[len(token)-8:]).Find(&links); err != nil {
+		return nil, err
+	}
+	for _, l := range links {
+		if subtle.ConstantTimeCompare([]byte(l.TokenHash), []byte(auth_model.HashToken(token, l.TokenSalt))) == 1 {
+			return l, nil
+		}
+	}
+	return nil, ErrDownloadLinkNotExist{}
+}
+
+// GetDownloadLinkByID returns a download link of a repository
+func GetDownloadLinkByID(ctx context.Context, repoID, id int64) (*DownloadLink, error) {
+	l := &DownloadLink{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Get(l)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrDownloadLinkNotExist{ID: id, RepoID: repoID}
+	}
+	return l, nil
+}
+
+// GetDownloadLinksByRepoID returns the download links of a repository, including the revoked and expired ones
+func GetDownloadLinksByRepoID(ctx context.Context, repoID int64) ([]*DownloadLink, error) {
+	links := make([]*DownloadLink, 0, 10)
+	return links, db.GetEngine(ctx).Where("repo_id = ?", repoID).OrderBy("id DESC").Find(&links)
+}
+
+// RevokeDownloadLink revokes a download link, revoking it again keeps the first revocation
+func RevokeDownloadLink(ctx context.Context, l *DownloadLink, doerID int64) error {
+	if l.RevokedUnix != 0 {
+		return nil
+	}
+	l.RevokerID = doerID
+	l.RevokedUnix = timeutil.TimeStampNow()
+	_, err := db.GetEngine(ctx).ID(l.ID).Cols("revoker_id", "revoked_unix").Update(l)
+	return err
+}
+
+// IncreaseDownloadCount increases the download count of a download link
+func (l *DownloadLink) IncreaseDownloadCount(ctx context.Context) error {
+	_, err := db.GetEngine(ctx).Exec("UPDATE `download_link` SET download_count=download_count+1 WHERE id=?", l.ID)
+	return err
+}
@@ -0,0 +1,67 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDownloadLink(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	link := &repo_model.DownloadLink{
+		RepoID:       1,
+		AttachmentID: 1,
+		CreatorID:    2,
+		ExpiresUnix:  timeutil.TimeStampNow().Add(3600),
+	}
+	assert.NoError(t, repo_model.CreateDownloadLink(db.DefaultContext, link))
+	assert.Len(t, link.Token, 40)
+	assert.NotEqual(t, link.Token, link.TokenHash)
+	assert.True(t, link.IsActive())
+
+	byToken, err := repo_model.GetDownloadLinkByToken(db.DefaultContext, link.Token)
+	assert.NoError(t, err)
+	assert.Equal(t, link.ID, byToken.ID)
+	assert.Empty(t, byToken.Token)
+
+	_, err = repo_model.GetDownloadLinkByToken(db.DefaultContext, "0000"+link.Token[4:])
+	assert.True(t, repo_model.IsErrDownloadLinkNotExist(err))
+	_, err = repo_model.GetDownloadLinkByToken(db.DefaultContext, "")
+	assert.True(t, repo_model.IsErrDownloadLinkNotExist(err))
+
+	assert.NoError(t, byToken.IncreaseDownloadCount(db.DefaultContext))
+	_, err = repo_model.GetDownloadLinkByID(db.DefaultContext, 2, link.ID)
+	assert.True(t, repo_model.IsErrDownloadLinkNotExist(err))
+	link, err = repo_model.GetDownloadLinkByID(db.DefaultContext, 1, link.ID)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, link.DownloadCount)
+
+	assert.NoError(t, repo_model.RevokeDownloadLink(db.DefaultContext, link, 1))
+	revokedUnix := link.RevokedUnix
+	assert.False(t, link.IsActive())
+	// revoking again keeps the first revocation
+	assert.NoError(t, repo_model.RevokeDownloadLink(db.DefaultContext, link, 2))
+	assert.EqualValues(t, 1, link.RevokerID)
+	assert.Equal(t, revokedUnix, link.RevokedUnix)
+
+	assert.NoError(t, link.LoadAttributes(db.DefaultContext))
+	assert.EqualValues(t, 2, link.Creator.ID)
+	assert.EqualValues(t, 1, link.Revoker.ID)
+
+	links, err := repo_model.GetDownloadLinksByRepoID(db.DefaultContext, 1)
+	assert.NoError(t, err)
+	assert.Len(t, links, 1)
+
+	expired := &repo_model.DownloadLink{ExpiresUnix: timeutil.TimeStamp(time.Now().Add(-time.Minute).Unix())}
+	assert.False(t, expired.IsActive())
+}
@@ -0,0 +1,95 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// EditSessionRefPrefix is the prefix of the refs the changes of editing sessions accumulate in
+const EditSessionRefPrefix = "refs/edit-sessions/"
+
+// EditSession collects the web edits of a user to a branch in a temporary ref,
+// they are squashed into a single commit when the session is finished
+type EditSession struct {
+	ID         int64  `xorm:"pk autoincr"`
+	RepoID     int64  `xorm:"INDEX NOT NULL"`
+	UserID     int64  `xorm:"INDEX NOT NULL"`
+	BaseBranch string `xorm:"NOT NULL"`
+	// BaseCommitID is the commit of the base branch the changes are based on, HeadCommitID contains all changes
+	BaseCommitID string             `xorm:"VARCHAR(64) NOT NULL"`
+	HeadCommitID string             `xorm:"VARCHAR(64) NOT NULL"`
+	NumEdits     int                `xorm:"NOT NULL DEFAULT 0"`
+	ExpiresUnix  timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(EditSession))
+}
+
+// RefName returns the name of the ref the changes of the session accumulate in
+func (s *EditSession) RefName() string {
+	return fmt.Sprintf("%s%d", EditSessionRefPrefix, s.ID)
+}
+
+// HasChanges returns if any edit was made in the session
+func (s *EditSession) HasChanges() bool {
+	return s.HeadCommitID != s.BaseCommitID
+}
+
+// Touch extends the lifetime of the session after a change
+func (s *EditSession) Touch() {
+	s.ExpiresUnix = timeutil.TimeStampNow().AddDuration(setting.Repository.Editor.SessionLifetime)
+}
+
+// CreateEditSession creates an editing session
+func CreateEditSession(ctx context.Context, s *EditSession) error {
+	s.Touch()
+	return db.Insert(ctx, s)
+}
+
+// GetUserEditSession returns the editing session of a user for a branch, or nil if the user has none
+func GetUserEditSession(ctx context.Context, repoID, userID int64, branch string) (*EditSession, error) {
+	s := &EditSession{}
+	has, err := db.GetEngine(ctx).
+		Where("repo_id = ? AND user_id = ? AND base_branch = ? AND expires_unix > ?", repoID, userID, branch, timeutil.TimeStampNow()).
+		Desc("id").
+		Get(s)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return s, nil
+}
+
+// UpdateEditSession records a change of an editing session and extends its lifetime
+func UpdateEditSession(ctx context.Context, s *EditSession) error {
+	s.Touch()
+	_, err := db.GetEngine(ctx).ID(s.ID).Cols("base_commit_id", "head_commit_id", "num_edits", "expires_unix").Update(s)
+	return err
+}
+
+// DeleteEditSession deletes an editing session, its ref has to be removed by the caller
+func DeleteEditSession(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Delete(new(EditSession))
+	return err
+}
+
+// FindExpiredEditSessions returns the editing sessions which weren't changed within their lifetime
+func FindExpiredEditSessions(ctx context.Context, limit int) ([]*EditSession, error) {
+	sessions := make([]*EditSession, 0, limit)
+	return sessions, db.GetEngine(ctx).
+		Where("expires_unix <= ?", timeutil.TimeStampNow()).
+		Asc("id").
+		Limit(limit).
+		Find(&sessions)
+}
@@ -0,0 +1,72 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEditSession(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	s, err := repo_model.GetUserEditSession(db.DefaultContext, 1, 2, "master")
+	assert.NoError(t, err)
+	assert.Nil(t, s)
+
+	s = &repo_model.EditSession{RepoID: 1, UserID: 2, BaseBranch: "master", BaseCommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d", HeadCommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d"}
+	assert.NoError(t, repo_model.CreateEditSession(db.DefaultContext, s))
+	assert.Equal(t, fmt.Sprintf("%s%d", repo_model.EditSessionRefPrefix, s.ID), s.RefName())
+	assert.False(t, s.HasChanges())
+
+	s.HeadCommitID = "2a47ca4b614a9f5a43abbd5ad851a54a616ffee6"
+	s.NumEdits++
+	assert.NoError(t, repo_model.UpdateEditSession(db.DefaultContext, s))
+
+	s, err = repo_model.GetUserEditSession(db.DefaultContext, 1, 2, "master")
+	assert.NoError(t, err)
+	if assert.NotNil(t, s) {
+		assert.True(t, s.HasChanges())
+		assert.Equal(t, 1, s.NumEdits)
+	}
+
+	// sessions of other users or branches are not returned
+	other, err := repo_model.GetUserEditSession(db.DefaultContext, 1, 2, "develop")
+	assert.NoError(t, err)
+	assert.Nil(t, other)
+	other, err = repo_model.GetUserEditSession(db.DefaultContext, 1, 4, "master")
+	assert.NoError(t, err)
+	assert.Nil(t, other)
+
+	expired, err := repo_model.FindExpiredEditSessions(db.DefaultContext, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, expired)
+
+	defer func(lifetime time.Duration) {
+		setting.Repository.Editor.SessionLifetime = lifetime
+	}(setting.Repository.Editor.SessionLifetime)
+	setting.Repository.Editor.SessionLifetime = -time.Hour
+	assert.NoError(t, repo_model.UpdateEditSession(db.DefaultContext, s))
+
+	// an expired session can't be continued
+	other, err = repo_model.GetUserEditSession(db.DefaultContext, 1, 2, "master")
+	assert.NoError(t, err)
+	assert.Nil(t, other)
+	expired, err = repo_model.FindExpiredEditSessions(db.DefaultContext, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, expired, 1) {
+		assert.Equal(t, s.ID, expired[0].ID)
+	}
+
+	assert.NoError(t, repo_model.DeleteEditSession(db.DefaultContext, s.ID))
+	unittest.AssertNotExistsBean(t, &repo_model.EditSession{ID: s.ID})
+}
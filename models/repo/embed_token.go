@@ -0,0 +1,214 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// EmbedWidget is a widget of a repository which can be embedded by other sites
+type EmbedWidget string
+
+// The widgets which can be embedded
+const (
+	EmbedWidgetIssues   EmbedWidget = "issues"   // counters of the issues and pull requests
+	EmbedWidgetReleases EmbedWidget = "releases" // list of the latest releases
+	EmbedWidgetActivity EmbedWidget = "activity" // feed of the latest activity
+)
+
+// EmbedWidgets are all of the widgets
+var EmbedWidgets = []EmbedWidget{EmbedWidgetIssues, EmbedWidgetReleases, EmbedWidgetActivity}
+
+// IsValid returns true if the widget exists
+func (w EmbedWidget) IsValid() bool {
+	for _, widget := range EmbedWidgets {
+		if w == widget {
+			return true
+		}
+	}
+	return false
+}
+
+// ErrEmbedTokenNotExist represents a "EmbedTokenNotExist" kind of error.
+type ErrEmbedTokenNotExist struct {
+	RepoID int64
+	ID     int64
+}
+
+// IsErrEmbedTokenNotExist checks if an error is a ErrEmbedTokenNotExist.
+func IsErrEmbedTokenNotExist(err error) bool {
+	_, ok := err.(ErrEmbedTokenNotExist)
+	return ok
+}
+
+func (err ErrEmbedTokenNotExist) Error() string {
+	return fmt.Sprintf("embed token does not exist [repo_id: %d, id: %d]", err.RepoID, err.ID)
+}
+
+func (err ErrEmbedTokenNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// EmbedToken allows the sites of its origins to embed some widgets of a repository, without the cookies or the access
+// tokens of a user. The token is signed with the secret key of the instance and a salt, it grants at most the access
+// of its creator.
+type EmbedToken struct {
+	ID             int64              `xorm:"pk autoincr"`
+	RepoID         int64              `xorm:"INDEX NOT NULL"`
+	Name           string             `xorm:"VARCHAR(255) NOT NULL"`
+	AllowedOrigins []string           `xorm:"JSON TEXT"`
+	Widgets        []EmbedWidget      `xorm:"JSON TEXT"`
+	Salt           string             `xorm:"VARCHAR(40) NOT NULL"`
+	CreatorID      int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix    timeutil.TimeStamp `xorm:"created"`
+}
+
+func init() {
+	db.RegisterModel(new(EmbedToken))
+}
+
+func (t *EmbedToken) signature() string {
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	_, _ = mac.Write([]byte("embed:" + strconv.FormatInt(t.ID, 10) + ":" + strconv.FormatInt(t.RepoID, 10) + ":" + t.Salt))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Token returns the token to add to the URLs of the widgets, it starts with the ID of the embed token
+func (t *EmbedToken) Token() string {
+	return strconv.FormatInt(t.ID, 10) + "_" + t.signature()
+}
+
+// HasWidget returns true if the token allows to embed the widget
+func (t *EmbedToken) HasWidget(widget EmbedWidget) bool {
+	for _, w := range t.Widgets {
+		if w == widget {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOriginAllowed returns true if the site of the origin may embed the widgets
+func (t *EmbedToken) IsOriginAllowed(origin string) bool {
+	origin, err := NormalizeEmbedOrigin(origin)
+	if err != nil {
+		return false
+	}
+	return util.SliceContainsString(t.AllowedOrigins, origin)
+}
+
+// NormalizeEmbedOrigin checks that an origin is the scheme and the host of a http(s) site, e.g. https://example.com
+// or http://localhost:8080, and returns it in lower case
+func NormalizeEmbedOrigin(origin string) (string, error) {
+	u, err := url.Parse(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+		u.User != nil || u.Path != "" || u.RawQuery != "" || u.Fragment != "" {
+		return "", util.NewInvalidArgumentErrorf("invalid origin %q, it must be the scheme and the host of a site", origin)
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), nil
+}
+
+// ParseEmbedToken returns the ID of the embed token of a token given with the URL of a widget, or 0 if the token
+// is malformed
+func ParseEmbedToken(token string) int64 {
+	idStr, _, ok := strings.Cut(token, "_")
+	if !ok {
+		return 0
+	}
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil || id <= 0 {
+		return 0
+	}
+	return id
+}
+
+// GetEmbedTokenByToken returns the embed token of a repository which signed the token given with the URL of a widget
+func GetEmbedTokenByToken(ctx context.Context, repoID int64, token string) (*EmbedToken, error) {
+	t, err := GetEmbedToken(ctx, repoID, ParseEmbedToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if !hmac.Equal([]byte(t.Token()), []byte(token)) {
+		return nil, ErrEmbedTokenNotExist{RepoID: repoID, ID: t.ID}
+	}
+	return t, nil
+}
+
+// GetEmbedToken returns an embed token of a repository
+func GetEmbedToken(ctx context.Context, repoID, id int64) (*EmbedToken, error) {
+	t := &EmbedToken{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrEmbedTokenNotExist{RepoID: repoID, ID: id}
+	}
+	return t, nil
+}
+
+// FindEmbedTokens returns the embed tokens of a repository
+func FindEmbedTokens(ctx context.Context, repoID int64) ([]*EmbedToken, error) {
+	tokens := make([]*EmbedToken, 0, 5)
+	return tokens, db.GetEngine(ctx).Where("repo_id = ?", repoID).OrderBy("id").Find(&tokens)
+}
+
+// CreateEmbedToken validates and creates an embed token with a new salt
+func CreateEmbedToken(ctx context.Context, t *EmbedToken) error {
+	t.Name = strings.TrimSpace(t.Name)
+	if t.Name == "" {
+		return util.NewInvalidArgumentErrorf("the name of the embed token is empty")
+	}
+	if len(t.AllowedOrigins) == 0 {
+		return util.NewInvalidArgumentErrorf("an embed token needs at least one allowed origin")
+	}
+	origins := make([]string, 0, len(t.AllowedOrigins))
+	for _, origin := range t.AllowedOrigins {
+		origin, err := NormalizeEmbedOrigin(origin)
+		if err != nil {
+			return err
+		}
+		if !util.SliceContainsString(origins, origin) {
+			origins = append(origins, origin)
+		}
+	}
+	t.AllowedOrigins = origins
+	if len(t.Widgets) == 0 {
+		return util.NewInvalidArgumentErrorf("an embed token needs at least one widget")
+	}
+	for _, widget := range t.Widgets {
+		if !widget.IsValid() {
+			return util.NewInvalidArgumentErrorf("unknown widget %q", widget)
+		}
+	}
+
+	salt, err := util.CryptoRandomString(40)
+	if err != nil {
+		return err
+	}
+	t.Salt = salt
+	return db.Insert(ctx, t)
+}
+
+// DeleteEmbedToken revokes an embed token of a repository
+func DeleteEmbedToken(ctx context.Context, repoID, id int64) error {
+	n, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Delete(new(EmbedToken))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrEmbedTokenNotExist{RepoID: repoID, ID: id}
+	}
+	return nil
+}
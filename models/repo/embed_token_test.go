@@ -0,0 +1,86 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeEmbedOrigin(t *testing.T) {
+	for origin, expected := range map[string]string{
+		"https://Portal.example.com":  "https://portal.example.com",
+		"http://localhost:8080/":      "http://localhost:8080",
+		" https://example.com ":       "https://example.com",
+		"https://example.com/path":    "",
+		"https://user@example.com":    "",
+		"https://example.com/?a=b":    "",
+		"ftp://example.com":           "",
+		"example.com":                 "",
+		"":                            "",
+		"https://example.com#section": "",
+	} {
+		actual, err := repo_model.NormalizeEmbedOrigin(origin)
+		if expected == "" {
+			assert.ErrorIs(t, err, util.ErrInvalidArgument, origin)
+		} else {
+			assert.NoError(t, err, origin)
+			assert.Equal(t, expected, actual)
+		}
+	}
+}
+
+func TestEmbedToken(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	err := repo_model.CreateEmbedToken(db.DefaultContext, &repo_model.EmbedToken{RepoID: 2, Name: "portal"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	err = repo_model.CreateEmbedToken(db.DefaultContext, &repo_model.EmbedToken{
+		RepoID: 2, Name: "portal", AllowedOrigins: []string{"https://example.com"}, Widgets: []repo_model.EmbedWidget{"unknown"},
+	})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	token := &repo_model.EmbedToken{
+		RepoID:         2,
+		Name:           " portal ",
+		AllowedOrigins: []string{"https://Example.com", "https://example.com/"},
+		Widgets:        []repo_model.EmbedWidget{repo_model.EmbedWidgetIssues},
+		CreatorID:      2,
+	}
+	assert.NoError(t, repo_model.CreateEmbedToken(db.DefaultContext, token))
+	assert.Equal(t, "portal", token.Name)
+	assert.Equal(t, []string{"https://example.com"}, token.AllowedOrigins)
+	assert.True(t, token.IsOriginAllowed("https://EXAMPLE.com"))
+	assert.False(t, token.IsOriginAllowed("https://example.org"))
+	assert.True(t, token.HasWidget(repo_model.EmbedWidgetIssues))
+	assert.False(t, token.HasWidget(repo_model.EmbedWidgetActivity))
+	assert.Equal(t, token.ID, repo_model.ParseEmbedToken(token.Token()))
+
+	loaded, err := repo_model.GetEmbedTokenByToken(db.DefaultContext, 2, token.Token())
+	assert.NoError(t, err)
+	assert.Equal(t, token.ID, loaded.ID)
+	assert.Equal(t, token.Widgets, loaded.Widgets)
+
+	// the token is only valid for its repository and with its signature
+	_, err = repo_model.GetEmbedTokenByToken(db.DefaultContext, 3, token.Token())
+	assert.True(t, repo_model.IsErrEmbedTokenNotExist(err))
+	_, err = repo_model.GetEmbedTokenByToken(db.DefaultContext, 2, token.Token()+"0")
+	assert.True(t, repo_model.IsErrEmbedTokenNotExist(err))
+	_, err = repo_model.GetEmbedTokenByToken(db.DefaultContext, 2, "malformed")
+	assert.True(t, repo_model.IsErrEmbedTokenNotExist(err))
+
+	tokens, err := repo_model.FindEmbedTokens(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.Len(t, tokens, 1)
+
+	assert.NoError(t, repo_model.DeleteEmbedToken(db.DefaultContext, 2, token.ID))
+	assert.True(t, repo_model.IsErrEmbedTokenNotExist(repo_model.DeleteEmbedToken(db.DefaultContext, 2, token.ID)))
+	unittest.AssertCount(t, &repo_model.EmbedToken{RepoID: 2}, 0)
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 
 	"xorm.io/builder"
@@ -20,6 +21,20 @@ func GetRepositoriesByForkID(ctx context.Context, forkID int64) ([]*Repository,
 		Find(&repos)
 }
 
+// FindAccessibleForksOfRepos returns up to limit direct forks of the given repositories whose code the user can read
+func FindAccessibleForksOfRepos(ctx context.Context, doer *user_model.User, repoIDs []int64, limit int) ([]*Repository, error) {
+	repos := make([]*Repository, 0, 10)
+	if len(repoIDs) == 0 || limit <= 0 {
+		return repos, nil
+	}
+	return repos, db.GetEngine(ctx).
+		Where(builder.In("fork_id", repoIDs)).
+		And(AccessibleRepositoryCondition(doer, unit.TypeCode)).
+		OrderBy("id").
+		Limit(limit).
+		Find(&repos)
+}
+
 // GetForkedRepo checks if given user has already forked a repository with given ID.
 func GetForkedRepo(ownerID, repoID int64) *Repository {
 	repo := new(Repository)
@@ -0,0 +1,63 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// ForkDivergence caches how far the default branch of a fork is ahead or behind
+// the default branch of the repository it was forked from
+type ForkDivergence struct {
+	ID         int64 `xorm:"pk autoincr"`
+	RepoID     int64 `xorm:"UNIQUE NOT NULL"`
+	BaseRepoID int64 `xorm:"INDEX NOT NULL"`
+	Ahead      int   `xorm:"NOT NULL DEFAULT 0"`
+	Behind     int   `xorm:"NOT NULL DEFAULT 0"`
+	// CommitID and BaseCommitID are the commits the counts were computed for
+	CommitID     string `xorm:"VARCHAR(64)"`
+	BaseCommitID string `xorm:"VARCHAR(64)"`
+	// LastCommitUnix is the time of the latest commit on any branch of the fork
+	LastCommitUnix timeutil.TimeStamp
+	UpdatedUnix    timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ForkDivergence))
+}
+
+// GetForkDivergencesByRepoIDs returns the cached divergences of the given forks, keyed by repository id
+func GetForkDivergencesByRepoIDs(ctx context.Context, repoIDs []int64) (map[int64]*ForkDivergence, error) {
+	divergences := make(map[int64]*ForkDivergence, len(repoIDs))
+	if len(repoIDs) == 0 {
+		return divergences, nil
+	}
+	return divergences, db.GetEngine(ctx).In("repo_id", repoIDs).Find(&divergences)
+}
+
+// SaveForkDivergence creates or updates the cached divergence of a fork
+func SaveForkDivergence(ctx context.Context, d *ForkDivergence) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing := &ForkDivergence{}
+		has, err := db.GetEngine(ctx).Where("repo_id = ?", d.RepoID).Get(existing)
+		if err != nil {
+			return err
+		}
+		if !has {
+			return db.Insert(ctx, d)
+		}
+		d.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(d.ID).AllCols().Update(d)
+		return err
+	})
+}
+
+// DeleteForkDivergencesByBaseRepoID removes the cached divergences of the forks of a repository
+func DeleteForkDivergencesByBaseRepoID(ctx context.Context, baseRepoID int64) error {
+	_, err := db.GetEngine(ctx).Where("base_repo_id = ?", baseRepoID).Delete(new(ForkDivergence))
+	return err
+}
@@ -0,0 +1,51 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForkDivergence(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.NoError(t, repo_model.SaveForkDivergence(db.DefaultContext, &repo_model.ForkDivergence{RepoID: 11, BaseRepoID: 10, Ahead: 1, Behind: 2}))
+	// saving again updates the existing row
+	assert.NoError(t, repo_model.SaveForkDivergence(db.DefaultContext, &repo_model.ForkDivergence{RepoID: 11, BaseRepoID: 10, Ahead: 3}))
+
+	divergences, err := repo_model.GetForkDivergencesByRepoIDs(db.DefaultContext, []int64{11, 12})
+	assert.NoError(t, err)
+	if assert.Len(t, divergences, 1) {
+		assert.Equal(t, 3, divergences[11].Ahead)
+		assert.Equal(t, 0, divergences[11].Behind)
+	}
+	unittest.AssertCount(t, &repo_model.ForkDivergence{RepoID: 11}, 1)
+
+	assert.NoError(t, repo_model.DeleteForkDivergencesByBaseRepoID(db.DefaultContext, 10))
+	unittest.AssertCount(t, &repo_model.ForkDivergence{RepoID: 11}, 0)
+}
+
+func TestFindAccessibleForksOfRepos(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	forks, err := repo_model.FindAccessibleForksOfRepos(db.DefaultContext, nil, []int64{10, 27, 28}, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, forks, 2) {
+		assert.EqualValues(t, 11, forks[0].ID)
+		assert.EqualValues(t, 29, forks[1].ID)
+	}
+
+	// the owner can read the private fork
+	user20 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 20})
+	forks, err = repo_model.FindAccessibleForksOfRepos(db.DefaultContext, user20, []int64{10, 27, 28}, 10)
+	assert.NoError(t, err)
+	assert.Len(t, forks, 3)
+}
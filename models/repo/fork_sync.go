@@ -0,0 +1,148 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ForkSyncStatus is the result of the last scheduled sync of a fork branch
+type ForkSyncStatus int
+
+const (
+	// ForkSyncStatusNone means the branch has not been synced yet
+	ForkSyncStatusNone ForkSyncStatus = iota
+	// ForkSyncStatusSuccess means the branch is up to date with its upstream branch
+	ForkSyncStatusSuccess
+	// ForkSyncStatusDiverged means the branch has commits not in the upstream branch and merging is not allowed
+	ForkSyncStatusDiverged
+	// ForkSyncStatusConflict means merging the upstream branch failed because of conflicts
+	ForkSyncStatusConflict
+	// ForkSyncStatusFailed means the sync failed for another reason
+	ForkSyncStatusFailed
+)
+
+// String returns the name of the status
+func (s ForkSyncStatus) String() string {
+	switch s {
+	case ForkSyncStatusSuccess:
+		return "success"
+	case ForkSyncStatusDiverged:
+		return "diverged"
+	case ForkSyncStatusConflict:
+		return "conflict"
+	case ForkSyncStatusFailed:
+		return "failed"
+	}
+	return "none"
+}
+
+// ForkSync configures a branch of a fork to be updated from its upstream branch on a schedule
+type ForkSync struct {
+	ID             int64  `xorm:"pk autoincr"`
+	RepoID         int64  `xorm:"UNIQUE(s) NOT NULL"`
+	Branch         string `xorm:"UNIQUE(s) NOT NULL"`
+	UpstreamBranch string `xorm:"NOT NULL"`
+	// AllowMerge merges the upstream branch if the branch can't be fast-forwarded
+	AllowMerge bool `xorm:"NOT NULL DEFAULT false"`
+	// DoerID is the user the branch is updated as
+	DoerID int64 `xorm:"NOT NULL"`
+
+	LastStatus   ForkSyncStatus `xorm:"NOT NULL DEFAULT 0"`
+	LastMessage  string         `xorm:"TEXT"`
+	LastSyncUnix timeutil.TimeStamp
+	CreatedUnix  timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix  timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(ForkSync))
+}
+
+// ErrForkSyncNotExist represents a "ForkSyncNotExist" kind of error.
+type ErrForkSyncNotExist struct {
+	RepoID int64
+	Branch string
+}
+
+// IsErrForkSyncNotExist checks if an error is a ErrForkSyncNotExist.
+func IsErrForkSyncNotExist(err error) bool {
+	_, ok := err.(ErrForkSyncNotExist)
+	return ok
+}
+
+func (err ErrForkSyncNotExist) Error() string {
+	return fmt.Sprintf("fork sync does not exist [repo_id: %d, branch: %s]", err.RepoID, err.Branch)
+}
+
+func (err ErrForkSyncNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// GetForkSync returns the sync schedule of a branch of a repository
+func GetForkSync(ctx context.Context, repoID int64, branch string) (*ForkSync, error) {
+	s := &ForkSync{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ? AND branch = ?", repoID, branch).Get(s)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrForkSyncNotExist{RepoID: repoID, Branch: branch}
+	}
+	return s, nil
+}
+
+// GetForkSyncsByRepoID returns the sync schedules of the branches of a repository
+func GetForkSyncsByRepoID(ctx context.Context, repoID int64) ([]*ForkSync, error) {
+	syncs := make([]*ForkSync, 0, 5)
+	return syncs, db.GetEngine(ctx).Where("repo_id = ?", repoID).OrderBy("branch").Find(&syncs)
+}
+
+// SaveForkSync creates or updates the sync schedule of a branch
+func SaveForkSync(ctx context.Context, s *ForkSync) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetForkSync(ctx, s.RepoID, s.Branch)
+		if err != nil && !IsErrForkSyncNotExist(err) {
+			return err
+		}
+		if existing == nil {
+			return db.Insert(ctx, s)
+		}
+		s.ID = existing.ID
+		s.LastStatus = existing.LastStatus
+		s.LastMessage = existing.LastMessage
+		s.LastSyncUnix = existing.LastSyncUnix
+		_, err = db.GetEngine(ctx).ID(s.ID).AllCols().Update(s)
+		return err
+	})
+}
+
+// DeleteForkSync removes the sync schedule of a branch
+func DeleteForkSync(ctx context.Context, repoID int64, branch string) error {
+	n, err := db.GetEngine(ctx).Where("repo_id = ? AND branch = ?", repoID, branch).Delete(new(ForkSync))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrForkSyncNotExist{RepoID: repoID, Branch: branch}
+	}
+	return nil
+}
+
+// IterateForkSyncs calls f for all sync schedules
+func IterateForkSyncs(ctx context.Context, f func(ctx context.Context, s *ForkSync) error) error {
+	return db.Iterate(ctx, nil, f)
+}
+
+// UpdateForkSyncResult records the result of the last sync of a branch
+func UpdateForkSyncResult(ctx context.Context, s *ForkSync, status ForkSyncStatus, message string) error {
+	s.LastStatus = status
+	s.LastMessage = message
+	s.LastSyncUnix = timeutil.TimeStampNow()
+	_, err := db.GetEngine(ctx).ID(s.ID).Cols("last_status", "last_message", "last_sync_unix").NoAutoTime().Update(s)
+	return err
+}
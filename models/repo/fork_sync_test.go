@@ -0,0 +1,40 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForkSync(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	s := &repo_model.ForkSync{RepoID: 11, Branch: "master", UpstreamBranch: "master", DoerID: 13}
+	assert.NoError(t, repo_model.SaveForkSync(db.DefaultContext, s))
+	assert.NoError(t, repo_model.UpdateForkSyncResult(db.DefaultContext, s, repo_model.ForkSyncStatusConflict, "conflict in README.md"))
+
+	// saving again keeps the result of the last sync
+	s = &repo_model.ForkSync{RepoID: 11, Branch: "master", UpstreamBranch: "develop", AllowMerge: true, DoerID: 13}
+	assert.NoError(t, repo_model.SaveForkSync(db.DefaultContext, s))
+
+	syncs, err := repo_model.GetForkSyncsByRepoID(db.DefaultContext, 11)
+	assert.NoError(t, err)
+	if assert.Len(t, syncs, 1) {
+		assert.Equal(t, "develop", syncs[0].UpstreamBranch)
+		assert.True(t, syncs[0].AllowMerge)
+		assert.Equal(t, repo_model.ForkSyncStatusConflict, syncs[0].LastStatus)
+		assert.Equal(t, "conflict", syncs[0].LastStatus.String())
+	}
+
+	assert.NoError(t, repo_model.DeleteForkSync(db.DefaultContext, 11, "master"))
+	_, err = repo_model.GetForkSync(db.DefaultContext, 11, "master")
+	assert.True(t, repo_model.IsErrForkSyncNotExist(err))
+	assert.True(t, repo_model.IsErrForkSyncNotExist(repo_model.DeleteForkSync(db.DefaultContext, 11, "master")))
+}
@@ -0,0 +1,84 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// RepoLicense describes a license detected in the default branch of a repository,
+// or declared by one of its dependencies in a lock file
+type RepoLicense struct { //revive:disable-line:exported
+	ID         int64   `xorm:"pk autoincr"`
+	RepoID     int64   `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	CommitID   string  `xorm:"VARCHAR(64)"`
+	License    string  `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+	Path       string  `xorm:"UNIQUE(s) NOT NULL"`
+	Confidence float64 `xorm:"NOT NULL DEFAULT 0"`
+	// Dependency is the name and version of the dependency declaring the license, empty for the licenses of the repository itself
+	Dependency string `xorm:"UNIQUE(s) NOT NULL DEFAULT ''"`
+	// Forbidden is true if the license policy of the owner forbade the license when it was detected
+	Forbidden   bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX CREATED"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoLicense))
+}
+
+// RepoLicenseList defines a list of repository licenses
+type RepoLicenseList []*RepoLicense //revive:disable-line:exported
+
+// StringList returns the distinct license names of the list
+func (licenses RepoLicenseList) StringList() []string {
+	names := make([]string, 0, len(licenses))
+	seen := make(map[string]bool, len(licenses))
+	for _, l := range licenses {
+		if !seen[l.License] {
+			seen[l.License] = true
+			names = append(names, l.License)
+		}
+	}
+	return names
+}
+
+// ForbiddenList returns the distinct names of the licenses which were forbidden when they were detected
+func (licenses RepoLicenseList) ForbiddenList() []string {
+	names := make([]string, 0, len(licenses))
+	seen := make(map[string]bool, len(licenses))
+	for _, l := range licenses {
+		if l.Forbidden && !seen[l.License] {
+			seen[l.License] = true
+			names = append(names, l.License)
+		}
+	}
+	return names
+}
+
+// GetRepoLicenses returns the licenses detected in a repository
+func GetRepoLicenses(ctx context.Context, repoID int64) (RepoLicenseList, error) {
+	licenses := make(RepoLicenseList, 0, 2)
+	return licenses, db.GetEngine(ctx).Where("repo_id = ?", repoID).Asc("license", "path", "dependency").Find(&licenses)
+}
+
+// UpdateRepoLicenses replaces the detected licenses of a repository
+func UpdateRepoLicenses(ctx context.Context, repoID int64, commitID string, licenses RepoLicenseList) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Delete(new(RepoLicense)); err != nil {
+			return err
+		}
+		for _, l := range licenses {
+			l.ID = 0
+			l.RepoID = repoID
+			l.CommitID = commitID
+		}
+		if len(licenses) == 0 {
+			return nil
+		}
+		return db.Insert(ctx, licenses)
+	})
+}
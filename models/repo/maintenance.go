@@ -0,0 +1,82 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// MaintenanceTask is a housekeeping operation run on a git repository
+type MaintenanceTask string
+
+const (
+	// MaintenanceTaskGC runs 'git gc' to pack loose objects and prune unreachable ones
+	MaintenanceTaskGC MaintenanceTask = "gc"
+	// MaintenanceTaskRepack repacks all packs into a single one
+	MaintenanceTaskRepack MaintenanceTask = "repack"
+	// MaintenanceTaskCommitGraph writes the commit-graph
+	MaintenanceTaskCommitGraph MaintenanceTask = "commit-graph"
+	// MaintenanceTaskMultiPackIndex writes the multi-pack-index
+	MaintenanceTaskMultiPackIndex MaintenanceTask = "multi-pack-index"
+)
+
+// RepoMaintenance records the object statistics of a repository and the result of its last maintenance run
+type RepoMaintenance struct { //revive:disable-line:exported
+	ID              int64              `xorm:"pk autoincr"`
+	RepoID          int64              `xorm:"UNIQUE NOT NULL"`
+	LooseObjects    int64              `xorm:"NOT NULL DEFAULT 0"`
+	Packs           int64              `xorm:"NOT NULL DEFAULT 0"`
+	PackSize        int64              `xorm:"NOT NULL DEFAULT 0"`
+	LastTasks       []MaintenanceTask  `xorm:"JSON TEXT"`
+	LastError       string             `xorm:"TEXT"`
+	LastDuration    int64              `xorm:"NOT NULL DEFAULT 0"` // in milliseconds
+	LastCheckedUnix timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	LastRunUnix     timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoMaintenance))
+}
+
+// GetRepoMaintenance returns the maintenance record of a repository, or nil if it has never been checked
+func GetRepoMaintenance(ctx context.Context, repoID int64) (*RepoMaintenance, error) {
+	m := &RepoMaintenance{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(m)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, nil
+	}
+	return m, nil
+}
+
+// SaveRepoMaintenance creates or updates the maintenance record of a repository
+func SaveRepoMaintenance(ctx context.Context, m *RepoMaintenance) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetRepoMaintenance(ctx, m.RepoID)
+		if err != nil {
+			return err
+		}
+		if existing == nil {
+			return db.Insert(ctx, m)
+		}
+		m.ID = existing.ID
+		_, err = db.GetEngine(ctx).ID(m.ID).AllCols().Update(m)
+		return err
+	})
+}
+
+// FindRepoMaintenances returns the maintenance records, most recently run first
+func FindRepoMaintenances(ctx context.Context, opts db.ListOptions) ([]*RepoMaintenance, int64, error) {
+	sess := db.GetEngine(ctx).OrderBy("last_run_unix DESC, id DESC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+	records := make([]*RepoMaintenance, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&records)
+	return records, count, err
+}
@@ -10,6 +10,8 @@ import (
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
 )
@@ -31,6 +33,10 @@ type Mirror struct {
 	LFS         bool   `xorm:"lfs_enabled NOT NULL DEFAULT false"`
 	LFSEndpoint string `xorm:"lfs_endpoint TEXT"`
 
+	// SyncHookSecretEncrypted is the secret of the webhook the upstream forge calls to trigger a sync,
+	// it should be accessed using SyncHookSecret() and SetSyncHookSecret()
+	SyncHookSecretEncrypted string `xorm:"TEXT"`
+
 	Address string `xorm:"-"`
 }
 
@@ -64,6 +70,33 @@ func (m *Mirror) GetRemoteName() string {
 	return "origin"
 }
 
+// HasSyncHook returns true if the upstream forge can trigger a sync with a webhook
+func (m *Mirror) HasSyncHook() bool {
+	return m.SyncHookSecretEncrypted != ""
+}
+
+// SyncHookSecret returns the decrypted secret of the webhook which triggers a sync
+func (m *Mirror) SyncHookSecret() (string, error) {
+	if m.SyncHookSecretEncrypted == "" {
+		return "", nil
+	}
+	return secret.DecryptSecret(setting.SecretKey, m.SyncHookSecretEncrypted)
+}
+
+// SetSyncHookSecret encrypts and sets the secret of the webhook which triggers a sync, an empty secret disables the webhook
+func (m *Mirror) SetSyncHookSecret(cleartext string) error {
+	if cleartext == "" {
+		m.SyncHookSecretEncrypted = ""
+		return nil
+	}
+	ciphertext, err := secret.EncryptSecret(setting.SecretKey, cleartext)
+	if err != nil {
+		return err
+	}
+	m.SyncHookSecretEncrypted = ciphertext
+	return nil
+}
+
 // ScheduleNextUpdate calculates and sets next update time.
 func (m *Mirror) ScheduleNextUpdate() {
 	if m.Interval != 0 {
@@ -91,6 +124,12 @@ func UpdateMirror(ctx context.Context, m *Mirror) error {
 	return err
 }
 
+// UpdateMirrorSyncHookSecret updates the secret of the webhook which triggers a sync
+func UpdateMirrorSyncHookSecret(ctx context.Context, m *Mirror) error {
+	_, err := db.GetEngine(ctx).ID(m.ID).Cols("sync_hook_secret_encrypted").Update(m)
+	return err
+}
+
 // TouchMirror updates the mirror updatedUnix
 func TouchMirror(ctx context.Context, m *Mirror) error {
 	m.UpdatedUnix = timeutil.TimeStampNow()
@@ -16,6 +16,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/setting"
@@ -110,6 +111,7 @@ const (
 	RepositoryBeingMigrated                           // repository is migrating
 	RepositoryPendingTransfer                         // repository pending in ownership transfer state
 	RepositoryBroken                                  // repository is in a permanently broken state
+	RepositoryPendingDeletion                         // repository is deleted and hidden until it is purged or restored
 )
 
 // Repository represents a git repository.
@@ -146,6 +148,7 @@ type Repository struct {
 	NumOpenActionRuns   int `xorm:"-"`
 
 	IsPrivate  bool `xorm:"INDEX"`
+	IsInternal bool `xorm:"INDEX NOT NULL DEFAULT false"`
 	IsEmpty    bool `xorm:"INDEX"`
 	IsArchived bool `xorm:"INDEX"`
 	IsMirror   bool `xorm:"INDEX"`
@@ -171,6 +174,8 @@ type Repository struct {
 
 	TrustModel TrustModelType
 
+	ObjectFormatName string `xorm:"VARCHAR(6) NOT NULL DEFAULT 'sha1'"`
+
 	// Avatar: ID(10-20)-md5(32) - must fit into 64 symbols
 	Avatar string `xorm:"VARCHAR(64)"`
 
@@ -226,6 +231,21 @@ func (repo *Repository) IsBroken() bool {
 	return repo.Status == RepositoryBroken
 }
 
+// IsPendingDeletion indicates that repository is deleted and waits to be purged
+func (repo *Repository) IsPendingDeletion() bool {
+	return repo.Status == RepositoryPendingDeletion
+}
+
+// ObjectFormat returns the object format of the git repository
+func (repo *Repository) ObjectFormat() git.ObjectFormat {
+	objectFormat, err := git.ParseObjectFormat(repo.ObjectFormatName)
+	if err != nil {
+		log.Error("Repository[%d] has invalid object format %q: %v", repo.ID, repo.ObjectFormatName, err)
+		return git.ObjectFormatSHA1
+	}
+	return objectFormat
+}
+
 // MarkAsBrokenEmpty marks the repo as broken and empty
 func (repo *Repository) MarkAsBrokenEmpty() {
 	repo.Status = RepositoryBroken
@@ -360,6 +380,11 @@ func (repo *Repository) MustGetUnit(ctx context.Context, tp unit.Type) *RepoUnit
 			Type:   tp,
 			Config: new(IssuesConfig),
 		}
+	} else if tp == unit.TypeActions {
+		return &RepoUnit{
+			Type:   tp,
+			Config: new(ActionsConfig),
+		}
 	}
 	return &RepoUnit{
 		Type:   tp,
@@ -487,6 +512,11 @@ func (repo *Repository) RepoPath() string {
 	return RepoPath(repo.OwnerName, repo.Name)
 }
 
+// StorageRegion returns the storage region the data of the repository is pinned to, empty for the default storages
+func (repo *Repository) StorageRegion() string {
+	return user_model.StorageRegionOfID(repo.OwnerID)
+}
+
 // Link returns the repository relative url
 func (repo *Repository) Link() string {
 	return setting.AppSubURL + "/" + url.PathEscape(repo.OwnerName) + "/" + url.PathEscape(repo.Name)
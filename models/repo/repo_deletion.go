@@ -0,0 +1,137 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ErrRepoDeletionNotExist represents a "RepoDeletionNotExist" kind of error.
+type ErrRepoDeletionNotExist struct {
+	RepoID int64
+}
+
+// IsErrRepoDeletionNotExist checks if an error is a ErrRepoDeletionNotExist.
+func IsErrRepoDeletionNotExist(err error) bool {
+	_, ok := err.(ErrRepoDeletionNotExist)
+	return ok
+}
+
+func (err ErrRepoDeletionNotExist) Error() string {
+	return fmt.Sprintf("deleted repository does not exist [repo_id: %d]", err.RepoID)
+}
+
+func (err ErrRepoDeletionNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// RepoDeletion records the deletion of a repository which is kept, hidden, until it is purged or restored
+type RepoDeletion struct {
+	ID     int64 `xorm:"pk autoincr"`
+	RepoID int64 `xorm:"UNIQUE NOT NULL"`
+	// Status is the status of the repository before it was deleted, it is restored with the repository
+	Status      RepositoryStatus   `xorm:"NOT NULL DEFAULT 0"`
+	DeleterID   int64              `xorm:"NOT NULL DEFAULT 0"`
+	DeletedUnix timeutil.TimeStamp `xorm:"created"`
+	PurgeUnix   timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+
+	Repo *Repository `xorm:"-"`
+}
+
+func init() {
+	db.RegisterModel(new(RepoDeletion))
+}
+
+// GetRepoDeletion returns the deletion of a repository pending deletion
+func GetRepoDeletion(ctx context.Context, repoID int64) (*RepoDeletion, error) {
+	d := &RepoDeletion{}
+	has, err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Get(d)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrRepoDeletionNotExist{RepoID: repoID}
+	}
+	return d, nil
+}
+
+// FindRepoDeletions returns the deletions of the repositories of the owners with their repositories,
+// of all repositories if ownerIDs is nil. The repositories purged first come first.
+func FindRepoDeletions(ctx context.Context, ownerIDs []int64) ([]*RepoDeletion, error) {
+	cond := builder.NewCond()
+	if ownerIDs != nil {
+		cond = builder.In("repo_id", builder.Select("id").From("repository").Where(builder.In("owner_id", ownerIDs)))
+	}
+	deletions := make([]*RepoDeletion, 0, 10)
+	if err := db.GetEngine(ctx).Where(cond).Asc("purge_unix", "id").Find(&deletions); err != nil {
+		return nil, err
+	}
+	if len(deletions) == 0 {
+		return deletions, nil
+	}
+
+	repoIDs := make([]int64, 0, len(deletions))
+	for _, d := range deletions {
+		repoIDs = append(repoIDs, d.RepoID)
+	}
+	repos := make(map[int64]*Repository, len(repoIDs))
+	if err := db.GetEngine(ctx).In("id", repoIDs).Find(&repos); err != nil {
+		return nil, err
+	}
+	found := deletions[:0]
+	for _, d := range deletions {
+		if d.Repo = repos[d.RepoID]; d.Repo != nil {
+			found = append(found, d)
+		}
+	}
+	return found, nil
+}
+
+// FindExpiredRepoDeletions returns the deletions of the repositories which are to be purged
+func FindExpiredRepoDeletions(ctx context.Context) ([]*RepoDeletion, error) {
+	deletions := make([]*RepoDeletion, 0, 10)
+	return deletions, db.GetEngine(ctx).Where("purge_unix <= ?", timeutil.TimeStampNow()).Asc("purge_unix").Find(&deletions)
+}
+
+// MarkRepositoryPendingDeletion hides a repository until it is purged or restored
+func MarkRepositoryPendingDeletion(ctx context.Context, repo *Repository, deleterID int64, purgeUnix timeutil.TimeStamp) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		if repo.IsPendingDeletion() {
+			return util.NewInvalidArgumentErrorf("repository is already deleted")
+		}
+		if _, err := db.GetEngine(ctx).Insert(&RepoDeletion{
+			RepoID:    repo.ID,
+			Status:    repo.Status,
+			DeleterID: deleterID,
+			PurgeUnix: purgeUnix,
+		}); err != nil {
+			return err
+		}
+		repo.Status = RepositoryPendingDeletion
+		_, err := db.GetEngine(ctx).ID(repo.ID).Cols("status").NoAutoTime().Update(repo)
+		return err
+	})
+}
+
+// RestoreRepositoryPendingDeletion makes a repository pending deletion visible again with its previous status
+func RestoreRepositoryPendingDeletion(ctx context.Context, repo *Repository) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		d, err := GetRepoDeletion(ctx, repo.ID)
+		if err != nil {
+			return err
+		}
+		if _, err := db.GetEngine(ctx).ID(d.ID).Delete(new(RepoDeletion)); err != nil {
+			return err
+		}
+		repo.Status = d.Status
+		_, err = db.GetEngine(ctx).ID(repo.ID).Cols("status").NoAutoTime().Update(repo)
+		return err
+	})
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRepoDeletion(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	status := repo.Status
+
+	assert.NoError(t, repo_model.MarkRepositoryPendingDeletion(db.DefaultContext, repo, 2, timeutil.TimeStampNow().Add(3600)))
+	repo = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	assert.True(t, repo.IsPendingDeletion())
+	err := repo_model.MarkRepositoryPendingDeletion(db.DefaultContext, repo, 2, timeutil.TimeStampNow())
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+
+	deletions, err := repo_model.FindRepoDeletions(db.DefaultContext, []int64{repo.OwnerID})
+	assert.NoError(t, err)
+	if assert.Len(t, deletions, 1) {
+		assert.EqualValues(t, 1, deletions[0].Repo.ID)
+		assert.EqualValues(t, 2, deletions[0].DeleterID)
+	}
+	deletions, err = repo_model.FindRepoDeletions(db.DefaultContext, []int64{3})
+	assert.NoError(t, err)
+	assert.Empty(t, deletions)
+
+	// the retention didn't expire yet
+	deletions, err = repo_model.FindExpiredRepoDeletions(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Empty(t, deletions)
+
+	assert.NoError(t, repo_model.RestoreRepositoryPendingDeletion(db.DefaultContext, repo))
+	repo = unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	assert.Equal(t, status, repo.Status)
+	unittest.AssertNotExistsBean(t, &repo_model.RepoDeletion{RepoID: 1})
+
+	_, err = repo_model.GetRepoDeletion(db.DefaultContext, 1)
+	assert.True(t, repo_model.IsErrRepoDeletionNotExist(err))
+
+	assert.NoError(t, repo_model.MarkRepositoryPendingDeletion(db.DefaultContext, repo, 2, timeutil.TimeStampNow().AddDuration(-time.Minute)))
+	deletions, err = repo_model.FindExpiredRepoDeletions(db.DefaultContext)
+	assert.NoError(t, err)
+	if assert.Len(t, deletions, 1) {
+		assert.EqualValues(t, 1, deletions[0].RepoID)
+	}
+}
@@ -108,6 +108,68 @@ func (repos RepositoryList) LoadAttributes(ctx context.Context) error {
 	return nil
 }
 
+// LoadOwners loads the owners of the repositories which haven't been loaded yet
+func (repos RepositoryList) LoadOwners(ctx context.Context) error {
+	set := make(container.Set[int64])
+	for _, repo := range repos {
+		if repo.Owner == nil {
+			set.Add(repo.OwnerID)
+		}
+	}
+	if len(set) == 0 {
+		return nil
+	}
+
+	users := make(map[int64]*user_model.User, len(set))
+	if err := db.GetEngine(ctx).
+		Where("id > 0").
+		In("id", set.Values()).
+		Find(&users); err != nil {
+		return fmt.Errorf("find users: %w", err)
+	}
+	for _, repo := range repos {
+		if repo.Owner == nil {
+			repo.Owner = users[repo.OwnerID]
+		}
+	}
+	return nil
+}
+
+// LoadUnits loads the units of the repositories which haven't been loaded yet
+func (repos RepositoryList) LoadUnits(ctx context.Context) error {
+	repoIDs := make([]int64, 0, len(repos))
+	for _, repo := range repos {
+		if repo.Units == nil {
+			repoIDs = append(repoIDs, repo.ID)
+		}
+	}
+	if len(repoIDs) == 0 {
+		return nil
+	}
+
+	units := make([]*RepoUnit, 0, len(repoIDs)*5)
+	if err := db.GetEngine(ctx).
+		In("repo_id", repoIDs).
+		Find(&units); err != nil {
+		return fmt.Errorf("find units: %w", err)
+	}
+	repoUnits := make(map[int64][]*RepoUnit, len(repoIDs))
+	for _, u := range units {
+		if !u.Type.UnitGlobalDisabled() {
+			repoUnits[u.RepoID] = append(repoUnits[u.RepoID], u)
+		}
+	}
+	for _, repo := range repos {
+		if repo.Units == nil {
+			repo.Units = repoUnits[repo.ID]
+			if repo.Units == nil {
+				repo.Units = []*RepoUnit{}
+			}
+		}
+	}
+	return nil
+}
+
 // SearchRepoOptions holds the search options
 type SearchRepoOptions struct {
 	db.ListOptions
@@ -150,6 +212,16 @@ type SearchRepoOptions struct {
 	TopicOnly bool
 	// only search repositories with specified primary language
 	Language string
+	// only search repositories having all of these topics
+	Topics []string
+	// only search repositories of the owner with this name
+	OwnerName string
+	// only search repositories within these ranges of stars, forks,
+	// last update and creation time in unix seconds
+	Stars   Int64Range
+	Forks   Int64Range
+	Pushed  Int64Range
+	Created Int64Range
 	// include description in keyword search
 	IncludeDescription bool
 	// None -> include has milestones AND has no milestone
@@ -329,6 +401,24 @@ func userOrgPublicRepoCondPrivate(userID int64) builder.Cond {
 	)
 }
 
+// internalRepoCond returns the condition that one signed-in user could access all internal repositories
+// except those in private organizations that the user isn't a member of
+func internalRepoCond(userID int64) builder.Cond {
+	return builder.And(
+		builder.Eq{"`repository`.is_internal": true},
+		builder.Or(
+			builder.NotIn("`repository`.owner_id", builder.Select("id").From("`user`").Where(
+				builder.Eq{"type": user_model.UserTypeOrganization, "visibility": structs.VisibleTypePrivate},
+			)),
+			builder.In("`repository`.owner_id",
+				builder.Select("`org_user`.org_id").
+					From("org_user").
+					Where(builder.Eq{"`org_user`.uid": userID}),
+			),
+		),
+	)
+}
+
 // UserOrgPublicUnitRepoCond returns the condition that one user could access all public repositories in the special organization
 func UserOrgPublicUnitRepoCond(userID, orgID int64) builder.Cond {
 	return userOrgPublicRepoCond(userID).
@@ -337,7 +427,8 @@ func UserOrgPublicUnitRepoCond(userID, orgID int64) builder.Cond {
 
 // SearchRepositoryCondition creates a query condition according search repository options
 func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
-	cond := builder.NewCond()
+	// deleted repositories are hidden until they are restored or purged
+	cond := builder.NewCond().And(builder.Neq{"status": RepositoryPendingDeletion})
 
 	if opts.Private {
 		if opts.Actor != nil && !opts.Actor.IsAdmin && opts.Actor.ID != opts.OwnerID {
@@ -459,7 +550,30 @@ func SearchRepositoryCondition(opts *SearchRepoOptions) builder.Cond {
 		cond = cond.And(builder.In("id", builder.
 			Select("repo_id").
 			From("language_stat").
-			Where(builder.Eq{"language": opts.Language}).And(builder.Eq{"is_primary": true})))
+			Where(builder.Eq{"LOWER(language)": strings.ToLower(opts.Language)}).And(builder.Eq{"is_primary": true})))
+	}
+
+	for _, topic := range opts.Topics {
+		cond = cond.And(builder.In("id", builder.Select("repo_topic.repo_id").From("repo_topic").
+			Join("INNER", "topic", "topic.id = repo_topic.topic_id").
+			Where(builder.Eq{"topic.name": topic})))
+	}
+
+	if opts.OwnerName != "" {
+		cond = cond.And(builder.In("owner_id", builder.Select("id").From("`user`").Where(builder.Eq{"lower_name": strings.ToLower(opts.OwnerName)})))
+	}
+
+	if opts.Stars.IsSet() {
+		cond = cond.And(opts.Stars.cond("num_stars"))
+	}
+	if opts.Forks.IsSet() {
+		cond = cond.And(opts.Forks.cond("num_forks"))
+	}
+	if opts.Pushed.IsSet() {
+		cond = cond.And(opts.Pushed.cond("updated_unix"))
+	}
+	if opts.Created.IsSet() {
+		cond = cond.And(opts.Created.cond("created_unix"))
 	}
 
 	if opts.Fork != util.OptionalBoolNone || opts.OnlyShowRelevant {
@@ -643,6 +757,10 @@ func AccessibleRepositoryCondition(user *user_model.User, unitType unit.Type) bu
 			// 5. Be able to see all public repos in private organizations that we are an org_user of
 			userOrgPublicRepoCond(user.ID),
 		)
+		// 6. Be able to see all internal repositories if we're not restricted
+		if !user.IsRestricted && user.ID > 0 {
+			cond = cond.Or(internalRepoCond(user.ID))
+		}
 	}
 
 	return cond
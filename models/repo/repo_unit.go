@@ -126,6 +126,13 @@ type PullRequestsConfig struct {
 	DefaultDeleteBranchAfterMerge bool
 	DefaultMergeStyle             MergeStyle
 	DefaultAllowMaintainerEdit    bool
+	// DefaultMergeMessageTemplate and DefaultSquashMessageTemplate override the templates in .gitea/default_merge_message
+	DefaultMergeMessageTemplate  string
+	DefaultSquashMessageTemplate string
+	// MergeMessageTrailers is a template of the trailers added to the messages of the commits created by merging
+	MergeMessageTrailers string
+	// MergeTrainBatchSize is how many pull requests of a merge queue are tested together, one at a time if it is 0 or 1
+	MergeTrainBatchSize int
 }
 
 // FromDB fills up a PullRequestsConfig from serialized format.
@@ -149,6 +156,25 @@ func (cfg *PullRequestsConfig) IsMergeStyleAllowed(mergeStyle MergeStyle) bool {
 		mergeStyle == MergeStyleManuallyMerged && cfg.AllowManualMerge
 }
 
+// GetMergeMessageTemplate returns the template of the default message of a merge style, empty if it has none
+func (cfg *PullRequestsConfig) GetMergeMessageTemplate(mergeStyle MergeStyle) string {
+	switch mergeStyle {
+	case MergeStyleMerge, MergeStyleRebaseMerge:
+		return cfg.DefaultMergeMessageTemplate
+	case MergeStyleSquash:
+		return cfg.DefaultSquashMessageTemplate
+	}
+	return ""
+}
+
+// GetMergeTrainBatchSize returns how many pull requests of a merge queue are tested together
+func (cfg *PullRequestsConfig) GetMergeTrainBatchSize() int {
+	if cfg.MergeTrainBatchSize < 1 {
+		return 1
+	}
+	return cfg.MergeTrainBatchSize
+}
+
 // GetDefaultMergeStyle returns the default merge style for this pull request
 func (cfg *PullRequestsConfig) GetDefaultMergeStyle() MergeStyle {
 	if len(cfg.DefaultMergeStyle) != 0 {
@@ -162,6 +188,51 @@ func (cfg *PullRequestsConfig) GetDefaultMergeStyle() MergeStyle {
 	return MergeStyleMerge
 }
 
+// ActionsApprovalPolicy defines which workflow runs of pull requests from forks need the approval of a maintainer
+type ActionsApprovalPolicy string
+
+const (
+	// ActionsApprovalFirstRun requires an approval until a run triggered by the user was approved
+	ActionsApprovalFirstRun ActionsApprovalPolicy = "first_run"
+	// ActionsApprovalFirstContribution requires an approval until a pull request of the user was merged
+	ActionsApprovalFirstContribution ActionsApprovalPolicy = "first_contribution"
+	// ActionsApprovalAllForks requires an approval for every run of a pull request from a fork
+	ActionsApprovalAllForks ActionsApprovalPolicy = "all_forks"
+)
+
+// IsValid returns true if the approval policy is known
+func (p ActionsApprovalPolicy) IsValid() bool {
+	switch p {
+	case ActionsApprovalFirstRun, ActionsApprovalFirstContribution, ActionsApprovalAllForks:
+		return true
+	}
+	return false
+}
+
+// ActionsConfig describes actions config
+type ActionsConfig struct {
+	// ApprovalPolicy overrides the default approval policy of the instance if it's set
+	ApprovalPolicy ActionsApprovalPolicy
+}
+
+// FromDB fills up an ActionsConfig from serialized format.
+func (cfg *ActionsConfig) FromDB(bs []byte) error {
+	return json.UnmarshalHandleDoubleEncode(bs, &cfg)
+}
+
+// ToDB exports an ActionsConfig to a serialized format.
+func (cfg *ActionsConfig) ToDB() ([]byte, error) {
+	return json.Marshal(cfg)
+}
+
+// GetApprovalPolicy returns the approval policy of the repository or the default one of the instance
+func (cfg *ActionsConfig) GetApprovalPolicy() ActionsApprovalPolicy {
+	if cfg.ApprovalPolicy.IsValid() {
+		return cfg.ApprovalPolicy
+	}
+	return ActionsApprovalPolicy(setting.Actions.DefaultApprovalPolicy)
+}
+
 // BeforeSet is invoked from XORM before setting the value of a field of this object.
 func (r *RepoUnit) BeforeSet(colName string, val xorm.Cell) {
 	switch colName {
@@ -175,7 +246,9 @@ func (r *RepoUnit) BeforeSet(colName string, val xorm.Cell) {
 			r.Config = new(PullRequestsConfig)
 		case unit.TypeIssues:
 			r.Config = new(IssuesConfig)
-		case unit.TypeCode, unit.TypeReleases, unit.TypeWiki, unit.TypeProjects, unit.TypePackages, unit.TypeActions:
+		case unit.TypeActions:
+			r.Config = new(ActionsConfig)
+		case unit.TypeCode, unit.TypeReleases, unit.TypeWiki, unit.TypeProjects, unit.TypePackages, unit.TypeDiscussions:
 			fallthrough
 		default:
 			r.Config = new(UnitConfig)
@@ -218,6 +291,11 @@ func (r *RepoUnit) ExternalTrackerConfig() *ExternalTrackerConfig {
 	return r.Config.(*ExternalTrackerConfig)
 }
 
+// ActionsConfig returns config for unit.TypeActions
+func (r *RepoUnit) ActionsConfig() *ActionsConfig {
+	return r.Config.(*ActionsConfig)
+}
+
 func getUnitsByRepoID(ctx context.Context, repoID int64) (units []*RepoUnit, err error) {
 	var tmpUnits []*RepoUnit
 	if err := db.GetEngine(ctx).Where("repo_id = ?", repoID).Find(&tmpUnits); err != nil {
@@ -0,0 +1,229 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// Int64Range is an inclusive range used by the search qualifiers,
+// a range without a bound is open on that side.
+type Int64Range struct {
+	Min    int64
+	Max    int64
+	HasMin bool
+	HasMax bool
+}
+
+// IsSet returns true if the range has at least one bound
+func (r Int64Range) IsSet() bool {
+	return r.HasMin || r.HasMax
+}
+
+func (r Int64Range) cond(column string) builder.Cond {
+	cond := builder.NewCond()
+	if r.HasMin {
+		cond = cond.And(builder.Gte{column: r.Min})
+	}
+	if r.HasMax {
+		cond = cond.And(builder.Lte{column: r.Max})
+	}
+	return cond
+}
+
+// ErrInvalidSearchQuery represents a qualifier with an invalid value in a repository search query
+type ErrInvalidSearchQuery struct {
+	Qualifier string
+	Value     string
+}
+
+// IsErrInvalidSearchQuery checks if an error is a ErrInvalidSearchQuery.
+func IsErrInvalidSearchQuery(err error) bool {
+	_, ok := err.(ErrInvalidSearchQuery)
+	return ok
+}
+
+func (err ErrInvalidSearchQuery) Error() string {
+	return fmt.Sprintf("invalid value for search qualifier %s: %q", err.Qualifier, err.Value)
+}
+
+func (err ErrInvalidSearchQuery) Unwrap() error {
+	return util.ErrInvalidArgument
+}
+
+// splitSearchQuery splits the query at white space outside of double quotes
+func splitSearchQuery(query string) []string {
+	var fields []string
+	var field strings.Builder
+	inQuotes := false
+	for _, r := range query {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case unicode.IsSpace(r) && !inQuotes:
+			if field.Len() > 0 {
+				fields = append(fields, field.String())
+				field.Reset()
+			}
+		default:
+			field.WriteRune(r)
+		}
+	}
+	if field.Len() > 0 {
+		fields = append(fields, field.String())
+	}
+	return fields
+}
+
+var errInvalidQueryValue = errors.New("invalid value")
+
+// parseQueryCount parses a number, a single number is a range containing only itself
+func parseQueryCount(value string) (int64, int64, error) {
+	n, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || n < 0 {
+		return 0, 0, errInvalidQueryValue
+	}
+	return n, n, nil
+}
+
+// parseQueryDate parses a day or a RFC 3339 time, a day is the range of all seconds in that day
+func parseQueryDate(value string) (int64, int64, error) {
+	if t, err := time.ParseInLocation("2006-01-02", value, setting.DefaultUILocation); err == nil {
+		return t.Unix(), t.AddDate(0, 0, 1).Unix() - 1, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return 0, 0, errInvalidQueryValue
+	}
+	return t.Unix(), t.Unix(), nil
+}
+
+// parseQueryRange parses a range like "10", ">10", ">=10", "<10", "<=10", "10..20" or "10..*"
+func parseQueryRange(value string, parse func(string) (int64, int64, error)) (r Int64Range, err error) {
+	if from, to, ok := strings.Cut(value, ".."); ok {
+		if from != "*" {
+			if r.Min, _, err = parse(from); err != nil {
+				return r, err
+			}
+			r.HasMin = true
+		}
+		if to != "*" {
+			if _, r.Max, err = parse(to); err != nil {
+				return r, err
+			}
+			r.HasMax = true
+		}
+		return r, nil
+	}
+
+	var lo, hi int64
+	switch {
+	case strings.HasPrefix(value, ">="):
+		lo, _, err = parse(value[2:])
+		r.Min, r.HasMin = lo, true
+	case strings.HasPrefix(value, ">"):
+		_, hi, err = parse(value[1:])
+		r.Min, r.HasMin = hi+1, true
+	case strings.HasPrefix(value, "<="):
+		_, hi, err = parse(value[2:])
+		r.Max, r.HasMax = hi, true
+	case strings.HasPrefix(value, "<"):
+		lo, _, err = parse(value[1:])
+		r.Max, r.HasMax = lo-1, true
+	default:
+		lo, hi, err = parse(value)
+		r.Min, r.Max, r.HasMin, r.HasMax = lo, hi, true, true
+	}
+	return r, err
+}
+
+func parseQueryBool(value string) (util.OptionalBool, error) {
+	b, err := strconv.ParseBool(value)
+	if err != nil {
+		return util.OptionalBoolNone, errInvalidQueryValue
+	}
+	return util.OptionalBoolOf(b), nil
+}
+
+// ParseQuery parses an advanced search query into the options.
+// The qualifiers "language:", "topic:", "owner:", "stars:", "forks:", "pushed:", "created:",
+// "archived:", "fork:", "mirror:", "template:" and "is:" restrict the results,
+// the remaining words form the keyword. A query without qualifiers is used as keyword as it is.
+func (opts *SearchRepoOptions) ParseQuery(query string) error {
+	if !strings.Contains(query, ":") {
+		opts.Keyword = query
+		return nil
+	}
+
+	var keywords []string
+	for _, field := range splitSearchQuery(query) {
+		qualifier, value, _ := strings.Cut(field, ":")
+		qualifier = strings.ToLower(qualifier)
+
+		var err error
+		switch qualifier {
+		case "language":
+			opts.Language = value
+		case "topic":
+			opts.Topics = append(opts.Topics, strings.ToLower(value))
+		case "owner", "user", "org":
+			opts.OwnerName = value
+		case "stars":
+			opts.Stars, err = parseQueryRange(value, parseQueryCount)
+		case "forks":
+			opts.Forks, err = parseQueryRange(value, parseQueryCount)
+		case "pushed":
+			opts.Pushed, err = parseQueryRange(value, parseQueryDate)
+		case "created":
+			opts.Created, err = parseQueryRange(value, parseQueryDate)
+		case "archived":
+			opts.Archived, err = parseQueryBool(value)
+		case "fork":
+			opts.Fork, err = parseQueryBool(value)
+		case "mirror":
+			opts.Mirror, err = parseQueryBool(value)
+		case "template":
+			opts.Template, err = parseQueryBool(value)
+		case "is":
+			switch strings.ToLower(value) {
+			case "public":
+				opts.IsPrivate = util.OptionalBoolFalse
+			case "private":
+				opts.IsPrivate = util.OptionalBoolTrue
+			case "source":
+				opts.Fork = util.OptionalBoolFalse
+				opts.Mirror = util.OptionalBoolFalse
+			case "fork":
+				opts.Fork = util.OptionalBoolTrue
+			case "mirror":
+				opts.Mirror = util.OptionalBoolTrue
+			case "template":
+				opts.Template = util.OptionalBoolTrue
+			case "archived":
+				opts.Archived = util.OptionalBoolTrue
+			default:
+				err = errInvalidQueryValue
+			}
+		default:
+			// not a qualifier, e.g. a keyword containing a colon
+			keywords = append(keywords, field)
+			continue
+		}
+		if err != nil || value == "" {
+			return ErrInvalidSearchQuery{Qualifier: qualifier, Value: value}
+		}
+	}
+	opts.Keyword = strings.Join(keywords, " ")
+	return nil
+}
@@ -0,0 +1,48 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSearchRepoOptionsParseQuery(t *testing.T) {
+	defer func(loc *time.Location) { setting.DefaultUILocation = loc }(setting.DefaultUILocation)
+	setting.DefaultUILocation = time.UTC
+
+	opts := &SearchRepoOptions{}
+	assert.NoError(t, opts.ParseQuery("repo1,repo2"))
+	assert.Equal(t, "repo1,repo2", opts.Keyword)
+
+	opts = &SearchRepoOptions{}
+	assert.NoError(t, opts.ParseQuery(`gitea language:Go topic:git topic:"Forge" stars:>10 forks:5..20 pushed:>=2023-01-01 owner:user2 archived:false is:source`))
+	assert.Equal(t, "gitea", opts.Keyword)
+	assert.Equal(t, "Go", opts.Language)
+	assert.Equal(t, []string{"git", "forge"}, opts.Topics)
+	assert.Equal(t, "user2", opts.OwnerName)
+	assert.Equal(t, Int64Range{Min: 11, HasMin: true}, opts.Stars)
+	assert.Equal(t, Int64Range{Min: 5, Max: 20, HasMin: true, HasMax: true}, opts.Forks)
+	assert.Equal(t, Int64Range{Min: 1672531200, HasMin: true}, opts.Pushed)
+	assert.Equal(t, util.OptionalBoolFalse, opts.Archived)
+	assert.Equal(t, util.OptionalBoolFalse, opts.Fork)
+	assert.Equal(t, util.OptionalBoolFalse, opts.Mirror)
+
+	opts = &SearchRepoOptions{}
+	assert.NoError(t, opts.ParseQuery("created:2023-01-01 stars:<=3 forks:10..* https://example.com"))
+	assert.Equal(t, "https://example.com", opts.Keyword)
+	assert.Equal(t, Int64Range{Min: 1672531200, Max: 1672617599, HasMin: true, HasMax: true}, opts.Created)
+	assert.Equal(t, Int64Range{Max: 3, HasMax: true}, opts.Stars)
+	assert.Equal(t, Int64Range{Min: 10, HasMin: true}, opts.Forks)
+
+	for _, query := range []string{"stars:many", "pushed:>yesterday", "archived:maybe", "is:cool", "topic:", "forks:-1"} {
+		err := (&SearchRepoOptions{}).ParseQuery(query)
+		assert.True(t, IsErrInvalidSearchQuery(err), query)
+	}
+}
@@ -0,0 +1,121 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// StorageUsage is a snapshot of the storage used by a repository, or by all repositories and packages
+// of an owner when RepoID is 0. All records of a snapshot have the same CreatedUnix.
+type StorageUsage struct {
+	ID             int64              `xorm:"pk autoincr"`
+	OwnerID        int64              `xorm:"INDEX NOT NULL"`
+	RepoID         int64              `xorm:"INDEX NOT NULL DEFAULT 0"`
+	GitSize        int64              `xorm:"NOT NULL DEFAULT 0"`
+	LFSSize        int64              `xorm:"NOT NULL DEFAULT 0"`
+	AttachmentSize int64              `xorm:"NOT NULL DEFAULT 0"`
+	PackageSize    int64              `xorm:"NOT NULL DEFAULT 0"`
+	ActionsSize    int64              `xorm:"NOT NULL DEFAULT 0"`
+	TotalSize      int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix    timeutil.TimeStamp `xorm:"INDEX NOT NULL"`
+}
+
+func init() {
+	db.RegisterModel(new(StorageUsage))
+}
+
+// Add adds the sizes of other to the usage
+func (u *StorageUsage) Add(other *StorageUsage) {
+	u.GitSize += other.GitSize
+	u.LFSSize += other.LFSSize
+	u.AttachmentSize += other.AttachmentSize
+	u.PackageSize += other.PackageSize
+	u.ActionsSize += other.ActionsSize
+	u.UpdateTotalSize()
+}
+
+// UpdateTotalSize sets the total size to the sum of the sizes
+func (u *StorageUsage) UpdateTotalSize() {
+	u.TotalSize = u.GitSize + u.LFSSize + u.AttachmentSize + u.PackageSize + u.ActionsSize
+}
+
+// InsertStorageUsages inserts the records of a snapshot
+func InsertStorageUsages(ctx context.Context, usages []*StorageUsage) error {
+	if len(usages) == 0 {
+		return nil
+	}
+	_, err := db.GetEngine(ctx).Insert(&usages)
+	return err
+}
+
+// GetLatestStorageUsageTime returns the time of the latest snapshot, or 0 if there is none
+func GetLatestStorageUsageTime(ctx context.Context) (timeutil.TimeStamp, error) {
+	latest := &StorageUsage{}
+	has, err := db.GetEngine(ctx).Cols("created_unix").Desc("created_unix").Get(latest)
+	if err != nil || !has {
+		return 0, err
+	}
+	return latest.CreatedUnix, nil
+}
+
+// FindStorageUsageOptions represents the options to find the top storage consumers of the latest snapshot
+type FindStorageUsageOptions struct {
+	db.ListOptions
+	// Repos selects the records of repositories instead of owners
+	Repos bool
+	// OwnerID limits the repositories to the ones of the owner
+	OwnerID int64
+}
+
+// FindTopStorageUsages returns the records of the latest snapshot, largest total size first
+func FindTopStorageUsages(ctx context.Context, opts FindStorageUsageOptions) ([]*StorageUsage, int64, error) {
+	latest, err := GetLatestStorageUsageTime(ctx)
+	if err != nil || latest == 0 {
+		return nil, 0, err
+	}
+
+	cond := builder.NewCond().And(builder.Eq{"created_unix": latest})
+	if opts.Repos {
+		cond = cond.And(builder.Gt{"repo_id": 0})
+	} else {
+		cond = cond.And(builder.Eq{"repo_id": 0})
+	}
+	if opts.OwnerID > 0 {
+		cond = cond.And(builder.Eq{"owner_id": opts.OwnerID})
+	}
+
+	sess := db.GetEngine(ctx).Where(cond).OrderBy("total_size DESC, id ASC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts)
+	}
+	usages := make([]*StorageUsage, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&usages)
+	return usages, count, err
+}
+
+// GetStorageUsageHistory returns the records of the repository, or of the owner if repoID is 0,
+// which were recorded since the time, oldest first
+func GetStorageUsageHistory(ctx context.Context, ownerID, repoID int64, since timeutil.TimeStamp) ([]*StorageUsage, error) {
+	usages := make([]*StorageUsage, 0, 30)
+	return usages, db.GetEngine(ctx).
+		Where("owner_id = ? AND repo_id = ? AND created_unix >= ?", ownerID, repoID, since).
+		Asc("created_unix").
+		Find(&usages)
+}
+
+// DeleteStorageUsagesOlderThan deletes the records of the snapshots older than the duration
+func DeleteStorageUsagesOlderThan(ctx context.Context, olderThan time.Duration) error {
+	if olderThan <= 0 {
+		return nil
+	}
+	_, err := db.GetEngine(ctx).Where("created_unix < ?", time.Now().Add(-olderThan).Unix()).Delete(new(StorageUsage))
+	return err
+}
@@ -0,0 +1,66 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStorageUsage(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	latest, err := repo_model.GetLatestStorageUsageTime(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, latest)
+
+	now := timeutil.TimeStampNow()
+	old := now.AddDuration(-48 * time.Hour)
+	snapshot := func(created timeutil.TimeStamp, repo1Size, repo2Size int64) []*repo_model.StorageUsage {
+		repo1 := &repo_model.StorageUsage{OwnerID: 2, RepoID: 1, GitSize: repo1Size, CreatedUnix: created}
+		repo2 := &repo_model.StorageUsage{OwnerID: 2, RepoID: 2, LFSSize: repo2Size, CreatedUnix: created}
+		repo1.UpdateTotalSize()
+		repo2.UpdateTotalSize()
+		owner := &repo_model.StorageUsage{OwnerID: 2, PackageSize: 10, CreatedUnix: created}
+		owner.Add(repo1)
+		owner.Add(repo2)
+		return []*repo_model.StorageUsage{repo1, repo2, owner}
+	}
+	assert.NoError(t, repo_model.InsertStorageUsages(db.DefaultContext, snapshot(old, 100, 300)))
+	assert.NoError(t, repo_model.InsertStorageUsages(db.DefaultContext, snapshot(now, 500, 300)))
+
+	usages, count, err := repo_model.FindTopStorageUsages(db.DefaultContext, repo_model.FindStorageUsageOptions{Repos: true})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	if assert.Len(t, usages, 2) {
+		assert.EqualValues(t, 1, usages[0].RepoID)
+		assert.EqualValues(t, 500, usages[0].TotalSize)
+		assert.EqualValues(t, now, usages[0].CreatedUnix)
+	}
+
+	usages, _, err = repo_model.FindTopStorageUsages(db.DefaultContext, repo_model.FindStorageUsageOptions{})
+	assert.NoError(t, err)
+	if assert.Len(t, usages, 1) {
+		assert.EqualValues(t, 810, usages[0].TotalSize)
+	}
+
+	history, err := repo_model.GetStorageUsageHistory(db.DefaultContext, 2, 0, old)
+	assert.NoError(t, err)
+	if assert.Len(t, history, 2) {
+		assert.EqualValues(t, 410, history[0].TotalSize)
+		assert.EqualValues(t, 810, history[1].TotalSize)
+	}
+
+	assert.NoError(t, repo_model.DeleteStorageUsagesOlderThan(db.DefaultContext, 24*time.Hour))
+	history, err = repo_model.GetStorageUsageHistory(db.DefaultContext, 2, 1, 0)
+	assert.NoError(t, err)
+	assert.Len(t, history, 1)
+}
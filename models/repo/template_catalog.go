@@ -0,0 +1,169 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// TemplateCatalogKind is the kind of the files of a template catalog
+type TemplateCatalogKind string
+
+const (
+	// TemplateCatalogGitignore is a catalog of .gitignore files
+	TemplateCatalogGitignore TemplateCatalogKind = "gitignore"
+	// TemplateCatalogLicense is a catalog of licenses
+	TemplateCatalogLicense TemplateCatalogKind = "license"
+	// TemplateCatalogIssueTemplate is a catalog of issue templates, added to .gitea/ISSUE_TEMPLATE
+	TemplateCatalogIssueTemplate TemplateCatalogKind = "issue_template"
+	// TemplateCatalogWorkflowTemplate is a catalog of Actions workflows, added to .gitea/workflows
+	TemplateCatalogWorkflowTemplate TemplateCatalogKind = "workflow_template"
+)
+
+// TemplateCatalogKinds are all kinds of template catalogs
+var TemplateCatalogKinds = []TemplateCatalogKind{
+	TemplateCatalogGitignore,
+	TemplateCatalogLicense,
+	TemplateCatalogIssueTemplate,
+	TemplateCatalogWorkflowTemplate,
+}
+
+// IsValid returns true if the kind is known
+func (k TemplateCatalogKind) IsValid() bool {
+	for _, kind := range TemplateCatalogKinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Dir returns the directory of the repository the files of the catalog are added to, empty for the kinds which
+// are added as a single file
+func (k TemplateCatalogKind) Dir() string {
+	switch k {
+	case TemplateCatalogIssueTemplate:
+		return ".gitea/ISSUE_TEMPLATE"
+	case TemplateCatalogWorkflowTemplate:
+		return ".gitea/workflows"
+	}
+	return ""
+}
+
+// ErrTemplateCatalogItemNotExist represents a "TemplateCatalogItemNotExist" kind of error.
+type ErrTemplateCatalogItemNotExist struct {
+	OwnerID int64
+	Kind    TemplateCatalogKind
+	Name    string
+}
+
+// IsErrTemplateCatalogItemNotExist checks if an error is a ErrTemplateCatalogItemNotExist.
+func IsErrTemplateCatalogItemNotExist(err error) bool {
+	_, ok := err.(ErrTemplateCatalogItemNotExist)
+	return ok
+}
+
+func (err ErrTemplateCatalogItemNotExist) Error() string {
+	return fmt.Sprintf("template catalog item does not exist [owner_id: %d, kind: %s, name: %s]", err.OwnerID, err.Kind, err.Name)
+}
+
+func (err ErrTemplateCatalogItemNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// TemplateCatalogItem is a file of a template catalog which is offered when a repository is created or a file is
+// added with the web editor. Items of the instance have no owner, organizations can add their own items which
+// take precedence over the items of the instance with the same name.
+type TemplateCatalogItem struct {
+	ID          int64               `xorm:"pk autoincr"`
+	OwnerID     int64               `xorm:"UNIQUE(s) NOT NULL DEFAULT 0"`
+	Kind        TemplateCatalogKind `xorm:"VARCHAR(32) UNIQUE(s) NOT NULL"`
+	Name        string              `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+	Content     string              `xorm:"LONGTEXT"`
+	CreatedUnix timeutil.TimeStamp  `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp  `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(TemplateCatalogItem))
+}
+
+// ValidateTemplateCatalogName checks the name of an item of a catalog, the items which are added to a directory
+// of the repository must be markdown or YAML files
+func ValidateTemplateCatalogName(kind TemplateCatalogKind, name string) error {
+	if !kind.IsValid() {
+		return util.NewInvalidArgumentErrorf("invalid template catalog kind %q", kind)
+	}
+	if name == "" || len(name) > 255 || strings.ContainsAny(name, "/\\,") || name != strings.TrimSpace(name) || name == "." || name == ".." {
+		return util.NewInvalidArgumentErrorf("invalid template name %q", name)
+	}
+	switch kind {
+	case TemplateCatalogIssueTemplate:
+		if ext := strings.ToLower(path.Ext(name)); ext != ".md" && ext != ".yaml" && ext != ".yml" {
+			return util.NewInvalidArgumentErrorf("the name of an issue template must end with .md, .yaml or .yml")
+		}
+	case TemplateCatalogWorkflowTemplate:
+		if ext := strings.ToLower(path.Ext(name)); ext != ".yaml" && ext != ".yml" {
+			return util.NewInvalidArgumentErrorf("the name of a workflow template must end with .yaml or .yml")
+		}
+	}
+	return nil
+}
+
+// FindTemplateCatalogItems returns the items of a catalog of an owner ordered by name, ownerID 0 are the items of the instance
+func FindTemplateCatalogItems(ctx context.Context, ownerID int64, kind TemplateCatalogKind) ([]*TemplateCatalogItem, error) {
+	items := make([]*TemplateCatalogItem, 0, 10)
+	return items, db.GetEngine(ctx).Where("owner_id = ? AND kind = ?", ownerID, kind).Asc("name").Find(&items)
+}
+
+// GetTemplateCatalogItem returns an item of a catalog of an owner
+func GetTemplateCatalogItem(ctx context.Context, ownerID int64, kind TemplateCatalogKind, name string) (*TemplateCatalogItem, error) {
+	item := &TemplateCatalogItem{}
+	has, err := db.GetEngine(ctx).Where("owner_id = ? AND kind = ? AND name = ?", ownerID, kind, name).Get(item)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrTemplateCatalogItemNotExist{OwnerID: ownerID, Kind: kind, Name: name}
+	}
+	return item, nil
+}
+
+// SaveTemplateCatalogItem creates an item of a catalog or replaces the content of the existing item with the same name
+func SaveTemplateCatalogItem(ctx context.Context, item *TemplateCatalogItem) error {
+	if err := ValidateTemplateCatalogName(item.Kind, item.Name); err != nil {
+		return err
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		existing, err := GetTemplateCatalogItem(ctx, item.OwnerID, item.Kind, item.Name)
+		if err != nil {
+			if !IsErrTemplateCatalogItemNotExist(err) {
+				return err
+			}
+			item.ID = 0
+			return db.Insert(ctx, item)
+		}
+		item.ID = existing.ID
+		item.CreatedUnix = existing.CreatedUnix
+		_, err = db.GetEngine(ctx).ID(item.ID).Cols("content").Update(item)
+		return err
+	})
+}
+
+// DeleteTemplateCatalogItem deletes an item of a catalog of an owner
+func DeleteTemplateCatalogItem(ctx context.Context, ownerID int64, kind TemplateCatalogKind, name string) error {
+	n, err := db.GetEngine(ctx).Where("owner_id = ? AND kind = ? AND name = ?", ownerID, kind, name).Delete(new(TemplateCatalogItem))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrTemplateCatalogItemNotExist{OwnerID: ownerID, Kind: kind, Name: name}
+	}
+	return nil
+}
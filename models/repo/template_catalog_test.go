@@ -0,0 +1,72 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateTemplateCatalogName(t *testing.T) {
+	for _, c := range []struct {
+		kind  repo_model.TemplateCatalogKind
+		name  string
+		valid bool
+	}{
+		{repo_model.TemplateCatalogGitignore, "Go", true},
+		{repo_model.TemplateCatalogLicense, "Corp-Proprietary", true},
+		{repo_model.TemplateCatalogIssueTemplate, "bug.md", true},
+		{repo_model.TemplateCatalogIssueTemplate, "bug.yaml", true},
+		{repo_model.TemplateCatalogIssueTemplate, "bug.txt", false},
+		{repo_model.TemplateCatalogWorkflowTemplate, "ci.yml", true},
+		{repo_model.TemplateCatalogWorkflowTemplate, "ci.md", false},
+		{repo_model.TemplateCatalogGitignore, "", false},
+		{repo_model.TemplateCatalogGitignore, "../Go", false},
+		{repo_model.TemplateCatalogGitignore, "Go,Rust", false},
+		{"readme", "Default", false},
+	} {
+		err := repo_model.ValidateTemplateCatalogName(c.kind, c.name)
+		if c.valid {
+			assert.NoError(t, err, "%s %s", c.kind, c.name)
+		} else {
+			assert.ErrorIs(t, err, util.ErrInvalidArgument, "%s %s", c.kind, c.name)
+		}
+	}
+}
+
+func TestTemplateCatalogItem(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	item := &repo_model.TemplateCatalogItem{OwnerID: 3, Kind: repo_model.TemplateCatalogWorkflowTemplate, Name: "ci.yml", Content: "on: push"}
+	assert.NoError(t, repo_model.SaveTemplateCatalogItem(db.DefaultContext, item))
+	id := item.ID
+
+	// saving an item with the same name replaces its content
+	item = &repo_model.TemplateCatalogItem{OwnerID: 3, Kind: repo_model.TemplateCatalogWorkflowTemplate, Name: "ci.yml", Content: "on: [push, pull_request]"}
+	assert.NoError(t, repo_model.SaveTemplateCatalogItem(db.DefaultContext, item))
+	assert.Equal(t, id, item.ID)
+
+	assert.NoError(t, repo_model.SaveTemplateCatalogItem(db.DefaultContext, &repo_model.TemplateCatalogItem{Kind: repo_model.TemplateCatalogWorkflowTemplate, Name: "ci.yml", Content: "on: push"}))
+
+	items, err := repo_model.FindTemplateCatalogItems(db.DefaultContext, 3, repo_model.TemplateCatalogWorkflowTemplate)
+	assert.NoError(t, err)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, "on: [push, pull_request]", items[0].Content)
+	}
+
+	assert.NoError(t, repo_model.DeleteTemplateCatalogItem(db.DefaultContext, 3, repo_model.TemplateCatalogWorkflowTemplate, "ci.yml"))
+	_, err = repo_model.GetTemplateCatalogItem(db.DefaultContext, 3, repo_model.TemplateCatalogWorkflowTemplate, "ci.yml")
+	assert.True(t, repo_model.IsErrTemplateCatalogItemNotExist(err))
+	assert.True(t, repo_model.IsErrTemplateCatalogItemNotExist(repo_model.DeleteTemplateCatalogItem(db.DefaultContext, 3, repo_model.TemplateCatalogWorkflowTemplate, "ci.yml")))
+
+	// the item of the instance is kept
+	_, err = repo_model.GetTemplateCatalogItem(db.DefaultContext, 0, repo_model.TemplateCatalogWorkflowTemplate, "ci.yml")
+	assert.NoError(t, err)
+}
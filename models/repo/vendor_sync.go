@@ -0,0 +1,219 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// VendorSyncSourceType is the kind of external source the content of a vendor sync is imported from
+type VendorSyncSourceType string
+
+const (
+	// VendorSyncSourceHTTPTarball imports a tarball, optionally gzipped, downloaded over HTTP
+	VendorSyncSourceHTTPTarball VendorSyncSourceType = "http_tarball"
+	// VendorSyncSourceS3 imports the objects under a prefix of a bucket of an S3 compatible object storage
+	VendorSyncSourceS3 VendorSyncSourceType = "s3"
+)
+
+// IsValid returns if the source type is known
+func (t VendorSyncSourceType) IsValid() bool {
+	return t == VendorSyncSourceHTTPTarball || t == VendorSyncSourceS3
+}
+
+// VendorSyncStatus is the result of the last import of a vendor sync
+type VendorSyncStatus int
+
+const (
+	// VendorSyncStatusNone means the content has not been imported yet
+	VendorSyncStatusNone VendorSyncStatus = iota
+	// VendorSyncStatusSuccess means the content of the source was committed to the branch
+	VendorSyncStatusSuccess
+	// VendorSyncStatusUnchanged means the content of the source has not changed since the last import
+	VendorSyncStatusUnchanged
+	// VendorSyncStatusFailed means the import failed
+	VendorSyncStatusFailed
+)
+
+// String returns the name of the status
+func (s VendorSyncStatus) String() string {
+	switch s {
+	case VendorSyncStatusSuccess:
+		return "success"
+	case VendorSyncStatusUnchanged:
+		return "unchanged"
+	case VendorSyncStatusFailed:
+		return "failed"
+	}
+	return "none"
+}
+
+// VendorSync imports the content of an external source into a directory of a branch on a schedule
+type VendorSync struct {
+	ID         int64  `xorm:"pk autoincr"`
+	RepoID     int64  `xorm:"UNIQUE(s) NOT NULL"`
+	Branch     string `xorm:"UNIQUE(s) NOT NULL"`
+	TargetPath string `xorm:"UNIQUE(s) NOT NULL DEFAULT ''"`
+
+	SourceType VendorSyncSourceType `xorm:"VARCHAR(16) NOT NULL"`
+	// SourceURL is the URL of the tarball, or the endpoint of the object storage
+	SourceURL   string `xorm:"TEXT NOT NULL"`
+	Bucket      string
+	Prefix      string `xorm:"TEXT"`
+	AccessKeyID string
+	// SecretAccessKeyEncrypted should be accessed using SecretAccessKey() and SetSecretAccessKey()
+	SecretAccessKeyEncrypted string `xorm:"TEXT"`
+	// StripComponents is the number of leading path components removed from the files of the tarball
+	StripComponents int `xorm:"NOT NULL DEFAULT 0"`
+
+	Interval time.Duration
+	// DoerID is the user the content is committed as
+	DoerID int64 `xorm:"NOT NULL"`
+
+	NextSyncUnix timeutil.TimeStamp `xorm:"INDEX"`
+	LastStatus   VendorSyncStatus   `xorm:"NOT NULL DEFAULT 0"`
+	LastMessage  string             `xorm:"TEXT"`
+	LastSyncUnix timeutil.TimeStamp
+	// LastDigest and LastVersion identify the last imported content
+	LastDigest   string `xorm:"VARCHAR(64)"`
+	LastVersion  string
+	LastCommitID string `xorm:"VARCHAR(64)"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(VendorSync))
+}
+
+// ErrVendorSyncNotExist represents a "VendorSyncNotExist" kind of error.
+type ErrVendorSyncNotExist struct {
+	ID     int64
+	RepoID int64
+}
+
+// IsErrVendorSyncNotExist checks if an error is a ErrVendorSyncNotExist.
+func IsErrVendorSyncNotExist(err error) bool {
+	_, ok := err.(ErrVendorSyncNotExist)
+	return ok
+}
+
+func (err ErrVendorSyncNotExist) Error() string {
+	return fmt.Sprintf("vendor sync does not exist [id: %d, repo_id: %d]", err.ID, err.RepoID)
+}
+
+func (err ErrVendorSyncNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// SecretAccessKey returns the decrypted secret access key of the object storage
+func (s *VendorSync) SecretAccessKey() (string, error) {
+	if s.SecretAccessKeyEncrypted == "" {
+		return "", nil
+	}
+	return secret.DecryptSecret(setting.SecretKey, s.SecretAccessKeyEncrypted)
+}
+
+// SetSecretAccessKey encrypts and sets the secret access key of the object storage
+func (s *VendorSync) SetSecretAccessKey(cleartext string) error {
+	if cleartext == "" {
+		s.SecretAccessKeyEncrypted = ""
+		return nil
+	}
+	ciphertext, err := secret.EncryptSecret(setting.SecretKey, cleartext)
+	if err != nil {
+		return err
+	}
+	s.SecretAccessKeyEncrypted = ciphertext
+	return nil
+}
+
+// ScheduleNextSync sets the time of the next import from the interval
+func (s *VendorSync) ScheduleNextSync() {
+	s.NextSyncUnix = timeutil.TimeStampNow().AddDuration(s.Interval)
+}
+
+// GetVendorSync returns a vendor sync of a repository
+func GetVendorSync(ctx context.Context, repoID, id int64) (*VendorSync, error) {
+	s := &VendorSync{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Get(s)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrVendorSyncNotExist{ID: id, RepoID: repoID}
+	}
+	return s, nil
+}
+
+// GetVendorSyncsByRepoID returns the vendor syncs of a repository
+func GetVendorSyncsByRepoID(ctx context.Context, repoID int64) ([]*VendorSync, error) {
+	syncs := make([]*VendorSync, 0, 5)
+	return syncs, db.GetEngine(ctx).Where("repo_id = ?", repoID).OrderBy("branch, target_path").Find(&syncs)
+}
+
+// ExistVendorSync returns if another vendor sync imports into the same directory of the branch
+func ExistVendorSync(ctx context.Context, s *VendorSync) (bool, error) {
+	return db.GetEngine(ctx).
+		Where("repo_id = ? AND branch = ? AND target_path = ? AND id <> ?", s.RepoID, s.Branch, s.TargetPath, s.ID).
+		Exist(new(VendorSync))
+}
+
+// InsertVendorSync creates a vendor sync
+func InsertVendorSync(ctx context.Context, s *VendorSync) error {
+	return db.Insert(ctx, s)
+}
+
+// UpdateVendorSync updates the settings of a vendor sync, the content of the source is imported again
+// at the next sync even if it has not changed
+func UpdateVendorSync(ctx context.Context, s *VendorSync) error {
+	s.LastDigest = ""
+	s.LastVersion = ""
+	_, err := db.GetEngine(ctx).ID(s.ID).Cols("branch", "target_path", "source_type", "source_url", "bucket", "prefix",
+		"access_key_id", "secret_access_key_encrypted", "strip_components", "interval", "next_sync_unix",
+		"last_digest", "last_version").Update(s)
+	return err
+}
+
+// DeleteVendorSync removes a vendor sync of a repository
+func DeleteVendorSync(ctx context.Context, repoID, id int64) error {
+	n, err := db.GetEngine(ctx).Where("id = ? AND repo_id = ?", id, repoID).Delete(new(VendorSync))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrVendorSyncNotExist{ID: id, RepoID: repoID}
+	}
+	return nil
+}
+
+// FindDueVendorSyncs returns the vendor syncs whose next import is due
+func FindDueVendorSyncs(ctx context.Context, limit int) ([]*VendorSync, error) {
+	syncs := make([]*VendorSync, 0, limit)
+	return syncs, db.GetEngine(ctx).
+		Where("next_sync_unix <= ?", timeutil.TimeStampNow()).
+		Asc("next_sync_unix").
+		Limit(limit).
+		Find(&syncs)
+}
+
+// UpdateVendorSyncResult records the result of the last import and schedules the next one
+func UpdateVendorSyncResult(ctx context.Context, s *VendorSync, status VendorSyncStatus, message string) error {
+	s.LastStatus = status
+	s.LastMessage = message
+	s.LastSyncUnix = timeutil.TimeStampNow()
+	s.ScheduleNextSync()
+	_, err := db.GetEngine(ctx).ID(s.ID).
+		Cols("last_status", "last_message", "last_sync_unix", "last_digest", "last_version", "last_commit_id", "next_sync_unix").
+		NoAutoTime().
+		Update(s)
+	return err
+}
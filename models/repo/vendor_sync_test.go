@@ -0,0 +1,76 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo_test
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVendorSync(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	s := &repo_model.VendorSync{
+		RepoID:       1,
+		Branch:       "master",
+		TargetPath:   "third_party/data",
+		SourceType:   repo_model.VendorSyncSourceS3,
+		SourceURL:    "https://s3.example.com",
+		Bucket:       "datasets",
+		AccessKeyID:  "access",
+		Interval:     time.Hour,
+		DoerID:       2,
+		NextSyncUnix: timeutil.TimeStampNow(),
+	}
+	assert.NoError(t, s.SetSecretAccessKey("secret"))
+	assert.NotEqual(t, "secret", s.SecretAccessKeyEncrypted)
+	assert.NoError(t, repo_model.InsertVendorSync(db.DefaultContext, s))
+
+	exist, err := repo_model.ExistVendorSync(db.DefaultContext, s)
+	assert.NoError(t, err)
+	assert.False(t, exist)
+	exist, err = repo_model.ExistVendorSync(db.DefaultContext, &repo_model.VendorSync{RepoID: 1, Branch: "master", TargetPath: "third_party/data"})
+	assert.NoError(t, err)
+	assert.True(t, exist)
+
+	due, err := repo_model.FindDueVendorSyncs(db.DefaultContext, 10)
+	assert.NoError(t, err)
+	if assert.Len(t, due, 1) {
+		secretAccessKey, err := due[0].SecretAccessKey()
+		assert.NoError(t, err)
+		assert.Equal(t, "secret", secretAccessKey)
+	}
+
+	s.LastDigest = "digest"
+	assert.NoError(t, repo_model.UpdateVendorSyncResult(db.DefaultContext, s, repo_model.VendorSyncStatusSuccess, "commit"))
+	due, err = repo_model.FindDueVendorSyncs(db.DefaultContext, 10)
+	assert.NoError(t, err)
+	assert.Empty(t, due)
+
+	s, err = repo_model.GetVendorSync(db.DefaultContext, 1, s.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, repo_model.VendorSyncStatusSuccess, s.LastStatus)
+	assert.Equal(t, "success", s.LastStatus.String())
+	assert.Equal(t, "digest", s.LastDigest)
+
+	// changing the settings forgets the last imported content
+	s.Prefix = "v2/"
+	assert.NoError(t, repo_model.UpdateVendorSync(db.DefaultContext, s))
+	s, err = repo_model.GetVendorSync(db.DefaultContext, 1, s.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "v2/", s.Prefix)
+	assert.Empty(t, s.LastDigest)
+
+	_, err = repo_model.GetVendorSync(db.DefaultContext, 2, s.ID)
+	assert.True(t, repo_model.IsErrVendorSyncNotExist(err))
+	assert.NoError(t, repo_model.DeleteVendorSync(db.DefaultContext, 1, s.ID))
+	assert.True(t, repo_model.IsErrVendorSyncNotExist(repo_model.DeleteVendorSync(db.DefaultContext, 1, s.ID)))
+}
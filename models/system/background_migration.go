@@ -0,0 +1,93 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package system
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// BackgroundMigration stores the progress of a background migration, which backfills data while Gitea is running
+type BackgroundMigration struct {
+	ID         int64  `xorm:"pk autoincr"`
+	Name       string `xorm:"UNIQUE NOT NULL"`
+	Total      int64  `xorm:"NOT NULL DEFAULT 0"`
+	Processed  int64  `xorm:"NOT NULL DEFAULT 0"`
+	LastID     int64  `xorm:"NOT NULL DEFAULT 0"`
+	IsFinished bool   `xorm:"NOT NULL DEFAULT false"`
+	LastError  string `xorm:"TEXT"`
+
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(BackgroundMigration))
+}
+
+// GetOrCreateBackgroundMigration returns the progress of the background migration, a new record is created if it didn't run before
+func GetOrCreateBackgroundMigration(ctx context.Context, name string) (*BackgroundMigration, error) {
+	var bm *BackgroundMigration
+	return bm, db.WithTx(ctx, func(ctx context.Context) error {
+		bm = &BackgroundMigration{Name: name}
+		has, err := db.GetEngine(ctx).Get(bm)
+		if err != nil || has {
+			return err
+		}
+		return db.Insert(ctx, bm)
+	})
+}
+
+// GetBackgroundMigrations returns the progress of all background migrations which have been started
+func GetBackgroundMigrations(ctx context.Context) ([]*BackgroundMigration, error) {
+	bms := make([]*BackgroundMigration, 0, 10)
+	return bms, db.GetEngine(ctx).OrderBy("id").Find(&bms)
+}
+
+// SetBackgroundMigrationTotal stores the number of rows the background migration has to process
+func SetBackgroundMigrationTotal(ctx context.Context, bm *BackgroundMigration, total int64) error {
+	bm.Total = total
+	_, err := db.GetEngine(ctx).ID(bm.ID).Cols("total").Update(bm)
+	return err
+}
+
+// AdvanceBackgroundMigration stores the progress of a processed batch. It returns false if another
+// instance has advanced the background migration in the meantime, in which case bm should be reloaded.
+func AdvanceBackgroundMigration(ctx context.Context, bm *BackgroundMigration, lastID, processed int64) (bool, error) {
+	res, err := db.GetEngine(ctx).Exec("UPDATE background_migration SET last_id = ?, processed = processed + ?, last_error = '', updated_unix = ? WHERE id = ? AND last_id = ?",
+		lastID, processed, timeutil.TimeStampNow(), bm.ID, bm.LastID)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return false, nil
+	}
+	bm.LastID = lastID
+	bm.Processed += processed
+	bm.LastError = ""
+	return true, nil
+}
+
+// ReloadBackgroundMigration loads the current progress of the background migration
+func ReloadBackgroundMigration(ctx context.Context, bm *BackgroundMigration) error {
+	_, err := db.GetEngine(ctx).ID(bm.ID).Get(bm)
+	return err
+}
+
+// FinishBackgroundMigration marks the background migration as finished
+func FinishBackgroundMigration(ctx context.Context, bm *BackgroundMigration) error {
+	bm.IsFinished = true
+	bm.LastError = ""
+	_, err := db.GetEngine(ctx).ID(bm.ID).Cols("is_finished", "last_error").Update(bm)
+	return err
+}
+
+// SetBackgroundMigrationError stores the error which interrupted the background migration
+func SetBackgroundMigrationError(ctx context.Context, bm *BackgroundMigration, errMsg string) error {
+	bm.LastError = errMsg
+	_, err := db.GetEngine(ctx).ID(bm.ID).Cols("last_error").Update(bm)
+	return err
+}
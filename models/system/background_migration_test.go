@@ -0,0 +1,49 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package system_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackgroundMigration(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	bm, err := system.GetOrCreateBackgroundMigration(db.DefaultContext, "test")
+	assert.NoError(t, err)
+	assert.NotZero(t, bm.ID)
+	assert.False(t, bm.IsFinished)
+
+	same, err := system.GetOrCreateBackgroundMigration(db.DefaultContext, "test")
+	assert.NoError(t, err)
+	assert.Equal(t, bm.ID, same.ID)
+
+	advanced, err := system.AdvanceBackgroundMigration(db.DefaultContext, bm, 10, 5)
+	assert.NoError(t, err)
+	assert.True(t, advanced)
+	assert.EqualValues(t, 10, bm.LastID)
+	assert.EqualValues(t, 5, bm.Processed)
+
+	// the stale copy can't advance, another instance processed the batch already
+	advanced, err = system.AdvanceBackgroundMigration(db.DefaultContext, same, 10, 5)
+	assert.NoError(t, err)
+	assert.False(t, advanced)
+	assert.NoError(t, system.ReloadBackgroundMigration(db.DefaultContext, same))
+	assert.EqualValues(t, 10, same.LastID)
+
+	assert.NoError(t, system.FinishBackgroundMigration(db.DefaultContext, bm))
+
+	bms, err := system.GetBackgroundMigrations(db.DefaultContext)
+	assert.NoError(t, err)
+	if assert.Len(t, bms, 1) {
+		assert.True(t, bms[0].IsFinished)
+		assert.EqualValues(t, 5, bms[0].Processed)
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package system
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// MailDeliveryStatus is the state of an outgoing mail
+type MailDeliveryStatus int
+
+const (
+	// MailDeliveryQueued the mail waits in the mail queue
+	MailDeliveryQueued MailDeliveryStatus = iota
+	// MailDeliverySent the mail was accepted by a provider
+	MailDeliverySent
+	// MailDeliveryFailed all providers failed to accept the mail
+	MailDeliveryFailed
+	// MailDeliveryDelivered the provider reported the mail as delivered
+	MailDeliveryDelivered
+	// MailDeliveryBounced the provider reported a permanent delivery failure
+	MailDeliveryBounced
+	// MailDeliveryComplained the recipient marked the mail as spam
+	MailDeliveryComplained
+)
+
+// String returns the API name of the status
+func (s MailDeliveryStatus) String() string {
+	switch s {
+	case MailDeliverySent:
+		return "sent"
+	case MailDeliveryFailed:
+		return "failed"
+	case MailDeliveryDelivered:
+		return "delivered"
+	case MailDeliveryBounced:
+		return "bounced"
+	case MailDeliveryComplained:
+		return "complained"
+	default:
+		return "queued"
+	}
+}
+
+// ParseMailDeliveryStatus returns the status with the given API name
+func ParseMailDeliveryStatus(s string) (MailDeliveryStatus, bool) {
+	for status := MailDeliveryQueued; status <= MailDeliveryComplained; status++ {
+		if status.String() == s {
+			return status, true
+		}
+	}
+	return MailDeliveryQueued, false
+}
+
+// MailDelivery tracks an outgoing mail from the queue to the recipient's mailbox
+type MailDelivery struct {
+	ID        int64  `xorm:"pk autoincr"`
+	MessageID string `xorm:"VARCHAR(255) INDEX"`
+	Recipient string `xorm:"VARCHAR(320) INDEX NOT NULL"`
+	Subject   string `xorm:"TEXT"`
+	// Provider is the name of the provider which accepted the mail
+	Provider          string             `xorm:"VARCHAR(50)"`
+	ProviderMessageID string             `xorm:"VARCHAR(255) INDEX"`
+	Status            MailDeliveryStatus `xorm:"INDEX NOT NULL DEFAULT 0"`
+	Error             string             `xorm:"TEXT"`
+	CreatedUnix       timeutil.TimeStamp `xorm:"INDEX created"`
+	UpdatedUnix       timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(MailDelivery))
+}
+
+// CreateMailDelivery records a queued mail
+func CreateMailDelivery(ctx context.Context, delivery *MailDelivery) error {
+	delivery.Status = MailDeliveryQueued
+	return db.Insert(ctx, delivery)
+}
+
+// UpdateMailDelivery updates the provider and status of a mail
+func UpdateMailDelivery(ctx context.Context, delivery *MailDelivery) error {
+	_, err := db.GetEngine(ctx).ID(delivery.ID).Cols("provider", "provider_message_id", "status", "error").Update(delivery)
+	return err
+}
+
+// GetMailDeliveryByProviderMessageID returns the mail with the id assigned by the provider,
+// it returns nil if there is none
+func GetMailDeliveryByProviderMessageID(ctx context.Context, provider, providerMessageID string) (*MailDelivery, error) {
+	delivery := &MailDelivery{}
+	has, err := db.GetEngine(ctx).
+		Where(builder.Eq{"provider": provider, "provider_message_id": providerMessageID}).
+		Desc("id").
+		Get(delivery)
+	if err != nil || !has {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+// FindMailDeliveriesOptions represents the options to search mail deliveries
+type FindMailDeliveriesOptions struct {
+	db.ListOptions
+	Recipient string
+	Statuses  []MailDeliveryStatus
+}
+
+// ToConds implements db.FindOptions
+func (opts FindMailDeliveriesOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.Recipient != "" {
+		cond = cond.And(builder.Eq{"recipient": opts.Recipient})
+	}
+	if len(opts.Statuses) > 0 {
+		cond = cond.And(builder.In("status", opts.Statuses))
+	}
+	return cond
+}
+
+// FindMailDeliveries returns the mail deliveries matching the options, newest first
+func FindMailDeliveries(ctx context.Context, opts FindMailDeliveriesOptions) ([]*MailDelivery, int64, error) {
+	sess := db.GetEngine(ctx).Where(opts.ToConds()).OrderBy("id DESC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts.ListOptions)
+	}
+	deliveries := make([]*MailDelivery, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&deliveries)
+	return deliveries, count, err
+}
+
+// DeleteOldMailDeliveries deletes the records of mails sent before the given duration
+func DeleteOldMailDeliveries(ctx context.Context, olderThan time.Duration) error {
+	if olderThan <= 0 {
+		return nil
+	}
+	_, err := db.GetEngine(ctx).Where("created_unix < ?", time.Now().Add(-olderThan).Unix()).Delete(&MailDelivery{})
+	return err
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package system
+
+import (
+	"context"
+	"strconv"
+)
+
+// DefaultMaintenanceMessage is shown if the maintenance mode was enabled without a message
+const DefaultMaintenanceMessage = "The site is in read-only maintenance mode, please try again later."
+
+// MaintenanceMode represents the state of the read-only maintenance mode
+type MaintenanceMode struct {
+	Enabled bool
+	Message string
+}
+
+// GetMaintenanceMode returns the state of the maintenance mode, it is read from the cache
+func GetMaintenanceMode(ctx context.Context) *MaintenanceMode {
+	mode := &MaintenanceMode{
+		Enabled: GetSettingWithCacheBool(ctx, KeyMaintenanceModeEnabled),
+	}
+	if mode.Enabled {
+		mode.Message, _ = GetSettingWithCache(ctx, KeyMaintenanceModeMessage)
+		if mode.Message == "" {
+			mode.Message = DefaultMaintenanceMessage
+		}
+	}
+	return mode
+}
+
+// SetMaintenanceMode enables or disables the maintenance mode
+func SetMaintenanceMode(ctx context.Context, enabled bool, message string) error {
+	if !enabled {
+		message = ""
+	}
+	if err := SetSettingNoVersion(ctx, KeyMaintenanceModeMessage, message); err != nil {
+		return err
+	}
+	return SetSettingNoVersion(ctx, KeyMaintenanceModeEnabled, strconv.FormatBool(enabled))
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package system_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceMode(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.False(t, system.GetMaintenanceMode(db.DefaultContext).Enabled)
+
+	assert.NoError(t, system.SetMaintenanceMode(db.DefaultContext, true, ""))
+	mode := system.GetMaintenanceMode(db.DefaultContext)
+	assert.True(t, mode.Enabled)
+	assert.Equal(t, system.DefaultMaintenanceMessage, mode.Message)
+
+	assert.NoError(t, system.SetMaintenanceMode(db.DefaultContext, true, "Backup in progress"))
+	mode = system.GetMaintenanceMode(db.DefaultContext)
+	assert.True(t, mode.Enabled)
+	assert.Equal(t, "Backup in progress", mode.Message)
+
+	assert.NoError(t, system.SetMaintenanceMode(db.DefaultContext, false, "Backup in progress"))
+	mode = system.GetMaintenanceMode(db.DefaultContext)
+	assert.False(t, mode.Enabled)
+	assert.Empty(t, mode.Message)
+}
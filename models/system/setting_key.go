@@ -7,6 +7,8 @@ package system
 const (
 	KeyPictureDisableGravatar       = "picture.disable_gravatar"
 	KeyPictureEnableFederatedAvatar = "picture.enable_federated_avatar"
+	KeyMaintenanceModeEnabled       = "maintenance.enabled"
+	KeyMaintenanceModeMessage       = "maintenance.message"
 )
 
 // genSettingCacheKey returns the cache key for some configuration
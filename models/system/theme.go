@@ -0,0 +1,178 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// Theme is a theme managed by the site administrators. It overrides CSS variables of the default theme,
+// users can choose it like the themes of the config and organizations can use it for their branding if approved.
+type Theme struct {
+	ID          int64  `xorm:"pk autoincr"`
+	Name        string `xorm:"VARCHAR(30) UNIQUE NOT NULL"`
+	DisplayName string
+	// Variables maps the names of CSS variables, without the leading dashes, to their values
+	Variables map[string]string `xorm:"TEXT JSON"`
+	// Logo is the path of the logo in the avatar storage, empty to keep the default logo
+	Logo string
+	// OrgBranding allows organizations to use the theme for their pages
+	OrgBranding bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(Theme))
+}
+
+// ErrThemeNotExist represents a "ThemeNotExist" kind of error.
+type ErrThemeNotExist struct {
+	ID   int64
+	Name string
+}
+
+// IsErrThemeNotExist checks if an error is a ErrThemeNotExist.
+func IsErrThemeNotExist(err error) bool {
+	_, ok := err.(ErrThemeNotExist)
+	return ok
+}
+
+func (err ErrThemeNotExist) Error() string {
+	return fmt.Sprintf("theme does not exist [id: %d, name: %s]", err.ID, err.Name)
+}
+
+func (err ErrThemeNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// ErrThemeAlreadyExist represents a "ThemeAlreadyExist" kind of error.
+type ErrThemeAlreadyExist struct {
+	Name string
+}
+
+// IsErrThemeAlreadyExist checks if an error is a ErrThemeAlreadyExist.
+func IsErrThemeAlreadyExist(err error) bool {
+	_, ok := err.(ErrThemeAlreadyExist)
+	return ok
+}
+
+func (err ErrThemeAlreadyExist) Error() string {
+	return fmt.Sprintf("theme already exists [name: %s]", err.Name)
+}
+
+func (err ErrThemeAlreadyExist) Unwrap() error {
+	return util.ErrAlreadyExist
+}
+
+func (t *Theme) stylesheetPath() string {
+	return fmt.Sprintf("user/themes/%s/theme.css?v=%d", url.PathEscape(t.Name), t.UpdatedUnix)
+}
+
+// StylesheetLink returns the link of the stylesheet of the theme, it changes whenever the theme is updated
+func (t *Theme) StylesheetLink() string {
+	return setting.AppSubURL + "/" + t.stylesheetPath()
+}
+
+// StylesheetURL returns the absolute URL of the stylesheet of the theme
+func (t *Theme) StylesheetURL() string {
+	return setting.AppURL + t.stylesheetPath()
+}
+
+// LogoLink returns the link of the logo of the theme, empty if it has none
+func (t *Theme) LogoLink() string {
+	if t.Logo == "" {
+		return ""
+	}
+	return setting.AppSubURL + "/avatars/" + t.Logo
+}
+
+// LogoURL returns the absolute URL of the logo of the theme, empty if it has none
+func (t *Theme) LogoURL() string {
+	if t.Logo == "" {
+		return ""
+	}
+	return setting.AppURL + "avatars/" + t.Logo
+}
+
+// CSS renders the variables of the theme as stylesheet, they must have been validated
+func (t *Theme) CSS() string {
+	names := make([]string, 0, len(t.Variables))
+	for name := range t.Variables {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var sb strings.Builder
+	sb.WriteString(":root {\n")
+	for _, name := range names {
+		fmt.Fprintf(&sb, "  --%s: %s;\n", name, t.Variables[name])
+	}
+	sb.WriteString("}\n")
+	return sb.String()
+}
+
+// CreateTheme creates a theme
+func CreateTheme(ctx context.Context, t *Theme) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		has, err := db.GetEngine(ctx).Where("name = ?", t.Name).Exist(new(Theme))
+		if err != nil {
+			return err
+		} else if has {
+			return ErrThemeAlreadyExist{Name: t.Name}
+		}
+		return db.Insert(ctx, t)
+	})
+}
+
+// GetThemeByName returns the theme with the name
+func GetThemeByName(ctx context.Context, name string) (*Theme, error) {
+	t := &Theme{}
+	has, err := db.GetEngine(ctx).Where("name = ?", strings.ToLower(name)).Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrThemeNotExist{Name: name}
+	}
+	return t, nil
+}
+
+// GetThemeByID returns the theme with the id
+func GetThemeByID(ctx context.Context, id int64) (*Theme, error) {
+	t := &Theme{}
+	has, err := db.GetEngine(ctx).ID(id).Get(t)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrThemeNotExist{ID: id}
+	}
+	return t, nil
+}
+
+// GetThemes returns all themes sorted by name
+func GetThemes(ctx context.Context) ([]*Theme, error) {
+	themes := make([]*Theme, 0, 10)
+	return themes, db.GetEngine(ctx).Asc("name").Find(&themes)
+}
+
+// UpdateTheme updates the columns of a theme
+func UpdateTheme(ctx context.Context, t *Theme, cols ...string) error {
+	_, err := db.GetEngine(ctx).ID(t.ID).Cols(cols...).Update(t)
+	return err
+}
+
+// DeleteTheme deletes a theme
+func DeleteTheme(ctx context.Context, id int64) error {
+	_, err := db.GetEngine(ctx).ID(id).Delete(new(Theme))
+	return err
+}
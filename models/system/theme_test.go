@@ -0,0 +1,43 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package system_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestThemes(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	theme := &system.Theme{
+		Name:      "acme",
+		Variables: map[string]string{"color-primary": "#d94a2b", "color-body": "#fafafa"},
+	}
+	assert.NoError(t, system.CreateTheme(db.DefaultContext, theme))
+	assert.True(t, system.IsErrThemeAlreadyExist(system.CreateTheme(db.DefaultContext, &system.Theme{Name: "acme"})))
+
+	theme, err := system.GetThemeByName(db.DefaultContext, "ACME")
+	assert.NoError(t, err)
+	assert.Equal(t, ":root {\n  --color-body: #fafafa;\n  --color-primary: #d94a2b;\n}\n", theme.CSS())
+	assert.Empty(t, theme.LogoLink())
+
+	theme.OrgBranding = true
+	assert.NoError(t, system.UpdateTheme(db.DefaultContext, theme, "org_branding"))
+	theme, err = system.GetThemeByID(db.DefaultContext, theme.ID)
+	assert.NoError(t, err)
+	assert.True(t, theme.OrgBranding)
+
+	assert.NoError(t, system.DeleteTheme(db.DefaultContext, theme.ID))
+	_, err = system.GetThemeByName(db.DefaultContext, "acme")
+	assert.True(t, system.IsErrThemeNotExist(err))
+	themes, err := system.GetThemes(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Empty(t, themes)
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package system
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// TranslationOverride replaces the message of a translation key in a language, so terminology can be
+// changed without editing the locale files
+type TranslationOverride struct {
+	ID          int64              `xorm:"pk autoincr"`
+	Lang        string             `xorm:"VARCHAR(20) UNIQUE(s) NOT NULL"`
+	TrKey       string             `xorm:"VARCHAR(255) UNIQUE(s) NOT NULL"`
+	Message     string             `xorm:"TEXT NOT NULL"`
+	UpdaterID   int64              `xorm:"NOT NULL DEFAULT 0"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(TranslationOverride))
+}
+
+// ErrTranslationOverrideNotExist represents a "TranslationOverrideNotExist" kind of error.
+type ErrTranslationOverrideNotExist struct {
+	Lang  string
+	TrKey string
+}
+
+// IsErrTranslationOverrideNotExist checks if an error is a ErrTranslationOverrideNotExist.
+func IsErrTranslationOverrideNotExist(err error) bool {
+	_, ok := err.(ErrTranslationOverrideNotExist)
+	return ok
+}
+
+func (err ErrTranslationOverrideNotExist) Error() string {
+	return fmt.Sprintf("translation override does not exist [lang: %s, key: %s]", err.Lang, err.TrKey)
+}
+
+func (err ErrTranslationOverrideNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// GetTranslationOverrides returns the overrides of a language sorted by key, or of all languages if lang is empty
+func GetTranslationOverrides(ctx context.Context, lang string) ([]*TranslationOverride, error) {
+	sess := db.GetEngine(ctx)
+	if lang != "" {
+		sess = sess.Where("lang = ?", lang)
+	}
+	overrides := make([]*TranslationOverride, 0, 10)
+	return overrides, sess.Asc("lang", "tr_key").Find(&overrides)
+}
+
+// SetTranslationOverrides creates or updates the overrides of a language
+func SetTranslationOverrides(ctx context.Context, lang string, messages map[string]string, doerID int64) error {
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		for trKey, msg := range messages {
+			o := &TranslationOverride{}
+			has, err := db.GetEngine(ctx).Where("lang = ? AND tr_key = ?", lang, trKey).Get(o)
+			if err != nil {
+				return err
+			}
+			if !has {
+				if err := db.Insert(ctx, &TranslationOverride{Lang: lang, TrKey: trKey, Message: msg, UpdaterID: doerID}); err != nil {
+					return err
+				}
+				continue
+			}
+			o.Message = msg
+			o.UpdaterID = doerID
+			if _, err := db.GetEngine(ctx).ID(o.ID).Cols("message", "updater_id").Update(o); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteTranslationOverride removes the override of a key in a language
+func DeleteTranslationOverride(ctx context.Context, lang, trKey string) error {
+	n, err := db.GetEngine(ctx).Where("lang = ? AND tr_key = ?", lang, trKey).Delete(new(TranslationOverride))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrTranslationOverrideNotExist{Lang: lang, TrKey: trKey}
+	}
+	return nil
+}
+
+// DeleteTranslationOverrides removes all overrides of a language
+func DeleteTranslationOverrides(ctx context.Context, lang string) error {
+	_, err := db.GetEngine(ctx).Where("lang = ?", lang).Delete(new(TranslationOverride))
+	return err
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package system_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranslationOverrides(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.NoError(t, system.SetTranslationOverrides(db.DefaultContext, "en-US", map[string]string{"repo.settings": "Project settings", "home": "Start"}, 1))
+	assert.NoError(t, system.SetTranslationOverrides(db.DefaultContext, "de-DE", map[string]string{"home": "Start"}, 1))
+	assert.NoError(t, system.SetTranslationOverrides(db.DefaultContext, "en-US", map[string]string{"home": "Front page"}, 2))
+
+	overrides, err := system.GetTranslationOverrides(db.DefaultContext, "en-US")
+	assert.NoError(t, err)
+	if assert.Len(t, overrides, 2) {
+		assert.Equal(t, "home", overrides[0].TrKey)
+		assert.Equal(t, "Front page", overrides[0].Message)
+		assert.EqualValues(t, 2, overrides[0].UpdaterID)
+		assert.Equal(t, "repo.settings", overrides[1].TrKey)
+	}
+	overrides, err = system.GetTranslationOverrides(db.DefaultContext, "")
+	assert.NoError(t, err)
+	assert.Len(t, overrides, 3)
+
+	assert.NoError(t, system.DeleteTranslationOverride(db.DefaultContext, "en-US", "home"))
+	assert.True(t, system.IsErrTranslationOverrideNotExist(system.DeleteTranslationOverride(db.DefaultContext, "en-US", "home")))
+
+	assert.NoError(t, system.DeleteTranslationOverrides(db.DefaultContext, "en-US"))
+	overrides, err = system.GetTranslationOverrides(db.DefaultContext, "")
+	assert.NoError(t, err)
+	if assert.Len(t, overrides, 1) {
+		assert.Equal(t, "de-DE", overrides[0].Lang)
+	}
+}
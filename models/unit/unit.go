@@ -29,6 +29,7 @@ const (
 	TypeProjects                    // 8 Kanban board
 	TypePackages                    // 9 Packages
 	TypeActions                     // 10 Actions
+	TypeDiscussions                 // 11 Discussions
 )
 
 // Value returns integer value for unit type
@@ -58,6 +59,8 @@ func (u Type) String() string {
 		return "TypePackages"
 	case TypeActions:
 		return "TypeActions"
+	case TypeDiscussions:
+		return "TypeDiscussions"
 	}
 	return fmt.Sprintf("Unknown Type %d", u)
 }
@@ -82,6 +85,7 @@ var (
 		TypeProjects,
 		TypePackages,
 		TypeActions,
+		TypeDiscussions,
 	}
 
 	// DefaultRepoUnits contains the default unit types
@@ -304,6 +308,15 @@ var (
 		perm.AccessModeOwner,
 	}
 
+	UnitDiscussions = Unit{
+		TypeDiscussions,
+		"repo.discussions",
+		"/discussions",
+		"repo.discussions.desc",
+		8,
+		perm.AccessModeOwner,
+	}
+
 	// Units contains all the units
 	Units = map[Type]Unit{
 		TypeCode:            UnitCode,
@@ -316,6 +329,7 @@ var (
 		TypeProjects:        UnitProjects,
 		TypePackages:        UnitPackages,
 		TypeActions:         UnitActions,
+		TypeDiscussions:     UnitDiscussions,
 	}
 )
 
@@ -86,6 +86,10 @@ func (u *User) AvatarLinkWithSize(ctx context.Context, size int) string {
 		if u.Avatar == "" {
 			return avatars.DefaultAvatarLink()
 		}
+		if setting.Avatar.LibravatarServer && u.IsIndividual() {
+			// Libravatar clients look up the avatar by the hash of the email
+			avatars.SaveEmailHash(u.Email)
+		}
 		return avatars.GenerateUserAvatarImageLink(u.Avatar, size)
 	}
 	return avatars.GenerateEmailAvatarFastLink(ctx, u.AvatarEmail, size)
@@ -0,0 +1,139 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// ErrBlockNotExist represents a "BlockNotExist" kind of error.
+type ErrBlockNotExist struct {
+	BlockerID int64
+	BlockeeID int64
+}
+
+// IsErrBlockNotExist checks if an error is a ErrBlockNotExist.
+func IsErrBlockNotExist(err error) bool {
+	_, ok := err.(ErrBlockNotExist)
+	return ok
+}
+
+func (err ErrBlockNotExist) Error() string {
+	return fmt.Sprintf("user is not blocked [blocker_id: %d, blockee_id: %d]", err.BlockerID, err.BlockeeID)
+}
+
+func (err ErrBlockNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// Block represents a user or organization blocking a user from interacting with them and their repositories
+type Block struct {
+	ID          int64              `xorm:"pk autoincr"`
+	BlockerID   int64              `xorm:"UNIQUE(block) NOT NULL"`
+	BlockeeID   int64              `xorm:"UNIQUE(block) INDEX NOT NULL"`
+	Note        string             `xorm:"TEXT"`
+	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
+
+	Blockee *User `xorm:"-"`
+}
+
+// TableName sets the table name of the block
+func (Block) TableName() string {
+	return "user_block"
+}
+
+func init() {
+	db.RegisterModel(new(Block))
+}
+
+// IsBlocked checks if the blocker has blocked the blockee
+func IsBlocked(ctx context.Context, blockerID, blockeeID int64) (bool, error) {
+	return db.GetEngine(ctx).Where("blocker_id = ? AND blockee_id = ?", blockerID, blockeeID).Exist(new(Block))
+}
+
+// BlockUser blocks the blockee, the note of an existing block is updated
+func BlockUser(ctx context.Context, blockerID, blockeeID int64, note string) error {
+	if blockerID == blockeeID {
+		return util.NewInvalidArgumentErrorf("users cannot block themselves")
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		block := &Block{}
+		has, err := db.GetEngine(ctx).Where("blocker_id = ? AND blockee_id = ?", blockerID, blockeeID).Get(block)
+		if err != nil {
+			return err
+		}
+		if has {
+			block.Note = note
+			_, err = db.GetEngine(ctx).ID(block.ID).Cols("note").Update(block)
+			return err
+		}
+		return db.Insert(ctx, &Block{BlockerID: blockerID, BlockeeID: blockeeID, Note: note})
+	})
+}
+
+// UnblockUser removes a block
+func UnblockUser(ctx context.Context, blockerID, blockeeID int64) error {
+	n, err := db.GetEngine(ctx).Where("blocker_id = ? AND blockee_id = ?", blockerID, blockeeID).Delete(new(Block))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrBlockNotExist{BlockerID: blockerID, BlockeeID: blockeeID}
+	}
+	return nil
+}
+
+// FindBlockOptions represents the options to search blocks
+type FindBlockOptions struct {
+	db.ListOptions
+	BlockerID int64
+	BlockeeID int64
+}
+
+// ToConds implements db.FindOptions
+func (opts FindBlockOptions) ToConds() builder.Cond {
+	cond := builder.NewCond()
+	if opts.BlockerID > 0 {
+		cond = cond.And(builder.Eq{"blocker_id": opts.BlockerID})
+	}
+	if opts.BlockeeID > 0 {
+		cond = cond.And(builder.Eq{"blockee_id": opts.BlockeeID})
+	}
+	return cond
+}
+
+// FindBlocks returns the blocks matching the options with their blockees loaded, newest first
+func FindBlocks(ctx context.Context, opts FindBlockOptions) ([]*Block, int64, error) {
+	sess := db.GetEngine(ctx).Where(opts.ToConds()).OrderBy("id DESC")
+	if opts.PageSize > 0 {
+		sess = db.SetSessionPagination(sess, &opts.ListOptions)
+	}
+	blocks := make([]*Block, 0, opts.PageSize)
+	count, err := sess.FindAndCount(&blocks)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	blockeeIDs := make([]int64, 0, len(blocks))
+	for _, block := range blocks {
+		blockeeIDs = append(blockeeIDs, block.BlockeeID)
+	}
+	blockees := make(map[int64]*User, len(blockeeIDs))
+	if err := db.GetEngine(ctx).In("id", blockeeIDs).Find(&blockees); err != nil {
+		return nil, 0, err
+	}
+	for _, block := range blocks {
+		block.Blockee = blockees[block.BlockeeID]
+		if block.Blockee == nil {
+			block.Blockee = NewGhostUser()
+		}
+	}
+	return blocks, count, nil
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user_test
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBlockUser(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.Error(t, user_model.BlockUser(db.DefaultContext, 2, 2, ""))
+
+	assert.NoError(t, user_model.BlockUser(db.DefaultContext, 2, 4, "spam"))
+	assert.NoError(t, user_model.BlockUser(db.DefaultContext, 2, 4, "still spam"))
+
+	blocked, err := user_model.IsBlocked(db.DefaultContext, 2, 4)
+	assert.NoError(t, err)
+	assert.True(t, blocked)
+	blocked, err = user_model.IsBlocked(db.DefaultContext, 4, 2)
+	assert.NoError(t, err)
+	assert.False(t, blocked)
+
+	blocks, count, err := user_model.FindBlocks(db.DefaultContext, user_model.FindBlockOptions{BlockerID: 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, count)
+	if assert.Len(t, blocks, 1) {
+		assert.Equal(t, "still spam", blocks[0].Note)
+		assert.EqualValues(t, 4, blocks[0].Blockee.ID)
+	}
+
+	assert.NoError(t, user_model.UnblockUser(db.DefaultContext, 2, 4))
+	assert.True(t, user_model.IsErrBlockNotExist(user_model.UnblockUser(db.DefaultContext, 2, 4)))
+	unittest.AssertNotExistsBean(t, &user_model.Block{BlockerID: 2, BlockeeID: 4})
+}
@@ -0,0 +1,172 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// MaxLinkedIdentities is the maximum number of accounts on other instances a user can link
+const MaxLinkedIdentities = 10
+
+// ErrLinkedIdentityNotExist represents a "LinkedIdentityNotExist" kind of error.
+type ErrLinkedIdentityNotExist struct {
+	ID     int64
+	UserID int64
+}
+
+// IsErrLinkedIdentityNotExist checks if an error is a ErrLinkedIdentityNotExist.
+func IsErrLinkedIdentityNotExist(err error) bool {
+	_, ok := err.(ErrLinkedIdentityNotExist)
+	return ok
+}
+
+func (err ErrLinkedIdentityNotExist) Error() string {
+	return fmt.Sprintf("linked identity does not exist [id: %d, user_id: %d]", err.ID, err.UserID)
+}
+
+func (err ErrLinkedIdentityNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// ErrLinkedIdentityAlreadyExist represents a "LinkedIdentityAlreadyExist" kind of error.
+type ErrLinkedIdentityAlreadyExist struct {
+	InstanceURL    string
+	RemoteUsername string
+}
+
+// IsErrLinkedIdentityAlreadyExist checks if an error is a ErrLinkedIdentityAlreadyExist.
+func IsErrLinkedIdentityAlreadyExist(err error) bool {
+	_, ok := err.(ErrLinkedIdentityAlreadyExist)
+	return ok
+}
+
+func (err ErrLinkedIdentityAlreadyExist) Error() string {
+	return fmt.Sprintf("linked identity already exists [instance_url: %s, remote_username: %s]", err.InstanceURL, err.RemoteUsername)
+}
+
+func (err ErrLinkedIdentityAlreadyExist) Unwrap() error {
+	return util.ErrAlreadyExist
+}
+
+// LinkedIdentity is an account of the user on another Gitea instance. Once verified,
+// the public activity of the account is added to the heatmap of the user.
+type LinkedIdentity struct {
+	ID             int64  `xorm:"pk autoincr"`
+	UserID         int64  `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	InstanceURL    string `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+	RemoteUsername string `xorm:"UNIQUE(s) VARCHAR(255) NOT NULL"`
+	// Token has to be added to the profile description of the remote account to verify it belongs to the user
+	Token       string             `xorm:"VARCHAR(50) NOT NULL"`
+	IsVerified  bool               `xorm:"NOT NULL DEFAULT false"`
+	CreatedUnix timeutil.TimeStamp `xorm:"created"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+func init() {
+	db.RegisterModel(new(LinkedIdentity))
+}
+
+// ProfileURL returns the URL of the profile of the remote account
+func (l *LinkedIdentity) ProfileURL() string {
+	return l.InstanceURL + "/" + url.PathEscape(l.RemoteUsername)
+}
+
+// NormalizeInstanceURL returns the base URL of an instance without trailing slash
+func NormalizeInstanceURL(instanceURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(instanceURL))
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.User != nil || u.RawQuery != "" || u.Fragment != "" {
+		return "", util.NewInvalidArgumentErrorf("invalid instance URL %q", instanceURL)
+	}
+	return strings.TrimSuffix(u.Scheme+"://"+u.Host+u.Path, "/"), nil
+}
+
+// GetLinkedIdentities returns the linked identities of the user
+func GetLinkedIdentities(ctx context.Context, userID int64) ([]*LinkedIdentity, error) {
+	identities := make([]*LinkedIdentity, 0, 2)
+	return identities, db.GetEngine(ctx).Where("user_id = ?", userID).Asc("id").Find(&identities)
+}
+
+// GetVerifiedLinkedIdentities returns the verified linked identities of the user
+func GetVerifiedLinkedIdentities(ctx context.Context, userID int64) ([]*LinkedIdentity, error) {
+	identities := make([]*LinkedIdentity, 0, 2)
+	return identities, db.GetEngine(ctx).Where("user_id = ? AND is_verified = ?", userID, true).Asc("id").Find(&identities)
+}
+
+// GetLinkedIdentity returns the linked identity with the id of the user
+func GetLinkedIdentity(ctx context.Context, userID, id int64) (*LinkedIdentity, error) {
+	identity := &LinkedIdentity{}
+	has, err := db.GetEngine(ctx).Where("id = ? AND user_id = ?", id, userID).Get(identity)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrLinkedIdentityNotExist{ID: id, UserID: userID}
+	}
+	return identity, nil
+}
+
+// CreateLinkedIdentity links an unverified account on another instance to the user
+func CreateLinkedIdentity(ctx context.Context, identity *LinkedIdentity) error {
+	instanceURL, err := NormalizeInstanceURL(identity.InstanceURL)
+	if err != nil {
+		return err
+	}
+	identity.InstanceURL = instanceURL
+	identity.RemoteUsername = strings.TrimSpace(identity.RemoteUsername)
+	if identity.RemoteUsername == "" || strings.ContainsAny(identity.RemoteUsername, "/?#") {
+		return util.NewInvalidArgumentErrorf("invalid remote username %q", identity.RemoteUsername)
+	}
+	token, err := util.CryptoRandomString(20)
+	if err != nil {
+		return err
+	}
+	identity.Token = "gitea-link-" + token
+
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		e := db.GetEngine(ctx)
+		count, err := e.Where("user_id = ?", identity.UserID).Count(new(LinkedIdentity))
+		if err != nil {
+			return err
+		} else if count >= MaxLinkedIdentities {
+			return util.NewInvalidArgumentErrorf("at most %d accounts can be linked", MaxLinkedIdentities)
+		}
+
+		has, err := e.Where("user_id = ? AND instance_url = ? AND remote_username = ?", identity.UserID, identity.InstanceURL, identity.RemoteUsername).
+			Exist(new(LinkedIdentity))
+		if err != nil {
+			return err
+		} else if has {
+			return ErrLinkedIdentityAlreadyExist{InstanceURL: identity.InstanceURL, RemoteUsername: identity.RemoteUsername}
+		}
+
+		identity.IsVerified = false
+		_, err = e.Insert(identity)
+		return err
+	})
+}
+
+// MarkLinkedIdentityVerified marks the linked identity as verified
+func MarkLinkedIdentityVerified(ctx context.Context, identity *LinkedIdentity) error {
+	identity.IsVerified = true
+	_, err := db.GetEngine(ctx).ID(identity.ID).Cols("is_verified").Update(identity)
+	return err
+}
+
+// DeleteLinkedIdentity unlinks the account on another instance from the user
+func DeleteLinkedIdentity(ctx context.Context, userID, id int64) error {
+	deleted, err := db.GetEngine(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(new(LinkedIdentity))
+	if err != nil {
+		return err
+	} else if deleted == 0 {
+		return ErrLinkedIdentityNotExist{ID: id, UserID: userID}
+	}
+	return nil
+}
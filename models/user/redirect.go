@@ -9,7 +9,11 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
 )
 
 // ErrUserRedirectNotExist represents a "UserRedirectNotExist" kind of error.
@@ -33,9 +37,10 @@ func (err ErrUserRedirectNotExist) Unwrap() error {
 
 // Redirect represents that a user name should be redirected to another
 type Redirect struct {
-	ID             int64  `xorm:"pk autoincr"`
-	LowerName      string `xorm:"UNIQUE(s) INDEX NOT NULL"`
-	RedirectUserID int64  // userID to redirect to
+	ID             int64              `xorm:"pk autoincr"`
+	LowerName      string             `xorm:"UNIQUE(s) INDEX NOT NULL"`
+	RedirectUserID int64              // userID to redirect to
+	CreatedUnix    timeutil.TimeStamp `xorm:"INDEX created"`
 }
 
 // TableName provides the real table name
@@ -47,11 +52,39 @@ func init() {
 	db.RegisterModel(new(Redirect))
 }
 
+// ExpiresUnix returns when the redirect is deleted, 0 if it is kept until the name is used again
+func (r *Redirect) ExpiresUnix() timeutil.TimeStamp {
+	if setting.Service.UsernameRedirectRetention <= 0 {
+		return 0
+	}
+	return r.CreatedUnix.AddDuration(setting.Service.UsernameRedirectRetention)
+}
+
+// ReservedUntilUnix returns until when the name can't be used by other users or organizations,
+// 0 if it isn't reserved
+func (r *Redirect) ReservedUntilUnix() timeutil.TimeStamp {
+	if setting.Service.UsernameReservationPeriod <= 0 {
+		return 0
+	}
+	until := r.CreatedUnix.AddDuration(setting.Service.UsernameReservationPeriod)
+	if until <= timeutil.TimeStampNow() {
+		return 0
+	}
+	return until
+}
+
+func redirectNotExpiredCond() builder.Cond {
+	if setting.Service.UsernameRedirectRetention <= 0 {
+		return builder.NewCond()
+	}
+	return builder.Gt{"created_unix": timeutil.TimeStampNow().AddDuration(-setting.Service.UsernameRedirectRetention)}
+}
+
 // LookupUserRedirect look up userID if a user has a redirect name
 func LookupUserRedirect(userName string) (int64, error) {
 	userName = strings.ToLower(userName)
 	redirect := &Redirect{LowerName: userName}
-	if has, err := db.GetEngine(db.DefaultContext).Get(redirect); err != nil {
+	if has, err := db.GetEngine(db.DefaultContext).Where(redirectNotExpiredCond()).Get(redirect); err != nil {
 		return 0, err
 	} else if !has {
 		return 0, ErrUserRedirectNotExist{Name: userName}
@@ -81,3 +114,50 @@ func DeleteUserRedirect(ctx context.Context, userName string) error {
 	_, err := db.GetEngine(ctx).Delete(&Redirect{LowerName: userName})
 	return err
 }
+
+// CheckUserRedirectReserved returns ErrNameReserved if the name was used by another user or organization
+// which was renamed within the reservation period. Use 0 as userID for new users and organizations.
+func CheckUserRedirectReserved(ctx context.Context, userID int64, userName string) error {
+	redirect := &Redirect{}
+	has, err := db.GetEngine(ctx).Where("lower_name = ?", strings.ToLower(userName)).Get(redirect)
+	if err != nil {
+		return err
+	}
+	if has && redirect.RedirectUserID != userID && redirect.ReservedUntilUnix() > 0 {
+		return db.ErrNameReserved{Name: userName}
+	}
+	return nil
+}
+
+// GetUserRedirects returns the redirects to a user or organization, the most recent first
+func GetUserRedirects(ctx context.Context, userID int64) ([]*Redirect, error) {
+	redirects := make([]*Redirect, 0, 5)
+	return redirects, db.GetEngine(ctx).
+		Where("redirect_user_id = ?", userID).
+		And(redirectNotExpiredCond()).
+		Desc("created_unix", "id").
+		Find(&redirects)
+}
+
+// DeleteUserRedirectOf deletes a redirect to a user or organization, which releases its reservation
+func DeleteUserRedirectOf(ctx context.Context, userID int64, userName string) error {
+	userName = strings.ToLower(userName)
+	n, err := db.GetEngine(ctx).Where("redirect_user_id = ? AND lower_name = ?", userID, userName).Delete(new(Redirect))
+	if err != nil {
+		return err
+	} else if n == 0 {
+		return ErrUserRedirectNotExist{Name: userName}
+	}
+	return nil
+}
+
+// DeleteExpiredUserRedirects deletes the redirects older than the retention of the config
+func DeleteExpiredUserRedirects(ctx context.Context) error {
+	if setting.Service.UsernameRedirectRetention <= 0 {
+		return nil
+	}
+	_, err := db.GetEngine(ctx).
+		Where("created_unix <= ?", timeutil.TimeStampNow().AddDuration(-setting.Service.UsernameRedirectRetention)).
+		Delete(new(Redirect))
+	return err
+}
@@ -5,9 +5,12 @@ package user_test
 
 import (
 	"testing"
+	"time"
 
+	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -22,3 +25,58 @@ func TestLookupUserRedirect(t *testing.T) {
 	_, err = user_model.LookupUserRedirect("doesnotexist")
 	assert.True(t, user_model.IsErrUserRedirectNotExist(err))
 }
+
+func TestUserRedirectRetention(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer func(retention time.Duration) {
+		setting.Service.UsernameRedirectRetention = retention
+	}(setting.Service.UsernameRedirectRetention)
+
+	// the redirect of the fixture is older than any retention
+	setting.Service.UsernameRedirectRetention = time.Hour
+	_, err := user_model.LookupUserRedirect("olduser1")
+	assert.True(t, user_model.IsErrUserRedirectNotExist(err))
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.NoError(t, user_model.NewUserRedirect(db.DefaultContext, user.ID, user.Name, "newusername"))
+	userID, err := user_model.LookupUserRedirect(user.Name)
+	assert.NoError(t, err)
+	assert.EqualValues(t, user.ID, userID)
+
+	redirects, err := user_model.GetUserRedirects(db.DefaultContext, user.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, redirects, 1) {
+		assert.NotZero(t, redirects[0].ExpiresUnix())
+	}
+
+	assert.NoError(t, user_model.DeleteExpiredUserRedirects(db.DefaultContext))
+	unittest.AssertNotExistsBean(t, &user_model.Redirect{LowerName: "olduser1"})
+	unittest.AssertExistsAndLoadBean(t, &user_model.Redirect{LowerName: user.LowerName})
+}
+
+func TestCheckUserRedirectReserved(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	defer func(period time.Duration) {
+		setting.Service.UsernameReservationPeriod = period
+	}(setting.Service.UsernameReservationPeriod)
+
+	user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.NoError(t, user_model.NewUserRedirect(db.DefaultContext, user.ID, user.Name, "newusername"))
+
+	// without a reservation period anybody can take the name
+	assert.NoError(t, user_model.CheckUserRedirectReserved(db.DefaultContext, 0, user.Name))
+
+	setting.Service.UsernameReservationPeriod = time.Hour
+	err := user_model.CheckUserRedirectReserved(db.DefaultContext, 0, user.Name)
+	assert.True(t, db.IsErrNameReserved(err))
+	// the user can take back its former name
+	assert.NoError(t, user_model.CheckUserRedirectReserved(db.DefaultContext, user.ID, user.Name))
+	// the reservation of the fixture has ended
+	assert.NoError(t, user_model.CheckUserRedirectReserved(db.DefaultContext, 0, "olduser1"))
+
+	// deleting the redirect releases the name
+	err = user_model.DeleteUserRedirectOf(db.DefaultContext, 1, user.Name)
+	assert.True(t, user_model.IsErrUserRedirectNotExist(err))
+	assert.NoError(t, user_model.DeleteUserRedirectOf(db.DefaultContext, user.ID, user.Name))
+	assert.NoError(t, user_model.CheckUserRedirectReserved(db.DefaultContext, 0, user.Name))
+}
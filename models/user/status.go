@@ -0,0 +1,170 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"context"
+	"fmt"
+	"unicode/utf8"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/emoji"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// UserStatusMessageMaxLength is the maximum number of characters of a status message
+const UserStatusMessageMaxLength = 100
+
+// ErrUserStatusNotExist represents a "UserStatusNotExist" kind of error.
+type ErrUserStatusNotExist struct {
+	UserID int64
+}
+
+// IsErrUserStatusNotExist checks if an error is a ErrUserStatusNotExist.
+func IsErrUserStatusNotExist(err error) bool {
+	_, ok := err.(ErrUserStatusNotExist)
+	return ok
+}
+
+func (err ErrUserStatusNotExist) Error() string {
+	return fmt.Sprintf("user status does not exist [user_id: %d]", err.UserID)
+}
+
+func (err ErrUserStatusNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
+
+// UserStatus represents the status a user has set: an emoji, a short message and
+// whether the user is busy. A status with an end time is ignored once it has passed.
+type UserStatus struct { //revive:disable-line:exported
+	UserID      int64              `xorm:"pk"`
+	Emoji       string             `xorm:"VARCHAR(100)"`
+	Message     string             `xorm:"VARCHAR(255)"`
+	IsBusy      bool               `xorm:"INDEX NOT NULL DEFAULT false"`
+	EndUnix     timeutil.TimeStamp `xorm:"INDEX NOT NULL DEFAULT 0"`
+	UpdatedUnix timeutil.TimeStamp `xorm:"updated"`
+}
+
+// TableName sets the table name of the user status
+func (UserStatus) TableName() string {
+	return "user_status"
+}
+
+func init() {
+	db.RegisterModel(new(UserStatus))
+}
+
+// IsExpired returns true if the status has an end time that has passed
+func (s *UserStatus) IsExpired() bool {
+	return s.EndUnix > 0 && s.EndUnix <= timeutil.TimeStampNow()
+}
+
+// IsEmpty returns true if the status carries no information
+func (s *UserStatus) IsEmpty() bool {
+	return s.Emoji == "" && s.Message == "" && !s.IsBusy
+}
+
+// activeStatusCond returns the condition of statuses that have not expired
+func activeStatusCond() builder.Cond {
+	return builder.Eq{"end_unix": 0}.Or(builder.Gt{"end_unix": timeutil.TimeStampNow()})
+}
+
+// GetUserStatus returns the active status of the user
+func GetUserStatus(ctx context.Context, userID int64) (*UserStatus, error) {
+	status := &UserStatus{}
+	has, err := db.GetEngine(ctx).Where(builder.Eq{"user_id": userID}.And(activeStatusCond())).Get(status)
+	if err != nil {
+		return nil, err
+	} else if !has {
+		return nil, ErrUserStatusNotExist{UserID: userID}
+	}
+	return status, nil
+}
+
+// GetUserStatuses returns the active statuses of the given users mapped by user id
+func GetUserStatuses(ctx context.Context, userIDs []int64) (map[int64]*UserStatus, error) {
+	statuses := make(map[int64]*UserStatus, len(userIDs))
+	if len(userIDs) == 0 {
+		return statuses, nil
+	}
+	return statuses, db.GetEngine(ctx).
+		Where(builder.In("user_id", userIDs).And(activeStatusCond())).
+		Find(&statuses)
+}
+
+// isValidStatusEmoji returns true if the emoji is empty, a known emoji alias or a custom emoji
+func isValidStatusEmoji(e string) bool {
+	if e == "" || emoji.FromAlias(e) != nil || emoji.FromCode(e) != nil {
+		return true
+	}
+	_, ok := setting.UI.CustomEmojisMap[e]
+	return ok
+}
+
+// SetUserStatus creates or replaces the status of a user, an empty status clears it
+func SetUserStatus(ctx context.Context, status *UserStatus) error {
+	if utf8.RuneCountInString(status.Message) > UserStatusMessageMaxLength {
+		return util.NewInvalidArgumentErrorf("status message is longer than %d characters", UserStatusMessageMaxLength)
+	}
+	if !isValidStatusEmoji(status.Emoji) {
+		return util.NewInvalidArgumentErrorf("status emoji %q is unknown", status.Emoji)
+	}
+	if status.IsExpired() {
+		return util.NewInvalidArgumentErrorf("status end time must be in the future")
+	}
+	if status.IsEmpty() {
+		return ClearUserStatus(ctx, status.UserID)
+	}
+	return db.WithTx(ctx, func(ctx context.Context) error {
+		has, err := db.GetEngine(ctx).Exist(&UserStatus{UserID: status.UserID})
+		if err != nil {
+			return err
+		}
+		if has {
+			_, err = db.GetEngine(ctx).ID(status.UserID).AllCols().Update(status)
+			return err
+		}
+		return db.Insert(ctx, status)
+	})
+}
+
+// ClearUserStatus removes the status of a user
+func ClearUserStatus(ctx context.Context, userID int64) error {
+	_, err := db.GetEngine(ctx).Delete(&UserStatus{UserID: userID})
+	return err
+}
+
+// IsUserBusy returns true if the user has an active status marked as busy
+func IsUserBusy(ctx context.Context, userID int64) (bool, error) {
+	return db.GetEngine(ctx).
+		Where(builder.Eq{"user_id": userID, "is_busy": true}.And(activeStatusCond())).
+		Exist(new(UserStatus))
+}
+
+// FilterBusyUsers returns the users that are not busy, keeping their order
+func FilterBusyUsers(ctx context.Context, users []*User) ([]*User, error) {
+	if len(users) == 0 {
+		return users, nil
+	}
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		ids = append(ids, u.ID)
+	}
+	statuses, err := GetUserStatuses(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	available := make([]*User, 0, len(users))
+	for _, u := range users {
+		if s, ok := statuses[u.ID]; ok && s.IsBusy {
+			continue
+		}
+		available = append(available, u)
+	}
+	return available, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user_test
+
+import (
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUserStatus(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	assert.Error(t, user_model.SetUserStatus(db.DefaultContext, &user_model.UserStatus{UserID: 2, Message: strings.Repeat("a", 101)}))
+	assert.Error(t, user_model.SetUserStatus(db.DefaultContext, &user_model.UserStatus{UserID: 2, Message: "past", EndUnix: 1}))
+
+	assert.NoError(t, user_model.SetUserStatus(db.DefaultContext, &user_model.UserStatus{UserID: 2, Emoji: "palm_tree", Message: "On vacation", IsBusy: true}))
+	assert.NoError(t, user_model.SetUserStatus(db.DefaultContext, &user_model.UserStatus{UserID: 4, Message: "Around"}))
+
+	status, err := user_model.GetUserStatus(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.Equal(t, "On vacation", status.Message)
+	assert.True(t, status.IsBusy)
+
+	busy, err := user_model.IsUserBusy(db.DefaultContext, 2)
+	assert.NoError(t, err)
+	assert.True(t, busy)
+	busy, err = user_model.IsUserBusy(db.DefaultContext, 4)
+	assert.NoError(t, err)
+	assert.False(t, busy)
+
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	available, err := user_model.FilterBusyUsers(db.DefaultContext, []*user_model.User{user2, user4})
+	assert.NoError(t, err)
+	if assert.Len(t, available, 1) {
+		assert.EqualValues(t, 4, available[0].ID)
+	}
+
+	// an expired status is ignored
+	_, err = db.GetEngine(db.DefaultContext).ID(int64(2)).Cols("end_unix").Update(&user_model.UserStatus{EndUnix: timeutil.TimeStampNow() - 10})
+	assert.NoError(t, err)
+	_, err = user_model.GetUserStatus(db.DefaultContext, 2)
+	assert.True(t, user_model.IsErrUserStatusNotExist(err))
+
+	statuses, err := user_model.GetUserStatuses(db.DefaultContext, []int64{2, 4})
+	assert.NoError(t, err)
+	assert.Len(t, statuses, 1)
+	assert.Contains(t, statuses, int64(4))
+
+	// setting an empty status clears it
+	assert.NoError(t, user_model.SetUserStatus(db.DefaultContext, &user_model.UserStatus{UserID: 4}))
+	unittest.AssertNotExistsBean(t, &user_model.UserStatus{UserID: 4})
+}
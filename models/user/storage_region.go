@@ -0,0 +1,151 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// storageRegionCache holds the storage regions of the pinned owners. Repository paths are
+// resolved everywhere by owner name without a database context, so they are kept in memory.
+var storageRegionCache = struct {
+	sync.RWMutex
+	loaded  bool
+	byName  map[string]string
+	byID    map[int64]string
+	idNames map[int64]string
+}{}
+
+// loadStorageRegions fills the storage region cache from the database
+func loadStorageRegions(ctx context.Context) error {
+	users := make([]*User, 0, 10)
+	if err := db.GetEngine(ctx).Cols("id", "lower_name", "storage_region").
+		Where("storage_region <> ''").Find(&users); err != nil {
+		return err
+	}
+
+	storageRegionCache.byName = make(map[string]string, len(users))
+	storageRegionCache.byID = make(map[int64]string, len(users))
+	storageRegionCache.idNames = make(map[int64]string, len(users))
+	for _, u := range users {
+		if _, ok := setting.StorageRegions[u.StorageRegion]; !ok {
+			log.Error("%s is pinned to the storage region %q which isn't configured", u.LowerName, u.StorageRegion)
+		}
+		storageRegionCache.byName[u.LowerName] = u.StorageRegion
+		storageRegionCache.byID[u.ID] = u.StorageRegion
+		storageRegionCache.idNames[u.ID] = u.LowerName
+	}
+	storageRegionCache.loaded = true
+	return nil
+}
+
+// ensureStorageRegionsLoaded loads the storage region cache on first use
+func ensureStorageRegionsLoaded() bool {
+	storageRegionCache.RLock()
+	loaded := storageRegionCache.loaded
+	storageRegionCache.RUnlock()
+	if loaded {
+		return true
+	}
+	if !db.HasEngine {
+		return false
+	}
+
+	storageRegionCache.Lock()
+	defer storageRegionCache.Unlock()
+	if !storageRegionCache.loaded {
+		if err := loadStorageRegions(db.DefaultContext); err != nil {
+			log.Error("Unable to load storage regions: %v", err)
+			return false
+		}
+	}
+	return true
+}
+
+// StorageRegionOf returns the storage region the data of an owner is pinned to, empty for the default storages
+func StorageRegionOf(userName string) string {
+	if len(setting.StorageRegions) == 0 || !ensureStorageRegionsLoaded() {
+		return ""
+	}
+	storageRegionCache.RLock()
+	defer storageRegionCache.RUnlock()
+	return storageRegionCache.byName[strings.ToLower(userName)]
+}
+
+// StorageRegionOfID returns the storage region the data of an owner is pinned to, empty for the default storages
+func StorageRegionOfID(userID int64) string {
+	if len(setting.StorageRegions) == 0 || !ensureStorageRegionsLoaded() {
+		return ""
+	}
+	storageRegionCache.RLock()
+	defer storageRegionCache.RUnlock()
+	return storageRegionCache.byID[userID]
+}
+
+// SetStorageRegion pins the data of an owner to a storage region, the data must have been moved already
+func SetStorageRegion(ctx context.Context, u *User, region string) error {
+	if region != "" {
+		if _, ok := setting.StorageRegions[region]; !ok {
+			return ErrStorageRegionNotExist{Region: region}
+		}
+	}
+	u.StorageRegion = region
+	if _, err := db.GetEngine(ctx).ID(u.ID).Cols("storage_region").Update(u); err != nil {
+		return err
+	}
+	setCachedStorageRegion(u.ID, u.LowerName, region)
+	return nil
+}
+
+func setCachedStorageRegion(userID int64, lowerName, region string) {
+	if len(setting.StorageRegions) == 0 || !ensureStorageRegionsLoaded() {
+		return
+	}
+	storageRegionCache.Lock()
+	defer storageRegionCache.Unlock()
+	if oldName, ok := storageRegionCache.idNames[userID]; ok {
+		delete(storageRegionCache.byName, oldName)
+		delete(storageRegionCache.byID, userID)
+		delete(storageRegionCache.idNames, userID)
+	}
+	if region != "" {
+		storageRegionCache.byName[lowerName] = region
+		storageRegionCache.byID[userID] = region
+		storageRegionCache.idNames[userID] = lowerName
+	}
+}
+
+// RemoveCachedStorageRegion forgets the storage region of a deleted owner,
+// so a new owner with the same name doesn't inherit it
+func RemoveCachedStorageRegion(userID int64) {
+	setCachedStorageRegion(userID, "", "")
+}
+
+// ErrStorageRegionNotExist represents a "StorageRegionNotExist" kind of error.
+type ErrStorageRegionNotExist struct {
+	Region string
+}
+
+// IsErrStorageRegionNotExist checks if an error is a ErrStorageRegionNotExist.
+func IsErrStorageRegionNotExist(err error) bool {
+	_, ok := err.(ErrStorageRegionNotExist)
+	return ok
+}
+
+func (err ErrStorageRegionNotExist) Error() string {
+	return fmt.Sprintf("storage region does not exist [region: %s]", err.Region)
+}
+
+// Unwrap unwraps this error as a ErrNotExist error
+func (err ErrStorageRegionNotExist) Unwrap() error {
+	return util.ErrNotExist
+}
@@ -66,6 +66,15 @@ const (
 	EmailNotificationsAndYourOwn = "andyourown"
 )
 
+const (
+	// EmailDigestNone indicates that the user would like to receive each email notification immediately
+	EmailDigestNone = ""
+	// EmailDigestHourly indicates that the user would like to receive the non-urgent email notifications in an hourly digest
+	EmailDigestHourly = "hourly"
+	// EmailDigestDaily indicates that the user would like to receive the non-urgent email notifications in a daily digest
+	EmailDigestDaily = "daily"
+)
+
 // User represents the object of individual and member of organization.
 type User struct {
 	ID        int64  `xorm:"pk autoincr"`
@@ -135,11 +144,15 @@ type User struct {
 	NumMembers                int
 	Visibility                structs.VisibleType `xorm:"NOT NULL DEFAULT 0"`
 	RepoAdminChangeTeamAccess bool                `xorm:"NOT NULL DEFAULT false"`
+	// StorageRegion is the data residency region the data of the owner is pinned to, empty for the default storages
+	StorageRegion string `xorm:"VARCHAR(50) NOT NULL DEFAULT ''"`
 
 	// Preferences
 	DiffViewStyle       string `xorm:"NOT NULL DEFAULT ''"`
 	Theme               string `xorm:"NOT NULL DEFAULT ''"`
 	KeepActivityPrivate bool   `xorm:"NOT NULL DEFAULT false"`
+	// CountPrivateActivity counts the activity in repositories others can not see in the heatmap, without showing any details
+	CountPrivateActivity bool `xorm:"NOT NULL DEFAULT false"`
 }
 
 func init() {
@@ -483,6 +496,27 @@ func SetEmailNotifications(u *User, set string) error {
 	return nil
 }
 
+// IsEmailDigestFrequencyValid checks if the email digest frequency is valid
+func IsEmailDigestFrequencyValid(frequency string) bool {
+	return frequency == EmailDigestNone || frequency == EmailDigestHourly || frequency == EmailDigestDaily
+}
+
+// GetEmailDigestFrequency returns how often the user receives the digest of the non-urgent email notifications
+func GetEmailDigestFrequency(userID int64) (string, error) {
+	return GetUserSetting(userID, SettingsKeyEmailDigestFrequency, EmailDigestNone)
+}
+
+// SetEmailDigestFrequency sets how often the user receives the digest of the non-urgent email notifications
+func SetEmailDigestFrequency(userID int64, frequency string) error {
+	if !IsEmailDigestFrequencyValid(frequency) {
+		return util.NewInvalidArgumentErrorf("invalid email digest frequency %q", frequency)
+	}
+	if frequency == EmailDigestNone {
+		return DeleteUserSetting(userID, SettingsKeyEmailDigestFrequency)
+	}
+	return SetUserSetting(userID, SettingsKeyEmailDigestFrequency, frequency)
+}
+
 // IsUserExist checks if given user name exist,
 // the user name should be noncased unique.
 // If uid is presented, then check will rule out that one,
@@ -671,6 +705,10 @@ func CreateUser(u *User, overwriteDefault ...*CreateUserOverwriteOptions) (err e
 
 	// save changes to database
 
+	if err = CheckUserRedirectReserved(ctx, 0, u.Name); err != nil {
+		return err
+	}
+
 	if err = DeleteUserRedirect(ctx, u.Name); err != nil {
 		return err
 	}
@@ -776,12 +814,20 @@ func ChangeUserName(ctx context.Context, u *User, newUserName string) (err error
 		return ErrUserAlreadyExist{newUserName}
 	}
 
+	if err = CheckUserRedirectReserved(ctx, u.ID, newUserName); err != nil {
+		return err
+	}
+
 	if _, err = db.GetEngine(ctx).Exec("UPDATE `repository` SET owner_name=? WHERE owner_name=?", newUserName, oldUserName); err != nil {
 		return fmt.Errorf("Change repo owner name: %w", err)
 	}
 
 	// Do not fail if directory does not exist
-	if err = util.Rename(UserPath(oldUserName), UserPath(newUserName)); err != nil && !os.IsNotExist(err) {
+	oldUserPath := UserPath(oldUserName)
+	region := StorageRegionOf(oldUserName)
+	setCachedStorageRegion(u.ID, strings.ToLower(newUserName), region)
+	if err = util.Rename(oldUserPath, UserPath(newUserName)); err != nil && !os.IsNotExist(err) {
+		setCachedStorageRegion(u.ID, strings.ToLower(oldUserName), region)
 		return fmt.Errorf("Rename user directory: %w", err)
 	}
 
@@ -790,7 +836,9 @@ func ChangeUserName(ctx context.Context, u *User, newUserName string) (err error
 	}
 
 	if err = committer.Commit(); err != nil {
-		if err2 := util.Rename(UserPath(newUserName), UserPath(oldUserName)); err2 != nil && !os.IsNotExist(err2) {
+		newUserPath := UserPath(newUserName)
+		setCachedStorageRegion(u.ID, strings.ToLower(oldUserName), region)
+		if err2 := util.Rename(newUserPath, oldUserPath); err2 != nil && !os.IsNotExist(err2) {
 			log.Critical("Unable to rollback directory change during failed username change from: %s to: %s. DB Error: %v. Filesystem Error: %v", oldUserName, newUserName, err, err2)
 			return fmt.Errorf("failed to rollback directory change during failed username change from: %s to: %s. DB Error: %w. Filesystem Error: %v", oldUserName, newUserName, err, err2)
 		}
@@ -939,7 +987,7 @@ func GetInactiveUsers(ctx context.Context, olderThan time.Duration) ([]*User, er
 
 // UserPath returns the path absolute path of user repositories.
 func UserPath(userName string) string { //revive:disable-line:exported
-	return filepath.Join(setting.RepoRootPath, strings.ToLower(userName))
+	return filepath.Join(setting.GetRepoRootPathOfRegion(StorageRegionOf(userName)), strings.ToLower(userName))
 }
 
 // GetUserByID returns the user object by given ID if exists.
@@ -110,6 +110,31 @@ func HookTasks(hookID int64, page int) ([]*HookTask, error) {
 		Find(&tasks)
 }
 
+// GetLastDeliveryTimes returns the time of the last delivered task of each of the webhooks,
+// webhooks without delivered tasks are missing in the result.
+func GetLastDeliveryTimes(ctx context.Context, hookIDs []int64) (map[int64]timeutil.TimeStampNano, error) {
+	type lastDelivery struct {
+		HookID    int64
+		Delivered timeutil.TimeStampNano
+	}
+	deliveries := make([]*lastDelivery, 0, len(hookIDs))
+	if len(hookIDs) > 0 {
+		if err := db.GetEngine(ctx).Table("hook_task").
+			Select("hook_id, MAX(delivered) AS delivered").
+			In("hook_id", hookIDs).
+			And("is_delivered = ?", true).
+			GroupBy("hook_id").
+			Find(&deliveries); err != nil {
+			return nil, err
+		}
+	}
+	result := make(map[int64]timeutil.TimeStampNano, len(deliveries))
+	for _, d := range deliveries {
+		result[d.HookID] = d.Delivered
+	}
+	return result, nil
+}
+
 // CreateHookTask creates a new hook task,
 // it handles conversion from Payload to PayloadContent.
 func CreateHookTask(ctx context.Context, t *HookTask) (*HookTask, error) {
@@ -138,6 +138,16 @@ type Webhook struct {
 	// HeaderAuthorizationEncrypted should be accessed using HeaderAuthorization() and SetHeaderAuthorization()
 	HeaderAuthorizationEncrypted string `xorm:"TEXT"`
 
+	// ClientCertificate is the PEM encoded certificate presented to the target,
+	// its key ClientKeyEncrypted should be accessed using ClientKey() and SetClientCertificate()
+	ClientCertificate  string `xorm:"TEXT"`
+	ClientKeyEncrypted string `xorm:"TEXT"`
+	// AllowedIPs are the comma separated IP addresses and CIDR ranges the target is pinned to
+	AllowedIPs string `xorm:"allowed_ips TEXT"`
+	// PreviousSecret still signs the payloads after a secret rotation until it expires
+	PreviousSecret            string             `xorm:"TEXT"`
+	PreviousSecretExpiresUnix timeutil.TimeStamp `xorm:"NOT NULL DEFAULT 0"`
+
 	CreatedUnix timeutil.TimeStamp `xorm:"INDEX created"`
 	UpdatedUnix timeutil.TimeStamp `xorm:"INDEX updated"`
 }
@@ -298,6 +308,24 @@ func (w *Webhook) HasPackageEvent() bool {
 		(w.ChooseEvents && w.HookEvents.Package)
 }
 
+// HasDiscussionEvent returns if hook enabled discussion event.
+func (w *Webhook) HasDiscussionEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.Discussion)
+}
+
+// HasDiscussionCommentEvent returns if hook enabled discussion comment event.
+func (w *Webhook) HasDiscussionCommentEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.DiscussionComment)
+}
+
+// HasActionRunApprovalEvent returns if hook enabled action run approval event.
+func (w *Webhook) HasActionRunApprovalEvent() bool {
+	return w.SendEverything ||
+		(w.ChooseEvents && w.HookEvents.ActionRunApproval)
+}
+
 // EventCheckers returns event checkers
 func (w *Webhook) EventCheckers() []struct {
 	Has  func() bool
@@ -329,6 +357,9 @@ func (w *Webhook) EventCheckers() []struct {
 		{w.HasRepositoryEvent, webhook_module.HookEventRepository},
 		{w.HasReleaseEvent, webhook_module.HookEventRelease},
 		{w.HasPackageEvent, webhook_module.HookEventPackage},
+		{w.HasDiscussionEvent, webhook_module.HookEventDiscussion},
+		{w.HasDiscussionCommentEvent, webhook_module.HookEventDiscussionComment},
+		{w.HasActionRunApprovalEvent, webhook_module.HookEventActionRunApproval},
 	}
 }
 
@@ -423,9 +454,11 @@ func GetWebhookByOwnerID(ownerID, id int64) (*Webhook, error) {
 // ListWebhookOptions are options to filter webhooks on ListWebhooksByOpts
 type ListWebhookOptions struct {
 	db.ListOptions
-	RepoID   int64
-	OwnerID  int64
-	IsActive util.OptionalBool
+	RepoID     int64
+	OwnerID    int64
+	IsActive   util.OptionalBool
+	Type       webhook_module.HookType
+	URLKeyword string
 }
 
 func (opts *ListWebhookOptions) toCond() builder.Cond {
@@ -439,6 +472,12 @@ func (opts *ListWebhookOptions) toCond() builder.Cond {
 	if !opts.IsActive.IsNone() {
 		cond = cond.And(builder.Eq{"webhook.is_active": opts.IsActive.IsTrue()})
 	}
+	if opts.Type != "" {
+		cond = cond.And(builder.Eq{"webhook.type": opts.Type})
+	}
+	if opts.URLKeyword != "" {
+		cond = cond.And(builder.Like{"webhook.url", opts.URLKeyword})
+	}
 	return cond
 }
 
@@ -0,0 +1,136 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package webhook
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/secret"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ClientKey returns the decrypted PEM encoded key of the client certificate
+func (w *Webhook) ClientKey() (string, error) {
+	if w.ClientKeyEncrypted == "" {
+		return "", nil
+	}
+	return secret.DecryptSecret(setting.SecretKey, w.ClientKeyEncrypted)
+}
+
+// SetClientCertificate checks and sets the PEM encoded client certificate and its key,
+// the client certificate is removed if both are empty
+func (w *Webhook) SetClientCertificate(cert, key string) error {
+	if cert == "" && key == "" {
+		w.ClientCertificate = ""
+		w.ClientKeyEncrypted = ""
+		return nil
+	}
+	if _, err := tls.X509KeyPair([]byte(cert), []byte(key)); err != nil {
+		return util.NewInvalidArgumentErrorf("invalid client certificate or key: %v", err)
+	}
+	ciphertext, err := secret.EncryptSecret(setting.SecretKey, key)
+	if err != nil {
+		return err
+	}
+	w.ClientCertificate = cert
+	w.ClientKeyEncrypted = ciphertext
+	return nil
+}
+
+// ClientTLSCertificate returns the certificate presented to the target, or nil if there is none
+func (w *Webhook) ClientTLSCertificate() (*tls.Certificate, error) {
+	if w.ClientCertificate == "" {
+		return nil, nil
+	}
+	key, err := w.ClientKey()
+	if err != nil {
+		return nil, err
+	}
+	cert, err := tls.X509KeyPair([]byte(w.ClientCertificate), []byte(key))
+	if err != nil {
+		return nil, err
+	}
+	return &cert, nil
+}
+
+// ParseIPRanges parses comma separated IP addresses and CIDR ranges
+func ParseIPRanges(s string) ([]*net.IPNet, error) {
+	var ranges []*net.IPNet
+	for _, field := range strings.Split(s, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !strings.Contains(field, "/") {
+			ip := net.ParseIP(field)
+			if ip == nil {
+				return nil, util.NewInvalidArgumentErrorf("invalid IP address: %s", field)
+			}
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+			ranges = append(ranges, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(field)
+		if err != nil {
+			return nil, util.NewInvalidArgumentErrorf("invalid IP range: %s", field)
+		}
+		ranges = append(ranges, ipNet)
+	}
+	return ranges, nil
+}
+
+// SetAllowedIPs checks and sets the IP addresses and CIDR ranges the target is pinned to
+func (w *Webhook) SetAllowedIPs(s string) error {
+	ranges, err := ParseIPRanges(s)
+	if err != nil {
+		return err
+	}
+	allowed := make([]string, 0, len(ranges))
+	for _, r := range ranges {
+		allowed = append(allowed, r.String())
+	}
+	w.AllowedIPs = strings.Join(allowed, ",")
+	return nil
+}
+
+// AllowedIPRanges returns the IP ranges the target is pinned to, the target is not pinned if there are none
+func (w *Webhook) AllowedIPRanges() ([]*net.IPNet, error) {
+	return ParseIPRanges(w.AllowedIPs)
+}
+
+// RotateSecret replaces the secret of the webhook, the current secret keeps signing the payloads
+// beside the new one for the given overlap
+func (w *Webhook) RotateSecret(newSecret string, overlap time.Duration) {
+	w.PreviousSecret = ""
+	w.PreviousSecretExpiresUnix = 0
+	if w.Secret != "" && overlap > 0 {
+		w.PreviousSecret = w.Secret
+		w.PreviousSecretExpiresUnix = timeutil.TimeStamp(time.Now().Add(overlap).Unix())
+	}
+	w.Secret = newSecret
+}
+
+// ValidPreviousSecret returns the secret replaced by the last rotation while it is still valid
+func (w *Webhook) ValidPreviousSecret() string {
+	if w.PreviousSecret == "" || w.PreviousSecretExpiresUnix <= timeutil.TimeStampNow() {
+		return ""
+	}
+	return w.PreviousSecret
+}
+
+// UpdateWebhookSecret updates the secrets of the webhook after a rotation
+func UpdateWebhookSecret(ctx context.Context, w *Webhook) error {
+	_, err := db.GetEngine(ctx).ID(w.ID).Cols("secret", "previous_secret", "previous_secret_expires_unix").Update(w)
+	return err
+}
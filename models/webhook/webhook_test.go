@@ -73,7 +73,7 @@ func TestWebhook_EventsArray(t *testing.T) {
 		"pull_request", "pull_request_assign", "pull_request_label", "pull_request_milestone",
 		"pull_request_comment", "pull_request_review_approved", "pull_request_review_rejected",
 		"pull_request_review_comment", "pull_request_sync", "wiki", "repository", "release",
-		"package",
+		"package", "discussion", "discussion_comment", "action_run_approval",
 	},
 		(&Webhook{
 			HookEvent: &webhook_module.HookEvent{SendEverything: true},
@@ -328,3 +328,44 @@ func TestCleanupHookTaskTable_OlderThan_LeavesTaskEarlierThanAgeToDelete(t *test
 	assert.NoError(t, CleanupHookTaskTable(context.Background(), OlderThan, 168*time.Hour, 0))
 	unittest.AssertExistsAndLoadBean(t, hookTask)
 }
+
+func TestWebhook_SetAllowedIPs(t *testing.T) {
+	w := &Webhook{}
+	assert.NoError(t, w.SetAllowedIPs(" 192.168.1.7, 10.0.0.0/8,,::1 "))
+	assert.Equal(t, "192.168.1.7/32,10.0.0.0/8,::1/128", w.AllowedIPs)
+
+	ranges, err := w.AllowedIPRanges()
+	assert.NoError(t, err)
+	assert.Len(t, ranges, 3)
+
+	assert.ErrorIs(t, w.SetAllowedIPs("10.0.0.0/33"), util.ErrInvalidArgument)
+	assert.ErrorIs(t, w.SetAllowedIPs("example.com"), util.ErrInvalidArgument)
+
+	assert.NoError(t, w.SetAllowedIPs(""))
+	assert.Empty(t, w.AllowedIPs)
+}
+
+func TestWebhook_RotateSecret(t *testing.T) {
+	w := &Webhook{Secret: "old"}
+	w.RotateSecret("new", time.Hour)
+	assert.Equal(t, "new", w.Secret)
+	assert.Equal(t, "old", w.ValidPreviousSecret())
+
+	w.PreviousSecretExpiresUnix = timeutil.TimeStampNow() - 1
+	assert.Empty(t, w.ValidPreviousSecret())
+
+	w.RotateSecret("newer", 0)
+	assert.Equal(t, "newer", w.Secret)
+	assert.Empty(t, w.PreviousSecret)
+	assert.Empty(t, w.ValidPreviousSecret())
+}
+
+func TestWebhook_SetClientCertificate(t *testing.T) {
+	w := &Webhook{}
+	assert.ErrorIs(t, w.SetClientCertificate("not a certificate", "not a key"), util.ErrInvalidArgument)
+
+	assert.NoError(t, w.SetClientCertificate("", ""))
+	cert, err := w.ClientTLSCertificate()
+	assert.NoError(t, err)
+	assert.Nil(t, cert)
+}
@@ -0,0 +1,59 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"regexp"
+	"unicode/utf8"
+)
+
+// ansiEscapePattern matches CSI sequences like colors, OSC sequences like hyperlinks and the other two byte escapes
+var ansiEscapePattern = regexp.MustCompile(`\x1b(?:\[[0-9:;<=>?]*[ -/]*[@-~]|\][^\x07\x1b]*(?:\x07|\x1b\\)|[0-Z\\-_])`)
+
+// StripANSI removes the ANSI escape sequences from the content of a log line
+func StripANSI(content string) string {
+	return ansiEscapePattern.ReplaceAllString(content, "")
+}
+
+// ChunkLogContent splits the content of a log line into chunks of at most size bytes. A chunk never ends
+// within an ANSI escape sequence or an UTF-8 encoded character, so each chunk can be rendered on its own.
+// Only an escape sequence which is longer than size itself results in a longer chunk.
+func ChunkLogContent(content string, size int) []string {
+	if size <= 0 || len(content) <= size {
+		return []string{content}
+	}
+
+	escapes := ansiEscapePattern.FindAllStringIndex(content, -1)
+	chunks := make([]string, 0, len(content)/size+1)
+	start := 0
+	for len(content)-start > size {
+		end := start + size
+		for _, escape := range escapes {
+			if escape[0] >= end {
+				break
+			}
+			if escape[1] > end {
+				if escape[0] > start {
+					end = escape[0]
+				} else {
+					end = escape[1]
+				}
+				break
+			}
+		}
+		for end > start && end < len(content) && !utf8.RuneStart(content[end]) {
+			end--
+		}
+		if end == start {
+			// not valid UTF-8, nothing to keep together
+			end = start + size
+		}
+		chunks = append(chunks, content[start:end])
+		start = end
+	}
+	if start < len(content) {
+		chunks = append(chunks, content[start:])
+	}
+	return chunks
+}
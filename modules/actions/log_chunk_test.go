@@ -0,0 +1,32 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStripANSI(t *testing.T) {
+	assert.Equal(t, "plain text", StripANSI("plain text"))
+	assert.Equal(t, "error: failed", StripANSI("\x1b[1;31merror:\x1b[0m failed"))
+	assert.Equal(t, "see docs", StripANSI("see \x1b]8;;https://gitea.com\x07docs\x1b]8;;\x07"))
+	assert.Equal(t, "cursor", StripANSI("\x1b[2Kcursor\x1b7"))
+}
+
+func TestChunkLogContent(t *testing.T) {
+	assert.Equal(t, []string{"short"}, ChunkLogContent("short", 10))
+	assert.Equal(t, []string{""}, ChunkLogContent("", 10))
+	assert.Equal(t, []string{"abcd", "efgh", "ij"}, ChunkLogContent("abcdefghij", 4))
+
+	// escape sequences are kept together
+	assert.Equal(t, []string{"ab", "\x1b[31mc", "d"}, ChunkLogContent("ab\x1b[31mcd", 6))
+	// an escape sequence longer than the size is an own chunk
+	assert.Equal(t, []string{"\x1b[31m", "abc"}, ChunkLogContent("\x1b[31mabc", 3))
+
+	// multi-byte characters aren't split
+	assert.Equal(t, []string{"aä", "öü"}, ChunkLogContent("aäöü", 4))
+	assert.Equal(t, []string{"€", "€"}, ChunkLogContent("€€", 4))
+}
@@ -89,7 +89,22 @@ func GetEventsFromContent(content []byte) ([]*jobparser.Event, error) {
 	return events, nil
 }
 
+// repositoriesFilter is the filter of an event listing the other repositories of the same owner which trigger it,
+// besides the repository of the workflow
+const repositoriesFilter = "repositories"
+
+// DetectWorkflows returns the workflows of a commit triggered by an event of their repository
 func DetectWorkflows(commit *git.Commit, triggedEvent webhook_module.HookEventType, payload api.Payloader) (map[string][]byte, error) {
+	return detectWorkflows(commit, "", triggedEvent, payload)
+}
+
+// DetectCrossRepoWorkflows returns the workflows of a commit triggered by an event of sourceRepo, another repository
+// of the same owner, which their events list in their repositories filter
+func DetectCrossRepoWorkflows(commit *git.Commit, sourceRepo string, triggedEvent webhook_module.HookEventType, payload api.Payloader) (map[string][]byte, error) {
+	return detectWorkflows(commit, sourceRepo, triggedEvent, payload)
+}
+
+func detectWorkflows(commit *git.Commit, sourceRepo string, triggedEvent webhook_module.HookEventType, payload api.Payloader) (map[string][]byte, error) {
 	entries, err := ListWorkflows(commit)
 	if err != nil {
 		return nil, err
@@ -107,6 +122,12 @@ func DetectWorkflows(commit *git.Commit, triggedEvent webhook_module.HookEventTy
 			continue
 		}
 		for _, evt := range events {
+			// the repositories filter doesn't depend on the payload, so it's removed before matching the other ones
+			repos := evt.Acts()[repositoriesFilter]
+			delete(evt.Acts(), repositoriesFilter)
+			if sourceRepo != "" && !MatchRepositories(repos, sourceRepo) {
+				continue
+			}
 			log.Trace("detect workflow %q for event %#v matching %q", entry.Name(), evt, triggedEvent)
 			if detectMatched(commit, triggedEvent, payload, evt) {
 				workflows[entry.Name()] = content
@@ -117,6 +138,42 @@ func DetectWorkflows(commit *git.Commit, triggedEvent webhook_module.HookEventTy
 	return workflows, nil
 }
 
+// GetRepositoriesFilters returns the repositories filters of the events of a workflow, by event name
+func GetRepositoriesFilters(content []byte) (map[string][]string, error) {
+	events, err := GetEventsFromContent(content)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make(map[string][]string)
+	for _, evt := range events {
+		if repos, ok := evt.Acts()[repositoriesFilter]; ok && len(repos) > 0 {
+			filters[evt.Name] = repos
+		}
+	}
+	return filters, nil
+}
+
+// MatchRepositories returns whether a repository name matches one of the patterns of a repositories filter
+func MatchRepositories(patterns []string, repoName string) bool {
+	for _, pattern := range patterns {
+		g, err := glob.Compile(strings.ToLower(pattern))
+		if err != nil {
+			log.Warn("ignore invalid repositories pattern %q: %v", pattern, err)
+			continue
+		}
+		if g.Match(strings.ToLower(repoName)) {
+			return true
+		}
+	}
+	return false
+}
+
+// CanEventTrigger returns whether an event can trigger the workflows listening to the event eventName
+func CanEventTrigger(eventName string, triggedEvent webhook_module.HookEventType) bool {
+	return canGithubEventMatch(eventName, triggedEvent)
+}
+
 func detectMatched(commit *git.Commit, triggedEvent webhook_module.HookEventType, payload api.Payloader, evt *jobparser.Event) bool {
 	if !canGithubEventMatch(evt.Name, triggedEvent) {
 		return false
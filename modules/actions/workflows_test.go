@@ -110,3 +110,31 @@ func TestDetectMatched(t *testing.T) {
 		})
 	}
 }
+
+func TestGetRepositoriesFilters(t *testing.T) {
+	filters, err := GetRepositoriesFilters([]byte(`on:
+  push:
+    branches: [main]
+  release:
+    types: [published]
+    repositories: [library, "lib-*"]
+  registry_package:
+    repositories: sdk
+`))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"release":          {"library", "lib-*"},
+		"registry_package": {"sdk"},
+	}, filters)
+
+	filters, err = GetRepositoriesFilters([]byte("on: push"))
+	assert.NoError(t, err)
+	assert.Empty(t, filters)
+}
+
+func TestMatchRepositories(t *testing.T) {
+	assert.True(t, MatchRepositories([]string{"library"}, "library"))
+	assert.True(t, MatchRepositories([]string{"other", "lib-*"}, "Lib-Core"))
+	assert.False(t, MatchRepositories([]string{"lib-*"}, "library"))
+	assert.False(t, MatchRepositories(nil, "library"))
+}
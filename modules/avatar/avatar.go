@@ -46,6 +46,9 @@ func RandomImage(data []byte) (image.Image, error) {
 	return RandomImageSize(DefaultAvatarSize*setting.Avatar.RenderedSizeFactor, data)
 }
 
+// ErrAnimatedAvatar is returned for animated avatars if they are rejected by the policy
+var ErrAnimatedAvatar = errors.New("animated avatars are not allowed")
+
 // processAvatarImage process the avatar image data, crop and resize it if necessary.
 // the returned data could be the original image if no processing is needed.
 func processAvatarImage(data []byte, maxOriginSize int64) ([]byte, error) {
@@ -67,11 +70,49 @@ func processAvatarImage(data []byte, maxOriginSize int64) ([]byte, error) {
 		return nil, fmt.Errorf("image height is too large: %d > %d", imgCfg.Height, setting.Avatar.MaxHeight)
 	}
 
+	targetSize := DefaultAvatarSize * setting.Avatar.RenderedSizeFactor
+
+	// forceProcess is set if the origin must not be used even if it is small enough
+	forceProcess := false
+	animated := isAnimated(data, imgType)
+	if animated {
+		switch setting.Avatar.AnimatedPolicy {
+		case setting.AvatarAnimatedReject:
+			return nil, ErrAnimatedAvatar
+		case setting.AvatarAnimatedStatic:
+			// decoding only keeps the first frame
+			forceProcess = true
+		}
+	}
+
+	orientation := 1
+	if imgType == "jpeg" {
+		orientation = jpegOrientation(data)
+	}
+	if setting.Avatar.StripMetadata {
+		stripped, err := stripMetadata(data, imgType)
+		if err == nil {
+			data = stripped
+		} else {
+			// re-encoding drops all metadata
+			forceProcess = true
+		}
+		// the orientation is lost with the EXIF data, so it has to be applied to the pixels
+		if orientation != 1 {
+			forceProcess = true
+		}
+	}
+
+	// animations are only kept as they are, so they are not normalized
+	if setting.Avatar.NormalizeSize && (!animated || forceProcess) && (imgCfg.Width != targetSize || imgCfg.Height != targetSize) {
+		forceProcess = true
+	}
+
 	// If the origin is small enough, just use it, then APNG could be supported,
 	// otherwise, if the image is processed later, APNG loses animation.
 	// And one more thing, webp is not fully supported, for animated webp, image.DecodeConfig works but Decode fails.
 	// So for animated webp, if the uploaded file is smaller than maxOriginSize, it will be used, if it's larger, there will be an error.
-	if len(data) < int(maxOriginSize) {
+	if !forceProcess && len(data) < int(maxOriginSize) {
 		return data, nil
 	}
 
@@ -79,16 +120,18 @@ func processAvatarImage(data []byte, maxOriginSize int64) ([]byte, error) {
 	if err != nil {
 		return nil, fmt.Errorf("image.Decode: %w", err)
 	}
+	img = applyOrientation(img, orientation)
 
 	// try to crop and resize the origin image if necessary
-	if imgCfg.Width != imgCfg.Height {
+	width, height := img.Bounds().Dx(), img.Bounds().Dy()
+	if width != height {
 		var newSize, ax, ay int
-		if imgCfg.Width > imgCfg.Height {
-			newSize = imgCfg.Height
-			ax = (imgCfg.Width - imgCfg.Height) / 2
+		if width > height {
+			newSize = height
+			ax = (width - height) / 2
 		} else {
-			newSize = imgCfg.Width
-			ay = (imgCfg.Height - imgCfg.Width) / 2
+			newSize = width
+			ay = (height - width) / 2
 		}
 
 		img, err = cutter.Crop(img, cutter.Config{
@@ -101,8 +144,7 @@ func processAvatarImage(data []byte, maxOriginSize int64) ([]byte, error) {
 		}
 	}
 
-	targetSize := uint(DefaultAvatarSize * setting.Avatar.RenderedSizeFactor)
-	img = resize.Resize(targetSize, targetSize, img, resize.Bilinear)
+	img = resize.Resize(uint(targetSize), uint(targetSize), img, resize.Bilinear)
 
 	// try to encode the cropped/resized image to png
 	bs := bytes.Buffer{}
@@ -112,7 +154,7 @@ func processAvatarImage(data []byte, maxOriginSize int64) ([]byte, error) {
 	resized := bs.Bytes()
 
 	// usually the png compression is not good enough, use the original image (no cropping/resizing) if the origin is smaller
-	if len(data) <= len(resized) {
+	if !forceProcess && len(data) <= len(resized) {
 		return data, nil
 	}
 
@@ -27,3 +27,11 @@ func HashAvatar(uniqueID int64, data []byte) string {
 	h.Write(data)
 	return hex.EncodeToString(h.Sum(nil))
 }
+
+// HashAvatarContent returns the SHA256 hash of the stored avatar data.
+// Avatars named after their content are stored once and shared by everyone using the same image,
+// so a stored avatar may only be removed once nothing refers to it anymore.
+func HashAvatarContent(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
@@ -0,0 +1,276 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package avatar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/gif"
+)
+
+var errInvalidImage = errors.New("invalid image data")
+
+// stripMetadata removes EXIF, XMP, IPTC and text metadata from the image without re-encoding it,
+// uploaded photos must not leak camera details or locations.
+// GIF images are returned as they are.
+func stripMetadata(data []byte, imgType string) ([]byte, error) {
+	switch imgType {
+	case "jpeg":
+		return stripJPEGMetadata(data)
+	case "png":
+		return stripPNGMetadata(data)
+	case "webp":
+		return stripWebPMetadata(data)
+	}
+	return data, nil
+}
+
+// jpegSegments calls fn for every marker segment before the image data, fn returns whether to keep the segment.
+// It returns the data of the kept segments followed by the image data.
+func jpegSegments(data []byte, fn func(marker byte, payload []byte) bool) ([]byte, error) {
+	if len(data) < 2 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, errInvalidImage
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:2])
+	pos := 2
+	for pos < len(data) {
+		if data[pos] != 0xFF {
+			return nil, errInvalidImage
+		}
+		// skip fill bytes
+		for pos+1 < len(data) && data[pos+1] == 0xFF {
+			pos++
+		}
+		if pos+1 >= len(data) {
+			return nil, errInvalidImage
+		}
+		marker := data[pos+1]
+		// markers without a payload
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) {
+			out.Write(data[pos : pos+2])
+			pos += 2
+			continue
+		}
+		// start of scan or end of image: the rest is image data
+		if marker == 0xDA || marker == 0xD9 {
+			out.Write(data[pos:])
+			return out.Bytes(), nil
+		}
+		if pos+4 > len(data) {
+			return nil, errInvalidImage
+		}
+		end := pos + 2 + int(binary.BigEndian.Uint16(data[pos+2:pos+4]))
+		if end > len(data) || end < pos+4 {
+			return nil, errInvalidImage
+		}
+		if fn(marker, data[pos+4:end]) {
+			out.Write(data[pos:end])
+		}
+		pos = end
+	}
+	return out.Bytes(), nil
+}
+
+// stripJPEGMetadata removes the APP1 (EXIF, XMP), APP13 (IPTC) and comment segments.
+// The ICC profile in APP2 is kept as it affects the colors.
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	return jpegSegments(data, func(marker byte, _ []byte) bool {
+		return marker != 0xE1 && marker != 0xED && marker != 0xFE
+	})
+}
+
+// jpegOrientation returns the EXIF orientation of a JPEG image, 1 if there is none
+func jpegOrientation(data []byte) int {
+	orientation := 1
+	_, _ = jpegSegments(data, func(marker byte, payload []byte) bool {
+		if marker == 0xE1 && bytes.HasPrefix(payload, []byte("Exif\x00\x00")) {
+			if o := exifOrientation(payload[6:]); o >= 1 && o <= 8 {
+				orientation = o
+			}
+		}
+		return true
+	})
+	return orientation
+}
+
+// exifOrientation reads the orientation tag from the first IFD of TIFF encoded EXIF data
+func exifOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+	ifd := int(order.Uint32(tiff[4:8]))
+	if ifd < 8 || ifd+2 > len(tiff) {
+		return 0
+	}
+	entries := int(order.Uint16(tiff[ifd : ifd+2]))
+	for i := 0; i < entries; i++ {
+		entry := ifd + 2 + i*12
+		if entry+12 > len(tiff) {
+			return 0
+		}
+		if order.Uint16(tiff[entry:entry+2]) == 0x0112 {
+			return int(order.Uint16(tiff[entry+8 : entry+10]))
+		}
+	}
+	return 0
+}
+
+// applyOrientation rotates and flips the image so it is displayed as intended by the EXIF orientation
+func applyOrientation(img image.Image, orientation int) image.Image {
+	if orientation <= 1 || orientation > 8 {
+		return img
+	}
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+	var dst *image.NRGBA
+	if orientation >= 5 {
+		dst = image.NewNRGBA(image.Rect(0, 0, h, w))
+	} else {
+		dst = image.NewNRGBA(image.Rect(0, 0, w, h))
+	}
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // flipped horizontally
+				dx, dy = w-1-x, y
+			case 3: // rotated 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // flipped vertically
+				dx, dy = x, h-1-y
+			case 5: // transposed
+				dx, dy = y, x
+			case 6: // rotated 90 clockwise
+				dx, dy = h-1-y, x
+			case 7: // transversed
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotated 90 counter clockwise
+				dx, dy = y, w-1-x
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+// pngChunks calls fn for every chunk of a PNG image, fn returns whether to keep the chunk
+func pngChunks(data []byte, fn func(chunkType string) bool) ([]byte, error) {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return nil, errInvalidImage
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(pngSignature)
+	pos := len(pngSignature)
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, errInvalidImage
+		}
+		// length, type, data and crc
+		end := pos + 12 + int(binary.BigEndian.Uint32(data[pos:pos+4]))
+		if end > len(data) || end < pos+12 {
+			return nil, errInvalidImage
+		}
+		if fn(string(data[pos+4 : pos+8])) {
+			out.Write(data[pos:end])
+		}
+		pos = end
+	}
+	return out.Bytes(), nil
+}
+
+// stripPNGMetadata removes the EXIF, text and modification time chunks
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	return pngChunks(data, func(chunkType string) bool {
+		switch chunkType {
+		case "eXIf", "tEXt", "zTXt", "iTXt", "tIME":
+			return false
+		}
+		return true
+	})
+}
+
+// webpChunks calls fn for every chunk of a WebP image, fn returns whether to keep the chunk
+func webpChunks(data []byte, fn func(fourCC string, payload []byte) bool) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, errInvalidImage
+	}
+	out := bytes.NewBuffer(make([]byte, 0, len(data)))
+	out.Write(data[:12])
+	pos := 12
+	for pos < len(data) {
+		if pos+8 > len(data) {
+			return nil, errInvalidImage
+		}
+		size := int(binary.LittleEndian.Uint32(data[pos+4 : pos+8]))
+		end := pos + 8 + size + size%2
+		if end > len(data) || end < pos+8 {
+			return nil, errInvalidImage
+		}
+		if fn(string(data[pos:pos+4]), data[pos+8:pos+8+size]) {
+			out.Write(data[pos:end])
+		}
+		pos = end
+	}
+	result := out.Bytes()
+	binary.LittleEndian.PutUint32(result[4:8], uint32(len(result)-8))
+	return result, nil
+}
+
+// stripWebPMetadata removes the EXIF and XMP chunks and clears their flags in the extended header
+func stripWebPMetadata(data []byte) ([]byte, error) {
+	stripped := false
+	result, err := webpChunks(data, func(fourCC string, _ []byte) bool {
+		if fourCC == "EXIF" || fourCC == "XMP " {
+			stripped = true
+			return false
+		}
+		return true
+	})
+	if err != nil || !stripped {
+		return data, err
+	}
+	// the flags are the first byte of the VP8X chunk, which directly follows the header
+	if len(result) > 20 && string(result[12:16]) == "VP8X" {
+		result[20] &^= 0x08 | 0x04
+	}
+	return result, nil
+}
+
+// isAnimated checks if the image has more than one frame
+func isAnimated(data []byte, imgType string) bool {
+	switch imgType {
+	case "gif":
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		return err == nil && len(g.Image) > 1
+	case "png":
+		animated := false
+		_, _ = pngChunks(data, func(chunkType string) bool {
+			animated = animated || chunkType == "acTL"
+			return true
+		})
+		return animated
+	case "webp":
+		animated := false
+		_, _ = webpChunks(data, func(fourCC string, payload []byte) bool {
+			animated = animated || fourCC == "ANIM" || (fourCC == "VP8X" && len(payload) > 0 && payload[0]&0x02 != 0)
+			return true
+		})
+		return animated
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package avatar
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestImage(width, height int) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.NRGBA{R: uint8(x * 100), G: uint8(y * 100), A: 255})
+		}
+	}
+	return img
+}
+
+// exifSegment returns an APP1 segment with the given orientation in little endian TIFF format
+func exifSegment(orientation uint16) []byte {
+	tiff := []byte("II*\x00\x08\x00\x00\x00")
+	tiff = binary.LittleEndian.AppendUint16(tiff, 1)
+	entry := make([]byte, 12)
+	binary.LittleEndian.PutUint16(entry[0:], 0x0112)
+	binary.LittleEndian.PutUint16(entry[2:], 3)
+	binary.LittleEndian.PutUint32(entry[4:], 1)
+	binary.LittleEndian.PutUint16(entry[8:], orientation)
+	tiff = append(tiff, entry...)
+	tiff = append(tiff, 0, 0, 0, 0)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	segment := []byte{0xFF, 0xE1}
+	segment = binary.BigEndian.AppendUint16(segment, uint16(len(payload)+2))
+	return append(segment, payload...)
+}
+
+func TestStripJPEGMetadata(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, jpeg.Encode(buf, newTestImage(2, 1), nil))
+	origin := buf.Bytes()
+
+	withExif := append([]byte{}, origin[:2]...)
+	withExif = append(withExif, exifSegment(6)...)
+	withExif = append(withExif, origin[2:]...)
+
+	assert.Equal(t, 6, jpegOrientation(withExif))
+	assert.Equal(t, 1, jpegOrientation(origin))
+
+	stripped, err := stripJPEGMetadata(withExif)
+	assert.NoError(t, err)
+	assert.Equal(t, origin, stripped)
+}
+
+func TestStripPNGMetadata(t *testing.T) {
+	buf := &bytes.Buffer{}
+	assert.NoError(t, png.Encode(buf, newTestImage(2, 2)))
+	origin := buf.Bytes()
+
+	// signature and IHDR chunk
+	ihdrEnd := len(pngSignature) + 12 + 13
+	text := []byte("Comment\x00secret location")
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(text)))
+	chunk = append(chunk, "tEXt"...)
+	chunk = append(chunk, text...)
+	chunk = append(chunk, 0, 0, 0, 0)
+
+	withText := append([]byte{}, origin[:ihdrEnd]...)
+	withText = append(withText, chunk...)
+	withText = append(withText, origin[ihdrEnd:]...)
+
+	stripped, err := stripPNGMetadata(withText)
+	assert.NoError(t, err)
+	assert.Equal(t, origin, stripped)
+}
+
+func TestApplyOrientation(t *testing.T) {
+	img := newTestImage(2, 1)
+
+	rotated := applyOrientation(img, 6)
+	assert.Equal(t, image.Rect(0, 0, 1, 2), rotated.Bounds())
+	assert.Equal(t, img.At(0, 0), rotated.At(0, 0))
+	assert.Equal(t, img.At(1, 0), rotated.At(0, 1))
+
+	flipped := applyOrientation(img, 2)
+	assert.Equal(t, img.At(1, 0), flipped.At(0, 0))
+
+	assert.Equal(t, image.Image(img), applyOrientation(img, 1))
+}
+
+func TestProcessAvatarAnimated(t *testing.T) {
+	defer func(policy string) { setting.Avatar.AnimatedPolicy = policy }(setting.Avatar.AnimatedPolicy)
+	setting.Avatar.MaxWidth = 4096
+	setting.Avatar.MaxHeight = 4096
+
+	palette := color.Palette{color.Black, color.White}
+	anim := &gif.GIF{
+		Image: []*image.Paletted{image.NewPaletted(image.Rect(0, 0, 4, 4), palette), image.NewPaletted(image.Rect(0, 0, 4, 4), palette)},
+		Delay: []int{10, 10},
+	}
+	buf := &bytes.Buffer{}
+	assert.NoError(t, gif.EncodeAll(buf, anim))
+	origin := buf.Bytes()
+	assert.True(t, isAnimated(origin, "gif"))
+
+	setting.Avatar.AnimatedPolicy = setting.AvatarAnimatedAllow
+	result, err := processAvatarImage(origin, 262144)
+	assert.NoError(t, err)
+	assert.Equal(t, origin, result)
+
+	setting.Avatar.AnimatedPolicy = setting.AvatarAnimatedReject
+	_, err = processAvatarImage(origin, 262144)
+	assert.ErrorIs(t, err, ErrAnimatedAvatar)
+
+	setting.Avatar.AnimatedPolicy = setting.AvatarAnimatedStatic
+	result, err = processAvatarImage(origin, 262144)
+	assert.NoError(t, err)
+	_, imgType, err := image.DecodeConfig(bytes.NewReader(result))
+	assert.NoError(t, err)
+	assert.Equal(t, "png", imgType)
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package badge
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBadgeSVG(t *testing.T) {
+	b := &Badge{Label: "build", Message: "passing", Color: ColorBrightGreen}
+	svg := string(b.SVG())
+	assert.Contains(t, svg, `width="108"`)
+	assert.Contains(t, svg, `<title>build: passing</title>`)
+	assert.Contains(t, svg, `fill="#4c1"`)
+
+	b = &Badge{Label: "release", Message: `<script>"`, Color: ColorBlue}
+	svg = string(b.SVG())
+	assert.NotContains(t, svg, "<script>")
+	assert.Contains(t, svg, "&lt;script&gt;&#34;")
+}
+
+func TestCoverageColor(t *testing.T) {
+	assert.Equal(t, ColorBrightGreen, CoverageColor(100))
+	assert.Equal(t, ColorGreen, CoverageColor(80))
+	assert.Equal(t, ColorYellow, CoverageColor(60))
+	assert.Equal(t, ColorOrange, CoverageColor(59.9))
+	assert.Equal(t, ColorRed, CoverageColor(0))
+}
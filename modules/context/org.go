@@ -7,8 +7,10 @@ package context
 import (
 	"strings"
 
+	"code.gitea.io/gitea/models/avatars"
 	"code.gitea.io/gitea/models/organization"
 	"code.gitea.io/gitea/models/perm"
+	system_model "code.gitea.io/gitea/models/system"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/setting"
@@ -60,6 +62,47 @@ func GetOrganizationByParams(ctx *Context) {
 	}
 }
 
+// applyAvatarPolicy makes the templates render all users with the default avatar
+// on the pages of an organization which enforces it
+func applyAvatarPolicy(ctx *Context, owner *user_model.User) {
+	if !owner.IsOrganization() {
+		return
+	}
+	policy, err := organization.GetAvatarPolicy(ctx, owner.ID)
+	if err != nil {
+		ctx.ServerError("GetAvatarPolicy", err)
+		return
+	}
+	if policy != nil && policy.EnforceDefault {
+		ctx.Req = ctx.Req.WithContext(avatars.WithDefaultAvatarsEnforced(ctx.Req.Context()))
+	}
+}
+
+// applyBranding makes the templates render the pages of an organization with its theme and accent color.
+// Themes which are not approved for branding anymore are ignored.
+func applyBranding(ctx *Context, owner *user_model.User) {
+	if !owner.IsOrganization() {
+		return
+	}
+	branding, err := organization.GetBranding(ctx, owner.ID)
+	if err != nil {
+		ctx.ServerError("GetBranding", err)
+		return
+	} else if branding == nil {
+		return
+	}
+	if err := branding.LoadTheme(ctx); err != nil && !system_model.IsErrThemeNotExist(err) {
+		ctx.ServerError("LoadTheme", err)
+		return
+	}
+	if branding.Theme != nil && !branding.Theme.OrgBranding {
+		branding.Theme = nil
+	}
+	if branding.Theme != nil || branding.AccentColor != "" {
+		ctx.Data["OrgBranding"] = branding
+	}
+}
+
 // HandleOrgAssignment handles organization assignment
 func HandleOrgAssignment(ctx *Context, args ...bool) {
 	var (
@@ -119,6 +162,15 @@ func HandleOrgAssignment(ctx *Context, args ...bool) {
 	ctx.ContextUser = org.AsUser()
 	ctx.Data["Org"] = org
 
+	applyAvatarPolicy(ctx, ctx.ContextUser)
+	if ctx.Written() {
+		return
+	}
+	applyBranding(ctx, ctx.ContextUser)
+	if ctx.Written() {
+		return
+	}
+
 	// Admin has super access.
 	if ctx.IsSigned && ctx.Doer.IsAdmin {
 		ctx.Org.IsOwner = true
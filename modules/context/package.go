@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"net/http"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	"code.gitea.io/gitea/models/organization"
 	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/perm"
@@ -81,6 +82,12 @@ func packageAssignment(ctx *Context, errCb func(int, string, interface{})) {
 			errCb(http.StatusInternalServerError, "GetPackageDescriptor", err)
 			return
 		}
+
+		// packages linked to an internal repository are only visible for signed-in users
+		if ctx.Doer == nil && ctx.Package.Descriptor.Repository != nil && ctx.Package.Descriptor.Repository.IsInternal {
+			errCb(http.StatusNotFound, "GetPackageDescriptor", packages_model.ErrPackageNotExist)
+			return
+		}
 	}
 }
 
@@ -93,7 +100,10 @@ func determineAccessMode(ctx *Context) (perm.AccessMode, error) {
 		return perm.AccessModeNone, nil
 	}
 
-	// TODO: ActionUser permission check
+	if ctx.Doer.IsActions() {
+		return determineActionsAccessMode(ctx)
+	}
+
 	accessMode := perm.AccessModeNone
 	if ctx.Package.Owner.IsOrganization() {
 		org := organization.OrgFromUser(ctx.Package.Owner)
@@ -130,6 +140,24 @@ func determineAccessMode(ctx *Context) (perm.AccessMode, error) {
 	return accessMode, nil
 }
 
+// determineActionsAccessMode returns the access mode of the token of an Actions task,
+// which can access the packages of the owner of its repository and read public packages
+func determineActionsAccessMode(ctx *Context) (perm.AccessMode, error) {
+	if ctx.Data["IsActionsToken"] != true {
+		return perm.AccessModeNone, nil
+	}
+	task, err := actions_model.GetTaskByID(ctx, ctx.Data["ActionsTaskID"].(int64))
+	if err != nil {
+		return perm.AccessModeNone, err
+	}
+
+	accessMode := task.PackageAccessMode(ctx.Package.Owner.ID)
+	if accessMode == perm.AccessModeNone && ctx.Package.Owner.Visibility == structs.VisibleTypePublic {
+		accessMode = perm.AccessModeRead
+	}
+	return accessMode, nil
+}
+
 // PackageContexter initializes a package context for a request.
 func PackageContexter(ctx gocontext.Context) func(next http.Handler) http.Handler {
 	rnd := templates.HTMLRenderer()
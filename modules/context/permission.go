@@ -111,6 +111,17 @@ func RequireRepoReaderOr(unitTypes ...unit.Type) func(ctx *Context) {
 	}
 }
 
+// RequireRepoCodeUnrestricted returns a middleware for requiring read access to all paths of the code,
+// for pages which can not be limited to the code paths the user may read
+func RequireRepoCodeUnrestricted() func(ctx *Context) {
+	return func(ctx *Context) {
+		if ctx.Repo.Permission.IsCodePathRestricted() {
+			ctx.NotFound(ctx.Req.URL.RequestURI(), nil)
+			return
+		}
+	}
+}
+
 // RequireRepoScopedToken check whether personal access token has repo scope
 func CheckRepoScopedToken(ctx *Context, repo *repo_model.Repository) {
 	if !ctx.IsBasicAuth || ctx.Data["IsApiToken"] != true {
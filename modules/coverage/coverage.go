@@ -0,0 +1,190 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/xml"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/util"
+)
+
+// The formats of the coverage reports which can be parsed
+const (
+	FormatLcov      = "lcov"
+	FormatCobertura = "cobertura"
+)
+
+// File is the coverage of the lines of a file
+type File struct {
+	Path string
+	// Hits are the number of executions of the instrumented lines by line number
+	Hits map[int]int64
+}
+
+// Lines returns the sorted numbers of the covered and of the uncovered lines
+func (f *File) Lines() (covered, uncovered []int) {
+	for line, hits := range f.Hits {
+		if hits > 0 {
+			covered = append(covered, line)
+		} else {
+			uncovered = append(uncovered, line)
+		}
+	}
+	sort.Ints(covered)
+	sort.Ints(uncovered)
+	return covered, uncovered
+}
+
+// Report is a parsed coverage report
+type Report struct {
+	// Percentage of covered lines as reported by the tool
+	Percentage float64
+	Files      []*File
+}
+
+// report collects the files of a report, files listed several times are merged
+type report struct {
+	files  map[string]*File
+	sorted []*File
+	prefix string
+}
+
+func (r *report) file(name string) *File {
+	name = strings.TrimPrefix(name, r.prefix)
+	name = strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, "\\", "/")), "/")
+	f, ok := r.files[name]
+	if !ok {
+		f = &File{Path: name, Hits: make(map[int]int64)}
+		r.files[name] = f
+		r.sorted = append(r.sorted, f)
+	}
+	return f
+}
+
+func (f *File) add(line int, hits int64) {
+	if line > 0 {
+		f.Hits[line] += hits
+	}
+}
+
+// Parse parses a coverage report. The paths of the files are made relative to the root of the repository by removing
+// the prefix, e.g. the directory the CI system checked out the repository into.
+func Parse(format string, content []byte, prefix string) (*Report, error) {
+	r := &report{files: make(map[string]*File), prefix: prefix}
+	var percentage float64
+	var err error
+	switch format {
+	case FormatLcov:
+		percentage, err = parseLcov(r, content)
+	case FormatCobertura:
+		percentage, err = parseCobertura(r, content)
+	default:
+		return nil, util.NewInvalidArgumentErrorf("unsupported coverage report format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &Report{Percentage: percentage, Files: r.sorted}, nil
+}
+
+// parseLcov reads the hits of the lines (DA) of the files (SF) of a lcov tracefile. The percentage is computed from
+// the found (LF) and hit (LH) lines, or from the lines if the tracefile has no summary.
+func parseLcov(r *report, content []byte) (float64, error) {
+	var found, hit, instrumented, covered int64
+	var current *File
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(strings.TrimSpace(scanner.Text()), ":")
+		if !ok {
+			if key == "end_of_record" {
+				current = nil
+			}
+			continue
+		}
+		switch key {
+		case "SF":
+			current = r.file(value)
+		case "DA":
+			fields := strings.Split(value, ",")
+			if len(fields) < 2 {
+				return 0, util.NewInvalidArgumentErrorf("invalid lcov line DA:%s", value)
+			}
+			line, err1 := strconv.Atoi(fields[0])
+			hits, err2 := strconv.ParseInt(fields[1], 10, 64)
+			if err1 != nil || err2 != nil || hits < 0 {
+				return 0, util.NewInvalidArgumentErrorf("invalid lcov line DA:%s", value)
+			}
+			instrumented++
+			if hits > 0 {
+				covered++
+			}
+			if current != nil {
+				current.add(line, hits)
+			}
+		case "LF", "LH":
+			n, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || n < 0 {
+				return 0, util.NewInvalidArgumentErrorf("invalid lcov line %s:%s", key, value)
+			}
+			if key == "LF" {
+				found += n
+			} else {
+				hit += n
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return 0, util.NewInvalidArgumentErrorf("unable to read lcov report: %v", err)
+	}
+	if found == 0 {
+		found, hit = instrumented, covered
+	}
+	if found == 0 {
+		return 0, util.NewInvalidArgumentErrorf("the lcov report has no lines")
+	}
+	if hit > found {
+		return 0, util.NewInvalidArgumentErrorf("the lcov report has more hit than found lines")
+	}
+	return float64(hit) * 100 / float64(found), nil
+}
+
+// parseCobertura reads the hits of the lines of the classes of a Cobertura report, the percentage is the line-rate
+// of its root element
+func parseCobertura(r *report, content []byte) (float64, error) {
+	var doc struct {
+		XMLName  xml.Name `xml:"coverage"`
+		LineRate string   `xml:"line-rate,attr"`
+		Classes  []struct {
+			Filename string `xml:"filename,attr"`
+			Lines    []struct {
+				Number int   `xml:"number,attr"`
+				Hits   int64 `xml:"hits,attr"`
+			} `xml:"lines>line"`
+		} `xml:"packages>package>classes>class"`
+	}
+	if err := xml.Unmarshal(content, &doc); err != nil {
+		return 0, util.NewInvalidArgumentErrorf("invalid cobertura report: %v", err)
+	}
+	rate, err := strconv.ParseFloat(doc.LineRate, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return 0, util.NewInvalidArgumentErrorf("invalid line-rate %q of the cobertura report", doc.LineRate)
+	}
+	for _, class := range doc.Classes {
+		if class.Filename == "" {
+			continue
+		}
+		f := r.file(class.Filename)
+		for _, line := range class.Lines {
+			f.add(line.Number, line.Hits)
+		}
+	}
+	return rate * 100, nil
+}
@@ -0,0 +1,93 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseLcov(t *testing.T) {
+	lcov := `TN:
+SF:/builds/project/main.go
+DA:1,1
+DA:2,0
+LF:10
+LH:8
+end_of_record
+SF:/builds/project/pkg/util.go
+DA:3,4
+DA:5,0
+LF:30
+LH:22
+end_of_record
+`
+	report, err := Parse(FormatLcov, []byte(lcov), "/builds/project/")
+	assert.NoError(t, err)
+	assert.InDelta(t, 75, report.Percentage, 0.001)
+	if assert.Len(t, report.Files, 2) {
+		assert.Equal(t, "main.go", report.Files[0].Path)
+		assert.Equal(t, "pkg/util.go", report.Files[1].Path)
+		covered, uncovered := report.Files[1].Lines()
+		assert.Equal(t, []int{3}, covered)
+		assert.Equal(t, []int{5}, uncovered)
+	}
+
+	// without a summary the percentage is computed from the lines
+	report, err = Parse(FormatLcov, []byte("SF:main.go\nDA:1,1\nDA:2,0\nDA:3,2\nDA:4,0\nend_of_record\n"), "")
+	assert.NoError(t, err)
+	assert.InDelta(t, 50, report.Percentage, 0.001)
+}
+
+func TestParseCobertura(t *testing.T) {
+	cobertura := `<?xml version="1.0" ?>
+<coverage line-rate="0.8125" branch-rate="0.5" version="1.9">
+	<packages>
+		<package name="pkg">
+			<classes>
+				<class name="A" filename="./pkg/a.py">
+					<lines>
+						<line number="1" hits="1"/>
+						<line number="2" hits="0"/>
+					</lines>
+				</class>
+				<class name="B" filename="pkg/a.py">
+					<lines>
+						<line number="2" hits="3"/>
+					</lines>
+				</class>
+			</classes>
+		</package>
+	</packages>
+</coverage>`
+	report, err := Parse(FormatCobertura, []byte(cobertura), "")
+	assert.NoError(t, err)
+	assert.InDelta(t, 81.25, report.Percentage, 0.001)
+	if assert.Len(t, report.Files, 1) {
+		assert.Equal(t, "pkg/a.py", report.Files[0].Path)
+		covered, uncovered := report.Files[0].Lines()
+		assert.Equal(t, []int{1, 2}, covered)
+		assert.Empty(t, uncovered)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, c := range []struct {
+		format, content string
+	}{
+		{FormatLcov, "TN:\nend_of_record\n"},
+		{FormatLcov, "LF:1\nLH:2\n"},
+		{FormatLcov, "LF:x\n"},
+		{FormatLcov, "SF:main.go\nDA:1\n"},
+		{FormatCobertura, `<coverage line-rate="1.5"/>`},
+		{FormatCobertura, `<report line-rate="0.5"/>`},
+		{"jacoco", "<report/>"},
+	} {
+		_, err := Parse(c.format, []byte(c.content), "")
+		assert.ErrorIs(t, err, util.ErrInvalidArgument, "%s: %s", c.format, c.content)
+	}
+}
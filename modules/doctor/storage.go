@@ -8,12 +8,14 @@ import (
 	"errors"
 	"io/fs"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models/git"
 	"code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
 	packages_module "code.gitea.io/gitea/modules/packages"
 	"code.gitea.io/gitea/modules/setting"
@@ -102,6 +104,11 @@ func checkStorage(opts *checkStorageOptions) func(ctx context.Context, logger lo
 				&commonStorageCheckOptions{
 					storer: storage.Attachments,
 					isOrphaned: func(path string, obj storage.Object, stat fs.FileInfo) (bool, error) {
+						// the files of attachment blobs are named after their content
+						if strings.HasPrefix(path, "cas/") || strings.HasPrefix(path, "thumbnails/cas/") {
+							exists, err := repo.ExistAttachmentBlobWithSHA(ctx, stat.Name())
+							return !exists, err
+						}
 						exists, err := repo.ExistAttachmentsByUUID(ctx, stat.Name())
 						return !exists, err
 					},
@@ -120,6 +127,10 @@ func checkStorage(opts *checkStorageOptions) func(ctx context.Context, logger lo
 				&commonStorageCheckOptions{
 					storer: storage.LFS,
 					isOrphaned: func(path string, obj storage.Object, stat fs.FileInfo) (bool, error) {
+						// uploads of clients are staged until they are verified, only abandoned ones are orphaned
+						if strings.HasPrefix(path, lfs.DirectUploadDir+"/") {
+							return time.Since(stat.ModTime()) > setting.LFS.HTTPAuthExpiry, nil
+						}
 						// The oid of an LFS stored object is the name but with all the path.Separators removed
 						oid := strings.ReplaceAll(path, "/", "")
 						exists, err := git.ExistsLFSObject(ctx, oid)
@@ -0,0 +1,144 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package doctor
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"os"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+func isNotExist(err error) bool {
+	return errors.Is(err, os.ErrNotExist) || errors.Is(err, util.ErrNotExist)
+}
+
+// checkAttachmentBlobs verifies that the stored file of every attachment blob still has the content
+// the blob is named after. Missing thumbnails can be fixed, missing or corrupted files are reported only.
+func checkAttachmentBlobs(ctx context.Context, logger log.Logger, autofix bool) error {
+	totalCount, missingCount, corruptedCount, thumbnailCount := 0, 0, 0, 0
+	if err := db.Iterate(ctx, nil, func(ctx context.Context, blob *repo.AttachmentBlob) error {
+		totalCount++
+		attachmentStorage := storage.AttachmentsOfRegion(blob.Region)
+
+		f, err := attachmentStorage.Open(blob.RelativePath())
+		if err != nil {
+			if isNotExist(err) {
+				missingCount++
+				logger.Warn("Attachment blob %s in storage region %q is missing", blob.HashSHA256, blob.Region)
+				return nil
+			}
+			return err
+		}
+		hash := sha256.New()
+		size, err := io.Copy(hash, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		if hex.EncodeToString(hash.Sum(nil)) != blob.HashSHA256 || size != blob.Size {
+			corruptedCount++
+			logger.Warn("Attachment blob %s in storage region %q does not match its hash or size", blob.HashSHA256, blob.Region)
+		}
+
+		if blob.HasThumbnail {
+			if _, err := attachmentStorage.Stat(repo.AttachmentBlobThumbnailRelativePath(blob.HashSHA256)); err != nil {
+				if !isNotExist(err) {
+					return err
+				}
+				thumbnailCount++
+				if autofix {
+					return repo.ClearAttachmentBlobThumbnail(ctx, blob)
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		logger.Error("Error whilst checking the attachment blobs: %v", err)
+		return err
+	}
+
+	if thumbnailCount > 0 {
+		if autofix {
+			logger.Info("Removed %d missing thumbnail(s) from their attachment blobs", thumbnailCount)
+		} else {
+			logger.Warn("Found %d attachment blob(s) with a missing thumbnail", thumbnailCount)
+		}
+	}
+	if missingCount > 0 || corruptedCount > 0 {
+		logger.Critical("Found %d missing and %d corrupted attachment blob(s) of %d", missingCount, corruptedCount, totalCount)
+		return nil
+	}
+	logger.Info("Verified %d attachment blob(s)", totalCount)
+	return nil
+}
+
+// checkAvatarsExist reports the avatars of users and repositories which are missing from the storage
+func checkAvatarsExist(ctx context.Context, logger log.Logger) error {
+	missingCount := 0
+	if err := db.Iterate(ctx, builder.Neq{"avatar": ""}, func(ctx context.Context, u *user.User) error {
+		if _, err := storage.Avatars.Stat(u.CustomAvatarRelativePath()); err != nil {
+			if !isNotExist(err) {
+				return err
+			}
+			missingCount++
+			logger.Warn("Avatar %s of user %s is missing", u.Avatar, u.Name)
+		}
+		return nil
+	}); err != nil {
+		logger.Error("Error whilst checking the avatars: %v", err)
+		return err
+	}
+	if err := db.Iterate(ctx, builder.Neq{"avatar": ""}, func(ctx context.Context, r *repo.Repository) error {
+		if _, err := storage.RepoAvatars.Stat(r.CustomAvatarRelativePath()); err != nil {
+			if !isNotExist(err) {
+				return err
+			}
+			missingCount++
+			logger.Warn("Avatar %s of repository %d is missing", r.Avatar, r.ID)
+		}
+		return nil
+	}); err != nil {
+		logger.Error("Error whilst checking the repository avatars: %v", err)
+		return err
+	}
+	if missingCount > 0 {
+		logger.Warn("Found %d missing avatar(s)", missingCount)
+	}
+	return nil
+}
+
+func checkStorageIntegrity(ctx context.Context, logger log.Logger, autofix bool) error {
+	if err := storage.Init(); err != nil {
+		logger.Error("storage.Init failed: %v", err)
+		return err
+	}
+	if err := checkAttachmentBlobs(ctx, logger, autofix); err != nil {
+		return err
+	}
+	return checkAvatarsExist(ctx, logger)
+}
+
+func init() {
+	Register(&Check{
+		Title:                      "Check the integrity of the attachment blobs and avatars in storage",
+		Name:                       "storage-integrity",
+		IsDefault:                  false,
+		Run:                        checkStorageIntegrity,
+		AbortIfFailed:              false,
+		SkipDatabaseInitialization: false,
+		Priority:                   1,
+	})
+}
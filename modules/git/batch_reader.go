@@ -249,26 +249,26 @@ headerLoop:
 }
 
 // git tree files are a list:
-// <mode-in-ascii> SP <fname> NUL <20-byte SHA>
+// <mode-in-ascii> SP <fname> NUL <binary SHA>
 //
-// Unfortunately this 20-byte notation is somewhat in conflict to all other git tools
-// Therefore we need some method to convert these 20-byte SHAs to a 40-byte SHA
+// Unfortunately this binary notation is somewhat in conflict to all other git tools
+// Therefore we need some method to convert these binary SHAs to hexadecimal SHAs
 
-// constant hextable to help quickly convert between 20byte and 40byte hashes
+// constant hextable to help quickly convert between binary and hexadecimal hashes
 const hextable = "0123456789abcdef"
 
-// To40ByteSHA converts a 20-byte SHA into a 40-byte sha. Input and output can be the
-// same 40 byte slice to support in place conversion without allocations.
+// ToHexSHA converts a binary SHA into a hexadecimal sha. Input and output can be the
+// same slice to support in place conversion without allocations.
 // This is at least 100x quicker that hex.EncodeToString
-// NB This requires that out is a 40-byte slice
-func To40ByteSHA(sha, out []byte) []byte {
-	for i := 19; i >= 0; i-- {
+// NB This requires that out is twice as long as sha
+func ToHexSHA(sha, out []byte) []byte {
+	for i := len(sha) - 1; i >= 0; i-- {
 		v := sha[i]
 		vhi, vlo := v>>4, v&0x0f
 		shi, slo := hextable[vhi], hextable[vlo]
 		out[i*2], out[i*2+1] = shi, slo
 	}
-	return out
+	return out[:len(sha)*2]
 }
 
 // ParseTreeLine reads an entry from a tree in a cat-file --batch stream
@@ -276,10 +276,11 @@ func To40ByteSHA(sha, out []byte) []byte {
 // It is recommended therefore to pass in an fnameBuf large enough to avoid almost all allocations
 //
 // Each line is composed of:
-// <mode-in-ascii-dropping-initial-zeros> SP <fname> NUL <20-byte SHA>
+// <mode-in-ascii-dropping-initial-zeros> SP <fname> NUL <binary SHA>
 //
-// We don't attempt to convert the 20-byte SHA to 40-byte SHA to save a lot of time
-func ParseTreeLine(rd *bufio.Reader, modeBuf, fnameBuf, shaBuf []byte) (mode, fname, sha []byte, n int, err error) {
+// The binary SHA is 20 bytes long for SHA-1 and 32 bytes long for SHA-256 repositories.
+// We don't attempt to convert the binary SHA to hexadecimal SHA to save a lot of time
+func ParseTreeLine(objectFormat ObjectFormat, rd *bufio.Reader, modeBuf, fnameBuf, shaBuf []byte) (mode, fname, sha []byte, n int, err error) {
 	var readBytes []byte
 
 	// Read the Mode & fname
@@ -324,11 +325,16 @@ func ParseTreeLine(rd *bufio.Reader, modeBuf, fnameBuf, shaBuf []byte) (mode, fn
 	fnameBuf = fnameBuf[:len(fnameBuf)-1]
 	fname = fnameBuf
 
-	// Deal with the 20-byte SHA
+	// Deal with the binary SHA
+	size := objectFormat.Size()
+	if cap(shaBuf) < size {
+		shaBuf = make([]byte, size)
+	}
+	shaBuf = shaBuf[:size]
 	idx = 0
-	for idx < 20 {
+	for idx < size {
 		var read int
-		read, err = rd.Read(shaBuf[idx:20])
+		read, err = rd.Read(shaBuf[idx:size])
 		n += read
 		if err != nil {
 			return
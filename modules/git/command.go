@@ -223,6 +223,9 @@ type RunOpts struct {
 	Stdout, Stderr io.Writer
 	Stdin          io.Reader
 	PipelineFunc   func(context.Context, context.CancelFunc) error
+
+	// Schedule makes the command wait for a slot of the git scheduler before it is started
+	Schedule *SchedulerKey
 }
 
 func commonBaseEnvs() []string {
@@ -301,6 +304,16 @@ func (c *Command) Run(opts *RunOpts) error {
 	}
 	defer finished()
 
+	if opts.Schedule != nil {
+		if s := getScheduler(); s != nil {
+			release, err := s.Acquire(ctx, *opts.Schedule)
+			if err != nil {
+				return err
+			}
+			defer release()
+		}
+	}
+
 	cmd := exec.CommandContext(ctx, c.prog, c.args...)
 	if opts.Env == nil {
 		cmd.Env = os.Environ()
@@ -232,7 +232,7 @@ func (c *Commit) HasPreviousCommit(commitHash SHA1) (bool, error) {
 
 // IsForcePush returns true if a push from oldCommitHash to this is a force push
 func (c *Commit) IsForcePush(oldCommitID string) (bool, error) {
-	if oldCommitID == EmptySHA {
+	if IsEmptyCommitID(oldCommitID) {
 		return false, nil
 	}
 	oldCommit, err := c.repo.GetCommit(oldCommitID)
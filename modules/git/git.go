@@ -36,6 +36,9 @@ var (
 	// SupportProcReceive version >= 2.29.0
 	SupportProcReceive bool
 
+	// SupportSHA256 version >= 2.42.0, when git declared the SHA-256 object format stable, and not built with go-git
+	SupportSHA256 bool
+
 	gitVersion *version.Version
 )
 
@@ -190,6 +193,7 @@ func InitFull(ctx context.Context) (err error) {
 	}
 
 	SupportProcReceive = CheckGitVersionAtLeast("2.29") == nil
+	SupportSHA256 = supportSHA256Build && CheckGitVersionAtLeast("2.42") == nil
 
 	if setting.LFS.StartServer {
 		if CheckGitVersionAtLeast("2.1.2") != nil {
@@ -0,0 +1,157 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ObjectFormat is the hash algorithm used for the object IDs of a repository
+type ObjectFormat string
+
+const (
+	// ObjectFormatSHA1 is the default object format of git repositories
+	ObjectFormatSHA1 ObjectFormat = "sha1"
+	// ObjectFormatSHA256 is the object format of repositories created with git init --object-format=sha256
+	ObjectFormatSHA256 ObjectFormat = "sha256"
+)
+
+// SHA256FullLength is the full length of a git SHA-256 object ID
+const SHA256FullLength = 64
+
+// EmptySHA256 defines the empty git SHA-256 object ID
+const EmptySHA256 = "0000000000000000000000000000000000000000000000000000000000000000"
+
+// EmptyTreeSHA256 is the SHA-256 object ID of an empty tree
+const EmptyTreeSHA256 = "6ef19b41225c5369f1c104d45d8d85efa9b057b53b14b4b9b939dd74decc5321"
+
+// ErrObjectFormatNotSupported represents an error when a repository with an unsupported object format should be created
+type ErrObjectFormatNotSupported struct {
+	ObjectFormat string
+}
+
+// IsErrObjectFormatNotSupported checks if an error is an ErrObjectFormatNotSupported
+func IsErrObjectFormatNotSupported(err error) bool {
+	_, ok := err.(ErrObjectFormatNotSupported)
+	return ok
+}
+
+func (err ErrObjectFormatNotSupported) Error() string {
+	return fmt.Sprintf("object format is not supported [format: %s]", err.ObjectFormat)
+}
+
+// ParseObjectFormat returns the object format with the name, an empty name is the default SHA-1 format
+func ParseObjectFormat(name string) (ObjectFormat, error) {
+	switch ObjectFormat(strings.ToLower(name)) {
+	case "", ObjectFormatSHA1:
+		return ObjectFormatSHA1, nil
+	case ObjectFormatSHA256:
+		return ObjectFormatSHA256, nil
+	}
+	return "", fmt.Errorf("unknown object format %q", name)
+}
+
+// String returns the name of the object format
+func (f ObjectFormat) String() string {
+	if f == "" {
+		return string(ObjectFormatSHA1)
+	}
+	return string(f)
+}
+
+// Size returns the length of the binary object IDs in bytes
+func (f ObjectFormat) Size() int {
+	if f == ObjectFormatSHA256 {
+		return 32
+	}
+	return 20
+}
+
+// FullLength returns the length of the hexadecimal object IDs
+func (f ObjectFormat) FullLength() int {
+	return f.Size() * 2
+}
+
+// EmptyObjectID returns the object ID which git uses for a missing old or new value of a ref
+func (f ObjectFormat) EmptyObjectID() string {
+	if f == ObjectFormatSHA256 {
+		return EmptySHA256
+	}
+	return EmptySHA
+}
+
+// EmptyTree returns the object ID of an empty tree
+func (f ObjectFormat) EmptyTree() string {
+	if f == ObjectFormatSHA256 {
+		return EmptyTreeSHA256
+	}
+	return EmptyTreeSHA
+}
+
+// IsSupported returns whether repositories with the object format can be created and hosted
+func (f ObjectFormat) IsSupported() bool {
+	return f == ObjectFormatSHA1 || (f == ObjectFormatSHA256 && SupportSHA256)
+}
+
+// SupportedObjectFormats returns the object formats of the repositories which can be created
+func SupportedObjectFormats() []ObjectFormat {
+	if SupportSHA256 {
+		return []ObjectFormat{ObjectFormatSHA1, ObjectFormatSHA256}
+	}
+	return []ObjectFormat{ObjectFormatSHA1}
+}
+
+// IsEmptyCommitID checks if the commit ID only consists of zeros, which git uses
+// for the old value of a created ref or the new value of a deleted ref.
+func IsEmptyCommitID(commitID string) bool {
+	return commitID == EmptySHA || commitID == EmptySHA256
+}
+
+// IsFullSHA checks if the string is a full SHA-1 or SHA-256 object ID
+func IsFullSHA(sha string) bool {
+	return (len(sha) == SHAFullLength || len(sha) == SHA256FullLength) && IsValidSHAPattern(sha)
+}
+
+// GetObjectFormatOfRepo reads the object format from the config of the repository at repoPath.
+// It works for bare repositories and work trees, and doesn't need to run git.
+func GetObjectFormatOfRepo(repoPath string) (ObjectFormat, error) {
+	configPath := filepath.Join(repoPath, "config")
+	if !isFile(configPath) {
+		configPath = filepath.Join(repoPath, ".git", "config")
+	}
+	f, err := os.Open(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ObjectFormatSHA1, nil
+		}
+		return "", err
+	}
+	defer f.Close()
+
+	// the object format is stored as extensions.objectformat, section and key names are case-insensitive
+	inExtensions := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "[") {
+			inExtensions = strings.EqualFold(strings.TrimSpace(strings.Trim(line, "[]")), "extensions")
+			continue
+		}
+		if !inExtensions {
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if ok && strings.EqualFold(strings.TrimSpace(key), "objectformat") {
+			return ParseObjectFormat(strings.TrimSpace(value))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return ObjectFormatSHA1, nil
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseObjectFormat(t *testing.T) {
+	objectFormat, err := ParseObjectFormat("")
+	assert.NoError(t, err)
+	assert.Equal(t, ObjectFormatSHA1, objectFormat)
+
+	objectFormat, err = ParseObjectFormat("SHA256")
+	assert.NoError(t, err)
+	assert.Equal(t, ObjectFormatSHA256, objectFormat)
+	assert.Equal(t, 32, objectFormat.Size())
+	assert.Equal(t, SHA256FullLength, objectFormat.FullLength())
+
+	_, err = ParseObjectFormat("md5")
+	assert.Error(t, err)
+}
+
+func TestIsFullSHA(t *testing.T) {
+	assert.True(t, IsFullSHA("9023902390239023902390239023902390239023"))
+	assert.True(t, IsFullSHA(EmptyTreeSHA256))
+	assert.False(t, IsFullSHA("902390239023"))
+	assert.False(t, IsFullSHA("90239023902390239023902390239023902390239023"))
+
+	assert.True(t, IsEmptyCommitID(EmptySHA))
+	assert.True(t, IsEmptyCommitID(EmptySHA256))
+	assert.False(t, IsEmptyCommitID(""))
+}
+
+func TestGetObjectFormatOfRepo(t *testing.T) {
+	objectFormat, err := GetObjectFormatOfRepo(filepath.Join(testReposDir, "repo1_bare"))
+	assert.NoError(t, err)
+	assert.Equal(t, ObjectFormatSHA1, objectFormat)
+
+	tmpDir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(tmpDir, "config"), []byte("[core]\n\trepositoryformatversion = 1\n[Extensions]\n\tobjectFormat = sha256\n"), 0o644))
+	objectFormat, err = GetObjectFormatOfRepo(tmpDir)
+	assert.NoError(t, err)
+	assert.Equal(t, ObjectFormatSHA256, objectFormat)
+}
@@ -62,8 +62,12 @@ func IsRepoURLAccessible(ctx context.Context, url string) bool {
 	return err == nil
 }
 
-// InitRepository initializes a new Git repository.
-func InitRepository(ctx context.Context, repoPath string, bare bool) error {
+// InitRepository initializes a new Git repository with the object format, an empty object format is SHA-1.
+func InitRepository(ctx context.Context, repoPath string, bare bool, objectFormat ObjectFormat) error {
+	if objectFormat != "" && !objectFormat.IsSupported() {
+		return ErrObjectFormatNotSupported{ObjectFormat: objectFormat.String()}
+	}
+
 	err := os.MkdirAll(repoPath, os.ModePerm)
 	if err != nil {
 		return err
@@ -73,10 +77,18 @@ func InitRepository(ctx context.Context, repoPath string, bare bool) error {
 	if bare {
 		cmd.AddArguments("--bare")
 	}
+	if objectFormat == ObjectFormatSHA256 {
+		cmd.AddArguments("--object-format=sha256")
+	}
 	_, _, err = cmd.RunStdString(&RunOpts{Dir: repoPath})
 	return err
 }
 
+// ObjectFormat returns the object format of the repository
+func (repo *Repository) ObjectFormat() ObjectFormat {
+	return repo.objectFormat
+}
+
 // IsEmpty Check if repository is empty.
 func (repo *Repository) IsEmpty() (bool, error) {
 	var errbuf, output strings.Builder
@@ -246,9 +258,20 @@ type DivergeObject struct {
 
 // GetDivergingCommits returns the number of commits a targetBranch is ahead or behind a baseBranch
 func GetDivergingCommits(ctx context.Context, repoPath, baseBranch, targetBranch string) (do DivergeObject, err error) {
+	return getDivergingCommits(ctx, repoPath, baseBranch, targetBranch, nil)
+}
+
+// GetDivergingCommitsWithAlternate returns the number of commits targetCommitID is ahead or behind baseCommitID,
+// where baseCommitID may only exist in alternateRepoPath, e.g. the repository a fork was forked from.
+func GetDivergingCommitsWithAlternate(ctx context.Context, repoPath, alternateRepoPath, baseCommitID, targetCommitID string) (do DivergeObject, err error) {
+	env := append(os.Environ(), "GIT_ALTERNATE_OBJECT_DIRECTORIES="+filepath.Join(alternateRepoPath, "objects"))
+	return getDivergingCommits(ctx, repoPath, baseCommitID, targetCommitID, env)
+}
+
+func getDivergingCommits(ctx context.Context, repoPath, base, target string, env []string) (do DivergeObject, err error) {
 	cmd := NewCommand(ctx, "rev-list", "--count", "--left-right").
-		AddDynamicArguments(baseBranch + "..." + targetBranch)
-	stdout, _, err := cmd.RunStdString(&RunOpts{Dir: repoPath})
+		AddDynamicArguments(base + "..." + target)
+	stdout, _, err := cmd.RunStdString(&RunOpts{Dir: repoPath, Env: env})
 	if err != nil {
 		return do, err
 	}
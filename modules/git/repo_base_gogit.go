@@ -9,6 +9,7 @@ package git
 import (
 	"context"
 	"errors"
+	"fmt"
 	"path/filepath"
 
 	gitealog "code.gitea.io/gitea/modules/log"
@@ -29,6 +30,7 @@ type Repository struct {
 	gogitRepo    *gogit.Repository
 	gogitStorage *filesystem.Storage
 	gpgSettings  *GPGSettings
+	objectFormat ObjectFormat
 
 	Ctx             context.Context
 	LastCommitCache *LastCommitCache
@@ -48,6 +50,13 @@ func OpenRepository(ctx context.Context, repoPath string) (*Repository, error) {
 		return nil, errors.New("no such file or directory")
 	}
 
+	objectFormat, err := GetObjectFormatOfRepo(repoPath)
+	if err != nil {
+		return nil, err
+	} else if objectFormat != ObjectFormatSHA1 {
+		return nil, fmt.Errorf("object format %s of repository %s is not supported by go-git", objectFormat, repoPath)
+	}
+
 	fs := osfs.New(repoPath)
 	_, err = fs.Stat(".git")
 	if err == nil {
@@ -66,6 +75,7 @@ func OpenRepository(ctx context.Context, repoPath string) (*Repository, error) {
 		Path:         repoPath,
 		gogitRepo:    gogitRepo,
 		gogitStorage: storage,
+		objectFormat: objectFormat,
 		tagCache:     newObjectCache(),
 		Ctx:          ctx,
 	}, nil
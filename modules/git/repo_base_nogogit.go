@@ -23,6 +23,8 @@ type Repository struct {
 
 	gpgSettings *GPGSettings
 
+	objectFormat ObjectFormat
+
 	batchCancel context.CancelFunc
 	batchReader *bufio.Reader
 	batchWriter WriteCloserError
@@ -54,10 +56,16 @@ func OpenRepository(ctx context.Context, repoPath string) (*Repository, error) {
 		return nil, err
 	}
 
+	objectFormat, err := GetObjectFormatOfRepo(repoPath)
+	if err != nil {
+		return nil, err
+	}
+
 	repo := &Repository{
-		Path:     repoPath,
-		tagCache: newObjectCache(),
-		Ctx:      ctx,
+		Path:         repoPath,
+		tagCache:     newObjectCache(),
+		objectFormat: objectFormat,
+		Ctx:          ctx,
 	}
 
 	repo.batchWriter, repo.batchReader, repo.batchCancel = CatFileBatch(ctx, repoPath)
@@ -5,8 +5,8 @@
 package git
 
 import (
+	"bufio"
 	"bytes"
-	"encoding/hex"
 	"fmt"
 	"io"
 	"strconv"
@@ -254,22 +254,18 @@ func (repo *Repository) CommitsByFileAndRange(opts CommitsByFileAndRangeOptions)
 	}()
 
 	commits := []*Commit{}
-	shaline := [41]byte{}
-	var sha1 SHA1
+	rd := bufio.NewReader(stdoutReader)
 	for {
-		n, err := io.ReadFull(stdoutReader, shaline[:])
-		if err != nil || n < 40 {
+		shaline, err := rd.ReadString('\n')
+		if err != nil && (err != io.EOF || shaline == "") {
 			if err == io.EOF {
 				err = nil
 			}
 			return commits, err
 		}
-		n, err = hex.Decode(sha1[:], shaline[0:40])
-		if n != 20 {
-			err = fmt.Errorf("invalid sha %q", string(shaline[:40]))
-		}
+		sha1, err := NewIDFromString(shaline)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("invalid sha %q: %w", shaline, err)
 		}
 		commit, err := repo.getCommit(sha1)
 		if err != nil {
@@ -479,6 +475,18 @@ func (repo *Repository) getBranches(commit *Commit, limit int) ([]string, error)
 	return branches, nil
 }
 
+// GetBranchesContaining returns up to limit names of the branches which contain the given commit
+func (repo *Repository) GetBranchesContaining(commitID string, limit int) ([]string, error) {
+	stdout, _, err := NewCommand(repo.Ctx, "for-each-ref", "--format=%(refname:strip=2)").
+		AddOptionFormat("--count=%d", limit).
+		AddOptionValues("--contains", commitID, BranchPrefix).
+		RunStdString(&RunOpts{Dir: repo.Path})
+	if err != nil {
+		return nil, err
+	}
+	return strings.Fields(stdout), nil
+}
+
 // GetCommitsFromIDs get commits from commit IDs
 func (repo *Repository) GetCommitsFromIDs(commitIDs []string) []*Commit {
 	commits := make([]*Commit, 0, len(commitIDs))
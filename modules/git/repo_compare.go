@@ -331,3 +331,65 @@ func (repo *Repository) ReadPatchCommit(prID int64) (commitSHA string, err error
 	}
 	return commitSHA, nil
 }
+
+// GetEquivalentCommits returns the non-merge commits of head which aren't in base but introduce the same changes
+// as a commit of base, like cherry-picked commits, mapped to that commit. Commits are equivalent if their stable
+// patch ids are equal.
+func (repo *Repository) GetEquivalentCommits(base, head string) (map[string]string, error) {
+	headPatchIDs, err := repo.getPatchIDs(head, base)
+	if err != nil {
+		return nil, err
+	}
+	equivalent := make(map[string]string)
+	if len(headPatchIDs) == 0 {
+		return equivalent, nil
+	}
+	basePatchIDs, err := repo.getPatchIDs(base, head)
+	if err != nil {
+		return nil, err
+	}
+
+	baseCommitIDs := make(map[string]string, len(basePatchIDs))
+	for commitID, patchID := range basePatchIDs {
+		baseCommitIDs[patchID] = commitID
+	}
+	for commitID, patchID := range headPatchIDs {
+		if baseCommitID, ok := baseCommitIDs[patchID]; ok {
+			equivalent[commitID] = baseCommitID
+		}
+	}
+	return equivalent, nil
+}
+
+// getPatchIDs returns the stable patch ids of the non-merge commits of include which aren't in exclude,
+// commits without changes have no patch id
+func (repo *Repository) getPatchIDs(include, exclude string) (map[string]string, error) {
+	logReader, logWriter := io.Pipe()
+	defer logReader.Close()
+
+	go func() {
+		stderr := strings.Builder{}
+		err := NewCommand(repo.Ctx, "log", "-p", "--no-merges", "--no-color", "--no-ext-diff", "--format=commit %H").
+			AddDynamicArguments(include).AddArguments("--not").AddDynamicArguments(exclude).AddArguments("--").
+			Run(&RunOpts{Dir: repo.Path, Stdout: logWriter, Stderr: &stderr})
+		if err != nil {
+			_ = logWriter.CloseWithError(ConcatenateError(err, stderr.String()))
+		} else {
+			_ = logWriter.Close()
+		}
+	}()
+
+	stdout, _, err := NewCommand(repo.Ctx, "patch-id", "--stable").RunStdString(&RunOpts{Dir: repo.Path, Stdin: logReader})
+	if err != nil {
+		return nil, err
+	}
+
+	patchIDs := make(map[string]string)
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		patchID, commitID, ok := strings.Cut(line, " ")
+		if ok {
+			patchIDs[commitID] = patchID
+		}
+	}
+	return patchIDs, nil
+}
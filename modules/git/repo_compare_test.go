@@ -119,3 +119,38 @@ func TestReadWritePullHead(t *testing.T) {
 	err = repo.RemoveReference(PullPrefix + "1/head")
 	assert.NoError(t, err)
 }
+
+func TestGetEquivalentCommits(t *testing.T) {
+	bareRepo1Path := filepath.Join(testReposDir, "repo1_bare")
+	clonedPath, err := cloneRepo(t, bareRepo1Path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// cherry-pick "Added broken links" onto a branch started before it
+	_, _, err = NewCommand(DefaultContext, "checkout", "-b", "release", "95bb4d39648ee7e325106df01a621c530863a653").RunStdString(&RunOpts{Dir: clonedPath})
+	assert.NoError(t, err)
+	_, _, err = NewCommand(DefaultContext, "-c", "user.name=Tester", "-c", "user.email=tester@example.com", "cherry-pick", "6fbd69e9823458e6c4a2fc5c0f6bc022b2f2acd1").RunStdString(&RunOpts{Dir: clonedPath})
+	assert.NoError(t, err)
+
+	repo, err := openRepositoryWithDefaultContext(clonedPath)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer repo.Close()
+
+	picked, err := repo.GetBranchCommitID("release")
+	assert.NoError(t, err)
+
+	equivalent, err := repo.GetEquivalentCommits("master", "release")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{picked: "6fbd69e9823458e6c4a2fc5c0f6bc022b2f2acd1"}, equivalent)
+
+	equivalent, err = repo.GetEquivalentCommits("release", "master")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"6fbd69e9823458e6c4a2fc5c0f6bc022b2f2acd1": picked}, equivalent)
+
+	equivalent, err = repo.GetEquivalentCommits("master", "origin/branch1")
+	assert.NoError(t, err)
+	assert.Empty(t, equivalent)
+}
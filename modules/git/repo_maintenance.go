@@ -0,0 +1,106 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ObjectStats represents the output of 'git count-objects -v'
+type ObjectStats struct {
+	LooseObjects  int64
+	LooseSize     int64 // in KiB
+	PackedObjects int64
+	Packs         int64
+	PackSize      int64 // in KiB
+	PrunePackable int64
+	Garbage       int64
+}
+
+// CountObjects returns the object statistics of a repository
+func CountObjects(ctx context.Context, repoPath string) (*ObjectStats, error) {
+	stdout, _, err := NewCommand(ctx, "count-objects", "-v").RunStdString(&RunOpts{Dir: repoPath})
+	if err != nil {
+		return nil, fmt.Errorf("unable to count objects of '%s': %w", repoPath, err)
+	}
+	return parseObjectStats(stdout)
+}
+
+func parseObjectStats(stdout string) (*ObjectStats, error) {
+	stats := &ObjectStats{}
+	scanner := bufio.NewScanner(strings.NewReader(stdout))
+	for scanner.Scan() {
+		key, value, ok := strings.Cut(scanner.Text(), ":")
+		if !ok {
+			continue
+		}
+		var field *int64
+		switch key {
+		case "count":
+			field = &stats.LooseObjects
+		case "size":
+			field = &stats.LooseSize
+		case "in-pack":
+			field = &stats.PackedObjects
+		case "packs":
+			field = &stats.Packs
+		case "size-pack":
+			field = &stats.PackSize
+		case "prune-packable":
+			field = &stats.PrunePackable
+		case "garbage":
+			field = &stats.Garbage
+		default:
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for %s: %w", key, err)
+		}
+		*field = n
+	}
+	return stats, scanner.Err()
+}
+
+// HasCommitGraph checks if a commit-graph file has been written for the repository
+func HasCommitGraph(repoPath string) bool {
+	if exist, _ := util.IsExist(filepath.Join(repoPath, "objects", "info", "commit-graph")); exist {
+		return true
+	}
+	exist, _ := util.IsExist(filepath.Join(repoPath, "objects", "info", "commit-graphs"))
+	return exist
+}
+
+// HasMultiPackIndex checks if a multi-pack-index has been written for the repository
+func HasMultiPackIndex(repoPath string) bool {
+	exist, _ := util.IsExist(filepath.Join(repoPath, "objects", "pack", "multi-pack-index"))
+	return exist
+}
+
+// Repack packs all objects of the repository into a single pack and removes redundant packs
+func Repack(ctx context.Context, repoPath string, timeout time.Duration) error {
+	if _, _, err := NewCommand(ctx, "repack", "-a", "-d", "--write-bitmap-index").RunStdString(&RunOpts{Dir: repoPath, Timeout: timeout}); err != nil {
+		return fmt.Errorf("unable to repack '%s': %w", repoPath, err)
+	}
+	return nil
+}
+
+// WriteMultiPackIndex writes a multi-pack-index for the packs of the repository
+// this requires git v2.21 to be installed
+func WriteMultiPackIndex(ctx context.Context, repoPath string) error {
+	if CheckGitVersionAtLeast("2.21") == nil {
+		if _, _, err := NewCommand(ctx, "multi-pack-index", "write").RunStdString(&RunOpts{Dir: repoPath}); err != nil {
+			return fmt.Errorf("unable to write multi-pack-index for '%s': %w", repoPath, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseObjectStats(t *testing.T) {
+	stats, err := parseObjectStats(`count: 12
+size: 48
+in-pack: 2530
+packs: 3
+size-pack: 1024
+prune-packable: 1
+garbage: 0
+size-garbage: 0
+`)
+	assert.NoError(t, err)
+	assert.Equal(t, &ObjectStats{
+		LooseObjects:  12,
+		LooseSize:     48,
+		PackedObjects: 2530,
+		Packs:         3,
+		PackSize:      1024,
+		PrunePackable: 1,
+	}, stats)
+
+	_, err = parseObjectStats("count: many\n")
+	assert.Error(t, err)
+}
@@ -66,7 +66,7 @@ func (repo *Repository) getTree(id SHA1) (*Tree, error) {
 
 // GetTree find the tree object in the repository.
 func (repo *Repository) GetTree(idStr string) (*Tree, error) {
-	if len(idStr) != SHAFullLength {
+	if !IsFullSHA(idStr) {
 		res, err := repo.GetRefCommitID(idStr)
 		if err != nil {
 			return nil, err
@@ -0,0 +1,314 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// ErrSchedulerBusy is returned if a scheduled git command could not be started within the queue timeout
+var ErrSchedulerBusy = errors.New("too many concurrent git commands")
+
+// SchedulerKey identifies the repository and the user for which a git command is run.
+// Commands with a key are subject to the concurrency limits of the git scheduler.
+type SchedulerKey struct {
+	Repo string
+	User string
+}
+
+// SchedulerStats are the metrics of the git scheduler
+type SchedulerStats struct {
+	Limit     int
+	Running   int
+	Waiting   int
+	Started   int64
+	Rejected  int64
+	WaitTotal time.Duration
+}
+
+// SystemLoad describes the load of the system
+type SystemLoad struct {
+	LoadPerCPU         float64
+	FreeMemoryFraction float64
+}
+
+type schedulerOptions struct {
+	MinConcurrency  int
+	MaxConcurrency  int
+	MaxPerRepo      int
+	MaxPerUser      int
+	MaxLoadPerCPU   float64
+	MinFreeMemory   float64
+	SampleInterval  time.Duration
+	QueueTimeout    time.Duration
+	sampleLoad      func() (SystemLoad, bool)
+	currentTimeFunc func() time.Time
+}
+
+type schedulerWaiter struct {
+	key   SchedulerKey
+	ready chan struct{}
+}
+
+// scheduler limits the number of concurrent git commands. Waiting commands are started
+// in favor of the users with the fewest running commands, so one user can't starve the others.
+// The limit is lowered when the system is overloaded and raised again when it recovers.
+type scheduler struct {
+	opts schedulerOptions
+
+	mu          sync.Mutex
+	limit       int
+	running     int
+	runningRepo map[string]int
+	runningUser map[string]int
+	waiters     []*schedulerWaiter
+	lastSample  time.Time
+
+	started   int64
+	rejected  int64
+	waitTotal time.Duration
+}
+
+func newScheduler(opts schedulerOptions) *scheduler {
+	if opts.MaxConcurrency <= 0 {
+		opts.MaxConcurrency = 2 * runtime.NumCPU()
+	}
+	if opts.MinConcurrency <= 0 {
+		opts.MinConcurrency = 1
+	}
+	if opts.MinConcurrency > opts.MaxConcurrency {
+		opts.MinConcurrency = opts.MaxConcurrency
+	}
+	if opts.sampleLoad == nil {
+		opts.sampleLoad = readSystemLoad
+	}
+	if opts.currentTimeFunc == nil {
+		opts.currentTimeFunc = time.Now
+	}
+	return &scheduler{
+		opts:        opts,
+		limit:       opts.MaxConcurrency,
+		runningRepo: make(map[string]int),
+		runningUser: make(map[string]int),
+	}
+}
+
+var (
+	globalScheduler     *scheduler
+	globalSchedulerOnce sync.Once
+)
+
+// getScheduler returns the scheduler configured by the git.scheduler settings, or nil if it is disabled
+func getScheduler() *scheduler {
+	globalSchedulerOnce.Do(func() {
+		cfg := setting.Git.Scheduler
+		if !cfg.Enabled {
+			return
+		}
+		globalScheduler = newScheduler(schedulerOptions{
+			MinConcurrency: cfg.MinConcurrency,
+			MaxConcurrency: cfg.MaxConcurrency,
+			MaxPerRepo:     cfg.MaxPerRepo,
+			MaxPerUser:     cfg.MaxPerUser,
+			MaxLoadPerCPU:  cfg.MaxLoadPerCPU,
+			MinFreeMemory:  float64(cfg.MinFreeMemoryPercent) / 100,
+			SampleInterval: cfg.SampleInterval,
+			QueueTimeout:   cfg.QueueTimeout,
+		})
+	})
+	return globalScheduler
+}
+
+// GetSchedulerStats returns the metrics of the git scheduler, ok is false if the scheduler is disabled
+func GetSchedulerStats() (stats SchedulerStats, ok bool) {
+	s := getScheduler()
+	if s == nil {
+		return stats, false
+	}
+	return s.Stats(), true
+}
+
+// Stats returns the current metrics of the scheduler
+func (s *scheduler) Stats() SchedulerStats {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return SchedulerStats{
+		Limit:     s.limit,
+		Running:   s.running,
+		Waiting:   len(s.waiters),
+		Started:   s.started,
+		Rejected:  s.rejected,
+		WaitTotal: s.waitTotal,
+	}
+}
+
+// Acquire blocks until the command with the key may run. The returned function must be called when the command has finished.
+func (s *scheduler) Acquire(ctx context.Context, key SchedulerKey) (func(), error) {
+	start := s.opts.currentTimeFunc()
+
+	s.mu.Lock()
+	s.adjustLimitLocked(start)
+	if len(s.waiters) == 0 && s.canRunLocked(key) {
+		s.startLocked(key)
+		s.mu.Unlock()
+		return s.releaseFunc(key), nil
+	}
+	w := &schedulerWaiter{key: key, ready: make(chan struct{})}
+	s.waiters = append(s.waiters, w)
+	s.dispatchLocked()
+	s.mu.Unlock()
+
+	var timeout <-chan time.Time
+	if s.opts.QueueTimeout > 0 {
+		timer := time.NewTimer(s.opts.QueueTimeout)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	var err error
+	select {
+	case <-w.ready:
+	case <-ctx.Done():
+		err = ctx.Err()
+	case <-timeout:
+		err = ErrSchedulerBusy
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err != nil {
+		select {
+		case <-w.ready:
+			// the command was started concurrently with the cancellation, give the slot back
+			s.finishLocked(key)
+		default:
+			s.removeWaiterLocked(w)
+		}
+		s.rejected++
+		s.dispatchLocked()
+		return nil, err
+	}
+
+	s.waitTotal += s.opts.currentTimeFunc().Sub(start)
+	return s.releaseFunc(key), nil
+}
+
+func (s *scheduler) releaseFunc(key SchedulerKey) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+
+			s.finishLocked(key)
+			s.adjustLimitLocked(s.opts.currentTimeFunc())
+			s.dispatchLocked()
+		})
+	}
+}
+
+func (s *scheduler) canRunLocked(key SchedulerKey) bool {
+	if s.running >= s.limit {
+		return false
+	}
+	if s.opts.MaxPerRepo > 0 && s.runningRepo[key.Repo] >= s.opts.MaxPerRepo {
+		return false
+	}
+	if s.opts.MaxPerUser > 0 && s.runningUser[key.User] >= s.opts.MaxPerUser {
+		return false
+	}
+	return true
+}
+
+func (s *scheduler) startLocked(key SchedulerKey) {
+	s.running++
+	s.runningRepo[key.Repo]++
+	s.runningUser[key.User]++
+	s.started++
+}
+
+func (s *scheduler) finishLocked(key SchedulerKey) {
+	s.running--
+	if s.runningRepo[key.Repo]--; s.runningRepo[key.Repo] <= 0 {
+		delete(s.runningRepo, key.Repo)
+	}
+	if s.runningUser[key.User]--; s.runningUser[key.User] <= 0 {
+		delete(s.runningUser, key.User)
+	}
+}
+
+func (s *scheduler) removeWaiterLocked(w *schedulerWaiter) {
+	for i, waiter := range s.waiters {
+		if waiter == w {
+			s.waiters = append(s.waiters[:i], s.waiters[i+1:]...)
+			return
+		}
+	}
+}
+
+// dispatchLocked starts waiting commands while there are free slots. Of the commands which may run,
+// the one whose user has the fewest running commands is started first, the oldest one on ties.
+func (s *scheduler) dispatchLocked() {
+	for s.running < s.limit {
+		next := -1
+		for i, w := range s.waiters {
+			if !s.canRunLocked(w.key) {
+				continue
+			}
+			if next == -1 || s.runningUser[w.key.User] < s.runningUser[s.waiters[next].key.User] {
+				next = i
+			}
+		}
+		if next == -1 {
+			return
+		}
+		w := s.waiters[next]
+		s.waiters = append(s.waiters[:next], s.waiters[next+1:]...)
+		s.startLocked(w.key)
+		close(w.ready)
+	}
+}
+
+// adjustLimitLocked samples the system load and halves the limit if the system is overloaded,
+// or raises it by one if it isn't.
+func (s *scheduler) adjustLimitLocked(now time.Time) {
+	if s.opts.MaxLoadPerCPU <= 0 && s.opts.MinFreeMemory <= 0 {
+		return
+	}
+	if now.Sub(s.lastSample) < s.opts.SampleInterval {
+		return
+	}
+	s.lastSample = now
+
+	load, ok := s.opts.sampleLoad()
+	if !ok {
+		return
+	}
+
+	overloaded := (s.opts.MaxLoadPerCPU > 0 && load.LoadPerCPU > s.opts.MaxLoadPerCPU) ||
+		(s.opts.MinFreeMemory > 0 && load.FreeMemoryFraction < s.opts.MinFreeMemory)
+
+	limit := s.limit
+	if overloaded {
+		limit /= 2
+		if limit < s.opts.MinConcurrency {
+			limit = s.opts.MinConcurrency
+		}
+	} else if limit < s.opts.MaxConcurrency {
+		limit++
+	}
+	if limit != s.limit {
+		log.Debug("git scheduler: concurrency limit changed from %d to %d (load per cpu: %.2f, free memory: %.2f)", s.limit, limit, load.LoadPerCPU, load.FreeMemoryFraction)
+		s.limit = limit
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+)
+
+// readSystemLoad reads the load average and the available memory from procfs,
+// ok is false on systems without procfs
+func readSystemLoad() (load SystemLoad, ok bool) {
+	data, err := os.ReadFile("/proc/loadavg")
+	if err != nil {
+		return load, false
+	}
+	fields := strings.Fields(string(data))
+	if len(fields) == 0 {
+		return load, false
+	}
+	loadAvg, err := strconv.ParseFloat(fields[0], 64)
+	if err != nil {
+		return load, false
+	}
+	load.LoadPerCPU = loadAvg / float64(runtime.NumCPU())
+
+	data, err = os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return load, false
+	}
+	load.FreeMemoryFraction = parseMemInfoFreeFraction(data)
+	return load, true
+}
+
+// parseMemInfoFreeFraction returns the fraction of the available memory from the content of /proc/meminfo
+func parseMemInfoFreeFraction(data []byte) float64 {
+	var total, available int64
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		value, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			continue
+		}
+		switch fields[0] {
+		case "MemTotal:":
+			total = value
+		case "MemAvailable:":
+			available = value
+		}
+	}
+	if total <= 0 {
+		return 1
+	}
+	return float64(available) / float64(total)
+}
@@ -0,0 +1,124 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package git
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchedulerFairness(t *testing.T) {
+	s := newScheduler(schedulerOptions{MaxConcurrency: 2})
+
+	ctx := context.Background()
+	release1, err := s.Acquire(ctx, SchedulerKey{Repo: "ci/repo", User: "ci"})
+	assert.NoError(t, err)
+	release2, err := s.Acquire(ctx, SchedulerKey{Repo: "ci/repo", User: "ci"})
+	assert.NoError(t, err)
+
+	started := make(chan string, 2)
+	acquire := func(key SchedulerKey) {
+		release, err := s.Acquire(ctx, key)
+		if assert.NoError(t, err) {
+			started <- key.User
+			release()
+		}
+	}
+	go acquire(SchedulerKey{Repo: "ci/repo", User: "ci"})
+	assert.Eventually(t, func() bool { return s.Stats().Waiting == 1 }, time.Second, time.Millisecond)
+	go acquire(SchedulerKey{Repo: "user/repo", User: "user"})
+	assert.Eventually(t, func() bool { return s.Stats().Waiting == 2 }, time.Second, time.Millisecond)
+
+	// the user without running commands is started before the older command of the busy user
+	release1()
+	assert.Equal(t, "user", <-started)
+	release2()
+	assert.Equal(t, "ci", <-started)
+
+	assert.Eventually(t, func() bool { return s.Stats().Running == 0 }, time.Second, time.Millisecond)
+	assert.EqualValues(t, 4, s.Stats().Started)
+}
+
+func TestSchedulerPerKeyLimits(t *testing.T) {
+	s := newScheduler(schedulerOptions{MaxConcurrency: 10, MaxPerRepo: 1, MaxPerUser: 2, QueueTimeout: 10 * time.Millisecond})
+
+	ctx := context.Background()
+	release, err := s.Acquire(ctx, SchedulerKey{Repo: "a", User: "u1"})
+	assert.NoError(t, err)
+
+	_, err = s.Acquire(ctx, SchedulerKey{Repo: "a", User: "u2"})
+	assert.ErrorIs(t, err, ErrSchedulerBusy)
+
+	_, err = s.Acquire(ctx, SchedulerKey{Repo: "b", User: "u1"})
+	assert.NoError(t, err)
+	_, err = s.Acquire(ctx, SchedulerKey{Repo: "c", User: "u1"})
+	assert.ErrorIs(t, err, ErrSchedulerBusy)
+
+	release()
+	release() // releasing twice must not free a second slot
+	assert.Equal(t, 1, s.Stats().Running)
+	assert.EqualValues(t, 2, s.Stats().Rejected)
+
+	cancelCtx, cancel := context.WithCancel(ctx)
+	cancel()
+	s.opts.QueueTimeout = 0
+	_, err = s.Acquire(cancelCtx, SchedulerKey{Repo: "d", User: "u1"})
+	assert.NoError(t, err)
+	_, err = s.Acquire(cancelCtx, SchedulerKey{Repo: "e", User: "u1"})
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.Zero(t, s.Stats().Waiting)
+}
+
+func TestSchedulerAdaptiveLimit(t *testing.T) {
+	now := time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)
+	load := SystemLoad{LoadPerCPU: 0.5, FreeMemoryFraction: 0.5}
+	s := newScheduler(schedulerOptions{
+		MinConcurrency: 2,
+		MaxConcurrency: 8,
+		MaxLoadPerCPU:  1,
+		MinFreeMemory:  0.1,
+		SampleInterval: time.Second,
+		sampleLoad: func() (SystemLoad, bool) {
+			return load, true
+		},
+		currentTimeFunc: func() time.Time {
+			return now
+		},
+	})
+
+	adjust := func() int {
+		now = now.Add(time.Second)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.adjustLimitLocked(now)
+		return s.limit
+	}
+
+	assert.Equal(t, 8, adjust())
+
+	load.LoadPerCPU = 3
+	assert.Equal(t, 4, adjust())
+	assert.Equal(t, 2, adjust())
+	assert.Equal(t, 2, adjust())
+
+	load.LoadPerCPU = 0.5
+	load.FreeMemoryFraction = 0.05
+	assert.Equal(t, 2, adjust())
+
+	load.FreeMemoryFraction = 0.5
+	assert.Equal(t, 3, adjust())
+	assert.Equal(t, 4, adjust())
+}
+
+func TestParseMemInfoFreeFraction(t *testing.T) {
+	data := []byte(`MemTotal:       16000000 kB
+MemFree:         1000000 kB
+MemAvailable:    4000000 kB
+`)
+	assert.InDelta(t, 0.25, parseMemInfoFreeFraction(data), 0.0001)
+	assert.EqualValues(t, 1, parseMemInfoFreeFraction(nil))
+}
@@ -8,33 +8,71 @@ package git
 
 import (
 	"crypto/sha1"
+	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
 	"hash"
 	"strconv"
 )
 
-// SHA1 a git commit name
-type SHA1 [20]byte
+const supportSHA256Build = true
+
+// SHA1 a git object name. Despite its name it holds either a SHA-1 or a SHA-256 hash,
+// depending on the object format of the repository.
+type SHA1 struct {
+	hash [sha256.Size]byte
+	size uint8 // the length of the hash in bytes, the zero value is treated as an empty SHA-1
+}
+
+// MustID always creates a new SHA1 from a 20 or 32 byte array with no validation of input.
+func MustID(b []byte) SHA1 {
+	var id SHA1
+	id.size = uint8(copy(id.hash[:], b))
+	return id
+}
+
+// NewID creates a new SHA1 from a 20 byte SHA-1 or a 32 byte SHA-256 array.
+func NewID(b []byte) (SHA1, error) {
+	if len(b) != sha1.Size && len(b) != sha256.Size {
+		return SHA1{}, fmt.Errorf("Length must be 20 or 32: %v", b)
+	}
+	return MustID(b), nil
+}
+
+// RawValue returns the binary hash
+func (s SHA1) RawValue() []byte {
+	if s.size == 0 {
+		return s.hash[:sha1.Size]
+	}
+	return s.hash[:s.size]
+}
 
 // String returns a string representation of the SHA
 func (s SHA1) String() string {
-	return hex.EncodeToString(s[:])
+	return hex.EncodeToString(s.RawValue())
 }
 
 // IsZero returns whether this SHA1 is all zeroes
 func (s SHA1) IsZero() bool {
-	var empty SHA1
-	return s == empty
+	return s.hash == [sha256.Size]byte{}
+}
+
+// ObjectFormat returns the object format the hash belongs to
+func (s SHA1) ObjectFormat() ObjectFormat {
+	if s.size == sha256.Size {
+		return ObjectFormatSHA256
+	}
+	return ObjectFormatSHA1
 }
 
 // ComputeBlobHash compute the hash for a given blob content
-func ComputeBlobHash(content []byte) SHA1 {
-	return ComputeHash(ObjectBlob, content)
+func ComputeBlobHash(objectFormat ObjectFormat, content []byte) SHA1 {
+	return ComputeHash(objectFormat, ObjectBlob, content)
 }
 
 // ComputeHash compute the hash for a given ObjectType and content
-func ComputeHash(t ObjectType, content []byte) SHA1 {
-	h := NewHasher(t, int64(len(content)))
+func ComputeHash(objectFormat ObjectFormat, t ObjectType, content []byte) SHA1 {
+	h := NewHasher(objectFormat, t, int64(len(content)))
 	_, _ = h.Write(content)
 	return h.Sum()
 }
@@ -44,9 +82,12 @@ type Hasher struct {
 	hash.Hash
 }
 
-// NewHasher takes an object type and size and creates a hasher to generate a SHA
-func NewHasher(t ObjectType, size int64) Hasher {
+// NewHasher takes an object format, an object type and size and creates a hasher to generate a SHA
+func NewHasher(objectFormat ObjectFormat, t ObjectType, size int64) Hasher {
 	h := Hasher{sha1.New()}
+	if objectFormat == ObjectFormatSHA256 {
+		h = Hasher{sha256.New()}
+	}
 	_, _ = h.Write(t.Bytes())
 	_, _ = h.Write([]byte(" "))
 	_, _ = h.Write([]byte(strconv.FormatInt(size, 10)))
@@ -55,7 +96,6 @@ func NewHasher(t ObjectType, size int64) Hasher {
 }
 
 // Sum generates a SHA1 for the provided hash
-func (h Hasher) Sum() (sha1 SHA1) {
-	copy(sha1[:], h.Hash.Sum(nil))
-	return sha1
+func (h Hasher) Sum() SHA1 {
+	return MustID(h.Hash.Sum(nil))
 }
@@ -0,0 +1,253 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package gitgraph
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/git"
+)
+
+// LayoutOptions are the options to lay out a commit graph
+type LayoutOptions struct {
+	// Revisions the graph starts from, all refs if empty
+	Revisions []string
+	// Not excludes the commits reachable from it
+	Not        string
+	HidePRRefs bool
+	Page       int
+	PageSize   int
+}
+
+// LayoutCommit is a commit of a commit graph laid out in lanes
+type LayoutCommit struct {
+	ID          string
+	Parents     []string
+	Refs        []string
+	AuthorName  string
+	AuthorEmail string
+	AuthorDate  time.Time
+	Subject     string
+
+	// Lane is the lane of the commit
+	Lane int
+	// ParentLanes are the lanes the edges to the parents continue in, in the order of the parents
+	ParentLanes []int
+	// MergedLanes are the other lanes whose edges end at this commit
+	MergedLanes []int
+	// ActiveLanes are the lanes with edges passing below this commit
+	ActiveLanes []int
+}
+
+// LayoutGraph is a page of a commit graph laid out in lanes
+type LayoutGraph struct {
+	Commits []*LayoutCommit
+	// ActiveLanes are the lanes with edges passing above the first commit of the page
+	ActiveLanes []int
+	// Total is the number of commits of the whole graph
+	Total int64
+}
+
+// laneLayout assigns the commits of a graph to lanes. The commits must be
+// added children first, which git log --date-order guarantees.
+type laneLayout struct {
+	// lanes holds the commits the lanes are waiting for, empty for free lanes
+	lanes []string
+}
+
+func (l *laneLayout) freeLane() int {
+	for i, id := range l.lanes {
+		if id == "" {
+			return i
+		}
+	}
+	l.lanes = append(l.lanes, "")
+	return len(l.lanes) - 1
+}
+
+func (l *laneLayout) add(c *LayoutCommit) {
+	c.Lane = -1
+	for i, id := range l.lanes {
+		if id != c.ID {
+			continue
+		}
+		if c.Lane < 0 {
+			c.Lane = i
+		} else {
+			c.MergedLanes = append(c.MergedLanes, i)
+		}
+		l.lanes[i] = ""
+	}
+	if c.Lane < 0 {
+		// the tip of a branch
+		c.Lane = l.freeLane()
+	}
+
+	c.ParentLanes = make([]int, 0, len(c.Parents))
+	for i, parent := range c.Parents {
+		lane := -1
+		for j, id := range l.lanes {
+			if id == parent {
+				lane = j
+				break
+			}
+		}
+		if lane < 0 {
+			if i == 0 {
+				lane = c.Lane
+			} else {
+				lane = l.freeLane()
+			}
+			l.lanes[lane] = parent
+		}
+		c.ParentLanes = append(c.ParentLanes, lane)
+	}
+
+	for len(l.lanes) > 0 && l.lanes[len(l.lanes)-1] == "" {
+		l.lanes = l.lanes[:len(l.lanes)-1]
+	}
+	c.ActiveLanes = l.activeLanes()
+}
+
+func (l *laneLayout) activeLanes() []int {
+	active := make([]int, 0, len(l.lanes))
+	for i, id := range l.lanes {
+		if id != "" {
+			active = append(active, i)
+		}
+	}
+	return active
+}
+
+func addLayoutRevisionArguments(cmd *git.Command, opts *LayoutOptions) {
+	if len(opts.Revisions) == 0 {
+		if opts.HidePRRefs {
+			cmd.AddArguments("--exclude=" + git.PullPrefix + "*")
+		}
+		cmd.AddArguments("--all")
+	} else {
+		cmd.AddDynamicArguments(opts.Revisions...)
+	}
+	if opts.Not != "" {
+		cmd.AddArguments("--not").AddDynamicArguments(opts.Not)
+	}
+}
+
+// parseLayoutCommit parses a line of git log --format=%H%x1f%P%x1f%D%x1f%an%x1f%ae%x1f%aI%x1f%s
+func parseLayoutCommit(line string) (*LayoutCommit, error) {
+	fields := strings.SplitN(line, "\x1f", 7)
+	if len(fields) != 7 {
+		return nil, fmt.Errorf("malformed commit graph line: %q", line)
+	}
+	c := &LayoutCommit{
+		ID:          fields[0],
+		Parents:     strings.Fields(fields[1]),
+		Refs:        make([]string, 0, 2),
+		AuthorName:  fields[3],
+		AuthorEmail: fields[4],
+		Subject:     fields[6],
+	}
+	for _, ref := range newRefsFromRefNames([]byte(fields[2])) {
+		if ref.Name != "HEAD" {
+			c.Refs = append(c.Refs, ref.Name)
+		}
+	}
+	if fields[5] != "" {
+		date, err := time.Parse(time.RFC3339, fields[5])
+		if err != nil {
+			return nil, fmt.Errorf("malformed commit date %q: %w", fields[5], err)
+		}
+		c.AuthorDate = date
+	}
+	return c, nil
+}
+
+// GetCommitGraphLayout returns a page of a commit graph with its commits laid out in lanes,
+// so clients can draw it without computing the topology of the history themselves.
+// The commits of the previous pages are laid out too, so the lanes continue across pages.
+func GetCommitGraphLayout(ctx context.Context, repoPath string, opts LayoutOptions) (*LayoutGraph, error) {
+	if opts.Page <= 0 {
+		opts.Page = 1
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = 50
+	}
+
+	countCmd := git.NewCommand(ctx, "rev-list", "--count")
+	addLayoutRevisionArguments(countCmd, &opts)
+	stdout, _, runErr := countCmd.RunStdString(&git.RunOpts{Dir: repoPath})
+	if runErr != nil {
+		return nil, runErr
+	}
+	total, err := strconv.ParseInt(strings.TrimSpace(stdout), 10, 64)
+	if err != nil {
+		return nil, err
+	}
+
+	graph := &LayoutGraph{
+		Commits:     make([]*LayoutCommit, 0, opts.PageSize),
+		ActiveLanes: []int{},
+		Total:       total,
+	}
+	skip := (opts.Page - 1) * opts.PageSize
+	if total <= int64(skip) {
+		return graph, nil
+	}
+
+	logCmd := git.NewCommand(ctx, "log", "--date-order", "--decorate=full").
+		AddOptionFormat("-n %d", skip+opts.PageSize).
+		AddArguments("--format=%H%x1f%P%x1f%D%x1f%an%x1f%ae%x1f%aI%x1f%s")
+	if opts.HidePRRefs {
+		logCmd.AddArguments("--decorate-refs-exclude=" + git.PullPrefix + "*")
+	}
+	addLayoutRevisionArguments(logCmd, &opts)
+
+	stdoutReader, stdoutWriter, err := os.Pipe()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = stdoutReader.Close()
+		_ = stdoutWriter.Close()
+	}()
+
+	stderr := new(strings.Builder)
+	err = logCmd.Run(&git.RunOpts{
+		Dir:    repoPath,
+		Stdout: stdoutWriter,
+		Stderr: stderr,
+		PipelineFunc: func(ctx context.Context, cancel context.CancelFunc) error {
+			_ = stdoutWriter.Close()
+
+			layout := &laneLayout{}
+			scanner := bufio.NewScanner(stdoutReader)
+			scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+			for row := 0; scanner.Scan(); row++ {
+				c, err := parseLayoutCommit(scanner.Text())
+				if err != nil {
+					cancel()
+					return err
+				}
+				if row == skip {
+					graph.ActiveLanes = layout.activeLanes()
+				}
+				layout.add(c)
+				if row >= skip {
+					graph.Commits = append(graph.Commits, c)
+				}
+			}
+			return scanner.Err()
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to lay out the commit graph: %w - %s", err, stderr.String())
+	}
+	return graph, nil
+}
@@ -0,0 +1,85 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package gitgraph
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLaneLayout(t *testing.T) {
+	// m merges b into a, both branch off from r
+	//
+	//   m
+	//   |\
+	//   a b
+	//   |/
+	//   r
+	commits := []*LayoutCommit{
+		{ID: "m", Parents: []string{"a", "b"}},
+		{ID: "b", Parents: []string{"r"}},
+		{ID: "a", Parents: []string{"r"}},
+		{ID: "r"},
+	}
+	layout := &laneLayout{}
+	for _, c := range commits {
+		layout.add(c)
+	}
+
+	assert.Equal(t, 0, commits[0].Lane)
+	assert.Equal(t, []int{0, 1}, commits[0].ParentLanes)
+	assert.Equal(t, []int{0, 1}, commits[0].ActiveLanes)
+
+	assert.Equal(t, 1, commits[1].Lane)
+	assert.Equal(t, []int{1}, commits[1].ParentLanes)
+
+	assert.Equal(t, 0, commits[2].Lane)
+	// the edge to r continues in the lane of b which waits for r already
+	assert.Equal(t, []int{1}, commits[2].ParentLanes)
+	assert.Equal(t, []int{1}, commits[2].ActiveLanes)
+
+	assert.Equal(t, 1, commits[3].Lane)
+	assert.Empty(t, commits[3].MergedLanes)
+	assert.Empty(t, commits[3].ActiveLanes)
+}
+
+func TestLaneLayoutMergedLanes(t *testing.T) {
+	// two branch tips x and y on the same parent p
+	commits := []*LayoutCommit{
+		{ID: "x", Parents: []string{"p"}},
+		{ID: "y", Parents: []string{"q"}},
+		{ID: "z", Parents: []string{"p"}},
+		{ID: "q", Parents: []string{"p"}},
+		{ID: "p"},
+	}
+	layout := &laneLayout{}
+	for _, c := range commits {
+		layout.add(c)
+	}
+
+	assert.Equal(t, 0, commits[0].Lane)
+	assert.Equal(t, 1, commits[1].Lane)
+	// z is a new tip, its edge joins the lane waiting for p
+	assert.Equal(t, 2, commits[2].Lane)
+	assert.Equal(t, []int{0}, commits[2].ParentLanes)
+	assert.Equal(t, 1, commits[3].Lane)
+	assert.Equal(t, []int{0}, commits[3].ParentLanes)
+	assert.Equal(t, 0, commits[4].Lane)
+	assert.Empty(t, commits[4].ActiveLanes)
+}
+
+func TestParseLayoutCommit(t *testing.T) {
+	c, err := parseLayoutCommit("1111\x1f2222 3333\x1fHEAD -> refs/heads/main, tag: refs/tags/v1.0\x1fJohn\x1fjohn@example.com\x1f2023-05-01T10:00:00+02:00\x1fMerge | things")
+	assert.NoError(t, err)
+	assert.Equal(t, "1111", c.ID)
+	assert.Equal(t, []string{"2222", "3333"}, c.Parents)
+	assert.Equal(t, []string{"refs/heads/main", "refs/tags/v1.0"}, c.Refs)
+	assert.Equal(t, "John", c.AuthorName)
+	assert.Equal(t, "Merge | things", c.Subject)
+	assert.EqualValues(t, 1682928000, c.AuthorDate.Unix())
+
+	_, err = parseLayoutCommit("1111\x1f")
+	assert.Error(t, err)
+}
@@ -13,6 +13,12 @@ import (
 
 // NewDialContext returns a DialContext for Transport, the DialContext will do allow/block list check
 func NewDialContext(usage string, allowList, blockList *HostMatchList) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return NewDialContextWithIPRanges(usage, allowList, blockList, nil)
+}
+
+// NewDialContextWithIPRanges returns a DialContext for Transport like NewDialContext,
+// which additionally only allows the IP addresses in the given ranges if there are any
+func NewDialContextWithIPRanges(usage string, allowList, blockList *HostMatchList, ipRanges []*net.IPNet) func(ctx context.Context, network, addr string) (net.Conn, error) {
 	// How Go HTTP Client works with redirection:
 	//   transport.RoundTrip URL=http://domain.com, Host=domain.com
 	//   transport.DialContext addrOrHost=domain.com:80
@@ -48,6 +54,9 @@ func NewDialContext(usage string, allowList, blockList *HostMatchList) func(ctx
 						return fmt.Errorf("%s can only call allowed HTTP servers (check your %s setting), deny '%s(%s)'", usage, allowList.SettingKeyHint, host, ipAddr)
 					}
 				}
+				if len(ipRanges) > 0 && !ipRangesContain(ipRanges, tcpAddr.IP) {
+					return fmt.Errorf("%s can only call HTTP servers in its pinned IP ranges, deny '%s(%s)'", usage, host, ipAddr)
+				}
 				// otherwise, we always follow the blocked list
 				return blockedError
 			},
@@ -55,3 +64,12 @@ func NewDialContext(usage string, allowList, blockList *HostMatchList) func(ctx
 		return dialer.DialContext(ctx, network, addrOrHost)
 	}
 }
+
+func ipRangesContain(ipRanges []*net.IPNet, ip net.IP) bool {
+	for _, ipRange := range ipRanges {
+		if ipRange.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,273 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package discussions
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"code.gitea.io/gitea/models/db"
+	discussion_model "code.gitea.io/gitea/models/discussion"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// IndexerData data stored in the discussion indexer
+type IndexerData struct {
+	ID       int64    `json:"id"`
+	RepoID   int64    `json:"repo_id"`
+	Title    string   `json:"title"`
+	Content  string   `json:"content"`
+	Comments []string `json:"comments"`
+	IsDelete bool     `json:"is_delete"`
+	IDs      []int64  `json:"ids"`
+}
+
+// Match represents on search result
+type Match struct {
+	ID    int64   `json:"id"`
+	Score float64 `json:"score"`
+}
+
+// SearchResult represents search results
+type SearchResult struct {
+	Total int64
+	Hits  []Match
+}
+
+// Indexer defines an interface to index the contents of discussions
+type Indexer interface {
+	Init() (bool, error)
+	Ping() bool
+	Index(discussions []*IndexerData) error
+	Delete(ids ...int64) error
+	Search(ctx context.Context, kw string, repoIDs []int64, limit, start int) (*SearchResult, error)
+	Close()
+}
+
+type indexerHolder struct {
+	indexer   Indexer
+	mutex     sync.RWMutex
+	cond      *sync.Cond
+	cancelled bool
+}
+
+func newIndexerHolder() *indexerHolder {
+	h := &indexerHolder{}
+	h.cond = sync.NewCond(h.mutex.RLocker())
+	return h
+}
+
+func (h *indexerHolder) cancel() {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.cancelled = true
+	h.cond.Broadcast()
+}
+
+func (h *indexerHolder) set(indexer Indexer) {
+	h.mutex.Lock()
+	defer h.mutex.Unlock()
+	h.indexer = indexer
+	h.cond.Broadcast()
+}
+
+func (h *indexerHolder) get() Indexer {
+	h.mutex.RLock()
+	defer h.mutex.RUnlock()
+	if h.indexer == nil && !h.cancelled {
+		h.cond.Wait()
+	}
+	return h.indexer
+}
+
+var (
+	// discussionIndexerQueue queue of discussions to be updated
+	discussionIndexerQueue *queue.WorkerPoolQueue[*IndexerData]
+	holder                 = newIndexerHolder()
+)
+
+// InitDiscussionIndexer initialize the discussion indexer, syncReindex is true then reindex until
+// all discussions are indexed.
+func InitDiscussionIndexer(syncReindex bool) {
+	_, _, finished := process.GetManager().AddTypedContext(context.Background(), "Service: DiscussionIndexer", process.SystemProcessType, false)
+
+	// Create the Queue
+	switch setting.Indexer.DiscussionType {
+	case "bleve":
+		handler := func(items ...*IndexerData) (unhandled []*IndexerData) {
+			indexer := holder.get()
+			if indexer == nil {
+				log.Error("Discussion indexer handler: unable to get indexer.")
+				return items
+			}
+			toIndex := make([]*IndexerData, 0, len(items))
+			for _, indexerData := range items {
+				log.Trace("IndexerData Process: %d %v %t", indexerData.ID, indexerData.IDs, indexerData.IsDelete)
+				if indexerData.IsDelete {
+					if err := indexer.Delete(indexerData.IDs...); err != nil {
+						log.Error("Discussion indexer handler: failed to from index: %v Error: %v", indexerData.IDs, err)
+						unhandled = append(unhandled, indexerData)
+					}
+					continue
+				}
+				toIndex = append(toIndex, indexerData)
+			}
+			if err := indexer.Index(toIndex); err != nil {
+				log.Error("Error whilst indexing: %v Error: %v", toIndex, err)
+				unhandled = append(unhandled, toIndex...)
+			}
+			return unhandled
+		}
+
+		discussionIndexerQueue = queue.CreateSimpleQueue("discussion_indexer", handler)
+
+		if discussionIndexerQueue == nil {
+			log.Fatal("Unable to create discussion indexer queue")
+		}
+	default:
+		discussionIndexerQueue = queue.CreateSimpleQueue[*IndexerData]("discussion_indexer", nil)
+	}
+
+	// Create the Indexer
+	log.Info("PID %d: Initializing Discussion Indexer: %s", os.Getpid(), setting.Indexer.DiscussionType)
+	var populate bool
+	switch setting.Indexer.DiscussionType {
+	case "bleve":
+		discussionIndexer := NewBleveIndexer(setting.Indexer.DiscussionPath)
+		exist, err := discussionIndexer.Init()
+		if err != nil {
+			holder.cancel()
+			log.Fatal("Unable to initialize Bleve Discussion Indexer at path: %s Error: %v", setting.Indexer.DiscussionPath, err)
+		}
+		populate = !exist
+		holder.set(discussionIndexer)
+		graceful.GetManager().RunAtTerminate(func() {
+			log.Debug("Closing discussion indexer")
+			discussionIndexer.Close()
+			finished()
+			log.Info("PID: %d Discussion Indexer closed", os.Getpid())
+		})
+	case "db":
+		holder.set(&DBIndexer{})
+		graceful.GetManager().RunAtTerminate(finished)
+	default:
+		holder.cancel()
+		log.Fatal("Unknown discussion indexer type: %s", setting.Indexer.DiscussionType)
+	}
+
+	// Start processing the queue
+	go graceful.GetManager().RunWithShutdownFns(discussionIndexerQueue.Run)
+
+	// Populate the index
+	if populate {
+		if syncReindex {
+			graceful.GetManager().RunWithShutdownContext(populateDiscussionIndexer)
+		} else {
+			go graceful.GetManager().RunWithShutdownContext(populateDiscussionIndexer)
+		}
+	}
+}
+
+// populateDiscussionIndexer populates the discussion indexer with all discussions
+func populateDiscussionIndexer(ctx context.Context) {
+	ctx, _, finished := process.GetManager().AddTypedContext(ctx, "Service: PopulateDiscussionIndexer", process.SystemProcessType, true)
+	defer finished()
+	for page := 1; ; page++ {
+		select {
+		case <-ctx.Done():
+			log.Warn("Discussion Indexer population shutdown before completion")
+			return
+		default:
+		}
+		discussions, _, err := discussion_model.FindDiscussions(ctx, discussion_model.FindDiscussionsOptions{
+			ListOptions: db.ListOptions{Page: page, PageSize: repo_model.RepositoryListDefaultPageSize},
+		})
+		if err != nil {
+			log.Error("FindDiscussions: %v", err)
+			return
+		}
+		if len(discussions) == 0 {
+			log.Debug("Discussion Indexer population complete")
+			return
+		}
+		for _, d := range discussions {
+			UpdateDiscussionIndexer(ctx, d)
+		}
+	}
+}
+
+// UpdateDiscussionIndexer adds or updates a discussion and its replies in the discussion indexer
+func UpdateDiscussionIndexer(ctx context.Context, d *discussion_model.Discussion) {
+	replies, _, err := discussion_model.FindComments(ctx, d.ID, db.ListOptions{})
+	if err != nil {
+		log.Error("FindComments: %v", err)
+		return
+	}
+	comments := make([]string, 0, len(replies))
+	for _, c := range replies {
+		comments = append(comments, c.Content)
+	}
+	indexerData := &IndexerData{
+		ID:       d.ID,
+		RepoID:   d.RepoID,
+		Title:    d.Title,
+		Content:  d.Content,
+		Comments: comments,
+	}
+	log.Debug("Adding to channel: %v", indexerData)
+	if err := discussionIndexerQueue.Push(indexerData); err != nil {
+		log.Error("Unable to push to discussion indexer: %v: Error: %v", indexerData, err)
+	}
+}
+
+// DeleteDiscussionIndexer removes discussions from the discussion indexer
+func DeleteDiscussionIndexer(ids ...int64) {
+	if len(ids) == 0 {
+		return
+	}
+	indexerData := &IndexerData{
+		IDs:      ids,
+		IsDelete: true,
+	}
+	if err := discussionIndexerQueue.Push(indexerData); err != nil {
+		log.Error("Unable to push to discussion indexer: %v: Error: %v", indexerData, err)
+	}
+}
+
+// DeleteRepoDiscussionIndexer removes all discussions of a repository from the discussion indexer
+func DeleteRepoDiscussionIndexer(ctx context.Context, repo *repo_model.Repository) {
+	ids, err := discussion_model.GetDiscussionIDsByRepoID(ctx, repo.ID)
+	if err != nil {
+		log.Error("GetDiscussionIDsByRepoID failed: %v", err)
+		return
+	}
+	DeleteDiscussionIndexer(ids...)
+}
+
+// SearchDiscussionsByKeyword searches the discussions of the repositories matching the keyword,
+// returning their ids, best matches first, and the total number of matches.
+// The caller has to make sure the user may read the discussions of the repositories.
+func SearchDiscussionsByKeyword(ctx context.Context, repoIDs []int64, keyword string, limit, start int) ([]int64, int64, error) {
+	indexer := holder.get()
+	if indexer == nil {
+		log.Error("SearchDiscussionsByKeyword(): unable to get indexer!")
+		return nil, 0, fmt.Errorf("unable to get discussion indexer")
+	}
+	res, err := indexer.Search(ctx, keyword, repoIDs, limit, start)
+	if err != nil {
+		return nil, 0, err
+	}
+	ids := make([]int64, 0, len(res.Hits))
+	for _, r := range res.Hits {
+		ids = append(ids, r.ID)
+	}
+	return ids, res.Total, nil
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package discussions
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	discussion_model "code.gitea.io/gitea/models/discussion"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+
+	_ "code.gitea.io/gitea/models"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMain(m *testing.M) {
+	unittest.MainTest(m, &unittest.TestOptions{
+		GiteaRootPath: filepath.Join("..", "..", ".."),
+	})
+}
+
+func TestDBSearchDiscussions(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	category := &discussion_model.Category{RepoID: 1, Name: "General", Kind: discussion_model.CategoryKindGeneral}
+	assert.NoError(t, discussion_model.CreateCategory(db.DefaultContext, category))
+	hello := &discussion_model.Discussion{RepoID: 1, CategoryID: category.ID, PosterID: 2, Title: "Hello", Content: "world"}
+	assert.NoError(t, discussion_model.NewDiscussion(db.DefaultContext, hello))
+	build := &discussion_model.Discussion{RepoID: 1, CategoryID: category.ID, PosterID: 2, Title: "How to build?", Content: "make fails"}
+	assert.NoError(t, discussion_model.NewDiscussion(db.DefaultContext, build))
+	assert.NoError(t, discussion_model.CreateComment(db.DefaultContext, build, &discussion_model.Comment{PosterID: 1, Content: "run make clean first"}))
+
+	setting.Indexer.DiscussionType = "db"
+	InitDiscussionIndexer(true)
+
+	ids, total, err := SearchDiscussionsByKeyword(context.TODO(), []int64{1}, "world", 50, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 1, total)
+	assert.EqualValues(t, []int64{hello.ID}, ids)
+
+	// replies match the keyword as well
+	ids, _, err = SearchDiscussionsByKeyword(context.TODO(), []int64{1}, "clean", 50, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, []int64{build.ID}, ids)
+
+	ids, _, err = SearchDiscussionsByKeyword(context.TODO(), []int64{2}, "world", 50, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+}
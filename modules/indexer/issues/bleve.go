@@ -11,6 +11,7 @@ import (
 
 	gitea_bleve "code.gitea.io/gitea/modules/indexer/bleve"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 
 	"github.com/blevesearch/bleve/v2"
@@ -54,10 +55,11 @@ func numericEqualityQuery(value int64, field string) *query.NumericRangeQuery {
 	return q
 }
 
-func newMatchPhraseQuery(matchPhrase, field, analyzer string) *query.MatchPhraseQuery {
+func newMatchPhraseQuery(matchPhrase, field, analyzer string, boost float64) *query.MatchPhraseQuery {
 	q := bleve.NewMatchPhraseQuery(matchPhrase)
 	q.FieldVal = field
 	q.Analyzer = analyzer
+	q.SetBoost(boost)
 	return q
 }
 
@@ -248,9 +250,9 @@ func (b *BleveIndexer) Search(ctx context.Context, keyword string, repoIDs []int
 	indexerQuery := bleve.NewConjunctionQuery(
 		bleve.NewDisjunctionQuery(repoQueries...),
 		bleve.NewDisjunctionQuery(
-			newMatchPhraseQuery(keyword, "Title", issueIndexerAnalyzer),
-			newMatchPhraseQuery(keyword, "Content", issueIndexerAnalyzer),
-			newMatchPhraseQuery(keyword, "Comments", issueIndexerAnalyzer),
+			newMatchPhraseQuery(keyword, "Title", issueIndexerAnalyzer, setting.Indexer.IssueTitleBoost),
+			newMatchPhraseQuery(keyword, "Content", issueIndexerAnalyzer, setting.Indexer.IssueContentBoost),
+			newMatchPhraseQuery(keyword, "Comments", issueIndexerAnalyzer, setting.Indexer.IssueCommentsBoost),
 		))
 	search := bleve.NewSearchRequestOptions(indexerQuery, limit, start, false)
 	search.SortBy([]string{"-_score"})
@@ -261,7 +263,8 @@ func (b *BleveIndexer) Search(ctx context.Context, keyword string, repoIDs []int
 	}
 
 	ret := SearchResult{
-		Hits: make([]Match, 0, len(result.Hits)),
+		Total: int64(result.Total),
+		Hits:  make([]Match, 0, len(result.Hits)),
 	}
 	for _, hit := range result.Hits {
 		id, err := idOfIndexerID(hit.ID)
@@ -269,7 +272,8 @@ func (b *BleveIndexer) Search(ctx context.Context, keyword string, repoIDs []int
 			return nil, err
 		}
 		ret.Hits = append(ret.Hits, Match{
-			ID: id,
+			ID:    id,
+			Score: hit.Score,
 		})
 	}
 	return &ret, nil
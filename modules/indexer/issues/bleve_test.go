@@ -85,3 +85,32 @@ func TestBleveIndexAndSearch(t *testing.T) {
 		assert.ElementsMatch(t, kw.IDs, ids)
 	}
 }
+
+func TestBleveSearchRanking(t *testing.T) {
+	dir := t.TempDir()
+	indexer := NewBleveIndexer(dir)
+	defer indexer.Close()
+
+	if _, err := indexer.Init(); err != nil {
+		assert.Fail(t, "Unable to initialize bleve indexer: %v", err)
+		return
+	}
+
+	err := indexer.Index([]*IndexerData{
+		{ID: 1, RepoID: 2, Title: "Crash on startup", Content: "Nothing else", Comments: []string{"The indexer fails"}},
+		{ID: 2, RepoID: 2, Title: "Slow startup", Content: "The indexer is slow"},
+		{ID: 3, RepoID: 2, Title: "The indexer is slow", Content: "Nothing else"},
+	})
+	assert.NoError(t, err)
+
+	// matches in the title rank above matches in the content, which rank above matches in the comments
+	res, err := indexer.Search(context.TODO(), "indexer", []int64{2}, 10, 0)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 3, res.Total)
+	if assert.Len(t, res.Hits, 3) {
+		assert.EqualValues(t, 3, res.Hits[0].ID)
+		assert.EqualValues(t, 2, res.Hits[1].ID)
+		assert.EqualValues(t, 1, res.Hits[2].ID)
+		assert.Greater(t, res.Hits[0].Score, res.Hits[1].Score)
+	}
+}
@@ -14,6 +14,7 @@ import (
 
 	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/olivere/elastic/v7"
 )
@@ -218,7 +219,10 @@ func (b *ElasticSearchIndexer) Delete(ids ...int64) error {
 // Search searches for issues by given conditions.
 // Returns the matching issue IDs
 func (b *ElasticSearchIndexer) Search(ctx context.Context, keyword string, repoIDs []int64, limit, start int) (*SearchResult, error) {
-	kwQuery := elastic.NewMultiMatchQuery(keyword, "title", "content", "comments")
+	kwQuery := elastic.NewMultiMatchQuery(keyword).
+		FieldWithBoost("title", setting.Indexer.IssueTitleBoost).
+		FieldWithBoost("content", setting.Indexer.IssueContentBoost).
+		FieldWithBoost("comments", setting.Indexer.IssueCommentsBoost)
 	query := elastic.NewBoolQuery()
 	query = query.Must(kwQuery)
 	if len(repoIDs) > 0 {
@@ -242,8 +246,13 @@ func (b *ElasticSearchIndexer) Search(ctx context.Context, keyword string, repoI
 	hits := make([]Match, 0, limit)
 	for _, hit := range searchResult.Hits.Hits {
 		id, _ := strconv.ParseInt(hit.Id, 10, 64)
+		var score float64
+		if hit.Score != nil {
+			score = *hit.Score
+		}
 		hits = append(hits, Match{
-			ID: id,
+			ID:    id,
+			Score: score,
 		})
 	}
 
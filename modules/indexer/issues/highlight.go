@@ -0,0 +1,127 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"context"
+	"html"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+)
+
+const (
+	// highlightContext is the number of bytes around a match which are included in a snippet
+	highlightContext = 60
+	// highlightMaxSnippets is the maximum number of snippets of a field
+	highlightMaxSnippets = 3
+)
+
+// Highlight contains the snippets of a field of an issue which match the search keyword,
+// the snippets are html escaped and the matching terms are wrapped in <mark> elements
+type Highlight struct {
+	// Field is "title", "content" or "comment"
+	Field string
+	// CommentID is the comment the snippets are taken from, if the field is "comment"
+	CommentID int64
+	Snippets  []string
+}
+
+// keywordRegexp returns a case insensitive regular expression matching any of the terms of the keyword
+func keywordRegexp(keyword string) *regexp.Regexp {
+	terms := strings.Fields(keyword)
+	if len(terms) == 0 {
+		return nil
+	}
+	for i, term := range terms {
+		terms[i] = regexp.QuoteMeta(term)
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(terms, "|"))
+}
+
+// alignRuneStart moves the offset backwards to the start of a rune
+func alignRuneStart(text string, offset int) int {
+	for offset > 0 && offset < len(text) && !utf8.RuneStart(text[offset]) {
+		offset--
+	}
+	return offset
+}
+
+// highlightText returns the snippets of text around the matches of re
+func highlightText(text string, re *regexp.Regexp, maxSnippets int) []string {
+	matches := re.FindAllStringIndex(text, -1)
+	if len(matches) == 0 {
+		return nil
+	}
+
+	var snippets []string
+	for i := 0; i < len(matches) && len(snippets) < maxSnippets; {
+		start := matches[i][0] - highlightContext
+		if start < 0 {
+			start = 0
+		}
+		start = alignRuneStart(text, start)
+		end := matches[i][1] + highlightContext
+
+		var sb strings.Builder
+		if start > 0 {
+			sb.WriteString("…")
+		}
+		pos := start
+		// merge the following matches which fall within the context of the snippet
+		for ; i < len(matches) && matches[i][0] < end; i++ {
+			sb.WriteString(html.EscapeString(text[pos:matches[i][0]]))
+			sb.WriteString("<mark>")
+			sb.WriteString(html.EscapeString(text[matches[i][0]:matches[i][1]]))
+			sb.WriteString("</mark>")
+			pos = matches[i][1]
+			if e := matches[i][1] + highlightContext; e > end {
+				end = e
+			}
+		}
+		if end > len(text) {
+			end = len(text)
+		}
+		end = alignRuneStart(text, end)
+		if end < pos {
+			end = pos
+		}
+		sb.WriteString(html.EscapeString(text[pos:end]))
+		if end < len(text) {
+			sb.WriteString("…")
+		}
+		snippets = append(snippets, sb.String())
+	}
+	return snippets
+}
+
+// HighlightIssues returns the snippets of the title, content and comments of the issues which match the keyword,
+// mapped by issue ID. The comments of the issues are loaded if necessary.
+func HighlightIssues(ctx context.Context, issues issues_model.IssueList, keyword string) (map[int64][]*Highlight, error) {
+	re := keywordRegexp(keyword)
+	if re == nil || len(issues) == 0 {
+		return map[int64][]*Highlight{}, nil
+	}
+	if err := issues.LoadDiscussComments(ctx); err != nil {
+		return nil, err
+	}
+
+	highlights := make(map[int64][]*Highlight, len(issues))
+	for _, issue := range issues {
+		if snippets := highlightText(issue.Title, re, 1); len(snippets) > 0 {
+			highlights[issue.ID] = append(highlights[issue.ID], &Highlight{Field: "title", Snippets: snippets})
+		}
+		if snippets := highlightText(issue.Content, re, highlightMaxSnippets); len(snippets) > 0 {
+			highlights[issue.ID] = append(highlights[issue.ID], &Highlight{Field: "content", Snippets: snippets})
+		}
+		for _, comment := range issue.Comments {
+			if snippets := highlightText(comment.Content, re, highlightMaxSnippets); len(snippets) > 0 {
+				highlights[issue.ID] = append(highlights[issue.ID], &Highlight{Field: "comment", CommentID: comment.ID, Snippets: snippets})
+			}
+		}
+	}
+	return highlights, nil
+}
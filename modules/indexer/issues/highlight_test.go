@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issues
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHighlightText(t *testing.T) {
+	re := keywordRegexp("foo bar")
+
+	assert.Nil(t, keywordRegexp("  "))
+	assert.Empty(t, highlightText("nothing to see", re, highlightMaxSnippets))
+	assert.Equal(t, []string{"a &lt;b&gt; <mark>FOO</mark> and <mark>bar</mark>"}, highlightText("a <b> FOO and bar", re, highlightMaxSnippets))
+
+	text := strings.Repeat("x ", 50) + "foo" + strings.Repeat(" y", 50) + "bar" + strings.Repeat(" é", 60) + " foo"
+	snippets := highlightText(text, re, 2)
+	if assert.Len(t, snippets, 2) {
+		assert.Equal(t, "…"+strings.Repeat("x ", 30)+"<mark>foo</mark>"+strings.Repeat(" y", 30)+"…", snippets[0])
+		assert.True(t, strings.HasPrefix(snippets[1], "…"))
+		assert.Contains(t, snippets[1], "<mark>bar</mark>")
+	}
+}
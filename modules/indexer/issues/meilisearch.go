@@ -58,7 +58,7 @@ func NewMeilisearchIndexer(url, apiKey, indexerName string) (*MeilisearchIndexer
 func (b *MeilisearchIndexer) Init() (bool, error) {
 	_, err := b.client.GetIndex(b.indexerName)
 	if err == nil {
-		return true, nil
+		return true, b.updateSearchableAttributes()
 	}
 	_, err = b.client.CreateIndex(&meilisearch.IndexConfig{
 		Uid:        b.indexerName,
@@ -69,7 +69,17 @@ func (b *MeilisearchIndexer) Init() (bool, error) {
 	}
 
 	_, err = b.client.Index(b.indexerName).UpdateFilterableAttributes(&[]string{"repo_id"})
-	return false, b.checkError(err)
+	if err != nil {
+		return false, b.checkError(err)
+	}
+	return false, b.updateSearchableAttributes()
+}
+
+// updateSearchableAttributes ranks matches in the title above matches in the content and comments,
+// meilisearch has no field boosts but uses the order of the searchable attributes
+func (b *MeilisearchIndexer) updateSearchableAttributes() error {
+	_, err := b.client.Index(b.indexerName).UpdateSearchableAttributes(&[]string{"title", "content", "comments"})
+	return b.checkError(err)
 }
 
 // Ping checks if meilisearch is available
@@ -0,0 +1,210 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package kubernetes is a minimal client of the Kubernetes API, it supports what is needed to run the jobs of Actions in pods
+package kubernetes
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// JobNameLabel is set by Kubernetes on the pods of a job
+const JobNameLabel = "job-name"
+
+// Client is a client of the Kubernetes API which works in a single namespace
+type Client struct {
+	apiURL    string
+	namespace string
+	tokenFile string
+	client    *http.Client
+}
+
+// NewClient creates a client of the API server at apiURL. The bearer token is read from tokenFile before every request,
+// because the tokens of service accounts are rotated. If caFile is given, the server certificate must be signed by it.
+func NewClient(apiURL, namespace, tokenFile, caFile string) (*Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if caFile != "" {
+		ca, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, fmt.Errorf("read CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", caFile)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	return &Client{
+		apiURL:    strings.TrimSuffix(apiURL, "/"),
+		namespace: namespace,
+		tokenFile: tokenFile,
+		client:    &http.Client{Transport: transport, Timeout: time.Minute},
+	}, nil
+}
+
+// Job is the part of a Kubernetes job which is needed to follow it
+type Job struct {
+	Metadata struct {
+		Name   string            `json:"name"`
+		Labels map[string]string `json:"labels"`
+	} `json:"metadata"`
+	Status struct {
+		Active    int `json:"active"`
+		Succeeded int `json:"succeeded"`
+		Failed    int `json:"failed"`
+	} `json:"status"`
+}
+
+// IsFinished returns whether the pod of the job has terminated
+func (j *Job) IsFinished() bool {
+	return j.Status.Succeeded > 0 || j.Status.Failed > 0
+}
+
+// StatusError is returned if the API responds with an error status
+type StatusError struct {
+	StatusCode int
+	Message    string
+}
+
+func (err *StatusError) Error() string {
+	return fmt.Sprintf("kubernetes API responded with %d: %s", err.StatusCode, err.Message)
+}
+
+func (err *StatusError) Unwrap() error {
+	if err.StatusCode == http.StatusNotFound {
+		return util.ErrNotExist
+	}
+	return nil
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, result any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	u := c.apiURL + path
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.tokenFile != "" {
+		token, err := os.ReadFile(c.tokenFile)
+		if err != nil {
+			return fmt.Errorf("read token file: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+strings.TrimSpace(string(token)))
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		status := struct {
+			Message string `json:"message"`
+		}{}
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+		if json.Unmarshal(data, &status) != nil || status.Message == "" {
+			status.Message = strings.TrimSpace(string(data))
+		}
+		return &StatusError{StatusCode: resp.StatusCode, Message: status.Message}
+	}
+
+	if result == nil {
+		return nil
+	}
+	if s, ok := result.(*string); ok {
+		data, err := io.ReadAll(resp.Body)
+		*s = string(data)
+		return err
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (c *Client) jobsPath() string {
+	return "/apis/batch/v1/namespaces/" + url.PathEscape(c.namespace) + "/jobs"
+}
+
+// CreateJob creates a job from its manifest
+func (c *Client) CreateJob(ctx context.Context, manifest map[string]any) error {
+	return c.do(ctx, http.MethodPost, c.jobsPath(), nil, manifest, nil)
+}
+
+// GetJob returns a job, the error wraps util.ErrNotExist if it doesn't exist
+func (c *Client) GetJob(ctx context.Context, name string) (*Job, error) {
+	job := &Job{}
+	if err := c.do(ctx, http.MethodGet, c.jobsPath()+"/"+url.PathEscape(name), nil, nil, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// DeleteJob deletes a job together with its pods, a job which doesn't exist is ignored
+func (c *Client) DeleteJob(ctx context.Context, name string) error {
+	body := map[string]any{"propagationPolicy": "Background"}
+	err := c.do(ctx, http.MethodDelete, c.jobsPath()+"/"+url.PathEscape(name), nil, body, nil)
+	if errors.Is(err, util.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// GetJobLogs returns the last lines of the log of a container of the newest pod of a job
+func (c *Client) GetJobLogs(ctx context.Context, name, container string, tailLines int) (string, error) {
+	podsPath := "/api/v1/namespaces/" + url.PathEscape(c.namespace) + "/pods"
+	pods := struct {
+		Items []struct {
+			Metadata struct {
+				Name              string    `json:"name"`
+				CreationTimestamp time.Time `json:"creationTimestamp"`
+			} `json:"metadata"`
+		} `json:"items"`
+	}{}
+	if err := c.do(ctx, http.MethodGet, podsPath, url.Values{"labelSelector": {JobNameLabel + "=" + name}}, nil, &pods); err != nil {
+		return "", err
+	}
+	if len(pods.Items) == 0 {
+		return "", fmt.Errorf("pods of job %s: %w", name, util.ErrNotExist)
+	}
+	newest := pods.Items[0]
+	for _, pod := range pods.Items[1:] {
+		if pod.Metadata.CreationTimestamp.After(newest.Metadata.CreationTimestamp) {
+			newest = pod
+		}
+	}
+
+	var logs string
+	query := url.Values{"container": {container}, "tailLines": {fmt.Sprint(tailLines)}}
+	if err := c.do(ctx, http.MethodGet, podsPath+"/"+url.PathEscape(newest.Metadata.Name)+"/log", query, nil, &logs); err != nil {
+		return "", err
+	}
+	return logs, nil
+}
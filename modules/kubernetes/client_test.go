@@ -0,0 +1,79 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package kubernetes
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClient(t *testing.T) {
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	assert.NoError(t, os.WriteFile(tokenFile, []byte("secret\n"), 0o600))
+
+	var created string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/apis/batch/v1/namespaces/ci/jobs":
+			body, _ := io.ReadAll(r.Body)
+			created = string(body)
+			w.WriteHeader(http.StatusCreated)
+		case r.Method == http.MethodGet && r.URL.Path == "/apis/batch/v1/namespaces/ci/jobs/job-1":
+			_, _ = w.Write([]byte(`{"metadata":{"name":"job-1"},"status":{"failed":1}}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/apis/batch/v1/namespaces/ci/jobs/job-1":
+			w.WriteHeader(http.StatusOK)
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/namespaces/ci/pods":
+			assert.Equal(t, "job-name=job-1", r.URL.Query().Get("labelSelector"))
+			_, _ = w.Write([]byte(`{"items":[{"metadata":{"name":"pod-1","creationTimestamp":"2023-05-01T10:00:00Z"}},{"metadata":{"name":"pod-2","creationTimestamp":"2023-05-01T11:00:00Z"}}]}`))
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/namespaces/ci/pods/pod-2/log":
+			assert.Equal(t, "runner", r.URL.Query().Get("container"))
+			assert.Equal(t, "10", r.URL.Query().Get("tailLines"))
+			_, _ = w.Write([]byte("runner failed\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"kind":"Status","message":"not found"}`))
+		}
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	client, err := NewClient(server.URL+"/", "ci", tokenFile, "")
+	assert.NoError(t, err)
+
+	assert.NoError(t, client.CreateJob(ctx, map[string]any{"kind": "Job"}))
+	assert.JSONEq(t, `{"kind":"Job"}`, created)
+
+	job, err := client.GetJob(ctx, "job-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "job-1", job.Metadata.Name)
+	assert.True(t, job.IsFinished())
+
+	_, err = client.GetJob(ctx, "job-2")
+	assert.True(t, errors.Is(err, util.ErrNotExist))
+	var statusErr *StatusError
+	if assert.True(t, errors.As(err, &statusErr)) {
+		assert.Equal(t, "not found", statusErr.Message)
+	}
+
+	assert.NoError(t, client.DeleteJob(ctx, "job-1"))
+	assert.NoError(t, client.DeleteJob(ctx, "job-2"))
+
+	logs, err := client.GetJobLogs(ctx, "job-1", "runner", 10)
+	assert.NoError(t, err)
+	assert.Equal(t, "runner failed\n", logs)
+}
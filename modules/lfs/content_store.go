@@ -8,10 +8,15 @@ import (
 	"errors"
 	"hash"
 	"io"
+	"net/http"
+	"net/url"
 	"os"
+	"path"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/minio/sha256-simd"
 )
@@ -23,6 +28,15 @@ var (
 	ErrSizeMismatch = errors.New("content size does not match")
 )
 
+const (
+	// DirectUploadDir is the directory of the content store in which the objects clients upload with presigned URLs
+	// are staged until their upload is verified
+	DirectUploadDir = "tmp/direct-upload"
+
+	// maxDirectUploadSize is the size of the largest object S3 compatible storages accept in a single PUT request
+	maxDirectUploadSize = 5 * 1024 * 1024 * 1024
+)
+
 // ContentStore provides a simple file system based storage.
 type ContentStore struct {
 	storage.ObjectStorage
@@ -34,6 +48,11 @@ func NewContentStore() *ContentStore {
 	return contentStore
 }
 
+// NewContentStoreOfRegion creates the ContentStore of a storage region
+func NewContentStoreOfRegion(region string) *ContentStore {
+	return &ContentStore{ObjectStorage: storage.LFSOfRegion(region)}
+}
+
 // Get takes a Meta object and retrieves the content from the store, returning
 // it as an io.ReadSeekCloser.
 func (s *ContentStore) Get(pointer Pointer) (storage.Object, error) {
@@ -104,6 +123,65 @@ func (s *ContentStore) Verify(pointer Pointer) (bool, error) {
 	return true, nil
 }
 
+// DirectUploadURL returns a presigned URL the client uploads the content of the pointer to, the headers the upload
+// has to send and the path the upload is staged at. The storage verifies the content against the oid.
+// storage.ErrURLNotSupported is returned if the storage doesn't accept direct uploads.
+func (s *ContentStore) DirectUploadURL(pointer Pointer, expiry time.Duration) (*url.URL, http.Header, string, error) {
+	uploader, ok := s.ObjectStorage.(storage.DirectUploader)
+	if !ok || pointer.Size > maxDirectUploadSize {
+		return nil, nil, "", storage.ErrURLNotSupported
+	}
+
+	sum, err := hex.DecodeString(pointer.Oid)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	suffix, err := util.CryptoRandomString(16)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	// every upload is staged separately, so concurrent uploads of an object can't interfere
+	stagingPath := path.Join(DirectUploadDir, pointer.Oid+"-"+suffix)
+
+	u, header, err := uploader.UploadURL(stagingPath, sum, expiry)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return u, header, stagingPath, nil
+}
+
+// FinishDirectUpload moves an object uploaded with DirectUploadURL from its staging path into the content store.
+// It returns false if the staged object doesn't exist or doesn't have the size of the pointer.
+func (s *ContentStore) FinishDirectUpload(pointer Pointer, stagingPath string) (bool, error) {
+	uploader, ok := s.ObjectStorage.(storage.DirectUploader)
+	if !ok {
+		return false, storage.ErrURLNotSupported
+	}
+
+	fi, err := s.ObjectStorage.Stat(stagingPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	} else if err != nil {
+		return false, err
+	}
+	if fi.Size() != pointer.Size {
+		if err := s.Delete(stagingPath); err != nil {
+			log.Error("Cleaning the staged upload of LFS OID[%s] failed: %v", pointer.Oid, err)
+		}
+		return false, nil
+	}
+
+	exists, err := s.Exists(pointer)
+	if err != nil {
+		return false, err
+	}
+	if exists {
+		// the content was uploaded concurrently, the storage verified both uploads have the same content
+		return true, s.Delete(stagingPath)
+	}
+	return true, uploader.Move(stagingPath, pointer.RelativePath())
+}
+
 // ReadMetaObject will read a git_model.LFSMetaObject and return a reader
 func ReadMetaObject(pointer Pointer) (io.ReadSeekCloser, error) {
 	contentStore := NewContentStore()
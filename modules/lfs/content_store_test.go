@@ -0,0 +1,100 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package lfs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/storage"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// directUploadStorage accepts direct uploads like an S3 storage, it records the checksums of the presigned URLs
+type directUploadStorage struct {
+	storage.ObjectStorage
+	checksums map[string][]byte
+}
+
+func (s *directUploadStorage) UploadURL(path string, sha256sum []byte, expiry time.Duration) (*url.URL, http.Header, error) {
+	s.checksums[path] = sha256sum
+	return &url.URL{Scheme: "https", Host: "s3.example.com", Path: "/" + path}, http.Header{"X-Amz-Checksum-Sha256": []string{"checksum"}}, nil
+}
+
+func (s *directUploadStorage) Move(srcPath, dstPath string) error {
+	if _, err := storage.Copy(s.ObjectStorage, dstPath, s.ObjectStorage, srcPath); err != nil {
+		return err
+	}
+	return s.Delete(srcPath)
+}
+
+func TestContentStoreDirectUpload(t *testing.T) {
+	local, err := storage.NewLocalStorage(context.Background(), storage.LocalStorageConfig{Path: t.TempDir()})
+	assert.NoError(t, err)
+
+	content := "gitea lfs content"
+	sum := sha256.Sum256([]byte(content))
+	p := Pointer{Oid: hex.EncodeToString(sum[:]), Size: int64(len(content))}
+
+	_, _, _, err = (&ContentStore{ObjectStorage: local}).DirectUploadURL(p, time.Hour)
+	assert.ErrorIs(t, err, storage.ErrURLNotSupported)
+
+	s := &directUploadStorage{ObjectStorage: local, checksums: map[string][]byte{}}
+	cs := &ContentStore{ObjectStorage: s}
+
+	_, _, _, err = cs.DirectUploadURL(Pointer{Oid: p.Oid, Size: maxDirectUploadSize + 1}, time.Hour)
+	assert.ErrorIs(t, err, storage.ErrURLNotSupported)
+
+	u, header, stagingPath, err := cs.DirectUploadURL(p, time.Hour)
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(stagingPath, DirectUploadDir+"/"+p.Oid+"-"))
+	assert.Equal(t, "/"+stagingPath, u.Path)
+	assert.Equal(t, "checksum", header.Get("X-Amz-Checksum-Sha256"))
+	assert.Equal(t, sum[:], s.checksums[stagingPath])
+
+	// the upload wasn't done yet
+	ok, err := cs.FinishDirectUpload(p, stagingPath)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+
+	// uploads with a wrong size are discarded
+	_, err = s.Save(stagingPath, strings.NewReader("gitea"), 5)
+	assert.NoError(t, err)
+	ok, err = cs.FinishDirectUpload(p, stagingPath)
+	assert.NoError(t, err)
+	assert.False(t, ok)
+	_, err = s.Stat(stagingPath)
+	assert.Error(t, err)
+
+	_, err = s.Save(stagingPath, strings.NewReader(content), p.Size)
+	assert.NoError(t, err)
+	ok, err = cs.FinishDirectUpload(p, stagingPath)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+
+	exists, err := cs.Exists(p)
+	assert.NoError(t, err)
+	assert.True(t, exists)
+	_, err = s.Stat(stagingPath)
+	assert.Error(t, err)
+
+	// a concurrent upload of the same object only removes its staged object
+	_, _, otherStagingPath, err := cs.DirectUploadURL(p, time.Hour)
+	assert.NoError(t, err)
+	assert.NotEqual(t, stagingPath, otherStagingPath)
+	_, err = s.Save(otherStagingPath, strings.NewReader(content), p.Size)
+	assert.NoError(t, err)
+	ok, err = cs.FinishDirectUpload(p, otherStagingPath)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	_, err = s.Stat(otherStagingPath)
+	assert.Error(t, err)
+}
@@ -7,6 +7,7 @@ import (
 	"runtime"
 
 	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/setting"
 
 	"github.com/prometheus/client_golang/prometheus"
@@ -23,6 +24,14 @@ type Collector struct {
 	BuildInfo          *prometheus.Desc
 	Comments           *prometheus.Desc
 	Follows            *prometheus.Desc
+	GitCommands        *prometheus.Desc
+	GitCommandsLimit   *prometheus.Desc
+	GitCommandsStarted *prometheus.Desc
+	GitCommandsWait    *prometheus.Desc
+	GitHTTPRequests    *prometheus.Desc
+	GitHTTPReceived    *prometheus.Desc
+	GitHTTPSent        *prometheus.Desc
+	GitHTTPDuration    *prometheus.Desc
 	HookTasks          *prometheus.Desc
 	Issues             *prometheus.Desc
 	IssuesOpen         *prometheus.Desc
@@ -86,6 +95,46 @@ func NewCollector() Collector {
 			"Number of Follows",
 			nil, nil,
 		),
+		GitCommands: prometheus.NewDesc(
+			namespace+"git_scheduler_commands",
+			"Number of scheduled git commands",
+			[]string{"state"}, nil,
+		),
+		GitCommandsLimit: prometheus.NewDesc(
+			namespace+"git_scheduler_limit",
+			"Current limit of concurrent scheduled git commands",
+			nil, nil,
+		),
+		GitCommandsStarted: prometheus.NewDesc(
+			namespace+"git_scheduler_commands_total",
+			"Number of scheduled git commands which were started or rejected",
+			[]string{"result"}, nil,
+		),
+		GitCommandsWait: prometheus.NewDesc(
+			namespace+"git_scheduler_wait_seconds_total",
+			"Total time scheduled git commands waited before they were started",
+			nil, nil,
+		),
+		GitHTTPRequests: prometheus.NewDesc(
+			namespace+"git_http_requests_total",
+			"Number of git upload-pack and receive-pack requests served over HTTP",
+			[]string{"service", "size_class", "protocol", "result"}, nil,
+		),
+		GitHTTPReceived: prometheus.NewDesc(
+			namespace+"git_http_received_bytes_total",
+			"Bytes received by git upload-pack and receive-pack requests served over HTTP",
+			[]string{"service", "size_class", "protocol", "result"}, nil,
+		),
+		GitHTTPSent: prometheus.NewDesc(
+			namespace+"git_http_sent_bytes_total",
+			"Bytes sent by git upload-pack and receive-pack requests served over HTTP",
+			[]string{"service", "size_class", "protocol", "result"}, nil,
+		),
+		GitHTTPDuration: prometheus.NewDesc(
+			namespace+"git_http_duration_seconds_total",
+			"Total duration of git upload-pack and receive-pack requests served over HTTP",
+			[]string{"service", "size_class", "protocol", "result"}, nil,
+		),
 		HookTasks: prometheus.NewDesc(
 			namespace+"hooktasks",
 			"Number of HookTasks",
@@ -212,6 +261,14 @@ func (c Collector) Describe(ch chan<- *prometheus.Desc) {
 	ch <- c.BuildInfo
 	ch <- c.Comments
 	ch <- c.Follows
+	ch <- c.GitCommands
+	ch <- c.GitCommandsLimit
+	ch <- c.GitCommandsStarted
+	ch <- c.GitCommandsWait
+	ch <- c.GitHTTPRequests
+	ch <- c.GitHTTPReceived
+	ch <- c.GitHTTPSent
+	ch <- c.GitHTTPDuration
 	ch <- c.HookTasks
 	ch <- c.Issues
 	ch <- c.IssuesByLabel
@@ -396,4 +453,69 @@ func (c Collector) Collect(ch chan<- prometheus.Metric) {
 		prometheus.GaugeValue,
 		float64(stats.Counter.Webhook),
 	)
+
+	if gitStats, ok := git.GetSchedulerStats(); ok {
+		ch <- prometheus.MustNewConstMetric(
+			c.GitCommands,
+			prometheus.GaugeValue,
+			float64(gitStats.Running),
+			"running",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.GitCommands,
+			prometheus.GaugeValue,
+			float64(gitStats.Waiting),
+			"waiting",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.GitCommandsLimit,
+			prometheus.GaugeValue,
+			float64(gitStats.Limit),
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.GitCommandsStarted,
+			prometheus.CounterValue,
+			float64(gitStats.Started),
+			"started",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.GitCommandsStarted,
+			prometheus.CounterValue,
+			float64(gitStats.Rejected),
+			"rejected",
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.GitCommandsWait,
+			prometheus.CounterValue,
+			gitStats.WaitTotal.Seconds(),
+		)
+	}
+
+	for _, stat := range GetGitHTTPStats() {
+		labels := []string{stat.Service, stat.SizeClass, stat.Protocol, stat.Result}
+		ch <- prometheus.MustNewConstMetric(
+			c.GitHTTPRequests,
+			prometheus.CounterValue,
+			float64(stat.Requests),
+			labels...,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.GitHTTPReceived,
+			prometheus.CounterValue,
+			float64(stat.ReceivedBytes),
+			labels...,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.GitHTTPSent,
+			prometheus.CounterValue,
+			float64(stat.SentBytes),
+			labels...,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			c.GitHTTPDuration,
+			prometheus.CounterValue,
+			stat.Duration.Seconds(),
+			labels...,
+		)
+	}
 }
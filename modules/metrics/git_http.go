@@ -0,0 +1,80 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// Results of the git upload-pack and receive-pack commands served over HTTP
+const (
+	GitHTTPResultOK       = "ok"
+	GitHTTPResultTimeout  = "timeout"
+	GitHTTPResultTooLarge = "too_large"
+	GitHTTPResultBusy     = "busy"
+	GitHTTPResultError    = "error"
+)
+
+// GitHTTPKey identifies the requests of the git smart HTTP protocol a GitHTTPStat is aggregated over
+type GitHTTPKey struct {
+	Service   string
+	SizeClass string
+	Protocol  string
+	Result    string
+}
+
+// GitHTTPStat are the totals of the requests of the git smart HTTP protocol with the same key
+type GitHTTPStat struct {
+	GitHTTPKey
+	Requests      int64
+	ReceivedBytes int64
+	SentBytes     int64
+	Duration      time.Duration
+}
+
+var gitHTTPStats = struct {
+	mu    sync.Mutex
+	stats map[GitHTTPKey]*GitHTTPStat
+}{stats: make(map[GitHTTPKey]*GitHTTPStat)}
+
+// RecordGitHTTPRequest adds a served git upload-pack or receive-pack request to the metrics
+func RecordGitHTTPRequest(key GitHTTPKey, received, sent int64, duration time.Duration) {
+	gitHTTPStats.mu.Lock()
+	defer gitHTTPStats.mu.Unlock()
+
+	stat, ok := gitHTTPStats.stats[key]
+	if !ok {
+		stat = &GitHTTPStat{GitHTTPKey: key}
+		gitHTTPStats.stats[key] = stat
+	}
+	stat.Requests++
+	stat.ReceivedBytes += received
+	stat.SentBytes += sent
+	stat.Duration += duration
+}
+
+// GetGitHTTPStats returns the totals of the served git upload-pack and receive-pack requests
+func GetGitHTTPStats() []GitHTTPStat {
+	gitHTTPStats.mu.Lock()
+	stats := make([]GitHTTPStat, 0, len(gitHTTPStats.stats))
+	for _, stat := range gitHTTPStats.stats {
+		stats = append(stats, *stat)
+	}
+	gitHTTPStats.mu.Unlock()
+
+	sort.Slice(stats, func(i, j int) bool {
+		a, b := stats[i].GitHTTPKey, stats[j].GitHTTPKey
+		if a.Service != b.Service {
+			return a.Service < b.Service
+		} else if a.SizeClass != b.SizeClass {
+			return a.SizeClass < b.SizeClass
+		} else if a.Protocol != b.Protocol {
+			return a.Protocol < b.Protocol
+		}
+		return a.Result < b.Result
+	})
+	return stats
+}
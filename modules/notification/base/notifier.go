@@ -6,6 +6,8 @@ package base
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
+	discussion_model "code.gitea.io/gitea/models/discussion"
 	issues_model "code.gitea.io/gitea/models/issues"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -62,4 +64,14 @@ type Notifier interface {
 	NotifyRepoPendingTransfer(ctx context.Context, doer, newOwner *user_model.User, repo *repo_model.Repository)
 	NotifyPackageCreate(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor)
 	NotifyPackageDelete(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor)
+	NotifyNewDiscussion(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion)
+	NotifyDiscussionChangeContent(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion)
+	NotifyDiscussionChangeLock(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion)
+	NotifyDiscussionChangeAnswer(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion)
+	NotifyDeleteDiscussion(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion)
+	NotifyCreateDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment)
+	NotifyUpdateDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment)
+	NotifyDeleteDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment)
+	NotifyActionRunNeedApproval(ctx context.Context, run *actions_model.ActionRun)
+	NotifyActionRunApproved(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun)
 }
@@ -6,6 +6,8 @@ package base
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
+	discussion_model "code.gitea.io/gitea/models/discussion"
 	issues_model "code.gitea.io/gitea/models/issues"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -199,3 +201,43 @@ func (*NullNotifier) NotifyPackageCreate(ctx context.Context, doer *user_model.U
 // NotifyPackageDelete places a place holder function
 func (*NullNotifier) NotifyPackageDelete(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor) {
 }
+
+// NotifyNewDiscussion places a place holder function
+func (*NullNotifier) NotifyNewDiscussion(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+}
+
+// NotifyDiscussionChangeContent places a place holder function
+func (*NullNotifier) NotifyDiscussionChangeContent(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+}
+
+// NotifyDiscussionChangeLock places a place holder function
+func (*NullNotifier) NotifyDiscussionChangeLock(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+}
+
+// NotifyDiscussionChangeAnswer places a place holder function
+func (*NullNotifier) NotifyDiscussionChangeAnswer(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+}
+
+// NotifyDeleteDiscussion places a place holder function
+func (*NullNotifier) NotifyDeleteDiscussion(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+}
+
+// NotifyCreateDiscussionComment places a place holder function
+func (*NullNotifier) NotifyCreateDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) {
+}
+
+// NotifyUpdateDiscussionComment places a place holder function
+func (*NullNotifier) NotifyUpdateDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) {
+}
+
+// NotifyDeleteDiscussionComment places a place holder function
+func (*NullNotifier) NotifyDeleteDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) {
+}
+
+// NotifyActionRunNeedApproval places a place holder function
+func (*NullNotifier) NotifyActionRunNeedApproval(ctx context.Context, run *actions_model.ActionRun) {
+}
+
+// NotifyActionRunApproved places a place holder function
+func (*NullNotifier) NotifyActionRunApproved(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun) {
+}
@@ -6,11 +6,13 @@ package indexer
 import (
 	"context"
 
+	discussion_model "code.gitea.io/gitea/models/discussion"
 	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/git"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	discussion_indexer "code.gitea.io/gitea/modules/indexer/discussions"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
 	stats_indexer "code.gitea.io/gitea/modules/indexer/stats"
 	"code.gitea.io/gitea/modules/log"
@@ -110,6 +112,7 @@ func (r *indexerNotifier) NotifyDeleteComment(ctx context.Context, doer *user_mo
 
 func (r *indexerNotifier) NotifyDeleteRepository(ctx context.Context, doer *user_model.User, repo *repo_model.Repository) {
 	issue_indexer.DeleteRepoIssueIndexer(ctx, repo)
+	discussion_indexer.DeleteRepoDiscussionIndexer(ctx, repo)
 	if setting.Indexer.RepoIndexerEnabled {
 		code_indexer.UpdateRepoIndexer(repo)
 	}
@@ -154,3 +157,27 @@ func (r *indexerNotifier) NotifyIssueChangeTitle(ctx context.Context, doer *user
 func (r *indexerNotifier) NotifyIssueChangeRef(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, oldRef string) {
 	issue_indexer.UpdateIssueIndexer(issue)
 }
+
+func (r *indexerNotifier) NotifyNewDiscussion(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	discussion_indexer.UpdateDiscussionIndexer(ctx, d)
+}
+
+func (r *indexerNotifier) NotifyDiscussionChangeContent(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	discussion_indexer.UpdateDiscussionIndexer(ctx, d)
+}
+
+func (r *indexerNotifier) NotifyDeleteDiscussion(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	discussion_indexer.DeleteDiscussionIndexer(d.ID)
+}
+
+func (r *indexerNotifier) NotifyCreateDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) {
+	discussion_indexer.UpdateDiscussionIndexer(ctx, d)
+}
+
+func (r *indexerNotifier) NotifyUpdateDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) {
+	discussion_indexer.UpdateDiscussionIndexer(ctx, d)
+}
+
+func (r *indexerNotifier) NotifyDeleteDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) {
+	discussion_indexer.UpdateDiscussionIndexer(ctx, d)
+}
@@ -6,6 +6,8 @@ package notification
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
+	discussion_model "code.gitea.io/gitea/models/discussion"
 	issues_model "code.gitea.io/gitea/models/issues"
 	packages_model "code.gitea.io/gitea/models/packages"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -370,3 +372,73 @@ func NotifyPackageDelete(ctx context.Context, doer *user_model.User, pd *package
 		notifier.NotifyPackageDelete(ctx, doer, pd)
 	}
 }
+
+// NotifyNewDiscussion notifies new discussion to notifiers
+func NotifyNewDiscussion(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	for _, notifier := range notifiers {
+		notifier.NotifyNewDiscussion(ctx, doer, d)
+	}
+}
+
+// NotifyDiscussionChangeContent notifies a changed title, content or category of a discussion to notifiers
+func NotifyDiscussionChangeContent(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	for _, notifier := range notifiers {
+		notifier.NotifyDiscussionChangeContent(ctx, doer, d)
+	}
+}
+
+// NotifyDiscussionChangeLock notifies a locked or unlocked discussion to notifiers
+func NotifyDiscussionChangeLock(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	for _, notifier := range notifiers {
+		notifier.NotifyDiscussionChangeLock(ctx, doer, d)
+	}
+}
+
+// NotifyDiscussionChangeAnswer notifies an accepted or removed answer of a question to notifiers
+func NotifyDiscussionChangeAnswer(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	for _, notifier := range notifiers {
+		notifier.NotifyDiscussionChangeAnswer(ctx, doer, d)
+	}
+}
+
+// NotifyDeleteDiscussion notifies deleted discussion to notifiers
+func NotifyDeleteDiscussion(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	for _, notifier := range notifiers {
+		notifier.NotifyDeleteDiscussion(ctx, doer, d)
+	}
+}
+
+// NotifyCreateDiscussionComment notifies a reply to a discussion to notifiers
+func NotifyCreateDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) {
+	for _, notifier := range notifiers {
+		notifier.NotifyCreateDiscussionComment(ctx, doer, d, c)
+	}
+}
+
+// NotifyUpdateDiscussionComment notifies an edited reply to a discussion to notifiers
+func NotifyUpdateDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) {
+	for _, notifier := range notifiers {
+		notifier.NotifyUpdateDiscussionComment(ctx, doer, d, c)
+	}
+}
+
+// NotifyDeleteDiscussionComment notifies a deleted reply to a discussion to notifiers
+func NotifyDeleteDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) {
+	for _, notifier := range notifiers {
+		notifier.NotifyDeleteDiscussionComment(ctx, doer, d, c)
+	}
+}
+
+// NotifyActionRunNeedApproval notifies a workflow run waiting for the approval of a maintainer to notifiers
+func NotifyActionRunNeedApproval(ctx context.Context, run *actions_model.ActionRun) {
+	for _, notifier := range notifiers {
+		notifier.NotifyActionRunNeedApproval(ctx, run)
+	}
+}
+
+// NotifyActionRunApproved notifies an approved workflow run to notifiers
+func NotifyActionRunApproved(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun) {
+	for _, notifier := range notifiers {
+		notifier.NotifyActionRunApproved(ctx, doer, run)
+	}
+}
@@ -135,6 +135,29 @@ func parseOCIImageConfig(r io.Reader) (*Metadata, error) {
 	return metadata, nil
 }
 
+// ApplyAnnotations updates the metadata with the annotations of the image manifest or index,
+// the annotations take precedence over the labels of the image config
+func (m *Metadata) ApplyAnnotations(annotations map[string]string) {
+	if v := annotations[labelDescription]; v != "" {
+		m.Description = v
+	}
+	if v := annotations[labelLicenses]; v != "" {
+		m.Licenses = v
+	}
+	if v := annotations[labelAuthors]; v != "" {
+		m.Authors = []string{v}
+	}
+	if v := annotations[labelURL]; validation.IsValidURL(v) {
+		m.ProjectURL = v
+	}
+	if v := annotations[labelSource]; validation.IsValidURL(v) {
+		m.RepositoryURL = v
+	}
+	if v := annotations[labelDocumentation]; validation.IsValidURL(v) {
+		m.DocumentationURL = v
+	}
+}
+
 func parseHelmConfig(r io.Reader) (*Metadata, error) {
 	var config helm.Metadata
 	if err := json.NewDecoder(r).Decode(&config); err != nil {
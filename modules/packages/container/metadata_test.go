@@ -59,3 +59,23 @@ func TestParseImageConfig(t *testing.T) {
 	assert.Equal(t, projectURL, metadata.ProjectURL)
 	assert.Equal(t, repositoryURL, metadata.RepositoryURL)
 }
+
+func TestApplyAnnotations(t *testing.T) {
+	metadata := &Metadata{
+		Description: "Label Description",
+		Licenses:    "MIT",
+		ProjectURL:  "https://gitea.io",
+	}
+
+	metadata.ApplyAnnotations(map[string]string{
+		labelDescription: "# Annotation Description",
+		labelURL:         "invalid",
+		labelSource:      "https://gitea.com/gitea",
+	})
+
+	assert.Equal(t, "# Annotation Description", metadata.Description)
+	assert.Equal(t, "MIT", metadata.Licenses)
+	assert.Equal(t, "https://gitea.io", metadata.ProjectURL)
+	assert.Equal(t, "https://gitea.com/gitea", metadata.RepositoryURL)
+	assert.Empty(t, metadata.DocumentationURL)
+}
@@ -26,6 +26,11 @@ func NewContentStore() *ContentStore {
 	return contentStore
 }
 
+// NewContentStoreOfRegion creates the package store of a storage region
+func NewContentStoreOfRegion(region string) *ContentStore {
+	return &ContentStore{storage.PackagesOfRegion(region)}
+}
+
 // Get gets a package blob
 func (s *ContentStore) Get(key BlobHash256Key) (storage.Object, error) {
 	return s.store.Open(KeyToRelativePath(key))
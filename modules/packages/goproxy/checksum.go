@@ -0,0 +1,90 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package goproxy
+
+import (
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+// PropertyChecksumStatus stores the result of the last verification of a module against the checksum database
+const PropertyChecksumStatus = "go.checksum.status"
+
+// ChecksumStatus is the result of the verification of a module against the checksum database
+type ChecksumStatus string
+
+const (
+	// ChecksumStatusVerified means the module matches the checksum database
+	ChecksumStatusVerified ChecksumStatus = "verified"
+	// ChecksumStatusMismatch means the module differs from the module known to the checksum database
+	ChecksumStatusMismatch ChecksumStatus = "mismatch"
+	// ChecksumStatusUnknown means the module is not known to the checksum database
+	ChecksumStatusUnknown ChecksumStatus = "unknown"
+	// ChecksumStatusPrivate means the module path is private and is not looked up in the checksum database
+	ChecksumStatusPrivate ChecksumStatus = "private"
+)
+
+// HashZip returns the hash of a module zip file in the "h1:" format used by go.sum and the checksum database
+func HashZip(r io.ReaderAt, size int64) (string, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return "", err
+	}
+
+	files := make([]string, 0, len(archive.File))
+	zfiles := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files = append(files, file.Name)
+		zfiles[file.Name] = file
+	}
+	return dirhash.Hash1(files, func(name string) (io.ReadCloser, error) {
+		file, ok := zfiles[name]
+		if !ok {
+			return nil, fmt.Errorf("file %q not found in zip", name)
+		}
+		return file.Open()
+	})
+}
+
+// IsPrivateModule returns true if the module path can't be known to a public checksum database,
+// either because it doesn't start with a domain name or because it matches one of the private patterns
+func IsPrivateModule(modulePath string, privatePatterns []string) bool {
+	first, _, _ := strings.Cut(modulePath, "/")
+	if !strings.Contains(first, ".") {
+		return true
+	}
+	return module.MatchPrefixPatterns(strings.Join(privatePatterns, ","), modulePath)
+}
+
+// LookupPath returns the path of the checksum database lookup endpoint of a module version
+func LookupPath(modulePath, version string) (string, error) {
+	escapedPath, err := module.EscapePath(modulePath)
+	if err != nil {
+		return "", err
+	}
+	escapedVersion, err := module.EscapeVersion(version)
+	if err != nil {
+		return "", err
+	}
+	return "/lookup/" + escapedPath + "@" + escapedVersion, nil
+}
+
+// ParseLookupZipHash returns the hash of the module zip from the response of the checksum database lookup endpoint
+func ParseLookupZipHash(modulePath, version string, data []byte) (string, bool) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 3 && fields[0] == modulePath && fields[1] == version {
+			return fields[2], true
+		}
+	}
+	return "", false
+}
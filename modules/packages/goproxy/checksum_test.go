@@ -0,0 +1,65 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package goproxy
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/mod/sumdb/dirhash"
+)
+
+func TestHashZip(t *testing.T) {
+	var data bytes.Buffer
+	zw := zip.NewWriter(&data)
+	w, _ := zw.Create(packageName + "@" + packageVersion + "/go.mod")
+	w.Write([]byte("module " + packageName))
+	w, _ = zw.Create(packageName + "@" + packageVersion + "/main.go")
+	w.Write([]byte("package main"))
+	zw.Close()
+
+	hash, err := HashZip(bytes.NewReader(data.Bytes()), int64(data.Len()))
+	assert.NoError(t, err)
+
+	name := filepath.Join(t.TempDir(), "module.zip")
+	assert.NoError(t, os.WriteFile(name, data.Bytes(), 0o644))
+	expected, err := dirhash.HashZip(name, dirhash.Hash1)
+	assert.NoError(t, err)
+	assert.Equal(t, expected, hash)
+}
+
+func TestIsPrivateModule(t *testing.T) {
+	assert.True(t, IsPrivateModule("example", nil))
+	assert.True(t, IsPrivateModule("gitea.io/private/module", []string{"gitea.io/private"}))
+	assert.True(t, IsPrivateModule("corp.example.com/module", []string{"*.example.com"}))
+	assert.False(t, IsPrivateModule("gitea.io/gitea", []string{"gitea.io/private"}))
+}
+
+func TestLookupPath(t *testing.T) {
+	p, err := LookupPath("github.com/Azure/azure-sdk-for-go", "v1.0.0")
+	assert.NoError(t, err)
+	assert.Equal(t, "/lookup/github.com/!azure/azure-sdk-for-go@v1.0.0", p)
+}
+
+func TestParseLookupZipHash(t *testing.T) {
+	data := []byte(`12345
+golang.org/x/text v0.3.0 h1:g61tztE5qeGQ89tm6NTjjM9VPIm088od1l6aSorWRWg=
+golang.org/x/text v0.3.0/go.mod h1:NqM8EUOU14njkJ3fqMW+pc6Ldnwhi/IjpwHt7yyuwOQ=
+
+go.sum database tree
+123
+abc=
+`)
+
+	hash, found := ParseLookupZipHash("golang.org/x/text", "v0.3.0", data)
+	assert.True(t, found)
+	assert.Equal(t, "h1:g61tztE5qeGQ89tm6NTjjM9VPIm088od1l6aSorWRWg=", hash)
+
+	_, found = ParseLookupZipHash("golang.org/x/text", "v0.4.0", data)
+	assert.False(t, found)
+}
@@ -0,0 +1,234 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package npm
+
+import (
+	"errors"
+	"hash/fnv"
+	"strings"
+
+	"github.com/hashicorp/go-version"
+)
+
+// Severities of advisories as used by npm audit
+const (
+	SeverityInfo     = "info"
+	SeverityLow      = "low"
+	SeverityModerate = "moderate"
+	SeverityHigh     = "high"
+	SeverityCritical = "critical"
+)
+
+// IsValidSeverity returns if the severity is known to npm audit
+func IsValidSeverity(severity string) bool {
+	switch severity {
+	case SeverityInfo, SeverityLow, SeverityModerate, SeverityHigh, SeverityCritical:
+		return true
+	}
+	return false
+}
+
+// Advisory is an advisory in the response of the bulk advisory endpoint used by npm audit
+type Advisory struct {
+	ID                 int64    `json:"id"`
+	URL                string   `json:"url"`
+	Title              string   `json:"title"`
+	Severity           string   `json:"severity"`
+	VulnerableVersions string   `json:"vulnerable_versions"`
+	CWE                []string `json:"cwe"`
+}
+
+// ErrInvalidVersionRange indicates a version range which is not supported
+var ErrInvalidVersionRange = errors.New("version range is invalid")
+
+type versionComparator struct {
+	op      string
+	version *version.Version
+}
+
+func (c versionComparator) matches(v *version.Version) bool {
+	cmp := v.Compare(c.version)
+	switch c.op {
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	}
+	return cmp == 0
+}
+
+// VersionRange is a range of vulnerable versions in the subset of the npm range syntax used by advisories:
+// comparators like ">=1.0.0 <1.2.3" separated by spaces all have to match, alternatives are separated by "||"
+// and "*" matches every version.
+type VersionRange [][]versionComparator
+
+// ParseVersionRange parses a range of vulnerable versions
+func ParseVersionRange(s string) (VersionRange, error) {
+	var r VersionRange
+	for _, alternative := range strings.Split(s, "||") {
+		fields := strings.Fields(alternative)
+		if len(fields) == 0 {
+			return nil, ErrInvalidVersionRange
+		}
+		comparators := make([]versionComparator, 0, len(fields))
+		for _, field := range fields {
+			if field == "*" {
+				continue
+			}
+			op := ""
+			for _, prefix := range []string{"<=", ">=", "<", ">", "="} {
+				if strings.HasPrefix(field, prefix) {
+					op = prefix
+					break
+				}
+			}
+			v, err := version.NewSemver(strings.TrimPrefix(field[len(op):], "v"))
+			if err != nil {
+				return nil, ErrInvalidVersionRange
+			}
+			comparators = append(comparators, versionComparator{op: op, version: v})
+		}
+		r = append(r, comparators)
+	}
+	return r, nil
+}
+
+// Matches returns if the version is in the range
+func (r VersionRange) Matches(v *version.Version) bool {
+	for _, comparators := range r {
+		matches := true
+		for _, c := range comparators {
+			if !c.matches(v) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// OSVVulnerability is the subset of a vulnerability of the OSV schema used to create advisories
+type OSVVulnerability struct {
+	ID         string `json:"id"`
+	Summary    string `json:"summary"`
+	Withdrawn  string `json:"withdrawn"`
+	References []struct {
+		Type string `json:"type"`
+		URL  string `json:"url"`
+	} `json:"references"`
+	Affected []struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		Ranges []struct {
+			Type   string `json:"type"`
+			Events []struct {
+				Introduced   string `json:"introduced"`
+				Fixed        string `json:"fixed"`
+				LastAffected string `json:"last_affected"`
+			} `json:"events"`
+		} `json:"ranges"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		Severity string   `json:"severity"`
+		CWEIDs   []string `json:"cwe_ids"`
+	} `json:"database_specific"`
+}
+
+// AdvisoryFromOSV converts an OSV vulnerability of a package into an advisory, it returns nil if the
+// vulnerability is withdrawn or has no semver range of affected versions of the package
+func AdvisoryFromOSV(packageName string, vuln *OSVVulnerability) *Advisory {
+	if vuln.Withdrawn != "" {
+		return nil
+	}
+
+	var ranges []string
+	for _, affected := range vuln.Affected {
+		if affected.Package.Ecosystem != "npm" || affected.Package.Name != packageName {
+			continue
+		}
+		for _, r := range affected.Ranges {
+			if r.Type != "SEMVER" {
+				continue
+			}
+			introduced := ""
+			for _, event := range r.Events {
+				switch {
+				case event.Introduced != "":
+					introduced = event.Introduced
+				case event.Fixed != "":
+					ranges = append(ranges, osvRange(introduced, "<"+event.Fixed))
+					introduced = ""
+				case event.LastAffected != "":
+					ranges = append(ranges, osvRange(introduced, "<="+event.LastAffected))
+					introduced = ""
+				}
+			}
+			if introduced != "" {
+				ranges = append(ranges, osvRange(introduced, ""))
+			}
+		}
+	}
+	if len(ranges) == 0 {
+		return nil
+	}
+
+	severity := strings.ToLower(vuln.DatabaseSpecific.Severity)
+	if !IsValidSeverity(severity) {
+		severity = SeverityModerate
+	}
+	url := "https://osv.dev/vulnerability/" + vuln.ID
+	for _, ref := range vuln.References {
+		if ref.Type == "ADVISORY" {
+			url = ref.URL
+			break
+		}
+	}
+	title := vuln.Summary
+	if title == "" {
+		title = vuln.ID
+	}
+	cwe := vuln.DatabaseSpecific.CWEIDs
+	if cwe == nil {
+		cwe = []string{}
+	}
+
+	return &Advisory{
+		ID:                 OSVAdvisoryID(vuln.ID),
+		URL:                url,
+		Title:              title,
+		Severity:           severity,
+		VulnerableVersions: strings.Join(ranges, " || "),
+		CWE:                cwe,
+	}
+}
+
+func osvRange(introduced, end string) string {
+	if introduced == "" || introduced == "0" {
+		if end == "" {
+			return "*"
+		}
+		return end
+	}
+	if end == "" {
+		return ">=" + introduced
+	}
+	return ">=" + introduced + " " + end
+}
+
+// OSVAdvisoryID derives the numeric id npm audit expects from the id of an OSV vulnerability.
+// The ids are above 2^40 so they don't collide with the ids of the advisories published in Gitea.
+func OSVAdvisoryID(id string) int64 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(id))
+	return 1<<40 | int64(h.Sum32())
+}
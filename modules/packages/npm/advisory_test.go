@@ -0,0 +1,95 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package npm
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/json"
+
+	"github.com/hashicorp/go-version"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVersionRange(t *testing.T) {
+	cases := []struct {
+		r       string
+		version string
+		matches bool
+	}{
+		{"<1.2.3", "1.2.2", true},
+		{"<1.2.3", "1.2.3", false},
+		{">=1.0.0 <1.2.3 || >=2.0.0 <2.0.4", "1.1.0", true},
+		{">=1.0.0 <1.2.3 || >=2.0.0 <2.0.4", "1.5.0", false},
+		{">=1.0.0 <1.2.3 || >=2.0.0 <2.0.4", "2.0.3", true},
+		{"<=2.0.0", "2.0.0", true},
+		{"=1.0.0", "1.0.0", true},
+		{"1.0.0", "1.0.1", false},
+		{"*", "3.0.0", true},
+		{">1.0.0-beta.1", "1.0.0-beta.2", true},
+	}
+	for _, c := range cases {
+		r, err := ParseVersionRange(c.r)
+		assert.NoError(t, err, c.r)
+		assert.Equal(t, c.matches, r.Matches(version.Must(version.NewSemver(c.version))), "%s %s", c.r, c.version)
+	}
+
+	for _, r := range []string{"", "^1.2.0", ">=1.0.0 ||", "<latest"} {
+		_, err := ParseVersionRange(r)
+		assert.ErrorIs(t, err, ErrInvalidVersionRange, r)
+	}
+}
+
+func TestAdvisoryFromOSV(t *testing.T) {
+	data := `{
+		"id": "GHSA-35jh-r3h4-6jhm",
+		"summary": "Command Injection in lodash",
+		"affected": [
+			{
+				"package": {"ecosystem": "npm", "name": "lodash"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "4.17.21"}]}]
+			},
+			{
+				"package": {"ecosystem": "npm", "name": "lodash.template"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}]}]
+			},
+			{
+				"package": {"ecosystem": "npm", "name": "lodash"},
+				"ranges": [{"type": "SEMVER", "events": [{"introduced": "5.0.0"}, {"last_affected": "5.0.1"}, {"introduced": "6.0.0"}]}]
+			}
+		],
+		"references": [
+			{"type": "WEB", "url": "https://example.com"},
+			{"type": "ADVISORY", "url": "https://github.com/advisories/GHSA-35jh-r3h4-6jhm"}
+		],
+		"database_specific": {"severity": "HIGH", "cwe_ids": ["CWE-77"]}
+	}`
+
+	var vuln OSVVulnerability
+	assert.NoError(t, json.Unmarshal([]byte(data), &vuln))
+
+	advisory := AdvisoryFromOSV("lodash", &vuln)
+	if assert.NotNil(t, advisory) {
+		assert.Equal(t, OSVAdvisoryID("GHSA-35jh-r3h4-6jhm"), advisory.ID)
+		assert.Greater(t, advisory.ID, int64(1<<40))
+		assert.Equal(t, "https://github.com/advisories/GHSA-35jh-r3h4-6jhm", advisory.URL)
+		assert.Equal(t, "Command Injection in lodash", advisory.Title)
+		assert.Equal(t, SeverityHigh, advisory.Severity)
+		assert.Equal(t, "<4.17.21 || >=5.0.0 <=5.0.1 || >=6.0.0", advisory.VulnerableVersions)
+		assert.Equal(t, []string{"CWE-77"}, advisory.CWE)
+
+		_, err := ParseVersionRange(advisory.VulnerableVersions)
+		assert.NoError(t, err)
+	}
+
+	advisory = AdvisoryFromOSV("lodash.template", &vuln)
+	if assert.NotNil(t, advisory) {
+		assert.Equal(t, "*", advisory.VulnerableVersions)
+	}
+
+	assert.Nil(t, AdvisoryFromOSV("underscore", &vuln))
+
+	vuln.Withdrawn = "2023-01-01T00:00:00Z"
+	assert.Nil(t, AdvisoryFromOSV("lodash", &vuln))
+}
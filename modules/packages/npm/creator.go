@@ -44,6 +44,10 @@ type Package struct {
 	Metadata Metadata
 	Filename string
 	Data     []byte
+	// Deprecations maps the versions to their deprecation messages if the content updates the deprecations
+	// of the versions (as sent by "npm deprecate") instead of publishing a new version, an empty message
+	// removes the deprecation
+	Deprecations map[string]string
 }
 
 // PackageMetadata https://github.com/npm/registry/blob/master/docs/REGISTRY-API.md#package
@@ -85,6 +89,7 @@ type PackageMetadataVersion struct {
 	Readme               string              `json:"readme,omitempty"`
 	Dist                 PackageDistribution `json:"dist"`
 	Maintainers          []User              `json:"maintainers,omitempty"`
+	Deprecated           string              `json:"deprecated,omitempty"`
 }
 
 // PackageDistribution https://github.com/npm/registry/blob/master/docs/REGISTRY-API.md#version
@@ -183,6 +188,24 @@ func ParsePackage(r io.Reader) (*Package, error) {
 		return nil, err
 	}
 
+	if len(upload.Attachments) == 0 && len(upload.Versions) > 0 {
+		deprecations := make(map[string]string, len(upload.Versions))
+		for _, meta := range upload.Versions {
+			if !validateName(meta.Name) || meta.Name != upload.Name {
+				return nil, ErrInvalidPackageName
+			}
+			v, err := version.NewSemver(meta.Version)
+			if err != nil {
+				return nil, ErrInvalidPackageVersion
+			}
+			deprecations[v.String()] = meta.Deprecated
+		}
+		return &Package{
+			Name:         upload.Name,
+			Deprecations: deprecations,
+		}, nil
+	}
+
 	for _, meta := range upload.Versions {
 		if !validateName(meta.Name) {
 			return nil, ErrInvalidPackageName
@@ -280,4 +280,31 @@ func TestParsePackage(t *testing.T) {
 		assert.Equal(t, repository.Type, p.Metadata.Repository.Type)
 		assert.Equal(t, repository.URL, p.Metadata.Repository.URL)
 	})
+
+	t.Run("Deprecations", func(t *testing.T) {
+		b, _ := json.Marshal(packageUpload{
+			PackageMetadata: PackageMetadata{
+				ID:   packageFullName,
+				Name: packageFullName,
+				Versions: map[string]*PackageMetadataVersion{
+					packageVersion: {
+						Name:       packageFullName,
+						Version:    packageVersion,
+						Deprecated: "use 1.0.2",
+					},
+					"1.0.2": {
+						Name:    packageFullName,
+						Version: "1.0.2",
+					},
+				},
+			},
+		})
+
+		p, err := ParsePackage(bytes.NewReader(b))
+		assert.NoError(t, err)
+		assert.NotNil(t, p)
+		assert.Equal(t, packageFullName, p.Name)
+		assert.Empty(t, p.Data)
+		assert.Equal(t, map[string]string{packageVersion: "use 1.0.2", "1.0.2": ""}, p.Deprecations)
+	})
 }
@@ -9,6 +9,7 @@ import (
 	"encoding/xml"
 	"fmt"
 	"io"
+	"path"
 	"path/filepath"
 	"regexp"
 	"strings"
@@ -44,7 +45,10 @@ const (
 
 var idmatch = regexp.MustCompile(`\A\w+(?:[.-]\w+)*\z`)
 
-const maxNuspecFileSize = 3 * 1024 * 1024
+const (
+	maxNuspecFileSize = 3 * 1024 * 1024
+	maxReadmeFileSize = 1 * 1024 * 1024
+)
 
 // Package represents a Nuget package
 type Package struct {
@@ -57,6 +61,7 @@ type Package struct {
 // Metadata represents the metadata of a Nuget package
 type Metadata struct {
 	Description              string                  `json:"description,omitempty"`
+	Readme                   string                  `json:"readme,omitempty"`
 	ReleaseNotes             string                  `json:"release_notes,omitempty"`
 	Authors                  string                  `json:"authors,omitempty"`
 	ProjectURL               string                  `json:"project_url,omitempty"`
@@ -79,6 +84,7 @@ type nuspecPackage struct {
 		RequireLicenseAcceptance bool   `xml:"requireLicenseAcceptance"`
 		ProjectURL               string `xml:"projectUrl"`
 		Description              string `xml:"description"`
+		Readme                   string `xml:"readme"`
 		ReleaseNotes             string `xml:"releaseNotes"`
 		PackageTypes             struct {
 			PackageType []struct {
@@ -122,26 +128,67 @@ func ParsePackageMetaData(r io.ReaderAt, size int64) (*Package, error) {
 			}
 			defer f.Close()
 
-			return ParseNuspecMetaData(f)
+			np, nuspec, err := parseNuspec(f)
+			if err != nil {
+				return nil, err
+			}
+			if nuspec.Metadata.Readme != "" {
+				readme, err := readReadmeFile(archive, nuspec.Metadata.Readme)
+				if err != nil {
+					return nil, err
+				}
+				np.Metadata.Readme = readme
+			}
+			return np, nil
 		}
 	}
 	return nil, ErrMissingNuspecFile
 }
 
+// readReadmeFile reads the readme file referenced by the Nuspec file, a missing or too large readme file is ignored
+func readReadmeFile(archive *zip.Reader, name string) (string, error) {
+	name = strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, `\`, "/")), "/")
+	for _, file := range archive.File {
+		if !strings.EqualFold(file.Name, name) {
+			continue
+		}
+		if file.UncompressedSize64 > maxReadmeFileSize {
+			return "", nil
+		}
+		f, err := file.Open()
+		if err != nil {
+			return "", err
+		}
+		defer f.Close()
+
+		readme, err := io.ReadAll(io.LimitReader(f, maxReadmeFileSize))
+		if err != nil {
+			return "", err
+		}
+		return string(readme), nil
+	}
+	return "", nil
+}
+
 // ParseNuspecMetaData parses a Nuspec file to retrieve the metadata of a Nuget package
 func ParseNuspecMetaData(r io.Reader) (*Package, error) {
+	np, _, err := parseNuspec(r)
+	return np, err
+}
+
+func parseNuspec(r io.Reader) (*Package, *nuspecPackage, error) {
 	var p nuspecPackage
 	if err := xml.NewDecoder(r).Decode(&p); err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 
 	if !idmatch.MatchString(p.Metadata.ID) {
-		return nil, ErrNuspecInvalidID
+		return nil, nil, ErrNuspecInvalidID
 	}
 
 	v, err := version.NewSemver(p.Metadata.Version)
 	if err != nil {
-		return nil, ErrNuspecInvalidVersion
+		return nil, nil, ErrNuspecInvalidVersion
 	}
 
 	if !validation.IsValidURL(p.Metadata.ProjectURL) {
@@ -186,7 +233,7 @@ func ParseNuspecMetaData(r io.Reader) (*Package, error) {
 		ID:          p.Metadata.ID,
 		Version:     toNormalizedVersion(v),
 		Metadata:    m,
-	}, nil
+	}, &p, nil
 }
 
 // https://learn.microsoft.com/en-us/nuget/concepts/package-versioning#normalized-version-numbers
@@ -124,6 +124,31 @@ func TestParsePackageMetaData(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, np)
 	})
+
+	t.Run("Readme", func(t *testing.T) {
+		var buf bytes.Buffer
+		archive := zip.NewWriter(&buf)
+		w, _ := archive.Create("package.nuspec")
+		w.Write([]byte(strings.Replace(nuspecContent, "<releaseNotes>", `<readme>docs\README.md</readme><releaseNotes>`, 1)))
+		w, _ = archive.Create("docs/README.md")
+		w.Write([]byte("# Readme"))
+		archive.Close()
+		data := buf.Bytes()
+
+		np, err := ParsePackageMetaData(bytes.NewReader(data), int64(len(data)))
+		assert.NoError(t, err)
+		assert.NotNil(t, np)
+		assert.Equal(t, "# Readme", np.Metadata.Readme)
+	})
+
+	t.Run("MissingReadme", func(t *testing.T) {
+		data := createArchive("package.nuspec", strings.Replace(nuspecContent, "<releaseNotes>", "<readme>README.md</readme><releaseNotes>", 1))
+
+		np, err := ParsePackageMetaData(bytes.NewReader(data), int64(len(data)))
+		assert.NoError(t, err)
+		assert.NotNil(t, np)
+		assert.Empty(t, np.Metadata.Readme)
+	})
 }
 
 func TestParseNuspecMetaData(t *testing.T) {
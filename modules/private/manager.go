@@ -111,3 +111,33 @@ func Processes(ctx context.Context, out io.Writer, flat, noSystem, stacktraces,
 	_, extra := requestJSONResp(req, &callback)
 	return extra
 }
+
+// MaintenanceOptions represents the options for the enable maintenance mode call
+type MaintenanceOptions struct {
+	Message      string
+	DrainTimeout time.Duration
+}
+
+// EnableMaintenanceMode enables the read-only maintenance mode
+func EnableMaintenanceMode(ctx context.Context, message string, drainTimeout time.Duration) ResponseExtra {
+	reqURL := setting.LocalURL + "api/internal/manager/maintenance/enable"
+	req := newInternalRequest(ctx, reqURL, "POST", MaintenanceOptions{Message: message, DrainTimeout: drainTimeout})
+	if drainTimeout > 0 {
+		req.SetReadWriteTimeout(drainTimeout + 10*time.Second)
+	}
+	return requestJSONUserMsg(req, "Maintenance mode enabled")
+}
+
+// DisableMaintenanceMode disables the read-only maintenance mode
+func DisableMaintenanceMode(ctx context.Context) ResponseExtra {
+	reqURL := setting.LocalURL + "api/internal/manager/maintenance/disable"
+	req := newInternalRequest(ctx, reqURL, "POST")
+	return requestJSONUserMsg(req, "Maintenance mode disabled")
+}
+
+// MaintenanceModeStatus returns the state of the read-only maintenance mode
+func MaintenanceModeStatus(ctx context.Context) ResponseExtra {
+	reqURL := setting.LocalURL + "api/internal/manager/maintenance"
+	req := newInternalRequest(ctx, reqURL, "GET")
+	return requestJSONUserMsg(req, "")
+}
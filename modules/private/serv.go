@@ -43,6 +43,8 @@ type ServCommandResults struct {
 	OwnerName   string
 	RepoName    string
 	RepoID      int64
+	// RepoRootPath is the repository root path of the storage region of the owner
+	RepoRootPath string
 }
 
 // ServCommand preps for a serv call
@@ -0,0 +1,252 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package queue
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+
+	"github.com/nats-io/nats.go"
+)
+
+const (
+	// natsConsumerName is the durable consumer shared by all the instances popping from a queue
+	natsConsumerName = "gitea"
+	// natsPollInterval is how long a pop waits for an item before it checks whether it was cancelled
+	natsPollInterval = 5 * time.Second
+	// natsExpectedLastSubjectSequence makes JetStream refuse a message if its subject already has one
+	natsExpectedLastSubjectSequence = "Nats-Expected-Last-Subject-Sequence"
+)
+
+var natsConns = struct {
+	sync.Mutex
+	conns map[string]*nats.Conn
+}{conns: map[string]*nats.Conn{}}
+
+// getNatsConn returns the connection of a connection string, the queues using the same one share it
+func getNatsConn(connStr string) (*nats.Conn, error) {
+	natsConns.Lock()
+	defer natsConns.Unlock()
+
+	if nc, ok := natsConns.conns[connStr]; ok {
+		return nc, nil
+	}
+	var nc *nats.Conn
+	var err error
+	for i := 0; i < 10; i++ {
+		nc, err = nats.Connect(connStr, nats.Name("gitea"), nats.MaxReconnects(-1))
+		if err == nil {
+			break
+		}
+		log.Warn("NATS is not ready, waiting for 1 second to retry: %v", err)
+		time.Sleep(time.Second)
+	}
+	if err != nil {
+		return nil, err
+	}
+	natsConns.conns[connStr] = nc
+	return nc, nil
+}
+
+// natsStreamName returns a valid JetStream stream name for a queue, they cannot contain dots, wildcards or whitespace
+func natsStreamName(queueFullName string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case '.', '*', '>', '/', '\\', ' ', '\t', '\r', '\n':
+			return '_'
+		}
+		return r
+	}, queueFullName)
+}
+
+// baseNats stores the items of a queue in a JetStream work queue stream: an item is removed once it is acknowledged by a pop.
+// Every item of a unique queue is published to its own subject and JetStream refuses a message for a subject which has one.
+type baseNats struct {
+	nc       *nats.Conn
+	js       nats.JetStreamContext
+	sub      *nats.Subscription
+	stream   string
+	subject  string
+	isUnique bool
+	cfg      *BaseConfig
+
+	popMu sync.Mutex // a pull subscription can only fetch once at a time
+}
+
+var _ baseQueue = (*baseNats)(nil)
+
+func newBaseNatsGeneric(cfg *BaseConfig, unique bool) (baseQueue, error) {
+	if cfg.ConnStr == "" {
+		return nil, fmt.Errorf("queue %q of type nats requires a CONN_STR", cfg.ManagedName)
+	}
+	nc, err := getNatsConn(cfg.ConnStr)
+	if err != nil {
+		return nil, err
+	}
+	js, err := nc.JetStream(nats.Context(graceful.GetManager().ShutdownContext()))
+	if err != nil {
+		return nil, err
+	}
+
+	q := &baseNats{
+		nc:       nc,
+		js:       js,
+		stream:   natsStreamName(cfg.QueueFullName),
+		isUnique: unique,
+		cfg:      cfg,
+	}
+	q.subject = "gitea.queue." + q.stream
+
+	// adding a stream which exists with the same configuration succeeds, so every instance can do it
+	if _, err := js.AddStream(&nats.StreamConfig{
+		Name:      q.stream,
+		Subjects:  []string{q.subject + ".*"},
+		Retention: nats.WorkQueuePolicy,
+		Storage:   nats.FileStorage,
+	}); err != nil {
+		return nil, fmt.Errorf("unable to create the stream of queue %q: %w", cfg.ManagedName, err)
+	}
+	q.sub, err = js.PullSubscribe(q.subject+".*", natsConsumerName, nats.BindStream(q.stream), nats.AckExplicit())
+	if err != nil {
+		return nil, fmt.Errorf("unable to subscribe to the stream of queue %q: %w", cfg.ManagedName, err)
+	}
+	return q, nil
+}
+
+func newBaseNatsSimple(cfg *BaseConfig) (baseQueue, error) {
+	return newBaseNatsGeneric(cfg, false)
+}
+
+func newBaseNatsUnique(cfg *BaseConfig) (baseQueue, error) {
+	return newBaseNatsGeneric(cfg, true)
+}
+
+// itemSubject returns the subject an item is published to
+func (q *baseNats) itemSubject(data []byte) string {
+	if !q.isUnique {
+		return q.subject + ".item"
+	}
+	sum := sha256.Sum256(data)
+	return q.subject + "." + hex.EncodeToString(sum[:])
+}
+
+func (q *baseNats) PushItem(ctx context.Context, data []byte) error {
+	return backoffErr(ctx, backoffBegin, backoffUpper, time.After(pushBlockTime), func() (retry bool, err error) {
+		cnt, err := q.Len(ctx)
+		if err != nil {
+			return false, err
+		}
+		if cnt >= q.cfg.Length {
+			return true, nil
+		}
+
+		msg := nats.NewMsg(q.itemSubject(data))
+		msg.Data = data
+		if q.isUnique {
+			msg.Header.Set(natsExpectedLastSubjectSequence, "0")
+		}
+		if _, err := q.js.PublishMsg(msg, nats.Context(ctx)); err != nil {
+			// the item was refused if it is already in the queue
+			if has, hasErr := q.HasItem(ctx, data); hasErr == nil && has {
+				return false, ErrAlreadyInQueue
+			}
+			return false, err
+		}
+		return false, nil
+	})
+}
+
+func (q *baseNats) PopItem(ctx context.Context) ([]byte, error) {
+	q.popMu.Lock()
+	defer q.popMu.Unlock()
+
+	for {
+		fetchCtx, cancel := context.WithTimeout(ctx, natsPollInterval)
+		msgs, err := q.sub.Fetch(1, nats.Context(fetchCtx))
+		cancel()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			if !errors.Is(err, context.DeadlineExceeded) && !errors.Is(err, nats.ErrTimeout) {
+				log.Warn("Unable to pop an item from queue %q: %v", q.cfg.ManagedName, err)
+				time.Sleep(backoffBegin)
+			}
+			continue
+		}
+		if len(msgs) == 0 {
+			continue
+		}
+		// acknowledging removes the item from the stream, it is popped like in the other queues even if handling it fails
+		if err := msgs[0].AckSync(nats.Context(ctx)); err != nil {
+			log.Warn("Unable to acknowledge an item of queue %q: %v", q.cfg.ManagedName, err)
+			continue
+		}
+		return msgs[0].Data, nil
+	}
+}
+
+// natsMsgGetResponse is the response of the JetStream API to get a message of a stream
+type natsMsgGetResponse struct {
+	Error *struct {
+		Code        int    `json:"code"`
+		Description string `json:"description"`
+	} `json:"error"`
+	Message *struct {
+		Sequence uint64 `json:"seq"`
+	} `json:"message"`
+}
+
+func (q *baseNats) HasItem(ctx context.Context, data []byte) (bool, error) {
+	if !q.isUnique {
+		return false, nil
+	}
+	req, err := json.Marshal(map[string]string{"last_by_subj": q.itemSubject(data)})
+	if err != nil {
+		return false, err
+	}
+	msg, err := q.nc.RequestWithContext(ctx, "$JS.API.STREAM.MSG.GET."+q.stream, req)
+	if err != nil {
+		return false, err
+	}
+	var resp natsMsgGetResponse
+	if err := json.Unmarshal(msg.Data, &resp); err != nil {
+		return false, err
+	}
+	if resp.Error != nil {
+		if resp.Error.Code == 404 {
+			return false, nil
+		}
+		return false, errors.New(resp.Error.Description)
+	}
+	return resp.Message != nil, nil
+}
+
+func (q *baseNats) Len(ctx context.Context) (int, error) {
+	info, err := q.js.StreamInfo(q.stream, nats.Context(ctx))
+	if err != nil {
+		return 0, err
+	}
+	return int(info.State.Msgs), nil
+}
+
+func (q *baseNats) Close() error {
+	// the connection is shared by the queues, only the subscription of this one is closed,
+	// the durable consumer is kept for the other instances
+	return q.sub.Unsubscribe()
+}
+
+func (q *baseNats) RemoveAll(ctx context.Context) error {
+	return q.js.PurgeStream(q.stream, nats.Context(ctx))
+}
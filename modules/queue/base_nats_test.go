@@ -0,0 +1,31 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package queue
+
+import (
+	"os"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBaseNats(t *testing.T) {
+	// e.g. nats://127.0.0.1:4222, the server must have JetStream enabled
+	connStr := os.Getenv("TEST_QUEUE_NATS_CONN_STR")
+	if connStr == "" {
+		t.Skip("TEST_QUEUE_NATS_CONN_STR is not set")
+		return
+	}
+
+	testQueueBasic(t, newBaseNatsSimple, toBaseConfig("baseNats", setting.QueueSettings{ConnStr: connStr, Length: 10}), false)
+	testQueueBasic(t, newBaseNatsUnique, toBaseConfig("baseNatsUnique", setting.QueueSettings{ConnStr: connStr, Length: 10}), true)
+}
+
+func TestNatsStreamName(t *testing.T) {
+	assert.Equal(t, "code_indexer_queue", natsStreamName("code_indexer_queue"))
+	assert.Equal(t, "repo-archive_queue", natsStreamName("repo-archive_queue"))
+	assert.Equal(t, "a_b_c_d", natsStreamName("a.b*c>d"))
+}
@@ -0,0 +1,230 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package queue
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+
+	"github.com/lib/pq"
+)
+
+const (
+	// postgresNotifyChannel is the channel notified with the name of a queue when an item is pushed to it
+	postgresNotifyChannel = "gitea_queue"
+	// postgresPollInterval is how often an empty queue is checked if a notification got lost, e.g. during a reconnection
+	postgresPollInterval = 5 * time.Second
+)
+
+// the items of all the queues are stored in one table, the unique key of an item is only set for unique queues
+const postgresSchema = `
+CREATE TABLE IF NOT EXISTS gitea_queue_item (
+	id BIGSERIAL PRIMARY KEY,
+	queue_name TEXT NOT NULL,
+	unique_key TEXT NULL,
+	data BYTEA NOT NULL
+);
+CREATE INDEX IF NOT EXISTS gitea_queue_item_queue_name ON gitea_queue_item (queue_name, id);
+CREATE UNIQUE INDEX IF NOT EXISTS gitea_queue_item_unique_key ON gitea_queue_item (queue_name, unique_key);
+`
+
+var postgresDBs = struct {
+	sync.Mutex
+	dbs map[string]*sql.DB
+}{dbs: map[string]*sql.DB{}}
+
+// getPostgresDB returns the database of a connection string, the queues using the same one share it
+func getPostgresDB(connStr string) (*sql.DB, error) {
+	postgresDBs.Lock()
+	defer postgresDBs.Unlock()
+
+	if db, ok := postgresDBs.dbs[connStr]; ok {
+		return db, nil
+	}
+	db, err := sql.Open("postgres", connStr)
+	if err != nil {
+		return nil, err
+	}
+	postgresDBs.dbs[connStr] = db
+	return db, nil
+}
+
+type basePostgres struct {
+	db       *sql.DB
+	listener *pq.Listener
+	notified chan struct{}
+	isUnique bool
+	cfg      *BaseConfig
+}
+
+var _ baseQueue = (*basePostgres)(nil)
+
+func newBasePostgresGeneric(cfg *BaseConfig, unique bool) (baseQueue, error) {
+	if cfg.ConnStr == "" {
+		return nil, fmt.Errorf("queue %q of type postgres requires a CONN_STR", cfg.ManagedName)
+	}
+	db, err := getPostgresDB(cfg.ConnStr)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < 10; i++ {
+		err = db.PingContext(graceful.GetManager().ShutdownContext())
+		if err == nil {
+			break
+		}
+		log.Warn("PostgreSQL is not ready, waiting for 1 second to retry: %v", err)
+		time.Sleep(time.Second)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(postgresSchema); err != nil {
+		return nil, fmt.Errorf("unable to create the queue table: %w", err)
+	}
+
+	q := &basePostgres{
+		db:       db,
+		notified: make(chan struct{}, 1),
+		isUnique: unique,
+		cfg:      cfg,
+	}
+	q.listener = pq.NewListener(cfg.ConnStr, backoffBegin, backoffUpper, func(event pq.ListenerEventType, err error) {
+		if err != nil {
+			log.Warn("PostgreSQL listener of queue %q: %v", cfg.ManagedName, err)
+		}
+	})
+	if err := q.listener.Listen(postgresNotifyChannel); err != nil {
+		_ = q.listener.Close()
+		return nil, err
+	}
+	go q.dispatchNotifications()
+
+	return q, nil
+}
+
+func newBasePostgresSimple(cfg *BaseConfig) (baseQueue, error) {
+	return newBasePostgresGeneric(cfg, false)
+}
+
+func newBasePostgresUnique(cfg *BaseConfig) (baseQueue, error) {
+	return newBasePostgresGeneric(cfg, true)
+}
+
+// dispatchNotifications wakes up the pops waiting for the items pushed to this queue, by any instance
+func (q *basePostgres) dispatchNotifications() {
+	for n := range q.listener.Notify {
+		// a nil notification means the connection was re-established, some notifications may have been lost
+		if n != nil && n.Extra != q.cfg.QueueFullName {
+			continue
+		}
+		select {
+		case q.notified <- struct{}{}:
+		default:
+		}
+	}
+}
+
+func (q *basePostgres) uniqueKey(data []byte) any {
+	if !q.isUnique {
+		return nil
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func (q *basePostgres) PushItem(ctx context.Context, data []byte) error {
+	return backoffErr(ctx, backoffBegin, backoffUpper, time.After(pushBlockTime), func() (retry bool, err error) {
+		cnt, err := q.Len(ctx)
+		if err != nil {
+			return false, err
+		}
+		if cnt >= q.cfg.Length {
+			return true, nil
+		}
+
+		res, err := q.db.ExecContext(ctx, "INSERT INTO gitea_queue_item (queue_name, unique_key, data) VALUES ($1, $2, $3) ON CONFLICT DO NOTHING",
+			q.cfg.QueueFullName, q.uniqueKey(data), data)
+		if err != nil {
+			return false, err
+		}
+		if n, err := res.RowsAffected(); err != nil {
+			return false, err
+		} else if n == 0 {
+			return false, ErrAlreadyInQueue
+		}
+
+		_, err = q.db.ExecContext(ctx, "SELECT pg_notify($1, $2)", postgresNotifyChannel, q.cfg.QueueFullName)
+		return false, err
+	})
+}
+
+// popItem removes the oldest item of the queue, the items locked by the other instances are skipped
+func (q *basePostgres) popItem(ctx context.Context) ([]byte, error) {
+	var data []byte
+	err := q.db.QueryRowContext(ctx, `DELETE FROM gitea_queue_item WHERE id = (
+		SELECT id FROM gitea_queue_item WHERE queue_name = $1 ORDER BY id LIMIT 1 FOR UPDATE SKIP LOCKED
+	) RETURNING data`, q.cfg.QueueFullName).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func (q *basePostgres) PopItem(ctx context.Context) ([]byte, error) {
+	for {
+		data, err := q.popItem(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			log.Warn("Unable to pop an item from queue %q: %v", q.cfg.ManagedName, err)
+		} else if data != nil {
+			return data, nil
+		}
+
+		// wait for an item to be pushed, or poll again in case the notification was lost
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-q.notified:
+		case <-time.After(postgresPollInterval):
+		}
+	}
+}
+
+func (q *basePostgres) HasItem(ctx context.Context, data []byte) (bool, error) {
+	if !q.isUnique {
+		return false, nil
+	}
+	var has bool
+	err := q.db.QueryRowContext(ctx, "SELECT EXISTS (SELECT 1 FROM gitea_queue_item WHERE queue_name = $1 AND unique_key = $2)",
+		q.cfg.QueueFullName, q.uniqueKey(data)).Scan(&has)
+	return has, err
+}
+
+func (q *basePostgres) Len(ctx context.Context) (int, error) {
+	var cnt int
+	err := q.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM gitea_queue_item WHERE queue_name = $1", q.cfg.QueueFullName).Scan(&cnt)
+	return cnt, err
+}
+
+func (q *basePostgres) Close() error {
+	// the database is shared by the queues, only the listener of this one is closed
+	return q.listener.Close()
+}
+
+func (q *basePostgres) RemoveAll(ctx context.Context) error {
+	_, err := q.db.ExecContext(ctx, "DELETE FROM gitea_queue_item WHERE queue_name = $1", q.cfg.QueueFullName)
+	return err
+}
@@ -29,6 +29,8 @@ type ManagedWorkerPoolQueue interface {
 	GetWorkerMaxNumber() int
 	SetWorkerMaxNumber(num int)
 	GetQueueItemNumber() int
+	GetHandledItemNumber() int64
+	GetCreatedTime() time.Time
 
 	// FlushWithContext tries to make the handler process all items in the queue synchronously.
 	// It is for testing purpose only. It's not designed to be used in a cluster.
@@ -94,6 +94,7 @@ func (q *WorkerPoolQueue[T]) doWorkerHandle(batch []T) {
 	}()
 
 	unhandled := q.safeHandler(batch...)
+	q.handledItemNum.Add(int64(len(batch) - len(unhandled)))
 	// if none of the items were handled, it should back-off for a few seconds
 	// in this case the handler (eg: document indexer) may have encountered some errors/failures
 	if len(unhandled) == len(batch) && unhandledItemRequeueDuration.Load() != 0 {
@@ -39,6 +39,9 @@ type WorkerPoolQueue[T any] struct {
 	workerMaxNum    int
 	workerActiveNum int
 	workerNumMu     sync.Mutex
+
+	created        time.Time
+	handledItemNum atomic.Int64
 }
 
 type flushType chan struct{}
@@ -82,6 +85,16 @@ func (q *WorkerPoolQueue[T]) SetWorkerMaxNumber(num int) {
 	q.workerMaxNum = num
 }
 
+// GetHandledItemNumber returns the number of items the handler processed successfully since the queue was created
+func (q *WorkerPoolQueue[T]) GetHandledItemNumber() int64 {
+	return q.handledItemNum.Load()
+}
+
+// GetCreatedTime returns when the queue was created
+func (q *WorkerPoolQueue[T]) GetCreatedTime() time.Time {
+	return q.created
+}
+
 func (q *WorkerPoolQueue[T]) GetQueueItemNumber() int {
 	cnt, err := q.baseQueue.Len(q.ctxRun)
 	if err != nil {
@@ -202,6 +215,10 @@ func getNewQueueFn(t string) (string, func(cfg *BaseConfig, unique bool) (baseQu
 		return t, newBaseChannelGeneric
 	case "redis":
 		return t, newBaseRedisGeneric
+	case "postgres":
+		return t, newBasePostgresGeneric
+	case "nats":
+		return t, newBaseNatsGeneric
 	default: // level(leveldb,levelqueue,persistable-channel)
 		return "level", newBaseLevelQueueGeneric
 	}
@@ -226,6 +243,7 @@ func NewWorkerPoolQueueBySetting[T any](name string, queueSetting setting.QueueS
 
 	w.ctxRun, w.ctxRunCancel = context.WithCancel(graceful.GetManager().ShutdownContext())
 	w.batchChan = make(chan []T)
+	w.created = time.Now()
 	w.flushChan = make(chan flushType)
 	w.shutdownDone = make(chan struct{})
 	w.workerMaxNum = queueSetting.MaxWorkers
@@ -165,14 +165,19 @@ type CreateRepoOptions struct {
 	IssueLabels    string
 	License        string
 	Readme         string
-	DefaultBranch  string
-	IsPrivate      bool
-	IsMirror       bool
-	IsTemplate     bool
-	AutoInit       bool
-	Status         repo_model.RepositoryStatus
-	TrustModel     repo_model.TrustModelType
-	MirrorInterval string
+	// IssueTemplates and WorkflowTemplates are comma-separated templates of the catalogs added to the initial commit
+	IssueTemplates    string
+	WorkflowTemplates string
+	DefaultBranch     string
+	IsPrivate         bool
+	IsInternal        bool // readable by all signed-in users, implies IsPrivate
+	IsMirror          bool
+	IsTemplate        bool
+	AutoInit          bool
+	Status            repo_model.RepositoryStatus
+	TrustModel        repo_model.TrustModelType
+	MirrorInterval    string
+	ObjectFormat      git.ObjectFormat // empty means SHA-1
 }
 
 // CreateRepository creates a repository for the user/organization.
@@ -187,6 +192,12 @@ func CreateRepository(doer, u *user_model.User, opts CreateRepoOptions) (*repo_m
 		opts.DefaultBranch = setting.Repository.DefaultBranch
 	}
 
+	if opts.ObjectFormat == "" {
+		opts.ObjectFormat = git.ObjectFormatSHA1
+	} else if !opts.ObjectFormat.IsSupported() {
+		return nil, git.ErrObjectFormatNotSupported{ObjectFormat: opts.ObjectFormat.String()}
+	}
+
 	// Check if label template exist
 	if len(opts.IssueLabels) > 0 {
 		if _, err := LoadTemplateLabelsByDisplayName(opts.IssueLabels); err != nil {
@@ -203,7 +214,8 @@ func CreateRepository(doer, u *user_model.User, opts CreateRepoOptions) (*repo_m
 		Description:                     opts.Description,
 		OriginalURL:                     opts.OriginalURL,
 		OriginalServiceType:             opts.GitServiceType,
-		IsPrivate:                       opts.IsPrivate,
+		IsPrivate:                       opts.IsPrivate || opts.IsInternal,
+		IsInternal:                      opts.IsInternal,
 		IsFsckEnabled:                   !opts.IsMirror,
 		IsTemplate:                      opts.IsTemplate,
 		CloseIssuesViaCommitInAnyBranch: setting.Repository.DefaultCloseIssuesViaCommitsInAnyBranch,
@@ -212,6 +224,7 @@ func CreateRepository(doer, u *user_model.User, opts CreateRepoOptions) (*repo_m
 		TrustModel:                      opts.TrustModel,
 		IsMirror:                        opts.IsMirror,
 		DefaultBranch:                   opts.DefaultBranch,
+		ObjectFormatName:                opts.ObjectFormat.String(),
 	}
 
 	var rollbackRepo *repo_model.Repository
@@ -367,6 +380,8 @@ func CheckDaemonExportOK(ctx context.Context, repo *repo_model.Repository) error
 // UpdateRepository updates a repository with db context
 func UpdateRepository(ctx context.Context, repo *repo_model.Repository, visibilityChanged bool) (err error) {
 	repo.LowerName = strings.ToLower(repo.Name)
+	// only private repositories can be internal
+	repo.IsInternal = repo.IsInternal && repo.IsPrivate
 
 	e := db.GetEngine(ctx)
 
@@ -410,6 +425,7 @@ func UpdateRepository(ctx context.Context, repo *repo_model.Repository, visibili
 		}
 		for i := range forkRepos {
 			forkRepos[i].IsPrivate = repo.IsPrivate || repo.Owner.Visibility == api.VisibleTypePrivate
+			forkRepos[i].IsInternal = repo.IsInternal
 			if err = UpdateRepository(ctx, forkRepos[i], true); err != nil {
 				return fmt.Errorf("updateRepository[%d]: %w", forkRepos[i].ID, err)
 			}
@@ -6,6 +6,7 @@ package repository
 import (
 	"fmt"
 	"os"
+	"strconv"
 	"strings"
 
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -28,6 +29,7 @@ const (
 	EnvIsInternal   = "GITEA_INTERNAL_PUSH"
 	EnvAppURL       = "GITEA_ROOT_URL"
 	EnvActionPerm   = "GITEA_ACTION_PERM"
+	EnvPushLimits   = "GITEA_PUSH_LIMITS" // whether the push limits of the server are enabled
 )
 
 // InternalPushingEnvironment returns an os environment to switch off hooks on push
@@ -68,6 +70,7 @@ func FullPushingEnvironment(author, committer *user_model.User, repo *repo_model
 		EnvRepoID+"="+fmt.Sprintf("%d", repo.ID),
 		EnvPRID+"="+fmt.Sprintf("%d", prID),
 		EnvAppURL+"="+setting.AppURL,
+		EnvPushLimits+"="+strconv.FormatBool(setting.PushLimitsEnabled()),
 		"SSH_ORIGINAL_COMMAND=gitea-internal",
 	)
 
@@ -205,7 +205,7 @@ func generateRepoCommit(ctx context.Context, repo, templateRepo, generateRepo *r
 		}
 	}
 
-	if err := git.InitRepository(ctx, tmpDir, false); err != nil {
+	if err := git.InitRepository(ctx, tmpDir, false, repo.ObjectFormat()); err != nil {
 		return err
 	}
 
@@ -289,6 +289,7 @@ type GenerateRepoOptions struct {
 	DefaultBranch string
 	Description   string
 	Private       bool
+	Internal      bool
 	GitContent    bool
 	Topics        bool
 	GitHooks      bool
@@ -312,11 +313,14 @@ func GenerateRepository(ctx context.Context, doer, owner *user_model.User, templ
 		LowerName:     strings.ToLower(opts.Name),
 		Description:   opts.Description,
 		DefaultBranch: opts.DefaultBranch,
-		IsPrivate:     opts.Private,
+		IsPrivate:     opts.Private || opts.Internal,
+		IsInternal:    opts.Internal,
 		IsEmpty:       !opts.GitContent || templateRepo.IsEmpty,
 		IsFsckEnabled: templateRepo.IsFsckEnabled,
 		TemplateID:    templateRepo.ID,
 		TrustModel:    templateRepo.TrustModel,
+		// the commits of the template are copied, so the object format has to match
+		ObjectFormatName: templateRepo.ObjectFormatName,
 	}
 
 	if err = CreateRepositoryByExample(ctx, doer, owner, generateRepo, false, false); err != nil {
@@ -336,7 +340,7 @@ func GenerateRepository(ctx context.Context, doer, owner *user_model.User, templ
 		}
 	}
 
-	if err = checkInitRepository(ctx, owner.Name, generateRepo.Name); err != nil {
+	if err = checkInitRepository(ctx, owner.Name, generateRepo.Name, generateRepo.ObjectFormat()); err != nil {
 		return generateRepo, err
 	}
 
@@ -177,7 +177,7 @@ func prepareRepoCommit(ctx context.Context, repo *repo_model.Repository, tmpDir,
 		var buf bytes.Buffer
 		names := strings.Split(opts.Gitignores, ",")
 		for _, name := range names {
-			data, err = options.Gitignore(name)
+			data, err = GetTemplateCatalogContent(ctx, repo.OwnerID, repo_model.TemplateCatalogGitignore, name)
 			if err != nil {
 				return fmt.Errorf("GetRepoInitFile[%s]: %w", name, err)
 			}
@@ -195,14 +195,14 @@ func prepareRepoCommit(ctx context.Context, repo *repo_model.Repository, tmpDir,
 
 	// LICENSE
 	if len(opts.License) > 0 {
-		data, err = getLicense(opts.License, &licenseValues{
+		data, err = getCatalogLicense(ctx, repo.OwnerID, opts.License, &licenseValues{
 			Owner: repo.OwnerName,
 			Email: authorSig.Email,
 			Repo:  repo.Name,
 			Year:  time.Now().Format("2006"),
 		})
 		if err != nil {
-			return fmt.Errorf("getLicense[%s]: %w", opts.License, err)
+			return fmt.Errorf("getCatalogLicense[%s]: %w", opts.License, err)
 		}
 
 		if err = os.WriteFile(filepath.Join(tmpDir, "LICENSE"), data, 0o644); err != nil {
@@ -210,6 +210,38 @@ func prepareRepoCommit(ctx context.Context, repo *repo_model.Repository, tmpDir,
 		}
 	}
 
+	// issue and workflow templates
+	if err = writeTemplateCatalogFiles(ctx, repo.OwnerID, repo_model.TemplateCatalogIssueTemplate, opts.IssueTemplates, tmpDir); err != nil {
+		return err
+	}
+	if err = writeTemplateCatalogFiles(ctx, repo.OwnerID, repo_model.TemplateCatalogWorkflowTemplate, opts.WorkflowTemplates, tmpDir); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// writeTemplateCatalogFiles writes the comma-separated templates of a catalog to the directory of its kind
+func writeTemplateCatalogFiles(ctx context.Context, ownerID int64, kind repo_model.TemplateCatalogKind, names, tmpDir string) error {
+	if len(names) == 0 {
+		return nil
+	}
+	dir := filepath.Join(tmpDir, filepath.FromSlash(kind.Dir()))
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("create %s: %w", kind.Dir(), err)
+	}
+	for _, name := range strings.Split(names, ",") {
+		if err := repo_model.ValidateTemplateCatalogName(kind, name); err != nil {
+			return err
+		}
+		data, err := GetTemplateCatalogContent(ctx, ownerID, kind, name)
+		if err != nil {
+			return fmt.Errorf("GetTemplateCatalogContent[%s]: %w", name, err)
+		}
+		if err = os.WriteFile(filepath.Join(dir, name), data, 0o644); err != nil {
+			return fmt.Errorf("write %s/%s: %w", kind.Dir(), name, err)
+		}
+	}
 	return nil
 }
 
@@ -277,7 +309,7 @@ func initRepoCommit(ctx context.Context, tmpPath string, repo *repo_model.Reposi
 	return nil
 }
 
-func checkInitRepository(ctx context.Context, owner, name string) (err error) {
+func checkInitRepository(ctx context.Context, owner, name string, objectFormat git.ObjectFormat) (err error) {
 	// Somehow the directory could exist.
 	repoPath := repo_model.RepoPath(owner, name)
 	isExist, err := util.IsExist(repoPath)
@@ -293,7 +325,7 @@ func checkInitRepository(ctx context.Context, owner, name string) (err error) {
 	}
 
 	// Init git bare new repository.
-	if err = git.InitRepository(ctx, repoPath, true); err != nil {
+	if err = git.InitRepository(ctx, repoPath, true, objectFormat); err != nil {
 		return fmt.Errorf("git.InitRepository: %w", err)
 	} else if err = createDelegateHooks(repoPath); err != nil {
 		return fmt.Errorf("createDelegateHooks: %w", err)
@@ -303,7 +335,7 @@ func checkInitRepository(ctx context.Context, owner, name string) (err error) {
 
 // InitRepository initializes README and .gitignore if needed.
 func initRepository(ctx context.Context, repoPath string, u *user_model.User, repo *repo_model.Repository, opts CreateRepoOptions) (err error) {
-	if err = checkInitRepository(ctx, repo.OwnerName, repo.Name); err != nil {
+	if err = checkInitRepository(ctx, repo.OwnerName, repo.Name, repo.ObjectFormat()); err != nil {
 		return err
 	}
 
@@ -0,0 +1,188 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+)
+
+// maxDependencyManifestSize is the largest lock file which will be inspected for the licenses of dependencies
+const maxDependencyManifestSize = 16 * 1024 * 1024
+
+// dependencyManifestParsers are the lock files which declare the licenses of the dependencies they lock
+var dependencyManifestParsers = map[string]func(content []byte) ([]*DependencyLicense, error){
+	"package-lock.json": parsePackageLockLicenses,
+	"composer.lock":     parseComposerLockLicenses,
+}
+
+// DependencyLicense represents the license declared by a dependency in a lock file of a repository
+type DependencyLicense struct {
+	// Dependency is the name and the version of the dependency, e.g. left-pad@1.3.0
+	Dependency string
+	// License is the SPDX license expression declared by the dependency, e.g. MIT OR Apache-2.0
+	License string
+	// Path is the path of the lock file
+	Path string
+}
+
+// IsDependencyManifestFileName returns true if the licenses of dependencies can be read from the file
+func IsDependencyManifestFileName(name string) bool {
+	_, ok := dependencyManifestParsers[name]
+	return ok
+}
+
+// DetectDependencyLicensesFromCommit reads the licenses of the dependencies from the lock files in the root
+// directory of the commit
+func DetectDependencyLicensesFromCommit(commit *git.Commit) ([]*DependencyLicense, error) {
+	names := make([]string, 0, len(dependencyManifestParsers))
+	for name := range dependencyManifestParsers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return DetectDependencyLicensesFromPaths(commit, names)
+}
+
+// DetectDependencyLicensesFromPaths reads the licenses of the dependencies from the given files of the commit.
+// Only lock files in the root directory are inspected and files which don't exist in the commit are skipped.
+func DetectDependencyLicensesFromPaths(commit *git.Commit, paths []string) ([]*DependencyLicense, error) {
+	var licenses []*DependencyLicense
+	for _, p := range paths {
+		parse, ok := dependencyManifestParsers[p]
+		if !ok {
+			continue
+		}
+		entry, err := commit.GetTreeEntryByPath(p)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		if !entry.IsRegular() || entry.Size() > maxDependencyManifestSize {
+			continue
+		}
+		content, err := entry.Blob().GetBlobContent()
+		if err != nil {
+			return nil, err
+		}
+		found, err := parse([]byte(content))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p, err)
+		}
+		for _, l := range found {
+			l.Path = p
+		}
+		licenses = append(licenses, found...)
+	}
+	return licenses, nil
+}
+
+// DependencyLicenseNames returns the sorted, de-duplicated license expressions of the dependencies
+func DependencyLicenseNames(licenses []*DependencyLicense) []string {
+	names := make([]string, 0, len(licenses))
+	seen := make(map[string]bool, len(licenses))
+	for _, l := range licenses {
+		if !seen[l.License] {
+			seen[l.License] = true
+			names = append(names, l.License)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// packageLockLicense is the license of a package in a package-lock.json, a SPDX expression or
+// an object with a type for packages using the deprecated format
+type packageLockLicense string
+
+func (l *packageLockLicense) UnmarshalJSON(data []byte) error {
+	var expression string
+	if err := json.Unmarshal(data, &expression); err == nil {
+		*l = packageLockLicense(expression)
+		return nil
+	}
+	var legacy struct {
+		Type string `json:"type"`
+	}
+	if err := json.Unmarshal(data, &legacy); err != nil {
+		return err
+	}
+	*l = packageLockLicense(legacy.Type)
+	return nil
+}
+
+// parsePackageLockLicenses reads the licenses of the packages of a package-lock.json.
+// Only lockfileVersion 2 and 3 record licenses, older lock files don't contain any.
+func parsePackageLockLicenses(content []byte) ([]*DependencyLicense, error) {
+	var lock struct {
+		Packages map[string]struct {
+			Version string             `json:"version"`
+			License packageLockLicense `json:"license"`
+			Link    bool               `json:"link"`
+		} `json:"packages"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, err
+	}
+
+	licenses := make([]*DependencyLicense, 0, len(lock.Packages))
+	for location, pkg := range lock.Packages {
+		// the empty location is the project itself
+		idx := strings.LastIndex(location, "node_modules/")
+		if idx == -1 || pkg.Link || pkg.License == "" {
+			continue
+		}
+		licenses = append(licenses, &DependencyLicense{
+			Dependency: location[idx+len("node_modules/"):] + "@" + pkg.Version,
+			License:    string(pkg.License),
+		})
+	}
+	sortDependencyLicenses(licenses)
+	return licenses, nil
+}
+
+// parseComposerLockLicenses reads the licenses of the packages of a composer.lock.
+// A package with several licenses may be used under any of them.
+func parseComposerLockLicenses(content []byte) ([]*DependencyLicense, error) {
+	type composerPackage struct {
+		Name    string   `json:"name"`
+		Version string   `json:"version"`
+		License []string `json:"license"`
+	}
+	var lock struct {
+		Packages    []composerPackage `json:"packages"`
+		PackagesDev []composerPackage `json:"packages-dev"`
+	}
+	if err := json.Unmarshal(content, &lock); err != nil {
+		return nil, err
+	}
+
+	licenses := make([]*DependencyLicense, 0, len(lock.Packages)+len(lock.PackagesDev))
+	for _, pkg := range append(lock.Packages, lock.PackagesDev...) {
+		if len(pkg.License) == 0 {
+			continue
+		}
+		expression := strings.Join(pkg.License, " OR ")
+		if len(pkg.License) > 1 {
+			expression = "(" + expression + ")"
+		}
+		licenses = append(licenses, &DependencyLicense{
+			Dependency: pkg.Name + "@" + pkg.Version,
+			License:    expression,
+		})
+	}
+	sortDependencyLicenses(licenses)
+	return licenses, nil
+}
+
+func sortDependencyLicenses(licenses []*DependencyLicense) {
+	sort.Slice(licenses, func(i, j int) bool {
+		return licenses[i].Dependency < licenses[j].Dependency
+	})
+}
@@ -0,0 +1,54 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePackageLockLicenses(t *testing.T) {
+	licenses, err := parsePackageLockLicenses([]byte(`{
+	"lockfileVersion": 3,
+	"packages": {
+		"": {"name": "app", "version": "1.0.0", "license": "GPL-3.0-only"},
+		"node_modules/left-pad": {"version": "1.3.0", "license": "WTFPL"},
+		"node_modules/@scope/lib": {"version": "2.0.0", "license": "(MIT OR Apache-2.0)"},
+		"node_modules/@scope/lib/node_modules/old": {"version": "0.1.0", "license": {"type": "BSD-3-Clause"}},
+		"node_modules/unlicensed": {"version": "1.0.0"},
+		"node_modules/linked": {"resolved": "packages/linked", "link": true}
+	}
+}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []*DependencyLicense{
+		{Dependency: "@scope/lib@2.0.0", License: "(MIT OR Apache-2.0)"},
+		{Dependency: "left-pad@1.3.0", License: "WTFPL"},
+		{Dependency: "old@0.1.0", License: "BSD-3-Clause"},
+	}, licenses)
+
+	_, err = parsePackageLockLicenses([]byte(`{"packages": [`))
+	assert.Error(t, err)
+}
+
+func TestParseComposerLockLicenses(t *testing.T) {
+	licenses, err := parseComposerLockLicenses([]byte(`{
+	"packages": [
+		{"name": "monolog/monolog", "version": "3.4.0", "license": ["MIT"]},
+		{"name": "dual/licensed", "version": "1.0.0", "license": ["LGPL-2.1-only", "GPL-3.0-or-later"]},
+		{"name": "proprietary/lib", "version": "1.0.0", "license": []}
+	],
+	"packages-dev": [
+		{"name": "phpunit/phpunit", "version": "10.3.0", "license": ["BSD-3-Clause"]}
+	]
+}`))
+	assert.NoError(t, err)
+	assert.Equal(t, []*DependencyLicense{
+		{Dependency: "dual/licensed@1.0.0", License: "(LGPL-2.1-only OR GPL-3.0-or-later)"},
+		{Dependency: "monolog/monolog@3.4.0", License: "MIT"},
+		{Dependency: "phpunit/phpunit@10.3.0", License: "BSD-3-Clause"},
+	}, licenses)
+
+	assert.Equal(t, []string{"(LGPL-2.1-only OR GPL-3.0-or-later)", "BSD-3-Clause", "MIT"}, DependencyLicenseNames(append(licenses, &DependencyLicense{License: "MIT"})))
+}
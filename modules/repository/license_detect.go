@@ -0,0 +1,211 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/options"
+)
+
+// licenseMatchThreshold is the minimal similarity for a license text to be considered a match
+const licenseMatchThreshold = 0.85
+
+// maxLicenseFileSize is the largest file which will be inspected for a license
+const maxLicenseFileSize = 256 * 1024
+
+var (
+	licenseFileNameReg = regexp.MustCompile(`(?i)^(un)?licen[sc]e|^copying|^copyright`)
+	spdxIdentifierReg  = regexp.MustCompile(`SPDX-License-Identifier:\s*([A-Za-z0-9.+-]+)`)
+
+	licenseBigramsMu    sync.Mutex
+	licenseBigramsCache = map[string]map[string]struct{}{}
+)
+
+// DetectedLicense represents a license which was found in a repository
+type DetectedLicense struct {
+	Name       string
+	Path       string
+	Confidence float64
+}
+
+// IsLicenseFileName returns true if the file name looks like a license file, e.g. LICENSE, COPYING.md
+func IsLicenseFileName(name string) bool {
+	return licenseFileNameReg.MatchString(path.Base(name))
+}
+
+// DetectLicensesFromCommit detects the licenses of the files in the root directory of the commit
+func DetectLicensesFromCommit(commit *git.Commit) ([]*DetectedLicense, error) {
+	entries, err := commit.Tree.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+
+	var detected []*DetectedLicense
+	for _, entry := range entries {
+		l, err := detectLicenseOfEntry(entry)
+		if err != nil {
+			return nil, err
+		} else if l != nil {
+			detected = append(detected, l)
+		}
+	}
+	return detected, nil
+}
+
+// DetectLicensesFromPaths detects the licenses of the given files of the commit. Only license files in the root
+// directory are inspected, like DetectLicensesFromCommit does, and files which don't exist in the commit are skipped.
+func DetectLicensesFromPaths(commit *git.Commit, paths []string) ([]*DetectedLicense, error) {
+	var detected []*DetectedLicense
+	for _, p := range paths {
+		if strings.Contains(p, "/") || !IsLicenseFileName(p) {
+			continue
+		}
+		entry, err := commit.GetTreeEntryByPath(p)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		l, err := detectLicenseOfEntry(entry)
+		if err != nil {
+			return nil, err
+		} else if l != nil {
+			detected = append(detected, l)
+		}
+	}
+	return detected, nil
+}
+
+func detectLicenseOfEntry(entry *git.TreeEntry) (*DetectedLicense, error) {
+	if !entry.IsRegular() || !IsLicenseFileName(entry.Name()) || entry.Size() > maxLicenseFileSize {
+		return nil, nil
+	}
+	content, err := entry.Blob().GetBlobContent()
+	if err != nil {
+		return nil, err
+	}
+	name, confidence := DetectLicense([]byte(content))
+	if name == "" {
+		return nil, nil
+	}
+	return &DetectedLicense{
+		Name:       name,
+		Path:       entry.Name(),
+		Confidence: confidence,
+	}, nil
+}
+
+// DetectLicense returns the name of the license which matches the content best and the similarity of both texts.
+// An empty name is returned if no known license is similar enough.
+func DetectLicense(content []byte) (string, float64) {
+	return detectLicense(content, Licenses)
+}
+
+func detectLicense(content []byte, candidates []string) (string, float64) {
+	if id := spdxIdentifier(content); id != "" {
+		for _, name := range candidates {
+			if strings.EqualFold(name, id) {
+				return name, 1
+			}
+		}
+	}
+
+	bigrams := licenseBigrams(content)
+	if len(bigrams) == 0 {
+		return "", 0
+	}
+
+	var bestName string
+	var bestScore float64
+	for _, name := range candidates {
+		score := diceCoefficient(bigrams, cachedLicenseBigrams(name))
+		if score > bestScore {
+			bestName, bestScore = name, score
+		}
+	}
+	if bestScore < licenseMatchThreshold {
+		return "", bestScore
+	}
+	return bestName, bestScore
+}
+
+// spdxIdentifier returns the value of the first SPDX-License-Identifier tag in the first lines of the content
+func spdxIdentifier(content []byte) string {
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for i := 0; i < 5 && scanner.Scan(); i++ {
+		if m := spdxIdentifierReg.FindStringSubmatch(scanner.Text()); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+func cachedLicenseBigrams(name string) map[string]struct{} {
+	licenseBigramsMu.Lock()
+	defer licenseBigramsMu.Unlock()
+
+	if bigrams, ok := licenseBigramsCache[name]; ok {
+		return bigrams
+	}
+	data, err := options.License(name)
+	if err != nil {
+		log.Error("Unable to load license %s: %v", name, err)
+	}
+	bigrams := licenseBigrams(data)
+	licenseBigramsCache[name] = bigrams
+	return bigrams
+}
+
+// licenseBigrams normalizes the text (case, punctuation, whitespace) and returns the set of word pairs
+func licenseBigrams(content []byte) map[string]struct{} {
+	words := strings.FieldsFunc(strings.ToLower(string(content)), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	bigrams := make(map[string]struct{}, len(words))
+	for i := 1; i < len(words); i++ {
+		bigrams[words[i-1]+" "+words[i]] = struct{}{}
+	}
+	return bigrams
+}
+
+func diceCoefficient(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if len(a) > len(b) {
+		a, b = b, a
+	}
+	common := 0
+	for k := range a {
+		if _, ok := b[k]; ok {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+// LicenseNames returns the sorted, de-duplicated names of the detected licenses
+func LicenseNames(licenses []*DetectedLicense) []string {
+	names := make([]string, 0, len(licenses))
+	seen := make(map[string]bool, len(licenses))
+	for _, l := range licenses {
+		if !seen[l.Name] {
+			seen[l.Name] = true
+			names = append(names, l.Name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsLicenseFileName(t *testing.T) {
+	for _, name := range []string{"LICENSE", "LICENSE.md", "license.txt", "LICENCE", "COPYING", "COPYING.LESSER", "UNLICENSE"} {
+		assert.True(t, IsLicenseFileName(name), name)
+	}
+	for _, name := range []string{"README.md", "main.go", "docs/readme"} {
+		assert.False(t, IsLicenseFileName(name), name)
+	}
+}
+
+func Test_detectLicense(t *testing.T) {
+	candidates := []string{"Apache-2.0", "BSD-3-Clause", "MIT"}
+
+	mit, err := getLicense("MIT", &licenseValues{Owner: "Gitea", Year: "2023"})
+	assert.NoError(t, err)
+	name, confidence := detectLicense(mit, candidates)
+	assert.Equal(t, "MIT", name)
+	assert.Greater(t, confidence, licenseMatchThreshold)
+
+	apache, err := getLicense("Apache-2.0", &licenseValues{Owner: "Gitea", Year: "2023"})
+	assert.NoError(t, err)
+	name, _ = detectLicense(apache, candidates)
+	assert.Equal(t, "Apache-2.0", name)
+
+	name, confidence = detectLicense([]byte("// SPDX-License-Identifier: bsd-3-clause\n"), candidates)
+	assert.Equal(t, "BSD-3-Clause", name)
+	assert.EqualValues(t, 1, confidence)
+
+	name, _ = detectLicense([]byte("All rights reserved. Do not copy this software."), candidates)
+	assert.Empty(t, name)
+}
+
+func TestLicenseNames(t *testing.T) {
+	assert.Equal(t, []string{"Apache-2.0", "MIT"}, LicenseNames([]*DetectedLicense{
+		{Name: "MIT", Path: "LICENSE"},
+		{Name: "Apache-2.0", Path: "LICENSE-APACHE"},
+		{Name: "MIT", Path: "LICENSE-MIT"},
+	}))
+}
@@ -137,6 +137,9 @@ func MigrateRepositoryGitData(ctx context.Context, u *user_model.User,
 	}
 	defer gitRepo.Close()
 
+	// the clone keeps the object format of the remote repository
+	repo.ObjectFormatName = gitRepo.ObjectFormat().String()
+
 	repo.IsEmpty, err = gitRepo.IsEmpty()
 	if err != nil {
 		return repo, fmt.Errorf("git.IsEmpty: %w", err)
@@ -380,7 +383,7 @@ func PushUpdateAddTag(ctx context.Context, repo *repo_model.Repository, gitRepo
 
 // StoreMissingLfsObjectsInRepository downloads missing LFS objects
 func StoreMissingLfsObjectsInRepository(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, lfsClient lfs.Client) error {
-	contentStore := lfs.NewContentStore()
+	contentStore := lfs.NewContentStoreOfRegion(repo.StorageRegion())
 
 	pointerChan := make(chan lfs.PointerBlob)
 	errChan := make(chan error, 1)
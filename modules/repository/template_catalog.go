@@ -0,0 +1,171 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"strings"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/options"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// builtinTemplateNames returns the templates of a kind which are provided by bindata & custom-path
+func builtinTemplateNames(kind repo_model.TemplateCatalogKind) []string {
+	switch kind {
+	case repo_model.TemplateCatalogGitignore:
+		return Gitignores
+	case repo_model.TemplateCatalogLicense:
+		return Licenses
+	}
+	return nil
+}
+
+// mergeTemplateNames appends the names of the catalog items which aren't in the list yet
+func mergeTemplateNames(names []string, items ...[]*repo_model.TemplateCatalogItem) []string {
+	seen := container.SetOf(names...)
+	for _, list := range items {
+		for _, item := range list {
+			if seen.Add(item.Name) {
+				names = append(names, item.Name)
+			}
+		}
+	}
+	return names
+}
+
+func getTemplateCatalogNames(ctx context.Context, ownerID int64, kind repo_model.TemplateCatalogKind, names []string) ([]string, error) {
+	instanceItems, err := repo_model.FindTemplateCatalogItems(ctx, 0, kind)
+	if err != nil {
+		return nil, err
+	}
+	if ownerID == 0 {
+		return mergeTemplateNames(names, instanceItems), nil
+	}
+	ownerItems, err := repo_model.FindTemplateCatalogItems(ctx, ownerID, kind)
+	if err != nil {
+		return nil, err
+	}
+	return mergeTemplateNames(names, instanceItems, ownerItems), nil
+}
+
+// GetTemplateCatalogNames returns the names of the templates of a kind offered to the repositories of an owner: the
+// built-in templates first, followed by the templates added to the catalog of the instance and of the owner.
+// ownerID 0 only returns the templates of the instance.
+func GetTemplateCatalogNames(ctx context.Context, ownerID int64, kind repo_model.TemplateCatalogKind) ([]string, error) {
+	builtin := builtinTemplateNames(kind)
+	names := make([]string, len(builtin), len(builtin)+10)
+	copy(names, builtin)
+	return getTemplateCatalogNames(ctx, ownerID, kind, names)
+}
+
+// GetCustomTemplateCatalogNames returns the names of the templates of a kind added to the catalog of the instance
+// and of the owner, without the built-in templates
+func GetCustomTemplateCatalogNames(ctx context.Context, ownerID int64, kind repo_model.TemplateCatalogKind) ([]string, error) {
+	return getTemplateCatalogNames(ctx, ownerID, kind, make([]string, 0, 10))
+}
+
+// CheckTemplateCatalogSelection checks that the comma-separated templates are offered to the repositories of an owner
+func CheckTemplateCatalogSelection(ctx context.Context, ownerID int64, kind repo_model.TemplateCatalogKind, names string) error {
+	if names == "" {
+		return nil
+	}
+	offered, err := GetTemplateCatalogNames(ctx, ownerID, kind)
+	if err != nil {
+		return err
+	}
+	for _, name := range strings.Split(names, ",") {
+		if !util.SliceContainsString(offered, name) {
+			return repo_model.ErrTemplateCatalogItemNotExist{OwnerID: ownerID, Kind: kind, Name: name}
+		}
+	}
+	return nil
+}
+
+// getTemplateCatalogItem returns the item of the catalog of the owner, falling back to the catalog of the instance.
+// It returns nil if neither has an item with the name.
+func getTemplateCatalogItem(ctx context.Context, ownerID int64, kind repo_model.TemplateCatalogKind, name string) (*repo_model.TemplateCatalogItem, error) {
+	ownerIDs := []int64{0}
+	if ownerID > 0 {
+		ownerIDs = []int64{ownerID, 0}
+	}
+	for _, id := range ownerIDs {
+		item, err := repo_model.GetTemplateCatalogItem(ctx, id, kind, name)
+		if err == nil {
+			return item, nil
+		} else if !repo_model.IsErrTemplateCatalogItemNotExist(err) {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+// GetTemplateCatalogContent returns the content of a template offered to the repositories of an owner. The catalog of
+// the owner takes precedence over the catalog of the instance, which takes precedence over the built-in templates.
+func GetTemplateCatalogContent(ctx context.Context, ownerID int64, kind repo_model.TemplateCatalogKind, name string) ([]byte, error) {
+	notExist := repo_model.ErrTemplateCatalogItemNotExist{OwnerID: ownerID, Kind: kind, Name: name}
+	if repo_model.ValidateTemplateCatalogName(kind, name) != nil {
+		return nil, notExist
+	}
+
+	item, err := getTemplateCatalogItem(ctx, ownerID, kind, name)
+	if err != nil {
+		return nil, err
+	} else if item != nil {
+		return []byte(item.Content), nil
+	}
+
+	var data []byte
+	switch kind {
+	case repo_model.TemplateCatalogGitignore:
+		data, err = options.Gitignore(name)
+	case repo_model.TemplateCatalogLicense:
+		data, err = options.License(name)
+	default:
+		return nil, notExist
+	}
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, notExist
+	}
+	return data, err
+}
+
+// GetTemplateCatalogFile returns the path and the content of the file a template adds to a repository, the
+// placeholders of licenses are filled with the names of the repository and of its owner
+func GetTemplateCatalogFile(ctx context.Context, repo *repo_model.Repository, kind repo_model.TemplateCatalogKind, name string) (string, []byte, error) {
+	if kind == repo_model.TemplateCatalogLicense {
+		data, err := getCatalogLicense(ctx, repo.OwnerID, name, &licenseValues{
+			Owner: repo.OwnerName,
+			Repo:  repo.Name,
+			Year:  time.Now().Format("2006"),
+		})
+		if err != nil {
+			return "", nil, err
+		}
+		return "LICENSE", data, nil
+	}
+
+	data, err := GetTemplateCatalogContent(ctx, repo.OwnerID, kind, name)
+	if err != nil {
+		return "", nil, err
+	}
+	if kind == repo_model.TemplateCatalogGitignore {
+		return ".gitignore", data, nil
+	}
+	return kind.Dir() + "/" + name, data, nil
+}
+
+// getCatalogLicense returns the license offered to the repositories of an owner with its placeholders filled
+func getCatalogLicense(ctx context.Context, ownerID int64, name string, values *licenseValues) ([]byte, error) {
+	data, err := GetTemplateCatalogContent(ctx, ownerID, repo_model.TemplateCatalogLicense, name)
+	if err != nil {
+		return nil, err
+	}
+	return fillLicensePlaceholder(name, values, data), nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTemplateCatalog(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	oldGitignores := Gitignores
+	defer func() { Gitignores = oldGitignores }()
+	Gitignores = []string{"Go", "Rust"}
+
+	for _, item := range []*repo_model.TemplateCatalogItem{
+		{OwnerID: 0, Kind: repo_model.TemplateCatalogGitignore, Name: "Corp", Content: "instance"},
+		{OwnerID: 0, Kind: repo_model.TemplateCatalogGitignore, Name: "Go", Content: "instance go"},
+		{OwnerID: 3, Kind: repo_model.TemplateCatalogGitignore, Name: "Corp", Content: "org"},
+		{OwnerID: 3, Kind: repo_model.TemplateCatalogGitignore, Name: "Team", Content: "team"},
+	} {
+		assert.NoError(t, repo_model.SaveTemplateCatalogItem(db.DefaultContext, item))
+	}
+
+	names, err := GetTemplateCatalogNames(db.DefaultContext, 0, repo_model.TemplateCatalogGitignore)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Go", "Rust", "Corp"}, names)
+
+	names, err = GetTemplateCatalogNames(db.DefaultContext, 3, repo_model.TemplateCatalogGitignore)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Go", "Rust", "Corp", "Team"}, names)
+
+	names, err = GetCustomTemplateCatalogNames(db.DefaultContext, 3, repo_model.TemplateCatalogGitignore)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"Corp", "Go", "Team"}, names)
+
+	// the catalog of the owner takes precedence over the catalog of the instance, which overrides the built-in templates
+	data, err := GetTemplateCatalogContent(db.DefaultContext, 3, repo_model.TemplateCatalogGitignore, "Corp")
+	assert.NoError(t, err)
+	assert.Equal(t, "org", string(data))
+	data, err = GetTemplateCatalogContent(db.DefaultContext, 2, repo_model.TemplateCatalogGitignore, "Corp")
+	assert.NoError(t, err)
+	assert.Equal(t, "instance", string(data))
+	data, err = GetTemplateCatalogContent(db.DefaultContext, 3, repo_model.TemplateCatalogGitignore, "Go")
+	assert.NoError(t, err)
+	assert.Equal(t, "instance go", string(data))
+
+	_, err = GetTemplateCatalogContent(db.DefaultContext, 2, repo_model.TemplateCatalogGitignore, "Team")
+	assert.True(t, repo_model.IsErrTemplateCatalogItemNotExist(err))
+	_, err = GetTemplateCatalogContent(db.DefaultContext, 3, repo_model.TemplateCatalogIssueTemplate, "bug.md")
+	assert.True(t, repo_model.IsErrTemplateCatalogItemNotExist(err))
+
+	assert.NoError(t, CheckTemplateCatalogSelection(db.DefaultContext, 3, repo_model.TemplateCatalogGitignore, "Rust,Team"))
+	assert.True(t, repo_model.IsErrTemplateCatalogItemNotExist(CheckTemplateCatalogSelection(db.DefaultContext, 2, repo_model.TemplateCatalogGitignore, "Rust,Team")))
+}
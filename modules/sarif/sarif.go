@@ -0,0 +1,254 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package sarif
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+)
+
+var (
+	// ErrInvalidSARIF is returned for files which are not valid SARIF JSON
+	ErrInvalidSARIF = errors.New("invalid SARIF file")
+	// ErrUnsupportedVersion is returned for SARIF files which are not version 2.1.0
+	ErrUnsupportedVersion = errors.New("unsupported SARIF version")
+)
+
+// Severity is the normalized severity of a finding
+type Severity string
+
+// The known severities, from most to least severe
+const (
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityMedium   Severity = "medium"
+	SeverityLow      Severity = "low"
+)
+
+// Rank returns a number which is higher for more severe findings
+func (s Severity) Rank() int {
+	switch s {
+	case SeverityCritical:
+		return 4
+	case SeverityHigh:
+		return 3
+	case SeverityMedium:
+		return 2
+	case SeverityLow:
+		return 1
+	}
+	return 0
+}
+
+// IsValid checks if the severity is known
+func (s Severity) IsValid() bool {
+	return s.Rank() > 0
+}
+
+// Log is the root object of a SARIF file. Only the fields used by Gitea are declared.
+type Log struct {
+	Version string `json:"version"`
+	Runs    []Run  `json:"runs"`
+}
+
+// Run describes a single invocation of an analysis tool
+type Run struct {
+	Tool    Tool     `json:"tool"`
+	Results []Result `json:"results"`
+}
+
+// Tool describes the analysis tool
+type Tool struct {
+	Driver ToolComponent `json:"driver"`
+}
+
+// ToolComponent describes the tool driver and its rules
+type ToolComponent struct {
+	Name    string                `json:"name"`
+	Version string                `json:"version"`
+	Rules   []ReportingDescriptor `json:"rules"`
+}
+
+// ReportingDescriptor describes a rule
+type ReportingDescriptor struct {
+	ID                   string                 `json:"id"`
+	ShortDescription     *Message               `json:"shortDescription"`
+	DefaultConfiguration *ReportingConfig       `json:"defaultConfiguration"`
+	Properties           map[string]interface{} `json:"properties"`
+}
+
+// ReportingConfig is the default configuration of a rule
+type ReportingConfig struct {
+	Level string `json:"level"`
+}
+
+// Message is a SARIF message
+type Message struct {
+	Text string `json:"text"`
+}
+
+// Result is a single finding
+type Result struct {
+	RuleID              string            `json:"ruleId"`
+	RuleIndex           *int              `json:"ruleIndex"`
+	Level               string            `json:"level"`
+	Message             Message           `json:"message"`
+	Locations           []Location        `json:"locations"`
+	PartialFingerprints map[string]string `json:"partialFingerprints"`
+}
+
+// Location is the location of a finding
+type Location struct {
+	PhysicalLocation *PhysicalLocation `json:"physicalLocation"`
+}
+
+// PhysicalLocation is a location in a file
+type PhysicalLocation struct {
+	ArtifactLocation ArtifactLocation `json:"artifactLocation"`
+	Region           *Region          `json:"region"`
+}
+
+// ArtifactLocation points to a file
+type ArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+// Region is a range of lines in a file
+type Region struct {
+	StartLine int `json:"startLine"`
+	EndLine   int `json:"endLine"`
+}
+
+// Finding is a flattened SARIF result
+type Finding struct {
+	Tool        string
+	RuleID      string
+	Description string
+	Severity    Severity
+	Message     string
+	Path        string
+	StartLine   int
+	EndLine     int
+	Fingerprint string
+}
+
+// Parse reads and validates a SARIF log
+func Parse(r io.Reader) (*Log, error) {
+	var log Log
+	if err := json.NewDecoder(r).Decode(&log); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidSARIF, err)
+	}
+	if log.Version != "2.1.0" {
+		return nil, ErrUnsupportedVersion
+	}
+	return &log, nil
+}
+
+// Findings flattens all results of all runs. Results without a location are ignored.
+func (l *Log) Findings() []*Finding {
+	var findings []*Finding
+	for _, run := range l.Runs {
+		rules := make(map[string]*ReportingDescriptor, len(run.Tool.Driver.Rules))
+		for i := range run.Tool.Driver.Rules {
+			rules[run.Tool.Driver.Rules[i].ID] = &run.Tool.Driver.Rules[i]
+		}
+
+		for _, result := range run.Results {
+			if len(result.Locations) == 0 || result.Locations[0].PhysicalLocation == nil {
+				continue
+			}
+			loc := result.Locations[0].PhysicalLocation
+
+			var rule *ReportingDescriptor
+			if r, ok := rules[result.RuleID]; ok {
+				rule = r
+			} else if result.RuleIndex != nil && *result.RuleIndex >= 0 && *result.RuleIndex < len(run.Tool.Driver.Rules) {
+				rule = &run.Tool.Driver.Rules[*result.RuleIndex]
+			}
+
+			f := &Finding{
+				Tool:     run.Tool.Driver.Name,
+				RuleID:   result.RuleID,
+				Severity: severity(rule, result.Level),
+				Message:  result.Message.Text,
+				Path:     strings.TrimPrefix(loc.ArtifactLocation.URI, "file://"),
+			}
+			if f.RuleID == "" && rule != nil {
+				f.RuleID = rule.ID
+			}
+			if rule != nil && rule.ShortDescription != nil {
+				f.Description = rule.ShortDescription.Text
+			}
+			if loc.Region != nil {
+				f.StartLine = loc.Region.StartLine
+				f.EndLine = loc.Region.EndLine
+			}
+			if f.EndLine < f.StartLine {
+				f.EndLine = f.StartLine
+			}
+			f.Fingerprint = fingerprint(f, result.PartialFingerprints)
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+// severity prefers the numeric "security-severity" of the rule and falls back to the result level
+func severity(rule *ReportingDescriptor, level string) Severity {
+	if rule != nil {
+		if v, ok := rule.Properties["security-severity"]; ok {
+			if score, err := strconv.ParseFloat(fmt.Sprint(v), 64); err == nil {
+				switch {
+				case score >= 9:
+					return SeverityCritical
+				case score >= 7:
+					return SeverityHigh
+				case score >= 4:
+					return SeverityMedium
+				default:
+					return SeverityLow
+				}
+			}
+		}
+		if level == "" && rule.DefaultConfiguration != nil {
+			level = rule.DefaultConfiguration.Level
+		}
+	}
+	switch level {
+	case "error":
+		return SeverityHigh
+	case "note", "none":
+		return SeverityLow
+	default: // "warning" is the default level of SARIF
+		return SeverityMedium
+	}
+}
+
+// fingerprint identifies a finding across analyses. Partial fingerprints provided by the tool are
+// preferred because they are stable when the surrounding code moves.
+func fingerprint(f *Finding, partial map[string]string) string {
+	h := sha256.New()
+	_, _ = io.WriteString(h, f.Tool+"\x00"+f.RuleID+"\x00"+f.Path+"\x00")
+	if len(partial) > 0 {
+		keys := make([]string, 0, len(partial))
+		for k := range partial {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			_, _ = io.WriteString(h, k+"="+partial[k]+"\x00")
+		}
+	} else {
+		_, _ = io.WriteString(h, strconv.Itoa(f.StartLine)+"\x00"+f.Message)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package sarif
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testLog = `{
+  "version": "2.1.0",
+  "runs": [{
+    "tool": {"driver": {"name": "gosec", "rules": [
+      {"id": "G101", "shortDescription": {"text": "Hardcoded credentials"}, "properties": {"security-severity": "9.1"}},
+      {"id": "G104", "defaultConfiguration": {"level": "note"}}
+    ]}},
+    "results": [
+      {"ruleId": "G101", "message": {"text": "Potential hardcoded credentials"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "main.go"}, "region": {"startLine": 12}}}],
+       "partialFingerprints": {"primaryLocationLineHash": "abc"}},
+      {"ruleId": "G104", "message": {"text": "Errors unhandled"},
+       "locations": [{"physicalLocation": {"artifactLocation": {"uri": "file://cmd/run.go"}, "region": {"startLine": 3, "endLine": 5}}}]},
+      {"ruleId": "G104", "level": "error", "message": {"text": "no location"}}
+    ]
+  }]
+}`
+
+func TestParse(t *testing.T) {
+	log, err := Parse(strings.NewReader(testLog))
+	assert.NoError(t, err)
+
+	findings := log.Findings()
+	assert.Len(t, findings, 2)
+
+	assert.Equal(t, "gosec", findings[0].Tool)
+	assert.Equal(t, "G101", findings[0].RuleID)
+	assert.Equal(t, "Hardcoded credentials", findings[0].Description)
+	assert.Equal(t, SeverityCritical, findings[0].Severity)
+	assert.Equal(t, "main.go", findings[0].Path)
+	assert.Equal(t, 12, findings[0].StartLine)
+	assert.Equal(t, 12, findings[0].EndLine)
+
+	assert.Equal(t, SeverityLow, findings[1].Severity)
+	assert.Equal(t, "cmd/run.go", findings[1].Path)
+	assert.Equal(t, 5, findings[1].EndLine)
+	assert.NotEqual(t, findings[0].Fingerprint, findings[1].Fingerprint)
+
+	_, err = Parse(strings.NewReader(`{"version": "1.0.0"}`))
+	assert.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	_, err = Parse(strings.NewReader(`{"version": "2.1.0", "runs": [`))
+	assert.ErrorIs(t, err, ErrInvalidSARIF)
+}
@@ -4,6 +4,12 @@
 package setting
 
 import (
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"code.gitea.io/gitea/modules/log"
 )
 
@@ -14,21 +20,103 @@ var (
 		ArtifactStorage   Storage // how the created artifacts should be stored
 		Enabled           bool
 		DefaultActionsURL string `ini:"DEFAULT_ACTIONS_URL"`
+		// RegistryCredentials provides the jobs a docker configuration to log into the container registry
+		RegistryCredentials bool
+		// ArtifactRetentionDays is the number of days artifacts are kept if the workflow doesn't set it
+		ArtifactRetentionDays int64 `ini:"ARTIFACT_RETENTION_DAYS"`
+		// LogRetentionDays is the number of days the logs of finished tasks are kept
+		LogRetentionDays int64 `ini:"LOG_RETENTION_DAYS"`
+		// DefaultApprovalPolicy defines which runs of pull requests from forks need an approval, if the repository doesn't set it:
+		// first_run, first_contribution or all_forks
+		DefaultApprovalPolicy string
+	}{
+		Enabled:               false,
+		DefaultActionsURL:     "https://gitea.com",
+		RegistryCredentials:   true,
+		ArtifactRetentionDays: 90,
+		LogRetentionDays:      365,
+		DefaultApprovalPolicy: "first_run",
+	}
+
+	// ActionsKubernetes settings of the dispatch of jobs to Kubernetes, where every job runs in an own pod
+	ActionsKubernetes = struct {
+		Enabled bool
+		// APIURL of the Kubernetes API server, the one of the cluster Gitea runs in if empty
+		APIURL    string `ini:"API_URL"`
+		TokenFile string
+		CAFile    string `ini:"CA_FILE"`
+		Namespace string
+		// RunnerImage is the image of the runner container, if the pod template doesn't have one
+		RunnerImage string
+		// InstanceURL is the URL the runners connect to, the ROOT_URL if empty
+		InstanceURL     string `ini:"INSTANCE_URL"`
+		PodTemplatePath string
+		MaxJobs         int
+		PollInterval    time.Duration
+		// PendingTimeout is how long a pod can take to start its job before it's deleted
+		PendingTimeout time.Duration
 	}{
-		Enabled:           false,
-		DefaultActionsURL: "https://gitea.com",
+		Enabled:        false,
+		TokenFile:      kubernetesServiceAccountPath + "token",
+		CAFile:         kubernetesServiceAccountPath + "ca.crt",
+		RunnerImage:    "gitea/act_runner:latest",
+		MaxJobs:        10,
+		PollInterval:   10 * time.Second,
+		PendingTimeout: 10 * time.Minute,
 	}
 )
 
+const kubernetesServiceAccountPath = "/var/run/secrets/kubernetes.io/serviceaccount/"
+
 func loadActionsFrom(rootCfg ConfigProvider) {
 	sec := rootCfg.Section("actions")
 	if err := sec.MapTo(&Actions); err != nil {
 		log.Fatal("Failed to map Actions settings: %v", err)
 	}
+	switch Actions.DefaultApprovalPolicy {
+	case "first_run", "first_contribution", "all_forks":
+	default:
+		log.Fatal("Invalid [actions] DEFAULT_APPROVAL_POLICY: %q", Actions.DefaultApprovalPolicy)
+	}
 
 	actionsSec := rootCfg.Section("actions.artifacts")
 	storageType := actionsSec.Key("STORAGE_TYPE").MustString("")
 
 	Actions.LogStorage = getStorage(rootCfg, "actions_log", "", nil)
 	Actions.ArtifactStorage = getStorage(rootCfg, "actions_artifacts", storageType, actionsSec)
+
+	loadActionsKubernetesFrom(rootCfg)
+}
+
+func loadActionsKubernetesFrom(rootCfg ConfigProvider) {
+	sec := rootCfg.Section("actions.kubernetes")
+	if err := sec.MapTo(&ActionsKubernetes); err != nil {
+		log.Fatal("Failed to map Actions Kubernetes settings: %v", err)
+	}
+	if !ActionsKubernetes.Enabled {
+		return
+	}
+
+	if ActionsKubernetes.APIURL == "" {
+		host, port := os.Getenv("KUBERNETES_SERVICE_HOST"), os.Getenv("KUBERNETES_SERVICE_PORT")
+		if host == "" || port == "" {
+			log.Fatal("[actions.kubernetes] API_URL must be set if Gitea doesn't run in a Kubernetes cluster")
+		}
+		ActionsKubernetes.APIURL = "https://" + net.JoinHostPort(host, port)
+	}
+	ActionsKubernetes.APIURL = strings.TrimSuffix(ActionsKubernetes.APIURL, "/")
+	if ActionsKubernetes.Namespace == "" {
+		ActionsKubernetes.Namespace = "default"
+		if namespace, err := os.ReadFile(kubernetesServiceAccountPath + "namespace"); err == nil {
+			ActionsKubernetes.Namespace = strings.TrimSpace(string(namespace))
+		}
+	}
+	if ActionsKubernetes.InstanceURL == "" {
+		ActionsKubernetes.InstanceURL = AppURL
+	}
+	if ActionsKubernetes.PodTemplatePath == "" {
+		ActionsKubernetes.PodTemplatePath = filepath.Join(CustomPath, "actions", "kubernetes")
+	} else if !filepath.IsAbs(ActionsKubernetes.PodTemplatePath) {
+		ActionsKubernetes.PodTemplatePath = filepath.Join(CustomPath, ActionsKubernetes.PodTemplatePath)
+	}
 }
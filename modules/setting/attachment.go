@@ -10,6 +10,9 @@ var Attachment = struct {
 	MaxSize      int64
 	MaxFiles     int
 	Enabled      bool
+	Deduplicate  bool
+	// ThumbnailSize is the maximum width and height of the thumbnails generated for images, 0 disables them
+	ThumbnailSize int
 }{
 	Storage: Storage{
 		ServeDirect: false,
@@ -18,6 +21,7 @@ var Attachment = struct {
 	MaxSize:      4,
 	MaxFiles:     5,
 	Enabled:      true,
+	Deduplicate:  true,
 }
 
 func loadAttachmentFrom(rootCfg ConfigProvider) {
@@ -30,4 +34,6 @@ func loadAttachmentFrom(rootCfg ConfigProvider) {
 	Attachment.MaxSize = sec.Key("MAX_SIZE").MustInt64(4)
 	Attachment.MaxFiles = sec.Key("MAX_FILES").MustInt(5)
 	Attachment.Enabled = sec.Key("ENABLED").MustBool(true)
+	Attachment.Deduplicate = sec.Key("DEDUPLICATE").MustBool(true)
+	Attachment.ThumbnailSize = sec.Key("THUMBNAIL_SIZE").MustInt(0)
 }
@@ -46,6 +46,9 @@ var (
 		ConnMaxLifetime   time.Duration
 		IterateBufferSize int
 		AutoMigration     bool
+
+		BackgroundMigrationBatchSize  int
+		BackgroundMigrationBatchDelay time.Duration
 	}{
 		Timeout:           500,
 		IterateBufferSize: 50,
@@ -96,6 +99,8 @@ func loadDBSetting(rootCfg ConfigProvider) {
 	Database.DBConnectRetries = sec.Key("DB_RETRIES").MustInt(10)
 	Database.DBConnectBackoff = sec.Key("DB_RETRY_BACKOFF").MustDuration(3 * time.Second)
 	Database.AutoMigration = sec.Key("AUTO_MIGRATION").MustBool(true)
+	Database.BackgroundMigrationBatchSize = sec.Key("BACKGROUND_MIGRATION_BATCH_SIZE").MustInt(1000)
+	Database.BackgroundMigrationBatchDelay = sec.Key("BACKGROUND_MIGRATION_BATCH_DELAY").MustDuration(100 * time.Millisecond)
 }
 
 // DBConnStr returns database connection string
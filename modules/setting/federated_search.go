@@ -0,0 +1,53 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// FederatedSearch settings
+var FederatedSearch = struct {
+	// Enabled answers the search requests of peer instances and merges the results of the peers into the federated search
+	Enabled bool
+	// Peers are the base URLs of the peer instances, which have to enable the federated search too
+	Peers []string
+	// Timeout is the time given to every peer to answer
+	Timeout time.Duration
+	// MaxResults limits the results returned by an instance
+	MaxResults int
+	// MaxWikiRepos limits the most recently updated repositories whose wikis are searched
+	MaxWikiRepos int
+}{
+	Enabled:      false,
+	Timeout:      5 * time.Second,
+	MaxResults:   20,
+	MaxWikiRepos: 50,
+}
+
+func loadFederatedSearchFrom(rootCfg ConfigProvider) {
+	if err := rootCfg.Section("federated_search").MapTo(&FederatedSearch); err != nil {
+		log.Fatal("Failed to map FederatedSearch settings: %v", err)
+	}
+
+	peers := make([]string, 0, len(FederatedSearch.Peers))
+	for _, peer := range FederatedSearch.Peers {
+		u, err := url.Parse(peer)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			log.Error("FederatedSearch.PEERS: %q isn't a http or https URL, ignored", peer)
+			continue
+		}
+		peers = append(peers, strings.TrimSuffix(peer, "/"))
+	}
+	FederatedSearch.Peers = peers
+
+	if FederatedSearch.MaxResults <= 0 {
+		log.Warn("FederatedSearch.MAX_RESULTS must be positive, set to 20")
+		FederatedSearch.MaxResults = 20
+	}
+}
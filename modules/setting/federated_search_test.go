@@ -0,0 +1,28 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoadFederatedSearch(t *testing.T) {
+	iniStr := `
+[federated_search]
+ENABLED = true
+PEERS = https://gitea.example.com/, ftp://gitea.example.com, http://10.0.0.2:3000/gitea
+TIMEOUT = 2s
+`
+	cfg, err := NewConfigProviderFromData(iniStr)
+	assert.NoError(t, err)
+	loadFederatedSearchFrom(cfg)
+
+	assert.True(t, FederatedSearch.Enabled)
+	assert.Equal(t, []string{"https://gitea.example.com", "http://10.0.0.2:3000/gitea"}, FederatedSearch.Peers)
+	assert.Equal(t, 2*time.Second, FederatedSearch.Timeout)
+	assert.Equal(t, 20, FederatedSearch.MaxResults)
+}
@@ -40,6 +40,24 @@ var Git = struct {
 		Pull    int
 		GC      int `ini:"GC"`
 	} `ini:"git.timeout"`
+	ManagedHooks struct {
+		Enabled       bool
+		Timeout       time.Duration
+		MaxCPUTime    time.Duration `ini:"MAX_CPU_TIME"`
+		MaxMemory     int64
+		MaxOutputSize int
+	} `ini:"git.managed_hooks"`
+	Scheduler struct {
+		Enabled              bool
+		MinConcurrency       int
+		MaxConcurrency       int
+		MaxPerRepo           int
+		MaxPerUser           int
+		MaxLoadPerCPU        float64 `ini:"MAX_LOAD_PER_CPU"`
+		MinFreeMemoryPercent int
+		SampleInterval       time.Duration
+		QueueTimeout         time.Duration
+	} `ini:"git.scheduler"`
 }{
 	Reflog: struct {
 		Enabled    bool
@@ -76,6 +94,40 @@ var Git = struct {
 		Pull:    300,
 		GC:      60,
 	},
+	ManagedHooks: struct {
+		Enabled       bool
+		Timeout       time.Duration
+		MaxCPUTime    time.Duration `ini:"MAX_CPU_TIME"`
+		MaxMemory     int64
+		MaxOutputSize int
+	}{
+		Enabled:       false,
+		Timeout:       60 * time.Second,
+		MaxCPUTime:    30 * time.Second,
+		MaxMemory:     512 * 1024 * 1024,
+		MaxOutputSize: 64 * 1024,
+	},
+	Scheduler: struct {
+		Enabled              bool
+		MinConcurrency       int
+		MaxConcurrency       int
+		MaxPerRepo           int
+		MaxPerUser           int
+		MaxLoadPerCPU        float64 `ini:"MAX_LOAD_PER_CPU"`
+		MinFreeMemoryPercent int
+		SampleInterval       time.Duration
+		QueueTimeout         time.Duration
+	}{
+		Enabled:              false,
+		MinConcurrency:       1,
+		MaxConcurrency:       0,
+		MaxPerRepo:           8,
+		MaxPerUser:           4,
+		MaxLoadPerCPU:        2,
+		MinFreeMemoryPercent: 10,
+		SampleInterval:       5 * time.Second,
+		QueueTimeout:         2 * time.Minute,
+	},
 }
 
 func loadGitFrom(rootCfg ConfigProvider) {
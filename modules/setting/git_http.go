@@ -0,0 +1,102 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"time"
+)
+
+// GitHTTPLimits are the limits of the git upload-pack and receive-pack commands served over HTTP
+// for the repositories of a size class
+type GitHTTPLimits struct {
+	UploadPackTimeout  time.Duration
+	ReceivePackTimeout time.Duration
+	// MaxUploadPackRequestSize limits the negotiation sent by a fetch or clone, -1 for no limit
+	MaxUploadPackRequestSize int64
+	// MaxReceivePackSize limits the pack sent by a push, -1 for no limit
+	MaxReceivePackSize int64
+}
+
+// Size classes of the repositories the limits of the smart HTTP protocol are configured for
+const (
+	GitHTTPSizeClassSmall  = "small"
+	GitHTTPSizeClassMedium = "medium"
+	GitHTTPSizeClassLarge  = "large"
+)
+
+// GitHTTP settings of the git smart HTTP protocol
+var GitHTTP = struct {
+	EnableProtocolV2 bool
+	AllowFilter      bool
+	AllowRefInWant   bool
+	// MediumRepoSize and LargeRepoSize are the sizes from which a repository is in the medium and large size class
+	MediumRepoSize int64
+	LargeRepoSize  int64
+	Small          GitHTTPLimits
+	Medium         GitHTTPLimits
+	Large          GitHTTPLimits
+}{
+	EnableProtocolV2: true,
+	AllowFilter:      true,
+	AllowRefInWant:   false,
+	MediumRepoSize:   1 << 30,
+	LargeRepoSize:    10 << 30,
+	Small: GitHTTPLimits{
+		UploadPackTimeout:        10 * time.Minute,
+		ReceivePackTimeout:       10 * time.Minute,
+		MaxUploadPackRequestSize: -1,
+		MaxReceivePackSize:       -1,
+	},
+	Medium: GitHTTPLimits{
+		UploadPackTimeout:        30 * time.Minute,
+		ReceivePackTimeout:       30 * time.Minute,
+		MaxUploadPackRequestSize: -1,
+		MaxReceivePackSize:       -1,
+	},
+	Large: GitHTTPLimits{
+		UploadPackTimeout:        2 * time.Hour,
+		ReceivePackTimeout:       2 * time.Hour,
+		MaxUploadPackRequestSize: -1,
+		MaxReceivePackSize:       -1,
+	},
+}
+
+// GitHTTPLimitsForRepoSize returns the size class of a repository and its limits
+func GitHTTPLimitsForRepoSize(size int64) (string, *GitHTTPLimits) {
+	switch {
+	case size >= GitHTTP.LargeRepoSize:
+		return GitHTTPSizeClassLarge, &GitHTTP.Large
+	case size >= GitHTTP.MediumRepoSize:
+		return GitHTTPSizeClassMedium, &GitHTTP.Medium
+	}
+	return GitHTTPSizeClassSmall, &GitHTTP.Small
+}
+
+func loadGitHTTPFrom(rootCfg ConfigProvider) {
+	sec := rootCfg.Section("git.http")
+	GitHTTP.EnableProtocolV2 = sec.Key("ENABLE_PROTOCOL_V2").MustBool(GitHTTP.EnableProtocolV2)
+	GitHTTP.AllowFilter = sec.Key("ALLOW_FILTER").MustBool(GitHTTP.AllowFilter)
+	GitHTTP.AllowRefInWant = sec.Key("ALLOW_REF_IN_WANT").MustBool(GitHTTP.AllowRefInWant)
+	if sec.HasKey("MEDIUM_REPO_SIZE") {
+		GitHTTP.MediumRepoSize = mustBytes(sec, "MEDIUM_REPO_SIZE")
+	}
+	if sec.HasKey("LARGE_REPO_SIZE") {
+		GitHTTP.LargeRepoSize = mustBytes(sec, "LARGE_REPO_SIZE")
+	}
+
+	loadGitHTTPLimits(rootCfg.Section("git.http.small"), &GitHTTP.Small)
+	loadGitHTTPLimits(rootCfg.Section("git.http.medium"), &GitHTTP.Medium)
+	loadGitHTTPLimits(rootCfg.Section("git.http.large"), &GitHTTP.Large)
+}
+
+func loadGitHTTPLimits(sec ConfigSection, limits *GitHTTPLimits) {
+	limits.UploadPackTimeout = sec.Key("UPLOAD_PACK_TIMEOUT").MustDuration(limits.UploadPackTimeout)
+	limits.ReceivePackTimeout = sec.Key("RECEIVE_PACK_TIMEOUT").MustDuration(limits.ReceivePackTimeout)
+	if sec.HasKey("MAX_UPLOAD_PACK_REQUEST_SIZE") {
+		limits.MaxUploadPackRequestSize = mustBytes(sec, "MAX_UPLOAD_PACK_REQUEST_SIZE")
+	}
+	if sec.HasKey("MAX_RECEIVE_PACK_SIZE") {
+		limits.MaxReceivePackSize = mustBytes(sec, "MAX_RECEIVE_PACK_SIZE")
+	}
+}
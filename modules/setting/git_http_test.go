@@ -0,0 +1,57 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_loadGitHTTPFrom(t *testing.T) {
+	defaults := GitHTTP
+	defer func() {
+		GitHTTP = defaults
+	}()
+
+	iniStr := `
+[git.http]
+ENABLE_PROTOCOL_V2 = false
+MEDIUM_REPO_SIZE = 100MiB
+LARGE_REPO_SIZE = 1GiB
+
+[git.http.small]
+UPLOAD_PACK_TIMEOUT = 1m
+MAX_RECEIVE_PACK_SIZE = 10MiB
+
+[git.http.large]
+MAX_UPLOAD_PACK_REQUEST_SIZE = 1MiB
+`
+	cfg, err := NewConfigProviderFromData(iniStr)
+	assert.NoError(t, err)
+	loadGitHTTPFrom(cfg)
+
+	assert.False(t, GitHTTP.EnableProtocolV2)
+	assert.True(t, GitHTTP.AllowFilter)
+	assert.EqualValues(t, 100<<20, GitHTTP.MediumRepoSize)
+	assert.EqualValues(t, 1<<30, GitHTTP.LargeRepoSize)
+
+	assert.Equal(t, time.Minute, GitHTTP.Small.UploadPackTimeout)
+	assert.Equal(t, 10*time.Minute, GitHTTP.Small.ReceivePackTimeout)
+	assert.EqualValues(t, 10<<20, GitHTTP.Small.MaxReceivePackSize)
+	assert.EqualValues(t, -1, GitHTTP.Small.MaxUploadPackRequestSize)
+
+	class, limits := GitHTTPLimitsForRepoSize(10 << 20)
+	assert.Equal(t, GitHTTPSizeClassSmall, class)
+	assert.Equal(t, &GitHTTP.Small, limits)
+
+	class, _ = GitHTTPLimitsForRepoSize(100 << 20)
+	assert.Equal(t, GitHTTPSizeClassMedium, class)
+
+	class, limits = GitHTTPLimitsForRepoSize(2 << 30)
+	assert.Equal(t, GitHTTPSizeClassLarge, class)
+	assert.EqualValues(t, 1<<20, limits.MaxUploadPackRequestSize)
+	assert.Equal(t, 2*time.Hour, limits.UploadPackTimeout)
+}
@@ -23,6 +23,13 @@ var Indexer = struct {
 	IssueIndexerName string
 	StartupTimeout   time.Duration
 
+	IssueTitleBoost    float64
+	IssueContentBoost  float64
+	IssueCommentsBoost float64
+
+	DiscussionType string
+	DiscussionPath string
+
 	RepoIndexerEnabled bool
 	RepoType           string
 	RepoPath           string
@@ -39,6 +46,13 @@ var Indexer = struct {
 	IssueConnAuth:    "",
 	IssueIndexerName: "gitea_issues",
 
+	IssueTitleBoost:    3,
+	IssueContentBoost:  2,
+	IssueCommentsBoost: 1,
+
+	DiscussionType: "bleve",
+	DiscussionPath: "indexers/discussions.bleve",
+
 	RepoIndexerEnabled: false,
 	RepoType:           "bleve",
 	RepoPath:           "indexers/repos.bleve",
@@ -69,6 +83,15 @@ func loadIndexerFrom(rootCfg ConfigProvider) {
 	}
 
 	Indexer.IssueIndexerName = sec.Key("ISSUE_INDEXER_NAME").MustString(Indexer.IssueIndexerName)
+	Indexer.IssueTitleBoost = sec.Key("ISSUE_INDEXER_TITLE_BOOST").MustFloat64(Indexer.IssueTitleBoost)
+	Indexer.IssueContentBoost = sec.Key("ISSUE_INDEXER_CONTENT_BOOST").MustFloat64(Indexer.IssueContentBoost)
+	Indexer.IssueCommentsBoost = sec.Key("ISSUE_INDEXER_COMMENTS_BOOST").MustFloat64(Indexer.IssueCommentsBoost)
+
+	Indexer.DiscussionType = sec.Key("DISCUSSION_INDEXER_TYPE").MustString("bleve")
+	Indexer.DiscussionPath = filepath.ToSlash(sec.Key("DISCUSSION_INDEXER_PATH").MustString(filepath.ToSlash(filepath.Join(AppDataPath, "indexers/discussions.bleve"))))
+	if !filepath.IsAbs(Indexer.DiscussionPath) {
+		Indexer.DiscussionPath = filepath.ToSlash(filepath.Join(AppWorkPath, Indexer.DiscussionPath))
+	}
 
 	Indexer.RepoIndexerEnabled = sec.Key("REPO_INDEXER_ENABLED").MustBool(false)
 	Indexer.RepoType = sec.Key("REPO_INDEXER_TYPE").MustString("bleve")
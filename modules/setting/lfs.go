@@ -19,6 +19,7 @@ var LFS = struct {
 	HTTPAuthExpiry  time.Duration `ini:"LFS_HTTP_AUTH_EXPIRY"`
 	MaxFileSize     int64         `ini:"LFS_MAX_FILE_SIZE"`
 	LocksPagingNum  int           `ini:"LFS_LOCKS_PAGING_NUM"`
+	DirectUpload    bool          `ini:"-"`
 
 	Storage
 }{}
@@ -39,6 +40,7 @@ func loadLFSFrom(rootCfg ConfigProvider) {
 	lfsSec.Key("PATH").MustString(sec.Key("LFS_CONTENT_PATH").String())
 
 	LFS.Storage = getStorage(rootCfg, "lfs", storageType, lfsSec)
+	LFS.DirectUpload = lfsSec.Key("DIRECT_UPLOAD").MustBool(false)
 
 	// Rest of LFS service settings
 	if LFS.LocksPagingNum == 0 {
@@ -0,0 +1,41 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// LoginRisk settings
+var LoginRisk = struct {
+	// Enabled records the devices users sign in from
+	Enabled bool
+	// NotifyNewDevice emails users when they sign in from a device they didn't use before
+	NotifyNewDevice bool
+	// RequireTwoFactorForNewDevice asks users enrolled in two-factor authentication for their second factor
+	// when they sign in from a new device, even if their authentication source skips it
+	RequireTwoFactorForNewDevice bool `ini:"REQUIRE_2FA_FOR_NEW_DEVICE"`
+	// GeoIPURL is requested with "{ip}" replaced by the address of the user to locate it
+	GeoIPURL     string `ini:"GEOIP_URL"`
+	GeoIPTimeout time.Duration
+	// DeviceCookieDays is the lifetime of the cookie identifying a device
+	DeviceCookieDays int
+}{
+	Enabled:          false,
+	NotifyNewDevice:  true,
+	GeoIPTimeout:     5 * time.Second,
+	DeviceCookieDays: 365,
+}
+
+func loadLoginRiskFrom(rootCfg ConfigProvider) {
+	if err := rootCfg.Section("login_risk").MapTo(&LoginRisk); err != nil {
+		log.Fatal("Failed to map LoginRisk settings: %v", err)
+	}
+	if LoginRisk.DeviceCookieDays <= 0 {
+		log.Warn("LoginRisk.DEVICE_COOKIE_DAYS must be positive, set to 365")
+		LoginRisk.DeviceCookieDays = 365
+	}
+}
@@ -45,8 +45,27 @@ type Mailer struct {
 	SendmailArgs        []string      `ini:"-"`
 	SendmailTimeout     time.Duration `ini:"SENDMAIL_TIMEOUT"`
 	SendmailConvertCRLF bool          `ini:"SENDMAIL_CONVERT_CRLF"`
+
+	// API based senders: sendgrid, mailgun and ses
+	APIKey    string `ini:"API_KEY"`
+	APISecret string `ini:"API_SECRET"`
+	APIURL    string `ini:"API_URL"`
+	APIDomain string `ini:"API_DOMAIN"`
+	APIRegion string `ini:"API_REGION"`
+	// WebhookToken authenticates the delivery events posted by the provider
+	WebhookToken string `ini:"WEBHOOK_TOKEN"`
+
+	// Providers are the additional providers of the [mailer.provider.NAME] sections by name
+	Providers map[string]*Mailer `ini:"-"`
+	// Failover are the names of the providers tried in order when sending fails
+	Failover []string `ini:"-"`
+	// Routing maps recipient domains to the name of the provider sending to them
+	Routing map[string]string `ini:"-"`
 }
 
+// DefaultMailProvider is the name of the provider configured in the [mailer] section
+const DefaultMailProvider = "default"
+
 // MailService the global mailer
 var MailService *Mailer
 
@@ -136,50 +155,105 @@ func loadMailerFrom(rootCfg ConfigProvider) {
 
 	// Set default values & validate
 	sec.Key("NAME").MustString(AppName)
-	sec.Key("PROTOCOL").In("", []string{"smtp", "smtps", "smtp+starttls", "smtp+unix", "sendmail", "dummy"})
+	sec.Key("FROM").MustString(sec.Key("USER").String())
+
+	// Now map the values on to the MailService
+	MailService = loadMailProviderFrom(sec)
+
+	MailService.Providers = map[string]*Mailer{}
+	MailService.Failover = sec.Key("FAILOVER").Strings(",")
+	MailService.Routing = map[string]string{}
+	for _, key := range rootCfg.Section("mailer.routing").Keys() {
+		MailService.Routing[strings.ToLower(key.Name())] = key.String()
+	}
+	names := append([]string{}, MailService.Failover...)
+	for _, name := range MailService.Routing {
+		names = append(names, name)
+	}
+	for _, name := range names {
+		if _, ok := MailService.Providers[name]; ok || name == DefaultMailProvider {
+			continue
+		}
+		providerSec, err := rootCfg.GetSection("mailer.provider." + name)
+		if err != nil {
+			log.Fatal("mailer provider %q is used but there is no [mailer.provider.%s] section", name, name)
+		}
+		MailService.Providers[name] = loadMailProviderFrom(providerSec)
+	}
+
+	if MailService.From != "" {
+		parsed, err := mail.ParseAddress(MailService.From)
+		if err != nil {
+			log.Fatal("Invalid mailer.FROM (%s): %v", MailService.From, err)
+		}
+		MailService.FromName = parsed.Name
+		MailService.FromEmail = parsed.Address
+	} else {
+		log.Error("no mailer.FROM provided, email system may not work.")
+	}
+
+	switch MailService.EnvelopeFrom {
+	case "":
+		MailService.OverrideEnvelopeFrom = false
+	case "<>":
+		MailService.EnvelopeFrom = ""
+		MailService.OverrideEnvelopeFrom = true
+	default:
+		parsed, err := mail.ParseAddress(MailService.EnvelopeFrom)
+		if err != nil {
+			log.Fatal("Invalid mailer.ENVELOPE_FROM (%s): %v", MailService.EnvelopeFrom, err)
+		}
+		MailService.OverrideEnvelopeFrom = true
+		MailService.EnvelopeFrom = parsed.Address
+	}
+
+	log.Info("Mail Service Enabled")
+}
+
+// loadMailProviderFrom maps the delivery settings of a [mailer] or [mailer.provider.NAME] section
+func loadMailProviderFrom(sec ConfigSection) *Mailer {
+	sec.Key("PROTOCOL").In("", []string{"smtp", "smtps", "smtp+starttls", "smtp+unix", "sendmail", "dummy", "sendgrid", "mailgun", "ses"})
 	sec.Key("ENABLE_HELO").MustBool(true)
 	sec.Key("FORCE_TRUST_SERVER_CERT").MustBool(false)
 	sec.Key("USE_CLIENT_CERT").MustBool(false)
 	sec.Key("SENDMAIL_PATH").MustString("sendmail")
 	sec.Key("SENDMAIL_TIMEOUT").MustDuration(5 * time.Minute)
 	sec.Key("SENDMAIL_CONVERT_CRLF").MustBool(true)
-	sec.Key("FROM").MustString(sec.Key("USER").String())
 
-	// Now map the values on to the MailService
-	MailService = &Mailer{}
-	if err := sec.MapTo(MailService); err != nil {
-		log.Fatal("Unable to map [mailer] section on to MailService. Error: %v", err)
+	m := &Mailer{}
+	if err := sec.MapTo(m); err != nil {
+		log.Fatal("Unable to map [%s] section. Error: %v", sec.Name(), err)
 	}
 
 	// Infer SMTPPort if not set
-	if MailService.SMTPPort == "" {
-		switch MailService.Protocol {
+	if m.SMTPPort == "" {
+		switch m.Protocol {
 		case "smtp":
-			MailService.SMTPPort = "25"
+			m.SMTPPort = "25"
 		case "smtps":
-			MailService.SMTPPort = "465"
+			m.SMTPPort = "465"
 		case "smtp+starttls":
-			MailService.SMTPPort = "587"
+			m.SMTPPort = "587"
 		}
 	}
 
 	// Infer Protocol
-	if MailService.Protocol == "" {
-		if strings.ContainsAny(MailService.SMTPAddr, "/\\") {
-			MailService.Protocol = "smtp+unix"
+	if m.Protocol == "" {
+		if strings.ContainsAny(m.SMTPAddr, "/\\") {
+			m.Protocol = "smtp+unix"
 		} else {
-			switch MailService.SMTPPort {
+			switch m.SMTPPort {
 			case "25":
-				MailService.Protocol = "smtp"
+				m.Protocol = "smtp"
 			case "465":
-				MailService.Protocol = "smtps"
+				m.Protocol = "smtps"
 			case "587":
-				MailService.Protocol = "smtp+starttls"
+				m.Protocol = "smtp+starttls"
 			default:
-				log.Error("unable to infer unspecified mailer.PROTOCOL from mailer.SMTP_PORT = %q, assume using smtps", MailService.SMTPPort)
-				MailService.Protocol = "smtps"
-				if MailService.SMTPPort == "" {
-					MailService.SMTPPort = "465"
+				log.Error("unable to infer unspecified %s.PROTOCOL from %s.SMTP_PORT = %q, assume using smtps", sec.Name(), sec.Name(), m.SMTPPort)
+				m.Protocol = "smtps"
+				if m.SMTPPort == "" {
+					m.SMTPPort = "465"
 				}
 			}
 		}
@@ -188,16 +262,16 @@ func loadMailerFrom(rootCfg ConfigProvider) {
 	// we want to warn if users use SMTP on a non-local IP;
 	// we might as well take the opportunity to check that it has an IP at all
 	// This check is not needed for sendmail
-	switch MailService.Protocol {
+	switch m.Protocol {
 	case "sendmail":
 		var err error
-		MailService.SendmailArgs, err = shellquote.Split(sec.Key("SENDMAIL_ARGS").String())
+		m.SendmailArgs, err = shellquote.Split(sec.Key("SENDMAIL_ARGS").String())
 		if err != nil {
 			log.Error("Failed to parse Sendmail args: '%s' with error %v", sec.Key("SENDMAIL_ARGS").String(), err)
 		}
 	case "smtp", "smtps", "smtp+starttls", "smtp+unix":
-		ips := tryResolveAddr(MailService.SMTPAddr)
-		if MailService.Protocol == "smtp" {
+		ips := tryResolveAddr(m.SMTPAddr)
+		if m.Protocol == "smtp" {
 			for _, ip := range ips {
 				if !ip.IP.IsLoopback() {
 					log.Warn("connecting over insecure SMTP protocol to non-local address is not recommended")
@@ -205,36 +279,13 @@ func loadMailerFrom(rootCfg ConfigProvider) {
 				}
 			}
 		}
-	case "dummy": // just mention and do nothing
-	}
-
-	if MailService.From != "" {
-		parsed, err := mail.ParseAddress(MailService.From)
-		if err != nil {
-			log.Fatal("Invalid mailer.FROM (%s): %v", MailService.From, err)
-		}
-		MailService.FromName = parsed.Name
-		MailService.FromEmail = parsed.Address
-	} else {
-		log.Error("no mailer.FROM provided, email system may not work.")
-	}
-
-	switch MailService.EnvelopeFrom {
-	case "":
-		MailService.OverrideEnvelopeFrom = false
-	case "<>":
-		MailService.EnvelopeFrom = ""
-		MailService.OverrideEnvelopeFrom = true
-	default:
-		parsed, err := mail.ParseAddress(MailService.EnvelopeFrom)
-		if err != nil {
-			log.Fatal("Invalid mailer.ENVELOPE_FROM (%s): %v", MailService.EnvelopeFrom, err)
+	case "sendgrid", "mailgun", "ses":
+		if m.APIKey == "" {
+			log.Error("no %s.API_KEY provided, sending with %s will fail", sec.Name(), m.Protocol)
 		}
-		MailService.OverrideEnvelopeFrom = true
-		MailService.EnvelopeFrom = parsed.Address
+	case "dummy": // just mention and do nothing
 	}
-
-	log.Info("Mail Service Enabled")
+	return m
 }
 
 func loadRegisterMailFrom(rootCfg ConfigProvider) {
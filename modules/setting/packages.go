@@ -8,6 +8,8 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 
@@ -44,6 +46,13 @@ var (
 		LimitSizeRubyGems    int64
 		LimitSizeSwift       int64
 		LimitSizeVagrant     int64
+
+		// DeletionRetention is how long deleted package versions can be restored before they are purged, 0 deletes them immediately
+		DeletionRetention time.Duration
+
+		GoChecksumDatabase  string   `ini:"-"`
+		GoPrivate           []string `ini:"-"`
+		NpmAdvisoryDatabase string   `ini:"-"`
 	}{
 		Enabled:              true,
 		LimitTotalOwnerCount: -1,
@@ -91,6 +100,10 @@ func loadPackagesFrom(rootCfg ConfigProvider) {
 	Packages.LimitSizeRubyGems = mustBytes(sec, "LIMIT_SIZE_RUBYGEMS")
 	Packages.LimitSizeSwift = mustBytes(sec, "LIMIT_SIZE_SWIFT")
 	Packages.LimitSizeVagrant = mustBytes(sec, "LIMIT_SIZE_VAGRANT")
+
+	Packages.GoChecksumDatabase = strings.TrimSuffix(sec.Key("GO_CHECKSUM_DATABASE").MustString("https://sum.golang.org"), "/")
+	Packages.GoPrivate = sec.Key("GO_PRIVATE").Strings(",")
+	Packages.NpmAdvisoryDatabase = strings.TrimSuffix(sec.Key("NPM_ADVISORY_DATABASE").MustString("https://api.osv.dev"), "/")
 }
 
 func mustBytes(section ConfigSection, key string) int64 {
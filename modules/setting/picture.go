@@ -5,6 +5,13 @@ package setting
 
 // Avatar settings
 
+// Policies for uploaded animated avatars
+const (
+	AvatarAnimatedAllow  = "allow"
+	AvatarAnimatedStatic = "static"
+	AvatarAnimatedReject = "reject"
+)
+
 var (
 	Avatar = struct {
 		Storage
@@ -14,12 +21,18 @@ var (
 		MaxFileSize        int64
 		MaxOriginSize      int64
 		RenderedSizeFactor int
+		StripMetadata      bool
+		AnimatedPolicy     string
+		NormalizeSize      bool
+		LibravatarServer   bool
 	}{
 		MaxWidth:           4096,
 		MaxHeight:          4096,
 		MaxFileSize:        1048576,
 		MaxOriginSize:      262144,
 		RenderedSizeFactor: 2,
+		StripMetadata:      true,
+		AnimatedPolicy:     AvatarAnimatedAllow,
 	}
 
 	GravatarSource        string
@@ -50,6 +63,10 @@ func loadPictureFrom(rootCfg ConfigProvider) {
 	Avatar.MaxFileSize = sec.Key("AVATAR_MAX_FILE_SIZE").MustInt64(1048576)
 	Avatar.MaxOriginSize = sec.Key("AVATAR_MAX_ORIGIN_SIZE").MustInt64(262144)
 	Avatar.RenderedSizeFactor = sec.Key("AVATAR_RENDERED_SIZE_FACTOR").MustInt(2)
+	Avatar.StripMetadata = sec.Key("AVATAR_STRIP_METADATA").MustBool(true)
+	Avatar.AnimatedPolicy = sec.Key("AVATAR_ANIMATED").In(AvatarAnimatedAllow, []string{AvatarAnimatedAllow, AvatarAnimatedStatic, AvatarAnimatedReject})
+	Avatar.NormalizeSize = sec.Key("AVATAR_NORMALIZE_SIZE").MustBool(false)
+	Avatar.LibravatarServer = sec.Key("ENABLE_LIBRAVATAR_SERVER").MustBool(false)
 
 	switch source := sec.Key("GRAVATAR_SOURCE").MustString("gravatar"); source {
 	case "duoshuo":
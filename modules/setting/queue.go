@@ -16,7 +16,7 @@ type QueueSettings struct {
 
 	Type    string
 	Datadir string
-	ConnStr string // for leveldb or redis
+	ConnStr string // for leveldb, redis, postgres or nats
 	Length  int    // max queue length before blocking
 
 	QueueName, SetName string // the name suffix for storage (db key, redis key), "set" is for unique queue
@@ -26,7 +26,7 @@ type QueueSettings struct {
 }
 
 var queueSettingsDefault = QueueSettings{
-	Type:    "level",         // dummy, channel, level, redis
+	Type:    "level",         // dummy, channel, level, redis, postgres, nats
 	Datadir: "queues/common", // relative to AppDataPath
 	Length:  100,             // queue length before a channel queue will block
 
@@ -8,6 +8,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 )
@@ -51,10 +52,15 @@ var (
 		AllowDeleteOfUnadoptedRepositories      bool
 		DisableDownloadSourceArchives           bool
 		AllowForkWithoutMaximumLimit            bool
+		DownloadLinkMaxExpiry                   time.Duration
+		// DeletionRetention is how long deleted repositories can be restored before they are purged, 0 deletes them immediately
+		DeletionRetention time.Duration
 
 		// Repository editor settings
 		Editor struct {
 			LineWrapExtensions []string
+			// SessionLifetime is how long an editing session is kept after its last change
+			SessionLifetime time.Duration
 		} `ini:"-"`
 
 		// Repository upload settings
@@ -71,6 +77,13 @@ var (
 			LocalCopyPath string
 		} `ini:"-"`
 
+		// Push limits settings
+		PushLimits struct {
+			MaxPushSize       int64
+			MaxNewRefs        int
+			MaxCommitsPerPush int
+		} `ini:"-"`
+
 		// Pull request settings
 		PullRequest struct {
 			WorkInProgressPrefixes                   []string
@@ -85,6 +98,8 @@ var (
 			PopulateSquashCommentWithCommitMessages  bool
 			AddCoCommitterTrailers                   bool
 			TestConflictingPatchesWithGitApply       bool
+			EnableBackport                           bool
+			BackportLabelPrefix                      string
 		} `ini:"repository.pull-request"`
 
 		// Issue Setting
@@ -97,6 +112,12 @@ var (
 			DefaultPagingNum int
 		} `ini:"repository.release"`
 
+		// Readme settings
+		Readme struct {
+			FileNames   []string
+			Directories []string
+		} `ini:"repository.readme"`
+
 		Signing struct {
 			SigningKey        string
 			SigningName       string
@@ -164,12 +185,15 @@ var (
 		DisableStars:                            false,
 		DefaultBranch:                           "main",
 		AllowForkWithoutMaximumLimit:            true,
+		DownloadLinkMaxExpiry:                   30 * 24 * time.Hour,
 
 		// Repository editor settings
 		Editor: struct {
 			LineWrapExtensions []string
+			SessionLifetime    time.Duration
 		}{
 			LineWrapExtensions: strings.Split(".txt,.md,.markdown,.mdown,.mkd,.livemd,", ","),
+			SessionLifetime:    24 * time.Hour,
 		},
 
 		// Repository upload settings
@@ -208,6 +232,8 @@ var (
 			PopulateSquashCommentWithCommitMessages  bool
 			AddCoCommitterTrailers                   bool
 			TestConflictingPatchesWithGitApply       bool
+			EnableBackport                           bool
+			BackportLabelPrefix                      string
 		}{
 			WorkInProgressPrefixes: []string{"WIP:", "[WIP]"},
 			// Same as GitHub. See
@@ -222,6 +248,8 @@ var (
 			DefaultMergeMessageOfficialApproversOnly: true,
 			PopulateSquashCommentWithCommitMessages:  false,
 			AddCoCommitterTrailers:                   true,
+			EnableBackport:                           true,
+			BackportLabelPrefix:                      "backport/",
 		},
 
 		// Issue settings
@@ -239,6 +267,15 @@ var (
 			DefaultPagingNum: 10,
 		},
 
+		// Readme settings
+		Readme: struct {
+			FileNames   []string
+			Directories []string
+		}{
+			FileNames:   []string{"README"},
+			Directories: []string{"docs", ".gitea", ".github"},
+		},
+
 		// Signing settings
 		Signing: struct {
 			SigningKey        string
@@ -268,6 +305,12 @@ var (
 	}{}
 )
 
+// PushLimitsEnabled returns whether any of the push limits is set
+func PushLimitsEnabled() bool {
+	limits := Repository.PushLimits
+	return limits.MaxPushSize > 0 || limits.MaxNewRefs > 0 || limits.MaxCommitsPerPush > 0
+}
+
 func loadRepositoryFrom(rootCfg ConfigProvider) {
 	var err error
 	// Determine and create root git repository path.
@@ -306,6 +349,11 @@ func loadRepositoryFrom(rootCfg ConfigProvider) {
 		log.Fatal("Failed to map Repository.PullRequest settings: %v", err)
 	}
 
+	sec = rootCfg.Section("repository.push-limits")
+	Repository.PushLimits.MaxPushSize = mustBytes(sec, "MAX_PUSH_SIZE")
+	Repository.PushLimits.MaxNewRefs = sec.Key("MAX_NEW_REFS").MustInt(0)
+	Repository.PushLimits.MaxCommitsPerPush = sec.Key("MAX_COMMITS_PER_PUSH").MustInt(0)
+
 	if !rootCfg.Section("packages").Key("ENABLED").MustBool(Packages.Enabled) {
 		Repository.DisabledRepoUnits = append(Repository.DisabledRepoUnits, "repo.packages")
 	}
@@ -7,6 +7,7 @@ import (
 	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/modules/auth/password/hash"
 	"code.gitea.io/gitea/modules/generate"
@@ -35,6 +36,10 @@ var (
 	PasswordHashAlgo                   string
 	PasswordCheckPwn                   bool
 	SuccessfulTokensCacheSize          int
+	AccessTokenMaxLifetime             time.Duration
+	AccessTokenMaxInactivity           time.Duration
+	DetectLeakedAccessTokens           bool
+	RevokeLeakedAccessTokens           bool
 	CSRFCookieName                     = "_csrf"
 	CSRFCookieHTTPOnly                 = true
 )
@@ -135,6 +140,10 @@ func loadSecurityFrom(rootCfg ConfigProvider) {
 	CSRFCookieHTTPOnly = sec.Key("CSRF_COOKIE_HTTP_ONLY").MustBool(true)
 	PasswordCheckPwn = sec.Key("PASSWORD_CHECK_PWN").MustBool(false)
 	SuccessfulTokensCacheSize = sec.Key("SUCCESSFUL_TOKENS_CACHE_SIZE").MustInt(20)
+	AccessTokenMaxLifetime = sec.Key("ACCESS_TOKEN_MAX_LIFETIME").MustDuration(0)
+	AccessTokenMaxInactivity = sec.Key("ACCESS_TOKEN_MAX_INACTIVITY").MustDuration(0)
+	DetectLeakedAccessTokens = sec.Key("DETECT_LEAKED_ACCESS_TOKENS").MustBool(true)
+	RevokeLeakedAccessTokens = sec.Key("REVOKE_LEAKED_ACCESS_TOKENS").MustBool(true)
 
 	InternalToken = loadSecret(sec, "INTERNAL_TOKEN_URI", "INTERNAL_TOKEN")
 	if InstallLock && InternalToken == "" {
@@ -234,6 +234,8 @@ func loadCommonSettingsFrom(cfg ConfigProvider) {
 	loadRepositoryFrom(cfg)
 	loadPictureFrom(cfg)
 	loadPackagesFrom(cfg)
+	loadStorageRegionsFrom(cfg)
+	loadStorageEncryptionFrom(cfg)
 	loadActionsFrom(cfg)
 	loadUIFrom(cfg)
 	loadAdminFrom(cfg)
@@ -242,7 +244,11 @@ func loadCommonSettingsFrom(cfg ConfigProvider) {
 	loadCamoFrom(cfg)
 	loadI18nFrom(cfg)
 	loadGitFrom(cfg)
+	loadGitHTTPFrom(cfg)
 	loadMirrorFrom(cfg)
+	loadSpamFrom(cfg)
+	loadLoginRiskFrom(cfg)
+	loadFederatedSearchFrom(cfg)
 	loadMarkupFrom(cfg)
 	loadOtherFrom(cfg)
 }
@@ -0,0 +1,49 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"time"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Spam settings
+var Spam = struct {
+	Enabled                 bool
+	CheckRegistrations      bool
+	CheckFirstContributions bool
+	// Threshold is the total score from which content is held for review
+	Threshold         float64
+	HoneypotScore     float64
+	MaxLinks          int
+	MaxLinkDensity    float64
+	LinkScore         float64
+	ClassifierURL     string `ini:"CLASSIFIER_URL"`
+	ClassifierToken   string
+	ClassifierTimeout time.Duration
+	// ClassifierWeight multiplies the score between 0 and 1 returned by the classifier
+	ClassifierWeight float64
+}{
+	Enabled:                 false,
+	CheckRegistrations:      true,
+	CheckFirstContributions: true,
+	Threshold:               1,
+	HoneypotScore:           1,
+	MaxLinks:                5,
+	MaxLinkDensity:          0.2,
+	LinkScore:               0.5,
+	ClassifierTimeout:       5 * time.Second,
+	ClassifierWeight:        1,
+}
+
+func loadSpamFrom(rootCfg ConfigProvider) {
+	if err := rootCfg.Section("spam").MapTo(&Spam); err != nil {
+		log.Fatal("Failed to map Spam settings: %v", err)
+	}
+	if Spam.Threshold <= 0 {
+		log.Warn("Spam.THRESHOLD must be positive, set to 1")
+		Spam.Threshold = 1
+	}
+}
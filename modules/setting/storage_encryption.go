@@ -0,0 +1,123 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// StorageEncryption represents the configuration of the encryption at rest of stored objects
+var StorageEncryption = struct {
+	Enabled     bool
+	Storages    container.Set[string]
+	KeyProvider string
+
+	// local key provider
+	MasterKeys  map[string][]byte
+	ActiveKeyID string
+
+	// vault key provider
+	VaultAddress   string
+	VaultToken     string
+	VaultMountPath string
+	VaultKeyName   string
+}{
+	KeyProvider: "local",
+}
+
+// encryptableStorages are the storages whose objects can be encrypted
+var encryptableStorages = []string{"attachments", "lfs", "packages"}
+
+// IsStorageEncrypted returns if the objects of a storage are encrypted
+func IsStorageEncrypted(name string) bool {
+	return StorageEncryption.Enabled && StorageEncryption.Storages.Contains(name)
+}
+
+func loadStorageEncryptionFrom(rootCfg ConfigProvider) {
+	sec := rootCfg.Section("storage_encryption")
+	StorageEncryption.Enabled = sec.Key("ENABLED").MustBool(false)
+	if !StorageEncryption.Enabled {
+		return
+	}
+
+	StorageEncryption.Storages = make(container.Set[string])
+	for _, name := range sec.Key("STORAGES").Strings(",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !container.SetOf(encryptableStorages...).Contains(name) {
+			log.Fatal("Unsupported storage %q in [storage_encryption].STORAGES, allowed are: %s", name, strings.Join(encryptableStorages, ", "))
+		}
+		StorageEncryption.Storages.Add(name)
+	}
+	if len(StorageEncryption.Storages) == 0 {
+		StorageEncryption.Storages.AddMultiple(encryptableStorages...)
+	}
+
+	StorageEncryption.KeyProvider = sec.Key("KEY_PROVIDER").In("local", []string{"local", "vault"})
+	switch StorageEncryption.KeyProvider {
+	case "local":
+		StorageEncryption.MasterKeys = parseStorageEncryptionKeys(loadSecret(sec, "MASTER_KEYS_URI", "MASTER_KEYS"))
+		StorageEncryption.ActiveKeyID = sec.Key("ACTIVE_KEY_ID").String()
+		if StorageEncryption.ActiveKeyID == "" && len(StorageEncryption.MasterKeys) == 1 {
+			for id := range StorageEncryption.MasterKeys {
+				StorageEncryption.ActiveKeyID = id
+			}
+		}
+		if _, ok := StorageEncryption.MasterKeys[StorageEncryption.ActiveKeyID]; !ok {
+			log.Fatal("[storage_encryption].ACTIVE_KEY_ID %q must be one of the MASTER_KEYS", StorageEncryption.ActiveKeyID)
+		}
+	case "vault":
+		StorageEncryption.VaultAddress = strings.TrimSuffix(sec.Key("VAULT_ADDRESS").MustString("http://127.0.0.1:8200"), "/")
+		StorageEncryption.VaultToken = loadSecret(sec, "VAULT_TOKEN_URI", "VAULT_TOKEN")
+		StorageEncryption.VaultMountPath = strings.Trim(sec.Key("VAULT_MOUNT_PATH").MustString("transit"), "/")
+		StorageEncryption.VaultKeyName = sec.Key("VAULT_KEY_NAME").MustString("gitea")
+		if StorageEncryption.VaultToken == "" {
+			log.Fatal("[storage_encryption].VAULT_TOKEN or VAULT_TOKEN_URI must be set when KEY_PROVIDER is vault")
+		}
+	}
+
+	// the storages only contain the encrypted objects, so they can't serve them directly
+	disableServeDirect := func(name string, s *Storage) {
+		if s.ServeDirect && StorageEncryption.Storages.Contains(name) {
+			log.Warn("SERVE_DIRECT of the %s storage is ignored because its objects are encrypted", name)
+			s.ServeDirect = false
+		}
+	}
+	disableServeDirect("attachments", &Attachment.Storage)
+	disableServeDirect("lfs", &LFS.Storage)
+	disableServeDirect("packages", &Packages.Storage)
+	for _, region := range StorageRegions {
+		disableServeDirect("attachments", &region.Attachments)
+		disableServeDirect("lfs", &region.LFS)
+		disableServeDirect("packages", &region.Packages)
+	}
+}
+
+// parseStorageEncryptionKeys parses a comma or newline separated list of "id:base64-key" master keys
+func parseStorageEncryptionKeys(s string) map[string][]byte {
+	keys := make(map[string][]byte)
+	for _, entry := range strings.FieldsFunc(s, func(r rune) bool { return r == ',' || r == '\n' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(entry, ":")
+		id = strings.TrimSpace(id)
+		if !ok || id == "" {
+			log.Fatal("Invalid [storage_encryption] master key, the format is id:base64-key")
+		}
+		key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != 32 {
+			log.Fatal("Invalid [storage_encryption] master key %q, it must be 32 base64 encoded bytes", id)
+		}
+		keys[id] = key
+	}
+	if len(keys) == 0 {
+		log.Fatal("[storage_encryption].MASTER_KEYS or MASTER_KEYS_URI must be set when KEY_PROVIDER is local")
+	}
+	return keys
+}
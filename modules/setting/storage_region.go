@@ -0,0 +1,119 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// StorageRegion is a data residency location which organizations can be pinned to.
+// The git repositories, LFS objects, attachments and packages of pinned organizations
+// are only stored in the storages of their region.
+type StorageRegion struct {
+	Name         string
+	RepoRootPath string
+	LFS          Storage
+	Attachments  Storage
+	Packages     Storage
+}
+
+// StorageRegions are the configured storage regions by name
+var StorageRegions = map[string]*StorageRegion{}
+
+// StorageRegionNames returns the names of the configured storage regions, sorted
+func StorageRegionNames() []string {
+	names := make([]string, 0, len(StorageRegions))
+	for name := range StorageRegions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// GetRepoRootPathOfRegion returns the repository root path of a storage region,
+// the default repository root path is returned for the empty region
+func GetRepoRootPathOfRegion(region string) string {
+	if r, ok := StorageRegions[region]; ok {
+		return r.RepoRootPath
+	}
+	return RepoRootPath
+}
+
+func loadStorageRegionsFrom(rootCfg ConfigProvider) {
+	StorageRegions = map[string]*StorageRegion{}
+
+	for _, sec := range rootCfg.Section("storage_region").ChildSections() {
+		name := strings.TrimPrefix(sec.Name(), "storage_region.")
+		// the storages of a region are configured in generated sub sections
+		if name == "" || strings.Contains(name, ".") {
+			continue
+		}
+
+		regionPath := sec.Key("PATH").MustString(filepath.Join(AppDataPath, "storage_regions", name))
+		if !filepath.IsAbs(regionPath) {
+			regionPath = filepath.Join(AppWorkPath, regionPath)
+		}
+
+		region := &StorageRegion{Name: name}
+		region.RepoRootPath = sec.Key("REPO_ROOT_PATH").MustString(filepath.Join(regionPath, "gitea-repositories"))
+		forcePathSeparator(region.RepoRootPath)
+		if !filepath.IsAbs(region.RepoRootPath) {
+			region.RepoRootPath = filepath.Join(AppWorkPath, region.RepoRootPath)
+		} else {
+			region.RepoRootPath = filepath.Clean(region.RepoRootPath)
+		}
+		if region.RepoRootPath == RepoRootPath {
+			log.Fatal("The repository root path of storage region %q must differ from [repository].ROOT", name)
+		}
+
+		region.LFS = getStorageRegionStorage(rootCfg, sec, name, regionPath, "lfs")
+		region.Attachments = getStorageRegionStorage(rootCfg, sec, name, regionPath, "attachments")
+		region.Packages = getStorageRegionStorage(rootCfg, sec, name, regionPath, "packages")
+
+		StorageRegions[name] = region
+	}
+}
+
+// getStorageRegionStorage returns the storage of a storage region for a kind of data.
+// Unlike the default storages, settings of the [storage.lfs] like sections are not inherited,
+// so a region never shares a bucket or path with the default storages by accident.
+func getStorageRegionStorage(rootCfg ConfigProvider, regionSec ConfigSection, regionName, regionPath, name string) Storage {
+	targetSec, _ := rootCfg.NewSection(regionSec.Name() + "." + name)
+
+	var storage Storage
+	storage.Section = targetSec
+	storage.Type = regionSec.Key("STORAGE_TYPE").String()
+
+	overrides := make([]ConfigSection, 0, 3)
+	overrides = append(overrides, regionSec)
+	if typeSec, err := rootCfg.GetSection("storage." + storage.Type); err == nil {
+		overrides = append(overrides, typeSec)
+		if nextType := typeSec.Key("STORAGE_TYPE").String(); len(nextType) > 0 {
+			storage.Type = nextType
+		}
+	}
+	overrides = append(overrides, rootCfg.Section("storage"))
+
+	for _, override := range overrides {
+		for _, key := range override.Keys() {
+			if key.Name() == "PATH" || key.Name() == "REPO_ROOT_PATH" || key.Name() == "STORAGE_TYPE" || key.Name() == "MINIO_BASE_PATH" {
+				continue
+			}
+			if !targetSec.HasKey(key.Name()) {
+				_, _ = targetSec.NewKey(key.Name(), key.Value())
+			}
+		}
+	}
+	storage.ServeDirect = targetSec.Key("SERVE_DIRECT").MustBool(false)
+
+	storage.Path = filepath.Join(regionPath, name)
+	targetSec.Key("PATH").SetValue(storage.Path)
+	targetSec.Key("MINIO_BASE_PATH").MustString("storage_regions/" + regionName + "/" + name + "/")
+
+	return storage
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_loadStorageRegionsFrom(t *testing.T) {
+	iniStr := `
+[storage]
+MINIO_ENDPOINT = default:9000
+
+[lfs]
+MINIO_BUCKET = gitea-lfs
+
+[storage_region.eu]
+PATH = /data/eu
+
+[storage_region.us]
+STORAGE_TYPE = my_minio
+MINIO_BUCKET = gitea-us
+
+[storage.my_minio]
+STORAGE_TYPE = minio
+MINIO_ENDPOINT = us:9000
+`
+	cfg, err := NewConfigProviderFromData(iniStr)
+	assert.NoError(t, err)
+	loadStorageRegionsFrom(cfg)
+	defer func() {
+		StorageRegions = map[string]*StorageRegion{}
+	}()
+
+	assert.EqualValues(t, []string{"eu", "us"}, StorageRegionNames())
+
+	eu := StorageRegions["eu"]
+	assert.EqualValues(t, filepath.Join("/data/eu", "gitea-repositories"), eu.RepoRootPath)
+	assert.EqualValues(t, filepath.Join("/data/eu", "lfs"), eu.LFS.Path)
+	assert.EqualValues(t, filepath.Join("/data/eu", "packages"), eu.Packages.Path)
+	assert.EqualValues(t, "default:9000", eu.LFS.Section.Key("MINIO_ENDPOINT").String())
+	// the settings of [lfs] are not inherited
+	assert.EqualValues(t, "", eu.LFS.Section.Key("MINIO_BUCKET").String())
+
+	us := StorageRegions["us"]
+	assert.EqualValues(t, "minio", us.Attachments.Type)
+	assert.EqualValues(t, "us:9000", us.Attachments.Section.Key("MINIO_ENDPOINT").String())
+	assert.EqualValues(t, "gitea-us", us.Attachments.Section.Key("MINIO_BUCKET").String())
+	assert.EqualValues(t, "storage_regions/us/attachments/", us.Attachments.Section.Key("MINIO_BASE_PATH").String())
+
+	assert.EqualValues(t, eu.RepoRootPath, GetRepoRootPathOfRegion("eu"))
+	assert.EqualValues(t, RepoRootPath, GetRepoRootPathOfRegion(""))
+}
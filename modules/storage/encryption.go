@@ -0,0 +1,520 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+
+	"code.gitea.io/gitea/modules/log"
+)
+
+// Encrypted objects use envelope encryption: every object is encrypted with its own random data key,
+// which is stored in the header of the object wrapped with a master key of the KeyProvider.
+// The content is split into chunks which are sealed with AES-GCM separately, so objects can be
+// read from any offset. The nonce of a chunk contains its index and whether it is the last one,
+// which prevents reordering and truncating the chunks.
+//
+// Header: magic | uint16 key id length | key id | uint16 wrapped key length | wrapped key | nonce prefix
+const (
+	encryptionMagic           = "GTEAENC1"
+	encryptionChunkSize       = 64 * 1024
+	encryptionTagSize         = 16
+	encryptionNoncePrefixSize = 7
+	encryptionDataKeySize     = 32
+)
+
+var errNotEncrypted = errors.New("object is not encrypted")
+
+type encryptionHeader struct {
+	keyID       string
+	wrappedKey  []byte
+	noncePrefix []byte
+}
+
+func (h *encryptionHeader) marshal() []byte {
+	buf := make([]byte, 0, len(encryptionMagic)+4+len(h.keyID)+len(h.wrappedKey)+encryptionNoncePrefixSize)
+	buf = append(buf, encryptionMagic...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(h.keyID)))
+	buf = append(buf, h.keyID...)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(len(h.wrappedKey)))
+	buf = append(buf, h.wrappedKey...)
+	return append(buf, h.noncePrefix...)
+}
+
+// readEncryptionHeader reads the header of an object, errNotEncrypted is returned for
+// objects which were stored before the encryption was enabled
+func readEncryptionHeader(r io.Reader) (*encryptionHeader, int64, error) {
+	magic := make([]byte, len(encryptionMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return nil, 0, errNotEncrypted
+		}
+		return nil, 0, err
+	}
+	if string(magic) != encryptionMagic {
+		return nil, 0, errNotEncrypted
+	}
+
+	readField := func() ([]byte, error) {
+		var size uint16
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return nil, err
+		}
+		field := make([]byte, size)
+		_, err := io.ReadFull(r, field)
+		return field, err
+	}
+	keyID, err := readField()
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid encryption header: %w", err)
+	}
+	wrappedKey, err := readField()
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid encryption header: %w", err)
+	}
+	noncePrefix := make([]byte, encryptionNoncePrefixSize)
+	if _, err := io.ReadFull(r, noncePrefix); err != nil {
+		return nil, 0, fmt.Errorf("invalid encryption header: %w", err)
+	}
+
+	h := &encryptionHeader{keyID: string(keyID), wrappedKey: wrappedKey, noncePrefix: noncePrefix}
+	return h, int64(len(encryptionMagic) + 4 + len(keyID) + len(wrappedKey) + encryptionNoncePrefixSize), nil
+}
+
+func chunkNonce(prefix []byte, index int64, last bool) []byte {
+	nonce := make([]byte, 0, encryptionNoncePrefixSize+5)
+	nonce = append(nonce, prefix...)
+	nonce = binary.BigEndian.AppendUint32(nonce, uint32(index))
+	if last {
+		return append(nonce, 1)
+	}
+	return append(nonce, 0)
+}
+
+func newDataKeyAEAD(dataKey []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// encryptedContentSize returns the size of the encrypted chunks of content with the given size
+func encryptedContentSize(size int64) int64 {
+	chunks := (size + encryptionChunkSize - 1) / encryptionChunkSize
+	if chunks == 0 {
+		chunks = 1
+	}
+	return size + chunks*encryptionTagSize
+}
+
+// decryptedContentSize returns the size of the content and the number of encrypted chunks with the given size
+func decryptedContentSize(encryptedSize int64) (int64, int64, error) {
+	chunks := (encryptedSize + encryptionChunkSize + encryptionTagSize - 1) / (encryptionChunkSize + encryptionTagSize)
+	if chunks == 0 || encryptedSize-chunks*encryptionTagSize < 0 {
+		return 0, 0, errors.New("encrypted object is truncated")
+	}
+	return encryptedSize - chunks*encryptionTagSize, chunks, nil
+}
+
+// readChunk reads up to a chunk from r, a short chunk is only returned at the end of r
+func readChunk(r io.Reader, buf []byte) ([]byte, error) {
+	n, err := io.ReadFull(r, buf)
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		err = nil
+	}
+	return buf[:n], err
+}
+
+// encryptContent writes the encrypted chunks of the content of r to w and returns the size of the content
+func encryptContent(w io.Writer, r io.Reader, aead cipher.AEAD, noncePrefix []byte) (int64, error) {
+	cur, err := readChunk(r, make([]byte, encryptionChunkSize))
+	if err != nil {
+		return 0, err
+	}
+	next := make([]byte, encryptionChunkSize)
+	sealed := make([]byte, 0, encryptionChunkSize+encryptionTagSize)
+
+	var written int64
+	for index := int64(0); ; index++ {
+		if index > 0xFFFFFFFF {
+			return written, errors.New("object is too large to be encrypted")
+		}
+
+		var following []byte
+		last := len(cur) < encryptionChunkSize
+		if !last {
+			if following, err = readChunk(r, next); err != nil {
+				return written, err
+			}
+			last = len(following) == 0
+		}
+
+		sealed = aead.Seal(sealed[:0], chunkNonce(noncePrefix, index, last), cur, nil)
+		if _, err := w.Write(sealed); err != nil {
+			return written, err
+		}
+		written += int64(len(cur))
+		if last {
+			return written, nil
+		}
+		next = cur[:cap(cur)]
+		cur = following
+	}
+}
+
+// encryptedStorage encrypts the objects written to a storage and decrypts them transparently on read
+type encryptedStorage struct {
+	storage ObjectStorage
+	keys    KeyProvider
+}
+
+var _ ObjectStorage = &encryptedStorage{}
+
+// NewEncryptedStorage returns a storage which encrypts the objects stored in the given storage
+func NewEncryptedStorage(s ObjectStorage, keys KeyProvider) ObjectStorage {
+	return &encryptedStorage{storage: s, keys: keys}
+}
+
+func (s *encryptedStorage) newHeader() (*encryptionHeader, []byte, error) {
+	dataKey := make([]byte, encryptionDataKeySize)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return nil, nil, err
+	}
+	keyID, wrappedKey, err := s.keys.WrapKey(dataKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("unable to wrap the data key: %w", err)
+	}
+	if len(keyID) > 0xFFFF || len(wrappedKey) > 0xFFFF {
+		return nil, nil, errors.New("wrapped data key is too large")
+	}
+	noncePrefix := make([]byte, encryptionNoncePrefixSize)
+	if _, err := io.ReadFull(rand.Reader, noncePrefix); err != nil {
+		return nil, nil, err
+	}
+	return &encryptionHeader{keyID: keyID, wrappedKey: wrappedKey, noncePrefix: noncePrefix}, dataKey, nil
+}
+
+// Open opens an object, objects stored before the encryption was enabled are returned as they are
+func (s *encryptedStorage) Open(path string) (Object, error) {
+	obj, err := s.storage.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	decrypted, err := s.decryptObject(obj)
+	if err != nil {
+		_ = obj.Close()
+		return nil, fmt.Errorf("unable to decrypt %s: %w", path, err)
+	}
+	return decrypted, nil
+}
+
+func (s *encryptedStorage) decryptObject(obj Object) (Object, error) {
+	header, headerSize, err := readEncryptionHeader(obj)
+	if err == errNotEncrypted {
+		if _, err := obj.Seek(0, io.SeekStart); err != nil {
+			return nil, err
+		}
+		return obj, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	dataKey, err := s.keys.UnwrapKey(header.keyID, header.wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("unable to unwrap the data key: %w", err)
+	}
+	aead, err := newDataKeyAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	info, err := obj.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size, chunks, err := decryptedContentSize(info.Size() - headerSize)
+	if err != nil {
+		return nil, err
+	}
+	return &encryptedObject{
+		obj:         obj,
+		aead:        aead,
+		noncePrefix: header.noncePrefix,
+		headerSize:  headerSize,
+		objSize:     info.Size(),
+		objOffset:   headerSize,
+		size:        size,
+		chunks:      chunks,
+		chunkIndex:  -1,
+	}, nil
+}
+
+// Save encrypts and stores an object, the returned size is the size of the unencrypted content
+func (s *encryptedStorage) Save(path string, r io.Reader, size int64) (int64, error) {
+	header, dataKey, err := s.newHeader()
+	if err != nil {
+		return 0, err
+	}
+	aead, err := newDataKeyAEAD(dataKey)
+	if err != nil {
+		return 0, err
+	}
+
+	headerBytes := header.marshal()
+	encryptedSize := int64(-1)
+	if size >= 0 {
+		encryptedSize = int64(len(headerBytes)) + encryptedContentSize(size)
+	}
+
+	type result struct {
+		n   int64
+		err error
+	}
+	done := make(chan result, 1)
+	pr, pw := io.Pipe()
+	go func() {
+		var n int64
+		_, err := pw.Write(headerBytes)
+		if err == nil {
+			n, err = encryptContent(pw, r, aead, header.noncePrefix)
+		}
+		_ = pw.CloseWithError(err)
+		done <- result{n: n, err: err}
+	}()
+
+	_, err = s.storage.Save(path, pr, encryptedSize)
+	_ = pr.Close()
+	res := <-done
+	if err != nil {
+		return 0, err
+	}
+	return res.n, res.err
+}
+
+// Stat returns the info of an object with the size of the unencrypted content
+func (s *encryptedStorage) Stat(path string) (os.FileInfo, error) {
+	obj, err := s.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer obj.Close()
+	return obj.Stat()
+}
+
+func (s *encryptedStorage) Delete(path string) error {
+	return s.storage.Delete(path)
+}
+
+// URL isn't supported, the storage only has the encrypted objects
+func (s *encryptedStorage) URL(path, name string) (*url.URL, error) {
+	return nil, ErrURLNotSupported
+}
+
+func (s *encryptedStorage) IterateObjects(path string, iterator func(path string, obj Object) error) error {
+	return s.storage.IterateObjects(path, func(path string, obj Object) error {
+		decrypted, err := s.decryptObject(obj)
+		if err != nil {
+			return fmt.Errorf("unable to decrypt %s: %w", path, err)
+		}
+		return iterator(path, decrypted)
+	})
+}
+
+// reencrypt encrypts an object which isn't encrypted yet, and rewraps the data key of
+// an object which was encrypted with an old master key. It returns if the object was changed.
+func (s *encryptedStorage) reencrypt(path string) (bool, error) {
+	obj, err := s.storage.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer obj.Close()
+
+	header, headerSize, err := readEncryptionHeader(obj)
+	if err == errNotEncrypted {
+		if _, err := obj.Seek(0, io.SeekStart); err != nil {
+			return false, err
+		}
+		info, err := obj.Stat()
+		if err != nil {
+			return false, err
+		}
+		_, err = s.Save(path, obj, info.Size())
+		return err == nil, err
+	} else if err != nil {
+		return false, err
+	}
+	if s.keys.IsCurrentKey(header.keyID, header.wrappedKey) {
+		return false, nil
+	}
+
+	// only the data key is rewrapped, the encrypted content stays the same
+	dataKey, err := s.keys.UnwrapKey(header.keyID, header.wrappedKey)
+	if err != nil {
+		return false, fmt.Errorf("unable to unwrap the data key: %w", err)
+	}
+	if header.keyID, header.wrappedKey, err = s.keys.WrapKey(dataKey); err != nil {
+		return false, fmt.Errorf("unable to wrap the data key: %w", err)
+	}
+	info, err := obj.Stat()
+	if err != nil {
+		return false, err
+	}
+	headerBytes := header.marshal()
+	_, err = s.storage.Save(path, io.MultiReader(bytes.NewReader(headerBytes), obj), int64(len(headerBytes))+info.Size()-headerSize)
+	return err == nil, err
+}
+
+// ReencryptObjects encrypts the objects of an encrypting storage which were stored before the
+// encryption was enabled, and rewraps the data keys which were wrapped with old master keys.
+// It returns the number of changed objects.
+func ReencryptObjects(ctx context.Context, s ObjectStorage) (int, error) {
+	if _, ok := s.(discardStorage); ok {
+		// the kind of objects isn't enabled, so nothing is stored
+		return 0, nil
+	}
+	es, ok := s.(*encryptedStorage)
+	if !ok {
+		return 0, errors.New("the storage isn't encrypted")
+	}
+
+	var paths []string
+	if err := es.storage.IterateObjects("", func(path string, obj Object) error {
+		paths = append(paths, path)
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+
+	changed := 0
+	for _, p := range paths {
+		select {
+		case <-ctx.Done():
+			return changed, ctx.Err()
+		default:
+		}
+		ok, err := es.reencrypt(p)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				// the object was deleted meanwhile
+				continue
+			}
+			return changed, fmt.Errorf("unable to reencrypt %s: %w", p, err)
+		}
+		if ok {
+			log.Trace("Reencrypted %s", p)
+			changed++
+		}
+	}
+	return changed, nil
+}
+
+// encryptedObject decrypts the chunks of an encrypted object on read
+type encryptedObject struct {
+	obj         Object
+	aead        cipher.AEAD
+	noncePrefix []byte
+	headerSize  int64
+	objSize     int64
+	objOffset   int64
+	size        int64
+	chunks      int64
+	offset      int64
+
+	chunkIndex int64
+	chunk      []byte
+	buf        []byte
+}
+
+func (o *encryptedObject) loadChunk(index int64) error {
+	start := o.headerSize + index*(encryptionChunkSize+encryptionTagSize)
+	end := start + encryptionChunkSize + encryptionTagSize
+	if end > o.objSize {
+		end = o.objSize
+	}
+	if o.objOffset != start {
+		if _, err := o.obj.Seek(start, io.SeekStart); err != nil {
+			return err
+		}
+		o.objOffset = start
+	}
+	if o.buf == nil {
+		o.buf = make([]byte, encryptionChunkSize+encryptionTagSize)
+	}
+	n, err := io.ReadFull(o.obj, o.buf[:end-start])
+	o.objOffset += int64(n)
+	if err != nil {
+		return err
+	}
+
+	o.chunk, err = o.aead.Open(o.chunk[:0], chunkNonce(o.noncePrefix, index, index == o.chunks-1), o.buf[:n], nil)
+	if err != nil {
+		o.chunkIndex = -1
+		return fmt.Errorf("unable to decrypt chunk %d: %w", index, err)
+	}
+	o.chunkIndex = index
+	return nil
+}
+
+func (o *encryptedObject) Read(p []byte) (int, error) {
+	if o.offset >= o.size {
+		return 0, io.EOF
+	}
+	index := o.offset / encryptionChunkSize
+	if index != o.chunkIndex {
+		if err := o.loadChunk(index); err != nil {
+			return 0, err
+		}
+	}
+	n := copy(p, o.chunk[o.offset-index*encryptionChunkSize:])
+	o.offset += int64(n)
+	return n, nil
+}
+
+func (o *encryptedObject) Seek(offset int64, whence int) (int64, error) {
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		offset += o.offset
+	case io.SeekEnd:
+		offset += o.size
+	default:
+		return 0, errors.New("Seek: invalid whence")
+	}
+	if offset < 0 {
+		return 0, errors.New("Seek: invalid offset")
+	}
+	o.offset = offset
+	return offset, nil
+}
+
+func (o *encryptedObject) Stat() (os.FileInfo, error) {
+	info, err := o.obj.Stat()
+	if err != nil {
+		return nil, err
+	}
+	return decryptedFileInfo{FileInfo: info, size: o.size}, nil
+}
+
+func (o *encryptedObject) Close() error {
+	return o.obj.Close()
+}
+
+type decryptedFileInfo struct {
+	os.FileInfo
+	size int64
+}
+
+func (fi decryptedFileInfo) Size() int64 {
+	return fi.size
+}
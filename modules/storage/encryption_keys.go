@@ -0,0 +1,194 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// KeyProvider wraps the data keys of encrypted objects with master keys
+type KeyProvider interface {
+	// WrapKey encrypts a data key with the current master key and returns the id of the master key
+	WrapKey(dataKey []byte) (keyID string, wrapped []byte, err error)
+	// UnwrapKey decrypts a data key which was wrapped with the master key with the given id
+	UnwrapKey(keyID string, wrapped []byte) ([]byte, error)
+	// IsCurrentKey returns if a data key was wrapped with the current master key, so doesn't need to be rewrapped
+	IsCurrentKey(keyID string, wrapped []byte) bool
+}
+
+func newKeyProvider() (KeyProvider, error) {
+	switch setting.StorageEncryption.KeyProvider {
+	case "local":
+		return &localKeyProvider{
+			keys:        setting.StorageEncryption.MasterKeys,
+			activeKeyID: setting.StorageEncryption.ActiveKeyID,
+		}, nil
+	case "vault":
+		return newVaultKeyProvider(
+			setting.StorageEncryption.VaultAddress,
+			setting.StorageEncryption.VaultToken,
+			setting.StorageEncryption.VaultMountPath,
+			setting.StorageEncryption.VaultKeyName,
+		)
+	}
+	return nil, fmt.Errorf("unsupported key provider: %s", setting.StorageEncryption.KeyProvider)
+}
+
+// localKeyProvider wraps data keys with master keys from the configuration using AES-GCM
+type localKeyProvider struct {
+	keys        map[string][]byte
+	activeKeyID string
+}
+
+func (p *localKeyProvider) aead(keyID string) (cipher.AEAD, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, fmt.Errorf("master key %q does not exist", keyID)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func (p *localKeyProvider) WrapKey(dataKey []byte) (string, []byte, error) {
+	aead, err := p.aead(p.activeKeyID)
+	if err != nil {
+		return "", nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", nil, err
+	}
+	return p.activeKeyID, aead.Seal(nonce, nonce, dataKey, []byte(p.activeKeyID)), nil
+}
+
+func (p *localKeyProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	aead, err := p.aead(keyID)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < aead.NonceSize() {
+		return nil, errors.New("wrapped data key is too short")
+	}
+	return aead.Open(nil, wrapped[:aead.NonceSize()], wrapped[aead.NonceSize():], []byte(keyID))
+}
+
+func (p *localKeyProvider) IsCurrentKey(keyID string, _ []byte) bool {
+	return keyID == p.activeKeyID
+}
+
+// vaultKeyProvider wraps data keys with a key of the transit secrets engine of HashiCorp Vault,
+// the master key never leaves Vault and is rotated there
+type vaultKeyProvider struct {
+	client        *http.Client
+	baseURL       string
+	token         string
+	keyName       string
+	latestVersion int
+}
+
+func newVaultKeyProvider(address, token, mountPath, keyName string) (*vaultKeyProvider, error) {
+	p := &vaultKeyProvider{
+		client:  &http.Client{Timeout: 30 * time.Second},
+		baseURL: address + "/v1/" + mountPath,
+		token:   token,
+		keyName: keyName,
+	}
+
+	var resp struct {
+		Data struct {
+			LatestVersion int `json:"latest_version"`
+		} `json:"data"`
+	}
+	if err := p.request(http.MethodGet, "/keys/"+keyName, nil, &resp); err != nil {
+		return nil, fmt.Errorf("unable to read the vault key %q: %w", keyName, err)
+	}
+	p.latestVersion = resp.Data.LatestVersion
+	return p, nil
+}
+
+func (p *vaultKeyProvider) request(method, path string, body, result interface{}) error {
+	var reqBody io.Reader
+	if body != nil {
+		bs, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(bs)
+	}
+	req, err := http.NewRequest(method, p.baseURL+path, reqBody)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("X-Vault-Token", p.token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("vault responded with %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	return json.NewDecoder(resp.Body).Decode(result)
+}
+
+func (p *vaultKeyProvider) WrapKey(dataKey []byte) (string, []byte, error) {
+	var resp struct {
+		Data struct {
+			Ciphertext string `json:"ciphertext"`
+		} `json:"data"`
+	}
+	if err := p.request(http.MethodPost, "/encrypt/"+p.keyName, map[string]string{
+		"plaintext": base64.StdEncoding.EncodeToString(dataKey),
+	}, &resp); err != nil {
+		return "", nil, err
+	}
+	return p.keyName, []byte(resp.Data.Ciphertext), nil
+}
+
+func (p *vaultKeyProvider) UnwrapKey(keyID string, wrapped []byte) ([]byte, error) {
+	var resp struct {
+		Data struct {
+			Plaintext string `json:"plaintext"`
+		} `json:"data"`
+	}
+	if err := p.request(http.MethodPost, "/decrypt/"+keyID, map[string]string{
+		"ciphertext": string(wrapped),
+	}, &resp); err != nil {
+		return nil, err
+	}
+	return base64.StdEncoding.DecodeString(resp.Data.Plaintext)
+}
+
+// IsCurrentKey checks the key version of the ciphertext, which has the form "vault:v<version>:<data>"
+func (p *vaultKeyProvider) IsCurrentKey(keyID string, wrapped []byte) bool {
+	if keyID != p.keyName {
+		return false
+	}
+	parts := strings.SplitN(string(wrapped), ":", 3)
+	if len(parts) != 3 || !strings.HasPrefix(parts[1], "v") {
+		return false
+	}
+	version, err := strconv.Atoi(parts[1][1:])
+	return err == nil && version >= p.latestVersion
+}
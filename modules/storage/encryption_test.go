@@ -0,0 +1,121 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestEncryptedStorage(t *testing.T) (ObjectStorage, *localKeyProvider, string) {
+	dir := t.TempDir()
+	l, err := NewLocalStorage(context.Background(), LocalStorageConfig{Path: dir, TemporaryPath: t.TempDir()})
+	assert.NoError(t, err)
+	keys := &localKeyProvider{
+		keys: map[string][]byte{
+			"old": bytes.Repeat([]byte{1}, 32),
+			"new": bytes.Repeat([]byte{2}, 32),
+		},
+		activeKeyID: "old",
+	}
+	return NewEncryptedStorage(l, keys), keys, dir
+}
+
+func TestEncryptedStorage(t *testing.T) {
+	s, _, dir := newTestEncryptedStorage(t)
+
+	for _, size := range []int{0, 1, encryptionChunkSize - 1, encryptionChunkSize, 2*encryptionChunkSize + 7} {
+		data := make([]byte, size)
+		_, _ = rand.Read(data)
+
+		for _, knownSize := range []int64{int64(size), -1} {
+			n, err := s.Save("a/b", bytes.NewReader(data), knownSize)
+			assert.NoError(t, err)
+			assert.EqualValues(t, size, n)
+
+			raw, err := os.ReadFile(filepath.Join(dir, "a/b"))
+			assert.NoError(t, err)
+			if size > 0 {
+				assert.False(t, bytes.Contains(raw, data))
+			}
+
+			info, err := s.Stat("a/b")
+			assert.NoError(t, err)
+			assert.EqualValues(t, size, info.Size())
+
+			obj, err := s.Open("a/b")
+			assert.NoError(t, err)
+			content, err := io.ReadAll(obj)
+			assert.NoError(t, err)
+			assert.Equal(t, data, content)
+
+			if size > 10 {
+				_, err = obj.Seek(-5, io.SeekEnd)
+				assert.NoError(t, err)
+				content, err = io.ReadAll(obj)
+				assert.NoError(t, err)
+				assert.Equal(t, data[size-5:], content)
+			}
+			assert.NoError(t, obj.Close())
+		}
+	}
+}
+
+func TestEncryptedStorageTampered(t *testing.T) {
+	s, _, dir := newTestEncryptedStorage(t)
+
+	_, err := s.Save("a", bytes.NewReader(make([]byte, 3*encryptionChunkSize)), -1)
+	assert.NoError(t, err)
+
+	raw, err := os.ReadFile(filepath.Join(dir, "a"))
+	assert.NoError(t, err)
+	// drop the last chunk
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a"), raw[:len(raw)-encryptionChunkSize-encryptionTagSize], 0o644))
+
+	obj, err := s.Open("a")
+	assert.NoError(t, err)
+	defer obj.Close()
+	_, err = io.ReadAll(obj)
+	assert.Error(t, err)
+}
+
+func TestReencryptObjects(t *testing.T) {
+	s, keys, dir := newTestEncryptedStorage(t)
+
+	// stored before the encryption was enabled
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "plain"), []byte("plain content"), 0o644))
+	_, err := s.Save("encrypted", bytes.NewReader([]byte("encrypted content")), -1)
+	assert.NoError(t, err)
+
+	readAll := func(p string) string {
+		obj, err := s.Open(p)
+		assert.NoError(t, err)
+		defer obj.Close()
+		content, err := io.ReadAll(obj)
+		assert.NoError(t, err)
+		return string(content)
+	}
+	assert.Equal(t, "plain content", readAll("plain"))
+
+	keys.activeKeyID = "new"
+	changed, err := ReencryptObjects(context.Background(), s)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, changed)
+
+	// the old master key isn't needed anymore
+	delete(keys.keys, "old")
+	assert.Equal(t, "plain content", readAll("plain"))
+	assert.Equal(t, "encrypted content", readAll("encrypted"))
+
+	changed, err = ReencryptObjects(context.Background(), s)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, changed)
+}
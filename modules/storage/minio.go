@@ -6,6 +6,7 @@ package storage
 import (
 	"context"
 	"crypto/tls"
+	"encoding/base64"
 	"fmt"
 	"io"
 	"net/http"
@@ -23,7 +24,8 @@ import (
 )
 
 var (
-	_ ObjectStorage = &MinioStorage{}
+	_ ObjectStorage  = &MinioStorage{}
+	_ DirectUploader = &MinioStorage{}
 
 	quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
 )
@@ -227,6 +229,34 @@ func (m *MinioStorage) URL(path, name string) (*url.URL, error) {
 	return u, convertMinioErr(err)
 }
 
+// UploadURL returns a presigned URL to upload an object, the checksum header makes the storage verify the content
+func (m *MinioStorage) UploadURL(path string, sha256sum []byte, expiry time.Duration) (*url.URL, http.Header, error) {
+	if m.cfg.ChecksumAlgorithm == "md5" {
+		// the storage doesn't support the checksum headers, so it couldn't verify the content
+		return nil, nil, ErrURLNotSupported
+	}
+	header := make(http.Header)
+	header.Set("x-amz-checksum-sha256", base64.StdEncoding.EncodeToString(sha256sum))
+	u, err := m.client.PresignHeader(m.ctx, http.MethodPut, m.bucket, m.buildMinioPath(path), expiry, nil, header)
+	if err != nil {
+		return nil, nil, convertMinioErr(err)
+	}
+	return u, header, nil
+}
+
+// Move moves an object within the bucket with a server side copy
+func (m *MinioStorage) Move(srcPath, dstPath string) error {
+	// ComposeObject copies objects larger than the 5 GiB limit of CopyObject in parts
+	_, err := m.client.ComposeObject(m.ctx,
+		minio.CopyDestOptions{Bucket: m.bucket, Object: m.buildMinioPath(dstPath)},
+		minio.CopySrcOptions{Bucket: m.bucket, Object: m.buildMinioPath(srcPath)},
+	)
+	if err != nil {
+		return convertMinioErr(err)
+	}
+	return m.Delete(srcPath)
+}
+
 // IterateObjects iterates across the objects in the miniostorage
 func (m *MinioStorage) IterateObjects(dirName string, fn func(path string, obj Object) error) error {
 	opts := minio.GetObjectOptions{}
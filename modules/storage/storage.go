@@ -8,8 +8,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
 	"os"
+	"time"
 
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -68,6 +70,15 @@ type ObjectStorage interface {
 	IterateObjects(path string, iterator func(path string, obj Object) error) error
 }
 
+// DirectUploader is implemented by the object storages which accept uploads of clients with presigned URLs
+type DirectUploader interface {
+	// UploadURL returns a presigned URL to upload an object with a PUT request which has to send the returned headers,
+	// the storage rejects content which doesn't have the SHA-256 checksum
+	UploadURL(path string, sha256sum []byte, expiry time.Duration) (*url.URL, http.Header, error)
+	// Move moves an object within the storage without transferring its content
+	Move(srcPath, dstPath string) error
+}
+
 // Copy copies a file from source ObjectStorage to dest ObjectStorage
 func Copy(dstStorage ObjectStorage, dstPath string, srcStorage ObjectStorage, srcPath string) (int64, error) {
 	f, err := srcStorage.Open(srcPath)
@@ -135,6 +146,7 @@ var (
 // Init init the stoarge
 func Init() error {
 	for _, f := range []func() error{
+		initEncryption,
 		initAttachments,
 		initAvatars,
 		initRepoAvatars,
@@ -142,6 +154,7 @@ func Init() error {
 		initRepoArchives,
 		initPackages,
 		initActions,
+		initStorageRegions,
 	} {
 		if err := f(); err != nil {
 			return err
@@ -163,6 +176,31 @@ func NewStorage(typStr string, cfg interface{}) (ObjectStorage, error) {
 	return fn(context.Background(), cfg)
 }
 
+// encryptionKeys wraps the data keys of encrypted objects, nil if the encryption isn't enabled
+var encryptionKeys KeyProvider
+
+func initEncryption() (err error) {
+	encryptionKeys = nil
+	if !setting.StorageEncryption.Enabled {
+		return nil
+	}
+	log.Info("Initialising storage encryption with key provider: %s", setting.StorageEncryption.KeyProvider)
+	encryptionKeys, err = newKeyProvider()
+	return err
+}
+
+// newStorageOf creates the storage for a kind of objects, which encrypts them if configured
+func newStorageOf(name string, storageSetting setting.Storage) (ObjectStorage, error) {
+	s, err := NewStorage(storageSetting.Type, &storageSetting)
+	if err != nil {
+		return nil, err
+	}
+	if setting.IsStorageEncrypted(name) {
+		return NewEncryptedStorage(s, encryptionKeys), nil
+	}
+	return s, nil
+}
+
 func initAvatars() (err error) {
 	log.Info("Initialising Avatar storage with type: %s", setting.Avatar.Storage.Type)
 	Avatars, err = NewStorage(setting.Avatar.Storage.Type, &setting.Avatar.Storage)
@@ -175,7 +213,7 @@ func initAttachments() (err error) {
 		return nil
 	}
 	log.Info("Initialising Attachment storage with type: %s", setting.Attachment.Storage.Type)
-	Attachments, err = NewStorage(setting.Attachment.Storage.Type, &setting.Attachment.Storage)
+	Attachments, err = newStorageOf("attachments", setting.Attachment.Storage)
 	return err
 }
 
@@ -185,7 +223,7 @@ func initLFS() (err error) {
 		return nil
 	}
 	log.Info("Initialising LFS storage with type: %s", setting.LFS.Storage.Type)
-	LFS, err = NewStorage(setting.LFS.Storage.Type, &setting.LFS.Storage)
+	LFS, err = newStorageOf("lfs", setting.LFS.Storage)
 	return err
 }
 
@@ -207,7 +245,7 @@ func initPackages() (err error) {
 		return nil
 	}
 	log.Info("Initialising Packages storage with type: %s", setting.Packages.Storage.Type)
-	Packages, err = NewStorage(setting.Packages.Storage.Type, &setting.Packages.Storage)
+	Packages, err = newStorageOf("packages", setting.Packages.Storage)
 	return err
 }
 
@@ -225,3 +263,75 @@ func initActions() (err error) {
 	ActionsArtifacts, err = NewStorage(setting.Actions.ArtifactStorage.Type, &setting.Actions.ArtifactStorage)
 	return err
 }
+
+// regionStorages holds the storages of a storage region
+type regionStorages struct {
+	LFS         ObjectStorage
+	Attachments ObjectStorage
+	Packages    ObjectStorage
+}
+
+var storageRegions = map[string]*regionStorages{}
+
+func initStorageRegions() (err error) {
+	storageRegions = make(map[string]*regionStorages, len(setting.StorageRegions))
+	for name, region := range setting.StorageRegions {
+		log.Info("Initialising storages of storage region %s", name)
+		s := &regionStorages{
+			LFS:         discardStorage("LFS isn't enabled"),
+			Attachments: discardStorage("Attachment isn't enabled"),
+			Packages:    discardStorage("Packages isn't enabled"),
+		}
+		if setting.LFS.StartServer {
+			if s.LFS, err = newStorageOf("lfs", region.LFS); err != nil {
+				return err
+			}
+		}
+		if setting.Attachment.Enabled {
+			if s.Attachments, err = newStorageOf("attachments", region.Attachments); err != nil {
+				return err
+			}
+		}
+		if setting.Packages.Enabled {
+			if s.Packages, err = newStorageOf("packages", region.Packages); err != nil {
+				return err
+			}
+		}
+		storageRegions[name] = s
+	}
+	return nil
+}
+
+// getRegionStorages returns the storages of a storage region. Data of an unknown region
+// must not end up in the default storages, so all operations on them fail.
+func getRegionStorages(region string) *regionStorages {
+	if s, ok := storageRegions[region]; ok {
+		return s
+	}
+	unknown := discardStorage(fmt.Sprintf("storage region %q isn't configured", region))
+	return &regionStorages{LFS: unknown, Attachments: unknown, Packages: unknown}
+}
+
+// LFSOfRegion returns the LFS storage of a storage region, the default storage for the empty region
+func LFSOfRegion(region string) ObjectStorage {
+	if region == "" {
+		return LFS
+	}
+	return getRegionStorages(region).LFS
+}
+
+// AttachmentsOfRegion returns the attachment storage of a storage region, the default storage for the empty region
+func AttachmentsOfRegion(region string) ObjectStorage {
+	if region == "" {
+		return Attachments
+	}
+	return getRegionStorages(region).Attachments
+}
+
+// PackagesOfRegion returns the package storage of a storage region, the default storage for the empty region
+func PackagesOfRegion(region string) ObjectStorage {
+	if region == "" {
+		return Packages
+	}
+	return getRegionStorages(region).Packages
+}
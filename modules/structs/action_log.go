@@ -0,0 +1,18 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// ActionLogLine represents a line of the log of an Actions job
+type ActionLogLine struct {
+	// number of the line in the log of the job, starting at 1
+	Number int64 `json:"number"`
+	// swagger:strfmt date-time
+	Time time.Time `json:"time"`
+	// content of the line including its ANSI escape sequences
+	Content string `json:"content"`
+	// long lines are streamed in chunks, this is set if more chunks of the line follow
+	Continued bool `json:"continued,omitempty"`
+}
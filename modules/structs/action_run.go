@@ -0,0 +1,25 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// ActionRun represents a run of a workflow
+type ActionRun struct {
+	ID int64 `json:"id"`
+	// Index is the number of the run in its repository
+	Index        int64  `json:"index"`
+	Title        string `json:"title"`
+	WorkflowID   string `json:"workflow_id"`
+	Event        string `json:"event"`
+	Ref          string `json:"ref"`
+	CommitSHA    string `json:"commit_sha"`
+	TriggerUser  *User  `json:"trigger_user"`
+	Status       string `json:"status"`
+	IsFork       bool   `json:"is_fork_pull_request"`
+	NeedApproval bool   `json:"need_approval"`
+	HTMLURL      string `json:"html_url"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
@@ -53,3 +53,50 @@ type EditUserOption struct {
 	Restricted              *bool   `json:"restricted"`
 	Visibility              string  `json:"visibility" binding:"In(,public,limited,private)"`
 }
+
+// MigrateAuthSourceAccount is an account of the auth source the users are migrated to
+type MigrateAuthSourceAccount struct {
+	// Email or username of the user, depending on how the accounts are matched
+	//
+	// required: true
+	Match string `json:"match" binding:"Required"`
+	// Login name of the account in the new auth source, the matched email or username is used if it is empty
+	LoginName string `json:"login_name"`
+}
+
+// MigrateAuthSourceUsersOption options when migrating the users of an auth source to another one
+type MigrateAuthSourceUsersOption struct {
+	// ID of the auth source of the migrated users, 0 for the local users
+	FromSourceID int64 `json:"from_source_id"`
+	// ID of the auth source the users are migrated to
+	//
+	// required: true
+	ToSourceID int64 `json:"to_source_id" binding:"Required"`
+	// How the accounts are matched with the users
+	//
+	// enum: email,username
+	// required: true
+	MatchBy string `json:"match_by" binding:"Required;In(email,username)"`
+	// Accounts of the new auth source, all users of the old auth source are migrated if there are none
+	Accounts []*MigrateAuthSourceAccount `json:"accounts"`
+	// Only report what would be migrated, without changing anything
+	DryRun bool `json:"dry_run"`
+}
+
+// MigratedAuthSourceUser represents a user migrated to the new auth source
+type MigratedAuthSourceUser struct {
+	Username  string `json:"username"`
+	LoginName string `json:"login_name"`
+}
+
+// AuthSourceMigrationReport represents the users migrated to another auth source and the accounts which could not be matched
+type AuthSourceMigrationReport struct {
+	DryRun   bool                      `json:"dry_run"`
+	Migrated []*MigratedAuthSourceUser `json:"migrated"`
+	// Accounts which match no user of the old auth source
+	UnmatchedAccounts []string `json:"unmatched_accounts"`
+	// Users of the old auth source which no account matches
+	UnmatchedUsers []string `json:"unmatched_users"`
+	// Users whose login name is already used in the new auth source or matched by several accounts
+	Conflicts []string `json:"conflicts"`
+}
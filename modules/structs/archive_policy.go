@@ -0,0 +1,37 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// ArchivePolicy represents when the inactive repositories of an organization are archived automatically
+type ArchivePolicy struct {
+	// months without pushes or issue activity before a repository is archived, 0 disables archiving
+	InactiveMonths int `json:"inactive_months"`
+	// days between notifying the owners and archiving the repository
+	NoticeDays int `json:"notice_days"`
+	// repositories with one of these topics are never archived
+	ExemptTopics []string `json:"exempt_topics"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// EditArchivePolicyOption options for setting the archive policy of an organization
+type EditArchivePolicyOption struct {
+	InactiveMonths int      `json:"inactive_months"`
+	NoticeDays     int      `json:"notice_days"`
+	ExemptTopics   []string `json:"exempt_topics"`
+}
+
+// UpcomingArchival represents an inactive repository whose owners were notified it is going to be archived
+type UpcomingArchival struct {
+	RepoID   int64  `json:"repo_id"`
+	FullName string `json:"full_name"`
+	// swagger:strfmt date-time
+	LastActivity time.Time `json:"last_activity"`
+	// swagger:strfmt date-time
+	Notified time.Time `json:"notified_at"`
+	// swagger:strfmt date-time
+	Archive time.Time `json:"archive_at"`
+}
@@ -25,3 +25,30 @@ type Attachment struct {
 type EditAttachmentOptions struct {
 	Name string `json:"name"`
 }
+
+// AttachmentTypeRule allows a file type to be attached to issues, pull requests and releases
+type AttachmentTypeRule struct {
+	// an extension like ".pdf", a mime type like "application/zip" or a wildcard like "image/*"
+	Type string `json:"type"`
+	// maximum size in bytes of files of this type, 0 to only apply the global limit
+	MaxSize int64 `json:"max_size"`
+}
+
+// AttachmentPolicy represents the attachment policy of an organization
+type AttachmentPolicy struct {
+	Rules []*AttachmentTypeRule `json:"rules"`
+	// recompress uploaded jpeg and png images
+	OptimizeImages bool `json:"optimize_images"`
+	// maximum width and height of optimized images, 0 to keep their size
+	MaxImageDimension int `json:"max_image_dimension"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// EditAttachmentPolicyOption options for setting the attachment policy of an organization
+type EditAttachmentPolicyOption struct {
+	// the file types which may be attached, empty to only apply the global settings
+	Rules             []*AttachmentTypeRule `json:"rules"`
+	OptimizeImages    bool                  `json:"optimize_images"`
+	MaxImageDimension int                   `json:"max_image_dimension"`
+}
@@ -0,0 +1,20 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// BackgroundMigration represents the progress of a background migration
+type BackgroundMigration struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// number of rows when the background migration started
+	Total int64 `json:"total"`
+	// number of processed rows
+	Processed  int64  `json:"processed"`
+	IsFinished bool   `json:"is_finished"`
+	LastError  string `json:"last_error"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated"`
+}
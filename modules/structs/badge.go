@@ -0,0 +1,53 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// CoverageReport represents the test coverage of a commit
+// swagger:model
+type CoverageReport struct {
+	CommitSHA string `json:"commit_sha"`
+	Branch    string `json:"branch"`
+	// Percentage of covered lines
+	Coverage float64 `json:"coverage"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateCoverageReportOption options to upload the test coverage of a commit
+type CreateCoverageReportOption struct {
+	// required: true
+	CommitSHA string `json:"commit_sha" binding:"Required;MaxSize(64)"`
+	// Branch the commit was tested on, the coverage badge shows the last coverage of a branch
+	Branch string `json:"branch" binding:"MaxSize(255)"`
+	// Percentage of covered lines, required unless a report is given
+	Coverage *float64 `json:"coverage"`
+	// Format of the report
+	// enum: lcov,cobertura
+	Format string `json:"format"`
+	// Coverage report the percentage of covered lines and the coverage of the lines of the files are read from
+	Report string `json:"report"`
+	// Prefix removed from the paths of the files of the report to make them relative to the root of the repository
+	PathPrefix string `json:"path_prefix"`
+}
+
+// PullRequestDiffCoverage represents the test coverage of the lines added by a pull request
+// swagger:model
+type PullRequestDiffCoverage struct {
+	// Coverage report of the head commit of the pull request
+	Report *CoverageReport `json:"report"`
+	// Percentage of the added lines which are covered by tests
+	DiffCoverage      float64 `json:"diff_coverage"`
+	CoveredLines      int     `json:"covered_lines"`
+	InstrumentedLines int     `json:"instrumented_lines"`
+}
+
+// BadgeToken represents the token which allows to embed the badges of a private repository
+// swagger:model
+type BadgeToken struct {
+	Token string `json:"token"`
+	// URLs of the badges including the token, by kind of badge
+	Badges map[string]string `json:"badges"`
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// ChatOpsCommand represents a slash command which is executed by calling a webhook
+type ChatOpsCommand struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	URL         string `json:"url"`
+	// access to issues or pull requests required to run the command
+	// enum: read,write,admin
+	AccessMode string `json:"access_mode"`
+	Active     bool   `json:"active"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateChatOpsCommandOption options for registering a slash command
+type CreateChatOpsCommandOption struct {
+	// required: true
+	Name        string `json:"name" binding:"Required;AlphaDashDot;MaxSize(50)"`
+	Description string `json:"description"`
+	// required: true
+	URL string `json:"url" binding:"Required;ValidUrl"`
+	// used to sign the request in the X-Gitea-Signature header
+	Secret string `json:"secret"`
+	// enum: read,write,admin
+	AccessMode string `json:"access_mode"`
+	// default: true
+	Active *bool `json:"active"`
+}
+
+// EditChatOpsCommandOption options for editing a slash command
+type EditChatOpsCommandOption struct {
+	Description *string `json:"description"`
+	URL         *string `json:"url" binding:"ValidUrl"`
+	Secret      *string `json:"secret"`
+	// enum: read,write,admin
+	AccessMode *string `json:"access_mode"`
+	Active     *bool   `json:"active"`
+}
+
+// ChatOpsAudit represents an execution of a slash command
+type ChatOpsAudit struct {
+	ID        int64    `json:"id"`
+	RepoID    int64    `json:"repo_id"`
+	IssueID   int64    `json:"issue_id"`
+	CommentID int64    `json:"comment_id"`
+	DoerID    int64    `json:"doer_id"`
+	Command   string   `json:"command"`
+	Args      []string `json:"args"`
+	// enum: success,denied,failed
+	Status  string `json:"status"`
+	Message string `json:"message"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// ChatOpsCommandPayload is sent to the webhook of a slash command
+type ChatOpsCommandPayload struct {
+	Command    string      `json:"command"`
+	Args       []string    `json:"args"`
+	Repository *Repository `json:"repository"`
+	Issue      *Issue      `json:"issue"`
+	Comment    *Comment    `json:"comment,omitempty"`
+	Sender     *User       `json:"sender"`
+}
@@ -0,0 +1,63 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// CodeScanningAlert represents a finding of a code scanning tool
+type CodeScanningAlert struct {
+	ID              int64  `json:"id"`
+	Ref             string `json:"ref"`
+	Tool            string `json:"tool"`
+	RuleID          string `json:"rule_id"`
+	RuleDescription string `json:"rule_description"`
+	// enum: critical,high,medium,low
+	Severity  string `json:"severity"`
+	Message   string `json:"message"`
+	Path      string `json:"path"`
+	StartLine int    `json:"start_line"`
+	EndLine   int    `json:"end_line"`
+	CommitSHA string `json:"commit_sha"`
+	// enum: open,dismissed,fixed
+	State           string `json:"state"`
+	DismissedBy     *User  `json:"dismissed_by"`
+	DismissedReason string `json:"dismissed_reason"`
+	// swagger:strfmt date-time
+	Fixed *time.Time `json:"fixed_at"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CodeScanningAnalysis represents an uploaded report of a code scanning tool
+type CodeScanningAnalysis struct {
+	ID           int64  `json:"id"`
+	Ref          string `json:"ref"`
+	CommitSHA    string `json:"commit_sha"`
+	Tool         string `json:"tool"`
+	ResultsCount int    `json:"results_count"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// UploadSARIFOption options for uploading a SARIF report
+type UploadSARIFOption struct {
+	// SHA of the analyzed commit
+	// required: true
+	CommitSHA string `json:"commit_sha" binding:"Required;MaxSize(40)"`
+	// full name of the analyzed ref, e.g. refs/heads/main or refs/pull/1/head
+	// required: true
+	Ref string `json:"ref" binding:"Required;MaxSize(255)"`
+	// base64 encoded SARIF 2.1.0 file, optionally gzip compressed before encoding
+	// required: true
+	SARIF string `json:"sarif" binding:"Required"`
+}
+
+// EditCodeScanningAlertOption options for changing the state of a code scanning alert
+type EditCodeScanningAlertOption struct {
+	// enum: open,dismissed
+	State           string `json:"state" binding:"Required;In(open,dismissed)"`
+	DismissedReason string `json:"dismissed_reason"`
+}
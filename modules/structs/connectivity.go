@@ -0,0 +1,26 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// ConnectivityTestStep is a step of the test of an integration
+type ConnectivityTestStep struct {
+	Name string `json:"name"`
+	// enum: success,failure,skipped
+	Status  string `json:"status"`
+	Message string `json:"message,omitempty"`
+	// duration of the step in milliseconds
+	Duration int64 `json:"duration"`
+}
+
+// ConnectivityTestResult is the result of the test of an integration
+type ConnectivityTestResult struct {
+	Success bool                    `json:"success"`
+	Steps   []*ConnectivityTestStep `json:"steps"`
+}
+
+// SendTestMailOption options to send a test mail
+type SendTestMailOption struct {
+	// required: true
+	Email string `json:"email" binding:"Required;Email;MaxSize(254)"`
+}
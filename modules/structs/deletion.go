@@ -0,0 +1,35 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// DeletedRepository represents a deleted repository which can be restored until it is purged
+// swagger:model
+type DeletedRepository struct {
+	// ID of the repository
+	ID        int64  `json:"id"`
+	Owner     string `json:"owner"`
+	Name      string `json:"name"`
+	FullName  string `json:"full_name"`
+	DeletedBy string `json:"deleted_by"`
+	// swagger:strfmt date-time
+	Deleted time.Time `json:"deleted_at"`
+	// swagger:strfmt date-time
+	Purge time.Time `json:"purge_at"`
+}
+
+// DeletedPackageVersion represents a deleted package version which can be restored until it is purged
+// swagger:model
+type DeletedPackageVersion struct {
+	ID        int64  `json:"id"`
+	Type      string `json:"type"`
+	Name      string `json:"name"`
+	Version   string `json:"version"`
+	DeletedBy string `json:"deleted_by"`
+	// swagger:strfmt date-time
+	Deleted time.Time `json:"deleted_at"`
+	// swagger:strfmt date-time
+	Purge time.Time `json:"purge_at"`
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import (
+	"time"
+)
+
+// DiscussionCategory groups the discussions of a repository
+type DiscussionCategory struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// enum: general,qa,announcement
+	Kind string `json:"kind"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateDiscussionCategoryOption options for creating a discussion category
+type CreateDiscussionCategoryOption struct {
+	// required: true
+	Name        string `json:"name" binding:"Required;MaxSize(255)"`
+	Description string `json:"description"`
+	// questions (qa) can accept a reply as answer, announcements are only started and replied to by writers
+	// enum: general,qa,announcement
+	Kind string `json:"kind"`
+}
+
+// EditDiscussionCategoryOption options for editing a discussion category
+type EditDiscussionCategoryOption struct {
+	Name        *string `json:"name" binding:"OmitEmpty;MaxSize(255)"`
+	Description *string `json:"description"`
+	// enum: general,qa,announcement
+	Kind *string `json:"kind"`
+}
+
+// Discussion represents a discussion of a repository
+type Discussion struct {
+	ID       int64               `json:"id"`
+	URL      string              `json:"url"`
+	HTMLURL  string              `json:"html_url"`
+	Index    int64               `json:"number"`
+	Category *DiscussionCategory `json:"category"`
+	Poster   *User               `json:"user"`
+	Title    string              `json:"title"`
+	Body     string              `json:"body"`
+	IsLocked bool                `json:"is_locked"`
+	// id of the comment accepted as answer, 0 if the discussion is not an answered question
+	AnswerID int64 `json:"answer_id"`
+	Comments int64 `json:"comments"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateDiscussionOption options to create a discussion
+type CreateDiscussionOption struct {
+	// required: true
+	CategoryID int64 `json:"category_id" binding:"Required"`
+	// required: true
+	Title string `json:"title" binding:"Required"`
+	Body  string `json:"body"`
+}
+
+// EditDiscussionOption options for editing a discussion
+type EditDiscussionOption struct {
+	Title      *string `json:"title"`
+	Body       *string `json:"body"`
+	CategoryID *int64  `json:"category_id"`
+	// only writers can lock a discussion, a locked discussion can only be replied to by writers
+	IsLocked *bool `json:"is_locked"`
+}
+
+// DiscussionComment represents a reply to a discussion
+type DiscussionComment struct {
+	ID       int64  `json:"id"`
+	HTMLURL  string `json:"html_url"`
+	Poster   *User  `json:"user"`
+	Body     string `json:"body"`
+	IsAnswer bool   `json:"is_answer"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateDiscussionCommentOption options for replying to a discussion
+type CreateDiscussionCommentOption struct {
+	// required: true
+	Body string `json:"body" binding:"Required"`
+}
+
+// EditDiscussionCommentOption options for editing a reply to a discussion
+type EditDiscussionCommentOption struct {
+	// required: true
+	Body string `json:"body" binding:"Required"`
+}
+
+// SetDiscussionAnswerOption options for accepting a reply as answer of a question
+type SetDiscussionAnswerOption struct {
+	// required: true
+	CommentID int64 `json:"comment_id" binding:"Required"`
+}
+
+// ConvertIssueToDiscussionOption options for converting an issue to a discussion
+type ConvertIssueToDiscussionOption struct {
+	// required: true
+	CategoryID int64 `json:"category_id" binding:"Required"`
+}
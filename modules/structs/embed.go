@@ -0,0 +1,35 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// EmbedToken represents a token which allows other sites to embed the widgets of a repository
+// swagger:model
+type EmbedToken struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// Origins of the sites which may embed the widgets, e.g. https://portal.example.com
+	AllowedOrigins []string `json:"allowed_origins"`
+	// Widgets which may be embedded, of issues, releases and activity
+	Widgets []string `json:"widgets"`
+	Token   string   `json:"token"`
+	// URLs of the pages of the widgets including the token, by widget
+	URLs map[string]string `json:"urls"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateEmbedTokenOption options for creating an embed token
+type CreateEmbedTokenOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;MaxSize(255)"`
+	// Origins of the sites which may embed the widgets, e.g. https://portal.example.com
+	// required: true
+	AllowedOrigins []string `json:"allowed_origins" binding:"Required"`
+	// Widgets which may be embedded
+	// required: true
+	// enum: issues,releases,activity
+	Widgets []string `json:"widgets" binding:"Required"`
+}
@@ -0,0 +1,33 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// FederatedSearchResult represents a public issue, pull request or wiki page found by the federated search
+type FederatedSearchResult struct {
+	// base URL of the instance the result was found on
+	Instance string `json:"instance"`
+	// enum: issue,pull,wiki
+	Type     string `json:"type"`
+	RepoName string `json:"repo_full_name"`
+	Title    string `json:"title"`
+	HTMLURL  string `json:"html_url"`
+	// text around the first match of the keyword
+	Snippet string `json:"snippet"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// FederatedSearchPeerError represents a peer instance which couldn't be searched
+type FederatedSearchPeerError struct {
+	Instance string `json:"instance"`
+	Message  string `json:"message"`
+}
+
+// FederatedSearchResponse represents the merged results of this instance and its peers
+type FederatedSearchResponse struct {
+	Results []*FederatedSearchResult    `json:"results"`
+	Errors  []*FederatedSearchPeerError `json:"errors"`
+}
@@ -3,6 +3,8 @@
 
 package structs
 
+import "time"
+
 // CreateForkOption options for creating a fork
 type CreateForkOption struct {
 	// organization name, if forking into an organization
@@ -10,3 +12,77 @@ type CreateForkOption struct {
 	// name of the forked repository
 	Name *string `json:"name"`
 }
+
+// MergeUpstreamOption options for updating a branch of a fork from its upstream repository
+type MergeUpstreamOption struct {
+	// name of the branch of the fork to update
+	// required: true
+	Branch string `json:"branch" binding:"Required"`
+	// name of the branch of the upstream repository, defaults to the same name as branch
+	UpstreamBranch string `json:"upstream_branch"`
+	// only fast-forward the branch, fail if it has commits which are not in the upstream branch
+	FfOnly bool `json:"ff_only"`
+}
+
+// MergeUpstreamResult represents the result of updating a branch of a fork from its upstream repository
+type MergeUpstreamResult struct {
+	// how the branch was updated
+	// enum: none,fast-forward,merge
+	MergeType string `json:"merge_type"`
+	// the commit the branch points to after the update
+	CommitID string `json:"commit_id"`
+}
+
+// MergeUpstreamConflict represents the conflicts preventing a merge of the upstream branch
+type MergeUpstreamConflict struct {
+	Message         string   `json:"message"`
+	ConflictedFiles []string `json:"conflicted_files"`
+}
+
+// ForkSync represents a branch of a fork which is synced from its upstream branch on a schedule
+type ForkSync struct {
+	Branch         string `json:"branch"`
+	UpstreamBranch string `json:"upstream_branch"`
+	AllowMerge     bool   `json:"allow_merge"`
+	// status of the last sync
+	// enum: none,success,diverged,conflict,failed
+	LastStatus  string `json:"last_status"`
+	LastMessage string `json:"last_message"`
+	// swagger:strfmt date-time
+	LastSync *time.Time `json:"last_sync"`
+}
+
+// CreateForkSyncOption options for scheduling a branch of a fork to be synced from its upstream branch
+type CreateForkSyncOption struct {
+	// required: true
+	Branch string `json:"branch" binding:"Required"`
+	// name of the branch of the upstream repository, defaults to the same name as branch
+	UpstreamBranch string `json:"upstream_branch"`
+	// merge the upstream branch if the branch can't be fast-forwarded
+	AllowMerge bool `json:"allow_merge"`
+}
+
+// ForkNetworkEntry represents a downstream fork in the fork network of a repository
+type ForkNetworkEntry struct {
+	Repository *Repository `json:"repository"`
+	// full name of the repository the fork was forked from
+	ParentFullName string `json:"parent_full_name"`
+	// 1 for direct forks, 2 for forks of those and so on
+	Depth int `json:"depth"`
+	// whether the ahead and behind counts are known, they are not for forks without commits
+	HasDivergence bool `json:"has_divergence"`
+	// number of commits on the default branch of the fork which are not on the default branch of its parent
+	AheadBy int `json:"ahead_by"`
+	// number of commits on the default branch of the parent which are not on the default branch of the fork
+	BehindBy int `json:"behind_by"`
+	// time of the latest commit on any branch of the fork
+	// swagger:strfmt date-time
+	LastCommitAt *time.Time `json:"last_commit_at"`
+}
+
+// ForkCommitMatch represents a downstream fork containing a commit
+type ForkCommitMatch struct {
+	Repository *Repository `json:"repository"`
+	// names of the branches of the fork containing the commit, limited to 10
+	Branches []string `json:"branches"`
+}
@@ -23,7 +23,13 @@ type Hook struct {
 	Config              map[string]string `json:"config"`
 	Events              []string          `json:"events"`
 	AuthorizationHeader string            `json:"authorization_header"`
-	Active              bool              `json:"active"`
+	// Comma separated IP addresses and CIDR ranges the target is pinned to
+	AllowedIPs           string `json:"allowed_ips"`
+	HasClientCertificate bool   `json:"has_client_certificate"`
+	// Until when the payloads are also signed with the secret replaced by the last rotation
+	// swagger:strfmt date-time
+	PreviousSecretExpires *time.Time `json:"previous_secret_expires_at,omitempty"`
+	Active                bool       `json:"active"`
 	// swagger:strfmt date-time
 	Updated time.Time `json:"updated_at"`
 	// swagger:strfmt date-time
@@ -33,6 +39,27 @@ type Hook struct {
 // HookList represents a list of API hook.
 type HookList []*Hook
 
+// InstanceHook represents a webhook of a repository, user or organization, or a system or default webhook,
+// as listed for instance administrators
+type InstanceHook struct {
+	ID              int64    `json:"id"`
+	Type            string   `json:"type"`
+	TargetURL       string   `json:"target_url"`
+	RepoID          int64    `json:"repo_id"`
+	OwnerID         int64    `json:"owner_id"`
+	IsSystemWebhook bool     `json:"is_system_webhook"`
+	Events          []string `json:"events"`
+	Active          bool     `json:"active"`
+	// "succeed", "fail" or empty if the webhook has not been delivered yet
+	LastStatus string `json:"last_status"`
+	// swagger:strfmt date-time
+	LastDelivered *time.Time `json:"last_delivered_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
 // CreateHookOptionConfig has all config options in it
 // required are "content_type" and "url" Required
 type CreateHookOptionConfig map[string]string
@@ -47,6 +74,11 @@ type CreateHookOption struct {
 	Events              []string               `json:"events"`
 	BranchFilter        string                 `json:"branch_filter" binding:"GlobPattern"`
 	AuthorizationHeader string                 `json:"authorization_header"`
+	// PEM encoded certificate presented to the target, along with its key
+	ClientCertificate string `json:"client_certificate"`
+	ClientKey         string `json:"client_key"`
+	// Comma separated IP addresses and CIDR ranges the target is pinned to
+	AllowedIPs string `json:"allowed_ips"`
 	// default: false
 	Active bool `json:"active"`
 }
@@ -57,7 +89,28 @@ type EditHookOption struct {
 	Events              []string          `json:"events"`
 	BranchFilter        string            `json:"branch_filter" binding:"GlobPattern"`
 	AuthorizationHeader string            `json:"authorization_header"`
-	Active              *bool             `json:"active"`
+	// PEM encoded certificate presented to the target, along with its key. Both empty remove the certificate
+	ClientCertificate *string `json:"client_certificate"`
+	ClientKey         *string `json:"client_key"`
+	// Comma separated IP addresses and CIDR ranges the target is pinned to, empty to unpin it
+	AllowedIPs *string `json:"allowed_ips"`
+	Active     *bool   `json:"active"`
+}
+
+// RotateHookSecretOption options when rotating the secret of a hook
+type RotateHookSecretOption struct {
+	// New secret of the hook, a random secret is generated if it is empty
+	Secret string `json:"secret"`
+	// Number of hours the payloads are also signed with the current secret, 24 by default and at most 720
+	OverlapHours *int64 `json:"overlap_hours"`
+}
+
+// HookSecretRotation represents the secret of a hook after a rotation
+type HookSecretRotation struct {
+	Secret string `json:"secret"`
+	// Until when the payloads are also signed with the replaced secret
+	// swagger:strfmt date-time
+	PreviousSecretExpires *time.Time `json:"previous_secret_expires_at,omitempty"`
 }
 
 // Payloader payload is some part of one hook
@@ -100,6 +153,7 @@ type PayloadCommitVerification struct {
 	Signature string       `json:"signature"`
 	Signer    *PayloadUser `json:"signer"`
 	Payload   string       `json:"payload"`
+	Rotated   bool         `json:"rotated"`
 }
 
 var (
@@ -488,3 +542,73 @@ type PackagePayload struct {
 func (p *PackagePayload) JSONPayload() ([]byte, error) {
 	return json.MarshalIndent(p, "", "  ")
 }
+
+// HookDiscussionAction an action that happens to a discussion
+type HookDiscussionAction string
+
+const (
+	// HookDiscussionCreated created
+	HookDiscussionCreated HookDiscussionAction = "created"
+	// HookDiscussionEdited edited
+	HookDiscussionEdited HookDiscussionAction = "edited"
+	// HookDiscussionDeleted deleted
+	HookDiscussionDeleted HookDiscussionAction = "deleted"
+	// HookDiscussionAnswered a comment was accepted as answer
+	HookDiscussionAnswered HookDiscussionAction = "answered"
+	// HookDiscussionUnanswered the answer was removed
+	HookDiscussionUnanswered HookDiscussionAction = "unanswered"
+	// HookDiscussionLocked locked
+	HookDiscussionLocked HookDiscussionAction = "locked"
+	// HookDiscussionUnlocked unlocked
+	HookDiscussionUnlocked HookDiscussionAction = "unlocked"
+)
+
+// DiscussionPayload represents a payload information of discussion event.
+type DiscussionPayload struct {
+	Action     HookDiscussionAction `json:"action"`
+	Discussion *Discussion          `json:"discussion"`
+	Repository *Repository          `json:"repository"`
+	Sender     *User                `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *DiscussionPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// DiscussionCommentPayload represents a payload information of discussion comment event.
+type DiscussionCommentPayload struct {
+	Action     HookIssueCommentAction `json:"action"`
+	Discussion *Discussion            `json:"discussion"`
+	Comment    *DiscussionComment     `json:"comment"`
+	Repository *Repository            `json:"repository"`
+	Sender     *User                  `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *DiscussionCommentPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
+
+// HookActionRunApprovalAction an action that happens to the approval of a workflow run
+type HookActionRunApprovalAction string
+
+const (
+	// HookActionRunApprovalRequested the run waits for the approval of a maintainer
+	HookActionRunApprovalRequested HookActionRunApprovalAction = "requested"
+	// HookActionRunApprovalApproved the run was approved
+	HookActionRunApprovalApproved HookActionRunApprovalAction = "approved"
+)
+
+// ActionRunApprovalPayload represents a payload information of action run approval event.
+type ActionRunApprovalPayload struct {
+	Action     HookActionRunApprovalAction `json:"action"`
+	Run        *ActionRun                  `json:"run"`
+	Repository *Repository                 `json:"repository"`
+	Sender     *User                       `json:"sender"`
+}
+
+// JSONPayload implements Payload
+func (p *ActionRunApprovalPayload) JSONPayload() ([]byte, error) {
+	return json.MarshalIndent(p, "", "  ")
+}
@@ -0,0 +1,88 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// InsightsDuration summarizes the durations of the pull requests merged in a time range
+type InsightsDuration struct {
+	// number of pull requests the duration is known of
+	Count int64 `json:"count"`
+	// average duration in seconds
+	Average int64 `json:"average"`
+	// median duration in seconds
+	Median int64 `json:"median"`
+}
+
+// InsightsMetrics represents the delivery metrics of a time range
+type InsightsMetrics struct {
+	MergedPullRequests int64 `json:"merged_pull_requests"`
+	// merged pull requests per week
+	MergeFrequency float64 `json:"merge_frequency"`
+	// published releases, which count as deployments
+	Deployments int64 `json:"deployments"`
+	// deployments per week
+	DeploymentFrequency float64 `json:"deployment_frequency"`
+	// time from opening a pull request to merging it
+	CycleTime InsightsDuration `json:"cycle_time"`
+	// time from the first commit of a pull request to merging it
+	LeadTime InsightsDuration `json:"lead_time"`
+	// time from opening a pull request to its first review by someone else than its poster
+	ReviewLatency InsightsDuration `json:"review_latency"`
+}
+
+// RepoInsights represents the delivery metrics of a repository
+type RepoInsights struct {
+	Repository *Repository `json:"repository"`
+	InsightsMetrics
+}
+
+// Insights represents the delivery metrics of a repository or an organization in a time range
+type Insights struct {
+	// swagger:strfmt date-time
+	Since time.Time `json:"since"`
+	// swagger:strfmt date-time
+	Before time.Time `json:"before"`
+	InsightsMetrics
+	// metrics of the repositories with merged pull requests or releases, only for organizations
+	Repositories []*RepoInsights `json:"repositories,omitempty"`
+}
+
+// PathContributor represents the contributions of an author to a directory
+type PathContributor struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	// the user the email belongs to, if any
+	User    *User `json:"user,omitempty"`
+	Commits int64 `json:"commits"`
+	// lines added and deleted
+	Lines int64 `json:"lines"`
+	// swagger:strfmt date-time
+	LastCommitAt time.Time `json:"last_commit_at"`
+}
+
+// PathOwnership represents the ownership of a directory of a repository
+type PathOwnership struct {
+	// the directory, empty for the root of the repository
+	Path    string `json:"path"`
+	Commits int64  `json:"commits"`
+	Lines   int64  `json:"lines"`
+	// swagger:strfmt date-time
+	LastCommitAt time.Time `json:"last_commit_at"`
+	// smallest number of contributors who changed more than half of the lines
+	BusFactor int `json:"bus_factor"`
+	// whether none of the contributors counted for the bus factor is an active user
+	Unowned bool `json:"unowned"`
+	// top contributors, sorted by the lines they changed
+	Contributors []*PathContributor `json:"contributors"`
+}
+
+// PathOwnershipReport represents the ownership of the directories of a repository
+type PathOwnershipReport struct {
+	// commit of the default branch the ownership was computed at, empty if it wasn't computed yet
+	CommitID string `json:"commit_id"`
+	// swagger:strfmt date-time
+	ComputedAt *time.Time       `json:"computed_at,omitempty"`
+	Paths      []*PathOwnership `json:"paths"`
+}
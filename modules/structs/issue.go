@@ -63,7 +63,13 @@ type Issue struct {
 	// enum: open,closed
 	State    StateType `json:"state"`
 	IsLocked bool      `json:"is_locked"`
-	Comments int       `json:"comments"`
+	// who can still comment on the locked issue, only set if it's locked
+	// enum: collaborators,maintainers
+	LockLevel string `json:"lock_level,omitempty"`
+	// when the locked issue is unlocked automatically
+	// swagger:strfmt date-time
+	LockUntil *time.Time `json:"lock_until,omitempty"`
+	Comments  int        `json:"comments"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -75,6 +81,18 @@ type Issue struct {
 
 	PullRequest *PullRequestMeta `json:"pull_request"`
 	Repo        *RepositoryMeta  `json:"repository"`
+	// the snippets matching the search string, only set when searching with highlight
+	SearchHighlights []*IssueSearchHighlight `json:"search_highlights,omitempty"`
+}
+
+// IssueSearchHighlight represents the snippets of a field of an issue which match a search string
+type IssueSearchHighlight struct {
+	// enum: title,content,comment
+	Field string `json:"field"`
+	// the comment the snippets are taken from, if the field is comment
+	CommentID int64 `json:"comment_id,omitempty"`
+	// html escaped snippets, the matching terms are wrapped in <mark> elements
+	Snippets []string `json:"snippets"`
 }
 
 // CreateIssueOption options to create one issue
@@ -110,6 +128,56 @@ type EditIssueOption struct {
 	RemoveDeadline *bool      `json:"unset_due_date"`
 }
 
+// ImportIssueAuthor is the author of an imported issue or comment in the system it is imported from
+type ImportIssueAuthor struct {
+	// name of the author in the other system
+	Name string `json:"name"`
+	// id of the author in the other system
+	ID int64 `json:"id"`
+}
+
+// ImportIssueComment is a comment of an imported issue
+type ImportIssueComment struct {
+	Author ImportIssueAuthor `json:"author"`
+	Body   string            `json:"body"`
+	// defaults to the creation time of the issue
+	// swagger:strfmt date-time
+	Created *time.Time `json:"created_at"`
+	// defaults to the creation time of the comment
+	// swagger:strfmt date-time
+	Updated *time.Time `json:"updated_at"`
+}
+
+// ImportIssue is an issue imported from another system with its original timestamps and author
+type ImportIssue struct {
+	// required:true
+	Title  string            `json:"title"`
+	Body   string            `json:"body"`
+	Author ImportIssueAuthor `json:"author"`
+	// names of labels of the repository
+	Labels []string `json:"labels"`
+	Closed bool     `json:"closed"`
+	// defaults to now
+	// swagger:strfmt date-time
+	Created *time.Time `json:"created_at"`
+	// defaults to the creation time of the issue
+	// swagger:strfmt date-time
+	Updated *time.Time `json:"updated_at"`
+	// defaults to the update time of the issue if it is closed
+	// swagger:strfmt date-time
+	ClosedAt *time.Time           `json:"closed_at"`
+	Comments []ImportIssueComment `json:"comments"`
+}
+
+// ImportIssuesOption options for importing issues from another system
+type ImportIssuesOption struct {
+	// required:true
+	Issues []ImportIssue `json:"issues"`
+	// maps the names of authors in the other system to the names of local users,
+	// the other authors are kept as placeholders showing their original name
+	Users map[string]string `json:"users"`
+}
+
 // EditDeadlineOption options for creating a deadline
 type EditDeadlineOption struct {
 	// required:true
@@ -18,6 +18,12 @@ type Comment struct {
 	OriginalAuthorID int64         `json:"original_author_id"`
 	Body             string        `json:"body"`
 	Attachments      []*Attachment `json:"assets"`
+	// reason a moderator hid the comment for, empty if it is not hidden
+	HiddenReason string `json:"hidden_reason"`
+	// id of the top-level comment this comment replies to, 0 for a top-level comment
+	ReplyToID int64 `json:"reply_to_id"`
+	// whether the replies to this comment are collapsed
+	ThreadCollapsed bool `json:"thread_collapsed"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at"`
 	// swagger:strfmt date-time
@@ -28,6 +34,8 @@ type Comment struct {
 type CreateIssueCommentOption struct {
 	// required:true
 	Body string `json:"body" binding:"Required"`
+	// id of the comment to reply to, a reply to a reply joins the thread of the top-level comment
+	ReplyToID int64 `json:"reply_to_id"`
 }
 
 // EditIssueCommentOption options for editing a comment
@@ -0,0 +1,53 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// LockIssueOption options for locking an issue or pull request
+type LockIssueOption struct {
+	// one of the lock reasons configured by the administrator, or empty
+	Reason string `json:"reason"`
+	// who can still comment, only the administrators of the repository can choose maintainers
+	// enum: collaborators,maintainers
+	// default: collaborators
+	Level string `json:"level" binding:"OmitEmpty;In(collaborators,maintainers)"`
+	// when the issue is unlocked automatically, empty keeps it locked
+	// swagger:strfmt date-time
+	Until *time.Time `json:"until"`
+}
+
+// IssueLockPolicy represents how the closed issues and pull requests of a repository are locked once inactive
+type IssueLockPolicy struct {
+	Enabled       bool `json:"enabled"`
+	ApplyToIssues bool `json:"apply_to_issues"`
+	ApplyToPulls  bool `json:"apply_to_pulls"`
+	// days a closed issue must be without activity before it's locked
+	DaysAfterClose int `json:"days_after_close"`
+	// enum: collaborators,maintainers
+	LockLevel   string `json:"lock_level"`
+	Reason      string `json:"reason"`
+	LockComment string `json:"lock_comment"`
+	// swagger:strfmt date-time
+	LastRun *time.Time `json:"last_run"`
+}
+
+// EditIssueLockPolicyOption options for setting the lock policy of a repository
+type EditIssueLockPolicyOption struct {
+	// default: true
+	Enabled *bool `json:"enabled"`
+	// default: true
+	ApplyToIssues *bool `json:"apply_to_issues"`
+	// default: true
+	ApplyToPulls *bool `json:"apply_to_pulls"`
+	// required: true
+	DaysAfterClose int `json:"days_after_close" binding:"Required;Range(1,3650)"`
+	// enum: collaborators,maintainers
+	// default: collaborators
+	LockLevel string `json:"lock_level" binding:"OmitEmpty;In(collaborators,maintainers)"`
+	// one of the lock reasons configured by the administrator, or empty
+	Reason string `json:"reason"`
+	// comment posted before an issue is locked, empty posts no comment
+	LockComment string `json:"lock_comment"`
+}
@@ -0,0 +1,52 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// StalePolicy represents how inactive issues and pull requests of a repository are handled
+type StalePolicy struct {
+	Enabled       bool `json:"enabled"`
+	ApplyToIssues bool `json:"apply_to_issues"`
+	ApplyToPulls  bool `json:"apply_to_pulls"`
+	// days without activity after which an issue is marked stale
+	DaysUntilStale int `json:"days_until_stale"`
+	// days after being marked stale without activity after which an issue is closed, 0 never closes
+	DaysUntilClose      int      `json:"days_until_close"`
+	StaleLabel          string   `json:"stale_label"`
+	StaleComment        string   `json:"stale_comment"`
+	CloseComment        string   `json:"close_comment"`
+	ExemptLabels        []string `json:"exempt_labels"`
+	ExemptMilestones    []string `json:"exempt_milestones"`
+	ExemptAllMilestones bool     `json:"exempt_all_milestones"`
+	// swagger:strfmt date-time
+	LastRun *time.Time `json:"last_run"`
+}
+
+// EditStalePolicyOption options for setting the stale policy of a repository
+type EditStalePolicyOption struct {
+	// default: true
+	Enabled *bool `json:"enabled"`
+	// default: true
+	ApplyToIssues *bool `json:"apply_to_issues"`
+	// default: true
+	ApplyToPulls *bool `json:"apply_to_pulls"`
+	// required: true
+	DaysUntilStale int `json:"days_until_stale" binding:"Required;Range(1,3650)"`
+	// 0 never closes stale issues
+	DaysUntilClose int `json:"days_until_close" binding:"Range(0,3650)"`
+	// name of the label marking stale issues, it is created if it does not exist
+	// default: stale
+	StaleLabel string `json:"stale_label"`
+	// comment posted when an issue is marked stale
+	StaleComment string `json:"stale_comment"`
+	// comment posted when a stale issue is closed
+	CloseComment string `json:"close_comment"`
+	// names of labels exempting issues from the policy
+	ExemptLabels []string `json:"exempt_labels"`
+	// names of milestones exempting issues from the policy
+	ExemptMilestones []string `json:"exempt_milestones"`
+	// exempt all issues which have a milestone
+	ExemptAllMilestones bool `json:"exempt_all_milestones"`
+}
@@ -0,0 +1,37 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// IssueSubscriptionRule represents a rule subscribing the user to the issues and pull requests it matches
+// swagger:model
+type IssueSubscriptionRule struct {
+	ID int64 `json:"id"`
+	// Kind of the rule: label, path or owned_paths
+	Kind string `json:"kind"`
+	// Value is the name of the label or the path, empty for owned_paths
+	Value string `json:"value"`
+	// Owner limits the rule to the repositories of a user or an organization
+	Owner string `json:"owner,omitempty"`
+	// Repository limits the rule to a repository, by full name
+	Repository string `json:"repository,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateIssueSubscriptionRuleOption options for creating a subscription rule
+type CreateIssueSubscriptionRuleOption struct {
+	// label subscribes to the issues and pull requests with the label, path to the pull requests changing files
+	// under the path and owned_paths to the pull requests changing the directories you are a main contributor of
+	// required: true
+	// enum: label,path,owned_paths
+	Kind string `json:"kind" binding:"Required"`
+	// Name of the label or path, e.g. pkg/net
+	Value string `json:"value" binding:"MaxSize(255)"`
+	// Limit the rule to the repositories of a user or an organization
+	Owner string `json:"owner"`
+	// Limit the rule to a repository, by full name, e.g. owner/repo
+	Repository string `json:"repository"`
+}
@@ -0,0 +1,57 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// LegalHold represents a legal hold of a repository or of an issue
+type LegalHold struct {
+	ID       int64  `json:"id"`
+	RepoID   int64  `json:"repo_id"`
+	RepoName string `json:"repo_full_name"`
+	// index of the held issue or pull request, 0 if the whole repository is held
+	IssueIndex int64  `json:"issue_index"`
+	Reason     string `json:"reason"`
+	CreatorID  int64  `json:"creator_id"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateLegalHoldOption options for placing a legal hold
+type CreateLegalHoldOption struct {
+	// required: true
+	Owner string `json:"owner" binding:"Required"`
+	// required: true
+	Repo string `json:"repo" binding:"Required"`
+	// index of the issue or pull request to hold, the whole repository is held if omitted
+	IssueIndex int64 `json:"issue_index"`
+	// required: true
+	Reason string `json:"reason" binding:"Required"`
+}
+
+// LegalRedaction represents the redaction of a content revision or of an attachment
+type LegalRedaction struct {
+	ID               int64  `json:"id"`
+	RepoID           int64  `json:"repo_id"`
+	IssueID          int64  `json:"issue_id"`
+	CommentID        int64  `json:"comment_id"`
+	ContentHistoryID int64  `json:"content_history_id"`
+	AttachmentID     int64  `json:"attachment_id"`
+	AttachmentUUID   string `json:"attachment_uuid"`
+	AttachmentName   string `json:"attachment_name"`
+	Reason           string `json:"reason"`
+	DoerID           int64  `json:"doer_id"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateLegalRedactionOption options for redacting a content revision or an attachment, exactly one of them has to be given
+type CreateLegalRedactionOption struct {
+	// id of an old revision of an issue or comment content
+	ContentHistoryID int64 `json:"content_history_id"`
+	// id of an attachment of an issue or comment
+	AttachmentID int64 `json:"attachment_id"`
+	// required: true
+	Reason string `json:"reason" binding:"Required"`
+}
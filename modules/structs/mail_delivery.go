@@ -0,0 +1,24 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// MailDelivery represents the delivery status of an outgoing mail
+type MailDelivery struct {
+	ID        int64  `json:"id"`
+	MessageID string `json:"message_id"`
+	Recipient string `json:"recipient"`
+	Subject   string `json:"subject"`
+	// name of the mail provider which accepted the mail
+	Provider          string `json:"provider"`
+	ProviderMessageID string `json:"provider_message_id"`
+	// enum: queued,sent,failed,delivered,bounced,complained
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
@@ -0,0 +1,18 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// MaintenanceMode represents the state of the read-only maintenance mode
+type MaintenanceMode struct {
+	Enabled bool   `json:"enabled"`
+	Message string `json:"message"`
+}
+
+// EnableMaintenanceModeOption options for enabling the read-only maintenance mode
+type EnableMaintenanceModeOption struct {
+	// message shown to the users, a default message is used if empty
+	Message string `json:"message"`
+	// seconds to wait until all queues are empty, 0 to not wait
+	DrainTimeout int64 `json:"drain_timeout"`
+}
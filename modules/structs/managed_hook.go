@@ -0,0 +1,42 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// ManagedHook represents a git hook script which runs for all repositories of the instance or of an organization
+type ManagedHook struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+	// enum: pre-receive,post-receive
+	Type    string `json:"type"`
+	Content string `json:"content"`
+	Active  bool   `json:"active"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateManagedHookOption options for creating a managed git hook
+type CreateManagedHookOption struct {
+	// required: true
+	Name string `json:"name" binding:"Required;AlphaDashDot;MaxSize(100)"`
+	// required: true
+	// enum: pre-receive,post-receive
+	Type string `json:"type" binding:"Required"`
+	// the script, starting with a shebang line
+	// required: true
+	Content string `json:"content" binding:"Required"`
+	// default: true
+	Active *bool `json:"active"`
+}
+
+// EditManagedHookOption options for editing a managed git hook
+type EditManagedHookOption struct {
+	// enum: pre-receive,post-receive
+	Type    *string `json:"type"`
+	Content *string `json:"content"`
+	Active  *bool   `json:"active"`
+}
@@ -24,3 +24,19 @@ type PushMirror struct {
 	Interval       string `json:"interval"`
 	SyncOnCommit   bool   `json:"sync_on_commit"`
 }
+
+// MirrorSyncHook represents the webhook the upstream forge of a pull mirror calls to trigger a sync
+// swagger:model
+type MirrorSyncHook struct {
+	Enabled bool `json:"enabled"`
+	// URL to configure as the webhook of the upstream repository
+	URL string `json:"url"`
+	// Secret of the webhook, only returned when it's set
+	Secret string `json:"secret,omitempty"`
+}
+
+// EditMirrorSyncHookOption options to enable the webhook which triggers a sync of a pull mirror
+type EditMirrorSyncHookOption struct {
+	// Secret of the webhook, a random secret is generated if it's empty
+	Secret string `json:"secret" binding:"MaxSize(255)"`
+}
@@ -0,0 +1,106 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// BlockedUser represents a user blocked by a user or organization
+type BlockedUser struct {
+	User *User  `json:"user"`
+	Note string `json:"note"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// BlockUserOption options for blocking a user
+type BlockUserOption struct {
+	// private note on why the user was blocked
+	Note string `json:"note" binding:"MaxSize(255)"`
+}
+
+// HideCommentOption options for hiding a comment
+type HideCommentOption struct {
+	// required: true
+	// enum: spam,abuse,off-topic,outdated,duplicate,resolved
+	Reason string `json:"reason" binding:"Required"`
+}
+
+// AbuseReport represents a report of abusive content
+type AbuseReport struct {
+	ID       int64 `json:"id"`
+	Reporter *User `json:"reporter"`
+	// enum: user,repository,issue,comment
+	ContentType string `json:"content_type"`
+	ContentID   int64  `json:"content_id"`
+	// enum: spam,abuse,malware,illegal,other
+	Category string `json:"category"`
+	Remarks  string `json:"remarks"`
+	// enum: open,resolved,dismissed
+	Status    string `json:"status"`
+	HandledBy *User  `json:"handled_by,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateAbuseReportOption options for reporting abusive content
+type CreateAbuseReportOption struct {
+	// required: true
+	// enum: user,repository,issue,comment
+	ContentType string `json:"content_type" binding:"Required"`
+	// required: true
+	ContentID int64 `json:"content_id" binding:"Required"`
+	// required: true
+	// enum: spam,abuse,malware,illegal,other
+	Category string `json:"category" binding:"Required"`
+	Remarks  string `json:"remarks" binding:"MaxSize(2000)"`
+}
+
+// EditAbuseReportOption options for handling an abuse report
+type EditAbuseReportOption struct {
+	// required: true
+	// enum: open,resolved,dismissed
+	Status string `json:"status" binding:"Required"`
+}
+
+// InteractionLimit represents a temporary limit on who may interact with a repository
+type InteractionLimit struct {
+	// accounts younger than this cannot open issues, pull requests or comment
+	MinAccountAgeDays int `json:"min_account_age_days"`
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires_at"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// EditInteractionLimitOption options for limiting interactions with a repository
+type EditInteractionLimitOption struct {
+	// required: true
+	MinAccountAgeDays int `json:"min_account_age_days" binding:"Required;Range(1,365)"`
+	// number of days the limit applies, 0 keeps it until it is removed
+	DurationDays int `json:"duration_days" binding:"Range(0,365)"`
+}
+
+// HeldContent represents a registration, issue or pull request held for review as likely spam
+type HeldContent struct {
+	ID int64 `json:"id"`
+	// enum: registration,issue,pull_request
+	Type       string      `json:"type"`
+	Poster     *User       `json:"poster"`
+	Repository *Repository `json:"repository,omitempty"`
+	Title      string      `json:"title"`
+	Content    string      `json:"content"`
+	Score      float64     `json:"score"`
+	Reasons    []string    `json:"reasons"`
+	// enum: pending,approved,rejected
+	Status    string `json:"status"`
+	HandledBy *User  `json:"handled_by,omitempty"`
+	// id of the issue or pull request created when it was approved
+	ResultID int64 `json:"result_id,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
@@ -3,6 +3,8 @@
 
 package structs
 
+import "time"
+
 // Organization represents an organization
 type Organization struct {
 	ID                        int64  `json:"id"`
@@ -54,3 +56,16 @@ type EditOrgOption struct {
 	Visibility                string `json:"visibility" binding:"In(,public,limited,private)"`
 	RepoAdminChangeTeamAccess *bool  `json:"repo_admin_change_team_access"`
 }
+
+// AvatarPolicy represents the avatar policy of an organization
+type AvatarPolicy struct {
+	// show all users with the default avatar on the pages of the organization and its repositories
+	EnforceDefaultAvatars bool `json:"enforce_default_avatars"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// EditAvatarPolicyOption options for setting the avatar policy of an organization
+type EditAvatarPolicyOption struct {
+	EnforceDefaultAvatars bool `json:"enforce_default_avatars"`
+}
@@ -19,6 +19,18 @@ type Team struct {
 	// example: {"repo.code":"read","repo.issues":"write","repo.ext_issues":"none","repo.wiki":"admin","repo.pulls":"owner","repo.releases":"none","repo.projects":"none","repo.ext_wiki":"none"}
 	UnitsMap         map[string]string `json:"units_map"`
 	CanCreateOrgRepo bool              `json:"can_create_org_repo"`
+	// directories and files of the code the members are limited to reading, empty for no restriction
+	CodePaths []string `json:"code_paths"`
+	// who is notified when the team is mentioned
+	// enum: all,leads,webhook
+	MentionMode string `json:"mention_mode"`
+	// type of the webhook mentions are posted to, if the mention mode is webhook
+	// enum: gitea,slack,discord,msteams
+	MentionWebhookType string `json:"mention_webhook_type"`
+	// number of mentions per hour which notify the team, 0 for no limit
+	MentionRateLimit int `json:"mention_rate_limit"`
+	// number of members who are requested to review in round-robin order when the team is, skipping busy members. 0 to request the whole team
+	ReviewAssignCount int `json:"review_assign_count"`
 }
 
 // CreateTeamOption options for creating a team
@@ -35,6 +47,20 @@ type CreateTeamOption struct {
 	// example: {"repo.code":"read","repo.issues":"write","repo.ext_issues":"none","repo.wiki":"admin","repo.pulls":"owner","repo.releases":"none","repo.projects":"none","repo.ext_wiki":"none"}
 	UnitsMap         map[string]string `json:"units_map"`
 	CanCreateOrgRepo bool              `json:"can_create_org_repo"`
+	// directories and files of the code the members are limited to reading, empty for no restriction
+	CodePaths []string `json:"code_paths"`
+	// who is notified when the team is mentioned, defaults to all
+	// enum: all,leads,webhook
+	MentionMode string `json:"mention_mode" binding:"OmitEmpty;In(all,leads,webhook)"`
+	// type of the webhook mentions are posted to, required if the mention mode is webhook
+	// enum: gitea,slack,discord,msteams
+	MentionWebhookType string `json:"mention_webhook_type" binding:"OmitEmpty;In(gitea,slack,discord,msteams)"`
+	// url of the webhook mentions are posted to, required if the mention mode is webhook
+	MentionWebhookURL string `json:"mention_webhook_url" binding:"OmitEmpty;ValidUrl"`
+	// number of mentions per hour which notify the team, 0 for no limit
+	MentionRateLimit int `json:"mention_rate_limit"`
+	// number of members who are requested to review in round-robin order when the team is, skipping busy members. 0 to request the whole team
+	ReviewAssignCount int `json:"review_assign_count"`
 }
 
 // EditTeamOption options for editing a team
@@ -51,4 +77,26 @@ type EditTeamOption struct {
 	// example: {"repo.code":"read","repo.issues":"write","repo.ext_issues":"none","repo.wiki":"admin","repo.pulls":"owner","repo.releases":"none","repo.projects":"none","repo.ext_wiki":"none"}
 	UnitsMap         map[string]string `json:"units_map"`
 	CanCreateOrgRepo *bool             `json:"can_create_org_repo"`
+	// directories and files of the code the members are limited to reading, an empty list removes the restriction
+	CodePaths *[]string `json:"code_paths"`
+	// enum: all,leads,webhook
+	MentionMode        *string `json:"mention_mode" binding:"OmitEmpty;In(all,leads,webhook)"`
+	MentionWebhookType *string `json:"mention_webhook_type" binding:"OmitEmpty;In(gitea,slack,discord,msteams)"`
+	MentionWebhookURL  *string `json:"mention_webhook_url" binding:"OmitEmpty;ValidUrl"`
+	MentionRateLimit   *int    `json:"mention_rate_limit"`
+	ReviewAssignCount  *int    `json:"review_assign_count"`
+}
+
+// TeamMentionPayload represents a mention of a team posted to its mention webhook of type gitea
+type TeamMentionPayload struct {
+	Organization *Organization `json:"organization"`
+	Team         *Team         `json:"team"`
+	Repository   string        `json:"repository"`
+	Index        int64         `json:"number"`
+	Title        string        `json:"title"`
+	IsPull       bool          `json:"is_pull"`
+	// url of the comment mentioning the team, or of the issue or pull request if it was mentioned in its description
+	HTMLURL string `json:"html_url"`
+	Sender  string `json:"sender"`
+	Body    string `json:"body"`
 }
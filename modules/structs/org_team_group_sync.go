@@ -0,0 +1,51 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// TeamGroupMapping represents an external group mapped to a team
+type TeamGroupMapping struct {
+	// name of the authentication source which reports the group, "api" or empty for the groups whose members are pushed through the API
+	Source string `json:"source"`
+	Group  string `json:"group"`
+}
+
+// TeamGroupSync represents how the members of a team are synced from external groups
+type TeamGroupSync struct {
+	// decides the membership if the sources of the mapped groups disagree: any source suffices or all sources are required
+	// enum: any,all
+	Policy string `json:"policy"`
+	// whether members which aren't in the mapped groups are removed, the members of the owners team are never removed
+	RemoveMembers bool                `json:"remove_members"`
+	Mappings      []*TeamGroupMapping `json:"mappings"`
+}
+
+// EditTeamGroupSyncOption options for replacing the group sync settings of a team
+type EditTeamGroupSyncOption struct {
+	// enum: any,all
+	Policy        string              `json:"policy" binding:"OmitEmpty;In(any,all)"`
+	RemoveMembers bool                `json:"remove_members"`
+	Mappings      []*TeamGroupMapping `json:"mappings"`
+}
+
+// TeamGroupSyncLog represents a change of the membership of a team made by the group sync
+type TeamGroupSyncLog struct {
+	ID     int64 `json:"id"`
+	TeamID int64 `json:"team_id"`
+	UserID int64 `json:"user_id"`
+	// the user, absent if it was deleted
+	User *User `json:"user,omitempty"`
+	// enum: add,remove
+	Action string `json:"action"`
+	Reason string `json:"reason"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// ExternalGroupMembersOption options for replacing the members of an external group
+type ExternalGroupMembersOption struct {
+	// names of the users in the group, unknown users are rejected
+	Usernames []string `json:"usernames"`
+}
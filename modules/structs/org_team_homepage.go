@@ -0,0 +1,47 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// TeamHomepage represents the landing page maintained by a team
+type TeamHomepage struct {
+	TeamID int64 `json:"team_id"`
+	// the README of the team in markdown
+	Readme      string        `json:"readme"`
+	PinnedRepos []*Repository `json:"pinned_repos"`
+	// swagger:strfmt date-time
+	Updated *time.Time `json:"updated_at,omitempty"`
+}
+
+// EditTeamHomepageOption options for editing the homepage of a team
+type EditTeamHomepageOption struct {
+	Readme *string `json:"readme"`
+	// full names (owner/name) of the repositories of the organization to pin, in their order
+	PinnedRepos *[]string `json:"pinned_repos"`
+}
+
+// TeamActivityCount is the number of actions of a group of a team activity summary
+type TeamActivityCount struct {
+	// the type of the actions, the full name of the repository or the name of the member
+	Key   string `json:"key"`
+	Count int64  `json:"count"`
+}
+
+// TeamActivitySummary aggregates the recent activity in the repositories of a team
+type TeamActivitySummary struct {
+	// swagger:strfmt date-time
+	Since    time.Time            `json:"since"`
+	Total    int64                `json:"total"`
+	ByType   []*TeamActivityCount `json:"by_type"`
+	ByRepo   []*TeamActivityCount `json:"by_repo"`
+	ByMember []*TeamActivityCount `json:"by_member"`
+}
+
+// TeamMilestone is a milestone with a deadline in the calendar of a team
+type TeamMilestone struct {
+	Milestone
+	// full name (owner/name) of the repository of the milestone
+	Repository string `json:"repository"`
+}
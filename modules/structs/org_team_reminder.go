@@ -0,0 +1,99 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// TeamReminder represents a scheduled digest posted to a chat webhook for a team
+type TeamReminder struct {
+	ID     int64 `json:"id"`
+	TeamID int64 `json:"team_id"`
+	// standard 5 field cron expression, e.g. "0 9 * * 1-5", evaluated in the server time zone
+	Schedule string `json:"schedule"`
+	// enum: gitea,slack,discord,msteams
+	WebhookType           string `json:"webhook_type"`
+	WebhookURL            string `json:"webhook_url"`
+	Active                bool   `json:"active"`
+	IncludeReviewRequests bool   `json:"include_review_requests"`
+	IncludeStalePulls     bool   `json:"include_stale_pulls"`
+	StaleDays             int    `json:"stale_days"`
+	IncludeFailingChecks  bool   `json:"include_failing_checks"`
+	IgnoreWorkInProgress  bool   `json:"ignore_work_in_progress"`
+	// swagger:strfmt date-time
+	LastRun *time.Time `json:"last_run,omitempty"`
+	// swagger:strfmt date-time
+	NextRun time.Time `json:"next_run"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateTeamReminderOption options for creating a team reminder
+type CreateTeamReminderOption struct {
+	// required: true
+	Schedule string `json:"schedule" binding:"Required;MaxSize(100)"`
+	// required: true
+	// enum: gitea,slack,discord,msteams
+	WebhookType string `json:"webhook_type" binding:"Required;In(gitea,slack,discord,msteams)"`
+	// required: true
+	WebhookURL            string `json:"webhook_url" binding:"Required;ValidUrl"`
+	Active                *bool  `json:"active"`
+	IncludeReviewRequests *bool  `json:"include_review_requests"`
+	IncludeStalePulls     *bool  `json:"include_stale_pulls"`
+	// defaults to 7
+	StaleDays            int   `json:"stale_days"`
+	IncludeFailingChecks *bool `json:"include_failing_checks"`
+	IgnoreWorkInProgress bool  `json:"ignore_work_in_progress"`
+}
+
+// EditTeamReminderOption options for editing a team reminder
+type EditTeamReminderOption struct {
+	Schedule *string `json:"schedule" binding:"OmitEmpty;MaxSize(100)"`
+	// enum: gitea,slack,discord,msteams
+	WebhookType           *string `json:"webhook_type" binding:"OmitEmpty;In(gitea,slack,discord,msteams)"`
+	WebhookURL            *string `json:"webhook_url" binding:"OmitEmpty;ValidUrl"`
+	Active                *bool   `json:"active"`
+	IncludeReviewRequests *bool   `json:"include_review_requests"`
+	IncludeStalePulls     *bool   `json:"include_stale_pulls"`
+	StaleDays             *int    `json:"stale_days"`
+	IncludeFailingChecks  *bool   `json:"include_failing_checks"`
+	IgnoreWorkInProgress  *bool   `json:"ignore_work_in_progress"`
+}
+
+// TeamReminderPull is a pull request listed in a team reminder digest
+type TeamReminderPull struct {
+	Repository string `json:"repository"`
+	Index      int64  `json:"number"`
+	Title      string `json:"title"`
+	HTMLURL    string `json:"html_url"`
+	Poster     string `json:"poster"`
+	// the team members whose review is requested
+	Reviewers []string `json:"reviewers,omitempty"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// TeamReminderFailingBranch is a default branch with failing checks listed in a team reminder digest
+type TeamReminderFailingBranch struct {
+	Repository string `json:"repository"`
+	Branch     string `json:"branch"`
+	CommitID   string `json:"commit_id"`
+	State      string `json:"state"`
+	TargetURL  string `json:"target_url"`
+}
+
+// TeamReminderPayload represents the digest posted by a team reminder to a webhook of type gitea
+type TeamReminderPayload struct {
+	Organization   *Organization                `json:"organization"`
+	Team           *Team                        `json:"team"`
+	ReviewRequests []*TeamReminderPull          `json:"review_requests"`
+	StalePulls     []*TeamReminderPull          `json:"stale_pulls"`
+	FailingChecks  []*TeamReminderFailingBranch `json:"failing_checks"`
+}
+
+// IsEmpty returns true if the digest has nothing to report
+func (p *TeamReminderPayload) IsEmpty() bool {
+	return len(p.ReviewRequests) == 0 && len(p.StalePulls) == 0 && len(p.FailingChecks) == 0
+}
@@ -16,10 +16,53 @@ type Package struct {
 	Type       string      `json:"type"`
 	Name       string      `json:"name"`
 	Version    string      `json:"version"`
+	// short description of the package taken from its metadata
+	Description string `json:"description,omitempty"`
+	// readme or long description of the package taken from its metadata, only returned for a single package
+	Readme string `json:"readme,omitempty"`
+	// deprecated versions are kept but should no longer be used
+	IsDeprecated       bool   `json:"is_deprecated"`
+	DeprecationMessage string `json:"deprecation_message,omitempty"`
 	// swagger:strfmt date-time
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// PackageVersionIdentifier identifies a version of a package
+type PackageVersionIdentifier struct {
+	// required: true
+	Owner string `json:"owner" binding:"Required"`
+	// required: true
+	Type string `json:"type" binding:"Required"`
+	// required: true
+	Name string `json:"name" binding:"Required"`
+	// required: true
+	Version string `json:"version" binding:"Required"`
+}
+
+// BatchPackageMetadataOption options to get the metadata of many package versions at once
+type BatchPackageMetadataOption struct {
+	// required: true
+	Versions []*PackageVersionIdentifier `json:"versions" binding:"Required"`
+}
+
+// PackageMetadata represents the metadata of a package version requested in a batch
+type PackageMetadata struct {
+	PackageVersionIdentifier
+	// whether the version exists and is visible, the other fields are only set if it is
+	Found   bool     `json:"found"`
+	Package *Package `json:"package,omitempty"`
+	// metadata extracted from the uploaded files, its fields depend on the package type
+	Metadata interface{} `json:"metadata,omitempty"`
+	// properties of the version set by the package registry
+	Properties map[string]string `json:"properties,omitempty"`
+}
+
+// DeprecatePackageOption options to deprecate a package version
+type DeprecatePackageOption struct {
+	// message shown by the package managers which support deprecations
+	Message string `json:"message" binding:"MaxSize(1000)"`
+}
+
 // PackageFile represents a package file
 type PackageFile struct {
 	ID         int64 `json:"id"`
@@ -30,3 +73,58 @@ type PackageFile struct {
 	HashSHA256 string `json:"sha256"`
 	HashSHA512 string `json:"sha512"`
 }
+
+// GoModuleVerification represents the verification of a stored Go module version against the checksum database
+type GoModuleVerification struct {
+	Owner   string `json:"owner"`
+	Module  string `json:"module"`
+	Version string `json:"version"`
+	// verified, mismatch, unknown if the checksum database doesn't know the module, or private if it wasn't looked up
+	Status     string `json:"status"`
+	LocalHash  string `json:"local_hash,omitempty"`
+	PublicHash string `json:"public_hash,omitempty"`
+}
+
+// PackageAdvisory represents a security advisory published by the owner of packages, it is served to
+// package managers auditing their dependencies
+type PackageAdvisory struct {
+	ID          int64  `json:"id"`
+	Type        string `json:"type"`
+	PackageName string `json:"package_name"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	// info, low, moderate, high or critical
+	Severity string `json:"severity"`
+	// range of affected versions like ">=1.0.0 <1.2.3 || >=2.0.0 <2.0.4"
+	VulnerableVersions string `json:"vulnerable_versions"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreatePackageAdvisoryOption options to publish a package advisory
+type CreatePackageAdvisoryOption struct {
+	// required: true
+	// enum: npm
+	Type string `json:"type" binding:"Required"`
+	// required: true
+	PackageName string `json:"package_name" binding:"Required"`
+	// required: true
+	Title string `json:"title" binding:"Required;MaxSize(255)"`
+	URL   string `json:"url" binding:"OmitEmpty;ValidUrl"`
+	// required: true
+	// enum: info,low,moderate,high,critical
+	Severity string `json:"severity" binding:"Required"`
+	// required: true
+	VulnerableVersions string `json:"vulnerable_versions" binding:"Required"`
+}
+
+// EditPackageAdvisoryOption options to edit a package advisory
+type EditPackageAdvisoryOption struct {
+	Title *string `json:"title" binding:"MaxSize(255)"`
+	URL   *string `json:"url" binding:"OmitEmpty;ValidUrl"`
+	// enum: info,low,moderate,high,critical
+	Severity           *string `json:"severity"`
+	VulnerableVersions *string `json:"vulnerable_versions"`
+}
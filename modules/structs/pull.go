@@ -100,6 +100,7 @@ type ChangedFile struct {
 	Filename         string `json:"filename"`
 	PreviousFilename string `json:"previous_filename,omitempty"`
 	Status           string `json:"status"`
+	Similarity       int    `json:"similarity,omitempty"`
 	Additions        int    `json:"additions"`
 	Deletions        int    `json:"deletions"`
 	Changes          int    `json:"changes"`
@@ -107,3 +108,15 @@ type ChangedFile struct {
 	ContentsURL      string `json:"contents_url,omitempty"`
 	RawURL           string `json:"raw_url,omitempty"`
 }
+
+// MergeQueueEntry represents a pull request in the merge queue of its base branch
+type MergeQueueEntry struct {
+	Number     int64  `json:"number"`
+	Title      string `json:"title"`
+	MergeStyle string `json:"merge_style"`
+	QueuedBy   *User  `json:"queued_by"`
+	// the branch of the merge train testing the pull request, empty while it waits for the next train
+	MergeTrainBranch string `json:"merge_train_branch"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
@@ -37,6 +37,8 @@ type PullReview struct {
 	Official          bool            `json:"official"`
 	Dismissed         bool            `json:"dismissed"`
 	CodeCommentsCount int             `json:"comments_count"`
+	// ids of the items of the review checklist of the repository ticked by the review
+	ChecklistItems []int64 `json:"checklist_items"`
 	// swagger:strfmt date-time
 	Submitted time.Time `json:"submitted_at"`
 	// swagger:strfmt date-time
@@ -76,6 +78,8 @@ type CreatePullReviewOptions struct {
 	Body     string                    `json:"body"`
 	CommitID string                    `json:"commit_id"`
 	Comments []CreatePullReviewComment `json:"comments"`
+	// ids of the items of the review checklist of the repository ticked by the review
+	ChecklistItems []int64 `json:"checklist_items"`
 }
 
 // CreatePullReviewComment represent a review comment for creation api
@@ -93,6 +97,8 @@ type CreatePullReviewComment struct {
 type SubmitPullReviewOptions struct {
 	Event ReviewStateType `json:"event"`
 	Body  string          `json:"body"`
+	// ids of the items of the review checklist of the repository ticked by the review
+	ChecklistItems []int64 `json:"checklist_items"`
 }
 
 // DismissPullReviewOptions are options to dismiss a pull review
@@ -106,3 +112,30 @@ type PullReviewRequestOptions struct {
 	Reviewers     []string `json:"reviewers"`
 	TeamReviewers []string `json:"team_reviewers"`
 }
+
+// ReviewChecklistItem represents an item of the review checklist of a repository, which reviewers tick when reviewing a pull request
+type ReviewChecklistItem struct {
+	ID          int64  `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// whether the item must be ticked for an approval to count if the branch protection requires the review checklist
+	Required bool `json:"required"`
+	Sort     int  `json:"sort"`
+}
+
+// CreateReviewChecklistItemOption options for adding an item to the review checklist of a repository
+type CreateReviewChecklistItemOption struct {
+	// required: true
+	Name        string `json:"name" binding:"Required;MaxSize(255)"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+	Sort        int    `json:"sort"`
+}
+
+// EditReviewChecklistItemOption options for editing an item of the review checklist of a repository
+type EditReviewChecklistItemOption struct {
+	Name        *string `json:"name" binding:"MaxSize(255)"`
+	Description *string `json:"description"`
+	Required    *bool   `json:"required"`
+	Sort        *int    `json:"sort"`
+}
@@ -92,6 +92,7 @@ type Repository struct {
 	HasReleases                   bool             `json:"has_releases"`
 	HasPackages                   bool             `json:"has_packages"`
 	HasActions                    bool             `json:"has_actions"`
+	HasDiscussions                bool             `json:"has_discussions"`
 	IgnoreWhitespaceConflicts     bool             `json:"ignore_whitespace_conflicts"`
 	AllowMerge                    bool             `json:"allow_merge_commits"`
 	AllowRebase                   bool             `json:"allow_rebase"`
@@ -101,9 +102,15 @@ type Repository struct {
 	DefaultDeleteBranchAfterMerge bool             `json:"default_delete_branch_after_merge"`
 	DefaultMergeStyle             string           `json:"default_merge_style"`
 	DefaultAllowMaintainerEdit    bool             `json:"default_allow_maintainer_edit"`
+	DefaultMergeMessageTemplate   string           `json:"default_merge_message_template"`
+	DefaultSquashMessageTemplate  string           `json:"default_squash_message_template"`
+	MergeMessageTrailers          string           `json:"merge_message_trailers"`
+	MergeTrainBatchSize           int              `json:"merge_train_batch_size"`
+	ActionsApprovalPolicy         string           `json:"actions_approval_policy,omitempty"`
 	AvatarURL                     string           `json:"avatar_url"`
 	Internal                      bool             `json:"internal"`
 	MirrorInterval                string           `json:"mirror_interval"`
+	ObjectFormatName              string           `json:"object_format_name"`
 	// swagger:strfmt date-time
 	MirrorUpdated time.Time     `json:"mirror_updated,omitempty"`
 	RepoTransfer  *RepoTransfer `json:"repo_transfer"`
@@ -121,6 +128,8 @@ type CreateRepoOption struct {
 	Description string `json:"description" binding:"MaxSize(2048)"`
 	// Whether the repository is private
 	Private bool `json:"private"`
+	// Whether the repository is internal, it can be read by all signed-in users. Internal repositories are private.
+	Internal bool `json:"internal"`
 	// Label-Set to use
 	IssueLabels string `json:"issue_labels"`
 	// Whether the repository should be auto-initialized?
@@ -133,11 +142,18 @@ type CreateRepoOption struct {
 	License string `json:"license"`
 	// Readme of the repository to create
 	Readme string `json:"readme"`
+	// Comma-separated issue templates of the catalogs to add to .gitea/ISSUE_TEMPLATE
+	IssueTemplates string `json:"issue_templates"`
+	// Comma-separated workflow templates of the catalogs to add to .gitea/workflows
+	WorkflowTemplates string `json:"workflow_templates"`
 	// DefaultBranch of the repository (used when initializes and in template)
 	DefaultBranch string `json:"default_branch" binding:"GitRefName;MaxSize(100)"`
 	// TrustModel of the repository
 	// enum: default,collaborator,committer,collaboratorcommitter
 	TrustModel string `json:"trust_model"`
+	// ObjectFormatName of the underlying git repository, sha256 is only available if the server supports it
+	// enum: sha1,sha256
+	ObjectFormatName string `json:"object_format_name" binding:"MaxSize(6)"`
 }
 
 // EditRepoOption options when editing a repository's properties
@@ -154,6 +170,9 @@ type EditRepoOption struct {
 	// Note: you will get a 422 error if the organization restricts changing repository visibility to organization
 	// owners and a non-owner tries to change the value of private.
 	Private *bool `json:"private,omitempty"`
+	// either `true` to make the repository internal, it can be read by all signed-in users, or `false` to make it only
+	// visible to its members. Internal repositories are private, making a repository public clears this flag.
+	Internal *bool `json:"internal,omitempty"`
 	// either `true` to make this repository a template or `false` to make it a normal repository
 	Template *bool `json:"template,omitempty"`
 	// either `true` to enable issues for this repository or `false` to disable them.
@@ -178,6 +197,8 @@ type EditRepoOption struct {
 	HasPackages *bool `json:"has_packages,omitempty"`
 	// either `true` to enable actions unit, or `false` to disable them.
 	HasActions *bool `json:"has_actions,omitempty"`
+	// either `true` to enable discussions unit, or `false` to disable them.
+	HasDiscussions *bool `json:"has_discussions,omitempty"`
 	// either `true` to ignore whitespace for conflicts, or `false` to not ignore whitespace.
 	IgnoreWhitespaceConflicts *bool `json:"ignore_whitespace_conflicts,omitempty"`
 	// either `true` to allow merging pull requests with a merge commit, or `false` to prevent merging pull requests with merge commits.
@@ -200,6 +221,17 @@ type EditRepoOption struct {
 	DefaultMergeStyle *string `json:"default_merge_style,omitempty"`
 	// set to `true` to allow edits from maintainers by default
 	DefaultAllowMaintainerEdit *bool `json:"default_allow_maintainer_edit,omitempty"`
+	// template of the default message of merge commits, overriding the one in `.gitea/default_merge_message`. Set to an empty string to use it again.
+	DefaultMergeMessageTemplate *string `json:"default_merge_message_template,omitempty"`
+	// template of the default message of squash commits, overriding the one in `.gitea/default_merge_message`. Set to an empty string to use it again.
+	DefaultSquashMessageTemplate *string `json:"default_squash_message_template,omitempty"`
+	// template of the trailers added to the message of every merge and squash commit, one per line
+	MergeMessageTrailers *string `json:"merge_message_trailers,omitempty"`
+	// how many pull requests of a merge queue are tested together by a merge train, one at a time if it is 0 or 1
+	MergeTrainBatchSize *int `json:"merge_train_batch_size,omitempty"`
+	// which workflow runs of pull requests from forks wait for the approval of a maintainer: `first_run`, `first_contribution`
+	// or `all_forks`. Set to an empty string to use the default of the instance.
+	ActionsApprovalPolicy *string `json:"actions_approval_policy,omitempty"`
 	// set to `true` to archive this repository.
 	Archived *bool `json:"archived,omitempty"`
 	// set to a string like `8h30m0s` to set the mirror interval time
@@ -226,6 +258,8 @@ type GenerateRepoOption struct {
 	Description string `json:"description" binding:"MaxSize(2048)"`
 	// Whether the repository is private
 	Private bool `json:"private"`
+	// Whether the repository is internal, it can be read by all signed-in users. Internal repositories are private.
+	Internal bool `json:"internal"`
 	// include git content of default branch in template repo
 	GitContent bool `json:"git_content"`
 	// include topics in template repo
@@ -42,7 +42,10 @@ type BranchProtection struct {
 	BlockOnRejectedReviews        bool     `json:"block_on_rejected_reviews"`
 	BlockOnOfficialReviewRequests bool     `json:"block_on_official_review_requests"`
 	BlockOnOutdatedBranch         bool     `json:"block_on_outdated_branch"`
+	BlockOnCodeScanningAlerts     bool     `json:"block_on_code_scanning_alerts"`
+	MinDiffCoverage               int64    `json:"min_diff_coverage"`
 	DismissStaleApprovals         bool     `json:"dismiss_stale_approvals"`
+	RequireReviewChecklist        bool     `json:"require_review_checklist"`
 	RequireSignedCommits          bool     `json:"require_signed_commits"`
 	ProtectedFilePatterns         string   `json:"protected_file_patterns"`
 	UnprotectedFilePatterns       string   `json:"unprotected_file_patterns"`
@@ -74,7 +77,10 @@ type CreateBranchProtectionOption struct {
 	BlockOnRejectedReviews        bool     `json:"block_on_rejected_reviews"`
 	BlockOnOfficialReviewRequests bool     `json:"block_on_official_review_requests"`
 	BlockOnOutdatedBranch         bool     `json:"block_on_outdated_branch"`
+	BlockOnCodeScanningAlerts     bool     `json:"block_on_code_scanning_alerts"`
+	MinDiffCoverage               int64    `json:"min_diff_coverage"`
 	DismissStaleApprovals         bool     `json:"dismiss_stale_approvals"`
+	RequireReviewChecklist        bool     `json:"require_review_checklist"`
 	RequireSignedCommits          bool     `json:"require_signed_commits"`
 	ProtectedFilePatterns         string   `json:"protected_file_patterns"`
 	UnprotectedFilePatterns       string   `json:"unprotected_file_patterns"`
@@ -99,7 +105,10 @@ type EditBranchProtectionOption struct {
 	BlockOnRejectedReviews        *bool    `json:"block_on_rejected_reviews"`
 	BlockOnOfficialReviewRequests *bool    `json:"block_on_official_review_requests"`
 	BlockOnOutdatedBranch         *bool    `json:"block_on_outdated_branch"`
+	BlockOnCodeScanningAlerts     *bool    `json:"block_on_code_scanning_alerts"`
+	MinDiffCoverage               *int64   `json:"min_diff_coverage"`
 	DismissStaleApprovals         *bool    `json:"dismiss_stale_approvals"`
+	RequireReviewChecklist        *bool    `json:"require_review_checklist"`
 	RequireSignedCommits          *bool    `json:"require_signed_commits"`
 	ProtectedFilePatterns         *string  `json:"protected_file_patterns"`
 	UnprotectedFilePatterns       *string  `json:"unprotected_file_patterns"`
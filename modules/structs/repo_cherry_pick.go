@@ -0,0 +1,39 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// CherryPickOptions options for cherry-picking or reverting a range of commits
+// Note: `author` and `committer` are optional (if only one is given, it will be used for the other, otherwise the authenticated user will be used).
+// Cherry-picked commits keep their original author if no author is given.
+type CherryPickOptions struct {
+	// branch (optional) to apply the commits to. if not given, the default branch is used
+	BranchName string `json:"branch" binding:"GitRefName;MaxSize(100)"`
+	// new_branch (optional) will make a new branch from `branch` for the picked commits
+	NewBranchName string `json:"new_branch" binding:"GitRefName;MaxSize(100)"`
+	// exclusive start of the commit range, if not given only `to` is picked
+	From string `json:"from"`
+	// inclusive end of the commit range
+	// required: true
+	To string `json:"to" binding:"Required"`
+	// parent number (starting at 1) merge commits are picked relative to, only the first-parent history of the range is picked if set
+	Mainline int `json:"mainline"`
+	// revert the commits instead of cherry-picking them
+	Revert    bool              `json:"revert"`
+	Author    Identity          `json:"author"`
+	Committer Identity          `json:"committer"`
+	Dates     CommitDateOptions `json:"dates"`
+	// Add a Signed-off-by trailer by the committer at the end of the commit log message.
+	Signoff bool `json:"signoff"`
+	// open a pull request from `new_branch` into `branch` with the result
+	CreatePullRequest bool `json:"create_pull_request"`
+	// title of the pull request, defaults to a summary of the picked range
+	PullRequestTitle string `json:"pull_request_title"`
+	PullRequestBody  string `json:"pull_request_body"`
+}
+
+// CherryPickResponse contains the commits created by a cherry-pick or revert
+type CherryPickResponse struct {
+	Commits     []*FileCommitResponse `json:"commits"`
+	PullRequest *PullRequest          `json:"pull_request,omitempty"`
+}
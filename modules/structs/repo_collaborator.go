@@ -3,9 +3,14 @@
 
 package structs
 
+import "time"
+
 // AddCollaboratorOption options when adding a user as a collaborator of a repository
 type AddCollaboratorOption struct {
 	Permission *string `json:"permission"`
+	// the collaborator is removed from the repository at this time, leave empty to never expire
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires"`
 }
 
 // RepoCollaboratorPermission to get repository permission for a collaborator
@@ -14,3 +19,17 @@ type RepoCollaboratorPermission struct {
 	RoleName   string `json:"role_name"`
 	User       *User  `json:"user"`
 }
+
+// OutsideCollaborator represents a collaborator on repositories of an organization who isn't a member of it
+type OutsideCollaborator struct {
+	User         *User                            `json:"user"`
+	Repositories []*OutsideCollaboratorRepository `json:"repositories"`
+}
+
+// OutsideCollaboratorRepository represents a repository an outside collaborator has access to
+type OutsideCollaboratorRepository struct {
+	FullName   string `json:"full_name"`
+	Permission string `json:"permission"`
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires,omitempty"`
+}
@@ -70,3 +70,27 @@ type CommitDateOptions struct {
 type CommitAffectedFiles struct {
 	Filename string `json:"filename"`
 }
+
+// CommitGraphNode is a commit of a commit graph with its position in the graph
+type CommitGraphNode struct {
+	SHA     string      `json:"sha"`
+	Parents []string    `json:"parents"`
+	Refs    []string    `json:"refs"`
+	Author  *CommitUser `json:"author"`
+	Subject string      `json:"subject"`
+	// Lane is the column of the commit in the graph
+	Lane int `json:"lane"`
+	// ParentLanes are the lanes the edges to the parents continue in, in the order of the parents
+	ParentLanes []int `json:"parent_lanes"`
+	// MergedLanes are the other lanes whose edges end at this commit
+	MergedLanes []int `json:"merged_lanes"`
+	// ActiveLanes are the lanes with edges passing below this commit
+	ActiveLanes []int `json:"active_lanes"`
+}
+
+// CommitGraph is a page of a commit graph laid out in lanes, newest commits first
+type CommitGraph struct {
+	// ActiveLanes are the lanes with edges passing above the first commit of the page
+	ActiveLanes []int              `json:"active_lanes"`
+	Commits     []*CommitGraphNode `json:"commits"`
+}
@@ -0,0 +1,32 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// Compare represents a comparison between two commits
+type Compare struct {
+	BaseCommitID string `json:"base_commit_id"`
+	HeadCommitID string `json:"head_commit_id"`
+	// the common ancestor of the base and head commits, the base commit if they are unrelated
+	MergeBaseCommitID string `json:"merge_base_commit_id"`
+	// whether the files were compared with the base commit (base..head) instead of the merge base (base...head)
+	DirectComparison bool `json:"direct_comparison"`
+	// number of commits in head which are not in base
+	AheadBy int `json:"ahead_by"`
+	// number of commits in base which are not in head
+	BehindBy     int            `json:"behind_by"`
+	TotalCommits int            `json:"total_commits"`
+	Commits      []*Commit      `json:"commits"`
+	TotalFiles   int            `json:"total_files"`
+	Files        []*ChangedFile `json:"files"`
+	// commits of head which introduce the same changes as a commit of base, like backported commits,
+	// only returned if requested with the equivalent parameter
+	EquivalentCommits []*EquivalentCommit `json:"equivalent_commits,omitempty"`
+}
+
+// EquivalentCommit represents a commit of the head of a comparison which is already present in the base with the same patch
+type EquivalentCommit struct {
+	SHA string `json:"sha"`
+	// the commit of base which introduces the same changes
+	BaseSHA string `json:"base_sha"`
+}
@@ -0,0 +1,41 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// DownloadLink is a secret, expiring link to download an attachment or an archive of a repository without authentication
+type DownloadLink struct {
+	ID int64 `json:"id"`
+	// URL of the link, only returned when the link is created
+	URL string `json:"url,omitempty"`
+	// AttachmentID is the release asset or attachment the link serves
+	AttachmentID int64 `json:"attachment_id,omitempty"`
+	// Archive is the name of the archive the link serves
+	Archive string `json:"archive,omitempty"`
+	// CommitID is the commit the archive is created from
+	CommitID      string `json:"commit_id,omitempty"`
+	Creator       *User  `json:"creator"`
+	DownloadCount int64  `json:"download_count"`
+	Active        bool   `json:"active"`
+	// swagger:strfmt date-time
+	Expires time.Time `json:"expires_at"`
+	Revoker *User     `json:"revoker,omitempty"`
+	// swagger:strfmt date-time
+	Revoked *time.Time `json:"revoked_at,omitempty"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateDownloadLinkOption options to create a download link of either an attachment or an archive
+type CreateDownloadLinkOption struct {
+	// id of a release asset or attachment of the repository
+	AttachmentID int64 `json:"attachment_id"`
+	// archive of a branch, tag or commit like `v1.0.zip`, `main.tar.gz` or `<sha>.bundle`,
+	// the link serves the commit the ref points to when the link is created
+	Archive string `json:"archive"`
+	// defaults to 7 days from now, limited by the configured maximum
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires_at"`
+}
@@ -108,6 +108,13 @@ type ContentsResponse struct {
 	Links           *FileLinksResponse `json:"_links"`
 }
 
+// RepoReadme represents the README of a directory of a repository
+type RepoReadme struct {
+	Contents *ContentsResponse `json:"contents"`
+	// the README rendered to HTML, only populated if requested and the README is a markup file
+	HTML string `json:"html"`
+}
+
 // FileCommitResponse contains information generated from a Git commit for a repo's file.
 type FileCommitResponse struct {
 	CommitMeta
@@ -16,11 +16,26 @@ type DeployKey struct {
 	Title       string `json:"title"`
 	Fingerprint string `json:"fingerprint"`
 	// swagger:strfmt date-time
-	Created    time.Time   `json:"created_at"`
-	ReadOnly   bool        `json:"read_only"`
+	Created  time.Time `json:"created_at"`
+	ReadOnly bool      `json:"read_only"`
+	// glob patterns of the branches and tags the key may push to, all if empty
+	RefPatterns []string `json:"ref_patterns"`
+	// the key may only download archives with git archive --remote
+	ArchiveOnly bool `json:"archive_only"`
+	// swagger:strfmt date-time
+	LastUsed   *time.Time  `json:"last_used_at,omitempty"`
 	Repository *Repository `json:"repository,omitempty"`
 }
 
+// DeployKeyUsage an access to a repository with a deploy key
+type DeployKeyUsage struct {
+	Permission    string `json:"permission"`
+	Command       string `json:"command"`
+	RemoteAddress string `json:"remote_address"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
 // CreateKeyOption options when creating a key
 type CreateKeyOption struct {
 	// Title of the key to add
@@ -37,4 +52,17 @@ type CreateKeyOption struct {
 	//
 	// required: false
 	ReadOnly bool `json:"read_only"`
+	// Glob patterns of the branches and tags a deploy key may push to, all if empty. Only used for deploy keys
+	//
+	// required: false
+	RefPatterns []string `json:"ref_patterns"`
+	// Describe if a deploy key may only download archives with git archive --remote, such a key is read-only. Only used for deploy keys
+	//
+	// required: false
+	ArchiveOnly bool `json:"archive_only"`
+	// Date until which the key is used to sign commits, its owner is reminded to rotate it before. Only used for user keys
+	//
+	// required: false
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires_at"`
 }
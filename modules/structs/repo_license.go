@@ -0,0 +1,37 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// RepoLicense represents a license detected in a repository or declared by one of its dependencies
+type RepoLicense struct {
+	// SPDX identifier of the license, or SPDX license expression declared by a dependency
+	License    string  `json:"license"`
+	Path       string  `json:"path"`
+	CommitID   string  `json:"commit_id"`
+	Confidence float64 `json:"confidence"`
+	// name and version of the dependency declaring the license in the lock file at `path`, empty for the licenses of the repository itself
+	Dependency string `json:"dependency,omitempty"`
+}
+
+// LicensePolicy represents the license policy of an organization
+type LicensePolicy struct {
+	Allowed   []string `json:"allowed"`
+	Forbidden []string `json:"forbidden"`
+	// enum: alert,block
+	Action string `json:"action"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// EditLicensePolicyOption options for setting the license policy of an organization
+type EditLicensePolicyOption struct {
+	// SPDX identifiers of the licenses which may be used, empty to allow all licenses which are not forbidden
+	Allowed []string `json:"allowed"`
+	// SPDX identifiers of the licenses which must not be used
+	Forbidden []string `json:"forbidden"`
+	// enum: alert,block
+	Action string `json:"action" binding:"Required;In(alert,block)"`
+}
@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// RepoMaintenance represents the object statistics and last maintenance run of a repository
+type RepoMaintenance struct {
+	RepoID   int64  `json:"repo_id"`
+	FullName string `json:"full_name"`
+	// number of loose objects
+	LooseObjects int64 `json:"loose_objects"`
+	// number of packs
+	Packs int64 `json:"packs"`
+	// size of the packs in KiB
+	PackSize int64 `json:"pack_size"`
+	// maintenance tasks run last time, e.g. gc, repack, commit-graph or multi-pack-index
+	LastTasks []string `json:"last_tasks"`
+	LastError string   `json:"last_error"`
+	// duration of the last run in milliseconds
+	LastDuration int64 `json:"last_duration"`
+	// swagger:strfmt date-time
+	LastChecked time.Time `json:"last_checked"`
+	// swagger:strfmt date-time
+	LastRun *time.Time `json:"last_run"`
+}
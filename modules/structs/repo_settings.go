@@ -0,0 +1,31 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// RepoSettings is a declarative document of all settings of a repository.
+// A section which is null is left unchanged when the document is applied.
+type RepoSettings struct {
+	// properties and merge options, the name, description, website, archived state and mirror settings aren't part of it
+	Repository        *EditRepoOption                 `json:"repository,omitempty"`
+	BranchProtections []*CreateBranchProtectionOption `json:"branch_protections"`
+	// webhooks are identified by their URL, their secrets and client keys aren't exported
+	Webhooks []*CreateHookOption  `json:"webhooks"`
+	Labels   []*CreateLabelOption `json:"labels"`
+}
+
+// RepoSettingsDifference is a setting of a repository which differs from a template
+type RepoSettingsDifference struct {
+	// path of the setting, items of lists are identified by their name or URL in brackets, e.g. labels[bug].color
+	Path string `json:"path"`
+	// value in the template, null if the repository has an item which isn't in the template
+	Expected any `json:"expected"`
+	// value of the repository, null if an item of the template is missing
+	Actual any `json:"actual"`
+}
+
+// RepoSettingsDrift lists the settings of a repository which differ from a template
+type RepoSettingsDrift struct {
+	Repository  string                    `json:"repository"`
+	Differences []*RepoSettingsDifference `json:"differences"`
+}
@@ -0,0 +1,77 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// VendorSync represents an external source whose content is imported into a directory of a branch on a schedule
+type VendorSync struct {
+	ID     int64  `json:"id"`
+	Branch string `json:"branch"`
+	// directory of the branch the content is imported into, empty for the root of the branch
+	TargetPath string `json:"target_path"`
+	// enum: http_tarball,s3
+	SourceType string `json:"source_type"`
+	// URL of the tarball, or endpoint of the object storage
+	SourceURL       string `json:"source_url"`
+	Bucket          string `json:"bucket"`
+	Prefix          string `json:"prefix"`
+	AccessKeyID     string `json:"access_key_id"`
+	StripComponents int    `json:"strip_components"`
+	Interval        string `json:"interval"`
+	// status of the last import
+	// enum: none,success,unchanged,failed
+	LastStatus  string `json:"last_status"`
+	LastMessage string `json:"last_message"`
+	// swagger:strfmt date-time
+	LastSync *time.Time `json:"last_sync"`
+	// sha256 digest of the last imported content
+	LastDigest string `json:"last_digest"`
+	// version of the last imported content reported by the source, the ETag of a tarball
+	LastVersion  string `json:"last_version"`
+	LastCommitID string `json:"last_commit_id"`
+	// swagger:strfmt date-time
+	NextSync time.Time `json:"next_sync"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateVendorSyncOption options for importing the content of an external source into a branch on a schedule
+type CreateVendorSyncOption struct {
+	// required: true
+	Branch string `json:"branch" binding:"Required"`
+	// directory of the branch the content is imported into, defaults to the root of the branch
+	TargetPath string `json:"target_path"`
+	// required: true
+	// enum: http_tarball,s3
+	SourceType string `json:"source_type" binding:"Required"`
+	// URL of the tarball, or endpoint of the object storage
+	// required: true
+	SourceURL string `json:"source_url" binding:"Required"`
+	// bucket of the object storage, required for s3 sources
+	Bucket string `json:"bucket"`
+	// prefix of the imported objects of the bucket
+	Prefix          string `json:"prefix"`
+	AccessKeyID     string `json:"access_key_id"`
+	SecretAccessKey string `json:"secret_access_key"`
+	// number of leading path components removed from the imported files
+	StripComponents int `json:"strip_components"`
+	// interval between imports as a duration, at least 10m, defaults to 24h
+	Interval string `json:"interval"`
+}
+
+// EditVendorSyncOption options for editing a vendor sync
+type EditVendorSyncOption struct {
+	Branch     *string `json:"branch"`
+	TargetPath *string `json:"target_path"`
+	// enum: http_tarball,s3
+	SourceType      *string `json:"source_type"`
+	SourceURL       *string `json:"source_url"`
+	Bucket          *string `json:"bucket"`
+	Prefix          *string `json:"prefix"`
+	AccessKeyID     *string `json:"access_key_id"`
+	SecretAccessKey *string `json:"secret_access_key"`
+	StripComponents *int    `json:"strip_components"`
+	Interval        *string `json:"interval"`
+}
@@ -11,6 +11,8 @@ type GeneralRepoSettings struct {
 	StarsDisabled        bool `json:"stars_disabled"`
 	TimeTrackingDisabled bool `json:"time_tracking_disabled"`
 	LFSDisabled          bool `json:"lfs_disabled"`
+	// the object formats repositories can be created with
+	ObjectFormats []string `json:"object_formats"`
 }
 
 // GeneralUISettings contains global ui settings exposed by API
@@ -40,3 +40,70 @@ type CreateStatusOption struct {
 	Description string            `json:"description"`
 	Context     string            `json:"context"`
 }
+
+// FlakyCheck marks the commit status contexts matching a pattern as flaky-tolerant
+type FlakyCheck struct {
+	ID int64 `json:"id"`
+	// glob pattern of the commit status contexts
+	ContextPattern string `json:"context_pattern"`
+	// number of times a failing Actions job of the contexts is re-dispatched automatically
+	MaxRetries int `json:"max_retries"`
+	// if set with attempts, the contexts pass if they pass in this many of the first attempts
+	RequiredPasses int `json:"required_passes"`
+	Attempts       int `json:"attempts"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateFlakyCheckOption options for creating a flaky check rule
+type CreateFlakyCheckOption struct {
+	// required: true
+	ContextPattern string `json:"context_pattern" binding:"Required"`
+	MaxRetries     int    `json:"max_retries"`
+	RequiredPasses int    `json:"required_passes"`
+	Attempts       int    `json:"attempts"`
+}
+
+// EditFlakyCheckOption options for editing a flaky check rule
+type EditFlakyCheckOption struct {
+	ContextPattern *string `json:"context_pattern"`
+	MaxRetries     *int    `json:"max_retries"`
+	RequiredPasses *int    `json:"required_passes"`
+	Attempts       *int    `json:"attempts"`
+}
+
+// CommitStatusAttempt an attempt of a flaky-tolerant commit status context on a commit
+type CommitStatusAttempt struct {
+	Context string `json:"context"`
+	Attempt int    `json:"attempt"`
+	// empty while the attempt is running
+	State CommitStatusState `json:"status"`
+	// what started the attempt: initial, auto or manual
+	Reason string `json:"reason"`
+	// the user who retried the context, empty for automatic retries
+	Doer  *User `json:"doer"`
+	JobID int64 `json:"job_id"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateCommitStatusAttemptOption holds the information needed to record a retry of a commit status context,
+// e.g. by an external CI
+type CreateCommitStatusAttemptOption struct {
+	// required: true
+	Context string `json:"context" binding:"Required"`
+}
+
+// FlakeRate the flakiness of a flaky-tolerant commit status context
+type FlakeRate struct {
+	Context string `json:"context"`
+	// number of commits the context finished on
+	Commits int `json:"commits"`
+	// number of commits the context both failed and passed on
+	FlakyCommits int     `json:"flaky_commits"`
+	Rate         float64 `json:"rate"`
+	Retries      int     `json:"retries"`
+	AutoRetries  int     `json:"auto_retries"`
+}
@@ -0,0 +1,18 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+// OrgStorageRegion represents the storage region the data of an organization is pinned to
+type OrgStorageRegion struct {
+	// empty if the data is in the default storages
+	Region string `json:"region"`
+	// the region the data is being moved to, absent if no move is running
+	MovingTo *string `json:"moving_to,omitempty"`
+}
+
+// EditOrgStorageRegionOption options for pinning an organization to a storage region
+type EditOrgStorageRegionOption struct {
+	// name of a configured storage region, empty to move the data back to the default storages
+	Region string `json:"region"`
+}
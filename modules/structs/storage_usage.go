@@ -0,0 +1,32 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// StorageUsage represents the storage used by an owner or a repository at the time of a snapshot, sizes are in bytes
+type StorageUsage struct {
+	Owner string `json:"owner"`
+	// full name of the repository, empty for the totals of the owner which include its packages
+	Repo           string `json:"repo,omitempty"`
+	GitSize        int64  `json:"git_size"`
+	LFSSize        int64  `json:"lfs_size"`
+	AttachmentSize int64  `json:"attachment_size"`
+	PackageSize    int64  `json:"package_size"`
+	// size of the artifacts and logs of Actions
+	ActionsSize int64 `json:"actions_size"`
+	TotalSize   int64 `json:"total_size"`
+	// swagger:strfmt date-time
+	Recorded time.Time `json:"recorded"`
+}
+
+// StorageUsageTrend represents the storage used by an owner or a repository over a period
+type StorageUsageTrend struct {
+	Owner string `json:"owner"`
+	Repo  string `json:"repo,omitempty"`
+	// snapshots of the period, oldest first
+	History []*StorageUsage `json:"history"`
+	// change of the total size in bytes between the first and the last snapshot of the period
+	TotalSizeChange int64 `json:"total_size_change"`
+}
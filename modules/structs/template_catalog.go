@@ -0,0 +1,24 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// TemplateCatalogItem is a template of a catalog which is offered when a repository is created or a file is added
+// with the web editor
+type TemplateCatalogItem struct {
+	// enum: gitignore,license,issue_template,workflow_template
+	Kind    string `json:"kind"`
+	Name    string `json:"name"`
+	Content string `json:"content"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// EditTemplateCatalogItemOption options to add a template to a catalog or to replace it
+type EditTemplateCatalogItemOption struct {
+	Content string `json:"content"`
+}
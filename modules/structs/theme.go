@@ -0,0 +1,60 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// Theme represents a theme managed by the site administrators
+type Theme struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+	// CSS variables, without the leading dashes, mapped to their values
+	Variables map[string]string `json:"variables"`
+	// empty if the theme keeps the default logo
+	LogoURL       string `json:"logo_url"`
+	StylesheetURL string `json:"stylesheet_url"`
+	// whether organizations can use the theme for their branding
+	OrgBranding bool `json:"org_branding"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// CreateThemeOption options for creating a theme
+type CreateThemeOption struct {
+	// lowercase letters, digits and dashes, it must differ from the themes of the config
+	// required: true
+	Name        string `json:"name" binding:"Required;MaxSize(30)"`
+	DisplayName string `json:"display_name" binding:"MaxSize(255)"`
+	// CSS variables, without the leading dashes, mapped to their values
+	Variables   map[string]string `json:"variables"`
+	OrgBranding bool              `json:"org_branding"`
+}
+
+// EditThemeOption options for editing a theme
+type EditThemeOption struct {
+	DisplayName *string `json:"display_name" binding:"MaxSize(255)"`
+	// replaces all variables of the theme if given
+	Variables   map[string]string `json:"variables"`
+	OrgBranding *bool             `json:"org_branding"`
+}
+
+// OrgBranding represents the branding of an organization
+type OrgBranding struct {
+	// the theme the pages of the organization and its repositories are rendered with
+	Theme *Theme `json:"theme"`
+	// overrides the primary color of the theme
+	AccentColor string `json:"accent_color"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// EditOrgBrandingOption options for setting the branding of an organization
+type EditOrgBrandingOption struct {
+	// name of a theme approved for organizations, empty to keep the default theme
+	Theme string `json:"theme"`
+	// hex color like #4183c4, empty to keep the primary color of the theme
+	AccentColor string `json:"accent_color" binding:"MaxSize(7)"`
+}
@@ -0,0 +1,30 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// TranslationOverride represents a message which replaces the one of the locale files
+type TranslationOverride struct {
+	Lang    string `json:"lang"`
+	Key     string `json:"key"`
+	Message string `json:"message"`
+	// swagger:strfmt date-time
+	Updated time.Time `json:"updated_at"`
+}
+
+// SetTranslationOverrideOption options for overriding a message
+type SetTranslationOverrideOption struct {
+	// required: true
+	Message string `json:"message" binding:"Required"`
+}
+
+// LocalePackResult represents the result of uploading a locale pack
+type LocalePackResult struct {
+	Lang string `json:"lang"`
+	// number of messages which were overridden
+	Imported int `json:"imported"`
+	// keys which are unknown or have an empty message
+	IgnoredKeys []string `json:"ignored_keys"`
+}
@@ -77,6 +77,8 @@ type UserSettings struct {
 	// Privacy
 	HideEmail    bool `json:"hide_email"`
 	HideActivity bool `json:"hide_activity"`
+	// CountPrivateActivity counts the activity in repositories others can not see in the heatmap
+	CountPrivateActivity bool `json:"count_private_activity"`
 }
 
 // UserSettingsOptions represents options to change user settings
@@ -90,8 +92,9 @@ type UserSettingsOptions struct {
 	Theme         *string `json:"theme"`
 	DiffViewStyle *string `json:"diff_view_style"`
 	// Privacy
-	HideEmail    *bool `json:"hide_email"`
-	HideActivity *bool `json:"hide_activity"`
+	HideEmail            *bool `json:"hide_email"`
+	HideActivity         *bool `json:"hide_activity"`
+	CountPrivateActivity *bool `json:"count_private_activity"`
 }
 
 // RenameUserOption options when renaming a user
@@ -102,3 +105,83 @@ type RenameUserOption struct {
 	// unique: true
 	NewName string `json:"new_username" binding:"Required"`
 }
+
+// MergeUserOption options when merging a user into another user
+type MergeUserOption struct {
+	// Name of the user the account is merged into, it receives the data of the merged user
+	//
+	// required: true
+	Target string `json:"target" binding:"Required"`
+	// Only report what would be moved and the conflicts, without changing anything
+	DryRun bool `json:"dry_run"`
+}
+
+// UserMergeConflict represents data of the merged user which can not be moved to the target user
+type UserMergeConflict struct {
+	// enum: repository,package,storage_region
+	Type string `json:"type"`
+	Name string `json:"name"`
+}
+
+// UserMergeReport represents the data moved by merging a user into another user and the conflicts preventing it
+type UserMergeReport struct {
+	Source       string               `json:"source"`
+	Target       string               `json:"target"`
+	DryRun       bool                 `json:"dry_run"`
+	Merged       bool                 `json:"merged"`
+	Repositories int64                `json:"repositories"`
+	Issues       int64                `json:"issues"`
+	Comments     int64                `json:"comments"`
+	Stars        int64                `json:"stars"`
+	PublicKeys   int64                `json:"public_keys"`
+	GPGKeys      int64                `json:"gpg_keys"`
+	Packages     int64                `json:"packages"`
+	Teams        int64                `json:"teams"`
+	Conflicts    []*UserMergeConflict `json:"conflicts"`
+}
+
+// UserStatus represents the status a user has set
+// swagger:model
+type UserStatus struct {
+	Emoji   string `json:"emoji"`
+	Message string `json:"message"`
+	// whether the user is busy and should not be picked for reviews
+	Busy bool `json:"busy"`
+	// swagger:strfmt date-time
+	EndsAt  *time.Time `json:"ends_at,omitempty"`
+	Updated time.Time  `json:"updated_at"`
+}
+
+// SetUserStatusOption options to set the status of the authenticated user
+// swagger:model
+type SetUserStatusOption struct {
+	Emoji   string `json:"emoji" binding:"MaxSize(50)"`
+	Message string `json:"message" binding:"MaxSize(100)"`
+	Busy    bool   `json:"busy"`
+	// when the status is cleared automatically, leave empty to keep it until changed
+	// swagger:strfmt date-time
+	EndsAt *time.Time `json:"ends_at"`
+}
+
+// LinkedIdentity represents an account of the user on another Gitea instance
+// swagger:model
+type LinkedIdentity struct {
+	ID             int64  `json:"id"`
+	InstanceURL    string `json:"instance_url"`
+	RemoteUsername string `json:"remote_username"`
+	// token to add to the profile description of the account to verify it
+	Token    string `json:"token,omitempty"`
+	Verified bool   `json:"verified"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+}
+
+// CreateLinkedIdentityOption options to link an account on another Gitea instance
+// swagger:model
+type CreateLinkedIdentityOption struct {
+	// base URL of the other instance
+	// required: true
+	InstanceURL string `json:"instance_url" binding:"Required;ValidUrl;MaxSize(255)"`
+	// required: true
+	RemoteUsername string `json:"remote_username" binding:"Required;MaxSize(255)"`
+}
@@ -22,6 +22,29 @@ type AccessToken struct {
 // swagger:response AccessTokenList
 type AccessTokenList []*AccessToken
 
+// InstanceAccessToken represents a personal access token of any user, as listed for instance administrators
+type InstanceAccessToken struct {
+	ID             int64    `json:"id"`
+	Name           string   `json:"name"`
+	TokenLastEight string   `json:"token_last_eight"`
+	Scopes         []string `json:"scopes"`
+	UserID         int64    `json:"user_id"`
+	UserName       string   `json:"username"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// null if the token has never been used
+	// swagger:strfmt date-time
+	LastUsed *time.Time `json:"last_used_at"`
+	// null if the token does not expire
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires_at"`
+}
+
+// RevokeAccessTokensResult is the number of access tokens revoked in bulk
+type RevokeAccessTokensResult struct {
+	Revoked int64 `json:"revoked"`
+}
+
 // CreateAccessTokenOption options when create access token
 type CreateAccessTokenOption struct {
 	// required: true
@@ -34,18 +57,34 @@ type CreateOAuth2ApplicationOptions struct {
 	Name               string   `json:"name" binding:"Required"`
 	ConfidentialClient bool     `json:"confidential_client"`
 	RedirectURIs       []string `json:"redirect_uris" binding:"Required"`
+	// access token scopes users can grant to the application, all scopes if empty
+	Scopes []string `json:"scopes"`
 }
 
 // OAuth2Application represents an OAuth2 application.
 // swagger:response OAuth2Application
 type OAuth2Application struct {
-	ID                 int64     `json:"id"`
-	Name               string    `json:"name"`
-	ClientID           string    `json:"client_id"`
-	ClientSecret       string    `json:"client_secret"`
-	ConfidentialClient bool      `json:"confidential_client"`
-	RedirectURIs       []string  `json:"redirect_uris"`
-	Created            time.Time `json:"created"`
+	ID                 int64    `json:"id"`
+	Name               string   `json:"name"`
+	ClientID           string   `json:"client_id"`
+	ClientSecret       string   `json:"client_secret"`
+	ConfidentialClient bool     `json:"confidential_client"`
+	RedirectURIs       []string `json:"redirect_uris"`
+	// access token scopes users can grant to the application, all scopes if empty
+	Scopes []string `json:"scopes"`
+	// lifetime of the access tokens in seconds
+	AccessTokenLifetime int64 `json:"access_token_lifetime"`
+	// lifetime of the refresh tokens in hours
+	RefreshTokenLifetime int64     `json:"refresh_token_lifetime"`
+	Created              time.Time `json:"created"`
+}
+
+// EditOAuth2TokenLifetimesOption options for setting the lifetimes of the tokens of an OAuth2 application
+type EditOAuth2TokenLifetimesOption struct {
+	// lifetime of the access tokens in seconds, 0 to use the one of the config
+	AccessTokenLifetime int64 `json:"access_token_lifetime"`
+	// lifetime of the refresh tokens in hours, 0 to use the one of the config
+	RefreshTokenLifetime int64 `json:"refresh_token_lifetime"`
 }
 
 // OAuth2ApplicationList represents a list of OAuth2 applications.
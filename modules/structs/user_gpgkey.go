@@ -20,6 +20,8 @@ type GPGKey struct {
 	CanEncryptStorage bool           `json:"can_encrypt_storage"`
 	CanCertify        bool           `json:"can_certify"`
 	Verified          bool           `json:"verified"`
+	Rotated           bool           `json:"rotated"`
+	SuccessorID       int64          `json:"successor_id"`
 	// swagger:strfmt date-time
 	Created time.Time `json:"created_at,omitempty"`
 	// swagger:strfmt date-time
@@ -51,3 +53,11 @@ type VerifyGPGKeyOption struct {
 	KeyID     string `json:"key_id" binding:"Required"`
 	Signature string `json:"armored_signature" binding:"Required"`
 }
+
+// RotateGPGKeyOption options to mark a GPG key as replaced by a successor key
+type RotateGPGKeyOption struct {
+	// ID of the GPG key of the user replacing the key
+	//
+	// required: true
+	SuccessorID int64 `json:"successor_id" binding:"Required"`
+}
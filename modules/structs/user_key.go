@@ -19,4 +19,24 @@ type PublicKey struct {
 	Owner    *User     `json:"user,omitempty"`
 	ReadOnly bool      `json:"read_only,omitempty"`
 	KeyType  string    `json:"key_type,omitempty"`
+	// swagger:strfmt date-time
+	Expires     *time.Time `json:"expires_at,omitempty"`
+	Rotated     bool       `json:"rotated"`
+	SuccessorID int64      `json:"successor_id"`
+}
+
+// RotatePublicKeyOption options to mark a SSH key as replaced by a successor key
+type RotatePublicKeyOption struct {
+	// ID of the SSH key of the user replacing the key
+	//
+	// required: true
+	SuccessorID int64 `json:"successor_id" binding:"Required"`
+}
+
+// SetPublicKeyExpiryOption options to set the date until which a SSH key is used to sign commits
+type SetPublicKeyExpiryOption struct {
+	// Expiry date of the key, the expiry date is removed if it is empty
+	//
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires_at"`
 }
@@ -0,0 +1,19 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package structs
+
+import "time"
+
+// UserRedirect represents a former name of a user or organization, which redirects to its current name
+type UserRedirect struct {
+	Name string `json:"name"`
+	// swagger:strfmt date-time
+	Created time.Time `json:"created_at"`
+	// when the redirect is deleted, omitted if it is kept until the name is used again
+	// swagger:strfmt date-time
+	Expires *time.Time `json:"expires_at,omitempty"`
+	// until when other users and organizations can't take the name, omitted if they can
+	// swagger:strfmt date-time
+	ReservedUntil *time.Time `json:"reserved_until,omitempty"`
+}
@@ -145,9 +145,21 @@ func NewFuncMap() template.FuncMap {
 		"DisableImportLocal": func() bool {
 			return !setting.ImportLocalPaths
 		},
+		"FederatedSearchEnabled": func() bool {
+			return setting.FederatedSearch.Enabled
+		},
 		"DefaultTheme": func() string {
 			return setting.UI.DefaultTheme
 		},
+		"ThemeStylesheetLink": func(ctx context.Context, name string) string {
+			// themes managed by the administrators are served from the database, all others from the assets
+			if !util.SliceContainsString(setting.UI.Themes, name, true) {
+				if t, err := system_model.GetThemeByName(ctx, name); err == nil {
+					return t.StylesheetLink()
+				}
+			}
+			return setting.StaticURLPrefix + "/assets/css/theme-" + url.PathEscape(name) + ".css?v=" + setting.AssetVersion
+		},
 		"NotificationSettings": func() map[string]interface{} {
 			return map[string]interface{}{
 				"MinTimeout":            int(setting.UI.Notification.MinTimeout / time.Millisecond),
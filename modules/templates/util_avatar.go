@@ -29,18 +29,26 @@ func AvatarHTML(src string, size int, class, name string) template.HTML {
 	return template.HTML(`<img class="` + class + `" src="` + src + `" title="` + html.EscapeString(name) + `" width="` + sizeStr + `" height="` + sizeStr + `"/>`)
 }
 
+// userAvatarLink returns the avatar link of a user, or the default avatar if the page enforces it
+func userAvatarLink(ctx context.Context, u *user_model.User, size int) string {
+	if !u.IsOrganization() && avatars.IsDefaultAvatarEnforced(ctx) {
+		return avatars.DefaultAvatarLink()
+	}
+	return u.AvatarLinkWithSize(ctx, size*setting.Avatar.RenderedSizeFactor)
+}
+
 // Avatar renders user avatars. args: user, size (int), class (string)
 func Avatar(ctx context.Context, item interface{}, others ...interface{}) template.HTML {
 	size, class := gitea_html.ParseSizeAndClass(avatars.DefaultAvatarPixelSize, avatars.DefaultAvatarClass, others...)
 
 	switch t := item.(type) {
 	case *user_model.User:
-		src := t.AvatarLinkWithSize(ctx, size*setting.Avatar.RenderedSizeFactor)
+		src := userAvatarLink(ctx, t, size)
 		if src != "" {
 			return AvatarHTML(src, size, class, t.DisplayName())
 		}
 	case *repo_model.Collaborator:
-		src := t.AvatarLinkWithSize(ctx, size*setting.Avatar.RenderedSizeFactor)
+		src := userAvatarLink(ctx, t.User, size)
 		if src != "" {
 			return AvatarHTML(src, size, class, t.DisplayName())
 		}
@@ -75,6 +83,9 @@ func RepoAvatar(repo *repo_model.Repository, others ...interface{}) template.HTM
 func AvatarByEmail(ctx context.Context, email, name string, others ...interface{}) template.HTML {
 	size, class := gitea_html.ParseSizeAndClass(avatars.DefaultAvatarPixelSize, avatars.DefaultAvatarClass, others...)
 	src := avatars.GenerateEmailAvatarFastLink(ctx, email, size*setting.Avatar.RenderedSizeFactor)
+	if avatars.IsDefaultAvatarEnforced(ctx) {
+		src = avatars.DefaultAvatarLink()
+	}
 
 	if src != "" {
 		return AvatarHTML(src, size, class, name)
@@ -0,0 +1,44 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package timeutil
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCronSchedule(t *testing.T) {
+	for _, spec := range []string{"", "* * * *", "60 * * * *", "* 24 * * *", "* * 0 * *", "*/0 * * * *", "5-1 * * * *", "@sometimes", "a * * * *"} {
+		_, err := ParseCronSchedule(spec)
+		assert.Error(t, err, spec)
+	}
+
+	start := time.Date(2023, 6, 14, 10, 30, 15, 0, time.UTC) // a wednesday
+	kases := []struct {
+		spec string
+		next time.Time
+	}{
+		{"* * * * *", time.Date(2023, 6, 14, 10, 31, 0, 0, time.UTC)},
+		{"@hourly", time.Date(2023, 6, 14, 11, 0, 0, 0, time.UTC)},
+		{"@daily", time.Date(2023, 6, 15, 0, 0, 0, 0, time.UTC)},
+		{"0 9 * * 1-5", time.Date(2023, 6, 15, 9, 0, 0, 0, time.UTC)},
+		{"*/20 10 * * *", time.Date(2023, 6, 14, 10, 40, 0, 0, time.UTC)},
+		{"0 9 * * 7", time.Date(2023, 6, 18, 9, 0, 0, 0, time.UTC)},
+		{"0 9 1,15 * *", time.Date(2023, 6, 15, 9, 0, 0, 0, time.UTC)},
+		{"0 9 1 * 1", time.Date(2023, 6, 19, 9, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, kase := range kases {
+		schedule, err := ParseCronSchedule(kase.spec)
+		if assert.NoError(t, err, kase.spec) {
+			assert.Equal(t, kase.next, schedule.Next(start), kase.spec)
+		}
+	}
+
+	schedule, err := ParseCronSchedule("0 0 31 2 *")
+	assert.NoError(t, err)
+	assert.True(t, schedule.Next(start).IsZero())
+}
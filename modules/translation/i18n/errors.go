@@ -9,5 +9,6 @@ import (
 
 var (
 	ErrLocaleAlreadyExist = util.SilentWrap{Message: "lang already exists", Err: util.ErrAlreadyExist}
+	ErrLocaleNotExist     = util.SilentWrap{Message: "lang does not exist", Err: util.ErrNotExist}
 	ErrUncertainArguments = util.SilentWrap{Message: "arguments to i18n should not contain uncertain slices", Err: util.ErrInvalidArgument}
 )
@@ -34,6 +34,8 @@ type LocaleStore interface {
 	HasLang(langName string) bool
 	// AddLocaleByIni adds a new language to the store
 	AddLocaleByIni(langName, langDesc string, source, moreSource []byte) error
+	// OverrideMessages replaces messages of a language in the store
+	OverrideMessages(langName string, messages map[string]string) error
 }
 
 // ResetDefaultLocales resets the current default locales
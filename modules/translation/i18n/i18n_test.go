@@ -129,3 +129,24 @@ func TestLocaleStoreQuirks(t *testing.T) {
 	//       so, the following line will be parsed as: value="`first", comment="second`" on Crowdin
 	//       > a = `first; second`
 }
+
+func TestLocaleStoreOverrideMessages(t *testing.T) {
+	ls := NewLocaleStore()
+	assert.NoError(t, ls.AddLocaleByIni("lang1", "Lang1", []byte("repo = Repository\n[section]\nsub = Sub String\n"), nil))
+	assert.NoError(t, ls.AddLocaleByIni("lang2", "Lang2", []byte("repo = Depot\n"), nil))
+	ls.SetDefaultLang("lang1")
+
+	messages, err := ParseMessages([]byte("repo = Project\n[section]\nsub = Changed Sub String\nunknown = Unknown\n"))
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{"repo": "Project", "section.sub": "Changed Sub String", "section.unknown": "Unknown"}, messages)
+
+	assert.NoError(t, ls.OverrideMessages("lang2", messages))
+	assert.Equal(t, "Project", ls.Tr("lang2", "repo"))
+	assert.Equal(t, "Changed Sub String", ls.Tr("lang2", "section.sub"))
+	// keys no language has are not added
+	assert.False(t, ls.Has("lang2", "section.unknown"))
+	// the other languages keep their messages
+	assert.Equal(t, "Repository", ls.Tr("lang1", "repo"))
+
+	assert.ErrorIs(t, ls.OverrideMessages("lang3", messages), ErrLocaleNotExist)
+}
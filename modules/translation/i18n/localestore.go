@@ -47,15 +47,49 @@ func (store *localeStore) AddLocaleByIni(langName, langDesc string, source, more
 	l := &locale{store: store, langName: langName, idxToMsgMap: make(map[int]string)}
 	store.localeMap[l.langName] = l
 
+	messages, err := ParseMessages(source, moreSource)
+	if err != nil {
+		return err
+	}
+	for trKey, msg := range messages {
+		idx, ok := store.trKeyToIdxMap[trKey]
+		if !ok {
+			idx = len(store.trKeyToIdxMap)
+			store.trKeyToIdxMap[trKey] = idx
+		}
+		l.idxToMsgMap[idx] = msg
+	}
+
+	return nil
+}
+
+// OverrideMessages replaces messages of a language added before, keys which no language has are ignored
+func (store *localeStore) OverrideMessages(langName string, messages map[string]string) error {
+	l, ok := store.localeMap[langName]
+	if !ok {
+		return ErrLocaleNotExist
+	}
+	for trKey, msg := range messages {
+		if idx, ok := store.trKeyToIdxMap[trKey]; ok {
+			l.idxToMsgMap[idx] = msg
+		}
+	}
+	return nil
+}
+
+// ParseMessages reads the messages of ini sources, the later sources override the earlier ones.
+// The keys of the messages are prefixed with the names of their sections.
+func ParseMessages(source any, moreSources ...any) (map[string]string, error) {
 	iniFile, err := ini.LoadSources(ini.LoadOptions{
 		IgnoreInlineComment:         true,
 		UnescapeValueCommentSymbols: true,
-	}, source, moreSource)
+	}, source, moreSources...)
 	if err != nil {
-		return fmt.Errorf("unable to load ini: %w", err)
+		return nil, fmt.Errorf("unable to load ini: %w", err)
 	}
 	iniFile.BlockMode = false
 
+	messages := make(map[string]string)
 	for _, section := range iniFile.Sections() {
 		for _, key := range section.Keys() {
 			var trKey string
@@ -64,16 +98,10 @@ func (store *localeStore) AddLocaleByIni(langName, langDesc string, source, more
 			} else {
 				trKey = section.Name() + "." + key.Name()
 			}
-			idx, ok := store.trKeyToIdxMap[trKey]
-			if !ok {
-				idx = len(store.trKeyToIdxMap)
-				store.trKeyToIdxMap[trKey] = idx
-			}
-			l.idxToMsgMap[idx] = key.Value()
+			messages[trKey] = key.Value()
 		}
 	}
-
-	return nil
+	return messages, nil
 }
 
 func (store *localeStore) HasLang(langName string) bool {
@@ -38,11 +38,13 @@ type LangType struct {
 }
 
 var (
-	lock          *sync.RWMutex
+	lock          sync.RWMutex
 	matcher       language.Matcher
 	allLangs      []*LangType
 	allLangMap    map[string]*LangType
 	supportedTags []language.Tag
+	// overrides are the messages administrators changed, per language
+	overrides map[string]map[string]string
 )
 
 // AllLangs returns all supported languages sorted by name
@@ -52,57 +54,8 @@ func AllLangs() []*LangType {
 
 // InitLocales loads the locales
 func InitLocales(ctx context.Context) {
-	if lock != nil {
-		lock.Lock()
-		defer lock.Unlock()
-	} else if !setting.IsProd && lock == nil {
-		lock = &sync.RWMutex{}
-	}
-
-	refreshLocales := func() {
-		i18n.ResetDefaultLocales()
-		localeNames, err := options.AssetFS().ListFiles("locale", true)
-		if err != nil {
-			log.Fatal("Failed to list locale files: %v", err)
-		}
-
-		localeData := make(map[string][]byte, len(localeNames))
-		for _, name := range localeNames {
-			localeData[name], err = options.Locale(name)
-			if err != nil {
-				log.Fatal("Failed to load %s locale file. %v", name, err)
-			}
-		}
-
-		supportedTags = make([]language.Tag, len(setting.Langs))
-		for i, lang := range setting.Langs {
-			supportedTags[i] = language.Raw.Make(lang)
-		}
-
-		matcher = language.NewMatcher(supportedTags)
-		for i := range setting.Names {
-			var localeDataBase []byte
-			if i == 0 && setting.Langs[0] != "en-US" {
-				// Only en-US has complete translations. When use other language as default, the en-US should still be used as fallback.
-				localeDataBase = localeData["locale_en-US.ini"]
-				if localeDataBase == nil {
-					log.Fatal("Failed to load locale_en-US.ini file.")
-				}
-			}
-
-			key := "locale_" + setting.Langs[i] + ".ini"
-			if err = i18n.DefaultLocales.AddLocaleByIni(setting.Langs[i], setting.Names[i], localeDataBase, localeData[key]); err != nil {
-				log.Error("Failed to set messages to %s: %v", setting.Langs[i], err)
-			}
-		}
-		if len(setting.Langs) != 0 {
-			defaultLangName := setting.Langs[0]
-			if defaultLangName != "en-US" {
-				log.Info("Use the first locale (%s) in LANGS setting option as default", defaultLangName)
-			}
-			i18n.DefaultLocales.SetDefaultLang(defaultLangName)
-		}
-	}
+	lock.Lock()
+	defer lock.Unlock()
 
 	refreshLocales()
 
@@ -129,8 +82,86 @@ func InitLocales(ctx context.Context) {
 	}
 }
 
+func refreshLocales() {
+	i18n.ResetDefaultLocales()
+	localeNames, err := options.AssetFS().ListFiles("locale", true)
+	if err != nil {
+		log.Fatal("Failed to list locale files: %v", err)
+	}
+
+	localeData := make(map[string][]byte, len(localeNames))
+	for _, name := range localeNames {
+		localeData[name], err = options.Locale(name)
+		if err != nil {
+			log.Fatal("Failed to load %s locale file. %v", name, err)
+		}
+	}
+
+	supportedTags = make([]language.Tag, len(setting.Langs))
+	for i, lang := range setting.Langs {
+		supportedTags[i] = language.Raw.Make(lang)
+	}
+
+	matcher = language.NewMatcher(supportedTags)
+	for i := range setting.Names {
+		var localeDataBase []byte
+		if i == 0 && setting.Langs[0] != "en-US" {
+			// Only en-US has complete translations. When use other language as default, the en-US should still be used as fallback.
+			localeDataBase = localeData["locale_en-US.ini"]
+			if localeDataBase == nil {
+				log.Fatal("Failed to load locale_en-US.ini file.")
+			}
+		}
+
+		key := "locale_" + setting.Langs[i] + ".ini"
+		if err = i18n.DefaultLocales.AddLocaleByIni(setting.Langs[i], setting.Names[i], localeDataBase, localeData[key]); err != nil {
+			log.Error("Failed to set messages to %s: %v", setting.Langs[i], err)
+		}
+	}
+	for lang, messages := range overrides {
+		if err := i18n.DefaultLocales.OverrideMessages(lang, messages); err != nil {
+			log.Warn("Failed to override messages of %s: %v", lang, err)
+		}
+	}
+	if len(setting.Langs) != 0 {
+		defaultLangName := setting.Langs[0]
+		if defaultLangName != "en-US" {
+			log.Info("Use the first locale (%s) in LANGS setting option as default", defaultLangName)
+		}
+		i18n.DefaultLocales.SetDefaultLang(defaultLangName)
+	}
+}
+
+// SetOverrides replaces the messages overridden per language and reloads the locales with them
+func SetOverrides(messages map[string]map[string]string) {
+	lock.Lock()
+	defer lock.Unlock()
+
+	overrides = messages
+	refreshLocales()
+}
+
+// HasKey returns if a key is translated by the default language, or by en-US which it falls back to
+func HasKey(trKey string) bool {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return len(setting.Langs) > 0 && i18n.DefaultLocales.Has(setting.Langs[0], trKey)
+}
+
+// HasLang returns if a language is supported
+func HasLang(lang string) bool {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	return i18n.DefaultLocales.HasLang(lang)
+}
+
 // Match matches accept languages
 func Match(tags ...language.Tag) language.Tag {
+	lock.RLock()
+	defer lock.RUnlock()
+
 	_, i, _ := matcher.Match(tags...)
 	return supportedTags[i]
 }
@@ -144,10 +175,8 @@ type locale struct {
 
 // NewLocale return a locale
 func NewLocale(lang string) Locale {
-	if lock != nil {
-		lock.RLock()
-		defer lock.RUnlock()
-	}
+	lock.RLock()
+	defer lock.RUnlock()
 
 	langName := "unknown"
 	if l, ok := allLangMap[lang]; ok {
@@ -4,6 +4,7 @@
 package upload
 
 import (
+	"fmt"
 	"mime"
 	"net/http"
 	"net/url"
@@ -11,6 +12,7 @@ import (
 	"regexp"
 	"strings"
 
+	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
@@ -31,8 +33,53 @@ func (err ErrFileTypeForbidden) Error() string {
 	return "This file extension or type is not allowed to be uploaded."
 }
 
+// ErrFileTooLarge is returned if a file exceeds the size limit for its type
+type ErrFileTooLarge struct {
+	Type    string
+	MaxSize int64
+}
+
+// IsErrFileTooLarge checks if an error is a ErrFileTooLarge.
+func IsErrFileTooLarge(err error) bool {
+	_, ok := err.(ErrFileTooLarge)
+	return ok
+}
+
+func (err ErrFileTooLarge) Error() string {
+	return fmt.Sprintf("Files of this type must not be larger than %s.", base.FileSize(err.MaxSize))
+}
+
 var wildcardTypeRe = regexp.MustCompile(`^[a-z]+/\*$`)
 
+// DetectType returns the mime type detected from the first bytes of a file,
+// without and with its parameters like the charset
+func DetectType(buf []byte) (mimeType, fullMimeType string, err error) {
+	fullMimeType = http.DetectContentType(buf)
+	mimeType, _, err = mime.ParseMediaType(fullMimeType)
+	if err != nil {
+		log.Warn("Detected attachment type could not be parsed %s", fullMimeType)
+		return fullMimeType, fullMimeType, ErrFileTypeForbidden{Type: fullMimeType}
+	}
+	return mimeType, fullMimeType, nil
+}
+
+// MatchType checks whether a file with the given mime type and name matches an entry of an allowed types list
+func MatchType(mimeType, fileName, allowEntry string) bool {
+	extension := strings.ToLower(path.Ext(fileName))
+
+	// https://developer.mozilla.org/en-US/docs/Web/HTML/Element/input/file#Unique_file_type_specifiers
+	if allowEntry == "*/*" {
+		return true // everything allowed
+	} else if strings.HasPrefix(allowEntry, ".") && allowEntry == extension {
+		return true // extension is allowed
+	} else if mimeType == allowEntry {
+		return true // mime type is allowed
+	} else if wildcardTypeRe.MatchString(allowEntry) && strings.HasPrefix(mimeType, allowEntry[:len(allowEntry)-1]) {
+		return true // wildcard match, e.g. image/*
+	}
+	return false
+}
+
 // Verify validates whether a file is allowed to be uploaded.
 func Verify(buf []byte, fileName, allowedTypesStr string) error {
 	allowedTypesStr = strings.ReplaceAll(allowedTypesStr, "|", ",") // compat for old config format
@@ -49,24 +96,14 @@ func Verify(buf []byte, fileName, allowedTypesStr string) error {
 		return nil // everything is allowed
 	}
 
-	fullMimeType := http.DetectContentType(buf)
-	mimeType, _, err := mime.ParseMediaType(fullMimeType)
+	mimeType, fullMimeType, err := DetectType(buf)
 	if err != nil {
-		log.Warn("Detected attachment type could not be parsed %s", fullMimeType)
-		return ErrFileTypeForbidden{Type: fullMimeType}
+		return err
 	}
-	extension := strings.ToLower(path.Ext(fileName))
 
-	// https://developer.mozilla.org/en-US/docs/Web/HTML/Element/input/file#Unique_file_type_specifiers
 	for _, allowEntry := range allowedTypes {
-		if allowEntry == "*/*" {
-			return nil // everything allowed
-		} else if strings.HasPrefix(allowEntry, ".") && allowEntry == extension {
-			return nil // extension is allowed
-		} else if mimeType == allowEntry {
-			return nil // mime type is allowed
-		} else if wildcardTypeRe.MatchString(allowEntry) && strings.HasPrefix(mimeType, allowEntry[:len(allowEntry)-1]) {
-			return nil // wildcard match, e.g. image/*
+		if MatchType(mimeType, fileName, allowEntry) {
+			return nil
 		}
 	}
 
@@ -301,19 +301,27 @@ func IsReadmeFileName(name string) bool {
 // the length of the provided extension list.
 // Note that the '.' should be provided in ext, e.g ".md"
 func IsReadmeFileExtension(name string, ext ...string) (int, bool) {
+	return IsFileNameWithExtension(name, "readme", ext...)
+}
+
+// IsFileNameWithExtension reports whether name is the given base name followed by an extension,
+// ignoring case. It provides the index of the matching extension in the extension list, or the
+// length of the list if the name has an unmatched extension.
+func IsFileNameWithExtension(name, base string, ext ...string) (int, bool) {
 	name = strings.ToLower(name)
-	if len(name) < 6 || name[:6] != "readme" {
+	base = strings.ToLower(base)
+	if !strings.HasPrefix(name, base) {
 		return 0, false
 	}
+	rest := name[len(base):]
 
 	for i, extension := range ext {
-		extension = strings.ToLower(extension)
-		if name[6:] == extension {
+		if rest == strings.ToLower(extension) {
 			return i, true
 		}
 	}
 
-	if name[6] == '.' {
+	if len(rest) > 0 && rest[0] == '.' {
 		return len(ext), true
 	}
 
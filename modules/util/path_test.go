@@ -137,6 +137,26 @@ func TestMisc_IsReadmeFileName(t *testing.T) {
 	}
 }
 
+func TestIsFileNameWithExtension(t *testing.T) {
+	idx, ok := IsFileNameWithExtension("CONTRIBUTING.md", "contributing", ".md", "")
+	assert.True(t, ok)
+	assert.Equal(t, 0, idx)
+
+	idx, ok = IsFileNameWithExtension("Index", "index", ".md", "")
+	assert.True(t, ok)
+	assert.Equal(t, 1, idx)
+
+	idx, ok = IsFileNameWithExtension("index.rst", "index", ".md", "")
+	assert.True(t, ok)
+	assert.Equal(t, 2, idx)
+
+	_, ok = IsFileNameWithExtension("indexes.md", "index", ".md", "")
+	assert.False(t, ok)
+
+	_, ok = IsFileNameWithExtension("index", "index", ".md")
+	assert.False(t, ok)
+}
+
 func TestCleanPath(t *testing.T) {
 	cases := []struct {
 		elems    []string
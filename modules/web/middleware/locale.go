@@ -7,7 +7,6 @@ import (
 	"net/http"
 
 	"code.gitea.io/gitea/modules/translation"
-	"code.gitea.io/gitea/modules/translation/i18n"
 
 	"golang.org/x/text/language"
 )
@@ -27,7 +26,7 @@ func Locale(resp http.ResponseWriter, req *http.Request) translation.Locale {
 	}
 
 	// Check again in case someone changes the supported language list.
-	if lang != "" && !i18n.DefaultLocales.HasLang(lang) {
+	if lang != "" && !translation.HasLang(lang) {
 		lang = ""
 		changeLang = false
 	}
@@ -0,0 +1,85 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package events decodes webhook deliveries and Actions event payloads into the typed payloads
+// of the structs module. The mapping of events to payloads is generated from the webhook payload
+// declarations of the API specification in routers/api/v1/swagger/webhook.go.
+package events
+
+//go:generate go run ../../../build/generate-webhook-events.go -o ./events_gen.go
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// EventTypeHeader is the header of a webhook delivery which tells the type of the event
+const EventTypeHeader = "X-Gitea-Event-Type"
+
+// ErrUnknownEvent represents an event without known payload type
+type ErrUnknownEvent struct {
+	Event string
+}
+
+// IsErrUnknownEvent checks if an error is a ErrUnknownEvent.
+func IsErrUnknownEvent(err error) bool {
+	_, ok := err.(ErrUnknownEvent)
+	return ok
+}
+
+func (err ErrUnknownEvent) Error() string {
+	return fmt.Sprintf("unknown event [event: %s]", err.Event)
+}
+
+func (err ErrUnknownEvent) Unwrap() error {
+	return util.ErrNotExist
+}
+
+func unmarshal(newPayload func() any, event string, body []byte) (any, error) {
+	if newPayload == nil {
+		return nil, ErrUnknownEvent{Event: event}
+	}
+	payload := newPayload()
+	if err := json.Unmarshal(body, payload); err != nil {
+		return nil, fmt.Errorf("unable to decode the payload of event %s: %w", event, err)
+	}
+	return payload, nil
+}
+
+// EventTypes returns the values of the event type header of webhook deliveries
+func EventTypes() []string {
+	eventTypes := make([]string, 0, len(newPayloadByEventType))
+	for eventType := range newPayloadByEventType {
+		eventTypes = append(eventTypes, eventType)
+	}
+	return eventTypes
+}
+
+// ParseWebhookPayload decodes the body of a webhook delivery with the event type, e.g. a
+// *structs.PushPayload for "push" or a *structs.PullRequestPayload for "pull_request_label"
+func ParseWebhookPayload(eventType string, body []byte) (any, error) {
+	return unmarshal(newPayloadByEventType[eventType], eventType, body)
+}
+
+// ParseRequest decodes the webhook delivery using the event type header of the request
+func ParseRequest(req *http.Request) (any, error) {
+	eventType := req.Header.Get(EventTypeHeader)
+	if eventType == "" {
+		return nil, fmt.Errorf("missing %s header", EventTypeHeader)
+	}
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseWebhookPayload(eventType, body)
+}
+
+// ParseActionsEventPayload decodes the event payload of an Actions run with the name of the event
+// which triggered the run
+func ParseActionsEventPayload(eventName string, body []byte) (any, error) {
+	return unmarshal(newPayloadByActionsEvent[eventName], eventName, body)
+}
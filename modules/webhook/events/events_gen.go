@@ -0,0 +1,56 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Code generated by build/generate-webhook-events.go. DO NOT EDIT.
+
+package events
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// newPayloadByEventType creates the payload for the value of the X-Gitea-Event-Type header
+var newPayloadByEventType = map[string]func() any{
+	"create":                       func() any { return &api.CreatePayload{} },
+	"delete":                       func() any { return &api.DeletePayload{} },
+	"discussion":                   func() any { return &api.DiscussionPayload{} },
+	"discussion_comment":           func() any { return &api.DiscussionCommentPayload{} },
+	"fork":                         func() any { return &api.ForkPayload{} },
+	"issue_assign":                 func() any { return &api.IssuePayload{} },
+	"issue_comment":                func() any { return &api.IssueCommentPayload{} },
+	"issue_label":                  func() any { return &api.IssuePayload{} },
+	"issue_milestone":              func() any { return &api.IssuePayload{} },
+	"issues":                       func() any { return &api.IssuePayload{} },
+	"package":                      func() any { return &api.PackagePayload{} },
+	"pull_request":                 func() any { return &api.PullRequestPayload{} },
+	"pull_request_assign":          func() any { return &api.PullRequestPayload{} },
+	"pull_request_comment":         func() any { return &api.IssueCommentPayload{} },
+	"pull_request_label":           func() any { return &api.PullRequestPayload{} },
+	"pull_request_milestone":       func() any { return &api.PullRequestPayload{} },
+	"pull_request_review_approved": func() any { return &api.PullRequestPayload{} },
+	"pull_request_review_comment":  func() any { return &api.PullRequestPayload{} },
+	"pull_request_review_rejected": func() any { return &api.PullRequestPayload{} },
+	"pull_request_sync":            func() any { return &api.PullRequestPayload{} },
+	"push":                         func() any { return &api.PushPayload{} },
+	"release":                      func() any { return &api.ReleasePayload{} },
+	"repository":                   func() any { return &api.RepositoryPayload{} },
+	"wiki":                         func() any { return &api.WikiPayload{} },
+}
+
+// newPayloadByActionsEvent creates the payload for the name of the event which triggered an Actions run
+var newPayloadByActionsEvent = map[string]func() any{
+	"create":                      func() any { return &api.CreatePayload{} },
+	"delete":                      func() any { return &api.DeletePayload{} },
+	"fork":                        func() any { return &api.ForkPayload{} },
+	"gollum":                      func() any { return &api.WikiPayload{} },
+	"issue_comment":               func() any { return &api.IssueCommentPayload{} },
+	"issues":                      func() any { return &api.IssuePayload{} },
+	"pull_request":                func() any { return &api.PullRequestPayload{} },
+	"pull_request_comment":        func() any { return &api.IssueCommentPayload{} },
+	"pull_request_review":         func() any { return &api.PullRequestPayload{} },
+	"pull_request_review_comment": func() any { return &api.PullRequestPayload{} },
+	"pull_request_target":         func() any { return &api.PullRequestPayload{} },
+	"push":                        func() any { return &api.PushPayload{} },
+	"registry_package":            func() any { return &api.PackagePayload{} },
+	"release":                     func() any { return &api.ReleasePayload{} },
+}
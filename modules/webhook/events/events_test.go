@@ -0,0 +1,62 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package events
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseWebhookPayload(t *testing.T) {
+	payload, err := ParseWebhookPayload("push", []byte(`{"ref":"refs/heads/main","commits":[{"id":"abc"}]}`))
+	assert.NoError(t, err)
+	if assert.IsType(t, &api.PushPayload{}, payload) {
+		push := payload.(*api.PushPayload)
+		assert.Equal(t, "refs/heads/main", push.Ref)
+		assert.Len(t, push.Commits, 1)
+	}
+
+	payload, err = ParseWebhookPayload("pull_request_label", []byte(`{"action":"label_updated","number":2}`))
+	assert.NoError(t, err)
+	if assert.IsType(t, &api.PullRequestPayload{}, payload) {
+		assert.EqualValues(t, 2, payload.(*api.PullRequestPayload).Index)
+	}
+
+	_, err = ParseWebhookPayload("unknown", []byte(`{}`))
+	assert.True(t, IsErrUnknownEvent(err))
+
+	_, err = ParseWebhookPayload("push", []byte(`{`))
+	assert.Error(t, err)
+	assert.False(t, IsErrUnknownEvent(err))
+}
+
+func TestParseRequest(t *testing.T) {
+	req, _ := http.NewRequest(http.MethodPost, "https://example.com/hook", strings.NewReader(`{"ref":"v1.0","ref_type":"tag"}`))
+	req.Header.Set(EventTypeHeader, "create")
+	payload, err := ParseRequest(req)
+	assert.NoError(t, err)
+	if assert.IsType(t, &api.CreatePayload{}, payload) {
+		assert.Equal(t, "tag", payload.(*api.CreatePayload).RefType)
+	}
+
+	req, _ = http.NewRequest(http.MethodPost, "https://example.com/hook", strings.NewReader(`{}`))
+	_, err = ParseRequest(req)
+	assert.Error(t, err)
+}
+
+func TestParseActionsEventPayload(t *testing.T) {
+	payload, err := ParseActionsEventPayload("gollum", []byte(`{"page":"Home"}`))
+	assert.NoError(t, err)
+	if assert.IsType(t, &api.WikiPayload{}, payload) {
+		assert.Equal(t, "Home", payload.(*api.WikiPayload).Page)
+	}
+
+	_, err = ParseActionsEventPayload("schedule", []byte(`{}`))
+	assert.True(t, IsErrUnknownEvent(err))
+}
@@ -25,6 +25,9 @@ type HookEvents struct {
 	Repository           bool `json:"repository"`
 	Release              bool `json:"release"`
 	Package              bool `json:"package"`
+	Discussion           bool `json:"discussion"`
+	DiscussionComment    bool `json:"discussion_comment"`
+	ActionRunApproval    bool `json:"action_run_approval"`
 }
 
 // HookEvent represents events that will delivery hook.
@@ -30,6 +30,9 @@ const (
 	HookEventRepository                HookEventType = "repository"
 	HookEventRelease                   HookEventType = "release"
 	HookEventPackage                   HookEventType = "package"
+	HookEventDiscussion                HookEventType = "discussion"
+	HookEventDiscussionComment         HookEventType = "discussion_comment"
+	HookEventActionRunApproval         HookEventType = "action_run_approval"
 )
 
 // Event returns the HookEventType as an event string
@@ -62,6 +65,12 @@ func (h HookEventType) Event() string {
 		return "repository"
 	case HookEventRelease:
 		return "release"
+	case HookEventDiscussion:
+		return "discussion"
+	case HookEventDiscussionComment:
+		return "discussion_comment"
+	case HookEventActionRunApproval:
+		return "action_run_approval"
 	}
 	return ""
 }
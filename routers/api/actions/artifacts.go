@@ -83,6 +83,7 @@ import (
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
+	actions_service "code.gitea.io/gitea/services/actions"
 )
 
 const (
@@ -132,8 +133,7 @@ func withContexter(goctx gocontext.Context) func(next http.Handler) http.Handler
 				ctx.Error(http.StatusUnauthorized, "Bad authorization header")
 				return
 			}
-			authToken := strings.TrimPrefix(authHeader, "Bearer ")
-			task, err := actions.GetRunningTaskByToken(req.Context(), authToken)
+			task, err := getTaskFromRequest(req)
 			if err != nil {
 				log.Error("Error runner api getting task: %v", err)
 				ctx.Error(http.StatusInternalServerError, "Error runner api getting task")
@@ -154,6 +154,22 @@ func withContexter(goctx gocontext.Context) func(next http.Handler) http.Handler
 	}
 }
 
+// getTaskFromRequest returns the running task of the ACTIONS_RUNTIME_TOKEN of the request,
+// which is either the runtime token created for the task or the token of the task
+func getTaskFromRequest(req *http.Request) (*actions.ActionTask, error) {
+	if taskID, err := actions_service.ParseAuthorizationToken(req); err == nil && taskID != 0 {
+		task, err := actions.GetTaskByID(req.Context(), taskID)
+		if err != nil {
+			return nil, err
+		}
+		if task.Status != actions.StatusRunning {
+			return nil, fmt.Errorf("task %d is not running: %w", taskID, util.ErrNotExist)
+		}
+		return task, nil
+	}
+	return actions.GetRunningTaskByToken(req.Context(), strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer "))
+}
+
 type artifactRoutes struct {
 	prefix string
 	fs     storage.ObjectStorage
@@ -167,8 +183,9 @@ func (ar artifactRoutes) buildArtifactURL(runID, artifactID int64, suffix string
 }
 
 type getUploadArtifactRequest struct {
-	Type string
-	Name string
+	Type          string
+	Name          string
+	RetentionDays int64
 }
 
 type getUploadArtifactResponse struct {
@@ -205,7 +222,12 @@ func (ar artifactRoutes) getUploadArtifactURL(ctx *context.Context) {
 		return
 	}
 
-	artifact, err := actions.CreateArtifact(ctx, task, req.Name)
+	// set retention days
+	retentionDays := setting.Actions.ArtifactRetentionDays
+	if req.RetentionDays > 0 {
+		retentionDays = req.RetentionDays
+	}
+	artifact, err := actions.CreateArtifact(ctx, task, req.Name, retentionDays)
 	if err != nil {
 		log.Error("Error creating artifact: %v", err)
 		ctx.Error(http.StatusInternalServerError, err.Error())
@@ -320,8 +342,8 @@ func (ar artifactRoutes) uploadArtifact(ctx *context.Context) {
 		return
 	}
 
-	// if artifact name is not set, update it
-	if artifact.ArtifactName == "" {
+	// if artifact path is not set, update it
+	if artifact.ArtifactPath == "" {
 		artifact.ArtifactName = artifactName
 		artifact.ArtifactPath = itemPath // path in container
 		artifact.FileSize = fileSize     // this is total size of all chunks
@@ -568,6 +590,10 @@ func (ar artifactRoutes) downloadArtifact(ctx *context.Context) {
 		ctx.Error(http.StatusBadRequest, err.Error())
 		return
 	}
+	if artifact.Status == actions.ArtifactStatusExpired {
+		ctx.Error(http.StatusNotFound, "artifact has expired")
+		return
+	}
 
 	fd, err := ar.fs.Open(artifact.StoragePath)
 	if err != nil {
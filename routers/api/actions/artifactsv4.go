@@ -0,0 +1,681 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+// GitHub Actions Artifacts V4 API Simple Description
+//
+// actions/upload-artifact@v4 and actions/download-artifact@v4 call Twirp services with JSON bodies under
+// {ACTIONS_RESULTS_URL}/twirp/github.actions.results.api.v1.ArtifactService, authenticated with the
+// ACTIONS_RUNTIME_TOKEN. They read the run and the job from the scp claim of the token.
+// An artifact is uploaded as one zip archive of all its files.
+//
+// 1. Upload artifact
+// 1.1. Create artifact
+// POST: /twirp/github.actions.results.api.v1.ArtifactService/CreateArtifact
+// Request:
+// {
+//   "workflowRunBackendId": "1",
+//   "workflowJobRunBackendId": "2",
+//   "name": "artifact",
+//   "expiresAt": "2023-12-01T00:00:00Z",
+//   "version": 4
+// }
+// Response:
+// {
+//   "ok": true,
+//   "signedUploadUrl": "/twirp/github.actions.results.api.v1.ArtifactService/UploadArtifact?sig=...&expires=...&artifactName=artifact&taskID=3"
+// }
+// expiresAt is optional, the retention period of [actions] ARTIFACT_RETENTION_DAYS applies without it
+// 1.2. Upload blocks
+// PUT: {signedUploadUrl}&comp=block&blockid={block_id}
+// the archive is uploaded in blocks like an Azure block blob, a failed block can be uploaded again
+// 1.3. Commit blocks
+// PUT: {signedUploadUrl}&comp=blocklist
+// Request:
+// <BlockList><Latest>{block_id}</Latest><Latest>{block_id}</Latest></BlockList>
+// it merges the blocks in the order of the list to one file
+// 1.4. Finalize artifact
+// POST: /twirp/github.actions.results.api.v1.ArtifactService/FinalizeArtifact
+// Request:
+// {
+//   "workflowRunBackendId": "1",
+//   "workflowJobRunBackendId": "2",
+//   "name": "artifact",
+//   "size": "2048"
+// }
+// Response:
+// {
+//   "ok": true,
+//   "artifactId": "4"
+// }
+//
+// 2. Download artifact
+// 2.1. List artifacts
+// POST: /twirp/github.actions.results.api.v1.ArtifactService/ListArtifacts
+// Request:
+// {
+//   "workflowRunBackendId": "1",
+//   "workflowJobRunBackendId": "2",
+//   "nameFilter": "artifact"
+// }
+// Response:
+// {
+//   "artifacts": [
+//     {
+//       "workflowRunBackendId": "1",
+//       "workflowJobRunBackendId": "2",
+//       "databaseId": "4",
+//       "name": "artifact",
+//       "size": "2048",
+//       "createdAt": "2023-09-01T00:00:00Z"
+//     }
+//   ]
+// }
+// 2.2. Get download url
+// POST: /twirp/github.actions.results.api.v1.ArtifactService/GetSignedArtifactURL
+// Request:
+// {
+//   "workflowRunBackendId": "1",
+//   "workflowJobRunBackendId": "2",
+//   "name": "artifact"
+// }
+// Response:
+// {
+//   "signedUrl": "/twirp/github.actions.results.api.v1.ArtifactService/DownloadArtifact?sig=...&expires=...&artifactName=artifact&taskID=3"
+// }
+// 2.3. Download artifact
+// GET: {signedUrl}
+// Response:
+// the zip archive, range requests are supported to resume interrupted downloads
+//
+// 3. Delete artifact
+// POST: /twirp/github.actions.results.api.v1.ArtifactService/DeleteArtifact
+// Request:
+// {
+//   "workflowRunBackendId": "1",
+//   "workflowJobRunBackendId": "2",
+//   "name": "artifact"
+// }
+// Response:
+// {
+//   "ok": true,
+//   "artifactId": "4"
+// }
+//
+
+import (
+	gocontext "context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// ArtifactV4RouteBase is the path of the artifact service of the v4 protocol
+const ArtifactV4RouteBase = "/twirp/github.actions.results.api.v1.ArtifactService"
+
+// artifactV4URLLifetime is how long the signed upload and download urls are valid
+const artifactV4URLLifetime = 3 * time.Hour
+
+// maxArtifactV4BlockIDLength is the length of the base64 encoding of the longest block id of an Azure block blob,
+// 64 bytes, it keeps the file names of the blocks short enough for every storage
+const maxArtifactV4BlockIDLength = 88
+
+func ArtifactsV4Routes(goctx gocontext.Context, prefix string) *web.Route {
+	m := web.NewRoute()
+	m.Use(withArtifactV4Contexter(goctx))
+
+	r := artifactV4Routes{
+		prefix: prefix,
+		fs:     storage.ActionsArtifacts,
+	}
+
+	m.Group("", func() {
+		m.Post("/CreateArtifact", r.createArtifact)
+		m.Post("/FinalizeArtifact", r.finalizeArtifact)
+		m.Post("/ListArtifacts", r.listArtifacts)
+		m.Post("/GetSignedArtifactURL", r.getSignedArtifactURL)
+		m.Post("/DeleteArtifact", r.deleteArtifact)
+	}, requireRuntimeToken)
+	// the signatures of the urls authenticate uploads and downloads
+	m.Put("/UploadArtifact", r.uploadArtifact)
+	m.Get("/DownloadArtifact", r.downloadArtifact)
+
+	return m
+}
+
+// withArtifactV4Contexter initializes a context for a request.
+func withArtifactV4Contexter(goctx gocontext.Context) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+			ctx := context.Context{
+				Resp: context.NewResponse(resp),
+				Data: map[string]interface{}{},
+			}
+			defer ctx.Close()
+
+			ctx.Req = context.WithContext(req, &ctx)
+
+			next.ServeHTTP(ctx.Resp, ctx.Req)
+		})
+	}
+}
+
+// requireRuntimeToken verifies the ACTIONS_RUNTIME_TOKEN of the request and stores its task in the context
+func requireRuntimeToken(ctx *context.Context) {
+	task, err := getTaskFromRequest(ctx.Req)
+	if err != nil {
+		log.Error("Error runner api getting task: %v", err)
+		ctx.Error(http.StatusUnauthorized, "Error runner api getting task")
+		return
+	}
+	if err := task.LoadJob(ctx); err != nil {
+		log.Error("Error runner api getting job: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error runner api getting job")
+		return
+	}
+	ctx.Data["task"] = task
+}
+
+type artifactV4Routes struct {
+	prefix string
+	fs     storage.ObjectStorage
+}
+
+type artifactV4Request struct {
+	WorkflowRunBackendID    string `json:"workflowRunBackendId"`
+	WorkflowJobRunBackendID string `json:"workflowJobRunBackendId"`
+}
+
+type (
+	createArtifactV4Request struct {
+		artifactV4Request
+		Name      string     `json:"name"`
+		ExpiresAt *time.Time `json:"expiresAt"`
+		Version   int32      `json:"version"`
+	}
+	createArtifactV4Response struct {
+		OK              bool   `json:"ok"`
+		SignedUploadURL string `json:"signedUploadUrl"`
+	}
+)
+
+type (
+	finalizeArtifactV4Request struct {
+		artifactV4Request
+		Name string `json:"name"`
+		Size int64  `json:"size,string"`
+		Hash string `json:"hash"`
+	}
+	finalizeArtifactV4Response struct {
+		OK         bool  `json:"ok"`
+		ArtifactID int64 `json:"artifactId,string"`
+	}
+)
+
+type (
+	listArtifactsV4Request struct {
+		artifactV4Request
+		NameFilter string `json:"nameFilter"`
+		IDFilter   int64  `json:"idFilter,string"`
+	}
+	listArtifactsV4Response struct {
+		Artifacts []*listArtifactsV4ResponseItem `json:"artifacts"`
+	}
+	listArtifactsV4ResponseItem struct {
+		WorkflowRunBackendID    string    `json:"workflowRunBackendId"`
+		WorkflowJobRunBackendID string    `json:"workflowJobRunBackendId"`
+		DatabaseID              int64     `json:"databaseId,string"`
+		Name                    string    `json:"name"`
+		Size                    int64     `json:"size,string"`
+		CreatedAt               time.Time `json:"createdAt"`
+	}
+)
+
+type (
+	getSignedArtifactURLV4Request struct {
+		artifactV4Request
+		Name string `json:"name"`
+	}
+	getSignedArtifactURLV4Response struct {
+		SignedURL string `json:"signedUrl"`
+	}
+)
+
+type (
+	deleteArtifactV4Request struct {
+		artifactV4Request
+		Name string `json:"name"`
+	}
+	deleteArtifactV4Response struct {
+		OK         bool  `json:"ok"`
+		ArtifactID int64 `json:"artifactId,string"`
+	}
+)
+
+// blockListV4 is the list of blocks an uploaded artifact consists of, in their order
+type blockListV4 struct {
+	Blocks []struct {
+		ID string `xml:",chardata"`
+	} `xml:",any"`
+}
+
+// decodeRequest decodes the body of a request and checks it belongs to the run of the task
+func (r artifactV4Routes) decodeRequest(ctx *context.Context, req any, ids *artifactV4Request) (*actions.ActionTask, bool) {
+	task, ok := ctx.Data["task"].(*actions.ActionTask)
+	if !ok {
+		log.Error("Error getting task in context")
+		ctx.Error(http.StatusInternalServerError, "Error getting task in context")
+		return nil, false
+	}
+	if err := json.NewDecoder(ctx.Req.Body).Decode(req); err != nil {
+		log.Error("Error decode request body: %v", err)
+		ctx.Error(http.StatusBadRequest, "Error decode request body")
+		return nil, false
+	}
+	if ids.WorkflowRunBackendID != strconv.FormatInt(task.Job.RunID, 10) {
+		log.Error("Error runID not match")
+		ctx.Error(http.StatusBadRequest, "run-id does not match")
+		return nil, false
+	}
+	return task, true
+}
+
+func (r artifactV4Routes) buildSignature(endpoint, expires, artifactName string, taskID int64) []byte {
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	_, _ = fmt.Fprintf(mac, "%s\n%s\n%s\n%d", endpoint, expires, artifactName, taskID)
+	return mac.Sum(nil)
+}
+
+// buildArtifactURL returns a signed url of the endpoint for the artifact of the run of a task
+func (r artifactV4Routes) buildArtifactURL(endpoint, artifactName string, taskID int64) string {
+	expires := strconv.FormatInt(time.Now().Add(artifactV4URLLifetime).Unix(), 10)
+	sig := r.buildSignature(endpoint, expires, artifactName, taskID)
+	return strings.TrimSuffix(setting.AppURL, "/") + strings.TrimSuffix(r.prefix, "/") + "/" + endpoint +
+		"?sig=" + base64.URLEncoding.EncodeToString(sig) +
+		"&expires=" + expires +
+		"&artifactName=" + url.QueryEscape(artifactName) +
+		"&taskID=" + strconv.FormatInt(taskID, 10)
+}
+
+// verifySignature checks the signature of a url built by buildArtifactURL and returns its task and artifact name
+func (r artifactV4Routes) verifySignature(ctx *context.Context, endpoint string) (*actions.ActionTask, string, bool) {
+	query := ctx.Req.URL.Query()
+	expires := query.Get("expires")
+	artifactName := query.Get("artifactName")
+	taskID, err := strconv.ParseInt(query.Get("taskID"), 10, 64)
+	if err != nil {
+		ctx.Error(http.StatusUnauthorized, "Error invalid task id")
+		return nil, "", false
+	}
+	sig, err := base64.URLEncoding.DecodeString(query.Get("sig"))
+	if err != nil || !hmac.Equal(sig, r.buildSignature(endpoint, expires, artifactName, taskID)) {
+		ctx.Error(http.StatusUnauthorized, "Error invalid signature")
+		return nil, "", false
+	}
+	expiresUnix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresUnix {
+		ctx.Error(http.StatusUnauthorized, "Error url has expired")
+		return nil, "", false
+	}
+
+	task, err := actions.GetTaskByID(ctx, taskID)
+	if err != nil {
+		log.Error("Error runner api getting task: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error runner api getting task")
+		return nil, "", false
+	}
+	if err := task.LoadJob(ctx); err != nil {
+		log.Error("Error runner api getting job: %v", err)
+		ctx.Error(http.StatusInternalServerError, "Error runner api getting job")
+		return nil, "", false
+	}
+	return task, artifactName, true
+}
+
+func (r artifactV4Routes) getArtifactByName(ctx *context.Context, runID int64, name string) (*actions.ActionArtifact, bool) {
+	artifact, err := actions.GetArtifactByArtifactName(ctx, runID, name)
+	if errors.Is(err, util.ErrNotExist) {
+		ctx.Error(http.StatusNotFound, "artifact not found")
+		return nil, false
+	} else if err != nil {
+		log.Error("Error getting artifact: %v", err)
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return nil, false
+	}
+	return artifact, true
+}
+
+func (r artifactV4Routes) createArtifact(ctx *context.Context) {
+	var req createArtifactV4Request
+	task, ok := r.decodeRequest(ctx, &req, &req.artifactV4Request)
+	if !ok {
+		return
+	}
+	if req.Name == "" || strings.ContainsAny(req.Name, invalidArtifactNameChars) {
+		log.Error("Error checking artifact name contains invalid character")
+		ctx.Error(http.StatusBadRequest, "Error invalid artifact name")
+		return
+	}
+
+	retentionDays := setting.Actions.ArtifactRetentionDays
+	if req.ExpiresAt != nil {
+		retentionDays = int64(math.Ceil(time.Until(*req.ExpiresAt).Hours() / 24))
+		if retentionDays < 1 {
+			retentionDays = 1
+		}
+	}
+	artifact, err := actions.CreateArtifact(ctx, task, req.Name, retentionDays)
+	if err != nil {
+		log.Error("Error creating artifact: %v", err)
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	// an artifact of the same name is replaced when it is committed
+	artifact.ArtifactPath = req.Name + ".zip"
+	artifact.ContentEncoding = actions.ArtifactContentEncodingZip
+	artifact.Status = actions.ArtifactStatusUploadPending
+	if err := actions.UpdateArtifactByID(ctx, artifact.ID, artifact); err != nil {
+		log.Error("Error updating artifact: %v", err)
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := createArtifactV4Response{
+		OK:              true,
+		SignedUploadURL: r.buildArtifactURL("UploadArtifact", artifact.ArtifactName, task.ID),
+	}
+	log.Debug("[artifact] get upload url: %s, artifact id: %d", resp.SignedUploadURL, artifact.ID)
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (r artifactV4Routes) blockDir(artifact *actions.ActionArtifact) string {
+	return fmt.Sprintf("tmpv4%d/%d", artifact.RunID, artifact.ID)
+}
+
+func (r artifactV4Routes) blockPath(artifact *actions.ActionArtifact, blockID string) string {
+	// block ids are base64 encoded by the clients, hex encoding them makes them safe paths
+	return r.blockDir(artifact) + "/" + hex.EncodeToString([]byte(blockID)) + ".block"
+}
+
+func (r artifactV4Routes) uploadArtifact(ctx *context.Context) {
+	task, artifactName, ok := r.verifySignature(ctx, "UploadArtifact")
+	if !ok {
+		return
+	}
+	if task.Status != actions.StatusRunning {
+		ctx.Error(http.StatusUnauthorized, "Error task is not running")
+		return
+	}
+	artifact, ok := r.getArtifactByName(ctx, task.Job.RunID, artifactName)
+	if !ok {
+		return
+	}
+
+	switch comp := ctx.Req.URL.Query().Get("comp"); comp {
+	case "block":
+		blockID := ctx.Req.URL.Query().Get("blockid")
+		if blockID == "" {
+			ctx.Error(http.StatusBadRequest, "Error missing block id")
+			return
+		}
+		if len(blockID) > maxArtifactV4BlockIDLength {
+			ctx.Error(http.StatusBadRequest, "Error block id is too long")
+			return
+		}
+		if _, err := r.fs.Save(r.blockPath(artifact, blockID), ctx.Req.Body, ctx.Req.ContentLength); err != nil {
+			log.Error("Error saving block: %v", err)
+			ctx.Error(http.StatusInternalServerError, err.Error())
+			return
+		}
+	case "blocklist":
+		var blockList blockListV4
+		if err := xml.NewDecoder(ctx.Req.Body).Decode(&blockList); err != nil {
+			log.Error("Error decode block list: %v", err)
+			ctx.Error(http.StatusBadRequest, "Error decode block list")
+			return
+		}
+		if err := r.commitBlocks(ctx, artifact, &blockList); err != nil {
+			log.Error("Error committing blocks: %v", err)
+			if errors.Is(err, util.ErrNotExist) || errors.Is(err, util.ErrInvalidArgument) {
+				ctx.Error(http.StatusBadRequest, err.Error())
+			} else {
+				ctx.Error(http.StatusInternalServerError, err.Error())
+			}
+			return
+		}
+	case "":
+		// the whole archive in one request
+		if err := r.saveArtifactFile(ctx, artifact, ctx.Req.Body); err != nil {
+			log.Error("Error saving artifact: %v", err)
+			ctx.Error(http.StatusInternalServerError, err.Error())
+			return
+		}
+	default:
+		ctx.Error(http.StatusBadRequest, fmt.Sprintf("unsupported comp: %s", comp))
+		return
+	}
+
+	ctx.Status(http.StatusCreated)
+}
+
+// commitBlocks merges the uploaded blocks of an artifact in the order of the block list and deletes all its blocks
+func (r artifactV4Routes) commitBlocks(ctx *context.Context, artifact *actions.ActionArtifact, blockList *blockListV4) error {
+	readers := make([]io.Reader, 0, len(blockList.Blocks))
+	closers := make([]io.Closer, 0, len(blockList.Blocks))
+	defer func() {
+		for _, c := range closers {
+			c.Close()
+		}
+	}()
+	for _, block := range blockList.Blocks {
+		if len(block.ID) > maxArtifactV4BlockIDLength {
+			return util.NewInvalidArgumentErrorf("block id %q is too long", block.ID)
+		}
+		reader, err := r.fs.Open(r.blockPath(artifact, block.ID))
+		if err != nil {
+			return fmt.Errorf("block %s wasn't uploaded: %w", block.ID, util.ErrNotExist)
+		}
+		readers = append(readers, reader)
+		closers = append(closers, reader)
+	}
+
+	if err := r.saveArtifactFile(ctx, artifact, io.MultiReader(readers...)); err != nil {
+		return err
+	}
+
+	// drop blocks, including the ones which weren't committed
+	return r.fs.IterateObjects(r.blockDir(artifact), func(path string, obj storage.Object) error {
+		return r.fs.Delete(path)
+	})
+}
+
+// saveArtifactFile stores the archive of an artifact, replacing a previously uploaded one
+func (r artifactV4Routes) saveArtifactFile(ctx *context.Context, artifact *actions.ActionArtifact, reader io.Reader) error {
+	storagePath := fmt.Sprintf("%d/%d/%d.zip", artifact.RunID%255, artifact.ID%255, time.Now().UnixNano())
+	written, err := r.fs.Save(storagePath, reader, -1)
+	if err != nil {
+		return fmt.Errorf("save artifact file error: %w", err)
+	}
+
+	if artifact.StoragePath != "" {
+		if err := r.fs.Delete(artifact.StoragePath); err != nil {
+			log.Error("Error deleting replaced artifact file: %s, %v", artifact.StoragePath, err)
+		}
+	}
+
+	log.Debug("[artifact] save artifact: %d, %s", artifact.ID, storagePath)
+	artifact.StoragePath = storagePath
+	artifact.FileSize = written
+	artifact.FileCompressedSize = written
+	return actions.UpdateArtifactByID(ctx, artifact.ID, artifact)
+}
+
+func (r artifactV4Routes) finalizeArtifact(ctx *context.Context) {
+	var req finalizeArtifactV4Request
+	task, ok := r.decodeRequest(ctx, &req, &req.artifactV4Request)
+	if !ok {
+		return
+	}
+	artifact, ok := r.getArtifactByName(ctx, task.Job.RunID, req.Name)
+	if !ok {
+		return
+	}
+	if artifact.StoragePath == "" {
+		ctx.Error(http.StatusBadRequest, "artifact wasn't uploaded")
+		return
+	}
+	if req.Size != artifact.FileSize {
+		log.Error("Error artifact size doesn't match, uploaded: %d, expected: %d", artifact.FileSize, req.Size)
+		ctx.Error(http.StatusBadRequest, "artifact size doesn't match")
+		return
+	}
+
+	artifact.Status = actions.ArtifactStatusUploadConfirmed
+	if err := actions.UpdateArtifactByID(ctx, artifact.ID, artifact); err != nil {
+		log.Error("Error updating artifact: %v", err)
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, finalizeArtifactV4Response{
+		OK:         true,
+		ArtifactID: artifact.ID,
+	})
+}
+
+func (r artifactV4Routes) listArtifacts(ctx *context.Context) {
+	var req listArtifactsV4Request
+	task, ok := r.decodeRequest(ctx, &req, &req.artifactV4Request)
+	if !ok {
+		return
+	}
+
+	artifacts, err := actions.ListUploadedArtifactsByRunID(ctx, task.Job.RunID)
+	if err != nil {
+		log.Error("Error getting artifacts: %v", err)
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	resp := listArtifactsV4Response{
+		Artifacts: make([]*listArtifactsV4ResponseItem, 0, len(artifacts)),
+	}
+	for _, a := range artifacts {
+		// artifacts uploaded with the v3 protocol aren't zip archives
+		if a.ContentEncoding != actions.ArtifactContentEncodingZip {
+			continue
+		}
+		if (req.NameFilter != "" && a.ArtifactName != req.NameFilter) || (req.IDFilter != 0 && a.ID != req.IDFilter) {
+			continue
+		}
+		resp.Artifacts = append(resp.Artifacts, &listArtifactsV4ResponseItem{
+			WorkflowRunBackendID:    req.WorkflowRunBackendID,
+			WorkflowJobRunBackendID: req.WorkflowJobRunBackendID,
+			DatabaseID:              a.ID,
+			Name:                    a.ArtifactName,
+			Size:                    a.FileSize,
+			CreatedAt:               a.CreatedUnix.AsLocalTime(),
+		})
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
+
+func (r artifactV4Routes) getSignedArtifactURL(ctx *context.Context) {
+	var req getSignedArtifactURLV4Request
+	task, ok := r.decodeRequest(ctx, &req, &req.artifactV4Request)
+	if !ok {
+		return
+	}
+	artifact, ok := r.getArtifactByName(ctx, task.Job.RunID, req.Name)
+	if !ok {
+		return
+	}
+	if artifact.Status != actions.ArtifactStatusUploadConfirmed {
+		ctx.Error(http.StatusNotFound, "artifact not found")
+		return
+	}
+
+	ctx.JSON(http.StatusOK, getSignedArtifactURLV4Response{
+		SignedURL: r.buildArtifactURL("DownloadArtifact", artifact.ArtifactName, task.ID),
+	})
+}
+
+func (r artifactV4Routes) downloadArtifact(ctx *context.Context) {
+	task, artifactName, ok := r.verifySignature(ctx, "DownloadArtifact")
+	if !ok {
+		return
+	}
+	artifact, ok := r.getArtifactByName(ctx, task.Job.RunID, artifactName)
+	if !ok {
+		return
+	}
+	if artifact.Status != actions.ArtifactStatusUploadConfirmed {
+		ctx.Error(http.StatusNotFound, "artifact not found")
+		return
+	}
+
+	fd, err := r.fs.Open(artifact.StoragePath)
+	if err != nil {
+		log.Error("Error opening file: %v", err)
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+	defer fd.Close()
+
+	// ServeContent answers range requests
+	ctx.ServeContent(fd, &context.ServeHeaderOptions{
+		Filename:     artifact.ArtifactName + ".zip",
+		LastModified: artifact.CreatedUnix.AsLocalTime(),
+	})
+}
+
+func (r artifactV4Routes) deleteArtifact(ctx *context.Context) {
+	var req deleteArtifactV4Request
+	task, ok := r.decodeRequest(ctx, &req, &req.artifactV4Request)
+	if !ok {
+		return
+	}
+	artifact, ok := r.getArtifactByName(ctx, task.Job.RunID, req.Name)
+	if !ok {
+		return
+	}
+
+	if artifact.StoragePath != "" {
+		if err := r.fs.Delete(artifact.StoragePath); err != nil {
+			log.Error("Error deleting artifact file: %v", err)
+			ctx.Error(http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+	if err := actions.DeleteArtifactByID(ctx, artifact.ID); err != nil {
+		log.Error("Error deleting artifact: %v", err)
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deleteArtifactV4Response{
+		OK:         true,
+		ArtifactID: artifact.ID,
+	})
+}
@@ -84,6 +84,14 @@ func getSecretsOfTask(ctx context.Context, task *actions_model.ActionTask) map[s
 	if _, ok := secrets["GITEA_TOKEN"]; !ok {
 		secrets["GITEA_TOKEN"] = task.Token
 	}
+	if _, ok := secrets["DOCKER_AUTH_CONFIG"]; !ok && setting.Actions.RegistryCredentials && setting.Packages.Enabled {
+		if authConfig, err := actions.RegistryAuthConfig(task.Token); err != nil {
+			log.Error("registry auth config of task %v: %v", task.ID, err)
+			// go on
+		} else {
+			secrets["DOCKER_AUTH_CONFIG"] = authConfig
+		}
+	}
 
 	return secrets
 }
@@ -107,6 +115,11 @@ func generateTaskContext(t *actions_model.ActionTask) *structpb.Struct {
 		refType = "tag"
 	}
 
+	giteaRuntimeToken, err := actions.CreateAuthorizationToken(t.ID, t.Job.RunID, t.JobID)
+	if err != nil {
+		log.Error("actions.CreateAuthorizationToken failed: %v", err)
+	}
+
 	taskContext, _ := structpb.NewStruct(map[string]interface{}{
 		// standard contexts, see https://docs.github.com/en/actions/learn-github-actions/contexts#github-context
 		"action":            "",                                                   // string, The name of the action currently running, or the id of a step. GitHub removes special characters, and uses the name __run when the current step runs a script without an id. If you use the same action more than once in the same job, the name will include a suffix with the sequence number with underscore before it. For example, the first script you run will have the name __run, and the second script will be named __run_2. Similarly, the second invocation of actions/checkout will be actionscheckout2.
@@ -146,6 +159,7 @@ func generateTaskContext(t *actions_model.ActionTask) *structpb.Struct {
 
 		// additional contexts
 		"gitea_default_actions_url": setting.Actions.DefaultActionsURL,
+		"gitea_runtime_token":       giteaRuntimeToken,
 	})
 
 	return taskContext
@@ -474,6 +474,7 @@ func CommonRoutes(ctx gocontext.Context) *web.Route {
 			r.Group("/-/v1/search", func() {
 				r.Get("", npm.PackageSearch)
 			})
+			r.Post("/-/npm/v1/security/advisories/bulk", npm.BulkAdvisories)
 		}, reqPackageAccess(perm.AccessModeRead))
 		r.Group("/pub", func() {
 			r.Group("/api/packages", func() {
@@ -6,6 +6,7 @@ package container
 import (
 	"net/http"
 
+	actions_model "code.gitea.io/gitea/models/actions"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/services/auth"
@@ -21,7 +22,7 @@ func (a *Auth) Name() string {
 // Verify extracts the user from the Bearer token
 // If it's an anonymous session a ghost user is returned
 func (a *Auth) Verify(req *http.Request, w http.ResponseWriter, store auth.DataStore, sess auth.SessionStore) (*user_model.User, error) {
-	uid, err := packages.ParseAuthorizationToken(req)
+	uid, taskID, err := packages.ParseAuthorizationToken(req)
 	if err != nil {
 		log.Trace("ParseAuthorizationToken: %v", err)
 		return nil, err
@@ -31,6 +32,23 @@ func (a *Auth) Verify(req *http.Request, w http.ResponseWriter, store auth.DataS
 		return nil, nil
 	}
 
+	if taskID != 0 {
+		// the token of an Actions task is only valid while the task is running
+		task, err := actions_model.GetTaskByID(req.Context(), taskID)
+		if err != nil {
+			log.Error("GetTaskByID:  %v", err)
+			return nil, err
+		}
+		if task.Status != actions_model.StatusRunning {
+			return nil, nil
+		}
+
+		store.GetData()["IsActionsToken"] = true
+		store.GetData()["ActionsTaskID"] = task.ID
+
+		return user_model.NewActionsUser(), nil
+	}
+
 	u, err := user_model.GetPossibleUserByID(req.Context(), uid)
 	if err != nil {
 		log.Error("GetPossibleUserByID:  %v", err)
@@ -31,7 +31,7 @@ func saveAsPackageBlob(hsr packages_module.HashedSizeReader, pci *packages_servi
 
 	exists := false
 
-	contentStore := packages_module.NewContentStore()
+	contentStore := packages_service.NewContentStoreOfOwner(pci.Owner.ID)
 
 	uploadVersion, err := getOrCreateUploadVersion(&pci.PackageInfo)
 	if err != nil {
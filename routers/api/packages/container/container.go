@@ -139,13 +139,20 @@ func DetermineSupport(ctx *context.Context) {
 
 // Authenticate creates a token for the current user
 // If the current user is anonymous, the ghost user is used
+// If the current user is an Actions task, the token is bound to the task
 func Authenticate(ctx *context.Context) {
 	u := ctx.Doer
 	if u == nil {
 		u = user_model.NewGhostUser()
 	}
 
-	token, err := packages_service.CreateAuthorizationToken(u)
+	var token string
+	var err error
+	if ctx.Data["IsActionsToken"] == true {
+		token, err = packages_service.CreateActionsAuthorizationToken(ctx.Data["ActionsTaskID"].(int64))
+	} else {
+		token, err = packages_service.CreateAuthorizationToken(u)
+	}
 	if err != nil {
 		apiError(ctx, http.StatusInternalServerError, err)
 		return
@@ -202,6 +209,11 @@ func InitiateUploadBlob(ctx *context.Context) {
 			Repository: from,
 			Digest:     mount,
 		})
+		// a blob can only be mounted if its content is in the storage region of the owner already
+		if blob != nil && len(setting.StorageRegions) > 0 &&
+			packages_service.NewContentStoreOfOwner(ctx.Package.Owner.ID).Has(packages_module.BlobHash256Key(blob.Blob.HashSHA256)) != nil {
+			blob = nil
+		}
 		if blob != nil {
 			if err := mountBlob(&packages_service.PackageInfo{Owner: ctx.Package.Owner, Name: image}, blob.Blob); err != nil {
 				apiError(ctx, http.StatusInternalServerError, err)
@@ -740,7 +752,11 @@ func workaroundGetContainerBlob(ctx *context.Context, opts *container_model.Blob
 		return nil, err
 	}
 
-	err = packages_module.NewContentStore().Has(packages_module.BlobHash256Key(blob.Blob.HashSHA256))
+	contentStore, err := packages_service.NewContentStoreOfFile(ctx, blob.File)
+	if err != nil {
+		return nil, err
+	}
+	err = contentStore.Has(packages_module.BlobHash256Key(blob.Blob.HashSHA256))
 	if err != nil {
 		if errors.Is(err, util.ErrNotExist) || errors.Is(err, os.ErrNotExist) {
 			log.Debug("Package registry inconsistent: blob %s does not exist on file system", blob.Blob.HashSHA256)
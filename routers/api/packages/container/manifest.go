@@ -107,7 +107,7 @@ func processImageManifest(mci *manifestCreationInfo, buf *packages_module.Hashed
 			return err
 		}
 
-		configReader, err := packages_module.NewContentStore().Get(packages_module.BlobHash256Key(configDescriptor.Blob.HashSHA256))
+		configReader, err := packages_service.NewContentStoreOfOwner(mci.Owner.ID).Get(packages_module.BlobHash256Key(configDescriptor.Blob.HashSHA256))
 		if err != nil {
 			return err
 		}
@@ -117,6 +117,7 @@ func processImageManifest(mci *manifestCreationInfo, buf *packages_module.Hashed
 		if err != nil {
 			return err
 		}
+		metadata.ApplyAnnotations(manifest.Annotations)
 
 		blobReferences := make([]*blobReference, 0, 1+len(manifest.Layers))
 
@@ -165,7 +166,7 @@ func processImageManifest(mci *manifestCreationInfo, buf *packages_module.Hashed
 		removeBlob := false
 		defer func() {
 			if removeBlob {
-				contentStore := packages_module.NewContentStore()
+				contentStore := packages_service.NewContentStoreOfOwner(mci.Owner.ID)
 				if err := contentStore.Delete(packages_module.BlobHash256Key(pb.HashSHA256)); err != nil {
 					log.Error("Error deleting package blob from content store: %v", err)
 				}
@@ -219,6 +220,7 @@ func processImageManifestIndex(mci *manifestCreationInfo, buf *packages_module.H
 			Type:      container_module.TypeOCI,
 			Manifests: make([]*container_module.Manifest, 0, len(index.Manifests)),
 		}
+		metadata.ApplyAnnotations(index.Annotations)
 
 		for _, manifest := range index.Manifests {
 			if !isImageManifestMediaType(manifest.MediaType) {
@@ -269,7 +271,7 @@ func processImageManifestIndex(mci *manifestCreationInfo, buf *packages_module.H
 		removeBlob := false
 		defer func() {
 			if removeBlob {
-				contentStore := packages_module.NewContentStore()
+				contentStore := packages_service.NewContentStoreOfOwner(mci.Owner.ID)
 				if err := contentStore.Delete(packages_module.BlobHash256Key(pb.HashSHA256)); err != nil {
 					log.Error("Error deleting package blob from content store: %v", err)
 				}
@@ -455,14 +457,14 @@ func createManifestBlob(ctx context.Context, mci *manifestCreationInfo, pv *pack
 	// FIXME: Workaround to be removed in v1.20
 	// https://github.com/go-gitea/gitea/issues/19586
 	if exists {
-		err = packages_module.NewContentStore().Has(packages_module.BlobHash256Key(pb.HashSHA256))
+		err = packages_service.NewContentStoreOfOwner(mci.Owner.ID).Has(packages_module.BlobHash256Key(pb.HashSHA256))
 		if err != nil && (errors.Is(err, util.ErrNotExist) || errors.Is(err, os.ErrNotExist)) {
 			log.Debug("Package registry inconsistent: blob %s does not exist on file system", pb.HashSHA256)
 			exists = false
 		}
 	}
 	if !exists {
-		contentStore := packages_module.NewContentStore()
+		contentStore := packages_service.NewContentStoreOfOwner(mci.Owner.ID)
 		if err := contentStore.Save(packages_module.BlobHash256Key(pb.HashSHA256), buf, buf.Size()); err != nil {
 			log.Error("Error saving package blob in content store: %v", err)
 			return nil, false, "", err
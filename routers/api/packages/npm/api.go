@@ -54,6 +54,15 @@ func createPackageMetadataVersion(registryURL string, pd *packages_model.Package
 
 	metadata := pd.Metadata.(*npm_module.Metadata)
 
+	deprecated := ""
+	if pd.Version.IsDeprecated {
+		deprecated = pd.Version.DeprecationMessage
+		if deprecated == "" {
+			// npm only treats a non-empty message as deprecation
+			deprecated = "This version is deprecated"
+		}
+	}
+
 	return &npm_module.PackageMetadataVersion{
 		ID:                   fmt.Sprintf("%s@%s", pd.Package.Name, pd.Version.Version),
 		Name:                 pd.Package.Name,
@@ -68,6 +77,7 @@ func createPackageMetadataVersion(registryURL string, pd *packages_model.Package
 		OptionalDependencies: metadata.OptionalDependencies,
 		Readme:               metadata.Readme,
 		Bin:                  metadata.Bin,
+		Deprecated:           deprecated,
 		Dist: npm_module.PackageDistribution{
 			Shasum:    pd.Files[0].Blob.HashSHA1,
 			Integrity: "sha512-" + base64.StdEncoding.EncodeToString(hashBytes),
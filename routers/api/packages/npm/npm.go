@@ -5,6 +5,7 @@ package npm
 
 import (
 	"bytes"
+	"compress/gzip"
 	"errors"
 	"fmt"
 	"io"
@@ -17,12 +18,14 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/json"
 	packages_module "code.gitea.io/gitea/modules/packages"
 	npm_module "code.gitea.io/gitea/modules/packages/npm"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/api/packages/helper"
 	packages_service "code.gitea.io/gitea/services/packages"
+	npm_service "code.gitea.io/gitea/services/packages/npm"
 
 	"github.com/hashicorp/go-version"
 )
@@ -169,6 +172,11 @@ func UploadPackage(ctx *context.Context) {
 		return
 	}
 
+	if npmPackage.Deprecations != nil {
+		updatePackageDeprecations(ctx, npmPackage)
+		return
+	}
+
 	repo, err := repo_model.GetRepositoryByURL(ctx, npmPackage.Metadata.Repository.URL)
 	if err == nil {
 		canWrite := repo.OwnerID == ctx.Doer.ID
@@ -250,6 +258,32 @@ func UploadPackage(ctx *context.Context) {
 	ctx.Status(http.StatusCreated)
 }
 
+// updatePackageDeprecations updates the deprecations of the existing versions of the package
+func updatePackageDeprecations(ctx *context.Context, npmPackage *npm_module.Package) {
+	pvs, err := packages_model.GetVersionsByPackageName(ctx, ctx.Package.Owner.ID, packages_model.TypeNpm, npmPackage.Name)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+	if len(pvs) == 0 {
+		apiError(ctx, http.StatusNotFound, packages_model.ErrPackageNotExist)
+		return
+	}
+
+	for _, pv := range pvs {
+		message, ok := npmPackage.Deprecations[pv.Version]
+		if !ok || (pv.IsDeprecated == (message != "") && pv.DeprecationMessage == message) {
+			continue
+		}
+		if err := packages_model.SetVersionDeprecation(ctx, pv, message != "", message); err != nil {
+			apiError(ctx, http.StatusInternalServerError, err)
+			return
+		}
+	}
+
+	ctx.Status(http.StatusOK)
+}
+
 // DeletePreview does nothing
 // The client tells the server what package version it knows about after deleting a version.
 func DeletePreview(ctx *context.Context) {
@@ -469,3 +503,34 @@ func PackageSearch(ctx *context.Context) {
 
 	ctx.JSON(http.StatusOK, resp)
 }
+
+// maxBulkAdvisoriesRequestSize limits the size of the uncompressed request of npm audit
+const maxBulkAdvisoriesRequestSize = 10 * 1024 * 1024
+
+// BulkAdvisories returns the advisories affecting the versions of the packages sent by npm audit
+func BulkAdvisories(ctx *context.Context) {
+	var body io.Reader = ctx.Req.Body
+	if ctx.Req.Header.Get("Content-Encoding") == "gzip" {
+		gz, err := gzip.NewReader(ctx.Req.Body)
+		if err != nil {
+			apiError(ctx, http.StatusBadRequest, err)
+			return
+		}
+		defer gz.Close()
+		body = gz
+	}
+
+	var request map[string][]string
+	if err := json.NewDecoder(io.LimitReader(body, maxBulkAdvisoriesRequestSize)).Decode(&request); err != nil {
+		apiError(ctx, http.StatusBadRequest, err)
+		return
+	}
+
+	advisories, err := npm_service.BulkAdvisories(ctx, ctx.Package.Owner.ID, request)
+	if err != nil {
+		apiError(ctx, http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, advisories)
+}
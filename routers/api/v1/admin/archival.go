@@ -0,0 +1,52 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListUpcomingArchivals lists the inactive repositories which are going to be archived
+func ListUpcomingArchivals(ctx *context.APIContext) {
+	// swagger:operation GET /admin/archivals admin adminListUpcomingArchivals
+	// ---
+	// summary: List the inactive repositories which are going to be archived, soonest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UpcomingArchivalList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	listOptions := utils.GetListOptions(ctx)
+	notices, count, err := repo_model.FindArchiveNotices(ctx, repo_model.FindArchiveNoticesOptions{ListOptions: listOptions})
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result, err := convert.ToUpcomingArchivals(notices)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
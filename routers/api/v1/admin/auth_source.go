@@ -0,0 +1,82 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	auth_service "code.gitea.io/gitea/services/auth"
+)
+
+func toAuthSourceMigrationReport(dryRun bool, report *auth_service.MigrateUsersReport) *api.AuthSourceMigrationReport {
+	result := &api.AuthSourceMigrationReport{
+		DryRun:            dryRun,
+		Migrated:          make([]*api.MigratedAuthSourceUser, 0, len(report.Migrated)),
+		UnmatchedAccounts: make([]string, 0, len(report.UnmatchedAccounts)),
+		UnmatchedUsers:    make([]string, 0, len(report.UnmatchedUsers)),
+		Conflicts:         make([]string, 0, len(report.Conflicts)),
+	}
+	for _, u := range report.Migrated {
+		result.Migrated = append(result.Migrated, &api.MigratedAuthSourceUser{Username: u.Name, LoginName: u.LoginName})
+	}
+	result.UnmatchedAccounts = append(result.UnmatchedAccounts, report.UnmatchedAccounts...)
+	result.UnmatchedUsers = append(result.UnmatchedUsers, report.UnmatchedUsers...)
+	result.Conflicts = append(result.Conflicts, report.Conflicts...)
+	return result
+}
+
+// MigrateAuthSourceUsers migrates the users of an auth source to another one
+func MigrateAuthSourceUsers(ctx *context.APIContext) {
+	// swagger:operation POST /admin/auth_sources/migrate_users admin adminMigrateAuthSourceUsers
+	// ---
+	// summary: Migrate the users of an auth source to another auth source
+	// description: Matches the accounts of the new auth source with the users of the old one by email or username,
+	//   sets their login name and removes their local password. Nothing is changed for a dry run.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/MigrateAuthSourceUsersOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AuthSourceMigrationReport"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.MigrateAuthSourceUsersOption)
+
+	opts := &auth_service.MigrateUsersOptions{
+		FromSourceID: form.FromSourceID,
+		ToSourceID:   form.ToSourceID,
+		MatchBy:      auth_service.MigrateUsersMatch(form.MatchBy),
+		Accounts:     make([]*auth_service.MigrateUsersAccount, 0, len(form.Accounts)),
+		DryRun:       form.DryRun,
+	}
+	for _, account := range form.Accounts {
+		opts.Accounts = append(opts.Accounts, &auth_service.MigrateUsersAccount{Match: account.Match, LoginName: account.LoginName})
+	}
+
+	report, err := auth_service.MigrateUsers(ctx, opts)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || auth_model.IsErrSourceNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "MigrateUsers", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, toAuthSourceMigrationReport(form.DryRun, report))
+}
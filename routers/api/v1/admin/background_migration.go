@@ -0,0 +1,51 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/migrations"
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ListBackgroundMigrations lists the progress of the background migrations
+func ListBackgroundMigrations(ctx *context.APIContext) {
+	// swagger:operation GET /admin/background_migrations admin adminListBackgroundMigrations
+	// ---
+	// summary: List the progress of the background migrations which backfill data after an upgrade
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BackgroundMigrationList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	bms, err := system_model.GetBackgroundMigrations(ctx)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.BackgroundMigration, 0, len(bms))
+	for _, bm := range bms {
+		var description string
+		if b := migrations.GetBackfill(bm.Name); b != nil {
+			description = b.Description
+		}
+		result = append(result, &api.BackgroundMigration{
+			Name:        bm.Name,
+			Description: description,
+			Total:       bm.Total,
+			Processed:   bm.Processed,
+			IsFinished:  bm.IsFinished,
+			LastError:   bm.LastError,
+			Updated:     bm.UpdatedUnix.AsTime(),
+		})
+	}
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,325 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	chatops_model "code.gitea.io/gitea/models/chatops"
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	chatops_service "code.gitea.io/gitea/services/chatops"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListChatOpsCommands lists the slash commands registered by administrators
+func ListChatOpsCommands(ctx *context.APIContext) {
+	// swagger:operation GET /admin/chatops/commands admin adminListChatOpsCommands
+	// ---
+	// summary: List the webhook-backed slash commands
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ChatOpsCommandList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	listOptions := utils.GetListOptions(ctx)
+	cmds, count, err := chatops_model.FindCommands(ctx, listOptions)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.ChatOpsCommand, 0, len(cmds))
+	for _, cmd := range cmds {
+		result = append(result, convert.ToChatOpsCommand(cmd))
+	}
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetChatOpsCommand returns a slash command
+func GetChatOpsCommand(ctx *context.APIContext) {
+	// swagger:operation GET /admin/chatops/commands/{id} admin adminGetChatOpsCommand
+	// ---
+	// summary: Get a webhook-backed slash command
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the command
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ChatOpsCommand"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	cmd := getChatOpsCommand(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToChatOpsCommand(cmd))
+}
+
+// CreateChatOpsCommand registers a slash command
+func CreateChatOpsCommand(ctx *context.APIContext) {
+	// swagger:operation POST /admin/chatops/commands admin adminCreateChatOpsCommand
+	// ---
+	// summary: Register a slash command which is executed by calling a webhook
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateChatOpsCommandOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ChatOpsCommand"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateChatOpsCommandOption)
+
+	if chatops_service.IsBuiltinCommand(form.Name) {
+		ctx.Error(http.StatusConflict, "", "a built-in command with this name exists")
+		return
+	}
+	accessMode := perm.AccessModeWrite
+	if form.AccessMode != "" {
+		accessMode = perm.ParseAccessMode(form.AccessMode)
+		if accessMode == perm.AccessModeNone {
+			ctx.Error(http.StatusUnprocessableEntity, "", "access_mode must be read, write or admin")
+			return
+		}
+	}
+
+	cmd := &chatops_model.Command{
+		Name:        form.Name,
+		Description: form.Description,
+		URL:         form.URL,
+		Secret:      form.Secret,
+		AccessMode:  accessMode,
+		IsActive:    form.Active == nil || *form.Active,
+	}
+	if err := chatops_model.CreateCommand(ctx, cmd); err != nil {
+		if chatops_model.IsErrCommandAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToChatOpsCommand(cmd))
+}
+
+// EditChatOpsCommand modifies a slash command
+func EditChatOpsCommand(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/chatops/commands/{id} admin adminEditChatOpsCommand
+	// ---
+	// summary: Update a webhook-backed slash command
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the command
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditChatOpsCommandOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ChatOpsCommand"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditChatOpsCommandOption)
+	cmd := getChatOpsCommand(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	cols := make([]string, 0, 5)
+	if form.Description != nil {
+		cmd.Description = *form.Description
+		cols = append(cols, "description")
+	}
+	if form.URL != nil {
+		if *form.URL == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "url must not be empty")
+			return
+		}
+		cmd.URL = *form.URL
+		cols = append(cols, "url")
+	}
+	if form.Secret != nil {
+		cmd.Secret = *form.Secret
+		cols = append(cols, "secret")
+	}
+	if form.AccessMode != nil {
+		cmd.AccessMode = perm.ParseAccessMode(*form.AccessMode)
+		if cmd.AccessMode == perm.AccessModeNone {
+			ctx.Error(http.StatusUnprocessableEntity, "", "access_mode must be read, write or admin")
+			return
+		}
+		cols = append(cols, "access_mode")
+	}
+	if form.Active != nil {
+		cmd.IsActive = *form.Active
+		cols = append(cols, "is_active")
+	}
+
+	if len(cols) > 0 {
+		if err := chatops_model.UpdateCommand(ctx, cmd, cols...); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, convert.ToChatOpsCommand(cmd))
+}
+
+// DeleteChatOpsCommand removes a slash command
+func DeleteChatOpsCommand(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/chatops/commands/{id} admin adminDeleteChatOpsCommand
+	// ---
+	// summary: Delete a webhook-backed slash command
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the command
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := chatops_model.DeleteCommandByID(ctx, ctx.ParamsInt64(":id")); err != nil {
+		if chatops_model.IsErrCommandNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListChatOpsAudit lists the executions of slash commands
+func ListChatOpsAudit(ctx *context.APIContext) {
+	// swagger:operation GET /admin/chatops/audit admin adminListChatOpsAudit
+	// ---
+	// summary: List the executions of slash commands, newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: repo_id
+	//   in: query
+	//   description: only executions in this repository
+	//   type: integer
+	//   format: int64
+	// - name: doer_id
+	//   in: query
+	//   description: only executions by this user
+	//   type: integer
+	//   format: int64
+	// - name: command
+	//   in: query
+	//   description: only executions of this command
+	//   type: string
+	// - name: status
+	//   in: query
+	//   description: only executions with this outcome
+	//   type: string
+	//   enum: [success, denied, failed]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ChatOpsAuditList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	opts := chatops_model.FindAuditOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.FormInt64("repo_id"),
+		DoerID:      ctx.FormInt64("doer_id"),
+		Command:     ctx.FormTrim("command"),
+		Status:      chatops_model.AuditStatus(ctx.FormTrim("status")),
+	}
+	audits, count, err := chatops_model.FindAudits(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.ChatOpsAudit, 0, len(audits))
+	for _, audit := range audits {
+		result = append(result, convert.ToChatOpsAudit(audit))
+	}
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+func getChatOpsCommand(ctx *context.APIContext) *chatops_model.Command {
+	cmd, err := chatops_model.GetCommandByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if chatops_model.IsErrCommandNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil
+	}
+	return cmd
+}
@@ -0,0 +1,141 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/connectivity"
+)
+
+func toConnectivityTestResult(r *connectivity.Report) *api.ConnectivityTestResult {
+	result := &api.ConnectivityTestResult{
+		Success: r.Success(),
+		Steps:   make([]*api.ConnectivityTestStep, 0, len(r.Steps)),
+	}
+	for _, step := range r.Steps {
+		result.Steps = append(result.Steps, &api.ConnectivityTestStep{
+			Name:     step.Name,
+			Status:   string(step.Status),
+			Message:  step.Message,
+			Duration: step.Duration.Milliseconds(),
+		})
+	}
+	return result
+}
+
+// SendTestMail sends a test mail with the configured mailer
+func SendTestMail(ctx *context.APIContext) {
+	// swagger:operation POST /admin/mail/test admin adminSendTestMail
+	// ---
+	// summary: Send a test mail and report the outcome of each mail provider which was tried
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/SendTestMailOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ConnectivityTestResult"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SendTestMailOption)
+	ctx.JSON(http.StatusOK, toConnectivityTestResult(connectivity.TestMail(ctx, form.Email)))
+}
+
+// TestAuthSource tests the connection to the provider of an OAuth2 authentication source
+func TestAuthSource(ctx *context.APIContext) {
+	// swagger:operation POST /admin/auth_sources/{id}/test admin adminTestAuthSource
+	// ---
+	// summary: Test an OAuth2 authentication source
+	// description: Discovers the token endpoint of the provider and exchanges an invalid authorization code there,
+	//   which the provider rejects but tells whether it accepted the client credentials. No user signs in.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the authentication source to test
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ConnectivityTestResult"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	source, err := auth_model.GetSourceByID(ctx.ParamsInt64(":id"))
+	if err != nil {
+		if auth_model.IsErrSourceNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSourceByID", err)
+		}
+		return
+	}
+	report, err := connectivity.TestOAuth2Source(ctx, source)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "TestOAuth2Source", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, toConnectivityTestResult(report))
+}
+
+// TestHook delivers a test event to a system or default webhook
+func TestHook(ctx *context.APIContext) {
+	// swagger:operation POST /admin/hooks/{id}/test admin adminTestHook
+	// ---
+	// summary: Deliver a signed test push event to a hook and report the response of the receiver
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook to test
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ConnectivityTestResult"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	hook, err := webhook.GetSystemOrDefaultWebhook(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if webhook.IsErrWebhookNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSystemOrDefaultWebhook", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, toConnectivityTestResult(connectivity.TestWebhook(ctx, hook, ctx.Doer)))
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// SetExternalGroupMembers replaces the members of an external group pushed through the API
+func SetExternalGroupMembers(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/external_groups/{group}/members admin adminSetExternalGroupMembers
+	// ---
+	// summary: Replace the members of an external group of the "api" source
+	// description: Allows an identity provider or a provisioning bridge, e.g. for SCIM, to push the members of its
+	//   groups. The teams which have the group mapped to them sync their members from it.
+	// consumes:
+	// - application/json
+	// parameters:
+	// - name: group
+	//   in: path
+	//   description: name of the group
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/ExternalGroupMembersOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.ExternalGroupMembersOption)
+
+	userIDs, err := user_model.GetUserIDsByNames(ctx, form.Usernames, false)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserIDsByNames", err)
+		}
+		return
+	}
+	if err := organization.ReplaceExternalGroupMembers(ctx, organization.ExternalGroupSourceAPI, ctx.Params(":group"), userIDs); err != nil {
+		ctx.Error(http.StatusInternalServerError, "ReplaceExternalGroupMembers", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -13,6 +13,7 @@ import (
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
 	webhook_service "code.gitea.io/gitea/services/webhook"
 )
 
@@ -53,6 +54,79 @@ func ListHooks(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, hooks)
 }
 
+// ListInstanceHooks list the webhooks of all repositories, users and organizations
+func ListInstanceHooks(ctx *context.APIContext) {
+	// swagger:operation GET /admin/webhooks admin adminListInstanceHooks
+	// ---
+	// summary: List the webhooks of all repositories, users and organizations and the system webhooks
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: only webhooks with a target url containing this keyword
+	//   type: string
+	// - name: type
+	//   in: query
+	//   description: only webhooks of this type
+	//   type: string
+	// - name: active
+	//   in: query
+	//   description: only active or inactive webhooks
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/InstanceHookList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	opts := &webhook.ListWebhookOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		Type:        ctx.FormTrim("type"),
+		URLKeyword:  ctx.FormTrim("q"),
+	}
+	if ctx.FormString("active") != "" {
+		opts.IsActive = util.OptionalBoolOf(ctx.FormBool("active"))
+	}
+
+	hooks, err := webhook.ListWebhooksByOpts(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	count, err := webhook.CountWebhooksByOpts(opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	hookIDs := make([]int64, 0, len(hooks))
+	for _, hook := range hooks {
+		hookIDs = append(hookIDs, hook.ID)
+	}
+	lastDeliveries, err := webhook.GetLastDeliveryTimes(ctx, hookIDs)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.InstanceHook, 0, len(hooks))
+	for _, hook := range hooks {
+		result = append(result, convert.ToInstanceHook(hook, lastDeliveries[hook.ID]))
+	}
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
 // GetHook get an organization's hook by id
 func GetHook(ctx *context.APIContext) {
 	// swagger:operation GET /admin/hooks/{id} admin adminGetHook
@@ -140,6 +214,39 @@ func EditHook(ctx *context.APIContext) {
 	utils.EditSystemHook(ctx, form, hookID)
 }
 
+// RotateHookSecret rotates the secret of a system hook
+func RotateHookSecret(ctx *context.APIContext) {
+	// swagger:operation POST /admin/hooks/{id}/secret/rotate admin adminRotateHookSecret
+	// ---
+	// summary: Rotate the secret of a hook
+	// description: The payloads are signed with both the new and the previous secret during the overlap.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RotateHookSecretOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookSecretRotation"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.RotateHookSecretOption)
+	utils.RotateSystemHookSecret(ctx, form, ctx.ParamsInt64(":id"))
+}
+
 // DeleteHook delete a system hook
 func DeleteHook(ctx *context.APIContext) {
 	// swagger:operation DELETE /admin/hooks/{id} admin adminDeleteHook
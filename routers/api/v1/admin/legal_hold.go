@@ -0,0 +1,302 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// ListLegalHolds lists the legal holds
+func ListLegalHolds(ctx *context.APIContext) {
+	// swagger:operation GET /admin/legal_holds admin adminListLegalHolds
+	// ---
+	// summary: List the legal holds of repositories and issues, newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: repo_id
+	//   in: query
+	//   description: only holds in this repository
+	//   type: integer
+	//   format: int64
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/LegalHoldList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	opts := issues_model.FindLegalHoldsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.FormInt64("repo_id"),
+	}
+	holds, count, err := issues_model.FindLegalHolds(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.LegalHold, 0, len(holds))
+	for _, h := range holds {
+		if err := h.LoadAttributes(ctx); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		result = append(result, convert.ToLegalHold(h))
+	}
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreateLegalHold places a legal hold on a repository or an issue
+func CreateLegalHold(ctx *context.APIContext) {
+	// swagger:operation POST /admin/legal_holds admin adminCreateLegalHold
+	// ---
+	// summary: Place a legal hold on a repository or an issue, held content can't be deleted until the hold is released
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateLegalHoldOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/LegalHold"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateLegalHoldOption)
+
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, form.Owner, form.Repo)
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	h := &issues_model.LegalHold{
+		RepoID:    repo.ID,
+		Reason:    form.Reason,
+		CreatorID: ctx.Doer.ID,
+		Repo:      repo,
+	}
+	if form.IssueIndex != 0 {
+		issue, err := issues_model.GetIssueByIndex(repo.ID, form.IssueIndex)
+		if err != nil {
+			if issues_model.IsErrIssueNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.InternalServerError(err)
+			}
+			return
+		}
+		h.IssueID = issue.ID
+		h.Issue = issue
+	}
+
+	if err := issues_model.CreateLegalHold(ctx, h); err != nil {
+		if errors.Is(err, util.ErrAlreadyExist) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToLegalHold(h))
+}
+
+// DeleteLegalHold releases a legal hold
+func DeleteLegalHold(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/legal_holds/{id} admin adminDeleteLegalHold
+	// ---
+	// summary: Release a legal hold
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the legal hold
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	h, err := issues_model.GetLegalHoldByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if issues_model.IsErrLegalHoldNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	if err := issues_model.DeleteLegalHold(ctx, h.ID); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListLegalRedactions lists the redactions
+func ListLegalRedactions(ctx *context.APIContext) {
+	// swagger:operation GET /admin/redactions admin adminListLegalRedactions
+	// ---
+	// summary: List the redactions of content revisions and attachments, newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: repo_id
+	//   in: query
+	//   description: only redactions in this repository
+	//   type: integer
+	//   format: int64
+	// - name: issue_id
+	//   in: query
+	//   description: only redactions of this issue and its comments
+	//   type: integer
+	//   format: int64
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/LegalRedactionList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	opts := issues_model.FindLegalRedactionsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.FormInt64("repo_id"),
+		IssueID:     ctx.FormInt64("issue_id"),
+	}
+	redactions, count, err := issues_model.FindLegalRedactions(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.LegalRedaction, 0, len(redactions))
+	for _, r := range redactions {
+		result = append(result, convert.ToLegalRedaction(r))
+	}
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreateLegalRedaction redacts a content revision or an attachment
+func CreateLegalRedaction(ctx *context.APIContext) {
+	// swagger:operation POST /admin/redactions admin adminCreateLegalRedaction
+	// ---
+	// summary: Redact an old revision of an issue or comment content, or an attachment of an issue or comment, and record the redaction
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateLegalRedactionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/LegalRedaction"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateLegalRedactionOption)
+
+	if (form.ContentHistoryID == 0) == (form.AttachmentID == 0) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "exactly one of content_history_id and attachment_id is required")
+		return
+	}
+
+	var redaction *issues_model.LegalRedaction
+	if form.ContentHistoryID != 0 {
+		history, err := issues_model.GetIssueContentHistoryByID(ctx, form.ContentHistoryID)
+		if err != nil {
+			if errors.Is(err, util.ErrNotExist) {
+				ctx.NotFound()
+			} else {
+				ctx.InternalServerError(err)
+			}
+			return
+		}
+		redaction, err = issue_service.RedactContentHistory(ctx, ctx.Doer, history, form.Reason)
+		if err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.InternalServerError(err)
+			}
+			return
+		}
+	} else {
+		attach, err := repo_model.GetAttachmentByID(ctx, form.AttachmentID)
+		if err != nil {
+			if repo_model.IsErrAttachmentNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.InternalServerError(err)
+			}
+			return
+		}
+		redaction, err = issue_service.RedactAttachment(ctx, ctx.Doer, attach, form.Reason)
+		if err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.InternalServerError(err)
+			}
+			return
+		}
+	}
+	ctx.JSON(http.StatusCreated, convert.ToLegalRedaction(redaction))
+}
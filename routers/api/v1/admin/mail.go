@@ -0,0 +1,75 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListMailDeliveries list the delivery status of outgoing mails
+func ListMailDeliveries(ctx *context.APIContext) {
+	// swagger:operation GET /admin/mail/deliveries admin adminListMailDeliveries
+	// ---
+	// summary: List the delivery status of outgoing mails, newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: recipient
+	//   in: query
+	//   description: only mails to this address
+	//   type: string
+	// - name: status
+	//   in: query
+	//   description: only mails with this status
+	//   type: string
+	//   enum: [queued, sent, failed, delivered, bounced, complained]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MailDeliveryList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := system_model.FindMailDeliveriesOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		Recipient:   ctx.FormTrim("recipient"),
+	}
+	if status := ctx.FormTrim("status"); status != "" {
+		s, ok := system_model.ParseMailDeliveryStatus(status)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", "invalid status")
+			return
+		}
+		opts.Statuses = []system_model.MailDeliveryStatus{s}
+	}
+
+	deliveries, count, err := system_model.FindMailDeliveries(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.MailDelivery, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		result = append(result, convert.ToMailDelivery(delivery))
+	}
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,92 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	maintenance_service "code.gitea.io/gitea/services/maintenance"
+)
+
+func toMaintenanceMode(mode *system_model.MaintenanceMode) *api.MaintenanceMode {
+	return &api.MaintenanceMode{
+		Enabled: mode.Enabled,
+		Message: mode.Message,
+	}
+}
+
+// GetMaintenanceMode returns the state of the read-only maintenance mode
+func GetMaintenanceMode(ctx *context.APIContext) {
+	// swagger:operation GET /admin/maintenance_mode admin adminGetMaintenanceMode
+	// ---
+	// summary: Get the state of the read-only maintenance mode
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MaintenanceMode"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	ctx.JSON(http.StatusOK, toMaintenanceMode(system_model.GetMaintenanceMode(ctx)))
+}
+
+// EnableMaintenanceMode enables the read-only maintenance mode
+func EnableMaintenanceMode(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/maintenance_mode admin adminEnableMaintenanceMode
+	// ---
+	// summary: Enable the read-only maintenance mode, requests which could change data are rejected
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EnableMaintenanceModeOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MaintenanceMode"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EnableMaintenanceModeOption)
+	if form.DrainTimeout < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "drain_timeout must not be negative")
+		return
+	}
+	if err := maintenance_service.Enable(ctx, form.Message, time.Duration(form.DrainTimeout)*time.Second); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, toMaintenanceMode(system_model.GetMaintenanceMode(ctx)))
+}
+
+// DisableMaintenanceMode disables the read-only maintenance mode
+func DisableMaintenanceMode(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/maintenance_mode admin adminDisableMaintenanceMode
+	// ---
+	// summary: Disable the read-only maintenance mode
+	// produces:
+	// - application/json
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	if err := maintenance_service.Disable(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,355 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	githook_model "code.gitea.io/gitea/models/githook"
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListManagedHooks lists the git hooks which run for all repositories
+func ListManagedHooks(ctx *context.APIContext) {
+	// swagger:operation GET /admin/git_hooks admin adminListManagedHooks
+	// ---
+	// summary: List the git hooks which run for all repositories
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ManagedHookList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	listManagedHooks(ctx, 0)
+}
+
+// CreateManagedHook creates a git hook which runs for all repositories
+func CreateManagedHook(ctx *context.APIContext) {
+	// swagger:operation POST /admin/git_hooks admin adminCreateManagedHook
+	// ---
+	// summary: Create a git hook which runs for all repositories
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateManagedHookOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ManagedHook"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	createManagedHook(ctx, 0)
+}
+
+// EditManagedHook modifies a git hook which runs for all repositories
+func EditManagedHook(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/git_hooks/{id} admin adminEditManagedHook
+	// ---
+	// summary: Update a git hook which runs for all repositories
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditManagedHookOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ManagedHook"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	editManagedHook(ctx, 0)
+}
+
+// DeleteManagedHook removes a git hook which runs for all repositories
+func DeleteManagedHook(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/git_hooks/{id} admin adminDeleteManagedHook
+	// ---
+	// summary: Delete a git hook which runs for all repositories
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	deleteManagedHook(ctx, 0)
+}
+
+// ListOrgManagedHooks lists the git hooks which run for the repositories of an organization
+func ListOrgManagedHooks(ctx *context.APIContext) {
+	// swagger:operation GET /admin/orgs/{org}/git_hooks admin adminListOrgManagedHooks
+	// ---
+	// summary: List the git hooks which run for the repositories of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ManagedHookList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if org := getOrgFromParams(ctx); org != nil {
+		listManagedHooks(ctx, org.ID)
+	}
+}
+
+// CreateOrgManagedHook creates a git hook which runs for the repositories of an organization
+func CreateOrgManagedHook(ctx *context.APIContext) {
+	// swagger:operation POST /admin/orgs/{org}/git_hooks admin adminCreateOrgManagedHook
+	// ---
+	// summary: Create a git hook which runs for the repositories of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateManagedHookOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ManagedHook"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if org := getOrgFromParams(ctx); org != nil {
+		createManagedHook(ctx, org.ID)
+	}
+}
+
+// EditOrgManagedHook modifies a git hook which runs for the repositories of an organization
+func EditOrgManagedHook(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/orgs/{org}/git_hooks/{id} admin adminEditOrgManagedHook
+	// ---
+	// summary: Update a git hook which runs for the repositories of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditManagedHookOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ManagedHook"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	if org := getOrgFromParams(ctx); org != nil {
+		editManagedHook(ctx, org.ID)
+	}
+}
+
+// DeleteOrgManagedHook removes a git hook which runs for the repositories of an organization
+func DeleteOrgManagedHook(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/orgs/{org}/git_hooks/{id} admin adminDeleteOrgManagedHook
+	// ---
+	// summary: Delete a git hook which runs for the repositories of an organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if org := getOrgFromParams(ctx); org != nil {
+		deleteManagedHook(ctx, org.ID)
+	}
+}
+
+func getOrgFromParams(ctx *context.APIContext) *organization.Organization {
+	org, err := organization.GetOrgByName(ctx, ctx.Params(":org"))
+	if err != nil {
+		if organization.IsErrOrgNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil
+	}
+	return org
+}
+
+func listManagedHooks(ctx *context.APIContext, ownerID int64) {
+	hooks, err := githook_model.FindManagedHooks(ctx, ownerID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.ManagedHook, 0, len(hooks))
+	for _, hook := range hooks {
+		result = append(result, convert.ToManagedHook(hook))
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+func createManagedHook(ctx *context.APIContext, ownerID int64) {
+	form := web.GetForm(ctx).(*api.CreateManagedHookOption)
+
+	hook := &githook_model.ManagedHook{
+		OwnerID:  ownerID,
+		Name:     form.Name,
+		Type:     githook_model.HookType(form.Type),
+		Content:  form.Content,
+		IsActive: form.Active == nil || *form.Active,
+	}
+	if !hook.Type.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "type must be pre-receive or post-receive")
+		return
+	}
+	if err := githook_model.CreateManagedHook(ctx, hook); err != nil {
+		if githook_model.IsErrManagedHookAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToManagedHook(hook))
+}
+
+func editManagedHook(ctx *context.APIContext, ownerID int64) {
+	form := web.GetForm(ctx).(*api.EditManagedHookOption)
+	hook, err := githook_model.GetManagedHook(ctx, ownerID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if githook_model.IsErrManagedHookNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	cols := make([]string, 0, 3)
+	if form.Type != nil {
+		hook.Type = githook_model.HookType(*form.Type)
+		if !hook.Type.IsValid() {
+			ctx.Error(http.StatusUnprocessableEntity, "", "type must be pre-receive or post-receive")
+			return
+		}
+		cols = append(cols, "type")
+	}
+	if form.Content != nil {
+		if *form.Content == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "content must not be empty")
+			return
+		}
+		hook.Content = *form.Content
+		cols = append(cols, "content")
+	}
+	if form.Active != nil {
+		hook.IsActive = *form.Active
+		cols = append(cols, "is_active")
+	}
+
+	if len(cols) > 0 {
+		if err := githook_model.UpdateManagedHook(ctx, hook, cols...); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, convert.ToManagedHook(hook))
+}
+
+func deleteManagedHook(ctx *context.APIContext, ownerID int64) {
+	if err := githook_model.DeleteManagedHook(ctx, ownerID, ctx.ParamsInt64(":id")); err != nil {
+		if githook_model.IsErrManagedHookNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
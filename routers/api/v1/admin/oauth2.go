@@ -0,0 +1,68 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// EditOAuth2TokenLifetimes sets the lifetimes of the tokens of an OAuth2 application
+func EditOAuth2TokenLifetimes(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/oauth2/{id}/token_lifetimes admin adminEditOAuth2TokenLifetimes
+	// ---
+	// summary: Set the lifetimes of the tokens of an OAuth2 application, they apply to the tokens issued afterwards
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the OAuth2 application
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditOAuth2TokenLifetimesOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OAuth2Application"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditOAuth2TokenLifetimesOption)
+	if form.AccessTokenLifetime < 0 || form.RefreshTokenLifetime < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the lifetimes must not be negative")
+		return
+	}
+
+	app, err := auth_model.GetOAuth2ApplicationByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if auth_model.IsErrOAuthApplicationNotFound(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	if err := auth_model.SetOAuth2ApplicationTokenLifetimes(ctx, app, form.AccessTokenLifetime, form.RefreshTokenLifetime); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	app.ClientSecret = ""
+	ctx.JSON(http.StatusOK, convert.ToOAuth2Application(app))
+}
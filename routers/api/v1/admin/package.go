@@ -0,0 +1,84 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	goproxy_service "code.gitea.io/gitea/services/packages/goproxy"
+)
+
+// VerifyGoModules verifies the stored Go modules against the checksum database
+func VerifyGoModules(ctx *context.APIContext) {
+	// swagger:operation POST /admin/packages/go/verify admin adminVerifyGoModules
+	// ---
+	// summary: Verify the zips of the stored Go modules with public module paths against the checksum database
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: query
+	//   description: only verify the modules of the owner with this name
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GoModuleVerificationList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	var ownerID int64
+	if ownerName := ctx.FormTrim("owner"); ownerName != "" {
+		owner, err := user_model.GetUserByName(ctx, ownerName)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.InternalServerError(err)
+			}
+			return
+		}
+		ownerID = owner.ID
+	}
+
+	verifications, err := goproxy_service.VerifyModules(ctx, ownerID)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	owners := make(map[int64]string)
+	result := make([]*api.GoModuleVerification, 0, len(verifications))
+	for _, v := range verifications {
+		ownerName, ok := owners[v.Package.OwnerID]
+		if !ok {
+			owner, err := user_model.GetPossibleUserByID(ctx, v.Package.OwnerID)
+			if err != nil {
+				ctx.InternalServerError(err)
+				return
+			}
+			ownerName = owner.Name
+			owners[v.Package.OwnerID] = ownerName
+		}
+		result = append(result, &api.GoModuleVerification{
+			Owner:      ownerName,
+			Module:     v.Package.Name,
+			Version:    v.Version.Version,
+			Status:     string(v.Status),
+			LocalHash:  v.LocalHash,
+			PublicHash: v.PublicHash,
+		})
+	}
+	ctx.JSON(http.StatusOK, result)
+}
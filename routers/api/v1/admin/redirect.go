@@ -0,0 +1,76 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListUserRedirects lists the former names of a user or organization
+func ListUserRedirects(ctx *context.APIContext) {
+	// swagger:operation GET /admin/users/{username}/redirects admin adminListUserRedirects
+	// ---
+	// summary: List the former names of a user or organization, which redirect to the current name
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserRedirectList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	redirects, err := user_model.GetUserRedirects(ctx, ctx.ContextUser.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToUserRedirects(redirects))
+}
+
+// DeleteUserRedirect deletes the redirect of a former name of a user or organization
+func DeleteUserRedirect(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/users/{username}/redirects/{name} admin adminDeleteUserRedirect
+	// ---
+	// summary: Delete the redirect of a former name of a user or organization, which releases its reservation
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user or organization
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: former name
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := user_model.DeleteUserRedirectOf(ctx, ctx.ContextUser.ID, ctx.Params(":name")); err != nil {
+		if user_model.IsErrUserRedirectNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
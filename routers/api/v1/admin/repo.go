@@ -4,10 +4,14 @@
 package admin
 
 import (
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/context"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/repo"
+	"code.gitea.io/gitea/services/convert"
 )
 
 // CreateRepo api for creating a repository
@@ -47,3 +51,28 @@ func CreateRepo(ctx *context.APIContext) {
 
 	repo.CreateUserRepo(ctx, ctx.ContextUser, *form)
 }
+
+// ListDeletedRepositories lists the deleted repositories of all owners which weren't purged yet
+func ListDeletedRepositories(ctx *context.APIContext) {
+	// swagger:operation GET /admin/deleted_repos admin adminListDeletedRepos
+	// ---
+	// summary: List the deleted repositories which can still be restored
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DeletedRepositoryList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	deletions, err := repo_model.FindRepoDeletions(ctx, nil)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoDeletions", err)
+		return
+	}
+	apiRepos := make([]*api.DeletedRepository, 0, len(deletions))
+	for _, d := range deletions {
+		apiRepos = append(apiRepos, convert.ToDeletedRepository(ctx, d))
+	}
+	ctx.JSON(http.StatusOK, apiRepos)
+}
@@ -0,0 +1,170 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+	"code.gitea.io/gitea/services/cron"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// ListRepoMaintenance lists the maintenance status of the repositories
+func ListRepoMaintenance(ctx *context.APIContext) {
+	// swagger:operation GET /admin/maintenance admin adminListRepoMaintenance
+	// ---
+	// summary: List the maintenance status of repositories, most recently maintained first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoMaintenanceList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	listOptions := utils.GetListOptions(ctx)
+	records, count, err := repo_model.FindRepoMaintenances(ctx, listOptions)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	repoIDs := make([]int64, 0, len(records))
+	for _, m := range records {
+		repoIDs = append(repoIDs, m.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.RepoMaintenance, 0, len(records))
+	for _, m := range records {
+		result = append(result, convert.ToRepoMaintenance(repos[m.RepoID], m))
+	}
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetRepoMaintenance returns the maintenance status of a repository
+func GetRepoMaintenance(ctx *context.APIContext) {
+	// swagger:operation GET /admin/maintenance/{owner}/{repo} admin adminGetRepoMaintenance
+	// ---
+	// summary: Get the maintenance status of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoMaintenance"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	repo := getMaintenanceRepo(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	m, err := repo_model.GetRepoMaintenance(ctx, repo.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	} else if m == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepoMaintenance(repo, m))
+}
+
+// RunRepoMaintenance runs the needed maintenance tasks on a repository immediately
+func RunRepoMaintenance(ctx *context.APIContext) {
+	// swagger:operation POST /admin/maintenance/{owner}/{repo} admin adminRunRepoMaintenance
+	// ---
+	// summary: Run the needed maintenance tasks on a repository, regardless of the off-peak window
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoMaintenance"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+
+	repo := getMaintenanceRepo(ctx)
+	if ctx.Written() {
+		return
+	}
+	if repo.IsEmpty {
+		ctx.Error(http.StatusUnprocessableEntity, "", "repository is empty")
+		return
+	}
+
+	m, err := repo_service.RunRepoMaintenance(ctx, repo, cron.GetRepoMaintenanceOptions())
+	if err != nil && m == nil {
+		if errors.Is(err, repo_service.ErrMaintenanceRunning) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	// failures of the maintenance tasks themselves are reported in last_error
+	ctx.JSON(http.StatusOK, convert.ToRepoMaintenance(repo, m))
+}
+
+func getMaintenanceRepo(ctx *context.APIContext) *repo_model.Repository {
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ctx.Params(":username"), ctx.Params(":reponame"))
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil
+	}
+	return repo
+}
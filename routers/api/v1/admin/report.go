@@ -0,0 +1,151 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	moderation_model "code.gitea.io/gitea/models/moderation"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListAbuseReports list the abuse reports, the open ones by default
+func ListAbuseReports(ctx *context.APIContext) {
+	// swagger:operation GET /admin/reports admin adminListAbuseReports
+	// ---
+	// summary: List abuse reports, oldest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: status
+	//   in: query
+	//   description: only reports with this status, defaults to open
+	//   type: string
+	//   enum: [open, resolved, dismissed, all]
+	// - name: type
+	//   in: query
+	//   description: only reports about this kind of content
+	//   type: string
+	//   enum: [user, repository, issue, comment]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AbuseReportList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := moderation_model.FindReportOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		ContentType: moderation_model.ReportContentType(ctx.FormTrim("type")),
+	}
+	if opts.ContentType != "" && !opts.ContentType.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid content type")
+		return
+	}
+	switch status := ctx.FormTrim("status"); status {
+	case "all":
+	case "":
+		opts.Statuses = []moderation_model.ReportStatus{moderation_model.ReportStatusOpen}
+	default:
+		s, ok := moderation_model.ParseReportStatus(status)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", "invalid status")
+			return
+		}
+		opts.Statuses = []moderation_model.ReportStatus{s}
+	}
+
+	reports, count, err := moderation_model.FindReports(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.AbuseReport, 0, len(reports))
+	for _, report := range reports {
+		apiReport, err := convert.ToAbuseReport(ctx, report, ctx.Doer)
+		if err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		result = append(result, apiReport)
+	}
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// EditAbuseReport resolve, dismiss or reopen an abuse report
+func EditAbuseReport(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/reports/{id} admin adminEditAbuseReport
+	// ---
+	// summary: Resolve, dismiss or reopen an abuse report
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the report
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditAbuseReportOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AbuseReport"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditAbuseReportOption)
+
+	status, ok := moderation_model.ParseReportStatus(form.Status)
+	if !ok {
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid status")
+		return
+	}
+
+	report, err := moderation_model.GetReportByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if moderation_model.IsErrReportNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	if err := moderation_model.UpdateReportStatus(ctx, report, status, ctx.Doer.ID); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	apiReport, err := convert.ToAbuseReport(ctx, report, ctx.Doer)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiReport)
+}
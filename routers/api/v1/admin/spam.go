@@ -0,0 +1,172 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	stdCtx "context"
+	"errors"
+	"net/http"
+
+	moderation_model "code.gitea.io/gitea/models/moderation"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+	spam_service "code.gitea.io/gitea/services/spam"
+)
+
+// ListHeldContent list the registrations, issues and pull requests held for review
+func ListHeldContent(ctx *context.APIContext) {
+	// swagger:operation GET /admin/spam admin adminListHeldContent
+	// ---
+	// summary: List registrations, issues and pull requests held for review as likely spam, oldest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: status
+	//   in: query
+	//   description: only content with this status, defaults to pending
+	//   type: string
+	//   enum: [pending, approved, rejected, all]
+	// - name: type
+	//   in: query
+	//   description: only content of this type
+	//   type: string
+	//   enum: [registration, issue, pull_request]
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HeldContentList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := moderation_model.FindHeldContentOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		Type:        moderation_model.HeldContentType(ctx.FormTrim("type")),
+	}
+	switch status := ctx.FormTrim("status"); status {
+	case "all":
+	case "":
+		opts.Statuses = []moderation_model.HeldContentStatus{moderation_model.HeldContentStatusPending}
+	default:
+		s, ok := moderation_model.ParseHeldContentStatus(status)
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", "invalid status")
+			return
+		}
+		opts.Statuses = []moderation_model.HeldContentStatus{s}
+	}
+
+	held, count, err := moderation_model.FindHeldContent(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.HeldContent, 0, len(held))
+	for _, h := range held {
+		apiHeld, err := convert.ToHeldContent(ctx, h, ctx.Doer)
+		if err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		result = append(result, apiHeld)
+	}
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// ApproveHeldContent publish held content
+func ApproveHeldContent(ctx *context.APIContext) {
+	// swagger:operation POST /admin/spam/{id}/approve admin adminApproveHeldContent
+	// ---
+	// summary: Approve held content, a registration may sign in and an issue or pull request is created
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the held content
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HeldContent"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	handleHeldContent(ctx, spam_service.Approve)
+}
+
+// RejectHeldContent discard held content
+func RejectHeldContent(ctx *context.APIContext) {
+	// swagger:operation POST /admin/spam/{id}/reject admin adminRejectHeldContent
+	// ---
+	// summary: Reject held content, a rejected registration stays unable to sign in
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the held content
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HeldContent"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	handleHeldContent(ctx, spam_service.Reject)
+}
+
+func handleHeldContent(ctx *context.APIContext, handle func(stdCtx.Context, *moderation_model.HeldContent, *user_model.User) error) {
+	held, err := moderation_model.GetHeldContentByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if moderation_model.IsErrHeldContentNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	if err := handle(ctx, held, ctx.Doer); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) || errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	apiHeld, err := convert.ToHeldContent(ctx, held, ctx.Doer)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiHeld)
+}
@@ -0,0 +1,123 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	org_service "code.gitea.io/gitea/services/org"
+)
+
+// ListStorageRegions lists the names of the configured storage regions
+func ListStorageRegions(ctx *context.APIContext) {
+	// swagger:operation GET /admin/storage_regions admin adminListStorageRegions
+	// ---
+	// summary: List the names of the configured storage regions
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StringSlice"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	ctx.JSON(http.StatusOK, setting.StorageRegionNames())
+}
+
+// GetOrgStorageRegion returns the storage region an organization is pinned to
+func GetOrgStorageRegion(ctx *context.APIContext) {
+	// swagger:operation GET /admin/orgs/{org}/storage_region admin adminGetOrgStorageRegion
+	// ---
+	// summary: Get the storage region an organization is pinned to
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrgStorageRegion"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	org := getOrgFromParams(ctx)
+	if org == nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, toOrgStorageRegion(org))
+}
+
+// EditOrgStorageRegion pins an organization to a storage region and moves its data there
+func EditOrgStorageRegion(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/orgs/{org}/storage_region admin adminEditOrgStorageRegion
+	// ---
+	// summary: Pin an organization to a storage region and move its data there
+	// description: The data of the organization is moved in the background, pushes to its repositories are rejected meanwhile.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditOrgStorageRegionOption"
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/OrgStorageRegion"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditOrgStorageRegionOption)
+	org := getOrgFromParams(ctx)
+	if org == nil {
+		return
+	}
+
+	if err := org_service.MoveToStorageRegion(org, form.Region); err != nil {
+		switch {
+		case user_model.IsErrStorageRegionNotExist(err):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		case org_service.IsErrStorageRegionMoveInProgress(err):
+			ctx.Error(http.StatusConflict, "", err)
+		default:
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusAccepted, toOrgStorageRegion(org))
+}
+
+func toOrgStorageRegion(org *organization.Organization) *api.OrgStorageRegion {
+	result := &api.OrgStorageRegion{
+		Region: user_model.StorageRegionOfID(org.ID),
+	}
+	if region, ok := org_service.GetMovingStorageRegion(org.ID); ok {
+		result.MovingTo = &region
+	}
+	return result
+}
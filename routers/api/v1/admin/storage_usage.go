@@ -0,0 +1,228 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// defaultStorageUsageDays is the default period of a storage usage trend
+const defaultStorageUsageDays = 30
+
+// ListStorageUsage lists the top storage consumers of the latest snapshot
+func ListStorageUsage(ctx *context.APIContext) {
+	// swagger:operation GET /admin/storage/usage admin adminListStorageUsage
+	// ---
+	// summary: List the owners or repositories using the most storage according to the latest snapshot
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: query
+	//   description: list owners, which includes their packages, or repositories
+	//   type: string
+	//   enum: [owners, repos]
+	//   default: owners
+	// - name: owner
+	//   in: query
+	//   description: only repositories of the owner with this name
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StorageUsageList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts := repo_model.FindStorageUsageOptions{ListOptions: utils.GetListOptions(ctx)}
+	switch ctx.FormTrim("type") {
+	case "", "owners":
+	case "repos":
+		opts.Repos = true
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", "type must be owners or repos")
+		return
+	}
+	if ownerName := ctx.FormTrim("owner"); ownerName != "" {
+		owner, err := user_model.GetUserByName(ctx, ownerName)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.InternalServerError(err)
+			}
+			return
+		}
+		opts.OwnerID = owner.ID
+	}
+
+	usages, count, err := repo_model.FindTopStorageUsages(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	ownerIDs := make([]int64, 0, len(usages))
+	repoIDs := make([]int64, 0, len(usages))
+	for _, u := range usages {
+		ownerIDs = append(ownerIDs, u.OwnerID)
+		if u.RepoID > 0 {
+			repoIDs = append(repoIDs, u.RepoID)
+		}
+	}
+	owners, err := user_model.GetUsersByIDs(ownerIDs)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ownerNames := make(map[int64]string, len(owners))
+	for _, owner := range owners {
+		ownerNames[owner.ID] = owner.Name
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.StorageUsage, 0, len(usages))
+	for _, u := range usages {
+		result = append(result, convert.ToStorageUsage(ownerNames[u.OwnerID], repos[u.RepoID], u))
+	}
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetOwnerStorageUsage returns the storage usage trend of an owner
+func GetOwnerStorageUsage(ctx *context.APIContext) {
+	// swagger:operation GET /admin/storage/usage/{owner} admin adminGetOwnerStorageUsage
+	// ---
+	// summary: Get the storage used by an owner over a period, including its packages
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: name of the user or organization
+	//   type: string
+	//   required: true
+	// - name: days
+	//   in: query
+	//   description: number of days of history, defaults to 30
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StorageUsageTrend"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	since, ok := storageUsageSince(ctx)
+	if !ok {
+		return
+	}
+	owner, err := user_model.GetUserByName(ctx, ctx.Params(":username"))
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	usages, err := repo_model.GetStorageUsageHistory(ctx, owner.ID, 0, since)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToStorageUsageTrend(owner.Name, nil, usages))
+}
+
+// GetRepoStorageUsage returns the storage usage trend of a repository
+func GetRepoStorageUsage(ctx *context.APIContext) {
+	// swagger:operation GET /admin/storage/usage/{owner}/{repo} admin adminGetRepoStorageUsage
+	// ---
+	// summary: Get the storage used by a repository over a period
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: days
+	//   in: query
+	//   description: number of days of history, defaults to 30
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StorageUsageTrend"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	since, ok := storageUsageSince(ctx)
+	if !ok {
+		return
+	}
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ctx.Params(":username"), ctx.Params(":reponame"))
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	usages, err := repo_model.GetStorageUsageHistory(ctx, repo.OwnerID, repo.ID, since)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToStorageUsageTrend(repo.OwnerName, repo, usages))
+}
+
+func storageUsageSince(ctx *context.APIContext) (timeutil.TimeStamp, bool) {
+	days := defaultStorageUsageDays
+	if ctx.FormString("days") != "" {
+		if days = ctx.FormInt("days"); days <= 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", "days must be a positive number")
+			return 0, false
+		}
+	}
+	return timeutil.TimeStampNow().AddDuration(-time.Duration(days) * 24 * time.Hour), true
+}
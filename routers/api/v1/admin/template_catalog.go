@@ -0,0 +1,134 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListTemplateCatalogItems lists the templates of a catalog of the instance
+func ListTemplateCatalogItems(ctx *context.APIContext) {
+	// swagger:operation GET /admin/templates/{kind} admin adminListTemplateCatalogItems
+	// ---
+	// summary: List the templates of a catalog of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: kind
+	//   in: path
+	//   description: kind of the catalog
+	//   type: string
+	//   enum: [gitignore, license, issue_template, workflow_template]
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TemplateCatalogItemList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.ListTemplateCatalogItems(ctx, 0)
+}
+
+// GetTemplateCatalogItem gets a template of a catalog of the instance
+func GetTemplateCatalogItem(ctx *context.APIContext) {
+	// swagger:operation GET /admin/templates/{kind}/{name} admin adminGetTemplateCatalogItem
+	// ---
+	// summary: Get a template of a catalog of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: kind
+	//   in: path
+	//   description: kind of the catalog
+	//   type: string
+	//   enum: [gitignore, license, issue_template, workflow_template]
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TemplateCatalogItem"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.GetTemplateCatalogItem(ctx, 0)
+}
+
+// EditTemplateCatalogItem adds a template to a catalog of the instance or replaces it
+func EditTemplateCatalogItem(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/templates/{kind}/{name} admin adminEditTemplateCatalogItem
+	// ---
+	// summary: Add a template to a catalog of the instance or replace it
+	// description: Issue templates must be markdown or YAML files, workflow templates YAML files.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: kind
+	//   in: path
+	//   description: kind of the catalog
+	//   type: string
+	//   enum: [gitignore, license, issue_template, workflow_template]
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditTemplateCatalogItemOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TemplateCatalogItem"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.EditTemplateCatalogItem(ctx, 0)
+}
+
+// DeleteTemplateCatalogItem deletes a template of a catalog of the instance
+func DeleteTemplateCatalogItem(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/templates/{kind}/{name} admin adminDeleteTemplateCatalogItem
+	// ---
+	// summary: Delete a template of a catalog of the instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: kind
+	//   in: path
+	//   description: kind of the catalog
+	//   type: string
+	//   enum: [gitignore, license, issue_template, workflow_template]
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteTemplateCatalogItem(ctx, 0)
+}
@@ -0,0 +1,311 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+	theme_service "code.gitea.io/gitea/services/theme"
+)
+
+// ListThemes lists the themes managed by the site administrators
+func ListThemes(ctx *context.APIContext) {
+	// swagger:operation GET /admin/themes admin adminListThemes
+	// ---
+	// summary: List the themes managed by the site administrators
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ThemeList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	themes, err := system_model.GetThemes(ctx)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToThemes(themes))
+}
+
+// CreateTheme creates a theme users can choose
+func CreateTheme(ctx *context.APIContext) {
+	// swagger:operation POST /admin/themes admin adminCreateTheme
+	// ---
+	// summary: Create a theme users can choose and, if approved, organizations can use for their branding
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateThemeOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Theme"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateThemeOption)
+
+	t := &system_model.Theme{
+		Name:        form.Name,
+		DisplayName: form.DisplayName,
+		Variables:   form.Variables,
+		OrgBranding: form.OrgBranding,
+	}
+	if err := theme_service.CreateTheme(ctx, t); err != nil {
+		switch {
+		case system_model.IsErrThemeAlreadyExist(err):
+			ctx.Error(http.StatusConflict, "", err)
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		default:
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToTheme(t))
+}
+
+// GetTheme returns a theme managed by the site administrators
+func GetTheme(ctx *context.APIContext) {
+	// swagger:operation GET /admin/themes/{name} admin adminGetTheme
+	// ---
+	// summary: Get a theme managed by the site administrators
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: name
+	//   in: path
+	//   description: name of the theme
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Theme"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t := getThemeFromParams(ctx)
+	if t == nil {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTheme(t))
+}
+
+// EditTheme modifies a theme managed by the site administrators
+func EditTheme(ctx *context.APIContext) {
+	// swagger:operation PATCH /admin/themes/{name} admin adminEditTheme
+	// ---
+	// summary: Update a theme managed by the site administrators
+	// description: Organizations keep a theme which is not approved for branding anymore,
+	//   but their pages are rendered with the default theme until it is approved again.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: name
+	//   in: path
+	//   description: name of the theme
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditThemeOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Theme"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditThemeOption)
+	t := getThemeFromParams(ctx)
+	if t == nil {
+		return
+	}
+
+	cols := make([]string, 0, 3)
+	if form.DisplayName != nil {
+		t.DisplayName = *form.DisplayName
+		cols = append(cols, "display_name")
+	}
+	if form.Variables != nil {
+		t.Variables = form.Variables
+		cols = append(cols, "variables")
+	}
+	if form.OrgBranding != nil {
+		t.OrgBranding = *form.OrgBranding
+		cols = append(cols, "org_branding")
+	}
+	if len(cols) > 0 {
+		if err := theme_service.UpdateTheme(ctx, t, cols...); err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.InternalServerError(err)
+			}
+			return
+		}
+	}
+	ctx.JSON(http.StatusOK, convert.ToTheme(t))
+}
+
+// DeleteTheme removes a theme managed by the site administrators
+func DeleteTheme(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/themes/{name} admin adminDeleteTheme
+	// ---
+	// summary: Delete a theme managed by the site administrators
+	// description: The brandings of the organizations using the theme are removed,
+	//   users who chose it are shown the default theme.
+	// parameters:
+	// - name: name
+	//   in: path
+	//   description: name of the theme
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t := getThemeFromParams(ctx)
+	if t == nil {
+		return
+	}
+	if err := theme_service.DeleteTheme(ctx, t); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// UploadThemeLogo replaces the logo of a theme
+func UploadThemeLogo(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/themes/{name}/logo admin adminUploadThemeLogo
+	// ---
+	// summary: Upload the logo of a theme, which is shown on the pages of the organizations using it
+	// consumes:
+	// - multipart/form-data
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: name
+	//   in: path
+	//   description: name of the theme
+	//   type: string
+	//   required: true
+	// - name: logo
+	//   in: formData
+	//   description: PNG, JPEG, GIF or WebP image within the size limit of avatars
+	//   type: file
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Theme"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	t := getThemeFromParams(ctx)
+	if t == nil {
+		return
+	}
+
+	file, _, err := ctx.Req.FormFile("logo")
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", "logo is required")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, setting.Avatar.MaxFileSize+1))
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if err := theme_service.UploadLogo(ctx, t, data); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTheme(t))
+}
+
+// DeleteThemeLogo removes the logo of a theme
+func DeleteThemeLogo(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/themes/{name}/logo admin adminDeleteThemeLogo
+	// ---
+	// summary: Delete the logo of a theme, the default logo is shown instead
+	// parameters:
+	// - name: name
+	//   in: path
+	//   description: name of the theme
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t := getThemeFromParams(ctx)
+	if t == nil {
+		return
+	}
+	if err := theme_service.DeleteLogo(ctx, t); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getThemeFromParams(ctx *context.APIContext) *system_model.Theme {
+	t, err := system_model.GetThemeByName(ctx, ctx.Params(":name"))
+	if err != nil {
+		if system_model.IsErrThemeNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil
+	}
+	return t
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"net/http"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// accessTokensOptions reads the filters shared by listing and revoking access tokens
+func accessTokensOptions(ctx *context.APIContext) (*auth_model.FindAccessTokensOptions, bool) {
+	opts := &auth_model.FindAccessTokensOptions{
+		NamePattern: ctx.FormTrim("name"),
+	}
+	if username := ctx.FormTrim("user"); username != "" {
+		u, err := user_model.GetUserByName(ctx, username)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return nil, false
+		}
+		opts.UserID = u.ID
+	}
+	if ctx.FormString("unused_days") != "" {
+		days := ctx.FormInt("unused_days")
+		if days <= 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", "unused_days must be a positive number")
+			return nil, false
+		}
+		opts.UnusedSince = timeutil.TimeStampNow().AddDuration(-time.Duration(days) * 24 * time.Hour)
+	}
+	return opts, true
+}
+
+// ListAccessTokens list the personal access tokens of all users
+func ListAccessTokens(ctx *context.APIContext) {
+	// swagger:operation GET /admin/tokens admin adminListAccessTokens
+	// ---
+	// summary: List the personal access tokens of all users, newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: user
+	//   in: query
+	//   description: only tokens of the user with this username
+	//   type: string
+	// - name: name
+	//   in: query
+	//   description: only tokens with a matching name, "*" matches any characters
+	//   type: string
+	// - name: unused_days
+	//   in: query
+	//   description: only tokens which have not been used for this number of days
+	//   type: integer
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/InstanceAccessTokenList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts, ok := accessTokensOptions(ctx)
+	if !ok {
+		return
+	}
+	opts.ListOptions = utils.GetListOptions(ctx)
+
+	tokens, count, err := auth_model.FindAccessTokens(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	userIDs := make([]int64, 0, len(tokens))
+	for _, t := range tokens {
+		userIDs = append(userIDs, t.UID)
+	}
+	users, err := user_model.GetUsersByIDs(userIDs)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	usersByID := make(map[int64]*user_model.User, len(users))
+	for _, u := range users {
+		usersByID[u.ID] = u
+	}
+
+	result := make([]*api.InstanceAccessToken, 0, len(tokens))
+	for _, t := range tokens {
+		result = append(result, convert.ToInstanceAccessToken(t, usersByID[t.UID]))
+	}
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// RevokeAccessTokens revoke the personal access tokens matching the filters
+func RevokeAccessTokens(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/tokens admin adminRevokeAccessTokens
+	// ---
+	// summary: Revoke the personal access tokens of all users matching the filters, at least one filter is required
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: user
+	//   in: query
+	//   description: only tokens of the user with this username
+	//   type: string
+	// - name: name
+	//   in: query
+	//   description: only tokens with a matching name, "*" matches any characters
+	//   type: string
+	// - name: unused_days
+	//   in: query
+	//   description: only tokens which have not been used for this number of days
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RevokeAccessTokensResult"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	opts, ok := accessTokensOptions(ctx)
+	if !ok {
+		return
+	}
+	if !opts.IsFiltered() {
+		// an accidental request without filters must not revoke all tokens
+		ctx.Error(http.StatusUnprocessableEntity, "", "at least one of user, name and unused_days is required")
+		return
+	}
+
+	revoked, err := auth_model.DeleteAccessTokens(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.RevokeAccessTokensResult{Revoked: revoked})
+}
@@ -0,0 +1,210 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+	translation_service "code.gitea.io/gitea/services/translation"
+)
+
+// ListTranslationOverrides lists the messages overridden by the site administrators
+func ListTranslationOverrides(ctx *context.APIContext) {
+	// swagger:operation GET /admin/translations admin adminListTranslationOverrides
+	// ---
+	// summary: List the messages which replace the ones of the locale files
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: lang
+	//   in: query
+	//   description: only list the overrides of this language
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TranslationOverrideList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	overrides, err := system_model.GetTranslationOverrides(ctx, ctx.FormString("lang"))
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTranslationOverrides(overrides))
+}
+
+// SetTranslationOverride overrides the message of a key in a language
+func SetTranslationOverride(ctx *context.APIContext) {
+	// swagger:operation PUT /admin/translations/{lang}/{key} admin adminSetTranslationOverride
+	// ---
+	// summary: Replace the message of a key in a language, it is shown without restarting
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: lang
+	//   in: path
+	//   description: language, like en-US
+	//   type: string
+	//   required: true
+	// - name: key
+	//   in: path
+	//   description: translation key, like repo.settings
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/SetTranslationOverrideOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetTranslationOverrideOption)
+	if err := translation_service.SetOverride(ctx, ctx.Doer, ctx.Params(":lang"), ctx.Params(":key"), form.Message); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeleteTranslationOverride restores the message of a key in a language
+func DeleteTranslationOverride(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/translations/{lang}/{key} admin adminDeleteTranslationOverride
+	// ---
+	// summary: Restore the message of the locale files for a key in a language
+	// parameters:
+	// - name: lang
+	//   in: path
+	//   description: language, like en-US
+	//   type: string
+	//   required: true
+	// - name: key
+	//   in: path
+	//   description: translation key, like repo.settings
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := translation_service.DeleteOverride(ctx, ctx.Params(":lang"), ctx.Params(":key")); err != nil {
+		if system_model.IsErrTranslationOverrideNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// DeleteTranslationOverrides restores the messages of a language
+func DeleteTranslationOverrides(ctx *context.APIContext) {
+	// swagger:operation DELETE /admin/translations/{lang} admin adminDeleteTranslationOverrides
+	// ---
+	// summary: Restore the messages of the locale files for all keys of a language
+	// parameters:
+	// - name: lang
+	//   in: path
+	//   description: language, like en-US
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	if err := translation_service.DeleteOverrides(ctx, ctx.Params(":lang")); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// UploadLocalePack overrides the messages of a language with the ones of a locale file
+func UploadLocalePack(ctx *context.APIContext) {
+	// swagger:operation POST /admin/translations/{lang}/upload admin adminUploadLocalePack
+	// ---
+	// summary: Replace the messages of a language with the ones of a locale file, they are shown without restarting
+	// consumes:
+	// - multipart/form-data
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: lang
+	//   in: path
+	//   description: language, like en-US
+	//   type: string
+	//   required: true
+	// - name: pack
+	//   in: formData
+	//   description: locale file in the ini format of options/locale, unknown keys are ignored
+	//   type: file
+	//   required: true
+	// - name: replace
+	//   in: formData
+	//   description: remove the overrides of the language which are not in the pack
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/LocalePackResult"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	file, _, err := ctx.Req.FormFile("pack")
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", "pack is required")
+		return
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, translation_service.MaxPackSize+1))
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	lang := ctx.Params(":lang")
+	imported, ignored, err := translation_service.UploadLocalePack(ctx, ctx.Doer, lang, data, ctx.FormBool("replace"))
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.LocalePackResult{
+		Lang:        lang,
+		Imported:    imported,
+		IgnoredKeys: ignored,
+	})
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package admin
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	user_service "code.gitea.io/gitea/services/user"
+)
+
+func toUserMergeReport(source, target *user_model.User, dryRun bool, report *user_service.MergeReport) *api.UserMergeReport {
+	result := &api.UserMergeReport{
+		Source:       source.Name,
+		Target:       target.Name,
+		DryRun:       dryRun,
+		Merged:       !dryRun && len(report.Conflicts) == 0,
+		Repositories: report.Repositories,
+		Issues:       report.Issues,
+		Comments:     report.Comments,
+		Stars:        report.Stars,
+		PublicKeys:   report.PublicKeys,
+		GPGKeys:      report.GPGKeys,
+		Packages:     report.Packages,
+		Teams:        report.Teams,
+		Conflicts:    make([]*api.UserMergeConflict, 0, len(report.Conflicts)),
+	}
+	for _, c := range report.Conflicts {
+		result.Conflicts = append(result.Conflicts, &api.UserMergeConflict{Type: string(c.Type), Name: c.Name})
+	}
+	return result
+}
+
+// MergeUser merges a user into another user
+func MergeUser(ctx *context.APIContext) {
+	// swagger:operation POST /admin/users/{username}/merge admin adminMergeUser
+	// ---
+	// summary: Merge a user into another user
+	// description: Moves the repositories, issues, comments, stars, SSH and GPG keys, packages and team memberships
+	//   of the user to the target user and deletes the user. Nothing is changed for a dry run or if there are conflicts,
+	//   which are listed in the report.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user to merge into the target user
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/MergeUserOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserMergeReport"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/UserMergeReport"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.MergeUserOption)
+
+	target, err := user_model.GetUserByName(ctx, form.Target)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Errorf("target user %s does not exist", form.Target))
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+		}
+		return
+	}
+
+	report, err := user_service.MergeUser(ctx, ctx.Doer, ctx.ContextUser, target, form.DryRun)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "MergeUser", err)
+		}
+		return
+	}
+
+	status := http.StatusOK
+	if len(report.Conflicts) > 0 && !form.DryRun {
+		status = http.StatusConflict
+	}
+	ctx.JSON(status, toUserMergeReport(ctx.ContextUser, target, form.DryRun, report))
+}
@@ -181,6 +181,11 @@ func repoAssignment() func(ctx *context.APIContext) {
 			}
 			return
 		}
+		// deleted repositories are hidden until they are restored or purged
+		if repo.IsPendingDeletion() {
+			ctx.NotFound()
+			return
+		}
 
 		repo.Owner = owner
 		ctx.Repo.Repository = repo
@@ -192,17 +197,12 @@ func repoAssignment() func(ctx *context.APIContext) {
 				ctx.Error(http.StatusInternalServerError, "actions_model.GetTaskByID", err)
 				return
 			}
-			if task.RepoID != repo.ID {
+			ctx.Repo.Permission.AccessMode = task.RepoAccessMode(repo)
+			if ctx.Repo.Permission.AccessMode == perm.AccessModeNone {
 				ctx.NotFound()
 				return
 			}
 
-			if task.IsForkPullRequest {
-				ctx.Repo.Permission.AccessMode = perm.AccessModeRead
-			} else {
-				ctx.Repo.Permission.AccessMode = perm.AccessModeWrite
-			}
-
 			if err := ctx.Repo.Repository.LoadUnits(ctx); err != nil {
 				ctx.Error(http.StatusInternalServerError, "LoadUnits", err)
 				return
@@ -362,6 +362,16 @@ func reqRepoReader(unitType unit.Type) func(ctx *context.APIContext) {
 	}
 }
 
+// reqRepoCodeUnrestricted user should be able to read all paths of the code
+func reqRepoCodeUnrestricted() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if ctx.Repo.Permission.IsCodePathRestricted() {
+			ctx.Error(http.StatusForbidden, "reqRepoCodeUnrestricted", "user may only read some paths of the code")
+			return
+		}
+	}
+}
+
 // reqAnyRepoReader user should have any permission to read repository or permissions of site admin
 func reqAnyRepoReader() func(ctx *context.APIContext) {
 	return func(ctx *context.APIContext) {
@@ -441,6 +451,33 @@ func reqTeamMembership() func(ctx *context.APIContext) {
 	}
 }
 
+// reqTeamLeadOrOrgOwnership user should be a lead of the team, an organization owner or a site admin
+func reqTeamLeadOrOrgOwnership() func(ctx *context.APIContext) {
+	return func(ctx *context.APIContext) {
+		if ctx.Context.IsUserSiteAdmin() {
+			return
+		}
+		if ctx.Org.Team == nil {
+			ctx.Error(http.StatusInternalServerError, "", "reqTeamLeadOrOrgOwnership: unprepared context")
+			return
+		}
+
+		isOwner, err := organization.IsOrganizationOwner(ctx, ctx.Org.Team.OrgID, ctx.Doer.ID)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "IsOrganizationOwner", err)
+			return
+		} else if isOwner {
+			return
+		}
+
+		if isLead, err := organization.IsTeamLead(ctx, ctx.Org.Team.ID, ctx.Doer.ID); err != nil {
+			ctx.Error(http.StatusInternalServerError, "IsTeamLead", err)
+		} else if !isLead {
+			ctx.Error(http.StatusForbidden, "", "Must be a team lead or an organization owner")
+		}
+	}
+}
+
 // reqOrgMembership user should be an organization member, or a site admin
 func reqOrgMembership() func(ctx *context.APIContext) {
 	return func(ctx *context.APIContext) {
@@ -619,6 +656,13 @@ func mustEnableWiki(ctx *context.APIContext) {
 	}
 }
 
+func mustEnableDiscussions(ctx *context.APIContext) {
+	if !ctx.Repo.CanRead(unit.TypeDiscussions) {
+		ctx.NotFound()
+		return
+	}
+}
+
 func mustNotBeArchived(ctx *context.APIContext) {
 	if ctx.Repo.Repository.IsArchived {
 		ctx.NotFound()
@@ -715,6 +759,10 @@ func Routes(ctx gocontext.Context) *web.Route {
 				}, context_service.UserIDAssignmentAPI())
 			})
 		}
+		if setting.FederatedSearch.Enabled {
+			m.Get("/federation/search", misc.SearchPublic)
+			m.Get("/federation/search/all", reqToken(""), misc.FederatedSearch)
+		}
 		m.Get("/signing-key.gpg", misc.SigningKey)
 		m.Post("/markup", bind(api.MarkupOption{}), misc.Markup)
 		m.Post("/markdown", bind(api.MarkdownOption{}), misc.Markdown)
@@ -723,8 +771,12 @@ func Routes(ctx gocontext.Context) *web.Route {
 		m.Get("/gitignore/templates/{name}", misc.GetGitignoreTemplateInfo)
 		m.Get("/licenses", misc.ListLicenseTemplates)
 		m.Get("/licenses/{name}", misc.GetLicenseTemplateInfo)
+		m.Post("/reports", reqToken(""), bind(api.CreateAbuseReportOption{}), misc.ReportAbuse)
+		m.Post("/mailer/events/{provider}", misc.MailEvents)
+		m.Post("/mirror-sync/{owner}/{repo}", repo.ReceiveMirrorSyncHook)
 		m.Group("/settings", func() {
 			m.Get("/ui", settings.GetGeneralUISettings)
+			m.Get("/themes", settings.ListThemes)
 			m.Get("/api", settings.GetGeneralAPISettings)
 			m.Get("/attachment", settings.GetGeneralAttachmentSettings)
 			m.Get("/repository", settings.GetGeneralRepoSettings)
@@ -760,6 +812,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 				}, reqBasicAuth())
 
 				m.Get("/activities/feeds", user.ListUserActivityFeeds)
+				m.Get("/status", reqExploreSignIn(), user.GetStatus)
 			}, context_service.UserAssignmentAPI())
 		})
 
@@ -801,12 +854,52 @@ func Routes(ctx gocontext.Context) *web.Route {
 				}, context_service.UserAssignmentAPI())
 			})
 
+			m.Combo("/status").Get(reqToken(auth_model.AccessTokenScopeReadUser), user.GetMyStatus).
+				Put(reqToken(auth_model.AccessTokenScopeUser), bind(api.SetUserStatusOption{}), user.SetMyStatus).
+				Delete(reqToken(auth_model.AccessTokenScopeUser), user.ClearMyStatus)
+
+			m.Group("/linked_identities", func() {
+				m.Combo("").Get(reqToken(auth_model.AccessTokenScopeReadUser), user.ListMyLinkedIdentities).
+					Post(reqToken(auth_model.AccessTokenScopeUser), bind(api.CreateLinkedIdentityOption{}), user.CreateLinkedIdentity)
+				m.Delete("/{id}", reqToken(auth_model.AccessTokenScopeUser), user.DeleteLinkedIdentity)
+				m.Post("/{id}/verify", reqToken(auth_model.AccessTokenScopeUser), user.VerifyLinkedIdentity)
+			}, func(ctx *context.APIContext) {
+				if !setting.Service.EnableLinkedIdentities {
+					ctx.NotFound()
+				}
+			})
+
+			m.Group("/redirects", func() {
+				m.Get("", reqToken(auth_model.AccessTokenScopeReadUser), user.ListMyRedirects)
+				m.Delete("/{name}", reqToken(auth_model.AccessTokenScopeUser), user.DeleteMyRedirect)
+			})
+
+			m.Group("/blocks", func() {
+				m.Get("", user.ListMyBlocks)
+				m.Combo("/{username}").Put(bind(api.BlockUserOption{}), user.BlockUser).
+					Delete(user.UnblockUser)
+			}, reqToken(auth_model.AccessTokenScopeUser))
+
+			m.Group("/subscription_rules", func() {
+				m.Combo("").Get(reqToken(auth_model.AccessTokenScopeReadUser), user.ListMySubscriptionRules).
+					Post(reqToken(auth_model.AccessTokenScopeUser), bind(api.CreateIssueSubscriptionRuleOption{}), user.CreateSubscriptionRule)
+				m.Delete("/{id}", reqToken(auth_model.AccessTokenScopeUser), user.DeleteSubscriptionRule)
+			})
+
+			m.Group("/deleted_repos", func() {
+				m.Get("", reqToken(auth_model.AccessTokenScopeRepo), user.ListMyDeletedRepos)
+				m.Post("/{id}/restore", reqToken(auth_model.AccessTokenScopeRepo), user.RestoreDeletedRepo)
+				m.Delete("/{id}", reqToken(auth_model.AccessTokenScopeDeleteRepo), user.PurgeDeletedRepo)
+			})
+
 			// (admin:public_key scope)
 			m.Group("/keys", func() {
 				m.Combo("").Get(reqToken(auth_model.AccessTokenScopeReadPublicKey), user.ListMyPublicKeys).
 					Post(reqToken(auth_model.AccessTokenScopeWritePublicKey), bind(api.CreateKeyOption{}), user.CreatePublicKey)
 				m.Combo("/{id}").Get(reqToken(auth_model.AccessTokenScopeReadPublicKey), user.GetPublicKey).
 					Delete(reqToken(auth_model.AccessTokenScopeWritePublicKey), user.DeletePublicKey)
+				m.Post("/{id}/rotate", reqToken(auth_model.AccessTokenScopeWritePublicKey), bind(api.RotatePublicKeyOption{}), user.RotatePublicKey)
+				m.Put("/{id}/expiry", reqToken(auth_model.AccessTokenScopeWritePublicKey), bind(api.SetPublicKeyExpiryOption{}), user.SetPublicKeyExpiry)
 			})
 
 			// (admin:application scope)
@@ -826,6 +919,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 					Post(reqToken(auth_model.AccessTokenScopeWriteGPGKey), bind(api.CreateGPGKeyOption{}), user.CreateGPGKey)
 				m.Combo("/{id}").Get(reqToken(auth_model.AccessTokenScopeReadGPGKey), user.GetGPGKey).
 					Delete(reqToken(auth_model.AccessTokenScopeWriteGPGKey), user.DeleteGPGKey)
+				m.Post("/{id}/rotate", reqToken(auth_model.AccessTokenScopeWriteGPGKey), bind(api.RotateGPGKeyOption{}), user.RotateGPGKey)
 			})
 			m.Get("/gpg_key_token", reqToken(auth_model.AccessTokenScopeReadGPGKey), user.GetVerificationToken)
 			m.Post("/gpg_key_verify", reqToken(auth_model.AccessTokenScopeReadGPGKey), bind(api.VerifyGPGKeyOption{}), user.VerifyUserGPGKey)
@@ -853,6 +947,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 				m.Combo("/{id}").Get(user.GetHook).
 					Patch(bind(api.EditHookOption{}), user.EditHook).
 					Delete(user.DeleteHook)
+				m.Post("/{id}/secret/rotate", bind(api.RotateHookSecretOption{}), user.RotateHookSecret)
 			}, reqToken(auth_model.AccessTokenScopeAdminUserHook), reqWebhooksEnabled())
 		}, reqToken(""))
 
@@ -898,6 +993,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 							Patch(reqToken(auth_model.AccessTokenScopeWriteRepoHook), bind(api.EditHookOption{}), repo.EditHook).
 							Delete(reqToken(auth_model.AccessTokenScopeWriteRepoHook), repo.DeleteHook)
 						m.Post("/tests", reqToken(auth_model.AccessTokenScopeReadRepoHook), context.ReferencesGitRepo(), context.RepoRefForAPI, repo.TestHook)
+						m.Post("/secret/rotate", reqToken(auth_model.AccessTokenScopeWriteRepoHook), bind(api.RotateHookSecretOption{}), repo.RotateHookSecret)
 					})
 				}, reqAdmin(), reqWebhooksEnabled())
 				m.Group("/collaborators", func() {
@@ -919,9 +1015,30 @@ func Routes(ctx gocontext.Context) *web.Route {
 				}, reqToken(auth_model.AccessTokenScopeRepo))
 				m.Get("/raw/*", context.ReferencesGitRepo(), context.RepoRefForAPI, reqRepoReader(unit.TypeCode), repo.GetRawFile)
 				m.Get("/media/*", context.ReferencesGitRepo(), context.RepoRefForAPI, reqRepoReader(unit.TypeCode), repo.GetRawFileOrLFS)
-				m.Get("/archive/*", reqRepoReader(unit.TypeCode), repo.GetArchive)
+				m.Get("/archive/*", reqRepoReader(unit.TypeCode), reqRepoCodeUnrestricted(), repo.GetArchive)
 				m.Combo("/forks").Get(repo.ListForks).
 					Post(reqToken(auth_model.AccessTokenScopeRepo), reqRepoReader(unit.TypeCode), bind(api.CreateForkOption{}), repo.CreateFork)
+				m.Get("/compare/*", context.ReferencesGitRepo(), reqRepoReader(unit.TypeCode), reqRepoCodeUnrestricted(), repo.CompareDiff)
+				m.Group("/forks", func() {
+					m.Get("/network", repo.ListForkNetwork)
+					m.Get("/commits/{sha}", repo.FindCommitInForks)
+				}, reqRepoReader(unit.TypeCode))
+				m.Post("/merge-upstream", reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived, reqRepoWriter(unit.TypeCode), bind(api.MergeUpstreamOption{}), repo.MergeUpstream)
+				m.Group("/fork_syncs", func() {
+					m.Combo("").Get(repo.ListForkSyncs).
+						Post(mustNotBeArchived, bind(api.CreateForkSyncOption{}), repo.CreateForkSync)
+					m.Delete("/*", repo.DeleteForkSync)
+				}, reqToken(auth_model.AccessTokenScopeRepo), reqRepoWriter(unit.TypeCode))
+				m.Group("/vendor_syncs", func() {
+					m.Combo("").Get(repo.ListVendorSyncs).
+						Post(mustNotBeArchived, bind(api.CreateVendorSyncOption{}), repo.CreateVendorSync)
+					m.Group("/{id}", func() {
+						m.Combo("").Get(repo.GetVendorSync).
+							Patch(bind(api.EditVendorSyncOption{}), repo.EditVendorSync).
+							Delete(repo.DeleteVendorSync)
+						m.Post("/run", mustNotBeArchived, repo.RunVendorSync)
+					})
+				}, reqToken(auth_model.AccessTokenScopeRepo), reqRepoWriter(unit.TypeCode))
 				m.Group("/branches", func() {
 					m.Get("", repo.ListBranches)
 					m.Get("/*", repo.GetBranch)
@@ -937,6 +1054,8 @@ func Routes(ctx gocontext.Context) *web.Route {
 						m.Delete("", repo.DeleteBranchProtection)
 					})
 				}, reqToken(auth_model.AccessTokenScopeRepo), reqAdmin())
+				m.Combo("/settings", reqToken(auth_model.AccessTokenScopeRepo), reqAdmin()).Get(repo.GetSettings).
+					Put(bind(api.RepoSettings{}), repo.ApplySettings)
 				m.Group("/tags", func() {
 					m.Get("", repo.ListTags)
 					m.Get("/*", repo.GetTag)
@@ -948,6 +1067,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 						Post(bind(api.CreateKeyOption{}), repo.CreateDeployKey)
 					m.Combo("/{id}").Get(repo.GetDeployKey).
 						Delete(repo.DeleteDeploykey)
+					m.Get("/{id}/usages", repo.ListDeployKeyUsages)
 				}, reqToken(auth_model.AccessTokenScopeRepo), reqAdmin())
 				m.Group("/times", func() {
 					m.Combo("").Get(repo.ListTrackedTimesByRepository)
@@ -965,6 +1085,8 @@ func Routes(ctx gocontext.Context) *web.Route {
 				m.Group("/issues", func() {
 					m.Combo("").Get(repo.ListIssues).
 						Post(reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived, bind(api.CreateIssueOption{}), repo.CreateIssue)
+					m.Get("/export", reqToken(auth_model.AccessTokenScopeRepo), reqAdmin(), repo.ExportIssues)
+					m.Post("/import", reqToken(auth_model.AccessTokenScopeRepo), reqAdmin(), mustNotBeArchived, bind(api.ImportIssuesOption{}), repo.ImportIssues)
 					m.Group("/comments", func() {
 						m.Get("", repo.ListRepoIssueComments)
 						m.Group("/{id}", func() {
@@ -976,6 +1098,13 @@ func Routes(ctx gocontext.Context) *web.Route {
 								Get(repo.GetIssueCommentReactions).
 								Post(reqToken(auth_model.AccessTokenScopeRepo), bind(api.EditReactionOption{}), repo.PostIssueCommentReaction).
 								Delete(reqToken(auth_model.AccessTokenScopeRepo), bind(api.EditReactionOption{}), repo.DeleteIssueCommentReaction)
+							m.Combo("/hide", reqToken(auth_model.AccessTokenScopeRepo)).
+								Post(bind(api.HideCommentOption{}), repo.HideIssueComment).
+								Delete(repo.UnhideIssueComment)
+							m.Get("/replies", repo.ListIssueCommentReplies)
+							m.Combo("/collapse", reqToken(auth_model.AccessTokenScopeRepo)).
+								Post(repo.CollapseIssueCommentThread).
+								Delete(repo.ExpandIssueCommentThread)
 							m.Group("/assets", func() {
 								m.Combo("").
 									Get(repo.ListIssueCommentAttachments).
@@ -998,6 +1127,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 								Delete(repo.DeleteIssueCommentDeprecated)
 						})
 						m.Get("/timeline", repo.ListIssueCommentsAndTimeline)
+						m.Post("/convert-to-discussion", reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived, reqRepoWriter(unit.TypeIssues), bind(api.ConvertIssueToDiscussionOption{}), repo.ConvertIssueToDiscussion)
 						m.Group("/labels", func() {
 							m.Combo("").Get(repo.ListIssueLabels).
 								Post(reqToken(auth_model.AccessTokenScopeRepo), bind(api.IssueLabelsOption{}), repo.AddIssueLabels).
@@ -1013,6 +1143,9 @@ func Routes(ctx gocontext.Context) *web.Route {
 							m.Delete("/{id}", repo.DeleteTime)
 						}, reqToken(auth_model.AccessTokenScopeRepo))
 						m.Combo("/deadline").Post(reqToken(auth_model.AccessTokenScopeRepo), bind(api.EditDeadlineOption{}), repo.UpdateIssueDeadline)
+						m.Combo("/lock", reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived).
+							Put(bind(api.LockIssueOption{}), repo.LockIssue).
+							Delete(repo.UnlockIssue)
 						m.Group("/stopwatch", func() {
 							m.Post("/start", reqToken(auth_model.AccessTokenScopeRepo), repo.StartIssueStopwatch)
 							m.Post("/stop", reqToken(auth_model.AccessTokenScopeRepo), repo.StopIssueStopwatch)
@@ -1047,6 +1180,33 @@ func Routes(ctx gocontext.Context) *web.Route {
 							Delete(reqToken(auth_model.AccessTokenScopeRepo), bind(api.IssueMeta{}), repo.RemoveIssueBlocking)
 					})
 				}, mustEnableIssuesOrPulls)
+				m.Group("/discussions", func() {
+					m.Combo("").Get(repo.ListDiscussions).
+						Post(reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived, bind(api.CreateDiscussionOption{}), repo.CreateDiscussion)
+					m.Group("/categories", func() {
+						m.Combo("").Get(repo.ListDiscussionCategories).
+							Post(reqToken(auth_model.AccessTokenScopeRepo), reqRepoWriter(unit.TypeDiscussions), bind(api.CreateDiscussionCategoryOption{}), repo.CreateDiscussionCategory)
+						m.Combo("/{id}", reqToken(auth_model.AccessTokenScopeRepo), reqRepoWriter(unit.TypeDiscussions)).
+							Patch(bind(api.EditDiscussionCategoryOption{}), repo.EditDiscussionCategory).
+							Delete(repo.DeleteDiscussionCategory)
+					})
+					m.Group("/{index}", func() {
+						m.Combo("").Get(repo.GetDiscussion).
+							Patch(reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived, bind(api.EditDiscussionOption{}), repo.EditDiscussion).
+							Delete(reqToken(auth_model.AccessTokenScopeRepo), reqRepoWriter(unit.TypeDiscussions), repo.DeleteDiscussion)
+						m.Combo("/answer", reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived).
+							Put(bind(api.SetDiscussionAnswerOption{}), repo.SetDiscussionAnswer).
+							Delete(repo.DeleteDiscussionAnswer)
+						m.Post("/convert", reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived, reqRepoWriter(unit.TypeDiscussions), repo.ConvertDiscussionToIssue)
+						m.Group("/comments", func() {
+							m.Combo("").Get(repo.ListDiscussionComments).
+								Post(reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived, bind(api.CreateDiscussionCommentOption{}), repo.CreateDiscussionComment)
+							m.Combo("/{id}", reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived).
+								Patch(bind(api.EditDiscussionCommentOption{}), repo.EditDiscussionComment).
+								Delete(repo.DeleteDiscussionComment)
+						})
+					})
+				}, mustEnableDiscussions)
 				m.Group("/labels", func() {
 					m.Combo("").Get(repo.ListLabels).
 						Post(reqToken(auth_model.AccessTokenScopeRepo), reqRepoWriter(unit.TypeIssues, unit.TypePullRequests), bind(api.CreateLabelOption{}), repo.CreateLabel)
@@ -1094,6 +1254,19 @@ func Routes(ctx gocontext.Context) *web.Route {
 					})
 				}, reqRepoReader(unit.TypeReleases))
 				m.Post("/mirror-sync", reqToken(auth_model.AccessTokenScopeRepo), reqRepoWriter(unit.TypeCode), repo.MirrorSync)
+				m.Combo("/mirror-sync/hook", reqToken(auth_model.AccessTokenScopeRepo), reqAdmin()).Get(repo.GetMirrorSyncHook).
+					Put(bind(api.EditMirrorSyncHookOption{}), repo.EditMirrorSyncHook).
+					Delete(repo.DeleteMirrorSyncHook)
+				m.Combo("/coverage").Get(reqRepoReader(unit.TypeCode), repo.GetLatestCoverageReport).
+					Post(reqToken(auth_model.AccessTokenScopeRepo), reqRepoWriter(unit.TypeCode), bind(api.CreateCoverageReportOption{}), repo.UploadCoverageReport)
+				m.Combo("/badges/token", reqToken(auth_model.AccessTokenScopeRepo), reqAdmin()).Get(repo.GetBadgeToken).
+					Post(repo.RegenerateBadgeToken).
+					Delete(repo.DeleteBadgeToken)
+				m.Group("/embed_tokens", func() {
+					m.Combo("").Get(repo.ListEmbedTokens).
+						Post(bind(api.CreateEmbedTokenOption{}), repo.CreateEmbedToken)
+					m.Delete("/{id}", repo.DeleteEmbedToken)
+				}, reqToken(auth_model.AccessTokenScopeRepo), reqAdmin())
 				m.Post("/push_mirrors-sync", reqAdmin(), reqToken(auth_model.AccessTokenScopeRepo), repo.PushMirrorSync)
 				m.Group("/push_mirrors", func() {
 					m.Combo("").Get(repo.ListPushMirrors).
@@ -1102,21 +1275,61 @@ func Routes(ctx gocontext.Context) *web.Route {
 						Delete(repo.DeletePushMirrorByRemoteName).
 						Get(repo.GetPushMirrorByName)
 				}, reqAdmin(), reqToken(auth_model.AccessTokenScopeRepo))
+				m.Group("/download_links", func() {
+					m.Combo("").Get(repo.ListDownloadLinks).
+						Post(context.ReferencesGitRepo(), bind(api.CreateDownloadLinkOption{}), repo.CreateDownloadLink)
+					m.Delete("/{id}", repo.RevokeDownloadLink)
+				}, reqAdmin(), reqToken(auth_model.AccessTokenScopeRepo))
+				m.Group("/review_checklist", func() {
+					m.Combo("").Get(repo.ListReviewChecklist).
+						Post(reqToken(auth_model.AccessTokenScopeRepo), reqAdmin(), bind(api.CreateReviewChecklistItemOption{}), repo.CreateReviewChecklistItem)
+					m.Combo("/{id}").
+						Patch(reqToken(auth_model.AccessTokenScopeRepo), reqAdmin(), bind(api.EditReviewChecklistItemOption{}), repo.EditReviewChecklistItem).
+						Delete(reqToken(auth_model.AccessTokenScopeRepo), reqAdmin(), repo.DeleteReviewChecklistItem)
+				}, reqRepoReader(unit.TypePullRequests))
+				m.Group("/stale_policy", func() {
+					m.Combo("").Get(repo.GetStalePolicy).
+						Put(bind(api.EditStalePolicyOption{}), repo.EditStalePolicy).
+						Delete(repo.DeleteStalePolicy)
+				}, reqAdmin(), reqToken(auth_model.AccessTokenScopeRepo))
+				m.Group("/lock_policy", func() {
+					m.Combo("").Get(repo.GetIssueLockPolicy).
+						Put(bind(api.EditIssueLockPolicyOption{}), repo.EditIssueLockPolicy).
+						Delete(repo.DeleteIssueLockPolicy)
+				}, reqAdmin(), reqToken(auth_model.AccessTokenScopeRepo))
+				m.Group("/interaction_limit", func() {
+					m.Combo("").Get(repo.GetInteractionLimit).
+						Put(bind(api.EditInteractionLimitOption{}), repo.EditInteractionLimit).
+						Delete(repo.DeleteInteractionLimit)
+				}, reqAdmin(), reqToken(auth_model.AccessTokenScopeRepo))
 
-				m.Get("/editorconfig/{filename}", context.ReferencesGitRepo(), context.RepoRefForAPI, reqRepoReader(unit.TypeCode), repo.GetEditorconfig)
+				m.Get("/editorconfig/{filename}", context.ReferencesGitRepo(), context.RepoRefForAPI, reqRepoReader(unit.TypeCode), reqRepoCodeUnrestricted(), repo.GetEditorconfig)
+				m.Get("/insights", reqRepoReader(unit.TypePullRequests), repo.GetInsights)
+				m.Get("/insights/ownership", reqRepoReader(unit.TypeCode), repo.GetPathOwnership)
+				m.Post("/actions/runs/{run}/rerun-failed-jobs", reqToken(auth_model.AccessTokenScopeRepo), reqRepoWriter(unit.TypeActions), repo.RerunFailedJobs)
+				m.Post("/actions/runs/{run}/approve", reqToken(auth_model.AccessTokenScopeRepo), reqRepoWriter(unit.TypeActions), repo.ApproveRun)
+				m.Group("/actions/jobs/{job_id}/logs", func() {
+					m.Get("", repo.StreamActionJobLogs)
+					m.Get("/search", repo.SearchActionJobLogs)
+				}, reqRepoReader(unit.TypeActions))
 				m.Group("/pulls", func() {
 					m.Combo("").Get(repo.ListPullRequests).
 						Post(reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived, bind(api.CreatePullRequestOption{}), repo.CreatePullRequest)
 					m.Group("/{index}", func() {
 						m.Combo("").Get(repo.GetPullRequest).
 							Patch(reqToken(auth_model.AccessTokenScopeRepo), bind(api.EditPullRequestOption{}), repo.EditPullRequest)
-						m.Get(".{diffType:diff|patch}", repo.DownloadPullDiffOrPatch)
+						m.Get(".{diffType:diff|patch}", reqRepoCodeUnrestricted(), repo.DownloadPullDiffOrPatch)
 						m.Post("/update", reqToken(auth_model.AccessTokenScopeRepo), repo.UpdatePullRequest)
 						m.Get("/commits", repo.GetPullRequestCommits)
-						m.Get("/files", repo.GetPullRequestFiles)
+						m.Get("/files", reqRepoCodeUnrestricted(), repo.GetPullRequestFiles)
+						m.Get("/code_scanning", repo.ListPullRequestCodeScanningAlerts)
+						m.Get("/coverage", repo.GetPullRequestDiffCoverage)
 						m.Combo("/merge").Get(repo.IsPullRequestMerged).
 							Post(reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived, bind(forms.MergePullRequestForm{}), repo.MergePullRequest).
 							Delete(reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived, repo.CancelScheduledAutoMerge)
+						m.Combo("/merge-queue").
+							Post(reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived, bind(forms.MergePullRequestForm{}), repo.AddToMergeQueue).
+							Delete(reqToken(auth_model.AccessTokenScopeRepo), mustNotBeArchived, repo.RemoveFromMergeQueue)
 						m.Group("/reviews", func() {
 							m.Combo("").
 								Get(repo.ListPullReviews).
@@ -1137,10 +1350,21 @@ func Routes(ctx gocontext.Context) *web.Route {
 							Post(bind(api.PullReviewRequestOptions{}), repo.CreateReviewRequests)
 					})
 				}, mustAllowPulls, reqRepoReader(unit.TypeCode), context.ReferencesGitRepo())
+				m.Get("/merge-queue", mustAllowPulls, reqRepoReader(unit.TypeCode), repo.ListMergeQueue)
 				m.Group("/statuses", func() {
 					m.Combo("/{sha}").Get(repo.GetCommitStatuses).
 						Post(reqToken(auth_model.AccessTokenScopeRepoStatus), reqRepoWriter(unit.TypeCode), bind(api.CreateStatusOption{}), repo.NewCommitStatus)
+					m.Combo("/{sha}/attempts").Get(repo.ListCommitStatusAttempts).
+						Post(reqToken(auth_model.AccessTokenScopeRepoStatus), reqRepoWriter(unit.TypeCode), bind(api.CreateCommitStatusAttemptOption{}), repo.CreateCommitStatusAttempt)
 				}, reqRepoReader(unit.TypeCode))
+				m.Group("/flaky_checks", func() {
+					m.Combo("").Get(repo.ListFlakyChecks).
+						Post(reqAdmin(), bind(api.CreateFlakyCheckOption{}), repo.CreateFlakyCheck)
+					m.Get("/rates", repo.ListFlakeRates)
+					m.Combo("/{id}").Get(repo.GetFlakyCheck).
+						Patch(reqAdmin(), bind(api.EditFlakyCheckOption{}), repo.EditFlakyCheck).
+						Delete(reqAdmin(), repo.DeleteFlakyCheck)
+				}, reqToken(auth_model.AccessTokenScopeRepo), reqRepoReader(unit.TypeCode))
 				m.Group("/commits", func() {
 					m.Get("", context.ReferencesGitRepo(), repo.GetAllCommits)
 					m.Group("/{ref}", func() {
@@ -1151,16 +1375,22 @@ func Routes(ctx gocontext.Context) *web.Route {
 				m.Group("/git", func() {
 					m.Group("/commits", func() {
 						m.Get("/{sha}", repo.GetSingleCommit)
-						m.Get("/{sha}.{diffType:diff|patch}", repo.DownloadCommitDiffOrPatch)
+						m.Get("/{sha}.{diffType:diff|patch}", reqRepoCodeUnrestricted(), repo.DownloadCommitDiffOrPatch)
 					})
+					m.Get("/graph", repo.GetCommitGraph)
 					m.Get("/refs", repo.GetGitAllRefs)
 					m.Get("/refs/*", repo.GetGitRefs)
-					m.Get("/trees/{sha}", repo.GetTree)
-					m.Get("/blobs/{sha}", repo.GetBlob)
+					m.Get("/trees/{sha}", reqRepoCodeUnrestricted(), repo.GetTree)
+					m.Get("/blobs/{sha}", reqRepoCodeUnrestricted(), repo.GetBlob)
 					m.Get("/tags/{sha}", repo.GetAnnotatedTag)
 					m.Get("/notes/{sha}", repo.GetNote)
 				}, context.ReferencesGitRepo(true), reqRepoReader(unit.TypeCode))
 				m.Post("/diffpatch", reqRepoWriter(unit.TypeCode), reqToken(auth_model.AccessTokenScopeRepo), bind(api.ApplyDiffPatchFileOptions{}), repo.ApplyDiffPatch)
+				m.Post("/cherry-pick", reqRepoWriter(unit.TypeCode), reqToken(auth_model.AccessTokenScopeRepo), bind(api.CherryPickOptions{}), repo.CherryPick)
+				m.Group("/readme", func() {
+					m.Get("", repo.GetReadme)
+					m.Get("/*", repo.GetDirReadme)
+				}, reqRepoReader(unit.TypeCode))
 				m.Group("/contents", func() {
 					m.Get("", repo.GetContentsList)
 					m.Get("/*", repo.GetContents)
@@ -1183,18 +1413,44 @@ func Routes(ctx gocontext.Context) *web.Route {
 				m.Get("/issue_config", context.ReferencesGitRepo(), repo.GetIssueConfig)
 				m.Get("/issue_config/validate", context.ReferencesGitRepo(), repo.ValidateIssueConfig)
 				m.Get("/languages", reqRepoReader(unit.TypeCode), repo.GetLanguages)
+				m.Get("/licenses", reqRepoReader(unit.TypeCode), repo.GetLicenses)
+				m.Group("/code_scanning", func() {
+					m.Post("/sarifs", reqToken(auth_model.AccessTokenScopeRepo), reqRepoWriter(unit.TypeCode), bind(api.UploadSARIFOption{}), repo.UploadCodeScanningSARIF)
+					m.Get("/alerts", repo.ListCodeScanningAlerts)
+					m.Combo("/alerts/{id}").Get(repo.GetCodeScanningAlert).
+						Patch(reqToken(auth_model.AccessTokenScopeRepo), reqRepoWriter(unit.TypeCode), bind(api.EditCodeScanningAlertOption{}), repo.EditCodeScanningAlert)
+				}, reqRepoReader(unit.TypeCode))
 				m.Get("/activities/feeds", repo.ListRepoActivityFeeds)
 			}, repoAssignment())
 		})
 
 		// NOTE: these are Gitea package management API - see packages.CommonRoutes and packages.DockerContainerRoutes for endpoints that implement package manager APIs
+		m.Group("/packages/search", func() {
+			m.Get("", packages.SearchPackages)
+			m.Post("/batch", bind(api.BatchPackageMetadataOption{}), packages.BatchPackageMetadata)
+		}, reqToken(auth_model.AccessTokenScopeReadPackage))
 		m.Group("/packages/{username}", func() {
 			m.Group("/{type}/{name}/{version}", func() {
 				m.Get("", reqToken(auth_model.AccessTokenScopeReadPackage), packages.GetPackage)
 				m.Delete("", reqToken(auth_model.AccessTokenScopeDeletePackage), reqPackageAccess(perm.AccessModeWrite), packages.DeletePackage)
 				m.Get("/files", reqToken(auth_model.AccessTokenScopeReadPackage), packages.ListPackageFiles)
+				m.Combo("/deprecation", reqToken(auth_model.AccessTokenScopeWritePackage), reqPackageAccess(perm.AccessModeWrite)).
+					Put(bind(api.DeprecatePackageOption{}), packages.DeprecatePackage).
+					Delete(packages.UndeprecatePackage)
 			})
 			m.Get("/", reqToken(auth_model.AccessTokenScopeReadPackage), packages.ListPackages)
+			m.Group("/advisories", func() {
+				m.Combo("").Get(reqToken(auth_model.AccessTokenScopeReadPackage), packages.ListPackageAdvisories).
+					Post(reqToken(auth_model.AccessTokenScopeWritePackage), reqPackageAccess(perm.AccessModeWrite), bind(api.CreatePackageAdvisoryOption{}), packages.CreatePackageAdvisory)
+				m.Combo("/{id}", reqToken(auth_model.AccessTokenScopeWritePackage), reqPackageAccess(perm.AccessModeWrite)).
+					Patch(bind(api.EditPackageAdvisoryOption{}), packages.EditPackageAdvisory).
+					Delete(packages.DeletePackageAdvisory)
+			})
+			m.Group("/deleted", func() {
+				m.Get("", reqToken(auth_model.AccessTokenScopeReadPackage), packages.ListDeletedPackageVersions)
+				m.Post("/{id}/restore", reqToken(auth_model.AccessTokenScopeWritePackage), packages.RestoreDeletedPackageVersion)
+				m.Delete("/{id}", reqToken(auth_model.AccessTokenScopeDeletePackage), packages.PurgeDeletedPackageVersion)
+			}, reqPackageAccess(perm.AccessModeWrite))
 		}, context_service.UserAssignmentAPI(), context.PackageAssignmentAPI(), reqPackageAccess(perm.AccessModeRead))
 
 		// Organizations
@@ -1211,6 +1467,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 				Delete(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), org.Delete)
 			m.Combo("/repos").Get(user.ListOrgRepos).
 				Post(reqToken(auth_model.AccessTokenScopeWriteOrg), bind(api.CreateRepoOption{}), repo.CreateOrgRepo)
+			m.Post("/repos/settings/drift", reqToken(auth_model.AccessTokenScopeReadOrg), reqOrgOwnership(), bind(api.RepoSettings{}), org.GetReposSettingsDrift)
 			m.Group("/members", func() {
 				m.Get("", reqToken(auth_model.AccessTokenScopeReadOrg), org.ListMembers)
 				m.Combo("/{username}").Get(reqToken(auth_model.AccessTokenScopeReadOrg), org.IsMember).
@@ -1240,8 +1497,39 @@ func Routes(ctx gocontext.Context) *web.Route {
 				m.Combo("/{id}").Get(org.GetHook).
 					Patch(bind(api.EditHookOption{}), org.EditHook).
 					Delete(org.DeleteHook)
+				m.Post("/{id}/secret/rotate", bind(api.RotateHookSecretOption{}), org.RotateHookSecret)
 			}, reqToken(auth_model.AccessTokenScopeAdminOrgHook), reqOrgOwnership(), reqWebhooksEnabled())
 			m.Get("/activities/feeds", org.ListOrgActivityFeeds)
+			m.Get("/insights", reqToken(auth_model.AccessTokenScopeReadOrg), reqOrgOwnership(), org.GetInsights)
+			m.Get("/outside_collaborators", reqToken(auth_model.AccessTokenScopeReadOrg), reqOrgOwnership(), org.ListOutsideCollaborators)
+			m.Combo("/license_policy").Get(reqToken(auth_model.AccessTokenScopeReadOrg), reqOrgMembership(), org.GetLicensePolicy).
+				Put(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), bind(api.EditLicensePolicyOption{}), org.EditLicensePolicy).
+				Delete(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), org.DeleteLicensePolicy)
+			m.Combo("/avatar_policy").Get(reqToken(auth_model.AccessTokenScopeReadOrg), reqOrgMembership(), org.GetAvatarPolicy).
+				Put(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), bind(api.EditAvatarPolicyOption{}), org.EditAvatarPolicy).
+				Delete(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), org.DeleteAvatarPolicy)
+			m.Combo("/branding").Get(reqToken(auth_model.AccessTokenScopeReadOrg), reqOrgMembership(), org.GetBranding).
+				Put(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), bind(api.EditOrgBrandingOption{}), org.EditBranding).
+				Delete(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), org.DeleteBranding)
+			m.Combo("/attachment_policy").Get(reqToken(auth_model.AccessTokenScopeReadOrg), reqOrgMembership(), org.GetAttachmentPolicy).
+				Put(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), bind(api.EditAttachmentPolicyOption{}), org.EditAttachmentPolicy).
+				Delete(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), org.DeleteAttachmentPolicy)
+			m.Combo("/archive_policy").Get(reqToken(auth_model.AccessTokenScopeReadOrg), reqOrgMembership(), org.GetArchivePolicy).
+				Put(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), bind(api.EditArchivePolicyOption{}), org.EditArchivePolicy).
+				Delete(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), org.DeleteArchivePolicy)
+			m.Get("/archive_policy/upcoming", reqToken(auth_model.AccessTokenScopeReadOrg), reqOrgOwnership(), org.ListUpcomingArchivals)
+			m.Get("/group_sync/logs", reqToken(auth_model.AccessTokenScopeReadOrg), reqOrgOwnership(), org.ListTeamGroupSyncLogs)
+			m.Group("/templates/{kind}", func() {
+				m.Get("", reqToken(auth_model.AccessTokenScopeReadOrg), org.ListTemplateCatalogItems)
+				m.Combo("/{name}").Get(reqToken(auth_model.AccessTokenScopeReadOrg), org.GetTemplateCatalogItem).
+					Put(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), bind(api.EditTemplateCatalogItemOption{}), org.EditTemplateCatalogItem).
+					Delete(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), org.DeleteTemplateCatalogItem)
+			}, reqOrgMembership())
+			m.Group("/blocks", func() {
+				m.Get("", org.ListBlocks)
+				m.Combo("/{username}").Put(bind(api.BlockUserOption{}), org.BlockUser).
+					Delete(org.UnblockUser)
+			}, reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership())
 		}, orgAssignment(true))
 		m.Group("/teams/{teamid}", func() {
 			m.Combo("").Get(reqToken(auth_model.AccessTokenScopeReadOrg), org.GetTeam).
@@ -1254,6 +1542,12 @@ func Routes(ctx gocontext.Context) *web.Route {
 					Put(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), org.AddTeamMember).
 					Delete(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), org.RemoveTeamMember)
 			})
+			m.Group("/leads", func() {
+				m.Get("", reqToken(auth_model.AccessTokenScopeReadOrg), org.ListTeamLeads)
+				m.Combo("/{username}").
+					Put(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), org.AddTeamLead).
+					Delete(reqToken(auth_model.AccessTokenScopeWriteOrg), reqOrgOwnership(), org.RemoveTeamLead)
+			})
 			m.Group("/repos", func() {
 				m.Get("", reqToken(auth_model.AccessTokenScopeReadOrg), org.GetTeamRepos)
 				m.Combo("/{org}/{reponame}").
@@ -1261,7 +1555,26 @@ func Routes(ctx gocontext.Context) *web.Route {
 					Delete(reqToken(auth_model.AccessTokenScopeWriteOrg), org.RemoveTeamRepository).
 					Get(reqToken(auth_model.AccessTokenScopeReadOrg), org.GetTeamRepo)
 			})
+			m.Group("/reminders", func() {
+				m.Combo("").Get(reqToken(auth_model.AccessTokenScopeReadOrg), org.ListTeamReminders).
+					Post(reqToken(auth_model.AccessTokenScopeWriteOrg), bind(api.CreateTeamReminderOption{}), org.CreateTeamReminder)
+				m.Group("/{reminderid}", func() {
+					m.Combo("").Get(reqToken(auth_model.AccessTokenScopeReadOrg), org.GetTeamReminder).
+						Patch(reqToken(auth_model.AccessTokenScopeWriteOrg), bind(api.EditTeamReminderOption{}), org.EditTeamReminder).
+						Delete(reqToken(auth_model.AccessTokenScopeWriteOrg), org.DeleteTeamReminder)
+					m.Post("/test", reqToken(auth_model.AccessTokenScopeWriteOrg), org.TestTeamReminder)
+				})
+			}, reqOrgOwnership())
+			m.Group("/group_sync", func() {
+				m.Combo("").Get(reqToken(auth_model.AccessTokenScopeReadOrg), org.GetTeamGroupSync).
+					Put(reqToken(auth_model.AccessTokenScopeWriteOrg), bind(api.EditTeamGroupSyncOption{}), org.EditTeamGroupSync)
+				m.Post("/run", reqToken(auth_model.AccessTokenScopeWriteOrg), org.RunTeamGroupSync)
+			}, reqOrgOwnership())
+			m.Combo("/homepage").Get(reqToken(auth_model.AccessTokenScopeReadOrg), org.GetTeamHomepage).
+				Put(reqToken(auth_model.AccessTokenScopeWriteOrg), reqTeamLeadOrOrgOwnership(), bind(api.EditTeamHomepageOption{}), org.EditTeamHomepage)
+			m.Get("/milestones", reqToken(auth_model.AccessTokenScopeReadOrg), org.ListTeamMilestones)
 			m.Get("/activities/feeds", org.ListTeamActivityFeeds)
+			m.Get("/activities/summary", org.GetTeamActivitySummary)
 		}, orgAssignment(false, true), reqToken(""), reqTeamMembership())
 
 		m.Group("/admin", func() {
@@ -1270,6 +1583,15 @@ func Routes(ctx gocontext.Context) *web.Route {
 				m.Post("/{task}", admin.PostCronTask)
 			})
 			m.Get("/orgs", admin.GetAllOrgs)
+			m.Post("/auth_sources/migrate_users", bind(api.MigrateAuthSourceUsersOption{}), admin.MigrateAuthSourceUsers)
+			m.Post("/auth_sources/{id}/test", admin.TestAuthSource)
+			m.Put("/external_groups/{group}/members", bind(api.ExternalGroupMembersOption{}), admin.SetExternalGroupMembers)
+			m.Group("/templates/{kind}", func() {
+				m.Get("", admin.ListTemplateCatalogItems)
+				m.Combo("/{name}").Get(admin.GetTemplateCatalogItem).
+					Put(bind(api.EditTemplateCatalogItemOption{}), admin.EditTemplateCatalogItem).
+					Delete(admin.DeleteTemplateCatalogItem)
+			})
 			m.Group("/users", func() {
 				m.Get("", admin.SearchUsers)
 				m.Post("", bind(api.CreateUserOption{}), admin.CreateUser)
@@ -1284,23 +1606,108 @@ func Routes(ctx gocontext.Context) *web.Route {
 					m.Post("/orgs", bind(api.CreateOrgOption{}), admin.CreateOrg)
 					m.Post("/repos", bind(api.CreateRepoOption{}), admin.CreateRepo)
 					m.Post("/rename", bind(api.RenameUserOption{}), admin.RenameUser)
+					m.Post("/merge", bind(api.MergeUserOption{}), admin.MergeUser)
+					m.Group("/redirects", func() {
+						m.Get("", admin.ListUserRedirects)
+						m.Delete("/{name}", admin.DeleteUserRedirect)
+					})
 				}, context_service.UserAssignmentAPI())
 			})
 			m.Group("/emails", func() {
 				m.Get("", admin.GetAllEmails)
 				m.Get("/search", admin.SearchEmail)
 			})
+			m.Get("/deleted_repos", admin.ListDeletedRepositories)
 			m.Group("/unadopted", func() {
 				m.Get("", admin.ListUnadoptedRepositories)
 				m.Post("/{username}/{reponame}", admin.AdoptRepository)
 				m.Delete("/{username}/{reponame}", admin.DeleteUnadoptedRepository)
 			})
+			m.Group("/maintenance", func() {
+				m.Get("", admin.ListRepoMaintenance)
+				m.Combo("/{username}/{reponame}").Get(admin.GetRepoMaintenance).
+					Post(admin.RunRepoMaintenance)
+			})
+			m.Group("/chatops", func() {
+				m.Combo("/commands").Get(admin.ListChatOpsCommands).
+					Post(bind(api.CreateChatOpsCommandOption{}), admin.CreateChatOpsCommand)
+				m.Combo("/commands/{id}").Get(admin.GetChatOpsCommand).
+					Patch(bind(api.EditChatOpsCommandOption{}), admin.EditChatOpsCommand).
+					Delete(admin.DeleteChatOpsCommand)
+				m.Get("/audit", admin.ListChatOpsAudit)
+			})
+			m.Group("/legal_holds", func() {
+				m.Combo("").Get(admin.ListLegalHolds).
+					Post(bind(api.CreateLegalHoldOption{}), admin.CreateLegalHold)
+				m.Delete("/{id}", admin.DeleteLegalHold)
+			})
+			m.Combo("/redactions").Get(admin.ListLegalRedactions).
+				Post(bind(api.CreateLegalRedactionOption{}), admin.CreateLegalRedaction)
+			m.Group("/reports", func() {
+				m.Get("", admin.ListAbuseReports)
+				m.Patch("/{id}", bind(api.EditAbuseReportOption{}), admin.EditAbuseReport)
+			})
+			m.Group("/spam", func() {
+				m.Get("", admin.ListHeldContent)
+				m.Post("/{id}/approve", admin.ApproveHeldContent)
+				m.Post("/{id}/reject", admin.RejectHeldContent)
+			})
+			m.Group("/git_hooks", func() {
+				m.Combo("").Get(admin.ListManagedHooks).
+					Post(bind(api.CreateManagedHookOption{}), admin.CreateManagedHook)
+				m.Combo("/{id}").Patch(bind(api.EditManagedHookOption{}), admin.EditManagedHook).
+					Delete(admin.DeleteManagedHook)
+			})
+			m.Group("/themes", func() {
+				m.Combo("").Get(admin.ListThemes).
+					Post(bind(api.CreateThemeOption{}), admin.CreateTheme)
+				m.Combo("/{name}").Get(admin.GetTheme).
+					Patch(bind(api.EditThemeOption{}), admin.EditTheme).
+					Delete(admin.DeleteTheme)
+				m.Combo("/{name}/logo").Put(admin.UploadThemeLogo).
+					Delete(admin.DeleteThemeLogo)
+			})
+			m.Put("/oauth2/{id}/token_lifetimes", bind(api.EditOAuth2TokenLifetimesOption{}), admin.EditOAuth2TokenLifetimes)
+			m.Group("/translations", func() {
+				m.Get("", admin.ListTranslationOverrides)
+				m.Delete("/{lang}", admin.DeleteTranslationOverrides)
+				m.Post("/{lang}/upload", admin.UploadLocalePack)
+				m.Combo("/{lang}/{key}").Put(bind(api.SetTranslationOverrideOption{}), admin.SetTranslationOverride).
+					Delete(admin.DeleteTranslationOverride)
+			})
+			m.Get("/storage_regions", admin.ListStorageRegions)
+			m.Post("/packages/go/verify", admin.VerifyGoModules)
+			m.Combo("/maintenance_mode").Get(admin.GetMaintenanceMode).
+				Put(bind(api.EnableMaintenanceModeOption{}), admin.EnableMaintenanceMode).
+				Delete(admin.DisableMaintenanceMode)
+			m.Get("/background_migrations", admin.ListBackgroundMigrations)
+			m.Get("/archivals", admin.ListUpcomingArchivals)
+			m.Group("/storage/usage", func() {
+				m.Get("", admin.ListStorageUsage)
+				m.Get("/{username}", admin.GetOwnerStorageUsage)
+				m.Get("/{username}/{reponame}", admin.GetRepoStorageUsage)
+			})
+			m.Combo("/orgs/{org}/storage_region").Get(admin.GetOrgStorageRegion).
+				Put(bind(api.EditOrgStorageRegionOption{}), admin.EditOrgStorageRegion)
+			m.Group("/orgs/{org}/git_hooks", func() {
+				m.Combo("").Get(admin.ListOrgManagedHooks).
+					Post(bind(api.CreateManagedHookOption{}), admin.CreateOrgManagedHook)
+				m.Combo("/{id}").Patch(bind(api.EditManagedHookOption{}), admin.EditOrgManagedHook).
+					Delete(admin.DeleteOrgManagedHook)
+			})
+			m.Get("/mail/deliveries", admin.ListMailDeliveries)
+			m.Post("/mail/test", bind(api.SendTestMailOption{}), admin.SendTestMail)
+			m.Combo("/tokens").Get(admin.ListAccessTokens).
+				Delete(admin.RevokeAccessTokens)
+			m.Get("/webhooks", admin.ListInstanceHooks)
 			m.Group("/hooks", func() {
 				m.Combo("").Get(admin.ListHooks).
 					Post(bind(api.CreateHookOption{}), admin.CreateHook)
 				m.Combo("/{id}").Get(admin.GetHook).
 					Patch(bind(api.EditHookOption{}), admin.EditHook).
 					Delete(admin.DeleteHook)
+				m.Post("/{id}/secret/rotate", bind(api.RotateHookSecretOption{}), admin.RotateHookSecret)
+				m.Post("/{id}/test", admin.TestHook)
 			})
 		}, reqToken(auth_model.AccessTokenScopeSudo), reqSiteAdmin())
 
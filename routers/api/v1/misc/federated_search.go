@@ -0,0 +1,87 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package misc
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/services/federatedsearch"
+)
+
+func federatedSearchOptions(ctx *context.APIContext) federatedsearch.SearchOptions {
+	return federatedsearch.SearchOptions{
+		Keyword: ctx.FormTrim("q"),
+		Type:    ctx.FormTrim("type"),
+		Limit:   ctx.FormInt("limit"),
+	}
+}
+
+// SearchPublic searches the public issues, pull requests and wikis of this instance, it's requested by the peer instances
+func SearchPublic(ctx *context.APIContext) {
+	// swagger:operation GET /federation/search miscellaneous federationSearchPublic
+	// ---
+	// summary: Search the issues, pull requests and wiki pages of the public repositories of this instance
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: keyword
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: query
+	//   description: only search issues and pull requests or only wiki pages
+	//   type: string
+	//   enum: [issues, wiki]
+	// - name: limit
+	//   in: query
+	//   description: maximum number of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FederatedSearchResultList"
+
+	results, err := federatedsearch.SearchLocal(ctx, federatedSearchOptions(ctx))
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, results)
+}
+
+// FederatedSearch searches this instance and its peers
+func FederatedSearch(ctx *context.APIContext) {
+	// swagger:operation GET /federation/search/all miscellaneous federationSearch
+	// ---
+	// summary: Search the issues, pull requests and wiki pages of the public repositories of this instance and of its peers
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: q
+	//   in: query
+	//   description: keyword
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: query
+	//   description: only search issues and pull requests or only wiki pages
+	//   type: string
+	//   enum: [issues, wiki]
+	// - name: limit
+	//   in: query
+	//   description: maximum number of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FederatedSearchResponse"
+
+	resp, err := federatedsearch.Search(ctx, federatedSearchOptions(ctx))
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, resp)
+}
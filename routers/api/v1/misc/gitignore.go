@@ -6,8 +6,8 @@ package misc
 import (
 	"net/http"
 
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/context"
-	"code.gitea.io/gitea/modules/options"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
@@ -18,12 +18,18 @@ func ListGitignoresTemplates(ctx *context.APIContext) {
 	// swagger:operation GET /gitignore/templates miscellaneous listGitignoresTemplates
 	// ---
 	// summary: Returns a list of all gitignore templates
+	// description: The templates added to the catalog of the instance follow the built-in ones.
 	// produces:
 	// - application/json
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/GitignoreTemplateList"
-	ctx.JSON(http.StatusOK, repo_module.Gitignores)
+	gitignores, err := repo_module.GetTemplateCatalogNames(ctx, 0, repo_model.TemplateCatalogGitignore)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, gitignores)
 }
 
 // SHows information about a gitignore template
@@ -46,9 +52,13 @@ func GetGitignoreTemplateInfo(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 	name := util.PathJoinRelX(ctx.Params("name"))
 
-	text, err := options.Gitignore(name)
+	text, err := repo_module.GetTemplateCatalogContent(ctx, 0, repo_model.TemplateCatalogGitignore, name)
 	if err != nil {
-		ctx.NotFound()
+		if repo_model.IsErrTemplateCatalogItemNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
 		return
 	}
 
@@ -8,8 +8,8 @@ import (
 	"net/http"
 	"net/url"
 
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/context"
-	"code.gitea.io/gitea/modules/options"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
@@ -21,13 +21,19 @@ func ListLicenseTemplates(ctx *context.APIContext) {
 	// swagger:operation GET /licenses miscellaneous listLicenseTemplates
 	// ---
 	// summary: Returns a list of all license templates
+	// description: The licenses added to the catalog of the instance follow the built-in ones.
 	// produces:
 	// - application/json
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/LicenseTemplateList"
-	response := make([]api.LicensesTemplateListEntry, len(repo_module.Licenses))
-	for i, license := range repo_module.Licenses {
+	licenses, err := repo_module.GetTemplateCatalogNames(ctx, 0, repo_model.TemplateCatalogLicense)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	response := make([]api.LicensesTemplateListEntry, len(licenses))
+	for i, license := range licenses {
 		response[i] = api.LicensesTemplateListEntry{
 			Key:  license,
 			Name: license,
@@ -57,9 +63,13 @@ func GetLicenseTemplateInfo(ctx *context.APIContext) {
 	//     "$ref": "#/responses/notFound"
 	name := util.PathJoinRelX(ctx.Params("name"))
 
-	text, err := options.License(name)
+	text, err := repo_module.GetTemplateCatalogContent(ctx, 0, repo_model.TemplateCatalogLicense, name)
 	if err != nil {
-		ctx.NotFound()
+		if repo_model.IsErrTemplateCatalogItemNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
 		return
 	}
 
@@ -0,0 +1,72 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package misc
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// MailEvents receive delivery events of a mail provider
+func MailEvents(ctx *context.APIContext) {
+	// swagger:operation POST /mailer/events/{provider} miscellaneous mailEvents
+	// ---
+	// summary: Receive the delivery, bounce and complaint events of a mail provider
+	// consumes:
+	// - application/json
+	// parameters:
+	// - name: provider
+	//   in: path
+	//   description: name of the mail provider, "default" for the provider of the [mailer] section
+	//   type: string
+	//   required: true
+	// - name: token
+	//   in: query
+	//   description: the WEBHOOK_TOKEN of the mail provider
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if setting.MailService == nil {
+		ctx.NotFound()
+		return
+	}
+	name := ctx.Params(":provider")
+	opts := setting.MailService
+	if name != setting.DefaultMailProvider {
+		opts = setting.MailService.Providers[name]
+	}
+	provider, ok := mailer.GetProvider(name).(mailer.EventParser)
+	if opts == nil || opts.WebhookToken == "" || !ok {
+		ctx.NotFound()
+		return
+	}
+	if subtle.ConstantTimeCompare([]byte(ctx.FormString("token")), []byte(opts.WebhookToken)) != 1 {
+		ctx.Error(http.StatusForbidden, "", "invalid token")
+		return
+	}
+
+	events, err := provider.ParseEvents(ctx, ctx.Req)
+	if err != nil {
+		ctx.Error(http.StatusBadRequest, "ParseEvents", err)
+		return
+	}
+	if err := mailer.HandleDeliveryEvents(ctx, name, events); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,68 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package misc
+
+import (
+	"errors"
+	"net/http"
+
+	moderation_model "code.gitea.io/gitea/models/moderation"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+	"code.gitea.io/gitea/services/moderation"
+)
+
+// ReportAbuse report abusive content to the site administrators
+func ReportAbuse(ctx *context.APIContext) {
+	// swagger:operation POST /reports miscellaneous reportAbuse
+	// ---
+	// summary: Report a user, repository, issue or comment to the site administrators
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CreateAbuseReportOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/AbuseReport"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateAbuseReportOption)
+
+	report, err := moderation.ReportAbuse(ctx, ctx.Doer, moderation_model.ReportContentType(form.ContentType), form.ContentID,
+		moderation_model.ReportCategory(form.Category), form.Remarks)
+	if err != nil {
+		switch {
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "ReportAbuse", err)
+		case errors.Is(err, util.ErrNotExist):
+			ctx.NotFound()
+		case moderation_model.IsErrReportAlreadyExist(err):
+			ctx.Error(http.StatusConflict, "ReportAbuse", err)
+		default:
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	apiReport, err := convert.ToAbuseReport(ctx, report, ctx.Doer)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, apiReport)
+}
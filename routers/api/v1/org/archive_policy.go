@@ -0,0 +1,173 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetArchivePolicy returns the archive policy of an organization
+func GetArchivePolicy(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/archive_policy organization orgGetArchivePolicy
+	// ---
+	// summary: Get the policy for archiving inactive repositories of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ArchivePolicy"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	policy, err := organization.GetArchivePolicy(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	} else if policy == nil {
+		ctx.NotFound()
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToArchivePolicy(policy))
+}
+
+// EditArchivePolicy creates or updates the archive policy of an organization
+func EditArchivePolicy(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/archive_policy organization orgEditArchivePolicy
+	// ---
+	// summary: Create or update the policy for archiving inactive repositories of an organization, it replaces the instance policy
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditArchivePolicyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ArchivePolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditArchivePolicyOption)
+
+	policy := &organization.ArchivePolicy{
+		OrgID:          ctx.Org.Organization.ID,
+		InactiveMonths: form.InactiveMonths,
+		NoticeDays:     form.NoticeDays,
+		ExemptTopics:   form.ExemptTopics,
+	}
+	if err := organization.SetArchivePolicy(ctx, policy); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetArchivePolicy", err)
+		}
+		return
+	}
+
+	policy, err := organization.GetArchivePolicy(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToArchivePolicy(policy))
+}
+
+// DeleteArchivePolicy removes the archive policy of an organization
+func DeleteArchivePolicy(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/archive_policy organization orgDeleteArchivePolicy
+	// ---
+	// summary: Delete the archive policy of an organization, the instance policy applies again
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	if err := organization.DeleteArchivePolicy(ctx, ctx.Org.Organization.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteArchivePolicy", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListUpcomingArchivals lists the inactive repositories of an organization which are going to be archived
+func ListUpcomingArchivals(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/archive_policy/upcoming organization orgListUpcomingArchivals
+	// ---
+	// summary: List the inactive repositories of an organization which are going to be archived, soonest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UpcomingArchivalList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	listOptions := utils.GetListOptions(ctx)
+	notices, count, err := repo_model.FindArchiveNotices(ctx, repo_model.FindArchiveNoticesOptions{
+		ListOptions: listOptions,
+		OwnerID:     ctx.Org.Organization.ID,
+	})
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result, err := convert.ToUpcomingArchivals(notices)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
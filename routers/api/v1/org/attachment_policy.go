@@ -0,0 +1,130 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetAttachmentPolicy returns the attachment policy of an organization
+func GetAttachmentPolicy(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/attachment_policy organization orgGetAttachmentPolicy
+	// ---
+	// summary: Get the attachment policy of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AttachmentPolicy"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	policy, err := organization.GetAttachmentPolicy(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	} else if policy == nil {
+		ctx.NotFound()
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAttachmentPolicy(policy))
+}
+
+// EditAttachmentPolicy creates or updates the attachment policy of an organization
+func EditAttachmentPolicy(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/attachment_policy organization orgEditAttachmentPolicy
+	// ---
+	// summary: Create or update the attachment policy of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditAttachmentPolicyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AttachmentPolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditAttachmentPolicyOption)
+
+	policy := &organization.AttachmentPolicy{
+		OrgID:             ctx.Org.Organization.ID,
+		Rules:             make([]*organization.AttachmentTypeRule, 0, len(form.Rules)),
+		OptimizeImages:    form.OptimizeImages,
+		MaxImageDimension: form.MaxImageDimension,
+	}
+	for _, rule := range form.Rules {
+		policy.Rules = append(policy.Rules, &organization.AttachmentTypeRule{
+			Type:    rule.Type,
+			MaxSize: rule.MaxSize,
+		})
+	}
+	if err := organization.SetAttachmentPolicy(ctx, policy); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetAttachmentPolicy", err)
+		}
+		return
+	}
+
+	policy, err := organization.GetAttachmentPolicy(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAttachmentPolicy(policy))
+}
+
+// DeleteAttachmentPolicy removes the attachment policy of an organization
+func DeleteAttachmentPolicy(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/attachment_policy organization orgDeleteAttachmentPolicy
+	// ---
+	// summary: Delete the attachment policy of an organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	if err := organization.DeleteAttachmentPolicy(ctx, ctx.Org.Organization.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteAttachmentPolicy", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
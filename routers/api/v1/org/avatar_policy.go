@@ -0,0 +1,114 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetAvatarPolicy returns the avatar policy of an organization
+func GetAvatarPolicy(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/avatar_policy organization orgGetAvatarPolicy
+	// ---
+	// summary: Get the avatar policy of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AvatarPolicy"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	policy, err := organization.GetAvatarPolicy(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	} else if policy == nil {
+		ctx.NotFound()
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToAvatarPolicy(policy))
+}
+
+// EditAvatarPolicy creates or updates the avatar policy of an organization
+func EditAvatarPolicy(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/avatar_policy organization orgEditAvatarPolicy
+	// ---
+	// summary: Create or update the avatar policy of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditAvatarPolicyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/AvatarPolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	form := web.GetForm(ctx).(*api.EditAvatarPolicyOption)
+
+	policy := &organization.AvatarPolicy{
+		OrgID:          ctx.Org.Organization.ID,
+		EnforceDefault: form.EnforceDefaultAvatars,
+	}
+	if err := organization.SetAvatarPolicy(ctx, policy); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetAvatarPolicy", err)
+		return
+	}
+
+	policy, err := organization.GetAvatarPolicy(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAvatarPolicy(policy))
+}
+
+// DeleteAvatarPolicy removes the avatar policy of an organization
+func DeleteAvatarPolicy(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/avatar_policy organization orgDeleteAvatarPolicy
+	// ---
+	// summary: Delete the avatar policy of an organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	if err := organization.DeleteAvatarPolicy(ctx, ctx.Org.Organization.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteAvatarPolicy", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
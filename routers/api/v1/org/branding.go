@@ -0,0 +1,130 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+	theme_service "code.gitea.io/gitea/services/theme"
+)
+
+// GetBranding returns the branding of an organization
+func GetBranding(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/branding organization orgGetBranding
+	// ---
+	// summary: Get the branding of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrgBranding"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	branding, err := organization.GetBranding(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	} else if branding == nil {
+		ctx.NotFound()
+		return
+	}
+	if err := branding.LoadTheme(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToOrgBranding(branding))
+}
+
+// EditBranding sets the branding of an organization
+func EditBranding(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/branding organization orgEditBranding
+	// ---
+	// summary: Set the theme and the accent color of the pages of an organization and its repositories
+	// description: Only themes which the site administrators approved for organizations can be used.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditOrgBrandingOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OrgBranding"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditOrgBrandingOption)
+
+	branding, err := theme_service.SetOrgBranding(ctx, ctx.Org.Organization.ID, form.Theme, form.AccentColor)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetOrgBranding", err)
+		}
+		return
+	}
+
+	// reload for the timestamps
+	branding, err = organization.GetBranding(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if err := branding.LoadTheme(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToOrgBranding(branding))
+}
+
+// DeleteBranding removes the branding of an organization
+func DeleteBranding(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/branding organization orgDeleteBranding
+	// ---
+	// summary: Delete the branding of an organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	if err := organization.DeleteBranding(ctx, ctx.Org.Organization.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteBranding", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -148,6 +148,48 @@ func EditHook(ctx *context.APIContext) {
 	)
 }
 
+// RotateHookSecret rotates the secret of a hook of an organization
+func RotateHookSecret(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/hooks/{id}/secret/rotate organization orgRotateHookSecret
+	// ---
+	// summary: Rotate the secret of a hook
+	// description: The payloads are signed with both the new and the previous secret during the overlap.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RotateHookSecretOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookSecretRotation"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.RotateOwnerHookSecret(
+		ctx,
+		ctx.ContextUser,
+		web.GetForm(ctx).(*api.RotateHookSecretOption),
+		ctx.ParamsInt64("id"),
+	)
+}
+
 // DeleteHook delete a hook of an organization
 func DeleteHook(ctx *context.APIContext) {
 	// swagger:operation DELETE /orgs/{org}/hooks/{id} organization orgDeleteHook
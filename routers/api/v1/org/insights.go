@@ -0,0 +1,69 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetInsights returns the delivery metrics of an organization and its repositories
+func GetInsights(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/insights organization orgGetInsights
+	// ---
+	// summary: Get the delivery metrics of an organization and its repositories
+	// description: The metrics of the merged pull requests are computed in the background,
+	//   recently merged pull requests may be missing.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: Start of the time range, 30 days before its end if empty. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: End of the time range, now if empty. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Insights"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	insights := utils.GetInsights(ctx, ctx.Org.Organization.ID, 0)
+	if ctx.Written() {
+		return
+	}
+
+	repoIDs := make([]int64, 0, len(insights.PerRepo))
+	for repoID := range insights.PerRepo {
+		repoIDs = append(repoIDs, repoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoriesMapByIDs", err)
+		return
+	}
+
+	result := convert.ToInsights(insights)
+	result.Repositories = convert.ToRepoInsights(ctx, insights, repos, perm.AccessModeOwner)
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,135 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/modules/context"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetLicensePolicy returns the license policy of an organization
+func GetLicensePolicy(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/license_policy organization orgGetLicensePolicy
+	// ---
+	// summary: Get the license policy of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/LicensePolicy"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	policy, err := organization.GetLicensePolicy(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	} else if policy == nil {
+		ctx.NotFound()
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToLicensePolicy(policy))
+}
+
+// EditLicensePolicy creates or updates the license policy of an organization
+func EditLicensePolicy(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/license_policy organization orgEditLicensePolicy
+	// ---
+	// summary: Create or update the license policy of an organization
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditLicensePolicyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/LicensePolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditLicensePolicyOption)
+
+	for _, names := range [][]string{form.Allowed, form.Forbidden} {
+		for _, name := range names {
+			if !util.SliceContainsString(repo_module.Licenses, name, true) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("unknown license: %s", name))
+				return
+			}
+		}
+	}
+
+	policy := &organization.LicensePolicy{
+		OrgID:     ctx.Org.Organization.ID,
+		Allowed:   form.Allowed,
+		Forbidden: form.Forbidden,
+		Action:    organization.LicensePolicyAction(form.Action),
+	}
+	if !policy.Action.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid action: %s", form.Action))
+		return
+	}
+
+	if err := organization.SetLicensePolicy(ctx, policy); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetLicensePolicy", err)
+		return
+	}
+
+	policy, err := organization.GetLicensePolicy(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToLicensePolicy(policy))
+}
+
+// DeleteLicensePolicy removes the license policy of an organization
+func DeleteLicensePolicy(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/license_policy organization orgDeleteLicensePolicy
+	// ---
+	// summary: Delete the license policy of an organization
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	if err := organization.DeleteLicensePolicy(ctx, ctx.Org.Organization.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteLicensePolicy", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/convert"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// ListOutsideCollaborators lists the collaborators on the repositories of an organization who aren't members of it
+func ListOutsideCollaborators(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/outside_collaborators organization orgListOutsideCollaborators
+	// ---
+	// summary: List the collaborators on the repositories of an organization who aren't members of it, with the repositories they can access
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/OutsideCollaboratorList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	collaborators, err := repo_service.GetOutsideCollaborators(ctx, ctx.Org.Organization.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetOutsideCollaborators", err)
+		return
+	}
+
+	result := make([]*api.OutsideCollaborator, 0, len(collaborators))
+	for _, c := range collaborators {
+		apiCollaborator := &api.OutsideCollaborator{
+			User:         convert.ToUser(ctx, c.User, ctx.Doer),
+			Repositories: make([]*api.OutsideCollaboratorRepository, 0, len(c.Collaborations)),
+		}
+		for _, collaboration := range c.Collaborations {
+			apiRepo := &api.OutsideCollaboratorRepository{
+				FullName:   collaboration.Repo.FullName(),
+				Permission: collaboration.Collaboration.Mode.String(),
+			}
+			if collaboration.Collaboration.ExpiresUnix > 0 {
+				expires := collaboration.Collaboration.ExpiresUnix.AsTime()
+				apiRepo.Expires = &expires
+			}
+			apiCollaborator.Repositories = append(apiCollaborator.Repositories, apiRepo)
+		}
+		result = append(result, apiCollaborator)
+	}
+	ctx.JSON(http.StatusOK, result)
+}
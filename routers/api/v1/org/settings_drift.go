@@ -0,0 +1,96 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// GetReposSettingsDrift compares the settings of the repositories of an organization with a template
+func GetReposSettingsDrift(ctx *context.APIContext) {
+	// swagger:operation POST /orgs/{org}/repos/settings/drift organization orgGetReposSettingsDrift
+	// ---
+	// summary: Compare the settings of the repositories of an organization with a template
+	// description: Only the properties set in the template and the lists which aren't null are compared. Branch
+	//   protections, webhooks and labels are matched by their rule name, URL and name, missing items as well as
+	//   items which aren't in the template are reported. The secrets of the webhooks can't be compared. Every
+	//   repository of the page is listed, the ones which match the template without differences.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/RepoSettings"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoSettingsDriftList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	template := web.GetForm(ctx).(*api.RepoSettings)
+	listOptions := utils.GetListOptions(ctx)
+
+	repos, count, err := repo_model.GetUserRepositories(&repo_model.SearchRepoOptions{
+		Actor:       ctx.Org.Organization.AsUser(),
+		Private:     true,
+		ListOptions: listOptions,
+		OrderBy:     "id ASC",
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepositories", err)
+		return
+	}
+	if err := repos.LoadAttributes(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RepositoryList.LoadAttributes", err)
+		return
+	}
+
+	drifts := make([]*api.RepoSettingsDrift, 0, len(repos))
+	for _, repo := range repos {
+		settings, err := repo_service.ExportSettings(ctx, repo)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ExportSettings", err)
+			return
+		}
+		differences, err := repo_service.DiffSettings(template, settings)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "DiffSettings", err)
+			return
+		}
+		drifts = append(drifts, &api.RepoSettingsDrift{
+			Repository:  repo.FullName(),
+			Differences: differences,
+		})
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, drifts)
+}
@@ -18,6 +18,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/user"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -217,6 +218,12 @@ func CreateTeam(ctx *context.APIContext) {
 		IncludesAllRepositories: form.IncludesAllRepositories,
 		CanCreateOrgRepo:        form.CanCreateOrgRepo,
 		AccessMode:              p,
+		CodePaths:               form.CodePaths,
+		MentionMode:             organization.TeamMentionMode(form.MentionMode),
+		MentionWebhookType:      form.MentionWebhookType,
+		MentionWebhookURL:       form.MentionWebhookURL,
+		MentionRateLimit:        form.MentionRateLimit,
+		ReviewAssignCount:       form.ReviewAssignCount,
 	}
 
 	if team.AccessMode < perm.AccessModeAdmin {
@@ -233,7 +240,7 @@ func CreateTeam(ctx *context.APIContext) {
 	}
 
 	if err := models.NewTeam(team); err != nil {
-		if organization.IsErrTeamAlreadyExist(err) {
+		if organization.IsErrTeamAlreadyExist(err) || errors.Is(err, util.ErrInvalidArgument) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "NewTeam", err)
@@ -271,6 +278,8 @@ func EditTeam(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/Team"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
 
 	form := web.GetForm(ctx).(*api.EditTeamOption)
 	team := ctx.Org.Team
@@ -291,6 +300,26 @@ func EditTeam(ctx *context.APIContext) {
 		team.Description = *form.Description
 	}
 
+	if form.CodePaths != nil {
+		team.CodePaths = *form.CodePaths
+	}
+
+	if form.MentionMode != nil {
+		team.MentionMode = organization.TeamMentionMode(*form.MentionMode)
+	}
+	if form.MentionWebhookType != nil {
+		team.MentionWebhookType = *form.MentionWebhookType
+	}
+	if form.MentionWebhookURL != nil {
+		team.MentionWebhookURL = *form.MentionWebhookURL
+	}
+	if form.MentionRateLimit != nil {
+		team.MentionRateLimit = *form.MentionRateLimit
+	}
+	if form.ReviewAssignCount != nil {
+		team.ReviewAssignCount = *form.ReviewAssignCount
+	}
+
 	isAuthChanged := false
 	isIncludeAllChanged := false
 	if !team.IsOwnerTeam() && len(form.Permission) != 0 {
@@ -322,6 +351,10 @@ func EditTeam(ctx *context.APIContext) {
 	}
 
 	if err := models.UpdateTeam(team, isAuthChanged, isIncludeAllChanged); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "EditTeam", err)
 		return
 	}
@@ -524,6 +557,109 @@ func RemoveTeamMember(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// ListTeamLeads api for list the leads of a team
+func ListTeamLeads(ctx *context.APIContext) {
+	// swagger:operation GET /teams/{id}/leads organization orgListTeamLeads
+	// ---
+	// summary: List the leads of a team, which are notified of mentions of a team with mention mode leads
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserList"
+
+	leads, err := organization.GetTeamLeads(ctx, ctx.Org.Team.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTeamLeads", err)
+		return
+	}
+
+	users := make([]*api.User, len(leads))
+	for i, lead := range leads {
+		users[i] = convert.ToUser(ctx, lead, ctx.Doer)
+	}
+	ctx.JSON(http.StatusOK, users)
+}
+
+// AddTeamLead api for make a member of a team one of its leads
+func AddTeamLead(ctx *context.APIContext) {
+	// swagger:operation PUT /teams/{id}/leads/{username} organization orgAddTeamLead
+	// ---
+	// summary: Make a team member one of the leads of the team
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: username
+	//   in: path
+	//   description: username of the member
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	setTeamLead(ctx, true)
+}
+
+// RemoveTeamLead api for remove a member of a team from its leads
+func RemoveTeamLead(ctx *context.APIContext) {
+	// swagger:operation DELETE /teams/{id}/leads/{username} organization orgRemoveTeamLead
+	// ---
+	// summary: Remove a team member from the leads of the team
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: username
+	//   in: path
+	//   description: username of the member
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	setTeamLead(ctx, false)
+}
+
+func setTeamLead(ctx *context.APIContext, isLead bool) {
+	u := user.GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := organization.SetTeamLead(ctx, ctx.Org.Team, u.ID, isLead); err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound(err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetTeamLead", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
 // GetTeamRepos api for get a team's repos
 func GetTeamRepos(ctx *context.APIContext) {
 	// swagger:operation GET /teams/{id}/repos organization orgListTeamRepos
@@ -0,0 +1,221 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+	org_service "code.gitea.io/gitea/services/org"
+)
+
+func writeTeamGroupSync(ctx *context.APIContext, team *organization.Team) {
+	mappings, err := organization.FindTeamGroupMappings(ctx, team.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindTeamGroupMappings", err)
+		return
+	}
+	sourceIDs := make([]int64, 0, len(mappings))
+	for _, m := range mappings {
+		sourceIDs = append(sourceIDs, m.SourceID)
+	}
+	sourceNames, err := org_service.GetExternalGroupSourceNames(sourceIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetExternalGroupSourceNames", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTeamGroupSync(team, mappings, sourceNames))
+}
+
+func writeTeamGroupSyncLogs(ctx *context.APIContext, logs []*organization.TeamGroupSyncLog) {
+	userIDs := make([]int64, 0, len(logs))
+	for _, l := range logs {
+		userIDs = append(userIDs, l.UserID)
+	}
+	users, err := user_model.GetUsersByIDs(userIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUsersByIDs", err)
+		return
+	}
+	usersByID := make(map[int64]*user_model.User, len(users))
+	for _, u := range users {
+		usersByID[u.ID] = u
+	}
+
+	result := make([]*api.TeamGroupSyncLog, 0, len(logs))
+	for _, l := range logs {
+		result = append(result, convert.ToTeamGroupSyncLog(ctx, l, usersByID[l.UserID], ctx.Doer))
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetTeamGroupSync get how the members of a team are synced from external groups
+func GetTeamGroupSync(ctx *context.APIContext) {
+	// swagger:operation GET /teams/{id}/group_sync organization orgGetTeamGroupSync
+	// ---
+	// summary: Get the external groups mapped to a team and how its members are synced from them
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TeamGroupSync"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	writeTeamGroupSync(ctx, ctx.Org.Team)
+}
+
+// EditTeamGroupSync replace how the members of a team are synced from external groups
+func EditTeamGroupSync(ctx *context.APIContext) {
+	// swagger:operation PUT /teams/{id}/group_sync organization orgEditTeamGroupSync
+	// ---
+	// summary: Replace the external groups mapped to a team and how its members are synced from them
+	// description: The groups of an authentication source are recorded when its users sign in or are synchronized,
+	//   the members of the groups of the "api" source are pushed through the admin API. The members of the team are
+	//   synced periodically, an empty list of mappings stops the sync.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditTeamGroupSyncOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TeamGroupSync"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditTeamGroupSyncOption)
+	team := ctx.Org.Team
+
+	mappings := make([]*organization.TeamGroupMapping, 0, len(form.Mappings))
+	for _, m := range form.Mappings {
+		sourceID, err := org_service.GetExternalGroupSourceID(m.Source)
+		if err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetExternalGroupSourceID", err)
+			}
+			return
+		}
+		mappings = append(mappings, &organization.TeamGroupMapping{SourceID: sourceID, GroupName: m.Group})
+	}
+	team.GroupSyncPolicy = organization.TeamGroupSyncPolicy(form.Policy)
+	team.GroupSyncRemoval = form.RemoveMembers
+
+	if err := organization.ReplaceTeamGroupMappings(ctx, team, mappings); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "ReplaceTeamGroupMappings", err)
+		}
+		return
+	}
+	writeTeamGroupSync(ctx, team)
+}
+
+// RunTeamGroupSync sync the members of a team from its external groups now
+func RunTeamGroupSync(ctx *context.APIContext) {
+	// swagger:operation POST /teams/{id}/group_sync/run organization orgRunTeamGroupSync
+	// ---
+	// summary: Sync the members of a team from its external groups now
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TeamGroupSyncLogList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	logs, err := org_service.SyncTeamGroups(ctx, ctx.Org.Team)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SyncTeamGroups", err)
+		return
+	}
+	writeTeamGroupSyncLogs(ctx, logs)
+}
+
+// ListTeamGroupSyncLogs list the changes of the team memberships made by the group sync
+func ListTeamGroupSyncLogs(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/group_sync/logs organization orgListTeamGroupSyncLogs
+	// ---
+	// summary: List the changes of the team memberships of an organization made by the group sync, the most recent first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: team
+	//   in: query
+	//   description: id of the team to list the changes of
+	//   type: integer
+	//   format: int64
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TeamGroupSyncLogList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	opts := organization.FindTeamGroupSyncLogsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		OrgID:       ctx.Org.Organization.ID,
+		TeamID:      ctx.FormInt64("team"),
+	}
+	logs, count, err := organization.FindTeamGroupSyncLogs(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindTeamGroupSyncLogs", err)
+		return
+	}
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	writeTeamGroupSyncLogs(ctx, logs)
+}
@@ -0,0 +1,280 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+
+	"xorm.io/builder"
+)
+
+const (
+	defaultTeamActivityPeriod = 7 * 24 * time.Hour
+	defaultTeamCalendarPeriod = 90 * 24 * time.Hour
+)
+
+func writeTeamHomepage(ctx *context.APIContext, status int) {
+	homepage, err := organization.GetTeamHomepage(ctx, ctx.Org.Team)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTeamHomepage", err)
+		return
+	}
+	pinned, err := organization.GetTeamPinnedRepos(ctx, ctx.Org.Team.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTeamPinnedRepos", err)
+		return
+	}
+	apiHomepage, err := convert.ToTeamHomepage(ctx, homepage, pinned, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToTeamHomepage", err)
+		return
+	}
+	ctx.JSON(status, apiHomepage)
+}
+
+// GetTeamHomepage get the homepage of a team
+func GetTeamHomepage(ctx *context.APIContext) {
+	// swagger:operation GET /teams/{id}/homepage organization orgGetTeamHomepage
+	// ---
+	// summary: Get the homepage of a team, its README and pinned repositories
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TeamHomepage"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	writeTeamHomepage(ctx, http.StatusOK)
+}
+
+// EditTeamHomepage edit the homepage of a team
+func EditTeamHomepage(ctx *context.APIContext) {
+	// swagger:operation PUT /teams/{id}/homepage organization orgEditTeamHomepage
+	// ---
+	// summary: Edit the homepage of a team, only its leads and the organization owners can
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditTeamHomepageOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TeamHomepage"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditTeamHomepageOption)
+	team := ctx.Org.Team
+
+	if form.PinnedRepos != nil {
+		repoIDs := make([]int64, 0, len(*form.PinnedRepos))
+		for _, fullName := range *form.PinnedRepos {
+			owner, name, ok := strings.Cut(fullName, "/")
+			if !ok {
+				ctx.Error(http.StatusUnprocessableEntity, "", "pinned repositories must be given by their full name")
+				return
+			}
+			repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, owner, name)
+			if err != nil {
+				if repo_model.IsErrRepoNotExist(err) {
+					ctx.Error(http.StatusUnprocessableEntity, "", err)
+				} else {
+					ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+				}
+				return
+			}
+			repoIDs = append(repoIDs, repo.ID)
+		}
+		if err := organization.SetTeamPinnedRepos(ctx, team, repoIDs); err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "SetTeamPinnedRepos", err)
+			}
+			return
+		}
+	}
+
+	if form.Readme != nil {
+		if err := organization.UpdateTeamReadme(ctx, team, *form.Readme); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UpdateTeamReadme", err)
+			return
+		}
+	}
+
+	writeTeamHomepage(ctx, http.StatusOK)
+}
+
+// GetTeamActivitySummary aggregates the recent activity in the repositories of a team
+func GetTeamActivitySummary(ctx *context.APIContext) {
+	// swagger:operation GET /teams/{id}/activities/summary organization orgGetTeamActivitySummary
+	// ---
+	// summary: Count the recent activity in the repositories of a team by type, repository and member
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: Only count the activity since this time, defaults to a week ago. The time must be given in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TeamActivitySummary"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	_, since, err := context.GetQueryBeforeSince(ctx.Context)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+	if since == 0 {
+		since = time.Now().Add(-defaultTeamActivityPeriod).Unix()
+	}
+
+	summary, err := activities_model.GetTeamActivitySummary(ctx, ctx.Org.Team, ctx.Doer, timeutil.TimeStamp(since))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTeamActivitySummary", err)
+		return
+	}
+	apiSummary, err := convert.ToTeamActivitySummary(summary)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToTeamActivitySummary", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiSummary)
+}
+
+// ListTeamMilestones list the milestones of the repositories of a team as a calendar
+func ListTeamMilestones(ctx *context.APIContext) {
+	// swagger:operation GET /teams/{id}/milestones organization orgListTeamMilestones
+	// ---
+	// summary: List the milestones of the repositories of a team by their deadline
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: Only list the milestones due after this time, defaults to now. The time must be given in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: Only list the milestones due before this time, defaults to 90 days after since. The time must be given in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TeamMilestoneList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	before, since, err := context.GetQueryBeforeSince(ctx.Context)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return
+	}
+	if since == 0 {
+		since = time.Now().Unix()
+	}
+	if before == 0 {
+		before = time.Unix(since, 0).Add(defaultTeamCalendarPeriod).Unix()
+	}
+	if before < since {
+		ctx.Error(http.StatusUnprocessableEntity, "", "before must not be earlier than since")
+		return
+	}
+
+	repoCond := builder.In("id", builder.Select("repo_id").From("team_repo").Where(builder.Eq{"team_id": ctx.Org.Team.ID}))
+	milestones, err := issues_model.GetMilestonesDueBetween(ctx, repoCond, timeutil.TimeStamp(since), timeutil.TimeStamp(before))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetMilestonesDueBetween", err)
+		return
+	}
+
+	repoIDs := make([]int64, 0, len(milestones))
+	for _, m := range milestones {
+		repoIDs = append(repoIDs, m.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetRepositoriesMapByIDs", err)
+		return
+	}
+	canRead := make(map[int64]bool, len(repos))
+	for id, repo := range repos {
+		permission, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+			return
+		}
+		canRead[id] = permission.CanRead(unit.TypeIssues) || permission.CanRead(unit.TypePullRequests)
+	}
+
+	apiMilestones := make([]*api.TeamMilestone, 0, len(milestones))
+	for _, m := range milestones {
+		if !canRead[m.RepoID] {
+			continue
+		}
+		apiMilestones = append(apiMilestones, convert.ToTeamMilestone(m, repos[m.RepoID]))
+	}
+	ctx.JSON(http.StatusOK, apiMilestones)
+}
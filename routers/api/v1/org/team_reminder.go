@@ -0,0 +1,319 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+	reminder_service "code.gitea.io/gitea/services/reminder"
+)
+
+// ListTeamReminders list the scheduled reminders of a team
+func ListTeamReminders(ctx *context.APIContext) {
+	// swagger:operation GET /teams/{id}/reminders organization orgListTeamReminders
+	// ---
+	// summary: List the scheduled reminders of a team
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TeamReminderList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	reminders, err := organization.FindTeamReminders(ctx, ctx.Org.Team.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindTeamReminders", err)
+		return
+	}
+	apiReminders := make([]*api.TeamReminder, 0, len(reminders))
+	for _, r := range reminders {
+		apiReminders = append(apiReminders, convert.ToTeamReminder(r))
+	}
+	ctx.JSON(http.StatusOK, apiReminders)
+}
+
+func getTeamReminderByParams(ctx *context.APIContext) *organization.TeamReminder {
+	r, err := organization.GetTeamReminder(ctx, ctx.Org.Team.ID, ctx.ParamsInt64(":reminderid"))
+	if err != nil {
+		if organization.IsErrTeamReminderNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetTeamReminder", err)
+		}
+		return nil
+	}
+	return r
+}
+
+func saveTeamReminderError(ctx *context.APIContext, name string, err error) {
+	if errors.Is(err, util.ErrInvalidArgument) {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+	} else {
+		ctx.Error(http.StatusInternalServerError, name, err)
+	}
+}
+
+// GetTeamReminder get a scheduled reminder of a team
+func GetTeamReminder(ctx *context.APIContext) {
+	// swagger:operation GET /teams/{id}/reminders/{reminder_id} organization orgGetTeamReminder
+	// ---
+	// summary: Get a scheduled reminder of a team
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: reminder_id
+	//   in: path
+	//   description: id of the reminder
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TeamReminder"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	r := getTeamReminderByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTeamReminder(r))
+}
+
+// CreateTeamReminder create a scheduled reminder for a team
+func CreateTeamReminder(ctx *context.APIContext) {
+	// swagger:operation POST /teams/{id}/reminders organization orgCreateTeamReminder
+	// ---
+	// summary: Create a scheduled reminder which posts a digest of pending reviews, stale pull requests and failing checks to a chat webhook
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateTeamReminderOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/TeamReminder"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateTeamReminderOption)
+	if !reminder_service.IsValidWebhookType(form.WebhookType) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "unsupported webhook type")
+		return
+	}
+
+	r := &organization.TeamReminder{
+		OrgID:                 ctx.Org.Team.OrgID,
+		TeamID:                ctx.Org.Team.ID,
+		Schedule:              form.Schedule,
+		WebhookType:           form.WebhookType,
+		WebhookURL:            form.WebhookURL,
+		IsActive:              form.Active == nil || *form.Active,
+		IncludeReviewRequests: form.IncludeReviewRequests == nil || *form.IncludeReviewRequests,
+		IncludeStalePulls:     form.IncludeStalePulls == nil || *form.IncludeStalePulls,
+		StaleDays:             form.StaleDays,
+		IncludeFailingChecks:  form.IncludeFailingChecks == nil || *form.IncludeFailingChecks,
+		IgnoreWorkInProgress:  form.IgnoreWorkInProgress,
+	}
+	if r.StaleDays == 0 {
+		r.StaleDays = 7
+	}
+	if err := organization.CreateTeamReminder(ctx, r); err != nil {
+		saveTeamReminderError(ctx, "CreateTeamReminder", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToTeamReminder(r))
+}
+
+// EditTeamReminder edit a scheduled reminder of a team
+func EditTeamReminder(ctx *context.APIContext) {
+	// swagger:operation PATCH /teams/{id}/reminders/{reminder_id} organization orgEditTeamReminder
+	// ---
+	// summary: Edit a scheduled reminder of a team
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: reminder_id
+	//   in: path
+	//   description: id of the reminder
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditTeamReminderOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TeamReminder"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditTeamReminderOption)
+	r := getTeamReminderByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Schedule != nil {
+		r.Schedule = *form.Schedule
+	}
+	if form.WebhookType != nil {
+		if !reminder_service.IsValidWebhookType(*form.WebhookType) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "unsupported webhook type")
+			return
+		}
+		r.WebhookType = *form.WebhookType
+	}
+	if form.WebhookURL != nil {
+		r.WebhookURL = *form.WebhookURL
+	}
+	if form.Active != nil {
+		r.IsActive = *form.Active
+	}
+	if form.IncludeReviewRequests != nil {
+		r.IncludeReviewRequests = *form.IncludeReviewRequests
+	}
+	if form.IncludeStalePulls != nil {
+		r.IncludeStalePulls = *form.IncludeStalePulls
+	}
+	if form.StaleDays != nil {
+		r.StaleDays = *form.StaleDays
+	}
+	if form.IncludeFailingChecks != nil {
+		r.IncludeFailingChecks = *form.IncludeFailingChecks
+	}
+	if form.IgnoreWorkInProgress != nil {
+		r.IgnoreWorkInProgress = *form.IgnoreWorkInProgress
+	}
+
+	if err := organization.UpdateTeamReminder(ctx, r); err != nil {
+		saveTeamReminderError(ctx, "UpdateTeamReminder", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTeamReminder(r))
+}
+
+// DeleteTeamReminder delete a scheduled reminder of a team
+func DeleteTeamReminder(ctx *context.APIContext) {
+	// swagger:operation DELETE /teams/{id}/reminders/{reminder_id} organization orgDeleteTeamReminder
+	// ---
+	// summary: Delete a scheduled reminder of a team
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: reminder_id
+	//   in: path
+	//   description: id of the reminder
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := organization.DeleteTeamReminder(ctx, ctx.Org.Team.ID, ctx.ParamsInt64(":reminderid")); err != nil {
+		if organization.IsErrTeamReminderNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteTeamReminder", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// TestTeamReminder send the digest of a scheduled reminder now
+func TestTeamReminder(ctx *context.APIContext) {
+	// swagger:operation POST /teams/{id}/reminders/{reminder_id}/test organization orgTestTeamReminder
+	// ---
+	// summary: Send the digest of a scheduled reminder now, without changing its schedule
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the team
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: reminder_id
+	//   in: path
+	//   description: id of the reminder
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	r := getTeamReminderByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := reminder_service.Send(ctx, r); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,154 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// ListTemplateCatalogItems lists the templates of a catalog of an organization
+func ListTemplateCatalogItems(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/templates/{kind} organization orgListTemplateCatalogItems
+	// ---
+	// summary: List the templates of a catalog of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: kind
+	//   in: path
+	//   description: kind of the catalog
+	//   type: string
+	//   enum: [gitignore, license, issue_template, workflow_template]
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TemplateCatalogItemList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.ListTemplateCatalogItems(ctx, ctx.Org.Organization.ID)
+}
+
+// GetTemplateCatalogItem gets a template of a catalog of an organization
+func GetTemplateCatalogItem(ctx *context.APIContext) {
+	// swagger:operation GET /orgs/{org}/templates/{kind}/{name} organization orgGetTemplateCatalogItem
+	// ---
+	// summary: Get a template of a catalog of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: kind
+	//   in: path
+	//   description: kind of the catalog
+	//   type: string
+	//   enum: [gitignore, license, issue_template, workflow_template]
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TemplateCatalogItem"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.GetTemplateCatalogItem(ctx, ctx.Org.Organization.ID)
+}
+
+// EditTemplateCatalogItem adds a template to a catalog of an organization or replaces it
+func EditTemplateCatalogItem(ctx *context.APIContext) {
+	// swagger:operation PUT /orgs/{org}/templates/{kind}/{name} organization orgEditTemplateCatalogItem
+	// ---
+	// summary: Add a template to a catalog of an organization or replace it
+	// description: Issue templates must be markdown or YAML files, workflow templates YAML files.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: kind
+	//   in: path
+	//   description: kind of the catalog
+	//   type: string
+	//   enum: [gitignore, license, issue_template, workflow_template]
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditTemplateCatalogItemOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/TemplateCatalogItem"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.EditTemplateCatalogItem(ctx, ctx.Org.Organization.ID)
+}
+
+// DeleteTemplateCatalogItem deletes a template of a catalog of an organization
+func DeleteTemplateCatalogItem(ctx *context.APIContext) {
+	// swagger:operation DELETE /orgs/{org}/templates/{kind}/{name} organization orgDeleteTemplateCatalogItem
+	// ---
+	// summary: Delete a template of a catalog of an organization
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: org
+	//   in: path
+	//   description: name of the organization
+	//   type: string
+	//   required: true
+	// - name: kind
+	//   in: path
+	//   description: kind of the catalog
+	//   type: string
+	//   enum: [gitignore, license, issue_template, workflow_template]
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the template
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	utils.DeleteTemplateCatalogItem(ctx, ctx.Org.Organization.ID)
+}
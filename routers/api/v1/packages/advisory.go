@@ -0,0 +1,218 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	npm_module "code.gitea.io/gitea/modules/packages/npm"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListPackageAdvisories lists the advisories published by the owner of packages
+func ListPackageAdvisories(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/advisories package listPackageAdvisories
+	// ---
+	// summary: List the security advisories published for the packages of an owner
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageAdvisoryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pas, err := packages.GetAdvisoriesByOwner(ctx, ctx.Package.Owner.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetAdvisoriesByOwner", err)
+		return
+	}
+
+	result := make([]*api.PackageAdvisory, 0, len(pas))
+	for _, pa := range pas {
+		result = append(result, convert.ToPackageAdvisory(pa))
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreatePackageAdvisory publishes an advisory for a package of the owner
+func CreatePackageAdvisory(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/advisories package createPackageAdvisory
+	// ---
+	// summary: Publish a security advisory for a package of an owner
+	// description: The advisory is served to package managers auditing their dependencies, like `npm audit`.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreatePackageAdvisoryOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/PackageAdvisory"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreatePackageAdvisoryOption)
+	pa := &packages.PackageAdvisory{
+		OwnerID:            ctx.Package.Owner.ID,
+		Type:               packages.Type(form.Type),
+		PackageName:        strings.ToLower(strings.TrimSpace(form.PackageName)),
+		Title:              form.Title,
+		URL:                form.URL,
+		Severity:           form.Severity,
+		VulnerableVersions: strings.TrimSpace(form.VulnerableVersions),
+	}
+	if !validatePackageAdvisory(ctx, pa) {
+		return
+	}
+
+	if err := packages.InsertAdvisory(ctx, pa); err != nil {
+		ctx.Error(http.StatusInternalServerError, "InsertAdvisory", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToPackageAdvisory(pa))
+}
+
+// EditPackageAdvisory edits an advisory published by the owner of packages
+func EditPackageAdvisory(ctx *context.APIContext) {
+	// swagger:operation PATCH /packages/{owner}/advisories/{id} package editPackageAdvisory
+	// ---
+	// summary: Edit a security advisory published for a package of an owner
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the advisory
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditPackageAdvisoryOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageAdvisory"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditPackageAdvisoryOption)
+	pa, err := packages.GetAdvisoryByID(ctx, ctx.Package.Owner.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if err == packages.ErrPackageAdvisoryNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetAdvisoryByID", err)
+		}
+		return
+	}
+
+	if form.Title != nil {
+		pa.Title = *form.Title
+	}
+	if form.URL != nil {
+		pa.URL = *form.URL
+	}
+	if form.Severity != nil {
+		pa.Severity = *form.Severity
+	}
+	if form.VulnerableVersions != nil {
+		pa.VulnerableVersions = strings.TrimSpace(*form.VulnerableVersions)
+	}
+	if !validatePackageAdvisory(ctx, pa) {
+		return
+	}
+
+	if err := packages.UpdateAdvisory(ctx, pa); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateAdvisory", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToPackageAdvisory(pa))
+}
+
+// DeletePackageAdvisory deletes an advisory published by the owner of packages
+func DeletePackageAdvisory(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/advisories/{id} package deletePackageAdvisory
+	// ---
+	// summary: Delete a security advisory published for a package of an owner
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the advisory
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := packages.DeleteAdvisoryByID(ctx, ctx.Package.Owner.ID, ctx.ParamsInt64(":id")); err != nil {
+		if err == packages.ErrPackageAdvisoryNotExist {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteAdvisoryByID", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func validatePackageAdvisory(ctx *context.APIContext, pa *packages.PackageAdvisory) bool {
+	if pa.Type != packages.TypeNpm {
+		ctx.Error(http.StatusUnprocessableEntity, "Type", "advisories are only supported for npm packages")
+		return false
+	}
+	if pa.Title == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "Title", "title must not be empty")
+		return false
+	}
+	if !npm_module.IsValidSeverity(pa.Severity) {
+		ctx.Error(http.StatusUnprocessableEntity, "Severity", "severity must be info, low, moderate, high or critical")
+		return false
+	}
+	if _, err := npm_module.ParseVersionRange(pa.VulnerableVersions); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "VulnerableVersions", err)
+		return false
+	}
+	return true
+}
@@ -0,0 +1,150 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/convert"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// ListDeletedPackageVersions lists the deleted package versions of an owner which weren't purged yet
+func ListDeletedPackageVersions(ctx *context.APIContext) {
+	// swagger:operation GET /packages/{owner}/deleted package listDeletedPackageVersions
+	// ---
+	// summary: List the deleted package versions of an owner which can still be restored
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DeletedPackageVersionList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	deletions, err := packages.FindPackageVersionDeletions(ctx, ctx.Package.Owner.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindPackageVersionDeletions", err)
+		return
+	}
+
+	result := make([]*api.DeletedPackageVersion, 0, len(deletions))
+	for _, d := range deletions {
+		result = append(result, convert.ToDeletedPackageVersion(ctx, d))
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+func getPackageVersionDeletion(ctx *context.APIContext) *packages.PackageVersionDeletion {
+	d, err := packages.GetPackageVersionDeletion(ctx, ctx.Package.Owner.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if packages.IsErrPackageVersionDeletionNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPackageVersionDeletion", err)
+		}
+		return nil
+	}
+	return d
+}
+
+// RestoreDeletedPackageVersion restores a deleted package version
+func RestoreDeletedPackageVersion(ctx *context.APIContext) {
+	// swagger:operation POST /packages/{owner}/deleted/{id}/restore package restoreDeletedPackageVersion
+	// ---
+	// summary: Restore a deleted package version of an owner
+	// description: Registries with index files, like Debian, Alpine and RPM, list the restored version once their indexes are rebuilt.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the deleted package version
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Package"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	d := getPackageVersionDeletion(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	pd, err := packages_service.RestorePackageVersion(ctx, ctx.Doer, d)
+	if err != nil {
+		if errors.Is(err, packages.ErrDuplicatePackageVersion) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "RestorePackageVersion", err)
+		}
+		return
+	}
+	apiPackage, err := convert.ToPackage(ctx, pd, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ToPackage", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiPackage)
+}
+
+// PurgeDeletedPackageVersion drops a deleted package version before its retention expires
+func PurgeDeletedPackageVersion(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/deleted/{id} package purgeDeletedPackageVersion
+	// ---
+	// summary: Purge a deleted package version of an owner, it can't be restored anymore
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the packages
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the deleted package version
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	d := getPackageVersionDeletion(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if err := packages.DeletePackageVersionDeletion(ctx, d.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeletePackageVersionDeletion", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
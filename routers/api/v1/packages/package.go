@@ -5,11 +5,13 @@ package packages
 
 import (
 	"net/http"
+	"strings"
 
 	"code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/modules/context"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/convert"
 	packages_service "code.gitea.io/gitea/services/packages"
@@ -55,11 +57,12 @@ func ListPackages(ctx *context.APIContext) {
 	query := ctx.FormTrim("q")
 
 	pvs, count, err := packages.SearchVersions(ctx, &packages.PackageSearchOptions{
-		OwnerID:    ctx.Package.Owner.ID,
-		Type:       packages.Type(packageType),
-		Name:       packages.SearchValue{Value: query},
-		IsInternal: util.OptionalBoolFalse,
-		Paginator:  &listOptions,
+		OwnerID:           ctx.Package.Owner.ID,
+		HideInternalRepos: ctx.Doer == nil,
+		Type:              packages.Type(packageType),
+		Name:              packages.SearchValue{Value: query},
+		IsInternal:        util.OptionalBoolFalse,
+		Paginator:         &listOptions,
 	})
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "SearchVersions", err)
@@ -126,6 +129,7 @@ func GetPackage(ctx *context.APIContext) {
 		ctx.Error(http.StatusInternalServerError, "Error converting package for api", err)
 		return
 	}
+	apiPackage.Readme = convert.ToPackageReadme(ctx.Package.Descriptor)
 
 	ctx.JSON(http.StatusOK, apiPackage)
 }
@@ -170,6 +174,105 @@ func DeletePackage(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// DeprecatePackage marks a package version as deprecated
+func DeprecatePackage(ctx *context.APIContext) {
+	// swagger:operation PUT /packages/{owner}/{type}/{name}/{version}/deprecation package deprecatePackage
+	// ---
+	// summary: Deprecate a package version instead of deleting it
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/DeprecatePackageOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Package"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.DeprecatePackageOption)
+	setPackageDeprecation(ctx, true, strings.TrimSpace(form.Message))
+}
+
+// UndeprecatePackage removes the deprecation of a package version
+func UndeprecatePackage(ctx *context.APIContext) {
+	// swagger:operation DELETE /packages/{owner}/{type}/{name}/{version}/deprecation package undeprecatePackage
+	// ---
+	// summary: Remove the deprecation of a package version
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the package
+	//   type: string
+	//   required: true
+	// - name: type
+	//   in: path
+	//   description: type of the package
+	//   type: string
+	//   required: true
+	// - name: name
+	//   in: path
+	//   description: name of the package
+	//   type: string
+	//   required: true
+	// - name: version
+	//   in: path
+	//   description: version of the package
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Package"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	setPackageDeprecation(ctx, false, "")
+}
+
+func setPackageDeprecation(ctx *context.APIContext, deprecated bool, message string) {
+	pd := ctx.Package.Descriptor
+	if err := packages.SetVersionDeprecation(ctx, pd.Version, deprecated, message); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetVersionDeprecation", err)
+		return
+	}
+
+	apiPackage, err := convert.ToPackage(ctx, pd, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "Error converting package for api", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, apiPackage)
+}
+
 // ListPackageFiles gets all files of a package
 func ListPackageFiles(ctx *context.APIContext) {
 	// swagger:operation GET /packages/{owner}/{type}/{name}/{version}/files package listPackageFiles
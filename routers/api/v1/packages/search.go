@@ -0,0 +1,219 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// SearchPackages searches the packages of all owners visible to the doer
+func SearchPackages(ctx *context.APIContext) {
+	// swagger:operation GET /packages/search package searchPackages
+	// ---
+	// summary: Search the packages of all owners
+	// description: Only packages of owners visible to the user are found.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: type
+	//   in: query
+	//   description: package type filter
+	//   type: string
+	//   enum: [alpine, cargo, chef, composer, conan, conda, container, debian, generic, go, helm, maven, npm, nuget, pub, pypi, rpm, rubygems, swift, vagrant]
+	// - name: owner
+	//   in: query
+	//   description: owner filter
+	//   type: string
+	// - name: q
+	//   in: query
+	//   description: name filter, packages whose name contains the keyword are found
+	//   type: string
+	// - name: property
+	//   in: query
+	//   description: version property filter given as "name:value", only versions with all listed properties are found
+	//   type: array
+	//   items:
+	//     type: string
+	//   collectionFormat: multi
+	// - name: latest
+	//   in: query
+	//   description: only find the latest version of every package
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	listOptions := utils.GetListOptions(ctx)
+
+	opts := &packages.PackageSearchOptions{
+		OwnerCond:         user_model.BuildCanSeeUserCondition(ctx.Doer),
+		HideInternalRepos: ctx.Doer == nil,
+		Type:              packages.Type(ctx.FormTrim("type")),
+		Name:              packages.SearchValue{Value: ctx.FormTrim("q")},
+		IsInternal:        util.OptionalBoolFalse,
+		Paginator:         &listOptions,
+	}
+
+	if ownerName := ctx.FormTrim("owner"); ownerName != "" {
+		owner, err := user_model.GetUserByName(ctx, ownerName)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				// an owner which doesn't exist is treated like an invisible one
+				ctx.SetTotalCountHeader(0)
+				ctx.JSON(http.StatusOK, []*api.Package{})
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		opts.OwnerID = owner.ID
+	}
+
+	for _, property := range ctx.FormStrings("property") {
+		name, value, ok := strings.Cut(property, ":")
+		if !ok || name == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid property filter %q, it must be given as name:value", property))
+			return
+		}
+		if opts.Properties == nil {
+			opts.Properties = make(map[string]string)
+		}
+		opts.Properties[name] = value
+	}
+
+	search := packages.SearchVersions
+	if ctx.FormBool("latest") {
+		search = packages.SearchLatestVersions
+	}
+	pvs, count, err := search(ctx, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SearchVersions", err)
+		return
+	}
+
+	pds, err := packages.GetPackageDescriptors(ctx, pvs)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPackageDescriptors", err)
+		return
+	}
+
+	apiPackages := make([]*api.Package, 0, len(pds))
+	for _, pd := range pds {
+		apiPackage, err := convert.ToPackage(ctx, pd, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "Error converting package for api", err)
+			return
+		}
+		apiPackages = append(apiPackages, apiPackage)
+	}
+
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiPackages)
+}
+
+// BatchPackageMetadata gets the metadata of many package versions at once
+func BatchPackageMetadata(ctx *context.APIContext) {
+	// swagger:operation POST /packages/search/batch package batchPackageMetadata
+	// ---
+	// summary: Get the metadata of many package versions at once
+	// description: The results are in the order of the requested versions. Versions which don't exist or whose
+	//   owner isn't visible to the user aren't found. At most as many versions as the maximum page size can be requested.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/BatchPackageMetadataOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PackageMetadataList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.BatchPackageMetadataOption)
+	if len(form.Versions) > setting.API.MaxResponseItems {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("at most %d versions can be requested at once", setting.API.MaxResponseItems))
+		return
+	}
+
+	owners := make(map[string]*user_model.User)
+	results := make([]*api.PackageMetadata, 0, len(form.Versions))
+	for _, id := range form.Versions {
+		if id == nil {
+			continue
+		}
+		result := &api.PackageMetadata{PackageVersionIdentifier: *id}
+		results = append(results, result)
+
+		owner, ok := owners[strings.ToLower(id.Owner)]
+		if !ok {
+			var err error
+			owner, err = user_model.GetUserByName(ctx, id.Owner)
+			if err != nil && !user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+				return
+			}
+			if owner != nil && !user_model.IsUserVisibleToViewer(ctx, owner, ctx.Doer) {
+				owner = nil
+			}
+			owners[strings.ToLower(id.Owner)] = owner
+		}
+		if owner == nil {
+			continue
+		}
+
+		pv, err := packages.GetVersionByNameAndVersion(ctx, owner.ID, packages.Type(id.Type), id.Name, id.Version)
+		if err != nil {
+			if err == packages.ErrPackageNotExist {
+				continue
+			}
+			ctx.Error(http.StatusInternalServerError, "GetVersionByNameAndVersion", err)
+			return
+		}
+		pd, err := packages.GetPackageDescriptor(ctx, pv)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetPackageDescriptor", err)
+			return
+		}
+		if result.Package, err = convert.ToPackage(ctx, pd, ctx.Doer); err != nil {
+			ctx.Error(http.StatusInternalServerError, "Error converting package for api", err)
+			return
+		}
+		result.Found = true
+		result.Metadata = pd.Metadata
+		result.Properties = make(map[string]string, len(pd.VersionProperties))
+		for _, pvp := range pd.VersionProperties {
+			result.Properties[pvp.Name] = pvp.Value
+		}
+	}
+
+	ctx.JSON(http.StatusOK, results)
+}
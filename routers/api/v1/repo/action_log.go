@@ -0,0 +1,361 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/eventsource"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+
+	runnerv1 "code.gitea.io/actions-proto-go/runner/v1"
+)
+
+const (
+	// actionLogBatchSize is the number of lines read from the stored log at once
+	actionLogBatchSize = 1000
+	// actionLogChunkSize is the maximum size of the content of a streamed event, longer lines are split
+	actionLogChunkSize = 4096
+	// actionLogPollInterval is how often a followed job is checked for new lines
+	actionLogPollInterval = 2 * time.Second
+)
+
+// getActionJobForAPI returns the job of the current repository given in the path, or responds with an error
+func getActionJobForAPI(ctx *context.APIContext) *actions_model.ActionRunJob {
+	job, err := actions_model.GetRunJobByID(ctx, ctx.ParamsInt64(":job_id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRunJobByID", err)
+		}
+		return nil
+	}
+	if job.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return nil
+	}
+	return job
+}
+
+// getActionJobTask returns the task currently running or last run for the job, or nil if it hasn't been picked up yet
+func getActionJobTask(ctx *context.APIContext, job *actions_model.ActionRunJob) (*actions_model.ActionTask, error) {
+	if job.TaskID == 0 {
+		return nil, nil
+	}
+	task, err := actions_model.GetTaskByID(ctx, job.TaskID)
+	if err != nil {
+		return nil, fmt.Errorf("GetTaskByID: %w", err)
+	}
+	return task, nil
+}
+
+// readActionLogRows reads at most limit lines of the log of the task, starting with the line after cursor
+func readActionLogRows(ctx *context.APIContext, task *actions_model.ActionTask, cursor, limit int64) ([]*runnerv1.LogRow, error) {
+	// the indexes can lag behind the length if the task was read while its logs were written
+	if cursor >= task.LogLength || cursor >= int64(len(task.LogIndexes)) {
+		return nil, nil
+	}
+	if limit > task.LogLength-cursor {
+		limit = task.LogLength - cursor
+	}
+	return actions.ReadLogs(ctx, task.LogInStorage, task.LogFilename, task.LogIndexes[cursor], limit)
+}
+
+// StreamActionJobLogs streams the log of a job, optionally following it while the job is running
+func StreamActionJobLogs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/jobs/{job_id}/logs repository streamActionJobLogs
+	// ---
+	// summary: Stream the log of an Actions job
+	// description: If server-sent events are accepted, every line is sent as a `log` event with an ActionLogLine,
+	//   long lines are split into several events without breaking ANSI escape sequences. The id of an event is the
+	//   number of completely sent lines, so a reconnecting client resumes with the `Last-Event-ID` header. A `done`
+	//   event with the status of the job ends the stream. Otherwise the lines are sent as chunked plain text
+	//   prefixed with their time.
+	// produces:
+	// - text/event-stream
+	// - text/plain
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: job_id
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: cursor
+	//   in: query
+	//   description: number of lines to skip, overridden by the Last-Event-ID header
+	//   type: integer
+	//   format: int64
+	// - name: follow
+	//   in: query
+	//   description: keep the stream open and send new lines until the job is done
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     description: the log of the job
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "410":
+	//     description: the log of the job was deleted after the retention period
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	job := getActionJobForAPI(ctx)
+	if job == nil {
+		return
+	}
+
+	cursor := ctx.FormInt64("cursor")
+	if lastEventID := ctx.Req.Header.Get("Last-Event-ID"); lastEventID != "" {
+		var err error
+		if cursor, err = strconv.ParseInt(lastEventID, 10, 64); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("invalid Last-Event-ID %q", lastEventID))
+			return
+		}
+	}
+	if cursor < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "cursor must not be negative")
+		return
+	}
+	follow := ctx.FormBool("follow")
+	isEventStream := strings.Contains(ctx.Req.Header.Get("Accept"), "text/event-stream")
+
+	task, err := getActionJobTask(ctx, job)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if task != nil && task.LogExpired {
+		ctx.Error(http.StatusGone, "", "the log of the job has expired")
+		return
+	}
+
+	if isEventStream {
+		ctx.Resp.Header().Set("Content-Type", "text/event-stream")
+		ctx.Resp.Header().Set("Cache-Control", "no-cache")
+	} else {
+		ctx.Resp.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	ctx.Resp.Header().Set("X-Accel-Buffering", "no")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	ctx.Resp.Flush()
+
+	writeRows := func(rows []*runnerv1.LogRow) error {
+		for _, row := range rows {
+			number := cursor + 1
+			if !isEventStream {
+				if _, err := ctx.Resp.Write([]byte(actions.FormatLog(row.Time.AsTime(), row.Content) + "\n")); err != nil {
+					return err
+				}
+				cursor = number
+				continue
+			}
+			chunks := actions.ChunkLogContent(row.Content, actionLogChunkSize)
+			for i, chunk := range chunks {
+				continued := i < len(chunks)-1
+				id := number
+				if continued {
+					id = cursor
+				}
+				event := &eventsource.Event{
+					Name: "log",
+					ID:   strconv.FormatInt(id, 10),
+					Data: &api.ActionLogLine{
+						Number:    number,
+						Time:      row.Time.AsTime(),
+						Content:   chunk,
+						Continued: continued,
+					},
+				}
+				if _, err := event.WriteTo(ctx.Resp); err != nil {
+					return err
+				}
+			}
+			cursor = number
+		}
+		ctx.Resp.Flush()
+		return nil
+	}
+
+	taskID := job.TaskID
+	shutdownCtx := graceful.GetManager().ShutdownContext()
+	for {
+		// read everything which is available before waiting for new lines
+		hasNewRows := false
+		for task != nil && !task.LogExpired {
+			rows, err := readActionLogRows(ctx, task, cursor, actionLogBatchSize)
+			if err != nil {
+				// the response is already started, so the error can't be reported anymore
+				log.Error("readActionLogRows: %v", err)
+				return
+			}
+			if len(rows) == 0 {
+				break
+			}
+			hasNewRows = true
+			if err := writeRows(rows); err != nil {
+				// the client is gone
+				return
+			}
+		}
+
+		// all logs are uploaded once they are in the storage, a stopped task without them was abandoned by its runner
+		isFinished := task != nil && (task.LogExpired || task.LogInStorage || (task.IsStopped() && !hasNewRows))
+		if !follow || isFinished || (task == nil && job.Status.IsDone()) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-shutdownCtx.Done():
+			return
+		case <-time.After(actionLogPollInterval):
+		}
+
+		if job, err = actions_model.GetRunJobByID(ctx, job.ID); err != nil {
+			log.Error("GetRunJobByID: %v", err)
+			return
+		}
+		if taskID != 0 && job.TaskID != taskID {
+			// the job was rerun, the lines of the new attempt don't continue the streamed ones
+			break
+		}
+		taskID = job.TaskID
+		if task, err = getActionJobTask(ctx, job); err != nil {
+			log.Error("getActionJobTask: %v", err)
+			return
+		}
+	}
+
+	if isEventStream {
+		event := &eventsource.Event{
+			Name: "done",
+			ID:   strconv.FormatInt(cursor, 10),
+			Data: map[string]string{"status": job.Status.String()},
+		}
+		_, _ = event.WriteTo(ctx.Resp)
+		ctx.Resp.Flush()
+	}
+}
+
+// SearchActionJobLogs searches the log of a job for lines containing a text
+func SearchActionJobLogs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/actions/jobs/{job_id}/logs/search repository searchActionJobLogs
+	// ---
+	// summary: Search the log of an Actions job
+	// description: The lines are matched case-insensitively without their ANSI escape sequences.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: job_id
+	//   in: path
+	//   description: id of the job
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: q
+	//   in: query
+	//   description: text to search for
+	//   type: string
+	//   required: true
+	// - name: limit
+	//   in: query
+	//   description: maximum number of matching lines to return
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ActionLogLineList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "410":
+	//     description: the log of the job was deleted after the retention period
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	keyword := strings.ToLower(ctx.FormTrim("q"))
+	if keyword == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the search text must not be empty")
+		return
+	}
+	limit := ctx.FormInt("limit")
+	if limit <= 0 || limit > setting.API.MaxResponseItems {
+		limit = setting.API.MaxResponseItems
+	}
+
+	job := getActionJobForAPI(ctx)
+	if job == nil {
+		return
+	}
+	task, err := getActionJobTask(ctx, job)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if task != nil && task.LogExpired {
+		ctx.Error(http.StatusGone, "", "the log of the job has expired")
+		return
+	}
+
+	lines := make([]*api.ActionLogLine, 0, 10)
+	for cursor := int64(0); task != nil && len(lines) < limit; {
+		rows, err := readActionLogRows(ctx, task, cursor, actionLogBatchSize)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "readActionLogRows", err)
+			return
+		}
+		if len(rows) == 0 {
+			break
+		}
+		for _, row := range rows {
+			cursor++
+			if !strings.Contains(strings.ToLower(actions.StripANSI(row.Content)), keyword) {
+				continue
+			}
+			lines = append(lines, &api.ActionLogLine{
+				Number:  cursor,
+				Time:    row.Time.AsTime(),
+				Content: row.Content,
+			})
+			if len(lines) == limit {
+				break
+			}
+		}
+	}
+
+	ctx.JSON(http.StatusOK, lines)
+}
@@ -0,0 +1,126 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	badge_service "code.gitea.io/gitea/services/badge"
+)
+
+func toBadgeToken(repo *repo_model.Repository, t *repo_model.BadgeToken) *api.BadgeToken {
+	token := t.Token()
+	badges := make(map[string]string, len(badge_service.Kinds))
+	for _, kind := range badge_service.Kinds {
+		badges[string(kind)] = badge_service.URL(repo, kind, token)
+	}
+	return &api.BadgeToken{Token: token, Badges: badges}
+}
+
+// GetBadgeToken gets the token which allows to embed the badges of a repository
+func GetBadgeToken(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/badges/token repository repoGetBadgeToken
+	// ---
+	// summary: Get the token which allows to embed the badges of a private repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BadgeToken"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t, err := repo_model.GetBadgeToken(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetBadgeToken", err)
+		return
+	} else if t == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, toBadgeToken(ctx.Repo.Repository, t))
+}
+
+// RegenerateBadgeToken generates a new badge token, the previous token stops working
+func RegenerateBadgeToken(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/badges/token repository repoRegenerateBadgeToken
+	// ---
+	// summary: Generate a new token to embed the badges of a private repository, revoking the previous token
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/BadgeToken"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	t, err := repo_model.RegenerateBadgeToken(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "RegenerateBadgeToken", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, toBadgeToken(ctx.Repo.Repository, t))
+}
+
+// DeleteBadgeToken revokes the badge token of a repository
+func DeleteBadgeToken(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/badges/token repository repoDeleteBadgeToken
+	// ---
+	// summary: Revoke the token to embed the badges of a private repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := repo_model.DeleteBadgeToken(ctx, ctx.Repo.Repository.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "DeleteBadgeToken", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
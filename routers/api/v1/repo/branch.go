@@ -450,12 +450,6 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		return
 	}
 
-	isPlainRule := !git_model.IsRuleNameSpecial(ruleName)
-	var isBranchExist bool
-	if isPlainRule {
-		isBranchExist = git.IsBranchExist(ctx.Req.Context(), ctx.Repo.Repository.RepoPath(), ruleName)
-	}
-
 	protectBranch, err := git_model.GetProtectedBranchRuleByName(ctx, repo.ID, ruleName)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "GetProtectBranchOfRepoByName", err)
@@ -465,37 +459,75 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		return
 	}
 
+	protectBranch = &git_model.ProtectedBranch{
+		RepoID:   ctx.Repo.Repository.ID,
+		RuleName: ruleName,
+	}
+	if !applyBranchProtectionOption(ctx, protectBranch, form) {
+		return
+	}
+
+	// Reload from db to get all whitelists
+	bp, err := git_model.GetProtectedBranchRuleByName(ctx, ctx.Repo.Repository.ID, ruleName)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetProtectedBranchByID", err)
+		return
+	}
+	if bp == nil || bp.RepoID != ctx.Repo.Repository.ID {
+		ctx.Error(http.StatusInternalServerError, "New branch protection not found", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToBranchProtection(bp))
+}
+
+// applyBranchProtectionOption replaces the settings of the branch protection by the ones of `form`, resolving the
+// names of its whitelists, and saves it. If an error occurs, write to `ctx` accordingly. Return whether successful
+func applyBranchProtectionOption(ctx *context.APIContext, protectBranch *git_model.ProtectedBranch, form *api.CreateBranchProtectionOption) bool {
+	repo := ctx.Repo.Repository
+	ruleName := protectBranch.RuleName
+
+	isPlainRule := !git_model.IsRuleNameSpecial(ruleName)
+	var isBranchExist bool
+	if isPlainRule {
+		isBranchExist = git.IsBranchExist(ctx.Req.Context(), ctx.Repo.Repository.RepoPath(), ruleName)
+	}
+
 	var requiredApprovals int64
 	if form.RequiredApprovals > 0 {
 		requiredApprovals = form.RequiredApprovals
 	}
+	if form.MinDiffCoverage < 0 || form.MinDiffCoverage > 100 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "min_diff_coverage must be between 0 and 100")
+		return false
+	}
 
 	whitelistUsers, err := user_model.GetUserIDsByNames(ctx, form.PushWhitelistUsernames, false)
 	if err != nil {
 		if user_model.IsErrUserNotExist(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "User does not exist", err)
-			return
+			return false
 		}
 		ctx.Error(http.StatusInternalServerError, "GetUserIDsByNames", err)
-		return
+		return false
 	}
 	mergeWhitelistUsers, err := user_model.GetUserIDsByNames(ctx, form.MergeWhitelistUsernames, false)
 	if err != nil {
 		if user_model.IsErrUserNotExist(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "User does not exist", err)
-			return
+			return false
 		}
 		ctx.Error(http.StatusInternalServerError, "GetUserIDsByNames", err)
-		return
+		return false
 	}
 	approvalsWhitelistUsers, err := user_model.GetUserIDsByNames(ctx, form.ApprovalsWhitelistUsernames, false)
 	if err != nil {
 		if user_model.IsErrUserNotExist(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "User does not exist", err)
-			return
+			return false
 		}
 		ctx.Error(http.StatusInternalServerError, "GetUserIDsByNames", err)
-		return
+		return false
 	}
 	var whitelistTeams, mergeWhitelistTeams, approvalsWhitelistTeams []int64
 	if repo.Owner.IsOrganization() {
@@ -503,34 +535,36 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		if err != nil {
 			if organization.IsErrTeamNotExist(err) {
 				ctx.Error(http.StatusUnprocessableEntity, "Team does not exist", err)
-				return
+				return false
 			}
 			ctx.Error(http.StatusInternalServerError, "GetTeamIDsByNames", err)
-			return
+			return false
 		}
 		mergeWhitelistTeams, err = organization.GetTeamIDsByNames(repo.OwnerID, form.MergeWhitelistTeams, false)
 		if err != nil {
 			if organization.IsErrTeamNotExist(err) {
 				ctx.Error(http.StatusUnprocessableEntity, "Team does not exist", err)
-				return
+				return false
 			}
 			ctx.Error(http.StatusInternalServerError, "GetTeamIDsByNames", err)
-			return
+			return false
 		}
 		approvalsWhitelistTeams, err = organization.GetTeamIDsByNames(repo.OwnerID, form.ApprovalsWhitelistTeams, false)
 		if err != nil {
 			if organization.IsErrTeamNotExist(err) {
 				ctx.Error(http.StatusUnprocessableEntity, "Team does not exist", err)
-				return
+				return false
 			}
 			ctx.Error(http.StatusInternalServerError, "GetTeamIDsByNames", err)
-			return
+			return false
 		}
 	}
 
-	protectBranch = &git_model.ProtectedBranch{
-		RepoID:                        ctx.Repo.Repository.ID,
-		RuleName:                      ruleName,
+	*protectBranch = git_model.ProtectedBranch{
+		ID:                            protectBranch.ID,
+		RepoID:                        protectBranch.RepoID,
+		RuleName:                      protectBranch.RuleName,
+		CreatedUnix:                   protectBranch.CreatedUnix,
 		CanPush:                       form.EnablePush,
 		EnableWhitelist:               form.EnablePush && form.EnablePushWhitelist,
 		EnableMergeWhitelist:          form.EnableMergeWhitelist,
@@ -542,10 +576,13 @@ func CreateBranchProtection(ctx *context.APIContext) {
 		BlockOnRejectedReviews:        form.BlockOnRejectedReviews,
 		BlockOnOfficialReviewRequests: form.BlockOnOfficialReviewRequests,
 		DismissStaleApprovals:         form.DismissStaleApprovals,
+		RequireReviewChecklist:        form.RequireReviewChecklist,
 		RequireSignedCommits:          form.RequireSignedCommits,
 		ProtectedFilePatterns:         form.ProtectedFilePatterns,
 		UnprotectedFilePatterns:       form.UnprotectedFilePatterns,
 		BlockOnOutdatedBranch:         form.BlockOnOutdatedBranch,
+		BlockOnCodeScanningAlerts:     form.BlockOnCodeScanningAlerts,
+		MinDiffCoverage:               form.MinDiffCoverage,
 	}
 
 	err = git_model.UpdateProtectBranch(ctx, ctx.Repo.Repository, protectBranch, git_model.WhitelistOptions{
@@ -558,13 +595,13 @@ func CreateBranchProtection(ctx *context.APIContext) {
 	})
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, "UpdateProtectBranch", err)
-		return
+		return false
 	}
 
 	if isBranchExist {
 		if err = pull_service.CheckPRsForBaseBranch(ctx.Repo.Repository, ruleName); err != nil {
 			ctx.Error(http.StatusInternalServerError, "CheckPRsForBaseBranch", err)
-			return
+			return false
 		}
 	} else {
 		if !isPlainRule {
@@ -572,7 +609,7 @@ func CreateBranchProtection(ctx *context.APIContext) {
 				ctx.Repo.GitRepo, err = git.OpenRepository(ctx, ctx.Repo.Repository.RepoPath())
 				if err != nil {
 					ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
-					return
+					return false
 				}
 				defer func() {
 					ctx.Repo.GitRepo.Close()
@@ -583,30 +620,18 @@ func CreateBranchProtection(ctx *context.APIContext) {
 			matchedBranches, err := git_model.FindAllMatchedBranches(ctx, ctx.Repo.GitRepo, ruleName)
 			if err != nil {
 				ctx.Error(http.StatusInternalServerError, "FindAllMatchedBranches", err)
-				return
+				return false
 			}
 
 			for _, branchName := range matchedBranches {
 				if err = pull_service.CheckPRsForBaseBranch(ctx.Repo.Repository, branchName); err != nil {
 					ctx.Error(http.StatusInternalServerError, "CheckPRsForBaseBranch", err)
-					return
+					return false
 				}
 			}
 		}
 	}
-
-	// Reload from db to get all whitelists
-	bp, err := git_model.GetProtectedBranchRuleByName(ctx, ctx.Repo.Repository.ID, ruleName)
-	if err != nil {
-		ctx.Error(http.StatusInternalServerError, "GetProtectedBranchByID", err)
-		return
-	}
-	if bp == nil || bp.RepoID != ctx.Repo.Repository.ID {
-		ctx.Error(http.StatusInternalServerError, "New branch protection not found", err)
-		return
-	}
-
-	ctx.JSON(http.StatusCreated, convert.ToBranchProtection(bp))
+	return true
 }
 
 // EditBranchProtection edits a branch protection for a repo
@@ -710,6 +735,10 @@ func EditBranchProtection(ctx *context.APIContext) {
 		protectBranch.DismissStaleApprovals = *form.DismissStaleApprovals
 	}
 
+	if form.RequireReviewChecklist != nil {
+		protectBranch.RequireReviewChecklist = *form.RequireReviewChecklist
+	}
+
 	if form.RequireSignedCommits != nil {
 		protectBranch.RequireSignedCommits = *form.RequireSignedCommits
 	}
@@ -726,6 +755,18 @@ func EditBranchProtection(ctx *context.APIContext) {
 		protectBranch.BlockOnOutdatedBranch = *form.BlockOnOutdatedBranch
 	}
 
+	if form.BlockOnCodeScanningAlerts != nil {
+		protectBranch.BlockOnCodeScanningAlerts = *form.BlockOnCodeScanningAlerts
+	}
+
+	if form.MinDiffCoverage != nil {
+		if *form.MinDiffCoverage < 0 || *form.MinDiffCoverage > 100 {
+			ctx.Error(http.StatusUnprocessableEntity, "", "min_diff_coverage must be between 0 and 100")
+			return
+		}
+		protectBranch.MinDiffCoverage = *form.MinDiffCoverage
+	}
+
 	var whitelistUsers []int64
 	if form.PushWhitelistUsernames != nil {
 		whitelistUsers, err = user_model.GetUserIDsByNames(ctx, form.PushWhitelistUsernames, false)
@@ -0,0 +1,149 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+	pull_service "code.gitea.io/gitea/services/pull"
+	"code.gitea.io/gitea/services/repository/files"
+)
+
+// CherryPick handles API call for cherry-picking or reverting a range of commits
+func CherryPick(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/cherry-pick repository repoCherryPick
+	// ---
+	// summary: Cherry-pick or revert a range of commits onto a branch, optionally opening a pull request with the result
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/CherryPickOptions"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/CherryPickResponse"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	apiOpts := web.GetForm(ctx).(*api.CherryPickOptions)
+
+	if apiOpts.Mainline < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "mainline must not be negative")
+		return
+	}
+	if apiOpts.CreatePullRequest {
+		if apiOpts.NewBranchName == "" || apiOpts.NewBranchName == apiOpts.BranchName {
+			ctx.Error(http.StatusUnprocessableEntity, "", "new_branch is required to create a pull request")
+			return
+		}
+		if !ctx.Repo.Repository.UnitEnabled(ctx, unit.TypePullRequests) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "pull requests are disabled for this repository")
+			return
+		}
+	}
+
+	if !canWriteFiles(ctx, apiOpts.BranchName) {
+		ctx.Error(http.StatusForbidden, "CherryPick", repo_model.ErrUserDoesNotHaveAccessToRepo{
+			UserID:   ctx.Doer.ID,
+			RepoName: ctx.Repo.Repository.LowerName,
+		})
+		return
+	}
+
+	opts := &files.CherryPickRangeOptions{
+		OldBranch: apiOpts.BranchName,
+		NewBranch: apiOpts.NewBranchName,
+		From:      apiOpts.From,
+		To:        apiOpts.To,
+		Mainline:  apiOpts.Mainline,
+		Committer: &files.IdentityOptions{
+			Name:  apiOpts.Committer.Name,
+			Email: apiOpts.Committer.Email,
+		},
+		Author: &files.IdentityOptions{
+			Name:  apiOpts.Author.Name,
+			Email: apiOpts.Author.Email,
+		},
+		Signoff: apiOpts.Signoff,
+	}
+	if !apiOpts.Dates.Author.IsZero() && !apiOpts.Dates.Committer.IsZero() {
+		opts.Dates = &files.CommitDateOptions{
+			Author:    apiOpts.Dates.Author,
+			Committer: apiOpts.Dates.Committer,
+		}
+	}
+
+	commits, err := files.CherryPickRange(ctx, ctx.Repo.Repository, ctx.Doer, apiOpts.Revert, opts)
+	if err != nil {
+		switch {
+		case models.IsErrUserCannotCommit(err):
+			ctx.Error(http.StatusForbidden, "Access", err)
+		case models.IsErrCherryPickConflicts(err):
+			ctx.Error(http.StatusConflict, "CherryPickConflicts", err)
+		case models.IsErrBranchAlreadyExists(err) || models.IsErrCommitIDDoesNotMatch(err) || models.IsErrInvalidMainline(err) ||
+			errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "Invalid", err)
+		case models.IsErrBranchDoesNotExist(err) || git.IsErrBranchNotExist(err) || git.IsErrNotExist(err):
+			ctx.Error(http.StatusNotFound, "NotFound", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "CherryPickRange", err)
+		}
+		return
+	}
+
+	result := &api.CherryPickResponse{Commits: commits}
+	if apiOpts.CreatePullRequest {
+		title := apiOpts.PullRequestTitle
+		if title == "" {
+			action := "Cherry-pick"
+			if apiOpts.Revert {
+				action = "Revert"
+			}
+			if apiOpts.From != "" {
+				title = fmt.Sprintf("%s %s..%s onto %s", action, apiOpts.From, apiOpts.To, opts.OldBranch)
+			} else {
+				title = fmt.Sprintf("%s %s onto %s", action, apiOpts.To, opts.OldBranch)
+			}
+		}
+		pr, err := pull_service.NewPullRequestFromBranch(ctx, ctx.Repo.Repository, ctx.Doer, opts.NewBranch, opts.OldBranch, title, apiOpts.PullRequestBody)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "NewPullRequestFromBranch", err)
+			return
+		}
+		result.PullRequest = convert.ToAPIPullRequest(ctx, pr, ctx.Doer)
+	}
+
+	ctx.JSON(http.StatusCreated, result)
+}
@@ -0,0 +1,358 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+
+	codescanning_model "code.gitea.io/gitea/models/codescanning"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/sarif"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	codescanning_service "code.gitea.io/gitea/services/codescanning"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// UploadCodeScanningSARIF uploads a SARIF report of a code scanning tool
+func UploadCodeScanningSARIF(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/code_scanning/sarifs repository repoUploadCodeScanningSARIF
+	// ---
+	// summary: Upload a SARIF report of a code scanning analysis
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/UploadSARIFOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/CodeScanningAnalysisList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "413":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.UploadSARIFOption)
+
+	data, err := base64.StdEncoding.DecodeString(form.SARIF)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", "sarif is not base64 encoded")
+		return
+	}
+	var r io.Reader = bytes.NewReader(data)
+	if len(data) > 2 && data[0] == 0x1f && data[1] == 0x8b {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	analyses, err := codescanning_service.UploadSARIF(ctx, ctx.Repo.Repository, ctx.Doer, form.Ref, form.CommitSHA, r)
+	if err != nil {
+		switch {
+		case errors.Is(err, codescanning_service.ErrSARIFTooLarge):
+			ctx.Error(http.StatusRequestEntityTooLarge, "", err)
+		case errors.Is(err, sarif.ErrInvalidSARIF),
+			errors.Is(err, sarif.ErrUnsupportedVersion),
+			errors.Is(err, codescanning_service.ErrSARIFUnreadable):
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "UploadSARIF", err)
+		}
+		return
+	}
+
+	result := make([]*api.CodeScanningAnalysis, 0, len(analyses))
+	for _, analysis := range analyses {
+		result = append(result, convert.ToCodeScanningAnalysis(analysis))
+	}
+	ctx.JSON(http.StatusCreated, result)
+}
+
+// ListCodeScanningAlerts lists the code scanning alerts of a repository
+func ListCodeScanningAlerts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/code_scanning/alerts repository repoListCodeScanningAlerts
+	// ---
+	// summary: List the code scanning alerts of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: full name of the ref, defaults to the default branch
+	//   type: string
+	// - name: state
+	//   in: query
+	//   description: state of the alerts
+	//   type: string
+	//   enum: [open, dismissed, fixed]
+	// - name: severity
+	//   in: query
+	//   description: severity of the alerts
+	//   type: string
+	//   enum: [critical, high, medium, low]
+	// - name: tool
+	//   in: query
+	//   description: name of the tool which reported the alerts
+	//   type: string
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeScanningAlertList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	opts := codescanning_model.FindAlertsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+		Ref:         ctx.FormTrim("ref"),
+		Tool:        ctx.FormTrim("tool"),
+		Severity:    sarif.Severity(ctx.FormTrim("severity")),
+	}
+	if opts.Ref == "" {
+		opts.Ref = git.BranchPrefix + ctx.Repo.Repository.DefaultBranch
+	}
+	if state := ctx.FormTrim("state"); state != "" {
+		if opts.State = codescanning_model.ParseAlertState(state); opts.State == 0 {
+			ctx.Error(http.StatusUnprocessableEntity, "", "invalid state")
+			return
+		}
+	}
+
+	alerts, count, err := codescanning_model.FindAlerts(ctx, opts)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.CodeScanningAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		result = append(result, convert.ToCodeScanningAlert(ctx, alert, ctx.Doer))
+	}
+	ctx.SetLinkHeader(int(count), opts.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// GetCodeScanningAlert returns a single code scanning alert
+func GetCodeScanningAlert(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/code_scanning/alerts/{id} repository repoGetCodeScanningAlert
+	// ---
+	// summary: Get a code scanning alert
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the alert
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeScanningAlert"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	alert := getCodeScanningAlert(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToCodeScanningAlert(ctx, alert, ctx.Doer))
+}
+
+// EditCodeScanningAlert dismisses or reopens a code scanning alert
+func EditCodeScanningAlert(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/code_scanning/alerts/{id} repository repoEditCodeScanningAlert
+	// ---
+	// summary: Dismiss or reopen a code scanning alert
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the alert
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditCodeScanningAlertOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeScanningAlert"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditCodeScanningAlertOption)
+
+	alert := getCodeScanningAlert(ctx)
+	if ctx.Written() {
+		return
+	}
+	if alert.State == codescanning_model.AlertStateFixed {
+		ctx.Error(http.StatusUnprocessableEntity, "", "fixed alerts can not be changed")
+		return
+	}
+
+	var err error
+	if codescanning_model.ParseAlertState(form.State) == codescanning_model.AlertStateDismissed {
+		err = codescanning_model.DismissAlert(ctx, alert, ctx.Doer.ID, form.DismissedReason)
+	} else {
+		err = codescanning_model.ReopenAlert(ctx, alert)
+	}
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateAlert", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToCodeScanningAlert(ctx, alert, ctx.Doer))
+}
+
+func getCodeScanningAlert(ctx *context.APIContext) *codescanning_model.Alert {
+	alert, err := codescanning_model.GetAlertByID(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetAlertByID", err)
+		}
+		return nil
+	}
+	return alert
+}
+
+// ListPullRequestCodeScanningAlerts lists the open code scanning alerts of the files changed by a pull request
+func ListPullRequestCodeScanningAlerts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/code_scanning repository repoListPullRequestCodeScanningAlerts
+	// ---
+	// summary: List the open code scanning alerts in the files changed by a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CodeScanningAlertList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	alerts, err := codescanning_service.GetPullRequestAlerts(ctx, pr)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	changedFiles, err := ctx.Repo.GitRepo.GetFilesChangedBetween(pr.MergeBase, pr.GetGitRefName())
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFilesChangedBetween", err)
+		return
+	}
+	changed := make(map[string]bool, len(changedFiles))
+	for _, file := range changedFiles {
+		changed[file] = true
+	}
+
+	result := make([]*api.CodeScanningAlert, 0, len(alerts))
+	for _, alert := range alerts {
+		if changed[alert.Path] {
+			result = append(result, convert.ToCodeScanningAlert(ctx, alert, ctx.Doer))
+		}
+	}
+	ctx.JSON(http.StatusOK, result)
+}
@@ -7,6 +7,7 @@ package repo
 import (
 	"errors"
 	"net/http"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	"code.gitea.io/gitea/models/perm"
@@ -16,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/convert"
@@ -159,6 +161,15 @@ func AddCollaborator(ctx *context.APIContext) {
 
 	form := web.GetForm(ctx).(*api.AddCollaboratorOption)
 
+	var expires timeutil.TimeStamp
+	if form.Expires != nil {
+		if !form.Expires.After(time.Now()) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "expiration date must be in the future")
+			return
+		}
+		expires = timeutil.TimeStamp(form.Expires.Unix())
+	}
+
 	collaborator, err := user_model.GetUserByName(ctx, ctx.Params(":collaborator"))
 	if err != nil {
 		if user_model.IsErrUserNotExist(err) {
@@ -186,6 +197,11 @@ func AddCollaborator(ctx *context.APIContext) {
 		}
 	}
 
+	if err := repo_model.SetCollaborationExpiry(ctx, ctx.Repo.Repository.ID, collaborator.ID, expires); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetCollaborationExpiry", err)
+		return
+	}
+
 	ctx.Status(http.StatusNoContent)
 }
 
@@ -0,0 +1,144 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/gitgraph"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+)
+
+// GetCommitGraph returns a page of the commit graph of a repository laid out in lanes
+func GetCommitGraph(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/git/graph repository repoGetCommitGraph
+	// ---
+	// summary: Get the commit graph of a repository with its commits laid out in lanes
+	// description: The commits are ordered newest first, parents always after their children.
+	//   The lanes continue across pages, the active lanes of the response are those of the last commit of the previous page.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: branches, tags or commits the graph starts from, all refs if empty
+	//   type: array
+	//   items:
+	//     type: string
+	// - name: not
+	//   in: query
+	//   description: branch, tag or commit whose ancestors are excluded from the graph
+	//   type: string
+	// - name: hide_pr_refs
+	//   in: query
+	//   description: exclude the refs of pull requests, defaults to true
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CommitGraph"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/EmptyRepository"
+
+	if ctx.Repo.Repository.IsEmpty {
+		ctx.JSON(http.StatusConflict, api.APIError{
+			Message: "Git Repository is empty.",
+			URL:     setting.API.SwaggerURL,
+		})
+		return
+	}
+
+	listOptions := utils.GetListOptions(ctx)
+	if listOptions.PageSize > setting.Git.CommitsRangeSize {
+		listOptions.PageSize = setting.Git.CommitsRangeSize
+	}
+
+	opts := gitgraph.LayoutOptions{
+		HidePRRefs: !ctx.FormOptionalBool("hide_pr_refs").IsFalse(),
+		Page:       listOptions.Page,
+		PageSize:   listOptions.PageSize,
+	}
+	for _, ref := range ctx.FormStrings("ref") {
+		commit, err := ctx.Repo.GitRepo.GetCommit(ref)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				ctx.NotFound("GetCommit", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+			}
+			return
+		}
+		opts.Revisions = append(opts.Revisions, commit.ID.String())
+	}
+	if not := ctx.FormString("not"); not != "" {
+		commit, err := ctx.Repo.GitRepo.GetCommit(not)
+		if err != nil {
+			if git.IsErrNotExist(err) {
+				ctx.NotFound("GetCommit", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+			}
+			return
+		}
+		opts.Not = commit.ID.String()
+	}
+
+	graph, err := gitgraph.GetCommitGraphLayout(ctx, ctx.Repo.GitRepo.Path, opts)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommitGraphLayout", err)
+		return
+	}
+
+	result := &api.CommitGraph{
+		ActiveLanes: graph.ActiveLanes,
+		Commits:     make([]*api.CommitGraphNode, 0, len(graph.Commits)),
+	}
+	for _, c := range graph.Commits {
+		result.Commits = append(result.Commits, &api.CommitGraphNode{
+			SHA:     c.ID,
+			Parents: c.Parents,
+			Refs:    c.Refs,
+			Author: &api.CommitUser{
+				Identity: api.Identity{
+					Name:  c.AuthorName,
+					Email: c.AuthorEmail,
+				},
+				Date: c.AuthorDate.Format(time.RFC3339),
+			},
+			Subject:     c.Subject,
+			Lane:        c.Lane,
+			ParentLanes: c.ParentLanes,
+			MergedLanes: c.MergedLanes,
+			ActiveLanes: c.ActiveLanes,
+		})
+	}
+
+	ctx.SetLinkHeader(int(graph.Total), listOptions.PageSize)
+	ctx.SetTotalCountHeader(graph.Total)
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,407 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	backport_service "code.gitea.io/gitea/services/backport"
+	"code.gitea.io/gitea/services/convert"
+	"code.gitea.io/gitea/services/gitdiff"
+)
+
+// compareFormat is the response format of a comparison, negotiated with the Accept header
+type compareFormat int
+
+const (
+	compareFormatJSON compareFormat = iota
+	compareFormatDiff
+	compareFormatPatch
+)
+
+func parseCompareFormat(accept string) compareFormat {
+	for _, mediaType := range strings.Split(accept, ",") {
+		mediaType, _, _ = strings.Cut(mediaType, ";")
+		switch strings.ToLower(strings.TrimSpace(mediaType)) {
+		case "application/json":
+			return compareFormatJSON
+		case "text/x-diff", "application/vnd.gitea.diff":
+			return compareFormatDiff
+		case "text/x-patch", "application/mbox", "application/vnd.gitea.patch":
+			return compareFormatPatch
+		}
+	}
+	return compareFormatJSON
+}
+
+// resolveCompareRef returns the full reference name of a branch or tag, or the commit id
+func resolveCompareRef(gitRepo *git.Repository, ref string) (string, bool) {
+	switch {
+	case gitRepo.IsBranchExist(ref):
+		return git.BranchPrefix + ref, true
+	case gitRepo.IsTagExist(ref):
+		return git.TagPrefix + ref, true
+	case git.IsValidSHAPattern(ref) && gitRepo.IsCommitExist(ref):
+		return ref, true
+	}
+	return "", false
+}
+
+// isSameForkNetwork checks if two repositories are the same or one was forked from the other or both from the same repository
+func isSameForkNetwork(a, b *repo_model.Repository) bool {
+	return a.ID == b.ID ||
+		(a.IsFork && a.ForkID == b.ID) ||
+		(b.IsFork && b.ForkID == a.ID) ||
+		(a.IsFork && b.IsFork && a.ForkID == b.ForkID)
+}
+
+// getCompareHeadRepo returns the repository of the head of a comparison: the repository itself,
+// the repository given as "owner/repo" or the repository of the given owner in the fork network
+func getCompareHeadRepo(ctx *context.APIContext, headRepoInfo string) *repo_model.Repository {
+	baseRepo := ctx.Repo.Repository
+	if headRepoInfo == "" {
+		return baseRepo
+	}
+
+	var headRepo *repo_model.Repository
+	var err error
+	if ownerName, repoName, ok := strings.Cut(headRepoInfo, "/"); ok {
+		headRepo, err = repo_model.GetRepositoryByOwnerAndName(ctx, ownerName, repoName)
+		if err != nil {
+			if repo_model.IsErrRepoNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+			}
+			return nil
+		}
+	} else {
+		headUser, err := user_model.GetUserByName(ctx, headRepoInfo)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.NotFound()
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return nil
+		}
+		if err := baseRepo.GetBaseRepo(ctx); err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetBaseRepo", err)
+			return nil
+		}
+		switch {
+		case headUser.ID == baseRepo.OwnerID:
+			headRepo = baseRepo
+		case baseRepo.IsFork && headUser.ID == baseRepo.BaseRepo.OwnerID:
+			headRepo = baseRepo.BaseRepo
+		default:
+			headRepo = repo_model.GetForkedRepo(headUser.ID, baseRepo.ID)
+		}
+	}
+	if headRepo == nil || !isSameForkNetwork(baseRepo, headRepo) {
+		ctx.NotFound()
+		return nil
+	}
+
+	perm, err := access_model.GetUserRepoPermission(ctx, headRepo, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+		return nil
+	}
+	if !perm.CanRead(unit.TypeCode) {
+		ctx.NotFound()
+		return nil
+	}
+	return headRepo
+}
+
+// CompareDiff compares two branches, tags or commits
+func CompareDiff(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/compare/{basehead} repository repoCompareDiff
+	// ---
+	// summary: Compare two branches, tags or commits
+	// description: |
+	//   "base...head" compares head with the merge base of base and head, "base..head" compares head with base directly.
+	//   The head may be in another repository of the fork network, given as "owner:ref" or "owner/repo:ref".
+	//   The comparison is returned as JSON, or as a raw diff or patch if "text/x-diff" or "text/x-patch" is accepted.
+	//   Commits are paginated with page and limit, files with files_page and files_limit.
+	//   With equivalent, the commits of head which are already present in base with the same patch,
+	//   like cherry-picked or backported commits, are listed with their counterparts.
+	// produces:
+	// - application/json
+	// - text/x-diff
+	// - text/x-patch
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: basehead
+	//   in: path
+	//   description: compared references, "base...head" or "base..head"
+	//   type: string
+	//   required: true
+	// - name: stat
+	//   in: query
+	//   description: include diff stats for every commit (disable for speedup, default 'true')
+	//   type: boolean
+	// - name: verification
+	//   in: query
+	//   description: include verification for every commit (disable for speedup, default 'true')
+	//   type: boolean
+	// - name: files
+	//   in: query
+	//   description: include a list of affected files for every commit (disable for speedup, default 'true')
+	//   type: boolean
+	// - name: equivalent
+	//   in: query
+	//   description: include the commits of head which introduce the same changes as a commit of base (default 'false')
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of commits to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of commits
+	//   type: integer
+	// - name: files_page
+	//   in: query
+	//   description: page number of changed files to return (1-based)
+	//   type: integer
+	// - name: files_limit
+	//   in: query
+	//   description: page size of changed files
+	//   type: integer
+	// - name: rename_threshold
+	//   in: query
+	//   description: minimal similarity in percent of a renamed file (default 50)
+	//   type: integer
+	// - name: copy_threshold
+	//   in: query
+	//   description: minimal similarity in percent of a copied file, copies are only detected if it is set or find_copies_harder is enabled
+	//   type: integer
+	// - name: find_copies_harder
+	//   in: query
+	//   description: consider unchanged files as source of copies too (default 'false')
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Compare"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	renameDetection, ok := getRenameDetection(ctx)
+	if !ok {
+		return
+	}
+
+	baseRepo := ctx.Repo.Repository
+	infoPath := ctx.Params("*")
+	direct := false
+	baseInfo, headInfo, ok := strings.Cut(infoPath, "...")
+	if !ok {
+		baseInfo, headInfo, ok = strings.Cut(infoPath, "..")
+		direct = true
+	}
+	if !ok || baseInfo == "" || headInfo == "" {
+		ctx.NotFound()
+		return
+	}
+
+	headRepoInfo, headRef, ok := strings.Cut(headInfo, ":")
+	if !ok {
+		headRepoInfo, headRef = "", headInfo
+	}
+	headRepo := getCompareHeadRepo(ctx, headRepoInfo)
+	if ctx.Written() {
+		return
+	}
+
+	headGitRepo := ctx.Repo.GitRepo
+	if headRepo.ID != baseRepo.ID {
+		var err error
+		headGitRepo, err = git.OpenRepository(ctx, headRepo.RepoPath())
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "OpenRepository", err)
+			return
+		}
+		defer headGitRepo.Close()
+	}
+
+	baseRefName, ok := resolveCompareRef(ctx.Repo.GitRepo, baseInfo)
+	if !ok {
+		ctx.NotFound("base reference does not exist")
+		return
+	}
+	headRefName, ok := resolveCompareRef(headGitRepo, headRef)
+	if !ok {
+		ctx.NotFound("head reference does not exist")
+		return
+	}
+
+	// the base commits are fetched into the head repository, so all git commands run there
+	ci, err := headGitRepo.GetCompareInfo(baseRepo.RepoPath(), baseRefName, headRefName, direct, true)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCompareInfo", err)
+		return
+	}
+	beforeCommitID := ci.MergeBase
+	if direct {
+		beforeCommitID = ci.BaseCommitID
+	}
+
+	switch parseCompareFormat(ctx.Req.Header.Get("Accept")) {
+	case compareFormatDiff:
+		ctx.Resp.Header().Set("Content-Type", "text/x-diff; charset=utf-8")
+		if err := headGitRepo.GetDiff(beforeCommitID, ci.HeadCommitID, ctx.Resp); err != nil {
+			log.Error("Unable to write the diff of %s to %s in %-v: %v", beforeCommitID, ci.HeadCommitID, headRepo, err)
+		}
+		return
+	case compareFormatPatch:
+		// the patch series consists of the same commits for both comparisons
+		ctx.Resp.Header().Set("Content-Type", "text/x-patch; charset=utf-8")
+		if err := headGitRepo.GetPatch(ci.MergeBase, ci.HeadCommitID, ctx.Resp); err != nil {
+			log.Error("Unable to write the patch of %s to %s in %-v: %v", ci.MergeBase, ci.HeadCommitID, headRepo, err)
+		}
+		return
+	}
+
+	divergence, err := git.GetDivergingCommits(ctx, headGitRepo.Path, ci.BaseCommitID, ci.HeadCommitID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDivergingCommits", err)
+		return
+	}
+
+	// commits
+	listOptions := utils.GetListOptions(ctx)
+	listOptions.SetDefaultValues()
+	headCommit, err := headGitRepo.GetCommit(ci.HeadCommitID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+		return
+	}
+	var commits []*git.Commit
+	if divergence.Ahead > 0 {
+		baseCommit, err := headGitRepo.GetCommit(ci.BaseCommitID)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetCommit", err)
+			return
+		}
+		skip, take := listOptions.GetSkipTake()
+		commits, err = headGitRepo.CommitsBetweenLimit(headCommit, baseCommit, take, skip)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "CommitsBetweenLimit", err)
+			return
+		}
+	}
+	userCache := make(map[string]*user_model.User)
+	apiCommits := make([]*api.Commit, 0, len(commits))
+	for _, commit := range commits {
+		apiCommit, err := convert.ToCommit(ctx, headRepo, headGitRepo, commit, userCache, convert.ParseCommitOptions(ctx))
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "ToCommit", err)
+			return
+		}
+		apiCommits = append(apiCommits, apiCommit)
+	}
+
+	// files
+	filesOptions := db.ListOptions{
+		Page:     ctx.FormInt("files_page"),
+		PageSize: convert.ToCorrectPageSize(ctx.FormInt("files_limit")),
+	}
+	diff, err := gitdiff.GetDiff(headGitRepo,
+		&gitdiff.DiffOptions{
+			BeforeCommitID:    beforeCommitID,
+			AfterCommitID:     ci.HeadCommitID,
+			MaxLines:          setting.Git.MaxGitDiffLines,
+			MaxLineCharacters: setting.Git.MaxGitDiffLineCharacters,
+			MaxFiles:          -1, // GetDiff() will return all files
+			RenameDetection:   renameDetection,
+			DirectComparison:  direct,
+		})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetDiff", err)
+		return
+	}
+	start, end := filesOptions.GetStartEnd()
+	if end > len(diff.Files) {
+		end = len(diff.Files)
+	}
+	apiFiles := make([]*api.ChangedFile, 0, 10)
+	for i := start; i < end; i++ {
+		apiFiles = append(apiFiles, convert.ToChangedFile(diff.Files[i], headRepo, ci.HeadCommitID))
+	}
+
+	var apiEquivalentCommits []*api.EquivalentCommit
+	if ctx.FormBool("equivalent") && divergence.Ahead > 0 && divergence.Behind > 0 {
+		equivalentCommits, err := backport_service.FindEquivalentCommits(headRepo, headGitRepo, ci.BaseCommitID, ci.HeadCommitID)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "FindEquivalentCommits", err)
+			return
+		}
+		apiEquivalentCommits = make([]*api.EquivalentCommit, 0, len(equivalentCommits))
+		for _, c := range equivalentCommits {
+			apiEquivalentCommits = append(apiEquivalentCommits, &api.EquivalentCommit{SHA: c.CommitID, BaseSHA: c.BaseCommitID})
+		}
+	}
+
+	pageCount := int(math.Ceil(float64(divergence.Ahead) / float64(listOptions.PageSize)))
+	ctx.SetLinkHeader(divergence.Ahead, listOptions.PageSize)
+	ctx.SetTotalCountHeader(int64(divergence.Ahead))
+	ctx.RespHeader().Set("X-HasMore", strconv.FormatBool(listOptions.Page < pageCount))
+	ctx.AppendAccessControlExposeHeaders("X-HasMore")
+
+	ctx.JSON(http.StatusOK, &api.Compare{
+		BaseCommitID:      ci.BaseCommitID,
+		HeadCommitID:      ci.HeadCommitID,
+		MergeBaseCommitID: ci.MergeBase,
+		DirectComparison:  direct,
+		AheadBy:           divergence.Ahead,
+		BehindBy:          divergence.Behind,
+		TotalCommits:      divergence.Ahead,
+		Commits:           apiCommits,
+		TotalFiles:        len(diff.Files),
+		Files:             apiFiles,
+		EquivalentCommits: apiEquivalentCommits,
+	})
+}
+
+// getRenameDetection returns the options of the request for the detection of renamed and copied files,
+// it writes a validation error if they are invalid
+func getRenameDetection(ctx *context.APIContext) (gitdiff.RenameDetection, bool) {
+	renameDetection := gitdiff.RenameDetection{
+		RenameThreshold:  ctx.FormInt("rename_threshold"),
+		CopyThreshold:    ctx.FormInt("copy_threshold"),
+		FindCopiesHarder: ctx.FormBool("find_copies_harder"),
+	}
+	if err := renameDetection.Validate(); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return renameDetection, false
+	}
+	return renameDetection, true
+}
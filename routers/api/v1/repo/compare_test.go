@@ -0,0 +1,36 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCompareFormat(t *testing.T) {
+	assert.Equal(t, compareFormatJSON, parseCompareFormat(""))
+	assert.Equal(t, compareFormatJSON, parseCompareFormat("*/*"))
+	assert.Equal(t, compareFormatJSON, parseCompareFormat("application/json, text/x-diff"))
+	assert.Equal(t, compareFormatDiff, parseCompareFormat("text/x-diff"))
+	assert.Equal(t, compareFormatDiff, parseCompareFormat("text/html;q=0.9, Text/X-Diff;q=0.8"))
+	assert.Equal(t, compareFormatPatch, parseCompareFormat("text/x-patch"))
+	assert.Equal(t, compareFormatPatch, parseCompareFormat("application/mbox"))
+}
+
+func TestIsSameForkNetwork(t *testing.T) {
+	base := &repo_model.Repository{ID: 1}
+	fork := &repo_model.Repository{ID: 2, IsFork: true, ForkID: 1}
+	sibling := &repo_model.Repository{ID: 3, IsFork: true, ForkID: 1}
+	other := &repo_model.Repository{ID: 4}
+
+	assert.True(t, isSameForkNetwork(base, base))
+	assert.True(t, isSameForkNetwork(base, fork))
+	assert.True(t, isSameForkNetwork(fork, base))
+	assert.True(t, isSameForkNetwork(fork, sibling))
+	assert.False(t, isSameForkNetwork(base, other))
+	assert.False(t, isSameForkNetwork(fork, other))
+}
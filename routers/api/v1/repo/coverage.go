@@ -0,0 +1,176 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+	coverage_service "code.gitea.io/gitea/services/coverage"
+)
+
+// UploadCoverageReport records the test coverage of a commit
+func UploadCoverageReport(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/coverage repository repoUploadCoverageReport
+	// ---
+	// summary: Upload the test coverage of a commit, shown by the coverage badge of the branch
+	// description: Either the percentage of covered lines or a lcov or Cobertura report has to be given. The coverage
+	//   of the lines of a report is shown in the diffs of pull requests and used to compute their diff coverage.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateCoverageReportOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/CoverageReport"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateCoverageReportOption)
+	report, err := coverage_service.UploadReport(ctx, ctx.Repo.Repository, ctx.Doer, &coverage_service.UploadOptions{
+		CommitSHA:  form.CommitSHA,
+		Branch:     form.Branch,
+		Coverage:   form.Coverage,
+		Format:     form.Format,
+		Report:     []byte(form.Report),
+		PathPrefix: form.PathPrefix,
+	})
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "UploadReport", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UploadReport", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, convert.ToCoverageReport(report))
+}
+
+// GetLatestCoverageReport gets the last test coverage uploaded for a branch
+func GetLatestCoverageReport(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/coverage repository repoGetLatestCoverageReport
+	// ---
+	// summary: Get the last test coverage uploaded for a branch
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: query
+	//   description: branch, defaults to the default branch of the repository
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CoverageReport"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	branch := ctx.FormString("branch")
+	if branch == "" {
+		branch = ctx.Repo.Repository.DefaultBranch
+	}
+	report, err := git_model.GetLatestCoverageReport(ctx, ctx.Repo.Repository.ID, branch)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLatestCoverageReport", err)
+		return
+	} else if report == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToCoverageReport(report))
+}
+
+// GetPullRequestDiffCoverage gets the test coverage of the lines added by a pull request
+func GetPullRequestDiffCoverage(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/pulls/{index}/coverage repository repoGetPullRequestDiffCoverage
+	// ---
+	// summary: Get the test coverage of the lines added by a pull request
+	// description: The coverage is read from the last coverage report with line coverage uploaded for the head commit
+	//   of the pull request.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PullRequestDiffCoverage"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return
+	}
+
+	diffCoverage, err := coverage_service.GetPullRequestDiffCoverage(ctx, pr)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPullRequestDiffCoverage", err)
+		return
+	} else if diffCoverage == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.PullRequestDiffCoverage{
+		Report:            convert.ToCoverageReport(diffCoverage.Report),
+		DiffCoverage:      diffCoverage.Percentage(),
+		CoveredLines:      diffCoverage.CoveredLines,
+		InstrumentedLines: diffCoverage.InstrumentedLines,
+	})
+}
@@ -0,0 +1,1051 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	discussion_model "code.gitea.io/gitea/models/discussion"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/context"
+	discussion_indexer "code.gitea.io/gitea/modules/indexer/discussions"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+	discussion_service "code.gitea.io/gitea/services/discussion"
+)
+
+// ListDiscussionCategories lists the discussion categories of a repository
+func ListDiscussionCategories(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/discussions/categories repository repoListDiscussionCategories
+	// ---
+	// summary: List the discussion categories of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DiscussionCategoryList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	categories, err := discussion_model.GetCategoriesByRepoID(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	apiCategories := make([]*api.DiscussionCategory, len(categories))
+	for i, c := range categories {
+		apiCategories[i] = convert.ToDiscussionCategory(c)
+	}
+	ctx.JSON(http.StatusOK, &apiCategories)
+}
+
+// CreateDiscussionCategory adds a discussion category to a repository
+func CreateDiscussionCategory(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/discussions/categories repository repoCreateDiscussionCategory
+	// ---
+	// summary: Add a discussion category to a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateDiscussionCategoryOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/DiscussionCategory"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateDiscussionCategoryOption)
+	c := &discussion_model.Category{
+		RepoID:      ctx.Repo.Repository.ID,
+		Name:        form.Name,
+		Description: form.Description,
+		Kind:        discussion_model.CategoryKind(form.Kind),
+	}
+	if c.Kind == "" {
+		c.Kind = discussion_model.CategoryKindGeneral
+	}
+	if err := discussion_model.CreateCategory(ctx, c); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToDiscussionCategory(c))
+}
+
+// EditDiscussionCategory changes a discussion category
+func EditDiscussionCategory(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/discussions/categories/{id} repository repoEditDiscussionCategory
+	// ---
+	// summary: Edit a discussion category
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the category
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditDiscussionCategoryOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DiscussionCategory"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditDiscussionCategoryOption)
+	c := getDiscussionCategory(ctx, ctx.ParamsInt64(":id"))
+	if ctx.Written() {
+		return
+	}
+	if form.Name != nil {
+		c.Name = *form.Name
+	}
+	if form.Description != nil {
+		c.Description = *form.Description
+	}
+	if form.Kind != nil {
+		c.Kind = discussion_model.CategoryKind(*form.Kind)
+	}
+	if err := discussion_model.UpdateCategory(ctx, c); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToDiscussionCategory(c))
+}
+
+// DeleteDiscussionCategory removes an empty discussion category
+func DeleteDiscussionCategory(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/discussions/categories/{id} repository repoDeleteDiscussionCategory
+	// ---
+	// summary: Delete a discussion category, it must not contain any discussions
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the category
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	c := getDiscussionCategory(ctx, ctx.ParamsInt64(":id"))
+	if ctx.Written() {
+		return
+	}
+	if err := discussion_model.DeleteCategory(ctx, c); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListDiscussions lists and searches the discussions of a repository
+func ListDiscussions(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/discussions repository repoListDiscussions
+	// ---
+	// summary: List and search the discussions of a repository, most recently active first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: category
+	//   in: query
+	//   description: only discussions of the category with this id
+	//   type: integer
+	//   format: int64
+	// - name: q
+	//   in: query
+	//   description: search the title, body and replies of the discussions
+	//   type: string
+	// - name: answered
+	//   in: query
+	//   description: only answered or unanswered discussions
+	//   type: boolean
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DiscussionList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	var discussionIDs []int64
+	if keyword := ctx.FormTrim("q"); len(keyword) > 0 {
+		var err error
+		discussionIDs, _, err = discussion_indexer.SearchDiscussionsByKeyword(ctx, []int64{ctx.Repo.Repository.ID}, keyword, 50, 0)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "SearchDiscussionsByKeyword", err)
+			return
+		}
+		if len(discussionIDs) == 0 {
+			ctx.SetTotalCountHeader(0)
+			ctx.JSON(http.StatusOK, []*api.Discussion{})
+			return
+		}
+	}
+
+	discussions, count, err := discussion_model.FindDiscussions(ctx, discussion_model.FindDiscussionsOptions{
+		ListOptions: utils.GetListOptions(ctx),
+		RepoID:      ctx.Repo.Repository.ID,
+		CategoryID:  ctx.FormInt64("category"),
+		IDs:         discussionIDs,
+		IsAnswered:  ctx.FormOptionalBool("answered"),
+	})
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	apiDiscussions := make([]*api.Discussion, len(discussions))
+	for i, d := range discussions {
+		d.Repo = ctx.Repo.Repository
+		if err := d.LoadAttributes(ctx); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		apiDiscussions[i] = convert.ToDiscussion(ctx, d)
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiDiscussions)
+}
+
+// CreateDiscussion starts a discussion
+func CreateDiscussion(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/discussions repository repoCreateDiscussion
+	// ---
+	// summary: Start a discussion, announcements can only be started by writers
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateDiscussionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Discussion"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateDiscussionOption)
+	category := getDiscussionCategory(ctx, form.CategoryID)
+	if ctx.Written() {
+		return
+	}
+	if category.Kind == discussion_model.CategoryKindAnnouncement && !canWriteDiscussions(ctx) {
+		ctx.Error(http.StatusForbidden, "", "only writers can start announcements")
+		return
+	}
+
+	d, err := discussion_service.NewDiscussion(ctx, ctx.Doer, ctx.Repo.Repository, category, form.Title, form.Body)
+	if err != nil {
+		if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToDiscussion(ctx, d))
+}
+
+// GetDiscussion returns a discussion
+func GetDiscussion(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/discussions/{index} repository repoGetDiscussion
+	// ---
+	// summary: Get a discussion
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the discussion
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Discussion"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	d := getDiscussion(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToDiscussion(ctx, d))
+}
+
+// EditDiscussion changes a discussion
+func EditDiscussion(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/discussions/{index} repository repoEditDiscussion
+	// ---
+	// summary: Edit a discussion, only writers can lock it or move it to an announcement category
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the discussion
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditDiscussionOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Discussion"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.EditDiscussionOption)
+	d := getDiscussion(ctx)
+	if ctx.Written() {
+		return
+	}
+	canWrite := canWriteDiscussions(ctx)
+	if d.PosterID != ctx.Doer.ID && !canWrite {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+
+	if form.Title != nil || form.Body != nil || form.CategoryID != nil {
+		title, content, category := d.Title, d.Content, d.Category
+		if form.Title != nil {
+			title = *form.Title
+		}
+		if form.Body != nil {
+			content = *form.Body
+		}
+		if form.CategoryID != nil {
+			category = getDiscussionCategory(ctx, *form.CategoryID)
+			if ctx.Written() {
+				return
+			}
+			if category.Kind == discussion_model.CategoryKindAnnouncement && !canWrite {
+				ctx.Error(http.StatusForbidden, "", "only writers can start announcements")
+				return
+			}
+		}
+		if err := discussion_service.EditDiscussion(ctx, ctx.Doer, d, title, content, category); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+	}
+
+	if form.IsLocked != nil {
+		if !canWrite {
+			ctx.Error(http.StatusForbidden, "", "only writers can lock discussions")
+			return
+		}
+		if err := discussion_service.SetDiscussionLocked(ctx, ctx.Doer, d, *form.IsLocked); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToDiscussion(ctx, d))
+}
+
+// DeleteDiscussion removes a discussion
+func DeleteDiscussion(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/discussions/{index} repository repoDeleteDiscussion
+	// ---
+	// summary: Delete a discussion and its replies
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the discussion
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	d := getDiscussion(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := discussion_service.DeleteDiscussion(ctx, ctx.Doer, d); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// SetDiscussionAnswer accepts a reply as answer of a question
+func SetDiscussionAnswer(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/discussions/{index}/answer repository repoSetDiscussionAnswer
+	// ---
+	// summary: Accept a reply as answer of a question
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the discussion
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetDiscussionAnswerOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Discussion"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetDiscussionAnswerOption)
+	setDiscussionAnswer(ctx, form.CommentID)
+}
+
+// DeleteDiscussionAnswer removes the answer of a question
+func DeleteDiscussionAnswer(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/discussions/{index}/answer repository repoDeleteDiscussionAnswer
+	// ---
+	// summary: Remove the answer of a question
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the discussion
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Discussion"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	setDiscussionAnswer(ctx, 0)
+}
+
+func setDiscussionAnswer(ctx *context.APIContext, commentID int64) {
+	d := getDiscussion(ctx)
+	if ctx.Written() {
+		return
+	}
+	// the author of a question decides which reply answers it
+	if d.PosterID != ctx.Doer.ID && !canWriteDiscussions(ctx) {
+		ctx.Status(http.StatusForbidden)
+		return
+	}
+	if err := discussion_service.SetDiscussionAnswer(ctx, ctx.Doer, d, commentID); err != nil {
+		if discussion_model.IsErrCommentNotExist(err) || errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToDiscussion(ctx, d))
+}
+
+// ConvertDiscussionToIssue opens an issue from a discussion
+func ConvertDiscussionToIssue(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/discussions/{index}/convert repository repoConvertDiscussionToIssue
+	// ---
+	// summary: Open an issue with the content and replies of a discussion, the discussion is deleted
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the discussion
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Issue"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if !ctx.Repo.CanWrite(unit.TypeIssues) {
+		ctx.Error(http.StatusForbidden, "", "must have write access to issues")
+		return
+	}
+	d := getDiscussion(ctx)
+	if ctx.Written() {
+		return
+	}
+	issue, err := discussion_service.ConvertDiscussionToIssue(ctx, ctx.Doer, d)
+	if err != nil {
+		if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToAPIIssue(ctx, issue))
+}
+
+// ListDiscussionComments lists the replies to a discussion
+func ListDiscussionComments(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/discussions/{index}/comments repository repoListDiscussionComments
+	// ---
+	// summary: List the replies to a discussion, oldest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the discussion
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DiscussionCommentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	d := getDiscussion(ctx)
+	if ctx.Written() {
+		return
+	}
+	comments, count, err := discussion_model.FindComments(ctx, d.ID, utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	apiComments := make([]*api.DiscussionComment, len(comments))
+	for i, c := range comments {
+		if err := c.LoadPoster(ctx); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		apiComments[i] = convert.ToDiscussionComment(ctx, d, c)
+	}
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, &apiComments)
+}
+
+// CreateDiscussionComment replies to a discussion
+func CreateDiscussionComment(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/discussions/{index}/comments repository repoCreateDiscussionComment
+	// ---
+	// summary: Reply to a discussion, locked discussions and announcements can only be replied to by writers
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the discussion
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateDiscussionCommentOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/DiscussionComment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.CreateDiscussionCommentOption)
+	d := getDiscussion(ctx)
+	if ctx.Written() {
+		return
+	}
+	if (d.IsLocked || d.Category.Kind == discussion_model.CategoryKindAnnouncement) && !canWriteDiscussions(ctx) {
+		ctx.Error(http.StatusForbidden, "", "replies are limited to writers")
+		return
+	}
+
+	c, err := discussion_service.CreateComment(ctx, ctx.Doer, d, form.Body)
+	if err != nil {
+		if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToDiscussionComment(ctx, d, c))
+}
+
+// EditDiscussionComment changes a reply to a discussion
+func EditDiscussionComment(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/discussions/{index}/comments/{id} repository repoEditDiscussionComment
+	// ---
+	// summary: Edit a reply to a discussion
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the discussion
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditDiscussionCommentOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DiscussionComment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	form := web.GetForm(ctx).(*api.EditDiscussionCommentOption)
+	d, c := getDiscussionComment(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := discussion_service.UpdateComment(ctx, ctx.Doer, d, c, form.Body); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToDiscussionComment(ctx, d, c))
+}
+
+// DeleteDiscussionComment removes a reply to a discussion
+func DeleteDiscussionComment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/discussions/{index}/comments/{id} repository repoDeleteDiscussionComment
+	// ---
+	// summary: Delete a reply to a discussion
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the discussion
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	d, c := getDiscussionComment(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := discussion_service.DeleteComment(ctx, ctx.Doer, d, c); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func canWriteDiscussions(ctx *context.APIContext) bool {
+	return ctx.Repo.CanWrite(unit.TypeDiscussions) || ctx.Repo.IsAdmin() || ctx.IsUserSiteAdmin()
+}
+
+func getDiscussionCategory(ctx *context.APIContext, id int64) *discussion_model.Category {
+	c, err := discussion_model.GetCategoryByID(ctx, ctx.Repo.Repository.ID, id)
+	if err != nil {
+		if discussion_model.IsErrCategoryNotExist(err) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil
+	}
+	return c
+}
+
+// getDiscussion returns the discussion from the path with its attributes loaded
+func getDiscussion(ctx *context.APIContext) *discussion_model.Discussion {
+	d, err := discussion_model.GetDiscussionByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if discussion_model.IsErrDiscussionNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil
+	}
+	d.Repo = ctx.Repo.Repository
+	if err := d.LoadAttributes(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return nil
+	}
+	return d
+}
+
+// getDiscussionComment returns the discussion and comment from the path if the doer may change the comment
+func getDiscussionComment(ctx *context.APIContext) (*discussion_model.Discussion, *discussion_model.Comment) {
+	d := getDiscussion(ctx)
+	if ctx.Written() {
+		return nil, nil
+	}
+	c, err := discussion_model.GetCommentByID(ctx, d.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if discussion_model.IsErrCommentNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil, nil
+	}
+	if c.PosterID != ctx.Doer.ID && !canWriteDiscussions(ctx) {
+		ctx.Status(http.StatusForbidden)
+		return nil, nil
+	}
+	if err := c.LoadPoster(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return nil, nil
+	}
+	return d, c
+}
+
+// ConvertIssueToDiscussion moves an issue to a new discussion
+func ConvertIssueToDiscussion(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/{index}/convert-to-discussion issue issueConvertToDiscussion
+	// ---
+	// summary: Move an issue and its comments to a new discussion, the issue is closed and locked
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/ConvertIssueToDiscussionOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/Discussion"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.ConvertIssueToDiscussionOption)
+	if !ctx.Repo.CanRead(unit.TypeDiscussions) {
+		ctx.Error(http.StatusForbidden, "", "discussions are not enabled")
+		return
+	}
+	issue, err := issues_model.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	category := getDiscussionCategory(ctx, form.CategoryID)
+	if ctx.Written() {
+		return
+	}
+
+	d, err := discussion_service.ConvertIssueToDiscussion(ctx, ctx.Doer, issue, category)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	if err := d.LoadPoster(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToDiscussion(ctx, d))
+}
@@ -0,0 +1,162 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// ListDownloadLinks lists the download links of the repository
+func ListDownloadLinks(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/download_links repository repoListDownloadLinks
+	// ---
+	// summary: List the download links of the repository, including the revoked and expired ones
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DownloadLinkList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	links, err := repo_model.GetDownloadLinksByRepoID(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.DownloadLink, 0, len(links))
+	for _, link := range links {
+		if err := link.LoadAttributes(ctx); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		result = append(result, convert.ToDownloadLink(ctx, link, ctx.Doer))
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreateDownloadLink creates a download link of an attachment or an archive of the repository
+func CreateDownloadLink(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/download_links repository repoCreateDownloadLink
+	// ---
+	// summary: Create a link to download a release asset, attachment or archive of the repository without authentication
+	// description: The URL of the link is only returned in this response. Anyone with the URL can download until the link expires or is revoked.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateDownloadLinkOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/DownloadLink"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateDownloadLinkOption)
+	opts := repo_service.CreateDownloadLinkOptions{
+		AttachmentID: form.AttachmentID,
+		Archive:      form.Archive,
+	}
+	if form.Expires != nil {
+		opts.Expires = *form.Expires
+	}
+
+	link, err := repo_service.CreateDownloadLink(ctx, ctx.Doer, ctx.Repo.Repository, ctx.Repo.GitRepo, opts)
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "CreateDownloadLink", err)
+		} else if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "CreateDownloadLink", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToDownloadLink(ctx, link, ctx.Doer))
+}
+
+// RevokeDownloadLink revokes a download link of the repository
+func RevokeDownloadLink(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/download_links/{id} repository repoRevokeDownloadLink
+	// ---
+	// summary: Revoke a download link of the repository
+	// description: The revoked link is kept in the list of download links.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the download link
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if _, err := repo_service.RevokeDownloadLink(ctx, ctx.Doer, ctx.Repo.Repository, ctx.ParamsInt64(":id")); err != nil {
+		if repo_model.IsErrDownloadLinkNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
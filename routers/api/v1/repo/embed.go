@@ -0,0 +1,172 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	embed_service "code.gitea.io/gitea/services/embed"
+)
+
+func toEmbedToken(repo *repo_model.Repository, t *repo_model.EmbedToken) *api.EmbedToken {
+	token := t.Token()
+	widgets := make([]string, 0, len(t.Widgets))
+	urls := make(map[string]string, len(t.Widgets))
+	for _, widget := range t.Widgets {
+		widgets = append(widgets, string(widget))
+		urls[string(widget)] = embed_service.URL(repo, widget, token)
+	}
+	return &api.EmbedToken{
+		ID:             t.ID,
+		Name:           t.Name,
+		AllowedOrigins: t.AllowedOrigins,
+		Widgets:        widgets,
+		Token:          token,
+		URLs:           urls,
+		Created:        t.CreatedUnix.AsTime(),
+	}
+}
+
+// ListEmbedTokens lists the tokens which allow other sites to embed the widgets of a repository
+func ListEmbedTokens(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/embed_tokens repository repoListEmbedTokens
+	// ---
+	// summary: List the tokens which allow other sites to embed the widgets of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/EmbedTokenList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	tokens, err := repo_model.FindEmbedTokens(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindEmbedTokens", err)
+		return
+	}
+	apiTokens := make([]*api.EmbedToken, 0, len(tokens))
+	for _, t := range tokens {
+		apiTokens = append(apiTokens, toEmbedToken(ctx.Repo.Repository, t))
+	}
+	ctx.JSON(http.StatusOK, apiTokens)
+}
+
+// CreateEmbedToken creates a token which allows other sites to embed the widgets of a repository
+func CreateEmbedToken(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/embed_tokens repository repoCreateEmbedToken
+	// ---
+	// summary: Create a token which allows other sites to embed the widgets of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateEmbedTokenOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/EmbedToken"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateEmbedTokenOption)
+	t := &repo_model.EmbedToken{
+		RepoID:         ctx.Repo.Repository.ID,
+		Name:           form.Name,
+		AllowedOrigins: form.AllowedOrigins,
+		Widgets:        make([]repo_model.EmbedWidget, 0, len(form.Widgets)),
+		CreatorID:      ctx.Doer.ID,
+	}
+	for _, widget := range form.Widgets {
+		t.Widgets = append(t.Widgets, repo_model.EmbedWidget(widget))
+	}
+	if err := repo_model.CreateEmbedToken(ctx, t); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "CreateEmbedToken", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateEmbedToken", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, toEmbedToken(ctx.Repo.Repository, t))
+}
+
+// DeleteEmbedToken revokes an embed token of a repository
+func DeleteEmbedToken(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/embed_tokens/{id} repository repoDeleteEmbedToken
+	// ---
+	// summary: Revoke a token which allows other sites to embed the widgets of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the embed token
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := repo_model.DeleteEmbedToken(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		if repo_model.IsErrEmbedTokenNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteEmbedToken", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
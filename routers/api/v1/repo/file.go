@@ -201,7 +201,7 @@ func GetRawFileOrLFS(ctx *context.APIContext) {
 
 	if setting.LFS.ServeDirect {
 		// If we have a signed url (S3, object storage), redirect to this directly.
-		u, err := storage.LFS.URL(pointer.RelativePath(), blob.Name())
+		u, err := storage.LFSOfRegion(ctx.Repo.Repository.StorageRegion()).URL(pointer.RelativePath(), blob.Name())
 		if u != nil && err == nil {
 			ctx.Redirect(u.String())
 			return
@@ -219,6 +219,11 @@ func GetRawFileOrLFS(ctx *context.APIContext) {
 }
 
 func getBlobForEntry(ctx *context.APIContext) (blob *git.Blob, entry *git.TreeEntry, lastModified time.Time) {
+	if !ctx.Repo.Permission.CanReadCodePath(ctx.Repo.TreePath) {
+		ctx.NotFound()
+		return
+	}
+
 	entry, err := ctx.Repo.Commit.GetTreeEntryByPath(ctx.Repo.TreePath)
 	if err != nil {
 		if git.IsErrNotExist(err) {
@@ -773,11 +778,38 @@ func GetContents(ctx *context.APIContext) {
 			return
 		}
 		ctx.Error(http.StatusInternalServerError, "GetContentsOrList", err)
+	} else if fileList, ok := filterContentsByCodePaths(ctx.Repo, treePath, fileList); !ok {
+		ctx.NotFound("CanReadCodePath")
 	} else {
 		ctx.JSON(http.StatusOK, fileList)
 	}
 }
 
+// filterContentsByCodePaths removes the contents the user may not read, false is returned if the user
+// may not read the file or list the directory at all
+func filterContentsByCodePaths(r *context.Repository, treePath string, fileList interface{}) (interface{}, bool) {
+	if !r.Permission.IsCodePathRestricted() {
+		return fileList, true
+	}
+	switch contents := fileList.(type) {
+	case *api.ContentsResponse:
+		return contents, r.Permission.CanReadCodePath(contents.Path)
+	case []*api.ContentsResponse:
+		if !r.Permission.CanBrowseCodePath(treePath) {
+			return nil, false
+		}
+		filtered := make([]*api.ContentsResponse, 0, len(contents))
+		for _, content := range contents {
+			if r.Permission.CanReadCodePath(content.Path) ||
+				(content.Type == string(files_service.ContentTypeDir) && r.Permission.CanBrowseCodePath(content.Path)) {
+				filtered = append(filtered, content)
+			}
+		}
+		return filtered, true
+	}
+	return fileList, true
+}
+
 // GetContentsList Get the metadata of all the entries of the root dir
 func GetContentsList(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/contents repository repoGetContentsList
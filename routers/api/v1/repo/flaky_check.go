@@ -0,0 +1,425 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListFlakyChecks lists the flaky check rules of a repository
+func ListFlakyChecks(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/flaky_checks repository repoListFlakyChecks
+	// ---
+	// summary: List the flaky check rules of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FlakyCheckList"
+
+	rules, err := git_model.GetFlakyCheckRules(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFlakyCheckRules", err)
+		return
+	}
+
+	apiRules := make([]*api.FlakyCheck, 0, len(rules))
+	for _, rule := range rules {
+		apiRules = append(apiRules, convert.ToFlakyCheck(rule))
+	}
+	ctx.JSON(http.StatusOK, apiRules)
+}
+
+// GetFlakyCheck gets a flaky check rule of a repository
+func GetFlakyCheck(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/flaky_checks/{id} repository repoGetFlakyCheck
+	// ---
+	// summary: Get a flaky check rule of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FlakyCheck"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	rule := getFlakyCheckRule(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToFlakyCheck(rule))
+}
+
+// CreateFlakyCheck creates a flaky check rule for a repository
+func CreateFlakyCheck(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/flaky_checks repository repoCreateFlakyCheck
+	// ---
+	// summary: Mark the commit status contexts matching a pattern as flaky-tolerant
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateFlakyCheckOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/FlakyCheck"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateFlakyCheckOption)
+	rule := &git_model.FlakyCheckRule{
+		RepoID:         ctx.Repo.Repository.ID,
+		ContextPattern: form.ContextPattern,
+		MaxRetries:     form.MaxRetries,
+		RequiredPasses: form.RequiredPasses,
+		Attempts:       form.Attempts,
+	}
+	if err := git_model.CreateFlakyCheckRule(ctx, rule); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateFlakyCheckRule", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToFlakyCheck(rule))
+}
+
+// EditFlakyCheck edits a flaky check rule of a repository
+func EditFlakyCheck(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/flaky_checks/{id} repository repoEditFlakyCheck
+	// ---
+	// summary: Edit a flaky check rule of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditFlakyCheckOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FlakyCheck"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditFlakyCheckOption)
+	rule := getFlakyCheckRule(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.ContextPattern != nil {
+		rule.ContextPattern = *form.ContextPattern
+	}
+	if form.MaxRetries != nil {
+		rule.MaxRetries = *form.MaxRetries
+	}
+	if form.RequiredPasses != nil {
+		rule.RequiredPasses = *form.RequiredPasses
+	}
+	if form.Attempts != nil {
+		rule.Attempts = *form.Attempts
+	}
+	if err := git_model.UpdateFlakyCheckRule(ctx, rule); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UpdateFlakyCheckRule", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToFlakyCheck(rule))
+}
+
+// DeleteFlakyCheck deletes a flaky check rule of a repository
+func DeleteFlakyCheck(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/flaky_checks/{id} repository repoDeleteFlakyCheck
+	// ---
+	// summary: Delete a flaky check rule of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := git_model.DeleteFlakyCheckRule(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		if git_model.IsErrFlakyCheckRuleNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteFlakyCheckRule", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+func getFlakyCheckRule(ctx *context.APIContext) *git_model.FlakyCheckRule {
+	rule, err := git_model.GetFlakyCheckRuleByID(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if git_model.IsErrFlakyCheckRuleNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetFlakyCheckRuleByID", err)
+		}
+		return nil
+	}
+	return rule
+}
+
+// ListFlakeRates lists how flaky the flaky-tolerant commit status contexts of a repository are
+func ListFlakeRates(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/flaky_checks/rates repository repoListFlakeRates
+	// ---
+	// summary: List the flake rates of the flaky-tolerant commit status contexts of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: only count the attempts since this time, the last 30 days by default
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/FlakeRateList"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	since := time.Now().AddDate(0, 0, -30)
+	if ctx.FormString("since") != "" {
+		var err error
+		if since, err = time.Parse(time.RFC3339, ctx.FormString("since")); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return
+		}
+	}
+
+	rates, err := git_model.GetFlakeRates(ctx, ctx.Repo.Repository.ID, timeutil.TimeStamp(since.Unix()))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFlakeRates", err)
+		return
+	}
+
+	apiRates := make([]*api.FlakeRate, 0, len(rates))
+	for _, rate := range rates {
+		apiRates = append(apiRates, convert.ToFlakeRate(rate))
+	}
+	ctx.JSON(http.StatusOK, apiRates)
+}
+
+// ListCommitStatusAttempts lists the attempts of the flaky-tolerant commit status contexts on a commit
+func ListCommitStatusAttempts(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/statuses/{sha}/attempts repository repoListStatusAttempts
+	// ---
+	// summary: List the attempts of the flaky-tolerant commit status contexts on a commit
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: sha of the commit
+	//   type: string
+	//   required: true
+	// - name: context
+	//   in: query
+	//   description: only list the attempts of this context
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CommitStatusAttemptList"
+
+	var attempts []*git_model.CommitStatusAttempt
+	var err error
+	if statusContext := ctx.FormTrim("context"); statusContext != "" {
+		attempts, err = git_model.GetCommitStatusAttempts(ctx, ctx.Repo.Repository.ID, ctx.Params("sha"), statusContext)
+	} else {
+		attempts, err = git_model.GetCommitStatusAttemptsBySHA(ctx, ctx.Repo.Repository.ID, ctx.Params("sha"))
+	}
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetCommitStatusAttempts", err)
+		return
+	}
+
+	apiAttempts := make([]*api.CommitStatusAttempt, 0, len(attempts))
+	for _, attempt := range attempts {
+		apiAttempts = append(apiAttempts, convert.ToCommitStatusAttempt(ctx, attempt))
+	}
+	ctx.JSON(http.StatusOK, apiAttempts)
+}
+
+// CreateCommitStatusAttempt records a retry of a flaky-tolerant commit status context on a commit
+func CreateCommitStatusAttempt(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/statuses/{sha}/attempts repository repoCreateStatusAttempt
+	// ---
+	// summary: Record a retry of a flaky-tolerant commit status context, the next status of the context finishes it
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: sha of the commit
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateCommitStatusAttemptOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/CommitStatusAttempt"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateCommitStatusAttemptOption)
+	rules, err := git_model.GetFlakyCheckRules(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetFlakyCheckRules", err)
+		return
+	}
+	if git_model.MatchFlakyCheckRule(rules, form.Context) == nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the context is not flaky-tolerant")
+		return
+	}
+
+	attempt := &git_model.CommitStatusAttempt{
+		RepoID:  ctx.Repo.Repository.ID,
+		SHA:     ctx.Params("sha"),
+		Context: form.Context,
+		Reason:  git_model.CommitStatusAttemptReasonManual,
+		DoerID:  ctx.Doer.ID,
+	}
+	if err := git_model.StartCommitStatusAttempt(ctx, attempt); err != nil {
+		ctx.Error(http.StatusInternalServerError, "StartCommitStatusAttempt", err)
+		return
+	}
+	if attempt.ID == 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "an attempt of the context is still running")
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToCommitStatusAttempt(ctx, attempt))
+}
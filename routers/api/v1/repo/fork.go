@@ -14,7 +14,9 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/convert"
@@ -152,3 +154,132 @@ func CreateFork(ctx *context.APIContext) {
 	// TODO change back to 201
 	ctx.JSON(http.StatusAccepted, convert.ToRepo(ctx, fork, perm.AccessModeOwner))
 }
+
+// ListForkNetwork lists the downstream forks of a repository with their ahead/behind counts
+func ListForkNetwork(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/forks/network repository repoListForkNetwork
+	// ---
+	// summary: List the forks of a repository and their forks, most recently active first
+	// description: The ahead and behind counts compare the default branch of each fork with the default branch of its parent.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ForkNetwork"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	entries, err := repo_service.GetForkNetwork(ctx, ctx.Doer, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetForkNetwork", err)
+		return
+	}
+	count := len(entries)
+
+	listOpts := utils.GetListOptions(ctx)
+	entries = util.PaginateSlice(entries, listOpts.Page, listOpts.PageSize).([]*repo_service.ForkNetworkEntry)
+
+	result := make([]*api.ForkNetworkEntry, 0, len(entries))
+	for _, e := range entries {
+		access, err := access_model.AccessLevel(ctx, ctx.Doer, e.Repo)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
+		}
+		apiEntry := &api.ForkNetworkEntry{
+			Repository:     convert.ToRepo(ctx, e.Repo, access),
+			ParentFullName: e.Parent.FullName(),
+			Depth:          e.Depth,
+		}
+		if e.Divergence != nil {
+			lastCommit := e.Divergence.LastCommitUnix.AsTime()
+			apiEntry.HasDivergence = true
+			apiEntry.AheadBy = e.Divergence.Ahead
+			apiEntry.BehindBy = e.Divergence.Behind
+			apiEntry.LastCommitAt = &lastCommit
+		}
+		result = append(result, apiEntry)
+	}
+
+	ctx.SetTotalCountHeader(int64(count))
+	ctx.JSON(http.StatusOK, result)
+}
+
+// FindCommitInForks lists the downstream forks of a repository containing a commit
+func FindCommitInForks(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/forks/commits/{sha} repository repoFindCommitInForks
+	// ---
+	// summary: List the forks of a repository and their forks which contain a commit
+	// description: The commit does not need to exist in the repository itself.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: sha
+	//   in: path
+	//   description: full SHA of the commit
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ForkCommitMatchList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	// forks always have the object format of the repository they were forked from
+	sha := ctx.Params(":sha")
+	if len(sha) != ctx.Repo.Repository.ObjectFormat().FullLength() || !git.IsValidSHAPattern(sha) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("%q is not a full commit SHA", sha))
+		return
+	}
+
+	matches, err := repo_service.FindCommitInForks(ctx, ctx.Doer, ctx.Repo.Repository, sha)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindCommitInForks", err)
+		return
+	}
+
+	result := make([]*api.ForkCommitMatch, 0, len(matches))
+	for _, m := range matches {
+		access, err := access_model.AccessLevel(ctx, ctx.Doer, m.Repo)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
+			return
+		}
+		result = append(result, &api.ForkCommitMatch{
+			Repository: convert.ToRepo(ctx, m.Repo, access),
+			Branches:   m.Branches,
+		})
+	}
+	ctx.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,234 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// MergeUpstream updates a branch of a fork from its upstream repository
+func MergeUpstream(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/merge-upstream repository repoMergeUpstream
+	// ---
+	// summary: Update a branch of a fork from its upstream repository
+	// description: The branch is fast-forwarded if possible, otherwise the upstream branch is merged into it unless ff_only is set.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/MergeUpstreamOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MergeUpstreamResult"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/MergeUpstreamConflict"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.MergeUpstreamOption)
+	if !ctx.Repo.Repository.IsFork {
+		ctx.Error(http.StatusUnprocessableEntity, "", "repository is not a fork")
+		return
+	}
+
+	mergeType, commitID, err := pull_service.MergeUpstream(ctx, ctx.Doer, ctx.Repo.Repository, form.Branch, form.UpstreamBranch, !form.FfOnly)
+	if err != nil {
+		switch {
+		case models.IsErrBranchDoesNotExist(err) || repo_model.IsErrRepoNotExist(err):
+			ctx.NotFound(err)
+		case errors.Is(err, pull_service.ErrUpstreamDiverged):
+			ctx.Error(http.StatusConflict, "", err.Error())
+		case pull_service.IsErrUpstreamConflicts(err):
+			ctx.JSON(http.StatusConflict, api.MergeUpstreamConflict{
+				Message:         "merging the upstream branch failed because of conflicts",
+				ConflictedFiles: err.(pull_service.ErrUpstreamConflicts).Files,
+			})
+		case git.IsErrPushOutOfDate(err):
+			ctx.Error(http.StatusConflict, "", "branch was updated while merging")
+		case git.IsErrPushRejected(err):
+			ctx.Error(http.StatusConflict, "", "push rejected: "+err.(*git.ErrPushRejected).Message)
+		default:
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, api.MergeUpstreamResult{
+		MergeType: string(mergeType),
+		CommitID:  commitID,
+	})
+}
+
+// ListForkSyncs lists the branches of a fork which are synced from upstream on a schedule
+func ListForkSyncs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/fork_syncs repository repoListForkSyncs
+	// ---
+	// summary: List the branches of a fork which are synced from its upstream repository on a schedule
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ForkSyncList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	syncs, err := repo_model.GetForkSyncsByRepoID(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.ForkSync, 0, len(syncs))
+	for _, s := range syncs {
+		result = append(result, convert.ToForkSync(s))
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreateForkSync schedules a branch of a fork to be synced from upstream
+func CreateForkSync(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/fork_syncs repository repoCreateForkSync
+	// ---
+	// summary: Schedule a branch of a fork to be synced from its upstream repository
+	// description: The branch is updated as the authenticated user. An existing schedule of the branch is replaced.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateForkSyncOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ForkSync"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateForkSyncOption)
+	repo := ctx.Repo.Repository
+	if !repo.IsFork {
+		ctx.Error(http.StatusUnprocessableEntity, "", "repository is not a fork")
+		return
+	}
+	if !git.IsBranchExist(ctx, repo.RepoPath(), form.Branch) {
+		ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("branch %q does not exist", form.Branch))
+		return
+	}
+
+	s := &repo_model.ForkSync{
+		RepoID:         repo.ID,
+		Branch:         form.Branch,
+		UpstreamBranch: form.UpstreamBranch,
+		AllowMerge:     form.AllowMerge,
+		DoerID:         ctx.Doer.ID,
+	}
+	if s.UpstreamBranch == "" {
+		s.UpstreamBranch = s.Branch
+	}
+	if err := repo_model.SaveForkSync(ctx, s); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToForkSync(s))
+}
+
+// DeleteForkSync stops syncing a branch of a fork from upstream
+func DeleteForkSync(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/fork_syncs/{branch} repository repoDeleteForkSync
+	// ---
+	// summary: Stop syncing a branch of a fork from its upstream repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: path
+	//   description: name of the branch
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := repo_model.DeleteForkSync(ctx, ctx.Repo.Repository.ID, ctx.Params("*")); err != nil {
+		if repo_model.IsErrForkSyncNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -263,6 +263,49 @@ func EditHook(ctx *context.APIContext) {
 	utils.EditRepoHook(ctx, form, hookID)
 }
 
+// RotateHookSecret rotates the secret of a hook of a repository
+func RotateHookSecret(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/hooks/{id}/secret/rotate repository repoRotateHookSecret
+	// ---
+	// summary: Rotate the secret of a hook in a repository
+	// description: The payloads are signed with both the new and the previous secret during the overlap.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: index of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RotateHookSecretOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookSecretRotation"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+	form := web.GetForm(ctx).(*api.RotateHookSecretOption)
+	hookID := ctx.ParamsInt64(":id")
+	utils.RotateRepoHookSecret(ctx, form, hookID)
+}
+
 // DeleteHook delete a hook of a repository
 func DeleteHook(ctx *context.APIContext) {
 	// swagger:operation DELETE /repos/{owner}/{repo}/hooks/{id} repository repoDeleteHook
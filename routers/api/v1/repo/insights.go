@@ -0,0 +1,119 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+	insights_service "code.gitea.io/gitea/services/insights"
+)
+
+// GetInsights returns the delivery metrics of a repository
+func GetInsights(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/insights repository repoGetInsights
+	// ---
+	// summary: Get the delivery metrics of a repository
+	// description: The metrics of the merged pull requests are computed in the background,
+	//   recently merged pull requests may be missing.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: since
+	//   in: query
+	//   description: Start of the time range, 30 days before its end if empty. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// - name: before
+	//   in: query
+	//   description: End of the time range, now if empty. This is a timestamp in RFC 3339 format
+	//   type: string
+	//   format: date-time
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Insights"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	insights := utils.GetInsights(ctx, 0, ctx.Repo.Repository.ID)
+	if ctx.Written() {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToInsights(insights))
+}
+
+// GetPathOwnership returns the top contributors and bus factors of the directories of a repository
+func GetPathOwnership(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/insights/ownership repository repoGetPathOwnership
+	// ---
+	// summary: Get the top contributors and bus factors of the directories of a repository
+	// description: The ownership is computed in the background from the last year of history of the
+	//   default branch, up to a depth of 3 directories. Recent commits may be missing.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: path
+	//   in: query
+	//   description: directory to get the ownership of, the root of the repository if empty
+	//   type: string
+	// - name: depth
+	//   in: query
+	//   description: depth of the subdirectories to include, relative to the path (default 1)
+	//   type: integer
+	// - name: unowned
+	//   in: query
+	//   description: only include directories none of whose main contributors is an active user
+	//   type: boolean
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PathOwnershipReport"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	depth := 1
+	if ctx.FormString("depth") != "" {
+		depth = ctx.FormInt("depth")
+	}
+
+	paths, status, err := insights_service.GetPathOwnership(ctx, ctx.Repo.Repository, ctx.FormTrim("path"), depth, ctx.FormBool("unowned"))
+	if err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPathOwnership", err)
+		}
+		return
+	}
+
+	ctx.JSON(http.StatusOK, convert.ToPathOwnershipReport(ctx, paths, status, ctx.Doer))
+}
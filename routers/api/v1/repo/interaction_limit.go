@@ -0,0 +1,131 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	moderation_model "code.gitea.io/gitea/models/moderation"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// GetInteractionLimit returns the interaction limit of a repository
+func GetInteractionLimit(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/interaction_limit repository repoGetInteractionLimit
+	// ---
+	// summary: Get the interaction limit of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/InteractionLimit"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	limit, err := moderation_model.GetInteractionLimit(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	} else if limit == nil || limit.IsExpired() {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToInteractionLimit(limit))
+}
+
+// EditInteractionLimit sets the interaction limit of a repository
+func EditInteractionLimit(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/interaction_limit repository repoEditInteractionLimit
+	// ---
+	// summary: Prevent new accounts without write access from opening issues, pull requests and commenting
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditInteractionLimitOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/InteractionLimit"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditInteractionLimitOption)
+
+	limit := &moderation_model.InteractionLimit{
+		RepoID:            ctx.Repo.Repository.ID,
+		MinAccountAgeDays: form.MinAccountAgeDays,
+		CreatedByID:       ctx.Doer.ID,
+	}
+	if form.DurationDays > 0 {
+		limit.ExpiresUnix = timeutil.TimeStampNow().Add(int64(form.DurationDays) * 24 * 60 * 60)
+	}
+	if err := moderation_model.SetInteractionLimit(ctx, limit); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToInteractionLimit(limit))
+}
+
+// DeleteInteractionLimit removes the interaction limit of a repository
+func DeleteInteractionLimit(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/interaction_limit repository repoDeleteInteractionLimit
+	// ---
+	// summary: Remove the interaction limit of a repository
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+
+	if err := moderation_model.DeleteInteractionLimit(ctx, ctx.Repo.Repository.ID); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -5,8 +5,10 @@
 package repo
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
@@ -29,6 +31,7 @@ import (
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/convert"
 	issue_service "code.gitea.io/gitea/services/issue"
+	spam_service "code.gitea.io/gitea/services/spam"
 )
 
 // SearchIssues searches for issues across the repositories that the user has access to
@@ -55,6 +58,15 @@ func SearchIssues(ctx *context.APIContext) {
 	//   in: query
 	//   description: search string
 	//   type: string
+	// - name: sort
+	//   in: query
+	//   description: sort the issues matching the search string by relevance instead of the default order
+	//   type: string
+	//   enum: [relevance]
+	// - name: highlight
+	//   in: query
+	//   description: include the snippets of the title, body and comments matching the search string, default is false
+	//   type: boolean
 	// - name: priority_repo_id
 	//   in: query
 	//   description: repository to prioritize in the results
@@ -193,6 +205,10 @@ func SearchIssues(ctx *context.APIContext) {
 	if strings.IndexByte(keyword, 0) >= 0 {
 		keyword = ""
 	}
+	sortByRelevance, ok := parseIssueSearchSort(ctx, keyword)
+	if !ok {
+		return
+	}
 	var issueIDs []int64
 	if len(keyword) > 0 && len(repoIDs) > 0 {
 		if issueIDs, err = issue_indexer.SearchIssuesByKeyword(ctx, repoIDs, keyword); err != nil {
@@ -274,10 +290,20 @@ func SearchIssues(ctx *context.APIContext) {
 			issuesOpt.ReviewedID = ctxUserID
 		}
 
+		listOptions := issuesOpt.ListOptions
+		if sortByRelevance {
+			// the indexer returns a limited number of issues, which are paginated after ranking them
+			issuesOpt.ListOptions = db.ListOptions{
+				Page: -1,
+			}
+		}
 		if issues, err = issues_model.Issues(ctx, issuesOpt); err != nil {
 			ctx.Error(http.StatusInternalServerError, "Issues", err)
 			return
 		}
+		if sortByRelevance {
+			issues = sortIssuesByRelevance(issues, issueIDs, listOptions)
+		}
 
 		issuesOpt.ListOptions = db.ListOptions{
 			Page: -1,
@@ -288,9 +314,15 @@ func SearchIssues(ctx *context.APIContext) {
 		}
 	}
 
+	apiIssues, err := toAPISearchedIssues(ctx, issues, keyword)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "HighlightIssues", err)
+		return
+	}
+
 	ctx.SetLinkHeader(int(filteredCount), limit)
 	ctx.SetTotalCountHeader(filteredCount)
-	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, issues))
+	ctx.JSON(http.StatusOK, apiIssues)
 }
 
 // ListIssues list the issues of a repository
@@ -324,6 +356,15 @@ func ListIssues(ctx *context.APIContext) {
 	//   in: query
 	//   description: search string
 	//   type: string
+	// - name: sort
+	//   in: query
+	//   description: sort the issues matching the search string by relevance instead of the default order
+	//   type: string
+	//   enum: [relevance]
+	// - name: highlight
+	//   in: query
+	//   description: include the snippets of the title, body and comments matching the search string, default is false
+	//   type: boolean
 	// - name: type
 	//   in: query
 	//   description: filter by type (issues / pulls) if set
@@ -391,6 +432,10 @@ func ListIssues(ctx *context.APIContext) {
 	if strings.IndexByte(keyword, 0) >= 0 {
 		keyword = ""
 	}
+	sortByRelevance, ok := parseIssueSearchSort(ctx, keyword)
+	if !ok {
+		return
+	}
 	var issueIDs []int64
 	var labelIDs []int64
 	if len(keyword) > 0 {
@@ -483,10 +528,19 @@ func ListIssues(ctx *context.APIContext) {
 			MentionedID:       mentionedByID,
 		}
 
+		if sortByRelevance {
+			// the indexer returns a limited number of issues, which are paginated after ranking them
+			issuesOpt.ListOptions = db.ListOptions{
+				Page: -1,
+			}
+		}
 		if issues, err = issues_model.Issues(ctx, issuesOpt); err != nil {
 			ctx.Error(http.StatusInternalServerError, "Issues", err)
 			return
 		}
+		if sortByRelevance {
+			issues = sortIssuesByRelevance(issues, issueIDs, listOptions)
+		}
 
 		issuesOpt.ListOptions = db.ListOptions{
 			Page: -1,
@@ -497,9 +551,15 @@ func ListIssues(ctx *context.APIContext) {
 		}
 	}
 
+	apiIssues, err := toAPISearchedIssues(ctx, issues, keyword)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "HighlightIssues", err)
+		return
+	}
+
 	ctx.SetLinkHeader(int(filteredCount), listOptions.PageSize)
 	ctx.SetTotalCountHeader(filteredCount)
-	ctx.JSON(http.StatusOK, convert.ToAPIIssueList(ctx, issues))
+	ctx.JSON(http.StatusOK, apiIssues)
 }
 
 func getUserIDForFilter(ctx *context.APIContext, queryName string) int64 {
@@ -522,6 +582,62 @@ func getUserIDForFilter(ctx *context.APIContext, queryName string) int64 {
 	return user.ID
 }
 
+// parseIssueSearchSort returns if the issues matching the keyword are sorted by relevance
+func parseIssueSearchSort(ctx *context.APIContext, keyword string) (bool, bool) {
+	switch ctx.FormString("sort") {
+	case "":
+		return false, true
+	case "relevance":
+		return len(keyword) > 0, true
+	default:
+		ctx.Error(http.StatusUnprocessableEntity, "", "sort must be relevance")
+		return false, false
+	}
+}
+
+// sortIssuesByRelevance orders the issues like the ranked issue IDs returned by the indexer and returns the requested page
+func sortIssuesByRelevance(issues []*issues_model.Issue, rankedIDs []int64, listOptions db.ListOptions) []*issues_model.Issue {
+	ranks := make(map[int64]int, len(rankedIDs))
+	for i, id := range rankedIDs {
+		ranks[id] = i
+	}
+	sort.SliceStable(issues, func(i, j int) bool {
+		return ranks[issues[i].ID] < ranks[issues[j].ID]
+	})
+
+	start, end := listOptions.GetStartEnd()
+	if start > len(issues) {
+		start = len(issues)
+	}
+	if end > len(issues) {
+		end = len(issues)
+	}
+	return issues[start:end]
+}
+
+// toAPISearchedIssues converts the issues, adding the snippets matching the keyword if highlighting is requested
+func toAPISearchedIssues(ctx *context.APIContext, issues []*issues_model.Issue, keyword string) ([]*api.Issue, error) {
+	apiIssues := convert.ToAPIIssueList(ctx, issues)
+	if len(keyword) == 0 || !ctx.FormBool("highlight") {
+		return apiIssues, nil
+	}
+
+	highlights, err := issue_indexer.HighlightIssues(ctx, issues, keyword)
+	if err != nil {
+		return nil, err
+	}
+	for _, apiIssue := range apiIssues {
+		for _, h := range highlights[apiIssue.ID] {
+			apiIssue.SearchHighlights = append(apiIssue.SearchHighlights, &api.IssueSearchHighlight{
+				Field:     h.Field,
+				CommentID: h.CommentID,
+				Snippets:  h.Snippets,
+			})
+		}
+	}
+	return apiIssues, nil
+}
+
 // GetIssue get an issue of a repository
 func GetIssue(ctx *context.APIContext) {
 	// swagger:operation GET /repos/{owner}/{repo}/issues/{index} issue issueGetIssue
@@ -591,6 +707,8 @@ func CreateIssue(ctx *context.APIContext) {
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/Issue"
+	//   "202":
+	//     description: the issue was held for review by a site administrator
 	//   "403":
 	//     "$ref": "#/responses/forbidden"
 	//   "412":
@@ -651,10 +769,25 @@ func CreateIssue(ctx *context.APIContext) {
 		form.Labels = make([]int64, 0)
 	}
 
+	if held, err := spam_service.HoldIssueIfSpam(ctx, ctx.Repo.Repository, issue, form.Labels, nil, assigneeIDs, ""); err != nil {
+		if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "HoldIssueIfSpam", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "HoldIssueIfSpam", err)
+		}
+		return
+	} else if held {
+		ctx.Status(http.StatusAccepted)
+		return
+	}
+
 	if err := issue_service.NewIssue(ctx, ctx.Repo.Repository, issue, form.Labels, nil, assigneeIDs); err != nil {
 		if repo_model.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err)
 			return
+		} else if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "NewIssue", err)
+			return
 		}
 		ctx.Error(http.StatusInternalServerError, "NewIssue", err)
 		return
@@ -888,6 +1021,10 @@ func DeleteIssue(ctx *context.APIContext) {
 	}
 
 	if err = issue_service.DeleteIssue(ctx, ctx.Doer, ctx.Repo.GitRepo, issue); err != nil {
+		if issues_model.IsErrUnderLegalHold(err) {
+			ctx.Error(http.StatusForbidden, "DeleteIssueByID", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "DeleteIssueByID", err)
 		return
 	}
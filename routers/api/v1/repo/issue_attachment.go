@@ -12,6 +12,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/upload"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/attachment"
 	"code.gitea.io/gitea/services/convert"
@@ -183,6 +184,10 @@ func CreateIssueAttachment(ctx *context.APIContext) {
 		IssueID:    issue.ID,
 	})
 	if err != nil {
+		if upload.IsErrFileTypeForbidden(err) || upload.IsErrFileTooLarge(err) {
+			ctx.Error(http.StatusBadRequest, "UploadAttachment", err)
+			return
+		}
 		ctx.Error(http.StatusInternalServerError, "UploadAttachment", err)
 		return
 	}
@@ -290,6 +295,8 @@ func DeleteIssueAttachment(ctx *context.APIContext) {
 	// responses:
 	//   "204":
 	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
 	//   "404":
 	//     "$ref": "#/responses/error"
 
@@ -298,6 +305,15 @@ func DeleteIssueAttachment(ctx *context.APIContext) {
 		return
 	}
 
+	if err := issues_model.CheckAttachmentLegalHold(ctx, attachment); err != nil {
+		if issues_model.IsErrUnderLegalHold(err) {
+			ctx.Error(http.StatusForbidden, "CheckAttachmentLegalHold", err)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "CheckAttachmentLegalHold", err)
+		return
+	}
+
 	if err := repo_model.DeleteAttachment(attachment, true); err != nil {
 		ctx.Error(http.StatusInternalServerError, "DeleteAttachment", err)
 		return
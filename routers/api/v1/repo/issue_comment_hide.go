@@ -0,0 +1,151 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// HideIssueComment hides a comment
+func HideIssueComment(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/comments/{id}/hide issue issueHideComment
+	// ---
+	// summary: Hide a comment, its content stays available behind a collapsed section
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/HideCommentOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Comment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.HideCommentOption)
+
+	reason := issues_model.CommentHiddenReason(form.Reason)
+	if !reason.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "invalid reason")
+		return
+	}
+
+	comment := getModeratedComment(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := issues_model.HideComment(ctx, comment, ctx.Doer, reason); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToComment(ctx, comment))
+}
+
+// UnhideIssueComment shows a hidden comment again
+func UnhideIssueComment(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/comments/{id}/hide issue issueUnhideComment
+	// ---
+	// summary: Show a hidden comment again
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Comment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	comment := getModeratedComment(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := issues_model.UnhideComment(ctx, comment); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToComment(ctx, comment))
+}
+
+// getModeratedComment returns the comment from the path if the doer may moderate it
+func getModeratedComment(ctx *context.APIContext) *issues_model.Comment {
+	comment, err := issues_model.GetCommentByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if issues_model.IsErrCommentNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil
+	}
+	if err := comment.LoadIssue(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return nil
+	}
+	if comment.Issue.RepoID != ctx.Repo.Repository.ID || comment.Type != issues_model.CommentTypeComment {
+		ctx.NotFound()
+		return nil
+	}
+	if !ctx.Repo.IsAdmin() && !ctx.Repo.CanWriteIssuesOrPulls(comment.Issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", "must have write access to moderate comments")
+		return nil
+	}
+	if err := comment.LoadPoster(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return nil
+	}
+	if err := comment.LoadAttachments(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return nil
+	}
+	return comment
+}
@@ -0,0 +1,196 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListIssueCommentReplies lists the replies to a comment
+func ListIssueCommentReplies(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/comments/{id}/replies issue issueListCommentReplies
+	// ---
+	// summary: List the replies to a top-level comment, oldest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/CommentList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	comment := getThreadComment(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	replies, err := issues_model.FindCommentReplies(ctx, comment)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if err := replies.LoadPosters(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if err := replies.LoadAttachments(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	apiReplies := make([]*api.Comment, len(replies))
+	for i, reply := range replies {
+		reply.Issue = comment.Issue
+		apiReplies[i] = convert.ToComment(ctx, reply)
+	}
+	ctx.JSON(http.StatusOK, &apiReplies)
+}
+
+// CollapseIssueCommentThread collapses the replies to a comment
+func CollapseIssueCommentThread(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/issues/comments/{id}/collapse issue issueCollapseCommentThread
+	// ---
+	// summary: Collapse the replies to a top-level comment
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Comment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	setIssueCommentThreadCollapsed(ctx, true)
+}
+
+// ExpandIssueCommentThread expands the replies to a comment again
+func ExpandIssueCommentThread(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/comments/{id}/collapse issue issueExpandCommentThread
+	// ---
+	// summary: Expand the replies to a top-level comment again
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the comment
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Comment"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	setIssueCommentThreadCollapsed(ctx, false)
+}
+
+func setIssueCommentThreadCollapsed(ctx *context.APIContext, collapsed bool) {
+	comment := getThreadComment(ctx)
+	if ctx.Written() {
+		return
+	}
+	// the author of the comment starting the thread may collapse it as well as moderators
+	if ctx.Doer.ID != comment.PosterID && !ctx.Repo.IsAdmin() && !ctx.Repo.CanWriteIssuesOrPulls(comment.Issue.IsPull) {
+		ctx.Error(http.StatusForbidden, "", "must be the comment author or have write access to collapse the thread")
+		return
+	}
+	if comment.IsReply() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "comment is a reply, not the start of a thread")
+		return
+	}
+	if err := issues_model.SetCommentThreadCollapsed(ctx, comment, collapsed); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if err := comment.LoadPoster(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if err := comment.LoadAttachments(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToComment(ctx, comment))
+}
+
+// getThreadComment returns the plain comment from the path if it belongs to the repository
+func getThreadComment(ctx *context.APIContext) *issues_model.Comment {
+	comment, err := issues_model.GetCommentByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if issues_model.IsErrCommentNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil
+	}
+	if err := comment.LoadIssue(ctx); err != nil {
+		ctx.InternalServerError(err)
+		return nil
+	}
+	if comment.Issue.RepoID != ctx.Repo.Repository.ID || comment.Type != issues_model.CommentTypeComment {
+		ctx.NotFound()
+		return nil
+	}
+	comment.Issue.Repo = ctx.Repo.Repository
+	return comment
+}
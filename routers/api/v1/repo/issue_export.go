@@ -0,0 +1,69 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// ExportIssues exports the issues and pull requests of a repository as zip archive
+func ExportIssues(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/issues/export issue issueExportIssues
+	// ---
+	// summary: Export the issues, pull requests, comments, labels, milestones and attachment manifest of a repository
+	// description: The zip archive contains a file per table in the chosen format and a README.md documenting them.
+	// produces:
+	// - application/zip
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: format
+	//   in: query
+	//   description: format of the files in the archive
+	//   type: string
+	//   enum: [json, csv]
+	//   default: json
+	// responses:
+	//   "200":
+	//     description: zip archive with the exported files
+	//     schema:
+	//       type: file
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	format := issue_service.ExportFormat(ctx.FormString("format"))
+	if format == "" {
+		format = issue_service.ExportFormatJSON
+	}
+	if !format.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "", "format must be json or csv")
+		return
+	}
+
+	ctx.Resp.Header().Set("Content-Type", "application/zip")
+	ctx.Resp.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s-%s-issues.zip"`, ctx.Repo.Repository.OwnerName, ctx.Repo.Repository.Name))
+	ctx.Resp.WriteHeader(http.StatusOK)
+
+	// the archive is streamed, so errors can't change the response anymore and leave it truncated
+	if err := issue_service.ExportRepositoryIssues(ctx, ctx.Repo.Repository, format, ctx.Resp); err != nil {
+		log.Error("Unable to export the issues of %s: %v", ctx.Repo.Repository.FullName(), err)
+	}
+}
@@ -0,0 +1,298 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// isValidLockReason checks if the reason is empty or one of the configured lock reasons
+func isValidLockReason(reason string) bool {
+	return reason == "" || util.SliceContainsString(setting.Repository.Issue.LockReasons, reason)
+}
+
+// LockIssue locks the conversation of an issue or pull request
+func LockIssue(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/issues/{index}/lock issue issueLock
+	// ---
+	// summary: Lock the conversation of an issue or pull request
+	// description: The level and the unlock date of an issue which is locked already are replaced. Only the
+	//   administrators of the repository can lock a conversation to maintainers or change such a lock.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/LockIssueOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Issue"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.LockIssueOption)
+	issue := getIssueForLock(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	level := issues_model.IssueLockLevel(form.Level)
+	if !issue.CanChangeLock(ctx.Repo.Permission, level) {
+		ctx.Error(http.StatusForbidden, "", "no permission to lock the conversation at this level")
+		return
+	}
+	if !isValidLockReason(form.Reason) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "unknown lock reason")
+		return
+	}
+	var until timeutil.TimeStamp
+	if form.Until != nil {
+		if !form.Until.After(time.Now()) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "the unlock date must be in the future")
+			return
+		}
+		until = timeutil.TimeStamp(form.Until.Unix())
+	}
+
+	if err := issues_model.LockIssue(&issues_model.IssueLockOptions{
+		Doer:   ctx.Doer,
+		Issue:  issue,
+		Reason: form.Reason,
+		Level:  level,
+		Until:  until,
+	}); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LockIssue", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAPIIssue(ctx, issue))
+}
+
+// UnlockIssue unlocks the conversation of an issue or pull request
+func UnlockIssue(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/issues/{index}/lock issue issueUnlock
+	// ---
+	// summary: Unlock the conversation of an issue or pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the issue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Issue"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	issue := getIssueForLock(ctx)
+	if ctx.Written() {
+		return
+	}
+	if !issue.CanChangeLock(ctx.Repo.Permission, "") {
+		ctx.Error(http.StatusForbidden, "", "no permission to unlock the conversation")
+		return
+	}
+
+	if err := issues_model.UnlockIssue(&issues_model.IssueLockOptions{
+		Doer:  ctx.Doer,
+		Issue: issue,
+	}); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UnlockIssue", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToAPIIssue(ctx, issue))
+}
+
+func getIssueForLock(ctx *context.APIContext) *issues_model.Issue {
+	issue, err := issues_model.GetIssueByIndex(ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetIssueByIndex", err)
+		}
+		return nil
+	}
+	issue.Repo = ctx.Repo.Repository
+	return issue
+}
+
+// GetIssueLockPolicy returns the lock policy of a repository
+func GetIssueLockPolicy(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/lock_policy repository repoGetIssueLockPolicy
+	// ---
+	// summary: Get the policy for locking inactive closed issues and pull requests of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueLockPolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	policy, err := issues_model.GetIssueLockPolicy(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	} else if policy == nil {
+		ctx.NotFound()
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToIssueLockPolicy(policy))
+}
+
+// EditIssueLockPolicy creates or replaces the lock policy of a repository
+func EditIssueLockPolicy(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/lock_policy repository repoEditIssueLockPolicy
+	// ---
+	// summary: Set the policy for locking inactive closed issues and pull requests of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditIssueLockPolicyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueLockPolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditIssueLockPolicyOption)
+	if !isValidLockReason(form.Reason) {
+		ctx.Error(http.StatusUnprocessableEntity, "", "unknown lock reason")
+		return
+	}
+
+	policy := &issues_model.IssueLockPolicy{
+		RepoID:         ctx.Repo.Repository.ID,
+		Enabled:        form.Enabled == nil || *form.Enabled,
+		ApplyToIssues:  form.ApplyToIssues == nil || *form.ApplyToIssues,
+		ApplyToPulls:   form.ApplyToPulls == nil || *form.ApplyToPulls,
+		DaysAfterClose: form.DaysAfterClose,
+		LockLevel:      issues_model.IssueLockLevel(form.LockLevel),
+		Reason:         form.Reason,
+		LockComment:    form.LockComment,
+	}
+	if err := issues_model.SaveIssueLockPolicy(ctx, policy); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToIssueLockPolicy(policy))
+}
+
+// DeleteIssueLockPolicy removes the lock policy of a repository
+func DeleteIssueLockPolicy(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/lock_policy repository repoDeleteIssueLockPolicy
+	// ---
+	// summary: Delete the policy for locking inactive closed issues and pull requests of a repository
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := issues_model.DeleteIssueLockPolicy(ctx, ctx.Repo.Repository.ID); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
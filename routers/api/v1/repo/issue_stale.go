@@ -0,0 +1,211 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// defaultStaleLabelColor is the color of the stale label when it is created for a policy
+const defaultStaleLabelColor = "#ededed"
+
+// GetStalePolicy returns the stale policy of a repository
+func GetStalePolicy(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/stale_policy repository repoGetStalePolicy
+	// ---
+	// summary: Get the policy for inactive issues and pull requests of a repository
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StalePolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	policy, err := issues_model.GetStalePolicy(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	} else if policy == nil {
+		ctx.NotFound()
+		return
+	}
+
+	result, err := convert.ToStalePolicy(ctx, policy)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// EditStalePolicy creates or replaces the stale policy of a repository
+func EditStalePolicy(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/stale_policy repository repoEditStalePolicy
+	// ---
+	// summary: Set the policy for inactive issues and pull requests of a repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/EditStalePolicyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/StalePolicy"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditStalePolicyOption)
+	repo := ctx.Repo.Repository
+
+	policy := &issues_model.StalePolicy{
+		RepoID:              repo.ID,
+		Enabled:             form.Enabled == nil || *form.Enabled,
+		ApplyToIssues:       form.ApplyToIssues == nil || *form.ApplyToIssues,
+		ApplyToPulls:        form.ApplyToPulls == nil || *form.ApplyToPulls,
+		DaysUntilStale:      form.DaysUntilStale,
+		DaysUntilClose:      form.DaysUntilClose,
+		StaleComment:        form.StaleComment,
+		CloseComment:        form.CloseComment,
+		ExemptLabelIDs:      make([]int64, 0, len(form.ExemptLabels)),
+		ExemptMilestoneIDs:  make([]int64, 0, len(form.ExemptMilestones)),
+		ExemptAllMilestones: form.ExemptAllMilestones,
+	}
+	if !policy.ApplyToIssues && !policy.ApplyToPulls {
+		ctx.Error(http.StatusUnprocessableEntity, "", "the policy must apply to issues, pull requests or both")
+		return
+	}
+
+	staleLabelName := strings.TrimSpace(form.StaleLabel)
+	if staleLabelName == "" {
+		staleLabelName = "stale"
+	}
+	staleLabel, err := issues_model.GetLabelInRepoByName(ctx, repo.ID, staleLabelName)
+	if issues_model.IsErrRepoLabelNotExist(err) {
+		staleLabel = &issues_model.Label{
+			RepoID:      repo.ID,
+			Name:        staleLabelName,
+			Description: "No recent activity",
+			Color:       defaultStaleLabelColor,
+		}
+		err = issues_model.NewLabel(ctx, staleLabel)
+	}
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	policy.StaleLabelID = staleLabel.ID
+
+	for _, name := range form.ExemptLabels {
+		label, err := issues_model.GetLabelInRepoByName(ctx, repo.ID, name)
+		if err != nil {
+			if issues_model.IsErrRepoLabelNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("label %q does not exist", name))
+			} else {
+				ctx.InternalServerError(err)
+			}
+			return
+		}
+		if label.ID == staleLabel.ID {
+			ctx.Error(http.StatusUnprocessableEntity, "", "the stale label cannot be an exempt label")
+			return
+		}
+		policy.ExemptLabelIDs = append(policy.ExemptLabelIDs, label.ID)
+	}
+	for _, name := range form.ExemptMilestones {
+		milestone, err := issues_model.GetMilestoneByRepoIDANDName(repo.ID, name)
+		if err != nil {
+			if issues_model.IsErrMilestoneNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("milestone %q does not exist", name))
+			} else {
+				ctx.InternalServerError(err)
+			}
+			return
+		}
+		policy.ExemptMilestoneIDs = append(policy.ExemptMilestoneIDs, milestone.ID)
+	}
+
+	if err := issues_model.SaveStalePolicy(ctx, policy); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result, err := convert.ToStalePolicy(ctx, policy)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// DeleteStalePolicy removes the stale policy of a repository
+func DeleteStalePolicy(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/stale_policy repository repoDeleteStalePolicy
+	// ---
+	// summary: Delete the policy for inactive issues and pull requests of a repository
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := issues_model.DeleteStalePolicy(ctx, ctx.Repo.Repository.ID); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
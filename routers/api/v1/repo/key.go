@@ -167,6 +167,73 @@ func GetDeployKey(ctx *context.APIContext) {
 	ctx.JSON(http.StatusOK, apiKey)
 }
 
+// ListDeployKeyUsages lists the recorded accesses with a deploy key of a repository
+func ListDeployKeyUsages(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/keys/{id}/usages repository repoListKeyUsages
+	// ---
+	// summary: List the recorded accesses with a repository's deploy key, the newest first
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the key
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DeployKeyUsageList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	key, err := asymkey_model.GetDeployKeyByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if asymkey_model.IsErrDeployKeyNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetDeployKeyByID", err)
+		}
+		return
+	}
+	if key.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound()
+		return
+	}
+
+	usages, count, err := asymkey_model.ListDeployKeyUsages(ctx, key.ID, utils.GetListOptions(ctx))
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListDeployKeyUsages", err)
+		return
+	}
+
+	apiUsages := make([]*api.DeployKeyUsage, 0, len(usages))
+	for _, usage := range usages {
+		apiUsages = append(apiUsages, convert.ToDeployKeyUsage(usage))
+	}
+
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, apiUsages)
+}
+
 // HandleCheckKeyStringError handle check key error
 func HandleCheckKeyStringError(ctx *context.APIContext, err error) {
 	if db.IsErrSSHDisabled(err) {
@@ -231,12 +298,25 @@ func CreateDeployKey(ctx *context.APIContext) {
 		return
 	}
 
-	key, err := asymkey_model.AddDeployKey(ctx.Repo.Repository.ID, form.Title, content, form.ReadOnly)
+	refPatterns, err := asymkey_model.ValidateDeployKeyRefPatterns(form.RefPatterns)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	key, err := asymkey_model.AddDeployKey(ctx.Repo.Repository.ID, form.Title, content, form.ReadOnly || form.ArchiveOnly)
 	if err != nil {
 		HandleAddKeyError(ctx, err)
 		return
 	}
 
+	if len(refPatterns) > 0 || form.ArchiveOnly {
+		if err := asymkey_model.SetDeployKeyRestrictions(ctx, key, refPatterns, form.ArchiveOnly); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetDeployKeyRestrictions", err)
+			return
+		}
+	}
+
 	key.Content = content
 	apiLink := composeDeployKeysAPILink(ctx.Repo.Owner.Name, ctx.Repo.Repository.Name)
 	ctx.JSON(http.StatusCreated, convert.ToDeployKey(apiLink, key))
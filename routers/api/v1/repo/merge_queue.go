@@ -0,0 +1,288 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	asymkey_service "code.gitea.io/gitea/services/asymkey"
+	"code.gitea.io/gitea/services/convert"
+	"code.gitea.io/gitea/services/forms"
+	"code.gitea.io/gitea/services/mergequeue"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// AddToMergeQueue adds a pull request to the merge queue of its base branch
+func AddToMergeQueue(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/pulls/{index}/merge-queue repository repoAddToMergeQueue
+	// ---
+	// summary: Add a pull request to the merge queue of its base branch
+	// description: The pull requests of a merge queue are tested together by merge trains, which merge them into the
+	//   base branch in a branch prefixed with `gitea-merge-queue/`. They are merged once the status checks required by
+	//   the protection of the base branch succeed for the commit of their train.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request to queue
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     $ref: "#/definitions/MergePullRequestOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "405":
+	//     "$ref": "#/responses/empty"
+	//   "409":
+	//     "$ref": "#/responses/error"
+
+	form := web.GetForm(ctx).(*forms.MergePullRequestForm)
+
+	pr := getPullRequestForMergeQueue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if len(form.Do) == 0 {
+		form.Do = string(repo_model.MergeStyleMerge)
+	}
+	style := repo_model.MergeStyle(form.Do)
+	if style == repo_model.MergeStyleManuallyMerged {
+		ctx.Error(http.StatusMethodNotAllowed, "Invalid merge style", "pull requests cannot be queued to be merged manually")
+		return
+	}
+
+	message := strings.TrimSpace(form.MergeTitleField)
+	if len(message) == 0 {
+		var err error
+		message, _, err = pull_service.GetDefaultMergeMessage(ctx, ctx.Repo.GitRepo, pr, style)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetDefaultMergeMessage", err)
+			return
+		}
+	}
+	form.MergeMessageField = strings.TrimSpace(form.MergeMessageField)
+	if len(form.MergeMessageField) > 0 {
+		message += "\n\n" + form.MergeMessageField
+	}
+
+	if err := mergequeue.Add(ctx, ctx.Doer, pr, style, message); err != nil {
+		switch {
+		case pull_model.IsErrAlreadyInMergeQueue(err):
+			ctx.Error(http.StatusConflict, "AddToMergeQueue", err)
+		case errors.Is(err, pull_service.ErrIsClosed):
+			ctx.NotFound()
+		case errors.Is(err, mergequeue.ErrMergeStyleNotAllowed):
+			ctx.Error(http.StatusMethodNotAllowed, "Invalid merge style", fmt.Errorf("%s is not an allowed merge style for this repository", style))
+		case errors.Is(err, pull_service.ErrUserNotAllowedToMerge):
+			ctx.Error(http.StatusMethodNotAllowed, "Merge", "User not allowed to merge PR")
+		case errors.Is(err, pull_service.ErrHasMerged):
+			ctx.Error(http.StatusMethodNotAllowed, "PR already merged", "")
+		case errors.Is(err, pull_service.ErrIsWorkInProgress):
+			ctx.Error(http.StatusMethodNotAllowed, "PR is a work in progress", "Work in progress PRs cannot be merged")
+		case errors.Is(err, pull_service.ErrNotMergableState), errors.Is(err, pull_service.ErrIsChecking):
+			ctx.Error(http.StatusMethodNotAllowed, "PR not in mergeable state", "Please try again later")
+		case errors.Is(err, pull_service.ErrDependenciesLeft):
+			ctx.Error(http.StatusMethodNotAllowed, "PR is blocked by an open dependency", err)
+		case models.IsErrDisallowedToMerge(err):
+			ctx.Error(http.StatusMethodNotAllowed, "PR is not ready to be merged", err)
+		case asymkey_service.IsErrWontSign(err):
+			ctx.Error(http.StatusMethodNotAllowed, fmt.Sprintf("Protected branch %s requires signed commits but this merge would not be signed", pr.BaseBranch), err)
+		default:
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.Status(http.StatusCreated)
+}
+
+// RemoveFromMergeQueue removes a pull request from the merge queue of its base branch
+func RemoveFromMergeQueue(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/pulls/{index}/merge-queue repository repoRemoveFromMergeQueue
+	// ---
+	// summary: Remove a pull request from the merge queue of its base branch
+	// description: The merge train testing the pull request is discarded and the pull requests queued after it are tested again.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: index
+	//   in: path
+	//   description: index of the pull request to remove
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	pr := getPullRequestForMergeQueue(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	exist, entry, err := pull_model.GetMergeQueueEntryByPullID(ctx, pr.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if !exist {
+		ctx.NotFound()
+		return
+	}
+
+	if ctx.Doer.ID != entry.DoerID {
+		allowed, err := access_model.IsUserRepoAdmin(ctx, ctx.Repo.Repository, ctx.Doer)
+		if err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		if !allowed {
+			ctx.Error(http.StatusForbidden, "No permission to remove", "user has no permission to remove the pull request from the merge queue")
+			return
+		}
+	}
+
+	if err := mergequeue.Remove(ctx, pr); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListMergeQueue lists the pull requests of the merge queue of a branch
+func ListMergeQueue(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/merge-queue repository repoListMergeQueue
+	// ---
+	// summary: List the pull requests of the merge queue of a branch in their order
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: branch
+	//   in: query
+	//   description: base branch of the merge queue, defaults to the default branch of the repository
+	//   type: string
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MergeQueue"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	branch := ctx.FormString("branch")
+	if branch == "" {
+		branch = ctx.Repo.Repository.DefaultBranch
+	}
+
+	entries, err := pull_model.GetMergeQueue(ctx, ctx.Repo.Repository.ID, branch)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	train, err := pull_model.GetMergeTrain(ctx, ctx.Repo.Repository.ID, branch)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	apiEntries := make([]*api.MergeQueueEntry, 0, len(entries))
+	for _, entry := range entries {
+		pr, err := issues_model.GetPullRequestByID(ctx, entry.PullID)
+		if err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		if err := pr.LoadIssue(ctx); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		if err := entry.LoadDoer(ctx); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+		apiEntry := &api.MergeQueueEntry{
+			Number:     pr.Index,
+			Title:      pr.Issue.Title,
+			MergeStyle: string(entry.MergeStyle),
+			QueuedBy:   convert.ToUser(ctx, entry.Doer, ctx.Doer),
+			Created:    entry.CreatedUnix.AsTime(),
+		}
+		if train != nil && entry.TrainID == train.ID {
+			apiEntry.MergeTrainBranch = train.Branch
+		}
+		apiEntries = append(apiEntries, apiEntry)
+	}
+	ctx.JSON(http.StatusOK, apiEntries)
+}
+
+func getPullRequestForMergeQueue(ctx *context.APIContext) *issues_model.PullRequest {
+	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if issues_model.IsErrPullRequestNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetPullRequestByIndex", err)
+		}
+		return nil
+	}
+	if err := pr.LoadIssue(ctx); err != nil {
+		ctx.Error(http.StatusInternalServerError, "LoadIssue", err)
+		return nil
+	}
+	pr.Issue.Repo = ctx.Repo.Repository
+	pr.BaseRepo = ctx.Repo.Repository
+	return pr
+}
@@ -0,0 +1,279 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	mirror_module "code.gitea.io/gitea/modules/mirror"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	mirror_service "code.gitea.io/gitea/services/mirror"
+)
+
+func mirrorSyncHookURL(repo *repo_model.Repository) string {
+	return setting.AppURL + "api/v1/mirror-sync/" + url.PathEscape(repo.OwnerName) + "/" + url.PathEscape(repo.Name)
+}
+
+// getPullMirror returns the mirror of the repository. If it isn't a pull mirror, write to `ctx` accordingly
+func getPullMirror(ctx *context.APIContext) *repo_model.Mirror {
+	if !setting.Mirror.Enabled {
+		ctx.Error(http.StatusBadRequest, "", "Mirror feature is disabled")
+		return nil
+	}
+	m, err := repo_model.GetMirrorByRepoID(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		if errors.Is(err, repo_model.ErrMirrorNotExist) {
+			ctx.Error(http.StatusBadRequest, "", "Repository is not a mirror")
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetMirrorByRepoID", err)
+		}
+		return nil
+	}
+	return m
+}
+
+// GetMirrorSyncHook gets the webhook which triggers a sync of a pull mirror
+func GetMirrorSyncHook(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/mirror-sync/hook repository repoGetMirrorSyncHook
+	// ---
+	// summary: Get the webhook the upstream forge of a pull mirror calls to trigger a sync
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MirrorSyncHook"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	m := getPullMirror(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.MirrorSyncHook{
+		Enabled: m.HasSyncHook(),
+		URL:     mirrorSyncHookURL(ctx.Repo.Repository),
+	})
+}
+
+// EditMirrorSyncHook enables the webhook which triggers a sync of a pull mirror or changes its secret
+func EditMirrorSyncHook(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/mirror-sync/hook repository repoEditMirrorSyncHook
+	// ---
+	// summary: Enable the webhook the upstream forge of a pull mirror calls to trigger a sync, or change its secret
+	// description: The upstream forge has to sign its requests with the secret, GitHub, Bitbucket, Gitea, Forgejo and
+	//   Gogs signatures as well as the GitLab token are accepted. Only pushes and the creation and deletion of
+	//   branches and tags trigger a sync. The secret is only returned by this request.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditMirrorSyncHookOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/MirrorSyncHook"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditMirrorSyncHookOption)
+	m := getPullMirror(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	secret := form.Secret
+	if secret == "" {
+		var err error
+		if secret, err = util.CryptoRandomString(40); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+	}
+	if err := m.SetSyncHookSecret(secret); err != nil {
+		ctx.Error(http.StatusInternalServerError, "SetSyncHookSecret", err)
+		return
+	}
+	if err := repo_model.UpdateMirrorSyncHookSecret(ctx, m); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateMirrorSyncHookSecret", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, &api.MirrorSyncHook{
+		Enabled: true,
+		URL:     mirrorSyncHookURL(ctx.Repo.Repository),
+		Secret:  secret,
+	})
+}
+
+// DeleteMirrorSyncHook disables the webhook which triggers a sync of a pull mirror
+func DeleteMirrorSyncHook(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/mirror-sync/hook repository repoDeleteMirrorSyncHook
+	// ---
+	// summary: Disable the webhook the upstream forge of a pull mirror calls to trigger a sync
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "400":
+	//     "$ref": "#/responses/error"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	m := getPullMirror(ctx)
+	if ctx.Written() {
+		return
+	}
+	_ = m.SetSyncHookSecret("")
+	if err := repo_model.UpdateMirrorSyncHookSecret(ctx, m); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateMirrorSyncHookSecret", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// ReceiveMirrorSyncHook receives the webhook of the upstream forge of a pull mirror and triggers a sync
+func ReceiveMirrorSyncHook(ctx *context.APIContext) {
+	// swagger:operation POST /mirror-sync/{owner}/{repo} repository repoReceiveMirrorSyncHook
+	// ---
+	// summary: Receive the webhook of the upstream forge of a pull mirror and sync the mirror immediately
+	// description: The request must be signed with the secret of the webhook, it doesn't need any other
+	//   authentication. Events which don't change branches or tags, e.g. pings, are acknowledged without a sync.
+	// consumes:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "202":
+	//     "$ref": "#/responses/empty"
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "413":
+	//     "$ref": "#/responses/error"
+
+	if !setting.Mirror.Enabled {
+		ctx.NotFound()
+		return
+	}
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ctx.Params(":owner"), ctx.Params(":repo"))
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	if !repo.IsMirror {
+		ctx.NotFound()
+		return
+	}
+	m, err := repo_model.GetMirrorByRepoID(ctx, repo.ID)
+	if err != nil {
+		if errors.Is(err, repo_model.ErrMirrorNotExist) {
+			ctx.NotFound()
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	if !m.HasSyncHook() {
+		ctx.NotFound()
+		return
+	}
+
+	body, err := io.ReadAll(io.LimitReader(ctx.Req.Body, mirror_service.MaxSyncHookPayloadSize+1))
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if len(body) > mirror_service.MaxSyncHookPayloadSize {
+		ctx.Error(http.StatusRequestEntityTooLarge, "", "payload too large")
+		return
+	}
+	secret, err := m.SyncHookSecret()
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "SyncHookSecret", err)
+		return
+	}
+	// requests without a valid signature must not tell which repositories are mirrors with a webhook
+	if !mirror_service.VerifySyncHookRequest(ctx.Req.Header, body, secret) {
+		ctx.NotFound()
+		return
+	}
+
+	if !mirror_service.IsSyncHookRefEvent(ctx.Req.Header) {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+	log.Trace("Mirror sync of %s triggered by the upstream webhook", repo.FullName())
+	mirror_module.AddPullMirrorToQueue(repo.ID)
+	ctx.Status(http.StatusAccepted)
+}
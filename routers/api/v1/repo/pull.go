@@ -28,6 +28,7 @@ import (
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
@@ -38,6 +39,7 @@ import (
 	issue_service "code.gitea.io/gitea/services/issue"
 	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
+	spam_service "code.gitea.io/gitea/services/spam"
 )
 
 // ListPullRequests returns a list of all PRs
@@ -274,6 +276,8 @@ func CreatePullRequest(ctx *context.APIContext) {
 	// responses:
 	//   "201":
 	//     "$ref": "#/responses/PullRequest"
+	//   "202":
+	//     description: the pull request was held for review by a site administrator
 	//   "409":
 	//     "$ref": "#/responses/error"
 	//   "422":
@@ -417,10 +421,25 @@ func CreatePullRequest(ctx *context.APIContext) {
 		}
 	}
 
+	if held, err := spam_service.HoldPullRequestIfSpam(ctx, repo, prIssue, labelIDs, nil, pr, assigneeIDs, ""); err != nil {
+		if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "HoldPullRequestIfSpam", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "HoldPullRequestIfSpam", err)
+		}
+		return
+	} else if held {
+		ctx.Status(http.StatusAccepted)
+		return
+	}
+
 	if err := pull_service.NewPullRequest(ctx, repo, prIssue, labelIDs, []string{}, pr, assigneeIDs); err != nil {
 		if repo_model.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err)
 			return
+		} else if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, "NewPullRequest", err)
+			return
 		}
 		ctx.Error(http.StatusInternalServerError, "NewPullRequest", err)
 		return
@@ -1371,6 +1390,18 @@ func GetPullRequestFiles(ctx *context.APIContext) {
 	//   description: whitespace behavior
 	//   type: string
 	//   enum: [ignore-all, ignore-change, ignore-eol, show-all]
+	// - name: rename_threshold
+	//   in: query
+	//   description: minimal similarity in percent of a renamed file (default 50)
+	//   type: integer
+	// - name: copy_threshold
+	//   in: query
+	//   description: minimal similarity in percent of a copied file, copies are only detected if it is set or find_copies_harder is enabled
+	//   type: integer
+	// - name: find_copies_harder
+	//   in: query
+	//   description: consider unchanged files as source of copies too (default 'false')
+	//   type: boolean
 	// - name: page
 	//   in: query
 	//   description: page number of results to return (1-based)
@@ -1384,6 +1415,13 @@ func GetPullRequestFiles(ctx *context.APIContext) {
 	//     "$ref": "#/responses/ChangedFileList"
 	//   "404":
 	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	renameDetection, ok := getRenameDetection(ctx)
+	if !ok {
+		return
+	}
 
 	pr, err := issues_model.GetPullRequestByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
 	if err != nil {
@@ -1439,6 +1477,7 @@ func GetPullRequestFiles(ctx *context.APIContext) {
 			MaxLineCharacters:  setting.Git.MaxGitDiffLineCharacters,
 			MaxFiles:           -1, // GetDiff() will return all files
 			WhitespaceBehavior: gitdiff.GetWhitespaceFlag(ctx.FormString("whitespace")),
+			RenameDetection:    renameDetection,
 		})
 	if err != nil {
 		ctx.ServerError("GetDiff", err)
@@ -0,0 +1,168 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
+	api "code.gitea.io/gitea/modules/structs"
+	files_service "code.gitea.io/gitea/services/repository/files"
+)
+
+// GetReadme gets the README of the root directory of a repository
+func GetReadme(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/readme repository repoGetReadme
+	// ---
+	// summary: Gets the README of the root directory, which may be in a documentation directory like docs/
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
+	//   type: string
+	//   required: false
+	// - name: lang
+	//   in: query
+	//   description: "language of the preferred README variant, e.g. zh-CN for README.zh-cn.md. Default the language of the request"
+	//   type: string
+	//   required: false
+	// - name: render
+	//   in: query
+	//   description: render the README to HTML
+	//   type: boolean
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoReadme"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	getReadme(ctx, "")
+}
+
+// GetDirReadme gets the README of a directory of a repository
+func GetDirReadme(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/readme/{dir} repository repoGetDirReadme
+	// ---
+	// summary: Gets the README of a directory
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: dir
+	//   in: path
+	//   description: path of the directory in the repo
+	//   type: string
+	//   required: true
+	// - name: ref
+	//   in: query
+	//   description: "The name of the commit/branch/tag. Default the repository’s default branch (usually master)"
+	//   type: string
+	//   required: false
+	// - name: lang
+	//   in: query
+	//   description: "language of the preferred README variant, e.g. zh-CN for README.zh-cn.md. Default the language of the request"
+	//   type: string
+	//   required: false
+	// - name: render
+	//   in: query
+	//   description: render the README to HTML
+	//   type: boolean
+	//   required: false
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoReadme"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	getReadme(ctx, strings.Trim(ctx.Params("*"), "/"))
+}
+
+func getReadme(ctx *context.APIContext, treePath string) {
+	if !canReadFiles(ctx.Repo) {
+		ctx.Error(http.StatusInternalServerError, "GetReadme", repo_model.ErrUserDoesNotHaveAccessToRepo{
+			UserID:   ctx.Doer.ID,
+			RepoName: ctx.Repo.Repository.LowerName,
+		})
+		return
+	}
+
+	language := ctx.FormTrim("lang")
+	if language == "" {
+		language = ctx.Locale.Language()
+	}
+
+	contents, err := files_service.GetReadme(ctx, ctx.Repo.Repository, treePath, ctx.FormTrim("ref"), language)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("GetReadme", err)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "GetReadme", err)
+		return
+	}
+	if !ctx.Repo.Permission.CanReadCodePath(contents.Path) {
+		ctx.NotFound("CanReadCodePath")
+		return
+	}
+
+	readme := &api.RepoReadme{Contents: contents}
+	if ctx.FormBool("render") && contents.Content != nil && markup.Type(contents.Path) != "" {
+		readme.HTML = renderReadme(ctx, contents)
+	}
+	ctx.JSON(http.StatusOK, readme)
+}
+
+// renderReadme renders the README to HTML, nothing is returned if it can not be rendered
+func renderReadme(ctx *context.APIContext, contents *api.ContentsResponse) string {
+	content, err := base64.StdEncoding.DecodeString(*contents.Content)
+	if err != nil {
+		log.Error("Unable to decode %s: %v", contents.Path, err)
+		return ""
+	}
+
+	// relative links are resolved against the directory of the README
+	urlPrefix := ""
+	if contents.HTMLURL != nil {
+		urlPrefix = (*contents.HTMLURL)[:strings.LastIndex(*contents.HTMLURL, "/")+1]
+	}
+	rendered, err := markup.RenderString(&markup.RenderContext{
+		Ctx:          ctx,
+		RelativePath: contents.Path,
+		URLPrefix:    urlPrefix,
+		Metas:        ctx.Repo.Repository.ComposeDocumentMetas(),
+	}, string(content))
+	if err != nil {
+		log.Error("Render failed for %s in %-v: %v", contents.Path, ctx.Repo.Repository, err)
+		return ""
+	}
+	return rendered
+}
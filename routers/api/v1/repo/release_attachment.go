@@ -201,7 +201,7 @@ func CreateReleaseAttachment(ctx *context.APIContext) {
 		ReleaseID:  releaseID,
 	})
 	if err != nil {
-		if upload.IsErrFileTypeForbidden(err) {
+		if upload.IsErrFileTypeForbidden(err) || upload.IsErrFileTooLarge(err) {
 			ctx.Error(http.StatusBadRequest, "DetectContentType", err)
 			return
 		}
@@ -12,6 +12,7 @@ import (
 
 	activities_model "code.gitea.io/gitea/models/activities"
 	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/models/organization"
 	"code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
@@ -44,7 +45,8 @@ func Search(ctx *context.APIContext) {
 	// parameters:
 	// - name: q
 	//   in: query
-	//   description: keyword
+	//   description: keyword, may contain the qualifiers "language:", "topic:", "owner:", "stars:", "forks:",
+	//                "pushed:", "created:", "archived:", "fork:", "mirror:", "template:" and "is:"
 	//   type: string
 	// - name: topic
 	//   in: query
@@ -127,7 +129,6 @@ func Search(ctx *context.APIContext) {
 	opts := &repo_model.SearchRepoOptions{
 		ListOptions:        utils.GetListOptions(ctx),
 		Actor:              ctx.Doer,
-		Keyword:            ctx.FormTrim("q"),
 		OwnerID:            ctx.FormInt64("uid"),
 		PriorityOwnerID:    ctx.FormInt64("priority_owner_id"),
 		TeamID:             ctx.FormInt64("team_id"),
@@ -143,6 +144,11 @@ func Search(ctx *context.APIContext) {
 		opts.Template = util.OptionalBoolOf(ctx.FormBool("template"))
 	}
 
+	if err := opts.ParseQuery(ctx.FormTrim("q")); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "ParseQuery", err)
+		return
+	}
+
 	if ctx.FormBool("exclusive") {
 		opts.Collaborate = util.OptionalBoolFalse
 	}
@@ -202,23 +208,19 @@ func Search(ctx *context.APIContext) {
 		return
 	}
 
+	perms, err := access_model.GetUserRepoPermissions(ctx, repos, ctx.Doer)
+	if err != nil {
+		ctx.JSON(http.StatusInternalServerError, api.SearchError{
+			OK:    false,
+			Error: err.Error(),
+		})
+		return
+	}
+
 	results := make([]*api.Repository, len(repos))
 	for i, repo := range repos {
-		if err = repo.LoadOwner(ctx); err != nil {
-			ctx.JSON(http.StatusInternalServerError, api.SearchError{
-				OK:    false,
-				Error: err.Error(),
-			})
-			return
-		}
-		accessMode, err := access_model.AccessLevel(ctx, ctx.Doer, repo)
-		if err != nil {
-			ctx.JSON(http.StatusInternalServerError, api.SearchError{
-				OK:    false,
-				Error: err.Error(),
-			})
-		}
-		results[i] = convert.ToRepo(ctx, repo, accessMode)
+		p := perms[repo.ID]
+		results[i] = convert.ToRepo(ctx, repo, p.UnitAccessMode(unit_model.TypeCode))
 	}
 	ctx.SetLinkHeader(int(count), opts.PageSize)
 	ctx.SetTotalCountHeader(count)
@@ -240,25 +242,53 @@ func CreateUserRepo(ctx *context.APIContext, owner *user_model.User, opt api.Cre
 		return
 	}
 
+	objectFormat, err := git.ParseObjectFormat(opt.ObjectFormatName)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+		return
+	}
+
+	selected := map[repo_model.TemplateCatalogKind]string{
+		repo_model.TemplateCatalogGitignore:        opt.Gitignores,
+		repo_model.TemplateCatalogLicense:          opt.License,
+		repo_model.TemplateCatalogIssueTemplate:    opt.IssueTemplates,
+		repo_model.TemplateCatalogWorkflowTemplate: opt.WorkflowTemplates,
+	}
+	for _, kind := range repo_model.TemplateCatalogKinds {
+		if err := repo_module.CheckTemplateCatalogSelection(ctx, owner.ID, kind, selected[kind]); err != nil {
+			if repo_model.IsErrTemplateCatalogItemNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "CheckTemplateCatalogSelection", err)
+			}
+			return
+		}
+	}
+
 	repo, err := repo_service.CreateRepository(ctx, ctx.Doer, owner, repo_module.CreateRepoOptions{
-		Name:          opt.Name,
-		Description:   opt.Description,
-		IssueLabels:   opt.IssueLabels,
-		Gitignores:    opt.Gitignores,
-		License:       opt.License,
-		Readme:        opt.Readme,
-		IsPrivate:     opt.Private,
-		AutoInit:      opt.AutoInit,
-		DefaultBranch: opt.DefaultBranch,
-		TrustModel:    repo_model.ToTrustModel(opt.TrustModel),
-		IsTemplate:    opt.Template,
+		Name:              opt.Name,
+		Description:       opt.Description,
+		IssueLabels:       opt.IssueLabels,
+		Gitignores:        opt.Gitignores,
+		License:           opt.License,
+		Readme:            opt.Readme,
+		IssueTemplates:    opt.IssueTemplates,
+		WorkflowTemplates: opt.WorkflowTemplates,
+		IsPrivate:         opt.Private,
+		IsInternal:        opt.Internal,
+		AutoInit:          opt.AutoInit,
+		DefaultBranch:     opt.DefaultBranch,
+		TrustModel:        repo_model.ToTrustModel(opt.TrustModel),
+		IsTemplate:        opt.Template,
+		ObjectFormat:      objectFormat,
 	})
 	if err != nil {
 		if repo_model.IsErrRepoAlreadyExist(err) {
 			ctx.Error(http.StatusConflict, "", "The repository with the same name already exists.")
 		} else if db.IsErrNameReserved(err) ||
 			db.IsErrNamePatternNotAllowed(err) ||
-			label.IsErrTemplateLoad(err) {
+			label.IsErrTemplateLoad(err) ||
+			git.IsErrObjectFormatNotSupported(err) {
 			ctx.Error(http.StatusUnprocessableEntity, "", err)
 		} else {
 			ctx.Error(http.StatusInternalServerError, "CreateRepository", err)
@@ -359,6 +389,7 @@ func Generate(ctx *context.APIContext) {
 		DefaultBranch: form.DefaultBranch,
 		Description:   form.Description,
 		Private:       form.Private,
+		Internal:      form.Internal,
 		GitContent:    form.GitContent,
 		Topics:        form.Topics,
 		GitHooks:      form.GitHooks,
@@ -701,6 +732,23 @@ func updateBasicProperties(ctx *context.APIContext, opts api.EditRepoOption) err
 		repo.IsPrivate = *opts.Private
 	}
 
+	if opts.Internal != nil && repo.IsInternal != *opts.Internal {
+		// forks follow the visibility of their base repository
+		if repo.IsFork {
+			if err := repo.GetBaseRepo(ctx); err != nil {
+				ctx.Error(http.StatusInternalServerError, "Unable to load base repository", err)
+				return err
+			}
+			*opts.Internal = repo.BaseRepo.IsInternal
+		}
+		if *opts.Internal {
+			// internal repositories are private
+			repo.IsPrivate = true
+		}
+		visibilityChanged = visibilityChanged || repo.IsInternal != *opts.Internal
+		repo.IsInternal = *opts.Internal
+	}
+
 	if opts.Template != nil {
 		repo.IsTemplate = *opts.Template
 	}
@@ -916,6 +964,18 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 			if opts.DefaultAllowMaintainerEdit != nil {
 				config.DefaultAllowMaintainerEdit = *opts.DefaultAllowMaintainerEdit
 			}
+			if opts.DefaultMergeMessageTemplate != nil {
+				config.DefaultMergeMessageTemplate = *opts.DefaultMergeMessageTemplate
+			}
+			if opts.DefaultSquashMessageTemplate != nil {
+				config.DefaultSquashMessageTemplate = *opts.DefaultSquashMessageTemplate
+			}
+			if opts.MergeMessageTrailers != nil {
+				config.MergeMessageTrailers = *opts.MergeMessageTrailers
+			}
+			if opts.MergeTrainBatchSize != nil {
+				config.MergeTrainBatchSize = *opts.MergeTrainBatchSize
+			}
 
 			units = append(units, repo_model.RepoUnit{
 				RepoID: repo.ID,
@@ -960,17 +1020,44 @@ func updateRepoUnits(ctx *context.APIContext, opts api.EditRepoOption) error {
 		}
 	}
 
-	if opts.HasActions != nil && !unit_model.TypeActions.UnitGlobalDisabled() {
-		if *opts.HasActions {
+	if (opts.HasActions != nil || opts.ActionsApprovalPolicy != nil) && !unit_model.TypeActions.UnitGlobalDisabled() {
+		newHasActions := repo.UnitEnabled(ctx, unit_model.TypeActions)
+		if opts.HasActions != nil {
+			newHasActions = *opts.HasActions
+		}
+		if newHasActions {
+			// keep the config of the unit if it exists
+			config := repo.MustGetUnit(ctx, unit_model.TypeActions).ActionsConfig()
+			if opts.ActionsApprovalPolicy != nil {
+				policy := repo_model.ActionsApprovalPolicy(*opts.ActionsApprovalPolicy)
+				if policy != "" && !policy.IsValid() {
+					err := fmt.Errorf("unknown actions approval policy %q", policy)
+					ctx.Error(http.StatusUnprocessableEntity, "Invalid actions approval policy", err)
+					return err
+				}
+				config.ApprovalPolicy = policy
+			}
 			units = append(units, repo_model.RepoUnit{
 				RepoID: repo.ID,
 				Type:   unit_model.TypeActions,
+				Config: config,
 			})
 		} else {
 			deleteUnitTypes = append(deleteUnitTypes, unit_model.TypeActions)
 		}
 	}
 
+	if opts.HasDiscussions != nil && !unit_model.TypeDiscussions.UnitGlobalDisabled() {
+		if *opts.HasDiscussions {
+			units = append(units, repo_model.RepoUnit{
+				RepoID: repo.ID,
+				Type:   unit_model.TypeDiscussions,
+			})
+		} else {
+			deleteUnitTypes = append(deleteUnitTypes, unit_model.TypeDiscussions)
+		}
+	}
+
 	if len(units)+len(deleteUnitTypes) > 0 {
 		if err := repo_model.UpdateRepositoryUnits(repo, units, deleteUnitTypes); err != nil {
 			ctx.Error(http.StatusInternalServerError, "UpdateRepositoryUnits", err)
@@ -1115,8 +1202,12 @@ func Delete(ctx *context.APIContext) {
 		ctx.Repo.GitRepo.Close()
 	}
 
-	if err := repo_service.DeleteRepository(ctx, ctx.Doer, repo, true); err != nil {
-		ctx.Error(http.StatusInternalServerError, "DeleteRepository", err)
+	if err := repo_service.SoftDeleteRepository(ctx, ctx.Doer, repo); err != nil {
+		if issues_model.IsErrUnderLegalHold(err) {
+			ctx.Error(http.StatusForbidden, "SoftDeleteRepository", err)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "SoftDeleteRepository", err)
 		return
 	}
 
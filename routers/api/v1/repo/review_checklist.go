@@ -0,0 +1,219 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListReviewChecklist lists the items of the review checklist of the repository
+func ListReviewChecklist(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/review_checklist repository repoListReviewChecklist
+	// ---
+	// summary: List the items of the review checklist which reviewers tick when reviewing a pull request
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReviewChecklistItemList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	items, err := issues_model.GetReviewChecklist(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.ReviewChecklistItem, 0, len(items))
+	for _, item := range items {
+		result = append(result, convert.ToReviewChecklistItem(item))
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreateReviewChecklistItem adds an item to the review checklist of the repository
+func CreateReviewChecklistItem(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/review_checklist repository repoCreateReviewChecklistItem
+	// ---
+	// summary: Add an item to the review checklist of the repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateReviewChecklistItemOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/ReviewChecklistItem"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateReviewChecklistItemOption)
+	item := &issues_model.ReviewChecklistItem{
+		RepoID:      ctx.Repo.Repository.ID,
+		Name:        form.Name,
+		Description: form.Description,
+		Required:    form.Required,
+		Sort:        form.Sort,
+	}
+	if err := issues_model.InsertReviewChecklistItem(ctx, item); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToReviewChecklistItem(item))
+}
+
+// EditReviewChecklistItem edits an item of the review checklist of the repository
+func EditReviewChecklistItem(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/review_checklist/{id} repository repoEditReviewChecklistItem
+	// ---
+	// summary: Edit an item of the review checklist of the repository
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the checklist item
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditReviewChecklistItemOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ReviewChecklistItem"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditReviewChecklistItemOption)
+	item, err := issues_model.GetReviewChecklistItem(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if issues_model.IsErrReviewChecklistItemNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+
+	if form.Name != nil {
+		if *form.Name == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "Name", "name must not be empty")
+			return
+		}
+		item.Name = *form.Name
+	}
+	if form.Description != nil {
+		item.Description = *form.Description
+	}
+	if form.Required != nil {
+		item.Required = *form.Required
+	}
+	if form.Sort != nil {
+		item.Sort = *form.Sort
+	}
+
+	if err := issues_model.UpdateReviewChecklistItem(ctx, item); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToReviewChecklistItem(item))
+}
+
+// DeleteReviewChecklistItem removes an item from the review checklist of the repository
+func DeleteReviewChecklistItem(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/review_checklist/{id} repository repoDeleteReviewChecklistItem
+	// ---
+	// summary: Remove an item from the review checklist of the repository
+	// description: The ticks of the item by earlier reviews are removed as well.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the checklist item
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := issues_model.DeleteReviewChecklistItem(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		if issues_model.IsErrReviewChecklistItemNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,314 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/label"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// GetSettings exports all settings of a repository
+func GetSettings(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/settings repository repoGetSettings
+	// ---
+	// summary: Export all settings of a repository as a declarative document
+	// description: The secrets of the webhooks aren't exported.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoSettings"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	settings, err := repo_service.ExportSettings(ctx, ctx.Repo.Repository)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ExportSettings", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// ApplySettings applies a declarative document of settings to a repository
+func ApplySettings(ctx *context.APIContext) {
+	// swagger:operation PUT /repos/{owner}/{repo}/settings repository repoApplySettings
+	// ---
+	// summary: Apply a declarative document of settings to a repository
+	// description: The sections of the document are applied in order, sections which are null are left unchanged.
+	//   Branch protections are matched by their rule name, webhooks by their URL and labels by their name, existing
+	//   items are replaced and missing ones are added. The secret and the client certificate of an existing webhook
+	//   are kept if none is given. If applying a section fails, the previous sections stay applied.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: prune
+	//   in: query
+	//   description: delete the branch protections, webhooks and labels which aren't in the non-null sections of the document
+	//   type: boolean
+	// - name: body
+	//   in: body
+	//   required: true
+	//   schema:
+	//     "$ref": "#/definitions/RepoSettings"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/RepoSettings"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.RepoSettings)
+	prune := ctx.FormBool("prune")
+	if form.Webhooks != nil && setting.DisableWebhooks {
+		ctx.Error(http.StatusForbidden, "", "webhooks disabled by administrator")
+		return
+	}
+
+	if form.Repository != nil {
+		// these properties aren't settings, they are changed with the repository edit API
+		opts := *form.Repository
+		opts.Name = nil
+		opts.Description = nil
+		opts.Website = nil
+		opts.Archived = nil
+		opts.MirrorInterval = nil
+		opts.EnablePrune = nil
+
+		if err := updateBasicProperties(ctx, opts); err != nil {
+			return
+		}
+		if err := updateRepoUnits(ctx, opts); err != nil {
+			return
+		}
+	}
+
+	if form.BranchProtections != nil && !applyBranchProtectionSettings(ctx, form.BranchProtections, prune) {
+		return
+	}
+	if form.Webhooks != nil && !applyWebhookSettings(ctx, form.Webhooks, prune) {
+		return
+	}
+	if form.Labels != nil && !applyLabelSettings(ctx, form.Labels, prune) {
+		return
+	}
+
+	repo, err := repo_model.GetRepositoryByID(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	settings, err := repo_service.ExportSettings(ctx, repo)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ExportSettings", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, settings)
+}
+
+// applyBranchProtectionSettings replaces the branch protections of the repository by the given ones.
+// If an error occurs, write to `ctx` accordingly. Return whether successful
+func applyBranchProtectionSettings(ctx *context.APIContext, forms []*api.CreateBranchProtectionOption, prune bool) bool {
+	rules, err := git_model.FindRepoProtectedBranchRules(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRepoProtectedBranchRules", err)
+		return false
+	}
+	existing := make(map[string]*git_model.ProtectedBranch, len(rules))
+	for _, rule := range rules {
+		existing[rule.RuleName] = rule
+	}
+
+	applied := make(map[string]bool, len(forms))
+	for _, form := range forms {
+		ruleName := form.RuleName
+		if ruleName == "" {
+			ruleName = form.BranchName //nolint
+		}
+		if ruleName == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "both rule_name and branch_name of a branch protection are empty")
+			return false
+		}
+		if applied[ruleName] {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("branch protection %q is given twice", ruleName))
+			return false
+		}
+		applied[ruleName] = true
+
+		protectBranch, ok := existing[ruleName]
+		if !ok {
+			protectBranch = &git_model.ProtectedBranch{
+				RepoID:   ctx.Repo.Repository.ID,
+				RuleName: ruleName,
+			}
+		}
+		if !applyBranchProtectionOption(ctx, protectBranch, form) {
+			return false
+		}
+	}
+
+	if prune {
+		for _, rule := range rules {
+			if applied[rule.RuleName] {
+				continue
+			}
+			if err := git_model.DeleteProtectedBranch(ctx, ctx.Repo.Repository.ID, rule.ID); err != nil {
+				ctx.Error(http.StatusInternalServerError, "DeleteProtectedBranch", err)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// applyWebhookSettings replaces the webhooks of the repository by the given ones.
+// If an error occurs, write to `ctx` accordingly. Return whether successful
+func applyWebhookSettings(ctx *context.APIContext, forms []*api.CreateHookOption, prune bool) bool {
+	hooks, err := webhook_model.ListWebhooksByOpts(ctx, &webhook_model.ListWebhookOptions{RepoID: ctx.Repo.Repository.ID})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "ListWebhooksByOpts", err)
+		return false
+	}
+	existing := make(map[string]*webhook_model.Webhook, len(hooks))
+	for _, hook := range hooks {
+		existing[hook.URL] = hook
+	}
+
+	applied := make(map[string]bool, len(forms))
+	for _, form := range forms {
+		url := form.Config["url"]
+		if applied[url] {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("webhook %q is given twice", url))
+			return false
+		}
+		applied[url] = true
+
+		if !utils.SyncRepoHook(ctx, form, existing[url]) {
+			return false
+		}
+	}
+
+	if prune {
+		for _, hook := range hooks {
+			if applied[hook.URL] {
+				continue
+			}
+			if err := webhook_model.DeleteWebhookByRepoID(ctx.Repo.Repository.ID, hook.ID); err != nil {
+				ctx.Error(http.StatusInternalServerError, "DeleteWebhookByRepoID", err)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// applyLabelSettings replaces the labels of the repository by the given ones.
+// If an error occurs, write to `ctx` accordingly. Return whether successful
+func applyLabelSettings(ctx *context.APIContext, forms []*api.CreateLabelOption, prune bool) bool {
+	labels, err := issues_model.GetLabelsByRepoID(ctx, ctx.Repo.Repository.ID, "", db.ListOptions{})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLabelsByRepoID", err)
+		return false
+	}
+	existing := make(map[string]*issues_model.Label, len(labels))
+	for _, l := range labels {
+		existing[l.Name] = l
+	}
+
+	applied := make(map[string]bool, len(forms))
+	for _, form := range forms {
+		if form.Name == "" {
+			ctx.Error(http.StatusUnprocessableEntity, "", "the name of a label is empty")
+			return false
+		}
+		if applied[form.Name] {
+			ctx.Error(http.StatusUnprocessableEntity, "", fmt.Sprintf("label %q is given twice", form.Name))
+			return false
+		}
+		applied[form.Name] = true
+
+		color, err := label.NormalizeColor(form.Color)
+		if err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "StringToColor", err)
+			return false
+		}
+
+		l, ok := existing[form.Name]
+		if !ok {
+			l = &issues_model.Label{
+				Name:        form.Name,
+				Exclusive:   form.Exclusive,
+				Color:       color,
+				RepoID:      ctx.Repo.Repository.ID,
+				Description: form.Description,
+			}
+			if err := issues_model.NewLabel(ctx, l); err != nil {
+				ctx.Error(http.StatusInternalServerError, "NewLabel", err)
+				return false
+			}
+			continue
+		}
+
+		l.Exclusive = form.Exclusive
+		l.Color = color
+		l.Description = form.Description
+		if err := issues_model.UpdateLabel(l); err != nil {
+			ctx.Error(http.StatusInternalServerError, "UpdateLabel", err)
+			return false
+		}
+	}
+
+	if prune {
+		for _, l := range labels {
+			if applied[l.Name] {
+				continue
+			}
+			if err := issues_model.DeleteLabel(ctx.Repo.Repository.ID, l.ID); err != nil {
+				ctx.Error(http.StatusInternalServerError, "DeleteLabel", err)
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,421 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+	"code.gitea.io/gitea/services/migrations"
+	"code.gitea.io/gitea/services/vendorsync"
+)
+
+// ListVendorSyncs lists the external sources imported into the repository on a schedule
+func ListVendorSyncs(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/vendor_syncs repository repoListVendorSyncs
+	// ---
+	// summary: List the external sources imported into the repository on a schedule
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VendorSyncList"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	syncs, err := repo_model.GetVendorSyncsByRepoID(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+
+	result := make([]*api.VendorSync, 0, len(syncs))
+	for _, s := range syncs {
+		result = append(result, convert.ToVendorSync(s))
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
+// CreateVendorSync schedules the content of an external source to be imported into a branch
+func CreateVendorSync(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/vendor_syncs repository repoCreateVendorSync
+	// ---
+	// summary: Schedule the content of an external source to be imported into a directory of a branch
+	// description: The content replaces the directory in a commit of the authenticated user, whose message records the source, digest and version of the content. The first import is due immediately.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateVendorSyncOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/VendorSync"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateVendorSyncOption)
+	s := &repo_model.VendorSync{
+		RepoID:          ctx.Repo.Repository.ID,
+		Branch:          form.Branch,
+		TargetPath:      form.TargetPath,
+		SourceType:      repo_model.VendorSyncSourceType(form.SourceType),
+		SourceURL:       form.SourceURL,
+		Bucket:          form.Bucket,
+		Prefix:          form.Prefix,
+		AccessKeyID:     form.AccessKeyID,
+		StripComponents: form.StripComponents,
+		Interval:        vendorsync.DefaultInterval,
+		DoerID:          ctx.Doer.ID,
+	}
+	if form.Interval != "" && !parseVendorSyncInterval(ctx, s, form.Interval) {
+		return
+	}
+	if err := s.SetSecretAccessKey(form.SecretAccessKey); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	if !validateVendorSync(ctx, s) {
+		return
+	}
+
+	s.NextSyncUnix = timeutil.TimeStampNow()
+	if err := repo_model.InsertVendorSync(ctx, s); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToVendorSync(s))
+}
+
+// GetVendorSync gets a vendor sync of the repository
+func GetVendorSync(ctx *context.APIContext) {
+	// swagger:operation GET /repos/{owner}/{repo}/vendor_syncs/{id} repository repoGetVendorSync
+	// ---
+	// summary: Get an external source imported into the repository on a schedule
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the vendor sync
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VendorSync"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	s := getVendorSyncByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToVendorSync(s))
+}
+
+// EditVendorSync edits a vendor sync of the repository
+func EditVendorSync(ctx *context.APIContext) {
+	// swagger:operation PATCH /repos/{owner}/{repo}/vendor_syncs/{id} repository repoEditVendorSync
+	// ---
+	// summary: Edit an external source imported into the repository on a schedule
+	// description: The next import is due immediately.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the vendor sync
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/EditVendorSyncOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VendorSync"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.EditVendorSyncOption)
+	s := getVendorSyncByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if form.Branch != nil {
+		s.Branch = *form.Branch
+	}
+	if form.TargetPath != nil {
+		s.TargetPath = *form.TargetPath
+	}
+	if form.SourceType != nil {
+		s.SourceType = repo_model.VendorSyncSourceType(*form.SourceType)
+	}
+	if form.SourceURL != nil {
+		s.SourceURL = *form.SourceURL
+	}
+	if form.Bucket != nil {
+		s.Bucket = *form.Bucket
+	}
+	if form.Prefix != nil {
+		s.Prefix = *form.Prefix
+	}
+	if form.AccessKeyID != nil {
+		s.AccessKeyID = *form.AccessKeyID
+	}
+	if form.SecretAccessKey != nil {
+		if err := s.SetSecretAccessKey(*form.SecretAccessKey); err != nil {
+			ctx.InternalServerError(err)
+			return
+		}
+	}
+	if form.StripComponents != nil {
+		s.StripComponents = *form.StripComponents
+	}
+	if form.Interval != nil && !parseVendorSyncInterval(ctx, s, *form.Interval) {
+		return
+	}
+	if !validateVendorSync(ctx, s) {
+		return
+	}
+
+	s.NextSyncUnix = timeutil.TimeStampNow()
+	if err := repo_model.UpdateVendorSync(ctx, s); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToVendorSync(s))
+}
+
+// DeleteVendorSync stops importing an external source into the repository
+func DeleteVendorSync(ctx *context.APIContext) {
+	// swagger:operation DELETE /repos/{owner}/{repo}/vendor_syncs/{id} repository repoDeleteVendorSync
+	// ---
+	// summary: Stop importing an external source into the repository
+	// description: The content imported so far is kept in the branch.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the vendor sync
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := repo_model.DeleteVendorSync(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id")); err != nil {
+		if repo_model.IsErrVendorSyncNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// RunVendorSync imports the content of the source of a vendor sync now
+func RunVendorSync(ctx *context.APIContext) {
+	// swagger:operation POST /repos/{owner}/{repo}/vendor_syncs/{id}/run repository repoRunVendorSync
+	// ---
+	// summary: Import the content of an external source now
+	// description: The result of the import is returned in the last status of the vendor sync.
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: owner
+	//   in: path
+	//   description: owner of the repo
+	//   type: string
+	//   required: true
+	// - name: repo
+	//   in: path
+	//   description: name of the repo
+	//   type: string
+	//   required: true
+	// - name: id
+	//   in: path
+	//   description: id of the vendor sync
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/VendorSync"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	s := getVendorSyncByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := vendorsync.RunVendorSync(ctx, s); err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToVendorSync(s))
+}
+
+func getVendorSyncByParams(ctx *context.APIContext) *repo_model.VendorSync {
+	s, err := repo_model.GetVendorSync(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if repo_model.IsErrVendorSyncNotExist(err) {
+			ctx.NotFound(err)
+		} else {
+			ctx.InternalServerError(err)
+		}
+		return nil
+	}
+	return s
+}
+
+func parseVendorSyncInterval(ctx *context.APIContext, s *repo_model.VendorSync, interval string) bool {
+	d, err := time.ParseDuration(interval)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "Interval", err)
+		return false
+	}
+	if d < vendorsync.MinInterval {
+		ctx.Error(http.StatusUnprocessableEntity, "Interval", fmt.Sprintf("interval %s is below the minimum interval %s", d, vendorsync.MinInterval))
+		return false
+	}
+	s.Interval = d
+	return true
+}
+
+// validateVendorSync checks the settings of a vendor sync, and writes the error if they are invalid
+func validateVendorSync(ctx *context.APIContext, s *repo_model.VendorSync) bool {
+	if !s.SourceType.IsValid() {
+		ctx.Error(http.StatusUnprocessableEntity, "SourceType", fmt.Sprintf("unknown source type %q", s.SourceType))
+		return false
+	}
+	if s.SourceType == repo_model.VendorSyncSourceS3 && s.Bucket == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "Bucket", "bucket is required for s3 sources")
+		return false
+	}
+	if s.StripComponents < 0 {
+		ctx.Error(http.StatusUnprocessableEntity, "StripComponents", "strip_components must not be negative")
+		return false
+	}
+
+	u, err := url.Parse(s.SourceURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		ctx.Error(http.StatusUnprocessableEntity, "SourceURL", "source_url must be an http or https URL")
+		return false
+	}
+	if err := migrations.IsMigrateURLAllowed(s.SourceURL, ctx.Doer); err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "SourceURL", err.Error())
+		return false
+	}
+
+	targetPath, ok := vendorsync.CleanTargetPath(s.TargetPath)
+	if !ok {
+		ctx.Error(http.StatusUnprocessableEntity, "TargetPath", "target_path must not contain a .git directory")
+		return false
+	}
+	s.TargetPath = targetPath
+
+	if !git.IsBranchExist(ctx, ctx.Repo.Repository.RepoPath(), s.Branch) {
+		ctx.Error(http.StatusUnprocessableEntity, "Branch", fmt.Sprintf("branch %q does not exist", s.Branch))
+		return false
+	}
+	exist, err := repo_model.ExistVendorSync(ctx, s)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return false
+	} else if exist {
+		ctx.Error(http.StatusUnprocessableEntity, "TargetPath", "another vendor sync imports into this directory of the branch")
+		return false
+	}
+	return true
+}
@@ -6,9 +6,12 @@ package settings
 import (
 	"net/http"
 
+	system_model "code.gitea.io/gitea/models/system"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/convert"
 )
 
 // GetGeneralUISettings returns instance's global settings for ui
@@ -28,6 +31,24 @@ func GetGeneralUISettings(ctx *context.APIContext) {
 	})
 }
 
+// ListThemes lists the themes managed by the site administrators
+func ListThemes(ctx *context.APIContext) {
+	// swagger:operation GET /settings/themes settings listThemes
+	// ---
+	// summary: List the themes managed by the site administrators, which users can choose besides the themes of the config
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/ThemeList"
+	themes, err := system_model.GetThemes(ctx)
+	if err != nil {
+		ctx.InternalServerError(err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToThemes(themes))
+}
+
 // GetGeneralAPISettings returns instance's global settings for api
 func GetGeneralAPISettings(ctx *context.APIContext) {
 	// swagger:operation GET /settings/api settings getGeneralAPISettings
@@ -56,6 +77,11 @@ func GetGeneralRepoSettings(ctx *context.APIContext) {
 	// responses:
 	//   "200":
 	//     "$ref": "#/responses/GeneralRepoSettings"
+	supported := git.SupportedObjectFormats()
+	objectFormats := make([]string, 0, len(supported))
+	for _, objectFormat := range supported {
+		objectFormats = append(objectFormats, objectFormat.String())
+	}
 	ctx.JSON(http.StatusOK, api.GeneralRepoSettings{
 		MirrorsDisabled:      !setting.Mirror.Enabled,
 		HTTPGitDisabled:      setting.Repository.DisableHTTPGit,
@@ -63,6 +89,7 @@ func GetGeneralRepoSettings(ctx *context.APIContext) {
 		StarsDisabled:        setting.Repository.DisableStars,
 		TimeTrackingDisabled: !setting.Service.EnableTimetracking,
 		LFSDisabled:          !setting.LFS.StartServer,
+		ObjectFormats:        objectFormats,
 	})
 }
 
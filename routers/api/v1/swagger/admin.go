@@ -0,0 +1,64 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// InstanceAccessTokenList
+// swagger:response InstanceAccessTokenList
+type swaggerResponseInstanceAccessTokenList struct {
+	// in:body
+	Body []api.InstanceAccessToken `json:"body"`
+}
+
+// RevokeAccessTokensResult
+// swagger:response RevokeAccessTokensResult
+type swaggerResponseRevokeAccessTokensResult struct {
+	// in:body
+	Body api.RevokeAccessTokensResult `json:"body"`
+}
+
+// InstanceHookList
+// swagger:response InstanceHookList
+type swaggerResponseInstanceHookList struct {
+	// in:body
+	Body []api.InstanceHook `json:"body"`
+}
+
+// StorageUsageList
+// swagger:response StorageUsageList
+type swaggerResponseStorageUsageList struct {
+	// in:body
+	Body []api.StorageUsage `json:"body"`
+}
+
+// StorageUsageTrend
+// swagger:response StorageUsageTrend
+type swaggerResponseStorageUsageTrend struct {
+	// in:body
+	Body api.StorageUsageTrend `json:"body"`
+}
+
+// MaintenanceMode
+// swagger:response MaintenanceMode
+type swaggerResponseMaintenanceMode struct {
+	// in:body
+	Body api.MaintenanceMode `json:"body"`
+}
+
+// BackgroundMigrationList
+// swagger:response BackgroundMigrationList
+type swaggerResponseBackgroundMigrationList struct {
+	// in:body
+	Body []api.BackgroundMigration `json:"body"`
+}
+
+// ConnectivityTestResult
+// swagger:response ConnectivityTestResult
+type swaggerResponseConnectivityTestResult struct {
+	// in:body
+	Body api.ConnectivityTestResult `json:"body"`
+}
@@ -0,0 +1,29 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ChatOpsCommand
+// swagger:response ChatOpsCommand
+type swaggerResponseChatOpsCommand struct {
+	// in:body
+	Body api.ChatOpsCommand `json:"body"`
+}
+
+// ChatOpsCommandList
+// swagger:response ChatOpsCommandList
+type swaggerResponseChatOpsCommandList struct {
+	// in:body
+	Body []api.ChatOpsCommand `json:"body"`
+}
+
+// ChatOpsAuditList
+// swagger:response ChatOpsAuditList
+type swaggerResponseChatOpsAuditList struct {
+	// in:body
+	Body []api.ChatOpsAudit `json:"body"`
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// DiscussionCategory
+// swagger:response DiscussionCategory
+type swaggerResponseDiscussionCategory struct {
+	// in:body
+	Body api.DiscussionCategory `json:"body"`
+}
+
+// DiscussionCategoryList
+// swagger:response DiscussionCategoryList
+type swaggerResponseDiscussionCategoryList struct {
+	// in:body
+	Body []api.DiscussionCategory `json:"body"`
+}
+
+// Discussion
+// swagger:response Discussion
+type swaggerResponseDiscussion struct {
+	// in:body
+	Body api.Discussion `json:"body"`
+}
+
+// DiscussionList
+// swagger:response DiscussionList
+type swaggerResponseDiscussionList struct {
+	// in:body
+	Body []api.Discussion `json:"body"`
+}
+
+// DiscussionComment
+// swagger:response DiscussionComment
+type swaggerResponseDiscussionComment struct {
+	// in:body
+	Body api.DiscussionComment `json:"body"`
+}
+
+// DiscussionCommentList
+// swagger:response DiscussionCommentList
+type swaggerResponseDiscussionCommentList struct {
+	// in:body
+	Body []api.DiscussionComment `json:"body"`
+}
@@ -125,3 +125,17 @@ type swaggerReactionList struct {
 	// in:body
 	Body []api.Reaction `json:"body"`
 }
+
+// IssueLockPolicy
+// swagger:response IssueLockPolicy
+type swaggerResponseIssueLockPolicy struct {
+	// in:body
+	Body api.IssueLockPolicy `json:"body"`
+}
+
+// StalePolicy
+// swagger:response StalePolicy
+type swaggerResponseStalePolicy struct {
+	// in:body
+	Body api.StalePolicy `json:"body"`
+}
@@ -48,3 +48,10 @@ type swaggerResponseDeployKeyList struct {
 	// in:body
 	Body []api.DeployKey `json:"body"`
 }
+
+// DeployKeyUsageList
+// swagger:response DeployKeyUsageList
+type swaggerResponseDeployKeyUsageList struct {
+	// in:body
+	Body []api.DeployKeyUsage `json:"body"`
+}
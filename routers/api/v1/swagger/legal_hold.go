@@ -0,0 +1,36 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// LegalHold
+// swagger:response LegalHold
+type swaggerResponseLegalHold struct {
+	// in:body
+	Body api.LegalHold `json:"body"`
+}
+
+// LegalHoldList
+// swagger:response LegalHoldList
+type swaggerResponseLegalHoldList struct {
+	// in:body
+	Body []api.LegalHold `json:"body"`
+}
+
+// LegalRedaction
+// swagger:response LegalRedaction
+type swaggerResponseLegalRedaction struct {
+	// in:body
+	Body api.LegalRedaction `json:"body"`
+}
+
+// LegalRedactionList
+// swagger:response LegalRedactionList
+type swaggerResponseLegalRedactionList struct {
+	// in:body
+	Body []api.LegalRedaction `json:"body"`
+}
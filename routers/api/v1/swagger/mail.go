@@ -0,0 +1,15 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// MailDeliveryList
+// swagger:response MailDeliveryList
+type swaggerResponseMailDeliveryList struct {
+	// in:body
+	Body []api.MailDelivery `json:"body"`
+}
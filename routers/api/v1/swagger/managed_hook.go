@@ -0,0 +1,22 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ManagedHook
+// swagger:response ManagedHook
+type swaggerResponseManagedHook struct {
+	// in:body
+	Body api.ManagedHook `json:"body"`
+}
+
+// ManagedHookList
+// swagger:response ManagedHookList
+type swaggerResponseManagedHookList struct {
+	// in:body
+	Body []api.ManagedHook `json:"body"`
+}
@@ -42,9 +42,37 @@ type swaggerResponseLicenseTemplateInfo struct {
 	Body api.LicenseTemplateInfo `json:"body"`
 }
 
+// TemplateCatalogItem
+// swagger:response TemplateCatalogItem
+type swaggerResponseTemplateCatalogItem struct {
+	// in:body
+	Body api.TemplateCatalogItem `json:"body"`
+}
+
+// TemplateCatalogItemList
+// swagger:response TemplateCatalogItemList
+type swaggerResponseTemplateCatalogItemList struct {
+	// in:body
+	Body []api.TemplateCatalogItem `json:"body"`
+}
+
 // StringSlice
 // swagger:response StringSlice
 type swaggerResponseStringSlice struct {
 	// in:body
 	Body []string `json:"body"`
 }
+
+// FederatedSearchResultList
+// swagger:response FederatedSearchResultList
+type swaggerResponseFederatedSearchResultList struct {
+	// in:body
+	Body []api.FederatedSearchResult `json:"body"`
+}
+
+// FederatedSearchResponse
+// swagger:response FederatedSearchResponse
+type swaggerResponseFederatedSearchResponse struct {
+	// in:body
+	Body api.FederatedSearchResponse `json:"body"`
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// BlockedUserList
+// swagger:response BlockedUserList
+type swaggerResponseBlockedUserList struct {
+	// in:body
+	Body []api.BlockedUser `json:"body"`
+}
+
+// AbuseReport
+// swagger:response AbuseReport
+type swaggerResponseAbuseReport struct {
+	// in:body
+	Body api.AbuseReport `json:"body"`
+}
+
+// AbuseReportList
+// swagger:response AbuseReportList
+type swaggerResponseAbuseReportList struct {
+	// in:body
+	Body []api.AbuseReport `json:"body"`
+}
+
+// InteractionLimit
+// swagger:response InteractionLimit
+type swaggerResponseInteractionLimit struct {
+	// in:body
+	Body api.InteractionLimit `json:"body"`
+}
+
+// HeldContent
+// swagger:response HeldContent
+type swaggerResponseHeldContent struct {
+	// in:body
+	Body api.HeldContent `json:"body"`
+}
+
+// HeldContentList
+// swagger:response HeldContentList
+type swaggerResponseHeldContentList struct {
+	// in:body
+	Body []api.HeldContent `json:"body"`
+}
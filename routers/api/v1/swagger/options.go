@@ -26,6 +26,8 @@ type swaggerParameterBodies struct {
 	CreateHookOption api.CreateHookOption
 	// in:body
 	EditHookOption api.EditHookOption
+	// in:body
+	RotateHookSecretOption api.RotateHookSecretOption
 
 	// in:body
 	EditGitHookOption api.EditGitHookOption
@@ -35,6 +37,8 @@ type swaggerParameterBodies struct {
 	// in:body
 	EditIssueOption api.EditIssueOption
 	// in:body
+	ImportIssuesOption api.ImportIssuesOption
+	// in:body
 	EditDeadlineOption api.EditDeadlineOption
 
 	// in:body
@@ -53,6 +57,12 @@ type swaggerParameterBodies struct {
 	// in:body
 	RenameUserOption api.RenameUserOption
 
+	// in:body
+	MergeUserOption api.MergeUserOption
+
+	// in:body
+	MigrateAuthSourceUsersOption api.MigrateAuthSourceUsersOption
+
 	// in:body
 	CreateLabelOption api.CreateLabelOption
 	// in:body
@@ -98,12 +108,27 @@ type swaggerParameterBodies struct {
 
 	// in:body
 	CreateStatusOption api.CreateStatusOption
+	// in:body
+	CreateCommitStatusAttemptOption api.CreateCommitStatusAttemptOption
+	// in:body
+	CreateFlakyCheckOption api.CreateFlakyCheckOption
+	// in:body
+	EditFlakyCheckOption api.EditFlakyCheckOption
 
 	// in:body
 	CreateTeamOption api.CreateTeamOption
 	// in:body
 	EditTeamOption api.EditTeamOption
 
+	// in:body
+	CreateTeamReminderOption api.CreateTeamReminderOption
+
+	// in:body
+	EditTeamReminderOption api.EditTeamReminderOption
+
+	// in:body
+	EditTeamHomepageOption api.EditTeamHomepageOption
+
 	// in:body
 	AddTimeOption api.AddTimeOption
 
@@ -164,6 +189,15 @@ type swaggerParameterBodies struct {
 	// in:body
 	PullReviewRequestOptions api.PullReviewRequestOptions
 
+	// in:body
+	CreateReviewChecklistItemOption api.CreateReviewChecklistItemOption
+
+	// in:body
+	EditReviewChecklistItemOption api.EditReviewChecklistItemOption
+
+	// in:body
+	CreateDownloadLinkOption api.CreateDownloadLinkOption
+
 	// in:body
 	CreateTagOption api.CreateTagOption
 
@@ -173,9 +207,183 @@ type swaggerParameterBodies struct {
 	// in:body
 	UserSettingsOptions api.UserSettingsOptions
 
+	// in:body
+	SetUserStatusOption api.SetUserStatusOption
+
+	// in:body
+	CreateLinkedIdentityOption api.CreateLinkedIdentityOption
+
 	// in:body
 	CreateWikiPageOptions api.CreateWikiPageOptions
 
 	// in:body
 	CreatePushMirrorOption api.CreatePushMirrorOption
+
+	// in:body
+	EditLicensePolicyOption api.EditLicensePolicyOption
+
+	// in:body
+	UploadSARIFOption api.UploadSARIFOption
+
+	// in:body
+	EditCodeScanningAlertOption api.EditCodeScanningAlertOption
+
+	// in:body
+	CherryPickOptions api.CherryPickOptions
+
+	// in:body
+	CreateChatOpsCommandOption api.CreateChatOpsCommandOption
+
+	// in:body
+	EditChatOpsCommandOption api.EditChatOpsCommandOption
+
+	// in:body
+	EditStalePolicyOption api.EditStalePolicyOption
+
+	// in:body
+	BlockUserOption api.BlockUserOption
+
+	// in:body
+	HideCommentOption api.HideCommentOption
+
+	// in:body
+	CreateAbuseReportOption api.CreateAbuseReportOption
+
+	// in:body
+	EditAbuseReportOption api.EditAbuseReportOption
+
+	// in:body
+	EditInteractionLimitOption api.EditInteractionLimitOption
+
+	// in:body
+	EditAvatarPolicyOption api.EditAvatarPolicyOption
+
+	// in:body
+	EditOrgBrandingOption api.EditOrgBrandingOption
+
+	// in:body
+	CreateThemeOption api.CreateThemeOption
+
+	// in:body
+	EditThemeOption api.EditThemeOption
+
+	// in:body
+	SetTranslationOverrideOption api.SetTranslationOverrideOption
+
+	// in:body
+	EditOAuth2TokenLifetimesOption api.EditOAuth2TokenLifetimesOption
+
+	// in:body
+	EditAttachmentPolicyOption api.EditAttachmentPolicyOption
+
+	// in:body
+	EditArchivePolicyOption api.EditArchivePolicyOption
+
+	// in:body
+	MergeUpstreamOption api.MergeUpstreamOption
+
+	// in:body
+	CreateForkSyncOption api.CreateForkSyncOption
+
+	// in:body
+	CreateVendorSyncOption api.CreateVendorSyncOption
+
+	// in:body
+	EditVendorSyncOption api.EditVendorSyncOption
+
+	// in:body
+	CreateManagedHookOption api.CreateManagedHookOption
+
+	// in:body
+	EditManagedHookOption api.EditManagedHookOption
+
+	// in:body
+	EditOrgStorageRegionOption api.EditOrgStorageRegionOption
+
+	// in:body
+	CreateDiscussionCategoryOption api.CreateDiscussionCategoryOption
+
+	// in:body
+	EditDiscussionCategoryOption api.EditDiscussionCategoryOption
+
+	// in:body
+	CreateDiscussionOption api.CreateDiscussionOption
+
+	// in:body
+	EditDiscussionOption api.EditDiscussionOption
+
+	// in:body
+	CreateDiscussionCommentOption api.CreateDiscussionCommentOption
+
+	// in:body
+	EditDiscussionCommentOption api.EditDiscussionCommentOption
+
+	// in:body
+	SetDiscussionAnswerOption api.SetDiscussionAnswerOption
+
+	// in:body
+	ConvertIssueToDiscussionOption api.ConvertIssueToDiscussionOption
+
+	// in:body
+	DeprecatePackageOption api.DeprecatePackageOption
+
+	// in:body
+	BatchPackageMetadataOption api.BatchPackageMetadataOption
+
+	// in:body
+	CreatePackageAdvisoryOption api.CreatePackageAdvisoryOption
+
+	// in:body
+	EditPackageAdvisoryOption api.EditPackageAdvisoryOption
+
+	// in:body
+	EnableMaintenanceModeOption api.EnableMaintenanceModeOption
+
+	// in:body
+	RepoSettings api.RepoSettings
+
+	// in:body
+	EditTeamGroupSyncOption api.EditTeamGroupSyncOption
+
+	// in:body
+	ExternalGroupMembersOption api.ExternalGroupMembersOption
+
+	// in:body
+	LockIssueOption api.LockIssueOption
+
+	// in:body
+	EditIssueLockPolicyOption api.EditIssueLockPolicyOption
+
+	// in:body
+	EditTemplateCatalogItemOption api.EditTemplateCatalogItemOption
+
+	// in:body
+	EditMirrorSyncHookOption api.EditMirrorSyncHookOption
+
+	// in:body
+	CreateCoverageReportOption api.CreateCoverageReportOption
+
+	// in:body
+	CreateEmbedTokenOption api.CreateEmbedTokenOption
+
+	// in:body
+	CreateIssueSubscriptionRuleOption api.CreateIssueSubscriptionRuleOption
+
+	// in:body
+	SendTestMailOption api.SendTestMailOption
+
+	// in:body
+	RotateGPGKeyOption api.RotateGPGKeyOption
+
+	// in:body
+	RotatePublicKeyOption api.RotatePublicKeyOption
+
+	// in:body
+	SetPublicKeyExpiryOption api.SetPublicKeyExpiryOption
+
+	// in:body
+	CreateLegalHoldOption api.CreateLegalHoldOption
+
+	// in:body
+	CreateLegalRedactionOption api.CreateLegalRedactionOption
 }
@@ -41,3 +41,101 @@ type swaggerResponseOrganizationPermissions struct {
 	// in:body
 	Body api.OrganizationPermissions `json:"body"`
 }
+
+// LicensePolicy
+// swagger:response LicensePolicy
+type swaggerResponseLicensePolicy struct {
+	// in:body
+	Body api.LicensePolicy `json:"body"`
+}
+
+// AvatarPolicy
+// swagger:response AvatarPolicy
+type swaggerResponseAvatarPolicy struct {
+	// in:body
+	Body api.AvatarPolicy `json:"body"`
+}
+
+// OrgBranding
+// swagger:response OrgBranding
+type swaggerResponseOrgBranding struct {
+	// in:body
+	Body api.OrgBranding `json:"body"`
+}
+
+// AttachmentPolicy
+// swagger:response AttachmentPolicy
+type swaggerResponseAttachmentPolicy struct {
+	// in:body
+	Body api.AttachmentPolicy `json:"body"`
+}
+
+// ArchivePolicy
+// swagger:response ArchivePolicy
+type swaggerResponseArchivePolicy struct {
+	// in:body
+	Body api.ArchivePolicy `json:"body"`
+}
+
+// UpcomingArchivalList
+// swagger:response UpcomingArchivalList
+type swaggerResponseUpcomingArchivalList struct {
+	// in:body
+	Body []api.UpcomingArchival `json:"body"`
+}
+
+// TeamReminder
+// swagger:response TeamReminder
+type swaggerResponseTeamReminder struct {
+	// in:body
+	Body api.TeamReminder `json:"body"`
+}
+
+// TeamReminderList
+// swagger:response TeamReminderList
+type swaggerResponseTeamReminderList struct {
+	// in:body
+	Body []api.TeamReminder `json:"body"`
+}
+
+// TeamGroupSync
+// swagger:response TeamGroupSync
+type swaggerResponseTeamGroupSync struct {
+	// in:body
+	Body api.TeamGroupSync `json:"body"`
+}
+
+// TeamGroupSyncLogList
+// swagger:response TeamGroupSyncLogList
+type swaggerResponseTeamGroupSyncLogList struct {
+	// in:body
+	Body []api.TeamGroupSyncLog `json:"body"`
+}
+
+// TeamHomepage
+// swagger:response TeamHomepage
+type swaggerResponseTeamHomepage struct {
+	// in:body
+	Body api.TeamHomepage `json:"body"`
+}
+
+// TeamActivitySummary
+// swagger:response TeamActivitySummary
+type swaggerResponseTeamActivitySummary struct {
+	// in:body
+	Body api.TeamActivitySummary `json:"body"`
+}
+
+// TeamMilestoneList
+// swagger:response TeamMilestoneList
+type swaggerResponseTeamMilestoneList struct {
+	// in:body
+	Body []api.TeamMilestone `json:"body"`
+}
+
+// OutsideCollaboratorList
+// swagger:response OutsideCollaboratorList
+type swaggerResponseOutsideCollaboratorList struct {
+	// in:body
+	Body []api.OutsideCollaborator `json:"body"`
+}
@@ -21,9 +21,51 @@ type swaggerResponsePackageList struct {
 	Body []api.Package `json:"body"`
 }
 
+// PackageMetadataList
+// swagger:response PackageMetadataList
+type swaggerResponsePackageMetadataList struct {
+	// in:body
+	Body []api.PackageMetadata `json:"body"`
+}
+
 // PackageFileList
 // swagger:response PackageFileList
 type swaggerResponsePackageFileList struct {
 	// in:body
 	Body []api.PackageFile `json:"body"`
 }
+
+// GoModuleVerificationList
+// swagger:response GoModuleVerificationList
+type swaggerResponseGoModuleVerificationList struct {
+	// in:body
+	Body []api.GoModuleVerification `json:"body"`
+}
+
+// PackageAdvisory
+// swagger:response PackageAdvisory
+type swaggerResponsePackageAdvisory struct {
+	// in:body
+	Body api.PackageAdvisory `json:"body"`
+}
+
+// PackageAdvisoryList
+// swagger:response PackageAdvisoryList
+type swaggerResponsePackageAdvisoryList struct {
+	// in:body
+	Body []api.PackageAdvisory `json:"body"`
+}
+
+// DeletedPackageVersion
+// swagger:response DeletedPackageVersion
+type swaggerResponseDeletedPackageVersion struct {
+	// in:body
+	Body api.DeletedPackageVersion `json:"body"`
+}
+
+// DeletedPackageVersionList
+// swagger:response DeletedPackageVersionList
+type swaggerResponseDeletedPackageVersionList struct {
+	// in:body
+	Body []api.DeletedPackageVersion `json:"body"`
+}
@@ -91,6 +91,13 @@ type swaggerResponseHook struct {
 	Body api.Hook `json:"body"`
 }
 
+// HookSecretRotation
+// swagger:response HookSecretRotation
+type swaggerResponseHookSecretRotation struct {
+	// in:body
+	Body api.HookSecretRotation `json:"body"`
+}
+
 // HookList
 // swagger:response HookList
 type swaggerResponseHookList struct {
@@ -140,6 +147,13 @@ type swaggerResponsePullRequestList struct {
 	Body []api.PullRequest `json:"body"`
 }
 
+// MergeQueue
+// swagger:response MergeQueue
+type swaggerResponseMergeQueue struct {
+	// in:body
+	Body []api.MergeQueueEntry `json:"body"`
+}
+
 // PullReview
 // swagger:response PullReview
 type swaggerResponsePullReview struct {
@@ -154,6 +168,34 @@ type swaggerResponsePullReviewList struct {
 	Body []api.PullReview `json:"body"`
 }
 
+// ReviewChecklistItem
+// swagger:response ReviewChecklistItem
+type swaggerResponseReviewChecklistItem struct {
+	// in:body
+	Body api.ReviewChecklistItem `json:"body"`
+}
+
+// ReviewChecklistItemList
+// swagger:response ReviewChecklistItemList
+type swaggerResponseReviewChecklistItemList struct {
+	// in:body
+	Body []api.ReviewChecklistItem `json:"body"`
+}
+
+// DownloadLink
+// swagger:response DownloadLink
+type swaggerResponseDownloadLink struct {
+	// in:body
+	Body api.DownloadLink `json:"body"`
+}
+
+// DownloadLinkList
+// swagger:response DownloadLinkList
+type swaggerResponseDownloadLinkList struct {
+	// in:body
+	Body []api.DownloadLink `json:"body"`
+}
+
 // PullComment
 // swagger:response PullReviewComment
 type swaggerPullReviewComment struct {
@@ -182,6 +224,41 @@ type swaggerResponseCommitStatusList struct {
 	Body []api.CommitStatus `json:"body"`
 }
 
+// CommitStatusAttempt
+// swagger:response CommitStatusAttempt
+type swaggerResponseCommitStatusAttempt struct {
+	// in:body
+	Body api.CommitStatusAttempt `json:"body"`
+}
+
+// CommitStatusAttemptList
+// swagger:response CommitStatusAttemptList
+type swaggerResponseCommitStatusAttemptList struct {
+	// in:body
+	Body []api.CommitStatusAttempt `json:"body"`
+}
+
+// FlakyCheck
+// swagger:response FlakyCheck
+type swaggerResponseFlakyCheck struct {
+	// in:body
+	Body api.FlakyCheck `json:"body"`
+}
+
+// FlakyCheckList
+// swagger:response FlakyCheckList
+type swaggerResponseFlakyCheckList struct {
+	// in:body
+	Body []api.FlakyCheck `json:"body"`
+}
+
+// FlakeRateList
+// swagger:response FlakeRateList
+type swaggerResponseFlakeRateList struct {
+	// in:body
+	Body []api.FlakeRate `json:"body"`
+}
+
 // WatchInfo
 // swagger:response WatchInfo
 type swaggerResponseWatchInfo struct {
@@ -253,6 +330,16 @@ type swaggerCommitList struct {
 	Body []api.Commit `json:"body"`
 }
 
+// CommitGraph
+// swagger:response CommitGraph
+type swaggerCommitGraph struct {
+	// Total commit count
+	Total int `json:"X-Total-Count"`
+
+	// in: body
+	Body api.CommitGraph `json:"body"`
+}
+
 // ChangedFileList
 // swagger:response ChangedFileList
 type swaggerChangedFileList struct {
@@ -289,6 +376,13 @@ type swaggerEmptyRepository struct {
 	Body api.APIError `json:"body"`
 }
 
+// CherryPickResponse
+// swagger:response CherryPickResponse
+type swaggerCherryPickResponse struct {
+	// in: body
+	Body api.CherryPickResponse `json:"body"`
+}
+
 // FileResponse
 // swagger:response FileResponse
 type swaggerFileResponse struct {
@@ -296,6 +390,13 @@ type swaggerFileResponse struct {
 	Body api.FileResponse `json:"body"`
 }
 
+// RepoReadme
+// swagger:response RepoReadme
+type swaggerRepoReadme struct {
+	// in: body
+	Body api.RepoReadme `json:"body"`
+}
+
 // ContentsResponse
 // swagger:response ContentsResponse
 type swaggerContentsResponse struct {
@@ -338,6 +439,34 @@ type swaggerLanguageStatistics struct {
 	Body map[string]int64 `json:"body"`
 }
 
+// RepoLicenseList
+// swagger:response RepoLicenseList
+type swaggerRepoLicenseList struct {
+	// in: body
+	Body []api.RepoLicense `json:"body"`
+}
+
+// CodeScanningAlert
+// swagger:response CodeScanningAlert
+type swaggerCodeScanningAlert struct {
+	// in: body
+	Body api.CodeScanningAlert `json:"body"`
+}
+
+// CodeScanningAlertList
+// swagger:response CodeScanningAlertList
+type swaggerCodeScanningAlertList struct {
+	// in: body
+	Body []api.CodeScanningAlert `json:"body"`
+}
+
+// CodeScanningAnalysisList
+// swagger:response CodeScanningAnalysisList
+type swaggerCodeScanningAnalysisList struct {
+	// in: body
+	Body []api.CodeScanningAnalysis `json:"body"`
+}
+
 // CombinedStatus
 // swagger:response CombinedStatus
 type swaggerCombinedStatus struct {
@@ -373,6 +502,13 @@ type swaggerPushMirror struct {
 	Body api.PushMirror `json:"body"`
 }
 
+// MirrorSyncHook
+// swagger:response MirrorSyncHook
+type swaggerMirrorSyncHook struct {
+	// in:body
+	Body api.MirrorSyncHook `json:"body"`
+}
+
 // PushMirrorList
 // swagger:response PushMirrorList
 type swaggerPushMirrorList struct {
@@ -400,3 +536,157 @@ type swaggerRepoIssueConfigValidation struct {
 	// in:body
 	Body api.IssueConfigValidation `json:"body"`
 }
+
+// RepoMaintenance
+// swagger:response RepoMaintenance
+type swaggerResponseRepoMaintenance struct {
+	// in:body
+	Body api.RepoMaintenance `json:"body"`
+}
+
+// RepoMaintenanceList
+// swagger:response RepoMaintenanceList
+type swaggerResponseRepoMaintenanceList struct {
+	// in:body
+	Body []api.RepoMaintenance `json:"body"`
+}
+
+// MergeUpstreamResult
+// swagger:response MergeUpstreamResult
+type swaggerResponseMergeUpstreamResult struct {
+	// in:body
+	Body api.MergeUpstreamResult `json:"body"`
+}
+
+// MergeUpstreamConflict
+// swagger:response MergeUpstreamConflict
+type swaggerResponseMergeUpstreamConflict struct {
+	// in:body
+	Body api.MergeUpstreamConflict `json:"body"`
+}
+
+// ForkSync
+// swagger:response ForkSync
+type swaggerResponseForkSync struct {
+	// in:body
+	Body api.ForkSync `json:"body"`
+}
+
+// ForkSyncList
+// swagger:response ForkSyncList
+type swaggerResponseForkSyncList struct {
+	// in:body
+	Body []api.ForkSync `json:"body"`
+}
+
+// VendorSync
+// swagger:response VendorSync
+type swaggerResponseVendorSync struct {
+	// in:body
+	Body api.VendorSync `json:"body"`
+}
+
+// VendorSyncList
+// swagger:response VendorSyncList
+type swaggerResponseVendorSyncList struct {
+	// in:body
+	Body []api.VendorSync `json:"body"`
+}
+
+// ForkNetwork
+// swagger:response ForkNetwork
+type swaggerResponseForkNetwork struct {
+	// in:body
+	Body []api.ForkNetworkEntry `json:"body"`
+}
+
+// ForkCommitMatchList
+// swagger:response ForkCommitMatchList
+type swaggerResponseForkCommitMatchList struct {
+	// in:body
+	Body []api.ForkCommitMatch `json:"body"`
+}
+
+// Compare
+// swagger:response Compare
+type swaggerResponseCompare struct {
+	// in:body
+	Body api.Compare `json:"body"`
+}
+
+// Insights
+// swagger:response Insights
+type swaggerResponseInsights struct {
+	// in:body
+	Body api.Insights `json:"body"`
+}
+
+// PathOwnershipReport
+// swagger:response PathOwnershipReport
+type swaggerResponsePathOwnershipReport struct {
+	// in:body
+	Body api.PathOwnershipReport `json:"body"`
+}
+
+// ActionLogLineList
+// swagger:response ActionLogLineList
+type swaggerResponseActionLogLineList struct {
+	// in:body
+	Body []api.ActionLogLine `json:"body"`
+}
+
+// RepoSettings
+// swagger:response RepoSettings
+type swaggerResponseRepoSettings struct {
+	// in:body
+	Body api.RepoSettings `json:"body"`
+}
+
+// RepoSettingsDriftList
+// swagger:response RepoSettingsDriftList
+type swaggerResponseRepoSettingsDriftList struct {
+	// in:body
+	Body []api.RepoSettingsDrift `json:"body"`
+}
+
+// CoverageReport
+// swagger:response CoverageReport
+type swaggerResponseCoverageReport struct {
+	// in:body
+	Body api.CoverageReport `json:"body"`
+}
+
+// BadgeToken
+// swagger:response BadgeToken
+type swaggerResponseBadgeToken struct {
+	// in:body
+	Body api.BadgeToken `json:"body"`
+}
+
+// PullRequestDiffCoverage
+// swagger:response PullRequestDiffCoverage
+type swaggerResponsePullRequestDiffCoverage struct {
+	// in:body
+	Body api.PullRequestDiffCoverage `json:"body"`
+}
+
+// EmbedToken
+// swagger:response EmbedToken
+type swaggerResponseEmbedToken struct {
+	// in:body
+	Body api.EmbedToken `json:"body"`
+}
+
+// EmbedTokenList
+// swagger:response EmbedTokenList
+type swaggerResponseEmbedTokenList struct {
+	// in:body
+	Body []api.EmbedToken `json:"body"`
+}
+
+// DeletedRepositoryList
+// swagger:response DeletedRepositoryList
+type swaggerResponseDeletedRepositoryList struct {
+	// in:body
+	Body []api.DeletedRepository `json:"body"`
+}
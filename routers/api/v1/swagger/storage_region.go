@@ -0,0 +1,15 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// OrgStorageRegion
+// swagger:response OrgStorageRegion
+type swaggerResponseOrgStorageRegion struct {
+	// in:body
+	Body api.OrgStorageRegion `json:"body"`
+}
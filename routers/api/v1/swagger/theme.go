@@ -0,0 +1,22 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// Theme
+// swagger:response Theme
+type swaggerResponseTheme struct {
+	// in:body
+	Body api.Theme `json:"body"`
+}
+
+// ThemeList
+// swagger:response ThemeList
+type swaggerResponseThemeList struct {
+	// in:body
+	Body []api.Theme `json:"body"`
+}
@@ -0,0 +1,22 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// TranslationOverrideList
+// swagger:response TranslationOverrideList
+type swaggerResponseTranslationOverrideList struct {
+	// in:body
+	Body []api.TranslationOverride `json:"body"`
+}
+
+// LocalePackResult
+// swagger:response LocalePackResult
+type swaggerResponseLocalePackResult struct {
+	// in:body
+	Body api.LocalePackResult `json:"body"`
+}
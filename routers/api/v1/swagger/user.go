@@ -22,6 +22,13 @@ type swaggerResponseUserList struct {
 	Body []api.User `json:"body"`
 }
 
+// UserRedirectList
+// swagger:response UserRedirectList
+type swaggerResponseUserRedirectList struct {
+	// in:body
+	Body []api.UserRedirect `json:"body"`
+}
+
 // EmailList
 // swagger:response EmailList
 type swaggerResponseEmailList struct {
@@ -42,9 +49,58 @@ type swaggerResponseUserHeatmapData struct {
 	Body []activities_model.UserHeatmapData `json:"body"`
 }
 
+// UserStatus
+// swagger:response UserStatus
+type swaggerResponseUserStatus struct {
+	// in:body
+	Body api.UserStatus `json:"body"`
+}
+
+// UserMergeReport
+// swagger:response UserMergeReport
+type swaggerResponseUserMergeReport struct {
+	// in:body
+	Body api.UserMergeReport `json:"body"`
+}
+
+// AuthSourceMigrationReport
+// swagger:response AuthSourceMigrationReport
+type swaggerResponseAuthSourceMigrationReport struct {
+	// in:body
+	Body api.AuthSourceMigrationReport `json:"body"`
+}
+
+// LinkedIdentity
+// swagger:response LinkedIdentity
+type swaggerResponseLinkedIdentity struct {
+	// in:body
+	Body api.LinkedIdentity `json:"body"`
+}
+
+// LinkedIdentityList
+// swagger:response LinkedIdentityList
+type swaggerResponseLinkedIdentityList struct {
+	// in:body
+	Body []api.LinkedIdentity `json:"body"`
+}
+
 // UserSettings
 // swagger:response UserSettings
 type swaggerResponseUserSettings struct {
 	// in:body
 	Body []api.UserSettings `json:"body"`
 }
+
+// IssueSubscriptionRule
+// swagger:response IssueSubscriptionRule
+type swaggerResponseIssueSubscriptionRule struct {
+	// in:body
+	Body api.IssueSubscriptionRule `json:"body"`
+}
+
+// IssueSubscriptionRuleList
+// swagger:response IssueSubscriptionRuleList
+type swaggerResponseIssueSubscriptionRuleList struct {
+	// in:body
+	Body []api.IssueSubscriptionRule `json:"body"`
+}
@@ -0,0 +1,139 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package swagger
+
+import (
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// The payloads of webhook deliveries are described as responses, which adds their schemas to the spec.
+// The X-Gitea-Event-Type header tells which payload a delivery carries. Actions workflows get the same
+// payload for the listed events. modules/webhook/events is generated from these declarations.
+
+// WebhookCreatePayload is sent when a branch or tag is created
+// Actions events: create
+// swagger:response WebhookCreatePayload
+type swaggerWebhookCreatePayload struct {
+	// enum: create
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.CreatePayload `json:"body"`
+}
+
+// WebhookDeletePayload is sent when a branch or tag is deleted
+// Actions events: delete
+// swagger:response WebhookDeletePayload
+type swaggerWebhookDeletePayload struct {
+	// enum: delete
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.DeletePayload `json:"body"`
+}
+
+// WebhookForkPayload is sent when the repository is forked
+// Actions events: fork
+// swagger:response WebhookForkPayload
+type swaggerWebhookForkPayload struct {
+	// enum: fork
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.ForkPayload `json:"body"`
+}
+
+// WebhookPushPayload is sent when commits are pushed
+// Actions events: push
+// swagger:response WebhookPushPayload
+type swaggerWebhookPushPayload struct {
+	// enum: push
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.PushPayload `json:"body"`
+}
+
+// WebhookIssuePayload is sent when an issue is changed
+// Actions events: issues
+// swagger:response WebhookIssuePayload
+type swaggerWebhookIssuePayload struct {
+	// enum: issues, issue_assign, issue_label, issue_milestone
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.IssuePayload `json:"body"`
+}
+
+// WebhookIssueCommentPayload is sent when a comment on an issue or pull request is changed
+// Actions events: issue_comment, pull_request_comment
+// swagger:response WebhookIssueCommentPayload
+type swaggerWebhookIssueCommentPayload struct {
+	// enum: issue_comment, pull_request_comment
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.IssueCommentPayload `json:"body"`
+}
+
+// WebhookPullRequestPayload is sent when a pull request or its review is changed
+// Actions events: pull_request, pull_request_target, pull_request_review, pull_request_review_comment
+// swagger:response WebhookPullRequestPayload
+type swaggerWebhookPullRequestPayload struct {
+	// enum: pull_request, pull_request_assign, pull_request_label, pull_request_milestone, pull_request_sync, pull_request_review_approved, pull_request_review_rejected, pull_request_review_comment
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.PullRequestPayload `json:"body"`
+}
+
+// WebhookWikiPayload is sent when a wiki page is changed
+// Actions events: gollum
+// swagger:response WebhookWikiPayload
+type swaggerWebhookWikiPayload struct {
+	// enum: wiki
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.WikiPayload `json:"body"`
+}
+
+// WebhookRepositoryPayload is sent when the repository is created or deleted
+// swagger:response WebhookRepositoryPayload
+type swaggerWebhookRepositoryPayload struct {
+	// enum: repository
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.RepositoryPayload `json:"body"`
+}
+
+// WebhookReleasePayload is sent when a release is changed
+// Actions events: release
+// swagger:response WebhookReleasePayload
+type swaggerWebhookReleasePayload struct {
+	// enum: release
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.ReleasePayload `json:"body"`
+}
+
+// WebhookPackagePayload is sent when a package is changed
+// Actions events: registry_package
+// swagger:response WebhookPackagePayload
+type swaggerWebhookPackagePayload struct {
+	// enum: package
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.PackagePayload `json:"body"`
+}
+
+// WebhookDiscussionPayload is sent when a discussion is changed
+// swagger:response WebhookDiscussionPayload
+type swaggerWebhookDiscussionPayload struct {
+	// enum: discussion
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.DiscussionPayload `json:"body"`
+}
+
+// WebhookDiscussionCommentPayload is sent when a comment on a discussion is changed
+// swagger:response WebhookDiscussionCommentPayload
+type swaggerWebhookDiscussionCommentPayload struct {
+	// enum: discussion_comment
+	EventType string `json:"X-Gitea-Event-Type"`
+	// in:body
+	Body api.DiscussionCommentPayload `json:"body"`
+}
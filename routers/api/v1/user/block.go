@@ -0,0 +1,146 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/api/v1/utils"
+	"code.gitea.io/gitea/services/convert"
+	"code.gitea.io/gitea/services/moderation"
+)
+
+// ListMyBlocks list the users blocked by the authenticated user
+func ListMyBlocks(ctx *context.APIContext) {
+	// swagger:operation GET /user/blocks user userListBlocks
+	// ---
+	// summary: List the users blocked by the authenticated user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: page
+	//   in: query
+	//   description: page number of results to return (1-based)
+	//   type: integer
+	// - name: limit
+	//   in: query
+	//   description: page size of results
+	//   type: integer
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/BlockedUserList"
+
+	ListBlocks(ctx, ctx.Doer)
+}
+
+// BlockUser block a user from interacting with the authenticated user
+func BlockUser(ctx *context.APIContext) {
+	// swagger:operation PUT /user/blocks/{username} user userBlockUser
+	// ---
+	// summary: Block a user from interacting with the authenticated user and their repositories
+	// consumes:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user to block
+	//   type: string
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/BlockUserOption"
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	Block(ctx, ctx.Doer)
+}
+
+// UnblockUser unblock a user blocked by the authenticated user
+func UnblockUser(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/blocks/{username} user userUnblockUser
+	// ---
+	// summary: Unblock a user blocked by the authenticated user
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user to unblock
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	Unblock(ctx, ctx.Doer)
+}
+
+// ListBlocks writes the users blocked by a user or organization
+func ListBlocks(ctx *context.APIContext, blocker *user_model.User) {
+	listOptions := utils.GetListOptions(ctx)
+	blocks, count, err := user_model.FindBlocks(ctx, user_model.FindBlockOptions{
+		ListOptions: listOptions,
+		BlockerID:   blocker.ID,
+	})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindBlocks", err)
+		return
+	}
+
+	result := make([]*api.BlockedUser, 0, len(blocks))
+	for _, block := range blocks {
+		result = append(result, convert.ToBlockedUser(ctx, block, ctx.Doer))
+	}
+	ctx.SetLinkHeader(int(count), listOptions.PageSize)
+	ctx.SetTotalCountHeader(count)
+	ctx.JSON(http.StatusOK, result)
+}
+
+// Block blocks the user from the path for a user or organization
+func Block(ctx *context.APIContext, blocker *user_model.User) {
+	form := web.GetForm(ctx).(*api.BlockUserOption)
+
+	blockee := GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := moderation.BlockUser(ctx, blocker, blockee, form.Note); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "BlockUser", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "BlockUser", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
+
+// Unblock unblocks the user from the path for a user or organization
+func Unblock(ctx *context.APIContext, blocker *user_model.User) {
+	blockee := GetUserByParams(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := user_model.UnblockUser(ctx, blocker.ID, blockee.ID); err != nil {
+		if user_model.IsErrBlockNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "UnblockUser", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,137 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"net/http"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/convert"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// ListMyDeletedRepos list the deleted repositories the authenticated user can restore
+func ListMyDeletedRepos(ctx *context.APIContext) {
+	// swagger:operation GET /user/deleted_repos user userListDeletedRepos
+	// ---
+	// summary: List the deleted repositories of the authenticated user and of the organizations they own, which can still be restored
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/DeletedRepositoryList"
+
+	deletions, err := repo_service.FindRestorableRepoDeletions(ctx, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindRestorableRepoDeletions", err)
+		return
+	}
+	apiRepos := make([]*api.DeletedRepository, 0, len(deletions))
+	for _, d := range deletions {
+		apiRepos = append(apiRepos, convert.ToDeletedRepository(ctx, d))
+	}
+	ctx.JSON(http.StatusOK, apiRepos)
+}
+
+// getRestorableRepo returns the deleted repository of the request if the authenticated user can restore it
+func getRestorableRepo(ctx *context.APIContext) *repo_model.Repository {
+	repoID := ctx.ParamsInt64(":id")
+	if _, err := repo_model.GetRepoDeletion(ctx, repoID); err != nil {
+		if repo_model.IsErrRepoDeletionNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepoDeletion", err)
+		}
+		return nil
+	}
+	repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetRepositoryByID", err)
+		}
+		return nil
+	}
+	canRestore, err := repo_service.CanRestoreRepository(ctx, ctx.Doer, repo)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "CanRestoreRepository", err)
+		return nil
+	}
+	if !canRestore {
+		ctx.NotFound()
+		return nil
+	}
+	return repo
+}
+
+// RestoreDeletedRepo restore a deleted repository
+func RestoreDeletedRepo(ctx *context.APIContext) {
+	// swagger:operation POST /user/deleted_repos/{id}/restore user userRestoreDeletedRepo
+	// ---
+	// summary: Restore a deleted repository of the authenticated user or of an organization they own
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the deleted repository
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/Repository"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	repo := getRestorableRepo(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := repo_service.RestoreRepository(ctx, repo); err != nil {
+		ctx.Error(http.StatusInternalServerError, "RestoreRepository", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToRepo(ctx, repo, perm.AccessModeOwner))
+}
+
+// PurgeDeletedRepo purge a deleted repository before its retention expires
+func PurgeDeletedRepo(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/deleted_repos/{id} user userPurgeDeletedRepo
+	// ---
+	// summary: Purge a deleted repository of the authenticated user or of an organization they own, it can't be restored anymore
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the deleted repository
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "403":
+	//     "$ref": "#/responses/forbidden"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	repo := getRestorableRepo(ctx)
+	if ctx.Written() {
+		return
+	}
+	if err := repo_service.DeleteRepository(ctx, ctx.Doer, repo, true); err != nil {
+		if issues_model.IsErrUnderLegalHold(err) {
+			ctx.Error(http.StatusForbidden, "DeleteRepository", err)
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, "DeleteRepository", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
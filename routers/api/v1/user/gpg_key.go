@@ -4,6 +4,7 @@
 package user
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
@@ -12,6 +13,7 @@ import (
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/context"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/utils"
 	"code.gitea.io/gitea/services/convert"
@@ -269,6 +271,57 @@ func DeleteGPGKey(ctx *context.APIContext) {
 	ctx.Status(http.StatusNoContent)
 }
 
+// RotateGPGKey marks a GPG key of the authenticated user as replaced by a successor key
+func RotateGPGKey(ctx *context.APIContext) {
+	// swagger:operation POST /user/gpg_keys/{id}/rotate user userCurrentRotateGPGKey
+	// ---
+	// summary: Mark a GPG key as replaced by a successor key
+	// description: Commits signed with a rotated key stay verified and are marked as signed with a since-rotated key.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of key to rotate
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RotateGPGKeyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/GPGKey"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.RotateGPGKeyOption)
+	id := ctx.ParamsInt64(":id")
+	if err := asymkey_model.RotateGPGKey(ctx, ctx.Doer.ID, id, form.SuccessorID); err != nil {
+		switch {
+		case asymkey_model.IsErrGPGKeyNotExist(err):
+			ctx.NotFound()
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Error(http.StatusUnprocessableEntity, "RotateGPGKey", err)
+		default:
+			ctx.Error(http.StatusInternalServerError, "RotateGPGKey", err)
+		}
+		return
+	}
+
+	key, err := asymkey_model.GetGPGKeyByID(id)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetGPGKeyByID", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToGPGKey(key))
+}
+
 // HandleAddGPGKeyError handle add GPGKey error
 func HandleAddGPGKeyError(ctx *context.APIContext, err error, token string) {
 	switch {
@@ -128,6 +128,43 @@ func EditHook(ctx *context.APIContext) {
 	)
 }
 
+// RotateHookSecret rotates the secret of a hook of the authenticated user
+func RotateHookSecret(ctx *context.APIContext) {
+	// swagger:operation POST /user/hooks/{id}/secret/rotate user userRotateHookSecret
+	// ---
+	// summary: Rotate the secret of a hook
+	// description: The payloads are signed with both the new and the previous secret during the overlap.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the hook
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RotateHookSecretOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/HookSecretRotation"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	utils.RotateOwnerHookSecret(
+		ctx,
+		ctx.Doer,
+		web.GetForm(ctx).(*api.RotateHookSecretOption),
+		ctx.ParamsInt64("id"),
+	)
+}
+
 // DeleteHook delete a hook of the authenticated user
 func DeleteHook(ctx *context.APIContext) {
 	// swagger:operation DELETE /user/hooks/{id} user userDeleteHook
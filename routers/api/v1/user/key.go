@@ -5,7 +5,9 @@ package user
 
 import (
 	std_ctx "context"
+	"errors"
 	"net/http"
+	"time"
 
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
 	"code.gitea.io/gitea/models/db"
@@ -14,6 +16,8 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/setting"
 	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/api/v1/repo"
 	"code.gitea.io/gitea/routers/api/v1/utils"
@@ -200,12 +204,27 @@ func CreateUserPublicKey(ctx *context.APIContext, form api.CreateKeyOption, uid
 		repo.HandleCheckKeyStringError(ctx, err)
 		return
 	}
+	var expiry timeutil.TimeStamp
+	if form.Expires != nil {
+		if !form.Expires.After(time.Now()) {
+			ctx.Error(http.StatusUnprocessableEntity, "", "the expiry date must be in the future")
+			return
+		}
+		expiry = timeutil.TimeStamp(form.Expires.Unix())
+	}
 
 	key, err := asymkey_model.AddPublicKey(uid, form.Title, content, 0)
 	if err != nil {
 		repo.HandleAddKeyError(ctx, err)
 		return
 	}
+	if expiry != 0 {
+		if err := asymkey_model.SetPublicKeyExpiry(ctx, uid, key.ID, expiry); err != nil {
+			ctx.Error(http.StatusInternalServerError, "SetPublicKeyExpiry", err)
+			return
+		}
+		key.ExpiredUnix = expiry
+	}
 	apiLink := composePublicKeysAPILink()
 	apiKey := convert.ToPublicKey(apiLink, key)
 	if ctx.Doer.IsAdmin || ctx.Doer.ID == key.OwnerID {
@@ -287,3 +306,105 @@ func DeletePublicKey(ctx *context.APIContext) {
 
 	ctx.Status(http.StatusNoContent)
 }
+
+// RotatePublicKey marks a SSH key of the authenticated user as replaced by a successor key
+func RotatePublicKey(ctx *context.APIContext) {
+	// swagger:operation POST /user/keys/{id}/rotate user userCurrentRotateKey
+	// ---
+	// summary: Mark a public key as replaced by a successor key
+	// description: Commits signed with a rotated key stay verified and are marked as signed with a since-rotated key.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of key to rotate
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/RotatePublicKeyOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PublicKey"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.RotatePublicKeyOption)
+	id := ctx.ParamsInt64(":id")
+	if err := asymkey_model.RotatePublicKey(ctx, ctx.Doer.ID, id, form.SuccessorID); err != nil {
+		handleEditPublicKeyError(ctx, err)
+		return
+	}
+	respondWithPublicKey(ctx, id)
+}
+
+// SetPublicKeyExpiry sets the date until which a SSH key of the authenticated user is used to sign commits
+func SetPublicKeyExpiry(ctx *context.APIContext) {
+	// swagger:operation PUT /user/keys/{id}/expiry user userCurrentSetKeyExpiry
+	// ---
+	// summary: Set the expiry date of a public key
+	// description: SSH keys have no expiry date of their own. The owner of a key with an expiry date is reminded to
+	//   rotate it before, the key can still be used to authenticate after the date.
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of key
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetPublicKeyExpiryOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/PublicKey"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetPublicKeyExpiryOption)
+	id := ctx.ParamsInt64(":id")
+	var expiry timeutil.TimeStamp
+	if form.Expires != nil {
+		expiry = timeutil.TimeStamp(form.Expires.Unix())
+	}
+	if err := asymkey_model.SetPublicKeyExpiry(ctx, ctx.Doer.ID, id, expiry); err != nil {
+		handleEditPublicKeyError(ctx, err)
+		return
+	}
+	respondWithPublicKey(ctx, id)
+}
+
+func handleEditPublicKeyError(ctx *context.APIContext, err error) {
+	switch {
+	case asymkey_model.IsErrKeyNotExist(err):
+		ctx.NotFound()
+	case errors.Is(err, util.ErrInvalidArgument):
+		ctx.Error(http.StatusUnprocessableEntity, "", err)
+	default:
+		ctx.InternalServerError(err)
+	}
+}
+
+func respondWithPublicKey(ctx *context.APIContext, id int64) {
+	key, err := asymkey_model.GetPublicKeyByID(id)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetPublicKeyByID", err)
+		return
+	}
+	apiKey, _ := appendPrivateInformation(ctx, convert.ToPublicKey(composePublicKeysAPILink(), key), key, ctx.Doer)
+	ctx.JSON(http.StatusOK, apiKey)
+}
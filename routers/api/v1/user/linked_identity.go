@@ -0,0 +1,159 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"errors"
+	"net/http"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+	user_service "code.gitea.io/gitea/services/user"
+)
+
+// ListMyLinkedIdentities list the accounts on other instances linked by the authenticated user
+func ListMyLinkedIdentities(ctx *context.APIContext) {
+	// swagger:operation GET /user/linked_identities user userListLinkedIdentities
+	// ---
+	// summary: List the accounts on other Gitea instances linked by the authenticated user
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/LinkedIdentityList"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	identities, err := user_model.GetLinkedIdentities(ctx, ctx.Doer.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetLinkedIdentities", err)
+		return
+	}
+
+	apiIdentities := make([]*api.LinkedIdentity, 0, len(identities))
+	for _, identity := range identities {
+		apiIdentities = append(apiIdentities, convert.ToLinkedIdentity(identity))
+	}
+	ctx.JSON(http.StatusOK, apiIdentities)
+}
+
+// CreateLinkedIdentity link an account on another instance to the authenticated user
+func CreateLinkedIdentity(ctx *context.APIContext) {
+	// swagger:operation POST /user/linked_identities user userCreateLinkedIdentity
+	// ---
+	// summary: Link an account on another Gitea instance, it is verified once the returned token is in its profile description
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateLinkedIdentityOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/LinkedIdentity"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "409":
+	//     "$ref": "#/responses/error"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateLinkedIdentityOption)
+
+	identity := &user_model.LinkedIdentity{
+		UserID:         ctx.Doer.ID,
+		InstanceURL:    form.InstanceURL,
+		RemoteUsername: form.RemoteUsername,
+	}
+	if err := user_model.CreateLinkedIdentity(ctx, identity); err != nil {
+		if user_model.IsErrLinkedIdentityAlreadyExist(err) {
+			ctx.Error(http.StatusConflict, "", err)
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateLinkedIdentity", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusCreated, convert.ToLinkedIdentity(identity))
+}
+
+// VerifyLinkedIdentity verify an account on another instance linked by the authenticated user
+func VerifyLinkedIdentity(ctx *context.APIContext) {
+	// swagger:operation POST /user/linked_identities/{id}/verify user userVerifyLinkedIdentity
+	// ---
+	// summary: Verify a linked account by checking its profile description for the token
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the linked account
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/LinkedIdentity"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	identity, err := user_model.GetLinkedIdentity(ctx, ctx.Doer.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if user_model.IsErrLinkedIdentityNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetLinkedIdentity", err)
+		}
+		return
+	}
+
+	if err := user_service.VerifyLinkedIdentity(ctx, identity); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "VerifyLinkedIdentity", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToLinkedIdentity(identity))
+}
+
+// DeleteLinkedIdentity unlink an account on another instance from the authenticated user
+func DeleteLinkedIdentity(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/linked_identities/{id} user userDeleteLinkedIdentity
+	// ---
+	// summary: Unlink an account on another Gitea instance
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the linked account
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := user_model.DeleteLinkedIdentity(ctx, ctx.Doer.ID, ctx.ParamsInt64(":id")); err != nil {
+		if user_model.IsErrLinkedIdentityNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteLinkedIdentity", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
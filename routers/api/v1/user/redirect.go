@@ -0,0 +1,59 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"net/http"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// ListMyRedirects lists the former names of the authenticated user
+func ListMyRedirects(ctx *context.APIContext) {
+	// swagger:operation GET /user/redirects user userListRedirects
+	// ---
+	// summary: List the former names of the authenticated user, which redirect to the current name
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserRedirectList"
+
+	redirects, err := user_model.GetUserRedirects(ctx, ctx.Doer.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRedirects", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToUserRedirects(redirects))
+}
+
+// DeleteMyRedirect deletes the redirect of a former name of the authenticated user
+func DeleteMyRedirect(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/redirects/{name} user userDeleteRedirect
+	// ---
+	// summary: Delete the redirect of a former name of the authenticated user, other users and organizations can take the name afterwards
+	// parameters:
+	// - name: name
+	//   in: path
+	//   description: former name
+	//   type: string
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := user_model.DeleteUserRedirectOf(ctx, ctx.Doer.ID, ctx.Params(":name")); err != nil {
+		if user_model.IsErrUserRedirectNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteUserRedirectOf", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
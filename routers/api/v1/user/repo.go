@@ -9,6 +9,7 @@ import (
 	"code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/context"
 	api "code.gitea.io/gitea/modules/structs"
@@ -36,13 +37,16 @@ func listUserRepos(ctx *context.APIContext, u *user_model.User, private bool) {
 		return
 	}
 
+	perms, err := access_model.GetUserRepoPermissions(ctx, repos, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermissions", err)
+		return
+	}
+
 	apiRepos := make([]*api.Repository, 0, len(repos))
 	for i := range repos {
-		access, err := access_model.AccessLevel(ctx, ctx.Doer, repos[i])
-		if err != nil {
-			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
-			return
-		}
+		p := perms[repos[i].ID]
+		access := p.UnitAccessMode(unit.TypeCode)
 		if ctx.IsSigned && ctx.Doer.IsAdmin || access >= perm.AccessModeRead {
 			apiRepos = append(apiRepos, convert.ToRepo(ctx, repos[i], access))
 		}
@@ -117,17 +121,16 @@ func ListMyRepos(ctx *context.APIContext) {
 		return
 	}
 
+	perms, err := access_model.GetUserRepoPermissions(ctx, repos, ctx.Doer)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetUserRepoPermissions", err)
+		return
+	}
+
 	results := make([]*api.Repository, len(repos))
 	for i, repo := range repos {
-		if err = repo.LoadOwner(ctx); err != nil {
-			ctx.Error(http.StatusInternalServerError, "LoadOwner", err)
-			return
-		}
-		accessMode, err := access_model.AccessLevel(ctx, ctx.Doer, repo)
-		if err != nil {
-			ctx.Error(http.StatusInternalServerError, "AccessLevel", err)
-		}
-		results[i] = convert.ToRepo(ctx, repo, accessMode)
+		p := perms[repo.ID]
+		results[i] = convert.ToRepo(ctx, repo, p.UnitAccessMode(unit.TypeCode))
 	}
 
 	ctx.SetLinkHeader(int(count), opts.ListOptions.PageSize)
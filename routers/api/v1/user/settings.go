@@ -72,6 +72,9 @@ func UpdateUserSettings(ctx *context.APIContext) {
 	if form.HideActivity != nil {
 		ctx.Doer.KeepActivityPrivate = *form.HideActivity
 	}
+	if form.CountPrivateActivity != nil {
+		ctx.Doer.CountPrivateActivity = *form.CountPrivateActivity
+	}
 
 	if err := user_model.UpdateUser(ctx, ctx.Doer, false); err != nil {
 		ctx.InternalServerError(err)
@@ -0,0 +1,134 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+func getStatus(ctx *context.APIContext, u *user_model.User) {
+	status, err := user_model.GetUserStatus(ctx, u.ID)
+	if err != nil {
+		if user_model.IsErrUserStatusNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetUserStatus", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToUserStatus(status))
+}
+
+// GetMyStatus get the status of the authenticated user
+func GetMyStatus(ctx *context.APIContext) {
+	// swagger:operation GET /user/status user userGetStatus
+	// ---
+	// summary: Get the status of the authenticated user
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserStatus"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	getStatus(ctx, ctx.Doer)
+}
+
+// GetStatus get the status of a user
+func GetStatus(ctx *context.APIContext) {
+	// swagger:operation GET /users/{username}/status user userGetUserStatus
+	// ---
+	// summary: Get the status of a user
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: username
+	//   in: path
+	//   description: username of the user
+	//   type: string
+	//   required: true
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserStatus"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	getStatus(ctx, ctx.ContextUser)
+}
+
+// SetMyStatus set the status of the authenticated user
+func SetMyStatus(ctx *context.APIContext) {
+	// swagger:operation PUT /user/status user userSetStatus
+	// ---
+	// summary: Set the status of the authenticated user
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/SetUserStatusOption"
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/UserStatus"
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.SetUserStatusOption)
+
+	status := &user_model.UserStatus{
+		UserID:  ctx.Doer.ID,
+		Emoji:   strings.Trim(strings.TrimSpace(form.Emoji), ":"),
+		Message: strings.TrimSpace(form.Message),
+		IsBusy:  form.Busy,
+	}
+	if form.EndsAt != nil {
+		status.EndUnix = timeutil.TimeStamp(form.EndsAt.Unix())
+	}
+	if err := user_model.SetUserStatus(ctx, status); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SetUserStatus", err)
+		}
+		return
+	}
+	if status.IsEmpty() {
+		ctx.Status(http.StatusNoContent)
+		return
+	}
+
+	getStatus(ctx, ctx.Doer)
+}
+
+// ClearMyStatus clear the status of the authenticated user
+func ClearMyStatus(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/status user userClearStatus
+	// ---
+	// summary: Clear the status of the authenticated user
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+
+	if err := user_model.ClearUserStatus(ctx, ctx.Doer.ID); err != nil {
+		ctx.Error(http.StatusInternalServerError, "ClearUserStatus", err)
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -0,0 +1,204 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// toIssueSubscriptionRules converts the rules, loading the names of their owners and repositories
+func toIssueSubscriptionRules(ctx *context.APIContext, rules []*issues_model.IssueSubscriptionRule) ([]*api.IssueSubscriptionRule, error) {
+	ownerIDs := make(container.Set[int64])
+	repoIDs := make(container.Set[int64])
+	for _, rule := range rules {
+		if rule.OwnerID != 0 {
+			ownerIDs.Add(rule.OwnerID)
+		}
+		if rule.RepoID != 0 {
+			repoIDs.Add(rule.RepoID)
+		}
+	}
+	owners, err := user_model.GetUsersByIDs(ownerIDs.Values())
+	if err != nil {
+		return nil, err
+	}
+	ownerNames := make(map[int64]string, len(owners))
+	for _, owner := range owners {
+		ownerNames[owner.ID] = owner.Name
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(repoIDs.Values())
+	if err != nil {
+		return nil, err
+	}
+
+	apiRules := make([]*api.IssueSubscriptionRule, 0, len(rules))
+	for _, rule := range rules {
+		apiRule := &api.IssueSubscriptionRule{
+			ID:      rule.ID,
+			Kind:    string(rule.Kind),
+			Value:   rule.Value,
+			Owner:   ownerNames[rule.OwnerID],
+			Created: rule.CreatedUnix.AsTime(),
+		}
+		if repo, ok := repos[rule.RepoID]; ok {
+			apiRule.Repository = repo.FullName()
+		}
+		apiRules = append(apiRules, apiRule)
+	}
+	return apiRules, nil
+}
+
+// ListMySubscriptionRules list the auto-subscription rules of the authenticated user
+func ListMySubscriptionRules(ctx *context.APIContext) {
+	// swagger:operation GET /user/subscription_rules user userListSubscriptionRules
+	// ---
+	// summary: List the rules subscribing the authenticated user to issues and pull requests
+	// produces:
+	// - application/json
+	// responses:
+	//   "200":
+	//     "$ref": "#/responses/IssueSubscriptionRuleList"
+
+	rules, err := issues_model.GetIssueSubscriptionRules(ctx, ctx.Doer.ID)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetIssueSubscriptionRules", err)
+		return
+	}
+	apiRules, err := toIssueSubscriptionRules(ctx, rules)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "toIssueSubscriptionRules", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, apiRules)
+}
+
+// CreateSubscriptionRule create an auto-subscription rule of the authenticated user
+func CreateSubscriptionRule(ctx *context.APIContext) {
+	// swagger:operation POST /user/subscription_rules user userCreateSubscriptionRule
+	// ---
+	// summary: Create a rule subscribing the authenticated user to the issues and pull requests it matches when they are created or updated
+	// consumes:
+	// - application/json
+	// produces:
+	// - application/json
+	// parameters:
+	// - name: body
+	//   in: body
+	//   schema:
+	//     "$ref": "#/definitions/CreateIssueSubscriptionRuleOption"
+	// responses:
+	//   "201":
+	//     "$ref": "#/responses/IssueSubscriptionRule"
+	//   "422":
+	//     "$ref": "#/responses/validationError"
+
+	form := web.GetForm(ctx).(*api.CreateIssueSubscriptionRuleOption)
+
+	rule := &issues_model.IssueSubscriptionRule{
+		UserID: ctx.Doer.ID,
+		Kind:   issues_model.IssueSubscriptionRuleKind(form.Kind),
+		Value:  form.Value,
+	}
+	if form.Repository != "" {
+		// unknown repositories and repositories the user can't read are treated alike
+		errRepoNotExist := util.NewInvalidArgumentErrorf("repository %q does not exist", form.Repository)
+		ownerName, repoName, ok := strings.Cut(form.Repository, "/")
+		if !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", errRepoNotExist)
+			return
+		}
+		repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ownerName, repoName)
+		if err != nil {
+			if repo_model.IsErrRepoNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", errRepoNotExist)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetRepositoryByOwnerAndName", err)
+			}
+			return
+		}
+		perm, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
+		if err != nil {
+			ctx.Error(http.StatusInternalServerError, "GetUserRepoPermission", err)
+			return
+		}
+		if !perm.CanReadIssuesOrPulls(false) && !perm.CanReadIssuesOrPulls(true) {
+			ctx.Error(http.StatusUnprocessableEntity, "", errRepoNotExist)
+			return
+		}
+		rule.RepoID = repo.ID
+	} else if form.Owner != "" {
+		errOwnerNotExist := util.NewInvalidArgumentErrorf("owner %q does not exist", form.Owner)
+		owner, err := user_model.GetUserByName(ctx, form.Owner)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				ctx.Error(http.StatusUnprocessableEntity, "", errOwnerNotExist)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "GetUserByName", err)
+			}
+			return
+		}
+		if !organization.HasOrgOrUserVisible(ctx, owner, ctx.Doer) {
+			ctx.Error(http.StatusUnprocessableEntity, "", errOwnerNotExist)
+			return
+		}
+		rule.OwnerID = owner.ID
+	}
+
+	if err := issues_model.CreateIssueSubscriptionRule(ctx, rule); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "CreateIssueSubscriptionRule", err)
+		}
+		return
+	}
+	apiRules, err := toIssueSubscriptionRules(ctx, []*issues_model.IssueSubscriptionRule{rule})
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "toIssueSubscriptionRules", err)
+		return
+	}
+	ctx.JSON(http.StatusCreated, apiRules[0])
+}
+
+// DeleteSubscriptionRule delete an auto-subscription rule of the authenticated user
+func DeleteSubscriptionRule(ctx *context.APIContext) {
+	// swagger:operation DELETE /user/subscription_rules/{id} user userDeleteSubscriptionRule
+	// ---
+	// summary: Delete a rule subscribing the authenticated user to issues and pull requests
+	// parameters:
+	// - name: id
+	//   in: path
+	//   description: id of the subscription rule
+	//   type: integer
+	//   format: int64
+	//   required: true
+	// responses:
+	//   "204":
+	//     "$ref": "#/responses/empty"
+	//   "404":
+	//     "$ref": "#/responses/notFound"
+
+	if err := issues_model.DeleteIssueSubscriptionRule(ctx, ctx.Doer.ID, ctx.ParamsInt64(":id")); err != nil {
+		if issues_model.IsErrIssueSubscriptionRuleNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteIssueSubscriptionRule", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
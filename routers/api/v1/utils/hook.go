@@ -4,9 +4,11 @@
 package utils
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"strings"
+	"time"
 
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/models/webhook"
@@ -140,6 +142,43 @@ func AddRepoHook(ctx *context.APIContext, form *api.CreateHookOption) {
 	ctx.JSON(http.StatusCreated, apiHook)
 }
 
+// SyncRepoHook replaces the settings of the repo webhook `w` by the ones of `form`, or adds the webhook if `w` is nil.
+// The secret and the client certificate of `w` are kept if `form` has none. Writes to `ctx` accordingly. Return whether successful
+func SyncRepoHook(ctx *context.APIContext, form *api.CreateHookOption, w *webhook.Webhook) bool {
+	if w == nil {
+		_, ok := addHook(ctx, form, 0, ctx.Repo.Repository.ID)
+		return ok
+	}
+	if !checkCreateHookOption(ctx, form) {
+		return false
+	}
+
+	if form.Type != w.Type && form.Type == webhook_module.SLACK {
+		if _, ok := form.Config["channel"]; !ok {
+			ctx.Error(http.StatusUnprocessableEntity, "", "Missing config option: channel")
+			return false
+		}
+	}
+
+	if secret, ok := form.Config["secret"]; ok {
+		w.Secret = secret
+	}
+	w.Type = form.Type
+	editForm := &api.EditHookOption{
+		Config:              form.Config,
+		Events:              form.Events,
+		BranchFilter:        form.BranchFilter,
+		AuthorizationHeader: form.AuthorizationHeader,
+		AllowedIPs:          &form.AllowedIPs,
+		Active:              &form.Active,
+	}
+	if form.ClientCertificate != "" || form.ClientKey != "" {
+		editForm.ClientCertificate = &form.ClientCertificate
+		editForm.ClientKey = &form.ClientKey
+	}
+	return editHook(ctx, editForm, w)
+}
+
 // toAPIHook converts the hook to its API representation.
 // If there is an error, write to `ctx` accordingly. Return (hook, ok)
 func toAPIHook(ctx *context.APIContext, repoLink string, hook *webhook.Webhook) (*api.Hook, bool) {
@@ -198,6 +237,9 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, ownerID, repoI
 				Wiki:                 util.SliceContainsString(form.Events, string(webhook_module.HookEventWiki), true),
 				Repository:           util.SliceContainsString(form.Events, string(webhook_module.HookEventRepository), true),
 				Release:              util.SliceContainsString(form.Events, string(webhook_module.HookEventRelease), true),
+				Discussion:           util.SliceContainsString(form.Events, string(webhook_module.HookEventDiscussion), true),
+				DiscussionComment:    util.SliceContainsString(form.Events, string(webhook_module.HookEventDiscussionComment), true),
+				ActionRunApproval:    util.SliceContainsString(form.Events, string(webhook_module.HookEventActionRunApproval), true),
 			},
 			BranchFilter: form.BranchFilter,
 		},
@@ -209,6 +251,9 @@ func addHook(ctx *context.APIContext, form *api.CreateHookOption, ownerID, repoI
 		ctx.Error(http.StatusInternalServerError, "SetHeaderAuthorization", err)
 		return nil, false
 	}
+	if !setHookSecurityOptions(ctx, w, &form.ClientCertificate, &form.ClientKey, &form.AllowedIPs) {
+		return nil, false
+	}
 	if w.Type == webhook_module.SLACK {
 		channel, ok := form.Config["channel"]
 		if !ok {
@@ -357,6 +402,9 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *webhook.Webh
 	w.Repository = util.SliceContainsString(form.Events, string(webhook_module.HookEventRepository), true)
 	w.Wiki = util.SliceContainsString(form.Events, string(webhook_module.HookEventWiki), true)
 	w.Release = util.SliceContainsString(form.Events, string(webhook_module.HookEventRelease), true)
+	w.Discussion = util.SliceContainsString(form.Events, string(webhook_module.HookEventDiscussion), true)
+	w.DiscussionComment = util.SliceContainsString(form.Events, string(webhook_module.HookEventDiscussionComment), true)
+	w.ActionRunApproval = util.SliceContainsString(form.Events, string(webhook_module.HookEventActionRunApproval), true)
 	w.BranchFilter = form.BranchFilter
 
 	err := w.SetHeaderAuthorization(form.AuthorizationHeader)
@@ -364,6 +412,9 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *webhook.Webh
 		ctx.Error(http.StatusInternalServerError, "SetHeaderAuthorization", err)
 		return false
 	}
+	if !setHookSecurityOptions(ctx, w, form.ClientCertificate, form.ClientKey, form.AllowedIPs) {
+		return false
+	}
 
 	// Issues
 	w.Issues = issuesHook(form.Events, "issues_only")
@@ -397,6 +448,103 @@ func editHook(ctx *context.APIContext, form *api.EditHookOption, w *webhook.Webh
 	return true
 }
 
+// setHookSecurityOptions sets the client certificate of the webhook `w` if the certificate or its key
+// is not nil, and the IP ranges it is pinned to if they are not nil. If an error occurs, write to `ctx`
+// accordingly. Return whether successful
+func setHookSecurityOptions(ctx *context.APIContext, w *webhook.Webhook, clientCertificate, clientKey, allowedIPs *string) bool {
+	if clientCertificate != nil || clientKey != nil {
+		var cert, key string
+		if clientCertificate != nil {
+			cert = *clientCertificate
+		}
+		if clientKey != nil {
+			key = *clientKey
+		}
+		if err := w.SetClientCertificate(cert, key); err != nil {
+			if errors.Is(err, util.ErrInvalidArgument) {
+				ctx.Error(http.StatusUnprocessableEntity, "", err)
+			} else {
+				ctx.Error(http.StatusInternalServerError, "SetClientCertificate", err)
+			}
+			return false
+		}
+	}
+	if allowedIPs != nil {
+		if err := w.SetAllowedIPs(*allowedIPs); err != nil {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+			return false
+		}
+	}
+	return true
+}
+
+// RotateSystemHookSecret rotates the secret of a system or default webhook. Writes to `ctx` accordingly
+func RotateSystemHookSecret(ctx *context.APIContext, form *api.RotateHookSecretOption, hookID int64) {
+	hook, err := webhook.GetSystemOrDefaultWebhook(ctx, hookID)
+	if err != nil {
+		if webhook.IsErrWebhookNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetSystemOrDefaultWebhook", err)
+		}
+		return
+	}
+	rotateHookSecret(ctx, form, hook)
+}
+
+// RotateOwnerHookSecret rotates the secret of a webhook of an user or organization. Writes to `ctx` accordingly
+func RotateOwnerHookSecret(ctx *context.APIContext, owner *user_model.User, form *api.RotateHookSecretOption, hookID int64) {
+	hook, err := GetOwnerHook(ctx, owner.ID, hookID)
+	if err != nil {
+		return
+	}
+	rotateHookSecret(ctx, form, hook)
+}
+
+// RotateRepoHookSecret rotates the secret of a repository webhook. Writes to `ctx` accordingly
+func RotateRepoHookSecret(ctx *context.APIContext, form *api.RotateHookSecretOption, hookID int64) {
+	hook, err := GetRepoHook(ctx, ctx.Repo.Repository.ID, hookID)
+	if err != nil {
+		return
+	}
+	rotateHookSecret(ctx, form, hook)
+}
+
+// rotateHookSecret replaces the secret of the webhook `w` according to `form`,
+// the current secret keeps signing the payloads during the overlap. Writes to `ctx` accordingly
+func rotateHookSecret(ctx *context.APIContext, form *api.RotateHookSecretOption, w *webhook.Webhook) {
+	overlapHours := int64(24)
+	if form.OverlapHours != nil {
+		overlapHours = *form.OverlapHours
+	}
+	if overlapHours < 0 || overlapHours > 720 {
+		ctx.Error(http.StatusUnprocessableEntity, "", "overlap_hours must be between 0 and 720")
+		return
+	}
+
+	secret := form.Secret
+	if secret == "" {
+		var err error
+		if secret, err = util.CryptoRandomString(40); err != nil {
+			ctx.Error(http.StatusInternalServerError, "CryptoRandomString", err)
+			return
+		}
+	}
+
+	w.RotateSecret(secret, time.Duration(overlapHours)*time.Hour)
+	if err := webhook.UpdateWebhookSecret(ctx, w); err != nil {
+		ctx.Error(http.StatusInternalServerError, "UpdateWebhookSecret", err)
+		return
+	}
+
+	result := &api.HookSecretRotation{Secret: w.Secret}
+	if w.ValidPreviousSecret() != "" {
+		expires := w.PreviousSecretExpiresUnix.AsTime()
+		result.PreviousSecretExpires = &expires
+	}
+	ctx.JSON(http.StatusOK, result)
+}
+
 // DeleteOwnerHook deletes the hook owned by the owner.
 func DeleteOwnerHook(ctx *context.APIContext, owner *user_model.User, hookID int64) {
 	if err := webhook.DeleteWebhookByOwnerID(owner.ID, hookID); err != nil {
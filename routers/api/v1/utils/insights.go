@@ -0,0 +1,42 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"code.gitea.io/gitea/modules/context"
+	insights_service "code.gitea.io/gitea/services/insights"
+)
+
+// GetInsights returns the insights of the time range given by the since and before parameters,
+// of a repository or of all repositories of an owner if repoID is 0. It writes the error response on failure.
+func GetInsights(ctx *context.APIContext, ownerID, repoID int64) *insights_service.Insights {
+	before, since, err := context.GetQueryBeforeSince(ctx.Context)
+	if err != nil {
+		ctx.Error(http.StatusUnprocessableEntity, "GetQueryBeforeSince", err)
+		return nil
+	}
+
+	var sinceTime, beforeTime time.Time
+	if since != 0 {
+		sinceTime = time.Unix(since, 0)
+	}
+	if before != 0 {
+		beforeTime = time.Unix(before, 0)
+	}
+
+	insights, err := insights_service.GetInsights(ctx, ownerID, repoID, sinceTime, beforeTime)
+	if err != nil {
+		if errors.Is(err, insights_service.ErrInvalidRange) {
+			ctx.Error(http.StatusUnprocessableEntity, "GetInsights", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetInsights", err)
+		}
+		return nil
+	}
+	return insights
+}
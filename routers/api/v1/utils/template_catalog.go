@@ -0,0 +1,108 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package utils
+
+import (
+	"errors"
+	"net/http"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// templateCatalogKind returns the kind of the catalog given in the path. If it's unknown, write to `ctx` accordingly
+func templateCatalogKind(ctx *context.APIContext) (repo_model.TemplateCatalogKind, bool) {
+	kind := repo_model.TemplateCatalogKind(ctx.Params(":kind"))
+	if !kind.IsValid() {
+		ctx.NotFound()
+		return "", false
+	}
+	return kind, true
+}
+
+// ListTemplateCatalogItems lists the templates of a catalog of an owner, ownerID 0 is the catalog of the instance
+func ListTemplateCatalogItems(ctx *context.APIContext, ownerID int64) {
+	kind, ok := templateCatalogKind(ctx)
+	if !ok {
+		return
+	}
+	items, err := repo_model.FindTemplateCatalogItems(ctx, ownerID, kind)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "FindTemplateCatalogItems", err)
+		return
+	}
+	apiItems := make([]*api.TemplateCatalogItem, 0, len(items))
+	for _, item := range items {
+		apiItems = append(apiItems, convert.ToTemplateCatalogItem(item))
+	}
+	ctx.JSON(http.StatusOK, apiItems)
+}
+
+// GetTemplateCatalogItem gets a template of a catalog of an owner
+func GetTemplateCatalogItem(ctx *context.APIContext, ownerID int64) {
+	kind, ok := templateCatalogKind(ctx)
+	if !ok {
+		return
+	}
+	item, err := repo_model.GetTemplateCatalogItem(ctx, ownerID, kind, ctx.Params(":name"))
+	if err != nil {
+		if repo_model.IsErrTemplateCatalogItemNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "GetTemplateCatalogItem", err)
+		}
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTemplateCatalogItem(item))
+}
+
+// EditTemplateCatalogItem adds a template to a catalog of an owner or replaces it
+func EditTemplateCatalogItem(ctx *context.APIContext, ownerID int64) {
+	kind, ok := templateCatalogKind(ctx)
+	if !ok {
+		return
+	}
+	form := web.GetForm(ctx).(*api.EditTemplateCatalogItemOption)
+	item := &repo_model.TemplateCatalogItem{
+		OwnerID: ownerID,
+		Kind:    kind,
+		Name:    ctx.Params(":name"),
+		Content: form.Content,
+	}
+	if err := repo_model.SaveTemplateCatalogItem(ctx, item); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusUnprocessableEntity, "", err)
+		} else {
+			ctx.Error(http.StatusInternalServerError, "SaveTemplateCatalogItem", err)
+		}
+		return
+	}
+	item, err := repo_model.GetTemplateCatalogItem(ctx, ownerID, kind, item.Name)
+	if err != nil {
+		ctx.Error(http.StatusInternalServerError, "GetTemplateCatalogItem", err)
+		return
+	}
+	ctx.JSON(http.StatusOK, convert.ToTemplateCatalogItem(item))
+}
+
+// DeleteTemplateCatalogItem deletes a template of a catalog of an owner
+func DeleteTemplateCatalogItem(ctx *context.APIContext, ownerID int64) {
+	kind, ok := templateCatalogKind(ctx)
+	if !ok {
+		return
+	}
+	if err := repo_model.DeleteTemplateCatalogItem(ctx, ownerID, kind, ctx.Params(":name")); err != nil {
+		if repo_model.IsErrTemplateCatalogItemNotExist(err) {
+			ctx.NotFound()
+		} else {
+			ctx.Error(http.StatusInternalServerError, "DeleteTemplateCatalogItem", err)
+		}
+		return
+	}
+	ctx.Status(http.StatusNoContent)
+}
@@ -50,6 +50,12 @@ func migrateWithSetting(x *xorm.Engine) error {
 		// execute migrations when the database isn't initialized even if AutoMigration is false
 		return migrations.Migrate(x)
 	} else if expected := migrations.ExpectedVersion(); current != expected {
+		if compatible, err := migrations.IsSchemaCompatible(x); err != nil {
+			return err
+		} else if compatible {
+			log.Warn("Database version %d is newer than the expected version %d, but compatible with this release", current, expected)
+			return nil
+		}
 		log.Fatal(`"database.AUTO_MIGRATION" is disabled, but current database version %d is not equal to the expected version %d.`+
 			`You can set "database.AUTO_MIGRATION" to true or migrate manually by running "gitea [--config /path/to/app.ini] migrate"`, current, expected)
 	}
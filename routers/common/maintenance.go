@@ -0,0 +1,67 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"net/http"
+	"regexp"
+	"strings"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/json"
+)
+
+var (
+	// maintenanceAllowedPaths are the paths of the requests which are allowed in maintenance mode although they don't use a safe method
+	maintenanceAllowedPaths = []string{
+		"/api/v1/admin/maintenance_mode",
+		"/user/login",
+		"/user/logout",
+		"/user/two_factor",
+		"/user/webauthn/",
+		"/login/oauth/access_token",
+	}
+	// maintenanceAllowedPathsRe matches the read-only requests which use the POST method: git fetches, LFS batch requests and markup previews
+	maintenanceAllowedPathsRe = regexp.MustCompile(`^/[^/]+/[^/]+/(?:git-upload-pack|info/lfs/objects/batch)$|/(?:markup|markdown|markdown/raw)$`)
+)
+
+func isMaintenanceAllowedRequest(req *http.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	if strings.HasPrefix(req.URL.Path, "/api/internal/") {
+		// the internal API is used by the manager commands, pushes over SSH are rejected by the serv command
+		return true
+	}
+	for _, p := range maintenanceAllowedPaths {
+		if req.URL.Path == p || (strings.HasSuffix(p, "/") && strings.HasPrefix(req.URL.Path, p)) {
+			return true
+		}
+	}
+	return maintenanceAllowedPathsRe.MatchString(req.URL.Path)
+}
+
+// MaintenanceModeMiddleware rejects the requests which could change data while the read-only maintenance mode is enabled
+func MaintenanceModeMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
+		if isMaintenanceAllowedRequest(req) {
+			next.ServeHTTP(resp, req)
+			return
+		}
+		mode := system_model.GetMaintenanceMode(req.Context())
+		if !mode.Enabled {
+			next.ServeHTTP(resp, req)
+			return
+		}
+
+		if strings.HasPrefix(req.URL.Path, "/api/") || strings.HasPrefix(req.URL.Path, "/v2/") {
+			resp.Header().Set("Content-Type", "application/json;charset=utf-8")
+			resp.WriteHeader(http.StatusServiceUnavailable)
+			_ = json.NewEncoder(resp).Encode(map[string]string{"message": mode.Message})
+			return
+		}
+		http.Error(resp, mode.Message, http.StatusServiceUnavailable)
+	})
+}
@@ -0,0 +1,42 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package common
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsMaintenanceAllowedRequest(t *testing.T) {
+	cases := []struct {
+		method  string
+		path    string
+		allowed bool
+	}{
+		{http.MethodGet, "/user/repo/src/branch/main", true},
+		{http.MethodHead, "/api/v1/repos/user/repo", true},
+		{http.MethodPost, "/user/repo.git/git-upload-pack", true},
+		{http.MethodPost, "/user/repo.git/git-receive-pack", false},
+		{http.MethodPost, "/user/repo.git/info/lfs/objects/batch", true},
+		{http.MethodPut, "/user/repo.git/info/lfs/objects/abc", false},
+		{http.MethodPost, "/api/v1/markdown", true},
+		{http.MethodPost, "/user/repo/markup", true},
+		{http.MethodPost, "/user/login", true},
+		{http.MethodPost, "/user/webauthn/assertion", true},
+		{http.MethodPost, "/user/sign_up", false},
+		{http.MethodPut, "/api/v1/admin/maintenance_mode", true},
+		{http.MethodDelete, "/api/v1/admin/maintenance_mode", true},
+		{http.MethodPost, "/api/internal/manager/maintenance/disable", true},
+		{http.MethodPost, "/api/v1/user/repos", false},
+		{http.MethodPut, "/api/packages/user/generic/pkg/1.0/file.bin", false},
+		{http.MethodPatch, "/user/repo/issues/1/title", false},
+	}
+	for _, c := range cases {
+		req := httptest.NewRequest(c.method, c.path, nil)
+		assert.Equal(t, c.allowed, isMaintenanceAllowedRequest(req), "%s %s", c.method, c.path)
+	}
+}
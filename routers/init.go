@@ -10,11 +10,14 @@ import (
 
 	"code.gitea.io/gitea/models"
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	"code.gitea.io/gitea/models/migrations"
 	"code.gitea.io/gitea/modules/cache"
 	"code.gitea.io/gitea/modules/eventsource"
 	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/highlight"
 	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	discussion_indexer "code.gitea.io/gitea/modules/indexer/discussions"
 	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
 	stats_indexer "code.gitea.io/gitea/modules/indexer/stats"
 	"code.gitea.io/gitea/modules/log"
@@ -40,16 +43,23 @@ import (
 	"code.gitea.io/gitea/services/auth"
 	"code.gitea.io/gitea/services/auth/source/oauth2"
 	"code.gitea.io/gitea/services/automerge"
+	"code.gitea.io/gitea/services/backport"
+	"code.gitea.io/gitea/services/chatops"
 	"code.gitea.io/gitea/services/cron"
+	issue_service "code.gitea.io/gitea/services/issue"
 	"code.gitea.io/gitea/services/mailer"
 	mailer_incoming "code.gitea.io/gitea/services/mailer/incoming"
 	markup_service "code.gitea.io/gitea/services/markup"
+	"code.gitea.io/gitea/services/mergequeue"
 	repo_migrations "code.gitea.io/gitea/services/migrations"
 	mirror_service "code.gitea.io/gitea/services/mirror"
+	org_service "code.gitea.io/gitea/services/org"
 	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
 	"code.gitea.io/gitea/services/repository/archiver"
+	"code.gitea.io/gitea/services/stale"
 	"code.gitea.io/gitea/services/task"
+	translation_service "code.gitea.io/gitea/services/translation"
 	"code.gitea.io/gitea/services/webhook"
 )
 
@@ -144,10 +154,13 @@ func GlobalInitInstalled(ctx context.Context) {
 	mustInit(oauth2.Init)
 
 	mustInitCtx(ctx, models.Init)
+	mustInitCtx(ctx, translation_service.Init)
 	mustInit(repo_service.Init)
+	go graceful.GetManager().RunWithShutdownContext(migrations.RunBackgroundMigrations)
 
 	// Booting long running goroutines.
 	issue_indexer.InitIssueIndexer(false)
+	discussion_indexer.InitDiscussionIndexer(false)
 	code_indexer.Init()
 	mustInit(stats_indexer.Init)
 
@@ -155,6 +168,12 @@ func GlobalInitInstalled(ctx context.Context) {
 	mustInit(webhook.Init)
 	mustInit(pull_service.Init)
 	mustInit(automerge.Init)
+	mustInit(mergequeue.Init)
+	mustInit(backport.Init)
+	mustInit(chatops.Init)
+	mustInit(org_service.InitTeamMentions)
+	mustInit(issue_service.InitSubscriptionRules)
+	mustInit(stale.Init)
 	mustInit(task.Init)
 	mustInit(repo_migrations.Init)
 	eventsource.GetManager().Init()
@@ -178,6 +197,7 @@ func NormalRoutes(ctx context.Context) *web.Route {
 	_ = templates.HTMLRenderer()
 	r := web.NewRoute()
 	r.Use(common.ProtocolMiddlewares()...)
+	r.Use(common.MaintenanceModeMiddleware)
 
 	r.Mount("/", web_routers.Routes(ctx))
 	r.Mount("/api/v1", apiv1.Routes(ctx))
@@ -199,6 +219,9 @@ func NormalRoutes(ctx context.Context) *web.Route {
 		// TODO: this prefix should be generated with a token string with runner ?
 		prefix = "/api/actions_pipeline"
 		r.Mount(prefix, actions_router.ArtifactsRoutes(ctx, prefix))
+
+		// The artifact service of the v4 protocol, its clients call it at ACTIONS_RESULTS_URL which is the root url
+		r.Mount(actions_router.ArtifactV4RouteBase, actions_router.ArtifactsV4Routes(ctx, actions_router.ArtifactV4RouteBase))
 	}
 
 	return r
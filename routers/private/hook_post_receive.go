@@ -9,6 +9,7 @@ import (
 	"strconv"
 	"strings"
 
+	githook_model "code.gitea.io/gitea/models/githook"
 	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
 	gitea_context "code.gitea.io/gitea/modules/context"
@@ -90,6 +91,21 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 		}
 	}
 
+	if setting.Git.ManagedHooks.Enabled {
+		if repo == nil {
+			repo = loadRepository(ctx, ownerName, repoName)
+			if ctx.Written() {
+				// Error handled in loadRepository
+				return
+			}
+			wasEmpty = repo.IsEmpty
+		}
+		// failing post-receive hooks don't fail the push
+		if err := repo_service.RunManagedHooks(ctx, repo, githook_model.HookTypePostReceive, opts); err != nil {
+			log.Error("Unable to run the managed post-receive hooks of %-v: %v", repo, err)
+		}
+	}
+
 	// Handle Push Options
 	if len(opts.GitPushOptions) > 0 {
 		// load the repository
@@ -163,7 +179,7 @@ func HookPostReceive(ctx *gitea_context.PrivateContext) {
 		branch := git.RefEndName(opts.RefFullNames[i])
 
 		// If we've pushed a branch (and not deleted it)
-		if newCommitID != git.EmptySHA && strings.HasPrefix(refFullName, git.BranchPrefix) {
+		if !git.IsEmptyCommitID(newCommitID) && strings.HasPrefix(refFullName, git.BranchPrefix) {
 
 			// First ensure we have the repository loaded, we're allowed pulls requests and we can get the base repo
 			if repo == nil {
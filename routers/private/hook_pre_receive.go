@@ -12,9 +12,11 @@ import (
 	"code.gitea.io/gitea/models"
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
 	git_model "code.gitea.io/gitea/models/git"
+	githook_model "code.gitea.io/gitea/models/githook"
 	issues_model "code.gitea.io/gitea/models/issues"
 	perm_model "code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	gitea_context "code.gitea.io/gitea/modules/context"
@@ -22,7 +24,9 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/private"
 	"code.gitea.io/gitea/modules/web"
+	org_service "code.gitea.io/gitea/services/org"
 	pull_service "code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
 )
 
 type preReceiveContext struct {
@@ -32,6 +36,7 @@ type preReceiveContext struct {
 	loadedPusher        bool
 	user                *user_model.User // it's the org user if a DeployKey is used
 	userPerm            access_model.Permission
+	deployKey           *asymkey_model.DeployKey
 	deployKeyAccessMode perm_model.AccessMode
 
 	canCreatePullRequest        bool
@@ -102,10 +107,36 @@ func (ctx *preReceiveContext) AssertCreatePullRequest() bool {
 	return true
 }
 
+// AssertDeployKeyCanPushRef returns true if the pusher isn't a deploy key or the ref patterns of the deploy key allow pushing to the ref
+func (ctx *preReceiveContext) AssertDeployKeyCanPushRef(refFullName string) bool {
+	if ctx.opts.DeployKeyID == 0 {
+		return true
+	}
+	if !ctx.loadPusherAndPermission() {
+		return false
+	}
+	if !ctx.deployKey.CanPushRef(refFullName) {
+		log.Warn("Forbidden: Deploy key %d is not allowed to push to %s in %-v", ctx.deployKey.ID, refFullName, ctx.Repo.Repository)
+		ctx.JSON(http.StatusForbidden, private.Response{
+			UserMsg: fmt.Sprintf("Deploy key %s is not allowed to push to %s", ctx.deployKey.Name, refFullName),
+		})
+		return false
+	}
+	return true
+}
+
 // HookPreReceive checks whether a individual commit is acceptable
 func HookPreReceive(ctx *gitea_context.PrivateContext) {
 	opts := web.GetForm(ctx).(*private.HookOptions)
 
+	// the repository must not change while it is moved to another storage region
+	if org_service.IsMovingStorageRegion(ctx.Repo.Repository.OwnerID) {
+		ctx.JSON(http.StatusForbidden, private.Response{
+			UserMsg: "The repository is being moved to another storage region, please try again later.",
+		})
+		return
+	}
+
 	ourCtx := &preReceiveContext{
 		PrivateContext: ctx,
 		env:            generateGitEnv(opts), // Generate git environment for checking commits
@@ -118,6 +149,10 @@ func HookPreReceive(ctx *gitea_context.PrivateContext) {
 		newCommitID := opts.NewCommitIDs[i]
 		refFullName := opts.RefFullNames[i]
 
+		if !ourCtx.AssertDeployKeyCanPushRef(refFullName) {
+			return
+		}
+
 		switch {
 		case strings.HasPrefix(refFullName, git.BranchPrefix):
 			preReceiveBranch(ourCtx, oldCommitID, newCommitID, refFullName)
@@ -125,6 +160,10 @@ func HookPreReceive(ctx *gitea_context.PrivateContext) {
 			preReceiveTag(ourCtx, oldCommitID, newCommitID, refFullName)
 		case git.SupportProcReceive && strings.HasPrefix(refFullName, git.PullRequestPrefix):
 			preReceivePullRequest(ourCtx, oldCommitID, newCommitID, refFullName)
+		case strings.HasPrefix(refFullName, repo_model.EditSessionRefPrefix):
+			ctx.JSON(http.StatusForbidden, private.Response{
+				UserMsg: fmt.Sprintf("%s belongs to an editing session of the web editor and can't be pushed to", refFullName),
+			})
 		default:
 			ourCtx.AssertCanWriteCode()
 		}
@@ -133,6 +172,20 @@ func HookPreReceive(ctx *gitea_context.PrivateContext) {
 		}
 	}
 
+	if err := repo_service.RunManagedHooks(ctx, ctx.Repo.Repository, githook_model.HookTypePreReceive, opts); err != nil {
+		if repo_service.IsErrManagedHookFailed(err) {
+			ctx.JSON(http.StatusForbidden, private.Response{
+				UserMsg: err.Error(),
+			})
+			return
+		}
+		log.Error("Unable to run the managed pre-receive hooks of %-v: %v", ctx.Repo.Repository, err)
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err: fmt.Sprintf("Unable to run the managed pre-receive hooks: %v", err),
+		})
+		return
+	}
+
 	ctx.PlainText(http.StatusOK, "ok")
 }
 
@@ -147,7 +200,7 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID, refFullN
 	repo := ctx.Repo.Repository
 	gitRepo := ctx.Repo.GitRepo
 
-	if branchName == repo.DefaultBranch && newCommitID == git.EmptySHA {
+	if branchName == repo.DefaultBranch && git.IsEmptyCommitID(newCommitID) {
 		log.Warn("Forbidden: Branch: %s is the default branch in %-v and cannot be deleted", branchName, repo)
 		ctx.JSON(http.StatusForbidden, private.Response{
 			UserMsg: fmt.Sprintf("branch %s is the default branch and cannot be deleted", branchName),
@@ -175,7 +228,7 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID, refFullN
 	// First of all we need to enforce absolutely:
 	//
 	// 1. Detect and prevent deletion of the branch
-	if newCommitID == git.EmptySHA {
+	if git.IsEmptyCommitID(newCommitID) {
 		log.Warn("Forbidden: Branch: %s in %-v is protected from deletion", branchName, repo)
 		ctx.JSON(http.StatusForbidden, private.Response{
 			UserMsg: fmt.Sprintf("branch %s is protected from deletion", branchName),
@@ -184,7 +237,7 @@ func preReceiveBranch(ctx *preReceiveContext, oldCommitID, newCommitID, refFullN
 	}
 
 	// 2. Disallow force pushes to protected branches
-	if git.EmptySHA != oldCommitID {
+	if !git.IsEmptyCommitID(oldCommitID) {
 		output, _, err := git.NewCommand(ctx, "rev-list", "--max-count=1").AddDynamicArguments(oldCommitID, "^"+newCommitID).RunStdString(&git.RunOpts{Dir: repo.RepoPath(), Env: ctx.env})
 		if err != nil {
 			log.Error("Unable to detect force push between: %s and %s in %-v Error: %v", oldCommitID, newCommitID, repo, err)
@@ -516,6 +569,7 @@ func (ctx *preReceiveContext) loadPusherAndPermission() bool {
 			})
 			return false
 		}
+		ctx.deployKey = deployKey
 		ctx.deployKeyAccessMode = deployKey.Mode
 	}
 
@@ -0,0 +1,120 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package private
+
+import (
+	"fmt"
+	"io/fs"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/container"
+	gitea_context "code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/web"
+)
+
+// HookCheckPushLimits checks whether a push as a whole is within the push limits. It is called once per push
+// with all the ref updates, before they are checked one by one by HookPreReceive.
+func HookCheckPushLimits(ctx *gitea_context.PrivateContext) {
+	opts := web.GetForm(ctx).(*private.HookOptions)
+	limits := setting.Repository.PushLimits
+
+	if limits.MaxPushSize > 0 && opts.GitQuarantinePath != "" {
+		size, err := quarantineSize(opts.GitQuarantinePath)
+		if err != nil {
+			log.Error("Unable to determine the size of the push to %-v: %v", ctx.Repo.Repository, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: fmt.Sprintf("Unable to determine the size of the push: %v", err),
+			})
+			return
+		}
+		if size > limits.MaxPushSize {
+			ctx.JSON(http.StatusForbidden, private.Response{
+				UserMsg: fmt.Sprintf("Push size limit exceeded: the push is %s, the maximum push size is %s.", base.FileSize(size), base.FileSize(limits.MaxPushSize)),
+			})
+			return
+		}
+	}
+
+	if limits.MaxNewRefs > 0 {
+		newRefs := 0
+		for i := range opts.OldCommitIDs {
+			if git.IsEmptyCommitID(opts.OldCommitIDs[i]) && !git.IsEmptyCommitID(opts.NewCommitIDs[i]) {
+				newRefs++
+			}
+		}
+		if newRefs > limits.MaxNewRefs {
+			ctx.JSON(http.StatusForbidden, private.Response{
+				UserMsg: fmt.Sprintf("New reference limit exceeded: the push creates %d references, at most %d can be created by a push.", newRefs, limits.MaxNewRefs),
+			})
+			return
+		}
+	}
+
+	if limits.MaxCommitsPerPush > 0 {
+		commits, err := countPushedCommits(ctx, generateGitEnv(opts), opts.NewCommitIDs)
+		if err != nil {
+			log.Error("Unable to count the commits of the push to %-v: %v", ctx.Repo.Repository, err)
+			ctx.JSON(http.StatusInternalServerError, private.Response{
+				Err: fmt.Sprintf("Unable to count the commits of the push: %v", err),
+			})
+			return
+		}
+		if commits > limits.MaxCommitsPerPush {
+			ctx.JSON(http.StatusForbidden, private.Response{
+				UserMsg: fmt.Sprintf("Commit limit exceeded: the push adds %d commits, at most %d commits can be added by a push.", commits, limits.MaxCommitsPerPush),
+			})
+			return
+		}
+	}
+
+	ctx.PlainText(http.StatusOK, "ok")
+}
+
+// quarantineSize returns the size of the objects received by a push, git keeps them in the quarantine
+// directory until the pre-receive hook accepted the push
+func quarantineSize(quarantinePath string) (int64, error) {
+	var size int64
+	err := filepath.WalkDir(quarantinePath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err
+}
+
+// countPushedCommits returns the number of commits reachable from the new commits which are not in the repository yet
+func countPushedCommits(ctx *gitea_context.PrivateContext, env, newCommitIDs []string) (int, error) {
+	commitIDs := make(container.Set[string], len(newCommitIDs))
+	for _, commitID := range newCommitIDs {
+		if !git.IsEmptyCommitID(commitID) {
+			commitIDs.Add(commitID)
+		}
+	}
+	if len(commitIDs) == 0 {
+		return 0, nil
+	}
+
+	stdout, _, err := git.NewCommand(ctx, "rev-list", "--count").
+		AddDynamicArguments(commitIDs.Values()...).
+		AddArguments("--not", "--all").
+		RunStdString(&git.RunOpts{Dir: ctx.Repo.Repository.RepoPath(), Env: env})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(strings.TrimSpace(stdout))
+}
@@ -0,0 +1,50 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package private
+
+import (
+	"fmt"
+	"net/http"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/web"
+	maintenance_service "code.gitea.io/gitea/services/maintenance"
+)
+
+// MaintenanceModeStatus returns the state of the maintenance mode
+func MaintenanceModeStatus(ctx *context.PrivateContext) {
+	mode := system_model.GetMaintenanceMode(ctx)
+	if !mode.Enabled {
+		ctx.JSON(http.StatusOK, private.Response{UserMsg: "Maintenance mode is disabled"})
+		return
+	}
+	ctx.JSON(http.StatusOK, private.Response{UserMsg: fmt.Sprintf("Maintenance mode is enabled: %s", mode.Message)})
+}
+
+// EnableMaintenanceMode enables the maintenance mode
+func EnableMaintenanceMode(ctx *context.PrivateContext) {
+	opts := web.GetForm(ctx).(*private.MaintenanceOptions)
+	if err := maintenance_service.Enable(ctx, opts.Message, opts.DrainTimeout); err != nil {
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err:     err.Error(),
+			UserMsg: err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, private.Response{})
+}
+
+// DisableMaintenanceMode disables the maintenance mode
+func DisableMaintenanceMode(ctx *context.PrivateContext) {
+	if err := maintenance_service.Disable(ctx); err != nil {
+		ctx.JSON(http.StatusInternalServerError, private.Response{
+			Err:     err.Error(),
+			UserMsg: err.Error(),
+		})
+		return
+	}
+	ctx.JSON(http.StatusOK, private.Response{})
+}
@@ -6,12 +6,14 @@ package private
 import (
 	"fmt"
 	"net/http"
+	"path/filepath"
 	"strings"
 
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
 	"code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
+	system_model "code.gitea.io/gitea/models/system"
 	"code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/context"
@@ -95,6 +97,16 @@ func ServCommand(ctx *context.PrivateContext) {
 		modeString = "write to"
 	}
 
+	// Don't allow pushing in maintenance mode
+	if mode > perm.AccessModeRead {
+		if maintenance := system_model.GetMaintenanceMode(ctx); maintenance.Enabled {
+			ctx.JSON(http.StatusServiceUnavailable, private.Response{
+				UserMsg: maintenance.Message,
+			})
+			return
+		}
+	}
+
 	// The default unit we're trying to look at is code
 	unitType := unit.TypeCode
 
@@ -108,6 +120,15 @@ func ServCommand(ctx *context.PrivateContext) {
 	}
 
 	owner, err := user_model.GetUserByName(ctx, results.OwnerName)
+	if user_model.IsErrUserNotExist(err) {
+		// The owner may have been renamed, follow the redirect of its former name
+		if redirectUserID, err2 := user_model.LookupUserRedirect(results.OwnerName); err2 == nil {
+			if owner, err = user_model.GetUserByID(ctx, redirectUserID); err == nil {
+				results.OwnerName = owner.Name
+				ownerName = owner.Name
+			}
+		}
+	}
 	if err != nil {
 		if user_model.IsErrUserNotExist(err) {
 			// User is fetching/cloning a non-existent repository
@@ -155,6 +176,8 @@ func ServCommand(ctx *context.PrivateContext) {
 		}
 	}
 
+	results.RepoRootPath = filepath.Dir(user_model.UserPath(owner.Name))
+
 	if repoExist {
 		repo.Owner = owner
 		repo.OwnerName = ownerName
@@ -174,6 +197,14 @@ func ServCommand(ctx *context.PrivateContext) {
 			return
 		}
 
+		// deleted repositories are hidden until they are restored or purged
+		if repo.IsPendingDeletion() {
+			ctx.JSON(http.StatusNotFound, private.Response{
+				UserMsg: fmt.Sprintf("Cannot find repository: %s/%s", results.OwnerName, results.RepoName),
+			})
+			return
+		}
+
 		// We can shortcut at this point if the repo is a mirror
 		if mode > perm.AccessModeRead && repo.IsMirror {
 			ctx.JSON(http.StatusForbidden, private.Response{
@@ -234,6 +265,14 @@ func ServCommand(ctx *context.PrivateContext) {
 		results.DeployKeyID = deployKey.ID
 		results.KeyName = deployKey.Name
 
+		if deployKey.ArchiveOnly && !isArchiveOnlyRequest(ctx.FormStrings("verb")) {
+			log.Warn("Failed authentication attempt with deploy key %s (only allowed to download archives of %s/%s) from %s", deployKey.Name, ownerName, repoName, ctx.RemoteAddr())
+			ctx.JSON(http.StatusUnauthorized, private.Response{
+				UserMsg: fmt.Sprintf("Deploy Key: %d:%s may only download archives of %s/%s with git archive --remote.", key.ID, key.Name, results.OwnerName, results.RepoName),
+			})
+			return
+		}
+
 		// FIXME: Deploy keys aren't really the owner of the repo pushing changes
 		// however we don't have good way of representing deploy keys in hook.go
 		// so for now use the owner of the repository
@@ -319,6 +358,15 @@ func ServCommand(ctx *context.PrivateContext) {
 				})
 				return
 			}
+
+			// git can only transfer complete trees, partial clone filters are chosen by the client and can not limit the paths
+			if unitType == unit.TypeCode && perm.IsCodePathRestricted() {
+				log.Warn("Failed authentication attempt for %s with key %s (only allowed to read some paths of %s/%s) from %s", user.Name, key.Name, ownerName, repoName, ctx.RemoteAddr())
+				ctx.JSON(http.StatusUnauthorized, private.Response{
+					UserMsg: fmt.Sprintf("User: %d:%s with Key: %d:%s may only read some paths of %s/%s, use the web interface or the API instead.", user.ID, user.Name, key.ID, key.Name, ownerName, repoName),
+				})
+				return
+			}
 		}
 	}
 
@@ -392,6 +440,27 @@ func ServCommand(ctx *context.PrivateContext) {
 		results.RepoName,
 		results.RepoID)
 
+	if deployKey != nil {
+		if err := asymkey_model.RecordDeployKeyUsage(ctx, deployKey, mode, strings.Join(ctx.FormStrings("verb"), " "), ctx.RemoteAddr()); err != nil {
+			log.Error("Unable to record the usage of deploy key %d in %-v: %v", deployKey.ID, repo, err)
+		}
+	}
+
 	ctx.JSON(http.StatusOK, results)
 	// We will update the keys in a different call.
 }
+
+// isArchiveOnlyRequest checks if the only git command of a request is git-upload-archive
+func isArchiveOnlyRequest(verbs []string) bool {
+	hasArchive := false
+	for _, verb := range verbs {
+		if verb == "" {
+			continue
+		}
+		if verb != "git-upload-archive" {
+			return false
+		}
+		hasArchive = true
+	}
+	return hasArchive
+}
@@ -11,6 +11,8 @@ import (
 
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/httplib"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/services/capacity"
 )
 
 func MonitorDiagnosis(ctx *context.Context) {
@@ -59,3 +61,29 @@ func MonitorDiagnosis(ctx *context.Context) {
 	}
 	_ = pprof.Lookup("goroutine").WriteTo(f, 1)
 }
+
+// MonitorProfile downloads the anonymous profile of the instance for capacity planning
+func MonitorProfile(ctx *context.Context) {
+	top := 10
+	if ctx.FormString("top") != "" {
+		top = ctx.FormInt("top")
+	}
+
+	profile, err := capacity.GenerateProfile(ctx, top)
+	if err != nil {
+		ctx.ServerError("GenerateProfile", err)
+		return
+	}
+	data, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		ctx.ServerError("MarshalIndent", err)
+		return
+	}
+
+	httplib.ServeSetHeaders(ctx.Resp, &httplib.ServeHeaderOptions{
+		ContentType: "application/json",
+		Disposition: "attachment",
+		Filename:    fmt.Sprintf("gitea-profile-%s.json", time.Now().Format("20060102-150405")),
+	})
+	_, _ = ctx.Resp.Write(data)
+}
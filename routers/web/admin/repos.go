@@ -9,6 +9,7 @@ import (
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
@@ -30,6 +31,9 @@ const (
 func Repos(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("admin.repositories")
 	ctx.Data["PageIsAdminRepositories"] = true
+	if setting.Repository.DeletionRetention > 0 {
+		ctx.Data["DeletionRetention"] = util.SecToTime(int64(setting.Repository.DeletionRetention.Seconds()))
+	}
 
 	explore.RenderRepoSearch(ctx, &explore.RepoSearchOptions{
 		Private:          true,
@@ -51,13 +55,24 @@ func DeleteRepo(ctx *context.Context) {
 		ctx.Repo.GitRepo.Close()
 	}
 
-	if err := repo_service.DeleteRepository(ctx, ctx.Doer, repo, true); err != nil {
-		ctx.ServerError("DeleteRepository", err)
+	if err := repo_service.SoftDeleteRepository(ctx, ctx.Doer, repo); err != nil {
+		if issues_model.IsErrUnderLegalHold(err) {
+			ctx.Flash.Error(ctx.Tr("repo.legal_hold.deletion_blocked"))
+			ctx.JSON(http.StatusOK, map[string]interface{}{
+				"redirect": setting.AppSubURL + "/admin/repos?page=" + url.QueryEscape(ctx.FormString("page")) + "&sort=" + url.QueryEscape(ctx.FormString("sort")),
+			})
+			return
+		}
+		ctx.ServerError("SoftDeleteRepository", err)
 		return
 	}
 	log.Trace("Repository deleted: %s", repo.FullName())
 
-	ctx.Flash.Success(ctx.Tr("repo.settings.deletion_success"))
+	if setting.Repository.DeletionRetention > 0 {
+		ctx.Flash.Success(ctx.Tr("repo.settings.deletion_success_retention", util.SecToTime(int64(setting.Repository.DeletionRetention.Seconds()))))
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.settings.deletion_success"))
+	}
 	ctx.JSON(http.StatusOK, map[string]interface{}{
 		"redirect": setting.AppSubURL + "/admin/repos?page=" + url.QueryEscape(ctx.FormString("page")) + "&sort=" + url.QueryEscape(ctx.FormString("sort")),
 	})
@@ -29,7 +29,9 @@ import (
 	"code.gitea.io/gitea/services/auth/source/oauth2"
 	"code.gitea.io/gitea/services/externalaccount"
 	"code.gitea.io/gitea/services/forms"
+	"code.gitea.io/gitea/services/loginrisk"
 	"code.gitea.io/gitea/services/mailer"
+	spam_service "code.gitea.io/gitea/services/spam"
 
 	"github.com/markbates/goth"
 )
@@ -228,10 +230,18 @@ func SignInPost(ctx *context.Context) {
 
 	// Now handle 2FA:
 
-	// First of all if the source can skip local two fa we're done
+	// First of all if the source can skip local two fa we're done,
+	// unless the user signs in from a new device and has to pass it anyway
 	if skipper, ok := source.Cfg.(auth_service.LocalTwoFASkipper); ok && skipper.IsSkipLocalTwoFA() {
-		handleSignIn(ctx, u, form.Remember)
-		return
+		stepUp, err := loginrisk.RequiresStepUp(ctx, u)
+		if err != nil {
+			ctx.ServerError("UserSignIn", err)
+			return
+		}
+		if !stepUp {
+			handleSignIn(ctx, u, form.Remember)
+			return
+		}
 	}
 
 	// If this user is enrolled in 2FA TOTP, we can't sign the user in just yet.
@@ -339,6 +349,10 @@ func handleSignInFull(ctx *context.Context, u *user_model.User, remember, obeyRe
 		return setting.AppSubURL + "/"
 	}
 
+	if err := loginrisk.RecordSignIn(ctx, u); err != nil {
+		log.Error("RecordSignIn: %v", err)
+	}
+
 	if redirectTo := ctx.GetSiteCookie("redirect_to"); len(redirectTo) > 0 && !utils.IsExternalURL(redirectTo) {
 		middleware.DeleteRedirectToCookie(ctx.Resp)
 		if obeyRedirect {
@@ -465,6 +479,22 @@ func SignUpPost(ctx *context.Context) {
 		Passwd: form.Password,
 	}
 
+	// the first user becomes the site administrator and is never held for review
+	if spamResult := spam_service.CheckRegistration(ctx, u, ctx.FormString(spam_service.HoneypotField)); spamResult.IsSpam() && user_model.CountUsers(nil) > 0 {
+		u.ProhibitLogin = true
+		if !createUserInContext(ctx, tplSignUp, form, u, nil, nil, false) {
+			// error already handled
+			return
+		}
+		if err := spam_service.HoldRegistration(ctx, u, spamResult); err != nil {
+			ctx.ServerError("HoldRegistration", err)
+			return
+		}
+		ctx.Flash.Info(ctx.Tr("auth.sign_up_held_for_review"))
+		ctx.Redirect(setting.AppSubURL + "/")
+		return
+	}
+
 	if !createAndHandleCreatedUser(ctx, tplSignUp, form, u, nil, nil, false) {
 		// error already handled
 		return
@@ -33,6 +33,7 @@ import (
 	"code.gitea.io/gitea/services/auth/source/oauth2"
 	"code.gitea.io/gitea/services/externalaccount"
 	"code.gitea.io/gitea/services/forms"
+	"code.gitea.io/gitea/services/loginrisk"
 	user_service "code.gitea.io/gitea/services/user"
 
 	"gitea.com/go-chi/binding"
@@ -152,8 +153,16 @@ func newAccessTokenResponse(ctx stdContext.Context, grant *auth.OAuth2Grant, ser
 			}
 		}
 	}
+	app, err := auth.GetOAuth2ApplicationByID(ctx, grant.ApplicationID)
+	if err != nil {
+		return nil, &AccessTokenError{
+			ErrorCode:        AccessTokenErrorCodeInvalidRequest,
+			ErrorDescription: "cannot find application",
+		}
+	}
+
 	// generate access token to access the API
-	expirationDate := timeutil.TimeStampNow().Add(setting.OAuth2.AccessTokenExpirationTime)
+	expirationDate := timeutil.TimeStampNow().Add(app.AccessTokenLifetime())
 	accessToken := &oauth2.Token{
 		GrantID: grant.ID,
 		Type:    oauth2.TypeAccessToken,
@@ -170,7 +179,7 @@ func newAccessTokenResponse(ctx stdContext.Context, grant *auth.OAuth2Grant, ser
 	}
 
 	// generate refresh token to request an access token after it expired later
-	refreshExpirationDate := timeutil.TimeStampNow().Add(setting.OAuth2.RefreshTokenExpirationTime * 60 * 60).AsTime()
+	refreshExpirationDate := timeutil.TimeStampNow().Add(app.RefreshTokenLifetime() * 60 * 60).AsTime()
 	refreshToken := &oauth2.Token{
 		GrantID: grant.ID,
 		Counter: grant.Counter,
@@ -190,13 +199,6 @@ func newAccessTokenResponse(ctx stdContext.Context, grant *auth.OAuth2Grant, ser
 	// generate OpenID Connect id_token
 	signedIDToken := ""
 	if grant.ScopeContains("openid") {
-		app, err := auth.GetOAuth2ApplicationByID(ctx, grant.ApplicationID)
-		if err != nil {
-			return nil, &AccessTokenError{
-				ErrorCode:        AccessTokenErrorCodeInvalidRequest,
-				ErrorDescription: "cannot find application",
-			}
-		}
 		user, err := user_model.GetUserByID(ctx, grant.UserID)
 		if err != nil {
 			if user_model.IsErrUserNotExist(err) {
@@ -258,7 +260,7 @@ func newAccessTokenResponse(ctx stdContext.Context, grant *auth.OAuth2Grant, ser
 	return &AccessTokenResponse{
 		AccessToken:  signedAccessToken,
 		TokenType:    TokenTypeBearer,
-		ExpiresIn:    setting.OAuth2.AccessTokenExpirationTime,
+		ExpiresIn:    app.AccessTokenLifetime(),
 		RefreshToken: signedRefreshToken,
 		IDToken:      signedIDToken,
 	}, nil
@@ -462,14 +464,24 @@ func AuthorizeOAuth(ctx *context.Context) {
 		return
 	}
 
+	accessTokenScope, err := app.RequestedAccessTokenScope(form.Scope)
+	if err != nil {
+		handleAuthorizeError(ctx, AuthorizeError{
+			ErrorCode:        ErrorCodeInvalidScope,
+			ErrorDescription: err.Error(),
+			State:            form.State,
+		}, form.RedirectURI)
+		return
+	}
+
 	grant, err := app.GetGrantByUserID(ctx, ctx.Doer.ID)
 	if err != nil {
 		handleServerError(ctx, form.State, form.RedirectURI)
 		return
 	}
 
-	// Redirect if user already granted access
-	if grant != nil {
+	// Redirect if user already granted access, unless API scopes are requested the user didn't grant yet
+	if grant != nil && grant.CoversRequestedScope(form.Scope) {
 		code, err := grant.GenerateNewAuthorizationCode(ctx, form.RedirectURI, form.CodeChallenge, form.CodeChallengeMethod)
 		if err != nil {
 			handleServerError(ctx, form.State, form.RedirectURI)
@@ -497,6 +509,10 @@ func AuthorizeOAuth(ctx *context.Context) {
 	ctx.Data["State"] = form.State
 	ctx.Data["Scope"] = form.Scope
 	ctx.Data["Nonce"] = form.Nonce
+	if accessTokenScope != auth.AccessTokenScopeAll {
+		// the user can choose which of the requested API scopes to grant
+		ctx.Data["AccessTokenScopes"] = accessTokenScope.StringSlice()
+	}
 	if user != nil {
 		ctx.Data["ApplicationCreatorLinkHTML"] = fmt.Sprintf(`<a href="%s">@%s</a>`, html.EscapeString(user.HomeLink()), html.EscapeString(user.Name))
 	} else {
@@ -522,6 +538,12 @@ func AuthorizeOAuth(ctx *context.Context) {
 		log.Error(err.Error())
 		return
 	}
+	err = ctx.Session.Set("access_token_scope", string(accessTokenScope))
+	if err != nil {
+		handleServerError(ctx, form.State, form.RedirectURI)
+		log.Error(err.Error())
+		return
+	}
 	// Here we're just going to try to release the session early
 	if err := ctx.Session.Release(); err != nil {
 		// we'll tolerate errors here as they *should* get saved elsewhere
@@ -543,7 +565,18 @@ func GrantApplicationOAuth(ctx *context.Context) {
 		ctx.ServerError("GetOAuth2ApplicationByClientID", err)
 		return
 	}
-	grant, err := app.CreateGrant(ctx, ctx.Doer.ID, form.Scope)
+	accessTokenScope := grantedAccessTokenScope(ctx, form.GrantedScope)
+	grant, err := app.GetGrantByUserID(ctx, ctx.Doer.ID)
+	if err != nil {
+		handleServerError(ctx, form.State, form.RedirectURI)
+		return
+	}
+	if grant != nil {
+		// the user consented again because more API scopes were requested
+		err = grant.UpdateScope(ctx, form.Scope, accessTokenScope)
+	} else {
+		grant, err = app.CreateGrant(ctx, ctx.Doer.ID, form.Scope, accessTokenScope)
+	}
 	if err != nil {
 		handleAuthorizeError(ctx, AuthorizeError{
 			State:            form.State,
@@ -576,6 +609,27 @@ func GrantApplicationOAuth(ctx *context.Context) {
 	ctx.Redirect(redirect.String(), http.StatusSeeOther)
 }
 
+// grantedAccessTokenScope returns the API scopes the user chose on the consent page,
+// limited to the ones requested by the application
+func grantedAccessTokenScope(ctx *context.Context, chosen []string) auth.AccessTokenScope {
+	requestedScope, _ := ctx.Session.Get("access_token_scope").(string)
+	requested := auth.AccessTokenScope(requestedScope)
+	if requested == auth.AccessTokenScopeAll {
+		return requested
+	}
+	requestedBits, err := requested.Parse()
+	if err != nil {
+		return ""
+	}
+	var chosenBits auth.AccessTokenScopeBitmap
+	for _, scope := range chosen {
+		if bits, err := auth.AccessTokenScope(scope).Parse(); err == nil {
+			chosenBits |= bits
+		}
+	}
+	return (chosenBits & requestedBits).ToScope()
+}
+
 // OIDCWellKnown generates JSON so OIDC clients know Gitea's capabilities
 func OIDCWellKnown(ctx *context.Context) {
 	t, err := ctx.Render.TemplateLookup("user/auth/oidc_wellknown")
@@ -978,7 +1032,7 @@ func SignInOAuthCallback(ctx *context.Context) {
 				return
 			}
 
-			if err := syncGroupsToTeams(ctx, source, &gothUser, u); err != nil {
+			if err := syncGroupsToTeams(ctx, authSource, &gothUser, u); err != nil {
 				ctx.ServerError("SyncGroupsToTeams", err)
 				return
 			}
@@ -1009,7 +1063,8 @@ func claimValueToStringSet(claimValue interface{}) container.Set[string] {
 	return container.SetOf(groups...)
 }
 
-func syncGroupsToTeams(ctx *context.Context, source *oauth2.Source, gothUser *goth.User, u *user_model.User) error {
+func syncGroupsToTeams(ctx *context.Context, authSource *auth.Source, gothUser *goth.User, u *user_model.User) error {
+	source := authSource.Cfg.(*oauth2.Source)
 	if source.GroupTeamMap != "" || source.GroupTeamMapRemoval {
 		groupTeamMapping, err := auth_module.UnmarshalGroupTeamMapping(source.GroupTeamMap)
 		if err != nil {
@@ -1022,6 +1077,11 @@ func syncGroupsToTeams(ctx *context.Context, source *oauth2.Source, gothUser *go
 			return err
 		}
 	}
+	if source.GroupClaimName != "" {
+		if err := source_service.RecordExternalGroups(ctx, authSource.ID, u, getClaimedGroups(source, gothUser)); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
@@ -1081,8 +1141,14 @@ func updateAvatarIfNeed(url string, u *user_model.User) {
 func handleOAuth2SignIn(ctx *context.Context, source *auth.Source, u *user_model.User, gothUser goth.User) {
 	updateAvatarIfNeed(gothUser.AvatarURL, u)
 
+	stepUp, err := loginrisk.RequiresStepUp(ctx, u)
+	if err != nil {
+		ctx.ServerError("UserSignIn", err)
+		return
+	}
+
 	needs2FA := false
-	if !source.Cfg.(*oauth2.Source).SkipLocalTwoFA {
+	if !source.Cfg.(*oauth2.Source).SkipLocalTwoFA || stepUp {
 		_, err := auth.GetTwoFactorByUID(u.ID)
 		if err != nil && !auth.IsErrTwoFactorNotEnrolled(err) {
 			ctx.ServerError("UserSignIn", err)
@@ -1099,6 +1165,12 @@ func handleOAuth2SignIn(ctx *context.Context, source *auth.Source, u *user_model
 	}
 
 	groups := getClaimedGroups(oauth2Source, &gothUser)
+	if oauth2Source.GroupClaimName != "" {
+		if err := source_service.RecordExternalGroups(ctx, source.ID, u, groups); err != nil {
+			ctx.ServerError("RecordExternalGroups", err)
+			return
+		}
+	}
 
 	// If this user is enrolled in 2FA and this source doesn't override it,
 	// we can't sign the user in just yet. Instead, redirect them to the 2FA authentication page.
@@ -1129,6 +1201,10 @@ func handleOAuth2SignIn(ctx *context.Context, source *auth.Source, u *user_model
 			return
 		}
 
+		if err := loginrisk.RecordSignIn(ctx, u); err != nil {
+			log.Error("RecordSignIn: %v", err)
+		}
+
 		if oauth2Source.GroupTeamMap != "" || oauth2Source.GroupTeamMapRemoval {
 			if err := source_service.SyncGroupsToTeams(ctx, u, groups, groupTeamMapping, oauth2Source.GroupTeamMapRemoval); err != nil {
 				ctx.ServerError("SyncGroupsToTeams", err)
@@ -0,0 +1,58 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package explore
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/federatedsearch"
+)
+
+const (
+	// tplExploreFederated explore federated search page template
+	tplExploreFederated base.TplName = "explore/federated"
+)
+
+// FederatedSearch render the search of the public issues, pull requests and wiki pages of this instance and its peers
+func FederatedSearch(ctx *context.Context) {
+	if !setting.FederatedSearch.Enabled {
+		ctx.NotFound("FederatedSearch", nil)
+		return
+	}
+
+	ctx.Data["UsersIsDisabled"] = setting.Service.Explore.DisableUsersPage
+	ctx.Data["IsRepoIndexerEnabled"] = setting.Indexer.RepoIndexerEnabled
+	ctx.Data["Title"] = ctx.Tr("explore")
+	ctx.Data["PageIsExplore"] = true
+	ctx.Data["PageIsExploreFederated"] = true
+
+	keyword := ctx.FormTrim("q")
+	searchType := ctx.FormTrim("type")
+	if searchType != "issues" && searchType != "wiki" {
+		searchType = ""
+	}
+	ctx.Data["Keyword"] = keyword
+	ctx.Data["SearchType"] = searchType
+
+	if keyword == "" {
+		ctx.HTML(http.StatusOK, tplExploreFederated)
+		return
+	}
+
+	resp, err := federatedsearch.Search(ctx, federatedsearch.SearchOptions{
+		Keyword: keyword,
+		Type:    searchType,
+	})
+	if err != nil {
+		ctx.ServerError("Search", err)
+		return
+	}
+	ctx.Data["Results"] = resp.Results
+	ctx.Data["PeerErrors"] = resp.Errors
+
+	ctx.HTML(http.StatusOK, tplExploreFederated)
+}
@@ -96,7 +96,7 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 	language := ctx.FormTrim("language")
 	ctx.Data["Language"] = language
 
-	repos, count, err = repo_model.SearchRepository(ctx, &repo_model.SearchRepoOptions{
+	searchOpts := &repo_model.SearchRepoOptions{
 		ListOptions: db.ListOptions{
 			Page:     page,
 			PageSize: opts.PageSize,
@@ -104,7 +104,6 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 		Actor:              ctx.Doer,
 		OrderBy:            orderBy,
 		Private:            opts.Private,
-		Keyword:            keyword,
 		OwnerID:            opts.OwnerID,
 		AllPublic:          true,
 		AllLimited:         true,
@@ -112,10 +111,16 @@ func RenderRepoSearch(ctx *context.Context, opts *RepoSearchOptions) {
 		Language:           language,
 		IncludeDescription: setting.UI.SearchRepoDescription,
 		OnlyShowRelevant:   opts.OnlyShowRelevant,
-	})
-	if err != nil {
-		ctx.ServerError("SearchRepository", err)
-		return
+	}
+	if err = searchOpts.ParseQuery(keyword); err != nil {
+		// show the error instead of the results of a broader search
+		ctx.Flash.Error(ctx.Tr("explore.search_query_invalid", err.Error()), true)
+	} else {
+		repos, count, err = repo_model.SearchRepository(ctx, searchOpts)
+		if err != nil {
+			ctx.ServerError("SearchRepository", err)
+			return
+		}
 	}
 	if isSitemap {
 		m := sitemap.NewSitemap()
@@ -57,9 +57,12 @@ type response struct {
 // an object that provides detailed health statuses of additional downstream systems and endpoints
 // which can affect the overall health of the main API.
 type componentStatus struct {
-	Status status `json:"status"`
-	Time   string `json:"time"`             // the date-time, in ISO8601 format
-	Output string `json:"output,omitempty"` // this field SHOULD be omitted for "pass" state.
+	Status        status `json:"status"`
+	Time          string `json:"time"`                    // the date-time, in ISO8601 format
+	Output        string `json:"output,omitempty"`        // this field SHOULD be omitted for "pass" state.
+	ComponentType string `json:"componentType,omitempty"` // the type of the component, like "datastore" or "system"
+	ObservedValue any    `json:"observedValue,omitempty"` // the value measured by the check, like a latency
+	ObservedUnit  string `json:"observedUnit,omitempty"`  // the unit of the observed value, like "ms"
 }
 
 // Check is the health check API handler
@@ -0,0 +1,331 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package healthcheck
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"sort"
+	"sync"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/modules/cache"
+	code_indexer "code.gitea.io/gitea/modules/indexer/code"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+)
+
+const (
+	// historySize is the number of results kept for every check
+	historySize = 20
+	// queueBacklogWarning is the number of items waiting in a queue from which it is reported with a warning
+	queueBacklogWarning = 1000
+	// mailerDialTimeout is the timeout of connecting to the SMTP server
+	mailerDialTimeout = 5 * time.Second
+)
+
+// componentsResponse is the data returned by the components health endpoint, with the recent results of the checks
+type componentsResponse struct {
+	response
+	History checks `json:"history,omitempty"`
+}
+
+var history = struct {
+	sync.Mutex
+	checks checks
+}{checks: make(checks)}
+
+// recordHistory adds the results of the checks to the history and returns a copy of it
+func recordHistory(results checks) checks {
+	history.Lock()
+	defer history.Unlock()
+
+	for key, statuses := range results {
+		h := append(history.checks[key], statuses...)
+		if len(h) > historySize {
+			h = h[len(h)-historySize:]
+		}
+		history.checks[key] = h
+	}
+
+	ret := make(checks, len(history.checks))
+	for key, statuses := range history.checks {
+		ret[key] = append([]componentStatus(nil), statuses...)
+	}
+	return ret
+}
+
+// CheckComponents is the health check API handler reporting the status of every component of the instance.
+// A failing database or cache fails the instance, the other components only make it report a warning.
+func CheckComponents(w http.ResponseWriter, r *http.Request) {
+	rsp := componentsResponse{
+		response: response{
+			Status:      pass,
+			Description: setting.AppName,
+			Checks:      make(checks),
+		},
+	}
+
+	if setting.InstallLock {
+		critical := []status{
+			checkDatabaseLatency(rsp.Checks),
+			checkCacheLatency(rsp.Checks),
+		}
+		others := []status{
+			checkStorages(rsp.Checks),
+			checkQueues(rsp.Checks),
+			checkIndexers(rsp.Checks),
+			checkMailer(rsp.Checks),
+			checkActions(rsp.Checks),
+		}
+		for _, s := range others {
+			if s != pass {
+				rsp.Status = warn
+				break
+			}
+		}
+		for _, s := range critical {
+			if s != pass {
+				rsp.Status = fail
+				break
+			}
+		}
+		rsp.History = recordHistory(rsp.Checks)
+	}
+
+	data, _ := json.MarshalIndent(rsp, "", "  ")
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(rsp.Status.ToHTTPStatus())
+	_, _ = w.Write(data)
+}
+
+// measure runs a check and returns its status with its latency in milliseconds
+func measure(componentType string, check func() error) componentStatus {
+	start := time.Now()
+	err := check()
+	st := componentStatus{
+		Status:        pass,
+		Time:          getCheckTime(),
+		ComponentType: componentType,
+		ObservedValue: time.Since(start).Milliseconds(),
+		ObservedUnit:  "ms",
+	}
+	if err != nil {
+		st.Status = fail
+	}
+	return st
+}
+
+// worst returns the most severe of the statuses of a component
+func worst(statuses []componentStatus) status {
+	ret := pass
+	for _, st := range statuses {
+		if st.Status == fail {
+			return fail
+		} else if st.Status == warn {
+			ret = warn
+		}
+	}
+	return ret
+}
+
+func checkDatabaseLatency(checks checks) status {
+	st := measure("datastore", func() error {
+		err := db.GetEngine(db.DefaultContext).Ping()
+		if err != nil {
+			log.Error("database ping failed with error: %v", err)
+		}
+		return err
+	})
+	checks["database:responseTime"] = []componentStatus{st}
+	return st.Status
+}
+
+func checkCacheLatency(checks checks) status {
+	if !setting.CacheService.Enabled {
+		return pass
+	}
+
+	st := measure("datastore", func() error {
+		err := cache.GetCache().Ping()
+		if err != nil {
+			log.Error("cache ping failed with error: %v", err)
+		}
+		return err
+	})
+	checks["cache:responseTime"] = []componentStatus{st}
+	return st.Status
+}
+
+// checkStorages looks up an object which doesn't exist in every storage, only failing to look it up is an error
+func checkStorages(checks checks) status {
+	storages := map[string]storage.ObjectStorage{
+		"attachments":       storage.Attachments,
+		"lfs":               storage.LFS,
+		"avatars":           storage.Avatars,
+		"repo-avatars":      storage.RepoAvatars,
+		"repo-archives":     storage.RepoArchives,
+		"packages":          storage.Packages,
+		"actions":           storage.Actions,
+		"actions-artifacts": storage.ActionsArtifacts,
+	}
+	if !setting.Packages.Enabled {
+		delete(storages, "packages")
+	}
+	if !setting.Actions.Enabled {
+		delete(storages, "actions")
+		delete(storages, "actions-artifacts")
+	}
+
+	statuses := make([]componentStatus, 0, len(storages))
+	for name, s := range storages {
+		name, s := name, s
+		st := measure("datastore", func() error {
+			_, err := s.Stat("healthcheck")
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Error("%s storage health check failed with error: %v", name, err)
+				return err
+			}
+			return nil
+		})
+		checks["storage:"+name+":responseTime"] = []componentStatus{st}
+		statuses = append(statuses, st)
+	}
+	return worst(statuses)
+}
+
+// checkQueues reports the number of items waiting in every queue, with a warning for large backlogs
+func checkQueues(checks checks) status {
+	queues := queue.GetManager().ManagedQueues()
+	ids := make([]int64, 0, len(queues))
+	for id := range queues {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	statuses := make([]componentStatus, 0, len(queues))
+	for _, id := range ids {
+		q := queues[id]
+		items := q.GetQueueItemNumber()
+		st := componentStatus{
+			Status:        pass,
+			Time:          getCheckTime(),
+			ComponentType: "component",
+			ObservedValue: items,
+			ObservedUnit:  "items",
+		}
+		if items >= queueBacklogWarning {
+			st.Status = warn
+			st.Output = "the queue has a large backlog"
+		}
+		checks["queue:"+q.GetName()+":items"] = []componentStatus{st}
+		statuses = append(statuses, st)
+	}
+	return worst(statuses)
+}
+
+func checkIndexers(checks checks) status {
+	statuses := make([]componentStatus, 0, 2)
+
+	st := measure("component", func() error {
+		if !issue_indexer.IsAvailable() {
+			return errors.New("issue indexer is unavailable")
+		}
+		return nil
+	})
+	checks["indexer:issues:responseTime"] = []componentStatus{st}
+	statuses = append(statuses, st)
+
+	if setting.Indexer.RepoIndexerEnabled {
+		st := measure("component", func() error {
+			if !code_indexer.IsAvailable() {
+				return errors.New("code indexer is unavailable")
+			}
+			return nil
+		})
+		checks["indexer:code:responseTime"] = []componentStatus{st}
+		statuses = append(statuses, st)
+	}
+	return worst(statuses)
+}
+
+// checkMailer connects to the SMTP server or looks up the sendmail command, the other mailers are not checked
+func checkMailer(checks checks) status {
+	if setting.MailService == nil {
+		return pass
+	}
+
+	var check func() error
+	switch setting.MailService.Protocol {
+	case "smtp", "smtps", "smtp+starttls":
+		check = func() error {
+			conn, err := net.DialTimeout("tcp", net.JoinHostPort(setting.MailService.SMTPAddr, setting.MailService.SMTPPort), mailerDialTimeout)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}
+	case "smtp+unix":
+		check = func() error {
+			conn, err := net.DialTimeout("unix", setting.MailService.SMTPAddr, mailerDialTimeout)
+			if err != nil {
+				return err
+			}
+			return conn.Close()
+		}
+	case "sendmail":
+		check = func() error {
+			_, err := exec.LookPath(setting.MailService.SendmailPath)
+			return err
+		}
+	default:
+		return pass
+	}
+
+	st := measure("system", func() error {
+		err := check()
+		if err != nil {
+			log.Error("mailer health check failed with error: %v", err)
+		}
+		return err
+	})
+	checks["mailer:responseTime"] = []componentStatus{st}
+	return st.Status
+}
+
+// checkActions reports the number of online runners, with a warning if there is none
+func checkActions(checks checks) status {
+	if !setting.Actions.Enabled {
+		return pass
+	}
+
+	st := componentStatus{
+		Status:        pass,
+		Time:          getCheckTime(),
+		ComponentType: "component",
+		ObservedUnit:  "runners",
+	}
+	count, err := actions_model.CountOnlineRunners(db.DefaultContext)
+	if err != nil {
+		log.Error("counting the online actions runners failed with error: %v", err)
+		st.Status = fail
+	} else {
+		st.ObservedValue = count
+		if count == 0 {
+			st.Status = warn
+			st.Output = "no runner is online"
+		}
+	}
+	checks["actions:runners"] = []componentStatus{st}
+	return st.Status
+}
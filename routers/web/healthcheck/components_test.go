@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package healthcheck
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecordHistory(t *testing.T) {
+	for i := 0; i < historySize+5; i++ {
+		recordHistory(checks{"test:responseTime": {{Status: pass, ObservedValue: i}}})
+	}
+	h := recordHistory(checks{"test:responseTime": {{Status: fail, ObservedValue: -1}}})
+
+	assert.Len(t, h["test:responseTime"], historySize)
+	assert.Equal(t, 6, h["test:responseTime"][0].ObservedValue)
+	assert.Equal(t, fail, h["test:responseTime"][historySize-1].Status)
+}
+
+func TestWorst(t *testing.T) {
+	assert.Equal(t, pass, worst(nil))
+	assert.Equal(t, warn, worst([]componentStatus{{Status: pass}, {Status: warn}}))
+	assert.Equal(t, fail, worst([]componentStatus{{Status: warn}, {Status: fail}, {Status: pass}}))
+}
@@ -0,0 +1,26 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package misc
+
+import (
+	"strings"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/httpcache"
+)
+
+// ThemeStylesheet serves the stylesheet of a theme managed by the administrators
+func ThemeStylesheet(ctx *context.Context) {
+	t, err := system_model.GetThemeByName(ctx, ctx.Params("name"))
+	if err != nil {
+		if system_model.IsErrThemeNotExist(err) {
+			ctx.NotFound("GetThemeByName", err)
+		} else {
+			ctx.ServerError("GetThemeByName", err)
+		}
+		return
+	}
+	httpcache.ServeContentWithCacheControl(ctx.Resp, ctx.Req, "theme.css", t.UpdatedUnix.AsTime(), strings.NewReader(t.CSS()))
+}
@@ -5,6 +5,7 @@
 package org
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -16,13 +17,17 @@ import (
 	"code.gitea.io/gitea/models/db"
 	org_model "code.gitea.io/gitea/models/organization"
 	"code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	unit_model "code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/utils"
 	"code.gitea.io/gitea/services/convert"
@@ -319,6 +324,12 @@ func NewTeamPost(ctx *context.Context) {
 		AccessMode:              p,
 		IncludesAllRepositories: includesAllRepositories,
 		CanCreateOrgRepo:        form.CanCreateOrgRepo,
+		CodePaths:               strings.Split(form.CodePaths, "\n"),
+		MentionMode:             org_model.TeamMentionMode(form.MentionMode),
+		MentionWebhookType:      form.MentionWebhookType,
+		MentionWebhookURL:       form.MentionWebhookURL,
+		MentionRateLimit:        form.MentionRateLimit,
+		ReviewAssignCount:       form.ReviewAssignCount,
 	}
 
 	units := make([]*org_model.TeamUnit, 0, len(unitPerms))
@@ -347,11 +358,27 @@ func NewTeamPost(ctx *context.Context) {
 		return
 	}
 
+	if err := t.ValidateMentionSettings(); err != nil {
+		ctx.Data["Err_MentionSettings"] = true
+		ctx.RenderWithErr(ctx.Tr("org.teams.mention_settings_invalid"), tplTeamNew, &form)
+		return
+	}
+
+	if err := t.ValidateReviewAssignSettings(); err != nil {
+		ctx.Data["Err_ReviewAssignCount"] = true
+		ctx.RenderWithErr(ctx.Tr("org.teams.review_assign_count_invalid"), tplTeamNew, &form)
+		return
+	}
+
 	if err := models.NewTeam(t); err != nil {
 		ctx.Data["Err_TeamName"] = true
 		switch {
 		case org_model.IsErrTeamAlreadyExist(err):
 			ctx.RenderWithErr(ctx.Tr("form.team_name_been_taken"), tplTeamNew, &form)
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Data["Err_TeamName"] = false
+			ctx.Data["Err_CodePaths"] = true
+			ctx.RenderWithErr(ctx.Tr("org.teams.code_paths_invalid"), tplTeamNew, &form)
 		default:
 			ctx.ServerError("NewTeam", err)
 		}
@@ -380,6 +407,11 @@ func TeamMembers(ctx *context.Context) {
 	ctx.Data["Invites"] = invites
 	ctx.Data["IsEmailInviteEnabled"] = setting.MailService != nil
 
+	prepareTeamHomepage(ctx)
+	if ctx.Written() {
+		return
+	}
+
 	ctx.HTML(http.StatusOK, tplTeamMembers)
 }
 
@@ -393,9 +425,54 @@ func TeamRepositories(ctx *context.Context) {
 		return
 	}
 	ctx.Data["Units"] = unit_model.Units
+
+	prepareTeamHomepage(ctx)
+	if ctx.Written() {
+		return
+	}
+
 	ctx.HTML(http.StatusOK, tplTeamRepositories)
 }
 
+// prepareTeamHomepage renders the README of the team and lists its pinned repositories for the sidebar
+func prepareTeamHomepage(ctx *context.Context) {
+	homepage, err := org_model.GetTeamHomepage(ctx, ctx.Org.Team)
+	if err != nil {
+		ctx.ServerError("GetTeamHomepage", err)
+		return
+	}
+	if len(homepage.Readme) != 0 {
+		readme, err := markdown.RenderString(&markup.RenderContext{
+			Ctx:       ctx,
+			URLPrefix: ctx.Org.OrgLink,
+			Metas:     map[string]string{"mode": "document"},
+		}, homepage.Readme)
+		if err != nil {
+			ctx.ServerError("RenderString", err)
+			return
+		}
+		ctx.Data["TeamReadme"] = readme
+	}
+
+	pinned, err := org_model.GetTeamPinnedRepos(ctx, ctx.Org.Team.ID)
+	if err != nil {
+		ctx.ServerError("GetTeamPinnedRepos", err)
+		return
+	}
+	visible := make([]*repo_model.Repository, 0, len(pinned))
+	for _, repo := range pinned {
+		permission, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
+		if err != nil {
+			ctx.ServerError("GetUserRepoPermission", err)
+			return
+		}
+		if permission.HasAccess() {
+			visible = append(visible, repo)
+		}
+	}
+	ctx.Data["TeamPinnedRepos"] = visible
+}
+
 // SearchTeam api for searching teams
 func SearchTeam(ctx *context.Context) {
 	listOptions := db.ListOptions{
@@ -483,11 +560,17 @@ func EditTeamPost(ctx *context.Context) {
 			t.IncludesAllRepositories = includesAllRepositories
 		}
 		t.CanCreateOrgRepo = form.CanCreateOrgRepo
+		t.CodePaths = strings.Split(form.CodePaths, "\n")
 	} else {
 		t.CanCreateOrgRepo = true
 	}
 
 	t.Description = form.Description
+	t.MentionMode = org_model.TeamMentionMode(form.MentionMode)
+	t.MentionWebhookType = form.MentionWebhookType
+	t.MentionWebhookURL = form.MentionWebhookURL
+	t.MentionRateLimit = form.MentionRateLimit
+	t.ReviewAssignCount = form.ReviewAssignCount
 	units := make([]*org_model.TeamUnit, 0, len(unitPerms))
 	for tp, perm := range unitPerms {
 		units = append(units, &org_model.TeamUnit{
@@ -509,11 +592,27 @@ func EditTeamPost(ctx *context.Context) {
 		return
 	}
 
+	if err := t.ValidateMentionSettings(); err != nil {
+		ctx.Data["Err_MentionSettings"] = true
+		ctx.RenderWithErr(ctx.Tr("org.teams.mention_settings_invalid"), tplTeamNew, &form)
+		return
+	}
+
+	if err := t.ValidateReviewAssignSettings(); err != nil {
+		ctx.Data["Err_ReviewAssignCount"] = true
+		ctx.RenderWithErr(ctx.Tr("org.teams.review_assign_count_invalid"), tplTeamNew, &form)
+		return
+	}
+
 	if err := models.UpdateTeam(t, isAuthChanged, isIncludeAllChanged); err != nil {
 		ctx.Data["Err_TeamName"] = true
 		switch {
 		case org_model.IsErrTeamAlreadyExist(err):
 			ctx.RenderWithErr(ctx.Tr("form.team_name_been_taken"), tplTeamNew, &form)
+		case errors.Is(err, util.ErrInvalidArgument):
+			ctx.Data["Err_TeamName"] = false
+			ctx.Data["Err_CodePaths"] = true
+			ctx.RenderWithErr(ctx.Tr("org.teams.code_paths_invalid"), tplTeamNew, &form)
 		default:
 			ctx.ServerError("UpdateTeam", err)
 		}
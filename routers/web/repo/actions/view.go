@@ -51,15 +51,16 @@ type ViewRequest struct {
 type ViewResponse struct {
 	State struct {
 		Run struct {
-			Link       string     `json:"link"`
-			Title      string     `json:"title"`
-			Status     string     `json:"status"`
-			CanCancel  bool       `json:"canCancel"`
-			CanApprove bool       `json:"canApprove"` // the run needs an approval and the doer has permission to approve
-			CanRerun   bool       `json:"canRerun"`
-			Done       bool       `json:"done"`
-			Jobs       []*ViewJob `json:"jobs"`
-			Commit     ViewCommit `json:"commit"`
+			Link           string     `json:"link"`
+			Title          string     `json:"title"`
+			Status         string     `json:"status"`
+			CanCancel      bool       `json:"canCancel"`
+			CanApprove     bool       `json:"canApprove"` // the run needs an approval and the doer has permission to approve
+			CanRerun       bool       `json:"canRerun"`
+			CanRerunFailed bool       `json:"canRerunFailed"` // the run is done with failed jobs and the doer has permission to rerun them
+			Done           bool       `json:"done"`
+			Jobs           []*ViewJob `json:"jobs"`
+			Commit         ViewCommit `json:"commit"`
 		} `json:"run"`
 		CurrentJob struct {
 			Title  string         `json:"title"`
@@ -139,6 +140,7 @@ func ViewPost(ctx *context_module.Context) {
 	resp.State.Run.CanCancel = !run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
 	resp.State.Run.CanApprove = run.NeedApproval && ctx.Repo.CanWrite(unit.TypeActions)
 	resp.State.Run.CanRerun = run.Status.IsDone() && ctx.Repo.CanWrite(unit.TypeActions)
+	resp.State.Run.CanRerunFailed = resp.State.Run.CanRerun && run.Status.IsFailure()
 	resp.State.Run.Done = run.Status.IsDone()
 	resp.State.Run.Jobs = make([]*ViewJob, 0, len(jobs)) // marshal to '[]' instead fo 'null' in json
 	resp.State.Run.Status = run.Status.String()
@@ -260,7 +262,7 @@ func RerunOne(ctx *context_module.Context) {
 		return
 	}
 
-	if err := rerunJob(ctx, job); err != nil {
+	if err := actions_service.RerunJob(ctx, job, ctx.Doer); err != nil {
 		ctx.Error(http.StatusInternalServerError, err.Error())
 		return
 	}
@@ -277,7 +279,7 @@ func RerunAll(ctx *context_module.Context) {
 	}
 
 	for _, j := range jobs {
-		if err := rerunJob(ctx, j); err != nil {
+		if err := actions_service.RerunJob(ctx, j, ctx.Doer); err != nil {
 			ctx.Error(http.StatusInternalServerError, err.Error())
 			return
 		}
@@ -286,26 +288,24 @@ func RerunAll(ctx *context_module.Context) {
 	ctx.JSON(http.StatusOK, struct{}{})
 }
 
-func rerunJob(ctx *context_module.Context, job *actions_model.ActionRunJob) error {
-	status := job.Status
-	if !status.IsDone() {
-		return nil
-	}
+func RerunFailed(ctx *context_module.Context) {
+	runIndex := ctx.ParamsInt64("run")
 
-	job.TaskID = 0
-	job.Status = actions_model.StatusWaiting
-	job.Started = 0
-	job.Stopped = 0
+	_, jobs := getRunJobs(ctx, runIndex, -1)
+	if ctx.Written() {
+		return
+	}
 
-	if err := db.WithTx(ctx, func(ctx context.Context) error {
-		_, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": status}, "task_id", "status", "started", "stopped")
-		return err
-	}); err != nil {
-		return err
+	if err := actions_service.RerunFailedJobs(ctx, jobs, ctx.Doer); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusBadRequest, err.Error())
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, err.Error())
+		return
 	}
 
-	actions_service.CreateCommitStatus(ctx, job)
-	return nil
+	ctx.JSON(http.StatusOK, struct{}{})
 }
 
 func Cancel(ctx *context_module.Context) {
@@ -357,31 +357,16 @@ func Approve(ctx *context_module.Context) {
 		return
 	}
 	run := current.Run
-	doer := ctx.Doer
 
-	if err := db.WithTx(ctx, func(ctx context.Context) error {
-		run.NeedApproval = false
-		run.ApprovedBy = doer.ID
-		if err := actions_model.UpdateRun(ctx, run, "need_approval", "approved_by"); err != nil {
-			return err
-		}
-		for _, job := range jobs {
-			if len(job.Needs) == 0 && job.Status.IsBlocked() {
-				job.Status = actions_model.StatusWaiting
-				_, err := actions_model.UpdateRunJob(ctx, job, nil, "status")
-				if err != nil {
-					return err
-				}
-			}
+	if err := actions_service.ApproveRun(ctx, run, jobs, ctx.Doer); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Error(http.StatusBadRequest, err.Error())
+			return
 		}
-		return nil
-	}); err != nil {
 		ctx.Error(http.StatusInternalServerError, err.Error())
 		return
 	}
 
-	actions_service.CreateCommitStatus(ctx, jobs...)
-
 	ctx.JSON(http.StatusOK, struct{}{})
 }
 
@@ -466,6 +451,7 @@ func ArtifactsDownloadView(ctx *context_module.Context) {
 	artifact, err := actions_model.GetArtifactByID(ctx, artifactID)
 	if errors.Is(err, util.ErrNotExist) {
 		ctx.Error(http.StatusNotFound, err.Error())
+		return
 	} else if err != nil {
 		ctx.Error(http.StatusInternalServerError, err.Error())
 		return
@@ -479,7 +465,7 @@ func ArtifactsDownloadView(ctx *context_module.Context) {
 		ctx.Error(http.StatusInternalServerError, err.Error())
 		return
 	}
-	if artifact.RunID != run.ID {
+	if artifact.RunID != run.ID || artifact.Status == actions_model.ArtifactStatusExpired {
 		ctx.Error(http.StatusNotFound, "artifact not found")
 		return
 	}
@@ -491,8 +477,13 @@ func ArtifactsDownloadView(ctx *context_module.Context) {
 	}
 	defer f.Close()
 
+	filename := artifact.ArtifactName
+	if artifact.ContentEncoding == actions_model.ArtifactContentEncodingZip {
+		// artifacts uploaded with the v4 protocol are zip archives
+		filename += ".zip"
+	}
 	ctx.ServeContent(f, &context_module.ServeHeaderOptions{
-		Filename:     artifact.ArtifactName,
+		Filename:     filename,
 		LastModified: artifact.CreatedUnix.AsLocalTime(),
 	})
 }
@@ -7,13 +7,13 @@ import (
 	"fmt"
 	"net/http"
 
+	issues_model "code.gitea.io/gitea/models/issues"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/httpcache"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
-	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/upload"
 	"code.gitea.io/gitea/routers/common"
 	"code.gitea.io/gitea/services/attachment"
@@ -50,7 +50,7 @@ func uploadAttachment(ctx *context.Context, repoID int64, allowedTypes string) {
 		RepoID:     repoID,
 	})
 	if err != nil {
-		if upload.IsErrFileTypeForbidden(err) {
+		if upload.IsErrFileTypeForbidden(err) || upload.IsErrFileTooLarge(err) {
 			ctx.Error(http.StatusBadRequest, err.Error())
 			return
 		}
@@ -76,6 +76,14 @@ func DeleteAttachment(ctx *context.Context) {
 		ctx.Error(http.StatusForbidden)
 		return
 	}
+	if err := issues_model.CheckAttachmentLegalHold(ctx, attach); err != nil {
+		if issues_model.IsErrUnderLegalHold(err) {
+			ctx.Error(http.StatusForbidden, ctx.Tr("repo.legal_hold.deletion_blocked"))
+			return
+		}
+		ctx.Error(http.StatusInternalServerError, fmt.Sprintf("CheckAttachmentLegalHold: %v", err))
+		return
+	}
 	err = repo_model.DeleteAttachment(attach, true)
 	if err != nil {
 		ctx.Error(http.StatusInternalServerError, fmt.Sprintf("DeleteAttachment: %v", err))
@@ -126,14 +134,29 @@ func ServeAttachment(ctx *context.Context, uuid string) {
 		}
 	}
 
-	if err := attach.IncreaseDownloadCount(); err != nil {
+	// thumbnails are shown inline, they do not count as downloads
+	objectPath, etag := attach.RelativePath(), attach.UUID
+	if ctx.FormBool("thumbnail") && attach.HasThumbnail {
+		objectPath, etag = attach.ThumbnailRelativePath(), attach.UUID+"-thumbnail"
+	} else if err := attach.IncreaseDownloadCount(); err != nil {
 		ctx.ServerError("IncreaseDownloadCount", err)
 		return
 	}
 
+	serveAttachmentObject(ctx, attach, objectPath, etag)
+}
+
+// serveAttachmentObject serves the stored file or thumbnail of an attachment the doer has access to
+func serveAttachmentObject(ctx *context.Context, attach *repo_model.Attachment, objectPath, etag string) {
+	attachmentStorage, err := attach.Storage(ctx)
+	if err != nil {
+		ctx.ServerError("Storage", err)
+		return
+	}
+
 	if setting.Attachment.ServeDirect {
 		// If we have a signed url (S3, object storage), redirect to this directly.
-		u, err := storage.Attachments.URL(attach.RelativePath(), attach.Name)
+		u, err := attachmentStorage.URL(objectPath, attach.Name)
 
 		if u != nil && err == nil {
 			ctx.Redirect(u.String())
@@ -141,12 +164,12 @@ func ServeAttachment(ctx *context.Context, uuid string) {
 		}
 	}
 
-	if httpcache.HandleGenericETagCache(ctx.Req, ctx.Resp, `"`+attach.UUID+`"`) {
+	if httpcache.HandleGenericETagCache(ctx.Req, ctx.Resp, `"`+etag+`"`) {
 		return
 	}
 
 	// If we have matched and access to release or issue
-	fr, err := storage.Attachments.Open(attach.RelativePath())
+	fr, err := attachmentStorage.Open(objectPath)
 	if err != nil {
 		ctx.ServerError("Open", err)
 		return
@@ -0,0 +1,98 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"fmt"
+	"net/http"
+
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	badge_service "code.gitea.io/gitea/services/badge"
+)
+
+// badgeUnitType returns the unit whose read permission is required to see a badge
+func badgeUnitType(kind badge_service.Kind) unit.Type {
+	switch kind {
+	case badge_service.KindRelease:
+		return unit.TypeReleases
+	case badge_service.KindIssues:
+		return unit.TypeIssues
+	}
+	return unit.TypeCode
+}
+
+// canSeeBadge checks the badge token given with the URL, falling back to the permission of the signed-in user
+func canSeeBadge(ctx *context.Context, repo *repo_model.Repository, kind badge_service.Kind) (bool, error) {
+	if token := ctx.FormString("token"); token != "" {
+		badgeToken, err := repo_model.GetBadgeToken(ctx, repo.ID)
+		if err != nil {
+			return false, err
+		}
+		if badgeToken != nil && badgeToken.Verify(token) {
+			return true, nil
+		}
+	}
+
+	if ctx.Doer == nil && setting.Service.RequireSignInView {
+		return false, nil
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
+	if err != nil {
+		return false, err
+	}
+	return perm.CanRead(badgeUnitType(kind)), nil
+}
+
+// Badge renders a SVG badge of a repository. Badges of private repositories need a badge token, unless the user is
+// signed in and allowed to read the repository.
+func Badge(ctx *context.Context) {
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ctx.Params(":username"), ctx.Params(":reponame"))
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound("GetRepositoryByOwnerAndName", err)
+		} else {
+			ctx.ServerError("GetRepositoryByOwnerAndName", err)
+		}
+		return
+	}
+	if repo.IsPendingDeletion() {
+		ctx.NotFound("Badge", nil)
+		return
+	}
+
+	kind := badge_service.Kind(ctx.Params(":kind"))
+	canSee, err := canSeeBadge(ctx, repo, kind)
+	if err != nil {
+		ctx.ServerError("canSeeBadge", err)
+		return
+	} else if !canSee {
+		ctx.NotFound("Badge", nil)
+		return
+	}
+
+	b, err := badge_service.GetRepoBadge(ctx, repo, kind, ctx.FormString("branch"))
+	if err != nil {
+		ctx.ServerError("GetRepoBadge", err)
+		return
+	}
+
+	// badges of private repositories must not be kept by shared caches, e.g. the image proxies of the forges
+	visibility := "public"
+	if repo.IsPrivate || setting.Service.RequireSignInView {
+		visibility = "private"
+	}
+	ctx.Resp.Header().Set("Content-Type", "image/svg+xml;charset=utf-8")
+	ctx.Resp.Header().Set("Cache-Control", fmt.Sprintf("%s, max-age=%d", visibility, badge_service.CacheSeconds))
+	ctx.Resp.Header().Set("X-Content-Type-Options", "nosniff")
+	ctx.Resp.Header().Set("Content-Security-Policy", "default-src 'none'; style-src 'unsafe-inline'")
+	ctx.Resp.WriteHeader(http.StatusOK)
+	if _, err := ctx.Resp.Write(b.SVG()); err != nil {
+		log.Error("Unable to write the %s badge of %s: %v", kind, repo.FullName(), err)
+	}
+}
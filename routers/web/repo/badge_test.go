@@ -0,0 +1,35 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/test"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBadgePendingDeletion(t *testing.T) {
+	unittest.PrepareTestEnv(t)
+
+	badge := func() int {
+		ctx := test.MockContext(t, "user2/repo1/badges/issues.svg")
+		ctx.SetParams(":username", "user2")
+		ctx.SetParams(":reponame", "repo1")
+		ctx.SetParams(":kind", "issues")
+		Badge(ctx)
+		return ctx.Resp.Status()
+	}
+	assert.EqualValues(t, http.StatusOK, badge())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	assert.NoError(t, repo_model.MarkRepositoryPendingDeletion(db.DefaultContext, repo, 2, timeutil.TimeStampNow().Add(3600)))
+	assert.EqualValues(t, http.StatusNotFound, badge())
+}
@@ -44,6 +44,10 @@ func RefBlame(ctx *context.Context) {
 		ctx.NotFound("Blame FileName", nil)
 		return
 	}
+	if !ctx.Repo.Permission.CanReadCodePath(fileName) {
+		ctx.NotFound("CanReadCodePath", nil)
+		return
+	}
 
 	userName := ctx.Repo.Owner.Name
 	repoName := ctx.Repo.Repository.Name
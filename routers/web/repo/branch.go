@@ -26,6 +26,7 @@ import (
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/routers/utils"
 	"code.gitea.io/gitea/services/forms"
+	pull_service "code.gitea.io/gitea/services/pull"
 	release_service "code.gitea.io/gitea/services/release"
 	repo_service "code.gitea.io/gitea/services/repository"
 	files_service "code.gitea.io/gitea/services/repository/files"
@@ -160,6 +161,34 @@ func RestoreBranchPost(ctx *context.Context) {
 	ctx.Flash.Success(ctx.Tr("repo.branch.restore_success", deletedBranch.Name))
 }
 
+// MergeUpstreamPost updates a branch of a fork from the branch with the same name of its upstream repository
+func MergeUpstreamPost(ctx *context.Context) {
+	defer redirect(ctx)
+	branchName := ctx.FormString("name")
+
+	mergeType, _, err := pull_service.MergeUpstream(ctx, ctx.Doer, ctx.Repo.Repository, branchName, "", true)
+	if err != nil {
+		switch {
+		case pull_service.IsErrUpstreamConflicts(err):
+			ctx.Flash.Error(ctx.Tr("repo.branch.merge_upstream_conflict", branchName, strings.Join(err.(pull_service.ErrUpstreamConflicts).Files, ", ")))
+		case models.IsErrBranchDoesNotExist(err):
+			ctx.Flash.Error(ctx.Tr("repo.branch.merge_upstream_no_branch", branchName))
+		case git.IsErrPushRejected(err):
+			ctx.Flash.Error(ctx.Tr("repo.branch.merge_upstream_rejected", branchName))
+		default:
+			log.Error("MergeUpstream: %v", err)
+			ctx.Flash.Error(ctx.Tr("repo.branch.merge_upstream_failed", branchName))
+		}
+		return
+	}
+
+	if mergeType == pull_service.MergeUpstreamNone {
+		ctx.Flash.Info(ctx.Tr("repo.branch.merge_upstream_up_to_date", branchName))
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.branch.merge_upstream_success", branchName))
+	}
+}
+
 func redirect(ctx *context.Context) {
 	ctx.JSON(http.StatusOK, map[string]interface{}{
 		"redirect": ctx.Repo.RepoLink + "/branches?page=" + url.QueryEscape(ctx.FormString("page")),
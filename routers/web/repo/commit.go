@@ -40,6 +40,10 @@ func RefCommits(ctx *context.Context) {
 	case ctx.Repo.TreePath == "search":
 		SearchCommits(ctx)
 	default:
+		if !ctx.Repo.Permission.CanBrowseCodePath(ctx.Repo.TreePath) {
+			ctx.NotFound("CanBrowseCodePath", nil)
+			return
+		}
 		FileHistory(ctx)
 	}
 }
@@ -287,7 +291,7 @@ func Diff(ctx *context.Context) {
 		}
 		return
 	}
-	if len(commitID) != git.SHAFullLength {
+	if !git.IsFullSHA(commitID) {
 		commitID = commit.ID.String()
 	}
 
@@ -0,0 +1,141 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+
+	"code.gitea.io/gitea/models/db"
+	discussion_model "code.gitea.io/gitea/models/discussion"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	discussion_indexer "code.gitea.io/gitea/modules/indexer/discussions"
+	"code.gitea.io/gitea/modules/markup"
+	"code.gitea.io/gitea/modules/markup/markdown"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+const (
+	tplDiscussions    base.TplName = "repo/discussion/list"
+	tplDiscussionView base.TplName = "repo/discussion/view"
+)
+
+// Discussions renders the discussions of a repository, most recently active first
+func Discussions(ctx *context.Context) {
+	ctx.Data["Title"] = ctx.Tr("repo.discussions")
+	ctx.Data["PageIsDiscussionList"] = true
+
+	repo := ctx.Repo.Repository
+	page := ctx.FormInt("page")
+	if page <= 1 {
+		page = 1
+	}
+
+	categories, err := discussion_model.GetCategoriesByRepoID(ctx, repo.ID)
+	if err != nil {
+		ctx.ServerError("GetCategoriesByRepoID", err)
+		return
+	}
+	categoryID := ctx.FormInt64("category")
+	ctx.Data["Categories"] = categories
+	ctx.Data["CategoryID"] = categoryID
+
+	keyword := ctx.FormTrim("q")
+	ctx.Data["Keyword"] = keyword
+
+	var discussionIDs []int64
+	if len(keyword) > 0 {
+		discussionIDs, _, err = discussion_indexer.SearchDiscussionsByKeyword(ctx, []int64{repo.ID}, keyword, 50, 0)
+		if err != nil {
+			ctx.ServerError("SearchDiscussionsByKeyword", err)
+			return
+		}
+	}
+
+	var discussions []*discussion_model.Discussion
+	var count int64
+	if len(keyword) == 0 || len(discussionIDs) > 0 {
+		discussions, count, err = discussion_model.FindDiscussions(ctx, discussion_model.FindDiscussionsOptions{
+			ListOptions: db.ListOptions{Page: page, PageSize: setting.UI.IssuePagingNum},
+			RepoID:      repo.ID,
+			CategoryID:  categoryID,
+			IDs:         discussionIDs,
+		})
+		if err != nil {
+			ctx.ServerError("FindDiscussions", err)
+			return
+		}
+	}
+	for _, d := range discussions {
+		d.Repo = repo
+		if err := d.LoadAttributes(ctx); err != nil {
+			ctx.ServerError("LoadAttributes", err)
+			return
+		}
+	}
+	ctx.Data["Discussions"] = discussions
+
+	pager := context.NewPagination(int(count), setting.UI.IssuePagingNum, page, 5)
+	pager.AddParam(ctx, "q", "Keyword")
+	pager.AddParam(ctx, "category", "CategoryID")
+	ctx.Data["Page"] = pager
+
+	ctx.HTML(http.StatusOK, tplDiscussions)
+}
+
+// ViewDiscussion renders a discussion with its replies
+func ViewDiscussion(ctx *context.Context) {
+	d, err := discussion_model.GetDiscussionByIndex(ctx, ctx.Repo.Repository.ID, ctx.ParamsInt64(":index"))
+	if err != nil {
+		if discussion_model.IsErrDiscussionNotExist(err) {
+			ctx.NotFound("GetDiscussionByIndex", err)
+		} else {
+			ctx.ServerError("GetDiscussionByIndex", err)
+		}
+		return
+	}
+	d.Repo = ctx.Repo.Repository
+	if err := d.LoadAttributes(ctx); err != nil {
+		ctx.ServerError("LoadAttributes", err)
+		return
+	}
+
+	renderCtx := &markup.RenderContext{
+		URLPrefix: ctx.Repo.RepoLink,
+		Metas:     ctx.Repo.Repository.ComposeMetas(),
+		GitRepo:   ctx.Repo.GitRepo,
+		Ctx:       ctx,
+	}
+	ctx.Data["RenderedContent"], err = markdown.RenderString(renderCtx, d.Content)
+	if err != nil {
+		ctx.ServerError("RenderString", err)
+		return
+	}
+
+	replies, _, err := discussion_model.FindComments(ctx, d.ID, db.ListOptions{})
+	if err != nil {
+		ctx.ServerError("FindComments", err)
+		return
+	}
+	renderedReplies := make(map[int64]string, len(replies))
+	for _, c := range replies {
+		if err := c.LoadPoster(ctx); err != nil {
+			ctx.ServerError("LoadPoster", err)
+			return
+		}
+		renderedReplies[c.ID], err = markdown.RenderString(renderCtx, c.Content)
+		if err != nil {
+			ctx.ServerError("RenderString", err)
+			return
+		}
+	}
+
+	ctx.Data["Title"] = d.Title
+	ctx.Data["PageIsDiscussionList"] = true
+	ctx.Data["Discussion"] = d
+	ctx.Data["Replies"] = replies
+	ctx.Data["RenderedReplies"] = renderedReplies
+
+	ctx.HTML(http.StatusOK, tplDiscussionView)
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	archiver_service "code.gitea.io/gitea/services/repository/archiver"
+)
+
+// ServeDownloadLink serves the attachment or archive of a download link, the token of the link authorizes the download
+func ServeDownloadLink(ctx *context.Context) {
+	link, err := repo_model.GetDownloadLinkByToken(ctx, ctx.Params(":token"))
+	if err != nil {
+		if repo_model.IsErrDownloadLinkNotExist(err) {
+			ctx.NotFound("GetDownloadLinkByToken", err)
+		} else {
+			ctx.ServerError("GetDownloadLinkByToken", err)
+		}
+		return
+	}
+	if !link.IsActive() {
+		ctx.NotFound("DownloadLink", nil)
+		return
+	}
+
+	repo, err := repo_model.GetRepositoryByID(ctx, link.RepoID)
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound("GetRepositoryByID", err)
+		} else {
+			ctx.ServerError("GetRepositoryByID", err)
+		}
+		return
+	}
+	if repo.IsPendingDeletion() {
+		ctx.NotFound("DownloadLink", nil)
+		return
+	}
+
+	if link.AttachmentID > 0 {
+		attach, err := repo_model.GetAttachmentByID(ctx, link.AttachmentID)
+		if err != nil {
+			if repo_model.IsErrAttachmentNotExist(err) {
+				ctx.NotFound("GetAttachmentByID", err)
+			} else {
+				ctx.ServerError("GetAttachmentByID", err)
+			}
+			return
+		}
+		if attach.RepoID != repo.ID {
+			ctx.NotFound("GetAttachmentByID", nil)
+			return
+		}
+		if err := link.IncreaseDownloadCount(ctx); err != nil {
+			ctx.ServerError("IncreaseDownloadCount", err)
+			return
+		}
+		if err := attach.IncreaseDownloadCount(); err != nil {
+			ctx.ServerError("IncreaseDownloadCount", err)
+			return
+		}
+		serveAttachmentObject(ctx, attach, attach.RelativePath(), attach.UUID)
+		return
+	}
+
+	if setting.Repository.DisableDownloadSourceArchives {
+		ctx.NotFound("DisableDownloadSourceArchives", nil)
+		return
+	}
+
+	gitRepo, err := git.OpenRepository(ctx, repo.RepoPath())
+	if err != nil {
+		ctx.ServerError("OpenRepository", err)
+		return
+	}
+	defer gitRepo.Close()
+
+	// the archive of the commit is requested, the link serves it even if the ref has moved on
+	aReq, err := archiver_service.NewRequest(repo.ID, gitRepo, link.CommitID+"."+link.ArchiveType.String())
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			ctx.NotFound("NewRequest", err)
+		} else {
+			ctx.ServerError("NewRequest", err)
+		}
+		return
+	}
+	archiver, err := aReq.Await(ctx)
+	if err != nil {
+		ctx.ServerError("archiver.Await", err)
+		return
+	}
+	if err := link.IncreaseDownloadCount(ctx); err != nil {
+		ctx.ServerError("IncreaseDownloadCount", err)
+		return
+	}
+
+	download(ctx, repo.Name+"-"+link.ArchiveName, archiver)
+}
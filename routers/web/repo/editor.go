@@ -21,6 +21,7 @@ import (
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
+	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/typesniffer"
 	"code.gitea.io/gitea/modules/upload"
@@ -37,8 +38,9 @@ const (
 	tplDeleteFile      base.TplName = "repo/editor/delete"
 	tplUploadFile      base.TplName = "repo/editor/upload"
 
-	frmCommitChoiceDirect    string = "direct"
-	frmCommitChoiceNewBranch string = "commit-to-new-branch"
+	frmCommitChoiceDirect      string = "direct"
+	frmCommitChoiceNewBranch   string = "commit-to-new-branch"
+	frmCommitChoiceEditSession string = "edit-session"
 )
 
 func renderCommitRights(ctx *context.Context) bool {
@@ -51,6 +53,32 @@ func renderCommitRights(ctx *context.Context) bool {
 	return canCommitToBranch.CanCommitToBranch
 }
 
+// renderEditSession adds the editing session of the doer on the current branch to the form, so further
+// changes can be collected in it. It returns the session, nil if there is none.
+func renderEditSession(ctx *context.Context) (*repo_model.EditSession, error) {
+	ctx.Data["CanUseEditSession"] = true
+	s, err := repo_model.GetUserEditSession(ctx, ctx.Repo.Repository.ID, ctx.Doer.ID, ctx.Repo.BranchName)
+	if err != nil {
+		return nil, err
+	}
+	ctx.Data["EditSession"] = s
+	return s, nil
+}
+
+// applyEditSessionChange adds a change to the editing session of the doer on the current branch,
+// the session is started if there is none yet
+func applyEditSessionChange(ctx *context.Context, change *files_service.EditSessionChange) (*repo_model.EditSession, error) {
+	s, err := files_service.StartEditSession(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, ctx.Doer, ctx.Repo.BranchName)
+	if err != nil {
+		return nil, err
+	}
+	return s, files_service.ApplyEditSessionChange(ctx, ctx.Repo.Repository, ctx.Doer, s, change)
+}
+
+func editSessionLink(ctx *context.Context, branch string) string {
+	return ctx.Repo.RepoLink + "/_edit_session/" + util.PathEscapeSegments(branch)
+}
+
 // getParentTreeFields returns list of parent tree names and corresponding tree paths
 // based on given tree path.
 func getParentTreeFields(treePath string) (treeNames, treePaths []string) {
@@ -70,6 +98,11 @@ func editFile(ctx *context.Context, isNewFile bool) {
 	ctx.Data["PageIsEdit"] = true
 	ctx.Data["IsNewFile"] = isNewFile
 	canCommit := renderCommitRights(ctx)
+	editSession, err := renderEditSession(ctx)
+	if err != nil {
+		ctx.ServerError("GetUserEditSession", err)
+		return
+	}
 
 	treePath := cleanUploadFileName(ctx.Repo.TreePath)
 	if treePath != ctx.Repo.TreePath {
@@ -87,8 +120,33 @@ func editFile(ctx *context.Context, isNewFile bool) {
 	filePath = strings.Trim(filePath, "/")
 	treeNames, treePaths := getParentTreeFields(path.Join(ctx.Repo.TreePath, filePath))
 
+	if isNewFile {
+		if renderTemplateCatalogs(ctx); ctx.Written() {
+			return
+		}
+		// the file is prefilled with a template of the catalogs, it's added at the path of its kind
+		if name := ctx.FormString("template"); name != "" {
+			templatePath, content, err := repo_module.GetTemplateCatalogFile(ctx, ctx.Repo.Repository, repo_model.TemplateCatalogKind(ctx.FormString("catalog")), name)
+			if err != nil {
+				ctx.NotFoundOrServerError("GetTemplateCatalogFile", repo_model.IsErrTemplateCatalogItemNotExist, err)
+				return
+			}
+			filePath, fileName = path.Split(templatePath)
+			treeNames, treePaths = getParentTreeFields(strings.Trim(filePath, "/"))
+			ctx.Data["FileContent"] = string(content)
+		}
+	}
+
 	if !isNewFile {
-		entry, err := ctx.Repo.Commit.GetTreeEntryByPath(ctx.Repo.TreePath)
+		// the file is edited as it is in the editing session
+		commit := ctx.Repo.Commit
+		if editSession != nil {
+			if commit, err = ctx.Repo.GitRepo.GetCommit(editSession.HeadCommitID); err != nil {
+				ctx.ServerError("GetCommit", err)
+				return
+			}
+		}
+		entry, err := commit.GetTreeEntryByPath(ctx.Repo.TreePath)
 		if err != nil {
 			ctx.NotFoundOrServerError("GetTreeEntryByPath", git.IsErrNotExist, err)
 			return
@@ -149,7 +207,9 @@ func editFile(ctx *context.Context, isNewFile bool) {
 	ctx.Data["BranchLink"] = ctx.Repo.RepoLink + "/src/" + ctx.Repo.BranchNameSubURL()
 	ctx.Data["commit_summary"] = ""
 	ctx.Data["commit_message"] = ""
-	if canCommit {
+	if editSession != nil {
+		ctx.Data["commit_choice"] = frmCommitChoiceEditSession
+	} else if canCommit {
 		ctx.Data["commit_choice"] = frmCommitChoiceDirect
 	} else {
 		ctx.Data["commit_choice"] = frmCommitChoiceNewBranch
@@ -163,6 +223,28 @@ func editFile(ctx *context.Context, isNewFile bool) {
 	ctx.HTML(http.StatusOK, tplEditFile)
 }
 
+// templateCatalog are the templates of a kind offered by the catalogs of the instance and of the owner of the repository
+type templateCatalog struct {
+	Kind  repo_model.TemplateCatalogKind
+	Names []string
+}
+
+// renderTemplateCatalogs offers the templates added to the catalogs, the built-in templates aren't listed
+func renderTemplateCatalogs(ctx *context.Context) {
+	catalogs := make([]*templateCatalog, 0, len(repo_model.TemplateCatalogKinds))
+	for _, kind := range repo_model.TemplateCatalogKinds {
+		names, err := repo_module.GetCustomTemplateCatalogNames(ctx, ctx.Repo.Repository.OwnerID, kind)
+		if err != nil {
+			ctx.ServerError("GetCustomTemplateCatalogNames", err)
+			return
+		}
+		if len(names) > 0 {
+			catalogs = append(catalogs, &templateCatalog{Kind: kind, Names: names})
+		}
+	}
+	ctx.Data["TemplateCatalogs"] = catalogs
+}
+
 // GetEditorConfig returns a editorconfig JSON string for given treePath or "null"
 func GetEditorConfig(ctx *context.Context, treePath string) string {
 	ec, _, err := ctx.Repo.GetEditorconfig()
@@ -188,6 +270,10 @@ func NewFile(ctx *context.Context) {
 
 func editFilePost(ctx *context.Context, form forms.EditRepoFileForm, isNewFile bool) {
 	canCommit := renderCommitRights(ctx)
+	if _, err := renderEditSession(ctx); err != nil {
+		ctx.ServerError("GetUserEditSession", err)
+		return
+	}
 	treeNames, treePaths := getParentTreeFields(form.TreePath)
 	branchName := ctx.Repo.BranchName
 	if form.CommitChoice == frmCommitChoiceNewBranch {
@@ -216,8 +302,9 @@ func editFilePost(ctx *context.Context, form forms.EditRepoFileForm, isNewFile b
 		return
 	}
 
-	// Cannot commit to a an existing branch if user doesn't have rights
-	if branchName == ctx.Repo.BranchName && !canCommit {
+	// Cannot commit to a an existing branch if user doesn't have rights,
+	// the changes of an editing session are checked when they are committed
+	if branchName == ctx.Repo.BranchName && !canCommit && form.CommitChoice != frmCommitChoiceEditSession {
 		ctx.Data["Err_NewBranchName"] = true
 		ctx.Data["commit_choice"] = frmCommitChoiceNewBranch
 		ctx.RenderWithErr(ctx.Tr("repo.editor.cannot_commit_to_protected_branch", branchName), tplEditFile, &form)
@@ -239,19 +326,31 @@ func editFilePost(ctx *context.Context, form forms.EditRepoFileForm, isNewFile b
 		message += "\n\n" + form.CommitMessage
 	}
 
-	if _, err := files_service.CreateOrUpdateRepoFile(ctx, ctx.Repo.Repository, ctx.Doer, &files_service.UpdateRepoFileOptions{
-		LastCommitID: form.LastCommit,
-		OldBranch:    ctx.Repo.BranchName,
-		NewBranch:    branchName,
-		FromTreePath: ctx.Repo.TreePath,
-		TreePath:     form.TreePath,
-		Message:      message,
-		Content:      strings.ReplaceAll(form.Content, "\r", ""),
-		IsNewFile:    isNewFile,
-		Signoff:      form.Signoff,
-	}); err != nil {
+	content := strings.ReplaceAll(form.Content, "\r", "")
+	var err error
+	if form.CommitChoice == frmCommitChoiceEditSession {
+		_, err = applyEditSessionChange(ctx, &files_service.EditSessionChange{
+			FromTreePath: ctx.Repo.TreePath,
+			TreePath:     form.TreePath,
+			Content:      &content,
+			IsNewFile:    isNewFile,
+		})
+	} else {
+		_, err = files_service.CreateOrUpdateRepoFile(ctx, ctx.Repo.Repository, ctx.Doer, &files_service.UpdateRepoFileOptions{
+			LastCommitID: form.LastCommit,
+			OldBranch:    ctx.Repo.BranchName,
+			NewBranch:    branchName,
+			FromTreePath: ctx.Repo.TreePath,
+			TreePath:     form.TreePath,
+			Message:      message,
+			Content:      content,
+			IsNewFile:    isNewFile,
+			Signoff:      form.Signoff,
+		})
+	}
+	if err != nil {
 		// This is where we handle all the errors thrown by files_service.CreateOrUpdateRepoFile
-		if git.IsErrNotExist(err) {
+		if git.IsErrNotExist(err) || models.IsErrRepoFileDoesNotExist(err) {
 			ctx.RenderWithErr(ctx.Tr("repo.editor.file_editing_no_longer_exists", ctx.Repo.TreePath), tplEditFile, &form)
 		} else if git_model.IsErrLFSFileLocked(err) {
 			ctx.Data["Err_TreePath"] = true
@@ -331,7 +430,9 @@ func editFilePost(ctx *context.Context, form forms.EditRepoFileForm, isNewFile b
 		_ = repo_model.UpdateRepositoryCols(ctx, &repo_model.Repository{ID: ctx.Repo.Repository.ID, IsEmpty: false}, "is_empty")
 	}
 
-	if form.CommitChoice == frmCommitChoiceNewBranch && ctx.Repo.Repository.UnitEnabled(ctx, unit.TypePullRequests) {
+	if form.CommitChoice == frmCommitChoiceEditSession {
+		ctx.Redirect(editSessionLink(ctx, ctx.Repo.BranchName))
+	} else if form.CommitChoice == frmCommitChoiceNewBranch && ctx.Repo.Repository.UnitEnabled(ctx, unit.TypePullRequests) {
 		ctx.Redirect(ctx.Repo.RepoLink + "/compare/" + util.PathEscapeSegments(ctx.Repo.BranchName) + "..." + util.PathEscapeSegments(form.NewBranchName))
 	} else {
 		ctx.Redirect(ctx.Repo.RepoLink + "/src/branch/" + util.PathEscapeSegments(branchName) + "/" + util.PathEscapeSegments(form.TreePath))
@@ -396,11 +497,18 @@ func DeleteFile(ctx *context.Context) {
 
 	ctx.Data["TreePath"] = treePath
 	canCommit := renderCommitRights(ctx)
+	editSession, err := renderEditSession(ctx)
+	if err != nil {
+		ctx.ServerError("GetUserEditSession", err)
+		return
+	}
 
 	ctx.Data["commit_summary"] = ""
 	ctx.Data["commit_message"] = ""
 	ctx.Data["last_commit"] = ctx.Repo.CommitID
-	if canCommit {
+	if editSession != nil {
+		ctx.Data["commit_choice"] = frmCommitChoiceEditSession
+	} else if canCommit {
 		ctx.Data["commit_choice"] = frmCommitChoiceDirect
 	} else {
 		ctx.Data["commit_choice"] = frmCommitChoiceNewBranch
@@ -414,6 +522,10 @@ func DeleteFile(ctx *context.Context) {
 func DeleteFilePost(ctx *context.Context) {
 	form := web.GetForm(ctx).(*forms.DeleteRepoFileForm)
 	canCommit := renderCommitRights(ctx)
+	if _, err := renderEditSession(ctx); err != nil {
+		ctx.ServerError("GetUserEditSession", err)
+		return
+	}
 	branchName := ctx.Repo.BranchName
 	if form.CommitChoice == frmCommitChoiceNewBranch {
 		branchName = form.NewBranchName
@@ -433,7 +545,7 @@ func DeleteFilePost(ctx *context.Context) {
 		return
 	}
 
-	if branchName == ctx.Repo.BranchName && !canCommit {
+	if branchName == ctx.Repo.BranchName && !canCommit && form.CommitChoice != frmCommitChoiceEditSession {
 		ctx.Data["Err_NewBranchName"] = true
 		ctx.Data["commit_choice"] = frmCommitChoiceNewBranch
 		ctx.RenderWithErr(ctx.Tr("repo.editor.cannot_commit_to_protected_branch", branchName), tplDeleteFile, &form)
@@ -449,14 +561,20 @@ func DeleteFilePost(ctx *context.Context) {
 		message += "\n\n" + form.CommitMessage
 	}
 
-	if _, err := files_service.DeleteRepoFile(ctx, ctx.Repo.Repository, ctx.Doer, &files_service.DeleteRepoFileOptions{
-		LastCommitID: form.LastCommit,
-		OldBranch:    ctx.Repo.BranchName,
-		NewBranch:    branchName,
-		TreePath:     ctx.Repo.TreePath,
-		Message:      message,
-		Signoff:      form.Signoff,
-	}); err != nil {
+	var err error
+	if form.CommitChoice == frmCommitChoiceEditSession {
+		_, err = applyEditSessionChange(ctx, &files_service.EditSessionChange{TreePath: ctx.Repo.TreePath})
+	} else {
+		_, err = files_service.DeleteRepoFile(ctx, ctx.Repo.Repository, ctx.Doer, &files_service.DeleteRepoFileOptions{
+			LastCommitID: form.LastCommit,
+			OldBranch:    ctx.Repo.BranchName,
+			NewBranch:    branchName,
+			TreePath:     ctx.Repo.TreePath,
+			Message:      message,
+			Signoff:      form.Signoff,
+		})
+	}
+	if err != nil {
 		// This is where we handle all the errors thrown by repofiles.DeleteRepoFile
 		if git.IsErrNotExist(err) || models.IsErrRepoFileDoesNotExist(err) {
 			ctx.RenderWithErr(ctx.Tr("repo.editor.file_deleting_no_longer_exists", ctx.Repo.TreePath), tplDeleteFile, &form)
@@ -517,7 +635,9 @@ func DeleteFilePost(ctx *context.Context) {
 	}
 
 	ctx.Flash.Success(ctx.Tr("repo.editor.file_delete_success", ctx.Repo.TreePath))
-	if form.CommitChoice == frmCommitChoiceNewBranch && ctx.Repo.Repository.UnitEnabled(ctx, unit.TypePullRequests) {
+	if form.CommitChoice == frmCommitChoiceEditSession {
+		ctx.Redirect(editSessionLink(ctx, ctx.Repo.BranchName))
+	} else if form.CommitChoice == frmCommitChoiceNewBranch && ctx.Repo.Repository.UnitEnabled(ctx, unit.TypePullRequests) {
 		ctx.Redirect(ctx.Repo.RepoLink + "/compare/" + util.PathEscapeSegments(ctx.Repo.BranchName) + "..." + util.PathEscapeSegments(form.NewBranchName))
 	} else {
 		treePath := path.Dir(ctx.Repo.TreePath)
@@ -0,0 +1,201 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/routers/utils"
+	"code.gitea.io/gitea/services/forms"
+	files_service "code.gitea.io/gitea/services/repository/files"
+)
+
+const tplEditSession base.TplName = "repo/editor/session"
+
+// getEditSession returns the editing session of the doer on the current branch, it responds with 404 if there is none
+func getEditSession(ctx *context.Context) *repo_model.EditSession {
+	s, err := repo_model.GetUserEditSession(ctx, ctx.Repo.Repository.ID, ctx.Doer.ID, ctx.Repo.BranchName)
+	if err != nil {
+		ctx.ServerError("GetUserEditSession", err)
+		return nil
+	} else if s == nil {
+		ctx.NotFound("GetUserEditSession", nil)
+		return nil
+	}
+	return s
+}
+
+func renderEditSessionPage(ctx *context.Context, s *repo_model.EditSession) bool {
+	ctx.Data["Title"] = ctx.Tr("repo.editor.session_title", s.BaseBranch)
+	ctx.Data["PageIsEditSession"] = true
+	ctx.Data["EditSession"] = s
+	ctx.Data["BranchLink"] = ctx.Repo.RepoLink + "/src/" + ctx.Repo.BranchNameSubURL()
+	ctx.Data["TreePath"] = ""
+
+	files, err := files_service.GetEditSessionChangedFiles(ctx.Repo.GitRepo, s)
+	if err != nil {
+		ctx.ServerError("GetEditSessionChangedFiles", err)
+		return false
+	}
+	ctx.Data["ChangedFiles"] = files
+
+	isOutdated, err := files_service.IsEditSessionOutdated(ctx.Repo.GitRepo, s)
+	if err != nil {
+		ctx.ServerError("IsEditSessionOutdated", err)
+		return false
+	}
+	ctx.Data["IsOutdated"] = isOutdated
+	return true
+}
+
+// EditSession render the page of the editing session of the doer on a branch
+func EditSession(ctx *context.Context) {
+	s := getEditSession(ctx)
+	if s == nil {
+		return
+	}
+	canCommit := renderCommitRights(ctx)
+	if !renderEditSessionPage(ctx, s) {
+		return
+	}
+
+	ctx.Data["commit_summary"] = ""
+	ctx.Data["commit_message"] = ""
+	if canCommit {
+		ctx.Data["commit_choice"] = frmCommitChoiceDirect
+	} else {
+		ctx.Data["commit_choice"] = frmCommitChoiceNewBranch
+	}
+	ctx.Data["new_branch_name"] = GetUniquePatchBranchName(ctx)
+
+	ctx.HTML(http.StatusOK, tplEditSession)
+}
+
+// EditSessionPost response for committing the changes of an editing session
+func EditSessionPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.FinishEditSessionForm)
+	s := getEditSession(ctx)
+	if s == nil {
+		return
+	}
+	canCommit := renderCommitRights(ctx)
+	if !renderEditSessionPage(ctx, s) {
+		return
+	}
+
+	newBranchName := ""
+	if form.CommitChoice == frmCommitChoiceNewBranch {
+		newBranchName = form.NewBranchName
+	}
+	ctx.Data["commit_summary"] = form.CommitSummary
+	ctx.Data["commit_message"] = form.CommitMessage
+	ctx.Data["commit_choice"] = form.CommitChoice
+	ctx.Data["new_branch_name"] = form.NewBranchName
+
+	if ctx.HasError() {
+		ctx.HTML(http.StatusOK, tplEditSession)
+		return
+	}
+
+	if newBranchName == "" && !canCommit {
+		ctx.Data["Err_NewBranchName"] = true
+		ctx.Data["commit_choice"] = frmCommitChoiceNewBranch
+		ctx.RenderWithErr(ctx.Tr("repo.editor.cannot_commit_to_protected_branch", s.BaseBranch), tplEditSession, form)
+		return
+	}
+
+	message := strings.TrimSpace(form.CommitSummary)
+	if len(message) == 0 {
+		message = ctx.Tr("repo.editor.session_commit", s.BaseBranch)
+	}
+	form.CommitMessage = strings.TrimSpace(form.CommitMessage)
+	if len(form.CommitMessage) > 0 {
+		message += "\n\n" + form.CommitMessage
+	}
+
+	branchName, err := files_service.FinishEditSession(ctx, ctx.Repo.Repository, ctx.Repo.GitRepo, ctx.Doer, s, &files_service.FinishEditSessionOptions{
+		Message:   message,
+		NewBranch: newBranchName,
+		Signoff:   form.Signoff,
+	})
+	if err != nil {
+		if files_service.IsErrEditSessionConflict(err) {
+			ctx.RenderWithErr(ctx.Tr("repo.editor.session_conflict", strings.Join(err.(files_service.ErrEditSessionConflict).Files, ", ")), tplEditSession, form)
+		} else if models.IsErrBranchAlreadyExists(err) {
+			ctx.Data["Err_NewBranchName"] = true
+			ctx.RenderWithErr(ctx.Tr("repo.editor.branch_already_exists", newBranchName), tplEditSession, form)
+		} else if git.IsErrPushRejected(err) {
+			errPushRej := err.(*git.ErrPushRejected)
+			if len(errPushRej.Message) == 0 {
+				ctx.RenderWithErr(ctx.Tr("repo.editor.push_rejected_no_message"), tplEditSession, form)
+				return
+			}
+			flashError, err := ctx.RenderToString(tplAlertDetails, map[string]interface{}{
+				"Message": ctx.Tr("repo.editor.push_rejected"),
+				"Summary": ctx.Tr("repo.editor.push_rejected_summary"),
+				"Details": utils.SanitizeFlashErrorString(errPushRej.Message),
+			})
+			if err != nil {
+				ctx.ServerError("EditSessionPost.HTMLString", err)
+				return
+			}
+			ctx.RenderWithErr(flashError, tplEditSession, form)
+		} else {
+			ctx.ServerError("FinishEditSession", err)
+		}
+		return
+	}
+
+	ctx.Flash.Success(ctx.Tr("repo.editor.session_commit_success"))
+	if newBranchName != "" && ctx.Repo.Repository.UnitEnabled(ctx, unit.TypePullRequests) {
+		ctx.Redirect(ctx.Repo.RepoLink + "/compare/" + util.PathEscapeSegments(s.BaseBranch) + "..." + util.PathEscapeSegments(branchName))
+	} else {
+		ctx.Redirect(ctx.Repo.RepoLink + "/src/branch/" + util.PathEscapeSegments(branchName))
+	}
+}
+
+// RebaseEditSessionPost updates the editing session of the doer with the new commits of its branch
+func RebaseEditSessionPost(ctx *context.Context) {
+	s := getEditSession(ctx)
+	if s == nil {
+		return
+	}
+	if err := files_service.RebaseEditSession(ctx, ctx.Repo.Repository, ctx.Doer, s); err != nil {
+		if !files_service.IsErrEditSessionConflict(err) {
+			ctx.ServerError("RebaseEditSession", err)
+			return
+		}
+		ctx.Flash.Error(ctx.Tr("repo.editor.session_conflict", strings.Join(err.(files_service.ErrEditSessionConflict).Files, ", ")))
+	} else {
+		ctx.Flash.Success(ctx.Tr("repo.editor.session_rebase_success"))
+	}
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"redirect": editSessionLink(ctx, s.BaseBranch),
+	})
+}
+
+// DiscardEditSessionPost deletes the editing session of the doer with its changes
+func DiscardEditSessionPost(ctx *context.Context) {
+	s := getEditSession(ctx)
+	if s == nil {
+		return
+	}
+	if err := files_service.DiscardEditSession(ctx, ctx.Repo.GitRepo, s); err != nil {
+		ctx.ServerError("DiscardEditSession", err)
+		return
+	}
+	ctx.Flash.Success(ctx.Tr("repo.editor.session_discard_success"))
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"redirect": ctx.Repo.RepoLink + "/src/branch/" + util.PathEscapeSegments(s.BaseBranch),
+	})
+}
@@ -0,0 +1,91 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/util"
+	embed_service "code.gitea.io/gitea/services/embed"
+)
+
+const tplEmbedWidget base.TplName = "repo/embed/widget"
+
+// prepareEmbedWidget checks the embed token of the request and loads the widget. The session of the signed-in user
+// is never used, the token alone decides what is shown.
+func prepareEmbedWidget(ctx *context.Context) (*embed_service.Widget, *embed_service.Access, string) {
+	repo, err := repo_model.GetRepositoryByOwnerAndName(ctx, ctx.Params(":username"), ctx.Params(":reponame"))
+	if err != nil {
+		if repo_model.IsErrRepoNotExist(err) {
+			ctx.NotFound("GetRepositoryByOwnerAndName", err)
+		} else {
+			ctx.ServerError("GetRepositoryByOwnerAndName", err)
+		}
+		return nil, nil, ""
+	}
+	if repo.IsPendingDeletion() {
+		ctx.NotFound("EmbedWidget", nil)
+		return nil, nil, ""
+	}
+
+	widget := repo_model.EmbedWidget(ctx.Params(":widget"))
+	origin := embed_service.RequestOrigin(ctx.Req)
+	access, err := embed_service.CheckAccess(ctx, repo, widget, ctx.FormString("token"), origin)
+	if err != nil {
+		if errors.Is(err, util.ErrNotExist) {
+			ctx.NotFound("CheckAccess", err)
+		} else if errors.Is(err, util.ErrPermissionDenied) {
+			ctx.Error(http.StatusForbidden, err.Error())
+		} else {
+			ctx.ServerError("CheckAccess", err)
+		}
+		return nil, nil, ""
+	}
+
+	w, err := embed_service.GetWidget(ctx, repo, widget, access)
+	if err != nil {
+		ctx.ServerError("GetWidget", err)
+		return nil, nil, ""
+	}
+
+	ctx.Resp.Header().Set("Cache-Control", fmt.Sprintf("private, max-age=%d", embed_service.CacheSeconds))
+	ctx.Resp.Header().Add("Vary", "Origin, Referer")
+	return w, access, origin
+}
+
+// EmbedWidget renders a widget of a repository as a page to show in the frames of the sites allowed by the embed token
+func EmbedWidget(ctx *context.Context) {
+	w, access, _ := prepareEmbedWidget(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	// only the allowed origins may show the page in a frame
+	ctx.Resp.Header().Del("X-Frame-Options")
+	ctx.Resp.Header().Set("Content-Security-Policy", fmt.Sprintf(
+		"default-src 'none'; style-src 'unsafe-inline'; img-src 'self' data:; frame-ancestors 'self' %s",
+		strings.Join(access.Token.AllowedOrigins, " ")))
+
+	ctx.Data["Widget"] = w
+	ctx.HTML(http.StatusOK, tplEmbedWidget)
+}
+
+// EmbedWidgetJSON returns the data of a widget of a repository to the scripts of the sites allowed by the embed token
+func EmbedWidgetJSON(ctx *context.Context) {
+	w, _, origin := prepareEmbedWidget(ctx)
+	if ctx.Written() {
+		return
+	}
+
+	if origin != "" {
+		ctx.Resp.Header().Set("Access-Control-Allow-Origin", origin)
+	}
+	ctx.JSON(http.StatusOK, w)
+}
@@ -8,7 +8,10 @@ import (
 	"bytes"
 	"compress/gzip"
 	gocontext "context"
+	"errors"
 	"fmt"
+	"io"
+	"net"
 	"net/http"
 	"os"
 	"path"
@@ -27,6 +30,7 @@ import (
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/metrics"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
@@ -119,6 +123,12 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 		}
 	}
 
+	// deleted repositories are hidden until they are restored or purged
+	if repoExist && repo.IsPendingDeletion() {
+		ctx.PlainText(http.StatusNotFound, "Repository not found")
+		return
+	}
+
 	// Don't allow pushing if the repo is archived
 	if repoExist && repo.IsArchived && !isPull {
 		ctx.PlainText(http.StatusForbidden, "This repo is archived. You can view files and clone it, but cannot push or open issues/pull-requests.")
@@ -180,6 +190,7 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 			repo_module.EnvPusherName + "=" + ctx.Doer.Name,
 			repo_module.EnvPusherID + fmt.Sprintf("=%d", ctx.Doer.ID),
 			repo_module.EnvAppURL + "=" + setting.AppURL,
+			repo_module.EnvPushLimits + "=" + strconv.FormatBool(setting.PushLimitsEnabled()),
 		}
 
 		if repoExist {
@@ -195,24 +206,12 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 					ctx.ServerError("GetTaskByID", err)
 					return
 				}
-				if task.RepoID != repo.ID {
+				taskMode := task.RepoAccessMode(repo)
+				if taskMode == perm.AccessModeNone || accessMode > taskMode {
 					ctx.PlainText(http.StatusForbidden, "User permission denied")
 					return
 				}
-
-				if task.IsForkPullRequest {
-					if accessMode > perm.AccessModeRead {
-						ctx.PlainText(http.StatusForbidden, "User permission denied")
-						return
-					}
-					environ = append(environ, fmt.Sprintf("%s=%d", repo_module.EnvActionPerm, perm.AccessModeRead))
-				} else {
-					if accessMode > perm.AccessModeWrite {
-						ctx.PlainText(http.StatusForbidden, "User permission denied")
-						return
-					}
-					environ = append(environ, fmt.Sprintf("%s=%d", repo_module.EnvActionPerm, perm.AccessModeWrite))
-				}
+				environ = append(environ, fmt.Sprintf("%s=%d", repo_module.EnvActionPerm, taskMode))
 			} else {
 				p, err := access_model.GetUserRepoPermission(ctx, repo, ctx.Doer)
 				if err != nil {
@@ -224,6 +223,12 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 					ctx.PlainText(http.StatusNotFound, "Repository not found")
 					return
 				}
+
+				// git can only transfer complete trees, partial clone filters are chosen by the client and can not limit the paths
+				if unitType == unit.TypeCode && p.IsCodePathRestricted() {
+					ctx.PlainText(http.StatusForbidden, "User may only read some paths of the repository, use the web interface or the API instead")
+					return
+				}
 			}
 
 			if !isPull && repo.IsMirror {
@@ -307,7 +312,16 @@ func httpBase(ctx *context.Context) (h *serviceHandler) {
 		dir = repo_model.RepoPath(username, wikiRepoName)
 	}
 
-	return &serviceHandler{cfg, w, r, dir, cfg.Env}
+	scheduleKey := git.SchedulerKey{Repo: dir}
+	if ctx.Doer != nil {
+		scheduleKey.User = ctx.Doer.Name
+	} else if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		scheduleKey.User = "ip:" + host
+	} else {
+		scheduleKey.User = "ip:" + r.RemoteAddr
+	}
+
+	return &serviceHandler{cfg, w, r, dir, cfg.Env, scheduleKey, repo.Size}
 }
 
 var (
@@ -329,7 +343,7 @@ func dummyInfoRefs(ctx *context.Context) {
 			}
 		}()
 
-		if err := git.InitRepository(ctx, tmpDir, true); err != nil {
+		if err := git.InitRepository(ctx, tmpDir, true, git.ObjectFormatSHA1); err != nil {
 			log.Error("Failed to init bare repo for git-receive-pack cache: %v", err)
 			return
 		}
@@ -364,6 +378,11 @@ type serviceHandler struct {
 	r       *http.Request
 	dir     string
 	environ []string
+
+	// scheduleKey limits the concurrent git commands per repository and per user
+	scheduleKey git.SchedulerKey
+	// repoSize selects the timeouts and size limits of the git commands
+	repoSize int64
 }
 
 func (h *serviceHandler) setHeaderNoCache() {
@@ -417,17 +436,85 @@ func (h *serviceHandler) sendFile(contentType, file string) {
 // one or more key=value pairs separated by colons
 var safeGitProtocolHeader = regexp.MustCompile(`^[0-9a-zA-Z]+=[0-9a-zA-Z]+(:[0-9a-zA-Z]+=[0-9a-zA-Z]+)*$`)
 
-func prepareGitCmdWithAllowedService(service string, h *serviceHandler) (*git.Command, error) {
+// gitProtocol returns the Git-Protocol header of the request without the protocol versions which are disabled
+func gitProtocol(r *http.Request) string {
+	protocol := r.Header.Get("Git-Protocol")
+	if protocol == "" || !safeGitProtocolHeader.MatchString(protocol) {
+		return ""
+	}
+	if setting.GitHTTP.EnableProtocolV2 {
+		return protocol
+	}
+	params := strings.Split(protocol, ":")
+	kept := params[:0]
+	for _, param := range params {
+		if param != "version=2" {
+			kept = append(kept, param)
+		}
+	}
+	return strings.Join(kept, ":")
+}
+
+// gitProtocolVersion returns the protocol version requested by a Git-Protocol header for the metrics
+func gitProtocolVersion(protocol string) string {
+	for _, param := range strings.Split(protocol, ":") {
+		if strings.HasPrefix(param, "version=") {
+			return "v" + strings.TrimPrefix(param, "version=")
+		}
+	}
+	return "v0"
+}
+
+func prepareGitCmdWithAllowedService(ctx gocontext.Context, service string, h *serviceHandler) (*git.Command, error) {
 	if service == "receive-pack" && h.cfg.ReceivePack {
-		return git.NewCommand(h.r.Context(), "receive-pack"), nil
+		return git.NewCommand(ctx, "receive-pack"), nil
 	}
 	if service == "upload-pack" && h.cfg.UploadPack {
-		return git.NewCommand(h.r.Context(), "upload-pack"), nil
+		cmd := git.NewCommand(ctx)
+		if !setting.GitHTTP.AllowFilter {
+			cmd.AddArguments("-c", "uploadpack.allowFilter=false")
+		}
+		if setting.GitHTTP.AllowRefInWant {
+			cmd.AddArguments("-c", "uploadpack.allowRefInWant=true")
+		}
+		return cmd.AddArguments("upload-pack"), nil
 	}
 
 	return nil, fmt.Errorf("service %q is not allowed", service)
 }
 
+var errGitRequestTooLarge = errors.New("request is larger than allowed")
+
+// limitedRequestBody counts the bytes read from a request and fails once more than max bytes were read
+type limitedRequestBody struct {
+	r        io.Reader
+	n        int64
+	max      int64
+	exceeded bool
+}
+
+func (l *limitedRequestBody) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.n += int64(n)
+	if l.max >= 0 && l.n > l.max {
+		l.exceeded = true
+		return n, errGitRequestTooLarge
+	}
+	return n, err
+}
+
+// countingResponseWriter counts the bytes written to a response
+type countingResponseWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (c *countingResponseWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+	return n, err
+}
+
 func serviceRPC(h *serviceHandler, service string) {
 	defer func() {
 		if err := h.r.Body.Close(); err != nil {
@@ -442,31 +529,63 @@ func serviceRPC(h *serviceHandler, service string) {
 		return
 	}
 
-	cmd, err := prepareGitCmdWithAllowedService(service, h)
+	sizeClass, limits := setting.GitHTTPLimitsForRepoSize(h.repoSize)
+	timeout, maxSize := limits.UploadPackTimeout, limits.MaxUploadPackRequestSize
+	if service == "receive-pack" {
+		timeout, maxSize = limits.ReceivePackTimeout, limits.MaxReceivePackSize
+	}
+	ctx := h.r.Context()
+	if timeout > 0 {
+		var cancel gocontext.CancelFunc
+		ctx, cancel = gocontext.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	cmd, err := prepareGitCmdWithAllowedService(ctx, service, h)
 	if err != nil {
 		log.Error("Failed to prepareGitCmdWithService: %v", err)
 		h.w.WriteHeader(http.StatusUnauthorized)
 		return
 	}
 
-	h.w.Header().Set("Content-Type", fmt.Sprintf("application/x-git-%s-result", service))
+	protocol := gitProtocol(h.r)
+	start := time.Now()
+	reqBody := &limitedRequestBody{r: h.r.Body, max: maxSize}
+	respBody := &countingResponseWriter{w: h.w}
+	result := metrics.GitHTTPResultError
+	defer func() {
+		metrics.RecordGitHTTPRequest(metrics.GitHTTPKey{
+			Service:   service,
+			SizeClass: sizeClass,
+			Protocol:  gitProtocolVersion(protocol),
+			Result:    result,
+		}, reqBody.n, respBody.n, time.Since(start))
+	}()
 
-	reqBody := h.r.Body
+	// the length of a compressed request doesn't tell the size of its content
+	if maxSize >= 0 && h.r.ContentLength > maxSize && h.r.Header.Get("Content-Encoding") != "gzip" {
+		result = metrics.GitHTTPResultTooLarge
+		h.w.WriteHeader(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	h.w.Header().Set("Content-Type", fmt.Sprintf("application/x-git-%s-result", service))
 
 	// Handle GZIP.
 	if h.r.Header.Get("Content-Encoding") == "gzip" {
-		reqBody, err = gzip.NewReader(reqBody)
+		gzipReader, err := gzip.NewReader(h.r.Body)
 		if err != nil {
 			log.Error("Fail to create gzip reader: %v", err)
 			h.w.WriteHeader(http.StatusInternalServerError)
 			return
 		}
+		reqBody.r = gzipReader
 	}
 
 	// set this for allow pre-receive and post-receive execute
 	h.environ = append(h.environ, "SSH_ORIGINAL_COMMAND="+service)
 
-	if protocol := h.r.Header.Get("Git-Protocol"); protocol != "" && safeGitProtocolHeader.MatchString(protocol) {
+	if protocol != "" {
 		h.environ = append(h.environ, "GIT_PROTOCOL="+protocol)
 	}
 
@@ -476,16 +595,35 @@ func serviceRPC(h *serviceHandler, service string) {
 	if err := cmd.Run(&git.RunOpts{
 		Dir:               h.dir,
 		Env:               append(os.Environ(), h.environ...),
-		Stdout:            h.w,
+		Stdout:            respBody,
 		Stdin:             reqBody,
 		Stderr:            &stderr,
 		UseContextTimeout: true,
+		Schedule:          &h.scheduleKey,
 	}); err != nil {
-		if err.Error() != "signal: killed" {
+		switch {
+		case errors.Is(err, git.ErrSchedulerBusy):
+			result = metrics.GitHTTPResultBusy
+			log.Warn("Too many concurrent git commands, rejected %s in %s for %s", service, h.dir, h.scheduleKey.User)
+			h.w.WriteHeader(http.StatusServiceUnavailable)
+		case reqBody.exceeded:
+			result = metrics.GitHTTPResultTooLarge
+			log.Warn("Rejected %s in %s for %s: request is larger than %d bytes", service, h.dir, h.scheduleKey.User, maxSize)
+			if respBody.n == 0 {
+				h.w.WriteHeader(http.StatusRequestEntityTooLarge)
+			}
+		case errors.Is(ctx.Err(), gocontext.DeadlineExceeded):
+			result = metrics.GitHTTPResultTimeout
+			log.Warn("Timeout of %s in %s for %s after %v", service, h.dir, h.scheduleKey.User, timeout)
+			if respBody.n == 0 {
+				h.w.WriteHeader(http.StatusGatewayTimeout)
+			}
+		case err.Error() != "signal: killed":
 			log.Error("Fail to serve RPC(%s) in %s: %v - %s", service, h.dir, err, stderr.String())
 		}
 		return
 	}
+	result = metrics.GitHTTPResultOK
 }
 
 // ServiceUploadPack implements Git Smart HTTP protocol
@@ -536,9 +674,9 @@ func GetInfoRefs(ctx *context.Context) {
 	}
 	h.setHeaderNoCache()
 	service := getServiceType(h.r)
-	cmd, err := prepareGitCmdWithAllowedService(service, h)
+	cmd, err := prepareGitCmdWithAllowedService(ctx, service, h)
 	if err == nil {
-		if protocol := h.r.Header.Get("Git-Protocol"); protocol != "" && safeGitProtocolHeader.MatchString(protocol) {
+		if protocol := gitProtocol(h.r); protocol != "" {
 			h.environ = append(h.environ, "GIT_PROTOCOL="+protocol)
 		}
 		h.environ = append(os.Environ(), h.environ...)
@@ -4,8 +4,13 @@
 package repo
 
 import (
+	"io"
+	"net/http"
+	"strings"
 	"testing"
 
+	"code.gitea.io/gitea/modules/setting"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -40,3 +45,41 @@ func TestContainsParentDirectorySeparator(t *testing.T) {
 		assert.EqualValues(t, tests[i].b, containsParentDirectorySeparator(tests[i].v))
 	}
 }
+
+func TestGitProtocol(t *testing.T) {
+	req, _ := http.NewRequest("POST", "/user2/repo1.git/git-upload-pack", nil)
+	assert.Equal(t, "", gitProtocol(req))
+	assert.Equal(t, "v0", gitProtocolVersion(""))
+
+	req.Header.Set("Git-Protocol", "version=2:object-format=sha1")
+	assert.Equal(t, "version=2:object-format=sha1", gitProtocol(req))
+	assert.Equal(t, "v2", gitProtocolVersion(gitProtocol(req)))
+
+	setting.GitHTTP.EnableProtocolV2 = false
+	defer func() {
+		setting.GitHTTP.EnableProtocolV2 = true
+	}()
+	assert.Equal(t, "object-format=sha1", gitProtocol(req))
+	assert.Equal(t, "v0", gitProtocolVersion(gitProtocol(req)))
+
+	req.Header.Set("Git-Protocol", "version=2; rm -rf /")
+	assert.Equal(t, "", gitProtocol(req))
+}
+
+func TestLimitedRequestBody(t *testing.T) {
+	r := &limitedRequestBody{r: strings.NewReader("0123456789"), max: 10}
+	data, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Len(t, data, 10)
+	assert.False(t, r.exceeded)
+
+	r = &limitedRequestBody{r: strings.NewReader("0123456789"), max: 5}
+	_, err = io.ReadAll(r)
+	assert.ErrorIs(t, err, errGitRequestTooLarge)
+	assert.True(t, r.exceeded)
+
+	r = &limitedRequestBody{r: strings.NewReader("0123456789"), max: -1}
+	_, err = io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 10, r.n)
+}
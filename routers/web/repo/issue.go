@@ -48,10 +48,13 @@ import (
 	"code.gitea.io/gitea/routers/utils"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
 	"code.gitea.io/gitea/services/convert"
+	coverage_service "code.gitea.io/gitea/services/coverage"
 	"code.gitea.io/gitea/services/forms"
 	issue_service "code.gitea.io/gitea/services/issue"
+	"code.gitea.io/gitea/services/moderation"
 	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
+	spam_service "code.gitea.io/gitea/services/spam"
 )
 
 const (
@@ -99,7 +102,7 @@ func MustAllowUserComment(ctx *context.Context) {
 		return
 	}
 
-	if issue.IsLocked && !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) && !ctx.Doer.IsAdmin {
+	if issue.IsLockedFor(ctx.Repo.Permission, ctx.Doer) {
 		ctx.Flash.Error(ctx.Tr("repo.issues.comment_on_locked"))
 		ctx.Redirect(issue.Link())
 		return
@@ -314,6 +317,11 @@ func issues(ctx *context.Context, milestoneID, projectID int64, isPullOption uti
 	}
 	ctx.Data["Assignees"] = makeSelfOnTop(ctx, assigneeUsers)
 
+	handleUserStatuses(ctx, assigneeUsers)
+	if ctx.Written() {
+		return
+	}
+
 	handleTeamMentions(ctx)
 	if ctx.Written() {
 		return
@@ -502,6 +510,11 @@ func RetrieveRepoMilestonesAndAssignees(ctx *context.Context, repo *repo_model.R
 	}
 	ctx.Data["Assignees"] = makeSelfOnTop(ctx, assigneeUsers)
 
+	handleUserStatuses(ctx, assigneeUsers)
+	if ctx.Written() {
+		return
+	}
+
 	handleTeamMentions(ctx)
 }
 
@@ -606,6 +619,11 @@ func RetrieveRepoReviewers(ctx *context.Context, repo *repo_model.Repository, is
 			return
 		}
 
+		handleUserStatuses(ctx, reviewers)
+		if ctx.Written() {
+			return
+		}
+
 		teamReviewers, err = repo_service.GetReviewerTeams(ctx, repo)
 		if err != nil {
 			ctx.ServerError("GetReviewerTeams", err)
@@ -986,6 +1004,11 @@ func DeleteIssue(ctx *context.Context) {
 	}
 
 	if err := issue_service.DeleteIssue(ctx, ctx.Doer, ctx.Repo.GitRepo, issue); err != nil {
+		if issues_model.IsErrUnderLegalHold(err) {
+			ctx.Flash.Error(ctx.Tr("repo.legal_hold.deletion_blocked"))
+			ctx.Redirect(issue.Link())
+			return
+		}
 		ctx.ServerError("DeleteIssueByID", err)
 		return
 	}
@@ -1153,10 +1176,26 @@ func NewIssuePost(ctx *context.Context) {
 		Ref:         form.Ref,
 	}
 
+	if held, err := spam_service.HoldIssueIfSpam(ctx, repo, issue, labelIDs, attachments, assigneeIDs, ctx.FormString(spam_service.HoneypotField)); err != nil {
+		if flashInteractionDenied(ctx, err) {
+			ctx.Redirect(ctx.Link)
+			return
+		}
+		ctx.ServerError("HoldIssueIfSpam", err)
+		return
+	} else if held {
+		ctx.Flash.Info(ctx.Tr("repo.issues.held_for_review"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/issues")
+		return
+	}
+
 	if err := issue_service.NewIssue(ctx, repo, issue, labelIDs, attachments, assigneeIDs); err != nil {
 		if repo_model.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err.Error())
 			return
+		} else if flashInteractionDenied(ctx, err) {
+			ctx.Redirect(ctx.Link)
+			return
 		}
 		ctx.ServerError("NewIssue", err)
 		return
@@ -1182,6 +1221,19 @@ func NewIssuePost(ctx *context.Context) {
 	}
 }
 
+// flashInteractionDenied shows why the user is not allowed to interact with the repository, it returns false for other errors
+func flashInteractionDenied(ctx *context.Context, err error) bool {
+	switch {
+	case moderation.IsErrBlocked(err):
+		ctx.Flash.Error(ctx.Tr("repo.issues.blocked_by_owner"))
+	case moderation.IsErrInteractionLimited(err):
+		ctx.Flash.Error(ctx.Tr("repo.issues.interaction_limited", err.(moderation.ErrInteractionLimited).MinAccountAgeDays))
+	default:
+		return false
+	}
+	return true
+}
+
 // roleDescriptor returns the Role Descriptor for a comment in/with the given repo, poster and issue
 func roleDescriptor(ctx stdCtx.Context, repo *repo_model.Repository, poster *user_model.User, issue *issues_model.Issue, hasOriginalAuthor bool) (issues_model.RoleDescriptor, error) {
 	if hasOriginalAuthor {
@@ -1774,6 +1826,15 @@ func ViewIssue(ctx *context.Context) {
 			ctx.Data["IsBlockedByChangedProtectedFiles"] = len(pull.ChangedProtectedFiles) != 0
 			ctx.Data["ChangedProtectedFilesNum"] = len(pull.ChangedProtectedFiles)
 			ctx.Data["ShowMergeInstructions"] = showMergeInstructions
+			if pb.MinDiffCoverage > 0 {
+				diffCoverage, err := coverage_service.GetPullRequestDiffCoverage(ctx, pull)
+				if err != nil {
+					ctx.ServerError("GetPullRequestDiffCoverage", err)
+					return
+				}
+				ctx.Data["DiffCoverage"] = diffCoverage
+				ctx.Data["IsBlockedByDiffCoverage"] = diffCoverage == nil || !diffCoverage.Satisfies(pb.MinDiffCoverage)
+			}
 		}
 		ctx.Data["WillSign"] = false
 		if ctx.Doer != nil {
@@ -1854,6 +1915,11 @@ func ViewIssue(ctx *context.Context) {
 		return
 	}
 
+	handleUserStatuses(ctx, participants)
+	if ctx.Written() {
+		return
+	}
+
 	ctx.Data["Participants"] = participants
 	ctx.Data["NumParticipants"] = len(participants)
 	ctx.Data["Issue"] = issue
@@ -1864,6 +1930,7 @@ func ViewIssue(ctx *context.Context) {
 	ctx.Data["HasProjectsWritePermission"] = ctx.Repo.CanWrite(unit.TypeProjects)
 	ctx.Data["IsRepoAdmin"] = ctx.IsSigned && (ctx.Repo.IsAdmin() || ctx.Doer.IsAdmin)
 	ctx.Data["LockReasons"] = setting.Repository.Issue.LockReasons
+	ctx.Data["IsLockedForDoer"] = issue.IsLockedFor(ctx.Repo.Permission, ctx.Doer)
 	ctx.Data["RefEndName"] = git.RefEndName(issue.Ref)
 
 	var hiddenCommentTypes *big.Int
@@ -2090,6 +2157,10 @@ func UpdateIssueContent(ctx *context.Context) {
 	// when update the request doesn't intend to update attachments (eg: change checkbox state), ignore attachment updates
 	if !ctx.FormBool("ignore_attachments") {
 		if err := updateAttachments(ctx, issue, ctx.FormStrings("files[]")); err != nil {
+			if issues_model.IsErrUnderLegalHold(err) {
+				ctx.Error(http.StatusForbidden, ctx.Tr("repo.legal_hold.deletion_blocked"))
+				return
+			}
 			ctx.ServerError("UpdateAttachments", err)
 			return
 		}
@@ -2755,7 +2826,7 @@ func NewComment(ctx *context.Context) {
 		return
 	}
 
-	if issue.IsLocked && !ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull) && !ctx.Doer.IsAdmin {
+	if issue.IsLockedFor(ctx.Repo.Permission, ctx.Doer) {
 		ctx.Flash.Error(ctx.Tr("repo.issues.comment_on_locked"))
 		ctx.Redirect(issue.Link())
 		return
@@ -2900,7 +2971,9 @@ func NewComment(ctx *context.Context) {
 
 	comment, err := issue_service.CreateIssueComment(ctx, ctx.Doer, ctx.Repo.Repository, issue, form.Content, attachments)
 	if err != nil {
-		ctx.ServerError("CreateIssueComment", err)
+		if !flashInteractionDenied(ctx, err) {
+			ctx.ServerError("CreateIssueComment", err)
+		}
 		return
 	}
 
@@ -2951,6 +3024,10 @@ func UpdateCommentContent(ctx *context.Context) {
 	// when the update request doesn't intend to update attachments (eg: change checkbox state), ignore attachment updates
 	if !ctx.FormBool("ignore_attachments") {
 		if err := updateAttachments(ctx, comment, ctx.FormStrings("files[]")); err != nil {
+			if issues_model.IsErrUnderLegalHold(err) {
+				ctx.Error(http.StatusForbidden, ctx.Tr("repo.legal_hold.deletion_blocked"))
+				return
+			}
 			ctx.ServerError("UpdateAttachments", err)
 			return
 		}
@@ -2995,6 +3072,10 @@ func DeleteComment(ctx *context.Context) {
 	}
 
 	if err = issue_service.DeleteComment(ctx, ctx.Doer, comment); err != nil {
+		if issues_model.IsErrUnderLegalHold(err) {
+			ctx.Error(http.StatusForbidden, ctx.Tr("repo.legal_hold.deletion_blocked"))
+			return
+		}
 		ctx.ServerError("DeleteComment", err)
 		return
 	}
@@ -3282,6 +3363,9 @@ func updateAttachments(ctx *context.Context, item interface{}, files []string) e
 		if util.SliceContainsString(files, attachments[i].UUID) {
 			continue
 		}
+		if err := issues_model.CheckAttachmentLegalHold(ctx, attachments[i]); err != nil {
+			return err
+		}
 		if err := repo_model.DeleteAttachment(attachments[i], true); err != nil {
 			return err
 		}
@@ -3391,6 +3475,32 @@ func combineLabelComments(issue *issues_model.Issue) {
 	}
 }
 
+// handleUserStatuses adds the statuses of the given users to the "UserStatuses" map keyed
+// by user name, it is used to show statuses in mention suggestions and the reviewer picker
+func handleUserStatuses(ctx *context.Context, users []*user_model.User) {
+	statusMap, _ := ctx.Data["UserStatuses"].(map[string]*user_model.UserStatus)
+	if statusMap == nil {
+		statusMap = make(map[string]*user_model.UserStatus, len(users))
+	}
+	ids := make([]int64, 0, len(users))
+	for _, u := range users {
+		if _, ok := statusMap[u.Name]; !ok {
+			ids = append(ids, u.ID)
+		}
+	}
+	statuses, err := user_model.GetUserStatuses(ctx, ids)
+	if err != nil {
+		ctx.ServerError("GetUserStatuses", err)
+		return
+	}
+	for _, u := range users {
+		if status, ok := statuses[u.ID]; ok {
+			statusMap[u.Name] = status
+		}
+	}
+	ctx.Data["UserStatuses"] = statusMap
+}
+
 // get all teams that current user can mention
 func handleTeamMentions(ctx *context.Context) {
 	if ctx.Doer == nil || !ctx.Repo.Owner.IsOrganization() {
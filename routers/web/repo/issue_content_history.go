@@ -107,6 +107,14 @@ func canSoftDeleteContentHistory(ctx *context.Context, issue *issues_model.Issue
 			canSoftDelete = canSoftDelete && (history.CommentID == comment.ID)
 		}
 	}
+	if canSoftDelete {
+		// the revisions of held issues are kept, they can only be redacted by the site admins
+		held, err := issues_model.IsUnderLegalHold(ctx, issue.RepoID, issue.ID)
+		if err != nil {
+			log.Error("IsUnderLegalHold: %v", err)
+		}
+		canSoftDelete = err == nil && !held
+	}
 	return canSoftDelete
 }
 
@@ -4,8 +4,12 @@
 package repo
 
 import (
+	"time"
+
 	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/services/forms"
 )
@@ -31,10 +35,35 @@ func LockIssue(ctx *context.Context) {
 		return
 	}
 
+	level := issues_model.IssueLockLevel(ctx.FormString("level"))
+	if level != "" && !level.IsValid() {
+		ctx.Flash.Error(ctx.Tr("repo.issues.lock.level_not_allowed"))
+		ctx.Redirect(issue.Link())
+		return
+	}
+	if !issue.CanChangeLock(ctx.Repo.Permission, level) {
+		ctx.Flash.Error(ctx.Tr("repo.issues.lock.level_not_allowed"))
+		ctx.Redirect(issue.Link())
+		return
+	}
+
+	var until timeutil.TimeStamp
+	if untilStr := ctx.FormString("until"); untilStr != "" {
+		date, err := time.ParseInLocation("2006-01-02", untilStr, setting.DefaultUILocation)
+		if err != nil || !date.After(time.Now()) {
+			ctx.Flash.Error(ctx.Tr("repo.issues.lock.invalid_until"))
+			ctx.Redirect(issue.Link())
+			return
+		}
+		until = timeutil.TimeStamp(date.Unix())
+	}
+
 	if err := issues_model.LockIssue(&issues_model.IssueLockOptions{
 		Doer:   ctx.Doer,
 		Issue:  issue,
 		Reason: form.Reason,
+		Level:  level,
+		Until:  until,
 	}); err != nil {
 		ctx.ServerError("LockIssue", err)
 		return
@@ -56,6 +85,12 @@ func UnlockIssue(ctx *context.Context) {
 		return
 	}
 
+	if !issue.CanChangeLock(ctx.Repo.Permission, "") {
+		ctx.Flash.Error(ctx.Tr("repo.issues.lock.level_not_allowed"))
+		ctx.Redirect(issue.Link())
+		return
+	}
+
 	if err := issues_model.UnlockIssue(&issues_model.IssueLockOptions{
 		Doer:  ctx.Doer,
 		Issue: issue,
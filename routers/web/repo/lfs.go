@@ -364,7 +364,7 @@ func LFSDelete(ctx *context.Context) {
 	// Please note a similar condition happens in models/repo.go DeleteRepository
 	if count == 0 {
 		oidPath := path.Join(oid[0:2], oid[2:4], oid[4:])
-		err = storage.LFS.Delete(oidPath)
+		err = storage.LFSOfRegion(ctx.Repo.Repository.StorageRegion()).Delete(oidPath)
 		if err != nil {
 			ctx.ServerError("LFSDelete", err)
 			return
@@ -391,7 +391,7 @@ func LFSFileFind(ctx *context.Context) {
 	var hash git.SHA1
 	if len(sha) == 0 {
 		pointer := lfs.Pointer{Oid: oid, Size: size}
-		hash = git.ComputeBlobHash([]byte(pointer.StringContent()))
+		hash = git.ComputeBlobHash(ctx.Repo.GitRepo.ObjectFormat(), []byte(pointer.StringContent()))
 		sha = hash.String()
 	} else {
 		hash = git.MustIDFromString(sha)
@@ -442,8 +442,8 @@ func LFSPointerFiles(ctx *context.Context) {
 
 		results := []pointerResult{}
 
-		contentStore := lfs.NewContentStore()
 		repo := ctx.Repo.Repository
+		contentStore := lfs.NewContentStoreOfRegion(repo.StorageRegion())
 
 		for pointerBlob := range pointerChan {
 			numPointers++
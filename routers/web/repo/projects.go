@@ -316,6 +316,18 @@ func ViewProject(ctx *context.Context) {
 		return
 	}
 
+	var doerID int64
+	if ctx.Doer != nil {
+		doerID = ctx.Doer.ID
+	}
+	viewSetting, err := project_model.GetViewSetting(ctx, project.ID, doerID)
+	if err != nil {
+		ctx.ServerError("GetViewSetting", err)
+		return
+	}
+	ctx.Data["FilterOptions"] = issues_model.GetProjectFilterOptions(issuesMap)
+	issuesMap = issues_model.FilterProjectIssues(issuesMap, viewSetting)
+
 	if project.CardType != project_model.CardTypeTextOnly {
 		issuesAttachmentMap := make(map[int64][]*attachment_model.Attachment)
 		for _, issuesList := range issuesMap {
@@ -366,10 +378,48 @@ func ViewProject(ctx *context.Context) {
 	ctx.Data["Project"] = project
 	ctx.Data["IssuesMap"] = issuesMap
 	ctx.Data["Boards"] = boards
+	ctx.Data["ViewSetting"] = viewSetting
+	ctx.Data["Swimlanes"] = issues_model.GroupProjectIssuesIntoSwimlanes(issuesMap, boards, viewSetting.Swimlane)
 
 	ctx.HTML(http.StatusOK, tplProjectsView)
 }
 
+// UpdateProjectViewSetting changes how the user views the boards of a project
+func UpdateProjectViewSetting(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.ProjectViewSettingForm)
+	project, err := project_model.GetProjectByID(ctx, ctx.ParamsInt64(":id"))
+	if err != nil {
+		if project_model.IsErrProjectNotExist(err) {
+			ctx.NotFound("", nil)
+		} else {
+			ctx.ServerError("GetProjectByID", err)
+		}
+		return
+	}
+	if project.RepoID != ctx.Repo.Repository.ID {
+		ctx.NotFound("", nil)
+		return
+	}
+	if !project_model.IsSwimlaneTypeValid(form.Swimlane) {
+		ctx.Error(http.StatusBadRequest, "invalid swimlane type")
+		return
+	}
+
+	if err := project_model.SaveViewSetting(ctx, &project_model.ViewSetting{
+		ProjectID:   project.ID,
+		UserID:      ctx.Doer.ID,
+		Swimlane:    form.Swimlane,
+		AssigneeID:  form.AssigneeID,
+		LabelID:     form.LabelID,
+		MilestoneID: form.MilestoneID,
+	}); err != nil {
+		ctx.ServerError("SaveViewSetting", err)
+		return
+	}
+
+	ctx.Redirect(project.Link())
+}
+
 // UpdateIssueProject change an issue's project
 func UpdateIssueProject(ctx *context.Context) {
 	issues := getActionIssues(ctx)
@@ -549,6 +599,25 @@ func EditProjectBoard(ctx *context.Context) {
 		board.Sorting = form.Sorting
 	}
 
+	if form.WipLimit != nil {
+		if *form.WipLimit < 0 {
+			ctx.JSON(http.StatusUnprocessableEntity, map[string]string{
+				"message": ctx.Tr("repo.projects.column.wip_limit_invalid"),
+			})
+			return
+		}
+		board.WipLimit = *form.WipLimit
+	}
+	if form.WipLimitMode != nil {
+		if !project_model.IsWipLimitModeValid(*form.WipLimitMode) {
+			ctx.JSON(http.StatusUnprocessableEntity, map[string]string{
+				"message": ctx.Tr("repo.projects.column.wip_limit_invalid"),
+			})
+			return
+		}
+		board.WipLimitMode = *form.WipLimitMode
+	}
+
 	if err := project_model.UpdateBoard(ctx, board); err != nil {
 		ctx.ServerError("UpdateProjectBoard", err)
 		return
@@ -687,12 +756,23 @@ func MoveIssues(ctx *context.Context) {
 		}
 	}
 
-	if err = project_model.MoveIssuesOnProjectBoard(board, sortedIssueIDs); err != nil {
+	overWipLimit, err := project_model.MoveIssuesOnProjectBoard(board, sortedIssueIDs)
+	if err != nil {
+		if project_model.IsErrProjectBoardWipLimitExceeded(err) {
+			ctx.JSON(http.StatusUnprocessableEntity, map[string]string{
+				"message": ctx.Tr("repo.projects.column.wip_limit_blocked", board.Title, board.WipLimit),
+			})
+			return
+		}
 		ctx.ServerError("MoveIssuesOnProjectBoard", err)
 		return
 	}
 
-	ctx.JSON(http.StatusOK, map[string]interface{}{
+	resp := map[string]interface{}{
 		"ok": true,
-	})
+	}
+	if overWipLimit {
+		resp["message"] = ctx.Tr("repo.projects.column.wip_limit_exceeded", board.Title, board.WipLimit)
+	}
+	ctx.JSON(http.StatusOK, resp)
 }
@@ -41,10 +41,12 @@ import (
 	"code.gitea.io/gitea/routers/utils"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
 	"code.gitea.io/gitea/services/automerge"
+	codescanning_service "code.gitea.io/gitea/services/codescanning"
 	"code.gitea.io/gitea/services/forms"
 	"code.gitea.io/gitea/services/gitdiff"
 	pull_service "code.gitea.io/gitea/services/pull"
 	repo_service "code.gitea.io/gitea/services/repository"
+	spam_service "code.gitea.io/gitea/services/spam"
 
 	"github.com/gobwas/glob"
 )
@@ -756,6 +758,26 @@ func ViewPullFiles(ctx *context.Context) {
 		return
 	}
 
+	coverageReport, err := git_model.GetLatestCoverageReportByCommit(ctx, ctx.Repo.Repository.ID, headCommitID)
+	if err != nil {
+		ctx.ServerError("GetLatestCoverageReportByCommit", err)
+		return
+	}
+	if coverageReport != nil {
+		if err = diff.LoadCoverage(ctx, coverageReport); err != nil {
+			ctx.ServerError("LoadCoverage", err)
+			return
+		}
+		ctx.Data["CoverageReport"] = coverageReport
+	}
+
+	codeScanningAlerts, err := codescanning_service.GetPullRequestAlerts(ctx, pull)
+	if err != nil {
+		ctx.ServerError("GetPullRequestAlerts", err)
+		return
+	}
+	diff.LoadCodeScanningAlerts(codeScanningAlerts)
+
 	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pull.BaseRepoID, pull.BaseBranch)
 	if err != nil {
 		ctx.ServerError("LoadProtectedBranch", err)
@@ -801,6 +823,11 @@ func ViewPullFiles(ctx *context.Context) {
 	}
 	ctx.Data["Assignees"] = makeSelfOnTop(ctx, assigneeUsers)
 
+	handleUserStatuses(ctx, assigneeUsers)
+	if ctx.Written() {
+		return
+	}
+
 	handleTeamMentions(ctx)
 	if ctx.Written() {
 		return
@@ -826,6 +853,13 @@ func ViewPullFiles(ctx *context.Context) {
 	ctx.Data["CurrentReview"] = currentReview
 	ctx.Data["PendingCodeCommentNumber"] = numPendingCodeComments
 
+	reviewChecklist, err := issues_model.GetReviewChecklist(ctx, ctx.Repo.Repository.ID)
+	if err != nil {
+		ctx.ServerError("GetReviewChecklist", err)
+		return
+	}
+	ctx.Data["ReviewChecklist"] = reviewChecklist
+
 	getBranchData(ctx, issue)
 	ctx.Data["IsIssuePoster"] = ctx.IsSigned && issue.IsPoster(ctx.Doer.ID)
 	ctx.Data["HasIssuesOrPullsWritePermission"] = ctx.Repo.CanWriteIssuesOrPulls(issue.IsPull)
@@ -1259,10 +1293,26 @@ func CompareAndPullRequestPost(ctx *context.Context) {
 	// FIXME: check error in the case two people send pull request at almost same time, give nice error prompt
 	// instead of 500.
 
+	if held, err := spam_service.HoldPullRequestIfSpam(ctx, repo, pullIssue, labelIDs, attachments, pullRequest, assigneeIDs, ctx.FormString(spam_service.HoneypotField)); err != nil {
+		if flashInteractionDenied(ctx, err) {
+			ctx.Redirect(ctx.Link)
+			return
+		}
+		ctx.ServerError("HoldPullRequestIfSpam", err)
+		return
+	} else if held {
+		ctx.Flash.Info(ctx.Tr("repo.pulls.held_for_review"))
+		ctx.Redirect(ctx.Repo.RepoLink + "/pulls")
+		return
+	}
+
 	if err := pull_service.NewPullRequest(ctx, repo, pullIssue, labelIDs, attachments, pullRequest, assigneeIDs); err != nil {
 		if repo_model.IsErrUserDoesNotHaveAccessToRepo(err) {
 			ctx.Error(http.StatusBadRequest, "UserDoesNotHaveAccessToRepo", err.Error())
 			return
+		} else if flashInteractionDenied(ctx, err) {
+			ctx.Redirect(ctx.Link)
+			return
 		} else if git.IsErrPushRejected(err) {
 			pushrejErr := err.(*git.ErrPushRejected)
 			message := pushrejErr.Message
@@ -19,6 +19,11 @@ import (
 
 // RenderFile renders a file by repos path
 func RenderFile(ctx *context.Context) {
+	if !ctx.Repo.Permission.CanReadCodePath(ctx.Repo.TreePath) {
+		ctx.NotFound("CanReadCodePath", nil)
+		return
+	}
+
 	blob, err := ctx.Repo.Commit.GetBlobByPath(ctx.Repo.TreePath)
 	if err != nil {
 		if git.IsErrNotExist(err) {
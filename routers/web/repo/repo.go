@@ -150,20 +150,23 @@ func Create(ctx *context.Context) {
 	ctx.Data["Title"] = ctx.Tr("new_repo")
 
 	// Give default value for template to render.
-	ctx.Data["Gitignores"] = repo_module.Gitignores
 	ctx.Data["LabelTemplateFiles"] = repo_module.LabelTemplateFiles
-	ctx.Data["Licenses"] = repo_module.Licenses
 	ctx.Data["Readmes"] = repo_module.Readmes
 	ctx.Data["readme"] = "Default"
 	ctx.Data["private"] = getRepoPrivate(ctx)
 	ctx.Data["IsForcedPrivate"] = setting.Repository.ForcePrivate
 	ctx.Data["default_branch"] = setting.Repository.DefaultBranch
+	ctx.Data["SupportedObjectFormats"] = git.SupportedObjectFormats()
+	ctx.Data["object_format_name"] = git.ObjectFormatSHA1
 
 	ctxUser := checkContextUser(ctx, ctx.FormInt64("org"))
 	if ctx.Written() {
 		return
 	}
 	ctx.Data["ContextUser"] = ctxUser
+	if prepareTemplateCatalogs(ctx, ctxUser.ID); ctx.Written() {
+		return
+	}
 
 	ctx.Data["repo_template_name"] = ctx.Tr("repo.template_select")
 	templateID := ctx.FormInt64("template_id")
@@ -181,6 +184,37 @@ func Create(ctx *context.Context) {
 	ctx.HTML(http.StatusOK, tplCreate)
 }
 
+// prepareTemplateCatalogs offers the templates of the catalogs of the instance and of the owner of the new repository
+func prepareTemplateCatalogs(ctx *context.Context, ownerID int64) map[repo_model.TemplateCatalogKind][]string {
+	catalogs := make(map[repo_model.TemplateCatalogKind][]string, len(repo_model.TemplateCatalogKinds))
+	for _, kind := range repo_model.TemplateCatalogKinds {
+		names, err := repo_module.GetTemplateCatalogNames(ctx, ownerID, kind)
+		if err != nil {
+			ctx.ServerError("GetTemplateCatalogNames", err)
+			return nil
+		}
+		catalogs[kind] = names
+	}
+	ctx.Data["Gitignores"] = catalogs[repo_model.TemplateCatalogGitignore]
+	ctx.Data["Licenses"] = catalogs[repo_model.TemplateCatalogLicense]
+	ctx.Data["CatalogIssueTemplates"] = catalogs[repo_model.TemplateCatalogIssueTemplate]
+	ctx.Data["CatalogWorkflowTemplates"] = catalogs[repo_model.TemplateCatalogWorkflowTemplate]
+	return catalogs
+}
+
+// findUnknownTemplate returns the first of the comma-separated templates which isn't offered
+func findUnknownTemplate(offered []string, names string) string {
+	if names == "" {
+		return ""
+	}
+	for _, name := range strings.Split(names, ",") {
+		if !util.SliceContainsString(offered, name) {
+			return name
+		}
+	}
+	return ""
+}
+
 func handleCreateError(ctx *context.Context, owner *user_model.User, err error, name string, tpl base.TplName, form interface{}) {
 	switch {
 	case repo_model.IsErrReachLimitOfRepo(err):
@@ -208,6 +242,8 @@ func handleCreateError(ctx *context.Context, owner *user_model.User, err error,
 	case db.IsErrNamePatternNotAllowed(err):
 		ctx.Data["Err_RepoName"] = true
 		ctx.RenderWithErr(ctx.Tr("repo.form.name_pattern_not_allowed", err.(db.ErrNamePatternNotAllowed).Pattern), tpl, form)
+	case git.IsErrObjectFormatNotSupported(err):
+		ctx.RenderWithErr(ctx.Tr("repo.form.object_format_not_supported", err.(git.ErrObjectFormatNotSupported).ObjectFormat), tpl, form)
 	default:
 		ctx.ServerError(name, err)
 	}
@@ -218,10 +254,9 @@ func CreatePost(ctx *context.Context) {
 	form := web.GetForm(ctx).(*forms.CreateRepoForm)
 	ctx.Data["Title"] = ctx.Tr("new_repo")
 
-	ctx.Data["Gitignores"] = repo_module.Gitignores
 	ctx.Data["LabelTemplateFiles"] = repo_module.LabelTemplateFiles
-	ctx.Data["Licenses"] = repo_module.Licenses
 	ctx.Data["Readmes"] = repo_module.Readmes
+	ctx.Data["SupportedObjectFormats"] = git.SupportedObjectFormats()
 
 	ctx.Data["CanCreateRepo"] = ctx.Doer.CanCreateRepo()
 	ctx.Data["MaxCreationLimit"] = ctx.Doer.MaxCreationLimit()
@@ -231,6 +266,10 @@ func CreatePost(ctx *context.Context) {
 		return
 	}
 	ctx.Data["ContextUser"] = ctxUser
+	catalogs := prepareTemplateCatalogs(ctx, ctxUser.ID)
+	if ctx.Written() {
+		return
+	}
 
 	if ctx.HasError() {
 		ctx.HTML(http.StatusOK, tplCreate)
@@ -244,6 +283,7 @@ func CreatePost(ctx *context.Context) {
 			Name:        form.RepoName,
 			Description: form.Description,
 			Private:     form.Private,
+			Internal:    form.Internal,
 			GitContent:  form.GitContent,
 			Topics:      form.Topics,
 			GitHooks:    form.GitHooks,
@@ -274,18 +314,40 @@ func CreatePost(ctx *context.Context) {
 			return
 		}
 	} else {
+		selected := map[repo_model.TemplateCatalogKind]string{
+			repo_model.TemplateCatalogGitignore:        form.Gitignores,
+			repo_model.TemplateCatalogLicense:          form.License,
+			repo_model.TemplateCatalogIssueTemplate:    form.IssueTemplates,
+			repo_model.TemplateCatalogWorkflowTemplate: form.WorkflowTemplates,
+		}
+		for _, kind := range repo_model.TemplateCatalogKinds {
+			if name := findUnknownTemplate(catalogs[kind], selected[kind]); name != "" {
+				ctx.RenderWithErr(ctx.Tr("repo.template_catalog.not_exist", name), tplCreate, form)
+				return
+			}
+		}
+
+		var objectFormat git.ObjectFormat
+		if objectFormat, err = git.ParseObjectFormat(form.ObjectFormatName); err != nil {
+			ctx.RenderWithErr(ctx.Tr("repo.form.object_format_not_supported", form.ObjectFormatName), tplCreate, form)
+			return
+		}
 		repo, err = repo_service.CreateRepository(ctx, ctx.Doer, ctxUser, repo_module.CreateRepoOptions{
-			Name:          form.RepoName,
-			Description:   form.Description,
-			Gitignores:    form.Gitignores,
-			IssueLabels:   form.IssueLabels,
-			License:       form.License,
-			Readme:        form.Readme,
-			IsPrivate:     form.Private || setting.Repository.ForcePrivate,
-			DefaultBranch: form.DefaultBranch,
-			AutoInit:      form.AutoInit,
-			IsTemplate:    form.Template,
-			TrustModel:    repo_model.ToTrustModel(form.TrustModel),
+			Name:              form.RepoName,
+			Description:       form.Description,
+			Gitignores:        form.Gitignores,
+			IssueLabels:       form.IssueLabels,
+			License:           form.License,
+			Readme:            form.Readme,
+			IssueTemplates:    form.IssueTemplates,
+			WorkflowTemplates: form.WorkflowTemplates,
+			IsPrivate:         form.Private || setting.Repository.ForcePrivate,
+			IsInternal:        form.Internal,
+			DefaultBranch:     form.DefaultBranch,
+			AutoInit:          form.AutoInit,
+			IsTemplate:        form.Template,
+			TrustModel:        repo_model.ToTrustModel(form.TrustModel),
+			ObjectFormat:      objectFormat,
 		})
 		if err == nil {
 			log.Trace("Repository created [%d]: %s/%s", repo.ID, ctxUser.Name, repo.Name)
@@ -420,12 +482,10 @@ func Download(ctx *context.Context) {
 		return
 	}
 
-	download(ctx, aReq.GetArchiveName(), archiver)
+	download(ctx, ctx.Repo.Repository.Name+"-"+aReq.GetArchiveName(), archiver)
 }
 
-func download(ctx *context.Context, archiveName string, archiver *repo_model.RepoArchiver) {
-	downloadName := ctx.Repo.Repository.Name + "-" + archiveName
-
+func download(ctx *context.Context, downloadName string, archiver *repo_model.RepoArchiver) {
 	rPath := archiver.RelativePath()
 	if setting.RepoArchive.ServeDirect {
 		// If we have a signed url (S3, object storage), redirect to this directly.
@@ -608,7 +668,7 @@ func SearchRepo(ctx *context.Context) {
 				Stars:    repo.NumStars,
 				HTMLURL:  repo.HTMLURL(),
 				Link:     repo.Link(),
-				Internal: !repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePrivate,
+				Internal: repo.IsInternal || (!repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePrivate),
 			},
 			LatestCommitStatus: git_model.CalcCommitStatus(repoToItsLatestCommitStatuses[repo.ID]),
 		}
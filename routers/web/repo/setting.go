@@ -16,11 +16,13 @@ import (
 	"code.gitea.io/gitea/models"
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
 	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/models/organization"
 	"code.gitea.io/gitea/models/perm"
 	repo_model "code.gitea.io/gitea/models/repo"
 	unit_model "code.gitea.io/gitea/models/unit"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/avatar"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/git"
@@ -32,6 +34,7 @@ import (
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/typesniffer"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/validation"
@@ -64,6 +67,10 @@ func SettingsCtxData(ctx *context.Context) {
 	ctx.Data["PageIsSettingsOptions"] = true
 	ctx.Data["ForcePrivate"] = setting.Repository.ForcePrivate
 	ctx.Data["MirrorsEnabled"] = setting.Mirror.Enabled
+	if setting.Repository.DeletionRetention > 0 {
+		ctx.Data["DeletionRetention"] = util.SecToTime(int64(setting.Repository.DeletionRetention.Seconds()))
+	}
+	ctx.Data["DefaultActionsApprovalPolicy"] = setting.Actions.DefaultApprovalPolicy
 	ctx.Data["DisableNewPushMirrors"] = setting.Mirror.DisableNewPush
 	ctx.Data["DefaultMirrorInterval"] = setting.Mirror.DefaultInterval
 	ctx.Data["MinimumMirrorInterval"] = setting.Mirror.MinInterval
@@ -108,6 +115,10 @@ func SettingsPost(ctx *context.Context) {
 
 	ctx.Data["ForcePrivate"] = setting.Repository.ForcePrivate
 	ctx.Data["MirrorsEnabled"] = setting.Mirror.Enabled
+	if setting.Repository.DeletionRetention > 0 {
+		ctx.Data["DeletionRetention"] = util.SecToTime(int64(setting.Repository.DeletionRetention.Seconds()))
+	}
+	ctx.Data["DefaultActionsApprovalPolicy"] = setting.Actions.DefaultApprovalPolicy
 	ctx.Data["DisableNewPushMirrors"] = setting.Mirror.DisableNewPush
 	ctx.Data["DefaultMirrorInterval"] = setting.Mirror.DefaultInterval
 	ctx.Data["MinimumMirrorInterval"] = setting.Mirror.MinInterval
@@ -173,9 +184,12 @@ func SettingsPost(ctx *context.Context) {
 		// Visibility of forked repository is forced sync with base repository.
 		if repo.IsFork {
 			form.Private = repo.BaseRepo.IsPrivate || repo.BaseRepo.Owner.Visibility == structs.VisibleTypePrivate
+			form.Internal = repo.BaseRepo.IsInternal
 		}
+		// internal repositories are private
+		form.Private = form.Private || form.Internal
 
-		visibilityChanged := repo.IsPrivate != form.Private
+		visibilityChanged := repo.IsPrivate != form.Private || repo.IsInternal != form.Internal
 		// when ForcePrivate enabled, you could change public repo to private, but only admin users can change private to public
 		if visibilityChanged && setting.Repository.ForcePrivate && !form.Private && !ctx.Doer.IsAdmin {
 			ctx.RenderWithErr(ctx.Tr("form.repository_force_private"), tplSettingsOptions, form)
@@ -183,6 +197,7 @@ func SettingsPost(ctx *context.Context) {
 		}
 
 		repo.IsPrivate = form.Private
+		repo.IsInternal = form.Internal
 		if err := repo_service.UpdateRepository(ctx, repo, visibilityChanged); err != nil {
 			ctx.ServerError("UpdateRepository", err)
 			return
@@ -515,14 +530,28 @@ func SettingsPost(ctx *context.Context) {
 		}
 
 		if form.EnableActions && !unit_model.TypeActions.UnitGlobalDisabled() {
+			policy := repo_model.ActionsApprovalPolicy(form.ActionsApprovalPolicy)
+			if !policy.IsValid() {
+				policy = ""
+			}
 			units = append(units, repo_model.RepoUnit{
 				RepoID: repo.ID,
 				Type:   unit_model.TypeActions,
+				Config: &repo_model.ActionsConfig{ApprovalPolicy: policy},
 			})
 		} else if !unit_model.TypeActions.UnitGlobalDisabled() {
 			deleteUnitTypes = append(deleteUnitTypes, unit_model.TypeActions)
 		}
 
+		if form.EnableDiscussions && !unit_model.TypeDiscussions.UnitGlobalDisabled() {
+			units = append(units, repo_model.RepoUnit{
+				RepoID: repo.ID,
+				Type:   unit_model.TypeDiscussions,
+			})
+		} else if !unit_model.TypeDiscussions.UnitGlobalDisabled() {
+			deleteUnitTypes = append(deleteUnitTypes, unit_model.TypeDiscussions)
+		}
+
 		if form.EnablePulls && !unit_model.TypePullRequests.UnitGlobalDisabled() {
 			units = append(units, repo_model.RepoUnit{
 				RepoID: repo.ID,
@@ -794,13 +823,22 @@ func SettingsPost(ctx *context.Context) {
 			ctx.Repo.GitRepo.Close()
 		}
 
-		if err := repo_service.DeleteRepository(ctx, ctx.Doer, ctx.Repo.Repository, true); err != nil {
-			ctx.ServerError("DeleteRepository", err)
+		if err := repo_service.SoftDeleteRepository(ctx, ctx.Doer, ctx.Repo.Repository); err != nil {
+			if issues_model.IsErrUnderLegalHold(err) {
+				ctx.Flash.Error(ctx.Tr("repo.legal_hold.deletion_blocked"))
+				ctx.Redirect(ctx.Repo.RepoLink + "/settings")
+				return
+			}
+			ctx.ServerError("SoftDeleteRepository", err)
 			return
 		}
 		log.Trace("Repository deleted: %s/%s", ctx.Repo.Owner.Name, repo.Name)
 
-		ctx.Flash.Success(ctx.Tr("repo.settings.deletion_success"))
+		if setting.Repository.DeletionRetention > 0 {
+			ctx.Flash.Success(ctx.Tr("repo.settings.deletion_success_retention", util.SecToTime(int64(setting.Repository.DeletionRetention.Seconds()))))
+		} else {
+			ctx.Flash.Success(ctx.Tr("repo.settings.deletion_success"))
+		}
 		ctx.Redirect(ctx.Repo.Owner.DashboardLink())
 
 	case "delete-wiki":
@@ -916,6 +954,7 @@ func Collaboration(ctx *context.Context) {
 	ctx.Data["OrgName"] = ctx.Repo.Repository.OwnerName
 	ctx.Data["Org"] = ctx.Repo.Repository.Owner
 	ctx.Data["Units"] = unit_model.Units
+	ctx.Data["MinCollaborationExpiry"] = time.Now().AddDate(0, 0, 1).Format("2006-01-02")
 
 	ctx.HTML(http.StatusOK, tplCollaboration)
 }
@@ -971,11 +1010,29 @@ func CollaborationPost(ctx *context.Context) {
 		}
 	}
 
+	var expires timeutil.TimeStamp
+	if expiresStr := ctx.FormString("expires"); expiresStr != "" {
+		expiresDate, err := time.ParseInLocation("2006-01-02", expiresStr, setting.DefaultUILocation)
+		if err != nil || !expiresDate.After(time.Now()) {
+			ctx.Flash.Error(ctx.Tr("repo.settings.collaboration.expires_invalid"))
+			ctx.Redirect(setting.AppSubURL + ctx.Req.URL.EscapedPath())
+			return
+		}
+		expires = timeutil.TimeStamp(expiresDate.Unix())
+	}
+
 	if err = repo_module.AddCollaborator(ctx, ctx.Repo.Repository, u); err != nil {
 		ctx.ServerError("AddCollaborator", err)
 		return
 	}
 
+	if expires > 0 {
+		if err := repo_model.SetCollaborationExpiry(ctx, ctx.Repo.Repository.ID, u.ID, expires); err != nil {
+			ctx.ServerError("SetCollaborationExpiry", err)
+			return
+		}
+	}
+
 	if setting.Service.EnableNotifyMail {
 		mailer.SendCollaboratorMail(u, ctx.Doer, ctx.Repo.Repository)
 	}
@@ -1187,7 +1244,17 @@ func DeployKeysPost(ctx *context.Context) {
 		return
 	}
 
-	key, err := asymkey_model.AddDeployKey(ctx.Repo.Repository.ID, form.Title, content, !form.IsWritable)
+	refPatterns, err := asymkey_model.ValidateDeployKeyRefPatterns(strings.FieldsFunc(form.RefPatterns, func(r rune) bool {
+		return r == ',' || r == '\n'
+	}))
+	if err != nil {
+		ctx.Data["HasError"] = true
+		ctx.Data["Err_RefPatterns"] = true
+		ctx.RenderWithErr(ctx.Tr("repo.settings.deploy_key_ref_patterns_invalid", err.Error()), tplDeployKeys, &form)
+		return
+	}
+
+	key, err := asymkey_model.AddDeployKey(ctx.Repo.Repository.ID, form.Title, content, !form.IsWritable || form.IsArchiveOnly)
 	if err != nil {
 		ctx.Data["HasError"] = true
 		switch {
@@ -1209,6 +1276,13 @@ func DeployKeysPost(ctx *context.Context) {
 		return
 	}
 
+	if len(refPatterns) > 0 || form.IsArchiveOnly {
+		if err := asymkey_model.SetDeployKeyRestrictions(ctx, key, refPatterns, form.IsArchiveOnly); err != nil {
+			ctx.ServerError("SetDeployKeyRestrictions", err)
+			return
+		}
+	}
+
 	log.Trace("Deploy key added: %d", ctx.Repo.Repository.ID)
 	ctx.Flash.Success(ctx.Tr("repo.settings.add_key_success", key.Name))
 	ctx.Redirect(ctx.Repo.RepoLink + "/settings/keys")
@@ -1260,6 +1334,9 @@ func UpdateAvatarSetting(ctx *context.Context, form forms.AvatarForm) error {
 		return errors.New(ctx.Tr("settings.uploaded_avatar_not_a_image"))
 	}
 	if err = repo_service.UploadAvatar(ctx, ctxRepo, data); err != nil {
+		if errors.Is(err, avatar.ErrAnimatedAvatar) {
+			return errors.New(ctx.Tr("settings.uploaded_avatar_is_animated"))
+		}
 		return fmt.Errorf("UploadAvatar: %w", err)
 	}
 	return nil
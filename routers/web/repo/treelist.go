@@ -30,7 +30,7 @@ func TreeList(ctx *context.Context) {
 
 	files := make([]string, 0, len(entries))
 	for _, entry := range entries {
-		if !isExcludedEntry(entry) {
+		if !isExcludedEntry(entry) && ctx.Repo.Permission.CanReadCodePath(entry.Name()) {
 			files = append(files, entry.Name())
 		}
 	}
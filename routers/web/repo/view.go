@@ -41,6 +41,7 @@ import (
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/web/feed"
 	issue_service "code.gitea.io/gitea/services/issue"
+	files_service "code.gitea.io/gitea/services/repository/files"
 
 	"github.com/nektos/act/pkg/model"
 )
@@ -54,99 +55,6 @@ const (
 	tplMigrating    base.TplName = "repo/migrate/migrating"
 )
 
-// locate a README for a tree in one of the supported paths.
-//
-// entries is passed to reduce calls to ListEntries(), so
-// this has precondition:
-//
-//	entries == ctx.Repo.Commit.SubTree(ctx.Repo.TreePath).ListEntries()
-//
-// FIXME: There has to be a more efficient way of doing this
-func findReadmeFileInEntries(ctx *context.Context, entries []*git.TreeEntry, tryWellKnownDirs bool) (string, *git.TreeEntry, error) {
-	// Create a list of extensions in priority order
-	// 1. Markdown files - with and without localisation - e.g. README.en-us.md or README.md
-	// 2. Txt files - e.g. README.txt
-	// 3. No extension - e.g. README
-	exts := append(localizedExtensions(".md", ctx.Locale.Language()), ".txt", "") // sorted by priority
-	extCount := len(exts)
-	readmeFiles := make([]*git.TreeEntry, extCount+1)
-
-	docsEntries := make([]*git.TreeEntry, 3) // (one of docs/, .gitea/ or .github/)
-	for _, entry := range entries {
-		if tryWellKnownDirs && entry.IsDir() {
-			// as a special case for the top-level repo introduction README,
-			// fall back to subfolders, looking for e.g. docs/README.md, .gitea/README.zh-CN.txt, .github/README.txt, ...
-			// (note that docsEntries is ignored unless we are at the root)
-			lowerName := strings.ToLower(entry.Name())
-			switch lowerName {
-			case "docs":
-				if entry.Name() == "docs" || docsEntries[0] == nil {
-					docsEntries[0] = entry
-				}
-			case ".gitea":
-				if entry.Name() == ".gitea" || docsEntries[1] == nil {
-					docsEntries[1] = entry
-				}
-			case ".github":
-				if entry.Name() == ".github" || docsEntries[2] == nil {
-					docsEntries[2] = entry
-				}
-			}
-			continue
-		}
-		if i, ok := util.IsReadmeFileExtension(entry.Name(), exts...); ok {
-			log.Debug("Potential readme file: %s", entry.Name())
-			if readmeFiles[i] == nil || base.NaturalSortLess(readmeFiles[i].Name(), entry.Blob().Name()) {
-				if entry.IsLink() {
-					target, err := entry.FollowLinks()
-					if err != nil && !git.IsErrBadLink(err) {
-						return "", nil, err
-					} else if target != nil && (target.IsExecutable() || target.IsRegular()) {
-						readmeFiles[i] = entry
-					}
-				} else {
-					readmeFiles[i] = entry
-				}
-			}
-		}
-	}
-	var readmeFile *git.TreeEntry
-	for _, f := range readmeFiles {
-		if f != nil {
-			readmeFile = f
-			break
-		}
-	}
-
-	if ctx.Repo.TreePath == "" && readmeFile == nil {
-		for _, subTreeEntry := range docsEntries {
-			if subTreeEntry == nil {
-				continue
-			}
-			subTree := subTreeEntry.Tree()
-			if subTree == nil {
-				// this should be impossible; if subTreeEntry exists so should this.
-				continue
-			}
-			var err error
-			childEntries, err := subTree.ListEntries()
-			if err != nil {
-				return "", nil, err
-			}
-
-			subfolder, readmeFile, err := findReadmeFileInEntries(ctx, childEntries, false)
-			if err != nil && !git.IsErrNotExist(err) {
-				return "", nil, err
-			}
-			if readmeFile != nil {
-				return path.Join(subTreeEntry.Name(), subfolder), readmeFile, nil
-			}
-		}
-	}
-
-	return "", readmeFile, nil
-}
-
 func renderDirectory(ctx *context.Context, treeLink string) {
 	entries := renderDirectoryFiles(ctx, 1*time.Second)
 	if ctx.Written() {
@@ -158,35 +66,16 @@ func renderDirectory(ctx *context.Context, treeLink string) {
 		ctx.Data["Title"] = ctx.Tr("repo.file.title", ctx.Repo.Repository.Name+"/"+path.Base(ctx.Repo.TreePath), ctx.Repo.RefName)
 	}
 
-	subfolder, readmeFile, err := findReadmeFileInEntries(ctx, entries, true)
+	subfolder, readmeFile, err := files_service.FindReadmeFileInEntries(entries, ctx.Locale.Language(), ctx.Repo.TreePath == "")
 	if err != nil {
-		ctx.ServerError("findReadmeFileInEntries", err)
+		ctx.ServerError("FindReadmeFileInEntries", err)
 		return
 	}
-
-	renderReadmeFile(ctx, subfolder, readmeFile, treeLink)
-}
-
-// localizedExtensions prepends the provided language code with and without a
-// regional identifier to the provided extension.
-// Note: the language code will always be lower-cased, if a region is present it must be separated with a `-`
-// Note: ext should be prefixed with a `.`
-func localizedExtensions(ext, languageCode string) (localizedExts []string) {
-	if len(languageCode) < 1 {
-		return []string{ext}
+	if readmeFile != nil && !ctx.Repo.Permission.CanReadCodePath(path.Join(ctx.Repo.TreePath, subfolder, readmeFile.Name())) {
+		subfolder, readmeFile = "", nil
 	}
 
-	lowerLangCode := "." + strings.ToLower(languageCode)
-
-	if strings.Contains(lowerLangCode, "-") {
-		underscoreLangCode := strings.ReplaceAll(lowerLangCode, "-", "_")
-		indexOfDash := strings.Index(lowerLangCode, "-")
-		// e.g. [.zh-cn.md, .zh_cn.md, .zh.md, _zh.md, .md]
-		return []string{lowerLangCode + ext, underscoreLangCode + ext, lowerLangCode[:indexOfDash] + ext, "_" + lowerLangCode[1:indexOfDash] + ext, ext}
-	}
-
-	// e.g. [.en.md, .md]
-	return []string{lowerLangCode + ext, ext}
+	renderReadmeFile(ctx, subfolder, readmeFile, treeLink)
 }
 
 type fileInfo struct {
@@ -430,7 +319,7 @@ func renderFile(ctx *context.Context, entry *git.TreeEntry, treeLink, rawLink st
 		rd := charset.ToUTF8WithFallbackReader(io.MultiReader(bytes.NewReader(buf), dataRc))
 
 		shouldRenderSource := ctx.FormString("display") == "source"
-		readmeExist := util.IsReadmeFileName(blob.Name())
+		readmeExist := files_service.IsReadmeFileName(blob.Name())
 		ctx.Data["ReadmeExist"] = readmeExist
 
 		markupType := markup.Type(blob.Name())
@@ -668,6 +557,28 @@ func checkHomeCodeViewable(ctx *context.Context) {
 	ctx.NotFound("Home", fmt.Errorf(ctx.Tr("units.error.no_unit_allowed_repo")))
 }
 
+// canViewCodeEntry returns true if the user may list the directory or read the file at the path
+func canViewCodeEntry(ctx *context.Context, treePath string, entry *git.TreeEntry) bool {
+	if entry.IsDir() {
+		return ctx.Repo.Permission.CanBrowseCodePath(treePath)
+	}
+	return ctx.Repo.Permission.CanReadCodePath(treePath)
+}
+
+// filterCodeEntries removes the entries of the directory at the path which the user may not view
+func filterCodeEntries(ctx *context.Context, treePath string, entries git.Entries) git.Entries {
+	if !ctx.Repo.Permission.IsCodePathRestricted() {
+		return entries
+	}
+	filtered := make(git.Entries, 0, len(entries))
+	for _, entry := range entries {
+		if canViewCodeEntry(ctx, path.Join(treePath, entry.Name()), entry) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
 func checkCitationFile(ctx *context.Context, entry *git.TreeEntry) {
 	if entry.Name() != "" {
 		return
@@ -683,7 +594,7 @@ func checkCitationFile(ctx *context.Context, entry *git.TreeEntry) {
 		return
 	}
 	for _, entry := range allEntries {
-		if entry.Name() == "CITATION.cff" || entry.Name() == "CITATION.bib" {
+		if (entry.Name() == "CITATION.cff" || entry.Name() == "CITATION.bib") && ctx.Repo.Permission.CanReadCodePath(path.Join(ctx.Repo.TreePath, entry.Name())) {
 			ctx.Data["CitiationExist"] = true
 			// Read Citation file contents
 			blob := entry.Blob()
@@ -783,11 +694,17 @@ func renderDirectoryFiles(ctx *context.Context, timeout time.Duration) git.Entri
 		return nil
 	}
 
+	if !ctx.Repo.Permission.CanBrowseCodePath(ctx.Repo.TreePath) {
+		ctx.NotFound("Repo.Permission.CanBrowseCodePath", nil)
+		return nil
+	}
+
 	allEntries, err := tree.ListEntries()
 	if err != nil {
 		ctx.ServerError("ListEntries", err)
 		return nil
 	}
+	allEntries = filterCodeEntries(ctx, ctx.Repo.TreePath, allEntries)
 	allEntries.CustomSort(base.NaturalSortLess)
 
 	commitInfoCtx := gocontext.Context(ctx)
@@ -949,6 +866,11 @@ func renderCode(ctx *context.Context) {
 		return
 	}
 
+	if !canViewCodeEntry(ctx, ctx.Repo.TreePath, entry) {
+		ctx.NotFound("Repo.Permission.CanReadCodePath", nil)
+		return
+	}
+
 	checkCitationFile(ctx, entry)
 	if ctx.Written() {
 		return
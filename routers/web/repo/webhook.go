@@ -180,6 +180,9 @@ func ParseHookEvent(form forms.WebhookForm) *webhook_module.HookEvent {
 			Wiki:                 form.Wiki,
 			Repository:           form.Repository,
 			Package:              form.Package,
+			Discussion:           form.Discussion,
+			DiscussionComment:    form.DiscussionComment,
+			ActionRunApproval:    form.ActionRunApproval,
 		},
 		BranchFilter: form.BranchFilter,
 	}
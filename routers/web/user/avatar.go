@@ -11,6 +11,7 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/httpcache"
+	"code.gitea.io/gitea/modules/setting"
 )
 
 func cacheableRedirect(ctx *context.Context, location string) {
@@ -44,7 +45,8 @@ func AvatarByUserName(ctx *context.Context) {
 	cacheableRedirect(ctx, user.AvatarLinkWithSize(ctx, size))
 }
 
-// AvatarByEmailHash redirects the browser to the email avatar link
+// AvatarByEmailHash redirects the browser to the email avatar link.
+// If the Libravatar server is enabled, it also serves the avatars of local users to federated Libravatar clients.
 func AvatarByEmailHash(ctx *context.Context) {
 	hash := ctx.Params(":hash")
 	email, err := avatars.GetEmailForHash(hash)
@@ -53,5 +55,33 @@ func AvatarByEmailHash(ctx *context.Context) {
 		return
 	}
 	size := ctx.FormInt("size")
+
+	if setting.Avatar.LibravatarServer {
+		// Libravatar clients use "s" for the size and "d" for the default
+		if size == 0 {
+			size = ctx.FormInt("s")
+		}
+		var u *user_model.User
+		if email != "" {
+			if u, err = user_model.GetUserByEmail(ctx, email); err != nil && !user_model.IsErrUserNotExist(err) {
+				ctx.ServerError("GetUserByEmail", err)
+				return
+			}
+		}
+		if u != nil && u.UseCustomAvatar && u.Avatar != "" {
+			cacheableRedirect(ctx, avatars.GenerateUserAvatarImageLink(u.Avatar, size))
+			return
+		}
+		if ctx.FormString("d") == "404" {
+			ctx.NotFound("AvatarByEmailHash", nil)
+			return
+		}
+		if u != nil {
+			// the federated lookup of a local address would lead back to this server
+			cacheableRedirect(ctx, avatars.DefaultAvatarLink())
+			return
+		}
+	}
+
 	cacheableRedirect(ctx, avatars.GenerateEmailAvatarFinalLink(ctx, email, size))
 }
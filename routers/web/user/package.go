@@ -5,6 +5,7 @@ package user
 
 import (
 	"net/http"
+	"strings"
 
 	"code.gitea.io/gitea/models/db"
 	org_model "code.gitea.io/gitea/models/organization"
@@ -48,10 +49,11 @@ func ListPackages(ctx *context.Context) {
 			PageSize: setting.UI.PackagesPagingNum,
 			Page:     page,
 		},
-		OwnerID:    ctx.ContextUser.ID,
-		Type:       packages_model.Type(packageType),
-		Name:       packages_model.SearchValue{Value: query},
-		IsInternal: util.OptionalBoolFalse,
+		OwnerID:           ctx.ContextUser.ID,
+		HideInternalRepos: ctx.Doer == nil,
+		Type:              packages_model.Type(packageType),
+		Name:              packages_model.SearchValue{Value: query},
+		IsInternal:        util.OptionalBoolFalse,
 	})
 	if err != nil {
 		ctx.ServerError("SearchLatestVersions", err)
@@ -409,6 +411,16 @@ func PackageSettingsPost(ctx *context.Context) {
 			ctx.Flash.Error(ctx.Tr("packages.settings.link.error"))
 		}
 
+		ctx.Redirect(ctx.Link)
+		return
+	case "deprecate":
+		if err := packages_model.SetVersionDeprecation(ctx, pd.Version, form.IsDeprecated, strings.TrimSpace(form.DeprecationMessage)); err != nil {
+			log.Error("Error updating package deprecation: %v", err)
+			ctx.Flash.Error(ctx.Tr("packages.settings.deprecate.error"))
+		} else {
+			ctx.Flash.Success(ctx.Tr("packages.settings.deprecate.success"))
+		}
+
 		ctx.Redirect(ctx.Link)
 		return
 	case "delete":
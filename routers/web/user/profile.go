@@ -23,6 +23,8 @@ import (
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/routers/web/feed"
 	"code.gitea.io/gitea/routers/web/org"
+	files_service "code.gitea.io/gitea/services/repository/files"
+	user_service "code.gitea.io/gitea/services/user"
 )
 
 // Profile render user's profile page
@@ -68,16 +70,32 @@ func Profile(ctx *context.Context) {
 	ctx.Data["OpenIDs"] = openIDs
 	ctx.Data["IsFollowing"] = isFollowing
 
+	status, err := user_model.GetUserStatus(ctx, ctx.ContextUser.ID)
+	if err != nil && !user_model.IsErrUserStatusNotExist(err) {
+		ctx.ServerError("GetUserStatus", err)
+		return
+	}
+	ctx.Data["UserStatus"] = status
+
 	if setting.Service.EnableUserHeatmap {
-		data, err := activities_model.GetUserHeatmapDataByUser(ctx.ContextUser, ctx.Doer)
+		data, err := user_service.GetUserHeatmapDataWithLinkedIdentities(ctx, ctx.ContextUser, ctx.Doer)
 		if err != nil {
-			ctx.ServerError("GetUserHeatmapDataByUser", err)
+			ctx.ServerError("GetUserHeatmapDataWithLinkedIdentities", err)
 			return
 		}
 		ctx.Data["HeatmapData"] = data
 		ctx.Data["HeatmapTotalContributions"] = activities_model.GetTotalContributionsInHeatmap(data)
 	}
 
+	if setting.Service.EnableLinkedIdentities {
+		linkedIdentities, err := user_model.GetVerifiedLinkedIdentities(ctx, ctx.ContextUser.ID)
+		if err != nil {
+			ctx.ServerError("GetVerifiedLinkedIdentities", err)
+			return
+		}
+		ctx.Data["LinkedIdentities"] = linkedIdentities
+	}
+
 	if len(ctx.ContextUser.Description) != 0 {
 		content, err := markdown.RenderString(&markup.RenderContext{
 			URLPrefix: ctx.Repo.RepoLink,
@@ -105,17 +123,30 @@ func Profile(ctx *context.Context) {
 			ctx.ServerError("GetBranchCommit", err)
 			return
 		}
-		blob, err := commit.GetBlobByPath("README.md")
-		if err == nil {
-			bytes, err := blob.GetBlobContent()
+		readmePath, readmeFile, err := files_service.FindReadme(commit, "", ctx.Locale.Language())
+		if err != nil {
+			ctx.ServerError("FindReadme", err)
+			return
+		}
+		if readmeFile != nil {
+			bytes, err := readmeFile.Blob().GetBlobContent()
 			if err != nil {
 				ctx.ServerError("GetBlobContent", err)
 				return
 			}
-			profileContent, err := markdown.RenderString(&markup.RenderContext{
+			renderCtx := &markup.RenderContext{
 				Ctx:     ctx,
 				GitRepo: gitRepo,
-			}, bytes)
+			}
+			var profileContent string
+			if markup.Type(readmePath) != "" {
+				// e.g. README.rst or README.org
+				renderCtx.RelativePath = readmePath
+				profileContent, err = markup.RenderString(renderCtx, bytes)
+			} else {
+				// plain text READMEs are rendered as markdown, as the profile always has been
+				profileContent, err = markdown.RenderString(renderCtx, bytes)
+			}
 			if err != nil {
 				ctx.ServerError("RenderString", err)
 				return
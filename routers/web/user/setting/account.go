@@ -171,6 +171,19 @@ func EmailPost(ctx *context.Context) {
 		ctx.Redirect(setting.AppSubURL + "/user/settings/account")
 		return
 	}
+	// Set Email Digest Frequency
+	if ctx.FormString("_method") == "DIGEST" {
+		frequency := ctx.FormString("frequency")
+		if err := user_model.SetEmailDigestFrequency(ctx.Doer.ID, frequency); err != nil {
+			log.Error("Set Email Digest Frequency failed: %v", err)
+			ctx.ServerError("SetEmailDigestFrequency", err)
+			return
+		}
+		log.Trace("Email digest frequency made %q: %s", frequency, ctx.Doer.Name)
+		ctx.Flash.Success(ctx.Tr("settings.email_digest_set_success"))
+		ctx.Redirect(setting.AppSubURL + "/user/settings/account")
+		return
+	}
 
 	if ctx.HasError() {
 		loadAccountData(ctx)
@@ -288,6 +301,12 @@ func loadAccountData(ctx *context.Context) {
 	}
 	ctx.Data["Emails"] = emails
 	ctx.Data["EmailNotificationsPreference"] = ctx.Doer.EmailNotifications()
+	digestFrequency, err := user_model.GetEmailDigestFrequency(ctx.Doer.ID)
+	if err != nil {
+		ctx.ServerError("GetEmailDigestFrequency", err)
+		return
+	}
+	ctx.Data["EmailDigestFrequency"] = digestFrequency
 	ctx.Data["ActivationsPending"] = pendingActivation
 	ctx.Data["CanAddEmails"] = !pendingActivation || !setting.Service.RegisterEmailConfirm
 
@@ -5,13 +5,17 @@
 package setting
 
 import (
+	"errors"
 	"net/http"
+	"time"
 
 	asymkey_model "code.gitea.io/gitea/models/asymkey"
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	asymkey_service "code.gitea.io/gitea/services/asymkey"
 	"code.gitea.io/gitea/services/forms"
@@ -152,6 +156,19 @@ func KeysPost(ctx *context.Context) {
 		}
 		ctx.Flash.Success(ctx.Tr("settings.verify_gpg_key_success", keyID))
 		ctx.Redirect(setting.AppSubURL + "/user/settings/keys")
+	case "rotate_gpg":
+		if err := asymkey_model.RotateGPGKey(ctx, ctx.Doer.ID, ctx.FormInt64("id"), form.SuccessorID); err != nil {
+			switch {
+			case asymkey_model.IsErrGPGKeyNotExist(err), errors.Is(err, util.ErrInvalidArgument):
+				ctx.Flash.Error(err.Error())
+			default:
+				ctx.ServerError("RotateGPGKey", err)
+				return
+			}
+		} else {
+			ctx.Flash.Success(ctx.Tr("settings.rotate_gpg_key_success", asymkey_model.PaddedKeyID(form.Content)))
+		}
+		ctx.Redirect(setting.AppSubURL + "/user/settings/keys")
 	case "ssh":
 		content, err := asymkey_model.CheckPublicKeyString(form.Content)
 		if err != nil {
@@ -191,6 +208,42 @@ func KeysPost(ctx *context.Context) {
 		}
 		ctx.Flash.Success(ctx.Tr("settings.add_key_success", form.Title))
 		ctx.Redirect(setting.AppSubURL + "/user/settings/keys")
+	case "rotate_ssh":
+		if err := asymkey_model.RotatePublicKey(ctx, ctx.Doer.ID, ctx.FormInt64("id"), form.SuccessorID); err != nil {
+			switch {
+			case asymkey_model.IsErrKeyNotExist(err), errors.Is(err, util.ErrInvalidArgument):
+				ctx.Flash.Error(err.Error())
+			default:
+				ctx.ServerError("RotatePublicKey", err)
+				return
+			}
+		} else {
+			ctx.Flash.Success(ctx.Tr("settings.rotate_ssh_key_success", form.Content))
+		}
+		ctx.Redirect(setting.AppSubURL + "/user/settings/keys")
+	case "expiry_ssh":
+		var expiry timeutil.TimeStamp
+		if form.ExpiryDate != "" {
+			date, err := time.ParseInLocation("2006-01-02", form.ExpiryDate, time.Local)
+			if err != nil {
+				ctx.Flash.Error(ctx.Tr("settings.ssh_key_expiry_invalid"))
+				ctx.Redirect(setting.AppSubURL + "/user/settings/keys")
+				return
+			}
+			expiry = timeutil.TimeStamp(time.Date(date.Year(), date.Month(), date.Day(), 23, 59, 59, 0, date.Location()).Unix())
+		}
+		if err := asymkey_model.SetPublicKeyExpiry(ctx, ctx.Doer.ID, ctx.FormInt64("id"), expiry); err != nil {
+			switch {
+			case asymkey_model.IsErrKeyNotExist(err), errors.Is(err, util.ErrInvalidArgument):
+				ctx.Flash.Error(err.Error())
+			default:
+				ctx.ServerError("SetPublicKeyExpiry", err)
+				return
+			}
+		} else {
+			ctx.Flash.Success(ctx.Tr("settings.ssh_key_expiry_success", form.Content))
+		}
+		ctx.Redirect(setting.AppSubURL + "/user/settings/keys")
 	case "verify_ssh":
 		token := asymkey_model.VerificationToken(ctx.Doer, 1)
 		lastToken := asymkey_model.VerificationToken(ctx.Doer, 0)
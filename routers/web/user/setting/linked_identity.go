@@ -0,0 +1,79 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package setting
+
+import (
+	"errors"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/modules/web"
+	"code.gitea.io/gitea/services/forms"
+	user_service "code.gitea.io/gitea/services/user"
+)
+
+// LinkIdentityPost response for linking an account on another instance
+func LinkIdentityPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.LinkIdentityForm)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(setting.AppSubURL + "/user/settings")
+		return
+	}
+
+	identity := &user_model.LinkedIdentity{
+		UserID:         ctx.Doer.ID,
+		InstanceURL:    form.InstanceURL,
+		RemoteUsername: form.RemoteUsername,
+	}
+	if err := user_model.CreateLinkedIdentity(ctx, identity); err != nil {
+		if user_model.IsErrLinkedIdentityAlreadyExist(err) {
+			ctx.Flash.Error(ctx.Tr("settings.linked_identity_already_exists"))
+		} else if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Flash.Error(err.Error())
+		} else {
+			ctx.ServerError("CreateLinkedIdentity", err)
+			return
+		}
+	} else {
+		ctx.Flash.Info(ctx.Tr("settings.linked_identity_added", identity.Token))
+	}
+
+	ctx.Redirect(setting.AppSubURL + "/user/settings")
+}
+
+// VerifyLinkedIdentity checks the token in the profile of the account on the other instance
+func VerifyLinkedIdentity(ctx *context.Context) {
+	identity, err := user_model.GetLinkedIdentity(ctx, ctx.Doer.ID, ctx.ParamsInt64(":id"))
+	if err != nil {
+		ctx.NotFoundOrServerError("GetLinkedIdentity", user_model.IsErrLinkedIdentityNotExist, err)
+		return
+	}
+
+	if err := user_service.VerifyLinkedIdentity(ctx, identity); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Flash.Error(err.Error())
+		} else {
+			ctx.ServerError("VerifyLinkedIdentity", err)
+			return
+		}
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.linked_identity_verified", identity.ProfileURL()))
+	}
+
+	ctx.Redirect(setting.AppSubURL + "/user/settings")
+}
+
+// DeleteLinkedIdentity unlinks the account on another instance
+func DeleteLinkedIdentity(ctx *context.Context) {
+	if err := user_model.DeleteLinkedIdentity(ctx, ctx.Doer.ID, ctx.ParamsInt64(":id")); err != nil {
+		ctx.Flash.Error(err.Error())
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.linked_identity_deleted"))
+	}
+
+	ctx.Redirect(setting.AppSubURL + "/user/settings")
+}
@@ -36,12 +36,20 @@ func (oa *OAuth2CommonHandlers) AddApp(ctx *context.Context) {
 		return
 	}
 
+	scopes, err := form.GetScopes()
+	if err != nil {
+		ctx.Flash.Error(ctx.Tr("settings.oauth2_scopes_invalid", err.Error()))
+		ctx.Redirect(oa.BasePathList)
+		return
+	}
+
 	// TODO validate redirect URI
 	app, err := auth.CreateOAuth2Application(ctx, auth.CreateOAuth2ApplicationOptions{
 		Name:               form.Name,
 		RedirectURIs:       []string{form.RedirectURI},
 		UserID:             oa.OwnerID,
 		ConfidentialClient: form.ConfidentialClient,
+		Scopes:             scopes,
 	})
 	if err != nil {
 		ctx.ServerError("CreateOAuth2Application", err)
@@ -87,14 +95,21 @@ func (oa *OAuth2CommonHandlers) EditSave(ctx *context.Context) {
 		return
 	}
 
+	scopes, err := form.GetScopes()
+	if err != nil {
+		ctx.Flash.Error(ctx.Tr("settings.oauth2_scopes_invalid", err.Error()))
+		ctx.Redirect(fmt.Sprintf("%s/%d", oa.BasePathEditPrefix, ctx.ParamsInt64("id")))
+		return
+	}
+
 	// TODO validate redirect URI
-	var err error
 	if ctx.Data["App"], err = auth.UpdateOAuth2Application(auth.UpdateOAuth2ApplicationOptions{
 		ID:                 ctx.ParamsInt64("id"),
 		Name:               form.Name,
 		RedirectURIs:       []string{form.RedirectURI},
 		UserID:             oa.OwnerID,
 		ConfidentialClient: form.ConfidentialClient,
+		Scopes:             scopes,
 	}); err != nil {
 		ctx.ServerError("UpdateOAuth2Application", err)
 		return
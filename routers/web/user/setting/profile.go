@@ -13,21 +13,25 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
 	"code.gitea.io/gitea/models/organization"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/avatar"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/context"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/translation"
 	"code.gitea.io/gitea/modules/typesniffer"
 	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/modules/web"
 	"code.gitea.io/gitea/modules/web/middleware"
 	"code.gitea.io/gitea/services/forms"
+	theme_service "code.gitea.io/gitea/services/theme"
 	user_service "code.gitea.io/gitea/services/user"
 )
 
@@ -44,6 +48,23 @@ func Profile(ctx *context.Context) {
 	ctx.Data["PageIsSettingsProfile"] = true
 	ctx.Data["AllowedUserVisibilityModes"] = setting.Service.AllowedUserVisibilityModesSlice.ToVisibleTypeSlice()
 
+	status, err := user_model.GetUserStatus(ctx, ctx.Doer.ID)
+	if err != nil && !user_model.IsErrUserStatusNotExist(err) {
+		ctx.ServerError("GetUserStatus", err)
+		return
+	}
+	ctx.Data["UserStatus"] = status
+
+	ctx.Data["EnableLinkedIdentities"] = setting.Service.EnableLinkedIdentities
+	if setting.Service.EnableLinkedIdentities {
+		linkedIdentities, err := user_model.GetLinkedIdentities(ctx, ctx.Doer.ID)
+		if err != nil {
+			ctx.ServerError("GetLinkedIdentities", err)
+			return
+		}
+		ctx.Data["LinkedIdentities"] = linkedIdentities
+	}
+
 	ctx.HTML(http.StatusOK, tplSettingsProfile)
 }
 
@@ -104,6 +125,7 @@ func ProfilePost(ctx *context.Context) {
 	ctx.Doer.Location = form.Location
 	ctx.Doer.Description = form.Description
 	ctx.Doer.KeepActivityPrivate = form.KeepActivityPrivate
+	ctx.Doer.CountPrivateActivity = form.CountPrivateActivity
 	ctx.Doer.Visibility = form.Visibility
 	if err := user_model.UpdateUserSetting(ctx.Doer); err != nil {
 		if _, ok := err.(user_model.ErrEmailAlreadyUsed); ok {
@@ -154,6 +176,9 @@ func UpdateAvatarSetting(ctx *context.Context, form *forms.AvatarForm, ctxUser *
 			return errors.New(ctx.Tr("settings.uploaded_avatar_not_a_image"))
 		}
 		if err = user_service.UploadAvatar(ctxUser, data); err != nil {
+			if errors.Is(err, avatar.ErrAnimatedAvatar) {
+				return errors.New(ctx.Tr("settings.uploaded_avatar_is_animated"))
+			}
 			return fmt.Errorf("UploadAvatar: %w", err)
 		}
 	} else if ctxUser.UseCustomAvatar && ctxUser.Avatar == "" {
@@ -183,6 +208,55 @@ func AvatarPost(ctx *context.Context) {
 	ctx.Redirect(setting.AppSubURL + "/user/settings")
 }
 
+// StatusPost response for setting the user status
+func StatusPost(ctx *context.Context) {
+	form := web.GetForm(ctx).(*forms.UserStatusForm)
+	if ctx.HasError() {
+		ctx.Flash.Error(ctx.GetErrMsg())
+		ctx.Redirect(setting.AppSubURL + "/user/settings")
+		return
+	}
+
+	status := &user_model.UserStatus{
+		UserID:  ctx.Doer.ID,
+		Emoji:   strings.Trim(strings.TrimSpace(form.Emoji), ":"),
+		Message: strings.TrimSpace(form.Message),
+		IsBusy:  form.IsBusy,
+	}
+	if form.EndDate != "" {
+		// the status ends at the end of the chosen day
+		endDate, err := time.ParseInLocation("2006-01-02", form.EndDate, setting.DefaultUILocation)
+		if err != nil {
+			ctx.Flash.Error(ctx.Tr("settings.status_end_date_invalid"))
+			ctx.Redirect(setting.AppSubURL + "/user/settings")
+			return
+		}
+		status.EndUnix = timeutil.TimeStamp(endDate.AddDate(0, 0, 1).Add(-time.Second).Unix())
+	}
+
+	if err := user_model.SetUserStatus(ctx, status); err != nil {
+		if errors.Is(err, util.ErrInvalidArgument) {
+			ctx.Flash.Error(err.Error())
+		} else {
+			ctx.ServerError("SetUserStatus", err)
+			return
+		}
+	} else {
+		ctx.Flash.Success(ctx.Tr("settings.update_status_success"))
+	}
+
+	ctx.Redirect(setting.AppSubURL + "/user/settings")
+}
+
+// ClearStatus clears the user status
+func ClearStatus(ctx *context.Context) {
+	if err := user_model.ClearUserStatus(ctx, ctx.Doer.ID); err != nil {
+		ctx.Flash.Error(err.Error())
+	}
+
+	ctx.Redirect(setting.AppSubURL + "/user/settings")
+}
+
 // DeleteAvatar render delete avatar page
 func DeleteAvatar(ctx *context.Context) {
 	if err := user_service.DeleteAvatar(ctx.Doer); err != nil {
@@ -350,6 +424,12 @@ func Appearance(ctx *context.Context) {
 		return forms.IsUserHiddenCommentTypeGroupChecked(commentTypeGroup, hiddenCommentTypes)
 	}
 
+	ctx.Data["AllThemes"], err = theme_service.SelectableThemeNames(ctx)
+	if err != nil {
+		ctx.ServerError("SelectableThemeNames", err)
+		return
+	}
+
 	ctx.HTML(http.StatusOK, tplSettingsAppearance)
 }
 
@@ -364,7 +444,12 @@ func UpdateUIThemePost(ctx *context.Context) {
 		return
 	}
 
-	if !form.IsThemeExists() {
+	exists, err := theme_service.IsSelectableTheme(ctx, form.Theme)
+	if err != nil {
+		ctx.ServerError("IsSelectableTheme", err)
+		return
+	}
+	if !exists {
 		ctx.Flash.Error(ctx.Tr("settings.theme_update_error"))
 		ctx.Redirect(setting.AppSubURL + "/user/settings/appearance")
 		return
@@ -0,0 +1,30 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package security
+
+import (
+	"net/http"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// DeleteLoginDevice removes a device the user signed in from
+func DeleteLoginDevice(ctx *context.Context) {
+	if err := auth_model.DeleteLoginDevice(ctx, ctx.Doer.ID, ctx.FormInt64("id")); err != nil {
+		if !auth_model.IsErrLoginDeviceNotExist(err) {
+			ctx.ServerError("DeleteLoginDevice", err)
+			return
+		}
+	} else {
+		log.Trace("Login device deleted: %s", ctx.Doer.Name)
+		ctx.Flash.Success(ctx.Tr("settings.login_device_deletion_success"))
+	}
+
+	ctx.JSON(http.StatusOK, map[string]interface{}{
+		"redirect": setting.AppSubURL + "/user/settings/security",
+	})
+}
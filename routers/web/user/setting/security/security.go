@@ -121,4 +121,13 @@ func loadSecurityData(ctx *context.Context) {
 		return
 	}
 	ctx.Data["OpenIDs"] = openid
+
+	if setting.LoginRisk.Enabled {
+		devices, err := auth_model.GetLoginDevices(ctx, ctx.Doer.ID)
+		if err != nil {
+			ctx.ServerError("GetLoginDevices", err)
+			return
+		}
+		ctx.Data["LoginDevices"] = devices
+	}
 }
@@ -143,6 +143,7 @@ func Routes(ctx gocontext.Context) *web.Route {
 
 	routes.Get("/ssh_info", misc.SSHInfo)
 	routes.Get("/api/healthz", healthcheck.Check)
+	routes.Get("/api/healthz/components", healthcheck.CheckComponents)
 
 	mid = append(mid, common.Sessioner(), context.Contexter())
 
@@ -358,6 +359,7 @@ func registerRoutes(m *web.Route) {
 		m.Get("/organizations", explore.Organizations)
 		m.Get("/code", reqUnitAccess(unit.TypeCode, perm.AccessModeRead), explore.Code)
 		m.Get("/topics/search", explore.TopicSearch)
+		m.Get("/federated", reqSignIn, explore.FederatedSearch)
 	}, ignExploreSignIn)
 	m.Group("/issues", func() {
 		m.Get("", user.Issues)
@@ -424,6 +426,17 @@ func registerRoutes(m *web.Route) {
 		m.Post("/change_password", web.Bind(forms.MustChangePasswordForm{}), auth.MustChangePasswordPost)
 		m.Post("/avatar", web.Bind(forms.AvatarForm{}), user_setting.AvatarPost)
 		m.Post("/avatar/delete", user_setting.DeleteAvatar)
+		m.Post("/status", web.Bind(forms.UserStatusForm{}), user_setting.StatusPost)
+		m.Post("/status/clear", user_setting.ClearStatus)
+		m.Group("/linked_identities", func() {
+			m.Post("", web.Bind(forms.LinkIdentityForm{}), user_setting.LinkIdentityPost)
+			m.Post("/{id}/verify", user_setting.VerifyLinkedIdentity)
+			m.Post("/{id}/delete", user_setting.DeleteLinkedIdentity)
+		}, func(ctx *context.Context) {
+			if !setting.Service.EnableLinkedIdentities {
+				ctx.NotFound("EnableLinkedIdentities", nil)
+			}
+		})
 		m.Group("/account", func() {
 			m.Combo("").Get(user_setting.Account).Post(web.Bind(forms.ChangePasswordForm{}), user_setting.AccountPost)
 			m.Post("/email", web.Bind(forms.AddEmailForm{}), user_setting.EmailPost)
@@ -455,6 +468,7 @@ func registerRoutes(m *web.Route) {
 				m.Post("/toggle_visibility", security.ToggleOpenIDVisibility)
 			}, openIDSignInEnabled)
 			m.Post("/account_link", linkAccountEnabled, security.DeleteAccountLink)
+			m.Post("/login_devices/delete", security.DeleteLoginDevice)
 		})
 		m.Group("/applications/oauth2", func() {
 			m.Get("/{id}", user_setting.OAuth2ApplicationShow)
@@ -516,6 +530,7 @@ func registerRoutes(m *web.Route) {
 		m.Post("/activate", auth.ActivatePost)
 		m.Any("/activate_email", auth.ActivateEmail)
 		m.Get("/avatar/{username}/{size}", user.AvatarByUserName)
+		m.Get("/themes/{name}/theme.css", misc.ThemeStylesheet)
 		m.Get("/recover_account", auth.ResetPasswd)
 		m.Post("/recover_account", auth.ResetPasswdPost)
 		m.Get("/forgot_password", auth.ForgotPasswd)
@@ -557,6 +572,7 @@ func registerRoutes(m *web.Route) {
 				m.Post("/remove-all-items", admin.QueueRemoveAllItems)
 			})
 			m.Get("/diagnosis", admin.MonitorDiagnosis)
+			m.Get("/profile", admin.MonitorProfile)
 		})
 
 		m.Group("/users", func() {
@@ -649,6 +665,7 @@ func registerRoutes(m *web.Route) {
 	reqRepoCodeWriter := context.RequireRepoWriter(unit.TypeCode)
 	canEnableEditor := context.CanEnableEditor()
 	reqRepoCodeReader := context.RequireRepoReader(unit.TypeCode)
+	reqRepoCodeUnrestricted := context.RequireRepoCodeUnrestricted()
 	reqRepoReleaseWriter := context.RequireRepoWriter(unit.TypeReleases)
 	reqRepoReleaseReader := context.RequireRepoReader(unit.TypeReleases)
 	reqRepoWikiWriter := context.RequireRepoWriter(unit.TypeWiki)
@@ -797,6 +814,8 @@ func registerRoutes(m *web.Route) {
 		}, context.RepoIDAssignment(), context.UnitTypes(), reqRepoCodeReader)
 		m.Get("/search", repo.SearchRepo)
 	}, reqSignIn)
+	// download links are shared outside the instance, their token authorizes the download
+	m.Get("/repo/shared/{token}", repo.ServeDownloadLink)
 
 	m.Group("/{username}/-", func() {
 		if setting.Packages.Enabled {
@@ -821,6 +840,7 @@ func registerRoutes(m *web.Route) {
 			m.Group("", func() {
 				m.Get("", org.Projects)
 				m.Get("/{id}", org.ViewProject)
+				m.Post("/{id}/view_setting", reqSignIn, web.Bind(forms.ProjectViewSettingForm{}), org.UpdateProjectViewSetting)
 			}, reqUnitAccess(unit.TypeProjects, perm.AccessModeRead))
 			m.Group("", func() { //nolint:dupl
 				m.Get("/new", org.NewProject)
@@ -957,8 +977,8 @@ func registerRoutes(m *web.Route) {
 			m.Get("/tag/*", context.RepoRefByType(context.RepoRefTag), repo.TreeList)
 			m.Get("/commit/*", context.RepoRefByType(context.RepoRefCommit), repo.TreeList)
 		})
-		m.Get("/compare", repo.MustBeNotEmpty, reqRepoCodeReader, repo.SetEditorconfigIfExists, ignSignIn, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.CompareDiff)
-		m.Combo("/compare/*", repo.MustBeNotEmpty, reqRepoCodeReader, repo.SetEditorconfigIfExists).
+		m.Get("/compare", repo.MustBeNotEmpty, reqRepoCodeReader, reqRepoCodeUnrestricted, repo.SetEditorconfigIfExists, ignSignIn, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.CompareDiff)
+		m.Combo("/compare/*", repo.MustBeNotEmpty, reqRepoCodeReader, reqRepoCodeUnrestricted, repo.SetEditorconfigIfExists).
 			Get(repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.CompareDiff).
 			Post(reqSignIn, context.RepoMustNotBeArchived(), reqRepoPullsReader, repo.MustAllowPulls, web.Bind(forms.CreateIssueForm{}), repo.SetWhitespaceBehavior, repo.CompareAndPullRequestPost)
 		m.Group("/{type:issues|pulls}", func() {
@@ -1068,6 +1088,10 @@ func registerRoutes(m *web.Route) {
 					Post(web.Bind(forms.EditRepoFileForm{}), repo.NewDiffPatchPost)
 				m.Combo("/_cherrypick/{sha:([a-f0-9]{7,40})}/*").Get(repo.CherryPick).
 					Post(web.Bind(forms.CherryPickForm{}), repo.CherryPickPost)
+				m.Combo("/_edit_session/*").Get(repo.EditSession).
+					Post(web.Bind(forms.FinishEditSessionForm{}), repo.EditSessionPost)
+				m.Post("/_edit_session_rebase/*", repo.RebaseEditSessionPost)
+				m.Post("/_edit_session_discard/*", repo.DiscardEditSessionPost)
 			}, repo.MustBeEditable)
 			m.Group("", func() {
 				m.Post("/upload-file", repo.UploadFileToServer)
@@ -1083,6 +1107,7 @@ func registerRoutes(m *web.Route) {
 			}, web.Bind(forms.NewBranchForm{}))
 			m.Post("/delete", repo.DeleteBranchPost)
 			m.Post("/restore", repo.RestoreBranchPost)
+			m.Post("/merge-upstream", repo.MergeUpstreamPost)
 		}, context.RepoMustNotBeArchived(), reqRepoCodeWriter, repo.MustBeNotEmpty)
 	}, reqSignIn, context.RepoAssignment, context.UnitTypes())
 
@@ -1154,6 +1179,7 @@ func registerRoutes(m *web.Route) {
 		m.Group("/projects", func() {
 			m.Get("", repo.Projects)
 			m.Get("/{id}", repo.ViewProject)
+			m.Post("/{id}/view_setting", reqSignIn, web.Bind(forms.ProjectViewSettingForm{}), repo.UpdateProjectViewSetting)
 			m.Group("", func() { //nolint:dupl
 				m.Get("/new", repo.NewProject)
 				m.Post("/new", web.Bind(forms.CreateProjectForm{}), repo.NewProjectPost)
@@ -1177,6 +1203,11 @@ func registerRoutes(m *web.Route) {
 			}, reqRepoProjectsWriter, context.RepoMustNotBeArchived())
 		}, reqRepoProjectsReader, repo.MustEnableProjects)
 
+		m.Group("/discussions", func() {
+			m.Get("", repo.Discussions)
+			m.Get("/{index}", repo.ViewDiscussion)
+		}, context.RequireRepoReader(unit.TypeDiscussions))
+
 		m.Group("/actions", func() {
 			m.Get("", actions.List)
 
@@ -1195,6 +1226,7 @@ func registerRoutes(m *web.Route) {
 				m.Post("/artifacts", actions.ArtifactsView)
 				m.Get("/artifacts/{id}", actions.ArtifactsDownloadView)
 				m.Post("/rerun", reqRepoActionsWriter, actions.RerunAll)
+				m.Post("/rerun-failed", reqRepoActionsWriter, actions.RerunFailed)
 			})
 		}, reqRepoActionsReader, actions.MustEnableActions)
 
@@ -1229,7 +1261,7 @@ func registerRoutes(m *web.Route) {
 		m.Group("/archive", func() {
 			m.Get("/*", repo.Download)
 			m.Post("/*", repo.InitiateDownload)
-		}, repo.MustBeNotEmpty, dlSourceEnabled, reqRepoCodeReader)
+		}, repo.MustBeNotEmpty, dlSourceEnabled, reqRepoCodeReader, reqRepoCodeUnrestricted)
 
 		m.Group("/branches", func() {
 			m.Get("", repo.Branches)
@@ -1248,6 +1280,10 @@ func registerRoutes(m *web.Route) {
 			if ctx.Written() {
 				return
 			}
+			reqRepoCodeUnrestricted(ctx)
+			if ctx.Written() {
+				return
+			}
 			cancel = context.RepoRef()(ctx)
 			if ctx.Written() {
 				return
@@ -1258,8 +1294,8 @@ func registerRoutes(m *web.Route) {
 		})
 
 		m.Group("/pulls/{index}", func() {
-			m.Get(".diff", repo.DownloadPullDiff)
-			m.Get(".patch", repo.DownloadPullPatch)
+			m.Get(".diff", reqRepoCodeUnrestricted, repo.DownloadPullDiff)
+			m.Get(".patch", reqRepoCodeUnrestricted, repo.DownloadPullPatch)
 			m.Get("/commits", context.RepoRef(), repo.ViewPullCommits)
 			m.Post("/merge", context.RepoMustNotBeArchived(), web.Bind(forms.MergePullRequestForm{}), repo.MergePullRequest)
 			m.Post("/cancel_auto_merge", context.RepoMustNotBeArchived(), repo.CancelAutoMergePullRequest)
@@ -1267,7 +1303,7 @@ func registerRoutes(m *web.Route) {
 			m.Post("/set_allow_maintainer_edit", web.Bind(forms.UpdateAllowEditsForm{}), repo.SetAllowEdits)
 			m.Post("/cleanup", context.RepoMustNotBeArchived(), context.RepoRef(), repo.CleanUpPullRequest)
 			m.Group("/files", func() {
-				m.Get("", context.RepoRef(), repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.ViewPullFiles)
+				m.Get("", context.RepoRef(), reqRepoCodeUnrestricted, repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.ViewPullFiles)
 				m.Group("/reviews", func() {
 					m.Get("/new_comment", repo.RenderNewCodeCommentForm)
 					m.Post("/comments", web.Bind(forms.CodeCommentForm{}), repo.CreateCodeComment)
@@ -1280,7 +1316,7 @@ func registerRoutes(m *web.Route) {
 			m.Get("/branch/*", context.RepoRefByType(context.RepoRefBranch), repo.SingleDownloadOrLFS)
 			m.Get("/tag/*", context.RepoRefByType(context.RepoRefTag), repo.SingleDownloadOrLFS)
 			m.Get("/commit/*", context.RepoRefByType(context.RepoRefCommit), repo.SingleDownloadOrLFS)
-			m.Get("/blob/{sha}", context.RepoRefByType(context.RepoRefBlob), repo.DownloadByIDOrLFS)
+			m.Get("/blob/{sha}", context.RepoRefByType(context.RepoRefBlob), reqRepoCodeUnrestricted, repo.DownloadByIDOrLFS)
 			// "/*" route is deprecated, and kept for backward compatibility
 			m.Get("/*", context.RepoRefByType(context.RepoRefLegacy), repo.SingleDownloadOrLFS)
 		}, repo.MustBeNotEmpty, reqRepoCodeReader)
@@ -1289,7 +1325,7 @@ func registerRoutes(m *web.Route) {
 			m.Get("/branch/*", context.RepoRefByType(context.RepoRefBranch), repo.SingleDownload)
 			m.Get("/tag/*", context.RepoRefByType(context.RepoRefTag), repo.SingleDownload)
 			m.Get("/commit/*", context.RepoRefByType(context.RepoRefCommit), repo.SingleDownload)
-			m.Get("/blob/{sha}", context.RepoRefByType(context.RepoRefBlob), repo.DownloadByID)
+			m.Get("/blob/{sha}", context.RepoRefByType(context.RepoRefBlob), reqRepoCodeUnrestricted, repo.DownloadByID)
 			// "/*" route is deprecated, and kept for backward compatibility
 			m.Get("/*", context.RepoRefByType(context.RepoRefLegacy), repo.SingleDownload)
 		}, repo.MustBeNotEmpty, reqRepoCodeReader)
@@ -1298,7 +1334,7 @@ func registerRoutes(m *web.Route) {
 			m.Get("/branch/*", context.RepoRefByType(context.RepoRefBranch), repo.RenderFile)
 			m.Get("/tag/*", context.RepoRefByType(context.RepoRefTag), repo.RenderFile)
 			m.Get("/commit/*", context.RepoRefByType(context.RepoRefCommit), repo.RenderFile)
-			m.Get("/blob/{sha}", context.RepoRefByType(context.RepoRefBlob), repo.RenderFile)
+			m.Get("/blob/{sha}", context.RepoRefByType(context.RepoRefBlob), reqRepoCodeUnrestricted, repo.RenderFile)
 		}, repo.MustBeNotEmpty, reqRepoCodeReader)
 
 		m.Group("/commits", func() {
@@ -1317,8 +1353,8 @@ func registerRoutes(m *web.Route) {
 
 		m.Group("", func() {
 			m.Get("/graph", repo.Graph)
-			m.Get("/commit/{sha:([a-f0-9]{7,40})$}", repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.Diff)
-			m.Get("/cherry-pick/{sha:([a-f0-9]{7,40})$}", repo.SetEditorconfigIfExists, repo.CherryPick)
+			m.Get("/commit/{sha:([a-f0-9]{7,40})$}", reqRepoCodeUnrestricted, repo.SetEditorconfigIfExists, repo.SetDiffViewStyle, repo.SetWhitespaceBehavior, repo.Diff)
+			m.Get("/cherry-pick/{sha:([a-f0-9]{7,40})$}", reqRepoCodeUnrestricted, repo.SetEditorconfigIfExists, repo.CherryPick)
 		}, repo.MustBeNotEmpty, context.RepoRef(), reqRepoCodeReader)
 
 		m.Get("/rss/branch/*", context.RepoRefByType(context.RepoRefBranch), feedEnabled, feed.RenderBranchFeed)
@@ -1335,15 +1371,19 @@ func registerRoutes(m *web.Route) {
 		m.Group("", func() {
 			m.Get("/forks", repo.Forks)
 		}, context.RepoRef(), reqRepoCodeReader)
-		m.Get("/commit/{sha:([a-f0-9]{7,40})}.{ext:patch|diff}", repo.MustBeNotEmpty, reqRepoCodeReader, repo.RawDiff)
+		m.Get("/commit/{sha:([a-f0-9]{7,40})}.{ext:patch|diff}", repo.MustBeNotEmpty, reqRepoCodeReader, reqRepoCodeUnrestricted, repo.RawDiff)
 	}, ignSignIn, context.RepoAssignment, context.UnitTypes())
 
+	m.Get("/{username}/{reponame}/badges/{kind:status|release|coverage|issues}.svg", ignSignInAndCsrf, repo.Badge)
+	m.Get("/{username}/{reponame}/embed/{widget:issues|releases|activity}", ignSignInAndCsrf, repo.EmbedWidget)
+	m.Get("/{username}/{reponame}/embed/{widget:issues|releases|activity}.json", ignSignInAndCsrf, repo.EmbedWidgetJSON)
+
 	m.Post("/{username}/{reponame}/lastcommit/*", ignSignInAndCsrf, context.RepoAssignment, context.UnitTypes(), context.RepoRefByType(context.RepoRefCommit), reqRepoCodeReader, repo.LastCommit)
 
 	m.Group("/{username}/{reponame}", func() {
 		m.Get("/stars", repo.Stars)
 		m.Get("/watchers", repo.Watchers)
-		m.Get("/search", reqRepoCodeReader, repo.Search)
+		m.Get("/search", reqRepoCodeReader, reqRepoCodeUnrestricted, repo.Search)
 	}, ignSignIn, context.RepoAssignment, context.RepoRef(), context.UnitTypes())
 
 	m.Group("/{username}", func() {
@@ -1378,9 +1418,9 @@ func registerRoutes(m *web.Route) {
 				m.GetOptions("/objects/info/http-alternates", repo.GetTextFile("objects/info/http-alternates"))
 				m.GetOptions("/objects/info/packs", repo.GetInfoPacks)
 				m.GetOptions("/objects/info/{file:[^/]*}", repo.GetTextFile(""))
-				m.GetOptions("/objects/{head:[0-9a-f]{2}}/{hash:[0-9a-f]{38}}", repo.GetLooseObject)
-				m.GetOptions("/objects/pack/pack-{file:[0-9a-f]{40}}.pack", repo.GetPackFile)
-				m.GetOptions("/objects/pack/pack-{file:[0-9a-f]{40}}.idx", repo.GetIdxFile)
+				m.GetOptions("/objects/{head:[0-9a-f]{2}}/{hash:(?:[0-9a-f]{38}|[0-9a-f]{62})}", repo.GetLooseObject)
+				m.GetOptions("/objects/pack/pack-{file:(?:[0-9a-f]{40}|[0-9a-f]{64})}.pack", repo.GetPackFile)
+				m.GetOptions("/objects/pack/pack-{file:(?:[0-9a-f]{40}|[0-9a-f]{64})}.idx", repo.GetIdxFile)
 			}, ignSignInAndCsrf, repo.HTTPGitEnabledHandler, repo.CorsHandler(), context_service.UserAssignmentWeb())
 		})
 	})
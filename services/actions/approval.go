@@ -0,0 +1,51 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrRunNoApprovalNeeded is returned when approving a run which doesn't wait for an approval
+var ErrRunNoApprovalNeeded = util.NewInvalidArgumentErrorf("the run doesn't need an approval")
+
+// ApproveRun approves a run waiting for the approval of a maintainer, the jobs which don't need other jobs start.
+// The tasks of the run keep their restricted token.
+func ApproveRun(ctx context.Context, run *actions_model.ActionRun, jobs []*actions_model.ActionRunJob, doer *user_model.User) error {
+	if !run.NeedApproval {
+		return ErrRunNoApprovalNeeded
+	}
+
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		run.NeedApproval = false
+		run.ApprovedBy = doer.ID
+		if err := actions_model.UpdateRun(ctx, run, "need_approval", "approved_by"); err != nil {
+			return err
+		}
+		for _, job := range jobs {
+			if len(job.Needs) == 0 && job.Status.IsBlocked() {
+				job.Status = actions_model.StatusWaiting
+				_, err := actions_model.UpdateRunJob(ctx, job, nil, "status")
+				if err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	CreateCommitStatus(ctx, jobs...)
+
+	notification.NotifyActionRunApproved(ctx, doer, run)
+
+	return nil
+}
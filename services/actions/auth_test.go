@@ -0,0 +1,83 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorizationToken(t *testing.T) {
+	oldSecretKey := setting.SecretKey
+	setting.SecretKey = "secret"
+	defer func() {
+		setting.SecretKey = oldSecretKey
+	}()
+
+	request := func(authorization string) *http.Request {
+		req, _ := http.NewRequest("GET", "/", nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		return req
+	}
+
+	token, err := CreateAuthorizationToken(47, 791, 192)
+	assert.NoError(t, err)
+
+	taskID, err := ParseAuthorizationToken(request("Bearer " + token))
+	assert.NoError(t, err)
+	assert.EqualValues(t, 47, taskID)
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return []byte(setting.SecretKey), nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Actions.Results:791:192", claims["scp"])
+
+	// requests without a runtime token authenticate with the task token instead
+	taskID, err = ParseAuthorizationToken(request(""))
+	assert.NoError(t, err)
+	assert.Zero(t, taskID)
+
+	_, err = ParseAuthorizationToken(request("Basic " + token))
+	assert.Error(t, err)
+
+	t.Run("Expired", func(t *testing.T) {
+		expired := jwt.NewWithClaims(jwt.SigningMethodHS256, actionsClaims{
+			RegisteredClaims: jwt.RegisteredClaims{
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
+			},
+			TaskID: 47,
+		})
+		tokenString, err := expired.SignedString([]byte(setting.SecretKey))
+		assert.NoError(t, err)
+		_, err = ParseAuthorizationToken(request("Bearer " + tokenString))
+		assert.Error(t, err)
+	})
+
+	t.Run("OtherSecret", func(t *testing.T) {
+		setting.SecretKey = "other secret"
+		defer func() {
+			setting.SecretKey = "secret"
+		}()
+		_, err := ParseAuthorizationToken(request("Bearer " + token))
+		assert.Error(t, err)
+	})
+
+	t.Run("SigningMethodNone", func(t *testing.T) {
+		unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, actionsClaims{TaskID: 47})
+		tokenString, err := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+		assert.NoError(t, err)
+		_, err = ParseAuthorizationToken(request("Bearer " + tokenString))
+		assert.Error(t, err)
+	})
+}
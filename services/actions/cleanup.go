@@ -0,0 +1,84 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// CleanupArtifacts deletes the files of the artifacts whose retention period is over
+func CleanupArtifacts(ctx context.Context) error {
+	artifacts, err := actions_model.ListNeedExpiredArtifacts(ctx)
+	if err != nil {
+		return fmt.Errorf("list expired artifacts: %w", err)
+	}
+	log.Info("Found %d expired artifacts", len(artifacts))
+	for _, artifact := range artifacts {
+		if artifact.StoragePath != "" {
+			if err := storage.ActionsArtifacts.Delete(artifact.StoragePath); err != nil {
+				log.Error("Cannot delete artifact %d: %v", artifact.ID, err)
+				continue
+			}
+		}
+		if err := actions_model.SetArtifactExpired(ctx, artifact.ID); err != nil {
+			log.Error("Cannot set artifact %d expired: %v", artifact.ID, err)
+			continue
+		}
+		log.Info("Artifact %d set expired", artifact.ID)
+	}
+	return nil
+}
+
+const expiredLogsBatchSize = 100
+
+// CleanupExpiredLogs deletes the logs of the tasks which stopped longer ago than the log retention period.
+// The tasks are kept, but their line indexes are dropped as well because there is nothing left to index.
+func CleanupExpiredLogs(ctx context.Context) error {
+	if setting.Actions.LogRetentionDays <= 0 {
+		return nil
+	}
+	olderThan := timeutil.TimeStamp(time.Now().AddDate(0, 0, -int(setting.Actions.LogRetentionDays)).Unix())
+
+	count := 0
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		tasks, err := actions_model.FindTasksWithExpiredLogs(ctx, olderThan, expiredLogsBatchSize)
+		if err != nil {
+			return fmt.Errorf("find tasks with expired logs: %w", err)
+		}
+		for _, task := range tasks {
+			if task.LogFilename != "" {
+				if err := actions.RemoveLogs(ctx, task.LogInStorage, task.LogFilename); err != nil {
+					// the task is still marked as expired, otherwise it would be tried again every time
+					log.Error("Cannot remove logs of task %d: %v", task.ID, err)
+				}
+			}
+			task.LogExpired = true
+			task.LogIndexes = nil
+			if err := actions_model.UpdateTask(ctx, task, "log_expired", "log_indexes"); err != nil {
+				return fmt.Errorf("update task %d: %w", task.ID, err)
+			}
+			count++
+		}
+		if len(tasks) < expiredLogsBatchSize {
+			break
+		}
+	}
+	log.Info("Removed the expired logs of %d tasks", count)
+	return nil
+}
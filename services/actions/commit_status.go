@@ -15,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/services/mergequeue"
 
 	"github.com/nektos/act/pkg/jobparser"
 )
@@ -29,51 +30,61 @@ func CreateCommitStatus(ctx context.Context, jobs ...*actions_model.ActionRunJob
 	}
 }
 
-func createCommitStatus(ctx context.Context, job *actions_model.ActionRunJob) error {
+// getCommitStatusContext returns the commit and the commit status context of a job,
+// the commit is empty if the event of the run doesn't create commit statuses
+func getCommitStatusContext(ctx context.Context, job *actions_model.ActionRunJob) (sha, ctxname string, err error) {
 	if err := job.LoadAttributes(ctx); err != nil {
-		return fmt.Errorf("load run: %w", err)
+		return "", "", fmt.Errorf("load run: %w", err)
 	}
 
 	run := job.Run
 
-	var (
-		sha   string
-		event string
-	)
+	var event string
 	switch run.Event {
 	case webhook_module.HookEventPush:
 		event = "push"
 		payload, err := run.GetPushEventPayload()
 		if err != nil {
-			return fmt.Errorf("GetPushEventPayload: %w", err)
+			return "", "", fmt.Errorf("GetPushEventPayload: %w", err)
 		}
 		if payload.HeadCommit == nil {
-			return fmt.Errorf("head commit is missing in event payload")
+			return "", "", fmt.Errorf("head commit is missing in event payload")
 		}
 		sha = payload.HeadCommit.ID
 	case webhook_module.HookEventPullRequest, webhook_module.HookEventPullRequestSync:
 		event = "pull_request"
 		payload, err := run.GetPullRequestEventPayload()
 		if err != nil {
-			return fmt.Errorf("GetPullRequestEventPayload: %w", err)
+			return "", "", fmt.Errorf("GetPullRequestEventPayload: %w", err)
 		}
 		if payload.PullRequest == nil {
-			return fmt.Errorf("pull request is missing in event payload")
+			return "", "", fmt.Errorf("pull request is missing in event payload")
 		} else if payload.PullRequest.Head == nil {
-			return fmt.Errorf("head of pull request is missing in event payload")
+			return "", "", fmt.Errorf("head of pull request is missing in event payload")
 		}
 		sha = payload.PullRequest.Head.Sha
 	default:
-		return nil
+		return "", "", nil
 	}
 
-	repo := run.Repo
 	// TODO: store workflow name as a field in ActionRun to avoid parsing
 	runName := path.Base(run.WorkflowID)
 	if wfs, err := jobparser.Parse(job.WorkflowPayload); err == nil && len(wfs) > 0 {
 		runName = wfs[0].Name
 	}
-	ctxname := fmt.Sprintf("%s / %s (%s)", runName, job.Name, event)
+	return sha, fmt.Sprintf("%s / %s (%s)", runName, job.Name, event), nil
+}
+
+func createCommitStatus(ctx context.Context, job *actions_model.ActionRunJob) error {
+	sha, ctxname, err := getCommitStatusContext(ctx, job)
+	if err != nil {
+		return err
+	} else if sha == "" {
+		return nil
+	}
+
+	run := job.Run
+	repo := run.Repo
 	state := toCommitStatus(job.Status)
 	if statuses, _, err := git_model.GetLatestCommitStatus(ctx, repo.ID, sha, db.ListOptions{}); err == nil {
 		for _, v := range statuses {
@@ -130,6 +141,18 @@ func createCommitStatus(ctx context.Context, job *actions_model.ActionRunJob) er
 		return fmt.Errorf("NewCommitStatus: %w", err)
 	}
 
+	if !state.IsPending() {
+		if err := mergequeue.HandleCommitStatus(ctx, repo, sha); err != nil {
+			return fmt.Errorf("HandleCommitStatus: %w", err)
+		}
+	}
+
+	if job.Status.In(actions_model.StatusSuccess, actions_model.StatusFailure) {
+		if err := retryFlakyJob(ctx, job, sha, ctxname); err != nil {
+			return fmt.Errorf("retryFlakyJob: %w", err)
+		}
+	}
+
 	return nil
 }
 
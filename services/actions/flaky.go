@@ -0,0 +1,65 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// startCommitStatusAttempts records the retries of the jobs whose commit status contexts are flaky-tolerant.
+// It won't return an error, but will log it, because it's not critical.
+func startCommitStatusAttempts(ctx context.Context, reason string, doerID int64, jobs ...*actions_model.ActionRunJob) {
+	for _, job := range jobs {
+		if err := startCommitStatusAttempt(ctx, job, reason, doerID); err != nil {
+			log.Error("Failed to start commit status attempt for job %d: %v", job.ID, err)
+		}
+	}
+}
+
+func startCommitStatusAttempt(ctx context.Context, job *actions_model.ActionRunJob, reason string, doerID int64) error {
+	sha, ctxname, err := getCommitStatusContext(ctx, job)
+	if err != nil || sha == "" {
+		return err
+	}
+	rules, err := git_model.GetFlakyCheckRules(ctx, job.RepoID)
+	if err != nil {
+		return err
+	}
+	if git_model.MatchFlakyCheckRule(rules, ctxname) == nil {
+		return nil
+	}
+	return git_model.StartCommitStatusAttempt(ctx, &git_model.CommitStatusAttempt{
+		RepoID:  job.RepoID,
+		SHA:     sha,
+		Context: ctxname,
+		Reason:  reason,
+		DoerID:  doerID,
+		JobID:   job.ID,
+	})
+}
+
+// retryFlakyJob re-dispatches a finished job if its commit status context is flaky-tolerant and needs another attempt
+func retryFlakyJob(ctx context.Context, job *actions_model.ActionRunJob, sha, ctxname string) error {
+	rules, err := git_model.GetFlakyCheckRules(ctx, job.RepoID)
+	if err != nil {
+		return err
+	}
+	rule := git_model.MatchFlakyCheckRule(rules, ctxname)
+	if rule == nil {
+		return nil
+	}
+	attempts, err := git_model.GetCommitStatusAttempts(ctx, job.RepoID, sha, ctxname)
+	if err != nil {
+		return err
+	}
+	if !rule.NeedsRetry(attempts) {
+		return nil
+	}
+	log.Trace("Retrying flaky job %d of %q after %d attempts", job.ID, ctxname, len(attempts))
+	return rerunJob(ctx, job, git_model.CommitStatusAttemptReasonAuto, 0)
+}
@@ -19,4 +19,8 @@ func Init() {
 	go graceful.GetManager().RunWithShutdownFns(jobEmitterQueue.Run)
 
 	notification.RegisterNotifier(NewNotifier())
+
+	if setting.ActionsKubernetes.Enabled {
+		go graceful.GetManager().RunWithShutdownContext(runKubernetesDispatcher)
+	}
 }
@@ -0,0 +1,352 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/kubernetes"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+
+	gouuid "github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	// kubernetesRunnerContainer is the name of the container the runner runs in
+	kubernetesRunnerContainer = "runner"
+	// kubernetesRunnerCommand writes the registration of the runner, which is passed in an environment variable, and starts it
+	kubernetesRunnerCommand = `printenv GITEA_RUNNER_REGISTRATION > .runner && exec act_runner daemon`
+	// kubernetesTemplateDescriptionPrefix is followed by the name of the pod template in the description of the runner
+	kubernetesTemplateDescriptionPrefix = "Kubernetes pod template: "
+	kubernetesTemplateLabel             = "gitea.io/actions-pod-template"
+	kubernetesLogTailLines              = 50
+)
+
+// PodTemplate describes the pods which are created for the jobs with some labels
+type PodTemplate struct {
+	Name string `yaml:"-"`
+	// Labels of the jobs which run in the pods of the template
+	Labels []string `yaml:"labels"`
+	// Scheme is how the runner executes the jobs, e.g. "host" or "docker://node:16-bullseye"
+	Scheme string `yaml:"scheme"`
+	// Spec is the spec of the pods, a container named "runner" is added if there isn't one
+	Spec map[string]any `yaml:"spec"`
+}
+
+// CanRun returns whether a job with the given labels runs in the pods of the template
+func (t *PodTemplate) CanRun(labels []string) bool {
+	if len(labels) == 0 {
+		return false
+	}
+	set := container.SetOf(t.Labels...)
+	for _, label := range labels {
+		if !set.Contains(label) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParsePodTemplate parses a pod template, its name is used in the names of the created jobs
+func ParsePodTemplate(name string, content []byte) (*PodTemplate, error) {
+	t := &PodTemplate{}
+	if err := yaml.Unmarshal(content, t); err != nil {
+		return nil, fmt.Errorf("parse pod template %s: %w", name, err)
+	}
+	t.Name = name
+	if len(t.Labels) == 0 {
+		return nil, fmt.Errorf("pod template %s has no labels", name)
+	}
+	if t.Scheme == "" {
+		t.Scheme = "host"
+	}
+	if t.Spec == nil {
+		t.Spec = map[string]any{}
+	}
+	return t, nil
+}
+
+// LoadPodTemplates loads the pod templates from the YAML files in a directory, sorted by their name
+func LoadPodTemplates(dir string) ([]*PodTemplate, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	templates := make([]*PodTemplate, 0, len(entries))
+	for _, entry := range entries {
+		ext := filepath.Ext(entry.Name())
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		t, err := ParsePodTemplate(strings.TrimSuffix(entry.Name(), ext), content)
+		if err != nil {
+			return nil, err
+		}
+		templates = append(templates, t)
+	}
+	sort.Slice(templates, func(i, j int) bool {
+		return templates[i].Name < templates[j].Name
+	})
+	return templates, nil
+}
+
+// JobManifest returns the manifest of the Kubernetes job which runs the runner in a pod of the template
+func (t *PodTemplate) JobManifest(runner *actions_model.ActionRunner, instanceURL, runnerImage string) (map[string]any, error) {
+	labels := make([]string, 0, len(t.Labels))
+	for _, label := range t.Labels {
+		labels = append(labels, label+":"+t.Scheme)
+	}
+	registration, err := json.Marshal(map[string]any{
+		"id":      runner.ID,
+		"uuid":    runner.UUID,
+		"name":    runner.Name,
+		"token":   runner.Token,
+		"address": instanceURL,
+		"labels":  labels,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// copy the spec, it is modified for every job
+	data, err := json.Marshal(t.Spec)
+	if err != nil {
+		return nil, err
+	}
+	spec := map[string]any{}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	spec["restartPolicy"] = "Never"
+
+	containers, _ := spec["containers"].([]any)
+	var runnerContainer map[string]any
+	for _, c := range containers {
+		if c, ok := c.(map[string]any); ok && c["name"] == kubernetesRunnerContainer {
+			runnerContainer = c
+			break
+		}
+	}
+	if runnerContainer == nil {
+		runnerContainer = map[string]any{"name": kubernetesRunnerContainer}
+		containers = append([]any{runnerContainer}, containers...)
+		spec["containers"] = containers
+	}
+	if runnerContainer["image"] == nil {
+		runnerContainer["image"] = runnerImage
+	}
+	if runnerContainer["command"] == nil {
+		runnerContainer["command"] = []any{"sh", "-c", kubernetesRunnerCommand}
+	}
+	env, _ := runnerContainer["env"].([]any)
+	runnerContainer["env"] = append(env, map[string]any{"name": "GITEA_RUNNER_REGISTRATION", "value": string(registration)})
+
+	metadataLabels := map[string]any{
+		"app.kubernetes.io/managed-by": "gitea",
+		kubernetesTemplateLabel:        t.Name,
+	}
+	return map[string]any{
+		"apiVersion": "batch/v1",
+		"kind":       "Job",
+		"metadata": map[string]any{
+			"name":   runner.Name,
+			"labels": metadataLabels,
+		},
+		"spec": map[string]any{
+			"backoffLimit": 0,
+			"template": map[string]any{
+				"metadata": map[string]any{"labels": metadataLabels},
+				"spec":     spec,
+			},
+		},
+	}, nil
+}
+
+// kubernetesJobName returns a name for a job which is valid in Kubernetes, it is also the name of the runner
+func kubernetesJobName(template string, uuid string) string {
+	name := strings.Map(func(r rune) rune {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') || r == '-' {
+			return r
+		}
+		if r >= 'A' && r <= 'Z' {
+			return r - 'A' + 'a'
+		}
+		return '-'
+	}, template)
+	name, _ = util.SplitStringAtByteN(strings.Trim(name, "-"), 32)
+	return "gitea-actions-" + name + "-" + strings.ReplaceAll(uuid, "-", "")[:12]
+}
+
+// runKubernetesDispatcher creates Kubernetes jobs for the waiting jobs and removes the finished ones until shutdown
+func runKubernetesDispatcher(ctx context.Context) {
+	cfg := setting.ActionsKubernetes
+	templates, err := LoadPodTemplates(cfg.PodTemplatePath)
+	if err != nil {
+		log.Error("Unable to load the Kubernetes pod templates of Actions: %v", err)
+		return
+	}
+	if len(templates) == 0 {
+		log.Warn("No Kubernetes pod templates of Actions found in %s", cfg.PodTemplatePath)
+		return
+	}
+	client, err := kubernetes.NewClient(cfg.APIURL, cfg.Namespace, cfg.TokenFile, cfg.CAFile)
+	if err != nil {
+		log.Error("Unable to create the Kubernetes client of Actions: %v", err)
+		return
+	}
+
+	ticker := time.NewTicker(cfg.PollInterval)
+	defer ticker.Stop()
+	for {
+		if err := dispatchKubernetesJobs(ctx, client, templates); err != nil {
+			log.Error("Unable to dispatch Actions jobs to Kubernetes: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// dispatchKubernetesJobs removes the runners which are done and creates one for every waiting job the idle ones
+// don't cover. Jobs which are picked up by other runners meanwhile leave idle runners, they are removed after
+// the pending timeout.
+func dispatchKubernetesJobs(ctx context.Context, client *kubernetes.Client, templates []*PodTemplate) error {
+	runners, err := actions_model.FindRunners(ctx, actions_model.FindRunnerOptions{IsEphemeral: util.OptionalBoolTrue})
+	if err != nil {
+		return fmt.Errorf("find ephemeral runners: %w", err)
+	}
+	active := 0
+	idle := make(map[string]int)
+	for _, runner := range runners {
+		isActive, isIdle, err := checkKubernetesRunner(ctx, client, runner)
+		if err != nil {
+			log.Error("Unable to check Kubernetes job %s: %v", runner.Name, err)
+			isActive = true
+		}
+		if isActive {
+			active++
+		}
+		if isIdle {
+			idle[strings.TrimPrefix(runner.Description, kubernetesTemplateDescriptionPrefix)]++
+		}
+	}
+
+	waiting, err := actions_model.FindWaitingJobsLabels(ctx)
+	if err != nil {
+		return fmt.Errorf("find waiting jobs: %w", err)
+	}
+	needed := make(map[string]int)
+	for _, labels := range waiting {
+		for _, t := range templates {
+			if t.CanRun(labels) {
+				needed[t.Name]++
+				break
+			}
+		}
+	}
+
+	for _, t := range templates {
+		for n := idle[t.Name]; n < needed[t.Name]; n++ {
+			if active >= setting.ActionsKubernetes.MaxJobs {
+				return nil
+			}
+			if err := createKubernetesRunner(ctx, client, t); err != nil {
+				return err
+			}
+			active++
+		}
+	}
+	return nil
+}
+
+func createKubernetesRunner(ctx context.Context, client *kubernetes.Client, t *PodTemplate) error {
+	uuid := gouuid.New().String()
+	runner := &actions_model.ActionRunner{
+		UUID:        uuid,
+		Name:        kubernetesJobName(t.Name, uuid),
+		Description: kubernetesTemplateDescriptionPrefix + t.Name,
+		AgentLabels: t.Labels,
+		Ephemeral:   true,
+	}
+	if err := runner.GenerateToken(); err != nil {
+		return err
+	}
+	if err := actions_model.CreateRunner(ctx, runner); err != nil {
+		return fmt.Errorf("create runner: %w", err)
+	}
+
+	manifest, err := t.JobManifest(runner, setting.ActionsKubernetes.InstanceURL, setting.ActionsKubernetes.RunnerImage)
+	if err == nil {
+		err = client.CreateJob(ctx, manifest)
+	}
+	if err != nil {
+		if err := actions_model.DeleteRunner(ctx, runner.ID); err != nil {
+			log.Error("Unable to delete runner %s: %v", runner.Name, err)
+		}
+		return fmt.Errorf("create Kubernetes job %s: %w", runner.Name, err)
+	}
+	log.Trace("Created Kubernetes job %s for pod template %s", runner.Name, t.Name)
+	return nil
+}
+
+// checkKubernetesRunner removes the job and the runner if the runner is done. A runner is active while it exists,
+// it is idle until it got its task.
+func checkKubernetesRunner(ctx context.Context, client *kubernetes.Client, runner *actions_model.ActionRunner) (isActive, isIdle bool, err error) {
+	task, err := actions_model.GetTaskByRunnerID(ctx, runner.ID)
+	if err != nil {
+		return false, false, err
+	}
+
+	remove := false
+	if task != nil && task.IsStopped() {
+		remove = true
+	} else if job, err := client.GetJob(ctx, runner.Name); err != nil {
+		if !errors.Is(err, util.ErrNotExist) {
+			return false, false, err
+		}
+		log.Warn("Kubernetes job %s of runner %d was deleted before the runner finished", runner.Name, runner.ID)
+		remove = true
+	} else if job.IsFinished() {
+		// a running task is stopped once it is a zombie, but the log of the pod tells why it is one
+		logs, err := client.GetJobLogs(ctx, runner.Name, kubernetesRunnerContainer, kubernetesLogTailLines)
+		if err != nil {
+			logs = fmt.Sprintf("(unable to get the log: %v)", err)
+		}
+		log.Warn("Pod of Kubernetes job %s terminated before the runner finished, log of the runner:\n%s", runner.Name, logs)
+		remove = true
+	} else if task == nil && time.Since(runner.Created.AsTime()) > setting.ActionsKubernetes.PendingTimeout {
+		log.Trace("Kubernetes job %s got no task in time", runner.Name)
+		remove = true
+	}
+
+	if !remove {
+		return true, task == nil, nil
+	}
+	if err := client.DeleteJob(ctx, runner.Name); err != nil {
+		return false, false, fmt.Errorf("delete Kubernetes job: %w", err)
+	}
+	if err := actions_model.DeleteRunner(ctx, runner.ID); err != nil {
+		return false, false, fmt.Errorf("delete runner: %w", err)
+	}
+	return false, false, nil
+}
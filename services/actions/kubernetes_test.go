@@ -0,0 +1,126 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/modules/json"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testPodTemplate = `
+labels: [ubuntu-latest, linux]
+spec:
+  nodeSelector:
+    pool: ci
+  containers:
+    - name: runner
+      env:
+        - name: DEBUG
+          value: "1"
+    - name: docker
+      image: docker:dind
+`
+
+func TestParsePodTemplate(t *testing.T) {
+	tmpl, err := ParsePodTemplate("ubuntu", []byte(testPodTemplate))
+	assert.NoError(t, err)
+	assert.Equal(t, "ubuntu", tmpl.Name)
+	assert.Equal(t, []string{"ubuntu-latest", "linux"}, tmpl.Labels)
+	assert.Equal(t, "host", tmpl.Scheme)
+
+	assert.True(t, tmpl.CanRun([]string{"ubuntu-latest"}))
+	assert.True(t, tmpl.CanRun([]string{"linux", "ubuntu-latest"}))
+	assert.False(t, tmpl.CanRun([]string{"ubuntu-latest", "gpu"}))
+	assert.False(t, tmpl.CanRun(nil))
+
+	_, err = ParsePodTemplate("empty", []byte("spec: {}"))
+	assert.Error(t, err)
+	_, err = ParsePodTemplate("invalid", []byte("labels: ["))
+	assert.Error(t, err)
+}
+
+func TestLoadPodTemplates(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("labels: [b]"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "a.yml"), []byte("labels: [a]\nscheme: docker://node:16"), 0o644))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "README.md"), []byte("not a template"), 0o644))
+
+	templates, err := LoadPodTemplates(dir)
+	assert.NoError(t, err)
+	if assert.Len(t, templates, 2) {
+		assert.Equal(t, "a", templates[0].Name)
+		assert.Equal(t, "docker://node:16", templates[0].Scheme)
+		assert.Equal(t, "b", templates[1].Name)
+	}
+}
+
+func TestPodTemplateJobManifest(t *testing.T) {
+	tmpl, err := ParsePodTemplate("ubuntu", []byte(testPodTemplate))
+	assert.NoError(t, err)
+
+	runner := &actions_model.ActionRunner{ID: 3, UUID: "uuid", Name: "gitea-actions-ubuntu-0123", Token: "secret"}
+	manifest, err := tmpl.JobManifest(runner, "https://gitea.example.com/", "gitea/act_runner:latest")
+	assert.NoError(t, err)
+
+	data, err := json.Marshal(manifest)
+	assert.NoError(t, err)
+	parsed := struct {
+		Metadata struct {
+			Name string `json:"name"`
+		} `json:"metadata"`
+		Spec struct {
+			BackoffLimit int `json:"backoffLimit"`
+			Template     struct {
+				Spec struct {
+					RestartPolicy string            `json:"restartPolicy"`
+					NodeSelector  map[string]string `json:"nodeSelector"`
+					Containers    []struct {
+						Name    string   `json:"name"`
+						Image   string   `json:"image"`
+						Command []string `json:"command"`
+						Env     []struct {
+							Name  string `json:"name"`
+							Value string `json:"value"`
+						} `json:"env"`
+					} `json:"containers"`
+				} `json:"spec"`
+			} `json:"template"`
+		} `json:"spec"`
+	}{}
+	assert.NoError(t, json.Unmarshal(data, &parsed))
+
+	assert.Equal(t, "gitea-actions-ubuntu-0123", parsed.Metadata.Name)
+	assert.Equal(t, 0, parsed.Spec.BackoffLimit)
+	spec := parsed.Spec.Template.Spec
+	assert.Equal(t, "Never", spec.RestartPolicy)
+	assert.Equal(t, map[string]string{"pool": "ci"}, spec.NodeSelector)
+	if assert.Len(t, spec.Containers, 2) {
+		runnerContainer := spec.Containers[0]
+		assert.Equal(t, "runner", runnerContainer.Name)
+		assert.Equal(t, "gitea/act_runner:latest", runnerContainer.Image)
+		assert.Equal(t, []string{"sh", "-c", kubernetesRunnerCommand}, runnerContainer.Command)
+		if assert.Len(t, runnerContainer.Env, 2) {
+			assert.Equal(t, "DEBUG", runnerContainer.Env[0].Name)
+			assert.Equal(t, "GITEA_RUNNER_REGISTRATION", runnerContainer.Env[1].Name)
+			assert.JSONEq(t, `{"id":3,"uuid":"uuid","name":"gitea-actions-ubuntu-0123","token":"secret","address":"https://gitea.example.com/","labels":["ubuntu-latest:host","linux:host"]}`, runnerContainer.Env[1].Value)
+		}
+		assert.Equal(t, "docker:dind", spec.Containers[1].Image)
+	}
+
+	// the spec of the template isn't modified
+	containers := tmpl.Spec["containers"].([]any)
+	assert.Len(t, containers[0].(map[string]any)["env"], 1)
+}
+
+func TestKubernetesJobName(t *testing.T) {
+	assert.Equal(t, "gitea-actions-ubuntu-latest-0123456789ab", kubernetesJobName("Ubuntu_Latest", "01234567-89ab-cdef-0123-456789abcdef"))
+	name := kubernetesJobName("a-very-long-template-name-which-is-too-long-for-kubernetes", "01234567-89ab-cdef-0123-456789abcdef")
+	assert.LessOrEqual(t, len(name), 63)
+}
@@ -19,6 +19,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/json"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
 	api "code.gitea.io/gitea/modules/structs"
 	webhook_module "code.gitea.io/gitea/modules/webhook"
 	"code.gitea.io/gitea/services/convert"
@@ -101,6 +102,9 @@ func (input *notifyInput) Notify(ctx context.Context) {
 	if err := notify(ctx, input); err != nil {
 		log.Error("an error occurred while executing the %s actions method: %v", getMethod(ctx), err)
 	}
+	if err := notifyCrossRepo(ctx, input); err != nil {
+		log.Error("an error occurred while executing the %s actions method for other repositories: %v", getMethod(ctx), err)
+	}
 }
 
 func notify(ctx context.Context, input *notifyInput) error {
@@ -142,6 +146,12 @@ func notify(ctx context.Context, input *notifyInput) error {
 		return fmt.Errorf("gitRepo.GetCommit: %w", err)
 	}
 
+	if input.Event == webhook_module.HookEventPush && ref == git.BranchPrefix+input.Repo.DefaultBranch {
+		if err := updateWorkflowSubscriptions(ctx, input.Repo, commit); err != nil {
+			log.Error("updateWorkflowSubscriptions: %v", err)
+		}
+	}
+
 	workflows, err := actions_module.DetectWorkflows(commit, input.Event, input.Payload)
 	if err != nil {
 		return fmt.Errorf("DetectWorkflows: %w", err)
@@ -152,31 +162,39 @@ func notify(ctx context.Context, input *notifyInput) error {
 		return nil
 	}
 
+	return insertRuns(ctx, input, input.Repo, ref, commit, workflows, input.isForkPullRequest())
+}
+
+func (input *notifyInput) isForkPullRequest() bool {
+	pr := input.PullRequest
+	if pr == nil {
+		return false
+	}
+	switch pr.Flow {
+	case issues_model.PullRequestFlowGithub:
+		return pr.IsFromFork()
+	case issues_model.PullRequestFlowAGit:
+		// There is no fork concept in agit flow, anyone with read permission can push refs/for/<target-branch>/<topic-branch> to the repo.
+		// So we can treat it as a fork pull request because it may be from an untrusted user
+		return true
+	default:
+		// unknown flow, assume it's a fork pull request to be safe
+		return true
+	}
+}
+
+// insertRuns inserts the runs of the workflows of a commit of repo triggered by the event of the input
+func insertRuns(ctx context.Context, input *notifyInput, repo *repo_model.Repository, ref string, commit *git.Commit, workflows map[string][]byte, isForkPullRequest bool) error {
 	p, err := json.Marshal(input.Payload)
 	if err != nil {
 		return fmt.Errorf("json.Marshal: %w", err)
 	}
 
-	isForkPullRequest := false
-	if pr := input.PullRequest; pr != nil {
-		switch pr.Flow {
-		case issues_model.PullRequestFlowGithub:
-			isForkPullRequest = pr.IsFromFork()
-		case issues_model.PullRequestFlowAGit:
-			// There is no fork concept in agit flow, anyone with read permission can push refs/for/<target-branch>/<topic-branch> to the repo.
-			// So we can treat it as a fork pull request because it may be from an untrusted user
-			isForkPullRequest = true
-		default:
-			// unknown flow, assume it's a fork pull request to be safe
-			isForkPullRequest = true
-		}
-	}
-
 	for id, content := range workflows {
 		run := &actions_model.ActionRun{
 			Title:             strings.SplitN(commit.CommitMessage, "\n", 2)[0],
-			RepoID:            input.Repo.ID,
-			OwnerID:           input.Repo.OwnerID,
+			RepoID:            repo.ID,
+			OwnerID:           repo.OwnerID,
 			WorkflowID:        id,
 			TriggerUserID:     input.Doer.ID,
 			Ref:               ref,
@@ -186,8 +204,8 @@ func notify(ctx context.Context, input *notifyInput) error {
 			EventPayload:      string(p),
 			Status:            actions_model.StatusWaiting,
 		}
-		if need, err := ifNeedApproval(ctx, run, input.Repo, input.Doer); err != nil {
-			log.Error("check if need approval for repo %d with user %d: %v", input.Repo.ID, input.Doer.ID, err)
+		if need, err := ifNeedApproval(ctx, run, repo, input.Doer); err != nil {
+			log.Error("check if need approval for repo %d with user %d: %v", repo.ID, input.Doer.ID, err)
 			continue
 		} else {
 			run.NeedApproval = need
@@ -202,6 +220,9 @@ func notify(ctx context.Context, input *notifyInput) error {
 			log.Error("InsertRun: %v", err)
 			continue
 		}
+		if run.NeedApproval {
+			notification.NotifyActionRunNeedApproval(ctx, run)
+		}
 		if jobs, _, err := actions_model.FindRunJobs(ctx, actions_model.FindRunJobOptions{RunID: run.ID}); err != nil {
 			log.Error("FindRunJobs: %v", err)
 		} else {
@@ -278,6 +299,22 @@ func ifNeedApproval(ctx context.Context, run *actions_model.ActionRun, repo *rep
 		return false, nil
 	}
 
+	switch repo.MustGetUnit(ctx, unit_model.TypeActions).ActionsConfig().GetApprovalPolicy() {
+	case repo_model.ActionsApprovalAllForks:
+		log.Trace("need approval because all runs of pull requests from forks need approval")
+		return true, nil
+	case repo_model.ActionsApprovalFirstContribution:
+		// don't need approval if a pull request of the user has been merged before
+		if merged, err := issues_model.HasMergedPullRequestByPoster(ctx, repo.ID, user.ID); err != nil {
+			return false, fmt.Errorf("HasMergedPullRequestByPoster: %w", err)
+		} else if merged {
+			log.Trace("do not need approval because a pull request of user %d has been merged before", user.ID)
+			return false, nil
+		}
+		log.Trace("need approval because user %d has never contributed", user.ID)
+		return true, nil
+	}
+
 	// don't need approval if the user has been approved before
 	if count, err := actions_model.CountRuns(ctx, actions_model.FindRunOptions{
 		RepoID:        repo.ID,
@@ -0,0 +1,43 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"encoding/base64"
+	"net/url"
+
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+type registryAuth struct {
+	Auth string `json:"auth"`
+}
+
+type registryAuthConfig struct {
+	Auths map[string]registryAuth `json:"auths"`
+}
+
+// RegistryAuthConfig returns a docker configuration which logs into the container registry with the token of a task.
+// docker login exchanges it for a registry token bound to the task, so it expires with the task.
+func RegistryAuthConfig(token string) (string, error) {
+	appURL, err := url.Parse(setting.AppURL)
+	if err != nil {
+		return "", err
+	}
+
+	config := registryAuthConfig{
+		Auths: map[string]registryAuth{
+			appURL.Host: {
+				Auth: base64.StdEncoding.EncodeToString([]byte(user_model.ActionsUserName + ":" + token)),
+			},
+		},
+	}
+	data, err := json.Marshal(config)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
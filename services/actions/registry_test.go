@@ -0,0 +1,25 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegistryAuthConfig(t *testing.T) {
+	prevURL := setting.AppURL
+	setting.AppURL = "https://gitea.example.com:3000/sub/"
+	defer func() {
+		setting.AppURL = prevURL
+	}()
+
+	config, err := RegistryAuthConfig("0123456789abcdef0123456789abcdef01234567")
+	assert.NoError(t, err)
+	// base64 of gitea-actions:0123456789abcdef0123456789abcdef01234567
+	assert.JSONEq(t, `{"auths":{"gitea.example.com:3000":{"auth":"Z2l0ZWEtYWN0aW9uczowMTIzNDU2Nzg5YWJjZGVmMDEyMzQ1Njc4OWFiY2RlZjAxMjM0NTY3"}}}`, config)
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// RerunJob resets a finished job so that it is picked up by a runner again
+func RerunJob(ctx context.Context, job *actions_model.ActionRunJob, doer *user_model.User) error {
+	return rerunJob(ctx, job, git_model.CommitStatusAttemptReasonManual, doer.ID)
+}
+
+func rerunJob(ctx context.Context, job *actions_model.ActionRunJob, reason string, doerID int64) error {
+	status := job.Status
+	if !status.IsDone() {
+		return nil
+	}
+
+	job.TaskID = 0
+	job.Status = actions_model.StatusWaiting
+	job.Started = 0
+	job.Stopped = 0
+
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		_, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": status}, "task_id", "status", "started", "stopped")
+		return err
+	}); err != nil {
+		return err
+	}
+
+	startCommitStatusAttempts(ctx, reason, doerID, job)
+	CreateCommitStatus(ctx, job)
+	return nil
+}
+
+// ErrRunNotDone is returned when rerunning the failed jobs of a run which is still in progress
+var ErrRunNotDone = util.NewInvalidArgumentErrorf("the run is not done")
+
+// RerunFailedJobs reruns the failed and cancelled jobs of a finished run, together with the jobs which
+// depend on them. The succeeded jobs keep their results and artifacts, and a rerun job which needs
+// another rerun job stays blocked until that one is done.
+func RerunFailedJobs(ctx context.Context, jobs []*actions_model.ActionRunJob, doer *user_model.User) error {
+	for _, job := range jobs {
+		if !job.Status.IsDone() {
+			return ErrRunNotDone
+		}
+	}
+
+	statuses := getFailedJobsRerunStatuses(jobs)
+	if len(statuses) == 0 {
+		return nil
+	}
+
+	var rerunJobs []*actions_model.ActionRunJob
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		for _, job := range jobs {
+			status, ok := statuses[job.ID]
+			if !ok {
+				continue
+			}
+			oldStatus := job.Status
+			job.TaskID = 0
+			job.Status = status
+			job.Started = 0
+			job.Stopped = 0
+			n, err := actions_model.UpdateRunJob(ctx, job, builder.Eq{"status": oldStatus}, "task_id", "status", "started", "stopped")
+			if err != nil {
+				return err
+			}
+			if n == 0 {
+				return fmt.Errorf("job %d has changed, try again", job.ID)
+			}
+			rerunJobs = append(rerunJobs, job)
+		}
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	startCommitStatusAttempts(ctx, git_model.CommitStatusAttemptReasonManual, doer.ID, rerunJobs...)
+	CreateCommitStatus(ctx, rerunJobs...)
+	return nil
+}
+
+// getFailedJobsRerunStatuses returns the statuses the jobs to rerun should be reset to: the failed and cancelled
+// jobs and the jobs depending on them, directly or not. A rerun job waits unless it needs another rerun job.
+func getFailedJobsRerunStatuses(jobs []*actions_model.ActionRunJob) map[int64]actions_model.Status {
+	// the succeeded jobs of a matrix are kept, so the rerun jobs are tracked by id and their dependents by job id
+	rerun := make(map[int64]bool, len(jobs))
+	rerunJobIDs := make(map[string]bool, len(jobs))
+	for _, job := range jobs {
+		if job.Status.In(actions_model.StatusFailure, actions_model.StatusCancelled) {
+			rerun[job.ID] = true
+			rerunJobIDs[job.JobID] = true
+		}
+	}
+	if len(rerun) == 0 {
+		return nil
+	}
+
+	needsRerunJob := func(job *actions_model.ActionRunJob) bool {
+		for _, need := range job.Needs {
+			if rerunJobIDs[need] {
+				return true
+			}
+		}
+		return false
+	}
+
+	// add the dependents until there is none left, a chain of needs can't be longer than the jobs
+	for i := 0; i < len(jobs); i++ {
+		added := false
+		for _, job := range jobs {
+			if !rerun[job.ID] && needsRerunJob(job) {
+				rerun[job.ID] = true
+				rerunJobIDs[job.JobID] = true
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+
+	ret := make(map[int64]actions_model.Status, len(rerun))
+	for _, job := range jobs {
+		if !rerun[job.ID] {
+			continue
+		}
+		if needsRerunJob(job) {
+			ret[job.ID] = actions_model.StatusBlocked
+		} else {
+			ret[job.ID] = actions_model.StatusWaiting
+		}
+	}
+	return ret
+}
@@ -0,0 +1,86 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"testing"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_getFailedJobsRerunStatuses(t *testing.T) {
+	tests := []struct {
+		name string
+		jobs actions_model.ActionJobList
+		want map[int64]actions_model.Status
+	}{
+		{
+			name: "all succeeded",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "1", Status: actions_model.StatusSuccess, Needs: []string{}},
+				{ID: 2, JobID: "2", Status: actions_model.StatusSuccess, Needs: []string{"1"}},
+			},
+			want: nil,
+		},
+		{
+			name: "independent failure",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "1", Status: actions_model.StatusSuccess, Needs: []string{}},
+				{ID: 2, JobID: "2", Status: actions_model.StatusFailure, Needs: []string{}},
+				{ID: 3, JobID: "3", Status: actions_model.StatusCancelled, Needs: []string{}},
+			},
+			want: map[int64]actions_model.Status{
+				2: actions_model.StatusWaiting,
+				3: actions_model.StatusWaiting,
+			},
+		},
+		{
+			name: "chain of dependents",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "1", Status: actions_model.StatusSuccess, Needs: []string{}},
+				{ID: 2, JobID: "2", Status: actions_model.StatusFailure, Needs: []string{"1"}},
+				{ID: 3, JobID: "3", Status: actions_model.StatusSkipped, Needs: []string{"2"}},
+				{ID: 4, JobID: "4", Status: actions_model.StatusSkipped, Needs: []string{"3"}},
+				{ID: 5, JobID: "5", Status: actions_model.StatusSkipped, Needs: []string{}},
+			},
+			want: map[int64]actions_model.Status{
+				2: actions_model.StatusWaiting,
+				3: actions_model.StatusBlocked,
+				4: actions_model.StatusBlocked,
+			},
+		},
+		{
+			name: "dependents listed first",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "3", Status: actions_model.StatusSkipped, Needs: []string{"2"}},
+				{ID: 2, JobID: "2", Status: actions_model.StatusSkipped, Needs: []string{"1"}},
+				{ID: 3, JobID: "1", Status: actions_model.StatusFailure, Needs: []string{}},
+			},
+			want: map[int64]actions_model.Status{
+				1: actions_model.StatusBlocked,
+				2: actions_model.StatusBlocked,
+				3: actions_model.StatusWaiting,
+			},
+		},
+		{
+			name: "matrix job",
+			jobs: actions_model.ActionJobList{
+				{ID: 1, JobID: "1", Status: actions_model.StatusSuccess, Needs: []string{}},
+				{ID: 2, JobID: "1", Status: actions_model.StatusFailure, Needs: []string{}},
+				{ID: 3, JobID: "2", Status: actions_model.StatusSkipped, Needs: []string{"1"}},
+			},
+			want: map[int64]actions_model.Status{
+				2: actions_model.StatusWaiting,
+				3: actions_model.StatusBlocked,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, getFailedJobsRerunStatuses(tt.jobs))
+		})
+	}
+}
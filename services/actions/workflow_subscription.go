@@ -0,0 +1,138 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package actions
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	actions_module "code.gitea.io/gitea/modules/actions"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// updateWorkflowSubscriptions replaces the subscriptions of a repository by the events of the workflows of
+// the commit of its default branch which can be triggered by other repositories
+func updateWorkflowSubscriptions(ctx context.Context, repo *repo_model.Repository, commit *git.Commit) error {
+	entries, err := actions_module.ListWorkflows(commit)
+	if err != nil {
+		return err
+	}
+
+	var subs []*actions_model.ActionWorkflowSubscription
+	for _, entry := range entries {
+		content, err := actions_module.GetContentFromEntry(entry)
+		if err != nil {
+			return err
+		}
+		filters, err := actions_module.GetRepositoriesFilters(content)
+		if err != nil {
+			log.Warn("ignore invalid workflow %q: %v", entry.Name(), err)
+			continue
+		}
+		for event, repos := range filters {
+			subs = append(subs, &actions_model.ActionWorkflowSubscription{
+				OwnerID:      repo.OwnerID,
+				RepoID:       repo.ID,
+				WorkflowID:   entry.Name(),
+				Event:        event,
+				Repositories: repos,
+			})
+		}
+	}
+	return actions_model.ReplaceWorkflowSubscriptions(ctx, repo.ID, subs)
+}
+
+// notifyCrossRepo triggers the workflows of the other repositories of the organization of the repository of the event
+// which subscribed to it
+func notifyCrossRepo(ctx context.Context, input *notifyInput) error {
+	if input.Doer.IsActions() || unit_model.TypeActions.UnitGlobalDisabled() {
+		return nil
+	}
+	if input.isForkPullRequest() {
+		// the workflows of other repositories could use their secrets, they must not run for untrusted code
+		return nil
+	}
+	if err := input.Repo.LoadOwner(ctx); err != nil {
+		return fmt.Errorf("repo.LoadOwner: %w", err)
+	}
+	if !input.Repo.Owner.IsOrganization() {
+		return nil
+	}
+
+	subs, err := actions_model.FindWorkflowSubscriptions(ctx, input.Repo.OwnerID, input.Repo.ID)
+	if err != nil {
+		return fmt.Errorf("FindWorkflowSubscriptions: %w", err)
+	}
+	repoIDs := make(map[int64]bool, len(subs))
+	for _, sub := range subs {
+		if actions_module.CanEventTrigger(sub.Event, input.Event) && actions_module.MatchRepositories(sub.Repositories, input.Repo.Name) {
+			repoIDs[sub.RepoID] = true
+		}
+	}
+	ids := make([]int64, 0, len(repoIDs))
+	for id := range repoIDs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		if err := notifySubscribedRepo(ctx, input, id); err != nil {
+			log.Error("notify subscribed repo %d of event %v in repo %d: %v", id, input.Event, input.Repo.ID, err)
+		}
+	}
+	return nil
+}
+
+func notifySubscribedRepo(ctx context.Context, input *notifyInput, repoID int64) error {
+	repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+	if err != nil {
+		return fmt.Errorf("GetRepositoryByID: %w", err)
+	}
+	if repo.OwnerID != input.Repo.OwnerID || repo.IsArchived {
+		return nil
+	}
+	if input.Repo.IsPrivate && !repo.IsPrivate {
+		// the payload of the event would be visible in the runs of a public repository
+		log.Trace("ignore event %v of private repo %d for public repo %d", input.Event, input.Repo.ID, repo.ID)
+		return nil
+	}
+	if err := repo.LoadUnits(ctx); err != nil {
+		return fmt.Errorf("repo.LoadUnits: %w", err)
+	} else if !repo.UnitEnabled(ctx, unit_model.TypeActions) {
+		return nil
+	}
+	if perm, err := access_model.GetUserRepoPermission(ctx, repo, input.Doer); err != nil {
+		return fmt.Errorf("GetUserRepoPermission: %w", err)
+	} else if !perm.CanRead(unit_model.TypeActions) {
+		log.Trace("ignore event %v of repo %d for repo %d because user %d can't read its actions", input.Event, input.Repo.ID, repo.ID, input.Doer.ID)
+		return nil
+	}
+
+	gitRepo, err := git.OpenRepository(ctx, repo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("git.OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return fmt.Errorf("gitRepo.GetBranchCommit: %w", err)
+	}
+
+	workflows, err := actions_module.DetectCrossRepoWorkflows(commit, input.Repo.Name, input.Event, input.Payload)
+	if err != nil {
+		return fmt.Errorf("DetectCrossRepoWorkflows: %w", err)
+	}
+	if len(workflows) == 0 {
+		return nil
+	}
+
+	return insertRuns(ctx, input, repo, git.BranchPrefix+repo.DefaultBranch, commit, workflows, false)
+}
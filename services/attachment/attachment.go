@@ -6,11 +6,16 @@ package attachment
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
 	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/storage"
 	"code.gitea.io/gitea/modules/upload"
 	"code.gitea.io/gitea/modules/util"
@@ -20,17 +25,55 @@ import (
 
 // NewAttachment creates a new attachment object, but do not verify.
 func NewAttachment(attach *repo_model.Attachment, file io.Reader, size int64) (*repo_model.Attachment, error) {
+	return newAttachment(attach, file, size, 0, nil)
+}
+
+// newAttachment stores the file and the thumbnail of a new attachment. A file larger than maxSize
+// is rejected if maxSize is set. If deduplication is enabled, the file is stored in the blob with its
+// content, which is shared by all attachments with the same content in the storage region.
+func newAttachment(attach *repo_model.Attachment, file io.Reader, size, maxSize int64, thumbnail []byte) (*repo_model.Attachment, error) {
 	if attach.RepoID == 0 {
 		return nil, fmt.Errorf("attachment %s should belong to a repository", attach.Name)
 	}
 
 	err := db.WithTx(db.DefaultContext, func(ctx context.Context) error {
 		attach.UUID = uuid.New().String()
-		size, err := storage.Attachments.Save(attach.RelativePath(), file, size)
+		region, err := attach.StorageRegion(ctx)
+		if err != nil {
+			return err
+		}
+		attachmentStorage := storage.AttachmentsOfRegion(region)
+		hash := sha256.New()
+		size, err := attachmentStorage.Save(attach.RelativePath(), io.TeeReader(file, hash), size)
 		if err != nil {
 			return fmt.Errorf("Create: %w", err)
 		}
 		attach.Size = size
+		attach.ContentHash = hex.EncodeToString(hash.Sum(nil))
+
+		if maxSize > 0 && size > maxSize {
+			if err := attachmentStorage.Delete(attach.RelativePath()); err != nil {
+				log.Error("Unable to delete the rejected attachment %s: %v", attach.UUID, err)
+			}
+			return upload.ErrFileTooLarge{MaxSize: maxSize}
+		}
+
+		if setting.Attachment.Deduplicate {
+			blob, err := storeAttachmentBlob(ctx, region, attachmentStorage, attach.RelativePath(), attach.ContentHash, attach.Size, thumbnail)
+			if err != nil {
+				return err
+			}
+			attach.BlobID = blob.ID
+			attach.HasThumbnail = blob.HasThumbnail
+			return db.Insert(ctx, attach)
+		}
+
+		if len(thumbnail) > 0 {
+			if _, err := attachmentStorage.Save(attach.ThumbnailRelativePath(), bytes.NewReader(thumbnail), int64(len(thumbnail))); err != nil {
+				return fmt.Errorf("Create thumbnail: %w", err)
+			}
+			attach.HasThumbnail = true
+		}
 
 		return db.Insert(ctx, attach)
 	})
@@ -38,6 +81,33 @@ func NewAttachment(attach *repo_model.Attachment, file io.Reader, size int64) (*
 	return attach, err
 }
 
+// getAttachmentPolicy returns the attachment policy of the organization owning the repository, or nil if there is none
+func getAttachmentPolicy(ctx context.Context, repoID int64) (*organization.AttachmentPolicy, error) {
+	repo, err := repo_model.GetRepositoryByID(ctx, repoID)
+	if err != nil {
+		return nil, err
+	}
+	return organization.GetAttachmentPolicy(ctx, repo.OwnerID)
+}
+
+// verifyPolicy checks the type of the file against the rules of the policy and returns the size limit for it
+func verifyPolicy(policy *organization.AttachmentPolicy, mimeType, fileName string, fileSize int64) (int64, error) {
+	if policy == nil || len(policy.Rules) == 0 {
+		return 0, nil
+	}
+	for _, rule := range policy.Rules {
+		if !upload.MatchType(mimeType, fileName, rule.Type) {
+			continue
+		}
+		if rule.MaxSize > 0 && fileSize > rule.MaxSize {
+			return 0, upload.ErrFileTooLarge{Type: mimeType, MaxSize: rule.MaxSize}
+		}
+		return rule.MaxSize, nil
+	}
+	log.Info("Attachment with type %s blocked from upload by the organization policy", mimeType)
+	return 0, upload.ErrFileTypeForbidden{Type: mimeType}
+}
+
 // UploadAttachment upload new attachment into storage and update database
 func UploadAttachment(file io.Reader, allowedTypes string, fileSize int64, opts *repo_model.Attachment) (*repo_model.Attachment, error) {
 	buf := make([]byte, 1024)
@@ -48,5 +118,40 @@ func UploadAttachment(file io.Reader, allowedTypes string, fileSize int64, opts
 		return nil, err
 	}
 
-	return NewAttachment(opts, io.MultiReader(bytes.NewReader(buf), file), fileSize)
+	mimeType, _, err := upload.DetectType(buf)
+	if err != nil {
+		return nil, err
+	}
+	policy, err := getAttachmentPolicy(db.DefaultContext, opts.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	maxSize, err := verifyPolicy(policy, mimeType, opts.Name, fileSize)
+	if err != nil {
+		return nil, err
+	}
+
+	file = io.MultiReader(bytes.NewReader(buf), file)
+
+	var thumbnail []byte
+	optimize := policy != nil && policy.OptimizeImages
+	if isProcessableImage(mimeType) && (optimize || setting.Attachment.ThumbnailSize > 0) {
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return nil, err
+		}
+		if maxSize > 0 && int64(len(data)) > maxSize {
+			return nil, upload.ErrFileTooLarge{Type: mimeType, MaxSize: maxSize}
+		}
+		processed, err := processImage(data, optimize, policy)
+		if err != nil {
+			// the file is kept as it is if it can not be processed
+			log.Debug("Unable to process the image %s: %v", opts.Name, err)
+		} else {
+			data, thumbnail = processed.data, processed.thumbnail
+		}
+		file, fileSize = bytes.NewReader(data), int64(len(data))
+	}
+
+	return newAttachment(opts, file, fileSize, maxSize, thumbnail)
 }
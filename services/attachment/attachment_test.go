@@ -4,14 +4,19 @@
 package attachment
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/upload"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -44,3 +49,79 @@ func TestUploadAttachment(t *testing.T) {
 	assert.EqualValues(t, user.ID, attachment.UploaderID)
 	assert.Equal(t, int64(0), attachment.DownloadCount)
 }
+
+func TestNewAttachmentDeduplicate(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	content := []byte("identical attachment content")
+	first, err := NewAttachment(&repo_model.Attachment{RepoID: 1, UploaderID: 1, Name: "a.txt"}, bytes.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+	assert.Empty(t, first.ObjectUUID)
+	assert.Len(t, first.ContentHash, 64)
+	assert.NotZero(t, first.BlobID)
+
+	second, err := NewAttachment(&repo_model.Attachment{RepoID: 1, UploaderID: 1, Name: "b.txt"}, bytes.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+	assert.Equal(t, first.BlobID, second.BlobID)
+	assert.Equal(t, first.RelativePath(), second.RelativePath())
+	assert.Equal(t, repo_model.AttachmentBlobRelativePath(first.ContentHash), first.RelativePath())
+
+	blob := unittest.AssertExistsAndLoadBean(t, &repo_model.AttachmentBlob{ID: first.BlobID})
+	assert.EqualValues(t, 2, blob.RefCount)
+	assert.EqualValues(t, len(content), blob.Size)
+
+	// the files of blobs are never removed with their attachments, they are garbage collected
+	removable, err := repo_model.RemovableAttachments(db.DefaultContext, []*repo_model.Attachment{first, second})
+	assert.NoError(t, err)
+	assert.Empty(t, removable)
+}
+
+func TestGarbageCollectBlobs(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	content := []byte("attachment content to garbage collect")
+	attach, err := NewAttachment(&repo_model.Attachment{RepoID: 1, UploaderID: 1, Name: "a.txt"}, bytes.NewReader(content), int64(len(content)))
+	assert.NoError(t, err)
+
+	// referenced blobs are kept
+	assert.NoError(t, GarbageCollectBlobs(db.DefaultContext, false, 0))
+	unittest.AssertExistsAndLoadBean(t, &repo_model.AttachmentBlob{ID: attach.BlobID})
+
+	_, err = repo_model.DeleteAttachments(db.DefaultContext, []*repo_model.Attachment{attach}, true)
+	assert.NoError(t, err)
+	blob := unittest.AssertExistsAndLoadBean(t, &repo_model.AttachmentBlob{ID: attach.BlobID})
+	assert.EqualValues(t, 0, blob.RefCount)
+
+	// unreferenced blobs are kept for the grace period
+	assert.NoError(t, GarbageCollectBlobs(db.DefaultContext, false, time.Hour))
+	unittest.AssertExistsAndLoadBean(t, &repo_model.AttachmentBlob{ID: attach.BlobID})
+
+	assert.NoError(t, GarbageCollectBlobs(db.DefaultContext, false, -time.Hour))
+	unittest.AssertNotExistsBean(t, &repo_model.AttachmentBlob{ID: attach.BlobID})
+	_, err = storage.Attachments.Stat(attach.RelativePath())
+	assert.Error(t, err)
+}
+
+func TestVerifyPolicy(t *testing.T) {
+	policy := &organization.AttachmentPolicy{Rules: []*organization.AttachmentTypeRule{
+		{Type: "image/*", MaxSize: 100},
+		{Type: ".pdf"},
+	}}
+
+	maxSize, err := verifyPolicy(policy, "image/png", "a.png", 50)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 100, maxSize)
+
+	_, err = verifyPolicy(policy, "image/png", "a.png", 200)
+	assert.True(t, upload.IsErrFileTooLarge(err))
+
+	maxSize, err = verifyPolicy(policy, "application/pdf", "a.pdf", 1000)
+	assert.NoError(t, err)
+	assert.EqualValues(t, 0, maxSize)
+
+	_, err = verifyPolicy(policy, "application/zip", "a.zip", 10)
+	assert.True(t, upload.IsErrFileTypeForbidden(err))
+
+	_, err = verifyPolicy(nil, "application/zip", "a.zip", 10)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,265 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package attachment
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// legacyAttachmentsBatchSize is the number of attachments moved to blobs at once by the garbage collection
+const legacyAttachmentsBatchSize = 50
+
+// storeAttachmentBlob moves a file stored at a temporary path into the blob with its content and
+// returns the blob with its reference count increased. The file is dropped if the blob exists already.
+func storeAttachmentBlob(ctx context.Context, region string, attachmentStorage storage.ObjectStorage, stagedPath, hash string, size int64, thumbnail []byte) (*repo_model.AttachmentBlob, error) {
+	blob, err := repo_model.GetAttachmentBlob(ctx, region, hash)
+	if err == nil {
+		if err := attachmentStorage.Delete(stagedPath); err != nil {
+			return nil, fmt.Errorf("Delete duplicate: %w", err)
+		}
+		if !blob.HasThumbnail && len(thumbnail) > 0 {
+			if _, err := attachmentStorage.Save(repo_model.AttachmentBlobThumbnailRelativePath(hash), bytes.NewReader(thumbnail), int64(len(thumbnail))); err != nil {
+				return nil, fmt.Errorf("Create thumbnail: %w", err)
+			}
+			if err := repo_model.SetAttachmentBlobThumbnail(ctx, blob); err != nil {
+				return nil, err
+			}
+		}
+	} else if repo_model.IsErrAttachmentBlobNotExist(err) {
+		blob = &repo_model.AttachmentBlob{
+			Region:     region,
+			HashSHA256: hash,
+			Size:       size,
+		}
+		if _, err := storage.Copy(attachmentStorage, blob.RelativePath(), attachmentStorage, stagedPath); err != nil {
+			return nil, fmt.Errorf("Copy to blob: %w", err)
+		}
+		if err := attachmentStorage.Delete(stagedPath); err != nil {
+			log.Error("Unable to delete the stored file %s after moving it to its blob: %v", stagedPath, err)
+		}
+		if len(thumbnail) > 0 {
+			if _, err := attachmentStorage.Save(repo_model.AttachmentBlobThumbnailRelativePath(hash), bytes.NewReader(thumbnail), int64(len(thumbnail))); err != nil {
+				return nil, fmt.Errorf("Create thumbnail: %w", err)
+			}
+			blob.HasThumbnail = true
+		}
+		if err := repo_model.InsertAttachmentBlob(ctx, blob); err != nil {
+			return nil, err
+		}
+	} else {
+		return nil, err
+	}
+
+	if err := repo_model.IncreaseAttachmentBlobRefCount(ctx, blob.ID, 1); err != nil {
+		return nil, err
+	}
+	return blob, nil
+}
+
+// copyToAttachmentBlob returns the blob with the content of a stored file, copying the file
+// into the blob if it does not exist yet. The stored file is kept.
+func copyToAttachmentBlob(ctx context.Context, region string, dst, src storage.ObjectStorage, hash string, size int64, filePath, thumbnailPath string) (*repo_model.AttachmentBlob, error) {
+	blob, err := repo_model.GetAttachmentBlob(ctx, region, hash)
+	if err == nil {
+		return blob, nil
+	} else if !repo_model.IsErrAttachmentBlobNotExist(err) {
+		return nil, err
+	}
+
+	blob = &repo_model.AttachmentBlob{
+		Region:     region,
+		HashSHA256: hash,
+		Size:       size,
+	}
+	if err := copyIfNotExist(dst, blob.RelativePath(), src, filePath); err != nil {
+		return nil, err
+	}
+	if thumbnailPath != "" {
+		if err := copyIfNotExist(dst, repo_model.AttachmentBlobThumbnailRelativePath(hash), src, thumbnailPath); err != nil {
+			if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, util.ErrNotExist) {
+				return nil, err
+			}
+		} else {
+			blob.HasThumbnail = true
+		}
+	}
+	return blob, repo_model.InsertAttachmentBlob(ctx, blob)
+}
+
+// copyIfNotExist copies a file between storages unless it has been copied already
+func copyIfNotExist(dst storage.ObjectStorage, dstPath string, src storage.ObjectStorage, srcPath string) error {
+	if _, err := dst.Stat(dstPath); err == nil {
+		return nil
+	}
+	_, err := storage.Copy(dst, dstPath, src, srcPath)
+	return err
+}
+
+// MoveAttachmentBlobsToRegion makes the attachments of the given repositories reference blobs
+// in another storage region, the blobs of the old region are left to the garbage collection
+func MoveAttachmentBlobsToRegion(ctx context.Context, repoIDs []int64, from, to string) error {
+	if len(repoIDs) == 0 || from == to {
+		return nil
+	}
+	blobs, err := repo_model.GetAttachmentBlobsByRepoIDs(ctx, repoIDs)
+	if err != nil {
+		return err
+	}
+	src, dst := storage.AttachmentsOfRegion(from), storage.AttachmentsOfRegion(to)
+	for _, blob := range blobs {
+		if blob.Region == to {
+			continue
+		}
+		thumbnailPath := ""
+		if blob.HasThumbnail {
+			thumbnailPath = repo_model.AttachmentBlobThumbnailRelativePath(blob.HashSHA256)
+		}
+		newBlob, err := copyToAttachmentBlob(ctx, to, dst, src, blob.HashSHA256, blob.Size, blob.RelativePath(), thumbnailPath)
+		if err != nil {
+			return fmt.Errorf("copy attachment blob %s: %w", blob.HashSHA256, err)
+		}
+		if err := repo_model.ReplaceAttachmentBlob(ctx, repoIDs, blob, newBlob); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// moveLegacyAttachment moves an attachment stored under its uuid into the blob with its content.
+// The old file is removed once no other attachment stored under a uuid shares it.
+func moveLegacyAttachment(ctx context.Context, attach *repo_model.Attachment) error {
+	region, err := attach.StorageRegion(ctx)
+	if err != nil {
+		return err
+	}
+	attachmentStorage := storage.AttachmentsOfRegion(region)
+	objectID, filePath, oldPaths := attach.ObjectID(), attach.RelativePath(), attach.ObjectPaths()
+
+	if attach.ContentHash == "" {
+		f, err := attachmentStorage.Open(filePath)
+		if err != nil {
+			return err
+		}
+		hash := sha256.New()
+		_, err = io.Copy(hash, f)
+		f.Close()
+		if err != nil {
+			return err
+		}
+		attach.ContentHash = hex.EncodeToString(hash.Sum(nil))
+	}
+
+	thumbnailPath := ""
+	if attach.HasThumbnail {
+		thumbnailPath = attach.ThumbnailRelativePath()
+	}
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		blob, err := copyToAttachmentBlob(ctx, region, attachmentStorage, attachmentStorage, attach.ContentHash, attach.Size, filePath, thumbnailPath)
+		if err != nil {
+			return err
+		}
+		return repo_model.SetAttachmentBlob(ctx, attach, blob)
+	}); err != nil {
+		return err
+	}
+
+	shared, err := repo_model.ExistLegacyAttachmentsWithObjectID(ctx, objectID)
+	if err != nil || shared {
+		return err
+	}
+	for _, p := range oldPaths {
+		if err := attachmentStorage.Delete(p); err != nil {
+			log.Error("Unable to delete the file %s of attachment %s after moving it to its blob: %v", p, attach.UUID, err)
+		}
+	}
+	return nil
+}
+
+// moveLegacyAttachments moves all attachments stored under their uuid into blobs
+func moveLegacyAttachments(ctx context.Context) error {
+	var lastID int64
+	for {
+		attachments, err := repo_model.FindLegacyAttachments(ctx, lastID, legacyAttachmentsBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(attachments) == 0 {
+			return nil
+		}
+		for _, attach := range attachments {
+			select {
+			case <-ctx.Done():
+				return db.ErrCancelledf("before moving attachment %s to its blob", attach.UUID)
+			default:
+			}
+			lastID = attach.ID
+			// an attachment which can not be moved is kept as it is, it does not stop the others
+			if err := moveLegacyAttachment(ctx, attach); err != nil {
+				log.Warn("Unable to move attachment %s to its blob: %v", attach.UUID, err)
+			}
+		}
+	}
+}
+
+// GarbageCollectBlobs moves the attachments stored under their uuid into blobs when deduplication
+// is enabled, and deletes the blobs which no attachment has referenced for the given duration
+func GarbageCollectBlobs(ctx context.Context, moveLegacy bool, olderThan time.Duration) error {
+	if moveLegacy {
+		if err := moveLegacyAttachments(ctx); err != nil {
+			return err
+		}
+	}
+
+	if err := repo_model.RecountAttachmentBlobRefs(ctx); err != nil {
+		return err
+	}
+	blobs, err := repo_model.FindUnreferencedAttachmentBlobs(ctx, timeutil.TimeStamp(time.Now().Add(-olderThan).Unix()))
+	if err != nil {
+		return err
+	}
+
+	var deletedCount int
+	var deletedSize int64
+	for _, blob := range blobs {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before deleting attachment blob %s", blob.HashSHA256)
+		default:
+		}
+		deleted, err := repo_model.DeleteAttachmentBlob(ctx, blob)
+		if err != nil {
+			return err
+		}
+		if !deleted {
+			continue
+		}
+		attachmentStorage := storage.AttachmentsOfRegion(blob.Region)
+		for _, p := range blob.ObjectPaths() {
+			if err := attachmentStorage.Delete(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Error("Unable to delete the file %s of attachment blob %d: %v", p, blob.ID, err)
+			}
+		}
+		deletedCount++
+		deletedSize += blob.Size
+	}
+	if deletedCount > 0 {
+		log.Info("Deleted %d unreferenced attachment blob(s) of %d bytes", deletedCount, deletedSize)
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package attachment
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/nfnt/resize"
+)
+
+// maxProcessedPixels limits the size of the images which are decoded, larger images are stored as they are
+const maxProcessedPixels = 40_000_000
+
+// jpegQuality is the quality of recompressed jpeg images and thumbnails
+const jpegQuality = 85
+
+type processedImage struct {
+	data      []byte
+	thumbnail []byte
+}
+
+func isProcessableImage(mimeType string) bool {
+	return mimeType == "image/jpeg" || mimeType == "image/png"
+}
+
+func encodeImage(img image.Image, imgType string) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	if imgType == "jpeg" {
+		err = jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	} else {
+		err = (&png.Encoder{CompressionLevel: png.BestCompression}).Encode(&buf, img)
+	}
+	return buf.Bytes(), err
+}
+
+// fitImage downscales the image to fit within size x size, it returns nil if the image already fits
+func fitImage(img image.Image, size int) image.Image {
+	bounds := img.Bounds()
+	if bounds.Dx() <= size && bounds.Dy() <= size {
+		return nil
+	}
+	if bounds.Dx() >= bounds.Dy() {
+		return resize.Resize(uint(size), 0, img, resize.Bilinear)
+	}
+	return resize.Resize(0, uint(size), img, resize.Bilinear)
+}
+
+// processImage recompresses and downscales an image if the policy asks for it and generates its thumbnail.
+// The original data is kept if recompression does not make it smaller.
+func processImage(data []byte, optimize bool, policy *organization.AttachmentPolicy) (*processedImage, error) {
+	cfg, imgType, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("image.DecodeConfig: %w", err)
+	}
+	if imgType != "jpeg" && imgType != "png" {
+		return nil, fmt.Errorf("unsupported image type %s", imgType)
+	}
+	if cfg.Width*cfg.Height > maxProcessedPixels {
+		return nil, fmt.Errorf("image is too large: %dx%d", cfg.Width, cfg.Height)
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("image.Decode: %w", err)
+	}
+
+	result := &processedImage{data: data}
+	if optimize {
+		resized := false
+		if policy.MaxImageDimension > 0 {
+			if fitted := fitImage(img, policy.MaxImageDimension); fitted != nil {
+				img, resized = fitted, true
+			}
+		}
+		optimized, err := encodeImage(img, imgType)
+		if err != nil {
+			return nil, err
+		}
+		if resized || len(optimized) < len(data) {
+			result.data = optimized
+		}
+	}
+
+	if setting.Attachment.ThumbnailSize > 0 {
+		// small images are their own thumbnail
+		if thumb := fitImage(img, setting.Attachment.ThumbnailSize); thumb != nil {
+			if result.thumbnail, err = encodeImage(thumb, imgType); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+// MigrateUsersMatch is how the accounts of the new auth source are matched with the existing users
+type MigrateUsersMatch string
+
+const (
+	MigrateUsersMatchEmail    MigrateUsersMatch = "email"
+	MigrateUsersMatchUsername MigrateUsersMatch = "username"
+)
+
+// MigrateUsersAccount is an account of the new auth source
+type MigrateUsersAccount struct {
+	// Match is the email or the username of the account, depending on how the accounts are matched
+	Match string
+	// LoginName is the name of the account in the new auth source, Match is used if it is empty
+	LoginName string
+}
+
+// MigrateUsersOptions are the options to migrate users from an auth source to another one
+type MigrateUsersOptions struct {
+	// FromSourceID is the auth source of the migrated users, 0 for the local users
+	FromSourceID int64
+	ToSourceID   int64
+	MatchBy      MigrateUsersMatch
+	// Accounts are the accounts of the new auth source. If there are none, all the users of the old
+	// auth source are migrated and their email or username is used as login name.
+	Accounts []*MigrateUsersAccount
+	DryRun   bool
+}
+
+// MigratedUser is a user moved to the new auth source
+type MigratedUser struct {
+	Name      string
+	LoginName string
+}
+
+// MigrateUsersReport lists the users moved to the new auth source and the accounts which could not be matched
+type MigrateUsersReport struct {
+	Migrated []*MigratedUser
+	// UnmatchedAccounts are the accounts which match no user of the old auth source
+	UnmatchedAccounts []string
+	// UnmatchedUsers are the users of the old auth source which no account matches, they are left as they are
+	UnmatchedUsers []string
+	// Conflicts are the users whose login name is already used in the new auth source or matched by several accounts
+	Conflicts []string
+}
+
+// findMigrateUser returns the user matching the account of the new auth source
+func findMigrateUser(ctx context.Context, matchBy MigrateUsersMatch, match string) (*user_model.User, error) {
+	if matchBy == MigrateUsersMatchEmail {
+		return user_model.GetUserByEmail(ctx, match)
+	}
+	return user_model.GetUserByName(ctx, match)
+}
+
+// MigrateUsers moves the users of an auth source to another one: the matched users log in with
+// the new auth source under their new login name and their local password is removed.
+// Nothing is changed for a dry run, the report lists what would be migrated.
+func MigrateUsers(ctx context.Context, opts *MigrateUsersOptions) (*MigrateUsersReport, error) {
+	if opts.MatchBy != MigrateUsersMatchEmail && opts.MatchBy != MigrateUsersMatchUsername {
+		return nil, util.NewInvalidArgumentErrorf("users must be matched by email or username")
+	}
+	if opts.ToSourceID == 0 {
+		return nil, util.NewInvalidArgumentErrorf("users can only be migrated to an external auth source")
+	}
+	if opts.FromSourceID == opts.ToSourceID {
+		return nil, util.NewInvalidArgumentErrorf("users can not be migrated to their own auth source")
+	}
+	if opts.FromSourceID > 0 {
+		if _, err := auth.GetSourceByID(opts.FromSourceID); err != nil {
+			return nil, err
+		}
+	}
+	toSource, err := auth.GetSourceByID(opts.ToSourceID)
+	if err != nil {
+		return nil, err
+	}
+
+	users := make([]*user_model.User, 0, 50)
+	if err := db.GetEngine(ctx).
+		Where(builder.Eq{"login_source": opts.FromSourceID, "type": user_model.UserTypeIndividual}).
+		Asc("id").
+		Find(&users); err != nil {
+		return nil, err
+	}
+
+	report := &MigrateUsersReport{}
+	loginNames := make(map[int64]string, len(users))
+	duplicates := make(map[int64]bool)
+	if len(opts.Accounts) == 0 {
+		for _, u := range users {
+			if opts.MatchBy == MigrateUsersMatchEmail {
+				loginNames[u.ID] = u.Email
+			} else {
+				loginNames[u.ID] = u.Name
+			}
+		}
+	}
+	for _, account := range opts.Accounts {
+		u, err := findMigrateUser(ctx, opts.MatchBy, account.Match)
+		if err != nil && !user_model.IsErrUserNotExist(err) {
+			return nil, err
+		}
+		if err != nil || u.LoginSource != opts.FromSourceID || !u.IsIndividual() {
+			report.UnmatchedAccounts = append(report.UnmatchedAccounts, account.Match)
+			continue
+		}
+		if _, ok := loginNames[u.ID]; ok {
+			duplicates[u.ID] = true
+		}
+		loginNames[u.ID] = account.LoginName
+		if loginNames[u.ID] == "" {
+			loginNames[u.ID] = account.Match
+		}
+	}
+
+	migrated := make([]*user_model.User, 0, len(loginNames))
+	usedLoginNames := make(map[string]bool, len(loginNames))
+	for _, u := range users {
+		loginName, ok := loginNames[u.ID]
+		if !ok {
+			report.UnmatchedUsers = append(report.UnmatchedUsers, u.Name)
+			continue
+		}
+		used, err := db.GetEngine(ctx).
+			Where(builder.Eq{"login_source": toSource.ID, "login_name": loginName}.And(builder.Neq{"id": u.ID})).
+			Exist(new(user_model.User))
+		if err != nil {
+			return nil, err
+		}
+		if used || duplicates[u.ID] || usedLoginNames[strings.ToLower(loginName)] {
+			report.Conflicts = append(report.Conflicts, u.Name)
+			continue
+		}
+		usedLoginNames[strings.ToLower(loginName)] = true
+		u.LoginName = loginName
+		migrated = append(migrated, u)
+		report.Migrated = append(report.Migrated, &MigratedUser{Name: u.Name, LoginName: loginName})
+	}
+
+	if opts.DryRun || len(migrated) == 0 {
+		return report, nil
+	}
+
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		for _, u := range migrated {
+			u.LoginType = toSource.Type
+			u.LoginSource = toSource.ID
+			u.MustChangePassword = false
+			// the user logs in with the new auth source, the local password must not be usable anymore
+			if err := u.SetPassword(""); err != nil {
+				return err
+			}
+			if err := user_model.UpdateUserCols(ctx, u, "login_type", "login_source", "login_name", "must_change_password", "passwd", "salt", "passwd_hash_algo"); err != nil {
+				return err
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	log.Info("Migrated %d user(s) from auth source %d to %s", len(migrated), opts.FromSourceID, toSource.Name)
+	return report, nil
+}
@@ -0,0 +1,100 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package auth
+
+import (
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/auth/source/smtp"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func createMigrateUsersSource(t *testing.T) *auth_model.Source {
+	source := &auth_model.Source{
+		Type:     auth_model.SMTP,
+		Name:     "migrate-users-smtp",
+		IsActive: true,
+		Cfg:      &smtp.Source{Auth: "PLAIN", Host: "localhost", Port: 25},
+	}
+	assert.NoError(t, auth_model.CreateSource(source))
+	return source
+}
+
+func TestMigrateUsers(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	source := createMigrateUsersSource(t)
+
+	opts := &MigrateUsersOptions{
+		ToSourceID: source.ID,
+		MatchBy:    MigrateUsersMatchEmail,
+		Accounts: []*MigrateUsersAccount{
+			{Match: "user2@example.com", LoginName: "uid=user2"},
+			{Match: "user4@example.com"},
+			{Match: "nobody@example.com"},
+		},
+		DryRun: true,
+	}
+
+	report, err := MigrateUsers(db.DefaultContext, opts)
+	assert.NoError(t, err)
+	assert.Equal(t, []*MigratedUser{
+		{Name: "user2", LoginName: "uid=user2"},
+		{Name: "user4", LoginName: "user4@example.com"},
+	}, report.Migrated)
+	assert.Equal(t, []string{"nobody@example.com"}, report.UnmatchedAccounts)
+	assert.Contains(t, report.UnmatchedUsers, "user5")
+	assert.NotContains(t, report.UnmatchedUsers, "user2")
+	assert.Empty(t, report.Conflicts)
+
+	// a dry run changes nothing
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.EqualValues(t, 0, user2.LoginSource)
+	assert.True(t, user2.IsPasswordSet())
+
+	opts.DryRun = false
+	_, err = MigrateUsers(db.DefaultContext, opts)
+	assert.NoError(t, err)
+
+	user2 = unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	assert.Equal(t, source.ID, user2.LoginSource)
+	assert.Equal(t, auth_model.SMTP, user2.LoginType)
+	assert.Equal(t, "uid=user2", user2.LoginName)
+	assert.False(t, user2.IsPasswordSet())
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+	assert.Equal(t, "user4@example.com", user4.LoginName)
+
+	// the migrated users are not local anymore
+	report, err = MigrateUsers(db.DefaultContext, opts)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Migrated)
+	assert.Equal(t, []string{"user2@example.com", "user4@example.com", "nobody@example.com"}, report.UnmatchedAccounts)
+}
+
+func TestMigrateUsersConflicts(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	source := createMigrateUsersSource(t)
+
+	report, err := MigrateUsers(db.DefaultContext, &MigrateUsersOptions{
+		ToSourceID: source.ID,
+		MatchBy:    MigrateUsersMatchUsername,
+		Accounts: []*MigrateUsersAccount{
+			{Match: "user2", LoginName: "shared"},
+			{Match: "user4", LoginName: "shared"},
+		},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []*MigratedUser{{Name: "user2", LoginName: "shared"}}, report.Migrated)
+	assert.Equal(t, []string{"user4"}, report.Conflicts)
+
+	_, err = MigrateUsers(db.DefaultContext, &MigrateUsersOptions{ToSourceID: source.ID, MatchBy: "login"})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	_, err = MigrateUsers(db.DefaultContext, &MigrateUsersOptions{MatchBy: MigrateUsersMatchEmail})
+	assert.ErrorIs(t, err, util.ErrInvalidArgument)
+}
@@ -25,28 +25,38 @@ var (
 	_ Named  = &OAuth2{}
 )
 
-// CheckOAuthAccessToken returns uid of user from oauth token
-func CheckOAuthAccessToken(accessToken string) int64 {
+// CheckOAuthAccessToken returns uid of user from oauth token and the API scopes granted to the application
+func CheckOAuthAccessToken(accessToken string) (int64, auth_model.AccessTokenScope) {
 	// JWT tokens require a "."
 	if !strings.Contains(accessToken, ".") {
-		return 0
+		return 0, ""
 	}
 	token, err := oauth2.ParseToken(accessToken, oauth2.DefaultSigningKey)
 	if err != nil {
 		log.Trace("oauth2.ParseToken: %v", err)
-		return 0
+		return 0, ""
 	}
 	var grant *auth_model.OAuth2Grant
 	if grant, err = auth_model.GetOAuth2GrantByID(db.DefaultContext, token.GrantID); err != nil || grant == nil {
-		return 0
+		return 0, ""
 	}
 	if token.Type != oauth2.TypeAccessToken {
-		return 0
+		return 0, ""
 	}
 	if token.ExpiresAt.Before(time.Now()) || token.IssuedAt.After(time.Now()) {
-		return 0
+		return 0, ""
+	}
+	app, err := auth_model.GetOAuth2ApplicationByID(db.DefaultContext, grant.ApplicationID)
+	if err != nil {
+		log.Trace("GetOAuth2ApplicationByID: %v", err)
+		return 0, ""
+	}
+	scope, err := app.GrantedAccessTokenScope(grant)
+	if err != nil {
+		log.Error("Invalid scope of OAuth2 grant %d: %v", grant.ID, err)
+		return 0, ""
 	}
-	return grant.UserID
+	return grant.UserID, scope
 }
 
 // OAuth2 implements the Auth interface and authenticates requests
@@ -86,10 +96,10 @@ func (o *OAuth2) userIDFromToken(req *http.Request, store DataStore) int64 {
 
 	// Let's see if token is valid.
 	if strings.Contains(tokenSHA, ".") {
-		uid := CheckOAuthAccessToken(tokenSHA)
+		uid, scope := CheckOAuthAccessToken(tokenSHA)
 		if uid != 0 {
 			store.GetData()["IsApiToken"] = true
-			store.GetData()["ApiTokenScope"] = auth_model.AccessTokenScopeAll // fallback to all
+			store.GetData()["ApiTokenScope"] = scope
 		}
 		return uid
 	}
@@ -6,6 +6,7 @@ package auth
 import (
 	"code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
 	user_model "code.gitea.io/gitea/models/user"
 )
 
@@ -35,6 +36,11 @@ func DeleteSource(source *auth.Source) error {
 		}
 	}
 
-	_, err = db.GetEngine(db.DefaultContext).ID(source.ID).Delete(new(auth.Source))
-	return err
+	if _, err = db.GetEngine(db.DefaultContext).ID(source.ID).Delete(new(auth.Source)); err != nil {
+		return err
+	}
+	return db.DeleteBeans(db.DefaultContext,
+		&organization.TeamGroupMapping{SourceID: source.ID},
+		&organization.ExternalGroupMember{SourceID: source.ID},
+	)
 }
@@ -120,6 +120,11 @@ func (source *Source) Authenticate(user *user_model.User, userName, password str
 			return user, err
 		}
 	}
+	if source.GroupsEnabled {
+		if err := source_service.RecordExternalGroups(db.DefaultContext, source.authSource.ID, user, sr.Groups); err != nil {
+			return user, err
+		}
+	}
 
 	return user, nil
 }
@@ -184,6 +184,11 @@ func (source *Source) Sync(ctx context.Context, updateExisting bool) error {
 				log.Error("SyncGroupsToTeamsCached: %v", err)
 			}
 		}
+		if source.GroupsEnabled {
+			if err := source_service.RecordExternalGroups(ctx, source.authSource.ID, usr, su.Groups); err != nil {
+				log.Error("RecordExternalGroups: %v", err)
+			}
+		}
 	}
 
 	// Rewrite authorized_keys file if LDAP Public SSH Key attribute is set and any key was added or removed
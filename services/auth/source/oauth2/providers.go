@@ -141,9 +141,14 @@ func ClearProviders() {
 // ErrAuthSourceNotActived login source is not actived error
 var ErrAuthSourceNotActived = errors.New("auth source is not actived")
 
+// CallbackURL returns the URL the provider redirects to after the authorization
+func CallbackURL(providerName string) string {
+	return setting.AppURL + "user/oauth2/" + url.PathEscape(providerName) + "/callback"
+}
+
 // used to create different types of goth providers
 func createProvider(providerName string, source *Source) (goth.Provider, error) {
-	callbackURL := setting.AppURL + "user/oauth2/" + url.PathEscape(providerName) + "/callback"
+	callbackURL := CallbackURL(providerName)
 
 	var provider goth.Provider
 	var err error
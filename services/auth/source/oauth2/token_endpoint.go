@@ -0,0 +1,50 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package oauth2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"code.gitea.io/gitea/modules/json"
+)
+
+// TokenEndpoint returns the token endpoint of the source, it is discovered for OpenID Connect sources.
+// The empty string is returned for the providers whose token endpoint is built in.
+func (source *Source) TokenEndpoint(ctx context.Context, client *http.Client) (string, error) {
+	if source.Provider == (&OpenIDProvider{}).Name() {
+		return discoverTokenEndpoint(ctx, client, source.OpenIDConnectAutoDiscoveryURL)
+	}
+	if source.CustomURLMapping != nil {
+		return source.CustomURLMapping.TokenURL, nil
+	}
+	return "", nil
+}
+
+func discoverTokenEndpoint(ctx context.Context, client *http.Client, discoveryURL string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("discovery document %s returned status %d", discoveryURL, resp.StatusCode)
+	}
+
+	var discovery struct {
+		TokenEndpoint string `json:"token_endpoint"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&discovery); err != nil {
+		return "", fmt.Errorf("invalid discovery document %s: %w", discoveryURL, err)
+	}
+	if discovery.TokenEndpoint == "" {
+		return "", fmt.Errorf("discovery document %s has no token endpoint", discoveryURL)
+	}
+	return discovery.TokenEndpoint, nil
+}
@@ -45,6 +45,12 @@ func SyncGroupsToTeamsCached(ctx context.Context, user *user_model.User, sourceU
 	return nil
 }
 
+// RecordExternalGroups records the groups of a source which contain the user, so that the teams which have these
+// groups mapped to them can sync their members
+func RecordExternalGroups(ctx context.Context, sourceID int64, user *user_model.User, sourceUserGroups container.Set[string]) error {
+	return organization.ReplaceExternalGroupsOfUser(ctx, sourceID, user.ID, sourceUserGroups.Values())
+}
+
 func resolveMappedMemberships(sourceUserGroups container.Set[string], sourceGroupTeamMapping map[string]map[string][]string) (map[string][]string, map[string][]string) {
 	membershipsToAdd := map[string][]string{}
 	membershipsToRemove := map[string][]string{}
@@ -0,0 +1,182 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package backport
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+	issue_service "code.gitea.io/gitea/services/issue"
+	pull_service "code.gitea.io/gitea/services/pull"
+	"code.gitea.io/gitea/services/repository/files"
+)
+
+// ErrNotMerged is returned when backporting a pull request which has not been merged
+var ErrNotMerged = errors.New("pull request has not been merged")
+
+type backportRequest struct {
+	PullID int64
+	DoerID int64
+	Branch string
+}
+
+var backportQueue *queue.WorkerPoolQueue[backportRequest]
+
+// Init starts the backport queue and listens for backport commands
+func Init() error {
+	if !setting.Repository.PullRequest.EnableBackport {
+		return nil
+	}
+
+	backportQueue = queue.CreateUniqueQueue("pr_backport", handler)
+	if backportQueue == nil {
+		return fmt.Errorf("Unable to create pr_backport Queue")
+	}
+	go graceful.GetManager().RunWithShutdownFns(backportQueue.Run)
+
+	notification.RegisterNotifier(NewNotifier())
+	return nil
+}
+
+func handler(items ...backportRequest) []backportRequest {
+	ctx := graceful.GetManager().ShutdownContext()
+	for _, req := range items {
+		if err := handleBackport(ctx, req); err != nil {
+			log.Error("Backport of pull request %d onto %s failed: %v", req.PullID, req.Branch, err)
+		}
+	}
+	return nil
+}
+
+// addToQueue schedules backports of a merged pull request onto the given branches
+func addToQueue(pr *issues_model.PullRequest, doer *user_model.User, branches []string) {
+	for _, branch := range branches {
+		if branch == pr.BaseBranch {
+			continue
+		}
+		log.Trace("Adding backport of pull request %d onto %s to the queue", pr.ID, branch)
+		if err := backportQueue.Push(backportRequest{PullID: pr.ID, DoerID: doer.ID, Branch: branch}); err != nil && !errors.Is(err, queue.ErrAlreadyInQueue) {
+			log.Error("Unable to add backport of pull request %d onto %s to the queue: %v", pr.ID, branch, err)
+		}
+	}
+}
+
+// canBackport checks if the user may request backports of the pull request
+func canBackport(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User) bool {
+	if doer == nil || doer.IsGhost() {
+		return false
+	}
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		log.Error("LoadBaseRepo: %v", err)
+		return false
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, pr.BaseRepo, doer)
+	if err != nil {
+		log.Error("GetUserRepoPermission: %v", err)
+		return false
+	}
+	return perm.CanWrite(unit.TypeCode)
+}
+
+func handleBackport(ctx context.Context, req backportRequest) error {
+	pr, err := issues_model.GetPullRequestByID(ctx, req.PullID)
+	if err != nil {
+		return err
+	}
+	if err := pr.LoadIssue(ctx); err != nil {
+		return err
+	}
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return err
+	}
+	doer, err := user_model.GetUserByID(ctx, req.DoerID)
+	if err != nil {
+		return err
+	}
+
+	backportPR, err := Backport(ctx, pr, doer, req.Branch)
+	var message string
+	switch {
+	case err == nil:
+		message = fmt.Sprintf("Backported onto `%s` in #%d.", req.Branch, backportPR.Index)
+	case models.IsErrCherryPickConflicts(err):
+		conflicts := err.(models.ErrCherryPickConflicts)
+		message = fmt.Sprintf("Backport onto `%s` failed, cherry-picking %s conflicts in:\n\n- `%s`\n\nPlease backport this pull request manually.",
+			req.Branch, conflicts.CommitSHA, strings.Join(conflicts.Files, "`\n- `"))
+	case git.IsErrBranchNotExist(err):
+		message = fmt.Sprintf("Backport onto `%s` failed, the branch does not exist.", req.Branch)
+	case models.IsErrBranchAlreadyExists(err):
+		message = fmt.Sprintf("Backport onto `%s` failed, the branch `%s` already exists.", req.Branch, backportBranchName(pr, req.Branch))
+	default:
+		message = fmt.Sprintf("Backport onto `%s` failed.", req.Branch)
+	}
+	if _, commentErr := issue_service.CreateIssueComment(ctx, doer, pr.BaseRepo, pr.Issue, message, nil); commentErr != nil {
+		log.Error("CreateIssueComment: %v", commentErr)
+	}
+	return err
+}
+
+func backportBranchName(pr *issues_model.PullRequest, branch string) string {
+	return fmt.Sprintf("backport-%d-%s", pr.Index, strings.ReplaceAll(branch, "/", "-"))
+}
+
+// Backport cherry-picks the commits of a merged pull request onto a new branch based on
+// the target branch and opens a pull request from it into the target branch.
+func Backport(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, branch string) (*issues_model.PullRequest, error) {
+	if !pr.HasMerged {
+		return nil, ErrNotMerged
+	}
+	if err := pr.LoadIssue(ctx); err != nil {
+		return nil, err
+	}
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return nil, err
+	}
+	repo := pr.BaseRepo
+
+	gitRepo, closer, err := git.RepositoryFromContextOrOpen(ctx, repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	if !gitRepo.IsBranchExist(branch) {
+		return nil, git.ErrBranchNotExist{Name: branch}
+	}
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, fmt.Errorf("GetRefCommitID: %w", err)
+	}
+
+	newBranch := backportBranchName(pr, branch)
+	if _, err := files.CherryPickRange(ctx, repo, doer, false, &files.CherryPickRangeOptions{
+		OldBranch:  branch,
+		NewBranch:  newBranch,
+		From:       pr.MergeBase,
+		To:         headCommitID,
+		SkipMerges: true,
+	}); err != nil {
+		return nil, err
+	}
+
+	title := fmt.Sprintf("[%s] %s", branch, pr.Issue.Title)
+	content := fmt.Sprintf("Backport #%d onto `%s`.", pr.Index, branch)
+	if pr.Issue.Content != "" {
+		content += "\n\n" + pr.Issue.Content
+	}
+	return pull_service.NewPullRequestFromBranch(ctx, repo, doer, newBranch, branch, title, content)
+}
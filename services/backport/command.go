@@ -0,0 +1,48 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package backport
+
+import (
+	"regexp"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+var backportCommandPattern = regexp.MustCompile(`(?m)^/backport[ \t]+([^\r\n]+)`)
+
+// ParseBackportCommand returns the target branches of all `/backport <branch>...` lines of a comment.
+// Branches can be separated by spaces or commas, invalid branch names are ignored.
+func ParseBackportCommand(content string) []string {
+	var branches []string
+	seen := make(container.Set[string])
+	for _, match := range backportCommandPattern.FindAllStringSubmatch(content, -1) {
+		for _, branch := range strings.FieldsFunc(match[1], func(r rune) bool {
+			return r == ' ' || r == '\t' || r == ','
+		}) {
+			if git.IsValidRefPattern(branch) && seen.Add(branch) {
+				branches = append(branches, branch)
+			}
+		}
+	}
+	return branches
+}
+
+// labelBranches returns the target branches of the backport labels
+func labelBranches(labels []*issues_model.Label) []string {
+	prefix := setting.Repository.PullRequest.BackportLabelPrefix
+	if prefix == "" {
+		return nil
+	}
+	var branches []string
+	for _, label := range labels {
+		if strings.HasPrefix(label.Name, prefix) && len(label.Name) > len(prefix) {
+			branches = append(branches, label.Name[len(prefix):])
+		}
+	}
+	return branches
+}
@@ -0,0 +1,29 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package backport
+
+import (
+	"testing"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseBackportCommand(t *testing.T) {
+	assert.Empty(t, ParseBackportCommand("LGTM"))
+	assert.Empty(t, ParseBackportCommand("please /backport release/v1.21"))
+	assert.Equal(t, []string{"release/v1.21"}, ParseBackportCommand("/backport release/v1.21"))
+	assert.Equal(t, []string{"release/v1.20", "release/v1.21"}, ParseBackportCommand("Thanks!\r\n/backport release/v1.20, release/v1.21\r\n"))
+	assert.Equal(t, []string{"v1.20", "v1.21"}, ParseBackportCommand("/backport v1.20 v1.21\n/backport v1.21"))
+	assert.Equal(t, []string{"v1.21"}, ParseBackportCommand("/backport v1.21 ..invalid"))
+}
+
+func TestLabelBranches(t *testing.T) {
+	assert.Equal(t, []string{"release/v1.21"}, labelBranches([]*issues_model.Label{
+		{Name: "kind/bug"},
+		{Name: "backport/release/v1.21"},
+		{Name: "backport/"},
+	}))
+}
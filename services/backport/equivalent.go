@@ -0,0 +1,50 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package backport
+
+import (
+	"fmt"
+	"sort"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+)
+
+// EquivalentCommit is a commit of a head which introduces the same changes as a commit of a base
+type EquivalentCommit struct {
+	CommitID     string
+	BaseCommitID string
+}
+
+// FindEquivalentCommits returns the commits of head which aren't in base but are already present in it with the
+// same patch, like cherry-picked or backported commits. The commit ids must not be references, as the result is
+// cached for the pair of commits.
+func FindEquivalentCommits(repo *repo_model.Repository, gitRepo *git.Repository, baseCommitID, headCommitID string) ([]*EquivalentCommit, error) {
+	data, err := cache.GetString(fmt.Sprintf("equivalent_commits_%d_%s_%s", repo.ID, baseCommitID, headCommitID), func() (string, error) {
+		equivalent, err := gitRepo.GetEquivalentCommits(baseCommitID, headCommitID)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(equivalent)
+		return string(data), err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var equivalent map[string]string
+	if err := json.Unmarshal([]byte(data), &equivalent); err != nil {
+		return nil, err
+	}
+	commits := make([]*EquivalentCommit, 0, len(equivalent))
+	for commitID, baseCommitID := range equivalent {
+		commits = append(commits, &EquivalentCommit{CommitID: commitID, BaseCommitID: baseCommitID})
+	}
+	sort.Slice(commits, func(i, j int) bool {
+		return commits[i].CommitID < commits[j].CommitID
+	})
+	return commits, nil
+}
@@ -0,0 +1,111 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package backport
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+)
+
+type backportNotifier struct {
+	base.NullNotifier
+}
+
+var _ base.Notifier = &backportNotifier{}
+
+// NewNotifier create a new backportNotifier notifier
+func NewNotifier() base.Notifier {
+	return &backportNotifier{}
+}
+
+// NotifyCreateIssueComment backports merged pull requests on `/backport` commands.
+// Commands on pull requests which have not been merged yet are picked up when they are merged.
+func (*backportNotifier) NotifyCreateIssueComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository,
+	issue *issues_model.Issue, comment *issues_model.Comment, mentions []*user_model.User,
+) {
+	if !issue.IsPull {
+		return
+	}
+	branches := ParseBackportCommand(comment.Content)
+	if len(branches) == 0 {
+		return
+	}
+	if err := issue.LoadPullRequest(ctx); err != nil {
+		log.Error("LoadPullRequest: %v", err)
+		return
+	}
+	if issue.PullRequest.HasMerged && canBackport(ctx, issue.PullRequest, doer) {
+		addToQueue(issue.PullRequest, doer, branches)
+	}
+}
+
+// NotifyIssueChangeLabels backports merged pull requests when backport labels are added
+func (*backportNotifier) NotifyIssueChangeLabels(ctx context.Context, doer *user_model.User, issue *issues_model.Issue,
+	addedLabels, removedLabels []*issues_model.Label,
+) {
+	if !issue.IsPull {
+		return
+	}
+	branches := labelBranches(addedLabels)
+	if len(branches) == 0 {
+		return
+	}
+	if err := issue.LoadPullRequest(ctx); err != nil {
+		log.Error("LoadPullRequest: %v", err)
+		return
+	}
+	if issue.PullRequest.HasMerged && canBackport(ctx, issue.PullRequest, doer) {
+		addToQueue(issue.PullRequest, doer, branches)
+	}
+}
+
+func (*backportNotifier) NotifyMergePullRequest(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	backportOnMerge(ctx, doer, pr)
+}
+
+func (*backportNotifier) NotifyAutoMergePullRequest(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	backportOnMerge(ctx, doer, pr)
+}
+
+// backportOnMerge schedules the backports requested by labels and comments before the pull request was merged
+func backportOnMerge(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	if err := pr.LoadIssue(ctx); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	if err := pr.Issue.LoadLabels(ctx); err != nil {
+		log.Error("LoadLabels: %v", err)
+		return
+	}
+	if branches := labelBranches(pr.Issue.Labels); len(branches) > 0 && canBackport(ctx, pr, doer) {
+		addToQueue(pr, doer, branches)
+	}
+
+	comments, err := issues_model.FindComments(ctx, &issues_model.FindCommentsOptions{
+		IssueID: pr.IssueID,
+		Type:    issues_model.CommentTypeComment,
+	})
+	if err != nil {
+		log.Error("FindComments: %v", err)
+		return
+	}
+	for _, comment := range comments {
+		branches := ParseBackportCommand(comment.Content)
+		if len(branches) == 0 {
+			continue
+		}
+		if err := comment.LoadPoster(ctx); err != nil {
+			log.Error("LoadPoster: %v", err)
+			continue
+		}
+		if canBackport(ctx, pr, comment.Poster) {
+			addToQueue(pr, comment.Poster, branches)
+		}
+	}
+}
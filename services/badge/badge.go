@@ -0,0 +1,159 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package badge
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/badge"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// Kind is the information shown by a badge
+type Kind string
+
+// The kinds of the badges of a repository
+const (
+	KindStatus   Kind = "status"
+	KindRelease  Kind = "release"
+	KindCoverage Kind = "coverage"
+	KindIssues   Kind = "issues"
+)
+
+// Kinds are all the kinds of the badges
+var Kinds = []Kind{KindStatus, KindRelease, KindCoverage, KindIssues}
+
+// CacheSeconds is how long a badge is cached by Gitea and by the clients
+const CacheSeconds = 300
+
+func cacheKey(repoID int64, kind Kind, branch string) string {
+	return "repo_badge_" + strconv.FormatInt(repoID, 10) + "_" + string(kind) + "_" + branch
+}
+
+// GetRepoBadge returns the badge of a kind of a repository, the status and the coverage are those of the branch or
+// of the default branch if it's empty
+func GetRepoBadge(ctx context.Context, repo *repo_model.Repository, kind Kind, branch string) (*badge.Badge, error) {
+	if branch == "" {
+		branch = repo.DefaultBranch
+	}
+	if kind == KindRelease || kind == KindIssues {
+		branch = ""
+	}
+
+	key := cacheKey(repo.ID, kind, branch)
+	c := cache.GetCache()
+	if c != nil {
+		if cached, ok := c.Get(key).(string); ok {
+			b := &badge.Badge{}
+			if err := json.Unmarshal([]byte(cached), b); err == nil {
+				return b, nil
+			}
+		}
+	}
+
+	b, err := generateRepoBadge(ctx, repo, kind, branch)
+	if err != nil {
+		return nil, err
+	}
+
+	if c != nil {
+		if data, err := json.Marshal(b); err == nil {
+			if err := c.Put(key, string(data), CacheSeconds); err != nil {
+				log.Warn("Unable to cache the %s badge of %s: %v", kind, repo.FullName(), err)
+			}
+		}
+	}
+	return b, nil
+}
+
+// URL returns the URL of a badge of a repository, the token allows to embed the badges of a private repository
+func URL(repo *repo_model.Repository, kind Kind, token string) string {
+	u := repo.HTMLURL() + "/badges/" + string(kind) + ".svg"
+	if token != "" {
+		u += "?token=" + url.QueryEscape(token)
+	}
+	return u
+}
+
+// InvalidateCoverageBadge removes the cached coverage badge of a branch after a new report was uploaded
+func InvalidateCoverageBadge(repo *repo_model.Repository, branch string) {
+	cache.Remove(cacheKey(repo.ID, KindCoverage, branch))
+}
+
+func generateRepoBadge(ctx context.Context, repo *repo_model.Repository, kind Kind, branch string) (*badge.Badge, error) {
+	switch kind {
+	case KindStatus:
+		return generateStatusBadge(ctx, repo, branch)
+	case KindRelease:
+		rel, err := repo_model.GetLatestReleaseByRepoID(repo.ID)
+		if err != nil {
+			if repo_model.IsErrReleaseNotExist(err) {
+				return &badge.Badge{Label: "release", Message: "none", Color: badge.ColorGrey}, nil
+			}
+			return nil, err
+		}
+		return &badge.Badge{Label: "release", Message: rel.TagName, Color: badge.ColorBlue}, nil
+	case KindCoverage:
+		report, err := git_model.GetLatestCoverageReport(ctx, repo.ID, branch)
+		if err != nil {
+			return nil, err
+		} else if report == nil {
+			return &badge.Badge{Label: "coverage", Message: "unknown", Color: badge.ColorGrey}, nil
+		}
+		return &badge.Badge{
+			Label:   "coverage",
+			Message: strconv.FormatFloat(report.Coverage, 'f', 1, 64) + "%",
+			Color:   badge.CoverageColor(report.Coverage),
+		}, nil
+	case KindIssues:
+		return &badge.Badge{Label: "issues", Message: strconv.Itoa(repo.NumOpenIssues) + " open", Color: badge.ColorBlue}, nil
+	}
+	return &badge.Badge{Label: string(kind), Message: "unknown", Color: badge.ColorGrey}, nil
+}
+
+func generateStatusBadge(ctx context.Context, repo *repo_model.Repository, branch string) (*badge.Badge, error) {
+	if repo.IsEmpty {
+		return statusBadge(nil), nil
+	}
+	sha, err := git.GetBranchCommitID(ctx, repo.RepoPath(), branch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return statusBadge(nil), nil
+		}
+		return nil, err
+	}
+	statuses, _, err := git_model.GetLatestCommitStatus(ctx, repo.ID, sha, db.ListOptions{ListAll: true})
+	if err != nil {
+		return nil, err
+	}
+	return statusBadge(git_model.CalcCommitStatus(statuses)), nil
+}
+
+// statusBadge returns the badge of the combined status of a commit, which is nil if it has no status
+func statusBadge(status *git_model.CommitStatus) *badge.Badge {
+	b := &badge.Badge{Label: "build", Message: "unknown", Color: badge.ColorGrey}
+	if status == nil {
+		return b
+	}
+	switch status.State {
+	case api.CommitStatusSuccess:
+		b.Message, b.Color = "passing", badge.ColorBrightGreen
+	case api.CommitStatusFailure, api.CommitStatusError:
+		b.Message, b.Color = "failing", badge.ColorRed
+	case api.CommitStatusWarning:
+		b.Message, b.Color = "warning", badge.ColorOrange
+	case api.CommitStatusPending, api.CommitStatusRunning:
+		b.Message, b.Color = "pending", badge.ColorYellow
+	}
+	return b
+}
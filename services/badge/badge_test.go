@@ -0,0 +1,28 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package badge
+
+import (
+	"testing"
+
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/modules/badge"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusBadge(t *testing.T) {
+	assert.Equal(t, &badge.Badge{Label: "build", Message: "unknown", Color: badge.ColorGrey}, statusBadge(nil))
+	for state, expected := range map[api.CommitStatusState]string{
+		api.CommitStatusSuccess: "passing",
+		api.CommitStatusFailure: "failing",
+		api.CommitStatusError:   "failing",
+		api.CommitStatusWarning: "warning",
+		api.CommitStatusPending: "pending",
+		api.CommitStatusRunning: "pending",
+	} {
+		assert.Equal(t, expected, statusBadge(&git_model.CommitStatus{State: state}).Message, state)
+	}
+}
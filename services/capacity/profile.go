@@ -0,0 +1,283 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package capacity generates profiles of the instance, which help to plan its capacity and to find the cause of scaling problems.
+// The profiles are anonymous, they don't contain names, and they are only generated locally.
+package capacity
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"sort"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/setting"
+
+	"xorm.io/builder"
+)
+
+// MaxTopRepositories is the maximum number of the largest repositories in a profile
+const MaxTopRepositories = 100
+
+// Profile describes the size and the load of the instance
+type Profile struct {
+	GeneratedAt     time.Time                    `json:"generated_at"`
+	Version         string                       `json:"version"`
+	Database        string                       `json:"database"`
+	Runtime         *RuntimeProfile              `json:"runtime"`
+	Counts          map[string]int64             `json:"counts"`
+	Sizes           map[string]*SizeDistribution `json:"sizes"`
+	Queues          []*QueueProfile              `json:"queues"`
+	TopRepositories []*RepositoryProfile         `json:"top_repositories"`
+}
+
+// RuntimeProfile describes the process of the instance
+type RuntimeProfile struct {
+	GoVersion     string `json:"go_version"`
+	OS            string `json:"os"`
+	Arch          string `json:"arch"`
+	NumCPU        int    `json:"num_cpu"`
+	NumGoroutine  int    `json:"num_goroutine"`
+	HeapAlloc     uint64 `json:"heap_alloc"`
+	Sys           uint64 `json:"sys"`
+	UptimeSeconds int64  `json:"uptime_seconds"`
+}
+
+// SizeDistribution describes the sizes in bytes of a kind of stored objects
+type SizeDistribution struct {
+	Count   int64         `json:"count"`
+	Total   int64         `json:"total"`
+	Min     int64         `json:"min"`
+	Max     int64         `json:"max"`
+	Mean    int64         `json:"mean"`
+	Buckets []*SizeBucket `json:"buckets"`
+}
+
+// SizeBucket is the number of objects which are smaller than LessThan and not in a previous bucket, the last bucket has no limit
+type SizeBucket struct {
+	LessThan int64 `json:"less_than,omitempty"`
+	Count    int64 `json:"count"`
+}
+
+// QueueProfile describes the load of a queue
+type QueueProfile struct {
+	Name          string `json:"name"`
+	Type          string `json:"type"`
+	QueuedItems   int    `json:"queued_items"`
+	Workers       int    `json:"workers"`
+	ActiveWorkers int    `json:"active_workers"`
+	MaxWorkers    int    `json:"max_workers"`
+	HandledItems  int64  `json:"handled_items"`
+	// average number of handled items per minute since the queue was created
+	HandledPerMinute float64 `json:"handled_per_minute"`
+}
+
+// RepositoryProfile describes a large repository, it is only identified by its ID
+type RepositoryProfile struct {
+	ID        int64 `json:"id"`
+	Size      int64 `json:"size"`
+	LFSSize   int64 `json:"lfs_size"`
+	IsMirror  bool  `json:"is_mirror"`
+	IsFork    bool  `json:"is_fork"`
+	NumIssues int   `json:"num_issues"`
+	NumPulls  int   `json:"num_pulls"`
+	NumForks  int   `json:"num_forks"`
+	NumStars  int   `json:"num_stars"`
+}
+
+var profileCounts = []struct {
+	name  string
+	table string
+	cond  builder.Cond
+}{
+	{"users", "user", builder.Eq{"type": user_model.UserTypeIndividual}},
+	{"organizations", "user", builder.Eq{"type": user_model.UserTypeOrganization}},
+	{"teams", "team", nil},
+	{"public_keys", "public_key", nil},
+	{"repositories", "repository", nil},
+	{"mirrors", "repository", builder.Eq{"is_mirror": true}},
+	{"forks", "repository", builder.Eq{"is_fork": true}},
+	{"archived_repositories", "repository", builder.Eq{"is_archived": true}},
+	{"issues", "issue", builder.Eq{"is_pull": false}},
+	{"pull_requests", "issue", builder.Eq{"is_pull": true}},
+	{"comments", "comment", nil},
+	{"releases", "release", nil},
+	{"attachments", "attachment", nil},
+	{"lfs_objects", "lfs_meta_object", nil},
+	{"packages", "package", nil},
+	{"package_versions", "package_version", nil},
+	{"webhooks", "webhook", nil},
+	{"hook_tasks", "hook_task", nil},
+	{"action_runs", "action_run", nil},
+	{"action_tasks", "action_task", nil},
+}
+
+var profileSizes = []struct {
+	name   string
+	table  string
+	column string
+	cond   builder.Cond
+}{
+	{"repositories", "repository", "size", nil},
+	{"lfs_objects", "lfs_meta_object", "size", nil},
+	{"attachments", "attachment", "size", nil},
+	{"package_blobs", "package_blob", "size", nil},
+	{"action_logs", "action_task", "log_size", builder.Eq{"log_expired": false}},
+	{"action_artifacts", "action_artifact", "file_size", nil},
+}
+
+// sizeBucketBounds are the upper limits of the buckets of the size distributions
+var sizeBucketBounds = []int64{1 << 20, 10 << 20, 100 << 20, 1 << 30, 10 << 30}
+
+// GenerateProfile generates the profile of the instance with the given number of its largest repositories
+func GenerateProfile(ctx context.Context, topRepositories int) (*Profile, error) {
+	if topRepositories > MaxTopRepositories {
+		topRepositories = MaxTopRepositories
+	}
+
+	p := &Profile{
+		GeneratedAt: time.Now().UTC(),
+		Version:     setting.AppVer,
+		Database:    setting.Database.Type.String(),
+		Runtime:     getRuntimeProfile(),
+		Counts:      make(map[string]int64, len(profileCounts)),
+		Sizes:       make(map[string]*SizeDistribution, len(profileSizes)),
+		Queues:      getQueueProfiles(),
+	}
+
+	for _, c := range profileCounts {
+		count, err := db.GetEngine(ctx).Table(c.table).Where(condOrAll(c.cond)).Count()
+		if err != nil {
+			return nil, fmt.Errorf("count %s: %w", c.name, err)
+		}
+		p.Counts[c.name] = count
+	}
+
+	for _, s := range profileSizes {
+		distribution, err := getSizeDistribution(ctx, s.table, s.column, condOrAll(s.cond))
+		if err != nil {
+			return nil, fmt.Errorf("sizes of %s: %w", s.name, err)
+		}
+		p.Sizes[s.name] = distribution
+	}
+
+	var err error
+	if p.TopRepositories, err = getTopRepositories(ctx, topRepositories); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func condOrAll(cond builder.Cond) builder.Cond {
+	if cond == nil {
+		return builder.NewCond()
+	}
+	return cond
+}
+
+func getRuntimeProfile() *RuntimeProfile {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return &RuntimeProfile{
+		GoVersion:     runtime.Version(),
+		OS:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+		NumCPU:        runtime.NumCPU(),
+		NumGoroutine:  runtime.NumGoroutine(),
+		HeapAlloc:     m.HeapAlloc,
+		Sys:           m.Sys,
+		UptimeSeconds: int64(time.Since(setting.AppStartTime).Seconds()),
+	}
+}
+
+func getQueueProfiles() []*QueueProfile {
+	queues := queue.GetManager().ManagedQueues()
+	profiles := make([]*QueueProfile, 0, len(queues))
+	for _, q := range queues {
+		qp := &QueueProfile{
+			Name:          q.GetName(),
+			Type:          q.GetType(),
+			QueuedItems:   q.GetQueueItemNumber(),
+			Workers:       q.GetWorkerNumber(),
+			ActiveWorkers: q.GetWorkerActiveNumber(),
+			MaxWorkers:    q.GetWorkerMaxNumber(),
+			HandledItems:  q.GetHandledItemNumber(),
+		}
+		if minutes := time.Since(q.GetCreatedTime()).Minutes(); minutes > 0 {
+			qp.HandledPerMinute = float64(qp.HandledItems) / minutes
+		}
+		profiles = append(profiles, qp)
+	}
+	sort.Slice(profiles, func(i, j int) bool {
+		return profiles[i].Name < profiles[j].Name
+	})
+	return profiles
+}
+
+func getSizeDistribution(ctx context.Context, table, column string, cond builder.Cond) (*SizeDistribution, error) {
+	stats, err := db.GetColumnStats(ctx, table, column, cond)
+	if err != nil {
+		return nil, err
+	}
+	d := &SizeDistribution{
+		Count:   stats.Count,
+		Total:   stats.Total,
+		Min:     stats.Minimum,
+		Max:     stats.Maximum,
+		Buckets: make([]*SizeBucket, 0, len(sizeBucketBounds)+1),
+	}
+	if d.Count > 0 {
+		d.Mean = d.Total / d.Count
+	}
+
+	counted := int64(0)
+	for _, bound := range sizeBucketBounds {
+		smaller, err := db.GetEngine(ctx).Table(table).Where(cond.And(builder.Lt{column: bound})).Count()
+		if err != nil {
+			return nil, err
+		}
+		d.Buckets = append(d.Buckets, &SizeBucket{LessThan: bound, Count: smaller - counted})
+		counted = smaller
+	}
+	d.Buckets = append(d.Buckets, &SizeBucket{Count: d.Count - counted})
+	return d, nil
+}
+
+func getTopRepositories(ctx context.Context, limit int) ([]*RepositoryProfile, error) {
+	if limit <= 0 {
+		return []*RepositoryProfile{}, nil
+	}
+	repos := make([]*repo_model.Repository, 0, limit)
+	if err := db.GetEngine(ctx).Desc("size").Asc("id").Limit(limit).Find(&repos); err != nil {
+		return nil, fmt.Errorf("find largest repositories: %w", err)
+	}
+	ids := make([]int64, 0, len(repos))
+	for _, repo := range repos {
+		ids = append(ids, repo.ID)
+	}
+	lfsSizes, err := db.SumGroupBy(ctx, "lfs_meta_object", "repository_id", "size", builder.In("repository_id", ids))
+	if err != nil {
+		return nil, fmt.Errorf("sum LFS sizes: %w", err)
+	}
+
+	profiles := make([]*RepositoryProfile, 0, len(repos))
+	for _, repo := range repos {
+		profiles = append(profiles, &RepositoryProfile{
+			ID:        repo.ID,
+			Size:      repo.Size,
+			LFSSize:   lfsSizes[repo.ID],
+			IsMirror:  repo.IsMirror,
+			IsFork:    repo.IsFork,
+			NumIssues: repo.NumIssues,
+			NumPulls:  repo.NumPulls,
+			NumForks:  repo.NumForks,
+			NumStars:  repo.NumStars,
+		})
+	}
+	return profiles, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package capacity
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGenerateProfile(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// make the sizes distinguishable
+	_, err := db.GetEngine(db.DefaultContext).ID(54).Cols("size").Update(&repo_model.Repository{Size: 20 << 20})
+	assert.NoError(t, err)
+	_, err = db.GetEngine(db.DefaultContext).ID(1).Cols("size").Update(&repo_model.Repository{Size: 1 << 10})
+	assert.NoError(t, err)
+
+	p, err := GenerateProfile(db.DefaultContext, 2)
+	assert.NoError(t, err)
+
+	assert.EqualValues(t, unittest.GetCount(t, &repo_model.Repository{}), p.Counts["repositories"])
+	assert.EqualValues(t, unittest.GetCount(t, &user_model.User{}, "type = ?", user_model.UserTypeOrganization), p.Counts["organizations"])
+	assert.EqualValues(t, unittest.GetCount(t, &git_model.LFSMetaObject{}), p.Counts["lfs_objects"])
+
+	lfs := p.Sizes["lfs_objects"]
+	assert.EqualValues(t, 4, lfs.Count)
+	assert.EqualValues(t, 266, lfs.Total)
+	assert.EqualValues(t, 25, lfs.Min)
+	assert.EqualValues(t, 107, lfs.Max)
+	assert.EqualValues(t, 66, lfs.Mean)
+	if assert.Len(t, lfs.Buckets, 6) {
+		assert.EqualValues(t, 1<<20, lfs.Buckets[0].LessThan)
+		assert.EqualValues(t, 4, lfs.Buckets[0].Count)
+		assert.EqualValues(t, 0, lfs.Buckets[5].LessThan)
+	}
+
+	repos := p.Sizes["repositories"]
+	assert.EqualValues(t, 20<<20+1<<10, repos.Total)
+	assert.EqualValues(t, repos.Count-1, repos.Buckets[0].Count)
+	assert.EqualValues(t, 1, repos.Buckets[2].Count)
+
+	if assert.Len(t, p.TopRepositories, 2) {
+		assert.EqualValues(t, 54, p.TopRepositories[0].ID)
+		assert.EqualValues(t, 20<<20, p.TopRepositories[0].Size)
+		assert.EqualValues(t, 266, p.TopRepositories[0].LFSSize)
+		assert.EqualValues(t, 1, p.TopRepositories[1].ID)
+	}
+
+	p, err = GenerateProfile(db.DefaultContext, 0)
+	assert.NoError(t, err)
+	assert.Empty(t, p.TopRepositories)
+}
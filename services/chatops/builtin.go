@@ -0,0 +1,230 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package chatops
+
+import (
+	"fmt"
+	"strings"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	actions_service "code.gitea.io/gitea/services/actions"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+func init() {
+	Register(&assignCommand{})
+	Register(&unassignCommand{})
+	Register(&lockCommand{})
+	Register(&unlockCommand{})
+	Register(&milestoneCommand{})
+	Register(&retestCommand{})
+}
+
+// resolveUsers returns the users mentioned in the arguments, "me" refers to the doer
+func resolveUsers(ctx *Context, args []string) ([]*user_model.User, error) {
+	if len(args) == 0 {
+		return []*user_model.User{ctx.Doer}, nil
+	}
+	users := make([]*user_model.User, 0, len(args))
+	for _, arg := range args {
+		name := strings.TrimPrefix(arg, "@")
+		if strings.EqualFold(name, "me") {
+			users = append(users, ctx.Doer)
+			continue
+		}
+		user, err := user_model.GetUserByName(ctx, name)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				return nil, ErrUsage{Message: fmt.Sprintf("user `%s` does not exist.", name)}
+			}
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	return users, nil
+}
+
+type assignCommand struct{}
+
+func (*assignCommand) Name() string                { return "assign" }
+func (*assignCommand) Description() string         { return "Assign users or yourself" }
+func (*assignCommand) AccessMode() perm.AccessMode { return perm.AccessModeWrite }
+
+func (*assignCommand) Run(ctx *Context, args []string) (string, error) {
+	users, err := resolveUsers(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	for _, user := range users {
+		valid, err := access_model.CanBeAssigned(ctx, user, ctx.Repo, ctx.Issue.IsPull)
+		if err != nil {
+			return "", err
+		} else if !valid {
+			return "", ErrUsage{Message: fmt.Sprintf("@%s cannot be assigned.", user.Name)}
+		}
+		if err := issue_service.AddAssigneeIfNotAssigned(ctx, ctx.Issue, ctx.Doer, user.ID); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+type unassignCommand struct{}
+
+func (*unassignCommand) Name() string                { return "unassign" }
+func (*unassignCommand) Description() string         { return "Unassign users or yourself" }
+func (*unassignCommand) AccessMode() perm.AccessMode { return perm.AccessModeWrite }
+
+func (*unassignCommand) Run(ctx *Context, args []string) (string, error) {
+	users, err := resolveUsers(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	for _, user := range users {
+		isAssigned, err := issues_model.IsUserAssignedToIssue(ctx, ctx.Issue, user)
+		if err != nil {
+			return "", err
+		} else if !isAssigned {
+			continue
+		}
+		if _, _, err := issue_service.ToggleAssignee(ctx, ctx.Issue, ctx.Doer, user.ID); err != nil {
+			return "", err
+		}
+	}
+	return "", nil
+}
+
+type lockCommand struct{}
+
+func (*lockCommand) Name() string                { return "lock" }
+func (*lockCommand) Description() string         { return "Lock the conversation with an optional reason" }
+func (*lockCommand) AccessMode() perm.AccessMode { return perm.AccessModeWrite }
+
+func (*lockCommand) Run(ctx *Context, args []string) (string, error) {
+	if ctx.Issue.IsLocked {
+		return "", ErrUsage{Message: "the conversation is already locked."}
+	}
+	reason := strings.Join(args, " ")
+	if reason != "" {
+		valid := false
+		for _, lockReason := range setting.Repository.Issue.LockReasons {
+			if strings.EqualFold(lockReason, reason) {
+				reason, valid = lockReason, true
+				break
+			}
+		}
+		if !valid {
+			return "", ErrUsage{Message: fmt.Sprintf("the reason must be one of: %s.", strings.Join(setting.Repository.Issue.LockReasons, ", "))}
+		}
+	}
+	return "", issues_model.LockIssue(&issues_model.IssueLockOptions{
+		Doer:   ctx.Doer,
+		Issue:  ctx.Issue,
+		Reason: reason,
+	})
+}
+
+type unlockCommand struct{}
+
+func (*unlockCommand) Name() string                { return "unlock" }
+func (*unlockCommand) Description() string         { return "Unlock the conversation" }
+func (*unlockCommand) AccessMode() perm.AccessMode { return perm.AccessModeWrite }
+
+func (*unlockCommand) Run(ctx *Context, args []string) (string, error) {
+	if !ctx.Issue.IsLocked {
+		return "", ErrUsage{Message: "the conversation is not locked."}
+	}
+	permission, err := access_model.GetUserRepoPermission(ctx, ctx.Repo, ctx.Doer)
+	if err != nil {
+		return "", err
+	}
+	if !ctx.Issue.CanChangeLock(permission, "") {
+		return "", ErrUsage{Message: "only maintainers can unlock this conversation."}
+	}
+	return "", issues_model.UnlockIssue(&issues_model.IssueLockOptions{
+		Doer:  ctx.Doer,
+		Issue: ctx.Issue,
+	})
+}
+
+type milestoneCommand struct{}
+
+func (*milestoneCommand) Name() string                { return "milestone" }
+func (*milestoneCommand) Description() string         { return "Set the milestone, or `clear` it" }
+func (*milestoneCommand) AccessMode() perm.AccessMode { return perm.AccessModeWrite }
+
+func (*milestoneCommand) Run(ctx *Context, args []string) (string, error) {
+	name := strings.Join(args, " ")
+	if name == "" {
+		return "", ErrUsage{Message: "usage: `/milestone <name>` or `/milestone clear`."}
+	}
+
+	var milestoneID int64
+	if name != "clear" {
+		milestone, err := issues_model.GetMilestoneByRepoIDANDName(ctx.Repo.ID, name)
+		if err != nil {
+			if issues_model.IsErrMilestoneNotExist(err) {
+				return "", ErrUsage{Message: fmt.Sprintf("milestone `%s` does not exist.", name)}
+			}
+			return "", err
+		}
+		milestoneID = milestone.ID
+	}
+
+	oldMilestoneID := ctx.Issue.MilestoneID
+	if oldMilestoneID == milestoneID {
+		return "", nil
+	}
+	ctx.Issue.MilestoneID = milestoneID
+	return "", issue_service.ChangeMilestoneAssign(ctx.Issue, ctx.Doer, oldMilestoneID)
+}
+
+type retestCommand struct{}
+
+func (*retestCommand) Name() string                { return "retest" }
+func (*retestCommand) Description() string         { return "Rerun failed pull request jobs" }
+func (*retestCommand) AccessMode() perm.AccessMode { return perm.AccessModeWrite }
+
+func (*retestCommand) Run(ctx *Context, args []string) (string, error) {
+	if !ctx.Issue.IsPull {
+		return "", ErrUsage{Message: "only pull requests can be retested."}
+	}
+	if err := ctx.Issue.LoadPullRequest(ctx); err != nil {
+		return "", err
+	}
+
+	gitRepo, closer, err := git.RepositoryFromContextOrOpen(ctx, ctx.Repo.RepoPath())
+	if err != nil {
+		return "", err
+	}
+	defer closer.Close()
+	headCommitID, err := gitRepo.GetRefCommitID(ctx.Issue.PullRequest.GetGitRefName())
+	if err != nil {
+		return "", err
+	}
+
+	jobs, _, err := actions_model.FindRunJobs(ctx, actions_model.FindRunJobOptions{
+		RepoID:    ctx.Repo.ID,
+		CommitSHA: headCommitID,
+		Statuses:  []actions_model.Status{actions_model.StatusFailure, actions_model.StatusCancelled},
+	})
+	if err != nil {
+		return "", err
+	}
+	if len(jobs) == 0 {
+		return "", ErrUsage{Message: "there are no failed jobs to rerun."}
+	}
+	for _, job := range jobs {
+		if err := actions_service.RerunJob(ctx, job, ctx.Doer); err != nil {
+			return "", err
+		}
+	}
+	return fmt.Sprintf("Rerunning %d job(s).", len(jobs)), nil
+}
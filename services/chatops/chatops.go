@@ -0,0 +1,264 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package chatops
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	chatops_model "code.gitea.io/gitea/models/chatops"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/perm"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/queue"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// Context is passed to a command when it runs
+type Context struct {
+	context.Context
+	Doer    *user_model.User
+	Repo    *repo_model.Repository
+	Issue   *issues_model.Issue
+	Comment *issues_model.Comment
+}
+
+// Command is a slash command which can be used in issue and pull request comments
+type Command interface {
+	Name() string
+	Description() string
+	// AccessMode returns the access to the issues or pull requests unit required to run the command
+	AccessMode() perm.AccessMode
+	// Run executes the command and returns an optional reply which is posted as a comment
+	Run(ctx *Context, args []string) (string, error)
+}
+
+// ErrUsage is returned by commands if they are used incorrectly, the message is replied to the user
+type ErrUsage struct {
+	Message string
+}
+
+func (err ErrUsage) Error() string {
+	return err.Message
+}
+
+var (
+	builtinCommands   = make(map[string]Command)
+	builtinCommandsMu sync.RWMutex
+)
+
+// Register adds a built-in command, it panics if a command with the same name is already registered
+func Register(cmd Command) {
+	builtinCommandsMu.Lock()
+	defer builtinCommandsMu.Unlock()
+	if _, ok := builtinCommands[cmd.Name()]; ok {
+		panic(fmt.Sprintf("chatops command %s is already registered", cmd.Name()))
+	}
+	builtinCommands[cmd.Name()] = cmd
+}
+
+// BuiltinCommands returns the registered built-in commands ordered by name
+func BuiltinCommands() []Command {
+	builtinCommandsMu.RLock()
+	defer builtinCommandsMu.RUnlock()
+	cmds := make([]Command, 0, len(builtinCommands))
+	for _, cmd := range builtinCommands {
+		cmds = append(cmds, cmd)
+	}
+	sort.Slice(cmds, func(i, j int) bool { return cmds[i].Name() < cmds[j].Name() })
+	return cmds
+}
+
+// IsBuiltinCommand checks if a built-in command has the given name
+func IsBuiltinCommand(name string) bool {
+	builtinCommandsMu.RLock()
+	defer builtinCommandsMu.RUnlock()
+	_, ok := builtinCommands[strings.ToLower(name)]
+	return ok
+}
+
+// getCommand returns the built-in or active webhook command with the given name, or nil if there is none
+func getCommand(ctx context.Context, name string) (Command, error) {
+	builtinCommandsMu.RLock()
+	cmd, ok := builtinCommands[name]
+	builtinCommandsMu.RUnlock()
+	if ok {
+		return cmd, nil
+	}
+
+	model, err := chatops_model.GetCommandByName(ctx, name)
+	if err != nil {
+		if chatops_model.IsErrCommandNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !model.IsActive {
+		return nil, nil
+	}
+	return &webhookCommand{model}, nil
+}
+
+// Invocation is a command found in a comment
+type Invocation struct {
+	Name string
+	Args []string
+}
+
+var invocationPattern = regexp.MustCompile(`(?m)^/([a-zA-Z][a-zA-Z0-9_-]*)(?:[ \t]+([^\r\n]*))?\r?$`)
+
+// ParseInvocations returns the commands of all lines of a comment starting with a slash
+func ParseInvocations(content string) []*Invocation {
+	var invocations []*Invocation
+	for _, match := range invocationPattern.FindAllStringSubmatch(content, -1) {
+		invocations = append(invocations, &Invocation{
+			Name: strings.ToLower(match[1]),
+			Args: strings.Fields(match[2]),
+		})
+	}
+	return invocations
+}
+
+type commentRequest struct {
+	CommentID int64
+	DoerID    int64
+}
+
+var commandQueue *queue.WorkerPoolQueue[commentRequest]
+
+// Init starts the command queue and listens for commands in comments
+func Init() error {
+	commandQueue = queue.CreateUniqueQueue("chatops", handler)
+	if commandQueue == nil {
+		return fmt.Errorf("Unable to create chatops Queue")
+	}
+	go graceful.GetManager().RunWithShutdownFns(commandQueue.Run)
+
+	notification.RegisterNotifier(NewNotifier())
+	return nil
+}
+
+func handler(items ...commentRequest) []commentRequest {
+	ctx := graceful.GetManager().ShutdownContext()
+	for _, req := range items {
+		if err := handleComment(ctx, req); err != nil {
+			log.Error("Unable to run the commands of comment %d: %v", req.CommentID, err)
+		}
+	}
+	return nil
+}
+
+func handleComment(ctx context.Context, req commentRequest) error {
+	comment, err := issues_model.GetCommentByID(ctx, req.CommentID)
+	if err != nil {
+		return err
+	}
+	if err := comment.LoadIssue(ctx); err != nil {
+		return err
+	}
+	if err := comment.Issue.LoadRepo(ctx); err != nil {
+		return err
+	}
+	doer, err := user_model.GetUserByID(ctx, req.DoerID)
+	if err != nil {
+		return err
+	}
+
+	cmdCtx := &Context{
+		Context: ctx,
+		Doer:    doer,
+		Repo:    comment.Issue.Repo,
+		Issue:   comment.Issue,
+		Comment: comment,
+	}
+	var replies []string
+	for _, invocation := range ParseInvocations(comment.Content) {
+		if reply := Execute(cmdCtx, invocation); reply != "" {
+			replies = append(replies, reply)
+		}
+	}
+	if len(replies) == 0 {
+		return nil
+	}
+	// the replies are posted by the actions user, whose comments are never parsed for commands,
+	// so replies containing commands, e.g. the messages of webhook commands, can't run them
+	_, err = issue_service.CreateIssueComment(ctx, user_model.NewActionsUser(), cmdCtx.Repo, cmdCtx.Issue, strings.Join(replies, "\n\n"), nil)
+	return err
+}
+
+// Execute checks the permission of the user and runs a command, every execution of a known command is audited.
+// Unknown commands are ignored, as lines starting with a slash are not necessarily meant to be commands.
+func Execute(ctx *Context, invocation *Invocation) (reply string) {
+	cmd, err := getCommand(ctx, invocation.Name)
+	if err != nil {
+		log.Error("getCommand[%s]: %v", invocation.Name, err)
+		return ""
+	} else if cmd == nil {
+		return ""
+	}
+
+	audit := &chatops_model.Audit{
+		RepoID:  ctx.Repo.ID,
+		IssueID: ctx.Issue.ID,
+		DoerID:  ctx.Doer.ID,
+		Command: invocation.Name,
+		Args:    invocation.Args,
+		Status:  chatops_model.AuditStatusSuccess,
+	}
+	if ctx.Comment != nil {
+		audit.CommentID = ctx.Comment.ID
+	}
+	defer func() {
+		if err := chatops_model.InsertAudit(ctx, audit); err != nil {
+			log.Error("InsertAudit: %v", err)
+		}
+	}()
+
+	allowed, err := hasAccess(ctx, cmd.AccessMode())
+	if err != nil {
+		audit.Status, audit.Message = chatops_model.AuditStatusFailed, err.Error()
+		log.Error("hasAccess: %v", err)
+		return ""
+	} else if !allowed {
+		audit.Status = chatops_model.AuditStatusDenied
+		return fmt.Sprintf("@%s is not allowed to run `/%s`.", ctx.Doer.Name, invocation.Name)
+	}
+
+	reply, err = cmd.Run(ctx, invocation.Args)
+	if err != nil {
+		audit.Status, audit.Message = chatops_model.AuditStatusFailed, err.Error()
+		if usageErr, ok := err.(ErrUsage); ok {
+			return fmt.Sprintf("`/%s`: %s", invocation.Name, usageErr.Message)
+		}
+		log.Error("Running command %s on %s#%d failed: %v", invocation.Name, ctx.Repo.FullName(), ctx.Issue.Index, err)
+		return fmt.Sprintf("`/%s` failed.", invocation.Name)
+	}
+	audit.Message = reply
+	return reply
+}
+
+func hasAccess(ctx *Context, mode perm.AccessMode) (bool, error) {
+	if mode <= perm.AccessModeNone {
+		return true, nil
+	}
+	permission, err := access_model.GetUserRepoPermission(ctx, ctx.Repo, ctx.Doer)
+	if err != nil {
+		return false, err
+	}
+	unitType := unit.TypeIssues
+	if ctx.Issue.IsPull {
+		unitType = unit.TypePullRequests
+	}
+	return permission.UnitAccessMode(unitType) >= mode, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package chatops
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	issue_service "code.gitea.io/gitea/services/issue"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseInvocations(t *testing.T) {
+	assert.Empty(t, ParseInvocations("LGTM"))
+	assert.Empty(t, ParseInvocations("see /usr/bin"))
+	assert.Empty(t, ParseInvocations("/usr/bin/env"))
+
+	invocations := ParseInvocations("Thanks!\r\n/assign @user2 me\r\n/Lock  Off-topic\n/retest")
+	if assert.Len(t, invocations, 3) {
+		assert.Equal(t, &Invocation{Name: "assign", Args: []string{"@user2", "me"}}, invocations[0])
+		assert.Equal(t, &Invocation{Name: "lock", Args: []string{"Off-topic"}}, invocations[1])
+		assert.Equal(t, &Invocation{Name: "retest", Args: []string{}}, invocations[2])
+	}
+}
+
+func TestBuiltinCommands(t *testing.T) {
+	names := make([]string, 0, 6)
+	for _, cmd := range BuiltinCommands() {
+		names = append(names, cmd.Name())
+	}
+	assert.Equal(t, []string{"assign", "lock", "milestone", "retest", "unassign", "unlock"}, names)
+	assert.True(t, IsBuiltinCommand("Assign"))
+	assert.False(t, IsBuiltinCommand("deploy"))
+}
+
+// commandReplyCommand replies with commands, like a webhook command could
+type commandReplyCommand struct{}
+
+func (*commandReplyCommand) Name() string                { return "test-command-reply" }
+func (*commandReplyCommand) Description() string         { return "replies with commands" }
+func (*commandReplyCommand) AccessMode() perm.AccessMode { return perm.AccessModeRead }
+func (*commandReplyCommand) Run(ctx *Context, args []string) (string, error) {
+	return "/lock spam\n/test-command-reply", nil
+}
+
+func TestReplyDoesNotRunCommands(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	Register(&commandReplyCommand{})
+
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: 1})
+
+	comment, err := issue_service.CreateIssueComment(db.DefaultContext, doer, repo, issue, "/test-command-reply", nil)
+	assert.NoError(t, err)
+	assert.True(t, mayInvokeCommands(doer, comment))
+	assert.NoError(t, handleComment(db.DefaultContext, commentRequest{CommentID: comment.ID, DoerID: doer.ID}))
+
+	reply := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: issue.ID, Content: "/lock spam\n/test-command-reply"})
+	assert.EqualValues(t, user_model.ActionsUserID, reply.PosterID)
+	assert.False(t, mayInvokeCommands(user_model.NewActionsUser(), reply))
+
+	issue = unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: issue.ID})
+	assert.False(t, issue.IsLocked)
+	unittest.AssertCount(t, &issues_model.Comment{IssueID: issue.ID, Content: "/lock spam\n/test-command-reply"}, 1)
+}
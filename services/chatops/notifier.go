@@ -0,0 +1,48 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package chatops
+
+import (
+	"context"
+	"errors"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/queue"
+)
+
+type chatopsNotifier struct {
+	base.NullNotifier
+}
+
+var _ base.Notifier = &chatopsNotifier{}
+
+// NewNotifier create a new chatopsNotifier notifier
+func NewNotifier() base.Notifier {
+	return &chatopsNotifier{}
+}
+
+// NotifyCreateIssueComment schedules the commands of a new comment
+func (*chatopsNotifier) NotifyCreateIssueComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository,
+	issue *issues_model.Issue, comment *issues_model.Comment, mentions []*user_model.User,
+) {
+	if !mayInvokeCommands(doer, comment) {
+		return
+	}
+	if err := commandQueue.Push(commentRequest{CommentID: comment.ID, DoerID: doer.ID}); err != nil && !errors.Is(err, queue.ErrAlreadyInQueue) {
+		log.Error("Unable to add the commands of comment %d to the queue: %v", comment.ID, err)
+	}
+}
+
+// mayInvokeCommands checks if a new comment is parsed for commands. The comments of the ghost user and of the
+// actions user, which posts the replies of the commands, are never parsed.
+func mayInvokeCommands(doer *user_model.User, comment *issues_model.Comment) bool {
+	if doer == nil || doer.IsGhost() || doer.IsActions() || comment.Type != issues_model.CommentTypeComment {
+		return false
+	}
+	return len(ParseInvocations(comment.Content)) > 0
+}
@@ -0,0 +1,94 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package chatops
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+
+	chatops_model "code.gitea.io/gitea/models/chatops"
+	"code.gitea.io/gitea/models/perm"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/convert"
+	webhook_service "code.gitea.io/gitea/services/webhook"
+)
+
+// maxResponseSize limits how much of the webhook response is read
+const maxResponseSize = 64 * 1024
+
+// webhookCommand is a command registered by a site administrator which is delegated to a webhook
+type webhookCommand struct {
+	model *chatops_model.Command
+}
+
+func (c *webhookCommand) Name() string                { return c.model.Name }
+func (c *webhookCommand) Description() string         { return c.model.Description }
+func (c *webhookCommand) AccessMode() perm.AccessMode { return c.model.AccessMode }
+
+type webhookCommandResponse struct {
+	Message string `json:"message"`
+}
+
+// Run posts the command to the webhook, the message of a JSON response is used as the reply
+func (c *webhookCommand) Run(ctx *Context, args []string) (string, error) {
+	payload := &api.ChatOpsCommandPayload{
+		Command:    c.model.Name,
+		Args:       args,
+		Repository: convert.ToRepo(ctx, ctx.Repo, perm.AccessModeNone),
+		Issue:      convert.ToAPIIssue(ctx, ctx.Issue),
+		Sender:     convert.ToUser(ctx, ctx.Doer, nil),
+	}
+	if ctx.Comment != nil {
+		payload.Comment = convert.ToComment(ctx, ctx.Comment)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.model.URL, bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Gitea "+setting.AppVer)
+	req.Header.Set("X-Gitea-Event", "chatops_command")
+	if c.model.Secret != "" {
+		sig := hmac.New(sha256.New, []byte(c.model.Secret))
+		_, _ = sig.Write(body)
+		req.Header.Set("X-Gitea-Signature", hex.EncodeToString(sig.Sum(nil)))
+	}
+
+	resp, err := webhook_service.DoRequest(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	content, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("webhook of command %s responded with status %d", c.model.Name, resp.StatusCode)
+	}
+
+	var result webhookCommandResponse
+	if len(bytes.TrimSpace(content)) == 0 {
+		return "", nil
+	}
+	if err := json.Unmarshal(content, &result); err != nil {
+		log.Debug("Ignoring non-JSON response of the webhook of command %s: %v", c.model.Name, err)
+		return "", nil
+	}
+	return result.Message, nil
+}
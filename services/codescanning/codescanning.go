@@ -0,0 +1,243 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package codescanning
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	codescanning_model "code.gitea.io/gitea/models/codescanning"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/sarif"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// MaxSARIFSize is the maximal size of an uncompressed SARIF file
+const MaxSARIFSize = 20 * 1024 * 1024
+
+var (
+	// ErrSARIFTooLarge is returned if the uncompressed SARIF file exceeds MaxSARIFSize
+	ErrSARIFTooLarge = errors.New("SARIF file is too large")
+	// ErrSARIFUnreadable is returned if the SARIF file can't be read, e.g. because its compression is corrupt
+	ErrSARIFUnreadable = errors.New("SARIF file can't be read")
+)
+
+// UploadSARIF processes a SARIF report for a commit of a ref. New findings create alerts, known findings
+// update their alert and open alerts of the reporting tools which are no longer found are marked as fixed.
+func UploadSARIF(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, ref, commitSHA string, r io.Reader) ([]*codescanning_model.Analysis, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxSARIFSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrSARIFUnreadable, err)
+	} else if len(data) > MaxSARIFSize {
+		return nil, ErrSARIFTooLarge
+	}
+
+	log, err := sarif.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	byTool := make(map[string][]*sarif.Finding)
+	var tools []string
+	for _, run := range log.Runs {
+		if _, ok := byTool[run.Tool.Driver.Name]; !ok {
+			tools = append(tools, run.Tool.Driver.Name)
+			byTool[run.Tool.Driver.Name] = nil
+		}
+	}
+	for _, f := range log.Findings() {
+		byTool[f.Tool] = append(byTool[f.Tool], f)
+	}
+
+	analyses := make([]*codescanning_model.Analysis, 0, len(tools))
+	return analyses, db.WithTx(ctx, func(ctx context.Context) error {
+		for _, tool := range tools {
+			if err := processToolFindings(ctx, repo.ID, ref, commitSHA, tool, byTool[tool]); err != nil {
+				return fmt.Errorf("processToolFindings[%s]: %w", tool, err)
+			}
+			analysis := &codescanning_model.Analysis{
+				RepoID:       repo.ID,
+				Ref:          ref,
+				CommitSHA:    commitSHA,
+				Tool:         tool,
+				ResultsCount: len(byTool[tool]),
+				UploaderID:   doer.ID,
+			}
+			if err := codescanning_model.InsertAnalysis(ctx, analysis); err != nil {
+				return err
+			}
+			analyses = append(analyses, analysis)
+		}
+		return nil
+	})
+}
+
+func processToolFindings(ctx context.Context, repoID int64, ref, commitSHA, tool string, findings []*sarif.Finding) error {
+	existing, _, err := codescanning_model.FindAlerts(ctx, codescanning_model.FindAlertsOptions{
+		RepoID: repoID,
+		Ref:    ref,
+		Tool:   tool,
+	})
+	if err != nil {
+		return err
+	}
+	alerts := make(map[string]*codescanning_model.Alert, len(existing))
+	for _, a := range existing {
+		alerts[a.Fingerprint] = a
+	}
+
+	seen := make(map[string]bool, len(findings))
+	for _, f := range findings {
+		if seen[f.Fingerprint] {
+			continue
+		}
+		seen[f.Fingerprint] = true
+
+		alert, ok := alerts[f.Fingerprint]
+		if !ok {
+			if err := codescanning_model.InsertAlert(ctx, &codescanning_model.Alert{
+				RepoID:          repoID,
+				Ref:             ref,
+				Fingerprint:     f.Fingerprint,
+				Tool:            tool,
+				RuleID:          f.RuleID,
+				RuleDescription: f.Description,
+				Severity:        f.Severity,
+				Message:         f.Message,
+				Path:            f.Path,
+				StartLine:       f.StartLine,
+				EndLine:         f.EndLine,
+				CommitSHA:       commitSHA,
+				State:           codescanning_model.AlertStateOpen,
+			}); err != nil {
+				return err
+			}
+			continue
+		}
+
+		alert.RuleDescription = f.Description
+		alert.Severity = f.Severity
+		alert.Message = f.Message
+		alert.StartLine = f.StartLine
+		alert.EndLine = f.EndLine
+		alert.CommitSHA = commitSHA
+		if alert.State == codescanning_model.AlertStateFixed {
+			alert.State = codescanning_model.AlertStateOpen
+			alert.FixedUnix = 0
+		}
+		if err := codescanning_model.UpdateAlert(ctx, alert, "rule_description", "severity", "message", "start_line", "end_line", "commit_sha", "state", "fixed_unix"); err != nil {
+			return err
+		}
+	}
+
+	for _, alert := range existing {
+		if seen[alert.Fingerprint] || alert.State != codescanning_model.AlertStateOpen {
+			continue
+		}
+		alert.State = codescanning_model.AlertStateFixed
+		alert.FixedUnix = timeutil.TimeStampNow()
+		alert.CommitSHA = commitSHA
+		if err := codescanning_model.UpdateAlert(ctx, alert, "state", "fixed_unix", "commit_sha"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// PullRequestRefs returns the refs whose analyses belong to the head of a pull request
+func PullRequestRefs(pr *issues_model.PullRequest) []string {
+	refs := []string{pr.GetGitRefName()}
+	if pr.HeadRepoID == pr.BaseRepoID {
+		refs = append(refs, git.BranchPrefix+pr.HeadBranch)
+	}
+	return refs
+}
+
+// GetPullRequestAlerts returns the open alerts reported by the analyses of the head commit of a pull request.
+// The alerts of tools whose latest analysis is of an older commit are left out, the head commit superseded them.
+func GetPullRequestAlerts(ctx context.Context, pr *issues_model.PullRequest) ([]*codescanning_model.Alert, error) {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return nil, err
+	}
+	gitRepo, closer, err := git.RepositoryFromContextOrOpen(ctx, pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, err
+	}
+
+	for _, ref := range PullRequestRefs(pr) {
+		analyses, err := codescanning_model.GetLatestAnalyses(ctx, pr.BaseRepoID, ref)
+		if err != nil {
+			return nil, err
+		}
+		headTools := make(map[string]bool, len(analyses))
+		for _, analysis := range analyses {
+			if analysis.CommitSHA == headCommitID {
+				headTools[analysis.Tool] = true
+			}
+		}
+		if len(headTools) == 0 {
+			continue
+		}
+
+		refAlerts, _, err := codescanning_model.FindAlerts(ctx, codescanning_model.FindAlertsOptions{
+			RepoID: pr.BaseRepoID,
+			Ref:    ref,
+			State:  codescanning_model.AlertStateOpen,
+		})
+		if err != nil {
+			return nil, err
+		}
+		alerts := make([]*codescanning_model.Alert, 0, len(refAlerts))
+		for _, alert := range refAlerts {
+			if headTools[alert.Tool] {
+				alerts = append(alerts, alert)
+			}
+		}
+		return alerts, nil
+	}
+	return nil, nil
+}
+
+// NewHighSeverityAlerts returns the open alerts of high or critical severity of the head commit of a pull request
+// which are neither open nor dismissed on the base branch.
+func NewHighSeverityAlerts(ctx context.Context, pr *issues_model.PullRequest) ([]*codescanning_model.Alert, error) {
+	headAlerts, err := GetPullRequestAlerts(ctx, pr)
+	if err != nil || len(headAlerts) == 0 {
+		return nil, err
+	}
+
+	baseAlerts, _, err := codescanning_model.FindAlerts(ctx, codescanning_model.FindAlertsOptions{
+		RepoID: pr.BaseRepoID,
+		Ref:    git.BranchPrefix + pr.BaseBranch,
+	})
+	if err != nil {
+		return nil, err
+	}
+	known := make(map[string]bool, len(baseAlerts))
+	for _, a := range baseAlerts {
+		if a.State != codescanning_model.AlertStateFixed {
+			known[a.Fingerprint] = true
+		}
+	}
+
+	var alerts []*codescanning_model.Alert
+	for _, a := range headAlerts {
+		if a.IsHighSeverity() && !known[a.Fingerprint] {
+			alerts = append(alerts, a)
+		}
+	}
+	return alerts, nil
+}
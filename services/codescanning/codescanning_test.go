@@ -0,0 +1,93 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package codescanning
+
+import (
+	"strings"
+	"testing"
+
+	codescanning_model "code.gitea.io/gitea/models/codescanning"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func sarifWithResults(results ...string) string {
+	return `{"version": "2.1.0", "runs": [{"tool": {"driver": {"name": "linter"}}, "results": [` + strings.Join(results, ",") + `]}]}`
+}
+
+func sarifResult(rule, path, fingerprint string) string {
+	return `{"ruleId": "` + rule + `", "level": "error", "message": {"text": "bad"}, ` +
+		`"locations": [{"physicalLocation": {"artifactLocation": {"uri": "` + path + `"}, "region": {"startLine": 1}}}], ` +
+		`"partialFingerprints": {"hash": "` + fingerprint + `"}}`
+}
+
+func TestUploadSARIF(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	const ref = "refs/heads/master"
+
+	analyses, err := UploadSARIF(db.DefaultContext, repo, doer, ref, "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+		strings.NewReader(sarifWithResults(sarifResult("R1", "a.go", "1"), sarifResult("R2", "b.go", "2"))))
+	assert.NoError(t, err)
+	assert.Len(t, analyses, 1)
+	assert.Equal(t, 2, analyses[0].ResultsCount)
+
+	alerts, count, err := codescanning_model.FindAlerts(db.DefaultContext, codescanning_model.FindAlertsOptions{RepoID: repo.ID, Ref: ref, State: codescanning_model.AlertStateOpen})
+	assert.NoError(t, err)
+	assert.EqualValues(t, 2, count)
+	assert.True(t, alerts[0].IsHighSeverity())
+
+	// the second finding is gone, so its alert is fixed
+	_, err = UploadSARIF(db.DefaultContext, repo, doer, ref, "2a47ca4b614a9f5a43abbd5ad851a54a616ffee6",
+		strings.NewReader(sarifWithResults(sarifResult("R1", "a.go", "1"))))
+	assert.NoError(t, err)
+
+	fixed := unittest.AssertExistsAndLoadBean(t, &codescanning_model.Alert{RepoID: repo.ID, RuleID: "R2"})
+	assert.Equal(t, codescanning_model.AlertStateFixed, fixed.State)
+	assert.NotZero(t, fixed.FixedUnix)
+
+	open := unittest.AssertExistsAndLoadBean(t, &codescanning_model.Alert{RepoID: repo.ID, RuleID: "R1"})
+	assert.Equal(t, codescanning_model.AlertStateOpen, open.State)
+	assert.Equal(t, "2a47ca4b614a9f5a43abbd5ad851a54a616ffee6", open.CommitSHA)
+
+	// the finding reappears, so the alert is reopened
+	_, err = UploadSARIF(db.DefaultContext, repo, doer, ref, "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+		strings.NewReader(sarifWithResults(sarifResult("R1", "a.go", "1"), sarifResult("R2", "b.go", "2"))))
+	assert.NoError(t, err)
+	fixed = unittest.AssertExistsAndLoadBean(t, &codescanning_model.Alert{ID: fixed.ID})
+	assert.Equal(t, codescanning_model.AlertStateOpen, fixed.State)
+	unittest.AssertCount(t, &codescanning_model.Alert{RepoID: repo.ID}, 2)
+}
+
+func TestGetPullRequestAlerts(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	pr := unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: 1})
+
+	// the analysis of an older commit was superseded by the head commit
+	_, err := UploadSARIF(db.DefaultContext, repo, doer, pr.GetGitRefName(), "2a47ca4b614a9f5a43abbd5ad851a54a616ffee6",
+		strings.NewReader(sarifWithResults(sarifResult("R1", "a.go", "1"))))
+	assert.NoError(t, err)
+	alerts, err := GetPullRequestAlerts(db.DefaultContext, pr)
+	assert.NoError(t, err)
+	assert.Empty(t, alerts)
+
+	_, err = UploadSARIF(db.DefaultContext, repo, doer, pr.GetGitRefName(), "4a357436d925b5c974181ff12a994538ddc5a269",
+		strings.NewReader(sarifWithResults(sarifResult("R1", "a.go", "1"))))
+	assert.NoError(t, err)
+	alerts, err = GetPullRequestAlerts(db.DefaultContext, pr)
+	assert.NoError(t, err)
+	if assert.Len(t, alerts, 1) {
+		assert.Equal(t, "R1", alerts[0].RuleID)
+	}
+}
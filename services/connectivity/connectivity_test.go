@@ -0,0 +1,54 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package connectivity
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/gitea/services/auth/source/oauth2"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReport(t *testing.T) {
+	r := &Report{}
+	r.run("first", func() (string, error) { return "ok", nil })
+	assert.True(t, r.Success())
+	r.run("second", func() (string, error) { return "", errors.New("broken") })
+	r.run("third", func() (string, error) { return "ok", nil })
+
+	assert.False(t, r.Success())
+	if assert.Len(t, r.Steps, 3) {
+		assert.Equal(t, StepSuccess, r.Steps[0].Status)
+		assert.Equal(t, "ok", r.Steps[0].Message)
+		assert.Equal(t, StepFailure, r.Steps[1].Status)
+		assert.Equal(t, "broken", r.Steps[1].Message)
+		assert.Equal(t, StepSkipped, r.Steps[2].Status)
+	}
+}
+
+func TestExchangeInvalidCode(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, r.ParseForm())
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		if r.PostForm.Get("client_secret") == "secret" {
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+		} else {
+			_, _ = w.Write([]byte(`{"error":"invalid_client","error_description":"unknown client"}`))
+		}
+	}))
+	defer server.Close()
+
+	message, err := exchangeInvalidCode(context.Background(), server.URL, &oauth2.Source{ClientID: "id", ClientSecret: "secret"}, "http://localhost/callback")
+	assert.NoError(t, err)
+	assert.Equal(t, "the client credentials were accepted", message)
+
+	_, err = exchangeInvalidCode(context.Background(), server.URL, &oauth2.Source{ClientID: "id", ClientSecret: "wrong"}, "http://localhost/callback")
+	assert.EqualError(t, err, "the client credentials were rejected: invalid_client unknown client")
+}
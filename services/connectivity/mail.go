@@ -0,0 +1,46 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package connectivity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// TestMail sends a test mail to the address, the providers of the mailer are tried in the same order as for the
+// other mails and each attempt is a step of the report
+func TestMail(ctx context.Context, email string) *Report {
+	r := &Report{}
+	r.run("configuration", func() (string, error) {
+		if setting.MailService == nil {
+			return "", errors.New("the mailer is not enabled")
+		}
+		return fmt.Sprintf("sending from %s", setting.MailService.FromEmail), nil
+	})
+	if !r.Success() {
+		return r
+	}
+
+	for _, attempt := range mailer.SendTestMailThroughProviders(ctx, email) {
+		step := &Step{
+			Name:     "provider " + attempt.Provider,
+			Status:   StepSuccess,
+			Message:  fmt.Sprintf("test mail sent to %s", email),
+			Duration: attempt.Duration,
+		}
+		if attempt.ProviderMessageID != "" {
+			step.Message += fmt.Sprintf(" with message id %s", attempt.ProviderMessageID)
+		}
+		if attempt.Err != nil {
+			step.Status = StepFailure
+			step.Message = attempt.Err.Error()
+		}
+		r.Steps = append(r.Steps, step)
+	}
+	return r
+}
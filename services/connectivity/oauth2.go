@@ -0,0 +1,110 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package connectivity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/auth/source/oauth2"
+)
+
+var oauth2Client = &http.Client{
+	Timeout: 30 * time.Second,
+	Transport: &http.Transport{
+		Proxy: proxy.Proxy(),
+	},
+}
+
+// TestOAuth2Source discovers the token endpoint of an OAuth2 authentication source and exchanges an invalid
+// authorization code there. The provider rejects the code, but it tells whether it accepted the client credentials.
+func TestOAuth2Source(ctx context.Context, source *auth_model.Source) (*Report, error) {
+	cfg, ok := source.Cfg.(*oauth2.Source)
+	if !ok {
+		return nil, util.NewInvalidArgumentErrorf("authentication source %q is not an OAuth2 source", source.Name)
+	}
+
+	r := &Report{}
+	r.run("configuration", func() (string, error) {
+		if !source.IsActive {
+			return "", errors.New("the authentication source is not active")
+		}
+		return fmt.Sprintf("provider %s", cfg.Provider), nil
+	})
+
+	var tokenURL string
+	r.run("discovery", func() (message string, err error) {
+		tokenURL, err = cfg.TokenEndpoint(ctx, oauth2Client)
+		if err != nil {
+			return "", err
+		}
+		if tokenURL == "" {
+			return "the token endpoint is built into the provider", nil
+		}
+		return fmt.Sprintf("token endpoint %s", tokenURL), nil
+	})
+	if r.Success() && tokenURL == "" {
+		r.skip("token", "the token endpoint of the provider is not configurable")
+		return r, nil
+	}
+
+	r.run("token", func() (string, error) {
+		return exchangeInvalidCode(ctx, tokenURL, cfg, oauth2.CallbackURL(source.Name))
+	})
+	return r, nil
+}
+
+// exchangeInvalidCode requests a token for a code which can't be valid, providers answer "invalid_grant"
+// if they accepted the client credentials and "invalid_client" otherwise
+func exchangeInvalidCode(ctx context.Context, tokenURL string, cfg *oauth2.Source, callbackURL string) (string, error) {
+	form := url.Values{
+		"grant_type":    {"authorization_code"},
+		"code":          {"gitea-connectivity-test"},
+		"redirect_uri":  {callbackURL},
+		"client_id":     {cfg.ClientID},
+		"client_secret": {cfg.ClientSecret},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := oauth2Client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64*1024))
+	if err != nil {
+		return "", err
+	}
+
+	var tokenErr struct {
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+	if err := json.Unmarshal(body, &tokenErr); err != nil || tokenErr.Error == "" {
+		return "", fmt.Errorf("unexpected response of the token endpoint with status %d", resp.StatusCode)
+	}
+	description := strings.TrimSpace(tokenErr.Error + " " + tokenErr.ErrorDescription)
+	switch tokenErr.Error {
+	case "invalid_grant":
+		return "the client credentials were accepted", nil
+	case "invalid_client", "unauthorized_client":
+		return "", fmt.Errorf("the client credentials were rejected: %s", description)
+	}
+	return "", fmt.Errorf("the token endpoint responded with status %d: %s", resp.StatusCode, description)
+}
@@ -0,0 +1,64 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+// Package connectivity tests the integrations configured by the administrators, like the mailer, the OAuth2
+// authentication sources and the webhooks, and reports the outcome of each step of the test.
+package connectivity
+
+import (
+	"time"
+)
+
+// StepStatus is the outcome of a step of a test
+type StepStatus string
+
+// The outcomes of a step
+const (
+	StepSuccess StepStatus = "success"
+	StepFailure StepStatus = "failure"
+	StepSkipped StepStatus = "skipped"
+)
+
+// Step is a step of a test
+type Step struct {
+	Name     string
+	Status   StepStatus
+	Message  string
+	Duration time.Duration
+}
+
+// Report holds the steps of a test, the steps after a failed step are skipped
+type Report struct {
+	Steps []*Step
+}
+
+// Success returns true if no step failed
+func (r *Report) Success() bool {
+	for _, step := range r.Steps {
+		if step.Status == StepFailure {
+			return false
+		}
+	}
+	return true
+}
+
+// run runs the step unless a previous step failed, fn returns the message of the step
+func (r *Report) run(name string, fn func() (string, error)) {
+	if !r.Success() {
+		r.skip(name, "a previous step failed")
+		return
+	}
+
+	start := time.Now()
+	message, err := fn()
+	step := &Step{Name: name, Status: StepSuccess, Message: message, Duration: time.Since(start)}
+	if err != nil {
+		step.Status = StepFailure
+		step.Message = err.Error()
+	}
+	r.Steps = append(r.Steps, step)
+}
+
+func (r *Report) skip(name, reason string) {
+	r.Steps = append(r.Steps, &Step{Name: name, Status: StepSkipped, Message: reason})
+}
@@ -0,0 +1,89 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package connectivity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/models/perm"
+	user_model "code.gitea.io/gitea/models/user"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/services/convert"
+	webhook_service "code.gitea.io/gitea/services/webhook"
+)
+
+// TestWebhook delivers a push event of a fake commit to the webhook, signed like the other deliveries,
+// and reports the response of the receiver
+func TestWebhook(ctx context.Context, w *webhook_model.Webhook, doer *user_model.User) *Report {
+	r := &Report{}
+	r.run("configuration", func() (string, error) {
+		if setting.DisableWebhooks {
+			return "", errors.New("webhooks are disabled")
+		}
+		if !w.IsActive {
+			return "", errors.New("the webhook is not active")
+		}
+		if w.Secret == "" {
+			return fmt.Sprintf("%s webhook without secret, the deliveries are not signed", w.Type), nil
+		}
+		return fmt.Sprintf("%s webhook with secret, the deliveries are signed", w.Type), nil
+	})
+
+	r.run("delivery", func() (string, error) {
+		task, err := webhook_service.DeliverTestWebhook(ctx, w, webhook_module.HookEventPush, testPushPayload(ctx, doer))
+		if err != nil {
+			return "", err
+		}
+		if task.ResponseInfo == nil {
+			return "", errors.New("the webhook was not delivered")
+		}
+		if !task.IsSucceed {
+			return "", fmt.Errorf("the receiver responded with status %d: %s", task.ResponseInfo.Status, task.ResponseInfo.Body)
+		}
+		return fmt.Sprintf("delivery %s, the receiver responded with status %d", task.UUID, task.ResponseInfo.Status), nil
+	})
+	return r
+}
+
+func testPushPayload(ctx context.Context, doer *user_model.User) *api.PushPayload {
+	ghost := user_model.NewGhostUser()
+	apiCommit := &api.PayloadCommit{
+		ID:      git.EmptySHA,
+		Message: "This is a fake commit",
+		URL:     setting.AppURL,
+		Author: &api.PayloadUser{
+			Name:  ghost.Name,
+			Email: ghost.GetEmail(),
+		},
+		Committer: &api.PayloadUser{
+			Name:  ghost.Name,
+			Email: ghost.GetEmail(),
+		},
+	}
+	apiUser := convert.ToUserWithAccessMode(ctx, doer, perm.AccessModeNone)
+	return &api.PushPayload{
+		Ref:          git.BranchPrefix + setting.Repository.DefaultBranch,
+		Before:       git.EmptySHA,
+		After:        git.EmptySHA,
+		CompareURL:   setting.AppURL,
+		Commits:      []*api.PayloadCommit{apiCommit},
+		TotalCommits: 1,
+		HeadCommit:   apiCommit,
+		Repo: &api.Repository{
+			Name:          "connectivity-test",
+			FullName:      doer.Name + "/connectivity-test",
+			Owner:         apiUser,
+			HTMLURL:       setting.AppURL,
+			DefaultBranch: setting.Repository.DefaultBranch,
+		},
+		Pusher: apiUser,
+		Sender: apiUser,
+	}
+}
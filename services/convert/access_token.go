@@ -0,0 +1,32 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	auth_model "code.gitea.io/gitea/models/auth"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToInstanceAccessToken converts an access token of any user to API format, without the token itself
+func ToInstanceAccessToken(t *auth_model.AccessToken, owner *user_model.User) *api.InstanceAccessToken {
+	result := &api.InstanceAccessToken{
+		ID:             t.ID,
+		Name:           t.Name,
+		TokenLastEight: t.TokenLastEight,
+		Scopes:         t.Scope.StringSlice(),
+		UserID:         t.UID,
+		Created:        t.CreatedUnix.AsTime(),
+	}
+	if owner != nil {
+		result.UserName = owner.Name
+	}
+	if t.HasUsed {
+		result.LastUsed = t.UpdatedUnix.AsTimePtr()
+	}
+	if expires := t.ExpiresUnix(); expires != 0 {
+		result.Expires = expires.AsTimePtr()
+	}
+	return result
+}
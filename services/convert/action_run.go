@@ -0,0 +1,30 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToActionRun convert actions_model.ActionRun to api.ActionRun, its attributes must be loaded
+func ToActionRun(ctx context.Context, run *actions_model.ActionRun) *api.ActionRun {
+	return &api.ActionRun{
+		ID:           run.ID,
+		Index:        run.Index,
+		Title:        run.Title,
+		WorkflowID:   run.WorkflowID,
+		Event:        string(run.Event),
+		Ref:          run.Ref,
+		CommitSHA:    run.CommitSHA,
+		TriggerUser:  ToUser(ctx, run.TriggerUser, nil),
+		Status:       run.Status.String(),
+		IsFork:       run.IsForkPullRequest,
+		NeedApproval: run.NeedApproval,
+		HTMLURL:      run.HTMLURL(),
+		Created:      run.Created.AsTime(),
+	}
+}
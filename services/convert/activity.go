@@ -9,6 +9,7 @@ import (
 	activities_model "code.gitea.io/gitea/models/activities"
 	perm_model "code.gitea.io/gitea/models/perm"
 	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
 	api "code.gitea.io/gitea/modules/structs"
@@ -20,7 +21,10 @@ func ToActivity(ctx context.Context, ac *activities_model.Action, doer *user_mod
 		log.Error("GetUserRepoPermission[%d]: %v", ac.RepoID, err)
 		p.AccessMode = perm_model.AccessModeNone
 	}
+	return toActivity(ctx, ac, doer, p.AccessMode)
+}
 
+func toActivity(ctx context.Context, ac *activities_model.Action, doer *user_model.User, mode perm_model.AccessMode) *api.Activity {
 	result := &api.Activity{
 		ID:        ac.ID,
 		UserID:    ac.UserID,
@@ -28,7 +32,7 @@ func ToActivity(ctx context.Context, ac *activities_model.Action, doer *user_mod
 		ActUserID: ac.ActUserID,
 		ActUser:   ToUser(ctx, ac.ActUser, doer),
 		RepoID:    ac.RepoID,
-		Repo:      ToRepo(ctx, ac.Repo, p.AccessMode),
+		Repo:      ToRepo(ctx, ac.Repo, mode),
 		RefName:   ac.RefName,
 		IsPrivate: ac.IsPrivate,
 		Content:   ac.Content,
@@ -44,9 +48,22 @@ func ToActivity(ctx context.Context, ac *activities_model.Action, doer *user_mod
 }
 
 func ToActivities(ctx context.Context, al activities_model.ActionList, doer *user_model.User) []*api.Activity {
+	repos := make(repo_model.RepositoryList, 0, len(al))
+	for _, ac := range al {
+		repos = append(repos, ac.Repo)
+	}
+	perms, err := access_model.GetUserRepoPermissions(ctx, repos, doer)
+	if err != nil {
+		log.Error("GetUserRepoPermissions: %v", err)
+	}
+
 	result := make([]*api.Activity, 0, len(al))
 	for _, ac := range al {
-		result = append(result, ToActivity(ctx, ac, doer))
+		mode := perm_model.AccessModeNone
+		if p, ok := perms[ac.RepoID]; ok {
+			mode = p.AccessMode
+		}
+		result = append(result, toActivity(ctx, ac, doer, mode))
 	}
 	return result
 }
@@ -0,0 +1,56 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToArchivePolicy converts an archive policy to API format
+func ToArchivePolicy(p *organization.ArchivePolicy) *api.ArchivePolicy {
+	topics := p.ExemptTopics
+	if topics == nil {
+		topics = []string{}
+	}
+	return &api.ArchivePolicy{
+		InactiveMonths: p.InactiveMonths,
+		NoticeDays:     p.NoticeDays,
+		ExemptTopics:   topics,
+		Updated:        p.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToUpcomingArchival converts an archive notice to API format
+func ToUpcomingArchival(repo *repo_model.Repository, n *repo_model.ArchiveNotice) *api.UpcomingArchival {
+	result := &api.UpcomingArchival{
+		RepoID:       n.RepoID,
+		LastActivity: n.LastActivityUnix.AsTime(),
+		Notified:     n.CreatedUnix.AsTime(),
+		Archive:      n.ArchiveUnix.AsTime(),
+	}
+	if repo != nil {
+		result.FullName = repo.FullName()
+	}
+	return result
+}
+
+// ToUpcomingArchivals converts archive notices to API format
+func ToUpcomingArchivals(notices []*repo_model.ArchiveNotice) ([]*api.UpcomingArchival, error) {
+	repoIDs := make([]int64, 0, len(notices))
+	for _, n := range notices {
+		repoIDs = append(repoIDs, n.RepoID)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*api.UpcomingArchival, 0, len(notices))
+	for _, n := range notices {
+		result = append(result, ToUpcomingArchival(repos[n.RepoID], n))
+	}
+	return result, nil
+}
@@ -0,0 +1,26 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models/organization"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToAttachmentPolicy converts an attachment policy to API format
+func ToAttachmentPolicy(p *organization.AttachmentPolicy) *api.AttachmentPolicy {
+	rules := make([]*api.AttachmentTypeRule, 0, len(p.Rules))
+	for _, rule := range p.Rules {
+		rules = append(rules, &api.AttachmentTypeRule{
+			Type:    rule.Type,
+			MaxSize: rule.MaxSize,
+		})
+	}
+	return &api.AttachmentPolicy{
+		Rules:             rules,
+		OptimizeImages:    p.OptimizeImages,
+		MaxImageDimension: p.MaxImageDimension,
+		Updated:           p.UpdatedUnix.AsTime(),
+	}
+}
@@ -0,0 +1,17 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models/organization"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToAvatarPolicy converts an avatar policy to API format
+func ToAvatarPolicy(p *organization.AvatarPolicy) *api.AvatarPolicy {
+	return &api.AvatarPolicy{
+		EnforceDefaultAvatars: p.EnforceDefault,
+		Updated:               p.UpdatedUnix.AsTime(),
+	}
+}
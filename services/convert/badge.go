@@ -0,0 +1,19 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	git_model "code.gitea.io/gitea/models/git"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToCoverageReport converts a coverage report to its API format
+func ToCoverageReport(r *git_model.CoverageReport) *api.CoverageReport {
+	return &api.CoverageReport{
+		CommitSHA: r.CommitSHA,
+		Branch:    r.Branch,
+		Coverage:  r.Coverage,
+		Created:   r.CreatedUnix.AsTime(),
+	}
+}
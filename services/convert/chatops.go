@@ -0,0 +1,43 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	chatops_model "code.gitea.io/gitea/models/chatops"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToChatOpsCommand converts a chatops command to API format, the secret is never returned
+func ToChatOpsCommand(cmd *chatops_model.Command) *api.ChatOpsCommand {
+	return &api.ChatOpsCommand{
+		ID:          cmd.ID,
+		Name:        cmd.Name,
+		Description: cmd.Description,
+		URL:         cmd.URL,
+		AccessMode:  cmd.AccessMode.String(),
+		Active:      cmd.IsActive,
+		Created:     cmd.CreatedUnix.AsTime(),
+		Updated:     cmd.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToChatOpsAudit converts a chatops audit record to API format
+func ToChatOpsAudit(audit *chatops_model.Audit) *api.ChatOpsAudit {
+	args := audit.Args
+	if args == nil {
+		args = []string{}
+	}
+	return &api.ChatOpsAudit{
+		ID:        audit.ID,
+		RepoID:    audit.RepoID,
+		IssueID:   audit.IssueID,
+		CommentID: audit.CommentID,
+		DoerID:    audit.DoerID,
+		Command:   audit.Command,
+		Args:      args,
+		Status:    string(audit.Status),
+		Message:   audit.Message,
+		Created:   audit.CreatedUnix.AsTime(),
+	}
+}
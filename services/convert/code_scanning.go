@@ -0,0 +1,59 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	codescanning_model "code.gitea.io/gitea/models/codescanning"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToCodeScanningAlert converts a code scanning alert to API format
+func ToCodeScanningAlert(ctx context.Context, alert *codescanning_model.Alert, doer *user_model.User) *api.CodeScanningAlert {
+	result := &api.CodeScanningAlert{
+		ID:              alert.ID,
+		Ref:             alert.Ref,
+		Tool:            alert.Tool,
+		RuleID:          alert.RuleID,
+		RuleDescription: alert.RuleDescription,
+		Severity:        string(alert.Severity),
+		Message:         alert.Message,
+		Path:            alert.Path,
+		StartLine:       alert.StartLine,
+		EndLine:         alert.EndLine,
+		CommitSHA:       alert.CommitSHA,
+		State:           alert.State.String(),
+		DismissedReason: alert.DismissedReason,
+		Created:         alert.CreatedUnix.AsTime(),
+		Updated:         alert.UpdatedUnix.AsTime(),
+	}
+	if alert.FixedUnix > 0 {
+		fixed := alert.FixedUnix.AsTime()
+		result.Fixed = &fixed
+	}
+	if alert.DismissedByID > 0 {
+		dismissedBy, err := user_model.GetPossibleUserByID(ctx, alert.DismissedByID)
+		if err != nil {
+			log.Error("GetPossibleUserByID[%d]: %v", alert.DismissedByID, err)
+		} else {
+			result.DismissedBy = ToUser(ctx, dismissedBy, doer)
+		}
+	}
+	return result
+}
+
+// ToCodeScanningAnalysis converts a code scanning analysis to API format
+func ToCodeScanningAnalysis(analysis *codescanning_model.Analysis) *api.CodeScanningAnalysis {
+	return &api.CodeScanningAnalysis{
+		ID:           analysis.ID,
+		Ref:          analysis.Ref,
+		CommitSHA:    analysis.CommitSHA,
+		Tool:         analysis.Tool,
+		ResultsCount: analysis.ResultsCount,
+		Created:      analysis.CreatedUnix.AsTime(),
+	}
+}
@@ -158,7 +158,10 @@ func ToBranchProtection(bp *git_model.ProtectedBranch) *api.BranchProtection {
 		BlockOnRejectedReviews:        bp.BlockOnRejectedReviews,
 		BlockOnOfficialReviewRequests: bp.BlockOnOfficialReviewRequests,
 		BlockOnOutdatedBranch:         bp.BlockOnOutdatedBranch,
+		BlockOnCodeScanningAlerts:     bp.BlockOnCodeScanningAlerts,
+		MinDiffCoverage:               bp.MinDiffCoverage,
 		DismissStaleApprovals:         bp.DismissStaleApprovals,
+		RequireReviewChecklist:        bp.RequireReviewChecklist,
 		RequireSignedCommits:          bp.RequireSignedCommits,
 		ProtectedFilePatterns:         bp.ProtectedFilePatterns,
 		UnprotectedFilePatterns:       bp.UnprotectedFilePatterns,
@@ -185,6 +188,7 @@ func ToVerification(ctx context.Context, c *git.Commit) *api.PayloadCommitVerifi
 	commitVerification := &api.PayloadCommitVerification{
 		Verified: verif.Verified,
 		Reason:   verif.Reason,
+		Rotated:  verif.KeyRotated,
 	}
 	if c.Signature != nil {
 		commitVerification.Signature = c.Signature.Signature
@@ -201,14 +205,21 @@ func ToVerification(ctx context.Context, c *git.Commit) *api.PayloadCommitVerifi
 
 // ToPublicKey convert asymkey_model.PublicKey to api.PublicKey
 func ToPublicKey(apiLink string, key *asymkey_model.PublicKey) *api.PublicKey {
-	return &api.PublicKey{
+	apiKey := &api.PublicKey{
 		ID:          key.ID,
 		Key:         key.Content,
 		URL:         fmt.Sprintf("%s%d", apiLink, key.ID),
 		Title:       key.Name,
 		Fingerprint: key.Fingerprint,
 		Created:     key.CreatedUnix.AsTime(),
+		Rotated:     key.IsRotated(),
+		SuccessorID: key.SuccessorID,
 	}
+	if !key.ExpiredUnix.IsZero() {
+		expires := key.ExpiredUnix.AsTime()
+		apiKey.Expires = &expires
+	}
+	return apiKey
 }
 
 // ToGPGKey converts models.GPGKey to api.GPGKey
@@ -247,6 +258,8 @@ func ToGPGKey(key *asymkey_model.GPGKey) *api.GPGKey {
 		CanEncryptStorage: key.CanEncryptStorage,
 		CanCertify:        key.CanSign,
 		Verified:          key.Verified,
+		Rotated:           key.IsRotated(),
+		SuccessorID:       key.SuccessorID,
 	}
 }
 
@@ -269,7 +282,7 @@ func ToGitHook(h *git.Hook) *api.GitHook {
 
 // ToDeployKey convert asymkey_model.DeployKey to api.DeployKey
 func ToDeployKey(apiLink string, key *asymkey_model.DeployKey) *api.DeployKey {
-	return &api.DeployKey{
+	apiKey := &api.DeployKey{
 		ID:          key.ID,
 		KeyID:       key.KeyID,
 		Key:         key.Content,
@@ -278,6 +291,26 @@ func ToDeployKey(apiLink string, key *asymkey_model.DeployKey) *api.DeployKey {
 		Title:       key.Name,
 		Created:     key.CreatedUnix.AsTime(),
 		ReadOnly:    key.Mode == perm.AccessModeRead, // All deploy keys are read-only.
+		RefPatterns: key.RefPatterns,
+		ArchiveOnly: key.ArchiveOnly,
+	}
+	if apiKey.RefPatterns == nil {
+		apiKey.RefPatterns = []string{}
+	}
+	if key.LastUsedUnix > 0 {
+		lastUsed := key.LastUsedUnix.AsTime()
+		apiKey.LastUsed = &lastUsed
+	}
+	return apiKey
+}
+
+// ToDeployKeyUsage convert asymkey_model.DeployKeyUsage to api.DeployKeyUsage
+func ToDeployKeyUsage(usage *asymkey_model.DeployKeyUsage) *api.DeployKeyUsage {
+	return &api.DeployKeyUsage{
+		Permission:    usage.Mode.String(),
+		Command:       usage.Verb,
+		RemoteAddress: usage.RemoteAddr,
+		Created:       usage.CreatedUnix.AsTime(),
 	}
 }
 
@@ -328,6 +361,14 @@ func ToTeams(ctx context.Context, teams []*organization.Team, loadOrgs bool) ([]
 			Permission:              teams[i].AccessMode.String(),
 			Units:                   teams[i].GetUnitNames(),
 			UnitsMap:                teams[i].GetUnitsMap(),
+			CodePaths:               teams[i].CodePaths,
+			MentionMode:             string(teams[i].MentionMode),
+			MentionWebhookType:      teams[i].MentionWebhookType,
+			MentionRateLimit:        teams[i].MentionRateLimit,
+			ReviewAssignCount:       teams[i].ReviewAssignCount,
+		}
+		if apiTeams[i].CodePaths == nil {
+			apiTeams[i].CodePaths = []string{}
 		}
 
 		if loadOrgs {
@@ -382,13 +423,16 @@ func ToTopicResponse(topic *repo_model.Topic) *api.TopicResponse {
 // ToOAuth2Application convert from auth.OAuth2Application to api.OAuth2Application
 func ToOAuth2Application(app *auth.OAuth2Application) *api.OAuth2Application {
 	return &api.OAuth2Application{
-		ID:                 app.ID,
-		Name:               app.Name,
-		ClientID:           app.ClientID,
-		ClientSecret:       app.ClientSecret,
-		ConfidentialClient: app.ConfidentialClient,
-		RedirectURIs:       app.RedirectURIs,
-		Created:            app.CreatedUnix.AsTime(),
+		ID:                   app.ID,
+		Name:                 app.Name,
+		ClientID:             app.ClientID,
+		ClientSecret:         app.ClientSecret,
+		ConfidentialClient:   app.ConfidentialClient,
+		RedirectURIs:         app.RedirectURIs,
+		Scopes:               app.Scopes,
+		AccessTokenLifetime:  app.AccessTokenLifetime(),
+		RefreshTokenLifetime: app.RefreshTokenLifetime(),
+		Created:              app.CreatedUnix.AsTime(),
 	}
 }
 
@@ -434,8 +478,9 @@ func ToChangedFile(f *gitdiff.DiffFile, repo *repo_model.Repository, commit stri
 		RawURL:      fmt.Sprint(repo.HTMLURL(), "/raw/commit/", commit, "/", util.PathEscapeSegments(f.GetDiffFileName())),
 	}
 
-	if status == "rename" {
+	if status == "renamed" || status == "copied" {
 		file.PreviousFilename = f.OldName
+		file.Similarity = f.Similarity
 	}
 
 	return file
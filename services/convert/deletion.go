@@ -0,0 +1,48 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// deleterName returns the name of the user who deleted a repository or a package version
+func deleterName(ctx context.Context, deleterID int64) string {
+	deleter, err := user_model.GetPossibleUserByID(ctx, deleterID)
+	if err != nil {
+		return user_model.NewGhostUser().Name
+	}
+	return deleter.Name
+}
+
+// ToDeletedRepository convert repo_model.RepoDeletion to api.DeletedRepository
+func ToDeletedRepository(ctx context.Context, d *repo_model.RepoDeletion) *api.DeletedRepository {
+	return &api.DeletedRepository{
+		ID:        d.Repo.ID,
+		Owner:     d.Repo.OwnerName,
+		Name:      d.Repo.Name,
+		FullName:  d.Repo.FullName(),
+		DeletedBy: deleterName(ctx, d.DeleterID),
+		Deleted:   d.DeletedUnix.AsTime(),
+		Purge:     d.PurgeUnix.AsTime(),
+	}
+}
+
+// ToDeletedPackageVersion convert packages_model.PackageVersionDeletion to api.DeletedPackageVersion
+func ToDeletedPackageVersion(ctx context.Context, d *packages_model.PackageVersionDeletion) *api.DeletedPackageVersion {
+	return &api.DeletedPackageVersion{
+		ID:        d.ID,
+		Type:      string(d.PackageType),
+		Name:      d.PackageName,
+		Version:   d.Version,
+		DeletedBy: deleterName(ctx, d.DeleterID),
+		Deleted:   d.DeletedUnix.AsTime(),
+		Purge:     d.PurgeUnix.AsTime(),
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	discussion_model "code.gitea.io/gitea/models/discussion"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToDiscussionCategory converts a discussion_model.Category to the api.DiscussionCategory format
+func ToDiscussionCategory(c *discussion_model.Category) *api.DiscussionCategory {
+	return &api.DiscussionCategory{
+		ID:          c.ID,
+		Name:        c.Name,
+		Description: c.Description,
+		Kind:        string(c.Kind),
+		Created:     c.CreatedUnix.AsTime(),
+	}
+}
+
+// ToDiscussion converts a discussion_model.Discussion to the api.Discussion format,
+// the repository, category and poster must be loaded
+func ToDiscussion(ctx context.Context, d *discussion_model.Discussion) *api.Discussion {
+	return &api.Discussion{
+		ID:       d.ID,
+		URL:      d.APIURL(),
+		HTMLURL:  d.HTMLURL(),
+		Index:    d.Index,
+		Category: ToDiscussionCategory(d.Category),
+		Poster:   ToUser(ctx, d.Poster, nil),
+		Title:    d.Title,
+		Body:     d.Content,
+		IsLocked: d.IsLocked,
+		AnswerID: d.AnswerID,
+		Comments: d.NumComments,
+		Created:  d.CreatedUnix.AsTime(),
+		Updated:  d.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToDiscussionComment converts a discussion_model.Comment to the api.DiscussionComment format,
+// the repository of the discussion and the poster must be loaded
+func ToDiscussionComment(ctx context.Context, d *discussion_model.Discussion, c *discussion_model.Comment) *api.DiscussionComment {
+	return &api.DiscussionComment{
+		ID:       c.ID,
+		HTMLURL:  c.HTMLURL(d),
+		Poster:   ToUser(ctx, c.Poster, nil),
+		Body:     c.Content,
+		IsAnswer: d.AnswerID == c.ID,
+		Created:  c.CreatedUnix.AsTime(),
+		Updated:  c.UpdatedUnix.AsTime(),
+	}
+}
@@ -0,0 +1,38 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToDownloadLink converts a download link to API format, the creator and revoker have to be loaded
+func ToDownloadLink(ctx context.Context, l *repo_model.DownloadLink, doer *user_model.User) *api.DownloadLink {
+	result := &api.DownloadLink{
+		ID:            l.ID,
+		AttachmentID:  l.AttachmentID,
+		Archive:       l.ArchiveName,
+		CommitID:      l.CommitID,
+		Creator:       ToUser(ctx, l.Creator, doer),
+		DownloadCount: l.DownloadCount,
+		Active:        l.IsActive(),
+		Expires:       l.ExpiresUnix.AsTime(),
+		Created:       l.CreatedUnix.AsTime(),
+	}
+	if l.Token != "" {
+		result.URL = l.Link()
+	}
+	if l.RevokedUnix != 0 {
+		revoked := l.RevokedUnix.AsTime()
+		result.Revoked = &revoked
+		if l.Revoker != nil {
+			result.Revoker = ToUser(ctx, l.Revoker, doer)
+		}
+	}
+	return result
+}
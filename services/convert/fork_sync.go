@@ -0,0 +1,25 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToForkSync converts a sync schedule of a fork branch to its API format
+func ToForkSync(s *repo_model.ForkSync) *api.ForkSync {
+	result := &api.ForkSync{
+		Branch:         s.Branch,
+		UpstreamBranch: s.UpstreamBranch,
+		AllowMerge:     s.AllowMerge,
+		LastStatus:     s.LastStatus.String(),
+		LastMessage:    s.LastMessage,
+	}
+	if s.LastSyncUnix > 0 {
+		lastSync := s.LastSyncUnix.AsTime()
+		result.LastSync = &lastSync
+	}
+	return result
+}
@@ -0,0 +1,112 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+	"sort"
+
+	insights_model "code.gitea.io/gitea/models/insights"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	insights_service "code.gitea.io/gitea/services/insights"
+)
+
+func toInsightsDuration(d insights_model.DurationStats) api.InsightsDuration {
+	return api.InsightsDuration{
+		Count:   d.Count,
+		Average: d.Average,
+		Median:  d.Median,
+	}
+}
+
+func toInsightsMetrics(s *insights_model.Stats, weeks float64) api.InsightsMetrics {
+	m := api.InsightsMetrics{
+		MergedPullRequests: s.MergedPullRequests,
+		Deployments:        s.Deployments,
+		CycleTime:          toInsightsDuration(s.CycleTime),
+		LeadTime:           toInsightsDuration(s.LeadTime),
+		ReviewLatency:      toInsightsDuration(s.ReviewLatency),
+	}
+	if weeks > 0 {
+		m.MergeFrequency = float64(s.MergedPullRequests) / weeks
+		m.DeploymentFrequency = float64(s.Deployments) / weeks
+	}
+	return m
+}
+
+// ToInsights converts insights to API format
+func ToInsights(i *insights_service.Insights) *api.Insights {
+	return &api.Insights{
+		Since:           i.Since,
+		Before:          i.Before,
+		InsightsMetrics: toInsightsMetrics(i.Total, i.Weeks()),
+	}
+}
+
+// ToRepoInsights converts the insights of the given repositories to API format, sorted by repository name
+func ToRepoInsights(ctx context.Context, i *insights_service.Insights, repos map[int64]*repo_model.Repository, mode perm.AccessMode) []*api.RepoInsights {
+	weeks := i.Weeks()
+	result := make([]*api.RepoInsights, 0, len(i.PerRepo))
+	for repoID, stats := range i.PerRepo {
+		repo, ok := repos[repoID]
+		if !ok {
+			continue
+		}
+		result = append(result, &api.RepoInsights{
+			Repository:      ToRepo(ctx, repo, mode),
+			InsightsMetrics: toInsightsMetrics(stats, weeks),
+		})
+	}
+	sort.Slice(result, func(a, b int) bool {
+		return result[a].Repository.FullName < result[b].Repository.FullName
+	})
+	return result
+}
+
+// maxPathContributors is the number of top contributors listed per directory
+const maxPathContributors = 10
+
+// ToPathOwnershipReport converts the ownership of the directories of a repository to API format
+func ToPathOwnershipReport(ctx context.Context, paths []*insights_service.PathOwnership, status *insights_model.PathOwnershipStatus, doer *user_model.User) *api.PathOwnershipReport {
+	report := &api.PathOwnershipReport{Paths: make([]*api.PathOwnership, 0, len(paths))}
+	if status == nil {
+		return report
+	}
+	report.CommitID = status.CommitID
+	computed := status.ComputedUnix.AsTime()
+	report.ComputedAt = &computed
+
+	for _, po := range paths {
+		ownership := &api.PathOwnership{
+			Path:         po.Path,
+			Commits:      po.Commits,
+			Lines:        po.Lines,
+			LastCommitAt: po.LastCommitUnix.AsTime(),
+			BusFactor:    po.BusFactor,
+			Unowned:      po.Unowned,
+			Contributors: make([]*api.PathContributor, 0, maxPathContributors),
+		}
+		for i, c := range po.Contributors {
+			if i == maxPathContributors {
+				break
+			}
+			contributor := &api.PathContributor{
+				Name:         c.AuthorName,
+				Email:        c.AuthorEmail,
+				Commits:      c.Commits,
+				Lines:        c.Lines,
+				LastCommitAt: c.LastCommitUnix.AsTime(),
+			}
+			if u := po.Users[c.AuthorEmail]; u != nil {
+				contributor.User = ToUser(ctx, u, doer)
+			}
+			ownership.Contributors = append(ownership.Contributors, contributor)
+		}
+		report.Paths = append(report.Paths, ownership)
+	}
+	return report
+}
@@ -66,6 +66,12 @@ func ToAPIIssue(ctx context.Context, issue *issues_model.Issue) *api.Issue {
 	if issue.ClosedUnix != 0 {
 		apiIssue.Closed = issue.ClosedUnix.AsTimePtr()
 	}
+	if issue.IsLocked {
+		apiIssue.LockLevel = string(issue.GetLockLevel())
+		if issue.LockUntilUnix != 0 {
+			apiIssue.LockUntil = issue.LockUntilUnix.AsTimePtr()
+		}
+	}
 
 	if err := issue.LoadMilestone(ctx); err != nil {
 		return &api.Issue{}
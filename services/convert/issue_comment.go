@@ -16,15 +16,18 @@ import (
 // ToComment converts a issues_model.Comment to the api.Comment format
 func ToComment(ctx context.Context, c *issues_model.Comment) *api.Comment {
 	return &api.Comment{
-		ID:          c.ID,
-		Poster:      ToUser(ctx, c.Poster, nil),
-		HTMLURL:     c.HTMLURL(),
-		IssueURL:    c.IssueURL(),
-		PRURL:       c.PRURL(),
-		Body:        c.Content,
-		Attachments: ToAttachments(c.Attachments),
-		Created:     c.CreatedUnix.AsTime(),
-		Updated:     c.UpdatedUnix.AsTime(),
+		ID:              c.ID,
+		Poster:          ToUser(ctx, c.Poster, nil),
+		HTMLURL:         c.HTMLURL(),
+		IssueURL:        c.IssueURL(),
+		PRURL:           c.PRURL(),
+		Body:            c.Content,
+		Attachments:     ToAttachments(c.Attachments),
+		HiddenReason:    string(c.HiddenReason),
+		ReplyToID:       c.ReplyToID,
+		ThreadCollapsed: c.ThreadCollapsed,
+		Created:         c.CreatedUnix.AsTime(),
+		Updated:         c.UpdatedUnix.AsTime(),
 	}
 }
 
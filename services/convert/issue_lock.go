@@ -0,0 +1,26 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	issues_model "code.gitea.io/gitea/models/issues"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToIssueLockPolicy converts a lock policy to API format
+func ToIssueLockPolicy(p *issues_model.IssueLockPolicy) *api.IssueLockPolicy {
+	result := &api.IssueLockPolicy{
+		Enabled:        p.Enabled,
+		ApplyToIssues:  p.ApplyToIssues,
+		ApplyToPulls:   p.ApplyToPulls,
+		DaysAfterClose: p.DaysAfterClose,
+		LockLevel:      string(p.LockLevel),
+		Reason:         p.Reason,
+		LockComment:    p.LockComment,
+	}
+	if p.LastRunUnix > 0 {
+		result.LastRun = p.LastRunUnix.AsTimePtr()
+	}
+	return result
+}
@@ -0,0 +1,60 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToStalePolicy converts a stale policy to API format, resolving label and milestone IDs to names
+func ToStalePolicy(ctx context.Context, p *issues_model.StalePolicy) (*api.StalePolicy, error) {
+	result := &api.StalePolicy{
+		Enabled:             p.Enabled,
+		ApplyToIssues:       p.ApplyToIssues,
+		ApplyToPulls:        p.ApplyToPulls,
+		DaysUntilStale:      p.DaysUntilStale,
+		DaysUntilClose:      p.DaysUntilClose,
+		StaleComment:        p.StaleComment,
+		CloseComment:        p.CloseComment,
+		ExemptLabels:        make([]string, 0, len(p.ExemptLabelIDs)),
+		ExemptMilestones:    make([]string, 0, len(p.ExemptMilestoneIDs)),
+		ExemptAllMilestones: p.ExemptAllMilestones,
+	}
+	if p.LastRunUnix > 0 {
+		lastRun := p.LastRunUnix.AsTime()
+		result.LastRun = &lastRun
+	}
+
+	staleLabel, err := issues_model.GetLabelInRepoByID(ctx, p.RepoID, p.StaleLabelID)
+	if err != nil && !issues_model.IsErrRepoLabelNotExist(err) {
+		return nil, err
+	} else if err == nil {
+		result.StaleLabel = staleLabel.Name
+	}
+
+	if len(p.ExemptLabelIDs) > 0 {
+		labels, err := issues_model.GetLabelsInRepoByIDs(ctx, p.RepoID, p.ExemptLabelIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, label := range labels {
+			result.ExemptLabels = append(result.ExemptLabels, label.Name)
+		}
+	}
+
+	for _, id := range p.ExemptMilestoneIDs {
+		milestone, err := issues_model.GetMilestoneByRepoID(ctx, p.RepoID, id)
+		if err != nil {
+			if issues_model.IsErrMilestoneNotExist(err) {
+				continue
+			}
+			return nil, err
+		}
+		result.ExemptMilestones = append(result.ExemptMilestones, milestone.Name)
+	}
+	return result, nil
+}
@@ -0,0 +1,44 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	issues_model "code.gitea.io/gitea/models/issues"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToLegalHold converts a legal hold to API format, its attributes have to be loaded
+func ToLegalHold(h *issues_model.LegalHold) *api.LegalHold {
+	apiHold := &api.LegalHold{
+		ID:        h.ID,
+		RepoID:    h.RepoID,
+		Reason:    h.Reason,
+		CreatorID: h.CreatorID,
+		Created:   h.CreatedUnix.AsTime(),
+	}
+	if h.Repo != nil {
+		apiHold.RepoName = h.Repo.FullName()
+	}
+	if h.Issue != nil {
+		apiHold.IssueIndex = h.Issue.Index
+	}
+	return apiHold
+}
+
+// ToLegalRedaction converts a redaction record to API format
+func ToLegalRedaction(r *issues_model.LegalRedaction) *api.LegalRedaction {
+	return &api.LegalRedaction{
+		ID:               r.ID,
+		RepoID:           r.RepoID,
+		IssueID:          r.IssueID,
+		CommentID:        r.CommentID,
+		ContentHistoryID: r.ContentHistoryID,
+		AttachmentID:     r.AttachmentID,
+		AttachmentUUID:   r.AttachmentUUID,
+		AttachmentName:   r.AttachmentName,
+		Reason:           r.Reason,
+		DoerID:           r.DoerID,
+		Created:          r.CreatedUnix.AsTime(),
+	}
+}
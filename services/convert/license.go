@@ -0,0 +1,35 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRepoLicenses converts the detected licenses of a repository to API format
+func ToRepoLicenses(licenses repo_model.RepoLicenseList) []*api.RepoLicense {
+	result := make([]*api.RepoLicense, 0, len(licenses))
+	for _, l := range licenses {
+		result = append(result, &api.RepoLicense{
+			License:    l.License,
+			Path:       l.Path,
+			CommitID:   l.CommitID,
+			Confidence: l.Confidence,
+			Dependency: l.Dependency,
+		})
+	}
+	return result
+}
+
+// ToLicensePolicy converts a license policy to API format
+func ToLicensePolicy(p *organization.LicensePolicy) *api.LicensePolicy {
+	return &api.LicensePolicy{
+		Allowed:   p.Allowed,
+		Forbidden: p.Forbidden,
+		Action:    string(p.Action),
+		Updated:   p.UpdatedUnix.AsTime(),
+	}
+}
@@ -0,0 +1,25 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	system_model "code.gitea.io/gitea/models/system"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToMailDelivery converts a mail delivery to API format
+func ToMailDelivery(delivery *system_model.MailDelivery) *api.MailDelivery {
+	return &api.MailDelivery{
+		ID:                delivery.ID,
+		MessageID:         delivery.MessageID,
+		Recipient:         delivery.Recipient,
+		Subject:           delivery.Subject,
+		Provider:          delivery.Provider,
+		ProviderMessageID: delivery.ProviderMessageID,
+		Status:            delivery.Status.String(),
+		Error:             delivery.Error,
+		Created:           delivery.CreatedUnix.AsTime(),
+		Updated:           delivery.UpdatedUnix.AsTime(),
+	}
+}
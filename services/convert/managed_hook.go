@@ -0,0 +1,22 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	githook_model "code.gitea.io/gitea/models/githook"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToManagedHook converts a managed git hook to API format
+func ToManagedHook(hook *githook_model.ManagedHook) *api.ManagedHook {
+	return &api.ManagedHook{
+		ID:      hook.ID,
+		Name:    hook.Name,
+		Type:    string(hook.Type),
+		Content: hook.Content,
+		Active:  hook.IsActive,
+		Created: hook.CreatedUnix.AsTime(),
+		Updated: hook.UpdatedUnix.AsTime(),
+	}
+}
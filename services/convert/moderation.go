@@ -0,0 +1,110 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	moderation_model "code.gitea.io/gitea/models/moderation"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToBlockedUser converts a block to API format
+func ToBlockedUser(ctx context.Context, block *user_model.Block, doer *user_model.User) *api.BlockedUser {
+	return &api.BlockedUser{
+		User:    ToUser(ctx, block.Blockee, doer),
+		Note:    block.Note,
+		Created: block.CreatedUnix.AsTime(),
+	}
+}
+
+// ToAbuseReport converts an abuse report to API format
+func ToAbuseReport(ctx context.Context, report *moderation_model.AbuseReport, doer *user_model.User) (*api.AbuseReport, error) {
+	reporter, err := getUserOrGhost(ctx, report.ReporterID)
+	if err != nil {
+		return nil, err
+	}
+	result := &api.AbuseReport{
+		ID:          report.ID,
+		Reporter:    ToUser(ctx, reporter, doer),
+		ContentType: string(report.ContentType),
+		ContentID:   report.ContentID,
+		Category:    string(report.Category),
+		Remarks:     report.Remarks,
+		Status:      report.Status.String(),
+		Created:     report.CreatedUnix.AsTime(),
+		Updated:     report.UpdatedUnix.AsTime(),
+	}
+	if report.HandledByID > 0 {
+		handledBy, err := getUserOrGhost(ctx, report.HandledByID)
+		if err != nil {
+			return nil, err
+		}
+		result.HandledBy = ToUser(ctx, handledBy, doer)
+	}
+	return result, nil
+}
+
+// ToHeldContent converts content held for spam review to API format
+func ToHeldContent(ctx context.Context, held *moderation_model.HeldContent, doer *user_model.User) (*api.HeldContent, error) {
+	poster, err := getUserOrGhost(ctx, held.PosterID)
+	if err != nil {
+		return nil, err
+	}
+	result := &api.HeldContent{
+		ID:       held.ID,
+		Type:     string(held.Type),
+		Poster:   ToUser(ctx, poster, doer),
+		Title:    held.Title,
+		Content:  held.Content,
+		Score:    held.Score,
+		Reasons:  held.Reasons,
+		Status:   held.Status.String(),
+		ResultID: held.ResultID,
+		Created:  held.CreatedUnix.AsTime(),
+		Updated:  held.UpdatedUnix.AsTime(),
+	}
+	if held.RepoID > 0 {
+		repo, err := repo_model.GetRepositoryByID(ctx, held.RepoID)
+		if err != nil {
+			return nil, err
+		}
+		result.Repository = ToRepo(ctx, repo, perm.AccessModeAdmin)
+	}
+	if held.HandledByID > 0 {
+		handledBy, err := getUserOrGhost(ctx, held.HandledByID)
+		if err != nil {
+			return nil, err
+		}
+		result.HandledBy = ToUser(ctx, handledBy, doer)
+	}
+	return result, nil
+}
+
+// ToInteractionLimit converts an interaction limit to API format
+func ToInteractionLimit(limit *moderation_model.InteractionLimit) *api.InteractionLimit {
+	result := &api.InteractionLimit{
+		MinAccountAgeDays: limit.MinAccountAgeDays,
+		Created:           limit.CreatedUnix.AsTime(),
+	}
+	if limit.ExpiresUnix > 0 {
+		expires := limit.ExpiresUnix.AsTime()
+		result.Expires = &expires
+	}
+	return result
+}
+
+func getUserOrGhost(ctx context.Context, id int64) (*user_model.User, error) {
+	u, err := user_model.GetPossibleUserByID(ctx, id)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			return user_model.NewGhostUser(), nil
+		}
+		return nil, err
+	}
+	return u, nil
+}
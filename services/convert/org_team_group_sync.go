@@ -0,0 +1,41 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToTeamGroupSync converts the group sync settings of a team to their API format, the sources are given by their names
+func ToTeamGroupSync(team *organization.Team, mappings []*organization.TeamGroupMapping, sourceNames map[int64]string) *api.TeamGroupSync {
+	result := &api.TeamGroupSync{
+		Policy:        string(team.GroupSyncPolicy),
+		RemoveMembers: team.GroupSyncRemoval,
+		Mappings:      make([]*api.TeamGroupMapping, 0, len(mappings)),
+	}
+	for _, m := range mappings {
+		result.Mappings = append(result.Mappings, &api.TeamGroupMapping{
+			Source: sourceNames[m.SourceID],
+			Group:  m.GroupName,
+		})
+	}
+	return result
+}
+
+// ToTeamGroupSyncLog converts a change made by the group sync to its API format
+func ToTeamGroupSyncLog(ctx context.Context, l *organization.TeamGroupSyncLog, u, doer *user_model.User) *api.TeamGroupSyncLog {
+	return &api.TeamGroupSyncLog{
+		ID:      l.ID,
+		TeamID:  l.TeamID,
+		UserID:  l.UserID,
+		User:    ToUser(ctx, u, doer),
+		Action:  string(l.Action),
+		Reason:  l.Reason,
+		Created: l.CreatedUnix.AsTime(),
+	}
+}
@@ -0,0 +1,99 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"context"
+	"strconv"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToTeamHomepage converts the homepage of a team and its pinned repositories to their API format
+func ToTeamHomepage(ctx context.Context, h *organization.TeamHomepage, pinned []*repo_model.Repository, doer *user_model.User) (*api.TeamHomepage, error) {
+	result := &api.TeamHomepage{
+		TeamID:      h.TeamID,
+		Readme:      h.Readme,
+		PinnedRepos: make([]*api.Repository, 0, len(pinned)),
+	}
+	if h.UpdatedUnix > 0 {
+		result.Updated = h.UpdatedUnix.AsTimePtr()
+	}
+	for _, repo := range pinned {
+		permission, err := access_model.GetUserRepoPermission(ctx, repo, doer)
+		if err != nil {
+			return nil, err
+		}
+		if !permission.HasAccess() {
+			continue
+		}
+		result.PinnedRepos = append(result.PinnedRepos, ToRepo(ctx, repo, permission.AccessMode))
+	}
+	return result, nil
+}
+
+// ToTeamActivitySummary converts the activity summary of a team to its API format
+func ToTeamActivitySummary(summary *activities_model.TeamActivitySummary) (*api.TeamActivitySummary, error) {
+	repoIDs := make([]int64, 0, len(summary.ByRepo))
+	for _, c := range summary.ByRepo {
+		repoIDs = append(repoIDs, c.GroupKey)
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, 0, len(summary.ByMember))
+	for _, c := range summary.ByMember {
+		userIDs = append(userIDs, c.GroupKey)
+	}
+	users, err := user_model.GetUsersByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	userNames := make(map[int64]string, len(users))
+	for _, u := range users {
+		userNames[u.ID] = u.Name
+	}
+
+	result := &api.TeamActivitySummary{
+		Since:    summary.Since.AsTime(),
+		Total:    summary.Total,
+		ByType:   make([]*api.TeamActivityCount, 0, len(summary.ByOpType)),
+		ByRepo:   make([]*api.TeamActivityCount, 0, len(summary.ByRepo)),
+		ByMember: make([]*api.TeamActivityCount, 0, len(summary.ByMember)),
+	}
+	for _, c := range summary.ByOpType {
+		result.ByType = append(result.ByType, &api.TeamActivityCount{Key: activities_model.ActionType(c.GroupKey).String(), Count: c.Count})
+	}
+	for _, c := range summary.ByRepo {
+		key := strconv.FormatInt(c.GroupKey, 10)
+		if repo, ok := repos[c.GroupKey]; ok {
+			key = repo.FullName()
+		}
+		result.ByRepo = append(result.ByRepo, &api.TeamActivityCount{Key: key, Count: c.Count})
+	}
+	for _, c := range summary.ByMember {
+		key, ok := userNames[c.GroupKey]
+		if !ok {
+			key = user_model.NewGhostUser().Name
+		}
+		result.ByMember = append(result.ByMember, &api.TeamActivityCount{Key: key, Count: c.Count})
+	}
+	return result, nil
+}
+
+// ToTeamMilestone converts a milestone in the calendar of a team to its API format
+func ToTeamMilestone(m *issues_model.Milestone, repo *repo_model.Repository) *api.TeamMilestone {
+	return &api.TeamMilestone{
+		Milestone:  *ToAPIMilestone(m),
+		Repository: repo.FullName(),
+	}
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models/organization"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToTeamReminder converts a team reminder to its API format
+func ToTeamReminder(r *organization.TeamReminder) *api.TeamReminder {
+	result := &api.TeamReminder{
+		ID:                    r.ID,
+		TeamID:                r.TeamID,
+		Schedule:              r.Schedule,
+		WebhookType:           r.WebhookType,
+		WebhookURL:            r.WebhookURL,
+		Active:                r.IsActive,
+		IncludeReviewRequests: r.IncludeReviewRequests,
+		IncludeStalePulls:     r.IncludeStalePulls,
+		StaleDays:             r.StaleDays,
+		IncludeFailingChecks:  r.IncludeFailingChecks,
+		IgnoreWorkInProgress:  r.IgnoreWorkInProgress,
+		NextRun:               r.NextRunUnix.AsTime(),
+		Created:               r.CreatedUnix.AsTime(),
+		Updated:               r.UpdatedUnix.AsTime(),
+	}
+	if r.LastRunUnix > 0 {
+		lastRun := r.LastRunUnix.AsTime()
+		result.LastRun = &lastRun
+	}
+	return result
+}
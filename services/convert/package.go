@@ -9,6 +9,16 @@ import (
 	"code.gitea.io/gitea/models/packages"
 	access_model "code.gitea.io/gitea/models/perm/access"
 	user_model "code.gitea.io/gitea/models/user"
+	cargo_module "code.gitea.io/gitea/modules/packages/cargo"
+	chef_module "code.gitea.io/gitea/modules/packages/chef"
+	composer_module "code.gitea.io/gitea/modules/packages/composer"
+	container_module "code.gitea.io/gitea/modules/packages/container"
+	helm_module "code.gitea.io/gitea/modules/packages/helm"
+	npm_module "code.gitea.io/gitea/modules/packages/npm"
+	nuget_module "code.gitea.io/gitea/modules/packages/nuget"
+	pub_module "code.gitea.io/gitea/modules/packages/pub"
+	pypi_module "code.gitea.io/gitea/modules/packages/pypi"
+	rubygems_module "code.gitea.io/gitea/modules/packages/rubygems"
 	api "code.gitea.io/gitea/modules/structs"
 )
 
@@ -26,18 +36,59 @@ func ToPackage(ctx context.Context, pd *packages.PackageDescriptor, doer *user_m
 		}
 	}
 
+	description, _ := packageAbout(pd.Metadata)
+
 	return &api.Package{
-		ID:         pd.Version.ID,
-		Owner:      ToUser(ctx, pd.Owner, doer),
-		Repository: repo,
-		Creator:    ToUser(ctx, pd.Creator, doer),
-		Type:       string(pd.Package.Type),
-		Name:       pd.Package.Name,
-		Version:    pd.Version.Version,
-		CreatedAt:  pd.Version.CreatedUnix.AsTime(),
+		ID:                 pd.Version.ID,
+		Owner:              ToUser(ctx, pd.Owner, doer),
+		Repository:         repo,
+		Creator:            ToUser(ctx, pd.Creator, doer),
+		Type:               string(pd.Package.Type),
+		Name:               pd.Package.Name,
+		Version:            pd.Version.Version,
+		Description:        description,
+		IsDeprecated:       pd.Version.IsDeprecated,
+		DeprecationMessage: pd.Version.DeprecationMessage,
+		CreatedAt:          pd.Version.CreatedUnix.AsTime(),
 	}, nil
 }
 
+// ToPackageReadme returns the readme or long description of the package
+func ToPackageReadme(pd *packages.PackageDescriptor) string {
+	_, readme := packageAbout(pd.Metadata)
+	return readme
+}
+
+// packageAbout returns the short description and the readme or long description from the metadata of a package
+func packageAbout(metadata interface{}) (description, readme string) {
+	switch m := metadata.(type) {
+	case *cargo_module.Metadata:
+		return m.Description, m.Readme
+	case *chef_module.Metadata:
+		return m.Description, m.LongDescription
+	case *composer_module.Metadata:
+		return m.Description, ""
+	case *container_module.Metadata:
+		return m.Description, ""
+	case *helm_module.Metadata:
+		return m.Description, ""
+	case *npm_module.Metadata:
+		return m.Description, m.Readme
+	case *nuget_module.Metadata:
+		return m.Description, m.Readme
+	case *pub_module.Metadata:
+		return m.Description, m.Readme
+	case *pypi_module.Metadata:
+		if m.Summary != "" {
+			return m.Summary, m.LongDescription
+		}
+		return m.Description, m.LongDescription
+	case *rubygems_module.Metadata:
+		return m.Description, ""
+	}
+	return "", ""
+}
+
 // ToPackageFile converts packages.PackageFileDescriptor to api.PackageFile
 func ToPackageFile(pfd *packages.PackageFileDescriptor) *api.PackageFile {
 	return &api.PackageFile{
@@ -50,3 +101,18 @@ func ToPackageFile(pfd *packages.PackageFileDescriptor) *api.PackageFile {
 		HashSHA512: pfd.Blob.HashSHA512,
 	}
 }
+
+// ToPackageAdvisory converts packages.PackageAdvisory to api.PackageAdvisory
+func ToPackageAdvisory(pa *packages.PackageAdvisory) *api.PackageAdvisory {
+	return &api.PackageAdvisory{
+		ID:                 pa.ID,
+		Type:               string(pa.Type),
+		PackageName:        pa.PackageName,
+		Title:              pa.Title,
+		URL:                pa.URL,
+		Severity:           pa.Severity,
+		VulnerableVersions: pa.VulnerableVersions,
+		Created:            pa.CreatedUnix.AsTime(),
+		Updated:            pa.UpdatedUnix.AsTime(),
+	}
+}
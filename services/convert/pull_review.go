@@ -43,6 +43,10 @@ func ToPullReview(ctx context.Context, r *issues_model.Review, doer *user_model.
 		HTMLPullURL:       r.Issue.HTMLURL(),
 	}
 
+	if result.ChecklistItems, err = issues_model.GetReviewChecklistCheckedItemIDs(ctx, r.ID); err != nil {
+		return nil, err
+	}
+
 	switch r.Type {
 	case issues_model.ReviewTypeApprove:
 		result.State = api.ReviewStateApproved
@@ -125,3 +129,14 @@ func patch2diff(patch string) string {
 	}
 	return ""
 }
+
+// ToReviewChecklistItem converts an item of the review checklist of a repository to its API format
+func ToReviewChecklistItem(item *issues_model.ReviewChecklistItem) *api.ReviewChecklistItem {
+	return &api.ReviewChecklistItem{
+		ID:          item.ID,
+		Name:        item.Name,
+		Description: item.Description,
+		Required:    item.Required,
+		Sort:        item.Sort,
+	}
+}
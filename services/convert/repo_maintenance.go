@@ -0,0 +1,34 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToRepoMaintenance converts a repository maintenance record to API format
+func ToRepoMaintenance(repo *repo_model.Repository, m *repo_model.RepoMaintenance) *api.RepoMaintenance {
+	result := &api.RepoMaintenance{
+		RepoID:       m.RepoID,
+		LooseObjects: m.LooseObjects,
+		Packs:        m.Packs,
+		PackSize:     m.PackSize,
+		LastTasks:    make([]string, 0, len(m.LastTasks)),
+		LastError:    m.LastError,
+		LastDuration: m.LastDuration,
+		LastChecked:  m.LastCheckedUnix.AsTime(),
+	}
+	if repo != nil {
+		result.FullName = repo.FullName()
+	}
+	for _, task := range m.LastTasks {
+		result.LastTasks = append(result.LastTasks, string(task))
+	}
+	if m.LastRunUnix > 0 {
+		lastRun := m.LastRunUnix.AsTime()
+		result.LastRun = &lastRun
+	}
+	return result
+}
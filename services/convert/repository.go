@@ -82,6 +82,10 @@ func innerToRepo(ctx context.Context, repo *repo_model.Repository, mode perm.Acc
 	defaultDeleteBranchAfterMerge := false
 	defaultMergeStyle := repo_model.MergeStyleMerge
 	defaultAllowMaintainerEdit := false
+	defaultMergeMessageTemplate := ""
+	defaultSquashMessageTemplate := ""
+	mergeMessageTrailers := ""
+	mergeTrainBatchSize := 0
 	if unit, err := repo.GetUnit(ctx, unit_model.TypePullRequests); err == nil {
 		config := unit.PullRequestsConfig()
 		hasPullRequests = true
@@ -94,6 +98,10 @@ func innerToRepo(ctx context.Context, repo *repo_model.Repository, mode perm.Acc
 		defaultDeleteBranchAfterMerge = config.DefaultDeleteBranchAfterMerge
 		defaultMergeStyle = config.GetDefaultMergeStyle()
 		defaultAllowMaintainerEdit = config.DefaultAllowMaintainerEdit
+		defaultMergeMessageTemplate = config.DefaultMergeMessageTemplate
+		defaultSquashMessageTemplate = config.DefaultSquashMessageTemplate
+		mergeMessageTrailers = config.MergeMessageTrailers
+		mergeTrainBatchSize = config.GetMergeTrainBatchSize()
 	}
 	hasProjects := false
 	if _, err := repo.GetUnit(ctx, unit_model.TypeProjects); err == nil {
@@ -111,8 +119,15 @@ func innerToRepo(ctx context.Context, repo *repo_model.Repository, mode perm.Acc
 	}
 
 	hasActions := false
-	if _, err := repo.GetUnit(ctx, unit_model.TypeActions); err == nil {
+	actionsApprovalPolicy := ""
+	if unit, err := repo.GetUnit(ctx, unit_model.TypeActions); err == nil {
 		hasActions = true
+		actionsApprovalPolicy = string(unit.ActionsConfig().GetApprovalPolicy())
+	}
+
+	hasDiscussions := false
+	if _, err := repo.GetUnit(ctx, unit_model.TypeDiscussions); err == nil {
+		hasDiscussions = true
 	}
 
 	if err := repo.LoadOwner(ctx); err != nil {
@@ -192,6 +207,7 @@ func innerToRepo(ctx context.Context, repo *repo_model.Repository, mode perm.Acc
 		HasReleases:                   hasReleases,
 		HasPackages:                   hasPackages,
 		HasActions:                    hasActions,
+		HasDiscussions:                hasDiscussions,
 		ExternalWiki:                  externalWiki,
 		HasPullRequests:               hasPullRequests,
 		IgnoreWhitespaceConflicts:     ignoreWhitespaceConflicts,
@@ -203,9 +219,15 @@ func innerToRepo(ctx context.Context, repo *repo_model.Repository, mode perm.Acc
 		DefaultDeleteBranchAfterMerge: defaultDeleteBranchAfterMerge,
 		DefaultMergeStyle:             string(defaultMergeStyle),
 		DefaultAllowMaintainerEdit:    defaultAllowMaintainerEdit,
+		DefaultMergeMessageTemplate:   defaultMergeMessageTemplate,
+		DefaultSquashMessageTemplate:  defaultSquashMessageTemplate,
+		MergeMessageTrailers:          mergeMessageTrailers,
+		MergeTrainBatchSize:           mergeTrainBatchSize,
+		ActionsApprovalPolicy:         actionsApprovalPolicy,
 		AvatarURL:                     repo.AvatarLink(ctx),
-		Internal:                      !repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePrivate,
+		Internal:                      repo.IsInternal || (!repo.IsPrivate && repo.Owner.Visibility == api.VisibleTypePrivate),
 		MirrorInterval:                mirrorInterval,
+		ObjectFormatName:              repo.ObjectFormat().String(),
 		MirrorUpdated:                 mirrorUpdated,
 		RepoTransfer:                  transfer,
 	}
@@ -55,3 +55,47 @@ func ToCombinedStatus(ctx context.Context, statuses []*git_model.CommitStatus, r
 
 	return retStatus
 }
+
+// ToFlakyCheck converts git_model.FlakyCheckRule to api.FlakyCheck
+func ToFlakyCheck(rule *git_model.FlakyCheckRule) *api.FlakyCheck {
+	return &api.FlakyCheck{
+		ID:             rule.ID,
+		ContextPattern: rule.ContextPattern,
+		MaxRetries:     rule.MaxRetries,
+		RequiredPasses: rule.RequiredPasses,
+		Attempts:       rule.Attempts,
+		Created:        rule.CreatedUnix.AsTime(),
+		Updated:        rule.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToCommitStatusAttempt converts git_model.CommitStatusAttempt to api.CommitStatusAttempt
+func ToCommitStatusAttempt(ctx context.Context, attempt *git_model.CommitStatusAttempt) *api.CommitStatusAttempt {
+	apiAttempt := &api.CommitStatusAttempt{
+		Context: attempt.Context,
+		Attempt: attempt.Attempt,
+		State:   attempt.State,
+		Reason:  attempt.Reason,
+		JobID:   attempt.JobID,
+		Created: attempt.CreatedUnix.AsTime(),
+	}
+
+	if attempt.DoerID != 0 {
+		doer, _ := user_model.GetPossibleUserByID(ctx, attempt.DoerID)
+		apiAttempt.Doer = ToUser(ctx, doer, nil)
+	}
+
+	return apiAttempt
+}
+
+// ToFlakeRate converts git_model.FlakeRate to api.FlakeRate
+func ToFlakeRate(rate *git_model.FlakeRate) *api.FlakeRate {
+	return &api.FlakeRate{
+		Context:      rate.Context,
+		Commits:      rate.Commits,
+		FlakyCommits: rate.FlakyCommits,
+		Rate:         rate.Rate(),
+		Retries:      rate.Retries,
+		AutoRetries:  rate.AutoRetries,
+	}
+}
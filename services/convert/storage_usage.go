@@ -0,0 +1,45 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToStorageUsage converts a storage usage record to its API format
+func ToStorageUsage(ownerName string, repo *repo_model.Repository, u *repo_model.StorageUsage) *api.StorageUsage {
+	result := &api.StorageUsage{
+		Owner:          ownerName,
+		GitSize:        u.GitSize,
+		LFSSize:        u.LFSSize,
+		AttachmentSize: u.AttachmentSize,
+		PackageSize:    u.PackageSize,
+		ActionsSize:    u.ActionsSize,
+		TotalSize:      u.TotalSize,
+		Recorded:       u.CreatedUnix.AsTime(),
+	}
+	if repo != nil {
+		result.Repo = repo.FullName()
+	}
+	return result
+}
+
+// ToStorageUsageTrend converts the storage usage records of an owner or a repository to a trend
+func ToStorageUsageTrend(ownerName string, repo *repo_model.Repository, usages []*repo_model.StorageUsage) *api.StorageUsageTrend {
+	trend := &api.StorageUsageTrend{
+		Owner:   ownerName,
+		History: make([]*api.StorageUsage, 0, len(usages)),
+	}
+	if repo != nil {
+		trend.Repo = repo.FullName()
+	}
+	for _, u := range usages {
+		trend.History = append(trend.History, ToStorageUsage(ownerName, repo, u))
+	}
+	if len(usages) > 0 {
+		trend.TotalSizeChange = usages[len(usages)-1].TotalSize - usages[0].TotalSize
+	}
+	return trend
+}
@@ -0,0 +1,20 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToTemplateCatalogItem converts a template of a catalog to its API format
+func ToTemplateCatalogItem(item *repo_model.TemplateCatalogItem) *api.TemplateCatalogItem {
+	return &api.TemplateCatalogItem{
+		Kind:    string(item.Kind),
+		Name:    item.Name,
+		Content: item.Content,
+		Created: item.CreatedUnix.AsTime(),
+		Updated: item.UpdatedUnix.AsTime(),
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	"code.gitea.io/gitea/models/organization"
+	system_model "code.gitea.io/gitea/models/system"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToTheme converts a theme to API format
+func ToTheme(t *system_model.Theme) *api.Theme {
+	variables := t.Variables
+	if variables == nil {
+		variables = map[string]string{}
+	}
+	return &api.Theme{
+		Name:          t.Name,
+		DisplayName:   t.DisplayName,
+		Variables:     variables,
+		LogoURL:       t.LogoURL(),
+		StylesheetURL: t.StylesheetURL(),
+		OrgBranding:   t.OrgBranding,
+		Created:       t.CreatedUnix.AsTime(),
+		Updated:       t.UpdatedUnix.AsTime(),
+	}
+}
+
+// ToThemes converts a list of themes to API format
+func ToThemes(themes []*system_model.Theme) []*api.Theme {
+	result := make([]*api.Theme, len(themes))
+	for i, t := range themes {
+		result[i] = ToTheme(t)
+	}
+	return result
+}
+
+// ToOrgBranding converts the branding of an organization to API format, its theme must have been loaded
+func ToOrgBranding(b *organization.Branding) *api.OrgBranding {
+	result := &api.OrgBranding{
+		AccentColor: b.AccentColor,
+		Updated:     b.UpdatedUnix.AsTime(),
+	}
+	if b.Theme != nil {
+		result.Theme = ToTheme(b.Theme)
+	}
+	return result
+}
@@ -0,0 +1,23 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	system_model "code.gitea.io/gitea/models/system"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToTranslationOverrides converts a list of translation overrides to API format
+func ToTranslationOverrides(overrides []*system_model.TranslationOverride) []*api.TranslationOverride {
+	result := make([]*api.TranslationOverride, len(overrides))
+	for i, o := range overrides {
+		result[i] = &api.TranslationOverride{
+			Lang:    o.Lang,
+			Key:     o.TrKey,
+			Message: o.Message,
+			Updated: o.UpdatedUnix.AsTime(),
+		}
+	}
+	return result
+}
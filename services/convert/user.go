@@ -86,18 +86,49 @@ func toUser(ctx context.Context, user *user_model.User, signed, authed bool) *ap
 // User2UserSettings return UserSettings based on a user
 func User2UserSettings(user *user_model.User) api.UserSettings {
 	return api.UserSettings{
-		FullName:      user.FullName,
-		Website:       user.Website,
-		Location:      user.Location,
-		Language:      user.Language,
-		Description:   user.Description,
-		Theme:         user.Theme,
-		HideEmail:     user.KeepEmailPrivate,
-		HideActivity:  user.KeepActivityPrivate,
-		DiffViewStyle: user.DiffViewStyle,
+		FullName:             user.FullName,
+		Website:              user.Website,
+		Location:             user.Location,
+		Language:             user.Language,
+		Description:          user.Description,
+		Theme:                user.Theme,
+		HideEmail:            user.KeepEmailPrivate,
+		HideActivity:         user.KeepActivityPrivate,
+		DiffViewStyle:        user.DiffViewStyle,
+		CountPrivateActivity: user.CountPrivateActivity,
 	}
 }
 
+// ToLinkedIdentity convert user_model.LinkedIdentity to api.LinkedIdentity
+func ToLinkedIdentity(identity *user_model.LinkedIdentity) *api.LinkedIdentity {
+	result := &api.LinkedIdentity{
+		ID:             identity.ID,
+		InstanceURL:    identity.InstanceURL,
+		RemoteUsername: identity.RemoteUsername,
+		Verified:       identity.IsVerified,
+		Created:        identity.CreatedUnix.AsTime(),
+	}
+	if !identity.IsVerified {
+		result.Token = identity.Token
+	}
+	return result
+}
+
+// ToUserStatus convert user_model.UserStatus to api.UserStatus
+func ToUserStatus(status *user_model.UserStatus) *api.UserStatus {
+	result := &api.UserStatus{
+		Emoji:   status.Emoji,
+		Message: status.Message,
+		Busy:    status.IsBusy,
+		Updated: status.UpdatedUnix.AsTime(),
+	}
+	if status.EndUnix > 0 {
+		endsAt := status.EndUnix.AsTime()
+		result.EndsAt = &endsAt
+	}
+	return result
+}
+
 // ToUserAndPermission return User and its collaboration permission for a repository
 func ToUserAndPermission(ctx context.Context, user, doer *user_model.User, accessMode perm.AccessMode) api.RepoCollaboratorPermission {
 	return api.RepoCollaboratorPermission{
@@ -0,0 +1,29 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToUserRedirects converts a list of redirects of former user names to API format
+func ToUserRedirects(redirects []*user_model.Redirect) []*api.UserRedirect {
+	result := make([]*api.UserRedirect, len(redirects))
+	for i, r := range redirects {
+		result[i] = &api.UserRedirect{
+			Name:    r.LowerName,
+			Created: r.CreatedUnix.AsTime(),
+		}
+		if expires := r.ExpiresUnix(); expires > 0 {
+			t := expires.AsTime()
+			result[i].Expires = &t
+		}
+		if reservedUntil := r.ReservedUntilUnix(); reservedUntil > 0 {
+			t := reservedUntil.AsTime()
+			result[i].ReservedUntil = &t
+		}
+	}
+	return result
+}
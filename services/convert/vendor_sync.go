@@ -0,0 +1,37 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	repo_model "code.gitea.io/gitea/models/repo"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// ToVendorSync converts a vendor sync to its API format, the secret access key is never returned
+func ToVendorSync(s *repo_model.VendorSync) *api.VendorSync {
+	result := &api.VendorSync{
+		ID:              s.ID,
+		Branch:          s.Branch,
+		TargetPath:      s.TargetPath,
+		SourceType:      string(s.SourceType),
+		SourceURL:       s.SourceURL,
+		Bucket:          s.Bucket,
+		Prefix:          s.Prefix,
+		AccessKeyID:     s.AccessKeyID,
+		StripComponents: s.StripComponents,
+		Interval:        s.Interval.String(),
+		LastStatus:      s.LastStatus.String(),
+		LastMessage:     s.LastMessage,
+		LastDigest:      s.LastDigest,
+		LastVersion:     s.LastVersion,
+		LastCommitID:    s.LastCommitID,
+		NextSync:        s.NextSyncUnix.AsTime(),
+		Created:         s.CreatedUnix.AsTime(),
+	}
+	if s.LastSyncUnix > 0 {
+		lastSync := s.LastSyncUnix.AsTime()
+		result.LastSync = &lastSync
+	}
+	return result
+}
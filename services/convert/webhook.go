@@ -0,0 +1,39 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package convert
+
+import (
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+)
+
+// ToInstanceHook converts a webhook to the API format used to list all webhooks of the instance,
+// secrets and authorization headers are left out.
+func ToInstanceHook(w *webhook_model.Webhook, lastDelivered timeutil.TimeStampNano) *api.InstanceHook {
+	hook := &api.InstanceHook{
+		ID:              w.ID,
+		Type:            w.Type,
+		TargetURL:       w.URL,
+		RepoID:          w.RepoID,
+		OwnerID:         w.OwnerID,
+		IsSystemWebhook: w.IsSystemWebhook,
+		Events:          w.EventsArray(),
+		Active:          w.IsActive,
+		Updated:         w.UpdatedUnix.AsTime(),
+		Created:         w.CreatedUnix.AsTime(),
+	}
+	switch w.LastStatus {
+	case webhook_module.HookStatusSucceed:
+		hook.LastStatus = "succeed"
+	case webhook_module.HookStatusFail:
+		hook.LastStatus = "fail"
+	}
+	if lastDelivered != 0 {
+		t := lastDelivered.AsTime()
+		hook.LastDelivered = &t
+	}
+	return hook
+}
@@ -0,0 +1,148 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"context"
+	"fmt"
+
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/coverage"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	badge_service "code.gitea.io/gitea/services/badge"
+	"code.gitea.io/gitea/services/gitdiff"
+)
+
+// UploadOptions are the options to upload the test coverage of a commit
+type UploadOptions struct {
+	CommitSHA string
+	// Branch defaults to the default branch of the repository
+	Branch string
+	// Percentage of covered lines, ignored if a report is given
+	Coverage *float64
+	Format   string
+	Report   []byte
+	// PathPrefix is removed from the paths of the files of the report
+	PathPrefix string
+}
+
+// UploadReport records the test coverage of a commit. If a report is given, the coverage of the lines of its files
+// is recorded too.
+func UploadReport(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, opts *UploadOptions) (*git_model.CoverageReport, error) {
+	if !git.IsValidSHAPattern(opts.CommitSHA) {
+		return nil, util.NewInvalidArgumentErrorf("%q is not a valid commit SHA", opts.CommitSHA)
+	}
+
+	report := &git_model.CoverageReport{
+		RepoID:    repo.ID,
+		Branch:    opts.Branch,
+		CommitSHA: opts.CommitSHA,
+		CreatorID: doer.ID,
+	}
+	if report.Branch == "" {
+		report.Branch = repo.DefaultBranch
+	}
+
+	var files []*git_model.CoverageReportFile
+	switch {
+	case len(opts.Report) > 0:
+		parsed, err := coverage.Parse(opts.Format, opts.Report, opts.PathPrefix)
+		if err != nil {
+			return nil, err
+		}
+		report.Coverage = parsed.Percentage
+		files = make([]*git_model.CoverageReportFile, 0, len(parsed.Files))
+		for _, f := range parsed.Files {
+			covered, uncovered := f.Lines()
+			files = append(files, &git_model.CoverageReportFile{
+				Path:           f.Path,
+				CoveredLines:   covered,
+				UncoveredLines: uncovered,
+			})
+		}
+	case opts.Coverage != nil:
+		report.Coverage = *opts.Coverage
+	default:
+		return nil, util.NewInvalidArgumentErrorf("either the coverage or a report is required")
+	}
+
+	if err := git_model.InsertCoverageReport(ctx, report, files); err != nil {
+		return nil, err
+	}
+	badge_service.InvalidateCoverageBadge(repo, report.Branch)
+	return report, nil
+}
+
+// DiffCoverage is the test coverage of the lines added by a pull request
+type DiffCoverage struct {
+	// Report is the coverage report of the head commit of the pull request
+	Report            *git_model.CoverageReport
+	CoveredLines      int
+	InstrumentedLines int
+}
+
+// Percentage returns the percentage of the added lines which are covered, a pull request which doesn't add
+// instrumented lines is fully covered
+func (c *DiffCoverage) Percentage() float64 {
+	if c.InstrumentedLines == 0 {
+		return 100
+	}
+	return float64(c.CoveredLines) * 100 / float64(c.InstrumentedLines)
+}
+
+// Satisfies checks that the diff coverage reaches the minimum percentage
+func (c *DiffCoverage) Satisfies(minPercentage int64) bool {
+	return c.Percentage() >= float64(minPercentage)
+}
+
+// getPullRequestHeadReport returns the coverage report of the head commit of a pull request, or nil if none was
+// uploaded. Reports are uploaded to the base repository, also for pull requests from forks.
+func getPullRequestHeadReport(ctx context.Context, pr *issues_model.PullRequest, gitRepo *git.Repository) (*git_model.CoverageReport, error) {
+	headCommitID, err := gitRepo.GetRefCommitID(pr.GetGitRefName())
+	if err != nil {
+		return nil, err
+	}
+	return git_model.GetLatestCoverageReportByCommit(ctx, pr.BaseRepoID, headCommitID)
+}
+
+// GetPullRequestDiffCoverage returns the test coverage of the lines added by a pull request, or nil if no coverage
+// report was uploaded for its head commit
+func GetPullRequestDiffCoverage(ctx context.Context, pr *issues_model.PullRequest) (*DiffCoverage, error) {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return nil, err
+	}
+	gitRepo, closer, err := git.RepositoryFromContextOrOpen(ctx, pr.BaseRepo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	report, err := getPullRequestHeadReport(ctx, pr, gitRepo)
+	if err != nil || report == nil {
+		return nil, err
+	}
+
+	diff, err := gitdiff.GetDiff(gitRepo, &gitdiff.DiffOptions{
+		BeforeCommitID:    pr.MergeBase,
+		AfterCommitID:     report.CommitSHA,
+		MaxLines:          -1,
+		MaxLineCharacters: setting.Git.MaxGitDiffLineCharacters,
+		MaxFiles:          -1,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("GetDiff: %w", err)
+	}
+	if err := diff.LoadCoverage(ctx, report); err != nil {
+		return nil, err
+	}
+
+	c := &DiffCoverage{Report: report}
+	c.CoveredLines, c.InstrumentedLines = diff.CountAddedLinesCoverage()
+	return c, nil
+}
@@ -0,0 +1,21 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package coverage
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffCoverage(t *testing.T) {
+	c := &DiffCoverage{}
+	assert.EqualValues(t, 100, c.Percentage())
+	assert.True(t, c.Satisfies(100))
+
+	c = &DiffCoverage{CoveredLines: 3, InstrumentedLines: 4}
+	assert.EqualValues(t, 75, c.Percentage())
+	assert.True(t, c.Satisfies(75))
+	assert.False(t, c.Satisfies(80))
+}
@@ -18,6 +18,8 @@ func initActionsTasks() {
 	registerStopZombieTasks()
 	registerStopEndlessTasks()
 	registerCancelAbandonedJobs()
+	registerCleanupArtifacts()
+	registerCleanupExpiredLogs()
 }
 
 func registerStopZombieTasks() {
@@ -49,3 +51,23 @@ func registerCancelAbandonedJobs() {
 		return actions_service.CancelAbandonedJobs(ctx)
 	})
 }
+
+func registerCleanupArtifacts() {
+	RegisterTaskFatal("cleanup_artifacts", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@midnight",
+	}, func(ctx context.Context, _ *user_model.User, cfg Config) error {
+		return actions_service.CleanupArtifacts(ctx)
+	})
+}
+
+func registerCleanupExpiredLogs() {
+	RegisterTaskFatal("cleanup_expired_logs", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: true,
+		Schedule:   "@midnight",
+	}, func(ctx context.Context, _ *user_model.User, cfg Config) error {
+		return actions_service.CleanupExpiredLogs(ctx)
+	})
+}
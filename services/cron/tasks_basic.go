@@ -13,12 +13,21 @@ import (
 	"code.gitea.io/gitea/models/webhook"
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/setting"
+	attachment_service "code.gitea.io/gitea/services/attachment"
 	"code.gitea.io/gitea/services/auth"
+	insights_service "code.gitea.io/gitea/services/insights"
+	issue_service "code.gitea.io/gitea/services/issue"
+	"code.gitea.io/gitea/services/mailer"
 	"code.gitea.io/gitea/services/migrations"
 	mirror_service "code.gitea.io/gitea/services/mirror"
+	org_service "code.gitea.io/gitea/services/org"
 	packages_cleanup_service "code.gitea.io/gitea/services/packages/cleanup"
+	pull_service "code.gitea.io/gitea/services/pull"
+	reminder_service "code.gitea.io/gitea/services/reminder"
 	repo_service "code.gitea.io/gitea/services/repository"
 	archiver_service "code.gitea.io/gitea/services/repository/archiver"
+	stale_service "code.gitea.io/gitea/services/stale"
+	"code.gitea.io/gitea/services/vendorsync"
 )
 
 func registerUpdateMirrorTask() {
@@ -156,6 +165,148 @@ func registerCleanupPackages() {
 	})
 }
 
+func registerStaleIssues() {
+	type StaleIssuesConfig struct {
+		BaseConfig
+		OperationsPerRepo int
+	}
+	RegisterTaskFatal("stale_issues", &StaleIssuesConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@midnight",
+		},
+		OperationsPerRepo: 30,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		return stale_service.ProcessAll(ctx, config.(*StaleIssuesConfig).OperationsPerRepo)
+	})
+}
+
+func registerIssueLocks() {
+	type LockClosedIssuesConfig struct {
+		BaseConfig
+		OperationsPerRepo int
+	}
+	RegisterTaskFatal("lock_closed_issues", &LockClosedIssuesConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@midnight",
+		},
+		OperationsPerRepo: 100,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		return issue_service.LockClosedIssues(ctx, config.(*LockClosedIssuesConfig).OperationsPerRepo)
+	})
+	RegisterTaskFatal("unlock_expired_issues", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return issue_service.UnlockExpiredIssues(ctx)
+	})
+}
+
+func registerSyncForks() {
+	RegisterTaskFatal("sync_forks", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return pull_service.SyncForks(ctx)
+	})
+}
+
+func registerComputeInsights() {
+	RegisterTaskFatal("compute_insights", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return insights_service.ComputeMetrics(ctx)
+	})
+}
+
+func registerComputePathOwnership() {
+	RegisterTaskFatal("compute_path_ownership", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return insights_service.ComputePathOwnership(ctx)
+	})
+}
+
+func registerTeamReminders() {
+	RegisterTaskFatal("team_reminders", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 5m",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return reminder_service.ProcessDue(ctx)
+	})
+}
+
+func registerSyncTeamGroups() {
+	RegisterTaskFatal("sync_team_groups", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return org_service.SyncAllTeamGroups(ctx)
+	})
+}
+
+func registerMailDigests() {
+	RegisterTaskFatal("send_hourly_mail_digests", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return mailer.SendMailDigests(ctx, user_model.EmailDigestHourly)
+	})
+	RegisterTaskFatal("send_daily_mail_digests", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@midnight",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return mailer.SendMailDigests(ctx, user_model.EmailDigestDaily)
+	})
+}
+
+func registerGCAttachmentBlobs() {
+	RegisterTaskFatal("gc_attachment_blobs", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@midnight",
+		},
+		OlderThan: 24 * time.Hour,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		realConfig := config.(*OlderThanConfig)
+		return attachment_service.GarbageCollectBlobs(ctx, setting.Attachment.Deduplicate, realConfig.OlderThan)
+	})
+}
+
+func registerSyncVendoredData() {
+	RegisterTaskFatal("sync_vendored_data", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 10m",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return vendorsync.SyncDueVendorSyncs(ctx)
+	})
+}
+
+func registerPurgeDeletedRepositories() {
+	RegisterTaskFatal("purge_deleted_repos", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return repo_service.PurgeExpiredRepositories(ctx)
+	})
+}
+
 func initBasicTasks() {
 	if setting.Mirror.Enabled {
 		registerUpdateMirrorTask()
@@ -169,6 +320,19 @@ func initBasicTasks() {
 		registerUpdateMigrationPosterID()
 	}
 	registerCleanupHookTaskTable()
+	registerStaleIssues()
+	registerIssueLocks()
+	registerSyncForks()
+	registerComputeInsights()
+	registerComputePathOwnership()
+	registerTeamReminders()
+	registerSyncTeamGroups()
+	if setting.MailService != nil {
+		registerMailDigests()
+	}
+	registerGCAttachmentBlobs()
+	registerSyncVendoredData()
+	registerPurgeDeletedRepositories()
 	if setting.Packages.Enabled {
 		registerCleanupPackages()
 	}
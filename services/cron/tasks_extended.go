@@ -15,8 +15,10 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/updatechecker"
+	"code.gitea.io/gitea/services/mailer"
 	repo_service "code.gitea.io/gitea/services/repository"
 	archiver_service "code.gitea.io/gitea/services/repository/archiver"
+	files_service "code.gitea.io/gitea/services/repository/files"
 	user_service "code.gitea.io/gitea/services/user"
 )
 
@@ -65,6 +67,56 @@ func registerGarbageCollectRepositories() {
 	})
 }
 
+// RepoMaintenanceConfig represents the configuration of the repository maintenance task
+type RepoMaintenanceConfig struct {
+	BaseConfig
+	Timeout               time.Duration
+	Args                  []string `delim:" "`
+	LooseObjectsThreshold int64
+	PacksThreshold        int64
+	MaxConcurrency        int
+	OffPeakStartHour      int
+	OffPeakEndHour        int
+}
+
+// ToMaintenanceOptions converts the configuration to the options of the maintenance service
+func (c *RepoMaintenanceConfig) ToMaintenanceOptions() *repo_service.MaintenanceOptions {
+	return &repo_service.MaintenanceOptions{
+		LooseObjectsThreshold: c.LooseObjectsThreshold,
+		PacksThreshold:        c.PacksThreshold,
+		MaxConcurrency:        c.MaxConcurrency,
+		OffPeakStartHour:      c.OffPeakStartHour,
+		OffPeakEndHour:        c.OffPeakEndHour,
+		Timeout:               c.Timeout,
+		// the git args are set by config, they can be safe to be trusted
+		GCArgs: git.ToTrustedCmdArgs(c.Args),
+	}
+}
+
+// GetRepoMaintenanceOptions returns the configured options of the repository maintenance task
+func GetRepoMaintenanceOptions() *repo_service.MaintenanceOptions {
+	return GetTask("repo_maintenance").config.(*RepoMaintenanceConfig).ToMaintenanceOptions()
+}
+
+func registerRepositoryMaintenance() {
+	RegisterTaskFatal("repo_maintenance", &RepoMaintenanceConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    false,
+			RunAtStart: false,
+			Schedule:   "@every 1h",
+		},
+		Timeout:               time.Duration(setting.Git.Timeout.GC) * time.Second,
+		Args:                  setting.Git.GCArgs,
+		LooseObjectsThreshold: 1000,
+		PacksThreshold:        50,
+		MaxConcurrency:        2,
+		OffPeakStartHour:      1,
+		OffPeakEndHour:        5,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		return repo_service.RunMaintenance(ctx, config.(*RepoMaintenanceConfig).ToMaintenanceOptions())
+	})
+}
+
 func registerRewriteAllPublicKeys() {
 	RegisterTaskFatal("resync_all_sshkeys", &BaseConfig{
 		Enabled:    false,
@@ -171,6 +223,23 @@ func registerDeleteOldSystemNotices() {
 	})
 }
 
+func registerDeleteOldMailDeliveries() {
+	if setting.MailService == nil {
+		return
+	}
+	RegisterTaskFatal("delete_old_mail_deliveries", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    false,
+			RunAtStart: false,
+			Schedule:   "@every 168h",
+		},
+		OlderThan: 90 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		olderThanConfig := config.(*OlderThanConfig)
+		return system.DeleteOldMailDeliveries(ctx, olderThanConfig.OlderThan)
+	})
+}
+
 func registerGCLFS() {
 	if !setting.LFS.StartServer {
 		return
@@ -213,10 +282,111 @@ func registerGCLFS() {
 	})
 }
 
+func registerRecordStorageUsage() {
+	RegisterTaskFatal("record_storage_usage", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@midnight",
+		},
+		OlderThan: 365 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		olderThanConfig := config.(*OlderThanConfig)
+		return repo_service.RecordStorageUsage(ctx, olderThanConfig.OlderThan)
+	})
+}
+
+func registerArchiveInactiveRepositories() {
+	type ArchiveInactiveConfig struct {
+		BaseConfig
+		InactiveMonths int
+		NoticeDays     int
+		ExemptTopics   []string `delim:","`
+	}
+	RegisterTaskFatal("archive_inactive_repos", &ArchiveInactiveConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    false,
+			RunAtStart: false,
+			Schedule:   "@every 24h",
+		},
+		InactiveMonths: 0,
+		NoticeDays:     30,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		archiveConfig := config.(*ArchiveInactiveConfig)
+		return repo_service.ArchiveInactiveRepositories(ctx, &repo_service.ArchivePolicy{
+			InactiveMonths: archiveConfig.InactiveMonths,
+			NoticeDays:     archiveConfig.NoticeDays,
+			ExemptTopics:   archiveConfig.ExemptTopics,
+		})
+	})
+}
+
+func registerDeleteExpiredCollaborations() {
+	RegisterTaskFatal("delete_expired_collaborations", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return repo_service.DeleteExpiredCollaborations(ctx)
+	})
+}
+
+func registerDeleteOldDeployKeyUsages() {
+	RegisterTaskFatal("delete_old_deploy_key_usages", &OlderThanConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 24h",
+		},
+		OlderThan: 90 * 24 * time.Hour,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		olderThanConfig := config.(*OlderThanConfig)
+		return asymkey_model.DeleteOldDeployKeyUsages(ctx, olderThanConfig.OlderThan)
+	})
+}
+
+func registerDeleteExpiredUserRedirects() {
+	RegisterTaskFatal("delete_expired_user_redirects", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 24h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return user_model.DeleteExpiredUserRedirects(ctx)
+	})
+}
+
+func registerDeleteExpiredEditSessions() {
+	RegisterTaskFatal("delete_expired_edit_sessions", &BaseConfig{
+		Enabled:    true,
+		RunAtStart: false,
+		Schedule:   "@every 1h",
+	}, func(ctx context.Context, _ *user_model.User, _ Config) error {
+		return files_service.DeleteExpiredEditSessions(ctx)
+	})
+}
+
+func registerNotifyExpiringKeys() {
+	type NotifyExpiringConfig struct {
+		BaseConfig
+		NoticeDays int
+	}
+	RegisterTaskFatal("notify_expiring_keys", &NotifyExpiringConfig{
+		BaseConfig: BaseConfig{
+			Enabled:    true,
+			RunAtStart: false,
+			Schedule:   "@every 24h",
+		},
+		NoticeDays: 30,
+	}, func(ctx context.Context, _ *user_model.User, config Config) error {
+		return mailer.SendKeyExpiryMails(ctx, config.(*NotifyExpiringConfig).NoticeDays)
+	})
+}
+
 func initExtendedTasks() {
 	registerDeleteInactiveUsers()
 	registerDeleteRepositoryArchives()
 	registerGarbageCollectRepositories()
+	registerRepositoryMaintenance()
 	registerRewriteAllPublicKeys()
 	registerRewriteAllPrincipalKeys()
 	registerRepositoryUpdateHook()
@@ -226,5 +396,13 @@ func initExtendedTasks() {
 	registerDeleteOldActions()
 	registerUpdateGiteaChecker()
 	registerDeleteOldSystemNotices()
+	registerDeleteOldMailDeliveries()
 	registerGCLFS()
+	registerRecordStorageUsage()
+	registerArchiveInactiveRepositories()
+	registerDeleteExpiredCollaborations()
+	registerDeleteOldDeployKeyUsages()
+	registerDeleteExpiredUserRedirects()
+	registerDeleteExpiredEditSessions()
+	registerNotifyExpiringKeys()
 }
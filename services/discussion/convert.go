@@ -0,0 +1,120 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package discussion
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	discussion_model "code.gitea.io/gitea/models/discussion"
+	issues_model "code.gitea.io/gitea/models/issues"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/util"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+// ConvertIssueToDiscussion moves an issue and its plain comments to a new discussion,
+// the issue is closed and locked with a comment linking to the discussion.
+func ConvertIssueToDiscussion(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, category *discussion_model.Category) (*discussion_model.Discussion, error) {
+	if issue.IsPull {
+		return nil, util.NewInvalidArgumentErrorf("pull request %d can not be converted to a discussion", issue.Index)
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+
+	comments, err := issues_model.FindComments(ctx, &issues_model.FindCommentsOptions{
+		IssueID: issue.ID,
+		Type:    issues_model.CommentTypeComment,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	d := &discussion_model.Discussion{
+		RepoID:      issue.RepoID,
+		Repo:        issue.Repo,
+		CategoryID:  category.ID,
+		Category:    category,
+		PosterID:    issue.PosterID,
+		Title:       issue.Title,
+		Content:     issue.Content,
+		CreatedUnix: issue.CreatedUnix,
+	}
+	if err := discussion_model.NewDiscussion(ctx, d); err != nil {
+		return nil, err
+	}
+	replies := make([]*discussion_model.Comment, 0, len(comments))
+	for _, c := range comments {
+		replies = append(replies, &discussion_model.Comment{
+			PosterID:    c.PosterID,
+			Content:     c.Content,
+			CreatedUnix: c.CreatedUnix,
+			UpdatedUnix: c.UpdatedUnix,
+		})
+	}
+	if err := discussion_model.InsertComments(ctx, d, replies); err != nil {
+		return nil, err
+	}
+	notification.NotifyNewDiscussion(ctx, doer, d)
+
+	if _, err := issue_service.CreateIssueComment(ctx, doer, issue.Repo, issue, fmt.Sprintf("Converted to discussion %s", d.HTMLURL()), nil); err != nil {
+		return nil, err
+	}
+	if !issue.IsClosed {
+		if err := issue_service.ChangeStatus(issue, doer, "", true); err != nil {
+			return nil, err
+		}
+	}
+	if !issue.IsLocked {
+		if err := issues_model.LockIssue(&issues_model.IssueLockOptions{Doer: doer, Issue: issue}); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// ConvertDiscussionToIssue opens an issue with the content and replies of a discussion, the discussion is deleted
+func ConvertDiscussionToIssue(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) (*issues_model.Issue, error) {
+	if err := d.LoadAttributes(ctx); err != nil {
+		return nil, err
+	}
+	comments, _, err := discussion_model.FindComments(ctx, d.ID, db.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	issue := &issues_model.Issue{
+		RepoID:   d.RepoID,
+		Repo:     d.Repo,
+		Title:    d.Title,
+		PosterID: d.Poster.ID,
+		Poster:   d.Poster,
+		Content:  d.Content,
+	}
+	if err := issue_service.NewIssue(ctx, d.Repo, issue, nil, nil, nil); err != nil {
+		return nil, err
+	}
+	for _, c := range comments {
+		if err := c.LoadPoster(ctx); err != nil {
+			return nil, err
+		}
+		if _, err := issues_model.CreateComment(ctx, &issues_model.CreateCommentOptions{
+			Type:    issues_model.CommentTypeComment,
+			Doer:    c.Poster,
+			Repo:    d.Repo,
+			Issue:   issue,
+			Content: c.Content,
+		}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := DeleteDiscussion(ctx, doer, d); err != nil {
+		return nil, err
+	}
+	return issue, nil
+}
@@ -0,0 +1,140 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package discussion
+
+import (
+	"context"
+
+	discussion_model "code.gitea.io/gitea/models/discussion"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/services/moderation"
+)
+
+// NewDiscussion starts a discussion in a category of a repository
+func NewDiscussion(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, category *discussion_model.Category, title, content string) (*discussion_model.Discussion, error) {
+	if err := moderation.CheckInteraction(ctx, doer, repo); err != nil {
+		return nil, err
+	}
+
+	d := &discussion_model.Discussion{
+		RepoID:     repo.ID,
+		Repo:       repo,
+		CategoryID: category.ID,
+		Category:   category,
+		PosterID:   doer.ID,
+		Poster:     doer,
+		Title:      title,
+		Content:    content,
+	}
+	if err := discussion_model.NewDiscussion(ctx, d); err != nil {
+		return nil, err
+	}
+
+	notification.NotifyNewDiscussion(ctx, doer, d)
+	return d, nil
+}
+
+// EditDiscussion changes the title, content and category of a discussion
+func EditDiscussion(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, title, content string, category *discussion_model.Category) error {
+	d.Title = title
+	d.Content = content
+	cols := []string{"title", "content"}
+	if category.ID != d.CategoryID {
+		// an answer only means something for questions
+		if category.Kind != discussion_model.CategoryKindQA {
+			d.AnswerID = 0
+		}
+		d.CategoryID = category.ID
+		d.Category = category
+		cols = append(cols, "category_id", "answer_id")
+	}
+	if err := discussion_model.UpdateDiscussionCols(ctx, d, cols...); err != nil {
+		return err
+	}
+
+	notification.NotifyDiscussionChangeContent(ctx, doer, d)
+	return nil
+}
+
+// SetDiscussionLocked locks or unlocks a discussion, only writers can reply to locked discussions
+func SetDiscussionLocked(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, locked bool) error {
+	if d.IsLocked == locked {
+		return nil
+	}
+	d.IsLocked = locked
+	if err := discussion_model.UpdateDiscussionCols(ctx, d, "is_locked"); err != nil {
+		return err
+	}
+
+	notification.NotifyDiscussionChangeLock(ctx, doer, d)
+	return nil
+}
+
+// SetDiscussionAnswer accepts a reply as answer of a question, 0 removes the answer
+func SetDiscussionAnswer(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, commentID int64) error {
+	if d.AnswerID == commentID {
+		return nil
+	}
+	if err := discussion_model.SetAnswer(ctx, d, commentID); err != nil {
+		return err
+	}
+
+	notification.NotifyDiscussionChangeAnswer(ctx, doer, d)
+	return nil
+}
+
+// DeleteDiscussion removes a discussion and its replies
+func DeleteDiscussion(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) error {
+	if err := discussion_model.DeleteDiscussion(ctx, d); err != nil {
+		return err
+	}
+
+	notification.NotifyDeleteDiscussion(ctx, doer, d)
+	return nil
+}
+
+// CreateComment replies to a discussion
+func CreateComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, content string) (*discussion_model.Comment, error) {
+	if err := d.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	if err := moderation.CheckInteraction(ctx, doer, d.Repo); err != nil {
+		return nil, err
+	}
+
+	c := &discussion_model.Comment{
+		PosterID: doer.ID,
+		Poster:   doer,
+		Content:  content,
+	}
+	if err := discussion_model.CreateComment(ctx, d, c); err != nil {
+		return nil, err
+	}
+
+	notification.NotifyCreateDiscussionComment(ctx, doer, d, c)
+	return c, nil
+}
+
+// UpdateComment changes the content of a reply
+func UpdateComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment, content string) error {
+	c.Content = content
+	if err := discussion_model.UpdateComment(ctx, c); err != nil {
+		return err
+	}
+
+	notification.NotifyUpdateDiscussionComment(ctx, doer, d, c)
+	return nil
+}
+
+// DeleteComment removes a reply
+func DeleteComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) error {
+	if err := discussion_model.DeleteComment(ctx, d, c); err != nil {
+		return err
+	}
+
+	notification.NotifyDeleteDiscussionComment(ctx, doer, d, c)
+	return nil
+}
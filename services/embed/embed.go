@@ -0,0 +1,269 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package embed
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/models/db"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CacheSeconds is how long a widget is cached by the clients
+const CacheSeconds = 60
+
+// LatestCount is the number of releases and of actions shown by the widgets
+const LatestCount = 5
+
+// ErrOriginNotAllowed is returned when a site which isn't an allowed origin of the token embeds a widget
+var ErrOriginNotAllowed = util.NewPermissionDeniedErrorf("the origin is not allowed to embed the widget")
+
+// errWidgetNotExist hides why a widget can't be embedded, like the widgets of unknown repositories
+var errWidgetNotExist = util.NewNotExistErrorf("widget does not exist")
+
+// widgetUnitTypes returns the units which the creator of the token must be able to read to embed a widget
+func widgetUnitTypes(widget repo_model.EmbedWidget) []unit.Type {
+	switch widget {
+	case repo_model.EmbedWidgetIssues:
+		return []unit.Type{unit.TypeIssues, unit.TypePullRequests}
+	case repo_model.EmbedWidgetReleases:
+		return []unit.Type{unit.TypeReleases}
+	}
+	return []unit.Type{unit.TypeCode}
+}
+
+// RequestOrigin returns the origin of the site which embeds a widget: the Origin header sent by the scripts, or the
+// origin of the Referer sent by the frames. It is empty if the browser sent neither.
+func RequestOrigin(req *http.Request) string {
+	if origin := req.Header.Get("Origin"); origin != "" && origin != "null" {
+		return origin
+	}
+	if referer, err := url.Parse(req.Referer()); err == nil && referer.Scheme != "" && referer.Host != "" {
+		return referer.Scheme + "://" + referer.Host
+	}
+	return ""
+}
+
+// isInstanceOrigin returns true if the origin is Gitea itself, e.g. to preview the widgets
+func isInstanceOrigin(origin string) bool {
+	appURL, err := url.Parse(setting.AppURL)
+	if err != nil {
+		return false
+	}
+	return strings.EqualFold(origin, appURL.Scheme+"://"+appURL.Host)
+}
+
+// Access is what an embed token grants to a request for a widget
+type Access struct {
+	Token   *repo_model.EmbedToken
+	Creator *user_model.User
+	Perm    access_model.Permission
+}
+
+// CheckAccess checks that the token allows the origin of the request to embed the widget of the repository. The
+// widgets show at most what the creator of the token may read, they stop working when the creator loses access.
+func CheckAccess(ctx context.Context, repo *repo_model.Repository, widget repo_model.EmbedWidget, token, origin string) (*Access, error) {
+	t, err := repo_model.GetEmbedTokenByToken(ctx, repo.ID, token)
+	if err != nil {
+		if repo_model.IsErrEmbedTokenNotExist(err) {
+			return nil, errWidgetNotExist
+		}
+		return nil, err
+	}
+	if !t.HasWidget(widget) {
+		return nil, errWidgetNotExist
+	}
+	if origin != "" && !t.IsOriginAllowed(origin) && !isInstanceOrigin(origin) {
+		return nil, ErrOriginNotAllowed
+	}
+
+	creator, err := user_model.GetUserByID(ctx, t.CreatorID)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			return nil, errWidgetNotExist
+		}
+		return nil, err
+	}
+	if !creator.IsActive || creator.ProhibitLogin {
+		return nil, errWidgetNotExist
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, repo, creator)
+	if err != nil {
+		return nil, err
+	}
+	if !perm.CanReadAny(widgetUnitTypes(widget)...) {
+		return nil, errWidgetNotExist
+	}
+	return &Access{Token: t, Creator: creator, Perm: perm}, nil
+}
+
+// IssueCounts are the counters of the issues widget, the counters of units which can't be read are nil
+type IssueCounts struct {
+	OpenIssues   *int `json:"open_issues,omitempty"`
+	ClosedIssues *int `json:"closed_issues,omitempty"`
+	OpenPulls    *int `json:"open_pulls,omitempty"`
+	ClosedPulls  *int `json:"closed_pulls,omitempty"`
+}
+
+// Release is a release of the releases widget
+type Release struct {
+	TagName      string    `json:"tag_name"`
+	Title        string    `json:"title"`
+	URL          string    `json:"url"`
+	IsPrerelease bool      `json:"prerelease"`
+	PublishedAt  time.Time `json:"published_at"`
+}
+
+// Activity is an action of the activity widget
+type Activity struct {
+	OpType  string    `json:"op_type"`
+	Actor   string    `json:"actor"`
+	Title   string    `json:"title"`
+	URL     string    `json:"url"`
+	Created time.Time `json:"created"`
+}
+
+// Widget is the data of an embedded widget
+type Widget struct {
+	Widget     repo_model.EmbedWidget `json:"widget"`
+	Repository string                 `json:"repository"`
+	URL        string                 `json:"url"`
+	Issues     *IssueCounts           `json:"issues,omitempty"`
+	Releases   []*Release             `json:"releases,omitempty"`
+	Activity   []*Activity            `json:"activity,omitempty"`
+}
+
+// GetWidget returns the data of a widget of a repository which the access allows to embed
+func GetWidget(ctx context.Context, repo *repo_model.Repository, widget repo_model.EmbedWidget, access *Access) (*Widget, error) {
+	w := &Widget{Widget: widget, Repository: repo.FullName(), URL: repo.HTMLURL()}
+	switch widget {
+	case repo_model.EmbedWidgetIssues:
+		w.Issues = getIssueCounts(repo, access)
+	case repo_model.EmbedWidgetReleases:
+		releases, err := getReleases(ctx, repo)
+		if err != nil {
+			return nil, err
+		}
+		w.Releases = releases
+	case repo_model.EmbedWidgetActivity:
+		activity, err := getActivity(ctx, repo, access)
+		if err != nil {
+			return nil, err
+		}
+		w.Activity = activity
+	}
+	return w, nil
+}
+
+func getIssueCounts(repo *repo_model.Repository, access *Access) *IssueCounts {
+	counts := &IssueCounts{}
+	if access.Perm.CanRead(unit.TypeIssues) {
+		counts.OpenIssues, counts.ClosedIssues = &repo.NumOpenIssues, &repo.NumClosedIssues
+	}
+	if access.Perm.CanRead(unit.TypePullRequests) {
+		counts.OpenPulls, counts.ClosedPulls = &repo.NumOpenPulls, &repo.NumClosedPulls
+	}
+	return counts
+}
+
+func getReleases(ctx context.Context, repo *repo_model.Repository) ([]*Release, error) {
+	rels, err := repo_model.GetReleasesByRepoID(ctx, repo.ID, repo_model.FindReleasesOptions{
+		ListOptions: db.ListOptions{PageSize: LatestCount},
+	})
+	if err != nil {
+		return nil, err
+	}
+	releases := make([]*Release, 0, len(rels))
+	for _, rel := range rels {
+		rel.Repo = repo
+		title := rel.Title
+		if title == "" {
+			title = rel.TagName
+		}
+		releases = append(releases, &Release{
+			TagName:      rel.TagName,
+			Title:        title,
+			URL:          rel.HTMLURL(),
+			IsPrerelease: rel.IsPrerelease,
+			PublishedAt:  rel.CreatedUnix.AsTime(),
+		})
+	}
+	return releases, nil
+}
+
+// activityUnitTypes are the actions shown by the activity widget and the units which must be readable to show them
+var activityUnitTypes = map[activities_model.ActionType]unit.Type{
+	activities_model.ActionCommitRepo:           unit.TypeCode,
+	activities_model.ActionPushTag:              unit.TypeCode,
+	activities_model.ActionPublishRelease:       unit.TypeReleases,
+	activities_model.ActionCreateIssue:          unit.TypeIssues,
+	activities_model.ActionCloseIssue:           unit.TypeIssues,
+	activities_model.ActionReopenIssue:          unit.TypeIssues,
+	activities_model.ActionCommentIssue:         unit.TypeIssues,
+	activities_model.ActionCreatePullRequest:    unit.TypePullRequests,
+	activities_model.ActionMergePullRequest:     unit.TypePullRequests,
+	activities_model.ActionClosePullRequest:     unit.TypePullRequests,
+	activities_model.ActionReopenPullRequest:    unit.TypePullRequests,
+	activities_model.ActionCommentPull:          unit.TypePullRequests,
+	activities_model.ActionAutoMergePullRequest: unit.TypePullRequests,
+}
+
+// absoluteURL returns the absolute URL of a link of Gitea
+func absoluteURL(link string) string {
+	return setting.AppURL + strings.TrimPrefix(link, setting.AppSubURL+"/")
+}
+
+func getActivity(ctx context.Context, repo *repo_model.Repository, access *Access) ([]*Activity, error) {
+	actions, _, err := activities_model.GetFeeds(ctx, activities_model.GetFeedsOptions{
+		ListOptions:    db.ListOptions{PageSize: LatestCount * 4},
+		RequestedRepo:  repo,
+		Actor:          access.Creator,
+		IncludePrivate: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	activity := make([]*Activity, 0, LatestCount)
+	for _, act := range actions {
+		unitType, ok := activityUnitTypes[act.OpType]
+		if !ok || !access.Perm.CanRead(unitType) {
+			continue
+		}
+		a := &Activity{
+			OpType:  act.OpType.String(),
+			Actor:   act.GetActUserName(),
+			Created: act.GetCreate(),
+		}
+		switch act.OpType {
+		case activities_model.ActionCommitRepo:
+			a.Title, a.URL = act.GetBranch(), absoluteURL(act.GetRefLink())
+		case activities_model.ActionPushTag, activities_model.ActionPublishRelease:
+			a.Title, a.URL = act.GetTag(), repo.HTMLURL()+"/releases/tag/"+util.PathEscapeSegments(act.GetTag())
+		default:
+			index := act.GetIssueInfos()[0]
+			a.Title, a.URL = "#"+index, repo.HTMLURL()+"/issues/"+url.PathEscape(index)
+		}
+		activity = append(activity, a)
+		if len(activity) == LatestCount {
+			break
+		}
+	}
+	return activity, nil
+}
+
+// URL returns the URL of the page of a widget of a repository with the token, the URL of the JSON data ends with .json
+func URL(repo *repo_model.Repository, widget repo_model.EmbedWidget, token string) string {
+	return repo.HTMLURL() + "/embed/" + string(widget) + "?token=" + url.QueryEscape(token)
+}
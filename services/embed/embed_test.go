@@ -0,0 +1,41 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package embed
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestOrigin(t *testing.T) {
+	req := httptest.NewRequest("GET", "/user2/repo1/embed/issues", nil)
+	assert.Equal(t, "", RequestOrigin(req))
+
+	req.Header.Set("Referer", "https://portal.example.com/some/page?q=1")
+	assert.Equal(t, "https://portal.example.com", RequestOrigin(req))
+
+	req.Header.Set("Origin", "https://other.example.com")
+	assert.Equal(t, "https://other.example.com", RequestOrigin(req))
+
+	// sandboxed frames send a null origin
+	req.Header.Set("Origin", "null")
+	assert.Equal(t, "https://portal.example.com", RequestOrigin(req))
+}
+
+func TestIsInstanceOrigin(t *testing.T) {
+	prevURL := setting.AppURL
+	setting.AppURL = "https://try.gitea.io/sub/"
+	defer func() {
+		setting.AppURL = prevURL
+	}()
+
+	assert.True(t, isInstanceOrigin("https://try.gitea.io"))
+	assert.True(t, isInstanceOrigin("https://TRY.gitea.io"))
+	assert.False(t, isInstanceOrigin("http://try.gitea.io"))
+	assert.False(t, isInstanceOrigin("https://portal.example.com"))
+}
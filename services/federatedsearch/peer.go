@@ -0,0 +1,113 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package federatedsearch
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+)
+
+// maxPeerResponseSize is the maximum number of bytes read from the answer of a peer
+const maxPeerResponseSize = 1 << 20
+
+var peerClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: proxy.Proxy(),
+	},
+}
+
+// Search returns the results of this instance merged with the results of its peers.
+// The peers which can't be searched are reported in the errors of the response.
+func Search(ctx context.Context, opts SearchOptions) (*api.FederatedSearchResponse, error) {
+	local, err := SearchLocal(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	peers := setting.FederatedSearch.Peers
+	lists := make([][]*api.FederatedSearchResult, len(peers)+1)
+	lists[0] = local
+	peerErrors := make([]error, len(peers))
+	var wg sync.WaitGroup
+	for i, peer := range peers {
+		wg.Add(1)
+		go func(i int, peer string) {
+			defer wg.Done()
+			lists[i+1], peerErrors[i] = searchPeer(ctx, peer, opts)
+		}(i, peer)
+	}
+	wg.Wait()
+
+	resp := &api.FederatedSearchResponse{
+		Results: mergeResults(opts.limit(), lists...),
+		Errors:  []*api.FederatedSearchPeerError{},
+	}
+	for i, err := range peerErrors {
+		if err != nil {
+			log.Warn("federated search: unable to search %s: %v", peers[i], err)
+			resp.Errors = append(resp.Errors, &api.FederatedSearchPeerError{Instance: peers[i], Message: err.Error()})
+		}
+	}
+	return resp, nil
+}
+
+// searchPeer requests the local results of a peer
+func searchPeer(ctx context.Context, peer string, opts SearchOptions) ([]*api.FederatedSearchResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, setting.FederatedSearch.Timeout)
+	defer cancel()
+
+	query := url.Values{
+		"q":     {opts.Keyword},
+		"type":  {opts.Type},
+		"limit": {strconv.Itoa(opts.limit())},
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, peer+"/api/v1/federation/search?"+query.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := peerClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	var results []*api.FederatedSearchResult
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxPeerResponseSize)).Decode(&results); err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	return sanitizePeerResults(peer, results, opts.limit()), nil
+}
+
+// sanitizePeerResults attributes the results to the peer which returned them and drops the results
+// which can't be linked to safely
+func sanitizePeerResults(peer string, results []*api.FederatedSearchResult, limit int) []*api.FederatedSearchResult {
+	sanitized := make([]*api.FederatedSearchResult, 0, len(results))
+	for _, r := range results {
+		if len(sanitized) >= limit {
+			break
+		}
+		if r == nil || !(strings.HasPrefix(r.HTMLURL, "https://") || strings.HasPrefix(r.HTMLURL, "http://")) {
+			continue
+		}
+		r.Instance = peer
+		sanitized = append(sanitized, r)
+	}
+	return sanitized
+}
@@ -0,0 +1,361 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package federatedsearch
+
+import (
+	"context"
+	"io"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/modules/git"
+	issue_indexer "code.gitea.io/gitea/modules/indexer/issues"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+	wiki_service "code.gitea.io/gitea/services/wiki"
+
+	"xorm.io/builder"
+)
+
+// The types of the search results
+const (
+	TypeIssue = "issue"
+	TypePull  = "pull"
+	TypeWiki  = "wiki"
+)
+
+const (
+	// snippetContext is the number of bytes around a match which are included in a snippet
+	snippetContext = 80
+	// maxWikiPageSize is the maximum number of bytes of a wiki page read to build a snippet
+	maxWikiPageSize = 1 << 20
+)
+
+// SearchOptions represents the options of a search
+type SearchOptions struct {
+	Keyword string
+	// Type is "issues" to only search issues and pull requests, "wiki" to only search wiki pages
+	Type  string
+	Limit int
+}
+
+func (opts *SearchOptions) limit() int {
+	if opts.Limit <= 0 || opts.Limit > setting.FederatedSearch.MaxResults {
+		return setting.FederatedSearch.MaxResults
+	}
+	return opts.Limit
+}
+
+// InstanceURL returns the base URL this instance is identified by in the results
+func InstanceURL() string {
+	return strings.TrimSuffix(setting.AppURL, "/")
+}
+
+// SearchLocal returns the issues, pull requests and wiki pages of the public repositories of this instance which match the keyword
+func SearchLocal(ctx context.Context, opts SearchOptions) ([]*api.FederatedSearchResult, error) {
+	re := keywordRegexp(opts.Keyword)
+	if re == nil {
+		return []*api.FederatedSearchResult{}, nil
+	}
+
+	var issues, wikis []*api.FederatedSearchResult
+	var err error
+	if opts.Type != "wiki" {
+		if issues, err = searchIssues(ctx, opts.Keyword, re, opts.limit()); err != nil {
+			return nil, err
+		}
+	}
+	if opts.Type != "issues" {
+		if wikis, err = searchWikis(ctx, opts.Keyword, re, opts.limit()); err != nil {
+			return nil, err
+		}
+	}
+	return mergeResults(opts.limit(), issues, wikis), nil
+}
+
+func searchIssues(ctx context.Context, keyword string, re *regexp.Regexp, limit int) ([]*api.FederatedSearchResult, error) {
+	if !issue_indexer.IsAvailable() {
+		log.Warn("federated search: the issue indexer is unavailable, no issues are searched")
+		return nil, nil
+	}
+
+	// issues and pull requests are only found in the repositories which have them enabled
+	issueRepoIDs, err := searchRepoIDsWithUnit(ctx, unit.TypeIssues)
+	if err != nil {
+		return nil, err
+	}
+	pullRepoIDs, err := searchRepoIDsWithUnit(ctx, unit.TypePullRequests)
+	if err != nil {
+		return nil, err
+	}
+	repoIDs := make([]int64, 0, len(issueRepoIDs)+len(pullRepoIDs))
+	for id := range issueRepoIDs {
+		repoIDs = append(repoIDs, id)
+	}
+	for id := range pullRepoIDs {
+		if !issueRepoIDs[id] {
+			repoIDs = append(repoIDs, id)
+		}
+	}
+	if len(repoIDs) == 0 {
+		return nil, nil
+	}
+	issueIDs, err := issue_indexer.SearchIssuesByKeyword(ctx, repoIDs, keyword)
+	if err != nil {
+		return nil, err
+	}
+	if len(issueIDs) > limit {
+		issueIDs = issueIDs[:limit]
+	}
+	issues, err := issues_model.GetIssuesByIDs(ctx, issueIDs)
+	if err != nil {
+		return nil, err
+	}
+	issues = util.SliceRemoveAllFunc(issues, func(issue *issues_model.Issue) bool {
+		if issue.IsPull {
+			return !pullRepoIDs[issue.RepoID]
+		}
+		return !issueRepoIDs[issue.RepoID]
+	})
+	if _, err := issues.LoadRepositories(ctx); err != nil {
+		return nil, err
+	}
+
+	// keep the order of relevance of the indexer
+	issuesByID := make(map[int64]*issues_model.Issue, len(issues))
+	for _, issue := range issues {
+		issuesByID[issue.ID] = issue
+	}
+	results := make([]*api.FederatedSearchResult, 0, len(issues))
+	for _, id := range issueIDs {
+		issue, ok := issuesByID[id]
+		if !ok {
+			continue
+		}
+		tp := TypeIssue
+		if issue.IsPull {
+			tp = TypePull
+		}
+		results = append(results, &api.FederatedSearchResult{
+			Instance: InstanceURL(),
+			Type:     tp,
+			RepoName: issue.Repo.FullName(),
+			Title:    issue.Title,
+			HTMLURL:  issue.HTMLURL(),
+			Snippet:  snippet(issue.Content, re),
+			Updated:  issue.UpdatedUnix.AsTime(),
+		})
+	}
+	return results, nil
+}
+
+// searchRepoIDsWithUnit returns the IDs of the repositories with the unit enabled. Without Private only the public
+// repositories of public users and organizations are found.
+func searchRepoIDsWithUnit(ctx context.Context, unitType unit.Type) (map[int64]bool, error) {
+	if unitType.UnitGlobalDisabled() {
+		return nil, nil
+	}
+	cond := repo_model.SearchRepositoryCondition(&repo_model.SearchRepoOptions{}).
+		And(builder.In("id", builder.Select("repo_id").From("repo_unit").Where(builder.Eq{"type": unitType})))
+	ids, err := repo_model.SearchRepositoryIDsByCondition(ctx, cond)
+	if err != nil {
+		return nil, err
+	}
+	repoIDs := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		repoIDs[id] = true
+	}
+	return repoIDs, nil
+}
+
+func searchWikis(ctx context.Context, keyword string, re *regexp.Regexp, limit int) ([]*api.FederatedSearchResult, error) {
+	opts := &repo_model.SearchRepoOptions{
+		ListOptions: db.ListOptions{PageSize: setting.FederatedSearch.MaxWikiRepos},
+		OrderBy:     db.SearchOrderByRecentUpdated,
+	}
+	cond := repo_model.SearchRepositoryCondition(opts).
+		And(builder.In("id", builder.Select("repo_id").From("repo_unit").Where(builder.Eq{"type": unit.TypeWiki})))
+	repos, _, err := repo_model.SearchRepositoryByCondition(ctx, opts, cond, false)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*api.FederatedSearchResult, 0, limit)
+	for _, repo := range repos {
+		if len(results) >= limit {
+			break
+		}
+		if !repo.HasWiki() {
+			continue
+		}
+		pages, err := searchWiki(ctx, repo, keyword, re, limit-len(results))
+		if err != nil {
+			// a broken wiki mustn't fail the whole search
+			log.Error("federated search: unable to search the wiki of %s: %v", repo.FullName(), err)
+			continue
+		}
+		results = append(results, pages...)
+	}
+	return results, nil
+}
+
+// searchWiki returns the pages of the wiki of the repository whose title or content contain the keyword
+func searchWiki(ctx context.Context, repo *repo_model.Repository, keyword string, re *regexp.Regexp, limit int) ([]*api.FederatedSearchResult, error) {
+	wikiRepo, err := git.OpenRepository(ctx, repo.WikiPath())
+	if err != nil {
+		return nil, err
+	}
+	defer wikiRepo.Close()
+
+	commit, err := wikiRepo.GetBranchCommit(wiki_service.DefaultBranch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	stdout, _, runErr := git.NewCommand(ctx, "grep", "--ignore-case", "--fixed-strings", "--files-with-matches", "-I").
+		AddOptionFormat("--regexp=%s", keyword).
+		AddDynamicArguments(commit.ID.String()).
+		RunStdString(&git.RunOpts{Dir: repo.WikiPath()})
+	// git grep exits with 1 if nothing matches
+	if runErr != nil && !runErr.IsExitCode(1) {
+		return nil, runErr
+	}
+	matched := make(map[string]bool)
+	for _, line := range strings.Split(stdout, "\n") {
+		if line != "" {
+			matched[strings.TrimPrefix(line, commit.ID.String()+":")] = true
+		}
+	}
+
+	entries, err := commit.ListEntries()
+	if err != nil {
+		return nil, err
+	}
+	results := make([]*api.FederatedSearchResult, 0, limit)
+	for _, entry := range entries {
+		if len(results) >= limit {
+			break
+		}
+		if !entry.IsRegular() {
+			continue
+		}
+		webPath, err := wiki_service.GitPathToWebPath(entry.Name())
+		if err != nil {
+			continue
+		}
+		_, title := wiki_service.WebPathToUserTitle(webPath)
+		if !matched[entry.Name()] && !re.MatchString(title) {
+			continue
+		}
+
+		content, err := readWikiPage(entry)
+		if err != nil {
+			return nil, err
+		}
+		pageCommit, err := wikiRepo.GetCommitByPath(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, &api.FederatedSearchResult{
+			Instance: InstanceURL(),
+			Type:     TypeWiki,
+			RepoName: repo.FullName(),
+			Title:    title,
+			HTMLURL:  repo.HTMLURL() + "/wiki/" + wiki_service.WebPathToURLPath(webPath),
+			Snippet:  snippet(content, re),
+			Updated:  pageCommit.Committer.When,
+		})
+	}
+	return results, nil
+}
+
+func readWikiPage(entry *git.TreeEntry) (string, error) {
+	reader, err := entry.Blob().DataAsync()
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+	content, err := io.ReadAll(io.LimitReader(reader, maxWikiPageSize))
+	return string(content), err
+}
+
+// keywordRegexp returns a case insensitive regular expression matching any of the terms of the keyword
+func keywordRegexp(keyword string) *regexp.Regexp {
+	terms := strings.Fields(keyword)
+	if len(terms) == 0 {
+		return nil
+	}
+	for i, term := range terms {
+		terms[i] = regexp.QuoteMeta(term)
+	}
+	return regexp.MustCompile("(?i)" + strings.Join(terms, "|"))
+}
+
+// alignRuneStart moves the offset backwards to the start of a rune
+func alignRuneStart(text string, offset int) int {
+	for offset > 0 && offset < len(text) && !utf8.RuneStart(text[offset]) {
+		offset--
+	}
+	return offset
+}
+
+// snippet returns the plain text around the first match of re, or the beginning of the text if nothing matches.
+// Peers render the snippets of other instances, so they never contain markup.
+func snippet(text string, re *regexp.Regexp) string {
+	start, end := 0, 2*snippetContext
+	if loc := re.FindStringIndex(text); loc != nil {
+		start = loc[0] - snippetContext
+		if start < 0 {
+			start = 0
+		}
+		end = loc[1] + snippetContext
+	}
+	if end > len(text) {
+		end = len(text)
+	}
+	start, end = alignRuneStart(text, start), alignRuneStart(text, end)
+
+	s := strings.Join(strings.Fields(text[start:end]), " ")
+	if start > 0 {
+		s = "…" + s
+	}
+	if end < len(text) {
+		s += "…"
+	}
+	return s
+}
+
+// mergeResults interleaves the lists of results, which are ordered by relevance,
+// until limit results are merged. Results with the same URL are only merged once.
+func mergeResults(limit int, lists ...[]*api.FederatedSearchResult) []*api.FederatedSearchResult {
+	merged := make([]*api.FederatedSearchResult, 0, limit)
+	seen := make(map[string]bool)
+	for i := 0; len(merged) < limit; i++ {
+		more := false
+		for _, list := range lists {
+			if i >= len(list) {
+				continue
+			}
+			more = true
+			if r := list[i]; !seen[r.HTMLURL] && len(merged) < limit {
+				seen[r.HTMLURL] = true
+				merged = append(merged, r)
+			}
+		}
+		if !more {
+			break
+		}
+	}
+	return merged
+}
@@ -0,0 +1,101 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package federatedsearch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/json"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSnippet(t *testing.T) {
+	re := keywordRegexp("gitea")
+	assert.Equal(t, "short text about Gitea", snippet("short  text\nabout Gitea", re))
+
+	text := strings.Repeat("a ", 100) + "federated gitea search" + strings.Repeat(" b", 100)
+	s := snippet(text, re)
+	assert.True(t, strings.HasPrefix(s, "…"))
+	assert.True(t, strings.HasSuffix(s, "…"))
+	assert.Contains(t, s, "federated gitea search")
+
+	// the beginning of the text is returned if nothing matches
+	assert.Equal(t, "no match", snippet("no match", re))
+	assert.Nil(t, keywordRegexp("  "))
+}
+
+func TestSearchRepoIDsWithUnit(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	issueRepoIDs, err := searchRepoIDsWithUnit(db.DefaultContext, unit.TypeIssues)
+	assert.NoError(t, err)
+	assert.True(t, issueRepoIDs[1])
+	// private repositories are never searched
+	assert.False(t, issueRepoIDs[2])
+
+	_, err = db.GetEngine(db.DefaultContext).Delete(&repo_model.RepoUnit{RepoID: 1, Type: unit.TypeIssues})
+	assert.NoError(t, err)
+	issueRepoIDs, err = searchRepoIDsWithUnit(db.DefaultContext, unit.TypeIssues)
+	assert.NoError(t, err)
+	assert.False(t, issueRepoIDs[1])
+	pullRepoIDs, err := searchRepoIDsWithUnit(db.DefaultContext, unit.TypePullRequests)
+	assert.NoError(t, err)
+	assert.True(t, pullRepoIDs[1])
+}
+
+func TestMergeResults(t *testing.T) {
+	result := func(url string) *api.FederatedSearchResult {
+		return &api.FederatedSearchResult{HTMLURL: url}
+	}
+	urls := func(results []*api.FederatedSearchResult) []string {
+		s := make([]string, 0, len(results))
+		for _, r := range results {
+			s = append(s, r.HTMLURL)
+		}
+		return s
+	}
+
+	local := []*api.FederatedSearchResult{result("a1"), result("a2"), result("a3")}
+	peer := []*api.FederatedSearchResult{result("b1"), result("a2")}
+	assert.Equal(t, []string{"a1", "b1", "a2", "a3"}, urls(mergeResults(10, local, peer)))
+	assert.Equal(t, []string{"a1", "b1", "a2"}, urls(mergeResults(3, local, nil, peer)))
+	assert.Empty(t, mergeResults(10))
+}
+
+func TestSearchPeer(t *testing.T) {
+	var query string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/federation/search" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		query = r.URL.RawQuery
+		_ = json.NewEncoder(w).Encode([]*api.FederatedSearchResult{
+			{Instance: "https://other.example.com", Type: TypeIssue, Title: "issue", HTMLURL: "https://peer.example.com/org/repo/issues/1"},
+			{Type: TypeWiki, Title: "unsafe", HTMLURL: "javascript:alert(1)"},
+		})
+	}))
+	defer server.Close()
+
+	results, err := searchPeer(context.Background(), server.URL, SearchOptions{Keyword: "gitea", Type: "issues", Limit: 5})
+	assert.NoError(t, err)
+	assert.Equal(t, "limit=5&q=gitea&type=issues", query)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, server.URL, results[0].Instance)
+		assert.Equal(t, "issue", results[0].Title)
+	}
+
+	_, err = searchPeer(context.Background(), server.URL+"/sub", SearchOptions{Keyword: "gitea"})
+	assert.Error(t, err)
+}
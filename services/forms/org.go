@@ -66,6 +66,14 @@ type CreateTeamForm struct {
 	Permission       string
 	RepoAccess       string
 	CanCreateOrgRepo bool
+	CodePaths        string // one path per line
+
+	MentionMode        string `binding:"OmitEmpty;In(all,leads,webhook)"`
+	MentionWebhookType string `binding:"OmitEmpty;In(gitea,slack,discord,msteams)"`
+	MentionWebhookURL  string `binding:"OmitEmpty;ValidUrl"`
+	MentionRateLimit   int
+
+	ReviewAssignCount int
 }
 
 // Validate validates the fields
@@ -33,6 +33,7 @@ type CreateRepoForm struct {
 	UID           int64  `binding:"Required"`
 	RepoName      string `binding:"Required;AlphaDashDot;MaxSize(100)"`
 	Private       bool
+	Internal      bool
 	Description   string `binding:"MaxSize(2048)"`
 	DefaultBranch string `binding:"GitRefName;MaxSize(100)"`
 	AutoInit      bool
@@ -42,6 +43,9 @@ type CreateRepoForm struct {
 	Readme        string
 	Template      bool
 
+	IssueTemplates    string
+	WorkflowTemplates string
+
 	RepoTemplate int64
 	GitContent   bool
 	Topics       bool
@@ -50,6 +54,8 @@ type CreateRepoForm struct {
 	Avatar       bool
 	Labels       bool
 	TrustModel   string
+
+	ObjectFormatName string
 }
 
 // Validate validates the fields
@@ -130,6 +136,7 @@ type RepoSettingForm struct {
 	PushMirrorSyncOnCommit bool
 	PushMirrorInterval     string
 	Private                bool
+	Internal               bool
 	Template               bool
 	EnablePrune            bool
 
@@ -150,6 +157,8 @@ type RepoSettingForm struct {
 	EnablePackages                        bool
 	EnablePulls                           bool
 	EnableActions                         bool
+	ActionsApprovalPolicy                 string
+	EnableDiscussions                     bool
 	PullsIgnoreWhitespace                 bool
 	PullsAllowMerge                       bool
 	PullsAllowRebase                      bool
@@ -207,7 +216,10 @@ type ProtectBranchForm struct {
 	BlockOnRejectedReviews        bool
 	BlockOnOfficialReviewRequests bool
 	BlockOnOutdatedBranch         bool
+	BlockOnCodeScanningAlerts     bool
+	MinDiffCoverage               int64
 	DismissStaleApprovals         bool
+	RequireReviewChecklist        bool
 	RequireSignedCommits          bool
 	ProtectedFilePatterns         string
 	UnprotectedFilePatterns       string
@@ -249,6 +261,9 @@ type WebhookForm struct {
 	Wiki                 bool
 	Repository           bool
 	Package              bool
+	Discussion           bool
+	DiscussionComment    bool
+	ActionRunApproval    bool
 	Active               bool
 	BranchFilter         string `binding:"GlobPattern"`
 	AuthorizationHeader  string
@@ -532,6 +547,17 @@ type EditProjectBoardForm struct {
 	Title   string `binding:"Required;MaxSize(100)"`
 	Sorting int8
 	Color   string `binding:"MaxSize(7)"`
+	// the work in progress limit is kept if not given
+	WipLimit     *int                        `json:"wip_limit"`
+	WipLimitMode *project_model.WipLimitMode `json:"wip_limit_mode"`
+}
+
+// ProjectViewSettingForm is a form for changing how a user views the boards of a project
+type ProjectViewSettingForm struct {
+	Swimlane    project_model.SwimlaneType
+	AssigneeID  int64
+	LabelID     int64
+	MilestoneID int64
 }
 
 //    _____  .__.__                   __
@@ -635,10 +661,11 @@ func (f *CodeCommentForm) Validate(req *http.Request, errs binding.Errors) bindi
 
 // SubmitReviewForm for submitting a finished code review
 type SubmitReviewForm struct {
-	Content  string
-	Type     string
-	CommitID string
-	Files    []string
+	Content   string
+	Type      string
+	CommitID  string
+	Files     []string
+	Checklist []int64
 }
 
 // Validate validates the fields
@@ -863,6 +890,21 @@ func (f *DeleteRepoFileForm) Validate(req *http.Request, errs binding.Errors) bi
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// FinishEditSessionForm form for committing the changes of an editing session
+type FinishEditSessionForm struct {
+	CommitSummary string `binding:"MaxSize(100)"`
+	CommitMessage string
+	CommitChoice  string `binding:"Required;MaxSize(50)"`
+	NewBranchName string `binding:"GitRefName;MaxSize(100)"`
+	Signoff       bool
+}
+
+// Validate validates the fields
+func (f *FinishEditSessionForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // ___________.__                 ___________                     __
 // \__    ___/|__| _____   ____   \__    ___/___________    ____ |  | __ ___________
 // |    |   |  |/     \_/ __ \    |    |  \_  __ \__  \ _/ ___\|  |/ // __ \_  __ \
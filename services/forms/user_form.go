@@ -8,6 +8,7 @@ import (
 	"mime/multipart"
 	"net/http"
 	"strings"
+	"unicode"
 
 	auth_model "code.gitea.io/gitea/models/auth"
 	"code.gitea.io/gitea/modules/context"
@@ -188,11 +189,12 @@ func (f *AuthorizationForm) Validate(req *http.Request, errs binding.Errors) bin
 
 // GrantApplicationForm form for authorizing oauth2 clients
 type GrantApplicationForm struct {
-	ClientID    string `binding:"Required"`
-	RedirectURI string
-	State       string
-	Scope       string
-	Nonce       string
+	ClientID     string `binding:"Required"`
+	RedirectURI  string
+	State        string
+	Scope        string
+	Nonce        string
+	GrantedScope []string `form:"granted_scope"`
 }
 
 // Validate validates the fields
@@ -240,14 +242,15 @@ func (f *IntrospectTokenForm) Validate(req *http.Request, errs binding.Errors) b
 
 // UpdateProfileForm form for updating profile
 type UpdateProfileForm struct {
-	Name                string `binding:"Username;MaxSize(40)"`
-	FullName            string `binding:"MaxSize(100)"`
-	KeepEmailPrivate    bool
-	Website             string `binding:"ValidSiteUrl;MaxSize(255)"`
-	Location            string `binding:"MaxSize(50)"`
-	Description         string `binding:"MaxSize(255)"`
-	Visibility          structs.VisibleType
-	KeepActivityPrivate bool
+	Name                 string `binding:"Username;MaxSize(40)"`
+	FullName             string `binding:"MaxSize(100)"`
+	KeepEmailPrivate     bool
+	Website              string `binding:"ValidSiteUrl;MaxSize(255)"`
+	Location             string `binding:"MaxSize(50)"`
+	Description          string `binding:"MaxSize(255)"`
+	Visibility           structs.VisibleType
+	KeepActivityPrivate  bool
+	CountPrivateActivity bool
 }
 
 // Validate validates the fields
@@ -287,6 +290,32 @@ func (f *AvatarForm) Validate(req *http.Request, errs binding.Errors) binding.Er
 	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
 }
 
+// UserStatusForm form for setting the user status
+type UserStatusForm struct {
+	Emoji   string `binding:"MaxSize(50)"`
+	Message string `binding:"MaxSize(100)"`
+	IsBusy  bool
+	EndDate string `binding:"OmitEmpty;MaxSize(10)"`
+}
+
+// Validate validates the fields
+func (f *UserStatusForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
+// LinkIdentityForm form for linking an account on another instance
+type LinkIdentityForm struct {
+	InstanceURL    string `binding:"Required;ValidUrl;MaxSize(255)"`
+	RemoteUsername string `binding:"Required;MaxSize(255)"`
+}
+
+// Validate validates the fields
+func (f *LinkIdentityForm) Validate(req *http.Request, errs binding.Errors) binding.Errors {
+	ctx := context.GetContext(req)
+	return middleware.Validate(errs, ctx.Data, f, ctx.Locale)
+}
+
 // AddEmailForm form for adding new email
 type AddEmailForm struct {
 	Email string `binding:"Required;Email;MaxSize(254)"`
@@ -355,7 +384,12 @@ type AddKeyForm struct {
 	Signature   string `binding:"OmitEmpty"`
 	KeyID       string `binding:"OmitEmpty"`
 	Fingerprint string `binding:"OmitEmpty"`
+	SuccessorID int64  `binding:"OmitEmpty"`
+	ExpiryDate  string `binding:"OmitEmpty"`
 	IsWritable  bool
+	// RefPatterns and IsArchiveOnly are only used for deploy keys
+	RefPatterns   string
+	IsArchiveOnly bool
 }
 
 // Validate validates the fields
@@ -399,6 +433,15 @@ type EditOAuth2ApplicationForm struct {
 	Name               string `binding:"Required;MaxSize(255)" form:"application_name"`
 	RedirectURI        string `binding:"Required" form:"redirect_uri"`
 	ConfidentialClient bool   `form:"confidential_client"`
+	// Scopes are the comma or space separated API scopes users can grant to the application
+	Scopes string `form:"scopes"`
+}
+
+// GetScopes returns the normalized API scopes users can grant to the application, nil for all scopes
+func (f *EditOAuth2ApplicationForm) GetScopes() ([]string, error) {
+	return auth_model.NormalizeOAuth2ApplicationScopes(strings.FieldsFunc(f.Scopes, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	}))
 }
 
 // Validate validates the fields
@@ -453,8 +496,10 @@ func (f *WebauthnDeleteForm) Validate(req *http.Request, errs binding.Errors) bi
 
 // PackageSettingForm form for package settings
 type PackageSettingForm struct {
-	Action string
-	RepoID int64 `form:"repo_id"`
+	Action             string
+	RepoID             int64  `form:"repo_id"`
+	IsDeprecated       bool   `form:"is_deprecated"`
+	DeprecationMessage string `form:"deprecation_message" binding:"MaxSize(1000)"`
 }
 
 // Validate validates the fields
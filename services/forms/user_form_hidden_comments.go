@@ -20,6 +20,7 @@ var hiddenCommentTypeGroups = hiddenCommentTypeGroupsType{
 		/*4*/ issues_model.CommentTypeCommitRef,
 		/*5*/ issues_model.CommentTypeCommentRef,
 		/*6*/ issues_model.CommentTypePullRef,
+		/*36*/ issues_model.CommentTypeCloseIssueRef,
 	},
 	"label": {
 		/*7*/ issues_model.CommentTypeLabel,
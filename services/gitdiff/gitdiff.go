@@ -15,9 +15,11 @@ import (
 	"net/url"
 	"os"
 	"sort"
+	"strconv"
 	"strings"
 	"time"
 
+	codescanning_model "code.gitea.io/gitea/models/codescanning"
 	"code.gitea.io/gitea/models/db"
 	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
@@ -32,6 +34,7 @@ import (
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/translation"
+	"code.gitea.io/gitea/modules/util"
 
 	"github.com/sergi/go-diff/diffmatchpatch"
 	stdcharset "golang.org/x/net/html/charset"
@@ -74,6 +77,16 @@ const (
 	DiffLineExpandDown
 )
 
+// DiffLineCoverage represents the test coverage of the new version of a DiffLine.
+type DiffLineCoverage uint8
+
+// DiffLineCoverage possible values.
+const (
+	DiffLineCoverageUnknown DiffLineCoverage = iota
+	DiffLineCovered
+	DiffLineUncovered
+)
+
 // DiffLine represents a line difference in a DiffSection.
 type DiffLine struct {
 	LeftIdx     int
@@ -83,6 +96,9 @@ type DiffLine struct {
 	Content     string
 	Comments    []*issues_model.Comment
 	SectionInfo *DiffLineSectionInfo
+	Coverage    DiffLineCoverage
+	// CodeScanningAlerts are the alerts which start at the line of the new version
+	CodeScanningAlerts []*codescanning_model.Alert
 }
 
 // DiffLineSectionInfo represents diff line section meta data
@@ -117,6 +133,17 @@ func (d *DiffLine) GetHTMLDiffLineType() string {
 	return "same"
 }
 
+// GetCoverageClass returns the class of the line number marking its test coverage
+func (d *DiffLine) GetCoverageClass() string {
+	switch d.Coverage {
+	case DiffLineCovered:
+		return "coverage-covered"
+	case DiffLineUncovered:
+		return "coverage-uncovered"
+	}
+	return ""
+}
+
 // CanComment returns whether a line can get commented
 func (d *DiffLine) CanComment() bool {
 	return len(d.Comments) == 0 && d.Type != DiffLineSection
@@ -360,6 +387,7 @@ type DiffFile struct {
 	IsBin                     bool
 	IsLFSFile                 bool
 	IsRenamed                 bool
+	Similarity                int
 	IsAmbiguous               bool
 	IsSubmodule               bool
 	Sections                  []*DiffSection
@@ -473,6 +501,95 @@ func (diff *Diff) LoadComments(ctx context.Context, issue *issues_model.Issue, c
 	return nil
 }
 
+// LoadCoverage marks the test coverage of the lines of the new version of the files with the coverage report
+func (diff *Diff) LoadCoverage(ctx context.Context, report *git_model.CoverageReport) error {
+	paths := make([]string, 0, len(diff.Files))
+	for _, file := range diff.Files {
+		if !file.IsDeleted {
+			paths = append(paths, file.Name)
+		}
+	}
+	if len(paths) == 0 {
+		return nil
+	}
+	files, err := git_model.FindCoverageReportFiles(ctx, report.ID, paths...)
+	if err != nil {
+		return err
+	}
+	for _, file := range diff.Files {
+		coverageFile, ok := files[file.Name]
+		if !ok {
+			continue
+		}
+		hits := coverageFile.Hits()
+		for _, section := range file.Sections {
+			for _, line := range section.Lines {
+				if line.RightIdx <= 0 || line.Type == DiffLineSection {
+					continue
+				}
+				if covered, ok := hits[line.RightIdx]; ok {
+					if covered {
+						line.Coverage = DiffLineCovered
+					} else {
+						line.Coverage = DiffLineUncovered
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// LoadCodeScanningAlerts attaches the code scanning alerts to the lines of the new version of the files they start at
+func (diff *Diff) LoadCodeScanningAlerts(alerts []*codescanning_model.Alert) {
+	if len(alerts) == 0 {
+		return
+	}
+	byPath := make(map[string][]*codescanning_model.Alert, len(alerts))
+	for _, alert := range alerts {
+		if alert.StartLine > 0 {
+			byPath[alert.Path] = append(byPath[alert.Path], alert)
+		}
+	}
+	for _, file := range diff.Files {
+		fileAlerts, ok := byPath[file.Name]
+		if !ok || file.IsDeleted {
+			continue
+		}
+		for _, section := range file.Sections {
+			for _, line := range section.Lines {
+				if line.RightIdx <= 0 || line.Type == DiffLineSection {
+					continue
+				}
+				for _, alert := range fileAlerts {
+					if alert.StartLine == line.RightIdx {
+						line.CodeScanningAlerts = append(line.CodeScanningAlerts, alert)
+					}
+				}
+			}
+		}
+	}
+}
+
+// CountAddedLinesCoverage returns the number of added lines which are covered by tests and which are instrumented,
+// the coverage has to be loaded first
+func (diff *Diff) CountAddedLinesCoverage() (covered, instrumented int) {
+	for _, file := range diff.Files {
+		for _, section := range file.Sections {
+			for _, line := range section.Lines {
+				if line.Type != DiffLineAdd || line.Coverage == DiffLineCoverageUnknown {
+					continue
+				}
+				instrumented++
+				if line.Coverage == DiffLineCovered {
+					covered++
+				}
+			}
+		}
+	}
+	return covered, instrumented
+}
+
 const cmdDiffHead = "diff --git "
 
 // ParsePatch builds a Diff object from a io.Reader and some parameters.
@@ -635,8 +752,11 @@ parsingLoop:
 				if strings.HasSuffix(line, " 160000\n") {
 					curFile.IsSubmodule = true
 				}
-			case strings.HasPrefix(line, "similarity index 100%"):
-				curFile.Type = DiffFileRename
+			case strings.HasPrefix(line, "similarity index "):
+				curFile.Similarity, _ = strconv.Atoi(strings.TrimSuffix(line[len("similarity index "):len(line)-1], "%"))
+				if curFile.Similarity == 100 {
+					curFile.Type = DiffFileRename
+				}
 			case strings.HasPrefix(line, "Binary"):
 				curFile.IsBin = true
 			case strings.HasPrefix(line, "--- "):
@@ -1061,6 +1181,44 @@ func readFileName(rd *strings.Reader) (string, bool) {
 	return name[2:], ambiguity
 }
 
+// RenameDetection represents the options for the detection of renamed and copied files
+type RenameDetection struct {
+	// RenameThreshold is the minimal similarity in percent of a renamed file, 0 uses the default of git
+	RenameThreshold int
+	// CopyThreshold is the minimal similarity in percent of a copied file, 0 disables copy detection
+	CopyThreshold int
+	// FindCopiesHarder considers all files of the before commit as source of a copy, not only the changed ones
+	FindCopiesHarder bool
+}
+
+// Validate checks whether the thresholds are valid percentages
+func (rd RenameDetection) Validate() error {
+	if rd.RenameThreshold < 0 || rd.RenameThreshold > 100 {
+		return util.NewInvalidArgumentErrorf("rename threshold must be between 0 and 100")
+	}
+	if rd.CopyThreshold < 0 || rd.CopyThreshold > 100 {
+		return util.NewInvalidArgumentErrorf("copy threshold must be between 0 and 100")
+	}
+	return nil
+}
+
+// Args returns the arguments of git diff for the detection
+func (rd RenameDetection) Args() git.TrustedCmdArgs {
+	args := []string{"-M"}
+	if rd.RenameThreshold > 0 {
+		args[0] = fmt.Sprintf("-M%d%%", rd.RenameThreshold)
+	}
+	if rd.CopyThreshold > 0 {
+		args = append(args, fmt.Sprintf("-C%d%%", rd.CopyThreshold))
+	} else if rd.FindCopiesHarder {
+		args = append(args, "-C")
+	}
+	if rd.FindCopiesHarder {
+		args = append(args, "--find-copies-harder")
+	}
+	return git.ToTrustedCmdArgs(args)
+}
+
 // DiffOptions represents the options for a DiffRange
 type DiffOptions struct {
 	BeforeCommitID     string
@@ -1070,6 +1228,7 @@ type DiffOptions struct {
 	MaxLineCharacters  int
 	MaxFiles           int
 	WhitespaceBehavior git.TrustedCmdArgs
+	RenameDetection    RenameDetection
 	DirectComparison   bool
 }
 
@@ -1085,8 +1244,9 @@ func GetDiff(gitRepo *git.Repository, opts *DiffOptions, files ...string) (*Diff
 	}
 
 	cmdDiff := git.NewCommand(gitRepo.Ctx)
-	if (len(opts.BeforeCommitID) == 0 || opts.BeforeCommitID == git.EmptySHA) && commit.ParentCount() == 0 {
-		cmdDiff.AddArguments("diff", "--src-prefix=\\a/", "--dst-prefix=\\b/", "-M").
+	if (len(opts.BeforeCommitID) == 0 || git.IsEmptyCommitID(opts.BeforeCommitID)) && commit.ParentCount() == 0 {
+		cmdDiff.AddArguments("diff", "--src-prefix=\\a/", "--dst-prefix=\\b/").
+			AddArguments(opts.RenameDetection.Args()...).
 			AddArguments(opts.WhitespaceBehavior...).
 			AddArguments("4b825dc642cb6eb9a060e54bf8d69288fbee4904"). // append empty tree ref
 			AddDynamicArguments(opts.AfterCommitID)
@@ -1097,7 +1257,8 @@ func GetDiff(gitRepo *git.Repository, opts *DiffOptions, files ...string) (*Diff
 			actualBeforeCommitID = parentCommit.ID.String()
 		}
 
-		cmdDiff.AddArguments("diff", "--src-prefix=\\a/", "--dst-prefix=\\b/", "-M").
+		cmdDiff.AddArguments("diff", "--src-prefix=\\a/", "--dst-prefix=\\b/").
+			AddArguments(opts.RenameDetection.Args()...).
 			AddArguments(opts.WhitespaceBehavior...).
 			AddDynamicArguments(actualBeforeCommitID, opts.AfterCommitID)
 		opts.BeforeCommitID = actualBeforeCommitID
@@ -1193,8 +1354,8 @@ func GetDiff(gitRepo *git.Repository, opts *DiffOptions, files ...string) (*Diff
 	}
 
 	diffPaths := []string{opts.BeforeCommitID + separator + opts.AfterCommitID}
-	if len(opts.BeforeCommitID) == 0 || opts.BeforeCommitID == git.EmptySHA {
-		diffPaths = []string{git.EmptyTreeSHA, opts.AfterCommitID}
+	if len(opts.BeforeCommitID) == 0 || git.IsEmptyCommitID(opts.BeforeCommitID) {
+		diffPaths = []string{gitRepo.ObjectFormat().EmptyTree(), opts.AfterCommitID}
 	}
 	diff.NumFiles, diff.TotalAddition, diff.TotalDeletion, err = git.GetDiffShortStat(gitRepo.Ctx, repoPath, nil, diffPaths...)
 	if err != nil && strings.Contains(err.Error(), "no merge base") {
@@ -10,7 +10,9 @@ import (
 	"strings"
 	"testing"
 
+	codescanning_model "code.gitea.io/gitea/models/codescanning"
 	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
 	issues_model "code.gitea.io/gitea/models/issues"
 	"code.gitea.io/gitea/models/unittest"
 	user_model "code.gitea.io/gitea/models/user"
@@ -218,6 +220,7 @@ func TestParsePatch_singlefile(t *testing.T) {
 		deletion    int
 		oldFilename string
 		filename    string
+		similarity  int
 	}
 
 	tests := []testcase{
@@ -304,6 +307,7 @@ rename to "a\342\200\224as"
 			deletion:    0,
 			oldFilename: "𣐵b†vs",
 			filename:    "a—as",
+			similarity:  100,
 		},
 		{
 			name: "rename with spaces",
@@ -314,6 +318,7 @@ rename to a b/a a/file b/b file
 `,
 			oldFilename: "a b/file b/a a/file",
 			filename:    "a b/a a/file b/b file",
+			similarity:  100,
 		},
 		{
 			name: "ambiguous deleted",
@@ -354,6 +359,7 @@ rename to b
 `,
 			oldFilename: "b b/b b/b b/b b/b",
 			filename:    "b",
+			similarity:  100,
 		},
 		{
 			name: "ambiguous 1",
@@ -364,6 +370,7 @@ rename to b
 `,
 			oldFilename: "b b/b b/b b/b b/b",
 			filename:    "b",
+			similarity:  100,
 		},
 		{
 			name: "ambiguous 2",
@@ -374,6 +381,28 @@ rename to b b/b
 `,
 			oldFilename: "b b/b b/b b/b",
 			filename:    "b b/b",
+			similarity:  100,
+		},
+		{
+			name: "rename with changes",
+			gitdiff: `diff --git a/old.txt b/new.txt
+similarity index 87%
+rename from old.txt
+rename to new.txt
+index 2e65efe..9b6f2e1 100644
+--- a/old.txt
++++ b/new.txt
+@@ -1,3 +1,3 @@
+ a
+-b
++c
+ d
+`,
+			oldFilename: "old.txt",
+			filename:    "new.txt",
+			similarity:  87,
+			addition:    1,
+			deletion:    1,
 		},
 		{
 			name: "minuses-and-pluses",
@@ -430,6 +459,9 @@ index 6961180..9ba1a00 100644
 			if file.Name != testcase.filename {
 				t.Errorf("ParsePath(%q) did not have correct Name %q, wanted %q", testcase.name, file.Name, testcase.filename)
 			}
+			if file.Similarity != testcase.similarity {
+				t.Errorf("ParsePath(%q) did not have correct Similarity %d, wanted %d", testcase.name, file.Similarity, testcase.similarity)
+			}
 		})
 	}
 
@@ -604,6 +636,55 @@ func TestDiff_LoadComments(t *testing.T) {
 	assert.Len(t, diff.Files[0].Sections[0].Lines[0].Comments, 2)
 }
 
+func TestDiff_LoadCoverage(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	report := &git_model.CoverageReport{RepoID: 1, CommitSHA: "65f1bf27bc3bf70f64657658635e66094edbcb4d", Coverage: 50}
+	assert.NoError(t, git_model.InsertCoverageReport(db.DefaultContext, report, []*git_model.CoverageReportFile{
+		{Path: "README.md", CoveredLines: []int{4}, UncoveredLines: []int{5}},
+	}))
+
+	diff := setupDefaultDiff()
+	diff.Files[0].Sections[0].Lines = append(diff.Files[0].Sections[0].Lines,
+		&DiffLine{RightIdx: 5, Type: DiffLineAdd},
+		&DiffLine{RightIdx: 6, Type: DiffLineAdd},
+		&DiffLine{LeftIdx: 5, Type: DiffLineDel},
+	)
+	assert.NoError(t, diff.LoadCoverage(db.DefaultContext, report))
+	lines := diff.Files[0].Sections[0].Lines
+	assert.Equal(t, "coverage-covered", lines[0].GetCoverageClass())
+	assert.Equal(t, "coverage-uncovered", lines[1].GetCoverageClass())
+	assert.Equal(t, "", lines[2].GetCoverageClass())
+	assert.Equal(t, "", lines[3].GetCoverageClass())
+
+	// only the added lines count, the unchanged line 4 doesn't
+	covered, instrumented := diff.CountAddedLinesCoverage()
+	assert.Equal(t, 0, covered)
+	assert.Equal(t, 1, instrumented)
+}
+
+func TestDiff_LoadCodeScanningAlerts(t *testing.T) {
+	diff := setupDefaultDiff()
+	diff.Files[0].Sections[0].Lines = append(diff.Files[0].Sections[0].Lines,
+		&DiffLine{LeftIdx: 5, Type: DiffLineDel},
+		&DiffLine{RightIdx: 5, Type: DiffLineAdd},
+	)
+	diff.LoadCodeScanningAlerts([]*codescanning_model.Alert{
+		{Path: "README.md", StartLine: 5, RuleID: "R1"},
+		{Path: "README.md", StartLine: 4, RuleID: "R2"},
+		{Path: "README.md", StartLine: 0, RuleID: "R3"},
+		{Path: "main.go", StartLine: 5, RuleID: "R4"},
+	})
+	lines := diff.Files[0].Sections[0].Lines
+	if assert.Len(t, lines[0].CodeScanningAlerts, 1) {
+		assert.Equal(t, "R2", lines[0].CodeScanningAlerts[0].RuleID)
+	}
+	assert.Empty(t, lines[1].CodeScanningAlerts)
+	if assert.Len(t, lines[2].CodeScanningAlerts, 1) {
+		assert.Equal(t, "R1", lines[2].CodeScanningAlerts[0].RuleID)
+	}
+}
+
 func TestDiffLine_CanComment(t *testing.T) {
 	assert.False(t, (&DiffLine{Type: DiffLineSection}).CanComment())
 	assert.False(t, (&DiffLine{Type: DiffLineAdd, Comments: []*issues_model.Comment{{Content: "bla"}}}).CanComment())
@@ -640,6 +721,17 @@ func TestGetDiffRangeWithWhitespaceBehavior(t *testing.T) {
 	}
 }
 
+func TestRenameDetection(t *testing.T) {
+	assert.EqualValues(t, []string{"-M"}, RenameDetection{}.Args())
+	assert.EqualValues(t, []string{"-M80%"}, RenameDetection{RenameThreshold: 80}.Args())
+	assert.EqualValues(t, []string{"-M", "-C70%"}, RenameDetection{CopyThreshold: 70}.Args())
+	assert.EqualValues(t, []string{"-M90%", "-C", "--find-copies-harder"}, RenameDetection{RenameThreshold: 90, FindCopiesHarder: true}.Args())
+
+	assert.NoError(t, RenameDetection{RenameThreshold: 100, CopyThreshold: 0}.Validate())
+	assert.Error(t, RenameDetection{RenameThreshold: 101}.Validate())
+	assert.Error(t, RenameDetection{CopyThreshold: -1}.Validate())
+}
+
 func TestNoCrashes(t *testing.T) {
 	type testcase struct {
 		gitdiff string
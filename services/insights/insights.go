@@ -0,0 +1,176 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package insights
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	insights_model "code.gitea.io/gitea/models/insights"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+const computeBatchSize = 100
+
+// ComputeMetrics computes the metrics of the merged pull requests which don't have them yet
+func ComputeMetrics(ctx context.Context) error {
+	var afterID int64
+	for {
+		prs, err := insights_model.FindPullRequestsWithoutMetric(ctx, afterID, computeBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, pr := range prs {
+			select {
+			case <-ctx.Done():
+				return db.ErrCancelledf("before computing the metrics of pull request %d", pr.ID)
+			default:
+			}
+
+			afterID = pr.ID
+			metric, err := computeMetric(ctx, pr)
+			if err != nil {
+				if issues_model.IsErrIssueNotExist(err) {
+					continue
+				}
+				return err
+			}
+			if err := insights_model.InsertPullRequestMetric(ctx, metric); err != nil {
+				return err
+			}
+		}
+		if len(prs) < computeBatchSize {
+			return nil
+		}
+	}
+}
+
+func computeMetric(ctx context.Context, pr *issues_model.PullRequest) (*insights_model.PullRequestMetric, error) {
+	if err := pr.LoadIssue(ctx); err != nil {
+		return nil, err
+	}
+	opened := pr.Issue.CreatedUnix
+	merged := pr.MergedUnix
+
+	metric := &insights_model.PullRequestMetric{
+		PullRequestID: pr.ID,
+		RepoID:        pr.BaseRepoID,
+		CycleTime:     nonNegative(int64(merged - opened)),
+		ReviewLatency: -1,
+		OpenedUnix:    opened,
+		MergedUnix:    merged,
+	}
+
+	firstReview, err := insights_model.GetFirstReviewUnix(ctx, pr.IssueID, pr.Issue.PosterID)
+	if err != nil {
+		return nil, err
+	}
+	if firstReview > 0 {
+		metric.ReviewLatency = nonNegative(int64(firstReview - opened))
+	}
+
+	// the lead time starts with the oldest commit of the pull request, or with opening it if all
+	// its commits were authored later
+	metric.LeadTime = metric.CycleTime
+	firstCommit, err := getFirstCommitUnix(ctx, pr)
+	if err != nil {
+		// the pull request ref may have been removed, the lead time can't be known then
+		log.Warn("Unable to get the first commit of pull request %d: %v", pr.ID, err)
+	} else if firstCommit > 0 && firstCommit < opened {
+		metric.LeadTime = nonNegative(int64(merged - firstCommit))
+	}
+	return metric, nil
+}
+
+// getFirstCommitUnix returns the author time of the oldest commit of a pull request
+func getFirstCommitUnix(ctx context.Context, pr *issues_model.PullRequest) (timeutil.TimeStamp, error) {
+	if pr.MergeBase == "" {
+		return 0, nil
+	}
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return 0, err
+	}
+	stdout, _, err := git.NewCommand(ctx, "log", "--format=%at").
+		AddDynamicArguments(pr.MergeBase + ".." + pr.GetGitRefName()).
+		RunStdString(&git.RunOpts{Dir: pr.BaseRepo.RepoPath()})
+	if err != nil {
+		return 0, err
+	}
+
+	var first int64
+	for _, line := range strings.Fields(stdout) {
+		at, err := strconv.ParseInt(line, 10, 64)
+		if err != nil {
+			return 0, err
+		}
+		if first == 0 || at < first {
+			first = at
+		}
+	}
+	return timeutil.TimeStamp(first), nil
+}
+
+func nonNegative(seconds int64) int64 {
+	if seconds < 0 {
+		return 0
+	}
+	return seconds
+}
+
+// Insights are the aggregated metrics of a time range, in total and per repository
+type Insights struct {
+	Since   time.Time
+	Before  time.Time
+	Total   *insights_model.Stats
+	PerRepo map[int64]*insights_model.Stats
+}
+
+// Weeks returns the length of the time range in weeks, to compute frequencies
+func (i *Insights) Weeks() float64 {
+	return i.Before.Sub(i.Since).Hours() / (24 * 7)
+}
+
+// DefaultRange is the time range of the insights if no start is given
+const DefaultRange = 30 * 24 * time.Hour
+
+// ErrInvalidRange is returned if the start of the time range isn't before its end
+var ErrInvalidRange = util.NewInvalidArgumentErrorf("the start of the time range must be before its end")
+
+// GetInsights aggregates the metrics of the pull requests merged and the releases published in the time range
+// of a repository, or of all repositories of an owner if repoID is 0. The range ends now if before is zero
+// and starts DefaultRange before its end if since is zero.
+func GetInsights(ctx context.Context, ownerID, repoID int64, since, before time.Time) (*Insights, error) {
+	if before.IsZero() {
+		before = time.Now()
+	}
+	if since.IsZero() {
+		since = before.Add(-DefaultRange)
+	}
+	if !since.Before(before) {
+		return nil, ErrInvalidRange
+	}
+
+	total, perRepo, err := insights_model.GetStats(ctx, &insights_model.StatsOptions{
+		RepoID:  repoID,
+		OwnerID: ownerID,
+		Since:   timeutil.TimeStamp(since.Unix()),
+		Before:  timeutil.TimeStamp(before.Unix()),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Insights{
+		Since:   since,
+		Before:  before,
+		Total:   total,
+		PerRepo: perRepo,
+	}, nil
+}
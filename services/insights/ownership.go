@@ -0,0 +1,260 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package insights
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	insights_model "code.gitea.io/gitea/models/insights"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+
+	"xorm.io/builder"
+)
+
+const (
+	// ownershipWindow is how far back the history is considered for the path ownership
+	ownershipWindow = 365 * 24 * time.Hour
+	// ownershipMaxCommits limits the commits read per repository
+	ownershipMaxCommits = 20000
+	// OwnershipMaxDepth is the depth of the deepest directories the ownership is computed for
+	OwnershipMaxDepth = 3
+)
+
+// ComputePathOwnership computes the contributions to the directories of the repositories
+// whose default branch changed since they were last computed
+func ComputePathOwnership(ctx context.Context) error {
+	return db.Iterate(ctx, builder.Eq{"is_empty": false}, func(ctx context.Context, repo *repo_model.Repository) error {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before computing the path ownership of %s", repo.FullName())
+		default:
+		}
+
+		if repo.IsBeingCreated() {
+			return nil
+		}
+		if err := computeRepoPathOwnership(ctx, repo); err != nil {
+			// a broken repository must not stop the other ones
+			log.Warn("Unable to compute the path ownership of %s: %v", repo.FullName(), err)
+		}
+		return nil
+	})
+}
+
+func computeRepoPathOwnership(ctx context.Context, repo *repo_model.Repository) error {
+	commitID, err := git.GetBranchCommitID(ctx, repo.RepoPath(), repo.DefaultBranch)
+	if err != nil {
+		return err
+	}
+	status, err := insights_model.GetPathOwnershipStatus(ctx, repo.ID)
+	if err != nil {
+		return err
+	}
+	if status != nil && status.CommitID == commitID {
+		return nil
+	}
+
+	stdoutReader, stdoutWriter := io.Pipe()
+	defer func() {
+		_ = stdoutReader.Close()
+		_ = stdoutWriter.Close()
+	}()
+
+	var contributions []*insights_model.PathContribution
+	stderr := new(strings.Builder)
+	err = git.NewCommand(ctx, "log", "--no-merges", "--no-renames", "--numstat", "--format=%x1e%aN%x1f%aE%x1f%at").
+		AddOptionFormat("--max-count=%d", ownershipMaxCommits).
+		AddOptionFormat("--since=%d", time.Now().Add(-ownershipWindow).Unix()).
+		AddDynamicArguments(commitID).
+		Run(&git.RunOpts{
+			Dir:    repo.RepoPath(),
+			Stdout: stdoutWriter,
+			Stderr: stderr,
+			PipelineFunc: func(ctx context.Context, cancel context.CancelFunc) error {
+				_ = stdoutWriter.Close()
+				var err error
+				contributions, err = parsePathContributions(stdoutReader, OwnershipMaxDepth)
+				return err
+			},
+		})
+	if err != nil {
+		return fmt.Errorf("git log %s: %w - %s", commitID, err, stderr.String())
+	}
+
+	for _, c := range contributions {
+		c.RepoID = repo.ID
+	}
+	return insights_model.ReplacePathContributions(ctx, repo.ID, commitID, contributions)
+}
+
+// parsePathContributions reads the output of git log --numstat and attributes the changed lines of every
+// commit to its author in all the directories of the changed files, up to maxDepth
+func parsePathContributions(r io.Reader, maxDepth int) ([]*insights_model.PathContribution, error) {
+	type key struct {
+		path  string
+		email string
+	}
+	contributions := make(map[key]*insights_model.PathContribution)
+	var order []key
+
+	var name, email string
+	var when timeutil.TimeStamp
+	// the directories the current commit touched, each is counted once per commit
+	touched := make(map[string]bool)
+
+	attribute := func(dir string, depth int, lines int64) {
+		k := key{path: dir, email: email}
+		c, ok := contributions[k]
+		if !ok {
+			c = &insights_model.PathContribution{Path: dir, Depth: depth, AuthorName: name, AuthorEmail: email}
+			contributions[k] = c
+			order = append(order, k)
+		}
+		c.Lines += lines
+		if !touched[dir] {
+			touched[dir] = true
+			c.Commits++
+		}
+		// git log lists the newest commits first
+		if when > c.LastCommitUnix {
+			c.LastCommitUnix = when
+		}
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if line[0] == '\x1e' {
+			fields := strings.Split(line[1:], "\x1f")
+			if len(fields) != 3 {
+				return nil, fmt.Errorf("unexpected commit line: %q", line)
+			}
+			at, err := strconv.ParseInt(fields[2], 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("unexpected commit time: %q", line)
+			}
+			name, email, when = fields[0], strings.ToLower(fields[1]), timeutil.TimeStamp(at)
+			touched = make(map[string]bool)
+			continue
+		}
+
+		fields := strings.SplitN(line, "\t", 3)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("unexpected numstat line: %q", line)
+		}
+		// binary files have "-" instead of the numbers of lines
+		added, _ := strconv.ParseInt(fields[0], 10, 64)
+		deleted, _ := strconv.ParseInt(fields[1], 10, 64)
+		path := fields[2]
+		if strings.HasPrefix(path, `"`) {
+			if unquoted, err := strconv.Unquote(path); err == nil {
+				path = unquoted
+			}
+		}
+
+		attribute("", 0, added+deleted)
+		components := strings.Split(path, "/")
+		for depth := 1; depth < len(components) && depth <= maxDepth; depth++ {
+			attribute(strings.Join(components[:depth], "/"), depth, added+deleted)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	result := make([]*insights_model.PathContribution, 0, len(order))
+	for _, k := range order {
+		result = append(result, contributions[k])
+	}
+	return result, nil
+}
+
+// PathOwnership is the ownership of a directory with the users of the contributors, if their emails belong to one
+type PathOwnership struct {
+	*insights_model.PathOwnership
+	Users map[string]*user_model.User
+	// Unowned is set if none of the contributors needed for the bus factor is an active user anymore
+	Unowned bool
+}
+
+// ErrInvalidOwnershipDepth is returned if the ownership is requested deeper than it is computed
+var ErrInvalidOwnershipDepth = util.NewInvalidArgumentErrorf("the depth must be between 0 and %d", OwnershipMaxDepth)
+
+// GetPathOwnership returns the ownership of the directories of a repository below a path, up to a depth
+// relative to it. The status is nil if the ownership was not computed yet.
+func GetPathOwnership(ctx context.Context, repo *repo_model.Repository, path string, depth int, onlyUnowned bool) ([]*PathOwnership, *insights_model.PathOwnershipStatus, error) {
+	path = strings.Trim(path, "/")
+	base := 0
+	if path != "" {
+		base = strings.Count(path, "/") + 1
+	}
+	if depth < 0 || base+depth > OwnershipMaxDepth {
+		return nil, nil, ErrInvalidOwnershipDepth
+	}
+
+	status, err := insights_model.GetPathOwnershipStatus(ctx, repo.ID)
+	if err != nil || status == nil {
+		return nil, status, err
+	}
+	contributions, err := insights_model.GetPathContributions(ctx, repo.ID, base+depth)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	users := make(map[string]*user_model.User)
+	getUser := func(email string) (*user_model.User, error) {
+		if u, ok := users[email]; ok {
+			return u, nil
+		}
+		u, err := user_model.GetUserByEmail(ctx, email)
+		if err != nil && !user_model.IsErrUserNotExist(err) {
+			return nil, err
+		}
+		users[email] = u
+		return u, nil
+	}
+
+	result := make([]*PathOwnership, 0, 10)
+	for _, po := range insights_model.SummarizePathOwnership(contributions) {
+		if path != "" && po.Path != path && !strings.HasPrefix(po.Path, path+"/") {
+			continue
+		}
+
+		ownership := &PathOwnership{PathOwnership: po, Users: make(map[string]*user_model.User), Unowned: true}
+		for i, c := range po.Contributors {
+			u, err := getUser(c.AuthorEmail)
+			if err != nil {
+				return nil, nil, err
+			}
+			if u == nil {
+				continue
+			}
+			ownership.Users[c.AuthorEmail] = u
+			if i < po.BusFactor && u.IsActive && !u.ProhibitLogin {
+				ownership.Unowned = false
+			}
+		}
+		if onlyUnowned && !ownership.Unowned {
+			continue
+		}
+		result = append(result, ownership)
+	}
+	return result, status, nil
+}
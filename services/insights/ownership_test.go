@@ -0,0 +1,70 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package insights
+
+import (
+	"strings"
+	"testing"
+
+	insights_model "code.gitea.io/gitea/models/insights"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePathContributions(t *testing.T) {
+	log := "\x1eAlice\x1fAlice@Example.com\x1f300\n" +
+		"\n" +
+		"10\t2\tsrc/app/main.go\n" +
+		"3\t0\tsrc/app/util.go\n" +
+		"1\t1\tREADME.md\n" +
+		"\x1eBob\x1fbob@example.com\x1f200\n" +
+		"\n" +
+		"-\t-\tsrc/app/logo.png\n" +
+		"5\t5\t\"src/\\303\\244/deep/er/file.go\"\n" +
+		"\x1eAlice\x1falice@example.com\x1f100\n" +
+		"\n" +
+		"4\t0\tsrc/lib.go\n"
+
+	contributions, err := parsePathContributions(strings.NewReader(log), 2)
+	assert.NoError(t, err)
+
+	find := func(path, email string) *insights_model.PathContribution {
+		for _, c := range contributions {
+			if c.Path == path && c.AuthorEmail == email {
+				return c
+			}
+		}
+		return nil
+	}
+
+	root := find("", "alice@example.com")
+	if assert.NotNil(t, root) {
+		// every commit counts once per directory
+		assert.EqualValues(t, 2, root.Commits)
+		assert.EqualValues(t, 21, root.Lines)
+		assert.EqualValues(t, 300, root.LastCommitUnix)
+		assert.Equal(t, "Alice", root.AuthorName)
+	}
+	if c := find("src", "alice@example.com"); assert.NotNil(t, c) {
+		assert.EqualValues(t, 2, c.Commits)
+		assert.EqualValues(t, 19, c.Lines)
+		assert.Equal(t, 1, c.Depth)
+	}
+	if c := find("src/app", "alice@example.com"); assert.NotNil(t, c) {
+		assert.EqualValues(t, 1, c.Commits)
+		assert.EqualValues(t, 15, c.Lines)
+		assert.Equal(t, 2, c.Depth)
+	}
+	// binary files count as commits without lines
+	if c := find("src/app", "bob@example.com"); assert.NotNil(t, c) {
+		assert.EqualValues(t, 1, c.Commits)
+		assert.EqualValues(t, 0, c.Lines)
+	}
+	// quoted paths are unquoted and directories deeper than the maximum depth are not counted
+	assert.NotNil(t, find("src/ä", "bob@example.com"))
+	assert.Nil(t, find("src/ä/deep", "bob@example.com"))
+
+	_, err = parsePathContributions(strings.NewReader("\x1eAlice\x1falice@example.com\n"), 2)
+	assert.Error(t, err)
+}
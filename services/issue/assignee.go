@@ -243,6 +243,20 @@ func TeamReviewRequest(ctx context.Context, issue *issues_model.Issue, doer *use
 		return
 	}
 
+	// the request is assigned to some members of the team in round-robin order, they are notified of their own requests
+	if reviewer.IsReviewAssignEnabled() {
+		members, err := organization.AssignReviewers(ctx, reviewer, issue.PosterID)
+		if err != nil {
+			return nil, err
+		}
+		for _, member := range members {
+			if _, err := ReviewRequest(ctx, issue, doer, member, true); err != nil {
+				return nil, err
+			}
+		}
+		return comment, nil
+	}
+
 	// notify all user in this team
 	if err = comment.LoadIssue(ctx); err != nil {
 		return
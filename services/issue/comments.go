@@ -13,6 +13,7 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/moderation"
 )
 
 // CreateComment creates comment of issue or commit.
@@ -66,6 +67,24 @@ func CreateRefComment(doer *user_model.User, repo *repo_model.Repository, issue
 
 // CreateIssueComment creates a plain issue comment.
 func CreateIssueComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, issue *issues_model.Issue, content string, attachments []string) (*issues_model.Comment, error) {
+	return ReplyToIssueComment(ctx, doer, repo, issue, 0, content, attachments)
+}
+
+// ReplyToIssueComment creates a plain comment in the thread of the comment replyToID,
+// a replyToID of 0 creates a top-level comment.
+func ReplyToIssueComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, issue *issues_model.Issue, replyToID int64, content string, attachments []string) (*issues_model.Comment, error) {
+	if err := moderation.CheckInteraction(ctx, doer, repo); err != nil {
+		return nil, err
+	}
+
+	if replyToID > 0 {
+		rootID, err := issues_model.GetThreadRootID(ctx, issue.ID, replyToID)
+		if err != nil {
+			return nil, err
+		}
+		replyToID = rootID
+	}
+
 	comment, err := CreateComment(ctx, &issues_model.CreateCommentOptions{
 		Type:        issues_model.CommentTypeComment,
 		Doer:        doer,
@@ -73,6 +92,7 @@ func CreateIssueComment(ctx context.Context, doer *user_model.User, repo *repo_m
 		Issue:       issue,
 		Content:     content,
 		Attachments: attachments,
+		ReplyToID:   replyToID,
 	})
 	if err != nil {
 		return nil, err
@@ -122,6 +142,13 @@ func UpdateComment(ctx context.Context, c *issues_model.Comment, doer *user_mode
 
 // DeleteComment deletes the comment
 func DeleteComment(ctx context.Context, doer *user_model.User, comment *issues_model.Comment) error {
+	if err := comment.LoadIssue(ctx); err != nil {
+		return err
+	}
+	if err := issues_model.CheckLegalHold(ctx, comment.Issue.RepoID, comment.IssueID); err != nil {
+		return err
+	}
+
 	err := db.WithTx(ctx, func(ctx context.Context) error {
 		return issues_model.DeleteComment(ctx, comment)
 	})
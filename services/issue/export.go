@@ -0,0 +1,493 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// ExportFormat is the format of the tables of an issue export
+type ExportFormat string
+
+// The formats of issue exports
+const (
+	ExportFormatJSON ExportFormat = "json"
+	ExportFormatCSV  ExportFormat = "csv"
+)
+
+// IsValid returns if the export format is supported
+func (f ExportFormat) IsValid() bool {
+	return f == ExportFormatJSON || f == ExportFormatCSV
+}
+
+type exportColumn struct {
+	Name        string
+	Description string
+}
+
+// exportTable is a file of an issue export, its columns are documented in the README of the export
+type exportTable struct {
+	Name        string
+	Description string
+	Columns     []exportColumn
+}
+
+var (
+	exportIssuesTable = exportTable{
+		Name:        "issues",
+		Description: "The issues and pull requests of the repository.",
+		Columns: []exportColumn{
+			{"id", "Unique id of the issue"},
+			{"number", "Number of the issue in the repository"},
+			{"is_pull", "Whether the issue is a pull request"},
+			{"title", "Title"},
+			{"body", "Description in markdown"},
+			{"state", "open or closed"},
+			{"is_locked", "Whether the conversation is locked"},
+			{"poster_id", "Id of the user who opened the issue, 0 for migrated issues"},
+			{"poster", "Name of the user who opened the issue"},
+			{"milestone_id", "Id of the milestone, 0 if none"},
+			{"labels", "Names of the labels"},
+			{"assignees", "Names of the assigned users"},
+			{"ref", "Branch or tag the issue refers to"},
+			{"num_comments", "Number of comments"},
+			{"created", "Creation time in RFC 3339 format"},
+			{"updated", "Time of the last update in RFC 3339 format"},
+			{"closed", "Time of closing in RFC 3339 format, empty if open"},
+			{"deadline", "Due date in RFC 3339 format, empty if none"},
+			{"head_branch", "Head branch of the pull request"},
+			{"base_branch", "Base branch of the pull request"},
+			{"merged", "Whether the pull request has been merged"},
+			{"merged_at", "Time of merging in RFC 3339 format, empty if not merged"},
+			{"merge_commit", "SHA of the merge commit"},
+		},
+	}
+	exportCommentsTable = exportTable{
+		Name:        "comments",
+		Description: "The comments and reviews of the issues and pull requests, oldest first.",
+		Columns: []exportColumn{
+			{"id", "Unique id of the comment"},
+			{"issue_number", "Number of the issue or pull request"},
+			{"type", "comment, review or code (a comment on a line of a pull request)"},
+			{"poster_id", "Id of the user who wrote the comment, 0 for migrated comments"},
+			{"poster", "Name of the user who wrote the comment"},
+			{"body", "Content in markdown"},
+			{"review_id", "Id of the review a code comment belongs to, 0 if none"},
+			{"tree_path", "Path of the file of a code comment"},
+			{"line", "Line of a code comment, negative for lines of the old version of the file"},
+			{"commit_sha", "Commit of a code comment"},
+			{"created", "Creation time in RFC 3339 format"},
+			{"updated", "Time of the last update in RFC 3339 format"},
+		},
+	}
+	exportLabelsTable = exportTable{
+		Name:        "labels",
+		Description: "The labels of the repository and, for repositories of organizations, of the organization.",
+		Columns: []exportColumn{
+			{"id", "Unique id of the label"},
+			{"name", "Name"},
+			{"description", "Description"},
+			{"color", "Color as hex triplet"},
+			{"exclusive", "Whether the label excludes other labels with the same scope"},
+			{"is_org_label", "Whether the label belongs to the organization"},
+			{"num_issues", "Number of issues with the label"},
+			{"num_closed_issues", "Number of closed issues with the label"},
+		},
+	}
+	exportMilestonesTable = exportTable{
+		Name:        "milestones",
+		Description: "The milestones of the repository.",
+		Columns: []exportColumn{
+			{"id", "Unique id of the milestone"},
+			{"name", "Name"},
+			{"description", "Description in markdown"},
+			{"state", "open or closed"},
+			{"num_issues", "Number of issues in the milestone"},
+			{"num_closed_issues", "Number of closed issues in the milestone"},
+			{"created", "Creation time in RFC 3339 format"},
+			{"deadline", "Due date in RFC 3339 format, empty if none"},
+			{"closed", "Time of closing in RFC 3339 format, empty if open"},
+		},
+	}
+	exportAttachmentsTable = exportTable{
+		Name:        "attachments",
+		Description: "The manifest of the files attached to the issues and comments, the files themselves aren't included.",
+		Columns: []exportColumn{
+			{"id", "Unique id of the attachment"},
+			{"uuid", "UUID of the attachment"},
+			{"name", "File name"},
+			{"size", "Size in bytes"},
+			{"issue_number", "Number of the issue or pull request"},
+			{"comment_id", "Id of the comment, 0 if attached to the issue description"},
+			{"download_count", "Number of downloads"},
+			{"download_url", "URL to download the file"},
+			{"created", "Upload time in RFC 3339 format"},
+		},
+	}
+)
+
+// exportTableWriter writes the rows of a table of an export in a format
+type exportTableWriter interface {
+	WriteRow(values ...any) error
+	Close() error
+}
+
+type jsonTableWriter struct {
+	w       *bufio.Writer
+	columns []exportColumn
+	rows    int
+}
+
+func newJSONTableWriter(w io.Writer, table *exportTable) (*jsonTableWriter, error) {
+	jw := &jsonTableWriter{w: bufio.NewWriter(w), columns: table.Columns}
+	_, err := jw.w.WriteString("[")
+	return jw, err
+}
+
+func (jw *jsonTableWriter) WriteRow(values ...any) error {
+	if jw.rows > 0 {
+		_ = jw.w.WriteByte(',')
+	}
+	jw.rows++
+	_, _ = jw.w.WriteString("\n  {")
+	for i, column := range jw.columns {
+		if i > 0 {
+			_ = jw.w.WriteByte(',')
+		}
+		bs, err := json.Marshal(values[i])
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(jw.w, "%q:", column.Name)
+		if _, err := jw.w.Write(bs); err != nil {
+			return err
+		}
+	}
+	_, err := jw.w.WriteString("}")
+	return err
+}
+
+func (jw *jsonTableWriter) Close() error {
+	_, _ = jw.w.WriteString("\n]\n")
+	return jw.w.Flush()
+}
+
+type csvTableWriter struct {
+	w *csv.Writer
+}
+
+func newCSVTableWriter(w io.Writer, table *exportTable) (*csvTableWriter, error) {
+	cw := &csvTableWriter{w: csv.NewWriter(w)}
+	header := make([]string, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		header = append(header, column.Name)
+	}
+	return cw, cw.w.Write(header)
+}
+
+func (cw *csvTableWriter) WriteRow(values ...any) error {
+	record := make([]string, 0, len(values))
+	for _, value := range values {
+		switch v := value.(type) {
+		case nil:
+			record = append(record, "")
+		case string:
+			record = append(record, v)
+		case []string:
+			record = append(record, strings.Join(v, ", "))
+		case bool:
+			record = append(record, strconv.FormatBool(v))
+		case *time.Time:
+			if v == nil {
+				record = append(record, "")
+			} else {
+				record = append(record, v.Format(time.RFC3339))
+			}
+		case time.Time:
+			record = append(record, v.Format(time.RFC3339))
+		default:
+			record = append(record, fmt.Sprint(v))
+		}
+	}
+	return cw.w.Write(record)
+}
+
+func (cw *csvTableWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// optionalTime returns nil for unset timestamps, so they are exported as empty values
+func optionalTime(ts timeutil.TimeStamp) *time.Time {
+	if ts == 0 {
+		return nil
+	}
+	return ts.AsTimePtr()
+}
+
+func exportState(isClosed bool) string {
+	if isClosed {
+		return "closed"
+	}
+	return "open"
+}
+
+type issueExporter struct {
+	repo   *repo_model.Repository
+	format ExportFormat
+	zw     *zip.Writer
+
+	issueNumbers map[int64]int64
+	userNames    map[int64]string
+}
+
+func (e *issueExporter) writeTable(table *exportTable, fill func(w exportTableWriter) error) error {
+	f, err := e.zw.Create(table.Name + "." + string(e.format))
+	if err != nil {
+		return err
+	}
+	var w exportTableWriter
+	if e.format == ExportFormatCSV {
+		w, err = newCSVTableWriter(f, table)
+	} else {
+		w, err = newJSONTableWriter(f, table)
+	}
+	if err != nil {
+		return err
+	}
+	if err := fill(w); err != nil {
+		return fmt.Errorf("export %s: %w", table.Name, err)
+	}
+	return w.Close()
+}
+
+func (e *issueExporter) userName(ctx context.Context, userID int64, originalAuthor string) string {
+	if originalAuthor != "" {
+		return originalAuthor
+	}
+	if name, ok := e.userNames[userID]; ok {
+		return name
+	}
+	name := user_model.NewGhostUser().Name
+	if u, err := user_model.GetPossibleUserByID(ctx, userID); err == nil {
+		name = u.Name
+	}
+	e.userNames[userID] = name
+	return name
+}
+
+func (e *issueExporter) writeReadme() error {
+	f, err := e.zw.Create("README.md")
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	_, _ = fmt.Fprintf(w, "# Issues of %s\n\nExported at %s. ", e.repo.FullName(), time.Now().UTC().Format(time.RFC3339))
+	if e.format == ExportFormatCSV {
+		_, _ = w.WriteString("Every file is a CSV file with a header row, lists of names are separated by \", \".\n")
+	} else {
+		_, _ = w.WriteString("Every file is a JSON array of objects with the documented keys.\n")
+	}
+	for _, table := range []*exportTable{&exportIssuesTable, &exportCommentsTable, &exportLabelsTable, &exportMilestonesTable, &exportAttachmentsTable} {
+		_, _ = fmt.Fprintf(w, "\n## %s.%s\n\n%s\n\n| Column | Description |\n| --- | --- |\n", table.Name, e.format, table.Description)
+		for _, column := range table.Columns {
+			_, _ = fmt.Fprintf(w, "| %s | %s |\n", column.Name, column.Description)
+		}
+	}
+	return w.Flush()
+}
+
+func (e *issueExporter) exportIssues(ctx context.Context, w exportTableWriter) error {
+	for page := 1; ; page++ {
+		issues, err := issues_model.Issues(ctx, &issues_model.IssuesOptions{
+			ListOptions: db.ListOptions{Page: page, PageSize: 50},
+			RepoIDs:     []int64{e.repo.ID},
+			SortType:    "oldest",
+		})
+		if err != nil {
+			return err
+		}
+		if len(issues) == 0 {
+			return nil
+		}
+
+		for _, issue := range issues {
+			e.issueNumbers[issue.ID] = issue.Index
+
+			labels := make([]string, 0, len(issue.Labels))
+			for _, label := range issue.Labels {
+				labels = append(labels, label.Name)
+			}
+			assignees := make([]string, 0, len(issue.Assignees))
+			for _, assignee := range issue.Assignees {
+				assignees = append(assignees, assignee.Name)
+			}
+			var headBranch, baseBranch, mergeCommit string
+			var merged bool
+			var mergedAt *time.Time
+			if issue.IsPull && issue.PullRequest != nil {
+				headBranch = issue.PullRequest.HeadBranch
+				baseBranch = issue.PullRequest.BaseBranch
+				merged = issue.PullRequest.HasMerged
+				mergedAt = optionalTime(issue.PullRequest.MergedUnix)
+				mergeCommit = issue.PullRequest.MergedCommitID
+			}
+
+			if err := w.WriteRow(
+				issue.ID,
+				issue.Index,
+				issue.IsPull,
+				issue.Title,
+				issue.Content,
+				exportState(issue.IsClosed),
+				issue.IsLocked,
+				issue.PosterID,
+				e.userName(ctx, issue.PosterID, issue.OriginalAuthor),
+				issue.MilestoneID,
+				labels,
+				assignees,
+				issue.Ref,
+				issue.NumComments,
+				issue.CreatedUnix.AsTime(),
+				issue.UpdatedUnix.AsTime(),
+				optionalTime(issue.ClosedUnix),
+				optionalTime(issue.DeadlineUnix),
+				headBranch,
+				baseBranch,
+				merged,
+				mergedAt,
+				mergeCommit,
+			); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (e *issueExporter) exportComments(ctx context.Context, w exportTableWriter) error {
+	cond := builder.In("issue_id", builder.Select("id").From("issue").Where(builder.Eq{"repo_id": e.repo.ID})).
+		And(builder.In("type", issues_model.CommentTypeComment, issues_model.CommentTypeReview, issues_model.CommentTypeCode))
+	return db.Iterate(ctx, cond, func(ctx context.Context, c *issues_model.Comment) error {
+		return w.WriteRow(
+			c.ID,
+			e.issueNumbers[c.IssueID],
+			c.Type.String(),
+			c.PosterID,
+			e.userName(ctx, c.PosterID, c.OriginalAuthor),
+			c.Content,
+			c.ReviewID,
+			c.TreePath,
+			c.Line,
+			c.CommitSHA,
+			c.CreatedUnix.AsTime(),
+			c.UpdatedUnix.AsTime(),
+		)
+	})
+}
+
+func (e *issueExporter) exportLabels(ctx context.Context, w exportTableWriter) error {
+	cond := builder.Eq{"repo_id": e.repo.ID}.Or(builder.Eq{"org_id": e.repo.OwnerID})
+	return db.Iterate(ctx, cond, func(ctx context.Context, label *issues_model.Label) error {
+		return w.WriteRow(
+			label.ID,
+			label.Name,
+			label.Description,
+			label.Color,
+			label.Exclusive,
+			label.OrgID > 0,
+			label.NumIssues,
+			label.NumClosedIssues,
+		)
+	})
+}
+
+func (e *issueExporter) exportMilestones(ctx context.Context, w exportTableWriter) error {
+	return db.Iterate(ctx, builder.Eq{"repo_id": e.repo.ID}, func(ctx context.Context, m *issues_model.Milestone) error {
+		var closed *time.Time
+		if m.IsClosed {
+			closed = optionalTime(m.ClosedDateUnix)
+		}
+		return w.WriteRow(
+			m.ID,
+			m.Name,
+			m.Content,
+			exportState(m.IsClosed),
+			m.NumIssues,
+			m.NumClosedIssues,
+			m.CreatedUnix.AsTime(),
+			optionalTime(m.DeadlineUnix),
+			closed,
+		)
+	})
+}
+
+func (e *issueExporter) exportAttachments(ctx context.Context, w exportTableWriter) error {
+	cond := builder.Eq{"repo_id": e.repo.ID}.And(builder.Gt{"issue_id": 0})
+	return db.Iterate(ctx, cond, func(ctx context.Context, a *repo_model.Attachment) error {
+		return w.WriteRow(
+			a.ID,
+			a.UUID,
+			a.Name,
+			a.Size,
+			e.issueNumbers[a.IssueID],
+			a.CommentID,
+			a.DownloadCount,
+			a.DownloadURL(),
+			a.CreatedUnix.AsTime(),
+		)
+	})
+}
+
+// ExportRepositoryIssues writes a zip archive with the issues, pull requests, comments, labels,
+// milestones and the attachment manifest of a repository to w. The archive contains a README
+// documenting its files, the files are streamed so the export doesn't need to fit in memory.
+func ExportRepositoryIssues(ctx context.Context, repo *repo_model.Repository, format ExportFormat, w io.Writer) error {
+	e := &issueExporter{
+		repo:         repo,
+		format:       format,
+		zw:           zip.NewWriter(w),
+		issueNumbers: make(map[int64]int64),
+		userNames:    make(map[int64]string),
+	}
+
+	if err := e.writeReadme(); err != nil {
+		return err
+	}
+	for _, t := range []struct {
+		table  *exportTable
+		export func(context.Context, exportTableWriter) error
+	}{
+		// the issues must be exported first, the other tables refer to their numbers
+		{&exportIssuesTable, e.exportIssues},
+		{&exportCommentsTable, e.exportComments},
+		{&exportLabelsTable, e.exportLabels},
+		{&exportMilestonesTable, e.exportMilestones},
+		{&exportAttachmentsTable, e.exportAttachments},
+	} {
+		export := t.export
+		if err := e.writeTable(t.table, func(w exportTableWriter) error {
+			return export(ctx, w)
+		}); err != nil {
+			return err
+		}
+	}
+	return e.zw.Close()
+}
@@ -0,0 +1,71 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"io"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/json"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func readExportFile(t *testing.T, archive []byte, name string) []byte {
+	zr, err := zip.NewReader(bytes.NewReader(archive), int64(len(archive)))
+	assert.NoError(t, err)
+	f, err := zr.Open(name)
+	assert.NoError(t, err)
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	assert.NoError(t, err)
+	return content
+}
+
+func TestExportRepositoryIssues(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, ExportRepositoryIssues(db.DefaultContext, repo, ExportFormatJSON, buf))
+
+	var issues []map[string]any
+	assert.NoError(t, json.Unmarshal(readExportFile(t, buf.Bytes(), "issues.json"), &issues))
+	assert.Len(t, issues, unittest.GetCount(t, &issues_model.Issue{RepoID: repo.ID}))
+	for _, issue := range issues {
+		assert.Len(t, issue, len(exportIssuesTable.Columns))
+		if issue["number"] == float64(2) {
+			assert.Equal(t, true, issue["is_pull"])
+		}
+	}
+
+	var labels []map[string]any
+	assert.NoError(t, json.Unmarshal(readExportFile(t, buf.Bytes(), "labels.json"), &labels))
+	assert.Len(t, labels, unittest.GetCount(t, &issues_model.Label{RepoID: repo.ID}))
+
+	readme := string(readExportFile(t, buf.Bytes(), "README.md"))
+	assert.Contains(t, readme, "## attachments.json")
+	assert.Contains(t, readme, "| merge_commit |")
+}
+
+func TestExportRepositoryIssuesCSV(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	buf := &bytes.Buffer{}
+	assert.NoError(t, ExportRepositoryIssues(db.DefaultContext, repo, ExportFormatCSV, buf))
+
+	records, err := csv.NewReader(bytes.NewReader(readExportFile(t, buf.Bytes(), "milestones.csv"))).ReadAll()
+	assert.NoError(t, err)
+	assert.Len(t, records, unittest.GetCount(t, &issues_model.Milestone{RepoID: repo.ID})+1)
+	assert.Equal(t, "id", records[0][0])
+	assert.Len(t, records[0], len(exportMilestonesTable.Columns))
+}
@@ -0,0 +1,177 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// MaxImportIssues is the maximum number of issues imported by a single request
+const MaxImportIssues = 100
+
+// issueImporter resolves the authors and labels of imported issues
+type issueImporter struct {
+	ctx    context.Context
+	doer   *user_model.User
+	repo   *repo_model.Repository
+	now    time.Time
+	users  map[string]string
+	userID map[string]int64
+	labels map[string]*issues_model.Label
+}
+
+// ImportIssues imports issues with their comments from another system, keeping their original timestamps.
+// Authors mapped to local users become the posters, the others are kept as placeholders showing
+// their original name like the issues of migrated repositories.
+func ImportIssues(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, opts *api.ImportIssuesOption) ([]*issues_model.Issue, error) {
+	if len(opts.Issues) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("no issues to import")
+	}
+	if len(opts.Issues) > MaxImportIssues {
+		return nil, util.NewInvalidArgumentErrorf("at most %d issues can be imported at once", MaxImportIssues)
+	}
+
+	labels, err := issues_model.GetLabelsByRepoID(ctx, repo.ID, "", db.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	imp := &issueImporter{
+		ctx:    ctx,
+		doer:   doer,
+		repo:   repo,
+		now:    time.Now(),
+		users:  opts.Users,
+		userID: make(map[string]int64),
+		labels: make(map[string]*issues_model.Label, len(labels)),
+	}
+	for _, label := range labels {
+		imp.labels[label.Name] = label
+	}
+
+	issues := make([]*issues_model.Issue, 0, len(opts.Issues))
+	for i := range opts.Issues {
+		issue, err := imp.toIssue(&opts.Issues[i])
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, issue)
+	}
+
+	if err := issues_model.ImportIssues(ctx, repo, issues); err != nil {
+		return nil, err
+	}
+	return issues, nil
+}
+
+func (imp *issueImporter) toIssue(opts *api.ImportIssue) (*issues_model.Issue, error) {
+	title := strings.TrimSpace(opts.Title)
+	if title == "" {
+		return nil, util.NewInvalidArgumentErrorf("issue title is required")
+	}
+
+	created, err := imp.timestamp(opts.Created, imp.now)
+	if err != nil {
+		return nil, err
+	}
+	updated, err := imp.timestamp(opts.Updated, created)
+	if err != nil {
+		return nil, err
+	}
+
+	issue := &issues_model.Issue{
+		Repo:        imp.repo,
+		Title:       title,
+		Content:     opts.Body,
+		IsClosed:    opts.Closed,
+		CreatedUnix: timeutil.TimeStamp(created.Unix()),
+		UpdatedUnix: timeutil.TimeStamp(updated.Unix()),
+	}
+	if opts.Closed {
+		closed, err := imp.timestamp(opts.ClosedAt, updated)
+		if err != nil {
+			return nil, err
+		}
+		issue.ClosedUnix = timeutil.TimeStamp(closed.Unix())
+	}
+	if err := imp.remapAuthor(opts.Author, issue); err != nil {
+		return nil, err
+	}
+
+	for _, name := range opts.Labels {
+		label, ok := imp.labels[name]
+		if !ok {
+			return nil, util.NewInvalidArgumentErrorf("label %q does not exist", name)
+		}
+		issue.Labels = append(issue.Labels, label)
+	}
+
+	for i := range opts.Comments {
+		c := &opts.Comments[i]
+		commentCreated, err := imp.timestamp(c.Created, created)
+		if err != nil {
+			return nil, err
+		}
+		commentUpdated, err := imp.timestamp(c.Updated, commentCreated)
+		if err != nil {
+			return nil, err
+		}
+		comment := &issues_model.Comment{
+			Type:        issues_model.CommentTypeComment,
+			Content:     c.Body,
+			CreatedUnix: timeutil.TimeStamp(commentCreated.Unix()),
+			UpdatedUnix: timeutil.TimeStamp(commentUpdated.Unix()),
+		}
+		if err := imp.remapAuthor(c.Author, comment); err != nil {
+			return nil, err
+		}
+		issue.Comments = append(issue.Comments, comment)
+	}
+	return issue, nil
+}
+
+// timestamp returns t or the default if it is not set, timestamps in the future are rejected
+func (imp *issueImporter) timestamp(t *time.Time, def time.Time) (time.Time, error) {
+	if t == nil || t.IsZero() {
+		return def, nil
+	}
+	if t.After(imp.now) {
+		return time.Time{}, util.NewInvalidArgumentErrorf("timestamp %s is in the future", t.Format(time.RFC3339))
+	}
+	return *t, nil
+}
+
+// remapAuthor makes the local user the author is mapped to the poster, or keeps the author as placeholder
+func (imp *issueImporter) remapAuthor(author api.ImportIssueAuthor, target user_model.ExternalUserRemappable) error {
+	if author.Name == "" {
+		return util.NewInvalidArgumentErrorf("author name is required")
+	}
+
+	userName, ok := imp.users[author.Name]
+	if !ok {
+		return target.RemapExternalUser(author.Name, author.ID, imp.doer.ID)
+	}
+	userID, ok := imp.userID[userName]
+	if !ok {
+		u, err := user_model.GetUserByName(imp.ctx, userName)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				return util.NewInvalidArgumentErrorf("user %q the author %q is mapped to does not exist", userName, author.Name)
+			}
+			return err
+		}
+		userID = u.ID
+		imp.userID[userName] = userID
+	}
+	return target.RemapExternalUser("", 0, userID)
+}
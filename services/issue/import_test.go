@@ -0,0 +1,90 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"testing"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImportIssues(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	label := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
+
+	created := time.Date(2015, 3, 1, 10, 0, 0, 0, time.UTC)
+	closed := created.Add(48 * time.Hour)
+	issues, err := ImportIssues(db.DefaultContext, doer, repo, &api.ImportIssuesOption{
+		Issues: []api.ImportIssue{{
+			Title:    "Imported issue",
+			Body:     "Imported from the old tracker",
+			Author:   api.ImportIssueAuthor{Name: "alice", ID: 42},
+			Labels:   []string{"label1"},
+			Closed:   true,
+			Created:  &created,
+			ClosedAt: &closed,
+			Comments: []api.ImportIssueComment{
+				{Author: api.ImportIssueAuthor{Name: "bob"}, Body: "Confirmed"},
+			},
+		}},
+		Users: map[string]string{"bob": "user4"},
+	})
+	assert.NoError(t, err)
+	if !assert.Len(t, issues, 1) {
+		return
+	}
+
+	issue := unittest.AssertExistsAndLoadBean(t, &issues_model.Issue{ID: issues[0].ID})
+	unittest.AssertCount(t, &issues_model.Issue{RepoID: repo.ID, Index: issue.Index}, 1)
+	assert.Equal(t, "alice", issue.OriginalAuthor)
+	assert.EqualValues(t, 42, issue.OriginalAuthorID)
+	assert.Equal(t, doer.ID, issue.PosterID)
+	assert.EqualValues(t, created.Unix(), issue.CreatedUnix)
+	assert.EqualValues(t, created.Unix(), issue.UpdatedUnix)
+	assert.EqualValues(t, closed.Unix(), issue.ClosedUnix)
+	assert.Equal(t, 1, issue.NumComments)
+	unittest.AssertExistsAndLoadBean(t, &issues_model.IssueLabel{IssueID: issue.ID, LabelID: label.ID})
+
+	comment := unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: issue.ID})
+	assert.EqualValues(t, 4, comment.PosterID)
+	assert.Empty(t, comment.OriginalAuthor)
+	assert.EqualValues(t, created.Unix(), comment.CreatedUnix)
+
+	updatedRepo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	assert.Equal(t, repo.NumIssues+1, updatedRepo.NumIssues)
+	assert.Equal(t, repo.NumClosedIssues+1, updatedRepo.NumClosedIssues)
+	updatedLabel := unittest.AssertExistsAndLoadBean(t, &issues_model.Label{ID: 1})
+	assert.Equal(t, label.NumClosedIssues+1, updatedLabel.NumClosedIssues)
+}
+
+func TestImportIssuesInvalid(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	doer := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	repo := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+
+	future := time.Now().Add(time.Hour)
+	for _, opts := range []*api.ImportIssuesOption{
+		{},
+		{Issues: []api.ImportIssue{{Title: "", Author: api.ImportIssueAuthor{Name: "alice"}}}},
+		{Issues: []api.ImportIssue{{Title: "Issue"}}},
+		{Issues: []api.ImportIssue{{Title: "Issue", Author: api.ImportIssueAuthor{Name: "alice"}, Labels: []string{"unknown"}}}},
+		{Issues: []api.ImportIssue{{Title: "Issue", Author: api.ImportIssueAuthor{Name: "alice"}, Created: &future}}},
+		{Issues: []api.ImportIssue{{Title: "Issue", Author: api.ImportIssueAuthor{Name: "alice"}}}, Users: map[string]string{"alice": "no-such-user"}},
+	} {
+		_, err := ImportIssues(db.DefaultContext, doer, repo, opts)
+		assert.ErrorIs(t, err, util.ErrInvalidArgument)
+	}
+	unittest.AssertNotExistsBean(t, &issues_model.Issue{RepoID: repo.ID, Title: "Issue"})
+}
@@ -18,10 +18,15 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/notification"
 	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/services/moderation"
 )
 
 // NewIssue creates new issue with labels for repository.
 func NewIssue(ctx context.Context, repo *repo_model.Repository, issue *issues_model.Issue, labelIDs []int64, uuids []string, assigneeIDs []int64) error {
+	if err := moderation.CheckInteraction(ctx, issue.Poster, repo); err != nil {
+		return err
+	}
+
 	if err := issues_model.NewIssue(repo, issue, labelIDs, uuids); err != nil {
 		return err
 	}
@@ -133,6 +138,10 @@ func UpdateAssignees(ctx context.Context, issue *issues_model.Issue, oneAssignee
 
 // DeleteIssue deletes an issue
 func DeleteIssue(ctx context.Context, doer *user_model.User, gitRepo *git.Repository, issue *issues_model.Issue) error {
+	if err := issues_model.CheckLegalHold(ctx, issue.RepoID, issue.ID); err != nil {
+		return err
+	}
+
 	// load issue before deleting it
 	if err := issue.LoadAttributes(ctx); err != nil {
 		return err
@@ -244,8 +253,14 @@ func deleteIssue(ctx context.Context, issue *issues_model.Issue) error {
 		return err
 	}
 
-	for i := range issue.Attachments {
-		system_model.RemoveStorageWithNotice(ctx, storage.Attachments, "Delete issue attachment", issue.Attachments[i].RelativePath())
+	removable, err := repo_model.RemovableAttachments(ctx, issue.Attachments)
+	if err != nil {
+		return err
+	}
+	for _, attachment := range removable {
+		for _, attachmentPath := range attachment.ObjectPaths() {
+			system_model.RemoveStorageWithNotice(ctx, storage.AttachmentsOfRegion(issue.Repo.StorageRegion()), "Delete issue attachment", attachmentPath)
+		}
 	}
 
 	// delete all database data still assigned to this issue
@@ -0,0 +1,80 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// RedactContentHistory redacts an old revision of an issue or a comment and records the redaction.
+// The current content can't be redacted, it has to be edited first. Redacting is allowed under a legal hold.
+func RedactContentHistory(ctx context.Context, doer *user_model.User, history *issues_model.ContentHistory, reason string) (*issues_model.LegalRedaction, error) {
+	if history.IsDeleted {
+		return nil, util.NewInvalidArgumentErrorf("the revision has already been deleted")
+	}
+	latest, err := issues_model.IsLatestContentHistory(ctx, history)
+	if err != nil {
+		return nil, err
+	} else if latest {
+		return nil, util.NewInvalidArgumentErrorf("the current content can't be redacted, edit the content first")
+	}
+
+	issue, err := issues_model.GetIssueByID(ctx, history.IssueID)
+	if err != nil {
+		return nil, err
+	}
+
+	redaction := &issues_model.LegalRedaction{
+		RepoID:           issue.RepoID,
+		IssueID:          issue.ID,
+		CommentID:        history.CommentID,
+		ContentHistoryID: history.ID,
+		Reason:           reason,
+		DoerID:           doer.ID,
+	}
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if err := issues_model.SoftDeleteIssueContentHistory(ctx, history.ID); err != nil {
+			return err
+		}
+		return issues_model.InsertLegalRedaction(ctx, redaction)
+	}); err != nil {
+		return nil, err
+	}
+	return redaction, nil
+}
+
+// RedactAttachment deletes an attachment of an issue or a comment and records the redaction.
+// Redacting is allowed under a legal hold.
+func RedactAttachment(ctx context.Context, doer *user_model.User, attach *repo_model.Attachment, reason string) (*issues_model.LegalRedaction, error) {
+	if attach.IssueID == 0 {
+		return nil, util.NewInvalidArgumentErrorf("only the attachments of issues and comments can be redacted")
+	}
+
+	redaction := &issues_model.LegalRedaction{
+		RepoID:         attach.RepoID,
+		IssueID:        attach.IssueID,
+		CommentID:      attach.CommentID,
+		AttachmentID:   attach.ID,
+		AttachmentUUID: attach.UUID,
+		AttachmentName: attach.Name,
+		Reason:         reason,
+		DoerID:         doer.ID,
+	}
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if err := issues_model.InsertLegalRedaction(ctx, redaction); err != nil {
+			return err
+		}
+		_, err := repo_model.DeleteAttachments(ctx, []*repo_model.Attachment{attach}, true)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	return redaction, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+)
+
+const expiredLocksBatchSize = 100
+
+// UnlockExpiredIssues unlocks the issues whose lock has expired
+func UnlockExpiredIssues(ctx context.Context) error {
+	doer := user_model.NewActionsUser()
+	for {
+		issues, err := issues_model.FindExpiredIssueLocks(ctx, expiredLocksBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			select {
+			case <-ctx.Done():
+				return db.ErrCancelledf("before unlocking issue %d", issue.ID)
+			default:
+			}
+
+			if err := issue.LoadRepo(ctx); err != nil {
+				return err
+			}
+			if err := issues_model.UnlockIssue(&issues_model.IssueLockOptions{
+				Doer:  doer,
+				Issue: issue,
+			}); err != nil {
+				return err
+			}
+		}
+		if len(issues) < expiredLocksBatchSize {
+			return nil
+		}
+	}
+}
+
+// LockClosedIssues runs all enabled lock policies, each policy locks at most operationsPerRepo issues per run
+func LockClosedIssues(ctx context.Context, operationsPerRepo int) error {
+	policies, err := issues_model.FindEnabledIssueLockPolicies(ctx)
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before processing the lock policy of repository %d", policy.RepoID)
+		default:
+		}
+
+		repo, err := repo_model.GetRepositoryByID(ctx, policy.RepoID)
+		if err != nil {
+			log.Error("GetRepositoryByID[%d]: %v", policy.RepoID, err)
+			continue
+		}
+		if repo.IsArchived {
+			continue
+		}
+		if err := ApplyLockPolicy(ctx, repo, policy, operationsPerRepo); err != nil {
+			log.Error("Unable to process the lock policy of %s: %v", repo.FullName(), err)
+		}
+	}
+	return nil
+}
+
+// ApplyLockPolicy locks the closed issues of a repository which have been inactive for longer than the policy allows
+func ApplyLockPolicy(ctx context.Context, repo *repo_model.Repository, policy *issues_model.IssueLockPolicy, limit int) error {
+	issues, err := issues_model.FindIssueLockCandidates(ctx, policy, time.Now().AddDate(0, 0, -policy.DaysAfterClose), limit)
+	if err != nil {
+		return err
+	}
+	doer := user_model.NewActionsUser()
+	for _, issue := range issues {
+		issue.Repo = repo
+		if policy.LockComment != "" {
+			if _, err := CreateIssueComment(ctx, doer, repo, issue, policy.LockComment, nil); err != nil {
+				return err
+			}
+		}
+		if err := issues_model.LockIssue(&issues_model.IssueLockOptions{
+			Doer:   doer,
+			Issue:  issue,
+			Reason: policy.Reason,
+			Level:  policy.LockLevel,
+		}); err != nil {
+			return err
+		}
+	}
+	return issues_model.UpdateIssueLockPolicyLastRun(ctx, policy)
+}
@@ -0,0 +1,279 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package issue
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	insights_model "code.gitea.io/gitea/models/insights"
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	notify_base "code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/queue"
+	insights_service "code.gitea.io/gitea/services/insights"
+)
+
+type subscriptionRuleRequest struct {
+	IssueID int64
+	DoerID  int64
+}
+
+var subscriptionRuleQueue *queue.WorkerPoolQueue[subscriptionRuleRequest]
+
+// InitSubscriptionRules starts the queue evaluating the auto-subscription rules of the users
+// when issues and pull requests are created or updated
+func InitSubscriptionRules() error {
+	subscriptionRuleQueue = queue.CreateSimpleQueue("issue_subscription_rule", subscriptionRuleHandler)
+	if subscriptionRuleQueue == nil {
+		return fmt.Errorf("Unable to create issue_subscription_rule Queue")
+	}
+	go graceful.GetManager().RunWithShutdownFns(subscriptionRuleQueue.Run)
+
+	notification.RegisterNotifier(&subscriptionRuleNotifier{})
+	return nil
+}
+
+func subscriptionRuleHandler(items ...subscriptionRuleRequest) []subscriptionRuleRequest {
+	ctx := graceful.GetManager().ShutdownContext()
+	for _, req := range items {
+		if err := applySubscriptionRules(ctx, req); err != nil {
+			log.Error("Unable to apply the subscription rules to issue %d: %v", req.IssueID, err)
+		}
+	}
+	return nil
+}
+
+type subscriptionRuleNotifier struct {
+	notify_base.NullNotifier
+}
+
+var _ notify_base.Notifier = &subscriptionRuleNotifier{}
+
+// NotifyNewIssue evaluates the subscription rules for a new issue
+func (*subscriptionRuleNotifier) NotifyNewIssue(ctx context.Context, issue *issues_model.Issue, mentions []*user_model.User) {
+	queueSubscriptionRules(issue.ID, issue.PosterID)
+}
+
+// NotifyNewPullRequest evaluates the subscription rules for a new pull request
+func (*subscriptionRuleNotifier) NotifyNewPullRequest(ctx context.Context, pr *issues_model.PullRequest, mentions []*user_model.User) {
+	if err := pr.LoadIssue(ctx); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	queueSubscriptionRules(pr.IssueID, pr.Issue.PosterID)
+}
+
+// NotifyIssueChangeLabels evaluates the subscription rules when labels are added to an issue or a pull request
+func (*subscriptionRuleNotifier) NotifyIssueChangeLabels(ctx context.Context, doer *user_model.User, issue *issues_model.Issue,
+	addedLabels, removedLabels []*issues_model.Label,
+) {
+	if len(addedLabels) > 0 {
+		queueSubscriptionRules(issue.ID, doer.ID)
+	}
+}
+
+// NotifyPullRequestSynchronized evaluates the subscription rules when new commits change other files
+func (*subscriptionRuleNotifier) NotifyPullRequestSynchronized(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest) {
+	queueSubscriptionRules(pr.IssueID, doer.ID)
+}
+
+func queueSubscriptionRules(issueID, doerID int64) {
+	if err := subscriptionRuleQueue.Push(subscriptionRuleRequest{IssueID: issueID, DoerID: doerID}); err != nil {
+		log.Error("Unable to add issue %d to the subscription rule queue: %v", issueID, err)
+	}
+}
+
+// subscriptionMatcher evaluates the rules of the users against an issue, the changed files and the path ownership
+// are only loaded for the rules which need them
+type subscriptionMatcher struct {
+	issue *issues_model.Issue
+
+	files       []string
+	filesLoaded bool
+	// owners are the emails of the main contributors of the directories
+	owners       map[string]container.Set[string]
+	ownersLoaded bool
+}
+
+func (m *subscriptionMatcher) changedFiles(ctx context.Context) ([]string, error) {
+	if m.filesLoaded {
+		return m.files, nil
+	}
+	m.filesLoaded = true
+
+	pr := m.issue.PullRequest
+	if pr.MergeBase == "" {
+		return nil, nil
+	}
+	gitRepo, err := git.OpenRepository(ctx, m.issue.Repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+	m.files, err = gitRepo.GetFilesChangedBetween(pr.MergeBase, pr.GetGitRefName())
+	return m.files, err
+}
+
+func (m *subscriptionMatcher) pathOwners(ctx context.Context) (map[string]container.Set[string], error) {
+	if m.ownersLoaded {
+		return m.owners, nil
+	}
+	m.ownersLoaded = true
+
+	contributions, err := insights_model.GetPathContributions(ctx, m.issue.RepoID, insights_service.OwnershipMaxDepth)
+	if err != nil {
+		return nil, err
+	}
+	m.owners = make(map[string]container.Set[string])
+	for _, po := range insights_model.SummarizePathOwnership(contributions) {
+		// everybody contributes to the root, only the directories have owners
+		if po.Path == "" {
+			continue
+		}
+		emails := make(container.Set[string], po.BusFactor)
+		for _, c := range po.Contributors[:po.BusFactor] {
+			emails.Add(strings.ToLower(c.AuthorEmail))
+		}
+		m.owners[po.Path] = emails
+	}
+	return m.owners, nil
+}
+
+// matchesOwnedPaths returns true if the pull request changes a directory of which the user is a main contributor
+func (m *subscriptionMatcher) matchesOwnedPaths(ctx context.Context, userID int64) (bool, error) {
+	owners, err := m.pathOwners(ctx)
+	if err != nil || len(owners) == 0 {
+		return false, err
+	}
+	files, err := m.changedFiles(ctx)
+	if err != nil || len(files) == 0 {
+		return false, err
+	}
+	emails, err := user_model.GetEmailAddresses(userID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, file := range files {
+		for dir := file; ; {
+			i := strings.LastIndexByte(dir, '/')
+			if i < 0 {
+				break
+			}
+			dir = dir[:i]
+			for _, email := range emails {
+				if email.IsActivated && owners[dir].Contains(email.LowerEmail) {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+func (m *subscriptionMatcher) matches(ctx context.Context, rule *issues_model.IssueSubscriptionRule) (bool, error) {
+	switch rule.Kind {
+	case issues_model.IssueSubscriptionRuleLabel:
+		return rule.MatchesLabels(m.issue.Labels), nil
+	case issues_model.IssueSubscriptionRulePath:
+		if !m.issue.IsPull {
+			return false, nil
+		}
+		files, err := m.changedFiles(ctx)
+		if err != nil {
+			return false, err
+		}
+		return rule.MatchesFiles(files), nil
+	case issues_model.IssueSubscriptionRuleOwnedPaths:
+		if !m.issue.IsPull {
+			return false, nil
+		}
+		return m.matchesOwnedPaths(ctx, rule.UserID)
+	}
+	return false, nil
+}
+
+// applySubscriptionRules subscribes the users whose rules match the issue and notifies them. Users who already
+// watch the issue or explicitly stopped watching it are left alone.
+func applySubscriptionRules(ctx context.Context, req subscriptionRuleRequest) error {
+	issue, err := issues_model.GetIssueByID(ctx, req.IssueID)
+	if err != nil {
+		if issues_model.IsErrIssueNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		return err
+	}
+	rules, err := issues_model.GetRepoIssueSubscriptionRules(ctx, issue.RepoID, issue.Repo.OwnerID)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+	if err := issue.LoadLabels(ctx); err != nil {
+		return err
+	}
+	if issue.IsPull {
+		if err := issue.LoadPullRequest(ctx); err != nil {
+			return err
+		}
+	}
+
+	m := &subscriptionMatcher{issue: issue}
+	checked := make(container.Set[int64])
+	for _, rule := range rules {
+		if rule.UserID == req.DoerID || checked.Contains(rule.UserID) {
+			continue
+		}
+		matched, err := m.matches(ctx, rule)
+		if err != nil {
+			return err
+		} else if !matched {
+			continue
+		}
+		checked.Add(rule.UserID)
+
+		if err := subscribeByRule(ctx, issue, rule.UserID, req.DoerID); err != nil {
+			log.Error("Unable to subscribe user %d to issue %d: %v", rule.UserID, issue.ID, err)
+		}
+	}
+	return nil
+}
+
+func subscribeByRule(ctx context.Context, issue *issues_model.Issue, userID, doerID int64) error {
+	if _, exists, err := issues_model.GetIssueWatch(ctx, userID, issue.ID); err != nil || exists {
+		return err
+	}
+	user, err := user_model.GetUserByID(ctx, userID)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if !user.IsActive || user.ProhibitLogin {
+		return nil
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, issue.Repo, user)
+	if err != nil {
+		return err
+	}
+	if !perm.CanReadIssuesOrPulls(issue.IsPull) {
+		return nil
+	}
+
+	if err := issues_model.CreateOrUpdateIssueWatch(userID, issue.ID, true); err != nil {
+		return err
+	}
+	return activities_model.CreateOrUpdateIssueNotifications(issue.ID, 0, doerID, userID)
+}
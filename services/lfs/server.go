@@ -16,6 +16,7 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 
 	actions_model "code.gitea.io/gitea/models/actions"
 	git_model "code.gitea.io/gitea/models/git"
@@ -42,6 +43,11 @@ type requestContext struct {
 	Authorization string
 }
 
+// contentStore returns the LFS content store of the storage region of the repository owner
+func (rc *requestContext) contentStore() *lfs_module.ContentStore {
+	return lfs_module.NewContentStoreOfRegion(user_model.StorageRegionOf(rc.User))
+}
+
 // Claims is a JWT Token Claims
 type Claims struct {
 	RepoID int64
@@ -50,6 +56,15 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
+// DirectUploadClaims identify an object a client uploaded directly to the storage, they are signed into the
+// verify link of the upload
+type DirectUploadClaims struct {
+	RepoID      int64
+	Oid         string
+	StagingPath string
+	jwt.RegisteredClaims
+}
+
 // DownloadLink builds a URL to download the object.
 func (rc *requestContext) DownloadLink(p lfs_module.Pointer) string {
 	return setting.AppURL + path.Join(url.PathEscape(rc.User), url.PathEscape(rc.Repo+".git"), "info/lfs/objects", url.PathEscape(p.Oid))
@@ -114,7 +129,7 @@ func DownloadHandler(ctx *context.Context) {
 		}
 	}
 
-	contentStore := lfs_module.NewContentStore()
+	contentStore := rc.contentStore()
 	content, err := contentStore.Get(meta.Pointer)
 	if err != nil {
 		writeStatus(ctx, http.StatusNotFound)
@@ -177,13 +192,13 @@ func BatchHandler(ctx *context.Context) {
 		return
 	}
 
-	contentStore := lfs_module.NewContentStore()
+	contentStore := rc.contentStore()
 
 	var responseObjects []*lfs_module.ObjectResponse
 
 	for _, p := range br.Objects {
 		if !p.IsValid() {
-			responseObjects = append(responseObjects, buildObjectResponse(rc, p, false, false, &lfs_module.ObjectError{
+			responseObjects = append(responseObjects, buildObjectResponse(rc, repository.ID, p, false, false, &lfs_module.ObjectError{
 				Code:    http.StatusUnprocessableEntity,
 				Message: "Oid or size are invalid",
 			}))
@@ -205,7 +220,7 @@ func BatchHandler(ctx *context.Context) {
 		}
 
 		if meta != nil && p.Size != meta.Size {
-			responseObjects = append(responseObjects, buildObjectResponse(rc, p, false, false, &lfs_module.ObjectError{
+			responseObjects = append(responseObjects, buildObjectResponse(rc, repository.ID, p, false, false, &lfs_module.ObjectError{
 				Code:    http.StatusUnprocessableEntity,
 				Message: fmt.Sprintf("Object %s is not %d bytes", p.Oid, p.Size),
 			}))
@@ -241,7 +256,7 @@ func BatchHandler(ctx *context.Context) {
 				}
 			}
 
-			responseObject = buildObjectResponse(rc, p, false, !exists, err)
+			responseObject = buildObjectResponse(rc, repository.ID, p, false, !exists, err)
 		} else {
 			var err *lfs_module.ObjectError
 			if !exists || meta == nil {
@@ -251,7 +266,7 @@ func BatchHandler(ctx *context.Context) {
 				}
 			}
 
-			responseObject = buildObjectResponse(rc, p, true, false, err)
+			responseObject = buildObjectResponse(rc, repository.ID, p, true, false, err)
 		}
 		responseObjects = append(responseObjects, responseObject)
 	}
@@ -287,7 +302,7 @@ func UploadHandler(ctx *context.Context) {
 		return
 	}
 
-	contentStore := lfs_module.NewContentStore()
+	contentStore := rc.contentStore()
 	exists, err := contentStore.Exists(p)
 	if err != nil {
 		log.Error("Unable to check if LFS OID[%s] exist. Error: %v", p.Oid, err)
@@ -355,12 +370,17 @@ func VerifyHandler(ctx *context.Context) {
 
 	rc := getRequestContext(ctx)
 
+	if token := ctx.Req.URL.Query().Get("upload"); token != "" {
+		verifyDirectUpload(ctx, rc, p, token)
+		return
+	}
+
 	meta := getAuthenticatedMeta(ctx, rc, p, true)
 	if meta == nil {
 		return
 	}
 
-	contentStore := lfs_module.NewContentStore()
+	contentStore := rc.contentStore()
 	ok, err := contentStore.Verify(meta.Pointer)
 
 	status := http.StatusOK
@@ -373,6 +393,43 @@ func VerifyHandler(ctx *context.Context) {
 	writeStatus(ctx, status)
 }
 
+// verifyDirectUpload moves an object the client uploaded directly to the storage into the content store
+func verifyDirectUpload(ctx *context.Context, rc *requestContext, p lfs_module.Pointer, token string) {
+	if !p.IsValid() {
+		writeStatusMessage(ctx, http.StatusUnprocessableEntity, "Oid or size are invalid")
+		return
+	}
+
+	repository := getAuthenticatedRepository(ctx, rc, true)
+	if repository == nil {
+		return
+	}
+
+	claims, err := parseDirectUploadToken(token)
+	if err != nil || claims.RepoID != repository.ID || claims.Oid != p.Oid {
+		log.Info("Attempt to verify the upload of LFS OID[%s] in %s/%s with an invalid token", p.Oid, rc.User, rc.Repo)
+		writeStatusMessage(ctx, http.StatusUnprocessableEntity, "Invalid upload token")
+		return
+	}
+
+	ok, err := rc.contentStore().FinishDirectUpload(p, claims.StagingPath)
+	if err != nil {
+		log.Error("Error whilst verifying the direct upload of LFS OID[%s]: %v", p.Oid, err)
+		writeStatus(ctx, http.StatusInternalServerError)
+		return
+	} else if !ok {
+		writeStatus(ctx, http.StatusNotFound)
+		return
+	}
+
+	if _, err := git_model.NewLFSMetaObject(ctx, &git_model.LFSMetaObject{Pointer: p, RepositoryID: repository.ID}); err != nil {
+		log.Error("Unable to create LFS MetaObject [%s] for %s/%s. Error: %v", p.Oid, rc.User, rc.Repo, err)
+		writeStatus(ctx, http.StatusInternalServerError)
+		return
+	}
+	writeStatus(ctx, http.StatusOK)
+}
+
 func decodeJSON(req *http.Request, v interface{}) error {
 	defer req.Body.Close()
 
@@ -417,6 +474,11 @@ func getAuthenticatedRepository(ctx *context.Context, rc *requestContext, requir
 		writeStatus(ctx, http.StatusNotFound)
 		return nil
 	}
+	// deleted repositories are hidden until they are restored or purged
+	if repository.IsPendingDeletion() {
+		writeStatus(ctx, http.StatusNotFound)
+		return nil
+	}
 
 	if !authenticate(ctx, repository, rc.Authorization, false, requireWrite) {
 		requireAuth(ctx)
@@ -431,7 +493,7 @@ func getAuthenticatedRepository(ctx *context.Context, rc *requestContext, requir
 	return repository
 }
 
-func buildObjectResponse(rc *requestContext, pointer lfs_module.Pointer, download, upload bool, err *lfs_module.ObjectError) *lfs_module.ObjectResponse {
+func buildObjectResponse(rc *requestContext, repoID int64, pointer lfs_module.Pointer, download, upload bool, err *lfs_module.ObjectError) *lfs_module.ObjectResponse {
 	rep := &lfs_module.ObjectResponse{Pointer: pointer}
 	if err != nil {
 		rep.Error = err
@@ -448,7 +510,7 @@ func buildObjectResponse(rc *requestContext, pointer lfs_module.Pointer, downloa
 			var link *lfs_module.Link
 			if setting.LFS.ServeDirect {
 				// If we have a signed url (S3, object storage), redirect to this directly.
-				u, err := storage.LFS.URL(pointer.RelativePath(), pointer.Oid)
+				u, err := rc.contentStore().URL(pointer.RelativePath(), pointer.Oid)
 				if u != nil && err == nil {
 					// Presigned url does not need the Authorization header
 					// https://github.com/go-gitea/gitea/issues/21525
@@ -462,7 +524,17 @@ func buildObjectResponse(rc *requestContext, pointer lfs_module.Pointer, downloa
 			rep.Actions["download"] = link
 		}
 		if upload {
-			rep.Actions["upload"] = &lfs_module.Link{Href: rc.UploadLink(pointer), Header: header}
+			uploadLink := &lfs_module.Link{Href: rc.UploadLink(pointer), Header: header}
+			verifyHref := rc.VerifyLink(pointer)
+			if setting.LFS.DirectUpload {
+				// If the storage accepts presigned uploads, the client uploads the object directly to it.
+				// The verify request moves the upload into place after the storage verified its content.
+				if link, token := directUploadLink(rc, repoID, pointer); link != nil {
+					uploadLink = link
+					verifyHref += "?upload=" + url.QueryEscape(token)
+				}
+			}
+			rep.Actions["upload"] = uploadLink
 
 			verifyHeader := make(map[string]string)
 			for key, value := range header {
@@ -472,12 +544,66 @@ func buildObjectResponse(rc *requestContext, pointer lfs_module.Pointer, downloa
 			// This is only needed to workaround https://github.com/git-lfs/git-lfs/issues/3662
 			verifyHeader["Accept"] = lfs_module.MediaType
 
-			rep.Actions["verify"] = &lfs_module.Link{Href: rc.VerifyLink(pointer), Header: verifyHeader}
+			rep.Actions["verify"] = &lfs_module.Link{Href: verifyHref, Header: verifyHeader}
 		}
 	}
 	return rep
 }
 
+// directUploadLink returns a presigned link to upload the object directly to the storage and the token which
+// identifies the upload in the verify request, or nil if the object has to be uploaded to Gitea
+func directUploadLink(rc *requestContext, repoID int64, pointer lfs_module.Pointer) (*lfs_module.Link, string) {
+	u, header, stagingPath, err := rc.contentStore().DirectUploadURL(pointer, setting.LFS.HTTPAuthExpiry)
+	if err != nil {
+		if err != storage.ErrURLNotSupported {
+			log.Error("Unable to create a direct upload URL for LFS OID[%s]: %v", pointer.Oid, err)
+		}
+		return nil, ""
+	}
+
+	now := time.Now()
+	claims := DirectUploadClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(now.Add(setting.LFS.HTTPAuthExpiry)),
+			NotBefore: jwt.NewNumericDate(now),
+		},
+		RepoID:      repoID,
+		Oid:         pointer.Oid,
+		StagingPath: stagingPath,
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(setting.LFS.JWTSecretBytes)
+	if err != nil {
+		log.Error("Unable to sign the direct upload token for LFS OID[%s]: %v", pointer.Oid, err)
+		return nil, ""
+	}
+
+	// Presigned url only needs the signed headers, not the Authorization header
+	linkHeader := make(map[string]string, len(header))
+	for key := range header {
+		linkHeader[key] = header.Get(key)
+	}
+	expiresAt := now.Add(setting.LFS.HTTPAuthExpiry)
+	return &lfs_module.Link{Href: u.String(), Header: linkHeader, ExpiresAt: &expiresAt}, token
+}
+
+// parseDirectUploadToken returns the claims of a valid direct upload token
+func parseDirectUploadToken(tokenString string) (*DirectUploadClaims, error) {
+	token, err := jwt.ParseWithClaims(tokenString, &DirectUploadClaims{}, func(t *jwt.Token) (interface{}, error) {
+		if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", t.Header["alg"])
+		}
+		return setting.LFS.JWTSecretBytes, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	claims, ok := token.Claims.(*DirectUploadClaims)
+	if !token.Valid || !ok || claims.Oid == "" || claims.StagingPath == "" {
+		return nil, errors.New("invalid direct upload token")
+	}
+	return claims, nil
+}
+
 func writeStatus(ctx *context.Context, status int) {
 	writeStatusMessage(ctx, status, http.StatusText(status))
 }
@@ -509,14 +635,8 @@ func authenticate(ctx *context.Context, repository *repo_model.Repository, autho
 			log.Error("Unable to GetTaskByID for task[%d] Error: %v", taskID, err)
 			return false
 		}
-		if task.RepoID != repository.ID {
-			return false
-		}
-
-		if task.IsForkPullRequest {
-			return accessMode <= perm.AccessModeRead
-		}
-		return accessMode <= perm.AccessModeWrite
+		taskMode := task.RepoAccessMode(repository)
+		return taskMode > perm.AccessModeNone && accessMode <= taskMode
 	}
 
 	// ctx.IsSigned is unnecessary here, this will be checked in perm.CanAccess
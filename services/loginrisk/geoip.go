@@ -0,0 +1,79 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package loginrisk
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// maxGeoIPResponseSize limits how much of the GeoIP response is read
+const maxGeoIPResponseSize = 16 * 1024
+
+var geoIPClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: proxy.Proxy(),
+	},
+}
+
+type geoIPResponse struct {
+	City    string `json:"city"`
+	Region  string `json:"region"`
+	Country string `json:"country"`
+}
+
+func (r *geoIPResponse) location() string {
+	parts := make([]string, 0, 3)
+	for _, part := range []string{r.City, r.Region, r.Country} {
+		if part = strings.TrimSpace(part); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// lookupLocation asks the configured GeoIP service where an IP address is located,
+// it returns an empty location if no service is configured
+func lookupLocation(ctx context.Context, ip string) (string, error) {
+	if setting.LoginRisk.GeoIPURL == "" || ip == "" {
+		return "", nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, setting.LoginRisk.GeoIPTimeout)
+	defer cancel()
+	u := strings.ReplaceAll(setting.LoginRisk.GeoIPURL, "{ip}", url.PathEscape(ip))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "Gitea "+setting.AppVer)
+
+	resp, err := geoIPClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("GeoIP service responded with status %d", resp.StatusCode)
+	}
+
+	var result geoIPResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxGeoIPResponseSize)).Decode(&result); err != nil {
+		return "", err
+	}
+	location := result.location()
+	if len(location) > 255 {
+		location = location[:255]
+	}
+	return location, nil
+}
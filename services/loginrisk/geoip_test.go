@@ -0,0 +1,43 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package loginrisk
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupLocation(t *testing.T) {
+	location, err := lookupLocation(context.Background(), "192.0.2.1")
+	assert.NoError(t, err)
+	assert.Empty(t, location)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/192.0.2.1":
+			_, _ = w.Write([]byte(`{"city":"Amsterdam","region":"","country":"Netherlands"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+	oldURL := setting.LoginRisk.GeoIPURL
+	setting.LoginRisk.GeoIPURL = server.URL + "/{ip}"
+	defer func() {
+		setting.LoginRisk.GeoIPURL = oldURL
+	}()
+
+	location, err = lookupLocation(context.Background(), "192.0.2.1")
+	assert.NoError(t, err)
+	assert.Equal(t, "Amsterdam, Netherlands", location)
+
+	_, err = lookupLocation(context.Background(), "192.0.2.2")
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package loginrisk
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"strconv"
+	"strings"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/context"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// CookieName is the name of the cookie identifying the device of a browser
+const CookieName = "gitea_device"
+
+// maxUserAgentLength limits how much of the user agent is stored
+const maxUserAgentLength = 512
+
+// maxDeviceCookieUsers limits how many users the device cookie of a browser keeps a token for
+const maxDeviceCookieUsers = 10
+
+// deviceToken is the token identifying the device of a browser for a user
+type deviceToken struct {
+	UID   int64
+	Token string
+}
+
+// parseDeviceTokens parses the value of the device cookie, which keeps a token per user who signed in from the browser
+// as "<uid>:<token>" separated by "|", the least recently used first. A token without a user is the one of a cookie of
+// older versions, which kept a single token for whoever signed in last.
+func parseDeviceTokens(value string) []deviceToken {
+	tokens := make([]deviceToken, 0, maxDeviceCookieUsers)
+	for _, entry := range strings.Split(value, "|") {
+		if entry == "" {
+			continue
+		}
+		uid, token, ok := strings.Cut(entry, ":")
+		if !ok {
+			tokens = append(tokens, deviceToken{Token: entry})
+			continue
+		}
+		id, err := strconv.ParseInt(uid, 10, 64)
+		if err != nil || id <= 0 || token == "" {
+			continue
+		}
+		tokens = append(tokens, deviceToken{UID: id, Token: token})
+	}
+	return tokens
+}
+
+// formatDeviceTokens returns the value of the device cookie keeping the most recently used tokens
+func formatDeviceTokens(tokens []deviceToken) string {
+	if len(tokens) > maxDeviceCookieUsers {
+		tokens = tokens[len(tokens)-maxDeviceCookieUsers:]
+	}
+	entries := make([]string, 0, len(tokens))
+	for _, t := range tokens {
+		entries = append(entries, strconv.FormatInt(t.UID, 10)+":"+t.Token)
+	}
+	return strings.Join(entries, "|")
+}
+
+// findDeviceToken returns the token of a user, or the token of a cookie of older versions,
+// which is only used until the user it belongs to signs in again
+func findDeviceToken(tokens []deviceToken, uid int64) string {
+	legacy := ""
+	for _, t := range tokens {
+		if t.UID == uid {
+			return t.Token
+		} else if t.UID == 0 {
+			legacy = t.Token
+		}
+	}
+	return legacy
+}
+
+// setDeviceToken makes the token the most recently used one of the user, dropping the token of a cookie of older
+// versions if it was the one of the user
+func setDeviceToken(tokens []deviceToken, uid int64, token string) []deviceToken {
+	updated := make([]deviceToken, 0, len(tokens)+1)
+	for _, t := range tokens {
+		if t.UID == uid || (t.UID == 0 && t.Token == token) {
+			continue
+		}
+		updated = append(updated, t)
+	}
+	return append(updated, deviceToken{UID: uid, Token: token})
+}
+
+func fingerprint(token string) string {
+	hash := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(hash[:])
+}
+
+func remoteIP(ctx *context.Context) string {
+	ip, _, err := net.SplitHostPort(ctx.RemoteAddr())
+	if err != nil {
+		return ctx.RemoteAddr()
+	}
+	return ip
+}
+
+// currentDevice returns the device of the request for the user, and the token identifying it in the device cookie
+func currentDevice(ctx *context.Context, u *user_model.User) (*auth_model.LoginDevice, string, error) {
+	token := findDeviceToken(parseDeviceTokens(ctx.GetSiteCookie(CookieName)), u.ID)
+	if token == "" {
+		return nil, "", nil
+	}
+	d, err := auth_model.GetLoginDevice(ctx, u.ID, fingerprint(token))
+	return d, token, err
+}
+
+// rememberDevice stores the token of the device of the user in the device cookie, keeping the ones of the other users
+func rememberDevice(ctx *context.Context, u *user_model.User, token string) {
+	tokens := setDeviceToken(parseDeviceTokens(ctx.GetSiteCookie(CookieName)), u.ID, token)
+	ctx.SetSiteCookie(CookieName, formatDeviceTokens(tokens), 86400*setting.LoginRisk.DeviceCookieDays)
+}
+
+// IsNewDevice returns if the user never signed in from the device of the request
+func IsNewDevice(ctx *context.Context, u *user_model.User) (bool, error) {
+	d, _, err := currentDevice(ctx, u)
+	return d == nil, err
+}
+
+// RequiresStepUp returns if the user has to pass the two-factor authentication of Gitea although
+// the authentication source skips it, because the user signs in from a new device
+func RequiresStepUp(ctx *context.Context, u *user_model.User) (bool, error) {
+	if !setting.LoginRisk.Enabled || !setting.LoginRisk.RequireTwoFactorForNewDevice {
+		return false, nil
+	}
+	return IsNewDevice(ctx, u)
+}
+
+// RecordSignIn records the device the user signed in from. The user is notified of sign-ins
+// from new devices, except for the first device recorded for the user.
+func RecordSignIn(ctx *context.Context, u *user_model.User) error {
+	if !setting.LoginRisk.Enabled {
+		return nil
+	}
+
+	d, token, err := currentDevice(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	userAgent := ctx.Req.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+	ip := remoteIP(ctx)
+
+	if d != nil {
+		if d.IP != ip {
+			if d.Location, err = lookupLocation(ctx, ip); err != nil {
+				log.Warn("Unable to look up the location of %s: %v", ip, err)
+			}
+		}
+		d.UserAgent = userAgent
+		d.IP = ip
+		rememberDevice(ctx, u, token)
+		return auth_model.UpdateLoginDeviceUsage(ctx, d)
+	}
+
+	hasDevices, err := auth_model.HasLoginDevices(ctx, u.ID)
+	if err != nil {
+		return err
+	}
+
+	token, err = util.CryptoRandomString(40)
+	if err != nil {
+		return err
+	}
+	d = &auth_model.LoginDevice{
+		UID:         u.ID,
+		Fingerprint: fingerprint(token),
+		UserAgent:   userAgent,
+		IP:          ip,
+	}
+	if d.Location, err = lookupLocation(ctx, ip); err != nil {
+		log.Warn("Unable to look up the location of %s: %v", ip, err)
+	}
+	if err := auth_model.CreateLoginDevice(ctx, d); err != nil {
+		return err
+	}
+	rememberDevice(ctx, u, token)
+
+	if hasDevices && setting.LoginRisk.NotifyNewDevice {
+		mailer.SendNewDeviceMail(u, d)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package loginrisk
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeviceTokens(t *testing.T) {
+	tokens := parseDeviceTokens("")
+	assert.Empty(t, tokens)
+	assert.Empty(t, findDeviceToken(tokens, 1))
+
+	// the cookie of older versions keeps a single token, it is used until its user signs in again
+	tokens = parseDeviceTokens("legacy")
+	assert.Equal(t, "legacy", findDeviceToken(tokens, 1))
+	assert.Equal(t, "legacy", findDeviceToken(tokens, 2))
+	tokens = setDeviceToken(tokens, 1, "legacy")
+	assert.Equal(t, "1:legacy", formatDeviceTokens(tokens))
+
+	// signing in as another user keeps the token of the first one
+	tokens = setDeviceToken(parseDeviceTokens(formatDeviceTokens(tokens)), 2, "second")
+	assert.Equal(t, "1:legacy|2:second", formatDeviceTokens(tokens))
+	assert.Equal(t, "legacy", findDeviceToken(tokens, 1))
+	assert.Equal(t, "second", findDeviceToken(tokens, 2))
+	assert.Empty(t, findDeviceToken(tokens, 3))
+
+	tokens = setDeviceToken(tokens, 1, "first")
+	assert.Equal(t, "2:second|1:first", formatDeviceTokens(tokens))
+
+	// invalid entries are ignored
+	tokens = parseDeviceTokens("x:bad|0:bad|3:|2:second")
+	assert.Equal(t, "2:second", formatDeviceTokens(tokens))
+
+	// only the most recently used tokens are kept
+	for i := int64(1); i <= maxDeviceCookieUsers+2; i++ {
+		tokens = setDeviceToken(tokens, i, "token")
+	}
+	tokens = parseDeviceTokens(formatDeviceTokens(tokens))
+	assert.Len(t, tokens, maxDeviceCookieUsers)
+	assert.EqualValues(t, 3, tokens[0].UID)
+	assert.Empty(t, findDeviceToken(tokens, 2))
+}
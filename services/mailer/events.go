@@ -0,0 +1,59 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"context"
+	"net/mail"
+
+	system_model "code.gitea.io/gitea/models/system"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// HandleDeliveryEvents updates the delivery status of the mails the events are about.
+// Users whose address bounced permanently or who reported a mail as spam stop receiving
+// notification mails until they enable them again.
+func HandleDeliveryEvents(ctx context.Context, providerName string, events []*DeliveryEvent) error {
+	for _, event := range events {
+		delivery, err := system_model.GetMailDeliveryByProviderMessageID(ctx, providerName, event.ProviderMessageID)
+		if err != nil {
+			return err
+		} else if delivery == nil {
+			log.Debug("Mail provider %s reported an event for unknown mail %q", providerName, event.ProviderMessageID)
+			continue
+		}
+		delivery.Status = event.Status
+		delivery.Error = event.Reason
+		if err := system_model.UpdateMailDelivery(ctx, delivery); err != nil {
+			return err
+		}
+
+		if event.Status == system_model.MailDeliveryBounced || event.Status == system_model.MailDeliveryComplained {
+			if err := disableEmailNotifications(ctx, delivery.Recipient); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func disableEmailNotifications(ctx context.Context, recipient string) error {
+	if addr, err := mail.ParseAddress(recipient); err == nil {
+		recipient = addr.Address
+	}
+	u, err := user_model.GetUserByEmail(ctx, recipient)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if u.EmailNotifications() == user_model.EmailNotificationsDisabled {
+		return nil
+	}
+	log.Info("Disabling email notifications of %s after mails to %s bounced or were reported as spam", u.Name, recipient)
+	u.EmailNotificationsPreference = user_model.EmailNotificationsDisabled
+	return user_model.UpdateUserCols(ctx, u, "email_notifications_preference")
+}
@@ -72,7 +72,7 @@ func (h *ReplyHandler) Handle(ctx context.Context, content *MailContent, doer *u
 	}
 
 	// Locked issues require write permissions
-	if issue.IsLocked && !perm.CanWriteIssuesOrPulls(issue.IsPull) && !doer.IsAdmin {
+	if issue.IsLockedFor(perm, doer) {
 		log.Debug("can't write issue or pull")
 		return nil
 	}
@@ -97,6 +97,10 @@ func (h *ReplyHandler) Handle(ctx context.Context, content *MailContent, doer *u
 						log.Info("Skipping disallowed attachment type: %s", attachment.Name)
 						continue
 					}
+					if upload.IsErrFileTooLarge(err) {
+						log.Info("Skipping attachment exceeding the size limit of its type: %s", attachment.Name)
+						continue
+					}
 					return err
 				}
 				attachmentIDs = append(attachmentIDs, a.UUID)
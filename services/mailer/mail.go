@@ -17,11 +17,13 @@ import (
 	"time"
 
 	activities_model "code.gitea.io/gitea/models/activities"
+	auth_model "code.gitea.io/gitea/models/auth"
 	issues_model "code.gitea.io/gitea/models/issues"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/base"
 	"code.gitea.io/gitea/modules/emoji"
+	"code.gitea.io/gitea/modules/graceful"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/markup"
 	"code.gitea.io/gitea/modules/markup/markdown"
@@ -31,8 +33,6 @@ import (
 	"code.gitea.io/gitea/modules/translation"
 	incoming_payload "code.gitea.io/gitea/services/mailer/incoming/payload"
 	"code.gitea.io/gitea/services/mailer/token"
-
-	"gopkg.in/gomail.v2"
 )
 
 const (
@@ -42,8 +42,15 @@ const (
 	mailAuthRegisterNotify base.TplName = "auth/register_notify"
 
 	mailNotifyCollaborator base.TplName = "notify/collaborator"
+	mailNotifyLeakedToken  base.TplName = "notify/leaked_token"
+	mailNotifyNewDevice    base.TplName = "notify/new_device"
+	mailNotifyGPGKeyExpiry base.TplName = "notify/gpg_key_expiry"
+	mailNotifySSHKeyExpiry base.TplName = "notify/ssh_key_expiry"
+	mailNotifyDigest       base.TplName = "notify/digest"
 
 	mailRepoTransferNotify base.TplName = "notify/repo_transfer"
+	mailRepoArchiveNotify  base.TplName = "notify/repo_archive"
+	mailRepoLicenseNotify  base.TplName = "notify/repo_license_violation"
 
 	// There's no actual limit for subject in RFC 5322
 	mailMaxSubjectRunes = 256
@@ -61,7 +68,8 @@ func SendTestMail(email string) error {
 		// No mail service configured
 		return nil
 	}
-	return gomail.Send(Sender, NewMessage(email, "Gitea Test Email!", "Gitea Test Email!").ToMessage())
+	_, _, err := deliver(graceful.GetManager().ShutdownContext(), NewMessage(email, "Gitea Test Email!", "Gitea Test Email!"))
+	return err
 }
 
 // sendUserMail sends a mail to the user
@@ -208,6 +216,82 @@ func SendCollaboratorMail(u, doer *user_model.User, repo *repo_model.Repository)
 	SendAsync(msg)
 }
 
+// SendLeakedAccessTokenMail notifies the owner of an access token that it was found in changes pushed to a repository.
+func SendLeakedAccessTokenMail(u *user_model.User, tokenName string, repo *repo_model.Repository, path string, revoked bool) {
+	if setting.MailService == nil || !u.IsActive {
+		// No mail service configured OR the user is inactive
+		return
+	}
+	locale := translation.NewLocale(u.Language)
+	repoName := repo.FullName()
+
+	subject := locale.Tr("mail.leaked_token.subject", tokenName, repoName)
+	data := map[string]interface{}{
+		"Subject":      subject,
+		"TokenName":    tokenName,
+		"RepoName":     repoName,
+		"Path":         path,
+		"Revoked":      revoked,
+		"Link":         repo.HTMLURL(),
+		"SettingsLink": setting.AppURL + "user/settings/applications",
+		"Language":     locale.Language(),
+		// helper
+		"locale":    locale,
+		"Str2html":  templates.Str2html,
+		"DotEscape": templates.DotEscape,
+	}
+
+	var content bytes.Buffer
+
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyLeakedToken), data); err != nil {
+		log.Error("Template: %v", err)
+		return
+	}
+
+	msg := NewMessage(u.Email, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, leaked access token", u.ID)
+
+	SendAsync(msg)
+}
+
+// SendNewDeviceMail notifies a user that somebody signed in to their account from a new device.
+func SendNewDeviceMail(u *user_model.User, device *auth_model.LoginDevice) {
+	if setting.MailService == nil || !u.IsActive {
+		// No mail service configured OR the user is inactive
+		return
+	}
+	locale := translation.NewLocale(u.Language)
+
+	subject := locale.Tr("mail.new_device.subject", setting.AppName)
+	data := map[string]interface{}{
+		"Subject":      subject,
+		"DisplayName":  u.DisplayName(),
+		"UserAgent":    device.UserAgent,
+		"IP":           device.IP,
+		"Location":     device.Location,
+		"Time":         device.CreatedUnix.FormatLong(),
+		"Link":         setting.AppURL,
+		"SettingsLink": setting.AppURL + "user/settings/security",
+		"Language":     locale.Language(),
+		// helper
+		"locale":    locale,
+		"Str2html":  templates.Str2html,
+		"DotEscape": templates.DotEscape,
+	}
+
+	var content bytes.Buffer
+
+	if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyNewDevice), data); err != nil {
+		log.Error("Template: %v", err)
+		return
+	}
+
+	msg := NewMessage(u.Email, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, new device sign-in", u.ID)
+
+	SendAsync(msg)
+}
+
 func composeIssueCommentMessages(ctx *mailCommentContext, lang string, recipients []*user_model.User, fromMention bool, info string) ([]*Message, error) {
 	var (
 		subject string
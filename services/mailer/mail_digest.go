@@ -0,0 +1,231 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// mailDigestEvent is a notification listed in a digest under its issue
+type mailDigestEvent struct {
+	Description string
+	Link        string
+	Created     timeutil.TimeStamp
+}
+
+// mailDigestIssue is an issue or a pull request listed in a digest with its notifications
+type mailDigestIssue struct {
+	Issue  *issues_model.Issue
+	Repo   string
+	Link   string
+	Events []*mailDigestEvent
+}
+
+// digestActionTypes are the actions which have their own description in a digest
+var digestActionTypes = map[activities_model.ActionType]bool{
+	activities_model.ActionCreateIssue:               true,
+	activities_model.ActionCreatePullRequest:         true,
+	activities_model.ActionCommentIssue:              true,
+	activities_model.ActionCommentPull:               true,
+	activities_model.ActionCloseIssue:                true,
+	activities_model.ActionReopenIssue:               true,
+	activities_model.ActionClosePullRequest:          true,
+	activities_model.ActionReopenPullRequest:         true,
+	activities_model.ActionMergePullRequest:          true,
+	activities_model.ActionAutoMergePullRequest:      true,
+	activities_model.ActionPullRequestReadyForReview: true,
+	activities_model.ActionPullReviewDismissed:       true,
+}
+
+func digestActionKey(opType activities_model.ActionType) string {
+	if digestActionTypes[opType] {
+		return "mail.digest.action." + opType.String()
+	}
+	return "mail.digest.action.updated"
+}
+
+// queueMailDigestItem returns the item queuing the notification for the digest of the user,
+// or nil if the user receives the notification immediately
+func queueMailDigestItem(ctx *mailCommentContext, user *user_model.User) (*activities_model.MailDigestItem, error) {
+	frequency, err := user_model.GetEmailDigestFrequency(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	if frequency == user_model.EmailDigestNone {
+		return nil, nil
+	}
+	item := &activities_model.MailDigestItem{
+		UserID:  user.ID,
+		RepoID:  ctx.Issue.RepoID,
+		IssueID: ctx.Issue.ID,
+		DoerID:  ctx.Doer.ID,
+		OpType:  ctx.ActionType,
+	}
+	if ctx.Comment != nil {
+		item.CommentID = ctx.Comment.ID
+	}
+	return item, nil
+}
+
+// SendMailDigests sends their digest to the users who receive it with the given frequency.
+// The hourly run also sends the pending notifications of the users who went back to immediate delivery.
+func SendMailDigests(ctx context.Context, frequency string) error {
+	if setting.MailService == nil {
+		// No mail service configured
+		return nil
+	}
+
+	userIDs, err := activities_model.FindMailDigestUserIDs(ctx)
+	if err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		userFrequency, err := user_model.GetEmailDigestFrequency(userID)
+		if err != nil {
+			return err
+		}
+		if userFrequency != frequency && !(frequency == user_model.EmailDigestHourly && userFrequency == user_model.EmailDigestNone) {
+			continue
+		}
+		if err := sendMailDigest(ctx, userID); err != nil {
+			log.Error("sendMailDigest [user: %d]: %v", userID, err)
+		}
+	}
+	return nil
+}
+
+func sendMailDigest(ctx context.Context, userID int64) error {
+	items, err := activities_model.GetMailDigestItems(ctx, userID)
+	if err != nil || len(items) == 0 {
+		return err
+	}
+	maxID := items[len(items)-1].ID
+
+	user, err := user_model.GetUserByID(ctx, userID)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			return activities_model.DeleteMailDigestItems(ctx, userID, maxID)
+		}
+		return err
+	}
+	if !user.IsActive || user.EmailNotifications() == user_model.EmailNotificationsDisabled {
+		return activities_model.DeleteMailDigestItems(ctx, userID, maxID)
+	}
+
+	issueIDs := make([]int64, 0, len(items))
+	doerIDs := make([]int64, 0, len(items))
+	for _, item := range items {
+		issueIDs = append(issueIDs, item.IssueID)
+		doerIDs = append(doerIDs, item.DoerID)
+	}
+	issues, err := issues_model.GetIssuesByIDs(ctx, issueIDs)
+	if err != nil {
+		return err
+	}
+	repos, err := issues.LoadRepositories(ctx)
+	if err != nil {
+		return err
+	}
+	perms, err := access_model.GetUserRepoPermissions(ctx, repos, user)
+	if err != nil {
+		return err
+	}
+	doers, err := user_model.GetUsersByIDs(doerIDs)
+	if err != nil {
+		return err
+	}
+	doerNames := make(map[int64]string, len(doers))
+	for _, doer := range doers {
+		doerNames[doer.ID] = doer.DisplayName()
+	}
+
+	locale := translation.NewLocale(user.Language)
+
+	issueMap := make(map[int64]*mailDigestIssue, len(issues))
+	digestIssues := make([]*mailDigestIssue, 0, len(issues))
+	for _, issue := range issues {
+		checkUnit := unit.TypeIssues
+		if issue.IsPull {
+			checkUnit = unit.TypePullRequests
+		}
+		// the user might have lost the access to the issue since the notification was queued
+		if perm := perms[issue.RepoID]; !user.IsAdmin && !perm.CanRead(checkUnit) {
+			continue
+		}
+		issueMap[issue.ID] = &mailDigestIssue{
+			Issue: issue,
+			Repo:  issue.Repo.FullName(),
+			Link:  issue.HTMLURL(),
+		}
+	}
+
+	numEvents := 0
+	for _, item := range items {
+		digestIssue, ok := issueMap[item.IssueID]
+		if !ok {
+			continue
+		}
+		doerName, ok := doerNames[item.DoerID]
+		if !ok {
+			doerName = user_model.NewGhostUser().DisplayName()
+		}
+		link := digestIssue.Link
+		if item.CommentID > 0 {
+			link += "#" + issues_model.CommentHashTag(item.CommentID)
+		}
+		if len(digestIssue.Events) == 0 {
+			digestIssues = append(digestIssues, digestIssue)
+		}
+		digestIssue.Events = append(digestIssue.Events, &mailDigestEvent{
+			Description: locale.Tr(digestActionKey(item.OpType), doerName),
+			Link:        link,
+			Created:     item.CreatedUnix,
+		})
+		numEvents++
+	}
+
+	if numEvents > 0 {
+		subject := locale.TrN(numEvents, "mail.digest.subject_1", "mail.digest.subject_n", numEvents)
+		data := map[string]interface{}{
+			"Subject":  subject,
+			"Issues":   digestIssues,
+			"Link":     setting.AppURL + "notifications",
+			"Language": locale.Language(),
+			// helper
+			"locale":    locale,
+			"Str2html":  templates.Str2html,
+			"DotEscape": templates.DotEscape,
+		}
+
+		var content bytes.Buffer
+		if err := bodyTemplates.ExecuteTemplate(&content, string(mailNotifyDigest), data); err != nil {
+			return err
+		}
+
+		msg := NewMessage(user.Email, subject, content.String())
+		msg.Info = fmt.Sprintf("UID: %d, email notification digest", user.ID)
+		SendAsync(msg)
+	}
+
+	return activities_model.DeleteMailDigestItems(ctx, userID, maxID)
+}
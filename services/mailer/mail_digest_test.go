@@ -0,0 +1,53 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"context"
+	"testing"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMailIssueCommentBatchDigest(t *testing.T) {
+	doer, _, issue, comment := prepareMailerTest(t)
+	receiver := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 5})
+	assert.NoError(t, user_model.SetEmailDigestFrequency(receiver.ID, user_model.EmailDigestHourly))
+
+	ctx := &mailCommentContext{
+		Context: context.TODO(),
+		Issue:   issue, Doer: doer, ActionType: activities_model.ActionCommentIssue,
+		Content: "test body", Comment: comment,
+	}
+	assert.NoError(t, mailIssueCommentBatch(ctx, []*user_model.User{receiver}, make(container.Set[int64]), false))
+
+	items, err := activities_model.GetMailDigestItems(db.DefaultContext, receiver.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, items, 1) {
+		assert.Equal(t, issue.ID, items[0].IssueID)
+		assert.Equal(t, comment.ID, items[0].CommentID)
+		assert.Equal(t, activities_model.ActionCommentIssue, items[0].OpType)
+	}
+
+	userIDs, err := activities_model.FindMailDigestUserIDs(db.DefaultContext)
+	assert.NoError(t, err)
+	assert.Equal(t, []int64{receiver.ID}, userIDs)
+
+	assert.NoError(t, activities_model.DeleteMailDigestItems(db.DefaultContext, receiver.ID, items[0].ID))
+	items, err = activities_model.GetMailDigestItems(db.DefaultContext, receiver.ID)
+	assert.NoError(t, err)
+	assert.Empty(t, items)
+}
+
+func TestDigestActionKey(t *testing.T) {
+	assert.Equal(t, "mail.digest.action.comment_pull", digestActionKey(activities_model.ActionCommentPull))
+	assert.Equal(t, "mail.digest.action.updated", digestActionKey(activities_model.ActionType(0)))
+	assert.Equal(t, "mail.digest.action.updated", digestActionKey(activities_model.ActionStarRepo))
+}
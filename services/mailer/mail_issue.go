@@ -127,6 +127,7 @@ func mailIssueCommentBatch(ctx *mailCommentContext, users []*user_model.User, vi
 	}
 
 	langMap := make(map[string][]*user_model.User)
+	digestItems := make([]*activities_model.MailDigestItem, 0, len(users))
 	for _, user := range users {
 		if !user.IsActive {
 			// Exclude deactivated users
@@ -150,9 +151,25 @@ func mailIssueCommentBatch(ctx *mailCommentContext, users []*user_model.User, vi
 			continue
 		}
 
+		// mentions are always sent immediately, the other notifications may wait for the digest of the user
+		if !fromMention {
+			item, err := queueMailDigestItem(ctx, user)
+			if err != nil {
+				return err
+			}
+			if item != nil {
+				digestItems = append(digestItems, item)
+				continue
+			}
+		}
+
 		langMap[user.Language] = append(langMap[user.Language], user)
 	}
 
+	if err := activities_model.AddMailDigestItems(ctx, digestItems); err != nil {
+		return err
+	}
+
 	for lang, receivers := range langMap {
 		// because we know that the len(receivers) > 0 and we don't care about the order particularly
 		// working backwards from the last (possibly) incomplete batch. If len(receivers) can be 0 this
@@ -0,0 +1,117 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"time"
+
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/translation"
+)
+
+// SendKeyExpiryMails notifies the owners of GPG and SSH keys which expire within the notice period, once per key.
+// The expiry date of a SSH key is the one its owner set, keys without one are never notified.
+func SendKeyExpiryMails(ctx context.Context, noticeDays int) error {
+	if setting.MailService == nil {
+		// No mail service configured
+		return nil
+	}
+
+	until := timeutil.TimeStamp(time.Now().AddDate(0, 0, noticeDays).Unix())
+	gpgKeys, err := asymkey_model.FindExpiringGPGKeys(ctx, until)
+	if err != nil {
+		return err
+	}
+	for _, key := range gpgKeys {
+		owner, err := getKeyOwnerToNotify(ctx, key.OwnerID)
+		if err != nil {
+			return err
+		}
+		if owner != nil {
+			sendKeyExpiryMail(owner, mailNotifyGPGKeyExpiry, "gpg_key_expiry", key.PaddedKeyID(), key.ExpiredUnix)
+		}
+		if err := asymkey_model.SetGPGKeyExpiryMailed(ctx, key); err != nil {
+			return err
+		}
+	}
+
+	sshKeys, err := asymkey_model.FindExpiringPublicKeys(ctx, until)
+	if err != nil {
+		return err
+	}
+	for _, key := range sshKeys {
+		owner, err := getKeyOwnerToNotify(ctx, key.OwnerID)
+		if err != nil {
+			return err
+		}
+		if owner != nil {
+			sendKeyExpiryMail(owner, mailNotifySSHKeyExpiry, "ssh_key_expiry", key.Fingerprint, key.ExpiredUnix)
+		}
+		if err := asymkey_model.SetPublicKeyExpiryMailed(ctx, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getKeyOwnerToNotify returns the owner of a key if it is still active, nil otherwise
+func getKeyOwnerToNotify(ctx context.Context, ownerID int64) (*user_model.User, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	default:
+	}
+
+	owner, err := user_model.GetUserByID(ctx, ownerID)
+	if err != nil {
+		if user_model.IsErrUserNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !owner.IsActive {
+		return nil, nil
+	}
+	return owner, nil
+}
+
+func sendKeyExpiryMail(u *user_model.User, tplName base.TplName, localeKey, keyName string, expiry timeutil.TimeStamp) {
+	locale := translation.NewLocale(u.Language)
+
+	subject := locale.Tr("mail."+localeKey+".subject", keyName)
+	data := map[string]interface{}{
+		"Subject":      subject,
+		"DisplayName":  u.DisplayName(),
+		"KeyID":        keyName,
+		"ExpiryDate":   expiry.FormatDate(),
+		"Link":         setting.AppURL,
+		"SettingsLink": setting.AppURL + "user/settings/keys",
+		"Language":     locale.Language(),
+		// helper
+		"locale":    locale,
+		"Str2html":  templates.Str2html,
+		"DotEscape": templates.DotEscape,
+	}
+
+	var content bytes.Buffer
+
+	if err := bodyTemplates.ExecuteTemplate(&content, string(tplName), data); err != nil {
+		log.Error("Template: %v", err)
+		return
+	}
+
+	msg := NewMessage(u.Email, subject, content.String())
+	msg.Info = fmt.Sprintf("UID: %d, expiring key", u.ID)
+
+	SendAsync(msg)
+}
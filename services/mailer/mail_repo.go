@@ -9,10 +9,12 @@ import (
 	"fmt"
 
 	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/models/perm"
 	repo_model "code.gitea.io/gitea/models/repo"
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/templates"
+	"code.gitea.io/gitea/modules/timeutil"
 	"code.gitea.io/gitea/modules/translation"
 )
 
@@ -91,3 +93,139 @@ func sendRepoTransferNotifyMailPerLang(lang string, newOwner, doer *user_model.U
 
 	return nil
 }
+
+// SendRepoArchiveNoticeMail notifies the owners of an inactive repository that it is going to be archived
+func SendRepoArchiveNoticeMail(ctx context.Context, repo *repo_model.Repository, lastActivity, archiveTime timeutil.TimeStamp) error {
+	if setting.MailService == nil {
+		// No mail service configured
+		return nil
+	}
+	if err := repo.LoadOwner(ctx); err != nil {
+		return err
+	}
+
+	recipients := []*user_model.User{repo.Owner}
+	if repo.Owner.IsOrganization() {
+		team, err := organization.GetOwnerTeam(ctx, repo.OwnerID)
+		if err != nil {
+			return err
+		}
+		if err := team.LoadMembers(ctx); err != nil {
+			return err
+		}
+		recipients = team.Members
+	}
+
+	langMap := make(map[string][]string)
+	for _, user := range recipients {
+		if !user.IsActive || user.Email == "" {
+			// don't send emails to inactive users
+			continue
+		}
+		langMap[user.Language] = append(langMap[user.Language], user.Email)
+	}
+
+	for lang, tos := range langMap {
+		locale := translation.NewLocale(lang)
+		subject := locale.Tr("mail.repo.archive.subject", repo.FullName())
+		data := map[string]interface{}{
+			"Subject":      subject,
+			"Repo":         repo.FullName(),
+			"Link":         repo.HTMLURL(),
+			"LastActivity": lastActivity.FormatDate(),
+			"ArchiveDate":  archiveTime.FormatDate(),
+			"Language":     locale.Language(),
+			// helper
+			"locale":    locale,
+			"Str2html":  templates.Str2html,
+			"DotEscape": templates.DotEscape,
+		}
+
+		var content bytes.Buffer
+		if err := bodyTemplates.ExecuteTemplate(&content, string(mailRepoArchiveNotify), data); err != nil {
+			return err
+		}
+
+		for _, to := range tos {
+			msg := NewMessage(to, subject, content.String())
+			msg.Info = fmt.Sprintf("RepoID: %d, inactive repository archive notice", repo.ID)
+
+			SendAsync(msg)
+		}
+	}
+	return nil
+}
+
+// SendRepoLicenseViolationMail notifies the admins of the organization owning a repository that
+// the licenses of the repository or of its dependencies violate the license policy of the organization
+func SendRepoLicenseViolationMail(ctx context.Context, repo *repo_model.Repository, licenses repo_model.RepoLicenseList) error {
+	if setting.MailService == nil {
+		// No mail service configured
+		return nil
+	}
+
+	var forbidden repo_model.RepoLicenseList
+	for _, l := range licenses {
+		if l.Forbidden {
+			forbidden = append(forbidden, l)
+		}
+	}
+	if len(forbidden) == 0 {
+		return nil
+	}
+
+	teams, err := organization.FindOrgTeams(ctx, repo.OwnerID)
+	if err != nil {
+		return err
+	}
+	recipients := make(map[int64]*user_model.User)
+	for _, team := range teams {
+		if !team.IsOwnerTeam() && team.AccessMode < perm.AccessModeAdmin {
+			continue
+		}
+		if err := team.LoadMembers(ctx); err != nil {
+			return err
+		}
+		for _, u := range team.Members {
+			recipients[u.ID] = u
+		}
+	}
+
+	langMap := make(map[string][]string)
+	for _, user := range recipients {
+		if !user.IsActive || user.Email == "" {
+			// don't send emails to inactive users
+			continue
+		}
+		langMap[user.Language] = append(langMap[user.Language], user.Email)
+	}
+
+	for lang, tos := range langMap {
+		locale := translation.NewLocale(lang)
+		subject := locale.Tr("mail.repo.license_violation.subject", repo.FullName())
+		data := map[string]interface{}{
+			"Subject":  subject,
+			"Repo":     repo.FullName(),
+			"Link":     repo.HTMLURL(),
+			"Licenses": forbidden,
+			"Language": locale.Language(),
+			// helper
+			"locale":    locale,
+			"Str2html":  templates.Str2html,
+			"DotEscape": templates.DotEscape,
+		}
+
+		var content bytes.Buffer
+		if err := bodyTemplates.ExecuteTemplate(&content, string(mailRepoLicenseNotify), data); err != nil {
+			return err
+		}
+
+		for _, to := range tos {
+			msg := NewMessage(to, subject, content.String())
+			msg.Info = fmt.Sprintf("RepoID: %d, license policy violation", repo.ID)
+
+			SendAsync(msg)
+		}
+	}
+	return nil
+}
@@ -56,23 +56,14 @@ func (m *Message) ToMessage() *gomail.Message {
 		msg.SetHeader(header, m.Headers[header]...)
 	}
 
-	if len(setting.MailService.SubjectPrefix) > 0 {
-		msg.SetHeader("Subject", setting.MailService.SubjectPrefix+" "+m.Subject)
-	} else {
-		msg.SetHeader("Subject", m.Subject)
-	}
+	msg.SetHeader("Subject", m.fullSubject())
 	msg.SetDateHeader("Date", m.Date)
 	msg.SetHeader("X-Auto-Response-Suppress", "All")
 
-	plainBody, err := html2text.FromString(m.Body)
-	if err != nil || setting.MailService.SendAsPlainText {
-		if strings.Contains(base.TruncateString(m.Body, 100), "<html>") {
-			log.Warn("Mail contains HTML but configured to send as plain text.")
-		}
-		msg.SetBody("text/plain", plainBody)
-	} else {
-		msg.SetBody("text/plain", plainBody)
-		msg.AddAlternative("text/html", m.Body)
+	plainBody, htmlBody := m.bodies()
+	msg.SetBody("text/plain", plainBody)
+	if htmlBody != "" {
+		msg.AddAlternative("text/html", htmlBody)
 	}
 
 	if len(msg.GetHeader("Message-ID")) == 0 {
@@ -81,11 +72,39 @@ func (m *Message) ToMessage() *gomail.Message {
 	return msg
 }
 
+// fullSubject returns the subject with the configured prefix
+func (m *Message) fullSubject() string {
+	if len(setting.MailService.SubjectPrefix) > 0 {
+		return setting.MailService.SubjectPrefix + " " + m.Subject
+	}
+	return m.Subject
+}
+
+// bodies returns the plain text body and the html body, which is empty if the mail is sent as plain text
+func (m *Message) bodies() (plainBody, htmlBody string) {
+	plainBody, err := html2text.FromString(m.Body)
+	if err != nil || setting.MailService.SendAsPlainText {
+		if strings.Contains(base.TruncateString(m.Body, 100), "<html>") {
+			log.Warn("Mail contains HTML but configured to send as plain text.")
+		}
+		return plainBody, ""
+	}
+	return plainBody, m.Body
+}
+
 // SetHeader adds additional headers to a message
 func (m *Message) SetHeader(field string, value ...string) {
 	m.Headers[field] = value
 }
 
+// messageID returns the Message-ID header of the mail
+func (m *Message) messageID() string {
+	if ids := m.Headers["Message-ID"]; len(ids) > 0 {
+		return ids[0]
+	}
+	return m.generateAutoMessageID()
+}
+
 func (m *Message) generateAutoMessageID() string {
 	dateMs := m.Date.UnixNano() / 1e6
 	h := fnv.New64()
@@ -176,11 +195,13 @@ func (a *ntlmAuth) Next(fromServer []byte, more bool) ([]byte, error) {
 }
 
 // Sender SMTP mail sender
-type smtpSender struct{}
+type smtpSender struct {
+	opts *setting.Mailer
+}
 
 // Send send email
 func (s *smtpSender) Send(from string, to []string, msg io.WriterTo) error {
-	opts := setting.MailService
+	opts := s.opts
 
 	var network string
 	var address string
@@ -278,8 +299,8 @@ func (s *smtpSender) Send(from string, to []string, msg io.WriterTo) error {
 		}
 	}
 
-	if opts.OverrideEnvelopeFrom {
-		if err = client.Mail(opts.EnvelopeFrom); err != nil {
+	if setting.MailService.OverrideEnvelopeFrom {
+		if err = client.Mail(setting.MailService.EnvelopeFrom); err != nil {
 			return fmt.Errorf("failed to issue MAIL command: %w", err)
 		}
 	} else {
@@ -307,10 +328,13 @@ func (s *smtpSender) Send(from string, to []string, msg io.WriterTo) error {
 }
 
 // Sender sendmail mail sender
-type sendmailSender struct{}
+type sendmailSender struct {
+	opts *setting.Mailer
+}
 
 // Send send email
 func (s *sendmailSender) Send(from string, to []string, msg io.WriterTo) error {
+	opts := s.opts
 	var err error
 	var closeError error
 	var waitError error
@@ -321,16 +345,16 @@ func (s *sendmailSender) Send(from string, to []string, msg io.WriterTo) error {
 	}
 
 	args := []string{"-f", envelopeFrom, "-i"}
-	args = append(args, setting.MailService.SendmailArgs...)
+	args = append(args, opts.SendmailArgs...)
 	args = append(args, to...)
-	log.Trace("Sending with: %s %v", setting.MailService.SendmailPath, args)
+	log.Trace("Sending with: %s %v", opts.SendmailPath, args)
 
-	desc := fmt.Sprintf("SendMail: %s %v", setting.MailService.SendmailPath, args)
+	desc := fmt.Sprintf("SendMail: %s %v", opts.SendmailPath, args)
 
-	ctx, _, finished := process.GetManager().AddContextTimeout(graceful.GetManager().HammerContext(), setting.MailService.SendmailTimeout, desc)
+	ctx, _, finished := process.GetManager().AddContextTimeout(graceful.GetManager().HammerContext(), opts.SendmailTimeout, desc)
 	defer finished()
 
-	cmd := exec.CommandContext(ctx, setting.MailService.SendmailPath, args...)
+	cmd := exec.CommandContext(ctx, opts.SendmailPath, args...)
 	pipe, err := cmd.StdinPipe()
 	if err != nil {
 		return err
@@ -342,7 +366,7 @@ func (s *sendmailSender) Send(from string, to []string, msg io.WriterTo) error {
 		return err
 	}
 
-	if setting.MailService.SendmailConvertCRLF {
+	if opts.SendmailConvertCRLF {
 		buf := &strings.Builder{}
 		_, err = msg.WriteTo(buf)
 		if err == nil {
@@ -380,9 +404,6 @@ func (s *dummySender) Send(from string, to []string, msg io.WriterTo) error {
 
 var mailQueue *queue.WorkerPoolQueue[*Message]
 
-// Sender sender for sending mail synchronously
-var Sender gomail.Sender
-
 // NewContext start mail queue service
 func NewContext(ctx context.Context) {
 	// Need to check if mailQueue is nil because in during reinstall (user had installed
@@ -392,23 +413,15 @@ func NewContext(ctx context.Context) {
 		return
 	}
 
-	switch setting.MailService.Protocol {
-	case "sendmail":
-		Sender = &sendmailSender{}
-	case "dummy":
-		Sender = &dummySender{}
-	default:
-		Sender = &smtpSender{}
-	}
+	initProviders()
 
 	mailQueue = queue.CreateSimpleQueue("mail", func(items ...*Message) []*Message {
 		for _, msg := range items {
-			gomailMsg := msg.ToMessage()
-			log.Trace("New e-mail sending request %s: %s", gomailMsg.GetHeader("To"), msg.Info)
-			if err := gomail.Send(Sender, gomailMsg); err != nil {
-				log.Error("Failed to send emails %s: %s - %v", gomailMsg.GetHeader("To"), msg.Info, err)
+			log.Trace("New e-mail sending request %s: %s", msg.To, msg.Info)
+			if err := sendAndTrack(graceful.GetManager().ShutdownContext(), msg); err != nil {
+				log.Error("Failed to send emails %s: %s - %v", msg.To, msg.Info, err)
 			} else {
-				log.Trace("E-mails sent %s: %s", gomailMsg.GetHeader("To"), msg.Info)
+				log.Trace("E-mails sent %s: %s", msg.To, msg.Info)
 			}
 		}
 		return nil
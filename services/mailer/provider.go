@@ -0,0 +1,208 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+
+	"gopkg.in/gomail.v2"
+)
+
+// Provider delivers mails, either through a gomail.Sender or the HTTP API of a mail service
+type Provider interface {
+	Name() string
+	// Send delivers the mail and returns the id the provider assigned to it, if any
+	Send(ctx context.Context, msg *Message) (string, error)
+}
+
+// DeliveryEvent is a delivery status reported back by a provider
+type DeliveryEvent struct {
+	ProviderMessageID string
+	Recipient         string
+	Status            system_model.MailDeliveryStatus
+	Reason            string
+}
+
+// EventParser is implemented by providers which report delivery events to Gitea
+type EventParser interface {
+	ParseEvents(ctx context.Context, req *http.Request) ([]*DeliveryEvent, error)
+}
+
+// gomailProvider sends through SMTP, sendmail or the dummy sender
+type gomailProvider struct {
+	name   string
+	sender gomail.Sender
+}
+
+func (p *gomailProvider) Name() string { return p.name }
+
+func (p *gomailProvider) Send(_ context.Context, msg *Message) (string, error) {
+	return "", gomail.Send(p.sender, msg.ToMessage())
+}
+
+var apiClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: proxy.Proxy(),
+	},
+}
+
+func newProvider(name string, opts *setting.Mailer) Provider {
+	switch opts.Protocol {
+	case "sendgrid":
+		return &sendgridProvider{name: name, opts: opts}
+	case "mailgun":
+		return &mailgunProvider{name: name, opts: opts}
+	case "ses":
+		return &sesProvider{name: name, opts: opts}
+	case "sendmail":
+		return &gomailProvider{name: name, sender: &sendmailSender{opts: opts}}
+	case "dummy":
+		return &gomailProvider{name: name, sender: &dummySender{}}
+	default:
+		return &gomailProvider{name: name, sender: &smtpSender{opts: opts}}
+	}
+}
+
+var providers map[string]Provider
+
+func initProviders() {
+	providers = map[string]Provider{
+		setting.DefaultMailProvider: newProvider(setting.DefaultMailProvider, setting.MailService),
+	}
+	for name, opts := range setting.MailService.Providers {
+		providers[name] = newProvider(name, opts)
+	}
+}
+
+// GetProvider returns the configured provider with the given name
+func GetProvider(name string) Provider {
+	return providers[name]
+}
+
+// routedProvider returns the name of the provider the routing rules assign to the recipient domain.
+// Rules for a domain take precedence over wildcard rules like "*.example.com", the most specific
+// wildcard wins.
+func routedProvider(routing map[string]string, to string) string {
+	domain := strings.ToLower(to[strings.LastIndex(to, "@")+1:])
+	domain = strings.TrimSuffix(domain, ">")
+	if name, ok := routing[domain]; ok {
+		return name
+	}
+	for {
+		idx := strings.IndexByte(domain, '.')
+		if idx < 0 {
+			return setting.DefaultMailProvider
+		}
+		domain = domain[idx+1:]
+		if name, ok := routing["*."+domain]; ok {
+			return name
+		}
+	}
+}
+
+// providerChain returns the names of the providers to try in order for a recipient:
+// the routed provider followed by the failover providers
+func providerChain(to string) []string {
+	chain := []string{routedProvider(setting.MailService.Routing, to)}
+	for _, name := range setting.MailService.Failover {
+		if name != chain[0] {
+			chain = append(chain, name)
+		}
+	}
+	return chain
+}
+
+// deliver sends the mail through the first provider of the chain which accepts it
+func deliver(ctx context.Context, msg *Message) (providerName, providerMessageID string, err error) {
+	if providers == nil {
+		initProviders()
+	}
+	var errs []string
+	for _, name := range providerChain(msg.To) {
+		provider, ok := providers[name]
+		if !ok {
+			continue
+		}
+		providerMessageID, err = provider.Send(ctx, msg)
+		if err == nil {
+			return name, providerMessageID, nil
+		}
+		log.Warn("Mail provider %s failed to send to %s: %v", name, msg.To, err)
+		errs = append(errs, fmt.Sprintf("%s: %v", name, err))
+	}
+	return "", "", fmt.Errorf("all mail providers failed: %s", strings.Join(errs, "; "))
+}
+
+// ProviderAttempt is the result of sending a mail through a provider
+type ProviderAttempt struct {
+	Provider          string
+	ProviderMessageID string
+	Duration          time.Duration
+	Err               error
+}
+
+// SendTestMailThroughProviders sends a test mail through the providers of the chain of the address until one of
+// them accepts it, like the other mails are sent, and returns the attempt of each provider which was tried
+func SendTestMailThroughProviders(ctx context.Context, email string) []*ProviderAttempt {
+	if providers == nil {
+		initProviders()
+	}
+	msg := NewMessage(email, "Gitea Test Email!", "Gitea Test Email!")
+	var attempts []*ProviderAttempt
+	for _, name := range providerChain(msg.To) {
+		attempt := &ProviderAttempt{Provider: name}
+		attempts = append(attempts, attempt)
+		provider, ok := providers[name]
+		if !ok {
+			attempt.Err = fmt.Errorf("mail provider %s is not configured", name)
+			continue
+		}
+		start := time.Now()
+		attempt.ProviderMessageID, attempt.Err = provider.Send(ctx, msg)
+		attempt.Duration = time.Since(start)
+		if attempt.Err == nil {
+			break
+		}
+	}
+	return attempts
+}
+
+// sendAndTrack sends the mail and records its delivery status
+func sendAndTrack(ctx context.Context, msg *Message) error {
+	delivery := &system_model.MailDelivery{
+		MessageID: msg.messageID(),
+		Recipient: msg.To,
+		Subject:   msg.Subject,
+	}
+	if err := system_model.CreateMailDelivery(ctx, delivery); err != nil {
+		log.Error("Failed to record mail delivery to %s: %v", msg.To, err)
+		delivery = nil
+	}
+
+	name, providerMessageID, sendErr := deliver(ctx, msg)
+	if delivery == nil {
+		return sendErr
+	}
+	delivery.Provider = name
+	delivery.ProviderMessageID = providerMessageID
+	if sendErr != nil {
+		delivery.Status = system_model.MailDeliveryFailed
+		delivery.Error = sendErr.Error()
+	} else {
+		delivery.Status = system_model.MailDeliverySent
+	}
+	if err := system_model.UpdateMailDelivery(ctx, delivery); err != nil {
+		log.Error("Failed to update mail delivery %d: %v", delivery.ID, err)
+	}
+	return sendErr
+}
@@ -0,0 +1,142 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strings"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// mailgunProvider sends the MIME message through the Mailgun messages API
+type mailgunProvider struct {
+	name string
+	opts *setting.Mailer
+}
+
+func (p *mailgunProvider) Name() string { return p.name }
+
+func (p *mailgunProvider) Send(ctx context.Context, msg *Message) (string, error) {
+	body := &bytes.Buffer{}
+	form := multipart.NewWriter(body)
+	if err := form.WriteField("to", msg.To); err != nil {
+		return "", err
+	}
+	part, err := form.CreateFormFile("message", "message.mime")
+	if err != nil {
+		return "", err
+	}
+	if _, err := msg.ToMessage().WriteTo(part); err != nil {
+		return "", err
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	url := p.opts.APIURL
+	if url == "" {
+		url = "https://api.mailgun.net"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%s/v3/%s/messages.mime", strings.TrimSuffix(url, "/"), p.opts.APIDomain), body)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	req.SetBasicAuth("api", p.opts.APIKey)
+
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxEventsSize))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("mailgun responded with status %d: %s", resp.StatusCode, respBody)
+	}
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", err
+	}
+	return strings.Trim(result.ID, "<>"), nil
+}
+
+type mailgunWebhook struct {
+	Signature struct {
+		Timestamp string `json:"timestamp"`
+		Token     string `json:"token"`
+		Signature string `json:"signature"`
+	} `json:"signature"`
+	EventData struct {
+		Event     string `json:"event"`
+		Severity  string `json:"severity"`
+		Recipient string `json:"recipient"`
+		Message   struct {
+			Headers struct {
+				MessageID string `json:"message-id"`
+			} `json:"headers"`
+		} `json:"message"`
+		DeliveryStatus struct {
+			Message     string `json:"message"`
+			Description string `json:"description"`
+		} `json:"delivery-status"`
+	} `json:"event-data"`
+}
+
+// ParseEvents parses an event posted by a Mailgun webhook. If API_SECRET is set to the
+// webhook signing key, the signature of the event is verified.
+func (p *mailgunProvider) ParseEvents(_ context.Context, req *http.Request) ([]*DeliveryEvent, error) {
+	var webhook mailgunWebhook
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxEventsSize)).Decode(&webhook); err != nil {
+		return nil, err
+	}
+	if p.opts.APISecret != "" {
+		mac := hmac.New(sha256.New, []byte(p.opts.APISecret))
+		_, _ = mac.Write([]byte(webhook.Signature.Timestamp + webhook.Signature.Token))
+		if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(webhook.Signature.Signature)) {
+			return nil, errors.New("invalid mailgun webhook signature")
+		}
+	}
+
+	data := webhook.EventData
+	event := &DeliveryEvent{
+		ProviderMessageID: data.Message.Headers.MessageID,
+		Recipient:         data.Recipient,
+		Reason:            data.DeliveryStatus.Message,
+	}
+	if event.Reason == "" {
+		event.Reason = data.DeliveryStatus.Description
+	}
+	switch data.Event {
+	case "delivered":
+		event.Status = system_model.MailDeliveryDelivered
+	case "failed":
+		// temporary failures are retried by mailgun
+		if data.Severity != "permanent" {
+			return nil, nil
+		}
+		event.Status = system_model.MailDeliveryBounced
+	case "complained":
+		event.Status = system_model.MailDeliveryComplained
+	default:
+		return nil, nil
+	}
+	return []*DeliveryEvent{event}, nil
+}
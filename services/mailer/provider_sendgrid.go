@@ -0,0 +1,144 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// maxEventsSize limits how much of a provider response or event payload is read
+const maxEventsSize = 1024 * 1024
+
+type sendgridAddress struct {
+	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
+}
+
+type sendgridPersonalization struct {
+	To []sendgridAddress `json:"to"`
+}
+
+type sendgridContent struct {
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+type sendgridMail struct {
+	Personalizations []sendgridPersonalization `json:"personalizations"`
+	From             sendgridAddress           `json:"from"`
+	ReplyTo          *sendgridAddress          `json:"reply_to,omitempty"`
+	Subject          string                    `json:"subject"`
+	Content          []sendgridContent         `json:"content"`
+	Headers          map[string]string         `json:"headers,omitempty"`
+}
+
+// sendgridProvider sends through the SendGrid v3 mail send API
+type sendgridProvider struct {
+	name string
+	opts *setting.Mailer
+}
+
+func (p *sendgridProvider) Name() string { return p.name }
+
+func (p *sendgridProvider) Send(ctx context.Context, msg *Message) (string, error) {
+	plainBody, htmlBody := msg.bodies()
+	mail := &sendgridMail{
+		Personalizations: []sendgridPersonalization{{To: []sendgridAddress{{Email: msg.To}}}},
+		From:             sendgridAddress{Email: msg.FromAddress, Name: msg.FromDisplayName},
+		Subject:          msg.fullSubject(),
+		Content:          []sendgridContent{{Type: "text/plain", Value: plainBody}},
+		Headers: map[string]string{
+			"Message-ID":               msg.messageID(),
+			"X-Auto-Response-Suppress": "All",
+		},
+	}
+	if htmlBody != "" {
+		mail.Content = append(mail.Content, sendgridContent{Type: "text/html", Value: htmlBody})
+	}
+	if msg.ReplyTo != "" {
+		mail.ReplyTo = &sendgridAddress{Email: msg.ReplyTo}
+	}
+	for header, values := range msg.Headers {
+		mail.Headers[header] = strings.Join(values, ", ")
+	}
+	body, err := json.Marshal(mail)
+	if err != nil {
+		return "", err
+	}
+
+	url := p.opts.APIURL
+	if url == "" {
+		url = "https://api.sendgrid.com"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(url, "/")+"/v3/mail/send", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+p.opts.APIKey)
+
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxEventsSize))
+		return "", fmt.Errorf("sendgrid responded with status %d: %s", resp.StatusCode, respBody)
+	}
+	return resp.Header.Get("X-Message-Id"), nil
+}
+
+type sendgridEvent struct {
+	Email     string `json:"email"`
+	Event     string `json:"event"`
+	MessageID string `json:"sg_message_id"`
+	Reason    string `json:"reason"`
+	// Type is "bounce" or "blocked" for bounce events
+	Type string `json:"type"`
+}
+
+// ParseEvents parses the events posted by the SendGrid event webhook
+func (p *sendgridProvider) ParseEvents(_ context.Context, req *http.Request) ([]*DeliveryEvent, error) {
+	var sgEvents []*sendgridEvent
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxEventsSize)).Decode(&sgEvents); err != nil {
+		return nil, err
+	}
+	events := make([]*DeliveryEvent, 0, len(sgEvents))
+	for _, sgEvent := range sgEvents {
+		event := &DeliveryEvent{
+			Recipient: sgEvent.Email,
+			Reason:    sgEvent.Reason,
+		}
+		// the event message id is the id returned when sending followed by a filter suffix
+		event.ProviderMessageID, _, _ = strings.Cut(sgEvent.MessageID, ".")
+		switch sgEvent.Event {
+		case "delivered":
+			event.Status = system_model.MailDeliveryDelivered
+		case "bounce":
+			if sgEvent.Type == "blocked" {
+				event.Status = system_model.MailDeliveryFailed
+			} else {
+				event.Status = system_model.MailDeliveryBounced
+			}
+		case "dropped":
+			event.Status = system_model.MailDeliveryFailed
+		case "spamreport":
+			event.Status = system_model.MailDeliveryComplained
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
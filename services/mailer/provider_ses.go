@@ -0,0 +1,245 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// sesProvider sends the MIME message through the Amazon SES v2 API.
+// API_KEY is the access key id and API_SECRET the secret access key.
+type sesProvider struct {
+	name string
+	opts *setting.Mailer
+}
+
+func (p *sesProvider) Name() string { return p.name }
+
+type sesSendEmail struct {
+	FromEmailAddress string `json:"FromEmailAddress"`
+	Destination      struct {
+		ToAddresses []string `json:"ToAddresses"`
+	} `json:"Destination"`
+	Content struct {
+		Raw struct {
+			Data []byte `json:"Data"`
+		} `json:"Raw"`
+	} `json:"Content"`
+}
+
+func (p *sesProvider) Send(ctx context.Context, msg *Message) (string, error) {
+	raw := &bytes.Buffer{}
+	if _, err := msg.ToMessage().WriteTo(raw); err != nil {
+		return "", err
+	}
+	mail := &sesSendEmail{FromEmailAddress: msg.FromAddress}
+	mail.Destination.ToAddresses = []string{msg.To}
+	mail.Content.Raw.Data = raw.Bytes()
+	body, err := json.Marshal(mail)
+	if err != nil {
+		return "", err
+	}
+
+	region := p.opts.APIRegion
+	if region == "" {
+		region = "us-east-1"
+	}
+	endpoint := p.opts.APIURL
+	if endpoint == "" {
+		endpoint = fmt.Sprintf("https://email.%s.amazonaws.com", region)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimSuffix(endpoint, "/")+"/v2/email/outbound-emails", bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	signAWSRequest(req, body, p.opts.APIKey, p.opts.APISecret, region, "ses", time.Now())
+
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, maxEventsSize))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return "", fmt.Errorf("ses responded with status %d: %s", resp.StatusCode, respBody)
+	}
+	var result struct {
+		MessageID string `json:"MessageId"`
+	}
+	if err := json.Unmarshal(respBody, &result); err != nil {
+		return "", err
+	}
+	return result.MessageID, nil
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	_, _ = mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// signAWSRequest signs a request without query parameters with AWS signature version 4
+func signAWSRequest(req *http.Request, body []byte, accessKey, secretKey, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	payloadHash := sha256Hex(body)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	signedHeaders := "content-type;host;x-amz-content-sha256;x-amz-date"
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		req.URL.RawQuery,
+		"content-type:" + req.Header.Get("Content-Type"),
+		"host:" + req.URL.Host,
+		"x-amz-content-sha256:" + payloadHash,
+		"x-amz-date:" + amzDate,
+		"",
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{date, region, service, "aws4_request"}, "/")
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", accessKey, scope, signedHeaders, signature))
+}
+
+type snsMessage struct {
+	Type         string `json:"Type"`
+	Message      string `json:"Message"`
+	SubscribeURL string `json:"SubscribeURL"`
+}
+
+type sesRecipient struct {
+	EmailAddress   string `json:"emailAddress"`
+	DiagnosticCode string `json:"diagnosticCode"`
+}
+
+type sesNotification struct {
+	// NotificationType is set by identity notifications, EventType by configuration set event publishing
+	NotificationType string `json:"notificationType"`
+	EventType        string `json:"eventType"`
+	Mail             struct {
+		MessageID string `json:"messageId"`
+	} `json:"mail"`
+	Bounce struct {
+		BounceType        string          `json:"bounceType"`
+		BouncedRecipients []*sesRecipient `json:"bouncedRecipients"`
+	} `json:"bounce"`
+	Complaint struct {
+		ComplainedRecipients []*sesRecipient `json:"complainedRecipients"`
+	} `json:"complaint"`
+	Delivery struct {
+		Recipients []string `json:"recipients"`
+	} `json:"delivery"`
+}
+
+// ParseEvents parses the SES notifications delivered by an SNS HTTPS subscription.
+// Subscription confirmations are confirmed automatically.
+func (p *sesProvider) ParseEvents(ctx context.Context, req *http.Request) ([]*DeliveryEvent, error) {
+	var sns snsMessage
+	if err := json.NewDecoder(io.LimitReader(req.Body, maxEventsSize)).Decode(&sns); err != nil {
+		return nil, err
+	}
+	switch sns.Type {
+	case "SubscriptionConfirmation":
+		return nil, confirmSNSSubscription(ctx, sns.SubscribeURL)
+	case "Notification":
+	default:
+		return nil, nil
+	}
+
+	var notification sesNotification
+	if err := json.Unmarshal([]byte(sns.Message), &notification); err != nil {
+		return nil, err
+	}
+	kind := notification.EventType
+	if kind == "" {
+		kind = notification.NotificationType
+	}
+	messageID := notification.Mail.MessageID
+
+	var events []*DeliveryEvent
+	switch kind {
+	case "Delivery":
+		for _, recipient := range notification.Delivery.Recipients {
+			events = append(events, &DeliveryEvent{ProviderMessageID: messageID, Recipient: recipient, Status: system_model.MailDeliveryDelivered})
+		}
+	case "Bounce":
+		status := system_model.MailDeliveryBounced
+		// transient bounces like a full mailbox do not mean the address is invalid
+		if notification.Bounce.BounceType != "Permanent" {
+			status = system_model.MailDeliveryFailed
+		}
+		for _, recipient := range notification.Bounce.BouncedRecipients {
+			events = append(events, &DeliveryEvent{ProviderMessageID: messageID, Recipient: recipient.EmailAddress, Status: status, Reason: recipient.DiagnosticCode})
+		}
+	case "Complaint":
+		for _, recipient := range notification.Complaint.ComplainedRecipients {
+			events = append(events, &DeliveryEvent{ProviderMessageID: messageID, Recipient: recipient.EmailAddress, Status: system_model.MailDeliveryComplained})
+		}
+	}
+	return events, nil
+}
+
+func confirmSNSSubscription(ctx context.Context, subscribeURL string) error {
+	u, err := url.Parse(subscribeURL)
+	if err != nil {
+		return err
+	}
+	// only follow confirmation links pointing to SNS itself
+	if u.Scheme != "https" || !strings.HasPrefix(u.Hostname(), "sns.") || !strings.HasSuffix(u.Hostname(), ".amazonaws.com") {
+		return fmt.Errorf("invalid SNS subscribe url %q", subscribeURL)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return err
+	}
+	resp, err := apiClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("SNS subscription confirmation responded with status %d", resp.StatusCode)
+	}
+	log.Info("Confirmed SNS subscription for mail provider events")
+	return nil
+}
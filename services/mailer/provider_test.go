@@ -0,0 +1,57 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mailer
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoutedProvider(t *testing.T) {
+	routing := map[string]string{
+		"example.com":       "exact",
+		"*.example.com":     "wildcard",
+		"*.sub.example.com": "nested",
+	}
+	assert.Equal(t, "exact", routedProvider(routing, "user@example.com"))
+	assert.Equal(t, "exact", routedProvider(routing, "User@EXAMPLE.com"))
+	assert.Equal(t, "wildcard", routedProvider(routing, "user@mail.example.com"))
+	assert.Equal(t, "nested", routedProvider(routing, "user@a.sub.example.com"))
+	assert.Equal(t, setting.DefaultMailProvider, routedProvider(routing, "user@example.org"))
+	assert.Equal(t, setting.DefaultMailProvider, routedProvider(routing, "user@notexample.com"))
+}
+
+func TestProviderChain(t *testing.T) {
+	defer func(old *setting.Mailer) { setting.MailService = old }(setting.MailService)
+	setting.MailService = &setting.Mailer{
+		Failover: []string{"backup", setting.DefaultMailProvider},
+		Routing:  map[string]string{"example.com": "backup"},
+	}
+	assert.Equal(t, []string{"backup", setting.DefaultMailProvider}, providerChain("user@example.com"))
+	assert.Equal(t, []string{setting.DefaultMailProvider, "backup"}, providerChain("user@example.org"))
+}
+
+func TestSendgridParseEvents(t *testing.T) {
+	body := `[
+		{"email": "a@example.com", "event": "delivered", "sg_message_id": "abc.filter0001"},
+		{"email": "b@example.com", "event": "bounce", "type": "bounce", "reason": "550 unknown user", "sg_message_id": "def.filter0002"},
+		{"email": "c@example.com", "event": "open", "sg_message_id": "ghi.filter0003"},
+		{"email": "d@example.com", "event": "spamreport", "sg_message_id": "jkl.filter0004"}
+	]`
+	p := &sendgridProvider{name: "default", opts: &setting.Mailer{}}
+	events, err := p.ParseEvents(context.Background(), httptest.NewRequest("POST", "/", strings.NewReader(body)))
+	assert.NoError(t, err)
+	assert.Equal(t, []*DeliveryEvent{
+		{ProviderMessageID: "abc", Recipient: "a@example.com", Status: system_model.MailDeliveryDelivered},
+		{ProviderMessageID: "def", Recipient: "b@example.com", Status: system_model.MailDeliveryBounced, Reason: "550 unknown user"},
+		{ProviderMessageID: "jkl", Recipient: "d@example.com", Status: system_model.MailDeliveryComplained},
+	}, events)
+}
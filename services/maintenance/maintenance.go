@@ -0,0 +1,40 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package maintenance
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/queue"
+)
+
+// Enable enables the read-only maintenance mode. If drainTimeout is positive,
+// it waits until all queues are empty, so a backup or migration can be started afterwards.
+func Enable(ctx context.Context, message string, drainTimeout time.Duration) error {
+	if err := system_model.SetMaintenanceMode(ctx, true, message); err != nil {
+		return err
+	}
+	log.Info("Maintenance mode enabled")
+
+	if drainTimeout > 0 {
+		if err := queue.GetManager().FlushAll(ctx, drainTimeout); err != nil {
+			return fmt.Errorf("maintenance mode is enabled, but draining the queues failed: %w", err)
+		}
+		log.Info("Queues drained for maintenance")
+	}
+	return nil
+}
+
+// Disable disables the read-only maintenance mode
+func Disable(ctx context.Context) error {
+	if err := system_model.SetMaintenanceMode(ctx, false, ""); err != nil {
+		return err
+	}
+	log.Info("Maintenance mode disabled")
+	return nil
+}
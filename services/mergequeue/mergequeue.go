@@ -0,0 +1,399 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mergequeue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/sync"
+	issue_service "code.gitea.io/gitea/services/issue"
+	pull_service "code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
+)
+
+// BranchPrefix is the prefix of the branches the merge trains are pushed to, workflows testing them can run on pushes to "gitea-merge-queue/**"
+const BranchPrefix = "gitea-merge-queue/"
+
+// ErrMergeStyleNotAllowed is returned when a pull request is queued with a merge style the repository doesn't allow
+var ErrMergeStyleNotAllowed = errors.New("merge style is not allowed")
+
+// queueItem is a branch whose merge queue has to be handled
+type queueItem struct {
+	RepoID     int64
+	BaseBranch string
+}
+
+// mergeQueue handles the merge queues of branches, the queue of a branch is handled when a pull request is queued
+// and when a status of the commit of its merge train is created
+var mergeQueue *queue.WorkerPoolQueue[queueItem]
+
+// branchWorkingPool makes sure a merge queue is only handled once at a time
+var branchWorkingPool = sync.NewExclusivePool()
+
+// Init runs the task queue that handles the merge queues
+func Init() error {
+	mergeQueue = queue.CreateUniqueQueue("pr_merge_queue", handler)
+	if mergeQueue == nil {
+		return fmt.Errorf("Unable to create pr_merge_queue Queue")
+	}
+	go graceful.GetManager().RunWithShutdownFns(mergeQueue.Run)
+	return nil
+}
+
+func handler(items ...queueItem) []queueItem {
+	for _, item := range items {
+		handleBranch(item)
+	}
+	return nil
+}
+
+func addToQueue(repoID int64, baseBranch string) {
+	log.Trace("Adding the merge queue of repo[%d] branch %s to the merge queue handling queue", repoID, baseBranch)
+	if err := mergeQueue.Push(queueItem{RepoID: repoID, BaseBranch: baseBranch}); err != nil && err != queue.ErrAlreadyInQueue {
+		log.Error("Error adding the merge queue of repo[%d] branch %s to the merge queue handling queue: %v", repoID, baseBranch, err)
+	}
+}
+
+// Add adds a pull request at the end of the merge queue of its base branch, it is merged once it passed the status checks
+// together with the pull requests queued before it
+func Add(ctx context.Context, doer *user_model.User, pr *issues_model.PullRequest, style repo_model.MergeStyle, message string) error {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return err
+	}
+	prUnit, err := pr.BaseRepo.GetUnit(ctx, unit.TypePullRequests)
+	if err != nil {
+		return err
+	}
+	if !prUnit.PullRequestsConfig().IsMergeStyleAllowed(style) {
+		return ErrMergeStyleNotAllowed
+	}
+
+	perm, err := access_model.GetUserRepoPermission(ctx, pr.BaseRepo, doer)
+	if err != nil {
+		return err
+	}
+	if err := pull_service.CheckPullMergable(ctx, doer, &perm, pr, pull_service.MergeCheckTypeGeneral, false); err != nil {
+		return err
+	}
+
+	if err := pull_model.AddToMergeQueue(ctx, &pull_model.MergeQueueEntry{
+		RepoID:     pr.BaseRepoID,
+		BaseBranch: pr.BaseBranch,
+		PullID:     pr.ID,
+		DoerID:     doer.ID,
+		MergeStyle: style,
+		Message:    message,
+	}); err != nil {
+		return err
+	}
+	addToQueue(pr.BaseRepoID, pr.BaseBranch)
+	return nil
+}
+
+// Remove removes a pull request from the merge queue of its base branch, the merge train testing it is discarded
+func Remove(ctx context.Context, pr *issues_model.PullRequest) error {
+	exists, entry, err := pull_model.GetMergeQueueEntryByPullID(ctx, pr.ID)
+	if err != nil || !exists {
+		return err
+	}
+	if err := pull_model.DeleteMergeQueueEntries(ctx, entry.ID); err != nil {
+		return err
+	}
+	if entry.TrainID != 0 {
+		// the pull requests queued after it are tested again without it
+		addToQueue(entry.RepoID, entry.BaseBranch)
+	}
+	return nil
+}
+
+// HandleCommitStatus handles the merge queue of the merge train of a commit once a status of the commit is created
+func HandleCommitStatus(ctx context.Context, repo *repo_model.Repository, sha string) error {
+	train, err := pull_model.GetMergeTrainByCommitID(ctx, repo.ID, sha)
+	if err != nil || train == nil {
+		return err
+	}
+	addToQueue(train.RepoID, train.BaseBranch)
+	return nil
+}
+
+func handleBranch(item queueItem) {
+	key := fmt.Sprintf("%d:%s", item.RepoID, item.BaseBranch)
+	branchWorkingPool.CheckIn(key)
+	defer branchWorkingPool.CheckOut(key)
+
+	ctx, _, finished := process.GetManager().AddContext(graceful.GetManager().HammerContext(),
+		fmt.Sprintf("Handle the merge queue of repo[%d] branch %s", item.RepoID, item.BaseBranch))
+	defer finished()
+
+	repo, err := repo_model.GetRepositoryByID(ctx, item.RepoID)
+	if err != nil {
+		log.Error("GetRepositoryByID[%d]: %v", item.RepoID, err)
+		return
+	}
+	gitRepo, err := git.OpenRepository(ctx, repo.RepoPath())
+	if err != nil {
+		log.Error("OpenRepository %-v: %v", repo, err)
+		return
+	}
+	defer gitRepo.Close()
+
+	if err := handleMergeQueue(ctx, repo, gitRepo, item.BaseBranch); err != nil {
+		log.Error("Unable to handle the merge queue of %-v branch %s: %v", repo, item.BaseBranch, err)
+	}
+}
+
+// queuedPull is a pull request of a merge queue
+type queuedPull struct {
+	entry *pull_model.MergeQueueEntry
+	pr    *issues_model.PullRequest
+}
+
+// loadMergeQueue returns the pull requests of a merge queue in their order,
+// the pull requests which cannot be merged by the merge queue anymore are removed from it
+func loadMergeQueue(ctx context.Context, repo *repo_model.Repository, baseBranch string) ([]*queuedPull, error) {
+	entries, err := pull_model.GetMergeQueue(ctx, repo.ID, baseBranch)
+	if err != nil {
+		return nil, err
+	}
+	pulls := make([]*queuedPull, 0, len(entries))
+	removed := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		pr, err := issues_model.GetPullRequestByID(ctx, entry.PullID)
+		if err != nil && !issues_model.IsErrPullRequestNotExist(err) {
+			return nil, err
+		}
+		if err == nil {
+			err = pr.LoadIssue(ctx)
+		}
+		if err != nil && !issues_model.IsErrIssueNotExist(err) {
+			return nil, err
+		}
+		if err != nil || pr.HasMerged || pr.Issue.IsClosed || pr.BaseBranch != baseBranch {
+			removed = append(removed, entry.ID)
+			continue
+		}
+		pr.BaseRepo = repo
+		pr.Issue.Repo = repo
+		pulls = append(pulls, &queuedPull{entry: entry, pr: pr})
+	}
+	return pulls, pull_model.DeleteMergeQueueEntries(ctx, removed...)
+}
+
+// handleMergeQueue moves the merge queue of a branch forward: the pull requests of the merge train are merged
+// once its commit passed the status checks and the next ones are tested by a new train. If the commit fails,
+// the train is split in half until the failing pull request is found, which is removed from the queue.
+func handleMergeQueue(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, baseBranch string) error {
+	trainSize := 0
+	for {
+		pulls, err := loadMergeQueue(ctx, repo, baseBranch)
+		if err != nil {
+			return err
+		}
+		train, err := pull_model.GetMergeTrain(ctx, repo.ID, baseBranch)
+		if err != nil {
+			return err
+		}
+
+		if train != nil {
+			trainPulls := make([]*queuedPull, 0, len(pulls))
+			for _, pull := range pulls {
+				if pull.entry.TrainID == train.ID {
+					trainPulls = append(trainPulls, pull)
+				}
+			}
+
+			state, err := getMergeTrainState(ctx, repo, gitRepo, train, trainPulls)
+			if err != nil {
+				return err
+			}
+			switch {
+			case state == "":
+				// the train is not valid anymore, its pull requests are tested again by a new one
+				if err := discardMergeTrain(ctx, repo, gitRepo, train); err != nil {
+					return err
+				}
+				continue
+			case state.IsPending():
+				return nil
+			case state.IsSuccess():
+				if err := mergeMergeTrain(ctx, repo, gitRepo, train, trainPulls); err != nil {
+					return err
+				}
+				continue
+			default:
+				if err := discardMergeTrain(ctx, repo, gitRepo, train); err != nil {
+					return err
+				}
+				if len(trainPulls) == 1 {
+					removeFromMergeQueue(ctx, trainPulls[0], fmt.Sprintf("This pull request was removed from the merge queue because the status checks of its merge train `%s` failed.", train.Branch))
+					continue
+				}
+				// split the train to find the pull request which makes it fail
+				trainSize = (len(trainPulls) + 1) / 2
+				continue
+			}
+		}
+
+		if len(pulls) == 0 {
+			return nil
+		}
+		if trainSize == 0 {
+			prUnit, err := repo.GetUnit(ctx, unit.TypePullRequests)
+			if err != nil {
+				return err
+			}
+			trainSize = prUnit.PullRequestsConfig().GetMergeTrainBatchSize()
+		}
+		if len(pulls) > trainSize {
+			pulls = pulls[:trainSize]
+		}
+		if err := createMergeTrain(ctx, repo, baseBranch, pulls); err != nil {
+			return err
+		}
+		trainSize = 0
+	}
+}
+
+// getMergeTrainState returns the state of the required status checks of the commit of a merge train,
+// it is empty if the train has to be discarded because its base branch or its pull requests changed
+func getMergeTrainState(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, train *pull_model.MergeTrain, trainPulls []*queuedPull) (structs.CommitStatusState, error) {
+	if len(trainPulls) != train.Size {
+		return "", nil
+	}
+	for _, pull := range trainPulls {
+		headCommitID, err := gitRepo.GetRefCommitID(pull.pr.GetGitRefName())
+		if err != nil {
+			return "", err
+		}
+		if headCommitID != pull.entry.HeadCommitID {
+			return "", nil
+		}
+	}
+	baseCommitID, err := gitRepo.GetBranchCommitID(train.BaseBranch)
+	if err != nil {
+		return "", err
+	}
+	if baseCommitID != train.BaseCommitID {
+		return "", nil
+	}
+
+	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, repo.ID, train.BaseBranch)
+	if err != nil {
+		return "", err
+	}
+	if pb == nil || !pb.EnableStatusCheck {
+		return structs.CommitStatusSuccess, nil
+	}
+	statuses, _, err := git_model.GetLatestCommitStatus(ctx, repo.ID, train.CommitID, db.ListOptions{ListAll: true})
+	if err != nil {
+		return "", err
+	}
+	if len(statuses) == 0 {
+		return structs.CommitStatusPending, nil
+	}
+	return pull_service.MergeRequiredContextsCommitStatus(statuses, pb.StatusCheckContexts), nil
+}
+
+// createMergeTrain merges the pull requests into the base branch and pushes the result to the branch of a new merge train,
+// the pull requests which conflict are removed from the merge queue
+func createMergeTrain(ctx context.Context, repo *repo_model.Repository, baseBranch string, pulls []*queuedPull) error {
+	prs := make([]*issues_model.PullRequest, 0, len(pulls))
+	for _, pull := range pulls {
+		prs = append(prs, pull.pr)
+	}
+	branch := fmt.Sprintf("%s%s/pr-%d", BranchPrefix, baseBranch, prs[len(prs)-1].Index)
+	commit, err := pull_service.CreateMergeTrainCommit(ctx, user_model.NewActionsUser(), repo, baseBranch, branch, prs)
+	if err != nil {
+		return err
+	}
+
+	entries := make([]*pull_model.MergeQueueEntry, 0, len(pulls))
+	for _, pull := range pulls {
+		if headCommitID, ok := commit.HeadCommitIDs[pull.pr.ID]; ok {
+			pull.entry.HeadCommitID = headCommitID
+			entries = append(entries, pull.entry)
+			continue
+		}
+		removeFromMergeQueue(ctx, pull, fmt.Sprintf("This pull request was removed from the merge queue because it conflicts with `%s` or the pull requests queued before it.", baseBranch))
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	return pull_model.CreateMergeTrain(ctx, &pull_model.MergeTrain{
+		RepoID:       repo.ID,
+		BaseBranch:   baseBranch,
+		BaseCommitID: commit.BaseCommitID,
+		CommitID:     commit.CommitID,
+		Branch:       branch,
+	}, entries)
+}
+
+// mergeMergeTrain merges the pull requests of a merge train which passed the status checks
+func mergeMergeTrain(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, train *pull_model.MergeTrain, pulls []*queuedPull) error {
+	for _, pull := range pulls {
+		if err := mergeQueuedPull(ctx, gitRepo, pull); err != nil {
+			// the pull requests after it were tested together with it, they are tested again by a new train
+			log.Info("%-v of the merge train %s cannot be merged: %v", pull.pr, train.Branch, err)
+			removeFromMergeQueue(ctx, pull, fmt.Sprintf("This pull request was removed from the merge queue because it cannot be merged anymore: %v", err))
+			return discardMergeTrain(ctx, repo, gitRepo, train)
+		}
+		if err := pull_model.DeleteMergeQueueEntries(ctx, pull.entry.ID); err != nil {
+			return err
+		}
+	}
+	return discardMergeTrain(ctx, repo, gitRepo, train)
+}
+
+// mergeQueuedPull merges a pull request of a merge train with the head commit which was tested
+func mergeQueuedPull(ctx context.Context, gitRepo *git.Repository, pull *queuedPull) error {
+	if err := pull.entry.LoadDoer(ctx); err != nil {
+		return err
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, pull.pr.BaseRepo, pull.entry.Doer)
+	if err != nil {
+		return err
+	}
+	if err := pull_service.CheckPullMergable(ctx, pull.entry.Doer, &perm, pull.pr, pull_service.MergeCheckTypeGeneral, false); err != nil {
+		return err
+	}
+	return pull_service.Merge(ctx, pull.pr, pull.entry.Doer, gitRepo, pull.entry.MergeStyle, pull.entry.HeadCommitID, pull.entry.Message, true)
+}
+
+// discardMergeTrain deletes a merge train and its branch, the pull requests which are still queued wait for the next train
+func discardMergeTrain(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, train *pull_model.MergeTrain) error {
+	if err := pull_model.DeleteMergeTrain(ctx, train.ID); err != nil {
+		return err
+	}
+	if err := repo_service.DeleteBranch(ctx, user_model.NewActionsUser(), repo, gitRepo, train.Branch); err != nil && !git.IsErrBranchNotExist(err) {
+		log.Error("Unable to delete the branch %s of the merge train of %-v: %v", train.Branch, repo, err)
+	}
+	return nil
+}
+
+// removeFromMergeQueue removes a pull request from its merge queue and explains why in a comment
+func removeFromMergeQueue(ctx context.Context, pull *queuedPull, reason string) {
+	if err := pull_model.DeleteMergeQueueEntries(ctx, pull.entry.ID); err != nil {
+		log.Error("Unable to remove %-v from the merge queue: %v", pull.pr, err)
+		return
+	}
+	if _, err := issue_service.CreateIssueComment(ctx, user_model.NewActionsUser(), pull.pr.BaseRepo, pull.pr.Issue, reason, nil); err != nil {
+		log.Error("Unable to comment on %-v: %v", pull.pr, err)
+	}
+}
@@ -335,7 +335,7 @@ func (g *GiteaLocalUploader) CreateReleases(releases ...*base.Release) error {
 				if rc == nil {
 					return nil
 				}
-				_, err = storage.Attachments.Save(attach.RelativePath(), rc, int64(*asset.Size))
+				_, err = storage.AttachmentsOfRegion(g.repo.StorageRegion()).Save(attach.RelativePath(), rc, int64(*asset.Size))
 				rc.Close()
 				return err
 			}()
@@ -66,6 +66,10 @@ func Update(ctx context.Context, pullLimit, pushLimit int) error {
 			log.Error("Unknown bean: %v", bean)
 			return nil
 		}
+		// deleted repositories are not synced until they are restored
+		if repo.IsPendingDeletion() {
+			return nil
+		}
 
 		// Check we've not been cancelled
 		select {
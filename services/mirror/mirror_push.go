@@ -148,7 +148,7 @@ func runPushSync(ctx context.Context, m *repo_model.PushMirror) error {
 
 			endpoint := lfs.DetermineEndpoint(remoteURL.String(), "")
 			lfsClient := lfs.NewClient(endpoint, nil)
-			if err := pushAllLFSObjects(ctx, gitRepo, lfsClient); err != nil {
+			if err := pushAllLFSObjects(ctx, m.Repo, gitRepo, lfsClient); err != nil {
 				return util.SanitizeErrorCredentialURLs(err)
 			}
 		}
@@ -190,8 +190,8 @@ func runPushSync(ctx context.Context, m *repo_model.PushMirror) error {
 	return nil
 }
 
-func pushAllLFSObjects(ctx context.Context, gitRepo *git.Repository, lfsClient lfs.Client) error {
-	contentStore := lfs.NewContentStore()
+func pushAllLFSObjects(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, lfsClient lfs.Client) error {
+	contentStore := lfs.NewContentStoreOfRegion(repo.StorageRegion())
 
 	pointerChan := make(chan lfs.PointerBlob)
 	errChan := make(chan error, 1)
@@ -0,0 +1,79 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mirror
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"hash"
+	"net/http"
+	"strings"
+)
+
+// MaxSyncHookPayloadSize is the maximum size of the payload of a webhook which triggers a sync, payloads of large
+// pushes are big but only their signature is checked
+const MaxSyncHookPayloadSize = 25 * 1024 * 1024
+
+// syncHookSignatureHeaders are the headers with the hex encoded HMAC-SHA256 of the payload sent by the forges
+var syncHookSignatureHeaders = []string{"X-Gitea-Signature", "X-Forgejo-Signature", "X-Gogs-Signature"}
+
+func verifyHMAC(newHash func() hash.Hash, secret string, body []byte, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(newHash, []byte(secret))
+	_, _ = mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// VerifySyncHookRequest checks that the webhook of an upstream forge was sent with the secret of the mirror.
+// The HMAC signatures of GitHub, Bitbucket, Gitea, Forgejo and Gogs as well as the token of GitLab are accepted.
+func VerifySyncHookRequest(header http.Header, body []byte, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	for _, name := range []string{"X-Hub-Signature-256", "X-Hub-Signature"} {
+		algorithm, signature, ok := strings.Cut(header.Get(name), "=")
+		if !ok {
+			continue
+		}
+		switch algorithm {
+		case "sha256":
+			return verifyHMAC(sha256.New, secret, body, signature)
+		case "sha1":
+			return verifyHMAC(sha1.New, secret, body, signature)
+		}
+	}
+	for _, name := range syncHookSignatureHeaders {
+		if signature := header.Get(name); signature != "" {
+			return verifyHMAC(sha256.New, secret, body, signature)
+		}
+	}
+	if token := header.Get("X-Gitlab-Token"); token != "" {
+		return subtle.ConstantTimeCompare([]byte(token), []byte(secret)) == 1
+	}
+	return false
+}
+
+// IsSyncHookRefEvent returns true if the webhook reports a change of the branches or tags of the upstream repository.
+// Other events, e.g. the ping sent when the webhook is created, don't trigger a sync. Requests without a known event
+// header are treated as generic notifications of a change.
+func IsSyncHookRefEvent(header http.Header) bool {
+	for _, name := range []string{"X-GitHub-Event", "X-Gitea-Event", "X-Forgejo-Event", "X-Gogs-Event"} {
+		if event := header.Get(name); event != "" {
+			return event == "push" || event == "create" || event == "delete"
+		}
+	}
+	if event := header.Get("X-Gitlab-Event"); event != "" {
+		return event == "Push Hook" || event == "Tag Push Hook"
+	}
+	if event := header.Get("X-Event-Key"); event != "" {
+		return event == "repo:push" || event == "repo:refs_changed"
+	}
+	return true
+}
@@ -0,0 +1,62 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package mirror
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifySyncHookRequest(t *testing.T) {
+	body := []byte(`{"ref":"refs/heads/main"}`)
+	// HMACs of the body with the key "secret"
+	const validSHA256 = "d8f89f0618acd61fe621aa4e64078c0e2bca15d0b578b7f3eb734f55883c5320"
+	const validSHA1 = "b4a16e3f7e972ac84f7ec491f5bd8e412e95cd69"
+
+	header := func(kv ...string) http.Header {
+		h := http.Header{}
+		for i := 0; i < len(kv); i += 2 {
+			h.Set(kv[i], kv[i+1])
+		}
+		return h
+	}
+
+	assert.True(t, VerifySyncHookRequest(header("X-Hub-Signature-256", "sha256="+validSHA256), body, "secret"))
+	assert.True(t, VerifySyncHookRequest(header("X-Hub-Signature", "sha1="+validSHA1), body, "secret"))
+	assert.True(t, VerifySyncHookRequest(header("X-Hub-Signature", "sha256="+validSHA256), body, "secret"))
+	assert.True(t, VerifySyncHookRequest(header("X-Gitea-Signature", validSHA256), body, "secret"))
+	assert.True(t, VerifySyncHookRequest(header("X-Gogs-Signature", validSHA256), body, "secret"))
+	assert.True(t, VerifySyncHookRequest(header("X-Gitlab-Token", "secret"), body, "secret"))
+
+	assert.False(t, VerifySyncHookRequest(header("X-Hub-Signature-256", "sha256="+validSHA1), body, "secret"))
+	assert.False(t, VerifySyncHookRequest(header("X-Hub-Signature-256", "sha256="+validSHA256), body, "other"))
+	assert.False(t, VerifySyncHookRequest(header("X-Hub-Signature-256", "sha256="+validSHA256), []byte("{}"), "secret"))
+	assert.False(t, VerifySyncHookRequest(header("X-Gitea-Signature", "not hex"), body, "secret"))
+	assert.False(t, VerifySyncHookRequest(header("X-Gitlab-Token", "other"), body, "secret"))
+	assert.False(t, VerifySyncHookRequest(header(), body, "secret"))
+	assert.False(t, VerifySyncHookRequest(header("X-Gitlab-Token", ""), body, ""))
+}
+
+func TestIsSyncHookRefEvent(t *testing.T) {
+	for _, c := range []struct {
+		name, event string
+		expected    bool
+	}{
+		{"X-GitHub-Event", "push", true},
+		{"X-GitHub-Event", "ping", false},
+		{"X-GitHub-Event", "issues", false},
+		{"X-Gitea-Event", "create", true},
+		{"X-Gitlab-Event", "Tag Push Hook", true},
+		{"X-Gitlab-Event", "Issue Hook", false},
+		{"X-Event-Key", "repo:refs_changed", true},
+		{"X-Event-Key", "diagnostics:ping", false},
+		{"X-Custom-Event", "anything", true},
+	} {
+		h := http.Header{}
+		h.Set(c.name, c.event)
+		assert.Equal(t, c.expected, IsSyncHookRefEvent(h), "%s: %s", c.name, c.event)
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package moderation
+
+import (
+	"context"
+	"fmt"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	moderation_model "code.gitea.io/gitea/models/moderation"
+	"code.gitea.io/gitea/models/organization"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrBlocked is returned when a user interacts with a user or organization which blocked them
+type ErrBlocked struct {
+	BlockerID int64
+	UserID    int64
+}
+
+// IsErrBlocked checks if an error is a ErrBlocked.
+func IsErrBlocked(err error) bool {
+	_, ok := err.(ErrBlocked)
+	return ok
+}
+
+func (err ErrBlocked) Error() string {
+	return fmt.Sprintf("user is blocked [blocker_id: %d, user_id: %d]", err.BlockerID, err.UserID)
+}
+
+func (err ErrBlocked) Unwrap() error {
+	return util.ErrPermissionDenied
+}
+
+// ErrInteractionLimited is returned when an account is too new to interact with a repository
+type ErrInteractionLimited struct {
+	RepoID            int64
+	UserID            int64
+	MinAccountAgeDays int
+}
+
+// IsErrInteractionLimited checks if an error is a ErrInteractionLimited.
+func IsErrInteractionLimited(err error) bool {
+	_, ok := err.(ErrInteractionLimited)
+	return ok
+}
+
+func (err ErrInteractionLimited) Error() string {
+	return fmt.Sprintf("interactions are limited to accounts older than %d days [repo_id: %d, user_id: %d]", err.MinAccountAgeDays, err.RepoID, err.UserID)
+}
+
+func (err ErrInteractionLimited) Unwrap() error {
+	return util.ErrPermissionDenied
+}
+
+// CheckInteraction checks if the user may open issues and pull requests or comment in the repository.
+// Site administrators and system users are never restricted, users with write access are exempt from interaction limits.
+func CheckInteraction(ctx context.Context, doer *user_model.User, repo *repo_model.Repository) error {
+	if doer == nil || doer.ID <= 0 || doer.IsAdmin {
+		return nil
+	}
+
+	blocked, err := user_model.IsBlocked(ctx, repo.OwnerID, doer.ID)
+	if err != nil {
+		return err
+	} else if blocked {
+		return ErrBlocked{BlockerID: repo.OwnerID, UserID: doer.ID}
+	}
+
+	limit, err := moderation_model.GetInteractionLimit(ctx, repo.ID)
+	if err != nil {
+		return err
+	}
+	if limit == nil || limit.IsExpired() || !limit.IsAccountTooNew(doer.CreatedUnix) {
+		return nil
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, repo, doer)
+	if err != nil {
+		return err
+	}
+	if perm.IsAdmin() || perm.CanWrite(unit.TypeCode) || perm.CanWriteIssuesOrPulls(false) || perm.CanWriteIssuesOrPulls(true) {
+		return nil
+	}
+	return ErrInteractionLimited{RepoID: repo.ID, UserID: doer.ID, MinAccountAgeDays: limit.MinAccountAgeDays}
+}
+
+// BlockUser blocks a user from interacting with a user or organization.
+// Users stop following each other, members of an organization cannot be blocked by it.
+func BlockUser(ctx context.Context, blocker, blockee *user_model.User, note string) error {
+	if blockee.IsOrganization() {
+		return util.NewInvalidArgumentErrorf("organizations cannot be blocked")
+	}
+	if blocker.IsOrganization() {
+		isMember, err := organization.IsOrganizationMember(ctx, blocker.ID, blockee.ID)
+		if err != nil {
+			return err
+		} else if isMember {
+			return util.NewInvalidArgumentErrorf("members of the organization cannot be blocked")
+		}
+	} else {
+		if err := user_model.UnfollowUser(blocker.ID, blockee.ID); err != nil {
+			return err
+		}
+		if err := user_model.UnfollowUser(blockee.ID, blocker.ID); err != nil {
+			return err
+		}
+	}
+	return user_model.BlockUser(ctx, blocker.ID, blockee.ID, note)
+}
+
+// ReportAbuse files an abuse report for site administrators after checking the content exists and is visible to the reporter
+func ReportAbuse(ctx context.Context, reporter *user_model.User, contentType moderation_model.ReportContentType, contentID int64,
+	category moderation_model.ReportCategory, remarks string,
+) (*moderation_model.AbuseReport, error) {
+	if !contentType.IsValid() {
+		return nil, util.NewInvalidArgumentErrorf("invalid content type %q", contentType)
+	}
+	if !category.IsValid() {
+		return nil, util.NewInvalidArgumentErrorf("invalid category %q", category)
+	}
+
+	if err := checkReportable(ctx, reporter, contentType, contentID); err != nil {
+		return nil, err
+	}
+
+	report := &moderation_model.AbuseReport{
+		ReporterID:  reporter.ID,
+		ContentType: contentType,
+		ContentID:   contentID,
+		Category:    category,
+		Remarks:     remarks,
+	}
+	if err := moderation_model.CreateReport(ctx, report); err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// checkReportable returns a not-exist error if the content does not exist or the reporter cannot see it,
+// so reports cannot be used to probe for private content
+func checkReportable(ctx context.Context, reporter *user_model.User, contentType moderation_model.ReportContentType, contentID int64) error {
+	switch contentType {
+	case moderation_model.ReportContentTypeUser:
+		u, err := user_model.GetUserByID(ctx, contentID)
+		if err != nil {
+			return err
+		}
+		if !user_model.IsUserVisibleToViewer(ctx, u, reporter) {
+			return user_model.ErrUserNotExist{UID: contentID}
+		}
+	case moderation_model.ReportContentTypeRepository:
+		repo, err := repo_model.GetRepositoryByID(ctx, contentID)
+		if err != nil {
+			return err
+		}
+		perm, err := access_model.GetUserRepoPermission(ctx, repo, reporter)
+		if err != nil {
+			return err
+		}
+		if !perm.HasAccess() {
+			return repo_model.ErrRepoNotExist{ID: contentID}
+		}
+	case moderation_model.ReportContentTypeIssue:
+		issue, err := issues_model.GetIssueByID(ctx, contentID)
+		if err != nil {
+			return err
+		}
+		canRead, err := canReadIssue(ctx, reporter, issue)
+		if err != nil {
+			return err
+		}
+		if !canRead {
+			return issues_model.ErrIssueNotExist{ID: contentID}
+		}
+	case moderation_model.ReportContentTypeComment:
+		comment, err := issues_model.GetCommentByID(ctx, contentID)
+		if err != nil {
+			return err
+		}
+		if err := comment.LoadIssue(ctx); err != nil {
+			return err
+		}
+		canRead, err := canReadIssue(ctx, reporter, comment.Issue)
+		if err != nil {
+			return err
+		}
+		if !canRead {
+			return issues_model.ErrCommentNotExist{ID: contentID}
+		}
+	}
+	return nil
+}
+
+func canReadIssue(ctx context.Context, doer *user_model.User, issue *issues_model.Issue) (bool, error) {
+	if err := issue.LoadRepo(ctx); err != nil {
+		return false, err
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, issue.Repo, doer)
+	if err != nil {
+		return false, err
+	}
+	return perm.CanReadIssuesOrPulls(issue.IsPull), nil
+}
@@ -39,6 +39,10 @@ func DeleteOrganization(org *organization.Organization) error {
 		return models.ErrUserOwnPackages{UID: org.ID}
 	}
 
+	if err := packages_model.DeletePackageVersionDeletionsByOwner(ctx, org.ID); err != nil {
+		return fmt.Errorf("DeletePackageVersionDeletionsByOwner: %w", err)
+	}
+
 	if err := organization.DeleteOrganization(ctx, org); err != nil {
 		return fmt.Errorf("DeleteOrganization: %w", err)
 	}
@@ -51,15 +55,23 @@ func DeleteOrganization(org *organization.Organization) error {
 	// Note: There are something just cannot be roll back,
 	//	so just keep error logs of those operations.
 	path := user_model.UserPath(org.Name)
+	defer user_model.RemoveCachedStorageRegion(org.ID)
 
 	if err := util.RemoveAll(path); err != nil {
 		return fmt.Errorf("Failed to RemoveAll %s: %w", path, err)
 	}
 
+	// avatars are shared by everyone using the same image
 	if len(org.Avatar) > 0 {
 		avatarPath := org.CustomAvatarRelativePath()
-		if err := storage.Avatars.Delete(avatarPath); err != nil {
-			return fmt.Errorf("Failed to remove %s: %w", avatarPath, err)
+		used, err := user_model.ExistsWithAvatarAtStoragePath(db.DefaultContext, avatarPath)
+		if err != nil {
+			return err
+		}
+		if !used {
+			if err := storage.Avatars.Delete(avatarPath); err != nil {
+				return fmt.Errorf("Failed to remove %s: %w", avatarPath, err)
+			}
 		}
 	}
 
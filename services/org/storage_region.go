@@ -0,0 +1,319 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	"code.gitea.io/gitea/models/organization"
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
+	system_model "code.gitea.io/gitea/models/system"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/util"
+	attachment_service "code.gitea.io/gitea/services/attachment"
+
+	"xorm.io/builder"
+)
+
+// ErrStorageRegionMoveInProgress represents an error when the data of an organization is being moved already
+type ErrStorageRegionMoveInProgress struct {
+	OrgName string
+}
+
+// IsErrStorageRegionMoveInProgress checks if an error is a ErrStorageRegionMoveInProgress.
+func IsErrStorageRegionMoveInProgress(err error) bool {
+	_, ok := err.(ErrStorageRegionMoveInProgress)
+	return ok
+}
+
+func (err ErrStorageRegionMoveInProgress) Error() string {
+	return fmt.Sprintf("the data of the organization is being moved to another storage region already [org: %s]", err.OrgName)
+}
+
+// movingOwners holds the ids of the owners whose data is being moved, with their target regions
+var movingOwners = struct {
+	sync.Mutex
+	regions map[int64]string
+}{regions: map[int64]string{}}
+
+// IsMovingStorageRegion returns if the data of an owner is being moved to another storage region
+func IsMovingStorageRegion(ownerID int64) bool {
+	_, ok := GetMovingStorageRegion(ownerID)
+	return ok
+}
+
+// GetMovingStorageRegion returns the storage region the data of an owner is being moved to
+func GetMovingStorageRegion(ownerID int64) (string, bool) {
+	movingOwners.Lock()
+	defer movingOwners.Unlock()
+	region, ok := movingOwners.regions[ownerID]
+	return region, ok
+}
+
+// MoveToStorageRegion pins an organization to a storage region and starts moving its repositories,
+// LFS objects, attachments and packages there in the background. An empty region moves the data
+// back to the default storages. Pushes to the repositories of the organization are rejected meanwhile.
+func MoveToStorageRegion(org *organization.Organization, region string) error {
+	if region != "" {
+		if _, ok := setting.StorageRegions[region]; !ok {
+			return user_model.ErrStorageRegionNotExist{Region: region}
+		}
+	}
+
+	movingOwners.Lock()
+	defer movingOwners.Unlock()
+	if _, ok := movingOwners.regions[org.ID]; ok {
+		return ErrStorageRegionMoveInProgress{OrgName: org.Name}
+	}
+	if user_model.StorageRegionOfID(org.ID) == region {
+		return nil
+	}
+	movingOwners.regions[org.ID] = region
+
+	go func() {
+		defer func() {
+			movingOwners.Lock()
+			delete(movingOwners.regions, org.ID)
+			movingOwners.Unlock()
+		}()
+
+		ctx, _, finished := process.GetManager().AddContext(graceful.GetManager().ShutdownContext(), fmt.Sprintf("MoveToStorageRegion: %s to %q", org.Name, region))
+		defer finished()
+
+		if err := moveToStorageRegion(ctx, org, region); err != nil {
+			log.Error("Unable to move %s to the storage region %q: %v", org.Name, region, err)
+			_ = system_model.CreateNotice(ctx, system_model.NoticeTask, fmt.Sprintf("Failed to move organization %s to the storage region %q: %v", org.Name, region, err))
+			return
+		}
+		log.Info("Moved %s to the storage region %q", org.Name, region)
+	}()
+	return nil
+}
+
+// storageRegionMove moves the data of an owner from one storage region to another
+type storageRegionMove struct {
+	ownerID  int64
+	from, to string
+	repoIDs  []int64
+}
+
+func moveToStorageRegion(ctx context.Context, org *organization.Organization, region string) error {
+	m := &storageRegionMove{
+		ownerID: org.ID,
+		from:    user_model.StorageRegionOfID(org.ID),
+		to:      region,
+	}
+	if err := db.Iterate(ctx, builder.Eq{"owner_id": org.ID}, func(ctx context.Context, repo *repo_model.Repository) error {
+		m.repoIDs = append(m.repoIDs, repo.ID)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if err := m.copyObjects(ctx); err != nil {
+		return err
+	}
+
+	srcDir := filepath.Join(setting.GetRepoRootPathOfRegion(m.from), org.LowerName)
+	dstDir := filepath.Join(setting.GetRepoRootPathOfRegion(m.to), org.LowerName)
+	if err := moveDir(srcDir, dstDir); err != nil {
+		return fmt.Errorf("move repositories: %w", err)
+	}
+	if err := user_model.SetStorageRegion(ctx, org.AsUser(), m.to); err != nil {
+		if err2 := moveDir(dstDir, srcDir); err2 != nil {
+			log.Critical("Unable to move the repositories of %s back to %s: %v", org.Name, srcDir, err2)
+		}
+		return err
+	}
+
+	// objects may have been added to the old region while copying
+	if err := m.copyObjects(ctx); err != nil {
+		return err
+	}
+	if err := attachment_service.MoveAttachmentBlobsToRegion(ctx, m.repoIDs, m.from, m.to); err != nil {
+		return fmt.Errorf("move attachment blobs: %w", err)
+	}
+	m.removeOldObjects(ctx)
+	return nil
+}
+
+// copyObjects copies the LFS objects, attachments and package blobs of the owner to the new region
+func (m *storageRegionMove) copyObjects(ctx context.Context) error {
+	srcLFS, dstLFS := storage.LFSOfRegion(m.from), storage.LFSOfRegion(m.to)
+	for _, repoID := range m.repoIDs {
+		if err := git_model.IterateLFSMetaObjectsForRepo(ctx, repoID, func(ctx context.Context, meta *git_model.LFSMetaObject, _ int64) error {
+			return copyObject(dstLFS, srcLFS, meta.RelativePath())
+		}, &git_model.IterateLFSMetaObjectsForRepoOptions{}); err != nil {
+			return fmt.Errorf("copy LFS objects: %w", err)
+		}
+	}
+
+	srcAttachments, dstAttachments := storage.AttachmentsOfRegion(m.from), storage.AttachmentsOfRegion(m.to)
+	if len(m.repoIDs) > 0 {
+		if err := db.Iterate(ctx, builder.In("repo_id", m.repoIDs), func(ctx context.Context, attach *repo_model.Attachment) error {
+			if err := copyObject(dstAttachments, srcAttachments, attach.RelativePath()); err != nil {
+				return err
+			}
+			if attach.HasThumbnail {
+				return copyObject(dstAttachments, srcAttachments, attach.ThumbnailRelativePath())
+			}
+			return nil
+		}); err != nil {
+			return fmt.Errorf("copy attachments: %w", err)
+		}
+	}
+
+	pbs, err := packages_model.GetBlobsByOwnerID(ctx, m.ownerID)
+	if err != nil {
+		return err
+	}
+	srcPackages, dstPackages := storage.PackagesOfRegion(m.from), storage.PackagesOfRegion(m.to)
+	for _, pb := range pbs {
+		if err := copyObject(dstPackages, srcPackages, packages_module.KeyToRelativePath(packages_module.BlobHash256Key(pb.HashSHA256))); err != nil {
+			return fmt.Errorf("copy package blobs: %w", err)
+		}
+	}
+	return nil
+}
+
+// removeOldObjects removes the objects of the owner from the old region, unless other owners use them too
+func (m *storageRegionMove) removeOldObjects(ctx context.Context) {
+	srcLFS := storage.LFSOfRegion(m.from)
+	for _, repoID := range m.repoIDs {
+		if err := git_model.IterateLFSMetaObjectsForRepo(ctx, repoID, func(ctx context.Context, meta *git_model.LFSMetaObject, _ int64) error {
+			shared, err := git_model.IsLFSObjectReferencedByOtherOwners(ctx, meta.Oid, m.ownerID)
+			if err != nil || shared {
+				return err
+			}
+			removeObject(srcLFS, meta.RelativePath())
+			return nil
+		}, &git_model.IterateLFSMetaObjectsForRepoOptions{}); err != nil {
+			log.Error("Unable to remove the LFS objects of %d from the storage region %q: %v", m.ownerID, m.from, err)
+		}
+	}
+
+	srcAttachments := storage.AttachmentsOfRegion(m.from)
+	if len(m.repoIDs) > 0 {
+		// the blobs of the old region are left to the garbage collection, other owners may share them
+		if err := db.Iterate(ctx, builder.In("repo_id", m.repoIDs).And(builder.Eq{"blob_id": 0}), func(ctx context.Context, attach *repo_model.Attachment) error {
+			for _, p := range attach.ObjectPaths() {
+				removeObject(srcAttachments, p)
+			}
+			return nil
+		}); err != nil {
+			log.Error("Unable to remove the attachments of %d from the storage region %q: %v", m.ownerID, m.from, err)
+		}
+	}
+
+	pbs, err := packages_model.GetBlobsByOwnerID(ctx, m.ownerID)
+	if err != nil {
+		log.Error("Unable to remove the package blobs of %d from the storage region %q: %v", m.ownerID, m.from, err)
+		return
+	}
+	srcPackages := storage.PackagesOfRegion(m.from)
+	for _, pb := range pbs {
+		shared, err := packages_model.IsBlobReferencedByOtherOwners(ctx, pb.ID, m.ownerID)
+		if err != nil {
+			log.Error("Unable to remove the package blobs of %d from the storage region %q: %v", m.ownerID, m.from, err)
+			return
+		}
+		if !shared {
+			removeObject(srcPackages, packages_module.KeyToRelativePath(packages_module.BlobHash256Key(pb.HashSHA256)))
+		}
+	}
+}
+
+// copyObject copies an object between storages unless it exists in the destination already
+func copyObject(dst, src storage.ObjectStorage, p string) error {
+	if _, err := dst.Stat(p); err == nil {
+		return nil
+	}
+	if _, err := storage.Copy(dst, p, src, p); err != nil {
+		if errors.Is(err, os.ErrNotExist) || errors.Is(err, util.ErrNotExist) {
+			log.Warn("Object %s to move to another storage region does not exist", p)
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+func removeObject(s storage.ObjectStorage, p string) {
+	if err := s.Delete(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+		log.Error("Unable to remove %s from the old storage region: %v", p, err)
+	}
+}
+
+// moveDir moves a directory, copying it if the source and destination are on different file systems
+func moveDir(srcDir, dstDir string) error {
+	if _, err := os.Stat(srcDir); err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if _, err := os.Stat(dstDir); err == nil {
+		return fmt.Errorf("%s exists already", dstDir)
+	}
+	if err := os.MkdirAll(filepath.Dir(dstDir), os.ModePerm); err != nil {
+		return err
+	}
+	if err := os.Rename(srcDir, dstDir); err == nil {
+		return nil
+	}
+
+	if err := copyDir(srcDir, dstDir); err != nil {
+		_ = util.RemoveAll(dstDir)
+		return err
+	}
+	return util.RemoveAll(srcDir)
+}
+
+func copyDir(srcDir, dstDir string) error {
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dstDir, rel)
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		switch {
+		case d.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		default:
+			if err := util.CopyFile(path, target); err != nil {
+				return err
+			}
+			return os.Chmod(target, info.Mode().Perm())
+		}
+	})
+}
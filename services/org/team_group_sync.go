@@ -0,0 +1,280 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	auth_model "code.gitea.io/gitea/models/auth"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ExternalGroupSourceAPIName is the name of the source of the groups whose members are pushed through the API
+const ExternalGroupSourceAPIName = "api"
+
+// teamGroupChange is a change of the membership of a team which the group sync decided
+type teamGroupChange struct {
+	UserID int64
+	Action organization.TeamGroupSyncAction
+	// Groups are the mapped groups which contain the user, by source
+	Groups map[int64][]string
+	// MissingSources are the sources which don't have the user in a mapped group although others have
+	MissingSources []int64
+}
+
+// resolveTeamGroupChanges decides which users are added to or removed from a team. memberGroups has the mapped groups
+// which contain a user by source and user, sources are all sources with groups mapped to the team.
+func resolveTeamGroupChanges(policy organization.TeamGroupSyncPolicy, removal bool, sources []int64, memberGroups map[int64]map[int64][]string, currentMembers container.Set[int64]) []*teamGroupChange {
+	groupsByUser := make(map[int64]map[int64][]string)
+	for sourceID, users := range memberGroups {
+		for userID, groups := range users {
+			if groupsByUser[userID] == nil {
+				groupsByUser[userID] = make(map[int64][]string)
+			}
+			groupsByUser[userID][sourceID] = groups
+		}
+	}
+
+	isDesired := func(userID int64) bool {
+		n := len(groupsByUser[userID])
+		if policy == organization.TeamGroupSyncPolicyAll {
+			return n > 0 && n == len(sources)
+		}
+		return n > 0
+	}
+	missingSources := func(userID int64) []int64 {
+		if len(groupsByUser[userID]) == 0 {
+			return nil
+		}
+		var missing []int64
+		for _, sourceID := range sources {
+			if _, ok := groupsByUser[userID][sourceID]; !ok {
+				missing = append(missing, sourceID)
+			}
+		}
+		return missing
+	}
+
+	userIDs := make([]int64, 0, len(groupsByUser)+len(currentMembers))
+	for userID := range groupsByUser {
+		userIDs = append(userIDs, userID)
+	}
+	for userID := range currentMembers {
+		if _, ok := groupsByUser[userID]; !ok {
+			userIDs = append(userIDs, userID)
+		}
+	}
+	sort.Slice(userIDs, func(i, j int) bool { return userIDs[i] < userIDs[j] })
+
+	changes := make([]*teamGroupChange, 0, 5)
+	for _, userID := range userIDs {
+		desired, isMember := isDesired(userID), currentMembers.Contains(userID)
+		switch {
+		case desired && !isMember:
+			changes = append(changes, &teamGroupChange{
+				UserID:         userID,
+				Action:         organization.TeamGroupSyncActionAdd,
+				Groups:         groupsByUser[userID],
+				MissingSources: missingSources(userID),
+			})
+		case !desired && isMember && removal:
+			changes = append(changes, &teamGroupChange{
+				UserID:         userID,
+				Action:         organization.TeamGroupSyncActionRemove,
+				Groups:         groupsByUser[userID],
+				MissingSources: missingSources(userID),
+			})
+		}
+	}
+	return changes
+}
+
+// formatReason describes why the change was made, the sources are given by their names
+func (c *teamGroupChange) formatReason(sourceNames map[int64]string) string {
+	groups := make([]string, 0, len(c.Groups))
+	for sourceID, names := range c.Groups {
+		for _, name := range names {
+			groups = append(groups, sourceNames[sourceID]+"/"+name)
+		}
+	}
+	sort.Strings(groups)
+	missing := make([]string, 0, len(c.MissingSources))
+	for _, sourceID := range c.MissingSources {
+		missing = append(missing, sourceNames[sourceID])
+	}
+
+	if c.Action == organization.TeamGroupSyncActionAdd {
+		reason := "member of " + strings.Join(groups, ", ")
+		if len(missing) > 0 {
+			reason += "; not reported by " + strings.Join(missing, ", ")
+		}
+		return reason
+	}
+	if len(groups) == 0 {
+		return "not a member of any mapped group"
+	}
+	return "not a member of a mapped group of " + strings.Join(missing, ", ") + "; member of " + strings.Join(groups, ", ")
+}
+
+// GetExternalGroupSourceNames returns the names of the sources, the authentication sources which don't exist anymore are named by their ids
+func GetExternalGroupSourceNames(sourceIDs []int64) (map[int64]string, error) {
+	names := make(map[int64]string, len(sourceIDs))
+	for _, sourceID := range sourceIDs {
+		if sourceID == organization.ExternalGroupSourceAPI {
+			names[sourceID] = ExternalGroupSourceAPIName
+			continue
+		}
+		source, err := auth_model.GetSourceByID(sourceID)
+		if err != nil {
+			if !auth_model.IsErrSourceNotExist(err) {
+				return nil, err
+			}
+			names[sourceID] = fmt.Sprintf("source-%d", sourceID)
+			continue
+		}
+		names[sourceID] = source.Name
+	}
+	return names, nil
+}
+
+// GetExternalGroupSourceID returns the id of the source with the given name, an empty name is the source of the groups
+// whose members are pushed through the API
+func GetExternalGroupSourceID(name string) (int64, error) {
+	if name == "" || name == ExternalGroupSourceAPIName {
+		return organization.ExternalGroupSourceAPI, nil
+	}
+	sources, err := auth_model.Sources()
+	if err != nil {
+		return 0, err
+	}
+	for _, source := range sources {
+		if source.Name == name {
+			return source.ID, nil
+		}
+	}
+	return 0, util.NewInvalidArgumentErrorf("unknown source %q", name)
+}
+
+// SyncTeamGroups reconciles the members of a team with the external groups mapped to it.
+// The changes are recorded and returned, a team without mapped groups is left unchanged.
+func SyncTeamGroups(ctx context.Context, team *organization.Team) ([]*organization.TeamGroupSyncLog, error) {
+	mappings, err := organization.FindTeamGroupMappings(ctx, team.ID)
+	if err != nil {
+		return nil, fmt.Errorf("FindTeamGroupMappings: %w", err)
+	}
+	logs := make([]*organization.TeamGroupSyncLog, 0, 5)
+	if len(mappings) == 0 {
+		return logs, nil
+	}
+
+	groupsBySource := make(map[int64][]string)
+	sources := make([]int64, 0, 2)
+	for _, m := range mappings {
+		if _, ok := groupsBySource[m.SourceID]; !ok {
+			sources = append(sources, m.SourceID)
+		}
+		groupsBySource[m.SourceID] = append(groupsBySource[m.SourceID], m.GroupName)
+	}
+	memberGroups := make(map[int64]map[int64][]string, len(sources))
+	for _, sourceID := range sources {
+		members, err := organization.FindExternalGroupMembers(ctx, sourceID, groupsBySource[sourceID])
+		if err != nil {
+			return nil, fmt.Errorf("FindExternalGroupMembers: %w", err)
+		}
+		users := make(map[int64][]string)
+		for _, member := range members {
+			users[member.UserID] = append(users[member.UserID], member.GroupName)
+		}
+		memberGroups[sourceID] = users
+	}
+
+	teamUsers, err := organization.GetTeamUsersByTeamID(ctx, team.ID)
+	if err != nil {
+		return nil, fmt.Errorf("GetTeamUsersByTeamID: %w", err)
+	}
+	currentMembers := make(container.Set[int64], len(teamUsers))
+	for _, tu := range teamUsers {
+		currentMembers.Add(tu.UID)
+	}
+
+	// the owners team must not lose its last owner, so its members are never removed
+	removal := team.GroupSyncRemoval && !team.IsOwnerTeam()
+	changes := resolveTeamGroupChanges(team.GroupSyncPolicy, removal, sources, memberGroups, currentMembers)
+	if len(changes) == 0 {
+		return logs, nil
+	}
+
+	sourceNames, err := GetExternalGroupSourceNames(sources)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range changes {
+		if change.Action == organization.TeamGroupSyncActionAdd {
+			u, err := user_model.GetUserByID(ctx, change.UserID)
+			if err != nil {
+				if user_model.IsErrUserNotExist(err) {
+					continue
+				}
+				return logs, err
+			}
+			if u.IsOrganization() {
+				continue
+			}
+			if err := models.AddTeamMember(team, change.UserID); err != nil {
+				log.Error("group sync: Could not add user %d to team %d: %v", change.UserID, team.ID, err)
+				continue
+			}
+		} else if err := models.RemoveTeamMember(team, change.UserID); err != nil {
+			log.Error("group sync: Could not remove user %d from team %d: %v", change.UserID, team.ID, err)
+			continue
+		}
+
+		syncLog := &organization.TeamGroupSyncLog{
+			OrgID:  team.OrgID,
+			TeamID: team.ID,
+			UserID: change.UserID,
+			Action: change.Action,
+			Reason: change.formatReason(sourceNames),
+		}
+		if err := db.Insert(ctx, syncLog); err != nil {
+			return logs, err
+		}
+		logs = append(logs, syncLog)
+	}
+	return logs, nil
+}
+
+// SyncAllTeamGroups reconciles the members of all teams which have external groups mapped to them
+func SyncAllTeamGroups(ctx context.Context) error {
+	teamIDs, err := organization.FindTeamIDsWithGroupMappings(ctx)
+	if err != nil {
+		return err
+	}
+	for _, teamID := range teamIDs {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before syncing the groups of team %d", teamID)
+		default:
+		}
+
+		team, err := organization.GetTeamByID(ctx, teamID)
+		if err != nil {
+			log.Error("GetTeamByID[%d]: %v", teamID, err)
+			continue
+		}
+		if _, err := SyncTeamGroups(ctx, team); err != nil {
+			log.Error("SyncTeamGroups[%d]: %v", teamID, err)
+		}
+	}
+	return nil
+}
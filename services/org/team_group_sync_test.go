@@ -0,0 +1,68 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/modules/container"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveTeamGroupChanges(t *testing.T) {
+	const ldap, api = int64(1), organization.ExternalGroupSourceAPI
+	sources := []int64{ldap, api}
+	memberGroups := map[int64]map[int64][]string{
+		ldap: {
+			2: {"developers"},
+			4: {"developers", "admins"},
+			5: {"developers"},
+		},
+		api: {
+			2: {"engineering"},
+			3: {"engineering"},
+		},
+	}
+	// user 5 is a member already, user 6 isn't in any mapped group
+	currentMembers := container.SetOf[int64](5, 6)
+	sourceNames := map[int64]string{ldap: "corp-ldap", api: ExternalGroupSourceAPIName}
+
+	summarize := func(changes []*teamGroupChange) []string {
+		result := make([]string, 0, len(changes))
+		for _, c := range changes {
+			result = append(result, string(c.Action)+" "+c.formatReason(sourceNames))
+		}
+		return result
+	}
+
+	t.Run("Any", func(t *testing.T) {
+		changes := resolveTeamGroupChanges(organization.TeamGroupSyncPolicyAny, false, sources, memberGroups, currentMembers)
+		assert.Equal(t, []string{
+			"add member of api/engineering, corp-ldap/developers",
+			"add member of api/engineering; not reported by corp-ldap",
+			"add member of corp-ldap/admins, corp-ldap/developers; not reported by api",
+		}, summarize(changes))
+	})
+
+	t.Run("AnyWithRemoval", func(t *testing.T) {
+		changes := resolveTeamGroupChanges(organization.TeamGroupSyncPolicyAny, true, sources, memberGroups, currentMembers)
+		assert.Len(t, changes, 4)
+		assert.EqualValues(t, 6, changes[3].UserID)
+		assert.Equal(t, "remove not a member of any mapped group", summarize(changes)[3])
+	})
+
+	t.Run("All", func(t *testing.T) {
+		changes := resolveTeamGroupChanges(organization.TeamGroupSyncPolicyAll, true, sources, memberGroups, currentMembers)
+		assert.Equal(t, []string{
+			"add member of api/engineering, corp-ldap/developers",
+			"remove not a member of a mapped group of api; member of corp-ldap/developers",
+			"remove not a member of any mapped group",
+		}, summarize(changes))
+		assert.EqualValues(t, 2, changes[0].UserID)
+		assert.EqualValues(t, 5, changes[1].UserID)
+		assert.EqualValues(t, 6, changes[2].UserID)
+	})
+}
@@ -0,0 +1,255 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package org
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/graceful"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	notify_base "code.gitea.io/gitea/modules/notification/base"
+	"code.gitea.io/gitea/modules/queue"
+	"code.gitea.io/gitea/modules/references"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	"code.gitea.io/gitea/services/convert"
+	webhook_service "code.gitea.io/gitea/services/webhook"
+)
+
+// discordMaxContentLength is the maximum length of the content of a discord message
+const discordMaxContentLength = 2000
+
+type teamMentionRequest struct {
+	TeamID    int64
+	IssueID   int64
+	CommentID int64
+	DoerID    int64
+}
+
+var teamMentionQueue *queue.WorkerPoolQueue[teamMentionRequest]
+
+// InitTeamMentions starts the queue posting mentions of teams to their mention webhooks
+func InitTeamMentions() error {
+	teamMentionQueue = queue.CreateSimpleQueue("team_mention", teamMentionHandler)
+	if teamMentionQueue == nil {
+		return fmt.Errorf("Unable to create team_mention Queue")
+	}
+	go graceful.GetManager().RunWithShutdownFns(teamMentionQueue.Run)
+
+	notification.RegisterNotifier(&teamMentionNotifier{})
+	return nil
+}
+
+func teamMentionHandler(items ...teamMentionRequest) []teamMentionRequest {
+	ctx := graceful.GetManager().ShutdownContext()
+	for _, req := range items {
+		if err := deliverTeamMention(ctx, req); err != nil {
+			log.Error("Unable to post the mention of team %d in issue %d to its webhook: %v", req.TeamID, req.IssueID, err)
+		}
+	}
+	return nil
+}
+
+type teamMentionNotifier struct {
+	notify_base.NullNotifier
+}
+
+var _ notify_base.Notifier = &teamMentionNotifier{}
+
+// NotifyNewIssue queues the mentions of teams in the description of a new issue
+func (*teamMentionNotifier) NotifyNewIssue(ctx context.Context, issue *issues_model.Issue, mentions []*user_model.User) {
+	queueTeamMentions(ctx, issue.Poster, issue, 0, issue.Content)
+}
+
+// NotifyNewPullRequest queues the mentions of teams in the description of a new pull request
+func (*teamMentionNotifier) NotifyNewPullRequest(ctx context.Context, pr *issues_model.PullRequest, mentions []*user_model.User) {
+	if err := pr.LoadIssue(ctx); err != nil {
+		log.Error("LoadIssue: %v", err)
+		return
+	}
+	if err := pr.Issue.LoadPoster(ctx); err != nil {
+		log.Error("LoadPoster: %v", err)
+		return
+	}
+	queueTeamMentions(ctx, pr.Issue.Poster, pr.Issue, 0, pr.Issue.Content)
+}
+
+// NotifyCreateIssueComment queues the mentions of teams in a new comment
+func (*teamMentionNotifier) NotifyCreateIssueComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository,
+	issue *issues_model.Issue, comment *issues_model.Comment, mentions []*user_model.User,
+) {
+	if comment.Type != issues_model.CommentTypeComment {
+		return
+	}
+	queueTeamMentions(ctx, doer, issue, comment.ID, comment.Content)
+}
+
+// queueTeamMentions queues the mentions of teams which post their mentions to a webhook,
+// the members of the other teams are notified like mentioned users
+func queueTeamMentions(ctx context.Context, doer *user_model.User, issue *issues_model.Issue, commentID int64, content string) {
+	if doer == nil {
+		return
+	}
+	if err := issue.LoadRepo(ctx); err != nil {
+		log.Error("LoadRepo: %v", err)
+		return
+	}
+
+	prefix := strings.ToLower(issue.Repo.OwnerName) + "/"
+	var names []string
+	for _, mention := range references.FindAllMentionsMarkdown(content) {
+		if name := strings.ToLower(mention); strings.HasPrefix(name, prefix) {
+			names = append(names, strings.TrimPrefix(name, prefix))
+		}
+	}
+	if len(names) == 0 {
+		return
+	}
+
+	teams, err := issues_model.FindMentionedTeams(ctx, issue, names)
+	if err != nil {
+		log.Error("FindMentionedTeams: %v", err)
+		return
+	}
+	for _, team := range teams {
+		if team.MentionMode != organization.TeamMentionModeWebhook {
+			continue
+		}
+		allowed, err := organization.AllowTeamMention(ctx, team, issue.ID, doer.ID)
+		if err != nil {
+			log.Error("AllowTeamMention: %v", err)
+			continue
+		} else if !allowed {
+			log.Debug("Mention of team %d in issue %d exceeds the mention rate limit of the team", team.ID, issue.ID)
+			continue
+		}
+		req := teamMentionRequest{TeamID: team.ID, IssueID: issue.ID, CommentID: commentID, DoerID: doer.ID}
+		if err := teamMentionQueue.Push(req); err != nil {
+			log.Error("Unable to add the mention of team %d to the queue: %v", team.ID, err)
+		}
+	}
+}
+
+// buildTeamMentionPayload returns the payload of the mention of a team in an issue, or in a comment if commentID is set
+func buildTeamMentionPayload(ctx context.Context, team *organization.Team, issue *issues_model.Issue, commentID int64, doer *user_model.User) (*api.TeamMentionPayload, error) {
+	if err := issue.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	org, err := organization.GetOrgByID(ctx, team.OrgID)
+	if err != nil {
+		return nil, err
+	}
+	apiTeam, err := convert.ToTeam(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+
+	payload := &api.TeamMentionPayload{
+		Organization: convert.ToOrganization(ctx, org),
+		Team:         apiTeam,
+		Repository:   issue.Repo.FullName(),
+		Index:        issue.Index,
+		Title:        issue.Title,
+		IsPull:       issue.IsPull,
+		HTMLURL:      issue.HTMLURL(),
+		Sender:       doer.Name,
+		Body:         issue.Content,
+	}
+	if commentID > 0 {
+		comment, err := issues_model.GetCommentByID(ctx, commentID)
+		if err != nil {
+			return nil, err
+		}
+		payload.HTMLURL = comment.HTMLURL()
+		payload.Body = comment.Content
+	}
+	return payload, nil
+}
+
+// FormatTeamMention renders the mention as a chat message, link formats a link in the markup of the chat
+func FormatTeamMention(p *api.TeamMentionPayload, link func(text, link string) string) string {
+	return fmt.Sprintf("%s mentioned team %s/%s in %s\n\n%s", p.Sender, p.Organization.UserName, p.Team.Name,
+		link(fmt.Sprintf("%s#%d %s", p.Repository, p.Index, p.Title), p.HTMLURL), p.Body)
+}
+
+func markdownLink(text, link string) string {
+	return fmt.Sprintf("[%s](%s)", text, link)
+}
+
+func slackLink(text, link string) string {
+	return fmt.Sprintf("<%s|%s>", link, strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text))
+}
+
+func buildTeamMentionRequestBody(typ string, p *api.TeamMentionPayload) ([]byte, error) {
+	switch typ {
+	case webhook_module.SLACK:
+		return json.Marshal(map[string]string{"text": FormatTeamMention(p, slackLink)})
+	case webhook_module.DISCORD:
+		return json.Marshal(map[string]string{"content": base.EllipsisString(FormatTeamMention(p, markdownLink), discordMaxContentLength)})
+	case webhook_module.MSTEAMS:
+		return json.Marshal(map[string]string{"text": FormatTeamMention(p, markdownLink)})
+	default:
+		return json.Marshal(p)
+	}
+}
+
+func deliverTeamMention(ctx context.Context, req teamMentionRequest) error {
+	team, err := organization.GetTeamByID(ctx, req.TeamID)
+	if err != nil {
+		return err
+	}
+	if team.MentionMode != organization.TeamMentionModeWebhook {
+		// the mode was changed since the mention was queued
+		return nil
+	}
+	issue, err := issues_model.GetIssueByID(ctx, req.IssueID)
+	if err != nil {
+		return err
+	}
+	doer, err := user_model.GetPossibleUserByID(ctx, req.DoerID)
+	if err != nil {
+		return err
+	}
+	payload, err := buildTeamMentionPayload(ctx, team, issue, req.CommentID, doer)
+	if err != nil {
+		return err
+	}
+	body, err := buildTeamMentionRequestBody(team.MentionWebhookType, payload)
+	if err != nil {
+		return err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, team.MentionWebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "Gitea "+setting.AppVer)
+	httpReq.Header.Set("X-Gitea-Event", "team_mention")
+
+	resp, err := webhook_service.DoRequest(httpReq)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -19,9 +19,21 @@ import (
 type packageClaims struct {
 	jwt.RegisteredClaims
 	UserID int64
+	// ActionsTaskID is the task whose token the authorization token was created for
+	ActionsTaskID int64 `json:",omitempty"`
 }
 
 func CreateAuthorizationToken(u *user_model.User) (string, error) {
+	return createAuthorizationToken(u.ID, 0)
+}
+
+// CreateActionsAuthorizationToken creates an authorization token for the token of an Actions task,
+// it is only valid as long as the task is running.
+func CreateActionsAuthorizationToken(taskID int64) (string, error) {
+	return createAuthorizationToken(user_model.ActionsUserID, taskID)
+}
+
+func createAuthorizationToken(userID, actionsTaskID int64) (string, error) {
 	now := time.Now()
 
 	claims := packageClaims{
@@ -29,7 +41,8 @@ func CreateAuthorizationToken(u *user_model.User) (string, error) {
 			ExpiresAt: jwt.NewNumericDate(now.Add(24 * time.Hour)),
 			NotBefore: jwt.NewNumericDate(now),
 		},
-		UserID: u.ID,
+		UserID:        userID,
+		ActionsTaskID: actionsTaskID,
 	}
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
@@ -41,16 +54,17 @@ func CreateAuthorizationToken(u *user_model.User) (string, error) {
 	return tokenString, nil
 }
 
-func ParseAuthorizationToken(req *http.Request) (int64, error) {
+// ParseAuthorizationToken returns the user and the Actions task the authorization token of the request was created for
+func ParseAuthorizationToken(req *http.Request) (userID, actionsTaskID int64, err error) {
 	h := req.Header.Get("Authorization")
 	if h == "" {
-		return 0, nil
+		return 0, 0, nil
 	}
 
 	parts := strings.SplitN(h, " ", 2)
 	if len(parts) != 2 {
 		log.Error("split token failed: %s", h)
-		return 0, fmt.Errorf("split token failed")
+		return 0, 0, fmt.Errorf("split token failed")
 	}
 
 	token, err := jwt.ParseWithClaims(parts[1], &packageClaims{}, func(t *jwt.Token) (interface{}, error) {
@@ -60,13 +74,13 @@ func ParseAuthorizationToken(req *http.Request) (int64, error) {
 		return []byte(setting.SecretKey), nil
 	})
 	if err != nil {
-		return 0, err
+		return 0, 0, err
 	}
 
 	c, ok := token.Claims.(*packageClaims)
 	if !token.Valid || !ok {
-		return 0, fmt.Errorf("invalid token claim")
+		return 0, 0, fmt.Errorf("invalid token claim")
 	}
 
-	return c.UserID, nil
+	return c.UserID, c.ActionsTaskID, nil
 }
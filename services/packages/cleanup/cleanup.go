@@ -5,7 +5,9 @@ package container
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"time"
 
 	"code.gitea.io/gitea/models/db"
@@ -13,6 +15,7 @@ import (
 	user_model "code.gitea.io/gitea/models/user"
 	"code.gitea.io/gitea/modules/log"
 	packages_module "code.gitea.io/gitea/modules/packages"
+	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/util"
 	packages_service "code.gitea.io/gitea/services/packages"
 	cargo_service "code.gitea.io/gitea/services/packages/cargo"
@@ -139,6 +142,10 @@ func Cleanup(taskCtx context.Context, olderThan time.Duration) error {
 		}
 	}
 
+	if err := packages_service.PurgeExpiredPackageVersions(ctx); err != nil {
+		return err
+	}
+
 	pbs, err := packages_model.FindExpiredUnreferencedBlobs(ctx, olderThan)
 	if err != nil {
 		return err
@@ -154,10 +161,16 @@ func Cleanup(taskCtx context.Context, olderThan time.Duration) error {
 		return err
 	}
 
-	contentStore := packages_module.NewContentStore()
+	// the content of a blob may have been stored in the storage regions of several owners
+	contentStores := []*packages_module.ContentStore{packages_module.NewContentStore()}
+	for _, region := range setting.StorageRegionNames() {
+		contentStores = append(contentStores, packages_module.NewContentStoreOfRegion(region))
+	}
 	for _, pb := range pbs {
-		if err := contentStore.Delete(packages_module.BlobHash256Key(pb.HashSHA256)); err != nil {
-			log.Error("Error deleting package blob [%v]: %v", pb.ID, err)
+		for _, contentStore := range contentStores {
+			if err := contentStore.Delete(packages_module.BlobHash256Key(pb.HashSHA256)); err != nil && !errors.Is(err, os.ErrNotExist) {
+				log.Error("Error deleting package blob [%v]: %v", pb.ID, err)
+			}
 		}
 	}
 
@@ -0,0 +1,68 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package packages
+
+import (
+	"context"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// keepDeletedPackageVersion keeps a snapshot of a package version which is deleted, so it can be restored
+// until the retention of deleted package versions expires
+func keepDeletedPackageVersion(ctx context.Context, doer *user_model.User, pd *packages_model.PackageDescriptor) error {
+	if setting.Packages.DeletionRetention <= 0 {
+		return nil
+	}
+	var deleterID int64
+	if doer != nil {
+		deleterID = doer.ID
+	}
+	return packages_model.InsertPackageVersionDeletion(ctx, &packages_model.PackageVersionDeletion{
+		OwnerID:     pd.Package.OwnerID,
+		PackageType: pd.Package.Type,
+		PackageName: pd.Package.Name,
+		Version:     pd.Version.Version,
+		Snapshot:    packages_model.NewPackageVersionSnapshot(pd),
+		DeleterID:   deleterID,
+		PurgeUnix:   timeutil.TimeStampNow().AddDuration(setting.Packages.DeletionRetention),
+	})
+}
+
+// RestorePackageVersion restores a deleted package version
+func RestorePackageVersion(ctx context.Context, doer *user_model.User, d *packages_model.PackageVersionDeletion) (*packages_model.PackageDescriptor, error) {
+	pv, err := packages_model.RestorePackageVersionDeletion(ctx, d)
+	if err != nil {
+		return nil, err
+	}
+	pd, err := packages_model.GetPackageDescriptor(ctx, pv)
+	if err != nil {
+		return nil, err
+	}
+
+	notification.NotifyPackageCreate(ctx, doer, pd)
+
+	return pd, nil
+}
+
+// PurgeExpiredPackageVersions drops the deleted package versions whose retention expired,
+// their blobs are removed with the other unreferenced blobs
+func PurgeExpiredPackageVersions(ctx context.Context) error {
+	deletions, err := packages_model.FindExpiredPackageVersionDeletions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range deletions {
+		log.Trace("Purging deleted package version: %d", d.ID)
+		if err := packages_model.DeletePackageVersionDeletion(ctx, d.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package goproxy
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/log"
+	packages_module "code.gitea.io/gitea/modules/packages"
+	goproxy_module "code.gitea.io/gitea/modules/packages/goproxy"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+	packages_service "code.gitea.io/gitea/services/packages"
+)
+
+// maxLookupResponseSize limits how much of the checksum database response is read
+const maxLookupResponseSize = 64 * 1024
+
+var checksumDatabaseClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: proxy.Proxy(),
+	},
+}
+
+// ModuleVerification is the result of the verification of a stored module version
+type ModuleVerification struct {
+	Package    *packages_model.Package
+	Version    *packages_model.PackageVersion
+	Status     goproxy_module.ChecksumStatus
+	LocalHash  string
+	PublicHash string
+}
+
+// VerifyModules verifies the stored zips of the Go modules of the owner, or of all owners if ownerID is 0,
+// against the checksum database. The result is stored as property of the module versions.
+func VerifyModules(ctx context.Context, ownerID int64) ([]*ModuleVerification, error) {
+	if setting.Packages.GoChecksumDatabase == "" {
+		return nil, util.NewInvalidArgumentErrorf("the checksum database is disabled")
+	}
+
+	pvs, _, err := packages_model.SearchVersions(ctx, &packages_model.PackageSearchOptions{
+		OwnerID:    ownerID,
+		Type:       packages_model.TypeGo,
+		IsInternal: util.OptionalBoolFalse,
+		Sort:       packages_model.SortCreatedAsc,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*ModuleVerification, 0, len(pvs))
+	for _, pv := range pvs {
+		result, err := verifyModule(ctx, pv)
+		if err != nil {
+			return nil, fmt.Errorf("verify module version %d: %w", pv.ID, err)
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+func verifyModule(ctx context.Context, pv *packages_model.PackageVersion) (*ModuleVerification, error) {
+	p, err := packages_model.GetPackageByID(ctx, pv.PackageID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ModuleVerification{
+		Package: p,
+		Version: pv,
+		Status:  goproxy_module.ChecksumStatusPrivate,
+	}
+	if !goproxy_module.IsPrivateModule(p.Name, setting.Packages.GoPrivate) {
+		if result.LocalHash, err = hashStoredZip(ctx, pv); err != nil {
+			return nil, err
+		}
+		publicHash, found, err := lookupZipHash(ctx, p.Name, pv.Version)
+		if err != nil {
+			return nil, err
+		}
+		result.PublicHash = publicHash
+		switch {
+		case !found:
+			result.Status = goproxy_module.ChecksumStatusUnknown
+		case publicHash == result.LocalHash:
+			result.Status = goproxy_module.ChecksumStatusVerified
+		default:
+			result.Status = goproxy_module.ChecksumStatusMismatch
+			log.Warn("Go module %s@%s differs from the checksum database: %s != %s", p.Name, pv.Version, result.LocalHash, publicHash)
+		}
+	}
+
+	if err := packages_model.DeletePropertyByName(ctx, packages_model.PropertyTypeVersion, pv.ID, goproxy_module.PropertyChecksumStatus); err != nil {
+		return nil, err
+	}
+	if _, err := packages_model.InsertProperty(ctx, packages_model.PropertyTypeVersion, pv.ID, goproxy_module.PropertyChecksumStatus, string(result.Status)); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// hashStoredZip returns the hash of the stored zip of the module version
+func hashStoredZip(ctx context.Context, pv *packages_model.PackageVersion) (string, error) {
+	pfs, err := packages_model.GetFilesByVersionID(ctx, pv.ID)
+	if err != nil {
+		return "", err
+	}
+	if len(pfs) != 1 {
+		return "", fmt.Errorf("expected a single file, found %d", len(pfs))
+	}
+	pb, err := packages_model.GetBlobByID(ctx, pfs[0].BlobID)
+	if err != nil {
+		return "", err
+	}
+	contentStore, err := packages_service.NewContentStoreOfFile(ctx, pfs[0])
+	if err != nil {
+		return "", err
+	}
+	s, err := contentStore.Get(packages_module.BlobHash256Key(pb.HashSHA256))
+	if err != nil {
+		return "", err
+	}
+	defer s.Close()
+
+	buf, err := packages_module.CreateHashedBufferFromReader(s)
+	if err != nil {
+		return "", err
+	}
+	defer buf.Close()
+
+	return goproxy_module.HashZip(buf, buf.Size())
+}
+
+// lookupZipHash returns the hash of the module zip known to the checksum database
+func lookupZipHash(ctx context.Context, modulePath, version string) (string, bool, error) {
+	lookupPath, err := goproxy_module.LookupPath(modulePath, version)
+	if err != nil {
+		return "", false, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, setting.Packages.GoChecksumDatabase+lookupPath, nil)
+	if err != nil {
+		return "", false, err
+	}
+	resp, err := checksumDatabaseClient.Do(req)
+	if err != nil {
+		return "", false, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound, http.StatusGone:
+		return "", false, nil
+	default:
+		return "", false, fmt.Errorf("checksum database responded with status %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxLookupResponseSize))
+	if err != nil {
+		return "", false, err
+	}
+	hash, found := goproxy_module.ParseLookupZipHash(modulePath, version, data)
+	return hash, found, nil
+}
@@ -0,0 +1,193 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package npm
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+
+	packages_model "code.gitea.io/gitea/models/packages"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	npm_module "code.gitea.io/gitea/modules/packages/npm"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/hashicorp/go-version"
+)
+
+const (
+	// maxQueryResponseSize limits how much of a response of the advisory database is read
+	maxQueryResponseSize = 16 * 1024 * 1024
+	// maxQueryPages limits the number of pages of vulnerabilities of a package which are fetched
+	maxQueryPages = 10
+)
+
+var advisoryDatabaseClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: proxy.Proxy(),
+	},
+}
+
+// BulkAdvisories returns the advisories affecting the requested versions of the packages hosted by the owner
+// in the format of the bulk advisory endpoint of npm audit. Packages which are not hosted are skipped.
+func BulkAdvisories(ctx context.Context, ownerID int64, request map[string][]string) (map[string][]*npm_module.Advisory, error) {
+	result := make(map[string][]*npm_module.Advisory)
+	for name, versions := range request {
+		if _, err := packages_model.GetPackageByName(ctx, ownerID, packages_model.TypeNpm, name); err != nil {
+			if err == packages_model.ErrPackageNotExist {
+				continue
+			}
+			return nil, err
+		}
+
+		advisories, err := packageAdvisories(ctx, ownerID, name)
+		if err != nil {
+			return nil, err
+		}
+		if advisories = filterAdvisories(advisories, versions); len(advisories) > 0 {
+			result[name] = advisories
+		}
+	}
+	return result, nil
+}
+
+// packageAdvisories returns the advisories published by the owner and found in the advisory database for a package
+func packageAdvisories(ctx context.Context, ownerID int64, name string) ([]*npm_module.Advisory, error) {
+	pas, err := packages_model.GetAdvisoriesByPackageName(ctx, ownerID, packages_model.TypeNpm, name)
+	if err != nil {
+		return nil, err
+	}
+	advisories := make([]*npm_module.Advisory, 0, len(pas))
+	for _, pa := range pas {
+		advisories = append(advisories, &npm_module.Advisory{
+			ID:                 pa.ID,
+			URL:                pa.URL,
+			Title:              pa.Title,
+			Severity:           pa.Severity,
+			VulnerableVersions: pa.VulnerableVersions,
+			CWE:                []string{},
+		})
+	}
+
+	if setting.Packages.NpmAdvisoryDatabase == "" {
+		return advisories, nil
+	}
+	osvAdvisories, err := cachedDatabaseAdvisories(ctx, name)
+	if err != nil {
+		// the advisories published by the owner are still useful if the advisory database is unreachable
+		log.Warn("Unable to look up the advisories of npm package %s: %v", name, err)
+		return advisories, nil
+	}
+	return append(advisories, osvAdvisories...), nil
+}
+
+func cachedDatabaseAdvisories(ctx context.Context, name string) ([]*npm_module.Advisory, error) {
+	data, err := cache.GetString("npm_advisories_"+name, func() (string, error) {
+		advisories, err := queryDatabaseAdvisories(ctx, name)
+		if err != nil {
+			return "", err
+		}
+		data, err := json.Marshal(advisories)
+		return string(data), err
+	})
+	if err != nil {
+		return nil, err
+	}
+	var advisories []*npm_module.Advisory
+	return advisories, json.Unmarshal([]byte(data), &advisories)
+}
+
+// queryDatabaseAdvisories looks up the vulnerabilities of a package in the OSV advisory database
+func queryDatabaseAdvisories(ctx context.Context, name string) ([]*npm_module.Advisory, error) {
+	type osvQuery struct {
+		Package struct {
+			Name      string `json:"name"`
+			Ecosystem string `json:"ecosystem"`
+		} `json:"package"`
+		PageToken string `json:"page_token,omitempty"`
+	}
+	type osvQueryResponse struct {
+		Vulns         []*npm_module.OSVVulnerability `json:"vulns"`
+		NextPageToken string                         `json:"next_page_token"`
+	}
+
+	query := osvQuery{}
+	query.Package.Name = name
+	query.Package.Ecosystem = "npm"
+
+	advisories := make([]*npm_module.Advisory, 0, 5)
+	for page := 0; page < maxQueryPages; page++ {
+		body, err := json.Marshal(query)
+		if err != nil {
+			return nil, err
+		}
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, setting.Packages.NpmAdvisoryDatabase+"/v1/query", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := advisoryDatabaseClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		var queryResponse osvQueryResponse
+		err = func() error {
+			defer resp.Body.Close()
+			if resp.StatusCode != http.StatusOK {
+				return fmt.Errorf("advisory database responded with status %d", resp.StatusCode)
+			}
+			return json.NewDecoder(io.LimitReader(resp.Body, maxQueryResponseSize)).Decode(&queryResponse)
+		}()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, vuln := range queryResponse.Vulns {
+			if advisory := npm_module.AdvisoryFromOSV(name, vuln); advisory != nil {
+				advisories = append(advisories, advisory)
+			}
+		}
+		if queryResponse.NextPageToken == "" {
+			break
+		}
+		query.PageToken = queryResponse.NextPageToken
+	}
+	return advisories, nil
+}
+
+// filterAdvisories returns the advisories affecting any of the versions, or all advisories if no versions are given
+func filterAdvisories(advisories []*npm_module.Advisory, versions []string) []*npm_module.Advisory {
+	if len(versions) == 0 {
+		return advisories
+	}
+
+	parsed := make([]*version.Version, 0, len(versions))
+	for _, v := range versions {
+		if pv, err := version.NewSemver(v); err == nil {
+			parsed = append(parsed, pv)
+		}
+	}
+
+	filtered := make([]*npm_module.Advisory, 0, len(advisories))
+	for _, advisory := range advisories {
+		r, err := npm_module.ParseVersionRange(advisory.VulnerableVersions)
+		if err != nil {
+			// keep advisories npm can evaluate itself
+			filtered = append(filtered, advisory)
+			continue
+		}
+		for _, v := range parsed {
+			if r.Matches(v) {
+				filtered = append(filtered, advisory)
+				break
+			}
+		}
+	}
+	return filtered
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"os"
 	"strings"
 
 	"code.gitea.io/gitea/models/db"
@@ -85,11 +86,11 @@ func createPackageAndAddFile(pvci *PackageCreationInfo, pfci *PackageFileCreatio
 		return nil, nil, err
 	}
 
-	pf, pb, blobCreated, err := addFileToPackageVersion(ctx, pv, &pvci.PackageInfo, pfci)
+	contentStore := NewContentStoreOfOwner(pvci.Owner.ID)
+	pf, pb, blobCreated, err := addFileToPackageVersion(ctx, contentStore, pv, &pvci.PackageInfo, pfci)
 	removeBlob := false
 	defer func() {
 		if blobCreated && removeBlob {
-			contentStore := packages_module.NewContentStore()
 			if err := contentStore.Delete(packages_module.BlobHash256Key(pb.HashSHA256)); err != nil {
 				log.Error("Error deleting package blob from content store: %v", err)
 			}
@@ -188,25 +189,31 @@ func createPackageAndVersion(ctx context.Context, pvci *PackageCreationInfo, all
 
 // AddFileToExistingPackage adds a file to an existing package. If the package does not exist, ErrPackageNotExist is returned
 func AddFileToExistingPackage(pvi *PackageInfo, pfci *PackageFileCreationInfo) (*packages_model.PackageFile, error) {
-	return addFileToPackageWrapper(func(ctx context.Context) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error) {
+	contentStore := NewContentStoreOfOwner(pvi.Owner.ID)
+	return addFileToPackageWrapper(contentStore, func(ctx context.Context) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error) {
 		pv, err := packages_model.GetVersionByNameAndVersion(ctx, pvi.Owner.ID, pvi.PackageType, pvi.Name, pvi.Version)
 		if err != nil {
 			return nil, nil, false, err
 		}
 
-		return addFileToPackageVersion(ctx, pv, pvi, pfci)
+		return addFileToPackageVersion(ctx, contentStore, pv, pvi, pfci)
 	})
 }
 
 // AddFileToPackageVersionInternal adds a file to the package
 // This method skips quota checks and should only be used for system-managed packages.
 func AddFileToPackageVersionInternal(pv *packages_model.PackageVersion, pfci *PackageFileCreationInfo) (*packages_model.PackageFile, error) {
-	return addFileToPackageWrapper(func(ctx context.Context) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error) {
-		return addFileToPackageVersionUnchecked(ctx, pv, pfci)
+	p, err := packages_model.GetPackageByID(db.DefaultContext, pv.PackageID)
+	if err != nil {
+		return nil, err
+	}
+	contentStore := NewContentStoreOfOwner(p.OwnerID)
+	return addFileToPackageWrapper(contentStore, func(ctx context.Context) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error) {
+		return addFileToPackageVersionUnchecked(ctx, contentStore, pv, pfci)
 	})
 }
 
-func addFileToPackageWrapper(fn func(ctx context.Context) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error)) (*packages_model.PackageFile, error) {
+func addFileToPackageWrapper(contentStore *packages_module.ContentStore, fn func(ctx context.Context) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error)) (*packages_model.PackageFile, error) {
 	ctx, committer, err := db.TxContext(db.DefaultContext)
 	if err != nil {
 		return nil, err
@@ -217,7 +224,6 @@ func addFileToPackageWrapper(fn func(ctx context.Context) (*packages_model.Packa
 	removeBlob := false
 	defer func() {
 		if removeBlob {
-			contentStore := packages_module.NewContentStore()
 			if err := contentStore.Delete(packages_module.BlobHash256Key(pb.HashSHA256)); err != nil {
 				log.Error("Error deleting package blob from content store: %v", err)
 			}
@@ -236,6 +242,27 @@ func addFileToPackageWrapper(fn func(ctx context.Context) (*packages_model.Packa
 	return pf, nil
 }
 
+// NewContentStoreOfOwner returns the package content store of the storage region the owner is pinned to
+func NewContentStoreOfOwner(ownerID int64) *packages_module.ContentStore {
+	return packages_module.NewContentStoreOfRegion(user_model.StorageRegionOfID(ownerID))
+}
+
+// NewContentStoreOfFile returns the package content store of the storage region the owner of the package file is pinned to
+func NewContentStoreOfFile(ctx context.Context, pf *packages_model.PackageFile) (*packages_module.ContentStore, error) {
+	if len(setting.StorageRegions) == 0 {
+		return packages_module.NewContentStore(), nil
+	}
+	pv, err := packages_model.GetVersionByID(ctx, pf.VersionID)
+	if err != nil {
+		return nil, err
+	}
+	p, err := packages_model.GetPackageByID(ctx, pv.PackageID)
+	if err != nil {
+		return nil, err
+	}
+	return NewContentStoreOfOwner(p.OwnerID), nil
+}
+
 // NewPackageBlob creates a package blob instance
 func NewPackageBlob(hsr packages_module.HashedSizeReader) *packages_model.PackageBlob {
 	hashMD5, hashSHA1, hashSHA256, hashSHA512 := hsr.Sums()
@@ -249,15 +276,15 @@ func NewPackageBlob(hsr packages_module.HashedSizeReader) *packages_model.Packag
 	}
 }
 
-func addFileToPackageVersion(ctx context.Context, pv *packages_model.PackageVersion, pvi *PackageInfo, pfci *PackageFileCreationInfo) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error) {
+func addFileToPackageVersion(ctx context.Context, contentStore *packages_module.ContentStore, pv *packages_model.PackageVersion, pvi *PackageInfo, pfci *PackageFileCreationInfo) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error) {
 	if err := CheckSizeQuotaExceeded(ctx, pfci.Creator, pvi.Owner, pvi.PackageType, pfci.Data.Size()); err != nil {
 		return nil, nil, false, err
 	}
 
-	return addFileToPackageVersionUnchecked(ctx, pv, pfci)
+	return addFileToPackageVersionUnchecked(ctx, contentStore, pv, pfci)
 }
 
-func addFileToPackageVersionUnchecked(ctx context.Context, pv *packages_model.PackageVersion, pfci *PackageFileCreationInfo) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error) {
+func addFileToPackageVersionUnchecked(ctx context.Context, contentStore *packages_module.ContentStore, pv *packages_model.PackageVersion, pfci *PackageFileCreationInfo) (*packages_model.PackageFile, *packages_model.PackageBlob, bool, error) {
 	log.Trace("Adding package file: %v, %s", pv.ID, pfci.Filename)
 
 	pb, exists, err := packages_model.GetOrInsertBlob(ctx, NewPackageBlob(pfci.Data))
@@ -265,8 +292,16 @@ func addFileToPackageVersionUnchecked(ctx context.Context, pv *packages_model.Pa
 		log.Error("Error inserting package blob: %v", err)
 		return nil, nil, false, err
 	}
+	// blobs are shared between owners, but their content has to be in the storage region of each owner
+	if exists && len(setting.StorageRegions) > 0 {
+		if err := contentStore.Has(packages_module.BlobHash256Key(pb.HashSHA256)); err != nil {
+			if !errors.Is(err, util.ErrNotExist) && !errors.Is(err, os.ErrNotExist) {
+				return nil, nil, false, err
+			}
+			exists = false
+		}
+	}
 	if !exists {
-		contentStore := packages_module.NewContentStore()
 		if err := contentStore.Save(packages_module.BlobHash256Key(pb.HashSHA256), pfci.Data, pfci.Data.Size()); err != nil {
 			log.Error("Error saving package blob in content store: %v", err)
 			return nil, nil, false, err
@@ -477,6 +512,9 @@ func RemovePackageVersion(doer *user_model.User, pv *packages_model.PackageVersi
 
 	log.Trace("Deleting package: %v", pv.ID)
 
+	if err := keepDeletedPackageVersion(ctx, doer, pd); err != nil {
+		return err
+	}
 	if err := DeletePackageVersionAndReferences(ctx, pv); err != nil {
 		return err
 	}
@@ -623,7 +661,12 @@ func GetPackageFileStream(ctx context.Context, pf *packages_model.PackageFile) (
 		return nil, nil, err
 	}
 
-	s, err := packages_module.NewContentStore().Get(packages_module.BlobHash256Key(pb.HashSHA256))
+	contentStore, err := NewContentStoreOfFile(ctx, pf)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	s, err := contentStore.Get(packages_module.BlobHash256Key(pb.HashSHA256))
 	if err == nil {
 		if pf.IsLead {
 			if err := packages_model.IncrementDownloadCounter(ctx, pf.VersionID); err != nil {
@@ -136,6 +136,10 @@ func CheckPullMergable(stdCtx context.Context, doer *user_model.User, perm *acce
 			return err
 		}
 
+		if err := CheckLicensePolicy(ctx, pr); err != nil {
+			return err
+		}
+
 		if noDeps, err := issues_model.IssueNoDependenciesLeft(ctx, pr.Issue); err != nil {
 			return err
 		} else if !noDeps {
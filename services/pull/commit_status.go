@@ -148,6 +148,12 @@ func GetPullRequestCommitStatusState(ctx context.Context, pr *issues_model.PullR
 		return "", errors.Wrap(err, "GetLatestCommitStatus")
 	}
 
+	// flaky-tolerant contexts may pass in a number of attempts instead of the last one
+	commitStatuses, err = git_model.ApplyFlakyCheckRules(ctx, pr.BaseRepo.ID, sha, commitStatuses)
+	if err != nil {
+		return "", errors.Wrap(err, "ApplyFlakyCheckRules")
+	}
+
 	pb, err := git_model.GetFirstMatchProtectedBranchRule(ctx, pr.BaseRepoID, pr.BaseBranch)
 	if err != nil {
 		return "", errors.Wrap(err, "LoadProtectedBranch")
@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/git/pipeline"
 	"code.gitea.io/gitea/modules/lfs"
 	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/storage"
 )
 
 // LFSPush pushes lfs objects referred to in new commits in the head repository from the base repository
@@ -72,7 +73,9 @@ func createLFSMetaObjectsFromCatFileBatch(catFileBatchReader *io.PipeReader, wg
 	defer wg.Done()
 	defer catFileBatchReader.Close()
 
-	contentStore := lfs.NewContentStore()
+	// the head repository may be pinned to another storage region than the base repository
+	headContentStore := lfs.NewContentStoreOfRegion(pr.HeadRepo.StorageRegion())
+	baseContentStore := lfs.NewContentStoreOfRegion(pr.BaseRepo.StorageRegion())
 
 	bufferedReader := bufio.NewReader(catFileBatchReader)
 	buf := make([]byte, 1025)
@@ -110,7 +113,7 @@ func createLFSMetaObjectsFromCatFileBatch(catFileBatchReader *io.PipeReader, wg
 			continue
 		}
 
-		exist, _ := contentStore.Exists(pointer)
+		exist, _ := headContentStore.Exists(pointer)
 		if !exist {
 			continue
 		}
@@ -127,6 +130,14 @@ func createLFSMetaObjectsFromCatFileBatch(catFileBatchReader *io.PipeReader, wg
 		// OK we have a pointer that is associated with the head repo
 		// and is actually a file in the LFS
 		// Therefore it should be associated with the base repo
+		if headContentStore.ObjectStorage != baseContentStore.ObjectStorage {
+			if exist, _ := baseContentStore.Exists(pointer); !exist {
+				if _, err := storage.Copy(baseContentStore, pointer.RelativePath(), headContentStore, pointer.RelativePath()); err != nil {
+					_ = catFileBatchReader.CloseWithError(err)
+					break
+				}
+			}
+		}
 		meta := &git_model.LFSMetaObject{Pointer: pointer}
 		meta.RepositoryID = pr.BaseRepoID
 		if _, err := git_model.NewLFSMetaObject(db.DefaultContext, meta); err != nil {
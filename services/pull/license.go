@@ -0,0 +1,88 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// CheckLicensePolicy checks whether the pull request introduces licenses which are forbidden by the
+// license policy of the organization owning the base repository, in license files or in the licenses
+// of dependencies in lock files. Violations which already exist in the base repository are not reported
+// again. The licenses of the base repository are the stored ones, only the license and lock files changed
+// by the pull request are inspected.
+func CheckLicensePolicy(ctx context.Context, pr *issues_model.PullRequest) error {
+	if err := pr.LoadBaseRepo(ctx); err != nil {
+		return fmt.Errorf("LoadBaseRepo: %w", err)
+	}
+
+	policy, err := organization.GetLicensePolicy(ctx, pr.BaseRepo.OwnerID)
+	if err != nil {
+		return err
+	} else if policy == nil || !policy.BlocksMerge() {
+		return nil
+	}
+
+	gitRepo, err := git.OpenRepository(ctx, pr.BaseRepo.RepoPath())
+	if err != nil {
+		return fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	headCommit, err := gitRepo.GetCommit(pr.GetGitRefName())
+	if err != nil {
+		return fmt.Errorf("GetCommit: %w", err)
+	}
+	mergeBase := pr.MergeBase
+	if mergeBase == "" {
+		if mergeBase, _, err = gitRepo.GetMergeBase("", git.BranchPrefix+pr.BaseBranch, headCommit.ID.String()); err != nil {
+			return fmt.Errorf("GetMergeBase: %w", err)
+		}
+	}
+	changed, err := gitRepo.GetFilesChangedBetween(mergeBase, headCommit.ID.String())
+	if err != nil {
+		return fmt.Errorf("GetFilesChangedBetween: %w", err)
+	}
+	detected, err := repo_module.DetectLicensesFromPaths(headCommit, changed)
+	if err != nil {
+		return fmt.Errorf("DetectLicensesFromPaths: %w", err)
+	}
+	dependencies, err := repo_module.DetectDependencyLicensesFromPaths(headCommit, changed)
+	if err != nil {
+		return fmt.Errorf("DetectDependencyLicensesFromPaths: %w", err)
+	}
+	if len(detected) == 0 && len(dependencies) == 0 {
+		return nil
+	}
+
+	baseLicenses, err := repo_model.GetRepoLicenses(ctx, pr.BaseRepoID)
+	if err != nil {
+		return err
+	}
+	existing := policy.Violations(baseLicenses.StringList())
+
+	var introduced []string
+	licenses := append(repo_module.LicenseNames(detected), repo_module.DependencyLicenseNames(dependencies)...)
+	for _, l := range policy.Violations(licenses) {
+		if !util.SliceContainsString(existing, l, true) {
+			introduced = append(introduced, l)
+		}
+	}
+	if len(introduced) > 0 {
+		return models.ErrDisallowedToMerge{
+			Reason: fmt.Sprintf("Introduces licenses forbidden by the organization license policy: %s", strings.Join(introduced, ", ")),
+		}
+	}
+	return nil
+}
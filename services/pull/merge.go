@@ -31,6 +31,8 @@ import (
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/setting"
 	"code.gitea.io/gitea/modules/timeutil"
+	codescanning_service "code.gitea.io/gitea/services/codescanning"
+	coverage_service "code.gitea.io/gitea/services/coverage"
 	issue_service "code.gitea.io/gitea/services/issue"
 )
 
@@ -53,55 +55,15 @@ func GetDefaultMergeMessage(ctx context.Context, baseGitRepo *git.Repository, pr
 	}
 
 	if mergeStyle != "" {
-		templateFilepath := fmt.Sprintf(".gitea/default_merge_message/%s_TEMPLATE.md", strings.ToUpper(string(mergeStyle)))
-		commit, err := baseGitRepo.GetBranchCommit(pr.BaseRepo.DefaultBranch)
+		templateContent, err := getMergeMessageTemplate(ctx, baseGitRepo, pr, mergeStyle)
 		if err != nil {
 			return "", "", err
 		}
-		templateContent, err := commit.GetFileContent(templateFilepath, setting.Repository.PullRequest.DefaultMergeMessageSize)
-		if err != nil {
-			if !git.IsErrNotExist(err) {
+		if templateContent != "" {
+			vars, err := getMergeMessageVars(ctx, pr, templateContent)
+			if err != nil {
 				return "", "", err
 			}
-		} else {
-			vars := map[string]string{
-				"BaseRepoOwnerName":      pr.BaseRepo.OwnerName,
-				"BaseRepoName":           pr.BaseRepo.Name,
-				"BaseBranch":             pr.BaseBranch,
-				"HeadRepoOwnerName":      "",
-				"HeadRepoName":           "",
-				"HeadBranch":             pr.HeadBranch,
-				"PullRequestTitle":       pr.Issue.Title,
-				"PullRequestDescription": pr.Issue.Content,
-				"PullRequestPosterName":  pr.Issue.Poster.Name,
-				"PullRequestIndex":       strconv.FormatInt(pr.Index, 10),
-				"PullRequestReference":   fmt.Sprintf("%s%d", issueReference, pr.Index),
-			}
-			if pr.HeadRepo != nil {
-				vars["HeadRepoOwnerName"] = pr.HeadRepo.OwnerName
-				vars["HeadRepoName"] = pr.HeadRepo.Name
-			}
-			refs, err := pr.ResolveCrossReferences(ctx)
-			if err == nil {
-				closeIssueIndexes := make([]string, 0, len(refs))
-				closeWord := "close"
-				if len(setting.Repository.PullRequest.CloseKeywords) > 0 {
-					closeWord = setting.Repository.PullRequest.CloseKeywords[0]
-				}
-				for _, ref := range refs {
-					if ref.RefAction == references.XRefActionCloses {
-						if err := ref.LoadIssue(ctx); err != nil {
-							return "", "", err
-						}
-						closeIssueIndexes = append(closeIssueIndexes, fmt.Sprintf("%s %s%d", closeWord, issueReference, ref.Issue.Index))
-					}
-				}
-				if len(closeIssueIndexes) > 0 {
-					vars["ClosingIssues"] = strings.Join(closeIssueIndexes, ", ")
-				} else {
-					vars["ClosingIssues"] = ""
-				}
-			}
 			message, body = expandDefaultMergeMessage(templateContent, vars)
 			return message, body, nil
 		}
@@ -123,6 +85,163 @@ func GetDefaultMergeMessage(ctx context.Context, baseGitRepo *git.Repository, pr
 	return fmt.Sprintf("Merge pull request '%s' (%s%d) from %s:%s into %s", pr.Issue.Title, issueReference, pr.Issue.Index, pr.HeadRepo.FullName(), pr.HeadBranch, pr.BaseBranch), "", nil
 }
 
+// getMergeMessageTemplate returns the template of the default message of a merge style, the one of the repository
+// settings or else the one of the default branch, empty if there is none
+func getMergeMessageTemplate(ctx context.Context, baseGitRepo *git.Repository, pr *issues_model.PullRequest, mergeStyle repo_model.MergeStyle) (string, error) {
+	if prUnit, err := pr.BaseRepo.GetUnit(ctx, unit.TypePullRequests); err == nil {
+		if template := prUnit.PullRequestsConfig().GetMergeMessageTemplate(mergeStyle); template != "" {
+			return template, nil
+		}
+	}
+
+	templateFilepath := fmt.Sprintf(".gitea/default_merge_message/%s_TEMPLATE.md", strings.ToUpper(string(mergeStyle)))
+	commit, err := baseGitRepo.GetBranchCommit(pr.BaseRepo.DefaultBranch)
+	if err != nil {
+		return "", err
+	}
+	templateContent, err := commit.GetFileContent(templateFilepath, setting.Repository.PullRequest.DefaultMergeMessageSize)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return "", nil
+		}
+		return "", err
+	}
+	return templateContent, nil
+}
+
+// getMergeMessageVars returns the variables of the merge message templates, the co-authors are only looked up
+// if the template uses them
+func getMergeMessageVars(ctx context.Context, pr *issues_model.PullRequest, template string) (map[string]string, error) {
+	if err := pr.LoadIssue(ctx); err != nil {
+		return nil, err
+	}
+	if err := pr.Issue.LoadPoster(ctx); err != nil {
+		return nil, err
+	}
+
+	issueReference := "#"
+	if pr.BaseRepo.UnitEnabled(ctx, unit.TypeExternalTracker) {
+		issueReference = "!"
+	}
+
+	vars := map[string]string{
+		"BaseRepoOwnerName":      pr.BaseRepo.OwnerName,
+		"BaseRepoName":           pr.BaseRepo.Name,
+		"BaseBranch":             pr.BaseBranch,
+		"HeadRepoOwnerName":      "",
+		"HeadRepoName":           "",
+		"HeadBranch":             pr.HeadBranch,
+		"PullRequestTitle":       pr.Issue.Title,
+		"PullRequestDescription": pr.Issue.Content,
+		"PullRequestPosterName":  pr.Issue.Poster.Name,
+		"PullRequestIndex":       strconv.FormatInt(pr.Index, 10),
+		"PullRequestReference":   fmt.Sprintf("%s%d", issueReference, pr.Index),
+	}
+	if pr.HeadRepo != nil {
+		vars["HeadRepoOwnerName"] = pr.HeadRepo.OwnerName
+		vars["HeadRepoName"] = pr.HeadRepo.Name
+	}
+	refs, err := pr.ResolveCrossReferences(ctx)
+	if err == nil {
+		closeIssueIndexes := make([]string, 0, len(refs))
+		closeWord := "close"
+		if len(setting.Repository.PullRequest.CloseKeywords) > 0 {
+			closeWord = setting.Repository.PullRequest.CloseKeywords[0]
+		}
+		for _, ref := range refs {
+			if ref.RefAction == references.XRefActionCloses {
+				if err := ref.LoadIssue(ctx); err != nil {
+					return nil, err
+				}
+				if ref.Issue.RepoID != pr.BaseRepoID {
+					if err := ref.Issue.LoadRepo(ctx); err != nil {
+						return nil, err
+					}
+					closeIssueIndexes = append(closeIssueIndexes, fmt.Sprintf("%s %s#%d", closeWord, ref.Issue.Repo.FullName(), ref.Issue.Index))
+					continue
+				}
+				closeIssueIndexes = append(closeIssueIndexes, fmt.Sprintf("%s %s%d", closeWord, issueReference, ref.Issue.Index))
+			}
+		}
+		if len(closeIssueIndexes) > 0 {
+			vars["ClosingIssues"] = strings.Join(closeIssueIndexes, ", ")
+		} else {
+			vars["ClosingIssues"] = ""
+		}
+	}
+	if strings.Contains(template, "CoAuthors") {
+		coAuthors, err := getCoAuthorTrailers(ctx, pr)
+		if err != nil {
+			return nil, err
+		}
+		vars["CoAuthors"] = coAuthors
+	}
+	return vars, nil
+}
+
+// getCoAuthorTrailers returns a Co-authored-by trailer for every author of the commits of a pull request
+// except its poster, one per line
+func getCoAuthorTrailers(ctx context.Context, pr *issues_model.PullRequest) (string, error) {
+	if pr.MergeBase == "" {
+		return "", nil
+	}
+	stdout, _, err := git.NewCommand(ctx, "log", "--format=%an <%ae>").
+		AddDynamicArguments(pr.MergeBase + ".." + pr.GetGitRefName()).
+		RunStdString(&git.RunOpts{Dir: pr.BaseRepo.RepoPath()})
+	if err != nil {
+		return "", err
+	}
+
+	posterSig := pr.Issue.Poster.NewGitSig().String()
+	seen := make(map[string]bool)
+	trailers := make([]string, 0, 5)
+	lines := strings.Split(strings.TrimSpace(stdout), "\n")
+	// the log is in reverse chronological order, the first authors come first in the trailers
+	for i := len(lines) - 1; i >= 0; i-- {
+		author := strings.TrimSpace(lines[i])
+		if author == "" || author == posterSig || seen[author] {
+			continue
+		}
+		seen[author] = true
+		trailers = append(trailers, "Co-authored-by: "+author)
+	}
+	return strings.Join(trailers, "\n"), nil
+}
+
+// addMergeMessageTrailers adds the trailers of the repository settings missing from the message of a merge commit
+func addMergeMessageTrailers(ctx context.Context, pr *issues_model.PullRequest, prConfig *repo_model.PullRequestsConfig, message string) (string, error) {
+	if strings.TrimSpace(prConfig.MergeMessageTrailers) == "" {
+		return message, nil
+	}
+	vars, err := getMergeMessageVars(ctx, pr, prConfig.MergeMessageTrailers)
+	if err != nil {
+		return "", err
+	}
+	return appendTrailers(message, os.Expand(prConfig.MergeMessageTrailers, func(s string) string { return vars[s] })), nil
+}
+
+// appendTrailers appends the non-empty lines of trailers which aren't in the message yet, separated from its body
+// by an empty line unless it already ends with trailers
+func appendTrailers(message, trailers string) string {
+	message = strings.TrimRight(message, "\n")
+	var added []string
+	for _, line := range strings.Split(trailers, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.Contains("\n"+message+"\n", "\n"+line+"\n") {
+			continue
+		}
+		added = append(added, line)
+	}
+	if len(added) == 0 {
+		return message
+	}
+	// the subject of a message without body is never a trailer, even if it looks like one
+	if !strings.Contains(message, "\n\n") || !commitMessageTrailersPattern.MatchString(message) {
+		message += "\n"
+	}
+	return message + "\n" + strings.Join(added, "\n")
+}
+
 func expandDefaultMergeMessage(template string, vars map[string]string) (message, body string) {
 	message = strings.TrimSpace(template)
 	if splits := strings.SplitN(message, "\n", 2); len(splits) == 2 {
@@ -165,6 +284,12 @@ func Merge(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.U
 		return models.ErrInvalidMergeStyle{ID: pr.BaseRepo.ID, Style: mergeStyle}
 	}
 
+	if mergeStyle == repo_model.MergeStyleMerge || mergeStyle == repo_model.MergeStyleRebaseMerge || mergeStyle == repo_model.MergeStyleSquash {
+		if message, err = addMergeMessageTrailers(ctx, pr, prConfig, message); err != nil {
+			return fmt.Errorf("unable to add the merge message trailers: %w", err)
+		}
+	}
+
 	defer func() {
 		go AddTestPullRequestTask(doer, pr.BaseRepo.ID, pr.BaseBranch, false, "", "")
 	}()
@@ -220,12 +345,32 @@ func Merge(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.U
 			return err
 		}
 		close := ref.RefAction == references.XRefActionCloses
-		if close != ref.Issue.IsClosed {
-			if err = issue_service.ChangeStatus(ref.Issue, doer, pr.MergedCommitID, close); err != nil {
-				// Allow ErrDependenciesLeft
-				if !issues_model.IsErrDependenciesLeft(err) {
-					return err
-				}
+		if close == ref.Issue.IsClosed {
+			continue
+		}
+		crossRepo := ref.Issue.RepoID != pr.BaseRepoID
+		if crossRepo {
+			// The reference was checked against the permissions of its poster, the merger must be
+			// allowed to change the issue in the other repository as well
+			perm, err := access_model.GetUserRepoPermission(hammerCtx, ref.Issue.Repo, doer)
+			if err != nil {
+				return err
+			}
+			if !perm.CanWriteIssuesOrPulls(ref.Issue.IsPull) {
+				log.Debug("%-v cannot change the status of issue %d in %-v referenced by %-v", doer, ref.Issue.ID, ref.Issue.Repo, pr)
+				continue
+			}
+		}
+		if err = issue_service.ChangeStatus(ref.Issue, doer, pr.MergedCommitID, close); err != nil {
+			// Allow ErrDependenciesLeft
+			if !issues_model.IsErrDependenciesLeft(err) {
+				return err
+			}
+			continue
+		}
+		if crossRepo && close {
+			if _, err = issues_model.CreateCloseIssueRefComment(hammerCtx, doer, pr, ref.Issue); err != nil {
+				return err
 			}
 		}
 	}
@@ -441,6 +586,35 @@ func CheckPullBranchProtections(ctx context.Context, pr *issues_model.PullReques
 		}
 	}
 
+	if pb.BlockOnCodeScanningAlerts {
+		alerts, err := codescanning_service.NewHighSeverityAlerts(ctx, pr)
+		if err != nil {
+			return fmt.Errorf("NewHighSeverityAlerts: %w", err)
+		}
+		if len(alerts) > 0 {
+			return models.ErrDisallowedToMerge{
+				Reason: fmt.Sprintf("There are %d new code scanning alerts of high severity", len(alerts)),
+			}
+		}
+	}
+
+	if pb.MinDiffCoverage > 0 {
+		diffCoverage, err := coverage_service.GetPullRequestDiffCoverage(ctx, pr)
+		if err != nil {
+			return fmt.Errorf("GetPullRequestDiffCoverage: %w", err)
+		}
+		if diffCoverage == nil {
+			return models.ErrDisallowedToMerge{
+				Reason: "There is no coverage report for the head commit",
+			}
+		}
+		if !diffCoverage.Satisfies(pb.MinDiffCoverage) {
+			return models.ErrDisallowedToMerge{
+				Reason: fmt.Sprintf("The diff coverage of %.1f%% is below the required %d%%", diffCoverage.Percentage(), pb.MinDiffCoverage),
+			}
+		}
+	}
+
 	if skipProtectedFilesCheck {
 		return nil
 	}
@@ -65,3 +65,16 @@ func Test_expandDefaultMergeMessage(t *testing.T) {
 		})
 	}
 }
+
+func Test_appendTrailers(t *testing.T) {
+	trailers := "Reviewed-on: https://example.com/pulls/1\n\nCo-authored-by: Alice <alice@example.com>\n"
+
+	assert.Equal(t, "Fix: bug\n\nReviewed-on: https://example.com/pulls/1\nCo-authored-by: Alice <alice@example.com>",
+		appendTrailers("Fix: bug\n", trailers))
+	assert.Equal(t, "Title\n\nBody\n\nReviewed-on: https://example.com/pulls/1\nCo-authored-by: Alice <alice@example.com>",
+		appendTrailers("Title\n\nBody", trailers))
+	// trailers already in the message aren't repeated, and the new ones go below the existing ones
+	assert.Equal(t, "Title\n\nCo-authored-by: Alice <alice@example.com>\nReviewed-on: https://example.com/pulls/1",
+		appendTrailers("Title\n\nCo-authored-by: Alice <alice@example.com>", trailers))
+	assert.Equal(t, "Title", appendTrailers("Title", "\n"))
+}
@@ -0,0 +1,119 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+)
+
+// MergeTrainCommit is the result of merging the pull requests of a merge train one after another into their base branch
+type MergeTrainCommit struct {
+	BaseCommitID string
+	// CommitID is empty if none of the pull requests could be merged
+	CommitID string
+	// Merged are the pull requests which are part of the commit, in their order
+	Merged []*issues_model.PullRequest
+	// HeadCommitIDs are the head commits of the merged pull requests by their ID
+	HeadCommitIDs map[int64]string
+	// Conflicting are the pull requests which conflict with the base branch or the ones merged before them
+	Conflicting []*issues_model.PullRequest
+}
+
+// CreateMergeTrainCommit merges the pull requests into the base branch in a temporary repository, like merging them with merge commits
+// one after another would, and pushes the result to the branch of the train so it can be tested before the pull requests are merged.
+func CreateMergeTrainCommit(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, baseBranch, trainBranch string, prs []*issues_model.PullRequest) (*MergeTrainCommit, error) {
+	tmpBasePath, err := repo_module.CreateTemporaryPath("merge-train")
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := repo_module.RemoveTemporaryPath(tmpBasePath); err != nil {
+			log.Error("Error whilst removing temporary repo for merge train of %-v: %v", repo, err)
+		}
+	}()
+
+	errbuf := &strings.Builder{}
+	sig := doer.NewGitSig()
+	runOpts := func() *git.RunOpts {
+		errbuf.Reset()
+		return &git.RunOpts{
+			Dir: tmpBasePath,
+			Env: append(os.Environ(),
+				"GIT_AUTHOR_NAME="+sig.Name,
+				"GIT_AUTHOR_EMAIL="+sig.Email,
+				"GIT_COMMITTER_NAME="+sig.Name,
+				"GIT_COMMITTER_EMAIL="+sig.Email,
+			),
+			Stderr: errbuf,
+		}
+	}
+
+	if err := git.NewCommand(ctx, "clone", "-s", "--no-tags", "-b").AddDynamicArguments(baseBranch, repo.RepoPath(), tmpBasePath).
+		Run(&git.RunOpts{Stderr: errbuf}); err != nil {
+		return nil, fmt.Errorf("git clone: %w\n%s", err, errbuf.String())
+	}
+	baseCommitID, _, err := git.NewCommand(ctx, "rev-parse", "HEAD").RunStdString(runOpts())
+	if err != nil {
+		return nil, fmt.Errorf("git rev-parse: %w\n%s", err, errbuf.String())
+	}
+
+	result := &MergeTrainCommit{
+		BaseCommitID:  strings.TrimSpace(baseCommitID),
+		HeadCommitIDs: make(map[int64]string, len(prs)),
+	}
+	for _, pr := range prs {
+		// the head refs of all the pull requests are in the base repository, including the ones from forks
+		trainRef := fmt.Sprintf("refs/merge-train/%d", pr.Index)
+		if err := git.NewCommand(ctx, "fetch", "--no-tags", "origin").AddDynamicArguments("+" + pr.GetGitRefName() + ":" + trainRef).
+			Run(runOpts()); err != nil {
+			return nil, fmt.Errorf("git fetch %s: %w\n%s", pr.GetGitRefName(), err, errbuf.String())
+		}
+		headCommitID, _, err := git.NewCommand(ctx, "rev-parse").AddDynamicArguments(trainRef).RunStdString(runOpts())
+		if err != nil {
+			return nil, fmt.Errorf("git rev-parse %s: %w\n%s", trainRef, err, errbuf.String())
+		}
+		message := fmt.Sprintf("Merge pull request #%d into %s", pr.Index, baseBranch)
+		if err := git.NewCommand(ctx, "merge", "--no-ff", "--no-edit").AddOptionFormat("--message=%s", message).AddDynamicArguments(trainRef).
+			Run(runOpts()); err != nil {
+			log.Debug("%-v conflicts in the merge train of %s: %v\n%s", pr, baseBranch, err, errbuf.String())
+			if err := git.NewCommand(ctx, "merge", "--abort").Run(runOpts()); err != nil {
+				return nil, fmt.Errorf("git merge --abort: %w\n%s", err, errbuf.String())
+			}
+			result.Conflicting = append(result.Conflicting, pr)
+			continue
+		}
+		result.Merged = append(result.Merged, pr)
+		result.HeadCommitIDs[pr.ID] = strings.TrimSpace(headCommitID)
+	}
+	if len(result.Merged) == 0 {
+		return result, nil
+	}
+
+	commitID, _, err := git.NewCommand(ctx, "rev-parse", "HEAD").RunStdString(runOpts())
+	if err != nil {
+		return nil, fmt.Errorf("git rev-parse: %w\n%s", err, errbuf.String())
+	}
+	result.CommitID = strings.TrimSpace(commitID)
+
+	// pushing runs the hooks, so the train branch is tested like any other pushed branch
+	if err := git.NewCommand(ctx, "push", "-f", "origin").AddDynamicArguments("HEAD:" + git.BranchPrefix + trainBranch).
+		Run(&git.RunOpts{
+			Dir:    tmpBasePath,
+			Env:    repo_module.PushingEnvironment(doer, repo),
+			Stderr: errbuf,
+		}); err != nil {
+		return nil, fmt.Errorf("git push: %w\n%s", err, errbuf.String())
+	}
+	return result, nil
+}
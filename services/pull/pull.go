@@ -29,6 +29,7 @@ import (
 	"code.gitea.io/gitea/modules/sync"
 	"code.gitea.io/gitea/modules/util"
 	issue_service "code.gitea.io/gitea/services/issue"
+	"code.gitea.io/gitea/services/moderation"
 )
 
 // TODO: use clustered lock (unique queue? or *abuse* cache)
@@ -36,6 +37,10 @@ var pullWorkingPool = sync.NewExclusivePool()
 
 // NewPullRequest creates new pull request with labels for repository.
 func NewPullRequest(ctx context.Context, repo *repo_model.Repository, pull *issues_model.Issue, labelIDs []int64, uuids []string, pr *issues_model.PullRequest, assigneeIDs []int64) error {
+	if err := moderation.CheckInteraction(ctx, pull.Poster, repo); err != nil {
+		return err
+	}
+
 	if err := TestPatch(pr); err != nil {
 		return err
 	}
@@ -128,6 +133,43 @@ func NewPullRequest(ctx context.Context, repo *repo_model.Repository, pull *issu
 	return nil
 }
 
+// NewPullRequestFromBranch opens a pull request from headBranch into baseBranch of the same repository
+func NewPullRequestFromBranch(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, headBranch, baseBranch, title, content string) (*issues_model.PullRequest, error) {
+	gitRepo, closer, err := git.RepositoryFromContextOrOpen(ctx, repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	mergeBase, _, err := gitRepo.GetMergeBase("", git.BranchPrefix+baseBranch, git.BranchPrefix+headBranch)
+	if err != nil {
+		return nil, fmt.Errorf("GetMergeBase: %w", err)
+	}
+
+	issue := &issues_model.Issue{
+		RepoID:   repo.ID,
+		Title:    title,
+		PosterID: doer.ID,
+		Poster:   doer,
+		IsPull:   true,
+		Content:  content,
+	}
+	pr := &issues_model.PullRequest{
+		HeadRepoID: repo.ID,
+		BaseRepoID: repo.ID,
+		HeadBranch: headBranch,
+		BaseBranch: baseBranch,
+		HeadRepo:   repo,
+		BaseRepo:   repo,
+		MergeBase:  mergeBase,
+		Type:       issues_model.PullRequestGitea,
+	}
+	if err := NewPullRequest(ctx, repo, issue, nil, nil, pr, nil); err != nil {
+		return nil, err
+	}
+	return pr, nil
+}
+
 // ChangeTargetBranch changes the target branch of this pull request, as the given user.
 func ChangeTargetBranch(ctx context.Context, pr *issues_model.PullRequest, doer *user_model.User, targetBranch string) (err error) {
 	pullWorkingPool.CheckIn(fmt.Sprint(pr.ID))
@@ -292,7 +334,7 @@ func AddTestPullRequestTask(doer *user_model.User, repoID int64, branch string,
 			}
 			if err == nil {
 				for _, pr := range prs {
-					if newCommitID != "" && newCommitID != git.EmptySHA {
+					if newCommitID != "" && !git.IsEmptyCommitID(newCommitID) {
 						changed, err := checkIfPRContentChanged(ctx, pr, oldCommitID, newCommitID)
 						if err != nil {
 							log.Error("checkIfPRContentChanged: %v", err)
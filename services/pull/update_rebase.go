@@ -42,7 +42,9 @@ func updateHeadByRebaseOnToBase(ctx context.Context, pr *issues_model.PullReques
 		// the push as you can always re-rebase.
 		if err := LFSPush(ctx, mergeCtx.tmpBasePath, baseBranch, oldMergeBase, &issues_model.PullRequest{
 			HeadRepoID: pr.BaseRepoID,
+			HeadRepo:   pr.BaseRepo,
 			BaseRepoID: pr.HeadRepoID,
+			BaseRepo:   pr.HeadRepo,
 		}); err != nil {
 			log.Error("Unable to push lfs objects between %s and %s up to head branch in %-v: %v", baseBranch, oldMergeBase, pr, err)
 			return err
@@ -0,0 +1,234 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package pull
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unit"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// MergeUpstreamType is the way a branch of a fork was updated from its upstream branch
+type MergeUpstreamType string
+
+const (
+	// MergeUpstreamNone means the branch already contains the upstream branch
+	MergeUpstreamNone MergeUpstreamType = "none"
+	// MergeUpstreamFastForward means the branch was fast-forwarded to the upstream branch
+	MergeUpstreamFastForward MergeUpstreamType = "fast-forward"
+	// MergeUpstreamMerge means the upstream branch was merged into the branch
+	MergeUpstreamMerge MergeUpstreamType = "merge"
+)
+
+// ErrUpstreamDiverged is returned if the branch has commits which are not in the upstream branch and merging is not allowed
+var ErrUpstreamDiverged = errors.New("branch has diverged from the upstream branch")
+
+// ErrUpstreamConflicts represents changes in a branch of a fork which conflict with its upstream branch
+type ErrUpstreamConflicts struct {
+	Files []string
+}
+
+// IsErrUpstreamConflicts checks if an error is a ErrUpstreamConflicts.
+func IsErrUpstreamConflicts(err error) bool {
+	_, ok := err.(ErrUpstreamConflicts)
+	return ok
+}
+
+func (err ErrUpstreamConflicts) Error() string {
+	return fmt.Sprintf("branch conflicts with the upstream branch in: %s", strings.Join(err.Files, ", "))
+}
+
+// MergeUpstream updates a branch of a fork from a branch of the repository it was forked from.
+// The branch is fast-forwarded if possible, otherwise the upstream branch is merged into it if allowMerge is set.
+// An empty upstreamBranch uses the upstream branch with the same name.
+func MergeUpstream(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, branch, upstreamBranch string, allowMerge bool) (MergeUpstreamType, string, error) {
+	if !repo.IsFork {
+		return "", "", util.NewInvalidArgumentErrorf("repository %s is not a fork", repo.FullName())
+	}
+	if err := repo.GetBaseRepo(ctx); err != nil {
+		return "", "", fmt.Errorf("GetBaseRepo: %w", err)
+	}
+	// the upstream repository might have been made private since it was forked
+	perm, err := access_model.GetUserRepoPermission(ctx, repo.BaseRepo, doer)
+	if err != nil {
+		return "", "", err
+	}
+	if !perm.CanRead(unit.TypeCode) {
+		return "", "", repo_model.ErrRepoNotExist{ID: repo.BaseRepo.ID}
+	}
+	if upstreamBranch == "" {
+		upstreamBranch = branch
+	}
+	if !git.IsBranchExist(ctx, repo.RepoPath(), branch) {
+		return "", "", models.ErrBranchDoesNotExist{BranchName: branch}
+	}
+
+	// use the pull request functions with the upstream branch as head and the branch of the fork as base
+	pr := &issues_model.PullRequest{
+		HeadRepoID: repo.BaseRepo.ID,
+		HeadRepo:   repo.BaseRepo,
+		HeadBranch: upstreamBranch,
+
+		BaseRepoID: repo.ID,
+		BaseRepo:   repo,
+		BaseBranch: branch,
+	}
+
+	workingKey := fmt.Sprintf("upstream_%d_%s", repo.ID, branch)
+	pullWorkingPool.CheckIn(workingKey)
+	defer pullWorkingPool.CheckOut(workingKey)
+
+	prCtx, cancel, err := createTemporaryRepoForPR(ctx, pr)
+	if err != nil {
+		return "", "", err
+	}
+	defer cancel()
+
+	// Behind counts the commits only in the branch of the fork, Ahead those only in the upstream branch
+	diff, err := git.GetDivergingCommits(ctx, prCtx.tmpBasePath, baseBranch, trackingBranch)
+	if err != nil {
+		return "", "", fmt.Errorf("GetDivergingCommits: %w", err)
+	}
+
+	if diff.Ahead == 0 {
+		commitID, err := git.GetFullCommitID(ctx, prCtx.tmpBasePath, baseBranch)
+		return MergeUpstreamNone, commitID, err
+	}
+
+	if diff.Behind == 0 {
+		commitID, err := fastForwardToUpstream(prCtx, doer)
+		if err != nil {
+			return "", "", err
+		}
+		return MergeUpstreamFastForward, commitID, nil
+	}
+
+	if !allowMerge {
+		return "", "", ErrUpstreamDiverged
+	}
+
+	// check for conflicts first, so the conflicting files can be reported
+	gitRepo, err := git.OpenRepository(ctx, prCtx.tmpBasePath)
+	if err != nil {
+		return "", "", fmt.Errorf("OpenRepository: %w", err)
+	}
+	defer gitRepo.Close()
+
+	mergeBase, _, err := git.NewCommand(ctx, "merge-base", "--", baseBranch, trackingBranch).RunStdString(&git.RunOpts{Dir: prCtx.tmpBasePath})
+	if err != nil {
+		return "", "", fmt.Errorf("GetMergeBase: %w", err)
+	}
+	pr.MergeBase = strings.TrimSpace(mergeBase)
+	if conflicts, err := checkConflicts(ctx, pr, gitRepo, prCtx.tmpBasePath); err != nil {
+		return "", "", err
+	} else if conflicts {
+		return "", "", ErrUpstreamConflicts{Files: pr.ConflictedFiles}
+	}
+
+	message := fmt.Sprintf("Merge branch '%s' of %s into %s", upstreamBranch, repo.BaseRepo.FullName(), branch)
+	commitID, err := doMergeAndPush(ctx, pr, doer, repo_model.MergeStyleMerge, "", message)
+	if err != nil {
+		if models.IsErrMergeConflicts(err) {
+			return "", "", ErrUpstreamConflicts{}
+		}
+		return "", "", err
+	}
+	return MergeUpstreamMerge, commitID, nil
+}
+
+// fastForwardToUpstream pushes the upstream branch of the temporary repository to the branch of the fork
+func fastForwardToUpstream(ctx *prContext, doer *user_model.User) (string, error) {
+	headCommitID, err := git.GetFullCommitID(ctx, ctx.tmpBasePath, trackingBranch)
+	if err != nil {
+		return "", fmt.Errorf("GetFullCommitID: %w", err)
+	}
+	baseCommitID, err := git.GetFullCommitID(ctx, ctx.tmpBasePath, baseBranch)
+	if err != nil {
+		return "", fmt.Errorf("GetFullCommitID: %w", err)
+	}
+
+	// the LFS objects of the upstream commits have to be linked to the fork
+	if setting.LFS.StartServer {
+		if err := LFSPush(ctx, ctx.tmpBasePath, headCommitID, baseCommitID, ctx.pr); err != nil {
+			return "", err
+		}
+	}
+
+	opts := ctx.RunOpts()
+	opts.Env = repo_module.PushingEnvironment(doer, ctx.pr.BaseRepo)
+	if err := git.NewCommand(ctx, "push", "origin").AddDynamicArguments(trackingBranch + ":" + git.BranchPrefix + ctx.pr.BaseBranch).Run(opts); err != nil {
+		if strings.Contains(ctx.errbuf.String(), "non-fast-forward") {
+			return "", &git.ErrPushOutOfDate{
+				StdOut: ctx.outbuf.String(),
+				StdErr: ctx.errbuf.String(),
+				Err:    err,
+			}
+		} else if strings.Contains(ctx.errbuf.String(), "! [remote rejected]") {
+			err := &git.ErrPushRejected{
+				StdOut: ctx.outbuf.String(),
+				StdErr: ctx.errbuf.String(),
+				Err:    err,
+			}
+			err.GenerateMessage()
+			return "", err
+		}
+		log.Error("Unable to push upstream branch to %s: %v\n%s", ctx.pr.BaseRepo.FullName(), err, ctx.errbuf.String())
+		return "", fmt.Errorf("git push: %s", ctx.errbuf.String())
+	}
+	return headCommitID, nil
+}
+
+// SyncForks updates the branches of forks which are scheduled to be synced from their upstream branches
+func SyncForks(ctx context.Context) error {
+	return repo_model.IterateForkSyncs(ctx, func(ctx context.Context, s *repo_model.ForkSync) error {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before syncing branch %s of repository %d", s.Branch, s.RepoID)
+		default:
+		}
+
+		status, message := syncFork(ctx, s)
+		return repo_model.UpdateForkSyncResult(ctx, s, status, message)
+	})
+}
+
+func syncFork(ctx context.Context, s *repo_model.ForkSync) (repo_model.ForkSyncStatus, string) {
+	repo, err := repo_model.GetRepositoryByID(ctx, s.RepoID)
+	if err != nil {
+		return repo_model.ForkSyncStatusFailed, err.Error()
+	}
+	if repo.IsArchived {
+		return repo_model.ForkSyncStatusFailed, "repository is archived"
+	}
+	doer, err := user_model.GetUserByID(ctx, s.DoerID)
+	if err != nil {
+		return repo_model.ForkSyncStatusFailed, err.Error()
+	}
+
+	mergeType, commitID, err := MergeUpstream(ctx, doer, repo, s.Branch, s.UpstreamBranch, s.AllowMerge)
+	switch {
+	case err == nil:
+		return repo_model.ForkSyncStatusSuccess, fmt.Sprintf("%s: %s", mergeType, commitID)
+	case errors.Is(err, ErrUpstreamDiverged):
+		return repo_model.ForkSyncStatusDiverged, err.Error()
+	case IsErrUpstreamConflicts(err):
+		return repo_model.ForkSyncStatusConflict, err.Error()
+	}
+	log.Error("Unable to sync branch %s of %-v from upstream: %v", s.Branch, repo, err)
+	return repo_model.ForkSyncStatusFailed, err.Error()
+}
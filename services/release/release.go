@@ -211,6 +211,7 @@ func UpdateRelease(doer *user_model.User, gitRepo *git.Repository, rel *repo_mod
 	}
 
 	deletedUUIDs := make(container.Set[string])
+	var delAttachmentPaths []string
 	if len(delAttachmentUUIDs) > 0 {
 		// Check attachments
 		attachments, err := repo_model.GetAttachmentsByUUIDs(ctx, delAttachmentUUIDs)
@@ -227,7 +228,16 @@ func UpdateRelease(doer *user_model.User, gitRepo *git.Repository, rel *repo_mod
 			deletedUUIDs.Add(attach.UUID)
 		}
 
-		if _, err := repo_model.DeleteAttachments(ctx, attachments, true); err != nil {
+		// files shared with duplicate attachments elsewhere have to be kept
+		removable, err := repo_model.RemovableAttachments(ctx, attachments)
+		if err != nil {
+			return fmt.Errorf("RemovableAttachments: %w", err)
+		}
+		for _, attach := range removable {
+			delAttachmentPaths = append(delAttachmentPaths, attach.ObjectPaths()...)
+		}
+
+		if _, err := repo_model.DeleteAttachments(ctx, attachments, false); err != nil {
 			return fmt.Errorf("DeleteAttachments [uuids: %v]: %w", delAttachmentUUIDs, err)
 		}
 	}
@@ -267,13 +277,13 @@ func UpdateRelease(doer *user_model.User, gitRepo *git.Repository, rel *repo_mod
 		return
 	}
 
-	for _, uuid := range delAttachmentUUIDs {
-		if err := storage.Attachments.Delete(repo_model.AttachmentRelativePath(uuid)); err != nil {
+	for _, attachmentPath := range delAttachmentPaths {
+		if err := storage.AttachmentsOfRegion(rel.Repo.StorageRegion()).Delete(attachmentPath); err != nil {
 			// Even delete files failed, but the attachments has been removed from database, so we
 			// should not return error but only record the error on logs.
 			// users have to delete this attachments manually or we should have a
 			// synchronize between database attachment table and attachment storage
-			log.Error("delete attachment[uuid: %s] failed: %v", uuid, err)
+			log.Error("delete attachment[path: %s] failed: %v", attachmentPath, err)
 		}
 	}
 
@@ -352,10 +362,15 @@ func DeleteReleaseByID(ctx context.Context, id int64, doer *user_model.User, del
 		return fmt.Errorf("DeleteAttachments: %w", err)
 	}
 
-	for i := range rel.Attachments {
-		attachment := rel.Attachments[i]
-		if err := storage.Attachments.Delete(attachment.RelativePath()); err != nil {
-			log.Error("Delete attachment %s of release %s failed: %v", attachment.UUID, rel.ID, err)
+	removable, err := repo_model.RemovableAttachments(ctx, rel.Attachments)
+	if err != nil {
+		return fmt.Errorf("RemovableAttachments: %w", err)
+	}
+	for _, attachment := range removable {
+		for _, attachmentPath := range attachment.ObjectPaths() {
+			if err := storage.AttachmentsOfRegion(repo.StorageRegion()).Delete(attachmentPath); err != nil {
+				log.Error("Delete attachment %s of release %s failed: %v", attachment.UUID, rel.ID, err)
+			}
 		}
 	}
 
@@ -0,0 +1,111 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package reminder
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/models/organization"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	webhook_module "code.gitea.io/gitea/modules/webhook"
+	webhook_service "code.gitea.io/gitea/services/webhook"
+)
+
+// discordMaxContentLength is the maximum length of the content of a discord message
+const discordMaxContentLength = 2000
+
+// IsValidWebhookType returns true if reminders can be posted to webhooks of the given type
+func IsValidWebhookType(typ string) bool {
+	switch typ {
+	case webhook_module.GITEA, webhook_module.SLACK, webhook_module.DISCORD, webhook_module.MSTEAMS:
+		return true
+	}
+	return false
+}
+
+func markdownLink(text, link string) string {
+	return fmt.Sprintf("[%s](%s)", text, link)
+}
+
+func slackLink(text, link string) string {
+	return fmt.Sprintf("<%s|%s>", link, strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text))
+}
+
+// FormatDigest renders the digest as a chat message, link formats a link in the markup of the chat
+func FormatDigest(p *api.TeamReminderPayload, link func(text, link string) string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Reminder for team %s/%s\n", p.Organization.UserName, p.Team.Name)
+
+	if len(p.ReviewRequests) > 0 {
+		fmt.Fprintf(&sb, "\nPull requests awaiting review (%d):\n", len(p.ReviewRequests))
+		for _, pull := range p.ReviewRequests {
+			fmt.Fprintf(&sb, "- %s by %s, waiting for %s\n", link(fmt.Sprintf("%s#%d %s", pull.Repository, pull.Index, pull.Title), pull.HTMLURL), pull.Poster, strings.Join(pull.Reviewers, ", "))
+		}
+	}
+	if len(p.StalePulls) > 0 {
+		fmt.Fprintf(&sb, "\nStale pull requests (%d):\n", len(p.StalePulls))
+		for _, pull := range p.StalePulls {
+			fmt.Fprintf(&sb, "- %s by %s, last updated %s\n", link(fmt.Sprintf("%s#%d %s", pull.Repository, pull.Index, pull.Title), pull.HTMLURL), pull.Poster, pull.Updated.Format("2006-01-02"))
+		}
+	}
+	if len(p.FailingChecks) > 0 {
+		fmt.Fprintf(&sb, "\nFailing checks on default branches (%d):\n", len(p.FailingChecks))
+		for _, branch := range p.FailingChecks {
+			text := fmt.Sprintf("%s:%s", branch.Repository, branch.Branch)
+			if branch.TargetURL != "" {
+				text = link(text, branch.TargetURL)
+			}
+			fmt.Fprintf(&sb, "- %s is %s at %.10s\n", text, branch.State, branch.CommitID)
+		}
+	}
+	return sb.String()
+}
+
+func buildRequestBody(typ string, p *api.TeamReminderPayload) ([]byte, error) {
+	switch typ {
+	case webhook_module.SLACK:
+		return json.Marshal(map[string]string{"text": FormatDigest(p, slackLink)})
+	case webhook_module.DISCORD:
+		return json.Marshal(map[string]string{"content": base.EllipsisString(FormatDigest(p, markdownLink), discordMaxContentLength)})
+	case webhook_module.MSTEAMS:
+		return json.Marshal(map[string]string{"text": FormatDigest(p, markdownLink)})
+	default:
+		return json.Marshal(p)
+	}
+}
+
+func deliver(ctx context.Context, r *organization.TeamReminder, p *api.TeamReminderPayload) error {
+	body, err := buildRequestBody(r.WebhookType, p)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Gitea "+setting.AppVer)
+	req.Header.Set("X-Gitea-Event", "team_reminder")
+
+	resp, err := webhook_service.DoRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package reminder
+
+import (
+	"testing"
+	"time"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatDigest(t *testing.T) {
+	p := &api.TeamReminderPayload{
+		Organization: &api.Organization{UserName: "org3"},
+		Team:         &api.Team{Name: "reviewers"},
+		ReviewRequests: []*api.TeamReminderPull{
+			{Repository: "org3/repo3", Index: 2, Title: "Fix <bug>", HTMLURL: "https://try.gitea.io/org3/repo3/pulls/2", Poster: "user2", Reviewers: []string{"user4", "user5"}},
+		},
+		StalePulls: []*api.TeamReminderPull{
+			{Repository: "org3/repo3", Index: 1, Title: "Old", HTMLURL: "https://try.gitea.io/org3/repo3/pulls/1", Poster: "user2", Updated: time.Date(2023, 1, 2, 3, 4, 5, 0, time.UTC)},
+		},
+		FailingChecks: []*api.TeamReminderFailingBranch{
+			{Repository: "org3/repo3", Branch: "main", CommitID: "65f1bf27bc3bf70f64657658635e66094edbcb4d", State: "failure"},
+		},
+	}
+
+	assert.Equal(t, `Reminder for team org3/reviewers
+
+Pull requests awaiting review (1):
+- [org3/repo3#2 Fix <bug>](https://try.gitea.io/org3/repo3/pulls/2) by user2, waiting for user4, user5
+
+Stale pull requests (1):
+- [org3/repo3#1 Old](https://try.gitea.io/org3/repo3/pulls/1) by user2, last updated 2023-01-02
+
+Failing checks on default branches (1):
+- org3/repo3:main is failure at 65f1bf27bc
+`, FormatDigest(p, markdownLink))
+
+	assert.Contains(t, FormatDigest(p, slackLink), "<https://try.gitea.io/org3/repo3/pulls/2|org3/repo3#2 Fix &lt;bug&gt;>")
+}
@@ -0,0 +1,238 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package reminder
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/convert"
+)
+
+// maxItemsPerSection limits the length of each section of a digest
+const maxItemsPerSection = 25
+
+// ProcessDue sends the digests of all reminders whose scheduled time has passed
+func ProcessDue(ctx context.Context) error {
+	now := time.Now()
+	reminders, err := organization.FindDueTeamReminders(ctx, timeutil.TimeStamp(now.Unix()))
+	if err != nil {
+		return err
+	}
+	for _, r := range reminders {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before sending team reminder %d", r.ID)
+		default:
+		}
+
+		if err := Send(ctx, r); err != nil {
+			log.Error("Unable to send team reminder %d: %v", r.ID, err)
+		}
+		if err := organization.UpdateTeamReminderRun(ctx, r, now); err != nil {
+			log.Error("UpdateTeamReminderRun[%d]: %v", r.ID, err)
+		}
+	}
+	return nil
+}
+
+// Send builds the digest of a reminder and posts it to its webhook, nothing is posted if there is nothing to report
+func Send(ctx context.Context, r *organization.TeamReminder) error {
+	team, err := organization.GetTeamByID(ctx, r.TeamID)
+	if err != nil {
+		return fmt.Errorf("GetTeamByID: %w", err)
+	}
+	org, err := organization.GetOrgByID(ctx, team.OrgID)
+	if err != nil {
+		return fmt.Errorf("GetOrgByID: %w", err)
+	}
+	payload, err := BuildDigest(ctx, org, team, r)
+	if err != nil {
+		return err
+	}
+	if payload.IsEmpty() {
+		return nil
+	}
+	return deliver(ctx, r, payload)
+}
+
+// BuildDigest collects the pull requests and branches a reminder reports about
+func BuildDigest(ctx context.Context, org *organization.Organization, team *organization.Team, r *organization.TeamReminder) (*api.TeamReminderPayload, error) {
+	apiTeam, err := convert.ToTeam(ctx, team)
+	if err != nil {
+		return nil, err
+	}
+	payload := &api.TeamReminderPayload{
+		Organization:   convert.ToOrganization(ctx, org),
+		Team:           apiTeam,
+		ReviewRequests: []*api.TeamReminderPull{},
+		StalePulls:     []*api.TeamReminderPull{},
+		FailingChecks:  []*api.TeamReminderFailingBranch{},
+	}
+
+	if err := team.LoadRepositories(ctx); err != nil {
+		return nil, err
+	}
+	repos := make([]*repo_model.Repository, 0, len(team.Repos))
+	repoIDs := make([]int64, 0, len(team.Repos))
+	for _, repo := range team.Repos {
+		if repo.IsArchived {
+			continue
+		}
+		repos = append(repos, repo)
+		repoIDs = append(repoIDs, repo.ID)
+	}
+	if len(repos) == 0 {
+		return payload, nil
+	}
+
+	if r.IncludeReviewRequests {
+		if payload.ReviewRequests, err = findReviewRequests(ctx, team, repoIDs, r.IgnoreWorkInProgress); err != nil {
+			return nil, err
+		}
+	}
+	if r.IncludeStalePulls {
+		if payload.StalePulls, err = findStalePulls(ctx, repoIDs, r.StaleDays, r.IgnoreWorkInProgress); err != nil {
+			return nil, err
+		}
+	}
+	if r.IncludeFailingChecks {
+		if payload.FailingChecks, err = findFailingChecks(ctx, repos); err != nil {
+			return nil, err
+		}
+	}
+	return payload, nil
+}
+
+func toReminderPull(ctx context.Context, issue *issues_model.Issue) (*api.TeamReminderPull, error) {
+	if err := issue.LoadRepo(ctx); err != nil {
+		return nil, err
+	}
+	if err := issue.LoadPoster(ctx); err != nil {
+		return nil, err
+	}
+	return &api.TeamReminderPull{
+		Repository: issue.Repo.FullName(),
+		Index:      issue.Index,
+		Title:      issue.Title,
+		HTMLURL:    issue.HTMLURL(),
+		Poster:     issue.Poster.GetDisplayName(),
+		Updated:    issue.UpdatedUnix.AsTime(),
+	}, nil
+}
+
+// findReviewRequests returns the open pull requests waiting for a review of a team member
+func findReviewRequests(ctx context.Context, team *organization.Team, repoIDs []int64, ignoreWIP bool) ([]*api.TeamReminderPull, error) {
+	if err := team.LoadMembers(ctx); err != nil {
+		return nil, err
+	}
+
+	pulls := make([]*api.TeamReminderPull, 0, 10)
+	byIssueID := make(map[int64]*api.TeamReminderPull)
+	for _, member := range team.Members {
+		issues, err := issues_model.Issues(ctx, &issues_model.IssuesOptions{
+			RepoIDs:           repoIDs,
+			IsPull:            util.OptionalBoolTrue,
+			IsClosed:          util.OptionalBoolFalse,
+			ReviewRequestedID: member.ID,
+			SortType:          "oldest",
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			if ignoreWIP && issues_model.HasWorkInProgressPrefix(issue.Title) {
+				continue
+			}
+			pull, ok := byIssueID[issue.ID]
+			if !ok {
+				if len(pulls) >= maxItemsPerSection {
+					continue
+				}
+				if pull, err = toReminderPull(ctx, issue); err != nil {
+					return nil, err
+				}
+				byIssueID[issue.ID] = pull
+				pulls = append(pulls, pull)
+			}
+			if !util.SliceContainsString(pull.Reviewers, member.Name) {
+				pull.Reviewers = append(pull.Reviewers, member.Name)
+			}
+		}
+	}
+	return pulls, nil
+}
+
+// findStalePulls returns the open pull requests which have not been updated for the given number of days
+func findStalePulls(ctx context.Context, repoIDs []int64, staleDays int, ignoreWIP bool) ([]*api.TeamReminderPull, error) {
+	issues, err := issues_model.Issues(ctx, &issues_model.IssuesOptions{
+		ListOptions:       db.ListOptions{PageSize: maxItemsPerSection * 2},
+		RepoIDs:           repoIDs,
+		IsPull:            util.OptionalBoolTrue,
+		IsClosed:          util.OptionalBoolFalse,
+		UpdatedBeforeUnix: time.Now().AddDate(0, 0, -staleDays).Unix(),
+		SortType:          "leastupdate",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	pulls := make([]*api.TeamReminderPull, 0, len(issues))
+	for _, issue := range issues {
+		if len(pulls) >= maxItemsPerSection {
+			break
+		}
+		if ignoreWIP && issues_model.HasWorkInProgressPrefix(issue.Title) {
+			continue
+		}
+		pull, err := toReminderPull(ctx, issue)
+		if err != nil {
+			return nil, err
+		}
+		pulls = append(pulls, pull)
+	}
+	return pulls, nil
+}
+
+// findFailingChecks returns the default branches whose latest commit has failing commit statuses
+func findFailingChecks(ctx context.Context, repos []*repo_model.Repository) ([]*api.TeamReminderFailingBranch, error) {
+	branches := make([]*api.TeamReminderFailingBranch, 0, 5)
+	for _, repo := range repos {
+		if repo.IsEmpty || len(branches) >= maxItemsPerSection {
+			continue
+		}
+		commitID, err := git.GetBranchCommitID(ctx, repo.RepoPath(), repo.DefaultBranch)
+		if err != nil {
+			log.Debug("Unable to get the default branch of %s: %v", repo.FullName(), err)
+			continue
+		}
+		statuses, _, err := git_model.GetLatestCommitStatus(ctx, repo.ID, commitID, db.ListOptions{ListAll: true})
+		if err != nil {
+			return nil, err
+		}
+		combined := git_model.CalcCommitStatus(statuses)
+		if combined == nil || !(combined.State.IsFailure() || combined.State.IsError()) {
+			continue
+		}
+		branches = append(branches, &api.TeamReminderFailingBranch{
+			Repository: repo.FullName(),
+			Branch:     repo.DefaultBranch,
+			CommitID:   commitID,
+			State:      string(combined.State),
+			TargetURL:  combined.TargetURL,
+		})
+	}
+	return branches, nil
+}
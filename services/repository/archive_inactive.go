@@ -0,0 +1,149 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/container"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/services/mailer"
+
+	"xorm.io/builder"
+)
+
+// ArchivePolicy defines when inactive repositories are archived automatically
+type ArchivePolicy struct {
+	// InactiveMonths without pushes or issue activity before a repository is archived, 0 disables archiving
+	InactiveMonths int
+	// NoticeDays between notifying the owners and archiving the repository
+	NoticeDays int
+	// ExemptTopics are the topics which exempt a repository from being archived
+	ExemptTopics []string
+}
+
+// IsExempt returns if the repository is never archived by the policy
+func (p *ArchivePolicy) IsExempt(repo *repo_model.Repository) bool {
+	if p.InactiveMonths <= 0 {
+		return true
+	}
+	exempt := make(container.Set[string], len(p.ExemptTopics))
+	exempt.AddMultiple(p.ExemptTopics...)
+	for _, topic := range repo.Topics {
+		if exempt.Contains(topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// getArchivePolicy returns the archive policy of the organization, or the instance policy for users and
+// organizations without their own policy
+func getArchivePolicy(ctx context.Context, ownerID int64, instance *ArchivePolicy) (*ArchivePolicy, error) {
+	p, err := organization.GetArchivePolicy(ctx, ownerID)
+	if err != nil || p == nil {
+		return instance, err
+	}
+	return &ArchivePolicy{
+		InactiveMonths: p.InactiveMonths,
+		NoticeDays:     p.NoticeDays,
+		ExemptTopics:   p.ExemptTopics,
+	}, nil
+}
+
+// GetLastRepoActivity returns when the repository was last pushed to or one of its issues was updated
+func GetLastRepoActivity(ctx context.Context, repo *repo_model.Repository) (timeutil.TimeStamp, error) {
+	lastIssue, err := issues_model.GetLastIssueUpdatedUnix(ctx, repo.ID)
+	if err != nil {
+		return 0, err
+	}
+	if lastIssue > repo.UpdatedUnix {
+		return lastIssue, nil
+	}
+	return repo.UpdatedUnix, nil
+}
+
+// ArchiveInactiveRepositories notifies the owners of repositories which have been inactive for longer than the
+// archive policy allows, and archives the repositories which are still inactive once the notice period is over.
+// The instance policy applies to the repositories of users and organizations without their own policy.
+func ArchiveInactiveRepositories(ctx context.Context, instance *ArchivePolicy) error {
+	policies := make(map[int64]*ArchivePolicy)
+	now := time.Now()
+
+	// archived repositories are not excluded by the condition, as archiving them would shift the batches
+	return db.Iterate(ctx, builder.Eq{"is_mirror": false}, func(ctx context.Context, repo *repo_model.Repository) error {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before checking the activity of %s", repo.FullName())
+		default:
+		}
+		if repo.IsArchived {
+			return repo_model.DeleteArchiveNotice(ctx, repo.ID)
+		}
+
+		policy, ok := policies[repo.OwnerID]
+		if !ok {
+			var err error
+			if policy, err = getArchivePolicy(ctx, repo.OwnerID, instance); err != nil {
+				return fmt.Errorf("getArchivePolicy: %w", err)
+			}
+			policies[repo.OwnerID] = policy
+		}
+
+		notice, err := repo_model.GetArchiveNotice(ctx, repo.ID)
+		if err != nil {
+			return fmt.Errorf("GetArchiveNotice: %w", err)
+		}
+		if policy.IsExempt(repo) {
+			if notice != nil {
+				return repo_model.DeleteArchiveNotice(ctx, repo.ID)
+			}
+			return nil
+		}
+
+		lastActivity, err := GetLastRepoActivity(ctx, repo)
+		if err != nil {
+			return fmt.Errorf("GetLastRepoActivity: %w", err)
+		}
+		if lastActivity.AsTime().After(now.AddDate(0, -policy.InactiveMonths, 0)) || (notice != nil && notice.OwnerID != repo.OwnerID) {
+			// the repository became active again or was transferred since its owners were notified
+			if notice != nil {
+				return repo_model.DeleteArchiveNotice(ctx, repo.ID)
+			}
+			return nil
+		}
+
+		if notice == nil {
+			notice = &repo_model.ArchiveNotice{
+				RepoID:           repo.ID,
+				OwnerID:          repo.OwnerID,
+				LastActivityUnix: lastActivity,
+				ArchiveUnix:      timeutil.TimeStamp(now.AddDate(0, 0, policy.NoticeDays).Unix()),
+			}
+			if err := repo_model.CreateArchiveNotice(ctx, notice); err != nil {
+				return fmt.Errorf("CreateArchiveNotice: %w", err)
+			}
+			if err := mailer.SendRepoArchiveNoticeMail(ctx, repo, notice.LastActivityUnix, notice.ArchiveUnix); err != nil {
+				log.Error("Unable to notify the owners of %s about its archival: %v", repo.FullName(), err)
+			}
+			return nil
+		}
+
+		if notice.ArchiveUnix.AsTime().After(now) {
+			return nil
+		}
+		if err := repo_model.SetArchiveRepoState(repo, true); err != nil {
+			return fmt.Errorf("SetArchiveRepoState: %w", err)
+		}
+		log.Info("Archived the inactive repository %s, last activity at %s", repo.FullName(), lastActivity.AsTime())
+		return repo_model.DeleteArchiveNotice(ctx, repo.ID)
+	})
+}
@@ -0,0 +1,45 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArchiveInactiveRepositories(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	// the policy of org 3 disables archiving its repositories
+	assert.NoError(t, organization.SetArchivePolicy(db.DefaultContext, &organization.ArchivePolicy{OrgID: 3}))
+	repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1})
+	repo1.Topics = []string{"keep"}
+	_, err := db.GetEngine(db.DefaultContext).ID(repo1.ID).Cols("topics").Update(repo1)
+	assert.NoError(t, err)
+
+	policy := &ArchivePolicy{InactiveMonths: 12, ExemptTopics: []string{"keep"}}
+	assert.NoError(t, ArchiveInactiveRepositories(db.DefaultContext, policy))
+
+	unittest.AssertNotExistsBean(t, &repo_model.ArchiveNotice{RepoID: 1})
+	unittest.AssertNotExistsBean(t, &repo_model.ArchiveNotice{RepoID: 3})
+	unittest.AssertExistsAndLoadBean(t, &repo_model.ArchiveNotice{RepoID: 2, OwnerID: 2})
+	assert.False(t, unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2}).IsArchived)
+
+	notices, count, err := repo_model.FindArchiveNotices(db.DefaultContext, repo_model.FindArchiveNoticesOptions{OwnerID: 2})
+	assert.NoError(t, err)
+	assert.EqualValues(t, len(notices), count)
+	assert.NotEmpty(t, notices)
+
+	// the notice period is over
+	assert.NoError(t, ArchiveInactiveRepositories(db.DefaultContext, policy))
+	assert.True(t, unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2}).IsArchived)
+	assert.False(t, unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 1}).IsArchived)
+	unittest.AssertNotExistsBean(t, &repo_model.ArchiveNotice{RepoID: 2})
+}
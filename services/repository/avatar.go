@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io"
 	"strconv"
-	"strings"
 
 	"code.gitea.io/gitea/models/db"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -17,7 +16,17 @@ import (
 	"code.gitea.io/gitea/modules/storage"
 )
 
+// removeUnusedAvatar removes a stored avatar unless another repository still uses the same image
+func removeUnusedAvatar(ctx context.Context, avatarPath string) error {
+	used, err := repo_model.ExistsWithAvatarAtStoragePath(ctx, avatarPath)
+	if err != nil || used {
+		return err
+	}
+	return storage.RepoAvatars.Delete(avatarPath)
+}
+
 // UploadAvatar saves custom avatar for repository.
+// The avatar is named after its content, repositories with the same image share the stored file.
 // FIXME: split uploads to different subdirs in case we have massive number of repos.
 func UploadAvatar(ctx context.Context, repo *repo_model.Repository, data []byte) error {
 	avatarData, err := avatar.ProcessAvatarImage(data)
@@ -25,7 +34,7 @@ func UploadAvatar(ctx context.Context, repo *repo_model.Repository, data []byte)
 		return err
 	}
 
-	newAvatar := avatar.HashAvatar(repo.ID, data)
+	newAvatar := avatar.HashAvatarContent(avatarData)
 	if repo.Avatar == newAvatar { // upload the same picture
 		return nil
 	}
@@ -38,8 +47,6 @@ func UploadAvatar(ctx context.Context, repo *repo_model.Repository, data []byte)
 
 	oldAvatarPath := repo.CustomAvatarRelativePath()
 
-	// Users can upload the same image to other repo - prefix it with ID
-	// Then repo will be removed - only it avatar file will be removed
 	repo.Avatar = newAvatar
 	if err := repo_model.UpdateRepositoryCols(ctx, repo, "avatar"); err != nil {
 		return fmt.Errorf("UploadAvatar: Update repository avatar: %w", err)
@@ -53,7 +60,7 @@ func UploadAvatar(ctx context.Context, repo *repo_model.Repository, data []byte)
 	}
 
 	if len(oldAvatarPath) > 0 {
-		if err := storage.RepoAvatars.Delete(oldAvatarPath); err != nil {
+		if err := removeUnusedAvatar(ctx, oldAvatarPath); err != nil {
 			return fmt.Errorf("UploadAvatar: Failed to remove old repo avatar %s: %w", oldAvatarPath, err)
 		}
 	}
@@ -82,7 +89,7 @@ func DeleteAvatar(ctx context.Context, repo *repo_model.Repository) error {
 		return fmt.Errorf("DeleteAvatar: Update repository avatar: %w", err)
 	}
 
-	if err := storage.RepoAvatars.Delete(avatarPath); err != nil {
+	if err := removeUnusedAvatar(ctx, avatarPath); err != nil {
 		return fmt.Errorf("DeleteAvatar: Failed to remove %s: %w", avatarPath, err)
 	}
 
@@ -105,12 +112,8 @@ func RemoveRandomAvatars(ctx context.Context) error {
 	})
 }
 
-// generateAvatar generates the avatar from a template repository
+// generateAvatar generates the avatar from a template repository, the generated repository shares its stored file
 func generateAvatar(ctx context.Context, templateRepo, generateRepo *repo_model.Repository) error {
-	generateRepo.Avatar = strings.Replace(templateRepo.Avatar, strconv.FormatInt(templateRepo.ID, 10), strconv.FormatInt(generateRepo.ID, 10), 1)
-	if _, err := storage.Copy(storage.RepoAvatars, generateRepo.CustomAvatarRelativePath(), storage.RepoAvatars, templateRepo.CustomAvatarRelativePath()); err != nil {
-		return err
-	}
-
+	generateRepo.Avatar = templateRepo.Avatar
 	return repo_model.UpdateRepositoryCols(ctx, generateRepo, "avatar")
 }
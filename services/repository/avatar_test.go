@@ -13,6 +13,7 @@ import (
 	repo_model "code.gitea.io/gitea/models/repo"
 	"code.gitea.io/gitea/models/unittest"
 	"code.gitea.io/gitea/modules/avatar"
+	"code.gitea.io/gitea/modules/storage"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -28,7 +29,9 @@ func TestUploadAvatar(t *testing.T) {
 
 	err := UploadAvatar(db.DefaultContext, repo, buff.Bytes())
 	assert.NoError(t, err)
-	assert.Equal(t, avatar.HashAvatar(10, buff.Bytes()), repo.Avatar)
+	avatarData, err := avatar.ProcessAvatarImage(buff.Bytes())
+	assert.NoError(t, err)
+	assert.Equal(t, avatar.HashAvatarContent(avatarData), repo.Avatar)
 }
 
 func TestUploadBigAvatar(t *testing.T) {
@@ -61,3 +64,28 @@ func TestDeleteAvatar(t *testing.T) {
 
 	assert.Equal(t, "", repo.Avatar)
 }
+
+func TestDeleteSharedAvatar(t *testing.T) {
+	// Generate image
+	myImage := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	var buff bytes.Buffer
+	png.Encode(&buff, myImage)
+
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	repo1 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 10})
+	repo2 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 11})
+
+	assert.NoError(t, UploadAvatar(db.DefaultContext, repo1, buff.Bytes()))
+	assert.NoError(t, UploadAvatar(db.DefaultContext, repo2, buff.Bytes()))
+	assert.Equal(t, repo1.Avatar, repo2.Avatar)
+	avatarPath := repo1.CustomAvatarRelativePath()
+
+	// the stored file is kept while another repository uses it
+	assert.NoError(t, DeleteAvatar(db.DefaultContext, repo1))
+	_, err := storage.RepoAvatars.Stat(avatarPath)
+	assert.NoError(t, err)
+
+	assert.NoError(t, DeleteAvatar(db.DefaultContext, repo2))
+	_, err = storage.RepoAvatars.Stat(avatarPath)
+	assert.Error(t, err)
+}
@@ -0,0 +1,102 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+)
+
+// DeleteExpiredCollaborations removes the collaborations whose expiration date has passed
+func DeleteExpiredCollaborations(ctx context.Context) error {
+	collaborations, err := repo_model.FindExpiredCollaborations(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range collaborations {
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("aborted due to shutdown")
+		default:
+		}
+
+		repo, err := repo_model.GetRepositoryByID(ctx, c.RepoID)
+		if err != nil {
+			return err
+		}
+		if err := models.DeleteCollaboration(repo, c.UserID); err != nil {
+			return fmt.Errorf("delete collaboration of user %d on %s: %w", c.UserID, repo.FullName(), err)
+		}
+		log.Trace("Expired collaboration of user %d on %s removed", c.UserID, repo.FullName())
+	}
+	return nil
+}
+
+// OutsideCollaborator is a collaborator on repositories of an organization who isn't a member of it
+type OutsideCollaborator struct {
+	User           *user_model.User
+	Collaborations []*OutsideCollaboration
+}
+
+// OutsideCollaboration is a repository an outside collaborator has access to
+type OutsideCollaboration struct {
+	Repo          *repo_model.Repository
+	Collaboration *repo_model.Collaboration
+}
+
+// GetOutsideCollaborators returns the collaborators on the repositories of the organization who aren't members of it,
+// together with the repositories they have access to
+func GetOutsideCollaborators(ctx context.Context, orgID int64) ([]*OutsideCollaborator, error) {
+	collaborations, err := repo_model.FindOutsideCollaborations(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]int64, 0, len(collaborations))
+	repoIDs := make([]int64, 0, len(collaborations))
+	for _, c := range collaborations {
+		userIDs = append(userIDs, c.UserID)
+		repoIDs = append(repoIDs, c.RepoID)
+	}
+	userList, err := user_model.GetUsersByIDs(userIDs)
+	if err != nil {
+		return nil, err
+	}
+	users := make(map[int64]*user_model.User, len(userList))
+	for _, u := range userList {
+		users[u.ID] = u
+	}
+	repos, err := repo_model.GetRepositoriesMapByIDs(repoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*OutsideCollaborator, 0, len(users))
+	var current *OutsideCollaborator
+	for _, c := range collaborations {
+		u, ok := users[c.UserID]
+		if !ok {
+			continue
+		}
+		repo, ok := repos[c.RepoID]
+		if !ok {
+			continue
+		}
+		if current == nil || current.User.ID != u.ID {
+			current = &OutsideCollaborator{User: u}
+			result = append(result, current)
+		}
+		current.Collaborations = append(current.Collaborations, &OutsideCollaboration{
+			Repo:          repo,
+			Collaboration: c,
+		})
+	}
+	return result, nil
+}
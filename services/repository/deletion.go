@@ -0,0 +1,98 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// SoftDeleteRepository deletes a repository on behalf of a user. With a retention of deleted repositories,
+// the repository is only hidden until the retention expires and can be restored in the meantime.
+func SoftDeleteRepository(ctx context.Context, doer *user_model.User, repo *repo_model.Repository) error {
+	// a held repository can't be deleted, so it isn't hidden either
+	if err := issues_model.CheckRepositoryLegalHold(ctx, repo.ID); err != nil {
+		return err
+	}
+	if setting.Repository.DeletionRetention <= 0 || repo.IsBeingCreated() {
+		return DeleteRepository(ctx, doer, repo, true)
+	}
+	return repo_model.MarkRepositoryPendingDeletion(ctx, repo, doer.ID, timeutil.TimeStampNow().AddDuration(setting.Repository.DeletionRetention))
+}
+
+// CanRestoreRepository returns true if the user may restore or purge a deleted repository:
+// its owner, an owner of its organization or a site administrator
+func CanRestoreRepository(ctx context.Context, doer *user_model.User, repo *repo_model.Repository) (bool, error) {
+	if doer.IsAdmin || repo.OwnerID == doer.ID {
+		return true, nil
+	}
+	if err := repo.LoadOwner(ctx); err != nil {
+		return false, err
+	}
+	if !repo.Owner.IsOrganization() {
+		return false, nil
+	}
+	return organization.IsOrganizationOwner(ctx, repo.OwnerID, doer.ID)
+}
+
+// RestoreRepository restores a deleted repository which wasn't purged yet
+func RestoreRepository(ctx context.Context, repo *repo_model.Repository) error {
+	return repo_model.RestoreRepositoryPendingDeletion(ctx, repo)
+}
+
+// PurgeExpiredRepositories deletes the repositories whose retention expired
+func PurgeExpiredRepositories(ctx context.Context) error {
+	deletions, err := repo_model.FindExpiredRepoDeletions(ctx)
+	if err != nil {
+		return err
+	}
+	for _, d := range deletions {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before purging the deleted repository %d", d.RepoID)
+		default:
+		}
+
+		repo, err := repo_model.GetRepositoryByID(ctx, d.RepoID)
+		if err != nil {
+			log.Error("Unable to get the deleted repository %d: %v", d.RepoID, err)
+			continue
+		}
+		deleter, err := user_model.GetPossibleUserByID(ctx, d.DeleterID)
+		if err != nil {
+			deleter = user_model.NewGhostUser()
+		}
+		if err := DeleteRepository(ctx, deleter, repo, true); err != nil {
+			// a repository which can't be purged must not stop the other ones
+			log.Error("Unable to purge the deleted repository %-v: %v", repo, err)
+		}
+	}
+	return nil
+}
+
+// FindRestorableRepoDeletions returns the deleted repositories of the user and of the organizations the user owns
+func FindRestorableRepoDeletions(ctx context.Context, doer *user_model.User) ([]*repo_model.RepoDeletion, error) {
+	orgs, err := organization.FindOrgs(organization.FindOrgOptions{UserID: doer.ID, IncludePrivate: true})
+	if err != nil {
+		return nil, err
+	}
+	ownerIDs := []int64{doer.ID}
+	for _, org := range orgs {
+		isOwner, err := organization.IsOrganizationOwner(ctx, org.ID, doer.ID)
+		if err != nil {
+			return nil, err
+		} else if isOwner {
+			ownerIDs = append(ownerIDs, org.ID)
+		}
+	}
+	return repo_model.FindRepoDeletions(ctx, ownerIDs)
+}
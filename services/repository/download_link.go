@@ -0,0 +1,110 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	archiver_service "code.gitea.io/gitea/services/repository/archiver"
+)
+
+// DefaultDownloadLinkExpiry is the validity of download links which are created without an expiry
+const DefaultDownloadLinkExpiry = 7 * 24 * time.Hour
+
+// CreateDownloadLinkOptions describe the attachment or the archive a download link serves
+type CreateDownloadLinkOptions struct {
+	AttachmentID int64
+	// Archive is a ref or commit with the extension of the archive format like "v1.0.zip"
+	Archive string
+	// Expires defaults to DefaultDownloadLinkExpiry from now
+	Expires time.Time
+}
+
+// CreateDownloadLink creates a link to download an attachment or an archive of the repository without authentication
+func CreateDownloadLink(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, gitRepo *git.Repository, opts CreateDownloadLinkOptions) (*repo_model.DownloadLink, error) {
+	if setting.Repository.DownloadLinkMaxExpiry <= 0 {
+		return nil, util.NewPermissionDeniedErrorf("download links are disabled")
+	}
+
+	now := time.Now()
+	if opts.Expires.IsZero() {
+		opts.Expires = now.Add(DefaultDownloadLinkExpiry)
+		if maxExpires := now.Add(setting.Repository.DownloadLinkMaxExpiry); opts.Expires.After(maxExpires) {
+			opts.Expires = maxExpires
+		}
+	} else if !opts.Expires.After(now) {
+		return nil, util.NewInvalidArgumentErrorf("expiry must be in the future")
+	} else if opts.Expires.After(now.Add(setting.Repository.DownloadLinkMaxExpiry)) {
+		return nil, util.NewInvalidArgumentErrorf("download links can't be valid longer than %s", setting.Repository.DownloadLinkMaxExpiry)
+	}
+
+	link := &repo_model.DownloadLink{
+		RepoID:      repo.ID,
+		CreatorID:   doer.ID,
+		Creator:     doer,
+		ExpiresUnix: timeutil.TimeStamp(opts.Expires.Unix()),
+	}
+
+	switch {
+	case opts.AttachmentID > 0 && opts.Archive != "":
+		return nil, util.NewInvalidArgumentErrorf("a download link serves either an attachment or an archive")
+	case opts.AttachmentID > 0:
+		attach, err := repo_model.GetAttachmentByID(ctx, opts.AttachmentID)
+		if err != nil {
+			if repo_model.IsErrAttachmentNotExist(err) {
+				return nil, util.NewInvalidArgumentErrorf("attachment %d does not exist", opts.AttachmentID)
+			}
+			return nil, err
+		}
+		if attach.RepoID != repo.ID {
+			return nil, util.NewInvalidArgumentErrorf("attachment %d does not belong to the repository", opts.AttachmentID)
+		}
+		link.AttachmentID = attach.ID
+	case opts.Archive != "":
+		if setting.Repository.DisableDownloadSourceArchives {
+			return nil, util.NewPermissionDeniedErrorf("source archives are disabled")
+		}
+		aReq, err := archiver_service.NewRequest(repo.ID, gitRepo, opts.Archive)
+		if err != nil {
+			if errors.Is(err, archiver_service.ErrUnknownArchiveFormat{}) ||
+				errors.Is(err, archiver_service.RepoRefNotFoundError{}) || git.IsErrNotExist(err) {
+				return nil, util.NewInvalidArgumentErrorf("invalid archive %q: %v", opts.Archive, err)
+			}
+			return nil, err
+		}
+		link.ArchiveName = aReq.GetArchiveName()
+		link.ArchiveType = aReq.Type
+		link.CommitID = aReq.CommitID
+	default:
+		return nil, util.NewInvalidArgumentErrorf("a download link needs an attachment or an archive")
+	}
+
+	if err := repo_model.CreateDownloadLink(ctx, link); err != nil {
+		return nil, err
+	}
+	log.Info("User %s created download link %d of repository %s valid until %s", doer.Name, link.ID, repo.FullName(), opts.Expires.Format(time.RFC3339))
+	return link, nil
+}
+
+// RevokeDownloadLink revokes a download link of the repository
+func RevokeDownloadLink(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, id int64) (*repo_model.DownloadLink, error) {
+	link, err := repo_model.GetDownloadLinkByID(ctx, repo.ID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := repo_model.RevokeDownloadLink(ctx, link, doer.ID); err != nil {
+		return nil, err
+	}
+	log.Info("User %s revoked download link %d of repository %s", doer.Name, link.ID, repo.FullName())
+	return link, nil
+}
@@ -7,6 +7,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"code.gitea.io/gitea/models"
 	repo_model "code.gitea.io/gitea/models/repo"
@@ -14,6 +15,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/log"
 	"code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
 	"code.gitea.io/gitea/services/pull"
 )
 
@@ -65,26 +67,9 @@ func CherryPick(ctx context.Context, repo *repo_model.Repository, doer *user_mod
 	if err != nil {
 		return nil, err
 	}
-	parent, err := commit.ParentID(0)
-	if err != nil {
-		parent = git.MustIDFromString(git.EmptyTreeSHA)
-	}
-
-	base, right := parent.String(), commit.ID.String()
 
-	if revert {
-		right, base = base, right
-	}
-
-	description := fmt.Sprintf("CherryPick %s onto %s", right, opts.OldBranch)
-	conflict, _, err := pull.AttemptThreeWayMerge(ctx,
-		t.basePath, t.gitRepo, base, opts.LastCommitID, right, description)
-	if err != nil {
-		return nil, fmt.Errorf("failed to three-way merge %s onto %s: %w", right, opts.OldBranch, err)
-	}
-
-	if conflict {
-		return nil, fmt.Errorf("failed to merge due to conflicts")
+	if err := t.cherryPickCommit(commit, 1, revert, opts.LastCommitID, opts.OldBranch); err != nil {
+		return nil, err
 	}
 
 	treeHash, err := t.WriteTree()
@@ -123,3 +108,215 @@ func CherryPick(ctx context.Context, repo *repo_model.Repository, doer *user_mod
 
 	return fileResponse, nil
 }
+
+// cherryPickCommit applies the changes of a commit, or their reverse, onto head in the index of the temporary repository.
+// For merge commits mainline is the 1-based number of the parent the changes are taken relative to.
+func (t *TemporaryUploadRepository) cherryPickCommit(commit *git.Commit, mainline int, revert bool, head, branch string) error {
+	parentIdx := 0
+	if commit.ParentCount() > 1 {
+		if mainline < 1 || mainline > commit.ParentCount() {
+			return models.ErrInvalidMainline{CommitSHA: commit.ID.String(), Parents: commit.ParentCount(), Mainline: mainline}
+		}
+		parentIdx = mainline - 1
+	} else if mainline > 1 {
+		return models.ErrInvalidMainline{CommitSHA: commit.ID.String(), Parents: commit.ParentCount(), Mainline: mainline}
+	}
+
+	parent, err := commit.ParentID(parentIdx)
+	if err != nil {
+		parent = git.MustIDFromString(git.EmptyTreeSHA)
+	}
+
+	base, right := parent.String(), commit.ID.String()
+
+	if revert {
+		right, base = base, right
+	}
+
+	description := fmt.Sprintf("CherryPick %s onto %s", right, branch)
+	conflict, conflictedFiles, err := pull.AttemptThreeWayMerge(t.ctx,
+		t.basePath, t.gitRepo, base, head, right, description)
+	if err != nil {
+		return fmt.Errorf("failed to three-way merge %s onto %s: %w", right, branch, err)
+	}
+
+	if conflict {
+		return models.ErrCherryPickConflicts{CommitSHA: commit.ID.String(), Files: conflictedFiles}
+	}
+	return nil
+}
+
+// MaxCherryPickRangeCommits is the maximal number of commits which can be cherry-picked or reverted at once
+const MaxCherryPickRangeCommits = 250
+
+// CherryPickRangeOptions holds the options to cherry-pick or revert a range of commits
+type CherryPickRangeOptions struct {
+	LastCommitID string
+	OldBranch    string
+	NewBranch    string
+	// From is the exclusive start of the range, if empty only To is picked
+	From string
+	// To is the inclusive end of the range
+	To string
+	// Mainline is the 1-based parent number merge commits are picked relative to.
+	// If set, only the first-parent history of the range is picked.
+	Mainline int
+	// SkipMerges leaves out the merge commits of the range
+	SkipMerges bool
+	Author     *IdentityOptions
+	Committer  *IdentityOptions
+	Dates      *CommitDateOptions
+	Signoff    bool
+}
+
+// CherryPickRange cherry-picks or reverts a range of commits onto a branch, creating one commit per picked commit.
+// Cherry-picks keep the author of the original commits unless another author is given.
+func CherryPickRange(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, revert bool, opts *CherryPickRangeOptions) ([]*structs.FileCommitResponse, error) {
+	patchOpts := &ApplyDiffPatchOptions{
+		LastCommitID: opts.LastCommitID,
+		OldBranch:    opts.OldBranch,
+		NewBranch:    opts.NewBranch,
+	}
+	if err := patchOpts.Validate(ctx, repo, doer); err != nil {
+		return nil, err
+	}
+	opts.OldBranch, opts.NewBranch = patchOpts.OldBranch, patchOpts.NewBranch
+
+	author, committer := GetAuthorAndCommitterUsers(opts.Author, opts.Committer, doer)
+	keepAuthor := !revert && (opts.Author == nil || opts.Author.Email == "")
+
+	t, err := NewTemporaryUploadRepository(ctx, repo)
+	if err != nil {
+		return nil, err
+	}
+	defer t.Close()
+	if err := t.Clone(opts.OldBranch); err != nil {
+		return nil, err
+	}
+	if err := t.SetDefaultIndex(); err != nil {
+		return nil, err
+	}
+
+	head, err := t.GetBranchCommit(opts.OldBranch)
+	if err != nil {
+		return nil, err
+	}
+	if opts.LastCommitID != "" {
+		lastCommitID, err := t.gitRepo.ConvertToSHA1(opts.LastCommitID)
+		if err != nil {
+			return nil, fmt.Errorf("CherryPickRange: Invalid last commit ID: %w", err)
+		}
+		if head.ID != lastCommitID {
+			return nil, models.ErrCommitIDDoesNotMatch{
+				GivenCommitID:   lastCommitID.String(),
+				CurrentCommitID: head.ID.String(),
+			}
+		}
+	}
+
+	commits, err := t.commitRange(opts.From, opts.To, opts.Mainline > 0, opts.SkipMerges)
+	if err != nil {
+		return nil, err
+	}
+	if !revert {
+		// rev-list lists the newest commit first, but cherry-picks must be applied oldest first
+		for i, j := 0, len(commits)-1; i < j; i, j = i+1, j-1 {
+			commits[i], commits[j] = commits[j], commits[i]
+		}
+	}
+
+	headID := head.ID.String()
+	picked := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		if err := t.cherryPickCommit(commit, opts.Mainline, revert, headID, opts.OldBranch); err != nil {
+			return nil, err
+		}
+		treeHash, err := t.WriteTree()
+		if err != nil {
+			return nil, err
+		}
+
+		var message string
+		if revert {
+			message = fmt.Sprintf("Revert \"%s\"\n\nThis reverts commit %s.", commit.Summary(), commit.ID.String())
+		} else {
+			message = fmt.Sprintf("%s\n\n(cherry picked from commit %s)", strings.TrimSpace(commit.CommitMessage), commit.ID.String())
+		}
+
+		commitAuthor, authorDate, committerDate := author, time.Now(), time.Now()
+		if keepAuthor {
+			commitAuthor = &user_model.User{FullName: commit.Author.Name, Email: commit.Author.Email}
+			authorDate = commit.Author.When
+		}
+		if opts.Dates != nil {
+			authorDate, committerDate = opts.Dates.Author, opts.Dates.Committer
+		}
+
+		headID, err = t.CommitTreeWithDate(headID, commitAuthor, committer, treeHash, message, opts.Signoff, authorDate, committerDate)
+		if err != nil {
+			return nil, err
+		}
+		picked = append(picked, headID)
+	}
+
+	if err := t.Push(doer, headID, opts.NewBranch); err != nil {
+		return nil, err
+	}
+
+	responses := make([]*structs.FileCommitResponse, 0, len(picked))
+	for _, id := range picked {
+		commit, err := t.GetCommit(id)
+		if err != nil {
+			return nil, err
+		}
+		response, err := GetFileCommitResponse(repo, commit)
+		if err != nil {
+			return nil, err
+		}
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+// commitRange returns the commits of the range from..to, newest first
+func (t *TemporaryUploadRepository) commitRange(from, to string, firstParent, noMerges bool) ([]*git.Commit, error) {
+	toCommit, err := t.GetCommit(strings.TrimSpace(to))
+	if err != nil {
+		return nil, err
+	}
+	if from == "" {
+		return []*git.Commit{toCommit}, nil
+	}
+	fromCommit, err := t.GetCommit(strings.TrimSpace(from))
+	if err != nil {
+		return nil, err
+	}
+
+	cmd := git.NewCommand(t.ctx, "rev-list")
+	if firstParent {
+		cmd.AddArguments("--first-parent")
+	}
+	if noMerges {
+		cmd.AddArguments("--no-merges")
+	}
+	stdout, _, err := cmd.AddDynamicArguments(fromCommit.ID.String() + ".." + toCommit.ID.String()).RunStdString(&git.RunOpts{Dir: t.basePath})
+	if err != nil {
+		return nil, err
+	}
+
+	ids := strings.Fields(stdout)
+	if len(ids) == 0 {
+		return nil, util.NewInvalidArgumentErrorf("no commits in range %s..%s", from, to)
+	} else if len(ids) > MaxCherryPickRangeCommits {
+		return nil, util.NewInvalidArgumentErrorf("range %s..%s has %d commits, at most %d can be picked", from, to, len(ids), MaxCherryPickRangeCommits)
+	}
+	commits := make([]*git.Commit, 0, len(ids))
+	for _, id := range ids {
+		commit, err := t.GetCommit(id)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, commit)
+	}
+	return commits, nil
+}
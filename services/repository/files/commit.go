@@ -14,6 +14,7 @@ import (
 	"code.gitea.io/gitea/modules/git"
 	"code.gitea.io/gitea/modules/structs"
 	"code.gitea.io/gitea/services/automerge"
+	"code.gitea.io/gitea/services/mergequeue"
 )
 
 // CreateCommitStatus creates a new CommitStatus given a bunch of parameters
@@ -32,7 +33,7 @@ func CreateCommitStatus(ctx context.Context, repo *repo_model.Repository, creato
 	if commit, err := gitRepo.GetCommit(sha); err != nil {
 		gitRepo.Close()
 		return fmt.Errorf("GetCommit[%s]: %w", sha, err)
-	} else if len(sha) != git.SHAFullLength {
+	} else if !git.IsFullSHA(sha) {
 		// use complete commit sha
 		sha = commit.ID.String()
 	}
@@ -53,6 +54,12 @@ func CreateCommitStatus(ctx context.Context, repo *repo_model.Repository, creato
 		}
 	}
 
+	if !status.State.IsPending() {
+		if err := mergequeue.HandleCommitStatus(ctx, repo, sha); err != nil {
+			return fmt.Errorf("HandleCommitStatus[repo_id: %d, user_id: %d, sha: %s]: %w", repo.ID, creator.ID, sha, err)
+		}
+	}
+
 	return nil
 }
 
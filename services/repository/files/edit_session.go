@@ -0,0 +1,381 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"code.gitea.io/gitea/models"
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrEditSessionConflict represents a "EditSessionConflict" kind of error, the changes of an editing
+// session can't be rebased onto the current commit of its base branch
+type ErrEditSessionConflict struct {
+	Files []string
+}
+
+// IsErrEditSessionConflict checks if an error is a ErrEditSessionConflict.
+func IsErrEditSessionConflict(err error) bool {
+	_, ok := err.(ErrEditSessionConflict)
+	return ok
+}
+
+func (err ErrEditSessionConflict) Error() string {
+	return fmt.Sprintf("edit session conflicts with its base branch [files: %s]", strings.Join(err.Files, ", "))
+}
+
+// EditSessionChange describes a change of a file in an editing session
+type EditSessionChange struct {
+	// FromTreePath is the path of the file before it was moved, empty to keep it
+	FromTreePath string
+	TreePath     string
+	// Content is the new content of the file, nil to delete it
+	Content   *string
+	IsNewFile bool
+}
+
+// FinishEditSessionOptions describes the commit the changes of an editing session are squashed into
+type FinishEditSessionOptions struct {
+	Message string
+	// NewBranch is the branch the commit is pushed to, empty to push it to the base branch
+	NewBranch string
+	Signoff   bool
+}
+
+// StartEditSession starts an editing session of a user for a branch, or returns the running one
+func StartEditSession(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, doer *user_model.User, branch string) (*repo_model.EditSession, error) {
+	s, err := repo_model.GetUserEditSession(ctx, repo.ID, doer.ID, branch)
+	if err != nil || s != nil {
+		return s, err
+	}
+
+	commitID, err := gitRepo.GetBranchCommitID(branch)
+	if err != nil {
+		return nil, err
+	}
+	s = &repo_model.EditSession{
+		RepoID:       repo.ID,
+		UserID:       doer.ID,
+		BaseBranch:   branch,
+		BaseCommitID: commitID,
+		HeadCommitID: commitID,
+	}
+	return s, db.WithTx(ctx, func(ctx context.Context) error {
+		if err := repo_model.CreateEditSession(ctx, s); err != nil {
+			return err
+		}
+		return gitRepo.SetReference(s.RefName(), commitID)
+	})
+}
+
+// ApplyEditSessionChange commits a change to the ref of an editing session
+func ApplyEditSessionChange(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, s *repo_model.EditSession, change *EditSessionChange) error {
+	treePath := CleanUploadFileName(change.TreePath)
+	if treePath == "" {
+		return models.ErrFilenameInvalid{Path: change.TreePath}
+	}
+	fromTreePath := CleanUploadFileName(change.FromTreePath)
+	if fromTreePath == "" {
+		fromTreePath = treePath
+	}
+
+	t, err := NewTemporaryUploadRepository(ctx, repo)
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+	if err := t.Clone(s.BaseBranch); err != nil {
+		return err
+	}
+	if err := t.setIndex(s.HeadCommitID); err != nil {
+		return err
+	}
+	head, err := t.GetCommit(s.HeadCommitID)
+	if err != nil {
+		return err
+	}
+
+	mode := "100644"
+	entry, err := head.GetTreeEntryByPath(fromTreePath)
+	if err != nil && !git.IsErrNotExist(err) {
+		return err
+	}
+	if entry == nil && !change.IsNewFile {
+		return models.ErrRepoFileDoesNotExist{Path: fromTreePath}
+	}
+	if change.IsNewFile || fromTreePath != treePath {
+		if existing, _ := head.GetTreeEntryByPath(treePath); existing != nil {
+			return models.ErrRepoFileAlreadyExists{Path: treePath}
+		}
+	}
+	if entry != nil {
+		if entry.IsDir() {
+			return models.ErrFilePathInvalid{
+				Message: fmt.Sprintf("a directory exists where you’re trying to create a file: %s", fromTreePath),
+				Path:    fromTreePath,
+				Name:    path.Base(fromTreePath),
+				Type:    git.EntryModeTree,
+			}
+		}
+		if entry.IsExecutable() {
+			mode = "100755"
+		}
+		if fromTreePath != treePath || change.Content == nil {
+			if err := t.RemoveFilesFromIndex(fromTreePath); err != nil {
+				return err
+			}
+		}
+	}
+
+	var message string
+	if change.Content != nil {
+		objectHash, err := t.HashObject(strings.NewReader(*change.Content))
+		if err != nil {
+			return err
+		}
+		if err := t.AddObjectToIndex(mode, objectHash, treePath); err != nil {
+			return err
+		}
+		message = "Update " + treePath
+	} else {
+		message = "Delete " + fromTreePath
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return err
+	}
+	commitHash, err := t.CommitTree(s.HeadCommitID, doer, doer, treeHash, message, false)
+	if err != nil {
+		return err
+	}
+	if err := t.pushEditSession(doer, s, commitHash, false); err != nil {
+		return err
+	}
+
+	s.HeadCommitID = commitHash
+	s.NumEdits++
+	return repo_model.UpdateEditSession(ctx, s)
+}
+
+// GetEditSessionChangedFiles returns the paths of the files changed in an editing session
+func GetEditSessionChangedFiles(gitRepo *git.Repository, s *repo_model.EditSession) ([]string, error) {
+	if !s.HasChanges() {
+		return nil, nil
+	}
+	return gitRepo.GetFilesChangedBetween(s.BaseCommitID, s.HeadCommitID)
+}
+
+// IsEditSessionOutdated returns if the base branch of an editing session has moved since it was started or rebased
+func IsEditSessionOutdated(gitRepo *git.Repository, s *repo_model.EditSession) (bool, error) {
+	commitID, err := gitRepo.GetBranchCommitID(s.BaseBranch)
+	if err != nil {
+		return false, err
+	}
+	return commitID != s.BaseCommitID, nil
+}
+
+// RebaseEditSession rebases the changes of an editing session onto the current commit of its base branch,
+// ErrEditSessionConflict is returned if they conflict with the changes of the base branch
+func RebaseEditSession(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, s *repo_model.EditSession) error {
+	t, err := NewTemporaryUploadRepository(ctx, repo)
+	if err != nil {
+		return err
+	}
+	defer t.Close()
+	if err := t.Clone(s.BaseBranch); err != nil {
+		return err
+	}
+	if err := t.rebaseEditSession(doer, s); err != nil {
+		return err
+	}
+	return repo_model.UpdateEditSession(ctx, s)
+}
+
+func (t *TemporaryUploadRepository) rebaseEditSession(doer *user_model.User, s *repo_model.EditSession) error {
+	baseCommitID, err := t.GetLastCommit()
+	if err != nil {
+		return err
+	}
+	if baseCommitID == s.BaseCommitID {
+		return nil
+	}
+	if !s.HasChanges() {
+		s.BaseCommitID = baseCommitID
+		s.HeadCommitID = baseCommitID
+		return t.pushEditSession(doer, s, baseCommitID, true)
+	}
+
+	patch := new(bytes.Buffer)
+	if err := git.NewCommand(t.ctx, "diff", "--binary", "--full-index").AddDynamicArguments(s.BaseCommitID, s.HeadCommitID).
+		Run(&git.RunOpts{Dir: t.basePath, Stdout: patch}); err != nil {
+		return fmt.Errorf("unable to diff edit session %d: %w", s.ID, err)
+	}
+	if err := t.SetDefaultIndex(); err != nil {
+		return err
+	}
+
+	cmdApply := git.NewCommand(t.ctx, "apply", "--cached", "--binary")
+	if git.CheckGitVersionAtLeast("2.32") == nil {
+		cmdApply.AddArguments("--3way")
+	}
+	stderr := new(strings.Builder)
+	if err := cmdApply.Run(&git.RunOpts{Dir: t.basePath, Stdin: patch, Stderr: stderr}); err != nil {
+		log.Debug("Unable to rebase edit session %d of %s: %v\nStderr: %s", s.ID, t.repo.FullName(), err, stderr.String())
+		return ErrEditSessionConflict{Files: t.unmergedFiles()}
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return err
+	}
+	commitHash, err := t.CommitTree(baseCommitID, doer, doer, treeHash, fmt.Sprintf("Rebase onto %s", baseCommitID), false)
+	if err != nil {
+		return err
+	}
+	if err := t.pushEditSession(doer, s, commitHash, true); err != nil {
+		return err
+	}
+	s.BaseCommitID = baseCommitID
+	s.HeadCommitID = commitHash
+	return nil
+}
+
+// unmergedFiles returns the paths of the files with conflicts in the index
+func (t *TemporaryUploadRepository) unmergedFiles() []string {
+	stdout, _, err := git.NewCommand(t.ctx, "ls-files", "-u", "-z").RunStdString(&git.RunOpts{Dir: t.basePath})
+	if err != nil {
+		return nil
+	}
+	// every line is "<mode> <object> <stage>\t<path>", with a line for every stage of a file
+	files := make([]string, 0, 5)
+	for _, line := range strings.Split(stdout, "\000") {
+		_, file, ok := strings.Cut(line, "\t")
+		if ok && (len(files) == 0 || files[len(files)-1] != file) {
+			files = append(files, file)
+		}
+	}
+	return files
+}
+
+// FinishEditSession squashes the changes of an editing session into a single commit and deletes the session.
+// The session is rebased first if its base branch has moved. It returns the branch the commit was pushed to.
+func FinishEditSession(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository, doer *user_model.User, s *repo_model.EditSession, opts *FinishEditSessionOptions) (string, error) {
+	if !s.HasChanges() {
+		return "", util.NewInvalidArgumentErrorf("the edit session has no changes")
+	}
+	branch := s.BaseBranch
+	if opts.NewBranch != "" {
+		branch = opts.NewBranch
+		if gitRepo.IsBranchExist(branch) {
+			return "", models.ErrBranchAlreadyExists{BranchName: branch}
+		}
+	}
+
+	t, err := NewTemporaryUploadRepository(ctx, repo)
+	if err != nil {
+		return "", err
+	}
+	defer t.Close()
+	if err := t.Clone(s.BaseBranch); err != nil {
+		return "", err
+	}
+	// a conflicting session is kept, so the user can resolve the conflicts
+	baseCommitID := s.BaseCommitID
+	if err := t.rebaseEditSession(doer, s); err != nil {
+		return "", err
+	}
+	if s.BaseCommitID != baseCommitID {
+		if err := repo_model.UpdateEditSession(ctx, s); err != nil {
+			return "", err
+		}
+	}
+
+	treeHash, err := t.GetLastCommitByRef(s.HeadCommitID + "^{tree}")
+	if err != nil {
+		return "", err
+	}
+	commitHash, err := t.CommitTree(s.BaseCommitID, doer, doer, treeHash, strings.TrimSpace(opts.Message), opts.Signoff)
+	if err != nil {
+		return "", err
+	}
+	if err := t.Push(doer, commitHash, branch); err != nil {
+		return "", err
+	}
+
+	return branch, DiscardEditSession(ctx, gitRepo, s)
+}
+
+// DiscardEditSession deletes an editing session with its changes
+func DiscardEditSession(ctx context.Context, gitRepo *git.Repository, s *repo_model.EditSession) error {
+	if err := repo_model.DeleteEditSession(ctx, s.ID); err != nil {
+		return err
+	}
+	if err := gitRepo.RemoveReference(s.RefName()); err != nil {
+		log.Error("Unable to remove the ref %s: %v", s.RefName(), err)
+	}
+	return nil
+}
+
+// DeleteExpiredEditSessions deletes the editing sessions which weren't changed within their lifetime
+func DeleteExpiredEditSessions(ctx context.Context) error {
+	for {
+		sessions, err := repo_model.FindExpiredEditSessions(ctx, 100)
+		if err != nil {
+			return err
+		}
+		for _, s := range sessions {
+			select {
+			case <-ctx.Done():
+				return db.ErrCancelledf("before deleting edit session %d", s.ID)
+			default:
+			}
+			if err := repo_model.DeleteEditSession(ctx, s.ID); err != nil {
+				return err
+			}
+			repo, err := repo_model.GetRepositoryByID(ctx, s.RepoID)
+			if err != nil {
+				if !repo_model.IsErrRepoNotExist(err) {
+					log.Error("GetRepositoryByID[%d]: %v", s.RepoID, err)
+				}
+				continue
+			}
+			if err := git.NewCommand(ctx, "update-ref", "-d").AddDynamicArguments(s.RefName()).Run(&git.RunOpts{Dir: repo.RepoPath()}); err != nil {
+				log.Error("Unable to remove the ref %s of %s: %v", s.RefName(), repo.FullName(), err)
+			}
+		}
+		if len(sessions) < 100 {
+			return nil
+		}
+	}
+}
+
+// setIndex sets the git index to the tree of a commit
+func (t *TemporaryUploadRepository) setIndex(commitID string) error {
+	if _, _, err := git.NewCommand(t.ctx, "read-tree").AddDynamicArguments(commitID).RunStdString(&git.RunOpts{Dir: t.basePath}); err != nil {
+		return fmt.Errorf("setIndex: %w", err)
+	}
+	return nil
+}
+
+// pushEditSession pushes a commit to the ref of an editing session, hooks don't run for it
+func (t *TemporaryUploadRepository) pushEditSession(doer *user_model.User, s *repo_model.EditSession, commitHash string, force bool) error {
+	return git.Push(t.ctx, t.basePath, git.PushOptions{
+		Remote: t.repo.RepoPath(),
+		Branch: commitHash + ":" + s.RefName(),
+		Force:  force,
+		Env:    repo_module.InternalPushingEnvironment(doer, t.repo),
+	})
+}
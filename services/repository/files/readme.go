@@ -0,0 +1,176 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package files
+
+import (
+	"context"
+	"path"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/base"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// localizedExtensions prepends the provided language code with and without a
+// regional identifier to the provided extension.
+// Note: the language code will always be lower-cased, if a region is present it must be separated with a `-`
+// Note: ext should be prefixed with a `.`
+func localizedExtensions(ext, languageCode string) (localizedExts []string) {
+	if len(languageCode) < 1 {
+		return []string{ext}
+	}
+
+	lowerLangCode := "." + strings.ToLower(languageCode)
+
+	if strings.Contains(lowerLangCode, "-") {
+		underscoreLangCode := strings.ReplaceAll(lowerLangCode, "-", "_")
+		indexOfDash := strings.Index(lowerLangCode, "-")
+		// e.g. [.zh-cn.md, .zh_cn.md, .zh.md, _zh.md, .md]
+		return []string{lowerLangCode + ext, underscoreLangCode + ext, lowerLangCode[:indexOfDash] + ext, "_" + lowerLangCode[1:indexOfDash] + ext, ext}
+	}
+
+	// e.g. [.en.md, .md]
+	return []string{lowerLangCode + ext, ext}
+}
+
+// readmeFileIndex returns the priority of a README file, lower is preferred.
+// Files are ordered by the configured file names first and then by the extensions.
+func readmeFileIndex(name string, exts []string) (int, bool) {
+	for i, fileName := range setting.Repository.Readme.FileNames {
+		if idx, ok := util.IsFileNameWithExtension(name, fileName, exts...); ok {
+			return i*(len(exts)+1) + idx, true
+		}
+	}
+	return 0, false
+}
+
+// IsReadmeFileName reports whether name looks like a README file based on the configured file names
+func IsReadmeFileName(name string) bool {
+	_, ok := readmeFileIndex(name, []string{""})
+	return ok
+}
+
+// FindReadmeFileInEntries locates the README of a tree given its entries.
+// A variant for the language, like README.zh-cn.md, is preferred. If the tree has no README and
+// tryWellKnownDirs is set, the configured documentation directories, like docs/, are searched as well.
+// It returns the subfolder the README was found in.
+func FindReadmeFileInEntries(entries []*git.TreeEntry, language string, tryWellKnownDirs bool) (string, *git.TreeEntry, error) {
+	// Create a list of extensions in priority order
+	// 1. Markdown files - with and without localisation - e.g. README.en-us.md or README.md
+	// 2. Txt files - e.g. README.txt
+	// 3. No extension - e.g. README
+	exts := append(localizedExtensions(".md", language), ".txt", "") // sorted by priority
+	readmeFiles := make([]*git.TreeEntry, len(setting.Repository.Readme.FileNames)*(len(exts)+1))
+
+	docsDirs := setting.Repository.Readme.Directories
+	docsEntries := make([]*git.TreeEntry, len(docsDirs)) // one per documentation directory, e.g. docs/, .gitea/ or .github/
+	for _, entry := range entries {
+		if tryWellKnownDirs && entry.IsDir() {
+			// as a special case for the top-level repo introduction README,
+			// fall back to subfolders, looking for e.g. docs/README.md, .gitea/README.zh-CN.txt, .github/README.txt, ...
+			for i, dir := range docsDirs {
+				if strings.EqualFold(entry.Name(), dir) && (entry.Name() == dir || docsEntries[i] == nil) {
+					docsEntries[i] = entry
+				}
+			}
+			continue
+		}
+		if i, ok := readmeFileIndex(entry.Name(), exts); ok {
+			log.Debug("Potential readme file: %s", entry.Name())
+			if readmeFiles[i] == nil || base.NaturalSortLess(readmeFiles[i].Name(), entry.Blob().Name()) {
+				if entry.IsLink() {
+					target, err := entry.FollowLinks()
+					if err != nil && !git.IsErrBadLink(err) {
+						return "", nil, err
+					} else if target != nil && (target.IsExecutable() || target.IsRegular()) {
+						readmeFiles[i] = entry
+					}
+				} else {
+					readmeFiles[i] = entry
+				}
+			}
+		}
+	}
+	for _, f := range readmeFiles {
+		if f != nil {
+			return "", f, nil
+		}
+	}
+
+	if !tryWellKnownDirs {
+		return "", nil, nil
+	}
+	for _, subTreeEntry := range docsEntries {
+		if subTreeEntry == nil {
+			continue
+		}
+		subTree := subTreeEntry.Tree()
+		if subTree == nil {
+			// this should be impossible; if subTreeEntry exists so should this.
+			continue
+		}
+		childEntries, err := subTree.ListEntries()
+		if err != nil {
+			return "", nil, err
+		}
+
+		subfolder, readmeFile, err := FindReadmeFileInEntries(childEntries, language, false)
+		if err != nil && !git.IsErrNotExist(err) {
+			return "", nil, err
+		}
+		if readmeFile != nil {
+			return path.Join(subTreeEntry.Name(), subfolder), readmeFile, nil
+		}
+	}
+	return "", nil, nil
+}
+
+// FindReadme locates the README of a directory of a commit, documentation directories are
+// only searched for the root directory. It returns the path of the README.
+func FindReadme(commit *git.Commit, treePath, language string) (string, *git.TreeEntry, error) {
+	tree, err := commit.SubTree(treePath)
+	if err != nil {
+		return "", nil, err
+	}
+	entries, err := tree.ListEntries()
+	if err != nil {
+		return "", nil, err
+	}
+	subfolder, readmeFile, err := FindReadmeFileInEntries(entries, language, treePath == "")
+	if err != nil || readmeFile == nil {
+		return "", nil, err
+	}
+	return path.Join(treePath, subfolder, readmeFile.Name()), readmeFile, nil
+}
+
+// GetReadme returns the contents of the README of a directory at the given ref
+func GetReadme(ctx context.Context, repo *repo_model.Repository, treePath, ref, language string) (*api.ContentsResponse, error) {
+	if ref == "" {
+		ref = repo.DefaultBranch
+	}
+	treePath = CleanUploadFileName(treePath)
+
+	gitRepo, closer, err := git.RepositoryFromContextOrOpen(ctx, repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer closer.Close()
+
+	commit, err := gitRepo.GetCommit(ref)
+	if err != nil {
+		return nil, err
+	}
+	readmePath, readmeFile, err := FindReadme(commit, treePath, language)
+	if err != nil {
+		return nil, err
+	} else if readmeFile == nil {
+		return nil, git.ErrNotExist{ID: ref, RelPath: treePath}
+	}
+	return GetContents(ctx, repo, readmePath, ref, false)
+}
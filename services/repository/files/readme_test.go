@@ -2,11 +2,15 @@
 // Copyright 2014 The Gogs Authors. All rights reserved.
 // SPDX-License-Identifier: MIT
 
-package repo
+package files
 
 import (
 	"reflect"
 	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func Test_localizedExtensions(t *testing.T) {
@@ -60,3 +64,23 @@ func Test_localizedExtensions(t *testing.T) {
 		})
 	}
 }
+
+func TestIsReadmeFileName(t *testing.T) {
+	oldFileNames := setting.Repository.Readme.FileNames
+	setting.Repository.Readme.FileNames = []string{"README", "INDEX"}
+	defer func() {
+		setting.Repository.Readme.FileNames = oldFileNames
+	}()
+
+	assert.True(t, IsReadmeFileName("README.md"))
+	assert.True(t, IsReadmeFileName("index.zh-cn.md"))
+	assert.True(t, IsReadmeFileName("Index"))
+	assert.False(t, IsReadmeFileName("indexes.md"))
+	assert.False(t, IsReadmeFileName("LICENSE"))
+
+	// files are ordered by the configured names before their extension
+	exts := localizedExtensions(".md", "zh-CN")
+	readme, _ := readmeFileIndex("README", exts)
+	index, _ := readmeFileIndex("INDEX.zh-cn.md", exts)
+	assert.Less(t, readme, index)
+}
@@ -428,7 +428,7 @@ func CreateOrUpdateRepoFile(ctx context.Context, repo *repo_model.Repository, do
 		if err != nil {
 			return nil, err
 		}
-		contentStore := lfs.NewContentStore()
+		contentStore := lfs.NewContentStoreOfRegion(repo.StorageRegion())
 		exist, err := contentStore.Exists(lfsMetaObject.Pointer)
 		if err != nil {
 			return nil, err
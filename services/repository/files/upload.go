@@ -155,7 +155,7 @@ func UploadRepoFiles(ctx context.Context, repo *repo_model.Repository, doer *use
 
 	// OK now we can insert the data into the store - there's no way to clean up the store
 	// once it's in there, it's in there.
-	contentStore := lfs.NewContentStore()
+	contentStore := lfs.NewContentStoreOfRegion(repo.StorageRegion())
 	for _, info := range infos {
 		if err := uploadToLFSContentStore(info, contentStore); err != nil {
 			return cleanUpAfterFailure(&infos, t, err)
@@ -79,9 +79,12 @@ func ForkRepository(ctx context.Context, doer, owner *user_model.User, opts Fork
 		Description:   opts.Description,
 		DefaultBranch: opts.BaseRepo.DefaultBranch,
 		IsPrivate:     opts.BaseRepo.IsPrivate || opts.BaseRepo.Owner.Visibility == structs.VisibleTypePrivate,
+		IsInternal:    opts.BaseRepo.IsInternal,
 		IsEmpty:       opts.BaseRepo.IsEmpty,
 		IsFork:        true,
 		ForkID:        opts.BaseRepo.ID,
+		// forks are cloned, so they keep the object format of the base repository
+		ObjectFormatName: opts.BaseRepo.ObjectFormatName,
 	}
 
 	oldRepoPath := opts.BaseRepo.RepoPath()
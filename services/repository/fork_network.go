@@ -0,0 +1,222 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+const (
+	// maxForkNetworkSize limits the number of forks loaded for the fork network of a repository
+	maxForkNetworkSize = 1000
+	// maxForkDivergenceUpdates limits the number of outdated ahead/behind counts recomputed per request,
+	// the remaining forks are listed with their previous counts
+	maxForkDivergenceUpdates = 50
+	// maxForkCommitBranches limits the number of branches listed per fork containing a commit
+	maxForkCommitBranches = 10
+)
+
+// ForkNetworkEntry is a downstream fork in the fork network of a repository
+type ForkNetworkEntry struct {
+	Repo *repo_model.Repository
+	// Parent is the repository the fork was forked from
+	Parent *repo_model.Repository
+	// Depth is 1 for direct forks, 2 for forks of those and so on
+	Depth int
+	// Divergence is nil if the fork or its parent has no commits
+	Divergence *repo_model.ForkDivergence
+}
+
+// lastActivity returns the time of the latest commit of the fork, or its last update if unknown
+func (e *ForkNetworkEntry) lastActivity() timeutil.TimeStamp {
+	if e.Divergence != nil && e.Divergence.LastCommitUnix > e.Repo.UpdatedUnix {
+		return e.Divergence.LastCommitUnix
+	}
+	return e.Repo.UpdatedUnix
+}
+
+// ForkCommitMatch is a fork which contains a commit
+type ForkCommitMatch struct {
+	Repo     *repo_model.Repository
+	Branches []string
+}
+
+// UpdateForkDivergence recomputes how far the default branch of a fork is ahead or behind
+// the default branch of the repository it was forked from.
+// Nothing is stored if the fork or its base repository has no commits.
+func UpdateForkDivergence(ctx context.Context, repo *repo_model.Repository) (*repo_model.ForkDivergence, error) {
+	if !repo.IsFork || repo.IsEmpty {
+		return nil, nil
+	}
+	if err := repo.GetBaseRepo(ctx); err != nil {
+		return nil, fmt.Errorf("GetBaseRepo: %w", err)
+	}
+	baseCommitID, err := git.GetFullCommitID(ctx, repo.BaseRepo.RepoPath(), git.BranchPrefix+repo.BaseRepo.DefaultBranch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return updateForkDivergence(ctx, repo, repo.BaseRepo, baseCommitID)
+}
+
+func updateForkDivergence(ctx context.Context, repo, baseRepo *repo_model.Repository, baseCommitID string) (*repo_model.ForkDivergence, error) {
+	commitID, err := git.GetFullCommitID(ctx, repo.RepoPath(), git.BranchPrefix+repo.DefaultBranch)
+	if err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	// the commits of the base repository are usually in the fork too, but only if they were there when forking
+	diff, err := git.GetDivergingCommitsWithAlternate(ctx, repo.RepoPath(), baseRepo.RepoPath(), baseCommitID, commitID)
+	if err != nil {
+		return nil, fmt.Errorf("GetDivergingCommitsWithAlternate: %w", err)
+	}
+	lastCommit, err := git.GetLatestCommitTime(ctx, repo.RepoPath())
+	if err != nil {
+		return nil, fmt.Errorf("GetLatestCommitTime: %w", err)
+	}
+
+	d := &repo_model.ForkDivergence{
+		RepoID:         repo.ID,
+		BaseRepoID:     baseRepo.ID,
+		Ahead:          diff.Ahead,
+		Behind:         diff.Behind,
+		CommitID:       commitID,
+		BaseCommitID:   baseCommitID,
+		LastCommitUnix: timeutil.TimeStamp(lastCommit.Unix()),
+	}
+	return d, repo_model.SaveForkDivergence(ctx, d)
+}
+
+// loadForkNetwork returns the downstream forks of a repository which the user can read, breadth first
+func loadForkNetwork(ctx context.Context, doer *user_model.User, repo *repo_model.Repository) ([]*ForkNetworkEntry, error) {
+	repos := map[int64]*repo_model.Repository{repo.ID: repo}
+	entries := make([]*ForkNetworkEntry, 0, repo.NumForks)
+	parentIDs := []int64{repo.ID}
+	for depth := 1; len(parentIDs) > 0 && len(entries) < maxForkNetworkSize; depth++ {
+		forks, err := repo_model.FindAccessibleForksOfRepos(ctx, doer, parentIDs, maxForkNetworkSize-len(entries))
+		if err != nil {
+			return nil, err
+		}
+		parentIDs = make([]int64, 0, len(forks))
+		for _, fork := range forks {
+			if _, ok := repos[fork.ID]; ok {
+				continue
+			}
+			repos[fork.ID] = fork
+			parentIDs = append(parentIDs, fork.ID)
+			entries = append(entries, &ForkNetworkEntry{
+				Repo:   fork,
+				Parent: repos[fork.ForkID],
+				Depth:  depth,
+			})
+		}
+	}
+	return entries, nil
+}
+
+// GetForkNetwork returns the downstream forks of a repository which the user can read, with how far
+// their default branches are ahead or behind their parents, most recently active forks first
+func GetForkNetwork(ctx context.Context, doer *user_model.User, repo *repo_model.Repository) ([]*ForkNetworkEntry, error) {
+	entries, err := loadForkNetwork(ctx, doer, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	repoIDs := make([]int64, 0, len(entries))
+	for _, e := range entries {
+		repoIDs = append(repoIDs, e.Repo.ID)
+	}
+	divergences, err := repo_model.GetForkDivergencesByRepoIDs(ctx, repoIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	// pushes to a fork update its counts, but not those of its forks, so compare with the current parent commits
+	parentCommitIDs := make(map[int64]string)
+	updates := 0
+	for _, e := range entries {
+		e.Divergence = divergences[e.Repo.ID]
+		if e.Repo.IsEmpty || e.Parent.IsEmpty || updates >= maxForkDivergenceUpdates {
+			continue
+		}
+
+		parentCommitID, ok := parentCommitIDs[e.Parent.ID]
+		if !ok {
+			parentCommitID, err = git.GetFullCommitID(ctx, e.Parent.RepoPath(), git.BranchPrefix+e.Parent.DefaultBranch)
+			if err != nil && !git.IsErrNotExist(err) {
+				return nil, err
+			}
+			parentCommitIDs[e.Parent.ID] = parentCommitID
+		}
+		if parentCommitID == "" || (e.Divergence != nil && e.Divergence.BaseCommitID == parentCommitID) {
+			continue
+		}
+
+		updates++
+		d, err := updateForkDivergence(ctx, e.Repo, e.Parent, parentCommitID)
+		if err != nil {
+			log.Error("Unable to update the divergence of %-v from %-v: %v", e.Repo, e.Parent, err)
+			continue
+		}
+		e.Divergence = d
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].lastActivity() > entries[j].lastActivity()
+	})
+	return entries, nil
+}
+
+// FindCommitInForks returns the downstream forks of a repository which the user can read and which contain the commit,
+// with the names of branches containing it. The commit id must be complete, it may not exist in the repository itself.
+func FindCommitInForks(ctx context.Context, doer *user_model.User, repo *repo_model.Repository, commitID string) ([]*ForkCommitMatch, error) {
+	entries, err := loadForkNetwork(ctx, doer, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := make([]*ForkCommitMatch, 0, 10)
+	for _, e := range entries {
+		if e.Repo.IsEmpty {
+			continue
+		}
+		branches, err := findCommitInRepo(ctx, e.Repo, commitID)
+		if err != nil {
+			return nil, err
+		}
+		if len(branches) > 0 {
+			matches = append(matches, &ForkCommitMatch{Repo: e.Repo, Branches: branches})
+		}
+	}
+	return matches, nil
+}
+
+func findCommitInRepo(ctx context.Context, repo *repo_model.Repository, commitID string) ([]string, error) {
+	gitRepo, err := git.OpenRepository(ctx, repo.RepoPath())
+	if err != nil {
+		return nil, err
+	}
+	defer gitRepo.Close()
+
+	if _, err := gitRepo.GetCommit(commitID); err != nil {
+		if git.IsErrNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return gitRepo.GetBranchesContaining(commitID, maxForkCommitBranches)
+}
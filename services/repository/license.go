@@ -0,0 +1,92 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/util"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// UpdateRepoLicenses detects the licenses of the default branch of a repository and the licenses its lock files
+// declare for its dependencies and stores them. If the owner of the repository has a license policy, violations are
+// reported as repository notices and mailed to the admins of the organization whenever they differ from the
+// violations of the previously detected licenses.
+func UpdateRepoLicenses(ctx context.Context, repo *repo_model.Repository, gitRepo *git.Repository) error {
+	if repo.IsEmpty {
+		return repo_model.UpdateRepoLicenses(ctx, repo.ID, "", nil)
+	}
+
+	commit, err := gitRepo.GetBranchCommit(repo.DefaultBranch)
+	if err != nil {
+		return fmt.Errorf("GetBranchCommit: %w", err)
+	}
+
+	detected, err := repo_module.DetectLicensesFromCommit(commit)
+	if err != nil {
+		return fmt.Errorf("DetectLicensesFromCommit: %w", err)
+	}
+
+	dependencies, err := repo_module.DetectDependencyLicensesFromCommit(commit)
+	if err != nil {
+		// a broken lock file must not prevent the licenses of the repository itself from being updated
+		log.Warn("DetectDependencyLicensesFromCommit for %s: %v", repo.FullName(), err)
+	}
+
+	licenses := make(repo_model.RepoLicenseList, 0, len(detected)+len(dependencies))
+	for _, l := range detected {
+		licenses = append(licenses, &repo_model.RepoLicense{
+			License:    l.Name,
+			Path:       l.Path,
+			Confidence: l.Confidence,
+		})
+	}
+	for _, l := range dependencies {
+		licenses = append(licenses, &repo_model.RepoLicense{
+			License:    l.License,
+			Path:       l.Path,
+			Dependency: l.Dependency,
+			Confidence: 1,
+		})
+	}
+
+	policy, err := organization.GetLicensePolicy(ctx, repo.OwnerID)
+	if err != nil {
+		return err
+	}
+	var violations []string
+	if policy != nil {
+		violations = policy.Violations(licenses.StringList())
+		for _, l := range licenses {
+			l.Forbidden = util.SliceContainsString(violations, l.License)
+		}
+	}
+
+	previous, err := repo_model.GetRepoLicenses(ctx, repo.ID)
+	if err != nil {
+		return err
+	}
+	if err := repo_model.UpdateRepoLicenses(ctx, repo.ID, commit.ID.String(), licenses); err != nil {
+		return err
+	}
+
+	if len(violations) > 0 && !util.SliceSortedEqual(violations, previous.ForbiddenList()) {
+		if err := system_model.CreateRepositoryNotice("Repository %s uses licenses forbidden by the organization license policy: %s", repo.FullName(), strings.Join(violations, ", ")); err != nil {
+			log.Error("CreateRepositoryNotice: %v", err)
+		}
+		if err := mailer.SendRepoLicenseViolationMail(ctx, repo, licenses); err != nil {
+			log.Error("SendRepoLicenseViolationMail: %v", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	gosync "sync"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/timeutil"
+
+	"xorm.io/builder"
+)
+
+// ErrMaintenanceRunning is returned if maintenance is already running for a repository
+var ErrMaintenanceRunning = errors.New("repository maintenance is already running")
+
+var maintenanceStatus = sync.NewStatusTable()
+
+// MaintenanceOptions configures when and how repository maintenance is run
+type MaintenanceOptions struct {
+	// LooseObjectsThreshold is the number of loose objects above which 'git gc' is run
+	LooseObjectsThreshold int64
+	// PacksThreshold is the number of packs above which the repository is repacked
+	PacksThreshold int64
+	// MaxConcurrency limits the number of repositories maintained at the same time
+	MaxConcurrency int
+	// OffPeakStartHour and OffPeakEndHour define the hours of the day in which maintenance may run,
+	// the window may wrap around midnight. If both are equal maintenance may run at any time.
+	OffPeakStartHour int
+	OffPeakEndHour   int
+	Timeout          time.Duration
+	GCArgs           git.TrustedCmdArgs
+}
+
+// InOffPeakWindow checks if the given time is inside the off-peak window
+func (opts *MaintenanceOptions) InOffPeakWindow(t time.Time) bool {
+	start, end, hour := opts.OffPeakStartHour, opts.OffPeakEndHour, t.Hour()
+	if start == end {
+		return true
+	}
+	if start < end {
+		return hour >= start && hour < end
+	}
+	return hour >= start || hour < end
+}
+
+// neededMaintenanceTasks decides which maintenance tasks a repository requires.
+// 'git gc' already repacks the repository, so an explicit repack is only needed if gc is not.
+func neededMaintenanceTasks(stats *git.ObjectStats, hasCommitGraph, hasMultiPackIndex bool, opts *MaintenanceOptions) []repo_model.MaintenanceTask {
+	var tasks []repo_model.MaintenanceTask
+	repacked := false
+	if stats.LooseObjects > opts.LooseObjectsThreshold || stats.PrunePackable > 0 || stats.Garbage > 0 {
+		tasks = append(tasks, repo_model.MaintenanceTaskGC)
+		repacked = true
+	} else if stats.Packs > opts.PacksThreshold {
+		tasks = append(tasks, repo_model.MaintenanceTaskRepack)
+		repacked = true
+	}
+	if repacked || !hasCommitGraph {
+		tasks = append(tasks, repo_model.MaintenanceTaskCommitGraph)
+	}
+	if !repacked && stats.Packs > 1 && !hasMultiPackIndex {
+		tasks = append(tasks, repo_model.MaintenanceTaskMultiPackIndex)
+	}
+	return tasks
+}
+
+// RunRepoMaintenance checks the object statistics of a repository and runs the maintenance tasks it needs
+func RunRepoMaintenance(ctx context.Context, repo *repo_model.Repository, opts *MaintenanceOptions) (*repo_model.RepoMaintenance, error) {
+	key := strconv.FormatInt(repo.ID, 10)
+	if !maintenanceStatus.StartIfNotRunning(key) {
+		return nil, ErrMaintenanceRunning
+	}
+	defer maintenanceStatus.Stop(key)
+
+	repoPath := repo.RepoPath()
+	stats, err := git.CountObjects(ctx, repoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &repo_model.RepoMaintenance{
+		RepoID:          repo.ID,
+		LooseObjects:    stats.LooseObjects,
+		Packs:           stats.Packs,
+		PackSize:        stats.PackSize,
+		LastCheckedUnix: timeutil.TimeStampNow(),
+	}
+	if previous, err := repo_model.GetRepoMaintenance(ctx, repo.ID); err != nil {
+		return nil, err
+	} else if previous != nil {
+		record.LastTasks = previous.LastTasks
+		record.LastError = previous.LastError
+		record.LastDuration = previous.LastDuration
+		record.LastRunUnix = previous.LastRunUnix
+	}
+
+	tasks := neededMaintenanceTasks(stats, git.HasCommitGraph(repoPath), git.HasMultiPackIndex(repoPath), opts)
+	if len(tasks) == 0 {
+		return record, repo_model.SaveRepoMaintenance(ctx, record)
+	}
+
+	log.Trace("Running maintenance tasks %v on %-v", tasks, repo)
+	start := time.Now()
+	var runErr error
+	for _, task := range tasks {
+		if runErr = runMaintenanceTask(ctx, repo, task, opts); runErr != nil {
+			break
+		}
+	}
+
+	record.LastTasks = tasks
+	record.LastDuration = time.Since(start).Milliseconds()
+	record.LastRunUnix = timeutil.TimeStampNow()
+	record.LastError = ""
+	if runErr != nil {
+		record.LastError = runErr.Error()
+		log.Warn("Maintenance of repository %-v failed: %v", repo, runErr)
+		if err := system_model.CreateRepositoryNotice("Maintenance of repository %s failed: %v", repo.FullName(), runErr); err != nil {
+			log.Error("CreateRepositoryNotice: %v", err)
+		}
+	} else if stats, err := git.CountObjects(ctx, repoPath); err == nil {
+		record.LooseObjects = stats.LooseObjects
+		record.Packs = stats.Packs
+		record.PackSize = stats.PackSize
+	}
+
+	if err := repo_model.SaveRepoMaintenance(ctx, record); err != nil {
+		return nil, err
+	}
+	return record, runErr
+}
+
+func runMaintenanceTask(ctx context.Context, repo *repo_model.Repository, task repo_model.MaintenanceTask, opts *MaintenanceOptions) error {
+	repoPath := repo.RepoPath()
+	switch task {
+	case repo_model.MaintenanceTaskGC:
+		return GitGcRepo(ctx, repo, opts.Timeout, opts.GCArgs)
+	case repo_model.MaintenanceTaskRepack:
+		if err := git.Repack(ctx, repoPath, opts.Timeout); err != nil {
+			return err
+		}
+		return repo_module.UpdateRepoSize(ctx, repo)
+	case repo_model.MaintenanceTaskCommitGraph:
+		return git.WriteCommitGraph(ctx, repoPath)
+	case repo_model.MaintenanceTaskMultiPackIndex:
+		return git.WriteMultiPackIndex(ctx, repoPath)
+	}
+	return fmt.Errorf("unknown maintenance task: %s", task)
+}
+
+// RunMaintenance runs the needed maintenance tasks on all repositories, with at most
+// opts.MaxConcurrency repositories at the same time. Nothing is done outside the off-peak window.
+func RunMaintenance(ctx context.Context, opts *MaintenanceOptions) error {
+	if !opts.InOffPeakWindow(time.Now()) {
+		log.Trace("Skipping repository maintenance outside of the off-peak window")
+		return nil
+	}
+	log.Trace("Doing: RunMaintenance")
+
+	concurrency := opts.MaxConcurrency
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg gosync.WaitGroup
+
+	err := db.Iterate(
+		ctx,
+		builder.Eq{"is_empty": false},
+		func(ctx context.Context, repo *repo_model.Repository) error {
+			select {
+			case <-ctx.Done():
+				return db.ErrCancelledf("before maintenance of %s", repo.FullName())
+			case sem <- struct{}{}:
+			}
+			if !opts.InOffPeakWindow(time.Now()) {
+				<-sem
+				return db.ErrCancelledf("off-peak window ended before maintenance of %s", repo.FullName())
+			}
+			wg.Add(1)
+			go func() {
+				defer func() {
+					<-sem
+					wg.Done()
+				}()
+				// errors are already logged and recorded by RunRepoMaintenance
+				_, _ = RunRepoMaintenance(ctx, repo, opts)
+			}()
+			return nil
+		},
+	)
+	wg.Wait()
+	if err != nil {
+		log.Trace("Error: RunMaintenance: %v", err)
+		return err
+	}
+
+	log.Trace("Finished: RunMaintenance")
+	return nil
+}
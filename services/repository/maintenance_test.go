@@ -0,0 +1,51 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/git"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenanceOptions_InOffPeakWindow(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2023, 1, 1, hour, 30, 0, 0, time.UTC)
+	}
+
+	always := &MaintenanceOptions{}
+	assert.True(t, always.InOffPeakWindow(at(12)))
+
+	night := &MaintenanceOptions{OffPeakStartHour: 22, OffPeakEndHour: 6}
+	assert.True(t, night.InOffPeakWindow(at(23)))
+	assert.True(t, night.InOffPeakWindow(at(3)))
+	assert.False(t, night.InOffPeakWindow(at(6)))
+	assert.False(t, night.InOffPeakWindow(at(12)))
+
+	morning := &MaintenanceOptions{OffPeakStartHour: 2, OffPeakEndHour: 5}
+	assert.True(t, morning.InOffPeakWindow(at(2)))
+	assert.False(t, morning.InOffPeakWindow(at(5)))
+}
+
+func TestNeededMaintenanceTasks(t *testing.T) {
+	opts := &MaintenanceOptions{LooseObjectsThreshold: 100, PacksThreshold: 10}
+
+	assert.Empty(t, neededMaintenanceTasks(&git.ObjectStats{LooseObjects: 5, Packs: 1}, true, false, opts))
+
+	assert.Equal(t, []repo_model.MaintenanceTask{repo_model.MaintenanceTaskCommitGraph},
+		neededMaintenanceTasks(&git.ObjectStats{Packs: 1}, false, false, opts))
+
+	assert.Equal(t, []repo_model.MaintenanceTask{repo_model.MaintenanceTaskGC, repo_model.MaintenanceTaskCommitGraph},
+		neededMaintenanceTasks(&git.ObjectStats{LooseObjects: 500, Packs: 20}, true, false, opts))
+
+	assert.Equal(t, []repo_model.MaintenanceTask{repo_model.MaintenanceTaskRepack, repo_model.MaintenanceTaskCommitGraph},
+		neededMaintenanceTasks(&git.ObjectStats{Packs: 20}, true, true, opts))
+
+	assert.Equal(t, []repo_model.MaintenanceTask{repo_model.MaintenanceTaskMultiPackIndex},
+		neededMaintenanceTasks(&git.ObjectStats{Packs: 3}, true, false, opts))
+}
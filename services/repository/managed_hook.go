@@ -0,0 +1,132 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	githook_model "code.gitea.io/gitea/models/githook"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/private"
+	"code.gitea.io/gitea/modules/process"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// ErrManagedHookFailed represents a managed hook script which exited with an error or timed out
+type ErrManagedHookFailed struct {
+	Name   string
+	Output string
+}
+
+// IsErrManagedHookFailed checks if an error is a ErrManagedHookFailed.
+func IsErrManagedHookFailed(err error) bool {
+	_, ok := err.(ErrManagedHookFailed)
+	return ok
+}
+
+func (err ErrManagedHookFailed) Error() string {
+	return fmt.Sprintf("managed hook %q failed: %s", err.Name, err.Output)
+}
+
+// RunManagedHooks runs the active managed hooks of a type which apply to a repository for a push.
+// Pre-receive hooks stop at the first failing script, which is returned as ErrManagedHookFailed,
+// the failures of post-receive hooks are only logged.
+func RunManagedHooks(ctx context.Context, repo *repo_model.Repository, typ githook_model.HookType, opts *private.HookOptions) error {
+	if !setting.Git.ManagedHooks.Enabled {
+		return nil
+	}
+	hooks, err := githook_model.GetActiveManagedHooks(ctx, repo.OwnerID, typ)
+	if err != nil || len(hooks) == 0 {
+		return err
+	}
+
+	tmpDir, err := os.MkdirTemp(os.TempDir(), "gitea-managed-hook")
+	if err != nil {
+		return fmt.Errorf("MkdirTemp: %w", err)
+	}
+	defer func() {
+		if err := util.RemoveAll(tmpDir); err != nil {
+			log.Error("Unable to remove temporary directory %s: %v", tmpDir, err)
+		}
+	}()
+
+	var stdin strings.Builder
+	for i := range opts.OldCommitIDs {
+		fmt.Fprintf(&stdin, "%s %s %s\n", opts.OldCommitIDs[i], opts.NewCommitIDs[i], opts.RefFullNames[i])
+	}
+	env := managedHookEnv(repo, tmpDir, opts)
+
+	for _, hook := range hooks {
+		err := runManagedHook(ctx, repo, hook, tmpDir, env, stdin.String())
+		if err != nil && IsErrManagedHookFailed(err) && typ == githook_model.HookTypePostReceive {
+			log.Warn("Managed post-receive hook %q failed for %-v: %v", hook.Name, repo, err)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// managedHookEnv returns the environment of managed hook scripts, which doesn't include the environment of Gitea
+func managedHookEnv(repo *repo_model.Repository, tmpDir string, opts *private.HookOptions) []string {
+	repoPath := repo.RepoPath()
+	if opts.IsWiki {
+		repoPath = repo.WikiPath()
+	}
+	env := []string{
+		"PATH=" + os.Getenv("PATH"),
+		"HOME=" + tmpDir,
+		"GIT_DIR=" + repoPath,
+		"GITEA_REPO_USER_NAME=" + repo.OwnerName,
+		"GITEA_REPO_NAME=" + repo.Name,
+		"GITEA_REPO_IS_WIKI=" + strconv.FormatBool(opts.IsWiki),
+		"GITEA_PUSHER_ID=" + strconv.FormatInt(opts.UserID, 10),
+		"GITEA_PUSHER_NAME=" + opts.UserName,
+	}
+	// the objects of a push are quarantined until the pre-receive hooks succeeded
+	if opts.GitObjectDirectory != "" {
+		env = append(env, private.GitObjectDirectory+"="+opts.GitObjectDirectory)
+	}
+	if opts.GitAlternativeObjectDirectories != "" {
+		env = append(env, private.GitAlternativeObjectDirectories+"="+opts.GitAlternativeObjectDirectories)
+	}
+	if opts.GitQuarantinePath != "" {
+		env = append(env, private.GitQuarantinePath+"="+opts.GitQuarantinePath)
+	}
+	return env
+}
+
+func runManagedHook(ctx context.Context, repo *repo_model.Repository, hook *githook_model.ManagedHook, tmpDir string, env []string, stdin string) error {
+	scriptPath := filepath.Join(tmpDir, "hook-"+strconv.FormatInt(hook.ID, 10))
+	if err := os.WriteFile(scriptPath, []byte(strings.ReplaceAll(hook.Content, "\r", "")), 0o700); err != nil {
+		return fmt.Errorf("WriteFile: %w", err)
+	}
+
+	cmdName, args := managedHookCommand(scriptPath)
+	desc := fmt.Sprintf("ManagedHook %s: %s", hook.Name, repo.FullName())
+	stdout, stderr, err := process.GetManager().ExecDirEnvStdIn(ctx, setting.Git.ManagedHooks.Timeout, tmpDir, desc, env, strings.NewReader(stdin), cmdName, args...)
+	if err == nil {
+		return nil
+	}
+
+	output := stdout + stderr
+	var processErr *process.Error
+	if errors.As(err, &processErr) && processErr.CtxErr != nil {
+		output += "\nhook timed out"
+	}
+	if limit := setting.Git.ManagedHooks.MaxOutputSize; limit > 0 && len(output) > limit {
+		output = output[:limit] + "\n(output truncated)"
+	}
+	return ErrManagedHookFailed{Name: hook.Name, Output: strings.TrimSpace(output)}
+}
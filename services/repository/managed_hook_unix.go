@@ -0,0 +1,27 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build !windows
+
+package repository
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/modules/setting"
+)
+
+// managedHookCommand returns the command which runs a managed hook script with the configured resource limits
+func managedHookCommand(scriptPath string) (string, []string) {
+	script := make([]string, 0, 3)
+	if cpuTime := setting.Git.ManagedHooks.MaxCPUTime; cpuTime > 0 {
+		script = append(script, fmt.Sprintf("ulimit -t %d", int64((cpuTime+time.Second-1)/time.Second)))
+	}
+	if memory := setting.Git.ManagedHooks.MaxMemory; memory > 0 {
+		script = append(script, fmt.Sprintf("ulimit -v %d", (memory+1023)/1024))
+	}
+	script = append(script, `exec "$0"`)
+	return "/bin/sh", []string{"-c", strings.Join(script, " && "), scriptPath}
+}
@@ -0,0 +1,11 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+//go:build windows
+
+package repository
+
+// managedHookCommand returns the command which runs a managed hook script, resource limits are not supported on Windows
+func managedHookCommand(scriptPath string) (string, []string) {
+	return "sh", []string{scriptPath}
+}
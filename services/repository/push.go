@@ -105,7 +105,7 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 		log.Trace("pushUpdates: %-v %s %s %s", repo, opts.OldCommitID, opts.NewCommitID, opts.RefFullName)
 
 		if opts.IsNewRef() && opts.IsDelRef() {
-			return fmt.Errorf("old and new revisions are both %s", git.EmptySHA)
+			return fmt.Errorf("old and new revisions are both %s", repo.ObjectFormat().EmptyObjectID())
 		}
 		if opts.IsTag() { // If is tag reference
 			if pusher == nil || pusher.ID != opts.PusherID {
@@ -125,7 +125,7 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 					&repo_module.PushUpdateOptions{
 						RefFullName: git.TagPrefix + tagName,
 						OldCommitID: opts.OldCommitID,
-						NewCommitID: git.EmptySHA,
+						NewCommitID: repo.ObjectFormat().EmptyObjectID(),
 					}, repo_module.NewPushCommits())
 
 				delTags = append(delTags, tagName)
@@ -138,13 +138,13 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 
 				commits := repo_module.NewPushCommits()
 				commits.HeadCommit = repo_module.CommitToPushCommit(newCommit)
-				commits.CompareURL = repo.ComposeCompareURL(git.EmptySHA, opts.NewCommitID)
+				commits.CompareURL = repo.ComposeCompareURL(repo.ObjectFormat().EmptyObjectID(), opts.NewCommitID)
 
 				notification.NotifyPushCommits(
 					ctx, pusher, repo,
 					&repo_module.PushUpdateOptions{
 						RefFullName: git.TagPrefix + tagName,
-						OldCommitID: git.EmptySHA,
+						OldCommitID: repo.ObjectFormat().EmptyObjectID(),
 						NewCommitID: opts.NewCommitID,
 					}, commits)
 
@@ -228,7 +228,7 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 				}
 
 				oldCommitID := opts.OldCommitID
-				if oldCommitID == git.EmptySHA && len(commits.Commits) > 0 {
+				if git.IsEmptyCommitID(oldCommitID) && len(commits.Commits) > 0 {
 					oldCommit, err := gitRepo.GetCommit(commits.Commits[len(commits.Commits)-1].Sha1)
 					if err != nil && !git.IsErrNotExist(err) {
 						log.Error("unable to GetCommit %s from %-v: %v", oldCommitID, repo, err)
@@ -244,11 +244,11 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 					}
 				}
 
-				if oldCommitID == git.EmptySHA && repo.DefaultBranch != branch {
+				if git.IsEmptyCommitID(oldCommitID) && repo.DefaultBranch != branch {
 					oldCommitID = repo.DefaultBranch
 				}
 
-				if oldCommitID != git.EmptySHA {
+				if !git.IsEmptyCommitID(oldCommitID) {
 					commits.CompareURL = repo.ComposeCompareURL(oldCommitID, opts.NewCommitID)
 				} else {
 					commits.CompareURL = ""
@@ -268,6 +268,23 @@ func pushUpdates(optsList []*repo_module.PushUpdateOptions) error {
 				if err := CacheRef(graceful.GetManager().HammerContext(), repo, gitRepo, opts.RefFullName); err != nil {
 					log.Error("repo_module.CacheRef %s/%s failed: %v", repo.ID, branch, err)
 				}
+
+				if branch == repo.DefaultBranch {
+					if err := UpdateRepoLicenses(ctx, repo, gitRepo); err != nil {
+						log.Error("UpdateRepoLicenses %-v failed: %v", repo, err)
+					}
+					if _, err := UpdateForkDivergence(ctx, repo); err != nil {
+						log.Error("UpdateForkDivergence %-v failed: %v", repo, err)
+					}
+				}
+
+				leakBase := oldCommitID
+				if git.IsEmptyCommitID(leakBase) {
+					leakBase = gitRepo.ObjectFormat().EmptyTree()
+				}
+				if err := DetectLeakedAccessTokens(ctx, repo, leakBase, opts.NewCommitID); err != nil {
+					log.Error("DetectLeakedAccessTokens %-v failed: %v", repo, err)
+				}
 			} else {
 				notification.NotifyDeleteRef(ctx, pusher, repo, "branch", opts.RefFullName)
 				if err = pull_service.CloseBranchPulls(pusher, repo.ID, branch); err != nil {
@@ -0,0 +1,335 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/perm"
+	repo_model "code.gitea.io/gitea/models/repo"
+	unit_model "code.gitea.io/gitea/models/unit"
+	webhook_model "code.gitea.io/gitea/models/webhook"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/label"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/convert"
+	webhook_service "code.gitea.io/gitea/services/webhook"
+)
+
+// ExportSettings returns all settings of a repository as a declarative document
+func ExportSettings(ctx context.Context, repo *repo_model.Repository) (*api.RepoSettings, error) {
+	settings := &api.RepoSettings{
+		Repository:        exportRepositorySettings(ctx, repo),
+		BranchProtections: make([]*api.CreateBranchProtectionOption, 0, 5),
+		Webhooks:          make([]*api.CreateHookOption, 0, 5),
+		Labels:            make([]*api.CreateLabelOption, 0, 10),
+	}
+
+	rules, err := git_model.FindRepoProtectedBranchRules(ctx, repo.ID)
+	if err != nil {
+		return nil, fmt.Errorf("FindRepoProtectedBranchRules: %w", err)
+	}
+	for _, rule := range rules {
+		bp := convert.ToBranchProtection(rule)
+		settings.BranchProtections = append(settings.BranchProtections, &api.CreateBranchProtectionOption{
+			RuleName:                      bp.RuleName,
+			EnablePush:                    bp.EnablePush,
+			EnablePushWhitelist:           bp.EnablePushWhitelist,
+			PushWhitelistUsernames:        bp.PushWhitelistUsernames,
+			PushWhitelistTeams:            bp.PushWhitelistTeams,
+			PushWhitelistDeployKeys:       bp.PushWhitelistDeployKeys,
+			EnableMergeWhitelist:          bp.EnableMergeWhitelist,
+			MergeWhitelistUsernames:       bp.MergeWhitelistUsernames,
+			MergeWhitelistTeams:           bp.MergeWhitelistTeams,
+			EnableStatusCheck:             bp.EnableStatusCheck,
+			StatusCheckContexts:           bp.StatusCheckContexts,
+			RequiredApprovals:             bp.RequiredApprovals,
+			EnableApprovalsWhitelist:      bp.EnableApprovalsWhitelist,
+			ApprovalsWhitelistUsernames:   bp.ApprovalsWhitelistUsernames,
+			ApprovalsWhitelistTeams:       bp.ApprovalsWhitelistTeams,
+			BlockOnRejectedReviews:        bp.BlockOnRejectedReviews,
+			BlockOnOfficialReviewRequests: bp.BlockOnOfficialReviewRequests,
+			BlockOnOutdatedBranch:         bp.BlockOnOutdatedBranch,
+			BlockOnCodeScanningAlerts:     bp.BlockOnCodeScanningAlerts,
+			MinDiffCoverage:               bp.MinDiffCoverage,
+			DismissStaleApprovals:         bp.DismissStaleApprovals,
+			RequireReviewChecklist:        bp.RequireReviewChecklist,
+			RequireSignedCommits:          bp.RequireSignedCommits,
+			ProtectedFilePatterns:         bp.ProtectedFilePatterns,
+			UnprotectedFilePatterns:       bp.UnprotectedFilePatterns,
+		})
+	}
+
+	hooks, err := webhook_model.ListWebhooksByOpts(ctx, &webhook_model.ListWebhookOptions{RepoID: repo.ID})
+	if err != nil {
+		return nil, fmt.Errorf("ListWebhooksByOpts: %w", err)
+	}
+	for _, hook := range hooks {
+		apiHook, err := webhook_service.ToHook(repo.Link(), hook)
+		if err != nil {
+			return nil, fmt.Errorf("ToHook: %w", err)
+		}
+		settings.Webhooks = append(settings.Webhooks, &api.CreateHookOption{
+			Type:                apiHook.Type,
+			Config:              apiHook.Config,
+			Events:              apiHook.Events,
+			BranchFilter:        hook.BranchFilter,
+			AuthorizationHeader: apiHook.AuthorizationHeader,
+			AllowedIPs:          apiHook.AllowedIPs,
+			Active:              apiHook.Active,
+		})
+	}
+
+	labels, err := issues_model.GetLabelsByRepoID(ctx, repo.ID, "", db.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("GetLabelsByRepoID: %w", err)
+	}
+	for _, l := range labels {
+		settings.Labels = append(settings.Labels, &api.CreateLabelOption{
+			Name:        l.Name,
+			Exclusive:   l.Exclusive,
+			Color:       l.Color,
+			Description: l.Description,
+		})
+	}
+
+	return settings, nil
+}
+
+func exportRepositorySettings(ctx context.Context, repo *repo_model.Repository) *api.EditRepoOption {
+	apiRepo := convert.ToRepo(ctx, repo, perm.AccessModeAdmin)
+	opts := &api.EditRepoOption{
+		Private:         &apiRepo.Private,
+		Internal:        &apiRepo.Internal,
+		Template:        &apiRepo.Template,
+		HasIssues:       &apiRepo.HasIssues,
+		InternalTracker: apiRepo.InternalTracker,
+		ExternalTracker: apiRepo.ExternalTracker,
+		HasWiki:         &apiRepo.HasWiki,
+		ExternalWiki:    apiRepo.ExternalWiki,
+		DefaultBranch:   &apiRepo.DefaultBranch,
+		HasPullRequests: &apiRepo.HasPullRequests,
+		HasProjects:     &apiRepo.HasProjects,
+		HasReleases:     &apiRepo.HasReleases,
+		HasPackages:     &apiRepo.HasPackages,
+		HasActions:      &apiRepo.HasActions,
+		HasDiscussions:  &apiRepo.HasDiscussions,
+	}
+	// the merge options only exist while pull requests are enabled
+	if unit, err := repo.GetUnit(ctx, unit_model.TypePullRequests); err == nil {
+		config := unit.PullRequestsConfig()
+		defaultMergeStyle := string(config.GetDefaultMergeStyle())
+		opts.IgnoreWhitespaceConflicts = &config.IgnoreWhitespaceConflicts
+		opts.AllowMerge = &config.AllowMerge
+		opts.AllowRebase = &config.AllowRebase
+		opts.AllowRebaseMerge = &config.AllowRebaseMerge
+		opts.AllowSquash = &config.AllowSquash
+		opts.AllowManualMerge = &config.AllowManualMerge
+		opts.AutodetectManualMerge = &config.AutodetectManualMerge
+		opts.AllowRebaseUpdate = &config.AllowRebaseUpdate
+		opts.DefaultDeleteBranchAfterMerge = &config.DefaultDeleteBranchAfterMerge
+		opts.DefaultMergeStyle = &defaultMergeStyle
+		opts.DefaultAllowMaintainerEdit = &config.DefaultAllowMaintainerEdit
+		opts.DefaultMergeMessageTemplate = &config.DefaultMergeMessageTemplate
+		opts.DefaultSquashMessageTemplate = &config.DefaultSquashMessageTemplate
+		opts.MergeMessageTrailers = &config.MergeMessageTrailers
+		opts.MergeTrainBatchSize = &config.MergeTrainBatchSize
+	}
+	return opts
+}
+
+// DiffSettings compares the settings of a repository with a template and returns the differences.
+// Only the properties set in the template are compared, and only the lists which aren't null. The items of
+// lists are matched by their rule name, URL or name, items missing in the repository as well as items which
+// aren't in the template are differences. Secrets and client certificates can't be compared.
+func DiffSettings(template, actual *api.RepoSettings) ([]*api.RepoSettingsDifference, error) {
+	differences := make([]*api.RepoSettingsDifference, 0, 5)
+
+	if template.Repository != nil {
+		expected, err := toJSONValue(template.Repository)
+		if err != nil {
+			return nil, err
+		}
+		var value any
+		if actual.Repository != nil {
+			if value, err = toJSONValue(actual.Repository); err != nil {
+				return nil, err
+			}
+		}
+		differences = diffJSONValues(differences, "repository", expected, value)
+	}
+
+	var err error
+	if template.BranchProtections != nil {
+		differences, err = diffSettingsItems(differences, "branch_protections", template.BranchProtections, actual.BranchProtections, func(bp *api.CreateBranchProtectionOption) string {
+			if bp.RuleName == "" {
+				return bp.BranchName //nolint
+			}
+			return bp.RuleName
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if template.Webhooks != nil {
+		hooks := make([]*api.CreateHookOption, 0, len(template.Webhooks))
+		for _, hook := range template.Webhooks {
+			h := *hook
+			h.Config = make(api.CreateHookOptionConfig, len(hook.Config))
+			for k, v := range hook.Config {
+				if k != "secret" {
+					h.Config[k] = v
+				}
+			}
+			h.ClientCertificate = ""
+			h.ClientKey = ""
+			hooks = append(hooks, &h)
+		}
+		differences, err = diffSettingsItems(differences, "webhooks", hooks, actual.Webhooks, func(hook *api.CreateHookOption) string {
+			return hook.Config["url"]
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if template.Labels != nil {
+		labels := make([]*api.CreateLabelOption, 0, len(template.Labels))
+		for _, l := range template.Labels {
+			normalized := *l
+			if color, err := label.NormalizeColor(l.Color); err == nil {
+				normalized.Color = color
+			}
+			labels = append(labels, &normalized)
+		}
+		differences, err = diffSettingsItems(differences, "labels", labels, actual.Labels, func(l *api.CreateLabelOption) string {
+			return l.Name
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return differences, nil
+}
+
+// diffSettingsItems compares two lists whose items are identified by key
+func diffSettingsItems[T any](differences []*api.RepoSettingsDifference, path string, expected, actual []*T, key func(*T) string) ([]*api.RepoSettingsDifference, error) {
+	actualItems := make(map[string]*T, len(actual))
+	for _, item := range actual {
+		actualItems[key(item)] = item
+	}
+	expectedKeys := make(map[string]bool, len(expected))
+	for _, item := range expected {
+		k := key(item)
+		expectedKeys[k] = true
+		itemPath := fmt.Sprintf("%s[%s]", path, k)
+		e, err := toJSONValue(item)
+		if err != nil {
+			return nil, err
+		}
+		actualItem, ok := actualItems[k]
+		if !ok {
+			differences = append(differences, &api.RepoSettingsDifference{Path: itemPath, Expected: e})
+			continue
+		}
+		a, err := toJSONValue(actualItem)
+		if err != nil {
+			return nil, err
+		}
+		differences = diffJSONValues(differences, itemPath, e, a)
+	}
+	for _, item := range actual {
+		k := key(item)
+		if expectedKeys[k] {
+			continue
+		}
+		a, err := toJSONValue(item)
+		if err != nil {
+			return nil, err
+		}
+		differences = append(differences, &api.RepoSettingsDifference{Path: fmt.Sprintf("%s[%s]", path, k), Actual: a})
+	}
+	return differences, nil
+}
+
+// diffJSONValues compares two decoded JSON values, objects are compared by the keys of the expected one
+func diffJSONValues(differences []*api.RepoSettingsDifference, path string, expected, actual any) []*api.RepoSettingsDifference {
+	expectedObject, isObject := expected.(map[string]any)
+	actualObject, isActualObject := actual.(map[string]any)
+	if !isObject || !isActualObject {
+		if !isEqualJSONValue(expected, actual) {
+			differences = append(differences, &api.RepoSettingsDifference{Path: path, Expected: expected, Actual: actual})
+		}
+		return differences
+	}
+
+	keys := make([]string, 0, len(expectedObject))
+	for k := range expectedObject {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		differences = diffJSONValues(differences, path+"."+k, expectedObject[k], actualObject[k])
+	}
+	return differences
+}
+
+// isEqualJSONValue compares two decoded JSON values, an empty list equals a missing one and the order of lists doesn't matter
+func isEqualJSONValue(a, b any) bool {
+	if isEmptyJSONList(a) && isEmptyJSONList(b) {
+		return true
+	}
+	listA, isList := a.([]any)
+	listB, isListB := b.([]any)
+	if !isList || !isListB {
+		return reflect.DeepEqual(a, b)
+	}
+	if len(listA) != len(listB) {
+		return false
+	}
+	sortedA, sortedB := sortedJSONList(listA), sortedJSONList(listB)
+	for i := range sortedA {
+		if sortedA[i] != sortedB[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func sortedJSONList(list []any) []string {
+	sorted := make([]string, 0, len(list))
+	for _, v := range list {
+		sorted = append(sorted, fmt.Sprint(v))
+	}
+	sort.Strings(sorted)
+	return sorted
+}
+
+func isEmptyJSONList(v any) bool {
+	if v == nil {
+		return true
+	}
+	list, ok := v.([]any)
+	return ok && len(list) == 0
+}
+
+func toJSONValue(v any) (any, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,83 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSettings(t *testing.T) {
+	yes, no := true, false
+	actual := &api.RepoSettings{
+		Repository: &api.EditRepoOption{
+			HasWiki:    &yes,
+			AllowMerge: &yes,
+		},
+		BranchProtections: []*api.CreateBranchProtectionOption{
+			{RuleName: "main", RequiredApprovals: 1, StatusCheckContexts: []string{"lint", "test"}},
+		},
+		Webhooks: []*api.CreateHookOption{
+			{Type: "gitea", Config: api.CreateHookOptionConfig{"url": "https://example.com/hook", "content_type": "json"}, Events: []string{"push"}, Active: true},
+		},
+		Labels: []*api.CreateLabelOption{
+			{Name: "bug", Color: "#ee0701"},
+			{Name: "extra", Color: "#000000"},
+		},
+	}
+
+	t.Run("Equal", func(t *testing.T) {
+		template := &api.RepoSettings{
+			Repository: &api.EditRepoOption{HasWiki: &yes},
+			BranchProtections: []*api.CreateBranchProtectionOption{
+				{RuleName: "main", RequiredApprovals: 1, StatusCheckContexts: []string{"test", "lint"}},
+			},
+			Webhooks: []*api.CreateHookOption{
+				{Type: "gitea", Config: api.CreateHookOptionConfig{"url": "https://example.com/hook", "content_type": "json", "secret": "s3cr3t"}, Events: []string{"push"}, Active: true},
+			},
+		}
+		differences, err := DiffSettings(template, actual)
+		assert.NoError(t, err)
+		assert.Empty(t, differences)
+	})
+
+	t.Run("Different", func(t *testing.T) {
+		template := &api.RepoSettings{
+			Repository: &api.EditRepoOption{HasWiki: &no, AllowSquash: &yes},
+			BranchProtections: []*api.CreateBranchProtectionOption{
+				{RuleName: "main", RequiredApprovals: 2, StatusCheckContexts: []string{"lint", "test"}},
+				{RuleName: "release/*"},
+			},
+			Labels: []*api.CreateLabelOption{
+				{Name: "bug", Color: "00ff00"},
+			},
+		}
+		differences, err := DiffSettings(template, actual)
+		assert.NoError(t, err)
+
+		paths := make([]string, 0, len(differences))
+		for _, d := range differences {
+			paths = append(paths, d.Path)
+		}
+		assert.Equal(t, []string{
+			"repository.allow_squash_merge",
+			"repository.has_wiki",
+			"branch_protections[main].required_approvals",
+			"branch_protections[release/*]",
+			"labels[bug].color",
+			"labels[extra]",
+		}, paths)
+
+		assert.Equal(t, true, differences[0].Expected)
+		assert.Nil(t, differences[0].Actual)
+		assert.EqualValues(t, 2, differences[2].Expected)
+		assert.EqualValues(t, 1, differences[2].Actual)
+		assert.Nil(t, differences[3].Actual)
+		assert.Equal(t, "#00ff00", differences[4].Expected)
+		assert.Nil(t, differences[5].Expected)
+	})
+}
@@ -0,0 +1,103 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+const storageUsageBatchSize = 100
+
+// RecordStorageUsage records a snapshot of the storage used by every repository and of the totals of every owner,
+// which include the packages of the owner. Snapshots older than olderThan are deleted.
+func RecordStorageUsage(ctx context.Context, olderThan time.Duration) error {
+	lfsSizes, err := git_model.GetLFSSizesByRepo(ctx)
+	if err != nil {
+		return fmt.Errorf("GetLFSSizesByRepo: %w", err)
+	}
+	attachmentSizes, err := repo_model.GetAttachmentSizesByRepo(ctx)
+	if err != nil {
+		return fmt.Errorf("GetAttachmentSizesByRepo: %w", err)
+	}
+	artifactSizes, err := actions_model.GetArtifactSizesByRepo(ctx)
+	if err != nil {
+		return fmt.Errorf("GetArtifactSizesByRepo: %w", err)
+	}
+	logSizes, err := actions_model.GetLogSizesByRepo(ctx)
+	if err != nil {
+		return fmt.Errorf("GetLogSizesByRepo: %w", err)
+	}
+	packageSizes, err := packages_model.GetBlobSizesByOwner(ctx)
+	if err != nil {
+		return fmt.Errorf("GetBlobSizesByOwner: %w", err)
+	}
+
+	now := timeutil.TimeStampNow()
+	owners := make(map[int64]*repo_model.StorageUsage)
+	ownerUsage := func(ownerID int64) *repo_model.StorageUsage {
+		usage, ok := owners[ownerID]
+		if !ok {
+			usage = &repo_model.StorageUsage{OwnerID: ownerID, CreatedUnix: now}
+			owners[ownerID] = usage
+		}
+		return usage
+	}
+
+	batch := make([]*repo_model.StorageUsage, 0, storageUsageBatchSize)
+	if err := db.Iterate(ctx, nil, func(ctx context.Context, repo *repo_model.Repository) error {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before recording the storage usage of %s", repo.FullName())
+		default:
+		}
+
+		usage := &repo_model.StorageUsage{
+			OwnerID:        repo.OwnerID,
+			RepoID:         repo.ID,
+			LFSSize:        lfsSizes[repo.ID],
+			AttachmentSize: attachmentSizes[repo.ID],
+			ActionsSize:    artifactSizes[repo.ID] + logSizes[repo.ID],
+			CreatedUnix:    now,
+		}
+		// the size of the repository includes the size of its lfs files
+		if usage.GitSize = repo.Size - usage.LFSSize; usage.GitSize < 0 {
+			usage.GitSize = 0
+		}
+		usage.UpdateTotalSize()
+		ownerUsage(repo.OwnerID).Add(usage)
+
+		batch = append(batch, usage)
+		if len(batch) < storageUsageBatchSize {
+			return nil
+		}
+		err := repo_model.InsertStorageUsages(ctx, batch)
+		batch = batch[:0]
+		return err
+	}); err != nil {
+		return err
+	}
+
+	for ownerID, size := range packageSizes {
+		ownerUsage(ownerID).Add(&repo_model.StorageUsage{PackageSize: size})
+	}
+	for _, usage := range owners {
+		batch = append(batch, usage)
+	}
+	if err := repo_model.InsertStorageUsages(ctx, batch); err != nil {
+		return err
+	}
+	log.Trace("Recorded the storage usage of %d owners", len(owners))
+
+	return repo_model.DeleteStorageUsagesOlderThan(ctx, olderThan)
+}
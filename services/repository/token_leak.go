@@ -0,0 +1,143 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	repo_model "code.gitea.io/gitea/models/repo"
+	system_model "code.gitea.io/gitea/models/system"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/mailer"
+)
+
+// maxLeakedTokenCandidates limits the number of token like strings looked up per push,
+// commit ids in lock files and the like have the same format as access tokens.
+const maxLeakedTokenCandidates = 1000
+
+var accessTokenPattern = regexp.MustCompile(`\b[0-9a-f]{40}\b`)
+
+// findTokenCandidates returns the token like strings on the added lines of a diff and the file they were added to.
+// It stops reading when there are too many candidates or a line is too long, complete is false then.
+func findTokenCandidates(diff io.Reader) (candidates map[string]string, complete bool, err error) {
+	candidates = make(map[string]string)
+	var path string
+	scanner := bufio.NewScanner(diff)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "+++ ") {
+			path = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			continue
+		}
+		if !strings.HasPrefix(line, "+") {
+			continue
+		}
+		for _, match := range accessTokenPattern.FindAllString(line, -1) {
+			if _, ok := candidates[match]; !ok {
+				candidates[match] = path
+			}
+			if len(candidates) >= maxLeakedTokenCandidates {
+				return candidates, false, nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			return candidates, false, nil
+		}
+		return nil, false, err
+	}
+	return candidates, true, nil
+}
+
+// addedTokenCandidates returns the token like strings added between two commits
+func addedTokenCandidates(ctx context.Context, repoPath, oldCommitID, newCommitID string) (map[string]string, error) {
+	stdoutReader, stdoutWriter := io.Pipe()
+	defer func() {
+		_ = stdoutReader.Close()
+		_ = stdoutWriter.Close()
+	}()
+
+	var candidates map[string]string
+	var complete bool
+	stderr := new(strings.Builder)
+	err := git.NewCommand(ctx, "diff", "--no-color", "--no-ext-diff", "--no-renames", "--text", "-U0").
+		AddDynamicArguments(oldCommitID, newCommitID).
+		Run(&git.RunOpts{
+			Dir:    repoPath,
+			Stdout: stdoutWriter,
+			Stderr: stderr,
+			PipelineFunc: func(ctx context.Context, cancel context.CancelFunc) error {
+				_ = stdoutWriter.Close()
+				var err error
+				candidates, complete, err = findTokenCandidates(stdoutReader)
+				if !complete {
+					// stop git instead of reading the rest of the diff
+					cancel()
+				}
+				return err
+			},
+		})
+	// git is killed if the diff was not read completely
+	if err != nil && (complete || candidates == nil) {
+		return nil, fmt.Errorf("git diff %s..%s: %w - %s", oldCommitID, newCommitID, err, stderr.String())
+	}
+	return candidates, nil
+}
+
+// DetectLeakedAccessTokens looks for personal access tokens of this instance on the lines added by a push.
+// Found tokens are revoked if configured, and their owners and the site administrators are notified.
+func DetectLeakedAccessTokens(ctx context.Context, repo *repo_model.Repository, oldCommitID, newCommitID string) error {
+	if !setting.DetectLeakedAccessTokens {
+		return nil
+	}
+
+	candidates, err := addedTokenCandidates(ctx, repo.RepoPath(), oldCommitID, newCommitID)
+	if err != nil || len(candidates) == 0 {
+		return err
+	}
+	values := make([]string, 0, len(candidates))
+	for value := range candidates {
+		values = append(values, value)
+	}
+
+	tokens, err := auth_model.FindAccessTokensByValues(ctx, values)
+	if err != nil {
+		return err
+	}
+	for _, t := range tokens {
+		path := candidates[t.Token]
+		revoked := false
+		if setting.RevokeLeakedAccessTokens {
+			if err := auth_model.DeleteAccessTokenByID(t.ID, t.UID); err != nil && !auth_model.IsErrAccessTokenNotExist(err) {
+				return err
+			}
+			revoked = true
+		}
+		log.Warn("Access token %d of user %d was pushed to %s in %s, revoked: %t", t.ID, t.UID, repo.FullName(), path, revoked)
+
+		owner, err := user_model.GetUserByID(ctx, t.UID)
+		if err != nil {
+			if user_model.IsErrUserNotExist(err) {
+				continue
+			}
+			return err
+		}
+		if err := system_model.CreateNotice(ctx, system_model.NoticeRepository, "Access token %q of %s was pushed to %s in %s, revoked: %t", t.Name, owner.Name, repo.FullName(), path, revoked); err != nil {
+			log.Error("CreateNotice: %v", err)
+		}
+		mailer.SendLeakedAccessTokenMail(owner, t.Name, repo, path, revoked)
+	}
+	return nil
+}
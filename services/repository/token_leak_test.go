@@ -0,0 +1,38 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package repository
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFindTokenCandidates(t *testing.T) {
+	diff := `diff --git a/.env b/.env
+new file mode 100644
+index 0000000..e69de29
+--- /dev/null
++++ b/.env
+@@ -0,0 +1,2 @@
++GITEA_TOKEN=d2c6c1ba3890b309189a8e618c72a162e4efbf36
++COMMIT=4c6f36e6cf498e2a448662f915d932c09c5a146c
+diff --git a/config.yml b/config.yml
+index 1234567..89abcde 100644
+--- a/config.yml
++++ b/config.yml
+@@ -1 +1 @@
+-token: 90a18faa671dc43924b795806ffe4fd169d28c91
++token: 90a18faa671dc43924b795806ffe4fd169d28c91x
++other: d2c6c1ba3890b309189a8e618c72a162e4efbf36
+`
+	candidates, complete, err := findTokenCandidates(strings.NewReader(diff))
+	assert.NoError(t, err)
+	assert.True(t, complete)
+	assert.Equal(t, map[string]string{
+		"d2c6c1ba3890b309189a8e618c72a162e4efbf36": ".env",
+		"4c6f36e6cf498e2a448662f915d932c09c5a146c": ".env",
+	}, candidates)
+}
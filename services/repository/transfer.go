@@ -17,6 +17,7 @@ import (
 	"code.gitea.io/gitea/modules/notification"
 	repo_module "code.gitea.io/gitea/modules/repository"
 	"code.gitea.io/gitea/modules/sync"
+	"code.gitea.io/gitea/modules/util"
 )
 
 // repoWorkingPool represents a working pool to order the parallel changes to the same repository
@@ -25,6 +26,9 @@ var repoWorkingPool = sync.NewExclusivePool()
 
 // TransferOwnership transfers all corresponding setting from old user to new one.
 func TransferOwnership(ctx context.Context, doer, newOwner *user_model.User, repo *repo_model.Repository, teams []*organization.Team) error {
+	if err := checkSameStorageRegion(newOwner, repo); err != nil {
+		return err
+	}
 	if err := repo.LoadOwner(ctx); err != nil {
 		return err
 	}
@@ -82,12 +86,23 @@ func ChangeRepositoryName(ctx context.Context, doer *user_model.User, repo *repo
 	return nil
 }
 
+// checkSameStorageRegion checks that a transfer doesn't move the data of a repository to another storage region
+func checkSameStorageRegion(newOwner *user_model.User, repo *repo_model.Repository) error {
+	if user_model.StorageRegionOfID(newOwner.ID) != repo.StorageRegion() {
+		return util.NewInvalidArgumentErrorf("the repository can't be transferred to an owner in another storage region")
+	}
+	return nil
+}
+
 // StartRepositoryTransfer transfer a repo from one owner to a new one.
 // it make repository into pending transfer state, if doer can not create repo for new owner.
 func StartRepositoryTransfer(ctx context.Context, doer, newOwner *user_model.User, repo *repo_model.Repository, teams []*organization.Team) error {
 	if err := models.TestRepositoryReadyForTransfer(repo.Status); err != nil {
 		return err
 	}
+	if err := checkSameStorageRegion(newOwner, repo); err != nil {
+		return err
+	}
 
 	// Admin is always allowed to transfer || user transfer repo back to his account
 	if doer.IsAdmin || doer.ID == newOwner.ID {
@@ -0,0 +1,143 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package spam
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/markup/common"
+	"code.gitea.io/gitea/modules/proxy"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/timeutil"
+)
+
+// honeypotChecker flags submissions which filled in the hidden form field
+type honeypotChecker struct{}
+
+func (c *honeypotChecker) Name() string { return "honeypot" }
+
+func (c *honeypotChecker) Check(_ context.Context, content *Content) (float64, string, error) {
+	if content.Honeypot == "" {
+		return 0, "", nil
+	}
+	return setting.Spam.HoneypotScore, "honeypot field was filled in", nil
+}
+
+// linkChecker flags content consisting mostly of links
+type linkChecker struct{}
+
+func (c *linkChecker) Name() string { return "links" }
+
+func (c *linkChecker) Check(_ context.Context, content *Content) (float64, string, error) {
+	text := content.Title + "\n" + content.Content
+	links := len(common.LinkRegex.FindAllStringIndex(text, -1))
+	if links == 0 {
+		return 0, "", nil
+	}
+	if setting.Spam.MaxLinks > 0 && links > setting.Spam.MaxLinks {
+		return setting.Spam.LinkScore, fmt.Sprintf("contains %d links", links), nil
+	}
+	words := len(strings.Fields(text))
+	if density := float64(links) / float64(words); setting.Spam.MaxLinkDensity > 0 && density > setting.Spam.MaxLinkDensity {
+		return setting.Spam.LinkScore, fmt.Sprintf("%d of %d words are links", links, words), nil
+	}
+	return 0, "", nil
+}
+
+// maxClassifierResponseSize limits how much of the classifier response is read
+const maxClassifierResponseSize = 64 * 1024
+
+var classifierClient = &http.Client{
+	Transport: &http.Transport{
+		Proxy: proxy.Proxy(),
+	},
+}
+
+type classifierRequest struct {
+	Type       string `json:"type"`
+	Username   string `json:"username"`
+	Email      string `json:"email"`
+	Repository string `json:"repository,omitempty"`
+	Title      string `json:"title"`
+	Content    string `json:"content"`
+	// AccountAge is the age of the account in seconds, 0 for registrations
+	AccountAge int64 `json:"account_age_seconds"`
+}
+
+type classifierResponse struct {
+	// Score is between 0 for ham and 1 for spam
+	Score  float64 `json:"score"`
+	Reason string  `json:"reason"`
+}
+
+// classifierChecker asks an external service to classify the content
+type classifierChecker struct{}
+
+func (c *classifierChecker) Name() string { return "classifier" }
+
+func (c *classifierChecker) Check(ctx context.Context, content *Content) (float64, string, error) {
+	if setting.Spam.ClassifierURL == "" {
+		return 0, "", nil
+	}
+
+	payload := &classifierRequest{
+		Type:    string(content.Type),
+		Title:   content.Title,
+		Content: content.Content,
+	}
+	if content.Poster != nil {
+		payload.Username = content.Poster.Name
+		payload.Email = content.Poster.Email
+		if content.Poster.CreatedUnix > 0 {
+			payload.AccountAge = int64(timeutil.TimeStampNow() - content.Poster.CreatedUnix)
+		}
+	}
+	if content.Repo != nil {
+		payload.Repository = content.Repo.FullName()
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, "", err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, setting.Spam.ClassifierTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, setting.Spam.ClassifierURL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Gitea "+setting.AppVer)
+	if setting.Spam.ClassifierToken != "" {
+		req.Header.Set("Authorization", "Bearer "+setting.Spam.ClassifierToken)
+	}
+
+	resp, err := classifierClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return 0, "", fmt.Errorf("classifier responded with status %d", resp.StatusCode)
+	}
+
+	var result classifierResponse
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxClassifierResponseSize)).Decode(&result); err != nil {
+		return 0, "", err
+	}
+	if result.Score <= 0 {
+		return 0, "", nil
+	}
+	reason := result.Reason
+	if reason == "" {
+		reason = fmt.Sprintf("classifier score %.2f", result.Score)
+	}
+	return result.Score * setting.Spam.ClassifierWeight, reason, nil
+}
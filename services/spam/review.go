@@ -0,0 +1,102 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package spam
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	moderation_model "code.gitea.io/gitea/models/moderation"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+	issue_service "code.gitea.io/gitea/services/issue"
+	pull_service "code.gitea.io/gitea/services/pull"
+)
+
+// Approve publishes held content: a held registration may log in, a held issue or pull request is created
+func Approve(ctx context.Context, held *moderation_model.HeldContent, doer *user_model.User) error {
+	if held.Status != moderation_model.HeldContentStatusPending {
+		return util.NewInvalidArgumentErrorf("held content was already %s", held.Status)
+	}
+
+	poster, err := user_model.GetUserByID(ctx, held.PosterID)
+	if err != nil {
+		return err
+	}
+
+	var resultID int64
+	switch held.Type {
+	case moderation_model.HeldContentTypeRegistration:
+		poster.ProhibitLogin = false
+		if err := user_model.UpdateUserCols(ctx, poster, "prohibit_login"); err != nil {
+			return err
+		}
+	case moderation_model.HeldContentTypeIssue, moderation_model.HeldContentTypePullRequest:
+		issue, err := createHeldIssue(ctx, held, poster)
+		if err != nil {
+			return err
+		}
+		resultID = issue.ID
+	default:
+		return util.NewInvalidArgumentErrorf("unknown held content type %q", held.Type)
+	}
+
+	return moderation_model.UpdateHeldContentStatus(ctx, held, moderation_model.HeldContentStatusApproved, doer.ID, resultID)
+}
+
+// Reject discards held content, a rejected registration stays prohibited from logging in
+func Reject(ctx context.Context, held *moderation_model.HeldContent, doer *user_model.User) error {
+	if held.Status != moderation_model.HeldContentStatusPending {
+		return util.NewInvalidArgumentErrorf("held content was already %s", held.Status)
+	}
+	return moderation_model.UpdateHeldContentStatus(ctx, held, moderation_model.HeldContentStatusRejected, doer.ID, 0)
+}
+
+func createHeldIssue(ctx context.Context, held *moderation_model.HeldContent, poster *user_model.User) (*issues_model.Issue, error) {
+	repo, err := repo_model.GetRepositoryByID(ctx, held.RepoID)
+	if err != nil {
+		return nil, err
+	}
+	payload := held.Payload
+	if payload == nil {
+		payload = &moderation_model.HeldIssuePayload{}
+	}
+
+	issue := &issues_model.Issue{
+		RepoID:      repo.ID,
+		Repo:        repo,
+		Title:       held.Title,
+		PosterID:    poster.ID,
+		Poster:      poster,
+		MilestoneID: payload.MilestoneID,
+		Content:     held.Content,
+		Ref:         payload.Ref,
+	}
+
+	if held.Type == moderation_model.HeldContentTypeIssue {
+		return issue, issue_service.NewIssue(ctx, repo, issue, payload.LabelIDs, payload.Attachments, payload.AssigneeIDs)
+	}
+
+	if payload.PullRequest == nil {
+		return nil, util.NewInvalidArgumentErrorf("held pull request has no branches")
+	}
+	headRepo, err := repo_model.GetRepositoryByID(ctx, payload.PullRequest.HeadRepoID)
+	if err != nil {
+		return nil, err
+	}
+	issue.IsPull = true
+	pr := &issues_model.PullRequest{
+		HeadRepoID:          headRepo.ID,
+		BaseRepoID:          repo.ID,
+		HeadBranch:          payload.PullRequest.HeadBranch,
+		BaseBranch:          payload.PullRequest.BaseBranch,
+		HeadRepo:            headRepo,
+		BaseRepo:            repo,
+		MergeBase:           payload.PullRequest.MergeBase,
+		Type:                issues_model.PullRequestGitea,
+		AllowMaintainerEdit: payload.PullRequest.AllowMaintainerEdit,
+	}
+	return issue, pull_service.NewPullRequest(ctx, repo, issue, payload.LabelIDs, payload.Attachments, pr, payload.AssigneeIDs)
+}
@@ -0,0 +1,211 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package spam
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	moderation_model "code.gitea.io/gitea/models/moderation"
+	access_model "code.gitea.io/gitea/models/perm/access"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/services/moderation"
+)
+
+// HoneypotField is the name of the hidden form field of the sign up, issue and pull request forms
+const HoneypotField = "contact_url"
+
+// Content is a registration or first contribution to be scored
+type Content struct {
+	Type    moderation_model.HeldContentType
+	Poster  *user_model.User
+	Repo    *repo_model.Repository
+	Title   string
+	Content string
+	// Honeypot is the value of the hidden form field which only bots fill in
+	Honeypot string
+}
+
+// Checker scores one aspect of content, a higher score means more likely spam
+type Checker interface {
+	Name() string
+	Check(ctx context.Context, c *Content) (score float64, reason string, err error)
+}
+
+var checkers = []Checker{
+	&honeypotChecker{},
+	&linkChecker{},
+	&classifierChecker{},
+}
+
+// RegisterChecker adds a checker to the scoring pipeline
+func RegisterChecker(c Checker) {
+	checkers = append(checkers, c)
+}
+
+// Result is the outcome of the scoring pipeline
+type Result struct {
+	Score   float64
+	Reasons []string
+}
+
+// IsSpam checks if the score reached the configured threshold
+func (r *Result) IsSpam() bool {
+	return r != nil && r.Score >= setting.Spam.Threshold
+}
+
+// Score runs the content through all checkers. Failing checkers are logged and
+// skipped so an unavailable classifier does not block registrations.
+func Score(ctx context.Context, c *Content) *Result {
+	result := &Result{}
+	for _, checker := range checkers {
+		score, reason, err := checker.Check(ctx, c)
+		if err != nil {
+			log.Error("Spam checker %s failed: %v", checker.Name(), err)
+			continue
+		}
+		if score > 0 {
+			result.Score += score
+			if reason != "" {
+				result.Reasons = append(result.Reasons, reason)
+			}
+		}
+	}
+	return result
+}
+
+// CheckRegistration scores a new account, it returns nil if registrations are not checked
+func CheckRegistration(ctx context.Context, u *user_model.User, honeypot string) *Result {
+	if !setting.Spam.Enabled || !setting.Spam.CheckRegistrations {
+		return nil
+	}
+	return Score(ctx, &Content{
+		Type:     moderation_model.HeldContentTypeRegistration,
+		Poster:   u,
+		Title:    u.Name,
+		Content:  u.Email,
+		Honeypot: honeypot,
+	})
+}
+
+// HoldRegistration puts a created account which was prohibited from logging in into the review queue
+func HoldRegistration(ctx context.Context, u *user_model.User, result *Result) error {
+	return moderation_model.CreateHeldContent(ctx, &moderation_model.HeldContent{
+		Type:     moderation_model.HeldContentTypeRegistration,
+		PosterID: u.ID,
+		Title:    u.Name,
+		Content:  u.Email,
+		Score:    result.Score,
+		Reasons:  result.Reasons,
+	})
+}
+
+// isFirstContribution checks if the poster never opened an issue or pull request in the repository.
+// Users with write access are trusted.
+func isFirstContribution(ctx context.Context, poster *user_model.User, repo *repo_model.Repository) (bool, error) {
+	if poster.IsAdmin || poster.ID <= 0 {
+		return false, nil
+	}
+	perm, err := access_model.GetUserRepoPermission(ctx, repo, poster)
+	if err != nil {
+		return false, err
+	}
+	if perm.IsAdmin() || perm.CanWriteIssuesOrPulls(false) || perm.CanWriteIssuesOrPulls(true) {
+		return false, nil
+	}
+	count, err := issues_model.CountIssues(ctx, &issues_model.IssuesOptions{
+		RepoIDs:  []int64{repo.ID},
+		PosterID: poster.ID,
+	})
+	if err != nil {
+		return false, err
+	}
+	return count == 0, nil
+}
+
+func checkContribution(ctx context.Context, repo *repo_model.Repository, issue *issues_model.Issue, honeypot string) (*Result, error) {
+	if !setting.Spam.Enabled || !setting.Spam.CheckFirstContributions {
+		return nil, nil
+	}
+	// blocked users must not be able to fill the review queue
+	if err := moderation.CheckInteraction(ctx, issue.Poster, repo); err != nil {
+		return nil, err
+	}
+	first, err := isFirstContribution(ctx, issue.Poster, repo)
+	if err != nil || !first {
+		return nil, err
+	}
+	contentType := moderation_model.HeldContentTypeIssue
+	if issue.IsPull {
+		contentType = moderation_model.HeldContentTypePullRequest
+	}
+	return Score(ctx, &Content{
+		Type:     contentType,
+		Poster:   issue.Poster,
+		Repo:     repo,
+		Title:    issue.Title,
+		Content:  issue.Content,
+		Honeypot: honeypot,
+	}), nil
+}
+
+// HoldIssueIfSpam puts a first issue which scores as spam into the review queue instead of creating it.
+// It returns whether the issue was held.
+func HoldIssueIfSpam(ctx context.Context, repo *repo_model.Repository, issue *issues_model.Issue, labelIDs []int64, uuids []string, assigneeIDs []int64, honeypot string) (bool, error) {
+	result, err := checkContribution(ctx, repo, issue, honeypot)
+	if err != nil || !result.IsSpam() {
+		return false, err
+	}
+	return true, moderation_model.CreateHeldContent(ctx, &moderation_model.HeldContent{
+		Type:     moderation_model.HeldContentTypeIssue,
+		PosterID: issue.PosterID,
+		RepoID:   repo.ID,
+		Title:    issue.Title,
+		Content:  issue.Content,
+		Payload: &moderation_model.HeldIssuePayload{
+			LabelIDs:    labelIDs,
+			AssigneeIDs: assigneeIDs,
+			MilestoneID: issue.MilestoneID,
+			Attachments: uuids,
+			Ref:         issue.Ref,
+		},
+		Score:   result.Score,
+		Reasons: result.Reasons,
+	})
+}
+
+// HoldPullRequestIfSpam puts a first pull request which scores as spam into the review queue instead of creating it.
+// It returns whether the pull request was held.
+func HoldPullRequestIfSpam(ctx context.Context, repo *repo_model.Repository, pull *issues_model.Issue, labelIDs []int64, uuids []string, pr *issues_model.PullRequest, assigneeIDs []int64, honeypot string) (bool, error) {
+	result, err := checkContribution(ctx, repo, pull, honeypot)
+	if err != nil || !result.IsSpam() {
+		return false, err
+	}
+	return true, moderation_model.CreateHeldContent(ctx, &moderation_model.HeldContent{
+		Type:     moderation_model.HeldContentTypePullRequest,
+		PosterID: pull.PosterID,
+		RepoID:   repo.ID,
+		Title:    pull.Title,
+		Content:  pull.Content,
+		Payload: &moderation_model.HeldIssuePayload{
+			LabelIDs:    labelIDs,
+			AssigneeIDs: assigneeIDs,
+			MilestoneID: pull.MilestoneID,
+			Attachments: uuids,
+			PullRequest: &moderation_model.HeldPullRequest{
+				HeadRepoID: pr.HeadRepoID,
+				HeadBranch: pr.HeadBranch,
+				BaseBranch: pr.BaseBranch,
+				MergeBase:  pr.MergeBase,
+
+				AllowMaintainerEdit: pr.AllowMaintainerEdit,
+			},
+		},
+		Score:   result.Score,
+		Reasons: result.Reasons,
+	})
+}
@@ -0,0 +1,35 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package spam
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScore(t *testing.T) {
+	ctx := context.Background()
+	assert.Empty(t, setting.Spam.ClassifierURL)
+
+	result := Score(ctx, &Content{Title: "Crash on startup", Content: "See https://example.com/log for the log"})
+	assert.Zero(t, result.Score)
+	assert.False(t, result.IsSpam())
+
+	result = Score(ctx, &Content{Title: "Hello", Honeypot: "https://example.com"})
+	assert.EqualValues(t, setting.Spam.HoneypotScore, result.Score)
+	assert.True(t, result.IsSpam())
+
+	result = Score(ctx, &Content{Title: "Cheap", Content: "https://a.example https://b.example buy"})
+	assert.EqualValues(t, setting.Spam.LinkScore, result.Score)
+	assert.Len(t, result.Reasons, 1)
+
+	result = Score(ctx, &Content{Content: strings.Repeat("word https://example.com word word word word word ", 6)})
+	assert.EqualValues(t, setting.Spam.LinkScore, result.Score)
+	assert.Contains(t, result.Reasons[0], "6 links")
+}
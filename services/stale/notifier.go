@@ -0,0 +1,47 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package stale
+
+import (
+	"context"
+
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification/base"
+)
+
+type staleNotifier struct {
+	base.NullNotifier
+}
+
+var _ base.Notifier = &staleNotifier{}
+
+// NewNotifier create a new staleNotifier notifier
+func NewNotifier() base.Notifier {
+	return &staleNotifier{}
+}
+
+// NotifyCreateIssueComment removes the stale label when somebody comments on a stale issue
+func (*staleNotifier) NotifyCreateIssueComment(ctx context.Context, doer *user_model.User, repo *repo_model.Repository,
+	issue *issues_model.Issue, comment *issues_model.Comment, mentions []*user_model.User,
+) {
+	if doer.IsActions() || issue.IsClosed {
+		return
+	}
+	if err := removeStaleLabel(ctx, issue); err != nil {
+		log.Error("Unable to remove the stale label of issue %d: %v", issue.ID, err)
+	}
+}
+
+// NotifyIssueChangeStatus removes the stale label when a stale issue is reopened
+func (*staleNotifier) NotifyIssueChangeStatus(ctx context.Context, doer *user_model.User, commitID string, issue *issues_model.Issue, actionComment *issues_model.Comment, isClosed bool) {
+	if isClosed {
+		return
+	}
+	if err := removeStaleLabel(ctx, issue); err != nil {
+		log.Error("Unable to remove the stale label of issue %d: %v", issue.ID, err)
+	}
+}
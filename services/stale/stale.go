@@ -0,0 +1,176 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package stale
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/notification"
+	"code.gitea.io/gitea/modules/util"
+	issue_service "code.gitea.io/gitea/services/issue"
+)
+
+const (
+	// DefaultStaleComment is posted when an issue is marked stale and the policy has no comment
+	DefaultStaleComment = "This has been automatically marked as stale because it has not had recent activity. It will be closed if no further activity occurs."
+	// DefaultCloseComment is posted when a stale issue is closed and the policy has no comment
+	DefaultCloseComment = "This has been automatically closed because it has not had any activity since being marked as stale."
+
+	pageSize = 50
+)
+
+// Init listens for activity on stale issues
+func Init() error {
+	notification.RegisterNotifier(NewNotifier())
+	return nil
+}
+
+// ProcessAll runs all enabled stale policies, each policy handles at most operationsPerRepo issues per run
+func ProcessAll(ctx context.Context, operationsPerRepo int) error {
+	policies, err := issues_model.FindEnabledStalePolicies(ctx)
+	if err != nil {
+		return err
+	}
+	for _, policy := range policies {
+		select {
+		case <-ctx.Done():
+			return db.ErrCancelledf("before processing the stale policy of repository %d", policy.RepoID)
+		default:
+		}
+
+		repo, err := repo_model.GetRepositoryByID(ctx, policy.RepoID)
+		if err != nil {
+			log.Error("GetRepositoryByID[%d]: %v", policy.RepoID, err)
+			continue
+		}
+		if repo.IsArchived {
+			continue
+		}
+		if err := Process(ctx, repo, policy, operationsPerRepo); err != nil {
+			log.Error("Unable to process the stale policy of %s: %v", repo.FullName(), err)
+		}
+	}
+	return nil
+}
+
+// Process closes the stale issues of a repository which have been inactive for too long
+// and marks the issues which have been inactive as stale
+func Process(ctx context.Context, repo *repo_model.Repository, policy *issues_model.StalePolicy, operations int) error {
+	staleLabel, err := issues_model.GetLabelInRepoByID(ctx, repo.ID, policy.StaleLabelID)
+	if err != nil {
+		return fmt.Errorf("GetLabelInRepoByID: %w", err)
+	}
+	doer := user_model.NewActionsUser()
+	now := time.Now()
+
+	if policy.DaysUntilClose > 0 {
+		issues, err := findCandidates(ctx, repo, policy, []int64{staleLabel.ID}, now.AddDate(0, 0, -policy.DaysUntilClose), operations)
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			if err := issue_service.ChangeStatus(issue, doer, "", true); err != nil {
+				if issues_model.IsErrDependenciesLeft(err) {
+					log.Debug("Stale issue %s#%d has open dependencies, not closing it", repo.FullName(), issue.Index)
+					continue
+				}
+				return err
+			}
+			comment := policy.CloseComment
+			if comment == "" {
+				comment = DefaultCloseComment
+			}
+			if _, err := issue_service.CreateIssueComment(ctx, doer, repo, issue, comment, nil); err != nil {
+				return err
+			}
+			operations--
+		}
+	}
+
+	if operations > 0 {
+		issues, err := findCandidates(ctx, repo, policy, []int64{-staleLabel.ID}, now.AddDate(0, 0, -policy.DaysUntilStale), operations)
+		if err != nil {
+			return err
+		}
+		for _, issue := range issues {
+			if err := issue_service.AddLabel(issue, doer, staleLabel); err != nil {
+				return err
+			}
+			comment := policy.StaleComment
+			if comment == "" {
+				comment = DefaultStaleComment
+			}
+			if _, err := issue_service.CreateIssueComment(ctx, doer, repo, issue, comment, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	return issues_model.UpdateStalePolicyLastRun(ctx, policy)
+}
+
+// findCandidates returns up to limit open, non-exempt issues matching the label filter which have not been updated since the given time
+func findCandidates(ctx context.Context, repo *repo_model.Repository, policy *issues_model.StalePolicy, labelIDs []int64, updatedBefore time.Time, limit int) ([]*issues_model.Issue, error) {
+	opts := &issues_model.IssuesOptions{
+		ListOptions:       db.ListOptions{Page: 1, PageSize: pageSize},
+		RepoIDs:           []int64{repo.ID},
+		IsClosed:          util.OptionalBoolFalse,
+		LabelIDs:          labelIDs,
+		UpdatedBeforeUnix: updatedBefore.Unix(),
+		SortType:          "leastupdate",
+	}
+	if policy.ApplyToIssues != policy.ApplyToPulls {
+		opts.IsPull = util.OptionalBoolOf(policy.ApplyToPulls)
+	}
+
+	candidates := make([]*issues_model.Issue, 0, limit)
+	for len(candidates) < limit {
+		issues, err := issues_model.Issues(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		for _, issue := range issues {
+			issue.Repo = repo
+			if !policy.IsExempt(issue) && len(candidates) < limit {
+				candidates = append(candidates, issue)
+			}
+		}
+		if len(issues) < pageSize {
+			break
+		}
+		opts.Page++
+	}
+	return candidates, nil
+}
+
+// removeStaleLabel removes the stale label of the policy from the issue if it has it
+func removeStaleLabel(ctx context.Context, issue *issues_model.Issue) error {
+	policy, err := issues_model.GetStalePolicy(ctx, issue.RepoID)
+	if err != nil || policy == nil || !policy.Enabled {
+		return err
+	}
+	if !issues_model.HasIssueLabel(ctx, issue.ID, policy.StaleLabelID) {
+		return nil
+	}
+	label, err := issues_model.GetLabelByID(ctx, policy.StaleLabelID)
+	if err != nil {
+		return err
+	}
+
+	doer := user_model.NewActionsUser()
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		return issues_model.DeleteIssueLabel(ctx, issue, label, doer)
+	}); err != nil {
+		return err
+	}
+	notification.NotifyIssueChangeLabels(ctx, doer, issue, nil, []*issues_model.Label{label})
+	return nil
+}
@@ -0,0 +1,223 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package theme
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	system_model "code.gitea.io/gitea/models/system"
+	"code.gitea.io/gitea/modules/avatar"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	"code.gitea.io/gitea/modules/typesniffer"
+	"code.gitea.io/gitea/modules/util"
+)
+
+const (
+	maxVariables     = 200
+	maxVariableValue = 200
+)
+
+var (
+	namePattern         = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,29}$`)
+	variableNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9-]{0,63}$`)
+	accentColorPattern  = regexp.MustCompile(`^#(?:[0-9a-fA-F]{3}|[0-9a-fA-F]{6})$`)
+
+	// ErrInvalidLogo is returned if a logo isn't a raster image within the size limit of avatars
+	ErrInvalidLogo = util.NewInvalidArgumentErrorf("the logo must be a PNG, JPEG, GIF or WebP image within the size limit of avatars")
+)
+
+// IsBuiltinTheme returns if a theme is one of the themes of the config
+func IsBuiltinTheme(name string) bool {
+	for _, t := range setting.UI.Themes {
+		if strings.EqualFold(t, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateName checks that the name of a theme can be used in CSS classes and doesn't shadow a theme of the config
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return util.NewInvalidArgumentErrorf("the theme name %q must consist of at most 30 lowercase letters, digits and dashes", name)
+	}
+	if IsBuiltinTheme(name) {
+		return util.NewInvalidArgumentErrorf("the theme name %q is used by a theme of the config", name)
+	}
+	return nil
+}
+
+// ValidateVariables checks that the variables can't escape their declarations in the stylesheet of the theme
+func ValidateVariables(variables map[string]string) error {
+	if len(variables) > maxVariables {
+		return util.NewInvalidArgumentErrorf("a theme can define at most %d variables", maxVariables)
+	}
+	for name, value := range variables {
+		if !variableNamePattern.MatchString(name) {
+			return util.NewInvalidArgumentErrorf("invalid CSS variable name %q, it must be given without the leading dashes", name)
+		}
+		if value = strings.TrimSpace(value); value == "" || len(value) > maxVariableValue || strings.ContainsAny(value, ";{}<>\\\"'\n\r") {
+			return util.NewInvalidArgumentErrorf("invalid value of the CSS variable %q", name)
+		}
+		variables[name] = value
+	}
+	return nil
+}
+
+// ValidateAccentColor checks that an accent color is a hex color
+func ValidateAccentColor(color string) error {
+	if color != "" && !accentColorPattern.MatchString(color) {
+		return util.NewInvalidArgumentErrorf("the accent color %q must be a hex color like #4183c4", color)
+	}
+	return nil
+}
+
+// CreateTheme validates and creates a theme
+func CreateTheme(ctx context.Context, t *system_model.Theme) error {
+	t.Name = strings.ToLower(t.Name)
+	if err := ValidateName(t.Name); err != nil {
+		return err
+	}
+	if err := ValidateVariables(t.Variables); err != nil {
+		return err
+	}
+	return system_model.CreateTheme(ctx, t)
+}
+
+// UpdateTheme validates and updates the columns of a theme. Organizations keep using a theme which is
+// not approved for branding anymore, but their pages are rendered with the default theme until it is again.
+func UpdateTheme(ctx context.Context, t *system_model.Theme, cols ...string) error {
+	if err := ValidateVariables(t.Variables); err != nil {
+		return err
+	}
+	return system_model.UpdateTheme(ctx, t, cols...)
+}
+
+// DeleteTheme deletes a theme with its logo and the brandings using it.
+// Users who chose it are shown the default theme.
+func DeleteTheme(ctx context.Context, t *system_model.Theme) error {
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		if err := organization.DeleteBrandingsByThemeID(ctx, t.ID); err != nil {
+			return err
+		}
+		return system_model.DeleteTheme(ctx, t.ID)
+	}); err != nil {
+		return err
+	}
+	removeLogo(t.Logo)
+	return nil
+}
+
+// UploadLogo replaces the logo of a theme
+func UploadLogo(ctx context.Context, t *system_model.Theme, data []byte) error {
+	if int64(len(data)) > setting.Avatar.MaxFileSize {
+		return ErrInvalidLogo
+	}
+	if ct := typesniffer.DetectContentType(data); !ct.IsImage() || ct.IsSvgImage() {
+		// SVG images could run scripts when they are opened from the avatar storage
+		return ErrInvalidLogo
+	}
+
+	oldLogo := t.Logo
+	t.Logo = fmt.Sprintf("themes/%s-%s", t.Name, avatar.HashAvatarContent(data))
+	if err := storage.SaveFrom(storage.Avatars, t.Logo, func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	}); err != nil {
+		return fmt.Errorf("save logo %s: %w", t.Logo, err)
+	}
+	if err := system_model.UpdateTheme(ctx, t, "logo"); err != nil {
+		return err
+	}
+	if oldLogo != t.Logo {
+		removeLogo(oldLogo)
+	}
+	return nil
+}
+
+// DeleteLogo removes the logo of a theme
+func DeleteLogo(ctx context.Context, t *system_model.Theme) error {
+	oldLogo := t.Logo
+	t.Logo = ""
+	if err := system_model.UpdateTheme(ctx, t, "logo"); err != nil {
+		return err
+	}
+	removeLogo(oldLogo)
+	return nil
+}
+
+func removeLogo(logo string) {
+	if logo == "" {
+		return
+	}
+	if err := storage.Avatars.Delete(logo); err != nil {
+		log.Warn("Unable to remove the theme logo %s: %v", logo, err)
+	}
+}
+
+// IsSelectableTheme returns if users can choose a theme, either from the config or managed by the administrators
+func IsSelectableTheme(ctx context.Context, name string) (bool, error) {
+	if IsBuiltinTheme(name) {
+		return true, nil
+	}
+	if _, err := system_model.GetThemeByName(ctx, name); err != nil {
+		if system_model.IsErrThemeNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// SelectableThemeNames returns the names of the themes of the config followed by the ones managed by the administrators
+func SelectableThemeNames(ctx context.Context) ([]string, error) {
+	themes, err := system_model.GetThemes(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(setting.UI.Themes)+len(themes))
+	names = append(names, setting.UI.Themes...)
+	for _, t := range themes {
+		names = append(names, t.Name)
+	}
+	return names, nil
+}
+
+// SetOrgBranding sets the theme and the accent color of an organization, the theme must be approved for branding
+func SetOrgBranding(ctx context.Context, orgID int64, themeName, accentColor string) (*organization.Branding, error) {
+	if themeName == "" && accentColor == "" {
+		return nil, util.NewInvalidArgumentErrorf("a branding needs a theme or an accent color")
+	}
+	if err := ValidateAccentColor(accentColor); err != nil {
+		return nil, err
+	}
+
+	b := &organization.Branding{OrgID: orgID, AccentColor: strings.ToLower(accentColor)}
+	if themeName != "" {
+		t, err := system_model.GetThemeByName(ctx, themeName)
+		if err != nil {
+			if system_model.IsErrThemeNotExist(err) {
+				return nil, util.NewInvalidArgumentErrorf("theme %q does not exist", themeName)
+			}
+			return nil, err
+		}
+		if !t.OrgBranding {
+			return nil, util.NewInvalidArgumentErrorf("theme %q is not approved for organizations", themeName)
+		}
+		b.ThemeID = t.ID
+		b.Theme = t
+	}
+	if err := organization.SetBranding(ctx, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
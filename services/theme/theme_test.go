@@ -0,0 +1,45 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package theme
+
+import (
+	"testing"
+
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateName(t *testing.T) {
+	assert.NoError(t, ValidateName("acme-dark"))
+	for _, name := range []string{"", "Acme", "-acme", "acme_dark", "arc-green", "gitea", "a23456789012345678901234567890x"} {
+		assert.ErrorIs(t, ValidateName(name), util.ErrInvalidArgument, name)
+	}
+}
+
+func TestValidateVariables(t *testing.T) {
+	variables := map[string]string{"color-primary": " #d94a2b ", "fonts-proportional": "Inter, sans-serif"}
+	assert.NoError(t, ValidateVariables(variables))
+	assert.Equal(t, "#d94a2b", variables["color-primary"])
+
+	for _, variables := range []map[string]string{
+		{"--color-primary": "red"},
+		{"color primary": "red"},
+		{"color-primary": ""},
+		{"color-primary": "red; } body { display: none"},
+		{"color-primary": "red</style><script>"},
+		{"fonts-proportional": `"Inter"`},
+	} {
+		assert.ErrorIs(t, ValidateVariables(variables), util.ErrInvalidArgument, variables)
+	}
+}
+
+func TestValidateAccentColor(t *testing.T) {
+	for _, color := range []string{"", "#fff", "#4183C4"} {
+		assert.NoError(t, ValidateAccentColor(color), color)
+	}
+	for _, color := range []string{"red", "#ff", "#4183c4ff", "4183c4"} {
+		assert.ErrorIs(t, ValidateAccentColor(color), util.ErrInvalidArgument, color)
+	}
+}
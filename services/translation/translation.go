@@ -0,0 +1,121 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package translation
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	system_model "code.gitea.io/gitea/models/system"
+	user_model "code.gitea.io/gitea/models/user"
+	translation_module "code.gitea.io/gitea/modules/translation"
+	"code.gitea.io/gitea/modules/translation/i18n"
+	"code.gitea.io/gitea/modules/util"
+)
+
+// MaxPackSize is the maximum size of an uploaded locale pack
+const MaxPackSize = 4 * 1024 * 1024
+
+// Init applies the overrides stored in the database to the locales
+func Init(ctx context.Context) error {
+	return reload(ctx)
+}
+
+func reload(ctx context.Context) error {
+	overrides, err := system_model.GetTranslationOverrides(ctx, "")
+	if err != nil {
+		return err
+	}
+	messages := make(map[string]map[string]string)
+	for _, o := range overrides {
+		if messages[o.Lang] == nil {
+			messages[o.Lang] = make(map[string]string)
+		}
+		messages[o.Lang][o.TrKey] = o.Message
+	}
+	translation_module.SetOverrides(messages)
+	return nil
+}
+
+// ValidateLang checks that a language is supported
+func ValidateLang(lang string) error {
+	if !translation_module.HasLang(lang) {
+		return util.NewInvalidArgumentErrorf("language %q is not supported", lang)
+	}
+	return nil
+}
+
+// SetOverride overrides the message of a known key in a language
+func SetOverride(ctx context.Context, doer *user_model.User, lang, trKey, message string) error {
+	if err := ValidateLang(lang); err != nil {
+		return err
+	}
+	if !translation_module.HasKey(trKey) {
+		return util.NewInvalidArgumentErrorf("translation key %q does not exist", trKey)
+	}
+	if strings.TrimSpace(message) == "" {
+		return util.NewInvalidArgumentErrorf("the message must not be empty, delete the override to restore the original one")
+	}
+	if err := system_model.SetTranslationOverrides(ctx, lang, map[string]string{trKey: message}, doer.ID); err != nil {
+		return err
+	}
+	return reload(ctx)
+}
+
+// DeleteOverride restores the original message of a key in a language
+func DeleteOverride(ctx context.Context, lang, trKey string) error {
+	if err := system_model.DeleteTranslationOverride(ctx, lang, trKey); err != nil {
+		return err
+	}
+	return reload(ctx)
+}
+
+// DeleteOverrides restores the original messages of a language
+func DeleteOverrides(ctx context.Context, lang string) error {
+	if err := system_model.DeleteTranslationOverrides(ctx, lang); err != nil {
+		return err
+	}
+	return reload(ctx)
+}
+
+// UploadLocalePack overrides the messages of a language with the ones of an ini locale file.
+// Keys which are unknown or have an empty message are ignored and returned sorted.
+// If replace is set, the overrides of the language which aren't in the pack are removed.
+func UploadLocalePack(ctx context.Context, doer *user_model.User, lang string, data []byte, replace bool) (imported int, ignored []string, err error) {
+	if err := ValidateLang(lang); err != nil {
+		return 0, nil, err
+	}
+	if len(data) > MaxPackSize {
+		return 0, nil, util.NewInvalidArgumentErrorf("the locale pack must not be larger than %d bytes", MaxPackSize)
+	}
+	parsed, err := i18n.ParseMessages(data)
+	if err != nil {
+		return 0, nil, util.NewInvalidArgumentErrorf("invalid locale pack: %v", err)
+	}
+
+	messages := make(map[string]string, len(parsed))
+	ignored = make([]string, 0)
+	for trKey, msg := range parsed {
+		if strings.TrimSpace(msg) == "" || !translation_module.HasKey(trKey) {
+			ignored = append(ignored, trKey)
+			continue
+		}
+		messages[trKey] = msg
+	}
+	sort.Strings(ignored)
+
+	if replace {
+		if err := system_model.DeleteTranslationOverrides(ctx, lang); err != nil {
+			return 0, nil, err
+		}
+	}
+	if err := system_model.SetTranslationOverrides(ctx, lang, messages, doer.ID); err != nil {
+		return 0, nil, err
+	}
+	if err := reload(ctx); err != nil {
+		return 0, nil, err
+	}
+	return len(messages), ignored, nil
+}
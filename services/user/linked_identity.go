@@ -0,0 +1,159 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/cache"
+	"code.gitea.io/gitea/modules/json"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/setting"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/modules/timeutil"
+	"code.gitea.io/gitea/modules/util"
+	webhook_service "code.gitea.io/gitea/services/webhook"
+)
+
+const (
+	// linkedIdentityRequestTimeout is the time another instance has to answer a request
+	linkedIdentityRequestTimeout = 10 * time.Second
+	// linkedIdentityMaxResponseSize is the maximum size of an answer of another instance
+	linkedIdentityMaxResponseSize = 1 << 20
+	// linkedHeatmapCacheSeconds is how long the heatmap of a linked identity is cached
+	linkedHeatmapCacheSeconds = 3600
+)
+
+// getFromLinkedInstance requests the API of the user on the other instance and decodes the JSON answer into v
+func getFromLinkedInstance(ctx context.Context, identity *user_model.LinkedIdentity, subPath string, v interface{}) error {
+	ctx, cancel := context.WithTimeout(ctx, linkedIdentityRequestTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, identity.InstanceURL+"/api/v1/users/"+url.PathEscape(identity.RemoteUsername)+subPath, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "Gitea "+setting.AppVer)
+
+	resp, err := webhook_service.DoRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, linkedIdentityMaxResponseSize))
+		return fmt.Errorf("%s responded with status %d", identity.InstanceURL, resp.StatusCode)
+	}
+	return json.NewDecoder(io.LimitReader(resp.Body, linkedIdentityMaxResponseSize)).Decode(v)
+}
+
+// VerifyLinkedIdentity checks that the token of the linked identity is in the profile description
+// of the account on the other instance, which proves the account belongs to the user
+func VerifyLinkedIdentity(ctx context.Context, identity *user_model.LinkedIdentity) error {
+	if identity.IsVerified {
+		return nil
+	}
+
+	remoteUser := &api.User{}
+	if err := getFromLinkedInstance(ctx, identity, "", remoteUser); err != nil {
+		log.Debug("Unable to get the account %s on %s: %v", identity.RemoteUsername, identity.InstanceURL, err)
+		return util.NewInvalidArgumentErrorf("unable to get the account %s on %s", identity.RemoteUsername, identity.InstanceURL)
+	}
+	if !strings.Contains(remoteUser.Description, identity.Token) {
+		return util.NewInvalidArgumentErrorf("the token was not found in the profile description of %s", identity.ProfileURL())
+	}
+	return user_model.MarkLinkedIdentityVerified(ctx, identity)
+}
+
+// getLinkedHeatmapData returns the heatmap of the public activity of the linked identity, which is cached for a while
+func getLinkedHeatmapData(ctx context.Context, identity *user_model.LinkedIdentity) ([]*activities_model.UserHeatmapData, error) {
+	key := "linked_identity_heatmap_" + strconv.FormatInt(identity.ID, 10)
+	c := cache.GetCache()
+	if c != nil {
+		if cached, ok := c.Get(key).(string); ok {
+			var hdata []*activities_model.UserHeatmapData
+			if err := json.Unmarshal([]byte(cached), &hdata); err == nil {
+				return hdata, nil
+			}
+		}
+	}
+
+	var hdata []*activities_model.UserHeatmapData
+	if err := getFromLinkedInstance(ctx, identity, "/heatmap", &hdata); err != nil {
+		return nil, err
+	}
+
+	if c != nil {
+		if data, err := json.Marshal(hdata); err == nil {
+			if err := c.Put(key, string(data), linkedHeatmapCacheSeconds); err != nil {
+				log.Warn("Unable to cache the heatmap of linked identity %d: %v", identity.ID, err)
+			}
+		}
+	}
+	return hdata, nil
+}
+
+// MergeHeatmapData adds up the contributions of the heatmaps per timestamp
+func MergeHeatmapData(heatmaps ...[]*activities_model.UserHeatmapData) []*activities_model.UserHeatmapData {
+	contributions := make(map[int64]int64)
+	for _, hdata := range heatmaps {
+		for _, v := range hdata {
+			if v != nil && v.Contributions > 0 {
+				contributions[int64(v.Timestamp)] += v.Contributions
+			}
+		}
+	}
+
+	merged := make([]*activities_model.UserHeatmapData, 0, len(contributions))
+	for timestamp, count := range contributions {
+		merged = append(merged, &activities_model.UserHeatmapData{Timestamp: timeutil.TimeStamp(timestamp), Contributions: count})
+	}
+	sort.Slice(merged, func(i, j int) bool {
+		return merged[i].Timestamp < merged[j].Timestamp
+	})
+	return merged
+}
+
+// GetUserHeatmapDataWithLinkedIdentities returns the heatmap of the user including the public activity of
+// the verified accounts of the user on other instances. Instances which can not be reached are skipped.
+func GetUserHeatmapDataWithLinkedIdentities(ctx context.Context, user, doer *user_model.User) ([]*activities_model.UserHeatmapData, error) {
+	hdata, err := activities_model.GetUserHeatmapDataByUser(user, doer)
+	if err != nil {
+		return nil, err
+	}
+	if !setting.Service.EnableLinkedIdentities || user.IsOrganization() || !activities_model.ActivityReadable(user, doer) {
+		return hdata, nil
+	}
+
+	identities, err := user_model.GetVerifiedLinkedIdentities(ctx, user.ID)
+	if err != nil {
+		return nil, err
+	} else if len(identities) == 0 {
+		return hdata, nil
+	}
+
+	heatmaps := make([][]*activities_model.UserHeatmapData, 0, len(identities)+1)
+	heatmaps = append(heatmaps, hdata)
+	for _, identity := range identities {
+		linked, err := getLinkedHeatmapData(ctx, identity)
+		if err != nil {
+			log.Warn("Unable to get the heatmap of %s: %v", identity.ProfileURL(), err)
+			continue
+		}
+		heatmaps = append(heatmaps, linked)
+	}
+	return MergeHeatmapData(heatmaps...), nil
+}
@@ -0,0 +1,31 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"testing"
+
+	activities_model "code.gitea.io/gitea/models/activities"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeHeatmapData(t *testing.T) {
+	local := []*activities_model.UserHeatmapData{
+		{Timestamp: 900, Contributions: 1},
+		{Timestamp: 2700, Contributions: 2},
+	}
+	linked := []*activities_model.UserHeatmapData{
+		{Timestamp: 1800, Contributions: 3},
+		{Timestamp: 2700, Contributions: 4},
+		{Timestamp: 3600, Contributions: 0},
+	}
+
+	assert.Equal(t, []*activities_model.UserHeatmapData{
+		{Timestamp: 900, Contributions: 1},
+		{Timestamp: 1800, Contributions: 3},
+		{Timestamp: 2700, Contributions: 6},
+	}, MergeHeatmapData(local, linked, nil))
+	assert.Empty(t, MergeHeatmapData())
+}
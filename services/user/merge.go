@@ -0,0 +1,257 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"context"
+	"fmt"
+
+	"code.gitea.io/gitea/models"
+	asymkey_model "code.gitea.io/gitea/models/asymkey"
+	"code.gitea.io/gitea/models/db"
+	issues_model "code.gitea.io/gitea/models/issues"
+	"code.gitea.io/gitea/models/organization"
+	packages_model "code.gitea.io/gitea/models/packages"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/util"
+	container_service "code.gitea.io/gitea/services/packages/container"
+	repo_service "code.gitea.io/gitea/services/repository"
+
+	"xorm.io/builder"
+)
+
+// MergeConflictType is the kind of data which prevents merging a user into another one
+type MergeConflictType string
+
+const (
+	MergeConflictRepository    MergeConflictType = "repository"
+	MergeConflictPackage       MergeConflictType = "package"
+	MergeConflictStorageRegion MergeConflictType = "storage_region"
+)
+
+// MergeConflict is data of the merged user which can not be moved to the target user
+type MergeConflict struct {
+	Type MergeConflictType
+	Name string
+}
+
+// MergeReport lists the data moved by merging a user into another one, and the conflicts preventing it
+type MergeReport struct {
+	Repositories int64
+	Issues       int64
+	Comments     int64
+	Stars        int64
+	PublicKeys   int64
+	GPGKeys      int64
+	Packages     int64
+	Teams        int64
+	Conflicts    []*MergeConflict
+}
+
+// newMergeReport counts the data of the source user and looks for the conflicts with the target user
+func newMergeReport(ctx context.Context, source, target *user_model.User) (*MergeReport, error) {
+	e := db.GetEngine(ctx)
+	report := &MergeReport{}
+
+	var err error
+	for _, c := range []struct {
+		count *int64
+		bean  interface{}
+		cond  builder.Cond
+	}{
+		{&report.Issues, new(issues_model.Issue), builder.Eq{"poster_id": source.ID}},
+		{&report.Comments, new(issues_model.Comment), builder.Eq{"poster_id": source.ID}},
+		{&report.Stars, new(repo_model.Star), builder.Eq{"uid": source.ID}},
+		{&report.PublicKeys, new(asymkey_model.PublicKey), builder.Eq{"owner_id": source.ID}},
+		{&report.GPGKeys, new(asymkey_model.GPGKey), builder.Eq{"owner_id": source.ID, "primary_key_id": ""}},
+		{&report.Teams, new(organization.TeamUser), builder.Eq{"uid": source.ID}},
+	} {
+		if *c.count, err = e.Where(c.cond).Count(c.bean); err != nil {
+			return nil, err
+		}
+	}
+
+	repos := make([]*repo_model.Repository, 0, 10)
+	if err := e.Where("owner_id = ?", source.ID).Find(&repos); err != nil {
+		return nil, err
+	}
+	report.Repositories = int64(len(repos))
+	if len(repos) > 0 && user_model.StorageRegionOfID(source.ID) != user_model.StorageRegionOfID(target.ID) {
+		report.Conflicts = append(report.Conflicts, &MergeConflict{Type: MergeConflictStorageRegion, Name: user_model.StorageRegionOfID(source.ID)})
+	}
+	for _, repo := range repos {
+		has, err := repo_model.IsRepositoryModelOrDirExist(ctx, target, repo.Name)
+		if err != nil {
+			return nil, err
+		} else if has {
+			report.Conflicts = append(report.Conflicts, &MergeConflict{Type: MergeConflictRepository, Name: repo.Name})
+		}
+	}
+
+	packages := make([]*packages_model.Package, 0, 10)
+	if err := e.Where("owner_id = ?", source.ID).Find(&packages); err != nil {
+		return nil, err
+	}
+	report.Packages = int64(len(packages))
+	for _, p := range packages {
+		has, err := e.Where(builder.Eq{"owner_id": target.ID, "type": p.Type, "lower_name": p.LowerName}).Exist(new(packages_model.Package))
+		if err != nil {
+			return nil, err
+		} else if has {
+			report.Conflicts = append(report.Conflicts, &MergeConflict{Type: MergeConflictPackage, Name: string(p.Type) + "/" + p.Name})
+		}
+	}
+
+	return report, nil
+}
+
+// MergeUser merges the source user into the target user: the repositories, issues, comments, stars,
+// SSH and GPG keys, packages and team memberships of the source user are moved to the target user,
+// then the source user is deleted. Nothing is changed if this is a dry run or if there are conflicts,
+// the report lists what would be moved and the conflicts.
+func MergeUser(ctx context.Context, doer, source, target *user_model.User, dryRun bool) (*MergeReport, error) {
+	if source.ID == target.ID {
+		return nil, util.NewInvalidArgumentErrorf("a user can not be merged into itself")
+	}
+	if source.IsOrganization() || target.IsOrganization() {
+		return nil, util.NewInvalidArgumentErrorf("organizations can not be merged")
+	}
+
+	report, err := newMergeReport(ctx, source, target)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun || len(report.Conflicts) > 0 {
+		return report, nil
+	}
+
+	// Repositories are moved one by one, their transfer can not be part of a single transaction
+	repos := make([]*repo_model.Repository, 0, report.Repositories)
+	if err := db.GetEngine(ctx).Where("owner_id = ?", source.ID).Find(&repos); err != nil {
+		return nil, err
+	}
+	for _, repo := range repos {
+		if err := repo_service.TransferOwnership(ctx, doer, target, repo, nil); err != nil {
+			return nil, fmt.Errorf("transfer repository %s: %w", repo.Name, err)
+		}
+	}
+
+	// The target user joins the teams first, so organizations are never left without owners
+	teamIDs, err := db.FindIDs(ctx, "team_user", "team_user.team_id", builder.Eq{"team_user.uid": source.ID})
+	if err != nil {
+		return nil, err
+	}
+	for _, teamID := range teamIDs {
+		team, err := organization.GetTeamByID(ctx, teamID)
+		if err != nil {
+			return nil, err
+		}
+		if err := models.AddTeamMember(team, target.ID); err != nil {
+			return nil, fmt.Errorf("add to team %d: %w", team.ID, err)
+		}
+	}
+
+	if err := db.WithTx(ctx, func(ctx context.Context) error {
+		return mergeUser(ctx, source, target)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := container_service.UpdateRepositoryNames(ctx, target, target.Name); err != nil {
+		return nil, err
+	}
+	if report.PublicKeys > 0 {
+		if err := asymkey_model.RewriteAllPublicKeys(); err != nil {
+			return nil, err
+		}
+		if err := asymkey_model.RewriteAllPrincipalKeys(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := DeleteUser(ctx, source, true); err != nil {
+		return nil, fmt.Errorf("delete merged user: %w", err)
+	}
+
+	log.Info("User %s merged into %s by %s", source.Name, target.Name, doer.Name)
+	return report, nil
+}
+
+// mergeUser moves the database records of the source user to the target user
+func mergeUser(ctx context.Context, source, target *user_model.User) error {
+	e := db.GetEngine(ctx)
+
+	// ***** START: Issues and comments *****
+	if _, err := e.Where("poster_id = ?", source.ID).Cols("poster_id").NoAutoTime().Update(&issues_model.Issue{PosterID: target.ID}); err != nil {
+		return fmt.Errorf("move issues: %w", err)
+	}
+	if _, err := e.Where("poster_id = ?", source.ID).Cols("poster_id").NoAutoTime().Update(&issues_model.Comment{PosterID: target.ID}); err != nil {
+		return fmt.Errorf("move comments: %w", err)
+	}
+	if _, err := e.Where("reviewer_id = ?", source.ID).Cols("reviewer_id").NoAutoTime().Update(&issues_model.Review{ReviewerID: target.ID}); err != nil {
+		return fmt.Errorf("move reviews: %w", err)
+	}
+
+	assignedIssueIDs, err := db.FindIDs(ctx, "issue_assignees", "issue_assignees.issue_id", builder.Eq{"issue_assignees.assignee_id": target.ID})
+	if err != nil {
+		return fmt.Errorf("get all assigned issues: %w", err)
+	}
+	if len(assignedIssueIDs) > 0 {
+		if _, err := e.Where(builder.Eq{"assignee_id": source.ID}.And(builder.In("issue_id", assignedIssueIDs))).Delete(new(issues_model.IssueAssignees)); err != nil {
+			return fmt.Errorf("delete duplicate assignments: %w", err)
+		}
+	}
+	if _, err := e.Where("assignee_id = ?", source.ID).Cols("assignee_id").NoAutoTime().Update(&issues_model.IssueAssignees{AssigneeID: target.ID}); err != nil {
+		return fmt.Errorf("move assignments: %w", err)
+	}
+	// ***** END: Issues and comments *****
+
+	// ***** START: Star *****
+	starredRepoIDs, err := db.FindIDs(ctx, "star", "star.repo_id", builder.Eq{"star.uid": target.ID})
+	if err != nil {
+		return fmt.Errorf("get all stars: %w", err)
+	}
+	if len(starredRepoIDs) > 0 {
+		// repositories starred by both users lose the star of the source user
+		duplicateRepoIDs, err := db.FindIDs(ctx, "star", "star.repo_id", builder.Eq{"star.uid": source.ID}.And(builder.In("star.repo_id", starredRepoIDs)))
+		if err != nil {
+			return fmt.Errorf("get duplicate stars: %w", err)
+		}
+		if err := db.DecrByIDs(ctx, duplicateRepoIDs, "num_stars", new(repo_model.Repository)); err != nil {
+			return fmt.Errorf("decrease repository num_stars: %w", err)
+		}
+		if _, err := e.Where(builder.Eq{"uid": source.ID}.And(builder.In("repo_id", starredRepoIDs))).Delete(new(repo_model.Star)); err != nil {
+			return fmt.Errorf("delete duplicate stars: %w", err)
+		}
+	}
+	if _, err := e.Where("uid = ?", source.ID).Cols("uid").NoAutoTime().Update(&repo_model.Star{UID: target.ID}); err != nil {
+		return fmt.Errorf("move stars: %w", err)
+	}
+	if _, err := e.Exec("UPDATE `user` SET num_stars = (SELECT COUNT(*) FROM `star` WHERE uid = ?) WHERE id = ?", target.ID, target.ID); err != nil {
+		return fmt.Errorf("update user num_stars: %w", err)
+	}
+	if _, err := e.Exec("UPDATE `user` SET num_stars = 0 WHERE id = ?", source.ID); err != nil {
+		return fmt.Errorf("update user num_stars: %w", err)
+	}
+	// ***** END: Star *****
+
+	// ***** START: Keys *****
+	if _, err := e.Where("owner_id = ?", source.ID).Cols("owner_id").NoAutoTime().Update(&asymkey_model.PublicKey{OwnerID: target.ID}); err != nil {
+		return fmt.Errorf("move public keys: %w", err)
+	}
+	if _, err := e.Where("owner_id = ?", source.ID).Cols("owner_id").NoAutoTime().Update(&asymkey_model.GPGKey{OwnerID: target.ID}); err != nil {
+		return fmt.Errorf("move GPG keys: %w", err)
+	}
+	// ***** END: Keys *****
+
+	// ***** START: Packages *****
+	if _, err := e.Where("owner_id = ?", source.ID).Cols("owner_id").NoAutoTime().Update(&packages_model.Package{OwnerID: target.ID}); err != nil {
+		return fmt.Errorf("move packages: %w", err)
+	}
+	// ***** END: Packages *****
+
+	return nil
+}
@@ -0,0 +1,80 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package user
+
+import (
+	"errors"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/organization"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/util"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeUserInvalid(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	admin := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	org3 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 3})
+
+	_, err := MergeUser(db.DefaultContext, admin, user2, user2, true)
+	assert.True(t, errors.Is(err, util.ErrInvalidArgument))
+	_, err = MergeUser(db.DefaultContext, admin, org3, user2, true)
+	assert.True(t, errors.Is(err, util.ErrInvalidArgument))
+}
+
+func TestMergeUserDryRun(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	admin := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+
+	// the target user owns a repository with the name of one of the merged user
+	assert.NoError(t, db.Insert(db.DefaultContext, &repo_model.Repository{OwnerID: user4.ID, OwnerName: user4.Name, Name: "repo1", LowerName: "repo1"}))
+
+	report, err := MergeUser(db.DefaultContext, admin, user2, user4, true)
+	assert.NoError(t, err)
+	assert.NotZero(t, report.Repositories)
+	assert.EqualValues(t, 2, report.Stars)
+	assert.EqualValues(t, 2, report.Teams)
+	if assert.Len(t, report.Conflicts, 1) {
+		assert.Equal(t, MergeConflictRepository, report.Conflicts[0].Type)
+		assert.Equal(t, "repo1", report.Conflicts[0].Name)
+	}
+
+	// nothing is changed with conflicts either
+	_, err = MergeUser(db.DefaultContext, admin, user2, user4, false)
+	assert.NoError(t, err)
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: user2.ID})
+	unittest.AssertExistsAndLoadBean(t, &repo_model.Star{UID: user2.ID, RepoID: 2})
+}
+
+func TestMergeUser(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+	admin := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 1})
+	user2 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+	user4 := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 4})
+
+	// user2 has starred the repository 2 already
+	assert.NoError(t, repo_model.StarRepo(user4.ID, 1, true))
+	assert.NoError(t, repo_model.StarRepo(user4.ID, 2, true))
+	repo2 := unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2})
+
+	report, err := MergeUser(db.DefaultContext, admin, user4, user2, false)
+	assert.NoError(t, err)
+	assert.Empty(t, report.Conflicts)
+	assert.EqualValues(t, 2, report.Stars)
+
+	unittest.AssertNotExistsBean(t, &user_model.User{ID: user4.ID})
+	unittest.AssertExistsAndLoadBean(t, &repo_model.Star{UID: user2.ID, RepoID: 1})
+	unittest.AssertCount(t, &repo_model.Star{UID: user2.ID}, 3)
+	assert.Equal(t, repo2.NumStars-1, unittest.AssertExistsAndLoadBean(t, &repo_model.Repository{ID: 2}).NumStars)
+	unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: user2.ID, NumStars: 3})
+	unittest.AssertExistsAndLoadBean(t, &organization.TeamUser{TeamID: 2, UID: user2.ID})
+}
@@ -197,6 +197,7 @@ func DeleteUser(ctx context.Context, u *user_model.User, purge bool) error {
 	// Note: There are something just cannot be roll back,
 	//	so just keep error logs of those operations.
 	path := user_model.UserPath(u.Name)
+	defer user_model.RemoveCachedStorageRegion(u.ID)
 	if err := util.RemoveAll(path); err != nil {
 		err = fmt.Errorf("Failed to RemoveAll %s: %w", path, err)
 		_ = system_model.CreateNotice(ctx, system_model.NoticeTask, fmt.Sprintf("delete user '%s': %v", u.Name, err))
@@ -204,9 +205,7 @@ func DeleteUser(ctx context.Context, u *user_model.User, purge bool) error {
 	}
 
 	if u.Avatar != "" {
-		avatarPath := u.CustomAvatarRelativePath()
-		if err := storage.Avatars.Delete(avatarPath); err != nil {
-			err = fmt.Errorf("Failed to remove %s: %w", avatarPath, err)
+		if err := removeUnusedAvatar(db.DefaultContext, u.CustomAvatarRelativePath()); err != nil {
 			_ = system_model.CreateNotice(ctx, system_model.NoticeTask, fmt.Sprintf("delete user '%s': %v", u.Name, err))
 			return err
 		}
@@ -241,7 +240,20 @@ func DeleteInactiveUsers(ctx context.Context, olderThan time.Duration) error {
 	return user_model.DeleteInactiveEmailAddresses(ctx)
 }
 
+// removeUnusedAvatar removes a stored avatar unless another user still uses the same image
+func removeUnusedAvatar(ctx context.Context, avatarPath string) error {
+	used, err := user_model.ExistsWithAvatarAtStoragePath(ctx, avatarPath)
+	if err != nil || used {
+		return err
+	}
+	if err := storage.Avatars.Delete(avatarPath); err != nil {
+		return fmt.Errorf("Failed to remove %s: %w", avatarPath, err)
+	}
+	return nil
+}
+
 // UploadAvatar saves custom avatar for user.
+// The avatar is named after its content, users uploading the same image share the stored file.
 func UploadAvatar(u *user_model.User, data []byte) error {
 	avatarData, err := avatar.ProcessAvatarImage(data)
 	if err != nil {
@@ -254,8 +266,10 @@ func UploadAvatar(u *user_model.User, data []byte) error {
 	}
 	defer committer.Close()
 
+	oldAvatarPath := u.CustomAvatarRelativePath()
+
 	u.UseCustomAvatar = true
-	u.Avatar = avatar.HashAvatar(u.ID, data)
+	u.Avatar = avatar.HashAvatarContent(avatarData)
 	if err = user_model.UpdateUserCols(ctx, u, "use_custom_avatar", "avatar"); err != nil {
 		return fmt.Errorf("updateUser: %w", err)
 	}
@@ -267,6 +281,12 @@ func UploadAvatar(u *user_model.User, data []byte) error {
 		return fmt.Errorf("Failed to create dir %s: %w", u.CustomAvatarRelativePath(), err)
 	}
 
+	if len(oldAvatarPath) > 0 && oldAvatarPath != u.CustomAvatarRelativePath() {
+		if err := removeUnusedAvatar(ctx, oldAvatarPath); err != nil {
+			return err
+		}
+	}
+
 	return committer.Commit()
 }
 
@@ -274,16 +294,24 @@ func UploadAvatar(u *user_model.User, data []byte) error {
 func DeleteAvatar(u *user_model.User) error {
 	aPath := u.CustomAvatarRelativePath()
 	log.Trace("DeleteAvatar[%d]: %s", u.ID, aPath)
-	if len(u.Avatar) > 0 {
-		if err := storage.Avatars.Delete(aPath); err != nil {
-			return fmt.Errorf("Failed to remove %s: %w", aPath, err)
-		}
+
+	ctx, committer, err := db.TxContext(db.DefaultContext)
+	if err != nil {
+		return err
 	}
+	defer committer.Close()
 
 	u.UseCustomAvatar = false
 	u.Avatar = ""
-	if _, err := db.GetEngine(db.DefaultContext).ID(u.ID).Cols("avatar, use_custom_avatar").Update(u); err != nil {
+	if _, err := db.GetEngine(ctx).ID(u.ID).Cols("avatar, use_custom_avatar").Update(u); err != nil {
 		return fmt.Errorf("UpdateUser: %w", err)
 	}
-	return nil
+
+	if len(aPath) > 0 {
+		if err := removeUnusedAvatar(ctx, aPath); err != nil {
+			return err
+		}
+	}
+
+	return committer.Commit()
 }
@@ -0,0 +1,209 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package vendorsync
+
+import (
+	"archive/tar"
+	"bufio"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/services/migrations"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// MaxContentSize is the maximum total size of the files imported by a vendor sync
+const MaxContentSize int64 = 1 << 30
+
+// errUnchanged is returned when the source reports that its content has not changed since the last import
+var errUnchanged = errors.New("the content of the source has not changed")
+
+// walkFunc is called for each file of the content of a source
+type walkFunc func(name string, executable bool, r io.Reader) error
+
+// vendorFilePath returns the path of a file of the source in the imported directory,
+// or false if the file is not imported
+func vendorFilePath(name string, stripComponents int) (string, bool) {
+	name = strings.TrimPrefix(path.Clean("/"+strings.ReplaceAll(name, "\\", "/")), "/")
+	if name == "" {
+		return "", false
+	}
+	parts := strings.Split(name, "/")
+	if len(parts) <= stripComponents {
+		return "", false
+	}
+	parts = parts[stripComponents:]
+	for _, part := range parts {
+		if strings.EqualFold(part, ".git") {
+			return "", false
+		}
+	}
+	return strings.Join(parts, "/"), true
+}
+
+// sizeLimitedReader fails once more than the remaining size has been read
+type sizeLimitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *sizeLimitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, fmt.Errorf("the content is larger than %d bytes", MaxContentSize)
+	}
+	return n, err
+}
+
+// walkSource calls f for each file of the current content of the source of the vendor sync, and returns
+// the digest and version identifying the content. errUnchanged is returned without calling f if the source
+// reports that the content has not changed since the last import.
+func walkSource(ctx context.Context, s *repo_model.VendorSync, f walkFunc) (digest, version string, err error) {
+	switch s.SourceType {
+	case repo_model.VendorSyncSourceHTTPTarball:
+		return walkHTTPTarball(ctx, s, f)
+	case repo_model.VendorSyncSourceS3:
+		return walkS3(ctx, s, f)
+	}
+	return "", "", fmt.Errorf("unknown source type %q", s.SourceType)
+}
+
+// walkHTTPTarball downloads a tarball, optionally gzipped. Its ETag is the version of the content
+// and the hash of the downloaded tarball is its digest.
+func walkHTTPTarball(ctx context.Context, s *repo_model.VendorSync, f walkFunc) (string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.SourceURL, nil)
+	if err != nil {
+		return "", "", err
+	}
+	if s.LastVersion != "" {
+		req.Header.Set("If-None-Match", s.LastVersion)
+	}
+	resp, err := migrations.NewMigrationHTTPClient().Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified {
+		return "", "", errUnchanged
+	} else if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("unexpected status %s", resp.Status)
+	}
+
+	hash := sha256.New()
+	body := bufio.NewReader(io.TeeReader(&sizeLimitedReader{r: resp.Body, remaining: MaxContentSize}, hash))
+	var archive io.Reader = body
+	if magic, err := body.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(body)
+		if err != nil {
+			return "", "", err
+		}
+		defer gz.Close()
+		archive = &sizeLimitedReader{r: gz, remaining: MaxContentSize}
+	}
+
+	tr := tar.NewReader(archive)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return "", "", fmt.Errorf("read tarball: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name, ok := vendorFilePath(hdr.Name, s.StripComponents)
+		if !ok {
+			continue
+		}
+		if err := f(name, hdr.FileInfo().Mode()&0o100 != 0, tr); err != nil {
+			return "", "", err
+		}
+	}
+	// the hash covers the whole download, including what follows the end of the archive
+	if _, err := io.Copy(io.Discard, body); err != nil {
+		return "", "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), resp.Header.Get("ETag"), nil
+}
+
+// walkS3 downloads the objects under a prefix of a bucket. The digest of the content is the hash
+// of the list of the objects with their ETags, the objects are not downloaded if it is unchanged.
+func walkS3(ctx context.Context, s *repo_model.VendorSync, f walkFunc) (string, string, error) {
+	endpoint, err := url.Parse(s.SourceURL)
+	if err != nil {
+		return "", "", err
+	}
+	secretAccessKey, err := s.SecretAccessKey()
+	if err != nil {
+		return "", "", err
+	}
+	client, err := minio.New(endpoint.Host, &minio.Options{
+		Creds:     credentials.NewStaticV4(s.AccessKeyID, secretAccessKey, ""),
+		Secure:    endpoint.Scheme == "https",
+		Transport: migrations.NewMigrationHTTPTransport(),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	objects := make([]minio.ObjectInfo, 0, 50)
+	var totalSize int64
+	for object := range client.ListObjects(ctx, s.Bucket, minio.ListObjectsOptions{Prefix: s.Prefix, Recursive: true}) {
+		if object.Err != nil {
+			return "", "", object.Err
+		}
+		if strings.HasSuffix(object.Key, "/") {
+			continue
+		}
+		totalSize += object.Size
+		if totalSize > MaxContentSize {
+			return "", "", fmt.Errorf("the content is larger than %d bytes", MaxContentSize)
+		}
+		objects = append(objects, object)
+	}
+	sort.Slice(objects, func(i, j int) bool { return objects[i].Key < objects[j].Key })
+
+	hash := sha256.New()
+	for _, object := range objects {
+		_, _ = fmt.Fprintf(hash, "%s\x00%s\x00%d\n", object.Key, object.ETag, object.Size)
+	}
+	digest := hex.EncodeToString(hash.Sum(nil))
+	if digest == s.LastDigest {
+		return "", "", errUnchanged
+	}
+
+	for _, object := range objects {
+		name, ok := vendorFilePath(strings.TrimPrefix(object.Key, s.Prefix), s.StripComponents)
+		if !ok {
+			continue
+		}
+		if err := func() error {
+			r, err := client.GetObject(ctx, s.Bucket, object.Key, minio.GetObjectOptions{})
+			if err != nil {
+				return err
+			}
+			defer r.Close()
+			return f(name, false, r)
+		}(); err != nil {
+			return "", "", fmt.Errorf("object %s: %w", object.Key, err)
+		}
+	}
+	return digest, "", nil
+}
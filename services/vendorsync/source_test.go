@@ -0,0 +1,65 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package vendorsync
+
+import (
+	"testing"
+
+	repo_model "code.gitea.io/gitea/models/repo"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVendorFilePath(t *testing.T) {
+	kases := []struct {
+		name  string
+		strip int
+		path  string
+		ok    bool
+	}{
+		{"data/a.csv", 0, "data/a.csv", true},
+		{"./data/a.csv", 0, "data/a.csv", true},
+		{"release-1.0/data/a.csv", 1, "data/a.csv", true},
+		{"release-1.0/a.csv", 2, "", false},
+		{"../../etc/passwd", 0, "etc/passwd", true},
+		{"data\\b.csv", 0, "data/b.csv", true},
+		{"data/.git/config", 0, "", false},
+		{".GIT/hooks/pre-receive", 0, "", false},
+		{"/", 0, "", false},
+	}
+	for _, kase := range kases {
+		p, ok := vendorFilePath(kase.name, kase.strip)
+		assert.Equal(t, kase.ok, ok, kase.name)
+		assert.Equal(t, kase.path, p, kase.name)
+	}
+}
+
+func TestCleanTargetPath(t *testing.T) {
+	p, ok := CleanTargetPath("/third_party//data/")
+	assert.True(t, ok)
+	assert.Equal(t, "third_party/data", p)
+
+	p, ok = CleanTargetPath("")
+	assert.True(t, ok)
+	assert.Equal(t, "", p)
+
+	_, ok = CleanTargetPath("data/.git")
+	assert.False(t, ok)
+}
+
+func TestVendorCommitMessage(t *testing.T) {
+	s := &repo_model.VendorSync{
+		SourceType: repo_model.VendorSyncSourceS3,
+		SourceURL:  "https://s3.example.com/",
+		Bucket:     "datasets",
+		Prefix:     "v1/",
+		TargetPath: "data",
+	}
+	msg := vendorCommitMessage(s, "0123", "")
+	assert.Contains(t, msg, "Import vendored data into data\n\n")
+	assert.Contains(t, msg, "Vendored-Source: https://s3.example.com/datasets/v1/\n")
+	assert.Contains(t, msg, "Vendored-Digest: sha256:0123\n")
+	assert.NotContains(t, msg, "Vendored-Version")
+	assert.Contains(t, msg, "Vendored-Imported-At: ")
+}
@@ -0,0 +1,200 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package vendorsync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.gitea.io/gitea/models/db"
+	repo_model "code.gitea.io/gitea/models/repo"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/log"
+	"code.gitea.io/gitea/modules/sync"
+	files_service "code.gitea.io/gitea/services/repository/files"
+)
+
+const (
+	// MinInterval is the shortest interval between the imports of a vendor sync
+	MinInterval = 10 * time.Minute
+	// DefaultInterval is the interval between the imports of a vendor sync if none is given
+	DefaultInterval = 24 * time.Hour
+)
+
+// vendorSyncWorkingPool prevents a vendor sync from running twice at the same time
+var vendorSyncWorkingPool = sync.NewExclusivePool()
+
+// CleanTargetPath returns the clean form of the directory of a branch the content of a vendor sync
+// is imported into, the root of the branch is the empty path
+func CleanTargetPath(p string) (string, bool) {
+	p = strings.Trim(path.Clean("/"+p), "/")
+	for _, part := range strings.Split(p, "/") {
+		if strings.EqualFold(part, ".git") {
+			return "", false
+		}
+	}
+	return p, true
+}
+
+// SyncDueVendorSyncs imports the content of the vendor syncs whose next import is due
+func SyncDueVendorSyncs(ctx context.Context) error {
+	for {
+		syncs, err := repo_model.FindDueVendorSyncs(ctx, 50)
+		if err != nil {
+			return err
+		}
+		if len(syncs) == 0 {
+			return nil
+		}
+		for _, s := range syncs {
+			select {
+			case <-ctx.Done():
+				return db.ErrCancelledf("before importing vendored data into branch %s of repository %d", s.Branch, s.RepoID)
+			default:
+			}
+			if err := RunVendorSync(ctx, s); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// RunVendorSync imports the current content of the source of the vendor sync and records the result
+func RunVendorSync(ctx context.Context, s *repo_model.VendorSync) error {
+	key := strconv.FormatInt(s.ID, 10)
+	vendorSyncWorkingPool.CheckIn(key)
+	defer vendorSyncWorkingPool.CheckOut(key)
+
+	status, message := syncVendor(ctx, s)
+	if status == repo_model.VendorSyncStatusFailed {
+		log.Warn("Importing vendored data into branch %s of repository %d failed: %s", s.Branch, s.RepoID, message)
+	}
+	return repo_model.UpdateVendorSyncResult(ctx, s, status, message)
+}
+
+func syncVendor(ctx context.Context, s *repo_model.VendorSync) (repo_model.VendorSyncStatus, string) {
+	repo, err := repo_model.GetRepositoryByID(ctx, s.RepoID)
+	if err != nil {
+		return repo_model.VendorSyncStatusFailed, err.Error()
+	}
+	if repo.IsArchived {
+		return repo_model.VendorSyncStatusFailed, "repository is archived"
+	}
+	doer, err := user_model.GetUserByID(ctx, s.DoerID)
+	if err != nil {
+		return repo_model.VendorSyncStatusFailed, err.Error()
+	}
+
+	commitID, digest, version, err := importVendorContent(ctx, repo, doer, s)
+	switch {
+	case errors.Is(err, errUnchanged):
+		return repo_model.VendorSyncStatusUnchanged, "content of the source has not changed"
+	case err != nil:
+		return repo_model.VendorSyncStatusFailed, err.Error()
+	}
+	s.LastDigest = digest
+	s.LastVersion = version
+	if commitID == "" {
+		return repo_model.VendorSyncStatusUnchanged, "content of the branch is already up to date"
+	}
+	s.LastCommitID = commitID
+	return repo_model.VendorSyncStatusSuccess, commitID
+}
+
+// importVendorContent replaces the content of the target directory of the branch with the content of the source,
+// and returns the ID of the commit, or an empty ID if the content of the branch did not change
+func importVendorContent(ctx context.Context, repo *repo_model.Repository, doer *user_model.User, s *repo_model.VendorSync) (commitID, digest, version string, err error) {
+	t, err := files_service.NewTemporaryUploadRepository(ctx, repo)
+	if err != nil {
+		return "", "", "", err
+	}
+	defer t.Close()
+	if err := t.Clone(s.Branch); err != nil {
+		return "", "", "", err
+	}
+	if err := t.SetDefaultIndex(); err != nil {
+		return "", "", "", err
+	}
+
+	var existing []string
+	if s.TargetPath == "" {
+		existing, err = t.LsFiles()
+	} else {
+		existing, err = t.LsFiles(s.TargetPath)
+	}
+	if err != nil {
+		return "", "", "", err
+	}
+	if err := t.RemoveFilesFromIndex(existing...); err != nil {
+		return "", "", "", err
+	}
+
+	digest, version, err = walkSource(ctx, s, func(name string, executable bool, r io.Reader) error {
+		hash, err := t.HashObject(r)
+		if err != nil {
+			return err
+		}
+		mode := "100644"
+		if executable {
+			mode = "100755"
+		}
+		return t.AddObjectToIndex(mode, hash, path.Join(s.TargetPath, name))
+	})
+	if err != nil {
+		return "", "", "", err
+	}
+
+	treeHash, err := t.WriteTree()
+	if err != nil {
+		return "", "", "", err
+	}
+	parent, err := t.GetLastCommit()
+	if err != nil {
+		return "", "", "", err
+	}
+	parentTreeHash, err := t.GetLastCommitByRef(parent + "^{tree}")
+	if err != nil {
+		return "", "", "", err
+	}
+	if treeHash == parentTreeHash {
+		return "", digest, version, nil
+	}
+
+	commitID, err = t.CommitTree(parent, doer, doer, treeHash, vendorCommitMessage(s, digest, version), false)
+	if err != nil {
+		return "", "", "", err
+	}
+	if err := t.Push(doer, commitID, s.Branch); err != nil {
+		return "", "", "", err
+	}
+	return commitID, digest, version, nil
+}
+
+// vendorCommitMessage returns the message of an import commit, with the provenance of the content as trailers
+func vendorCommitMessage(s *repo_model.VendorSync, digest, version string) string {
+	source := s.SourceURL
+	if s.SourceType == repo_model.VendorSyncSourceS3 {
+		source = fmt.Sprintf("%s/%s/%s", strings.TrimSuffix(s.SourceURL, "/"), s.Bucket, s.Prefix)
+	}
+	target := s.TargetPath
+	if target == "" {
+		target = "/"
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "Import vendored data into %s\n\n", target)
+	fmt.Fprintf(&msg, "Vendored-Source: %s\n", source)
+	fmt.Fprintf(&msg, "Vendored-Digest: sha256:%s\n", digest)
+	if version != "" {
+		fmt.Fprintf(&msg, "Vendored-Version: %s\n", version)
+	}
+	fmt.Fprintf(&msg, "Vendored-Imported-At: %s\n", time.Now().UTC().Format(time.RFC3339))
+	return msg.String()
+}
@@ -108,18 +108,7 @@ func Deliver(ctx context.Context, t *webhook_model.HookTask) error {
 		return fmt.Errorf("invalid http method for webhook task[%d] in webhook %s: %v", t.ID, w.URL, w.HTTPMethod)
 	}
 
-	var signatureSHA1 string
-	var signatureSHA256 string
-	if len(w.Secret) > 0 {
-		sig1 := hmac.New(sha1.New, []byte(w.Secret))
-		sig256 := hmac.New(sha256.New, []byte(w.Secret))
-		_, err = io.MultiWriter(sig1, sig256).Write([]byte(t.PayloadContent))
-		if err != nil {
-			log.Error("prepareWebhooks.sigWrite: %v", err)
-		}
-		signatureSHA1 = hex.EncodeToString(sig1.Sum(nil))
-		signatureSHA256 = hex.EncodeToString(sig256.Sum(nil))
-	}
+	signatureSHA1, signatureSHA256 := webhookSignatures(w.Secret, t.PayloadContent)
 
 	event := t.EventType.Event()
 	eventType := string(t.EventType)
@@ -137,6 +126,13 @@ func Deliver(ctx context.Context, t *webhook_model.HookTask) error {
 	req.Header["X-GitHub-Event"] = []string{event}
 	req.Header["X-GitHub-Event-Type"] = []string{eventType}
 
+	// During a secret rotation, the payload is also signed with the previous secret
+	if previousSecret := w.ValidPreviousSecret(); previousSecret != "" {
+		_, previousSignatureSHA256 := webhookSignatures(previousSecret, t.PayloadContent)
+		req.Header.Add("X-Gitea-Signature-Previous", previousSignatureSHA256)
+		req.Header.Add("X-Hub-Signature-256-Previous", "sha256="+previousSignatureSHA256)
+	}
+
 	// Add Authorization Header
 	authorization, err := w.HeaderAuthorization()
 	if err != nil {
@@ -210,7 +206,12 @@ func Deliver(ctx context.Context, t *webhook_model.HookTask) error {
 		return nil
 	}
 
-	resp, err := webhookHTTPClient.Do(req.WithContext(ctx))
+	client, err := webhookClient(w)
+	if err != nil {
+		t.ResponseInfo.Body = fmt.Sprintf("Delivery: %v", err)
+		return fmt.Errorf("unable to deliver webhook task[%d] in %s due to invalid client settings: %w", t.ID, w.URL, err)
+	}
+	resp, err := client.Do(req.WithContext(ctx))
 	if err != nil {
 		t.ResponseInfo.Body = fmt.Sprintf("Delivery: %v", err)
 		return fmt.Errorf("unable to deliver webhook task[%d] in %s due to error in http client: %w", t.ID, w.URL, err)
@@ -234,11 +235,60 @@ func Deliver(ctx context.Context, t *webhook_model.HookTask) error {
 }
 
 var (
-	webhookHTTPClient *http.Client
-	once              sync.Once
-	hostMatchers      []glob.Glob
+	webhookHTTPClient         *http.Client
+	webhookAllowedHostMatcher *hostmatcher.HostMatchList
+	once                      sync.Once
+	hostMatchers              []glob.Glob
 )
 
+// webhookSignatures returns the HMAC SHA1 and SHA256 signatures of the payload, they are empty without a secret
+func webhookSignatures(secret, payload string) (signatureSHA1, signatureSHA256 string) {
+	if len(secret) == 0 {
+		return "", ""
+	}
+	sig1 := hmac.New(sha1.New, []byte(secret))
+	sig256 := hmac.New(sha256.New, []byte(secret))
+	if _, err := io.MultiWriter(sig1, sig256).Write([]byte(payload)); err != nil {
+		log.Error("prepareWebhooks.sigWrite: %v", err)
+	}
+	return hex.EncodeToString(sig1.Sum(nil)), hex.EncodeToString(sig256.Sum(nil))
+}
+
+// webhookClient returns the HTTP client delivering the tasks of the webhook. The webhooks presenting
+// a client certificate or pinned to IP ranges get their own client, which does not keep connections alive.
+func webhookClient(w *webhook_model.Webhook) (*http.Client, error) {
+	if w.ClientCertificate == "" && w.AllowedIPs == "" {
+		return webhookHTTPClient, nil
+	}
+
+	ipRanges, err := w.AllowedIPRanges()
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{InsecureSkipVerify: setting.Webhook.SkipTLSVerify}
+	cert, err := w.ClientTLSCertificate()
+	if err != nil {
+		return nil, err
+	} else if cert != nil {
+		tlsConfig.Certificates = []tls.Certificate{*cert}
+	}
+
+	return &http.Client{
+		Timeout: webhookHTTPClient.Timeout,
+		Transport: &http.Transport{
+			TLSClientConfig:   tlsConfig,
+			Proxy:             webhookProxy(),
+			DialContext:       hostmatcher.NewDialContextWithIPRanges("webhook", webhookAllowedHostMatcher, nil, ipRanges),
+			DisableKeepAlives: true,
+		},
+	}, nil
+}
+
+// DoRequest sends a request with the webhook HTTP client, which honors the proxy and allowed host settings of webhooks
+func DoRequest(req *http.Request) (*http.Response, error) {
+	return webhookHTTPClient.Do(req)
+}
+
 func webhookProxy() func(req *http.Request) (*url.URL, error) {
 	if setting.Webhook.ProxyURL == "" {
 		return proxy.Proxy()
@@ -272,14 +322,14 @@ func Init() error {
 	if allowedHostListValue == "" {
 		allowedHostListValue = hostmatcher.MatchBuiltinExternal
 	}
-	allowedHostMatcher := hostmatcher.ParseHostMatchList("webhook.ALLOWED_HOST_LIST", allowedHostListValue)
+	webhookAllowedHostMatcher = hostmatcher.ParseHostMatchList("webhook.ALLOWED_HOST_LIST", allowedHostListValue)
 
 	webhookHTTPClient = &http.Client{
 		Timeout: timeout,
 		Transport: &http.Transport{
 			TLSClientConfig: &tls.Config{InsecureSkipVerify: setting.Webhook.SkipTLSVerify},
 			Proxy:           webhookProxy(),
-			DialContext:     hostmatcher.NewDialContext("webhook", allowedHostMatcher, nil),
+			DialContext:     hostmatcher.NewDialContext("webhook", webhookAllowedHostMatcher, nil),
 		},
 	}
 
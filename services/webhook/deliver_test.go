@@ -87,3 +87,71 @@ func TestWebhookDeliverAuthorizationHeader(t *testing.T) {
 
 	assert.True(t, hookTask.IsSucceed)
 }
+
+func TestWebhookDeliverPreviousSecret(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	done := make(chan struct{}, 1)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, newSignature := webhookSignatures("new-secret", "{}")
+		_, previousSignature := webhookSignatures("old-secret", "{}")
+		assert.Equal(t, newSignature, r.Header.Get("X-Gitea-Signature"))
+		assert.Equal(t, previousSignature, r.Header.Get("X-Gitea-Signature-Previous"))
+		assert.Equal(t, "sha256="+previousSignature, r.Header.Get("X-Hub-Signature-256-Previous"))
+		w.WriteHeader(200)
+		done <- struct{}{}
+	}))
+	t.Cleanup(s.Close)
+
+	hook := &webhook_model.Webhook{
+		RepoID:      3,
+		URL:         s.URL + "/webhook",
+		ContentType: webhook_model.ContentTypeJSON,
+		IsActive:    true,
+		Type:        webhook_module.GITEA,
+		Secret:      "old-secret",
+	}
+	hook.RotateSecret("new-secret", time.Hour)
+	assert.NoError(t, webhook_model.CreateWebhook(db.DefaultContext, hook))
+
+	hookTask, err := webhook_model.CreateHookTask(db.DefaultContext, &webhook_model.HookTask{HookID: hook.ID, EventType: webhook_module.HookEventPush, PayloadContent: "{}"})
+	assert.NoError(t, err)
+
+	assert.NoError(t, Deliver(context.Background(), hookTask))
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("waited to long for request to happen")
+	}
+
+	assert.True(t, hookTask.IsSucceed)
+}
+
+func TestWebhookDeliverAllowedIPs(t *testing.T) {
+	assert.NoError(t, unittest.PrepareTestDatabase())
+
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(200)
+	}))
+	t.Cleanup(s.Close)
+
+	deliver := func(allowedIPs string) *webhook_model.HookTask {
+		hook := &webhook_model.Webhook{
+			RepoID:      3,
+			URL:         s.URL + "/webhook",
+			ContentType: webhook_model.ContentTypeJSON,
+			IsActive:    true,
+			Type:        webhook_module.GITEA,
+		}
+		assert.NoError(t, hook.SetAllowedIPs(allowedIPs))
+		assert.NoError(t, webhook_model.CreateWebhook(db.DefaultContext, hook))
+
+		hookTask, err := webhook_model.CreateHookTask(db.DefaultContext, &webhook_model.HookTask{HookID: hook.ID, EventType: webhook_module.HookEventPush, PayloadContent: "{}"})
+		assert.NoError(t, err)
+		_ = Deliver(context.Background(), hookTask)
+		return hookTask
+	}
+
+	assert.False(t, deliver("10.0.0.0/8").IsSucceed)
+	assert.True(t, deliver("10.0.0.0/8, 127.0.0.1").IsSucceed)
+}
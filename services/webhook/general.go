@@ -246,15 +246,22 @@ func ToHook(repoLink string, w *webhook_model.Webhook) (*api.Hook, error) {
 		return nil, err
 	}
 
-	return &api.Hook{
-		ID:                  w.ID,
-		Type:                w.Type,
-		URL:                 fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID),
-		Active:              w.IsActive,
-		Config:              config,
-		Events:              w.EventsArray(),
-		AuthorizationHeader: authorizationHeader,
-		Updated:             w.UpdatedUnix.AsTime(),
-		Created:             w.CreatedUnix.AsTime(),
-	}, nil
+	apiHook := &api.Hook{
+		ID:                   w.ID,
+		Type:                 w.Type,
+		URL:                  fmt.Sprintf("%s/settings/hooks/%d", repoLink, w.ID),
+		Active:               w.IsActive,
+		Config:               config,
+		Events:               w.EventsArray(),
+		AuthorizationHeader:  authorizationHeader,
+		AllowedIPs:           w.AllowedIPs,
+		HasClientCertificate: w.ClientCertificate != "",
+		Updated:              w.UpdatedUnix.AsTime(),
+		Created:              w.CreatedUnix.AsTime(),
+	}
+	if w.ValidPreviousSecret() != "" {
+		expires := w.PreviousSecretExpiresUnix.AsTime()
+		apiHook.PreviousSecretExpires = &expires
+	}
+	return apiHook, nil
 }
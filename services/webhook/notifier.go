@@ -6,6 +6,8 @@ package webhook
 import (
 	"context"
 
+	actions_model "code.gitea.io/gitea/models/actions"
+	discussion_model "code.gitea.io/gitea/models/discussion"
 	issues_model "code.gitea.io/gitea/models/issues"
 	packages_model "code.gitea.io/gitea/models/packages"
 	"code.gitea.io/gitea/models/perm"
@@ -861,3 +863,111 @@ func notifyPackage(ctx context.Context, sender *user_model.User, pd *packages_mo
 		log.Error("PrepareWebhooks: %v", err)
 	}
 }
+
+func sendDiscussionHook(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, action api.HookDiscussionAction) {
+	if err := d.LoadAttributes(ctx); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return
+	}
+
+	mode, _ := access_model.AccessLevel(ctx, doer, d.Repo)
+	if err := PrepareWebhooks(ctx, EventSource{Repository: d.Repo}, webhook_module.HookEventDiscussion, &api.DiscussionPayload{
+		Action:     action,
+		Discussion: convert.ToDiscussion(ctx, d),
+		Repository: convert.ToRepo(ctx, d.Repo, mode),
+		Sender:     convert.ToUser(ctx, doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
+
+func (m *webhookNotifier) NotifyNewDiscussion(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	sendDiscussionHook(ctx, doer, d, api.HookDiscussionCreated)
+}
+
+func (m *webhookNotifier) NotifyDiscussionChangeContent(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	sendDiscussionHook(ctx, doer, d, api.HookDiscussionEdited)
+}
+
+func (m *webhookNotifier) NotifyDiscussionChangeLock(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	if d.IsLocked {
+		sendDiscussionHook(ctx, doer, d, api.HookDiscussionLocked)
+	} else {
+		sendDiscussionHook(ctx, doer, d, api.HookDiscussionUnlocked)
+	}
+}
+
+func (m *webhookNotifier) NotifyDiscussionChangeAnswer(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	if d.IsAnswered() {
+		sendDiscussionHook(ctx, doer, d, api.HookDiscussionAnswered)
+	} else {
+		sendDiscussionHook(ctx, doer, d, api.HookDiscussionUnanswered)
+	}
+}
+
+func (m *webhookNotifier) NotifyDeleteDiscussion(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion) {
+	sendDiscussionHook(ctx, doer, d, api.HookDiscussionDeleted)
+}
+
+func sendDiscussionCommentHook(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment, action api.HookIssueCommentAction) {
+	if err := d.LoadAttributes(ctx); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return
+	}
+	if err := c.LoadPoster(ctx); err != nil {
+		log.Error("LoadPoster: %v", err)
+		return
+	}
+
+	mode, _ := access_model.AccessLevel(ctx, doer, d.Repo)
+	if err := PrepareWebhooks(ctx, EventSource{Repository: d.Repo}, webhook_module.HookEventDiscussionComment, &api.DiscussionCommentPayload{
+		Action:     action,
+		Discussion: convert.ToDiscussion(ctx, d),
+		Comment:    convert.ToDiscussionComment(ctx, d, c),
+		Repository: convert.ToRepo(ctx, d.Repo, mode),
+		Sender:     convert.ToUser(ctx, doer, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
+
+func (m *webhookNotifier) NotifyCreateDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) {
+	sendDiscussionCommentHook(ctx, doer, d, c, api.HookIssueCommentCreated)
+}
+
+func (m *webhookNotifier) NotifyUpdateDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) {
+	sendDiscussionCommentHook(ctx, doer, d, c, api.HookIssueCommentEdited)
+}
+
+func (m *webhookNotifier) NotifyDeleteDiscussionComment(ctx context.Context, doer *user_model.User, d *discussion_model.Discussion, c *discussion_model.Comment) {
+	sendDiscussionCommentHook(ctx, doer, d, c, api.HookIssueCommentDeleted)
+}
+
+func sendActionRunApprovalHook(ctx context.Context, sender *user_model.User, run *actions_model.ActionRun, action api.HookActionRunApprovalAction) {
+	if err := run.LoadAttributes(ctx); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return
+	}
+
+	mode, _ := access_model.AccessLevel(ctx, sender, run.Repo)
+	if err := PrepareWebhooks(ctx, EventSource{Repository: run.Repo}, webhook_module.HookEventActionRunApproval, &api.ActionRunApprovalPayload{
+		Action:     action,
+		Run:        convert.ToActionRun(ctx, run),
+		Repository: convert.ToRepo(ctx, run.Repo, mode),
+		Sender:     convert.ToUser(ctx, sender, nil),
+	}); err != nil {
+		log.Error("PrepareWebhooks: %v", err)
+	}
+}
+
+func (m *webhookNotifier) NotifyActionRunNeedApproval(ctx context.Context, run *actions_model.ActionRun) {
+	if err := run.LoadAttributes(ctx); err != nil {
+		log.Error("LoadAttributes: %v", err)
+		return
+	}
+	sendActionRunApprovalHook(ctx, run.TriggerUser, run, api.HookActionRunApprovalRequested)
+}
+
+func (m *webhookNotifier) NotifyActionRunApproved(ctx context.Context, doer *user_model.User, run *actions_model.ActionRun) {
+	sendActionRunApprovalHook(ctx, doer, run, api.HookActionRunApprovalApproved)
+}
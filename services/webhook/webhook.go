@@ -210,6 +210,29 @@ func PrepareWebhook(ctx context.Context, w *webhook_model.Webhook, event webhook
 	return enqueueHookTask(task.ID)
 }
 
+// DeliverTestWebhook creates a task of the payload for the webhook regardless of its event and branch filters
+// and delivers it right away, the returned task holds the request and the response of the delivery
+func DeliverTestWebhook(ctx context.Context, w *webhook_model.Webhook, event webhook_module.HookEventType, p api.Payloader) (*webhook_model.HookTask, error) {
+	payloader := p
+	if webhook, ok := webhooks[w.Type]; ok {
+		var err error
+		payloader, err = webhook.payloadCreator(p, event, w.Meta)
+		if err != nil {
+			return nil, fmt.Errorf("create payload for %s[%s]: %w", w.Type, event, err)
+		}
+	}
+
+	task, err := webhook_model.CreateHookTask(ctx, &webhook_model.HookTask{
+		HookID:    w.ID,
+		Payloader: payloader,
+		EventType: event,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("CreateHookTask: %w", err)
+	}
+	return task, Deliver(ctx, task)
+}
+
 // PrepareWebhooks adds new webhooks to task queue for given payload.
 func PrepareWebhooks(ctx context.Context, source EventSource, event webhook_module.HookEventType, p api.Payloader) error {
 	owner := source.Owner
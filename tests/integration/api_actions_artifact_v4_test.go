@@ -0,0 +1,229 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	actions_model "code.gitea.io/gitea/models/actions"
+	"code.gitea.io/gitea/models/db"
+	"code.gitea.io/gitea/models/unittest"
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/modules/storage"
+	actions_service "code.gitea.io/gitea/services/actions"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const artifactV4Base = "/twirp/github.actions.results.api.v1.ArtifactService"
+
+type (
+	artifactV4CreateResponse struct {
+		OK              bool   `json:"ok"`
+		SignedUploadURL string `json:"signedUploadUrl"`
+	}
+	artifactV4FinalizeResponse struct {
+		OK         bool  `json:"ok"`
+		ArtifactID int64 `json:"artifactId,string"`
+	}
+	artifactV4ListResponse struct {
+		Artifacts []struct {
+			DatabaseID int64  `json:"databaseId,string"`
+			Name       string `json:"name"`
+			Size       int64  `json:"size,string"`
+		} `json:"artifacts"`
+	}
+	artifactV4SignedURLResponse struct {
+		SignedURL string `json:"signedUrl"`
+	}
+)
+
+// getArtifactV4Token returns the runtime token of task 47, which runs job 192 of run 791
+func getArtifactV4Token(t *testing.T) string {
+	token, err := actions_service.CreateAuthorizationToken(47, 791, 192)
+	assert.NoError(t, err)
+	return "Bearer " + token
+}
+
+// artifactV4Call posts a request to a method of the artifact service of the run of task 47
+func artifactV4Call(t *testing.T, token, method string, body map[string]any, expectedStatus int) *httptest.ResponseRecorder {
+	t.Helper()
+	body["workflowRunBackendId"] = "791"
+	body["workflowJobRunBackendId"] = "192"
+	req := NewRequestWithJSON(t, "POST", artifactV4Base+"/"+method, body)
+	req = addTokenAuthHeader(req, token)
+	return MakeRequest(t, req, expectedStatus)
+}
+
+// artifactV4Path returns the path of a signed url returned by the artifact service
+func artifactV4Path(signedURL string) string {
+	return signedURL[strings.Index(signedURL, artifactV4Base):]
+}
+
+// uploadArtifactV4 uploads and finalizes an artifact of two blocks, which are uploaded in reverse order
+func uploadArtifactV4(t *testing.T, token, name, content string) int64 {
+	t.Helper()
+	resp := artifactV4Call(t, token, "CreateArtifact", map[string]any{"name": name, "version": 4}, http.StatusOK)
+	var createResp artifactV4CreateResponse
+	DecodeJSON(t, resp, &createResp)
+	assert.True(t, createResp.OK)
+	uploadURL := artifactV4Path(createResp.SignedUploadURL)
+
+	half := len(content) / 2
+	req := NewRequestWithBody(t, "PUT", uploadURL+"&comp=block&blockid=YmxvY2sy", strings.NewReader(content[half:]))
+	MakeRequest(t, req, http.StatusCreated)
+	req = NewRequestWithBody(t, "PUT", uploadURL+"&comp=block&blockid=YmxvY2sx", strings.NewReader(content[:half]))
+	MakeRequest(t, req, http.StatusCreated)
+
+	// the list decides the order of the blocks
+	req = NewRequestWithBody(t, "PUT", uploadURL+"&comp=blocklist",
+		strings.NewReader("<BlockList><Latest>YmxvY2sx</Latest><Latest>YmxvY2sy</Latest></BlockList>"))
+	MakeRequest(t, req, http.StatusCreated)
+
+	resp = artifactV4Call(t, token, "FinalizeArtifact", map[string]any{"name": name, "size": strconv.Itoa(len(content))}, http.StatusOK)
+	var finalizeResp artifactV4FinalizeResponse
+	DecodeJSON(t, resp, &finalizeResp)
+	assert.True(t, finalizeResp.OK)
+	return finalizeResp.ArtifactID
+}
+
+func TestActionsArtifactV4UploadDownload(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	token := getArtifactV4Token(t)
+
+	artifactID := uploadArtifactV4(t, token, "artifact-v4", "hello world")
+
+	resp := artifactV4Call(t, token, "ListArtifacts", map[string]any{"nameFilter": "artifact-v4"}, http.StatusOK)
+	var listResp artifactV4ListResponse
+	DecodeJSON(t, resp, &listResp)
+	if assert.Len(t, listResp.Artifacts, 1) {
+		assert.Equal(t, artifactID, listResp.Artifacts[0].DatabaseID)
+		assert.Equal(t, "artifact-v4", listResp.Artifacts[0].Name)
+		assert.EqualValues(t, 11, listResp.Artifacts[0].Size)
+	}
+
+	resp = artifactV4Call(t, token, "GetSignedArtifactURL", map[string]any{"name": "artifact-v4"}, http.StatusOK)
+	var urlResp artifactV4SignedURLResponse
+	DecodeJSON(t, resp, &urlResp)
+	downloadURL := artifactV4Path(urlResp.SignedURL)
+
+	req := NewRequest(t, "GET", downloadURL)
+	assert.Equal(t, "hello world", MakeRequest(t, req, http.StatusOK).Body.String())
+
+	t.Run("Range", func(t *testing.T) {
+		req := NewRequest(t, "GET", downloadURL)
+		req.Header.Set("Range", "bytes=6-10")
+		resp := MakeRequest(t, req, http.StatusPartialContent)
+		assert.Equal(t, "world", resp.Body.String())
+		assert.Equal(t, "bytes 6-10/11", resp.Header().Get("Content-Range"))
+	})
+
+	t.Run("WithoutToken", func(t *testing.T) {
+		req := NewRequestWithJSON(t, "POST", artifactV4Base+"/ListArtifacts", map[string]any{"workflowRunBackendId": "791"})
+		MakeRequest(t, req, http.StatusUnauthorized)
+	})
+
+	t.Run("OtherRun", func(t *testing.T) {
+		req := NewRequestWithJSON(t, "POST", artifactV4Base+"/ListArtifacts", map[string]any{"workflowRunBackendId": "792"})
+		req = addTokenAuthHeader(req, token)
+		MakeRequest(t, req, http.StatusBadRequest)
+	})
+}
+
+func TestActionsArtifactV4Signature(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	token := getArtifactV4Token(t)
+
+	uploadArtifactV4(t, token, "artifact-v4", "hello world")
+	resp := artifactV4Call(t, token, "GetSignedArtifactURL", map[string]any{"name": "artifact-v4"}, http.StatusOK)
+	var urlResp artifactV4SignedURLResponse
+	DecodeJSON(t, resp, &urlResp)
+	downloadURL := artifactV4Path(urlResp.SignedURL)
+
+	// the signature covers the artifact name, the task and the expiry
+	req := NewRequest(t, "GET", strings.Replace(downloadURL, "artifactName=artifact-v4", "artifactName=other", 1))
+	MakeRequest(t, req, http.StatusUnauthorized)
+	req = NewRequest(t, "GET", strings.Replace(downloadURL, "taskID=47", "taskID=48", 1))
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	// downloads are refused once the url expired even though the signature is valid
+	expires := strconv.FormatInt(time.Now().Add(-time.Minute).Unix(), 10)
+	mac := hmac.New(sha256.New, []byte(setting.SecretKey))
+	_, _ = fmt.Fprintf(mac, "%s\n%s\n%s\n%d", "DownloadArtifact", expires, "artifact-v4", 47)
+	req = NewRequest(t, "GET", artifactV4Base+"/DownloadArtifact?sig="+url.QueryEscape(base64.URLEncoding.EncodeToString(mac.Sum(nil)))+
+		"&expires="+expires+"&artifactName=artifact-v4&taskID=47")
+	MakeRequest(t, req, http.StatusUnauthorized)
+
+	// a download url doesn't allow uploads
+	req = NewRequestWithBody(t, "PUT", strings.Replace(downloadURL, "/DownloadArtifact?", "/UploadArtifact?", 1)+"&comp=block&blockid=YmxvY2sx",
+		strings.NewReader("evil"))
+	MakeRequest(t, req, http.StatusUnauthorized)
+}
+
+func TestActionsArtifactV4InvalidBlocks(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	token := getArtifactV4Token(t)
+
+	resp := artifactV4Call(t, token, "CreateArtifact", map[string]any{"name": "artifact-v4", "version": 4}, http.StatusOK)
+	var createResp artifactV4CreateResponse
+	DecodeJSON(t, resp, &createResp)
+	uploadURL := artifactV4Path(createResp.SignedUploadURL)
+
+	req := NewRequestWithBody(t, "PUT", uploadURL+"&comp=block&blockid="+strings.Repeat("A", 89), strings.NewReader("hello"))
+	MakeRequest(t, req, http.StatusBadRequest)
+
+	req = NewRequestWithBody(t, "PUT", uploadURL+"&comp=block&blockid=YmxvY2sx", strings.NewReader("hello"))
+	MakeRequest(t, req, http.StatusCreated)
+
+	req = NewRequestWithBody(t, "PUT", uploadURL+"&comp=blocklist",
+		strings.NewReader("<BlockList><Latest>YmxvY2sx</Latest><Latest>"+strings.Repeat("A", 89)+"</Latest></BlockList>"))
+	MakeRequest(t, req, http.StatusBadRequest)
+
+	req = NewRequestWithBody(t, "PUT", uploadURL+"&comp=blocklist",
+		strings.NewReader("<BlockList><Latest>YmxvY2sx</Latest><Latest>YmxvY2sy</Latest></BlockList>"))
+	MakeRequest(t, req, http.StatusBadRequest)
+
+	// nothing was committed, so the artifact cannot be finalized
+	artifactV4Call(t, token, "FinalizeArtifact", map[string]any{"name": "artifact-v4", "size": "5"}, http.StatusBadRequest)
+}
+
+func TestActionsArtifactV4Cleanup(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+	token := getArtifactV4Token(t)
+
+	artifactID := uploadArtifactV4(t, token, "artifact-v4", "hello world")
+	artifact := unittest.AssertExistsAndLoadBean(t, &actions_model.ActionArtifact{ID: artifactID})
+	assert.NotZero(t, artifact.ExpiredUnix)
+	_, err := storage.ActionsArtifacts.Stat(artifact.StoragePath)
+	assert.NoError(t, err)
+
+	// nothing has expired yet
+	assert.NoError(t, actions_service.CleanupArtifacts(db.DefaultContext))
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionArtifact{ID: artifactID, Status: actions_model.ArtifactStatusUploadConfirmed})
+
+	_, err = db.GetEngine(db.DefaultContext).ID(artifactID).Cols("expired_unix").Update(&actions_model.ActionArtifact{ExpiredUnix: 1})
+	assert.NoError(t, err)
+	assert.NoError(t, actions_service.CleanupArtifacts(db.DefaultContext))
+
+	unittest.AssertExistsAndLoadBean(t, &actions_model.ActionArtifact{ID: artifactID, Status: actions_model.ArtifactStatusExpired})
+	_, err = storage.ActionsArtifacts.Stat(artifact.StoragePath)
+	assert.Error(t, err)
+
+	resp := artifactV4Call(t, token, "ListArtifacts", map[string]any{}, http.StatusOK)
+	var listResp artifactV4ListResponse
+	DecodeJSON(t, resp, &listResp)
+	assert.Empty(t, listResp.Artifacts)
+	artifactV4Call(t, token, "GetSignedArtifactURL", map[string]any{"name": "artifact-v4"}, http.StatusNotFound)
+}
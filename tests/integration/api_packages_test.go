@@ -140,6 +140,60 @@ func TestPackageAPI(t *testing.T) {
 		assert.Equal(t, "cf83e1357eefb8bdf1542850d66d8007d620e4050b5715dc83f4a921d36ce9ce47d0d13c5d85f2b0ff8318d2877eec2f63b931bd47417a81a538327af927da3e", files[0].HashSHA512)
 	})
 
+	t.Run("SearchPackages", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		search := func(t *testing.T, query string) []*api.Package {
+			req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/search?token=%s&%s", tokenReadPackage, query))
+			resp := MakeRequest(t, req, http.StatusOK)
+
+			var apiPackages []*api.Package
+			DecodeJSON(t, resp, &apiPackages)
+			return apiPackages
+		}
+
+		apiPackages := search(t, "type=generic&q=test-pack")
+		if assert.Len(t, apiPackages, 1) {
+			assert.Equal(t, packageName, apiPackages[0].Name)
+			assert.Equal(t, user.Name, apiPackages[0].Owner.UserName)
+		}
+		assert.Len(t, search(t, "owner="+user.Name), 1)
+		assert.Empty(t, search(t, "owner=user2"))
+		assert.Empty(t, search(t, "owner=not-existing"))
+		assert.Empty(t, search(t, "type=npm"))
+		assert.Empty(t, search(t, "property=generic.dummy:value"))
+
+		req := NewRequest(t, "GET", fmt.Sprintf("/api/v1/packages/search?token=%s&property=invalid", tokenReadPackage))
+		MakeRequest(t, req, http.StatusUnprocessableEntity)
+	})
+
+	t.Run("BatchPackageMetadata", func(t *testing.T) {
+		defer tests.PrintCurrentTest(t)()
+
+		req := NewRequestWithJSON(t, "POST", fmt.Sprintf("/api/v1/packages/search/batch?token=%s", tokenReadPackage), &api.BatchPackageMetadataOption{
+			Versions: []*api.PackageVersionIdentifier{
+				{Owner: user.Name, Type: "generic", Name: packageName, Version: packageVersion},
+				{Owner: user.Name, Type: "generic", Name: packageName, Version: "0.0.1"},
+				{Owner: "not-existing", Type: "generic", Name: packageName, Version: packageVersion},
+			},
+		})
+		resp := MakeRequest(t, req, http.StatusOK)
+
+		var results []*api.PackageMetadata
+		DecodeJSON(t, resp, &results)
+
+		if assert.Len(t, results, 3) {
+			assert.True(t, results[0].Found)
+			if assert.NotNil(t, results[0].Package) {
+				assert.Equal(t, packageVersion, results[0].Package.Version)
+			}
+			assert.Equal(t, "0.0.1", results[1].Version)
+			assert.False(t, results[1].Found)
+			assert.Nil(t, results[1].Package)
+			assert.False(t, results[2].Found)
+		}
+	})
+
 	t.Run("DeletePackage", func(t *testing.T) {
 		defer tests.PrintCurrentTest(t)()
 
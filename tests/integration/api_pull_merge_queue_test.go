@@ -0,0 +1,174 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/models/db"
+	git_model "code.gitea.io/gitea/models/git"
+	issues_model "code.gitea.io/gitea/models/issues"
+	pull_model "code.gitea.io/gitea/models/pull"
+	repo_model "code.gitea.io/gitea/models/repo"
+	"code.gitea.io/gitea/models/unittest"
+	user_model "code.gitea.io/gitea/models/user"
+	"code.gitea.io/gitea/modules/git"
+	repo_module "code.gitea.io/gitea/modules/repository"
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/services/forms"
+	"code.gitea.io/gitea/services/pull"
+	repo_service "code.gitea.io/gitea/services/repository"
+	files_service "code.gitea.io/gitea/services/repository/files"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIPullMergeQueue(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, giteaURL *url.URL) {
+		user := unittest.AssertExistsAndLoadBean(t, &user_model.User{ID: 2})
+		repo, err := repo_service.CreateRepository(db.DefaultContext, user, user, repo_module.CreateRepoOptions{
+			Name:          "merge-queue",
+			AutoInit:      true,
+			Readme:        "Default",
+			DefaultBranch: "main",
+		})
+		assert.NoError(t, err)
+
+		// the status check "ci" is required to merge into main
+		assert.NoError(t, git_model.UpdateProtectBranch(db.DefaultContext, repo, &git_model.ProtectedBranch{
+			RepoID:              repo.ID,
+			RuleName:            "main",
+			EnableStatusCheck:   true,
+			StatusCheckContexts: []string{"ci"},
+		}, git_model.WhitelistOptions{}))
+
+		setStatus := func(sha string, state api.CommitStatusState) {
+			assert.NoError(t, files_service.CreateCommitStatus(db.DefaultContext, repo, user, sha, &git_model.CommitStatus{
+				State:   state,
+				Context: "ci",
+			}))
+		}
+
+		// the pull request of feature-4 conflicts with the one of feature-1
+		prs := make([]*issues_model.PullRequest, 0, 4)
+		for i, content := range []string{"one", "two", "three", "four"} {
+			treePath := fmt.Sprintf("file-%d", i+1)
+			if i == 3 {
+				treePath = "file-1"
+			}
+			branch := fmt.Sprintf("feature-%d", i+1)
+			resp, err := files_service.CreateOrUpdateRepoFile(db.DefaultContext, repo, user, &files_service.UpdateRepoFileOptions{
+				TreePath:  treePath,
+				Message:   "Add " + treePath,
+				Content:   content,
+				IsNewFile: true,
+				OldBranch: "main",
+				NewBranch: branch,
+			})
+			assert.NoError(t, err)
+			setStatus(resp.Commit.SHA, api.CommitStatusSuccess)
+
+			issue := &issues_model.Issue{
+				RepoID:   repo.ID,
+				Title:    "Merge " + branch,
+				PosterID: user.ID,
+				Poster:   user,
+				IsPull:   true,
+			}
+			pr := &issues_model.PullRequest{
+				HeadRepoID: repo.ID,
+				BaseRepoID: repo.ID,
+				HeadBranch: branch,
+				BaseBranch: "main",
+				HeadRepo:   repo,
+				BaseRepo:   repo,
+				Type:       issues_model.PullRequestGitea,
+			}
+			assert.NoError(t, pull.NewPullRequest(db.DefaultContext, repo, issue, nil, nil, pr, nil))
+			prs = append(prs, pr)
+		}
+
+		token := getUserToken(t, user.Name)
+		queueURL := func(pr *issues_model.PullRequest) string {
+			return fmt.Sprintf("/api/v1/repos/%s/%s/pulls/%d/merge-queue?token=%s", user.Name, repo.Name, pr.Index, token)
+		}
+		getTrain := func() *pull_model.MergeTrain {
+			train, err := pull_model.GetMergeTrain(db.DefaultContext, repo.ID, "main")
+			assert.NoError(t, err)
+			return train
+		}
+
+		// the first pull request is tested alone as nothing else is queued yet
+		req := NewRequestWithJSON(t, "POST", queueURL(prs[0]), &forms.MergePullRequestForm{Do: string(repo_model.MergeStyleMerge)})
+		MakeRequest(t, req, http.StatusCreated)
+		MakeRequest(t, req, http.StatusConflict)
+		train := getTrain()
+		if assert.NotNil(t, train) {
+			assert.Equal(t, "gitea-merge-queue/main/pr-1", train.Branch)
+			assert.Equal(t, 1, train.Size)
+		}
+
+		req = NewRequest(t, "DELETE", queueURL(prs[1]))
+		MakeRequest(t, req, http.StatusNotFound)
+
+		for _, pr := range prs[1:] {
+			req := NewRequestWithJSON(t, "POST", queueURL(pr), &forms.MergePullRequestForm{Do: string(repo_model.MergeStyleMerge)})
+			MakeRequest(t, req, http.StatusCreated)
+		}
+		assert.Equal(t, train.ID, getTrain().ID)
+
+		req = NewRequest(t, "GET", fmt.Sprintf("/api/v1/repos/%s/%s/merge-queue?branch=main&token=%s", user.Name, repo.Name, token))
+		var queue []*api.MergeQueueEntry
+		DecodeJSON(t, MakeRequest(t, req, http.StatusOK), &queue)
+		if assert.Len(t, queue, 4) {
+			assert.EqualValues(t, prs[0].Index, queue[0].Number)
+			assert.Equal(t, "gitea-merge-queue/main/pr-1", queue[0].MergeTrainBranch)
+			assert.EqualValues(t, prs[1].Index, queue[1].Number)
+			assert.Empty(t, queue[1].MergeTrainBranch)
+		}
+
+		// several pull requests are tested together after the first one is merged, the conflicting one is removed
+		req = NewRequestWithJSON(t, "PATCH", fmt.Sprintf("/api/v1/repos/%s/%s?token=%s", user.Name, repo.Name, token), &api.EditRepoOption{
+			MergeTrainBatchSize: func(i int) *int { return &i }(3),
+		})
+		MakeRequest(t, req, http.StatusOK)
+
+		setStatus(train.CommitID, api.CommitStatusSuccess)
+		assert.True(t, unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: prs[0].ID}).HasMerged)
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: prs[3].IssueID, PosterID: user_model.ActionsUserID})
+		train = getTrain()
+		if assert.NotNil(t, train) {
+			assert.Equal(t, 2, train.Size)
+		}
+
+		gitRepo, err := git.OpenRepository(git.DefaultContext, repo.RepoPath())
+		assert.NoError(t, err)
+		defer gitRepo.Close()
+		assert.False(t, gitRepo.IsBranchExist("gitea-merge-queue/main/pr-1"))
+		assert.True(t, gitRepo.IsBranchExist(train.Branch))
+
+		// a failing train is split in half
+		setStatus(train.CommitID, api.CommitStatusFailure)
+		train = getTrain()
+		if assert.NotNil(t, train) {
+			assert.Equal(t, 1, train.Size)
+		}
+		setStatus(train.CommitID, api.CommitStatusSuccess)
+		assert.True(t, unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: prs[1].ID}).HasMerged)
+
+		// the pull request which fails alone is removed from the queue
+		train = getTrain()
+		if assert.NotNil(t, train) {
+			assert.Equal(t, 1, train.Size)
+		}
+		setStatus(train.CommitID, api.CommitStatusFailure)
+		assert.False(t, unittest.AssertExistsAndLoadBean(t, &issues_model.PullRequest{ID: prs[2].ID}).HasMerged)
+		unittest.AssertExistsAndLoadBean(t, &issues_model.Comment{IssueID: prs[2].IssueID, PosterID: user_model.ActionsUserID})
+		assert.Nil(t, getTrain())
+		unittest.AssertCount(t, &pull_model.MergeQueueEntry{RepoID: repo.ID}, 0)
+	})
+}
@@ -0,0 +1,81 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	auth_model "code.gitea.io/gitea/models/auth"
+	api "code.gitea.io/gitea/modules/structs"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPICherryPick(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		token := getUserToken(t, "user2", auth_model.AccessTokenScopeRepo)
+		cherryPick := func(t *testing.T, opts *api.CherryPickOptions, expectedStatus int) *api.CherryPickResponse {
+			req := NewRequestWithJSON(t, "POST", "/api/v1/repos/user2/repo1/cherry-pick?token="+token, opts)
+			resp := MakeRequest(t, req, expectedStatus)
+			if expectedStatus != http.StatusCreated {
+				return nil
+			}
+			var result api.CherryPickResponse
+			DecodeJSON(t, resp, &result)
+			return &result
+		}
+
+		t.Run("Range", func(t *testing.T) {
+			// the two commits of sub-home-md-img-check on top of master are picked oldest first
+			result := cherryPick(t, &api.CherryPickOptions{
+				BranchName:    "master",
+				NewBranchName: "cherry-pick-range",
+				From:          "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+				To:            "4649299398e4d39a5c09eb4f534df6f1e1eb87cc",
+			}, http.StatusCreated)
+			if assert.Len(t, result.Commits, 2) {
+				assert.Contains(t, result.Commits[0].Message, "add test fake img")
+				assert.Contains(t, result.Commits[0].Message, "(cherry picked from commit 78fb907e3a3309eae4fe8fef030874cebbf1cd5e)")
+				assert.Contains(t, result.Commits[1].Message, "Test how READMEs render images when found in a subfolder")
+				assert.Equal(t, result.Commits[0].SHA, result.Commits[1].Parents[0].SHA)
+			}
+		})
+
+		t.Run("RevertOrder", func(t *testing.T) {
+			// reverts are applied newest first
+			result := cherryPick(t, &api.CherryPickOptions{
+				BranchName:    "sub-home-md-img-check",
+				NewBranchName: "revert-range",
+				From:          "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+				To:            "4649299398e4d39a5c09eb4f534df6f1e1eb87cc",
+				Revert:        true,
+			}, http.StatusCreated)
+			if assert.Len(t, result.Commits, 2) {
+				assert.Contains(t, result.Commits[0].Message, "This reverts commit 4649299398e4d39a5c09eb4f534df6f1e1eb87cc.")
+				assert.Contains(t, result.Commits[1].Message, "This reverts commit 78fb907e3a3309eae4fe8fef030874cebbf1cd5e.")
+			}
+		})
+
+		t.Run("InvalidMainline", func(t *testing.T) {
+			// a commit with a single parent has no second parent to pick relative to
+			cherryPick(t, &api.CherryPickOptions{
+				BranchName:    "master",
+				NewBranchName: "cherry-pick-mainline",
+				To:            "78fb907e3a3309eae4fe8fef030874cebbf1cd5e",
+				Mainline:      2,
+			}, http.StatusUnprocessableEntity)
+		})
+
+		t.Run("EmptyRange", func(t *testing.T) {
+			cherryPick(t, &api.CherryPickOptions{
+				BranchName:    "master",
+				NewBranchName: "cherry-pick-empty",
+				From:          "4649299398e4d39a5c09eb4f534df6f1e1eb87cc",
+				To:            "65f1bf27bc3bf70f64657658635e66094edbcb4d",
+			}, http.StatusUnprocessableEntity)
+		})
+	})
+}
@@ -0,0 +1,34 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/http"
+	"testing"
+
+	api "code.gitea.io/gitea/modules/structs"
+	"code.gitea.io/gitea/tests"
+)
+
+func TestAPIReportAbuseVisibility(t *testing.T) {
+	defer tests.PrepareTestEnv(t)()
+
+	report := func(t *testing.T, token, contentType string, contentID int64, expectedStatus int) {
+		req := NewRequestWithJSON(t, "POST", "/api/v1/reports?token="+token, &api.CreateAbuseReportOption{
+			ContentType: contentType,
+			ContentID:   contentID,
+			Category:    "spam",
+		})
+		MakeRequest(t, req, expectedStatus)
+	}
+
+	// user5 cannot see the private user2/repo2 or its issues
+	token := getUserToken(t, "user5")
+	report(t, token, "repository", 2, http.StatusNotFound)
+	report(t, token, "issue", 4, http.StatusNotFound)
+	report(t, token, "repository", 1, http.StatusCreated)
+	report(t, token, "issue", 1, http.StatusCreated)
+
+	report(t, getUserToken(t, "user2"), "issue", 4, http.StatusCreated)
+}
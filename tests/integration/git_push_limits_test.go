@@ -0,0 +1,64 @@
+// Copyright 2023 The Gitea Authors. All rights reserved.
+// SPDX-License-Identifier: MIT
+
+package integration
+
+import (
+	"net/url"
+	"testing"
+
+	"code.gitea.io/gitea/modules/setting"
+	"code.gitea.io/gitea/tests"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGitPushLimits(t *testing.T) {
+	onGiteaRun(t, func(t *testing.T, u *url.URL) {
+		u.Path = "user2/repo1.git"
+		u.User = url.UserPassword("user2", userPassword)
+
+		dstPath := t.TempDir()
+		t.Run("Clone", doGitClone(dstPath, u))
+
+		t.Run("MaxNewRefs", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+			defer func(maxNewRefs int) {
+				setting.Repository.PushLimits.MaxNewRefs = maxNewRefs
+			}(setting.Repository.PushLimits.MaxNewRefs)
+			setting.Repository.PushLimits.MaxNewRefs = 1
+
+			t.Run("CreateBranch1", doGitCreateBranch(dstPath, "push-limits-1"))
+			t.Run("CreateBranch2", doGitCreateBranch(dstPath, "push-limits-2"))
+			t.Run("PushTwoBranches", doGitPushTestRepositoryFail(dstPath, "origin", "push-limits-1", "push-limits-2"))
+			t.Run("PushOneBranch", doGitPushTestRepository(dstPath, "origin", "push-limits-1"))
+		})
+
+		t.Run("MaxCommitsPerPush", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+			defer func(maxCommits int) {
+				setting.Repository.PushLimits.MaxCommitsPerPush = maxCommits
+			}(setting.Repository.PushLimits.MaxCommitsPerPush)
+			setting.Repository.PushLimits.MaxCommitsPerPush = 1
+
+			t.Run("CheckoutBranch", doGitCheckoutBranch(dstPath, "push-limits-1"))
+			for i := 0; i < 2; i++ {
+				_, err := generateCommitWithNewData(littleSize, dstPath, "user2@example.com", "User Two", "push-limits-")
+				assert.NoError(t, err)
+			}
+			t.Run("PushTwoCommits", doGitPushTestRepositoryFail(dstPath, "origin", "push-limits-1"))
+		})
+
+		t.Run("MaxPushSize", func(t *testing.T) {
+			defer tests.PrintCurrentTest(t)()
+			defer func(maxSize int64) {
+				setting.Repository.PushLimits.MaxPushSize = maxSize
+			}(setting.Repository.PushLimits.MaxPushSize)
+			setting.Repository.PushLimits.MaxPushSize = 4 * 1024
+
+			_, err := generateCommitWithNewData(10*littleSize, dstPath, "user2@example.com", "User Two", "push-limits-big-")
+			assert.NoError(t, err)
+			t.Run("PushBigCommit", doGitPushTestRepositoryFail(dstPath, "origin", "push-limits-1"))
+		})
+	})
+}
@@ -11,16 +11,18 @@ async function uploadFile(file, uploadUrl) {
     headers: {'X-Csrf-Token': csrfToken},
     body: formData,
   });
+  if (!res.ok) return null;
   return await res.json();
 }
 
-function clipboardPastedImages(e) {
+function clipboardPastedFiles(e) {
   if (!e.clipboardData) return [];
 
   const files = [];
   for (const item of e.clipboardData.items || []) {
-    if (!item.type || !item.type.startsWith('image/')) continue;
-    files.push(item.getAsFile());
+    if (item.kind !== 'file') continue;
+    const file = item.getAsFile();
+    if (file) files.push(file);
   }
   return files;
 }
@@ -96,27 +98,35 @@ class CodeMirrorEditor {
 }
 
 
-const uploadClipboardImage = async (editor, dropzone, e) => {
+const uploadClipboardFiles = async (editor, dropzone, e) => {
   const $dropzone = $(dropzone);
   const uploadUrl = $dropzone.attr('data-upload-url');
   const $files = $dropzone.find('.files');
 
   if (!uploadUrl || !$files.length) return;
 
-  const pastedImages = clipboardPastedImages(e);
-  if (!pastedImages || pastedImages.length === 0) {
+  const pastedFiles = clipboardPastedFiles(e);
+  if (!pastedFiles || pastedFiles.length === 0) {
     return;
   }
   e.preventDefault();
   e.stopPropagation();
 
-  for (const img of pastedImages) {
-    const name = img.name.slice(0, img.name.lastIndexOf('.'));
+  for (const file of pastedFiles) {
+    // images are embedded, other files are linked
+    const isImage = file.type.startsWith('image/');
+    const name = isImage ? file.name.slice(0, file.name.lastIndexOf('.')) : file.name;
+    const prefix = isImage ? '!' : '';
 
-    const placeholder = `![${name}](uploading ...)`;
+    const placeholder = `${prefix}[${name}](uploading ...)`;
     editor.insertPlaceholder(placeholder);
-    const data = await uploadFile(img, uploadUrl);
-    editor.replacePlaceholder(placeholder, `![${name}](/attachments/${data.uuid})`);
+    const data = await uploadFile(file, uploadUrl);
+    if (!data || !data.uuid) {
+      // the file was rejected, e.g. because of its type or size
+      editor.replacePlaceholder(placeholder, '');
+      continue;
+    }
+    editor.replacePlaceholder(placeholder, `${prefix}[${name}](/attachments/${data.uuid})`);
 
     const $input = $(`<input name="files" type="hidden">`).attr('id', data.uuid).val(data.uuid);
     $files.append($input);
@@ -126,13 +136,13 @@ const uploadClipboardImage = async (editor, dropzone, e) => {
 export function initEasyMDEImagePaste(easyMDE, dropzone) {
   if (!dropzone) return;
   easyMDE.codemirror.on('paste', async (_, e) => {
-    return uploadClipboardImage(new CodeMirrorEditor(easyMDE.codemirror), dropzone, e);
+    return uploadClipboardFiles(new CodeMirrorEditor(easyMDE.codemirror), dropzone, e);
   });
 }
 
 export function initTextareaImagePaste(textarea, dropzone) {
   if (!dropzone) return;
   $(textarea).on('paste', async (e) => {
-    return uploadClipboardImage(new TextareaEditor(textarea), dropzone, e.originalEvent);
+    return uploadClipboardFiles(new TextareaEditor(textarea), dropzone, e.originalEvent);
   });
 }
@@ -1,5 +1,6 @@
 import $ from 'jquery';
 import {useLightTextOnBackground, hexToRGBColor} from '../utils/color.js';
+import {showTemporaryTooltip} from '../modules/tippy.js';
 
 const {csrfToken} = window.config;
 
@@ -29,20 +30,45 @@ function moveIssue({item, from, to, oldIndex}) {
     },
     contentType: 'application/json',
     type: 'POST',
-    error: () => {
+    success: (data) => {
+      // the column is over its WIP limit, but it allows more items
+      if (data?.message) showTemporaryTooltip(item, data.message);
+    },
+    error: (xhr) => {
       from.insertBefore(item, from.children[oldIndex]);
+      updateIssueCount(from);
+      updateIssueCount(to);
+      if (xhr.responseJSON?.message) showTemporaryTooltip(item, xhr.responseJSON.message);
     }
   });
 }
 
 async function initRepoProjectSortable() {
-  const els = document.querySelectorAll('#project-board > .board.sortable');
+  // with swimlanes there is a board per lane, the columns can only be sorted without them
+  const els = document.querySelectorAll('#project-board > .board.sortable, #project-board > .board.cards-sortable');
   if (!els.length) return;
 
   const {Sortable} = await import(/* webpackChunkName: "sortable" */'sortablejs');
 
   // the HTML layout is: #project-board > .board > .board-column .board.cards > .board-card.card .content
-  const mainBoard = els[0];
+  for (const board of els) {
+    for (const boardColumn of board.getElementsByClassName('board-column')) {
+      const boardCardList = boardColumn.getElementsByClassName('board')[0];
+      new Sortable(boardCardList, {
+        // the cards can only be moved between the columns of their lane
+        group: `shared-${board.getAttribute('data-lane')}`,
+        animation: 150,
+        ghostClass: 'card-ghost',
+        onAdd: moveIssue,
+        onUpdate: moveIssue,
+        delayOnTouchOnly: true,
+        delay: 500,
+      });
+    }
+  }
+
+  const mainBoard = document.querySelector('#project-board > .board.sortable');
+  if (!mainBoard) return;
   let boardColumns = mainBoard.getElementsByClassName('board-column');
   new Sortable(mainBoard, {
     group: 'board-column',
@@ -70,19 +96,6 @@ async function initRepoProjectSortable() {
       }
     },
   });
-
-  for (const boardColumn of boardColumns) {
-    const boardCardList = boardColumn.getElementsByClassName('board')[0];
-    new Sortable(boardCardList, {
-      group: 'shared',
-      animation: 150,
-      ghostClass: 'card-ghost',
-      onAdd: moveIssue,
-      onUpdate: moveIssue,
-      delayOnTouchOnly: true,
-      delay: 500,
-    });
-  }
 }
 
 export function initRepoProject() {
@@ -97,6 +110,8 @@ export function initRepoProject() {
     const projectTitleLabel = projectHeader.find('.board-label');
     const projectTitleInput = $(this).find('.project-board-title');
     const projectColorInput = $(this).find('#new_board_color');
+    const projectWipLimitInput = $(this).find('.project-board-wip-limit');
+    const projectWipLimitModeInput = $(this).find('.project-board-wip-limit-mode');
     const boardColumn = $(this).closest('.board-column');
 
     if (boardColumn.css('backgroundColor')) {
@@ -108,7 +123,12 @@ export function initRepoProject() {
 
       $.ajax({
         url: $(this).data('url'),
-        data: JSON.stringify({title: projectTitleInput.val(), color: projectColorInput.val()}),
+        data: JSON.stringify({
+          title: projectTitleInput.val(),
+          color: projectColorInput.val(),
+          wip_limit: parseInt(projectWipLimitInput.val() || '0'),
+          wip_limit_mode: parseInt(projectWipLimitModeInput.val() || '0'),
+        }),
         headers: {
           'X-Csrf-Token': csrfToken,
         },
@@ -39,6 +39,7 @@ function makeCollections({mentions, emoji}) {
             <img src="${htmlEscape(item.original.avatar)}" class="gt-mr-3"/>
             <span class="name">${htmlEscape(item.original.name)}</span>
             ${item.original.fullname && item.original.fullname !== '' ? `<span class="fullname">${htmlEscape(item.original.fullname)}</span>` : ''}
+            ${item.original.status ? `<span class="status${item.original.status.busy ? ' busy' : ''}">${item.original.status.emoji ? emojiHTML(item.original.status.emoji) : ''}${htmlEscape(item.original.status.message)}</span>` : ''}
           </div>
         `;
       }